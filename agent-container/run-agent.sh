@@ -37,7 +37,19 @@ set -euo pipefail
 #   AGENT_VERBOSE        - Enable verbose output
 #   AGENT_MAX_TURNS      - Maximum conversation turns
 #   CONTAINER_MEMORY, CONTAINER_CPUS, CONTAINER_NETWORK, CONTAINER_USER
+#   AGENT_HARDENING      - Drop all capabilities and set no-new-privileges (true/false)
+#   CONTAINER_READ_ONLY  - Read-only root filesystem; only output/ and logs/ are writable
+#   CONTAINER_TMPFS      - Comma-separated container paths mounted as tmpfs scratch space
+#   AGENT_OFFLINE        - Air-gapped mode: disable agent CLI update checks and telemetry
+#   CONTAINER_CIDFILE    - Write the container ID to this file (used for resource sampling)
 #   SKILLS_DIR, DEBUG
+#   SKILLS_SELECTED      - Comma-separated skill bundles to mount from SKILLS_DIR (default: all)
+#   HTTP_PROXY, HTTPS_PROXY, NO_PROXY - forwarded to the agent container
+#   LLM_PROVIDER         - openai-compatible, azure-openai, or bedrock (default: provider API keys)
+#   LLM_BASE_URL, LLM_API_KEY - self-hosted OpenAI-compatible endpoint (codex, goose)
+#   AZURE_OPENAI_ENDPOINT, AZURE_OPENAI_DEPLOYMENT, AZURE_OPENAI_API_KEY, AZURE_OPENAI_API_VERSION
+#   AWS_REGION, AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN,
+#   AWS_ROLE_ARN, AWS_WEB_IDENTITY_TOKEN_FILE - AWS Bedrock (claude, goose)
 #
 # Legacy Claude-specific variables (still supported for backward compatibility):
 #   CLAUDE_MODEL, CLAUDE_ALLOWED_TOOLS, CLAUDE_OUTPUT_FORMAT, CLAUDE_SYSTEM_PROMPT_FILE
@@ -81,10 +93,19 @@ CONTAINER_MEMORY="${CONTAINER_MEMORY:-}"
 CONTAINER_CPUS="${CONTAINER_CPUS:-}"
 CONTAINER_NETWORK="${CONTAINER_NETWORK:-}"
 CONTAINER_USER="${CONTAINER_USER:-}"
+AGENT_HARDENING="${AGENT_HARDENING:-false}"
+CONTAINER_READ_ONLY="${CONTAINER_READ_ONLY:-false}"
+CONTAINER_TMPFS="${CONTAINER_TMPFS:-}"
+AGENT_OFFLINE="${AGENT_OFFLINE:-false}"
+CONTAINER_CIDFILE="${CONTAINER_CIDFILE:-}"
 SKILLS_DIR="${SKILLS_DIR:-}"
+SKILLS_SELECTED="${SKILLS_SELECTED:-}"
 DISABLE_TRIAGE_PRELOAD="${DISABLE_TRIAGE_PRELOAD:-false}"
 DEBUG="${DEBUG:-}"
 INCIDENT_ID="${INCIDENT_ID:-}"
+LLM_PROVIDER="${LLM_PROVIDER:-}"
+LLM_BASE_URL="${LLM_BASE_URL:-}"
+LLM_API_KEY="${LLM_API_KEY:-}"
 
 # =============================================================================
 # Help
@@ -167,7 +188,14 @@ ENVIRONMENT VARIABLES:
   CONTAINER_MEMORY              Memory limit
   CONTAINER_CPUS                CPU limit
   CONTAINER_NETWORK             Network mode
+  CONTAINER_USER                Run as specific user (UID:GID)
+  AGENT_HARDENING               Drop all capabilities, set no-new-privileges
+  CONTAINER_READ_ONLY           Read-only root filesystem (true/false)
+  CONTAINER_TMPFS               Comma-separated tmpfs scratch paths
+  AGENT_OFFLINE                 Disable agent CLI update checks and telemetry (true/false)
+  CONTAINER_CIDFILE             Write the container ID to this file
   SKILLS_DIR                    Skills directory
+  SKILLS_SELECTED               Comma-separated skill bundles to mount (default: all)
 
 EXAMPLES:
   # Basic usage with Claude (default)
@@ -359,6 +387,57 @@ if [[ -n "$AGENT_CLI" ]]; then
     esac
 fi
 
+# Self-hosted and managed-cloud LLM providers (set by nightcrier as LLM_PROVIDER):
+#   openai-compatible - vLLM, Ollama, ... at LLM_BASE_URL (codex, goose)
+#   azure-openai      - AZURE_OPENAI_ENDPOINT/DEPLOYMENT/API_KEY (codex, goose)
+#   bedrock           - AWS_REGION plus IAM credentials or role (claude, goose)
+# The agent CLIs insist on an API key even for local servers that ignore it, so a
+# placeholder is used when none is configured.
+require_provider_agent() {
+    local provider="$1"
+    shift
+    local agent
+    for agent in "$@"; do
+        [[ "$AGENT_CLI" == "$agent" ]] && return 0
+    done
+    echo "Error: LLM provider '$provider' is not supported by agent '$AGENT_CLI' (use: $*)" >&2
+    exit 1
+}
+
+case "$LLM_PROVIDER" in
+    "")
+        ;;
+    openai-compatible)
+        require_provider_agent "$LLM_PROVIDER" codex goose
+        if [[ -z "$LLM_BASE_URL" ]]; then
+            echo "Error: LLM_BASE_URL is required for the openai-compatible provider" >&2
+            exit 1
+        fi
+        export OPENAI_API_KEY="${LLM_API_KEY:-${OPENAI_API_KEY:-not-required}}"
+        ;;
+    azure-openai)
+        require_provider_agent "$LLM_PROVIDER" codex goose
+        if [[ -z "${AZURE_OPENAI_ENDPOINT:-}" || -z "${AZURE_OPENAI_DEPLOYMENT:-}" || -z "${AZURE_OPENAI_API_KEY:-}" ]]; then
+            echo "Error: AZURE_OPENAI_ENDPOINT, AZURE_OPENAI_DEPLOYMENT and AZURE_OPENAI_API_KEY are required for Azure OpenAI" >&2
+            exit 1
+        fi
+        export AZURE_OPENAI_ENDPOINT AZURE_OPENAI_DEPLOYMENT AZURE_OPENAI_API_KEY
+        export AZURE_OPENAI_API_VERSION="${AZURE_OPENAI_API_VERSION:-2025-04-01-preview}"
+        ;;
+    bedrock)
+        require_provider_agent "$LLM_PROVIDER" claude goose
+        if [[ -z "${AWS_REGION:-}" ]]; then
+            echo "Error: AWS_REGION is required for Bedrock" >&2
+            exit 1
+        fi
+        ;;
+    *)
+        echo "Error: Invalid LLM_PROVIDER '$LLM_PROVIDER'. Must be one of: openai-compatible, azure-openai, bedrock" >&2
+        exit 1
+        ;;
+esac
+export LLM_PROVIDER
+
 # Validate API key for selected agent
 validate_api_key() {
     case "$AGENT_CLI" in
@@ -392,7 +471,10 @@ validate_api_key() {
     esac
 }
 
-validate_api_key
+# Managed-cloud providers authenticate with their own credentials
+if [[ "$LLM_PROVIDER" != "azure-openai" && "$LLM_PROVIDER" != "bedrock" ]]; then
+    validate_api_key
+fi
 
 if [[ -z "$PROMPT" ]]; then
     echo "Error: Prompt is required" >&2
@@ -441,6 +523,11 @@ if [[ "$DEBUG" != "true" ]]; then
     DOCKER_ARGS+=("--rm")
 fi
 
+# Container ID file, so nightcrier can sample the container's cgroup stats
+if [[ -n "$CONTAINER_CIDFILE" ]]; then
+    DOCKER_ARGS+=("--cidfile" "$CONTAINER_CIDFILE")
+fi
+
 # Timeout via timeout command wrapper
 if [[ -n "$CONTAINER_TIMEOUT" ]]; then
     DOCKER_ARGS+=("--stop-timeout" "$CONTAINER_TIMEOUT")
@@ -466,6 +553,26 @@ if [[ -n "$CONTAINER_USER" ]]; then
     DOCKER_ARGS+=("--user" "$CONTAINER_USER")
 fi
 
+# Least-privilege hardening (set by nightcrier from agent_hardening): the agent
+# needs no Linux capabilities and must never gain privileges through setuid binaries
+if [[ "$AGENT_HARDENING" == "true" ]]; then
+    DOCKER_ARGS+=("--cap-drop" "ALL")
+    DOCKER_ARGS+=("--security-opt" "no-new-privileges")
+fi
+
+# Read-only root filesystem: only the output and logs mounts and the tmpfs
+# scratch paths are writable
+if [[ "$CONTAINER_READ_ONLY" == "true" ]]; then
+    DOCKER_ARGS+=("--read-only")
+    IFS=',' read -ra tmpfs_paths <<< "$CONTAINER_TMPFS"
+    for tmpfs_path in "${tmpfs_paths[@]}"; do
+        tmpfs_path="$(echo "$tmpfs_path" | xargs)"
+        if [[ -n "$tmpfs_path" ]]; then
+            DOCKER_ARGS+=("--tmpfs" "${tmpfs_path}:rw,nosuid,nodev")
+        fi
+    done
+fi
+
 # Environment variables based on agent
 if [[ -n "$ANTHROPIC_API_KEY" ]]; then
     DOCKER_ARGS+=("-e" "ANTHROPIC_API_KEY=${ANTHROPIC_API_KEY}")
@@ -484,6 +591,64 @@ if [[ -n "$KUBERNETES_CONTEXT" ]]; then
     DOCKER_ARGS+=("-e" "KUBERNETES_CONTEXT=${KUBERNETES_CONTEXT}")
 fi
 
+# LLM provider settings, translated to what each agent CLI expects
+case "$LLM_PROVIDER" in
+    openai-compatible)
+        # codex reads OPENAI_BASE_URL; goose uses the openai provider with the
+        # endpoint split into OPENAI_HOST and OPENAI_BASE_PATH
+        DOCKER_ARGS+=("-e" "OPENAI_BASE_URL=${LLM_BASE_URL}")
+        if [[ "$AGENT_CLI" == "goose" && "$LLM_BASE_URL" =~ ^(https?://[^/]+)/?(.*)$ ]]; then
+            base_path="${BASH_REMATCH[2]}"
+            DOCKER_ARGS+=("-e" "GOOSE_PROVIDER=openai")
+            DOCKER_ARGS+=("-e" "OPENAI_HOST=${BASH_REMATCH[1]}")
+            DOCKER_ARGS+=("-e" "OPENAI_BASE_PATH=${base_path:+${base_path%/}/}chat/completions")
+        fi
+        ;;
+    azure-openai)
+        # codex selects its azure provider in runners/codex.sh; goose reads these directly
+        for azure_var in AZURE_OPENAI_ENDPOINT AZURE_OPENAI_API_KEY AZURE_OPENAI_API_VERSION; do
+            DOCKER_ARGS+=("-e" "${azure_var}=${!azure_var}")
+        done
+        DOCKER_ARGS+=("-e" "AZURE_OPENAI_DEPLOYMENT_NAME=${AZURE_OPENAI_DEPLOYMENT}")
+        if [[ "$AGENT_CLI" == "goose" ]]; then
+            DOCKER_ARGS+=("-e" "GOOSE_PROVIDER=azure_openai")
+        fi
+        ;;
+    bedrock)
+        for aws_var in AWS_REGION AWS_ACCESS_KEY_ID AWS_SECRET_ACCESS_KEY AWS_SESSION_TOKEN AWS_ROLE_ARN AWS_WEB_IDENTITY_TOKEN_FILE; do
+            if [[ -n "${!aws_var:-}" ]]; then
+                DOCKER_ARGS+=("-e" "${aws_var}=${!aws_var}")
+            fi
+        done
+        # The projected token is read by the AWS SDK inside the container
+        if [[ -n "${AWS_WEB_IDENTITY_TOKEN_FILE:-}" ]]; then
+            DOCKER_ARGS+=("-v" "${AWS_WEB_IDENTITY_TOKEN_FILE}:${AWS_WEB_IDENTITY_TOKEN_FILE}:ro")
+        fi
+        case "$AGENT_CLI" in
+            claude) DOCKER_ARGS+=("-e" "CLAUDE_CODE_USE_BEDROCK=1") ;;
+            goose) DOCKER_ARGS+=("-e" "GOOSE_PROVIDER=aws_bedrock") ;;
+        esac
+        ;;
+esac
+
+# Air-gapped mode (set by nightcrier from offline.enabled): the agent CLIs must not
+# phone home for updates, telemetry, or error reporting
+if [[ "$AGENT_OFFLINE" == "true" ]]; then
+    DOCKER_ARGS+=("-e" "DO_NOT_TRACK=1")
+    if [[ "$AGENT_CLI" == "claude" ]]; then
+        DOCKER_ARGS+=("-e" "CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC=1")
+        DOCKER_ARGS+=("-e" "DISABLE_AUTOUPDATER=1")
+    fi
+fi
+
+# Proxy settings for LLM API calls (set by nightcrier from proxy.llm or inherited)
+for proxy_var in HTTP_PROXY HTTPS_PROXY NO_PROXY; do
+    if [[ -n "${!proxy_var}" ]]; then
+        lower_var="${proxy_var,,}"
+        DOCKER_ARGS+=("-e" "${proxy_var}=${!proxy_var}" "-e" "${lower_var}=${!proxy_var}")
+    fi
+done
+
 # Volume mounts - everything goes into /home/agent (the agent's home AND workspace)
 # This keeps skills, config files, and incident data all in one place
 AGENT_HOME="/home/agent"
@@ -498,6 +663,11 @@ if [[ -f "${WORKSPACE_DIR}/incident_cluster_permissions.json" ]]; then
     DOCKER_ARGS+=("-v" "${WORKSPACE_DIR}/incident_cluster_permissions.json:${AGENT_HOME}/incident_cluster_permissions.json:ro")
 fi
 
+# Mount runbooks attached for this fault type / resource kind
+if [[ -d "${WORKSPACE_DIR}/runbooks" ]]; then
+    DOCKER_ARGS+=("-v" "${WORKSPACE_DIR}/runbooks:${AGENT_HOME}/runbooks:ro")
+fi
+
 # Mount output directory into agent home (read-write for agent to write results)
 mkdir -p "${OUTPUT_DIR}"
 DOCKER_ARGS+=("-v" "${OUTPUT_DIR}:${AGENT_HOME}/output")
@@ -513,10 +683,23 @@ if [[ -f "$KUBECONFIG_PATH" ]]; then
 fi
 
 # Mount skills directory if specified (overrides built-in skills)
+# With SKILLS_SELECTED, only the listed bundles are mounted (per-cluster selection)
 if [[ -n "$SKILLS_DIR" && -d "$SKILLS_DIR" ]]; then
     SKILLS_DIR_ABS="$(cd "$SKILLS_DIR" && pwd)"
-    DOCKER_ARGS+=("-v" "${SKILLS_DIR_ABS}:${AGENT_HOME}/.claude/skills:ro")
-    DOCKER_ARGS+=("-v" "${SKILLS_DIR_ABS}:${AGENT_HOME}/.codex/skills:ro")
+    if [[ -n "$SKILLS_SELECTED" ]]; then
+        IFS=',' read -ra SELECTED_SKILLS <<< "$SKILLS_SELECTED"
+        for skill in "${SELECTED_SKILLS[@]}"; do
+            if [[ -d "${SKILLS_DIR_ABS}/${skill}" ]]; then
+                DOCKER_ARGS+=("-v" "${SKILLS_DIR_ABS}/${skill}:${AGENT_HOME}/.claude/skills/${skill}:ro")
+                DOCKER_ARGS+=("-v" "${SKILLS_DIR_ABS}/${skill}:${AGENT_HOME}/.codex/skills/${skill}:ro")
+            else
+                echo "Warning: selected skill not found in skills directory: $skill" >&2
+            fi
+        done
+    else
+        DOCKER_ARGS+=("-v" "${SKILLS_DIR_ABS}:${AGENT_HOME}/.claude/skills:ro")
+        DOCKER_ARGS+=("-v" "${SKILLS_DIR_ABS}:${AGENT_HOME}/.codex/skills:ro")
+    fi
 fi
 
 # Mount system prompt file if specified
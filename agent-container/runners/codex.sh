@@ -16,8 +16,8 @@ source "$SCRIPT_DIR/common.sh"
 # Validate required environment
 validate_runner_env || exit 1
 
-# Require OpenAI API key
-if [[ -z "$OPENAI_API_KEY" ]]; then
+# Require OpenAI API key (Azure OpenAI authenticates with AZURE_OPENAI_API_KEY)
+if [[ -z "$OPENAI_API_KEY" && "${LLM_PROVIDER:-}" != "azure-openai" ]]; then
     log_error "OPENAI_API_KEY is required for Codex"
     exit 1
 fi
@@ -36,11 +36,13 @@ build_codex_command() {
         cmd="$debug_check"
     fi
 
-    # Codex requires login with API key first
+    # Codex requires login with API key first (Azure OpenAI reads its key from env_key instead)
     # Store API key in temp file for login, then remove it
-    cmd+="echo -n \"\${OPENAI_API_KEY}\" > /tmp/.codex-key && "
-    cmd+="codex login --with-api-key < /tmp/.codex-key && "
-    cmd+="rm -f /tmp/.codex-key && "
+    if [[ "${LLM_PROVIDER:-}" != "azure-openai" ]]; then
+        cmd+="echo -n \"\${OPENAI_API_KEY}\" > /tmp/.codex-key && "
+        cmd+="codex login --with-api-key < /tmp/.codex-key && "
+        cmd+="rm -f /tmp/.codex-key && "
+    fi
 
     # Start Codex command with exec mode for non-interactive/headless operation
     # --skip-git-repo-check: needed when not in a git repo
@@ -48,6 +50,16 @@ build_codex_command() {
     # --dangerously-bypass-approvals-and-sandbox: required in Docker containers without Landlock
     cmd+="codex exec --skip-git-repo-check --enable skills --dangerously-bypass-approvals-and-sandbox"
 
+    # Azure OpenAI: define the azure model provider inline; the model is the deployment name
+    if [[ "${LLM_PROVIDER:-}" == "azure-openai" ]]; then
+        cmd+=" -c model_provider=azure"
+        cmd+=" -c 'model_providers.azure.name=\"Azure OpenAI\"'"
+        cmd+=" -c 'model_providers.azure.base_url=\"${AZURE_OPENAI_ENDPOINT}/openai\"'"
+        cmd+=" -c model_providers.azure.env_key=AZURE_OPENAI_API_KEY"
+        cmd+=" -c 'model_providers.azure.query_params={api-version=\"${AZURE_OPENAI_API_VERSION}\"}'"
+        cmd+=" -c model_providers.azure.wire_api=responses"
+    fi
+
     # Model mapping for Codex
     # Codex uses OpenAI model names, but we support friendly aliases
     if [[ -n "$LLM_MODEL" ]]; then
@@ -16,8 +16,8 @@ source "$SCRIPT_DIR/common.sh"
 # Validate required environment variables
 validate_runner_env || exit 1
 
-# Require API key for Goose (provider-specific)
-if [[ -z "${OPENAI_API_KEY:-}" && -z "${ANTHROPIC_API_KEY:-}" && -z "${GEMINI_API_KEY:-}" ]]; then
+# Require API key for Goose (provider-specific); managed-cloud providers bring their own credentials
+if [[ -z "${LLM_PROVIDER:-}" && -z "${OPENAI_API_KEY:-}" && -z "${ANTHROPIC_API_KEY:-}" && -z "${GEMINI_API_KEY:-}" ]]; then
     log_error "At least one API key required for Goose (OPENAI_API_KEY, ANTHROPIC_API_KEY, or GEMINI_API_KEY)"
     exit 1
 fi
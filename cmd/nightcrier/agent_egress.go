@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/egress"
+)
+
+// agentEgressPolicies returns the egress policy of each cluster's agent: the LLM
+// endpoint, the API servers of the cluster's kubeconfig, and the configured extra
+// hosts. The allow-list of all clusters is written to agent_egress.allowlist_file
+// for the proxy. Returns nil when egress control is disabled.
+func agentEgressPolicies(cfg *config.Config) (map[string]*egress.Policy, error) {
+	e := cfg.AgentEgress
+	if !e.Enabled {
+		return nil, nil
+	}
+	llmHosts := cfg.LLMHosts()
+	if len(llmHosts) == 0 {
+		slog.Warn("no LLM endpoint known for agent egress control; add it to agent_egress.allowed_hosts",
+			"agent_cli", cfg.AgentCLI)
+	}
+
+	policies := make(map[string]*egress.Policy, len(cfg.Clusters))
+	all := make([]*egress.Policy, 0, len(cfg.Clusters))
+	for _, cl := range cfg.Clusters {
+		var kubeHosts []string
+		if cl.Triage.Enabled && cl.Triage.Kubeconfig != "" {
+			hosts, err := egress.KubeAPIHosts(cl.Triage.Kubeconfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read API servers of cluster %s for agent egress control: %w", cl.Name, err)
+			}
+			kubeHosts = hosts
+		}
+		policy := egress.NewPolicy(e.Network, e.ProxyURL, llmHosts, kubeHosts, e.ExtraHosts())
+		policies[cl.Name] = policy
+		all = append(all, policy)
+		slog.Info("agent egress control enabled for cluster",
+			"cluster", cl.Name,
+			"network", policy.Network,
+			"proxy", policy.Proxy,
+			"allowed_hosts", policy.AllowedHosts)
+	}
+
+	if e.AllowlistFile != "" {
+		if err := egress.WriteAllowlist(e.AllowlistFile, all...); err != nil {
+			return nil, err
+		}
+		slog.Info("wrote agent egress allow-list", "path", e.AllowlistFile)
+	}
+	return policies, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/agent"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/webhooks"
+)
+
+// checkAgentPinsAtStartup compares the agent runtime with the pinned versions
+// before any investigation runs. Drift is logged; under the enforce policy it is
+// returned, so nightcrier refuses to start.
+func checkAgentPinsAtStartup(ctx context.Context, cfg *config.Config, prober *agent.RuntimeProber, image string) error {
+	if !cfg.AgentPinning.Enabled() {
+		return nil
+	}
+	digest, cliVersion, err := prober.Probe(ctx, image, cfg.AgentCLI)
+	if err != nil {
+		slog.Warn("failed to read agent runtime versions", "agent_image", image, "error", err)
+	}
+	drift := cfg.AgentPinning.Drift(cliVersion, digest, cfg.EffectiveAgentModel())
+	if len(drift) == 0 {
+		slog.Info("agent runtime matches the pinned versions",
+			"agent_cli_version", cliVersion,
+			"agent_image_digest", digest,
+			"model", cfg.EffectiveAgentModel())
+		return nil
+	}
+	if cfg.AgentPinning.Enforced() {
+		return fmt.Errorf("agent runtime drifted from agent_pinning: %s", strings.Join(drift, "; "))
+	}
+	slog.Warn("agent runtime drifted from the pinned versions", "drift", strings.Join(drift, "; "))
+	return nil
+}
+
+// checkAgentDrift compares the agent runtime an investigation would run with to
+// the pinned versions. Drift is logged; under the enforce policy it is returned
+// and the investigation is refused.
+func (p *eventProcessor) checkAgentDrift(ctx context.Context, info agent.AgentInfo) error {
+	if !p.cfg.AgentPinning.Enabled() {
+		return nil
+	}
+	drift := p.cfg.AgentPinning.Drift(info.CLIVersion, info.ImageDigest, info.Model)
+	if len(drift) == 0 {
+		return nil
+	}
+	if p.cfg.AgentPinning.Enforced() {
+		return fmt.Errorf("agent runtime drifted from the pinned versions: %s", strings.Join(drift, "; "))
+	}
+	incident.Logger(ctx).Warn("agent runtime drifted from the pinned versions", "drift", strings.Join(drift, "; "))
+	return nil
+}
+
+// refuseDriftedAgent closes an incident without an investigation because the
+// agent runtime drifted from the enforced pins.
+func (p *eventProcessor) refuseDriftedAgent(ctx context.Context, inc *incident.Incident, drift error) error {
+	log := incident.Logger(ctx)
+	log.Error("investigation refused", "error", drift)
+
+	setStatus(ctx, inc, incident.StatusFailed)
+	inc.FailureReason = drift.Error()
+	if p.stateStore != nil {
+		if err := p.stateStore.CompleteIncident(ctx, inc.IncidentID, inc.Status, -1, inc.FailureReason, ""); err != nil {
+			log.Error("failed to complete incident in state store", "error", err)
+		}
+	}
+	p.emitLifecycle(ctx, webhooks.EventFailed, inc, map[string]string{"failure_reason": inc.FailureReason})
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Agent usage command flags
+	agentUsageSince  time.Duration
+	agentUsageFormat string
+)
+
+var agentUsageCmd = &cobra.Command{
+	Use:   "agent-usage",
+	Short: "Show the resource usage of recent agent executions per agent version",
+	Long: `Show the CPU time, peak memory, peak process count, and disk writes of the
+agent executions recorded in the SQL state store, aggregated per agent CLI, image,
+and model.
+
+Every investigation samples its agent's resource usage from the agent container's
+cgroup (or the agent process tree when the cgroup cannot be found). Comparing the
+averages of agent images and models shows when a new agent version consumes more
+than its predecessor. Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier agent-usage
+  nightcrier agent-usage --since 720h --format json`,
+	Args: cobra.NoArgs,
+	RunE: runAgentUsage,
+}
+
+func init() {
+	agentUsageCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to config file (used to locate the state store)")
+	agentUsageCmd.Flags().DurationVar(&agentUsageSince, "since", 7*24*time.Hour, "Aggregate executions started within this period")
+	agentUsageCmd.Flags().StringVar(&agentUsageFormat, "format", "table", "Output format: table or json")
+
+	rootCmd.AddCommand(agentUsageCmd)
+}
+
+// agentResources serves the health server's /health/agents endpoint from the state
+// store.
+type agentResources struct {
+	store storage.StateStore
+}
+
+func (a agentResources) GetAgentResources(ctx context.Context, since time.Time) (interface{}, error) {
+	stats, err := a.store.AgentResourceStats(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		stats = []*storage.AgentResourceStats{}
+	}
+	return stats, nil
+}
+
+func runAgentUsage(cmd *cobra.Command, args []string) error {
+	if agentUsageFormat != "table" && agentUsageFormat != "json" {
+		return fmt.Errorf("unknown format %q: must be table or json", agentUsageFormat)
+	}
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	setupLogging("warn")
+
+	ctx := context.Background()
+	store, err := openStateStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("agent usage requires a sqlite or postgres state store (state_storage.type is %q)", cfg.GetStateStorageType())
+	}
+	defer store.Close()
+
+	stats, err := store.AgentResourceStats(ctx, time.Now().Add(-agentUsageSince))
+	if err != nil {
+		return err
+	}
+
+	if agentUsageFormat == "json" {
+		if stats == nil {
+			stats = []*storage.AgentResourceStats{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	if len(stats) == 0 {
+		fmt.Println("No agent executions with resource usage recorded")
+		return nil
+	}
+	fmt.Printf("%-8s %-40s %-24s %5s %9s %9s %9s %9s %5s %9s\n",
+		"AGENT", "IMAGE", "MODEL", "RUNS", "AVG CPU", "MAX CPU", "AVG RSS", "MAX RSS", "PROCS", "AVG DISK")
+	for _, st := range stats {
+		fmt.Printf("%-8s %-40s %-24s %5d %8.1fs %8.1fs %9s %9s %5d %9s\n",
+			truncateString(st.AgentCLI, 8),
+			truncateString(st.AgentImage, 40),
+			truncateString(st.AgentModel, 24),
+			st.Executions,
+			st.AvgCPUSeconds,
+			st.MaxCPUSeconds,
+			formatBytes(st.AvgPeakRSSBytes),
+			formatBytes(st.MaxPeakRSSBytes),
+			st.MaxPeakProcesses,
+			formatBytes(st.AvgDiskWrittenBytes))
+	}
+	return nil
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. "512.0Mi".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	value, suffix := float64(n)/unit, "Ki"
+	for _, next := range []string{"Mi", "Gi", "Ti"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	return fmt.Sprintf("%.1f%s", value, suffix)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// artifactChecksumsFile lists the exported artifacts' hashes in sha256sum format
+const artifactChecksumsFile = "SHA256SUMS"
+
+var (
+	// Artifacts export command flags
+	artifactsExportDir string
+)
+
+var artifactsCmd = &cobra.Command{
+	Use:   "artifacts",
+	Short: "Verify, export, and expire stored incident artifacts",
+	Long: `Verify and export the artifacts of an incident (report, incident.json, agent logs)
+from artifact storage.
+
+The SHA-256 hash of every artifact is recorded in the state store when it is
+uploaded. Both commands check the stored artifacts against these hashes, so
+postmortems can show that reports were not modified after the fact. Requires a
+sqlite or postgres state store.`,
+}
+
+var artifactsVerifyCmd = &cobra.Command{
+	Use:     "verify <incident-id>",
+	Short:   "Check an incident's stored artifacts against their recorded hashes",
+	Example: `  nightcrier artifacts verify 2f1c...`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runArtifactsVerify,
+}
+
+var artifactsExportCmd = &cobra.Command{
+	Use:   "export <incident-id>",
+	Short: "Download an incident's artifacts after verifying their hashes",
+	Long: `Download an incident's artifacts into a directory, together with a SHA256SUMS file
+that can be checked with "sha256sum -c". Nothing is written when any artifact
+fails verification.`,
+	Example: `  nightcrier artifacts export 2f1c... --out ./postmortem-2f1c`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runArtifactsExport,
+}
+
+func init() {
+	artifactsCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to config file (used to locate artifact storage and the state store)")
+	artifactsExportCmd.Flags().StringVarP(&artifactsExportDir, "out", "o", "", "Directory to export the artifacts to (required)")
+	_ = artifactsExportCmd.MarkFlagRequired("out")
+
+	artifactsCmd.AddCommand(artifactsVerifyCmd, artifactsExportCmd)
+	rootCmd.AddCommand(artifactsCmd)
+}
+
+// recordArtifactHashes records the hashes of an incident's stored artifacts in
+// incident.json (except incident.json's own) and in the state store.
+func (p *eventProcessor) recordArtifactHashes(ctx context.Context, inc *incident.Incident, hashes map[string]string) {
+	if len(hashes) == 0 {
+		return
+	}
+	inc.ArtifactHashes = make(map[string]string, len(hashes))
+	for name, hash := range hashes {
+		if name != "incident.json" {
+			inc.ArtifactHashes[name] = hash
+		}
+	}
+
+	if p.stateStore != nil {
+		if err := p.stateStore.RecordArtifactHashes(ctx, inc.IncidentID, hashes); err != nil {
+			incident.Logger(ctx).Error("failed to record artifact hashes in state store", "error", err)
+		}
+	}
+}
+
+// verifiedArtifact is a stored artifact whose contents match its recorded hash.
+type verifiedArtifact struct {
+	Name string
+	Hash string
+	Data []byte
+}
+
+// verifyIncidentArtifacts reads every artifact of an incident (by UUID or display
+// ID) with a recorded hash and checks it. It prints one line per artifact and fails when any artifact is
+// missing or modified.
+func verifyIncidentArtifacts(ctx context.Context, incidentID string) ([]verifiedArtifact, error) {
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	setupLogging("warn")
+
+	store, err := openStateStore(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, fmt.Errorf("artifact verification requires a sqlite or postgres state store (state_storage.type is %q)", cfg.GetStateStorageType())
+	}
+	defer store.Close()
+
+	backend, err := storage.NewStorage(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize artifact storage backend: %w", err)
+	}
+	reader, ok := backend.(storage.ArtifactReader)
+	if !ok {
+		return nil, fmt.Errorf("artifact storage backend does not support reading artifacts")
+	}
+
+	inc, err := resolveIncident(ctx, store, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := store.GetArtifactHashes(ctx, inc.IncidentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(hashes) == 0 {
+		return nil, fmt.Errorf("no artifact hashes recorded for incident %s", incidentID)
+	}
+
+	var retention storage.RetentionPolicy
+	if cfg.ArtifactRetention.Enabled {
+		retention = cfg.ArtifactRetention.Policy()
+	}
+
+	var verified []verifiedArtifact
+	failed := 0
+	for _, name := range storage.SortedArtifactNames(hashes) {
+		data, err := reader.ReadArtifact(ctx, inc.Ref(), name)
+		if err != nil && retention.Expired(name, inc.CreatedAt, time.Now()) {
+			// Deleted by artifact retention, not tampered with
+			fmt.Printf("EXPIRED  %s (%s retention)\n", name, storage.ArtifactClass(name))
+			continue
+		}
+		if err == nil {
+			err = storage.VerifyArtifact(name, data, hashes[name])
+		}
+		if err != nil {
+			fmt.Printf("FAIL  %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("OK    %s  %s\n", name, hashes[name])
+		verified = append(verified, verifiedArtifact{Name: name, Hash: hashes[name], Data: data})
+	}
+	if failed > 0 {
+		return nil, fmt.Errorf("%d of %d artifacts failed verification", failed, len(hashes))
+	}
+	return verified, nil
+}
+
+func runArtifactsVerify(cmd *cobra.Command, args []string) error {
+	verified, err := verifyIncidentArtifacts(context.Background(), args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("All %d artifacts match their recorded hashes\n", len(verified))
+	return nil
+}
+
+func runArtifactsExport(cmd *cobra.Command, args []string) error {
+	verified, err := verifyIncidentArtifacts(context.Background(), args[0])
+	if err != nil {
+		return err
+	}
+	if err := writeVerifiedArtifacts(artifactsExportDir, verified); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d verified artifacts to %s\n", len(verified), artifactsExportDir)
+	return nil
+}
+
+// writeVerifiedArtifacts writes the artifacts and their SHA256SUMS file to dir.
+func writeVerifiedArtifacts(dir string, artifacts []verifiedArtifact) error {
+	var sums strings.Builder
+	for _, artifact := range artifacts {
+		path := filepath.Join(dir, filepath.FromSlash(artifact.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return fmt.Errorf("failed to create export directory: %w", err)
+		}
+		if err := os.WriteFile(path, artifact.Data, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", artifact.Name, err)
+		}
+		fmt.Fprintf(&sums, "%s  %s\n", artifact.Hash, artifact.Name)
+	}
+	if err := os.WriteFile(filepath.Join(dir, artifactChecksumsFile), []byte(sums.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", artifactChecksumsFile, err)
+	}
+	return nil
+}
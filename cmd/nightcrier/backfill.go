@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/backfill"
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Backfill command flags
+	backfillCluster         string
+	backfillSource          string
+	backfillSince           time.Duration
+	backfillFrom            string
+	backfillTo              string
+	backfillNamespace       string
+	backfillLokiURL         string
+	backfillLokiQuery       string
+	backfillLokiOrgID       string
+	backfillAlertmanagerURL string
+	backfillMatchers        []string
+	backfillServer          string
+	backfillInterval        time.Duration
+	backfillDryRun          bool
+)
+
+// backfillRetryDelay is how long backfill waits when the daemon's event queue is
+// full and the response carries no Retry-After
+const backfillRetryDelay = 5 * time.Second
+
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Investigate the fault events of a past time range",
+	Long: `Find the fault events of a cluster in a past time range and queue them for
+investigation in the running nightcrier daemon, e.g. after nightcrier was down
+during an outage.
+
+Events are found in one of these sources:
+  mcp           The Warning events listed by the cluster's MCP server. Kubernetes
+                keeps events for an hour by default, so this only reaches back
+                that far.
+  loki          Fault event log lines stored in Loki (--loki-url, --loki-query).
+  alertmanager  The alerts in Alertmanager (--alertmanager-url, --matcher), which
+                are kept only while they fire.
+
+Repeats of the same fault are dropped and the rest are sent oldest first to the
+daemon's POST /admin/events endpoint, one every --interval so a large backfill
+does not flood the agents. The events then pass through the daemon's usual
+deduplication, aggregation, and budget checks. The endpoint requires the health
+server's auth_token (read from the configuration); it is unavailable without
+health server authentication. Use --dry-run to list the events without sending
+them.`,
+	Example: `  nightcrier backfill --cluster prod --since 1h
+  nightcrier backfill --cluster prod --source loki --loki-url http://loki:3100 \
+    --loki-query '{app="kubernetes-mcp-server"} |= "faultType"' \
+    --from 2026-01-10T02:00:00Z --to 2026-01-10T06:00:00Z
+  nightcrier backfill --cluster prod --source alertmanager \
+    --alertmanager-url http://alertmanager:9093 --matcher 'cluster="prod"' --since 6h --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runBackfill,
+}
+
+func init() {
+	backfillCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to config file (used to locate the cluster and health server)")
+	backfillCmd.Flags().StringVar(&backfillCluster, "cluster", "", "Cluster the events belong to (required)")
+	backfillCmd.Flags().StringVar(&backfillSource, "source", "mcp", "Where to find the events: mcp, loki, or alertmanager")
+	backfillCmd.Flags().DurationVar(&backfillSince, "since", time.Hour, "Backfill this period up to now (ignored with --from)")
+	backfillCmd.Flags().StringVar(&backfillFrom, "from", "", "Start of the time range (RFC 3339)")
+	backfillCmd.Flags().StringVar(&backfillTo, "to", "", "End of the time range (RFC 3339, default: now)")
+	backfillCmd.Flags().StringVar(&backfillNamespace, "namespace", "", "Only list the events of this namespace (mcp source)")
+	backfillCmd.Flags().StringVar(&backfillLokiURL, "loki-url", "", "Loki base URL (loki source)")
+	backfillCmd.Flags().StringVar(&backfillLokiQuery, "loki-query", "", "LogQL query selecting fault event log lines (loki source)")
+	backfillCmd.Flags().StringVar(&backfillLokiOrgID, "loki-org-id", "", "Loki tenant sent as X-Scope-OrgID (loki source)")
+	backfillCmd.Flags().StringVar(&backfillAlertmanagerURL, "alertmanager-url", "", "Alertmanager base URL (alertmanager source)")
+	backfillCmd.Flags().StringArrayVar(&backfillMatchers, "matcher", nil, "Alert matcher, e.g. 'cluster=\"prod\"' (alertmanager source, repeatable)")
+	backfillCmd.Flags().StringVar(&backfillServer, "server", "", "Health server URL of the running daemon (default: derived from the health server configuration)")
+	backfillCmd.Flags().DurationVar(&backfillInterval, "interval", 10*time.Second, "Delay between queued events")
+	backfillCmd.Flags().BoolVar(&backfillDryRun, "dry-run", false, "List the events without queueing them")
+	_ = backfillCmd.MarkFlagRequired("cluster")
+
+	rootCmd.AddCommand(backfillCmd)
+}
+
+// eventIngest serves the health server's /admin/events endpoint by injecting the
+// posted fault events into the connection manager's event stream.
+type eventIngest struct {
+	manager *cluster.ConnectionManager
+}
+
+func (e eventIngest) IngestEvent(body []byte) error {
+	var event events.FaultEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("invalid fault event: %w", err)
+	}
+	if event.Cluster == "" || event.FaultType == "" {
+		return fmt.Errorf("invalid fault event: cluster and faultType are required")
+	}
+	if event.Source == "" {
+		event.Source = "ingest"
+	}
+	event.ReceivedAt = time.Now()
+	if err := e.manager.Inject(event.Cluster, &event); err != nil {
+		return err
+	}
+	slog.Info("fault event ingested",
+		"cluster", event.Cluster,
+		"fault_id", event.FaultID,
+		"fault_type", event.FaultType,
+		"resource", event.GetResourceName(),
+		"timestamp", event.Timestamp)
+	return nil
+}
+
+// decodeSpilledEvent restores a fault event spilled to disk by the spill queue
+// overflow policy.
+func decodeSpilledEvent(data []byte, receivedAt time.Time) (interface{}, error) {
+	var event events.FaultEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("invalid spilled fault event: %w", err)
+	}
+	event.ReceivedAt = receivedAt
+	return &event, nil
+}
+
+func runBackfill(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	setupLogging("warn")
+
+	var clusterCfg *cluster.ClusterConfig
+	for i := range cfg.Clusters {
+		if cfg.Clusters[i].Name == backfillCluster {
+			clusterCfg = &cfg.Clusters[i]
+		}
+	}
+	if clusterCfg == nil {
+		return fmt.Errorf("cluster %q is not configured", backfillCluster)
+	}
+
+	from, to, err := backfillRange(time.Now())
+	if err != nil {
+		return err
+	}
+	source, err := backfillSourceFor(clusterCfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	found, err := source.FaultEvents(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to find fault events in %s: %w", source.Name(), err)
+	}
+	prepared := backfill.Prepare(backfillCluster, found)
+	fmt.Printf("Found %d fault events (%d after dropping repeats) in %s between %s and %s\n",
+		len(found), len(prepared), source.Name(), from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	if len(prepared) == 0 {
+		return nil
+	}
+
+	if backfillDryRun {
+		fmt.Printf("%-25s %-25s %-10s %s\n", "TIMESTAMP", "FAULT TYPE", "SEVERITY", "RESOURCE")
+		for _, event := range prepared {
+			resource := event.GetResourceName()
+			if event.Resource != nil && event.Resource.Kind != "" {
+				resource = event.Resource.Kind + "/" + resource
+			}
+			if ns := event.GetNamespace(); ns != "" {
+				resource = ns + "/" + resource
+			}
+			fmt.Printf("%-25s %-25s %-10s %s\n", event.Timestamp, truncateString(event.FaultType, 25), event.Severity, resource)
+		}
+		return nil
+	}
+
+	server := backfillServer
+	if server == "" {
+		server = defaultHealthServerURL(cfg.HealthServer)
+	}
+	client := &backfillClient{
+		url:   strings.TrimSuffix(server, "/") + "/admin/events",
+		token: cfg.HealthServer.AuthToken,
+		http:  &http.Client{Timeout: 30 * time.Second},
+	}
+
+	queued := 0
+	for i, event := range prepared {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				fmt.Printf("Interrupted: queued %d of %d events\n", queued, len(prepared))
+				return ctx.Err()
+			case <-time.After(backfillInterval):
+			}
+		}
+		if err := client.send(ctx, event); err != nil {
+			fmt.Printf("Queued %d of %d events\n", queued, len(prepared))
+			return err
+		}
+		queued++
+		fmt.Printf("[%d/%d] queued %s %s (%s)\n", queued, len(prepared), event.FaultType, event.GetResourceName(), event.Timestamp)
+	}
+	fmt.Printf("Queued %d events for investigation\n", queued)
+	return nil
+}
+
+// backfillRange returns the time range to backfill: --from to --to, or the
+// --since period up to now.
+func backfillRange(now time.Time) (time.Time, time.Time, error) {
+	to := now
+	if backfillTo != "" {
+		parsed, err := time.Parse(time.RFC3339, backfillTo)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to: %w", err)
+		}
+		to = parsed
+	}
+	from := to.Add(-backfillSince)
+	if backfillFrom != "" {
+		parsed, err := time.Parse(time.RFC3339, backfillFrom)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from: %w", err)
+		}
+		from = parsed
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid time range: %s is not before %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return from, to, nil
+}
+
+// backfillSourceFor returns the event source selected by --source.
+func backfillSourceFor(clusterCfg *cluster.ClusterConfig) (backfill.Source, error) {
+	httpClient := &http.Client{Timeout: 60 * time.Second}
+	switch backfillSource {
+	case "mcp":
+		return backfill.NewMCPSource(clusterCfg.MCP.Endpoint, backfillNamespace, httpClient), nil
+	case "loki":
+		if backfillLokiURL == "" || backfillLokiQuery == "" {
+			return nil, fmt.Errorf("the loki source requires --loki-url and --loki-query")
+		}
+		return backfill.NewLokiSource(backfill.LokiConfig{
+			URL:   strings.TrimSuffix(backfillLokiURL, "/"),
+			Query: backfillLokiQuery,
+			OrgID: backfillLokiOrgID,
+		}, httpClient), nil
+	case "alertmanager":
+		if backfillAlertmanagerURL == "" {
+			return nil, fmt.Errorf("the alertmanager source requires --alertmanager-url")
+		}
+		return backfill.NewAlertmanagerSource(backfill.AlertmanagerConfig{
+			URL:      strings.TrimSuffix(backfillAlertmanagerURL, "/"),
+			Matchers: backfillMatchers,
+		}, httpClient), nil
+	default:
+		return nil, fmt.Errorf("unknown source %q: must be mcp, loki, or alertmanager", backfillSource)
+	}
+}
+
+// defaultHealthServerURL returns the URL of the local daemon's health server on
+// the default port.
+func defaultHealthServerURL(h config.HealthServerConfig) string {
+	scheme, host := "http", h.BindAddress
+	if h.TLSEnabled() {
+		scheme = "https"
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, "8080"))
+}
+
+// backfillClient posts fault events to the daemon's event ingest endpoint.
+type backfillClient struct {
+	url   string
+	token string
+	http  *http.Client
+}
+
+// send queues one event, waiting and retrying while the daemon's event queue is
+// full.
+func (c *backfillClient) send(ctx context.Context, event *events.FaultEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fault event: %w", err)
+	}
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create event request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to queue fault event: %w", err)
+		}
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusAccepted:
+			return nil
+		case http.StatusServiceUnavailable:
+			delay := backfillRetryDelay
+			if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+				delay = time.Duration(seconds) * time.Second
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		case http.StatusNotFound:
+			return fmt.Errorf("the daemon at %s does not accept events: its health server needs auth_token or client_ca_file", c.url)
+		default:
+			return fmt.Errorf("daemon rejected fault event (status %d): %s", resp.StatusCode, strings.TrimSpace(string(message)))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+func TestBackfillClient_RetriesWhenQueueFull(t *testing.T) {
+	attempts := 0
+	var received events.FaultEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			t.Errorf("Authorization = %q, want the auth token", r.Header.Get("Authorization"))
+		}
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "event queue full", http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	client := &backfillClient{url: srv.URL, token: "s3cret", http: srv.Client()}
+	event := &events.FaultEvent{FaultID: "f1", Cluster: "prod", FaultType: "CrashLoop"}
+	if err := client.send(t.Context(), event); err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+	if attempts != 2 || received.FaultID != "f1" || received.Cluster != "prod" {
+		t.Errorf("attempts = %d, received %+v; want a retry delivering the event", attempts, received)
+	}
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "cluster not found: prod", http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+	client.url = rejecting.URL
+	if err := client.send(t.Context(), event); err == nil {
+		t.Error("send() should fail when the daemon rejects the event")
+	}
+}
+
+func TestBackfillRange(t *testing.T) {
+	defer func() { backfillFrom, backfillTo, backfillSince = "", "", time.Hour }()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	backfillSince = 2 * time.Hour
+	from, to, err := backfillRange(now)
+	if err != nil || !from.Equal(now.Add(-2*time.Hour)) || !to.Equal(now) {
+		t.Errorf("backfillRange() with --since = %v, %v, %v", from, to, err)
+	}
+
+	backfillFrom, backfillTo = "2026-01-10T02:00:00Z", "2026-01-10T06:00:00Z"
+	from, to, err = backfillRange(now)
+	if err != nil || from.Hour() != 2 || to.Hour() != 6 {
+		t.Errorf("backfillRange() with --from/--to = %v, %v, %v", from, to, err)
+	}
+
+	backfillFrom, backfillTo = "2026-01-10T06:00:00Z", "2026-01-10T02:00:00Z"
+	if _, _, err := backfillRange(now); err == nil {
+		t.Error("backfillRange() should reject a range ending before it starts")
+	}
+}
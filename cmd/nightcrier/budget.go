@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/budget"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Budget command flags
+	budgetDir                 string
+	budgetCluster             string
+	budgetDay                 string
+	budgetExtraInvestigations int
+	budgetExtraSpend          float64
+	budgetUnlimited           bool
+	budgetReason              string
+)
+
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Inspect and override daily investigation budgets",
+	Long: `Inspect and override the per-cluster daily investigation budgets.
+
+Budgets cap the number of investigations and the estimated LLM spend per cluster per
+UTC day. Overrides grant extra headroom for a single day and are picked up by the
+running nightcrier process on its next budget check; no restart is required.`,
+}
+
+var budgetStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show today's budget usage per cluster",
+	RunE:  runBudgetStatus,
+}
+
+var budgetOverrideCmd = &cobra.Command{
+	Use:   "override",
+	Short: "Grant additional budget to a cluster for one day",
+	Example: `  nightcrier budget override --cluster prod-east --investigations 20 --reason "incident storm"
+  nightcrier budget override --cluster prod-east --unlimited --reason "major outage"`,
+	RunE: runBudgetOverride,
+}
+
+func init() {
+	budgetCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to config file (used to locate the budget directory and limits)")
+	budgetCmd.PersistentFlags().StringVar(&budgetDir, "dir", "", "Budget directory (overrides budget.dir from the config file)")
+	budgetCmd.PersistentFlags().StringVar(&budgetDay, "day", "", "UTC day in YYYY-MM-DD format (default: today)")
+
+	budgetStatusCmd.Flags().StringVar(&budgetCluster, "cluster", "", "Only show this cluster")
+
+	budgetOverrideCmd.Flags().StringVar(&budgetCluster, "cluster", "", "Cluster to grant budget to (required)")
+	budgetOverrideCmd.Flags().IntVar(&budgetExtraInvestigations, "investigations", 0, "Additional investigations allowed for the day")
+	budgetOverrideCmd.Flags().Float64Var(&budgetExtraSpend, "spend", 0, "Additional estimated spend (USD) allowed for the day")
+	budgetOverrideCmd.Flags().BoolVar(&budgetUnlimited, "unlimited", false, "Lift all budget limits for the day")
+	budgetOverrideCmd.Flags().StringVar(&budgetReason, "reason", "", "Reason for the override (recorded in the overrides file)")
+	_ = budgetOverrideCmd.MarkFlagRequired("cluster")
+
+	budgetCmd.AddCommand(budgetStatusCmd, budgetOverrideCmd)
+	rootCmd.AddCommand(budgetCmd)
+}
+
+// loadBudgetContext resolves the budget directory and, when a configuration is
+// available, the configuration used to compute per-cluster limits.
+func loadBudgetContext() (string, *config.Config, error) {
+	if budgetDir != "" {
+		return budgetDir, nil, nil
+	}
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load configuration (use --dir to bypass): %w", err)
+	}
+	return cfg.Budget.Dir, cfg, nil
+}
+
+// resolveBudgetDay returns the --day flag value or today's UTC day.
+func resolveBudgetDay() (string, error) {
+	if budgetDay == "" {
+		return budget.Day(time.Now()), nil
+	}
+	if _, err := time.Parse("2006-01-02", budgetDay); err != nil {
+		return "", fmt.Errorf("invalid --day %q: must be YYYY-MM-DD", budgetDay)
+	}
+	return budgetDay, nil
+}
+
+func runBudgetStatus(cmd *cobra.Command, args []string) error {
+	dir, cfg, err := loadBudgetContext()
+	if err != nil {
+		return err
+	}
+	day, err := resolveBudgetDay()
+	if err != nil {
+		return err
+	}
+
+	usage, err := budget.LoadUsage(dir, day)
+	if err != nil {
+		return err
+	}
+	overrides, err := budget.LoadOverrides(dir)
+	if err != nil {
+		return err
+	}
+
+	// Show configured and overridden clusters even when they have no usage yet
+	clusters := make(map[string]bool)
+	for name := range usage {
+		clusters[name] = true
+	}
+	for _, ov := range overrides {
+		if ov.Day == day {
+			clusters[ov.Cluster] = true
+		}
+	}
+	if cfg != nil {
+		for _, cl := range cfg.Clusters {
+			clusters[cl.Name] = true
+		}
+	}
+	names := make([]string, 0, len(clusters))
+	for name := range clusters {
+		if budgetCluster == "" || name == budgetCluster {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	fmt.Printf("Budget usage for %s (UTC), directory %s\n\n", day, dir)
+	fmt.Printf("%-24s %16s %20s %s\n", "CLUSTER", "INVESTIGATIONS", "EST. SPEND (USD)", "OVERRIDE")
+	for _, name := range names {
+		u := budget.Usage{}
+		if cu := usage[name]; cu != nil {
+			u = *cu
+		}
+
+		investigations := fmt.Sprintf("%d", u.Investigations)
+		spend := fmt.Sprintf("%.2f", u.EstimatedSpend)
+		if cfg != nil {
+			limits := cfg.BudgetLimits(name)
+			if limits.MaxInvestigationsPerDay > 0 {
+				investigations = fmt.Sprintf("%d/%d", u.Investigations, limits.MaxInvestigationsPerDay)
+			}
+			if limits.MaxSpendPerDay > 0 {
+				spend = fmt.Sprintf("%.2f/%.2f", u.EstimatedSpend, limits.MaxSpendPerDay)
+			}
+		}
+
+		override := "-"
+		if ov := overrides.For(name, day); ov.Unlimited {
+			override = "unlimited"
+		} else if ov.ExtraInvestigations > 0 || ov.ExtraSpend > 0 {
+			override = fmt.Sprintf("+%d investigations, +$%.2f", ov.ExtraInvestigations, ov.ExtraSpend)
+		}
+
+		fmt.Printf("%-24s %16s %20s %s\n", name, investigations, spend, override)
+	}
+	return nil
+}
+
+func runBudgetOverride(cmd *cobra.Command, args []string) error {
+	if budgetExtraInvestigations <= 0 && budgetExtraSpend <= 0 && !budgetUnlimited {
+		return fmt.Errorf("specify at least one of --investigations, --spend, or --unlimited")
+	}
+
+	dir, cfg, err := loadBudgetContext()
+	if err != nil {
+		return err
+	}
+	day, err := resolveBudgetDay()
+	if err != nil {
+		return err
+	}
+
+	if cfg != nil {
+		known := false
+		for _, cl := range cfg.Clusters {
+			if cl.Name == budgetCluster {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("cluster %q is not defined in the configuration", budgetCluster)
+		}
+	}
+
+	ov := budget.Override{
+		Cluster:             budgetCluster,
+		Day:                 day,
+		ExtraInvestigations: budgetExtraInvestigations,
+		ExtraSpend:          budgetExtraSpend,
+		Unlimited:           budgetUnlimited,
+		Reason:              budgetReason,
+		CreatedAt:           time.Now().UTC(),
+	}
+	if err := budget.AddOverride(dir, ov); err != nil {
+		return fmt.Errorf("failed to record budget override: %w", err)
+	}
+
+	fmt.Printf("Budget override recorded for cluster %s on %s", budgetCluster, day)
+	if budgetUnlimited {
+		fmt.Printf(" (unlimited)\n")
+	} else {
+		fmt.Printf(" (+%d investigations, +$%.2f)\n", budgetExtraInvestigations, budgetExtraSpend)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/reporting"
+)
+
+// Pipeline stages exercised by a canary run, in order
+const (
+	canaryStageMCP     = "mcp"
+	canaryStageAgent   = "agent"
+	canaryStageStorage = "storage"
+	canaryStageNotify  = "notify"
+)
+
+// canaryFaultType marks canary incidents in incident.json and the agent prompt
+const canaryFaultType = "Canary"
+
+// canaryPrompt tells the agent that the investigation is synthetic, so it keeps it
+// short instead of searching for a problem that does not exist.
+const canaryPrompt = `## Synthetic Canary Investigation
+
+This is a scheduled canary run that validates the investigation pipeline. The
+resource below is a designated test resource and is expected to be healthy. Confirm
+its current state with one or two read-only commands, then write a brief report to
+output/investigation.md. Do not investigate further.`
+
+// canaryResult is the outcome of one canary run against a cluster.
+type canaryResult struct {
+	Cluster    string
+	IncidentID string
+	// Stage is the stage that failed; empty when the run passed
+	Stage    string
+	Err      error
+	Duration time.Duration
+}
+
+// canaryMonitor runs the canary for every cluster on a schedule and alerts when a
+// cluster's pipeline starts failing and when it recovers.
+type canaryMonitor struct {
+	processor *eventProcessor
+	states    func() []cluster.ConnectionState
+
+	mu sync.Mutex
+	// failingSince records when each failing cluster's canary started failing
+	failingSince map[string]time.Time
+}
+
+// newCanaryMonitor creates a canary monitor for the processor's clusters. states
+// reports the cluster connections checked by the MCP stage.
+func newCanaryMonitor(p *eventProcessor, states func() []cluster.ConnectionState) *canaryMonitor {
+	return &canaryMonitor{
+		processor:    p,
+		states:       states,
+		failingSince: make(map[string]time.Time),
+	}
+}
+
+// Run runs the canary for every cluster each interval until ctx is done. Clusters
+// are checked one after another so the canary never holds more than one agent slot.
+func (m *canaryMonitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, state := range m.states() {
+				if ctx.Err() != nil {
+					return
+				}
+				m.report(ctx, m.processor.runCanary(ctx, state))
+			}
+		}
+	}
+}
+
+// report logs a canary result and sends the alerts due: a failure alert when the
+// cluster's canary starts failing, a recovery alert when it passes again, and a
+// passing notification for every success when canary.notify_success is set.
+func (m *canaryMonitor) report(ctx context.Context, result canaryResult) {
+	log := slog.With("cluster", result.Cluster, "canary_run", result.IncidentID)
+	notifier := m.processor.notifier
+	notifySuccess := m.processor.cfg.Canary.NotifySuccess
+
+	m.mu.Lock()
+	failingSince, wasFailing := m.failingSince[result.Cluster]
+	if result.Err != nil && !wasFailing {
+		m.failingSince[result.Cluster] = time.Now()
+	} else if result.Err == nil {
+		delete(m.failingSince, result.Cluster)
+	}
+	m.mu.Unlock()
+
+	alert := reporting.CanaryAlert{
+		Cluster:      result.Cluster,
+		IncidentID:   result.IncidentID,
+		Passed:       result.Err == nil,
+		Recovered:    result.Err == nil && wasFailing,
+		Stage:        result.Stage,
+		Duration:     result.Duration,
+		FailingSince: failingSince,
+	}
+
+	if result.Err != nil {
+		alert.Error = result.Err.Error()
+		log.Warn("canary failed", "stage", result.Stage, "duration", result.Duration.Round(time.Second), "error", result.Err)
+		if wasFailing {
+			return // already alerted for this failure
+		}
+	} else {
+		log.Info("canary passed", "duration", result.Duration.Round(time.Second), "recovered", alert.Recovered)
+		if !alert.Recovered && !notifySuccess {
+			return
+		}
+	}
+
+	if notifier == nil {
+		return
+	}
+	if err := notifier.SendCanaryAlert(ctx, alert); err != nil {
+		// The notification stage itself is broken; there is no channel left to
+		// alert through, so the failure is only logged
+		log.Error("failed to send canary alert", "stage", canaryStageNotify, "error", err)
+	}
+}
+
+// runCanary runs one synthetic investigation against a cluster: it checks that
+// the cluster's MCP connection is active, runs the agent against the cluster's
+// canary test resource, and saves the result to storage. Clusters without a test
+// resource or without triage only have their connection checked. Canary runs are
+// not recorded in the state store and do not count against budgets or the
+// agent-failure circuit breaker.
+func (p *eventProcessor) runCanary(ctx context.Context, state cluster.ConnectionState) canaryResult {
+	start := time.Now()
+	result := canaryResult{Cluster: state.Cluster}
+	fail := func(stage string, err error) canaryResult {
+		result.Stage = stage
+		result.Err = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	// MCP: fault events can only arrive over an active connection
+	if state.Status != cluster.StatusActive {
+		err := fmt.Errorf("connection is %s", state.Status)
+		if state.LastError != "" {
+			err = fmt.Errorf("connection is %s: %s", state.Status, state.LastError)
+		}
+		return fail(canaryStageMCP, err)
+	}
+
+	target := p.cfg.CanaryTarget(state.Cluster)
+	executor, hasExecutor := p.executors[state.Cluster]
+	if !target.IsSet() || !hasExecutor || !p.triageEnabled(state.Cluster) {
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	// Agent: a trivial investigation of the healthy test resource
+	result.IncidentID = "canary-" + uuid.New().String()
+	event := &events.FaultEvent{
+		FaultID:   result.IncidentID,
+		Cluster:   state.Cluster,
+		FaultType: canaryFaultType,
+		Severity:  events.SeverityInfo,
+		Context:   "Synthetic canary investigation of a healthy test resource",
+		Timestamp: start.UTC().Format(time.RFC3339),
+		Resource: &events.ResourceInfo{
+			Kind:      target.ResourceKind,
+			Name:      target.ResourceName,
+			Namespace: target.Namespace,
+		},
+	}
+	inc := incident.NewFromEvent(result.IncidentID, event)
+	ctx = incident.WithContext(ctx, incident.NewIncidentContext(inc))
+
+	workspacePath, err := p.workspaceMgr.Create(result.IncidentID, p.cfg.WorkspaceTemplates(inc.Cluster)...)
+	if err != nil {
+		return fail(canaryStageAgent, fmt.Errorf("failed to create workspace: %w", err))
+	}
+	if err := inc.WriteToFile(filepath.Join(workspacePath, "incident.json")); err != nil {
+		return fail(canaryStageAgent, fmt.Errorf("failed to write incident context: %w", err))
+	}
+	facts := incident.BuildFacts(inc, event, start).Markdown() + "\n" + canaryPrompt
+
+	release, err := p.agentLimiter.Acquire(ctx, false)
+	if err != nil {
+		return fail(canaryStageAgent, fmt.Errorf("failed to acquire agent slot: %w", err))
+	}
+	exitCode, logPaths, execErr := executor.ExecuteWithFacts(ctx, workspacePath, result.IncidentID, facts)
+	release()
+	if failed, reason := detectAgentFailure(workspacePath, exitCode, execErr, p.tuning.Current()); failed {
+		return fail(canaryStageAgent, fmt.Errorf("%s", reason))
+	}
+	inc.MarkCompleted(exitCode, nil)
+	if err := inc.WriteToFile(filepath.Join(workspacePath, "incident.json")); err != nil {
+		return fail(canaryStageAgent, fmt.Errorf("failed to update incident context: %w", err))
+	}
+
+	// Storage: the report must be readable and saved like a real investigation's
+	if p.storageBackend != nil {
+		artifacts, err := readIncidentArtifacts(workspacePath, result.IncidentID, logPaths, p.markdownRenderer)
+		if err != nil {
+			return fail(canaryStageStorage, err)
+		}
+		if _, err := p.storageBackend.SaveIncident(ctx, result.IncidentID, artifacts); err != nil {
+			return fail(canaryStageStorage, err)
+		}
+	}
+
+	// Passing runs leave nothing to debug; failed runs keep their workspace
+	if err := os.RemoveAll(workspacePath); err != nil {
+		incident.Logger(ctx).Warn("failed to remove canary workspace", "path", workspacePath, "error", err)
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// triageEnabled reports whether agent investigations are enabled for a cluster.
+// Serve-only clusters never run agents, and no agents run while triage is paused.
+func (p *eventProcessor) triageEnabled(clusterName string) bool {
+	for _, cl := range p.cfg.Clusters {
+		if cl.Name == clusterName {
+			return cl.Triage.Enabled && !cl.ServeOnly && p.triagePaused(clusterName) == nil
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/reporting"
+)
+
+// canaryRecorder records canary alerts; other notifications are not expected.
+type canaryRecorder struct {
+	reporting.Notifier
+	alerts []reporting.CanaryAlert
+}
+
+func (r *canaryRecorder) SendCanaryAlert(ctx context.Context, alert reporting.CanaryAlert) error {
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func TestCanaryMonitor_AlertsOnTransitions(t *testing.T) {
+	rec := &canaryRecorder{}
+	m := newCanaryMonitor(&eventProcessor{notifier: rec, cfg: &config.Config{}}, nil)
+	ctx := context.Background()
+
+	m.report(ctx, canaryResult{Cluster: "prod"})
+	if len(rec.alerts) != 0 {
+		t.Fatalf("passing run sent %d alerts, want none without notify_success", len(rec.alerts))
+	}
+
+	failure := canaryResult{Cluster: "prod", Stage: canaryStageAgent, Err: errors.New("investigation.md file not found")}
+	m.report(ctx, failure)
+	m.report(ctx, failure)
+	if len(rec.alerts) != 1 || rec.alerts[0].Passed || rec.alerts[0].Stage != canaryStageAgent {
+		t.Fatalf("alerts = %+v, want one agent-stage failure alert", rec.alerts)
+	}
+
+	m.report(ctx, canaryResult{Cluster: "prod"})
+	if len(rec.alerts) != 2 || !rec.alerts[1].Recovered || rec.alerts[1].FailingSince.IsZero() {
+		t.Fatalf("alerts = %+v, want a recovery alert", rec.alerts)
+	}
+
+	m.processor.cfg.Canary.NotifySuccess = true
+	m.report(ctx, canaryResult{Cluster: "prod"})
+	if len(rec.alerts) != 3 || !rec.alerts[2].Passed || rec.alerts[2].Recovered {
+		t.Errorf("alerts = %+v, want a passing notification with notify_success", rec.alerts)
+	}
+}
+
+func TestRunCanary_ConnectionStage(t *testing.T) {
+	p := &eventProcessor{cfg: &config.Config{}}
+
+	result := p.runCanary(context.Background(), cluster.ConnectionState{Cluster: "prod", Status: cluster.StatusFailed, LastError: "connection refused"})
+	if result.Stage != canaryStageMCP || result.Err == nil {
+		t.Errorf("runCanary() = %+v, want an mcp-stage failure", result)
+	}
+
+	// Without a test resource only the connection is checked
+	result = p.runCanary(context.Background(), cluster.ConnectionState{Cluster: "prod", Status: cluster.StatusActive})
+	if result.Err != nil || result.IncidentID != "" {
+		t.Errorf("runCanary() = %+v, want a connection-only pass", result)
+	}
+}
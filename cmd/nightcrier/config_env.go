@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Config env command flags
+	configEnvFormat string
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect nightcrier's configuration surface",
+}
+
+var configEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "List every supported environment variable",
+	Long: `List every environment variable nightcrier reads, with the config file key it
+sets, its type, its default, and its description.
+
+The list is generated from the same bindings and struct tags the configuration
+loader uses, so deployment tooling (Helm values, Kustomize overlays, ConfigMap
+templates) can be kept in sync with the code instead of being maintained by hand.
+Credentials are flagged as secrets so they can be routed to a Secret.
+
+Formats: markdown (a table for documentation), json (for tooling).`,
+	Example: `  nightcrier config env
+  nightcrier config env --format json | jq -r '.[] | select(.secret) | .name'`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEnv,
+}
+
+func init() {
+	configEnvCmd.Flags().StringVar(&configEnvFormat, "format", "markdown", "Output format: markdown or json")
+
+	configCmd.AddCommand(configEnvCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfigEnv(cmd *cobra.Command, args []string) error {
+	vars := config.EnvVars()
+	out := cmd.OutOrStdout()
+
+	switch configEnvFormat {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(vars); err != nil {
+			return fmt.Errorf("failed to encode environment variables: %w", err)
+		}
+		return nil
+	case "markdown", "md":
+		writeEnvMarkdown(out, vars)
+		return nil
+	}
+	return fmt.Errorf("unknown format %q: must be markdown or json", configEnvFormat)
+}
+
+// writeEnvMarkdown writes the environment variables as a Markdown table.
+func writeEnvMarkdown(w io.Writer, vars []config.EnvVar) {
+	fmt.Fprintln(w, "| Variable | Config key | Type | Default | Secret | Description |")
+	fmt.Fprintln(w, "|---|---|---|---|---|---|")
+	for _, v := range vars {
+		secret := ""
+		if v.Secret {
+			secret = "yes"
+		}
+		fmt.Fprintf(w, "| `%s` | `%s` | %s | %s | %s | %s |\n",
+			v.Name, v.Key, markdownCell(v.Type), markdownCell(v.Default), secret, markdownCell(v.Description))
+	}
+}
+
+// markdownCell escapes a value for a Markdown table cell.
+func markdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
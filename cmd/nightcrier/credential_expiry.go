@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/credexpiry"
+	"github.com/rbias/nightcrier/internal/reporting"
+)
+
+// startCredentialExpiry starts checking the credentials of each triage-enabled
+// cluster's kubeconfig. A cluster whose credentials start expiring or expire is
+// alerted through the notifiers, and again when they are renewed. Kubeconfigs that
+// cannot be read are only logged and reported on /health/credentials, since the
+// permission validation already fails for them. Returns nil when the check is
+// disabled or no cluster has a triage kubeconfig.
+func startCredentialExpiry(ctx context.Context, cfg *config.Config, notifier reporting.Notifier) *credexpiry.Checker {
+	if !cfg.CredentialExpiry.Enabled() {
+		return nil
+	}
+	kubeconfigs := make(map[string]string)
+	for _, cl := range cfg.Clusters {
+		if cl.Triage.Enabled && cl.Triage.Kubeconfig != "" {
+			kubeconfigs[cl.Name] = cl.Triage.Kubeconfig
+		}
+	}
+	if len(kubeconfigs) == 0 {
+		return nil
+	}
+
+	checker := credexpiry.NewChecker(kubeconfigs, cfg.CredentialExpiry.WarnBefore())
+	checker.OnChange = func(ctx context.Context, previous string, status credexpiry.Status) {
+		alert, ok := credentialExpiryAlert(previous, status)
+		if !ok || notifier == nil {
+			return
+		}
+		if err := notifier.SendCredentialExpiryAlert(ctx, alert); err != nil {
+			slog.Error("failed to send credential expiry alert",
+				"cluster", status.Cluster,
+				"error", err)
+		}
+	}
+	go checker.Run(ctx, cfg.CredentialExpiry.CheckInterval())
+	slog.Info("credential expiry check enabled",
+		"clusters", len(kubeconfigs),
+		"warn_days", cfg.CredentialExpiry.WarnDays,
+		"check_interval_minutes", cfg.CredentialExpiry.CheckIntervalMinutes)
+	return checker
+}
+
+// credentialExpiryAlert returns the alert for a cluster's credential state change:
+// expiring or expired credentials, or credentials renewed after either. Other
+// changes (to or from an unreadable kubeconfig, the first check of valid
+// credentials) are not alerted.
+func credentialExpiryAlert(previous string, status credexpiry.Status) (reporting.CredentialExpiryAlert, bool) {
+	alert := reporting.CredentialExpiryAlert{
+		Cluster:    status.Cluster,
+		Kubeconfig: status.Kubeconfig,
+	}
+	if c := status.Expiring; c != nil {
+		alert.User = c.User
+		alert.Kind = c.Kind
+		alert.Subject = c.Subject
+		alert.ExpiresAt = *c.ExpiresAt
+	}
+	switch status.State {
+	case credexpiry.StateExpiring:
+		alert.State = reporting.CredentialsExpiring
+	case credexpiry.StateExpired:
+		alert.State = reporting.CredentialsExpired
+	case credexpiry.StateOK:
+		if previous != credexpiry.StateExpiring && previous != credexpiry.StateExpired {
+			return alert, false
+		}
+		alert.State = reporting.CredentialsRenewed
+	default:
+		return alert, false
+	}
+	return alert, true
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/rbias/nightcrier/internal/storage/postgres"
+	"github.com/rbias/nightcrier/internal/storage/sqlite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Database backup command flags
+	dbBackupOut  string
+	dbRestoreYes bool
+
+	// Database maintain command flags
+	dbMaintainVacuum bool
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Back up, restore, and maintain the state store",
+	Long: `Back up and restore the SQL state store (incidents, agent executions, reports,
+labels, holds).
+
+SQLite databases are backed up online with VACUUM INTO, which copies a consistent
+snapshot while nightcrier keeps running. PostgreSQL databases are backed up with
+pg_dump and restored with pg_restore, which must be installed.
+
+nightcrier also backs up the state store automatically before applying schema
+migrations to a database that holds data (see state_storage.skip_migration_backup).
+SQLite databases can be maintained in the background (see sqlite_maintenance) or
+with "nightcrier db maintain".`,
+}
+
+var dbBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the state store",
+	Example: `  nightcrier db backup
+  nightcrier db backup --out /mnt/backups/nightcrier-before-upgrade.db`,
+	Args: cobra.NoArgs,
+	RunE: runDBBackup,
+}
+
+var dbRestoreCmd = &cobra.Command{
+	Use:   "restore <backup-file>",
+	Short: "Replace the state store with a backup",
+	Long: `Replace the state store with a backup taken by "nightcrier db backup" or before a
+migration. Stop nightcrier first. A SQLite backup is integrity-checked before it
+replaces the database, and the replaced database is kept next to it.`,
+	Example: `  nightcrier db restore ./incidents/backups/nightcrier-pre-migration-v10-to-v11-20260114T101500Z.db --yes`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runDBRestore,
+}
+
+var dbMaintainCmd = &cobra.Command{
+	Use:   "maintain",
+	Short: "Checkpoint, vacuum, and report the size of a SQLite state store",
+	Long: `Run the SQLite maintenance that sqlite_maintenance runs in the background: copy
+the write-ahead log into the database and truncate it, return unused pages to the
+filesystem with an incremental vacuum, and refresh the query planner statistics.
+The database and WAL sizes are reported before and after.
+
+Databases created by older versions do not support incremental vacuuming. --vacuum
+rebuilds the database once, reclaiming every unused page and enabling incremental
+vacuuming from then on. It blocks writers while it runs (stop nightcrier for large
+databases) and needs free disk space for a copy of the database.
+
+PostgreSQL reclaims space with autovacuum and needs no maintenance command.`,
+	Example: `  nightcrier db maintain
+  nightcrier db maintain --vacuum`,
+	Args: cobra.NoArgs,
+	RunE: runDBMaintain,
+}
+
+func init() {
+	dbCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to config file (used to locate the state store)")
+	dbBackupCmd.Flags().StringVarP(&dbBackupOut, "out", "o", "", "Backup file (default: a timestamped file in state_storage.backup_dir)")
+	dbRestoreCmd.Flags().BoolVar(&dbRestoreYes, "yes", false, "Confirm replacing the state store (required)")
+	dbMaintainCmd.Flags().BoolVar(&dbMaintainVacuum, "vacuum", false, "Rebuild the database, reclaiming all unused space and enabling incremental vacuuming")
+
+	dbCmd.AddCommand(dbBackupCmd, dbRestoreCmd, dbMaintainCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+// loadDBConfig loads the configuration and checks that it uses a SQL state store.
+func loadDBConfig() (*config.Config, error) {
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	setupLogging("warn")
+	if t := cfg.GetStateStorageType(); t != "sqlite" && t != "postgres" {
+		return nil, fmt.Errorf("the db commands require a sqlite or postgres state store (state_storage.type is %q)", t)
+	}
+	return cfg, nil
+}
+
+func runDBBackup(cmd *cobra.Command, args []string) error {
+	cfg, err := loadDBConfig()
+	if err != nil {
+		return err
+	}
+	dest := dbBackupOut
+	if dest == "" {
+		dest = backupPath(cfg, "nightcrier")
+	}
+	if err := backupStateStore(context.Background(), cfg, dest); err != nil {
+		return err
+	}
+	fmt.Printf("Backed up the %s state store to %s\n", cfg.GetStateStorageType(), dest)
+	return nil
+}
+
+func runDBRestore(cmd *cobra.Command, args []string) error {
+	cfg, err := loadDBConfig()
+	if err != nil {
+		return err
+	}
+	if !dbRestoreYes {
+		return fmt.Errorf("restoring replaces the state store; stop nightcrier and rerun with --yes")
+	}
+
+	ctx := context.Background()
+	switch cfg.GetStateStorageType() {
+	case "sqlite":
+		previous, err := sqlite.Restore(ctx, args[0], cfg.StateStorage.SQLitePath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Restored %s from %s\n", cfg.StateStorage.SQLitePath, args[0])
+		if previous != "" {
+			fmt.Printf("The replaced database was saved to %s\n", previous)
+		}
+	case "postgres":
+		if err := postgres.RestoreDump(ctx, postgresConnString(cfg), args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Restored the PostgreSQL state store from %s\n", args[0])
+	}
+	return nil
+}
+
+func runDBMaintain(cmd *cobra.Command, args []string) error {
+	cfg, err := loadDBConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.GetStateStorageType() != "sqlite" {
+		return fmt.Errorf("db maintain supports sqlite state stores; PostgreSQL reclaims space with autovacuum")
+	}
+
+	ctx := context.Background()
+	stateStore, err := openStateStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer stateStore.Close()
+	store := stateStore.(*sqlite.Store)
+
+	result, err := maintainSQLite(ctx, store, 0)
+	if err != nil {
+		return err
+	}
+	printSQLiteStats("Before", result.Before)
+	if result.Checkpoint.Busy {
+		fmt.Printf("Checkpointed %d of %d WAL frames; readers kept the WAL from being truncated\n",
+			result.Checkpoint.CheckpointedFrames, result.Checkpoint.WALFrames)
+	} else {
+		fmt.Printf("Checkpointed %d WAL frames and truncated the WAL\n", result.Checkpoint.CheckpointedFrames)
+	}
+	if result.FreedPages > 0 {
+		fmt.Printf("Freed %d unused pages (%s)\n", result.FreedPages, formatBytes(result.FreedPages*result.Before.PageSize))
+	}
+
+	after := result.After
+	if dbMaintainVacuum {
+		if err := store.Vacuum(ctx); err != nil {
+			return err
+		}
+		if after, err = store.Stats(ctx); err != nil {
+			return err
+		}
+		fmt.Println("Rebuilt the database with incremental vacuuming enabled")
+	}
+	printSQLiteStats("After", after)
+	if after.AutoVacuum != sqlite.AutoVacuumIncremental && after.FreePages > 0 {
+		fmt.Printf("%s of unused space can only be reclaimed by rebuilding the database: rerun with --vacuum\n", formatBytes(after.FreeBytes()))
+	}
+	return nil
+}
+
+// printSQLiteStats prints the sizes of a SQLite database.
+func printSQLiteStats(label string, stats *sqlite.Stats) {
+	fmt.Printf("%-7s database %s (%s unused), WAL %s, auto-vacuum %s\n",
+		label+":", formatBytes(stats.DatabaseBytes), formatBytes(stats.FreeBytes()), formatBytes(stats.WALBytes), stats.AutoVacuum)
+}
+
+// backupPath returns a timestamped backup file in the backup directory, with the
+// extension of the state store's backup format.
+func backupPath(cfg *config.Config, name string) string {
+	ext := ".db"
+	if cfg.GetStateStorageType() == "postgres" {
+		ext = ".dump"
+	}
+	return filepath.Join(cfg.StateStorage.BackupDir, fmt.Sprintf("%s-%s%s", name, time.Now().UTC().Format("20060102T150405Z"), ext))
+}
+
+// backupStateStore backs up the SQL state store to dest.
+func backupStateStore(ctx context.Context, cfg *config.Config, dest string) error {
+	switch cfg.GetStateStorageType() {
+	case "sqlite":
+		return sqlite.Backup(ctx, cfg.StateStorage.SQLitePath, dest)
+	case "postgres":
+		return postgres.Dump(ctx, postgresConnString(cfg), dest)
+	}
+	return fmt.Errorf("unsupported state storage type %q", cfg.GetStateStorageType())
+}
+
+// backupBeforeMigration backs up the state store when schema migrations are
+// pending on a database that already holds data, so a failed or destructive
+// migration cannot silently lose incident history. A failed backup stops startup
+// before the schema is touched unless state_storage.skip_migration_backup is set.
+func backupBeforeMigration(ctx context.Context, cfg *config.Config, migrationCfg *storage.MigrationConfig) error {
+	if cfg.StateStorage.SkipMigrationBackup {
+		return nil
+	}
+	// A SQLite database that does not exist yet has nothing to lose (and checking
+	// its version would create it)
+	if migrationCfg.DatabaseType == "sqlite" {
+		if _, err := os.Stat(migrationCfg.DatabasePath); os.IsNotExist(err) {
+			return nil
+		}
+	}
+
+	current, latest, err := storage.PendingMigrations(migrationCfg)
+	if err != nil {
+		return fmt.Errorf("failed to check pending migrations: %w", err)
+	}
+	if current == 0 || latest <= current {
+		return nil
+	}
+
+	dest := backupPath(cfg, fmt.Sprintf("nightcrier-pre-migration-v%d-to-v%d", current, latest))
+	slog.Info("backing up state store before schema migration",
+		"from_version", current,
+		"to_version", latest,
+		"backup", dest)
+	if err := backupStateStore(ctx, cfg, dest); err != nil {
+		return fmt.Errorf("failed to back up the state store before migrating from version %d to %d (set STATE_STORAGE_SKIP_MIGRATION_BACKUP=true to migrate without a backup): %w", current, latest, err)
+	}
+	slog.Info("state store backed up before schema migration", "backup", dest)
+	return nil
+}
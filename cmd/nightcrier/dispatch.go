@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// dispatchWaiters is how many events of a class may be in flight beyond its agent
+// slots: waiting for an agent slot or finishing their upload and notification.
+const dispatchWaiters = 10
+
+// processFunc processes one fault event (eventProcessor.processEvent).
+type processFunc func(ctx context.Context, clusterName string, event *events.FaultEvent, permissions *cluster.ClusterPermissions) error
+
+// dispatchedEvent is a fault event waiting for a dispatch slot.
+type dispatchedEvent struct {
+	clusterName string
+	event       *events.FaultEvent
+	permissions *cluster.ClusterPermissions
+}
+
+// eventDispatcher processes fault events concurrently, with separate bounds for
+// priority events (at or above reserved_severity) and the others, so a flood of
+// low-severity events never keeps a priority one from reaching its reserved agent
+// slot.
+//
+// Priority events may have reserved_agent_slots plus dispatchWaiters in flight,
+// other events max_concurrent_agents plus dispatchWaiters. Other events beyond
+// their bound wait in order in a backlog, which a single goroutine drains as
+// their slots free up; only when the backlog is full does Dispatch block the
+// event loop, so the rest builds up in the event channel, where the queue
+// overflow policy and the queue depth alerts apply to it. Events are classified
+// by the severity they report; the severity policy may still raise it.
+type eventDispatcher struct {
+	process          processFunc
+	reservedSeverity string
+	prioritySlots    chan struct{}
+	normalSlots      chan struct{}
+	backlog          chan dispatchedEvent
+	drained          chan struct{}
+	inFlight         sync.WaitGroup
+}
+
+// newEventDispatcher creates a dispatcher and starts draining its backlog of
+// backlogSize events until Wait is called.
+func newEventDispatcher(ctx context.Context, process processFunc, maxAgents, reservedSlots, backlogSize int, reservedSeverity string) *eventDispatcher {
+	d := &eventDispatcher{
+		process:          process,
+		reservedSeverity: reservedSeverity,
+		prioritySlots:    make(chan struct{}, reservedSlots+dispatchWaiters),
+		normalSlots:      make(chan struct{}, maxAgents+dispatchWaiters),
+		backlog:          make(chan dispatchedEvent, backlogSize),
+		drained:          make(chan struct{}),
+	}
+	go d.drain(ctx)
+	return d
+}
+
+// Dispatch processes a fault event in its own goroutine. A priority event waits
+// only for a priority dispatch slot; another event is queued in the backlog.
+func (d *eventDispatcher) Dispatch(ctx context.Context, clusterName string, faultEvent *events.FaultEvent, permissions *cluster.ClusterPermissions) {
+	e := dispatchedEvent{clusterName: clusterName, event: faultEvent, permissions: permissions}
+	if events.MeetsSeverity(faultEvent.GetSeverity(), d.reservedSeverity) {
+		d.start(ctx, d.prioritySlots, e)
+		return
+	}
+	select {
+	case d.backlog <- e:
+	case <-ctx.Done():
+		logNotProcessed(e)
+	}
+}
+
+// Wait stops accepting events and waits until the backlog is drained and every
+// dispatched event was processed. Dispatch must not be called after Wait.
+func (d *eventDispatcher) Wait() {
+	close(d.backlog)
+	<-d.drained
+	d.inFlight.Wait()
+}
+
+// drain starts the backlogged events in order as their dispatch slots free up.
+func (d *eventDispatcher) drain(ctx context.Context) {
+	defer close(d.drained)
+	for e := range d.backlog {
+		d.start(ctx, d.normalSlots, e)
+	}
+}
+
+// start takes a dispatch slot and processes the event in a goroutine that gives
+// the slot back when it is done.
+func (d *eventDispatcher) start(ctx context.Context, slots chan struct{}, e dispatchedEvent) {
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		logNotProcessed(e)
+		return
+	}
+	d.inFlight.Add(1)
+	go func() {
+		defer d.inFlight.Done()
+		defer func() { <-slots }()
+		if err := d.process(ctx, e.clusterName, e.event, e.permissions); err != nil {
+			slog.Error("failed to process event",
+				"cluster", e.clusterName,
+				"fault_id", e.event.FaultID,
+				"error", err)
+		}
+	}()
+}
+
+// logNotProcessed logs an event left unprocessed at shutdown.
+func logNotProcessed(e dispatchedEvent) {
+	slog.Warn("shutting down, event not processed",
+		"cluster", e.clusterName,
+		"fault_id", e.event.FaultID)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/agent"
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// TestEventDispatcher_CriticalStartsDuringFlood floods the dispatcher with more
+// low-severity events than it has dispatch slots and checks that a CRITICAL event
+// still gets its reserved agent slot right away.
+func TestEventDispatcher_CriticalStartsDuringFlood(t *testing.T) {
+	const maxAgents, reserved, backlog = 2, 1, 5
+	limiter, err := agent.NewConcurrencyLimiter(maxAgents, reserved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	finish := make(chan struct{})
+	criticalStarted := make(chan struct{})
+	process := func(ctx context.Context, clusterName string, event *events.FaultEvent, permissions *cluster.ClusterPermissions) error {
+		priority := events.MeetsSeverity(event.Severity, "CRITICAL")
+		release, err := limiter.Acquire(ctx, priority)
+		if err != nil {
+			return err
+		}
+		defer release()
+		if priority {
+			close(criticalStarted)
+			return nil
+		}
+		<-finish
+		return nil
+	}
+	d := newEventDispatcher(ctx, process, maxAgents, reserved, backlog, "CRITICAL")
+
+	// Fill every normal dispatch slot and the backlog; none of this blocks
+	flooded := make(chan struct{})
+	go func() {
+		defer close(flooded)
+		for i := 0; i < maxAgents+dispatchWaiters+backlog; i++ {
+			d.Dispatch(ctx, "prod", &events.FaultEvent{FaultID: fmt.Sprintf("low-%d", i), Severity: "INFO"}, nil)
+		}
+	}()
+	select {
+	case <-flooded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispatching the low-severity flood blocked")
+	}
+
+	d.Dispatch(ctx, "prod", &events.FaultEvent{FaultID: "critical", Severity: "CRITICAL"}, nil)
+	select {
+	case <-criticalStarted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the CRITICAL event did not start while the low-severity flood waited")
+	}
+	if stats := limiter.Stats(); stats.WaitingNormal == 0 {
+		t.Errorf("limiter stats = %+v, want low-severity events still waiting", stats)
+	}
+
+	close(finish)
+	d.Wait()
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Doctor check outcomes
+const (
+	doctorPass = "PASS"
+	doctorWarn = "WARN"
+	doctorFail = "FAIL"
+	doctorSkip = "SKIP"
+)
+
+// doctorCheck is the outcome of one doctor check.
+type doctorCheck struct {
+	Name   string
+	Status string
+	Detail string
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the configuration and host for problems",
+	Long: `Check that the configuration loads, the agent can be started, the agent runs
+with least privilege (agent_hardening) and confined network access (agent_egress),
+and which settings need internet egress (offline).
+
+Each check reports PASS, WARN, FAIL, or SKIP. The command exits non-zero when any
+check fails; warnings point at settings that work but weaken isolation.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to config file")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	var checks []doctorCheck
+
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		checks = append(checks, doctorCheck{"configuration", doctorFail, err.Error()})
+	} else {
+		checks = append(checks, doctorCheck{"configuration", doctorPass, "loaded and valid"})
+		checks = append(checks,
+			checkAgentScript(cfg.AgentScriptPath),
+			checkDocker(),
+			checkWorkspaceRoot(cfg.WorkspaceRoot),
+			checkWorkspaceScratchDir(cfg.WorkspaceScratchDir),
+			checkAgentHardening(cfg.AgentHardening),
+			checkAgentEgress(cfg.AgentEgress, dockerNetworkInternal),
+			checkOffline(cfg))
+	}
+	checks = append(checks, checkProcessUser(os.Geteuid()), checkProcessCapabilities("/proc/self/status"))
+
+	failed := 0
+	for _, check := range checks {
+		fmt.Printf("%-4s  %-22s %s\n", check.Status, check.Name, check.Detail)
+		if check.Status == doctorFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d doctor check(s) failed", failed)
+	}
+	return nil
+}
+
+// checkAgentScript checks that the agent script exists.
+func checkAgentScript(path string) doctorCheck {
+	info, err := os.Stat(path)
+	if err != nil {
+		return doctorCheck{"agent script", doctorFail, err.Error()}
+	}
+	if info.IsDir() {
+		return doctorCheck{"agent script", doctorFail, path + " is a directory"}
+	}
+	return doctorCheck{"agent script", doctorPass, path}
+}
+
+// checkDocker checks that the docker CLI used by the agent script is installed.
+func checkDocker() doctorCheck {
+	path, err := exec.LookPath("docker")
+	if err != nil {
+		return doctorCheck{"docker", doctorFail, "docker CLI not found in PATH"}
+	}
+	return doctorCheck{"docker", doctorPass, path}
+}
+
+// checkWorkspaceRoot checks that incident workspaces can be created.
+func checkWorkspaceRoot(root string) doctorCheck {
+	return checkWritableDir("workspace root", root)
+}
+
+// checkWorkspaceScratchDir checks that active workspaces can be created in the
+// scratch directory (workspace_scratch_dir) when one is configured.
+func checkWorkspaceScratchDir(dir string) doctorCheck {
+	if dir == "" {
+		return doctorCheck{"workspace scratch dir", doctorPass, "not configured (workspaces are created under the workspace root)"}
+	}
+	return checkWritableDir("workspace scratch dir", dir)
+}
+
+// checkWritableDir checks that dir exists (creating it if needed) and is writable.
+func checkWritableDir(name, dir string) doctorCheck {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return doctorCheck{name, doctorFail, err.Error()}
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return doctorCheck{name, doctorFail, fmt.Sprintf("%s is not writable: %v", dir, err)}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return doctorCheck{name, doctorPass, dir + " is writable"}
+}
+
+// checkAgentHardening reports the least-privilege settings the agent container
+// runs with.
+func checkAgentHardening(h config.AgentHardeningConfig) doctorCheck {
+	if !h.Enabled {
+		return doctorCheck{"agent hardening", doctorWarn,
+			"disabled: the agent runs as the image user with default capabilities (set agent_hardening.enabled)"}
+	}
+	detail := fmt.Sprintf("user %s, all capabilities dropped, no-new-privileges", h.User)
+	if h.AllowWritableRoot {
+		return doctorCheck{"agent hardening", doctorWarn, detail + ", root filesystem writable (allow_writable_root)"}
+	}
+	return doctorCheck{"agent hardening", doctorPass,
+		fmt.Sprintf("%s, read-only root filesystem (tmpfs: %s)", detail, strings.Join(h.TmpfsPaths, ", "))}
+}
+
+// checkAgentEgress checks that the agent container's network access is confined:
+// the egress network must exist and have no route to the outside, or the agent
+// could bypass the proxy.
+func checkAgentEgress(e config.AgentEgressConfig, internal func(network string) (bool, error)) doctorCheck {
+	if !e.Enabled {
+		return doctorCheck{"agent egress", doctorWarn,
+			"disabled: the agent uses the host network with unrestricted egress (set agent_egress.enabled)"}
+	}
+	isInternal, err := internal(e.Network)
+	if err != nil {
+		return doctorCheck{"agent egress", doctorFail, fmt.Sprintf("network %s: %v", e.Network, err)}
+	}
+	if !isInternal {
+		return doctorCheck{"agent egress", doctorWarn,
+			fmt.Sprintf("network %s is not internal: the agent can bypass proxy %s (docker network create --internal)", e.Network, e.ProxyURL)}
+	}
+	return doctorCheck{"agent egress", doctorPass, fmt.Sprintf("internal network %s, proxy %s", e.Network, e.ProxyURL)}
+}
+
+// dockerNetworkInternal reports whether a Docker network is internal (has no
+// route to the outside).
+func dockerNetworkInternal(network string) (bool, error) {
+	out, err := exec.Command("docker", "network", "inspect", "--format", "{{.Internal}}", network).Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect docker network: %w", err)
+	}
+	return strings.TrimSpace(string(out)) == "true", nil
+}
+
+// checkOffline reports the settings that need internet egress. With offline mode
+// enabled they fail configuration loading, so this only lists them when it is off.
+func checkOffline(cfg *config.Config) doctorCheck {
+	if cfg.Offline.Enabled {
+		return doctorCheck{"offline mode", doctorPass, "enabled; no settings require internet egress"}
+	}
+	egress := cfg.EgressRequirements()
+	if len(egress) == 0 {
+		return doctorCheck{"offline mode", doctorSkip, "disabled; no settings require internet egress"}
+	}
+	return doctorCheck{"offline mode", doctorSkip, "disabled; requires egress: " + strings.Join(egress, "; ")}
+}
+
+// checkProcessUser warns when nightcrier itself runs as root; it only needs to
+// start the agent container and write workspaces.
+func checkProcessUser(euid int) doctorCheck {
+	if euid == 0 {
+		return doctorCheck{"nightcrier user", doctorWarn, "running as root; run nightcrier as a dedicated unprivileged user"}
+	}
+	return doctorCheck{"nightcrier user", doctorPass, fmt.Sprintf("uid %d", euid)}
+}
+
+// checkProcessCapabilities reports the effective and ambient capabilities of the
+// nightcrier process from a /proc status file. Ambient capabilities are inherited
+// by the agent script and everything it runs.
+func checkProcessCapabilities(statusPath string) doctorCheck {
+	data, err := os.ReadFile(statusPath)
+	if err != nil {
+		return doctorCheck{"capabilities", doctorSkip, "process capabilities not available on this platform"}
+	}
+	effective, ambient, err := parseCapabilities(string(data))
+	if err != nil {
+		return doctorCheck{"capabilities", doctorSkip, err.Error()}
+	}
+	switch {
+	case ambient != 0:
+		return doctorCheck{"capabilities", doctorWarn, fmt.Sprintf("ambient capabilities %#x are inherited by the agent; drop them", ambient)}
+	case effective != 0:
+		return doctorCheck{"capabilities", doctorWarn, fmt.Sprintf("effective capabilities %#x; nightcrier needs none", effective)}
+	}
+	return doctorCheck{"capabilities", doctorPass, "no effective or ambient capabilities"}
+}
+
+// parseCapabilities returns the CapEff and CapAmb masks of a /proc/<pid>/status
+// file. Kernels without ambient capabilities report none.
+func parseCapabilities(status string) (effective, ambient uint64, err error) {
+	foundEffective := false
+	scanner := bufio.NewScanner(strings.NewReader(status))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || (key != "CapEff" && key != "CapAmb") {
+			continue
+		}
+		mask, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to parse %s: %w", key, err)
+		}
+		if key == "CapEff" {
+			effective, foundEffective = mask, true
+		} else {
+			ambient = mask
+		}
+	}
+	if !foundEffective {
+		return 0, 0, fmt.Errorf("CapEff not found in process status")
+	}
+	return effective, ambient, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rbias/nightcrier/internal/config"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	status := "Name:\tnightcrier\nCapInh:\t0000000000000000\nCapPrm:\t0000000000000000\n" +
+		"CapEff:\t0000000000000400\nCapBnd:\t000001ffffffffff\nCapAmb:\t0000000000002000\n"
+	effective, ambient, err := parseCapabilities(status)
+	if err != nil {
+		t.Fatalf("parseCapabilities() error = %v", err)
+	}
+	if effective != 0x400 || ambient != 0x2000 {
+		t.Errorf("parseCapabilities() = %#x, %#x; want 0x400, 0x2000", effective, ambient)
+	}
+
+	if _, _, err := parseCapabilities("Name:\tnightcrier\n"); err == nil {
+		t.Error("parseCapabilities() should fail without CapEff")
+	}
+}
+
+func TestCheckAgentHardening(t *testing.T) {
+	if check := checkAgentHardening(config.AgentHardeningConfig{}); check.Status != doctorWarn {
+		t.Errorf("disabled hardening: status = %s, want %s", check.Status, doctorWarn)
+	}
+
+	h := config.AgentHardeningConfig{Enabled: true}
+	if err := h.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if check := checkAgentHardening(h); check.Status != doctorPass {
+		t.Errorf("hardening: status = %s (%s), want %s", check.Status, check.Detail, doctorPass)
+	}
+
+	h.AllowWritableRoot = true
+	if check := checkAgentHardening(h); check.Status != doctorWarn {
+		t.Errorf("writable root: status = %s, want %s", check.Status, doctorWarn)
+	}
+
+	if check := checkProcessUser(0); check.Status != doctorWarn {
+		t.Errorf("root process: status = %s, want %s", check.Status, doctorWarn)
+	}
+}
+
+func TestCheckAgentEgress(t *testing.T) {
+	networks := map[string]bool{"nightcrier-egress": true, "bridge": false}
+	inspect := func(network string) (bool, error) {
+		isInternal, ok := networks[network]
+		if !ok {
+			return false, fmt.Errorf("network %s not found", network)
+		}
+		return isInternal, nil
+	}
+
+	if check := checkAgentEgress(config.AgentEgressConfig{}, inspect); check.Status != doctorWarn {
+		t.Errorf("disabled egress control: status = %s, want %s", check.Status, doctorWarn)
+	}
+	for network, want := range map[string]string{"nightcrier-egress": doctorPass, "bridge": doctorWarn, "missing": doctorFail} {
+		e := config.AgentEgressConfig{Enabled: true, ProxyURL: "http://egress-proxy:3128", Network: network}
+		if check := checkAgentEgress(e, inspect); check.Status != want {
+			t.Errorf("network %s: status = %s (%s), want %s", network, check.Status, check.Detail, want)
+		}
+	}
+}
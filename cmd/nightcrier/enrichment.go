@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/enrichment"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/labels"
+)
+
+// newEnricher returns the enricher collecting fault context on the clusters
+// without kubeconfig triage, or nil when MCP enrichment is disabled or every
+// cluster has triage enabled.
+func newEnricher(cfg *config.Config) *enrichment.Enricher {
+	if !cfg.MCPEnrichment.Enabled {
+		return nil
+	}
+	endpoints := make(map[string]string)
+	for _, cl := range cfg.Clusters {
+		if !cl.Triage.Enabled && !cl.ServeOnly {
+			endpoints[cl.Name] = cl.MCP.Endpoint
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+	return enrichment.New(enrichment.Config{
+		Endpoints: endpoints,
+		MaxEvents: cfg.MCPEnrichment.MaxEvents,
+		LogLines:  cfg.MCPEnrichment.LogLines,
+	}, &http.Client{Timeout: cfg.MCPEnrichment.Timeout()})
+}
+
+// enrichIncident records the recent events and log tail of the incident's object,
+// read through the cluster's MCP server, as annotations of an incident no agent
+// investigates. Enrichment problems are logged and never fail the incident.
+func (p *eventProcessor) enrichIncident(ctx context.Context, inc *incident.Incident) {
+	if p.enricher == nil || inc.Resource == nil {
+		return
+	}
+	log := incident.Logger(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.MCPEnrichment.Timeout())
+	defer cancel()
+	result, err := p.enricher.Collect(ctx, enrichment.Target{
+		Cluster:   inc.Cluster,
+		Namespace: inc.Namespace,
+		Kind:      inc.Resource.Kind,
+		Name:      inc.Resource.Name,
+	})
+	if err != nil {
+		log.Warn("failed to enrich incident through MCP server", "error", err)
+		return
+	}
+	if result.Empty() {
+		log.Debug("MCP server returned no events or logs for the incident")
+		return
+	}
+
+	annotations := result.Annotations()
+	if err := labels.Validate(nil, annotations); err != nil {
+		log.Warn("MCP enrichment not recorded", "error", err)
+		return
+	}
+	inc.AddLabels(nil, annotations)
+	if p.stateStore != nil {
+		if err := p.stateStore.SetIncidentLabels(ctx, inc.IncidentID, nil, annotations); err != nil {
+			log.Error("failed to record MCP enrichment in state store", "error", err)
+		}
+	}
+	log.Info("enriched incident through MCP server",
+		"events", len(result.Events),
+		"log_bytes", len(result.Logs))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/rbias/nightcrier/internal/agent"
+	"github.com/rbias/nightcrier/internal/failures"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/metrics"
+)
+
+// newFailureMetrics registers the counter of agent failures per cluster and
+// failure class.
+func newFailureMetrics(registry *metrics.Registry) *metrics.CounterVec {
+	return registry.RegisterCounter(metrics.NewCounterVec(
+		"nightcrier_agent_failures_total",
+		"Agent investigations that failed, by canonical failure class.",
+		"cluster", "class"))
+}
+
+// recordAgentFailure classifies a failed investigation from its exit code, failure
+// reason, and the agent's logs in the workspace, records the reason and class on
+// the incident, and counts it in the failure metrics, the circuit breaker, and the
+// provider status monitor. When
+// the agent crashed, its debug bundle's output and kernel log lines are classified
+// too, and the bundle is marked with the class.
+func (p *eventProcessor) recordAgentFailure(ctx context.Context, inc *incident.Incident, exitCode int, reason, workspacePath string) {
+	log := incident.Logger(ctx)
+
+	output := readAgentOutput(workspacePath)
+	bundle, err := agent.ReadDebugBundle(workspacePath)
+	if err != nil {
+		log.Warn("failed to read agent debug bundle", "error", err)
+	}
+	if bundle != nil {
+		reason = fmt.Sprintf("agent crashed (signal: %s): %s", bundle.Signal, reason)
+		output += "\n" + bundle.Output()
+	}
+
+	class := failures.Classify(exitCode, reason, output)
+	inc.FailureReason = reason
+	inc.FailureClass = string(class)
+
+	if bundle != nil {
+		bundle.FailureClass = string(class)
+		if err := bundle.Write(workspacePath); err != nil {
+			log.Warn("failed to mark agent debug bundle with failure class", "error", err)
+		}
+		if inc.LogPaths == nil {
+			inc.LogPaths = make(map[string]string)
+		}
+		inc.LogPaths["debug-bundle.json"] = filepath.Join(workspacePath, agent.DebugBundleFile)
+	}
+
+	if p.failures != nil {
+		p.failures.Inc(inc.Cluster, string(class))
+	}
+	p.circuitBreaker.RecordClassifiedFailure(class, reason)
+	if p.providerStatus != nil {
+		p.providerStatus.RecordFailure(p.cfg.ActiveLLMProvider(), class)
+	}
+
+	log.Warn("agent execution failed validation",
+		"reason", reason,
+		"failure_class", class)
+}
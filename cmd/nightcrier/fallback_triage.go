@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rbias/nightcrier/internal/agent"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/enrichment"
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/keypool"
+	"github.com/rbias/nightcrier/internal/reporting"
+	"github.com/rbias/nightcrier/internal/ruletriage"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// fallbackContextTimeout bounds collecting a fault's events and logs for
+// rule-based triage
+const fallbackContextTimeout = 15 * time.Second
+
+// fallbackReasons describe the fallback triggers in reports and notifications
+var fallbackReasons = map[string]string{
+	config.FallbackNoAPIKey:        "no usable LLM API key",
+	config.FallbackBudgetExhausted: "investigation budget exhausted",
+	config.FallbackCircuitOpen:     "circuit breaker open after repeated agent failures",
+}
+
+// fallbackTriage produces rule-based reports for faults no agent can investigate.
+type fallbackTriage struct {
+	cfg    config.FallbackTriageConfig
+	engine *ruletriage.Engine
+	// enricher reads the fault's events and logs through the cluster's MCP server
+	enricher *enrichment.Enricher
+
+	mu        sync.Mutex
+	lastProbe time.Time // last agent run while the circuit breaker was open
+}
+
+// newFallbackTriage returns the rule-based triage fallback, or nil when it is
+// disabled.
+func newFallbackTriage(cfg *config.Config) (*fallbackTriage, error) {
+	if !cfg.FallbackTriage.Enabled {
+		return nil, nil
+	}
+	engine, err := ruletriage.New(cfg.FallbackTriage.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create triage rules: %w", err)
+	}
+	endpoints := make(map[string]string)
+	for _, cl := range cfg.Clusters {
+		if !cl.ServeOnly {
+			endpoints[cl.Name] = cl.MCP.Endpoint
+		}
+	}
+	return &fallbackTriage{
+		cfg:      cfg.FallbackTriage,
+		engine:   engine,
+		enricher: enrichment.New(enrichment.Config{Endpoints: endpoints}, &http.Client{Timeout: fallbackContextTimeout}),
+	}, nil
+}
+
+// covers reports whether a trigger falls back to rule-based triage.
+func (f *fallbackTriage) covers(trigger string) bool {
+	return f != nil && f.cfg.Triggered(trigger)
+}
+
+// probe reports whether an agent should still run while the circuit breaker is
+// open: once per circuit_probe_seconds, so that a recovered agent closes it.
+func (f *fallbackTriage) probe() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if time.Since(f.lastProbe) < f.cfg.CircuitProbeInterval() {
+		return false
+	}
+	f.lastProbe = time.Now()
+	return true
+}
+
+// fallbackTrigger returns the trigger that replaces the agent with rule-based
+// triage before the budget is charged (no usable API key, or the circuit breaker
+// open), or "" when the agent should run.
+func (p *eventProcessor) fallbackTrigger() string {
+	if p.fallback.covers(config.FallbackNoAPIKey) && !p.hasUsableAPIKey() {
+		return config.FallbackNoAPIKey
+	}
+	if p.fallback.covers(config.FallbackCircuitOpen) && p.circuitBreaker.GetState() == reporting.StateOpen && !p.fallback.probe() {
+		return config.FallbackCircuitOpen
+	}
+	return ""
+}
+
+// hasUsableAPIKey reports whether the agent has an API key that has not been
+// rejected. Self-hosted and managed-cloud providers carry their own credentials;
+// agents using several providers (goose) need a key of any of them.
+func (p *eventProcessor) hasUsableAPIKey() bool {
+	if p.cfg.LLMProvider() != "" {
+		return true
+	}
+	if provider := keypool.ProviderForAgent(p.cfg.AgentCLI); provider != "" {
+		return p.keyPool.Usable(provider) > 0
+	}
+	for provider := range p.cfg.APIKeys() {
+		if p.keyPool.Usable(provider) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// runFallbackTriage completes an incident with a rule-based report instead of an
+// agent investigation: the fault's context is collected through the cluster's MCP
+// server, matched against the triage rules, and the report is stored and notified
+// like an agent's, marked as rule-based. Rule-based reports are not cached, so the
+// next occurrence is investigated by an agent once one can run.
+func (p *eventProcessor) runFallbackTriage(ctx context.Context, inc *incident.Incident, event *events.FaultEvent, trigger string) error {
+	log := incident.Logger(ctx)
+	reason := fallbackReasons[trigger]
+	inc.FallbackTriage = trigger
+	log.Info("no agent can run - falling back to rule-based triage", "trigger", trigger)
+
+	workspacePath, err := p.workspaceMgr.Create(inc.Ref())
+	if err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	input := ruletriage.Input{
+		Cluster:   inc.Cluster,
+		Namespace: inc.Namespace,
+		FaultType: inc.FaultType,
+		Severity:  inc.Severity,
+		Context:   inc.Context,
+	}
+	if inc.Resource != nil {
+		input.Kind, input.Name = inc.Resource.Kind, inc.Resource.Name
+	}
+	p.collectFallbackContext(ctx, &input)
+
+	finding := p.fallback.engine.Triage(input)
+	report := finding.Report(input, reason, time.Now())
+	log.Info("rule-based triage complete",
+		"triage_rule", finding.Rule,
+		"confidence", finding.Confidence,
+		"evidence", len(finding.Evidence))
+
+	return p.completeWithoutAgent(ctx, inc, workspacePath, agentlessReport{
+		Markdown:   report,
+		RootCause:  finding.RootCause,
+		Confidence: finding.Confidence,
+		Fallback:   reason,
+		Outcome:    map[string]string{"fallback": trigger},
+	})
+}
+
+// agentlessReport is an investigation report produced without an agent, by
+// rule-based triage or by a fault handler.
+type agentlessReport struct {
+	Markdown   string
+	RootCause  string
+	Confidence string
+	// Fallback is why rule-based triage replaced the agent, Handler the fault
+	// handler that resolved the fault; they mark the notification
+	Fallback string
+	Handler  string
+	// Outcome is added to the data of the outcome lifecycle event
+	Outcome map[string]string
+}
+
+// completeWithoutAgent resolves an incident with a report produced without an
+// agent: the report is written to the workspace, stored like an agent's, filed and
+// exported, and notified.
+func (p *eventProcessor) completeWithoutAgent(ctx context.Context, inc *incident.Incident, workspacePath string, report agentlessReport) error {
+	log := incident.Logger(ctx)
+	incidentPath := filepath.Join(workspacePath, "incident.json")
+	reportPath := filepath.Join(workspacePath, "output", "investigation.md")
+	if err := os.MkdirAll(filepath.Dir(reportPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(reportPath, []byte(report.Markdown), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	exitCode := 0
+	completedAt := time.Now()
+	setStatus(ctx, inc, incident.StatusResolved)
+	inc.CompletedAt = &completedAt
+	inc.ExitCode = &exitCode
+	if err := inc.WriteToFile(incidentPath); err != nil {
+		return fmt.Errorf("failed to write incident context: %w", err)
+	}
+
+	if p.stateStore != nil {
+		if err := p.stateStore.CompleteIncident(ctx, inc.IncidentID, inc.Status, exitCode, "", ""); err != nil {
+			log.Error("failed to complete incident in state store", "error", err)
+		}
+	}
+
+	// Store the report like an agent's; there are no agent logs
+	var reportURL string
+	if p.storageBackend != nil {
+		artifacts, err := readIncidentArtifacts(workspacePath, inc.Ref(), agent.LogPaths{}, p.markdownRenderer)
+		if err != nil {
+			log.Warn("failed to read incident artifacts for storage", "error", err)
+		} else {
+			if p.stateStore != nil {
+				triageReport := &storage.TriageReport{
+					ReportID:       uuid.New().String(),
+					IncidentID:     inc.IncidentID,
+					ExecutionID:    inc.IncidentID,
+					GeneratedAt:    completedAt,
+					ReportMarkdown: string(artifacts.InvestigationMD),
+					ReportHTML:     string(artifacts.InvestigationHTML),
+				}
+				if err := p.stateStore.RecordTriageReport(ctx, triageReport); err != nil {
+					log.Error("failed to record triage report in state store", "error", err)
+				}
+			}
+			saveResult, err := p.storageBackend.SaveIncident(ctx, inc.Ref(), artifacts)
+			if err != nil {
+				log.Error("failed to save incident to storage", "error", err)
+				p.retryUpload(ctx, inc.Ref(), workspacePath, agent.LogPaths{})
+			} else {
+				reportURL = p.shortenReportURL(ctx, saveResult.ReportURL)
+				p.recordArtifactHashes(ctx, inc, saveResult.Hashes)
+				if err := inc.WriteToFile(incidentPath); err != nil {
+					log.Warn("failed to update incident.json with artifact hashes", "error", err)
+				}
+			}
+		}
+	}
+
+	if p.serviceNow != nil {
+		p.fileServiceNowRecord(ctx, inc, report.RootCause, report.Confidence, reportURL)
+	}
+	if p.searchIndex != nil {
+		p.exportToSearchIndex(ctx, inc, reportPath, report.RootCause, report.Confidence, reportURL)
+	}
+	outcome := map[string]string{"report_url": reportURL}
+	for k, v := range report.Outcome {
+		outcome[k] = v
+	}
+	p.emitOutcome(ctx, inc, outcome)
+
+	if p.notifier != nil {
+		kind, name := "", ""
+		if inc.Resource != nil {
+			kind, name = inc.Resource.Kind, inc.Resource.Name
+		}
+		summary := &reporting.IncidentSummary{
+			IncidentID: inc.Ref(),
+			Cluster:    inc.Cluster,
+			Namespace:  inc.Namespace,
+			Resource:   fmt.Sprintf("%s/%s", kind, name),
+			Reason:     inc.FaultType,
+			Severity:   inc.Severity,
+			Labels:     inc.Labels,
+			Status:     inc.Status,
+			RootCause:  report.RootCause,
+			Confidence: report.Confidence,
+			Duration:   completedAt.Sub(inc.CreatedAt),
+			ReportPath: reportPath,
+			ReportURL:  reportURL,
+			Fallback:   report.Fallback,
+			Handler:    report.Handler,
+		}
+		setSummaryOwner(summary, inc)
+		p.sendNotification(ctx, summary)
+	}
+
+	return nil
+}
+
+// collectFallbackContext adds the fault object's recent events and log tail, read
+// through the cluster's MCP server, to the triage input. Failures are logged; the
+// rules then match the fault type and description only.
+func (p *eventProcessor) collectFallbackContext(ctx context.Context, input *ruletriage.Input) {
+	log := incident.Logger(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, fallbackContextTimeout)
+	defer cancel()
+	result, err := p.fallback.enricher.Collect(ctx, enrichment.Target{
+		Cluster:   input.Cluster,
+		Namespace: input.Namespace,
+		Kind:      input.Kind,
+		Name:      input.Name,
+	})
+	if err != nil {
+		log.Warn("failed to collect fault context for rule-based triage", "error", err)
+		return
+	}
+	if result.Empty() {
+		return
+	}
+	for _, ev := range result.Events {
+		input.Events = append(input.Events, ev.String())
+	}
+	input.Logs = result.Logs
+}
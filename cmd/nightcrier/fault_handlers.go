@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/faulthandlers"
+	"github.com/rbias/nightcrier/internal/incident"
+)
+
+// faultHandlers routes faults to their configured specialized handlers.
+type faultHandlers struct {
+	registry *faulthandlers.Registry
+	// kubeconfigs maps each cluster with kubeconfig triage to its kubeconfig, which
+	// handlers act on the cluster with
+	kubeconfigs map[string]string
+}
+
+// newFaultHandlers loads the configured fault handlers, or returns nil when none
+// are configured.
+func newFaultHandlers(cfg *config.Config) (*faultHandlers, error) {
+	if len(cfg.FaultHandlers.Handlers) == 0 {
+		return nil, nil
+	}
+	registry, err := faulthandlers.New(cfg.FaultHandlers.Specs())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fault handlers: %w", err)
+	}
+	kubeconfigs := make(map[string]string)
+	for _, cl := range cfg.Clusters {
+		if cl.Triage.Enabled && cl.Triage.Kubeconfig != "" {
+			kubeconfigs[cl.Name] = cl.Triage.Kubeconfig
+		}
+	}
+	for _, h := range cfg.FaultHandlers.Handlers {
+		slog.Info("fault handler registered",
+			"fault_handler", h.Name,
+			"mode", h.Mode,
+			"fault_types", h.FaultTypes)
+	}
+	return &faultHandlers{registry: registry, kubeconfigs: kubeconfigs}, nil
+}
+
+// runFaultHandlers runs the handlers matching the incident's fault, in order, and
+// records each run on the incident. When a handler resolves the fault, the
+// incident is completed with its report and handled is true. Otherwise the
+// returned prompt section holds the findings of the handlers that ran, for the
+// agent. Failed handlers are logged and skipped, so the agent still investigates.
+func (p *eventProcessor) runFaultHandlers(ctx context.Context, inc *incident.Incident) (prompt string, handled bool, err error) {
+	if p.faultHandlers == nil {
+		return "", false, nil
+	}
+	in := faulthandlers.Input{
+		IncidentID: inc.Ref(),
+		Cluster:    inc.Cluster,
+		Namespace:  inc.Namespace,
+		FaultType:  inc.FaultType,
+		Severity:   inc.Severity,
+		Context:    inc.Context,
+		Kubeconfig: p.faultHandlers.kubeconfigs[inc.Cluster],
+	}
+	if inc.Resource != nil {
+		in.Kind, in.Name = inc.Resource.Kind, inc.Resource.Name
+	}
+	matched := p.faultHandlers.registry.Match(in.FaultType, in.Kind, in.Namespace)
+	if len(matched) == 0 {
+		return "", false, nil
+	}
+
+	log := incident.Logger(ctx)
+	workspacePath, err := p.workspaceMgr.Create(inc.Ref(), p.cfg.WorkspaceTemplates(inc.Cluster)...)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	in.Workspace = workspacePath
+
+	var findings []faulthandlers.Finding
+	for _, h := range matched {
+		run, result := h.Run(ctx, in)
+		inc.FaultHandlers = append(inc.FaultHandlers, run)
+		if run.Error != "" {
+			log.Warn("fault handler failed - continuing without it",
+				"fault_handler", run.Handler,
+				"mode", run.Mode,
+				"duration_ms", run.DurationMs,
+				"error", run.Error)
+			continue
+		}
+		log.Info("fault handler ran",
+			"fault_handler", run.Handler,
+			"mode", run.Mode,
+			"outcome", run.Outcome,
+			"duration_ms", run.DurationMs)
+		if run.Outcome == faulthandlers.OutcomeResolved {
+			return "", true, p.completeWithoutAgent(ctx, inc, workspacePath, agentlessReport{
+				Markdown:   result.Report,
+				RootCause:  result.RootCause,
+				Confidence: result.Confidence,
+				Handler:    run.Handler,
+				Outcome:    map[string]string{"fault_handler": run.Handler},
+			})
+		}
+		findings = append(findings, faulthandlers.Finding{Handler: run.Handler, Summary: result.Summary})
+	}
+	return faulthandlers.PromptSection(findings), false, nil
+}
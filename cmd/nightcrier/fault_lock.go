@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/faultlock"
+	"github.com/rbias/nightcrier/internal/fleet"
+)
+
+// faultLockPoll is how often an incident waiting for the fault lock retries it
+const faultLockPoll = 5 * time.Second
+
+// faultLockMargin is added to the longest agent timeout when waiting for the
+// fault lock, covering the report upload and notification
+const faultLockMargin = 5 * time.Minute
+
+// newFaultLocker returns the per-fault processing lock kept under
+// {workspace_root}/.locks and, with shared limits, in the state store.
+func newFaultLocker(cfg *config.Config, sharedLimits *fleet.State) (*faultlock.Locker, error) {
+	hostname, _ := os.Hostname()
+	locker, err := faultlock.New(filepath.Join(cfg.WorkspaceRoot, ".locks"), fmt.Sprintf("%s/%d", hostname, os.Getpid()))
+	if err != nil {
+		return nil, err
+	}
+	if sharedLimits != nil {
+		locker.Share(sharedLimits)
+	}
+	return locker, nil
+}
+
+// faultLockWait is how long an incident waits for another investigation of the
+// same fault to finish: the longest an investigation can run.
+func faultLockWait(cfg *config.Config) time.Duration {
+	longest := cfg.AgentTimeout
+	for _, timeout := range cfg.AgentSeverityTimeouts() {
+		longest = max(longest, timeout)
+	}
+	return time.Duration(longest)*time.Second + faultLockMargin
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/reporting"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// maxFollowUpChain bounds how many earlier incidents of a recurring fault are
+// followed through their parent links.
+const maxFollowUpChain = 10
+
+var incidentsChainCmd = &cobra.Command{
+	Use:   "chain <incident-id>",
+	Short: "Show the follow-up chain of a recurring incident",
+	Long: `Show the incidents of a recurring fault, oldest first: the earlier incidents
+the given incident follows up and the follow-ups created when the fault recurred
+after it was resolved (follow_up). Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier incidents chain 2f1c...`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runIncidentsChain,
+}
+
+func init() {
+	incidentsCmd.AddCommand(incidentsChainCmd)
+}
+
+// findPriorInvestigation returns the most recent resolved incident of the same
+// fault on the same resource within the follow_up lookback, or nil when follow-up
+// linking is disabled or the fault has not occurred before. The prior incident's
+// root cause is read from its stored report.
+func (p *eventProcessor) findPriorInvestigation(ctx context.Context, inc *incident.Incident) *incident.PriorInvestigation {
+	if !p.cfg.FollowUp.Enabled || p.stateStore == nil || inc.Resource == nil {
+		return nil
+	}
+	log := incident.Logger(ctx)
+
+	since := time.Now().Add(-p.cfg.FollowUp.Lookback())
+	previous, err := p.stateStore.ListIncidents(ctx, &storage.IncidentFilters{
+		Status:       []string{incident.StatusResolved},
+		Cluster:      inc.Cluster,
+		Namespace:    inc.Namespace,
+		FaultType:    inc.FaultType,
+		ResourceKind: inc.Resource.Kind,
+		ResourceName: inc.Resource.Name,
+		CreatedAfter: &since,
+		Limit:        1,
+	})
+	if err != nil {
+		log.Warn("failed to look up prior incidents", "error", err)
+		return nil
+	}
+	if len(previous) == 0 {
+		return nil
+	}
+
+	parent := previous[0]
+	prior := &incident.PriorInvestigation{
+		IncidentID: parent.IncidentID,
+		DisplayID:  parent.DisplayID,
+		Chain:      followUpChain(ctx, p.stateStore, parent),
+	}
+	if parent.CompletedAt != nil {
+		prior.CompletedAt = *parent.CompletedAt
+	}
+	report, err := p.stateStore.GetTriageReport(ctx, parent.IncidentID)
+	if err != nil {
+		log.Warn("failed to load prior investigation report", "parent_incident_id", parent.IncidentID, "error", err)
+	}
+	if report != nil {
+		prior.RootCause, prior.Confidence = reporting.ExtractSummary(report.ReportMarkdown)
+	}
+	return prior
+}
+
+// diffInvestigation compares a follow-up investigation's findings with the prior
+// investigation and writes the comparison to output/investigation-diff.md in the
+// workspace. It returns nil when the report has no readable summary.
+func diffInvestigation(ctx context.Context, workspacePath string, prior *incident.PriorInvestigation) *incident.InvestigationDiff {
+	log := incident.Logger(ctx)
+	rootCause, confidence, err := reporting.ExtractSummaryFromReport(workspacePath)
+	if err != nil {
+		log.Debug("not diffing investigation without a readable summary", "error", err)
+		return nil
+	}
+	diff := incident.DiffInvestigations(*prior, rootCause, confidence)
+	diffPath := filepath.Join(workspacePath, "output", "investigation-diff.md")
+	if err := os.WriteFile(diffPath, []byte(diff.Markdown()), 0644); err != nil {
+		log.Warn("failed to write investigation diff", "path", diffPath, "error", err)
+	}
+	log.Info("investigation diffed against prior incident",
+		"parent_incident_id", prior.IncidentID,
+		"root_cause", diff.RootCauseStatus,
+		"confidence_delta", diff.ConfidenceDelta)
+	return diff
+}
+
+// followUpChain lists an incident and the earlier incidents it follows up, most
+// recent first.
+func followUpChain(ctx context.Context, store storage.StateStore, inc *incident.Incident) []string {
+	chain := []string{inc.Ref()}
+	for parentID := inc.ParentIncidentID; parentID != "" && len(chain) < maxFollowUpChain; {
+		parent, err := store.GetIncident(ctx, parentID)
+		if err != nil || parent == nil {
+			chain = append(chain, parentID)
+			break
+		}
+		chain = append(chain, parent.Ref())
+		parentID = parent.ParentIncidentID
+	}
+	return chain
+}
+
+func runIncidentsChain(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	inc, err := resolveIncident(ctx, store, args[0])
+	if err != nil {
+		return err
+	}
+
+	// Walk up to the first incident of the fault, then down through its follow-ups
+	root := inc
+	for depth := 0; root.ParentIncidentID != "" && depth < maxFollowUpChain; depth++ {
+		parent, err := store.GetIncident(ctx, root.ParentIncidentID)
+		if err != nil || parent == nil {
+			break
+		}
+		root = parent
+	}
+
+	fmt.Printf("%-20s %-13s %s\n", "CREATED (UTC)", "STATUS", "INCIDENT")
+	var printChain func(current *incident.Incident, depth int) error
+	printChain = func(current *incident.Incident, depth int) error {
+		marker := ""
+		if current.IncidentID == inc.IncidentID {
+			marker = "  <-"
+		}
+		fmt.Printf("%-20s %-13s %s%s%s\n",
+			current.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+			current.Status,
+			strings.Repeat("  ", depth),
+			current.Ref(),
+			marker)
+		if depth >= maxFollowUpChain {
+			return nil
+		}
+		followUps, err := store.ListIncidents(ctx, &storage.IncidentFilters{ParentIncidentID: current.IncidentID})
+		if err != nil {
+			return err
+		}
+		// Oldest follow-up first
+		for i := len(followUps) - 1; i >= 0; i-- {
+			if err := printChain(followUps[i], depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return printChain(root, 0)
+}
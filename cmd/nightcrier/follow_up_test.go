@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// historyStore serves incidents and reports for follow-up lookups; other state
+// store methods are not expected.
+type historyStore struct {
+	storage.StateStore
+	incidents []*incident.Incident
+	reports   map[string]*storage.TriageReport
+	filters   *storage.IncidentFilters
+}
+
+func (s *historyStore) ListIncidents(ctx context.Context, filters *storage.IncidentFilters) ([]*incident.Incident, error) {
+	s.filters = filters
+	for _, inc := range s.incidents {
+		if inc.Status == incident.StatusResolved && inc.Resource.Name == filters.ResourceName {
+			return []*incident.Incident{inc}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *historyStore) GetIncident(ctx context.Context, incidentID string) (*incident.Incident, error) {
+	for _, inc := range s.incidents {
+		if inc.IncidentID == incidentID {
+			return inc, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *historyStore) GetTriageReport(ctx context.Context, incidentID string) (*storage.TriageReport, error) {
+	return s.reports[incidentID], nil
+}
+
+func TestFindPriorInvestigation(t *testing.T) {
+	event := &events.FaultEvent{
+		Cluster:   "prod",
+		Resource:  &events.ResourceInfo{Kind: "Pod", Name: "web", Namespace: "default"},
+		FaultType: "OOMKilled",
+	}
+	completed := time.Now().Add(-time.Hour)
+	first := incident.NewFromEvent("inc-1", event)
+	first.Status = incident.StatusResolved
+	second := incident.NewFromEvent("inc-2", event)
+	second.Status = incident.StatusResolved
+	second.CompletedAt = &completed
+	second.ParentIncidentID = "inc-1"
+
+	store := &historyStore{
+		incidents: []*incident.Incident{second, first},
+		reports: map[string]*storage.TriageReport{
+			"inc-2": {ReportMarkdown: "## Root Cause\n\nMemory limit too low.\n\n**Confidence Level**: HIGH\n"},
+		},
+	}
+	cfg := &config.Config{FollowUp: config.FollowUpConfig{Enabled: true, LookbackHours: 24}}
+	p := &eventProcessor{cfg: cfg, stateStore: store}
+
+	inc := incident.NewFromEvent("inc-3", event)
+	prior := p.findPriorInvestigation(context.Background(), inc)
+	if prior == nil {
+		t.Fatal("findPriorInvestigation() = nil, want the resolved incident")
+	}
+	if prior.IncidentID != "inc-2" || !prior.CompletedAt.Equal(completed) {
+		t.Errorf("prior = %+v, want inc-2", prior)
+	}
+	if prior.RootCause != "Memory limit too low." || prior.Confidence != "HIGH" {
+		t.Errorf("prior root cause = %q (%s), want the stored report's", prior.RootCause, prior.Confidence)
+	}
+	if len(prior.Chain) != 2 || prior.Chain[0] != "inc-2" || prior.Chain[1] != "inc-1" {
+		t.Errorf("Chain = %v, want [inc-2 inc-1]", prior.Chain)
+	}
+	f := store.filters
+	if f.ResourceKind != "Pod" || f.Namespace != "default" || f.FaultType != "OOMKilled" || f.CreatedAfter == nil || f.Limit != 1 {
+		t.Errorf("ListIncidents() filters = %+v, want the same fault on the same resource", f)
+	}
+
+	// A fault on another resource is not a recurrence
+	other := incident.NewFromEvent("inc-4", &events.FaultEvent{
+		Cluster:   "prod",
+		Resource:  &events.ResourceInfo{Kind: "Pod", Name: "api", Namespace: "default"},
+		FaultType: "OOMKilled",
+	})
+	if prior := p.findPriorInvestigation(context.Background(), other); prior != nil {
+		t.Errorf("findPriorInvestigation() = %+v for a different resource, want nil", prior)
+	}
+
+	cfg.FollowUp.Enabled = false
+	if prior := p.findPriorInvestigation(context.Background(), inc); prior != nil {
+		t.Errorf("findPriorInvestigation() = %+v when disabled, want nil", prior)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Legal hold command flags
+	holdReason      string
+	holdActor       string
+	holdsIncludeAll bool
+)
+
+var incidentsHoldCmd = &cobra.Command{
+	Use:   "hold <incident-id>",
+	Short: "Place a legal hold on an incident",
+	Long: `Place a legal hold on an incident. Its workspace, artifacts, and state store rows
+are exempt from retention and garbage collection until the hold is released.
+Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier incidents hold 2f1c... --reason "Matter 2024-17"`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runIncidentsHold,
+}
+
+var incidentsReleaseCmd = &cobra.Command{
+	Use:     "release <incident-id>",
+	Short:   "Release the legal hold on an incident",
+	Example: `  nightcrier incidents release 2f1c...`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runIncidentsRelease,
+}
+
+var incidentsHoldsCmd = &cobra.Command{
+	Use:   "holds",
+	Short: "List legal holds",
+	Args:  cobra.NoArgs,
+	RunE:  runIncidentsHolds,
+}
+
+func init() {
+	incidentsHoldCmd.Flags().StringVar(&holdReason, "reason", "", "Reason for the hold, e.g. the matter or ticket number (required)")
+	incidentsHoldCmd.Flags().StringVar(&holdActor, "by", os.Getenv("USER"), "Who is placing the hold")
+	_ = incidentsHoldCmd.MarkFlagRequired("reason")
+
+	incidentsReleaseCmd.Flags().StringVar(&holdActor, "by", os.Getenv("USER"), "Who is releasing the hold")
+
+	incidentsHoldsCmd.Flags().BoolVar(&holdsIncludeAll, "all", false, "Include released holds")
+
+	incidentsCmd.AddCommand(incidentsHoldCmd, incidentsReleaseCmd, incidentsHoldsCmd)
+}
+
+func runIncidentsHold(cmd *cobra.Command, args []string) error {
+	if holdActor == "" {
+		return fmt.Errorf("--by is required when $USER is not set")
+	}
+
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	inc, err := resolveIncident(ctx, store, args[0])
+	if err != nil {
+		return err
+	}
+	hold := &storage.LegalHold{
+		HoldID:     uuid.New().String(),
+		IncidentID: inc.IncidentID,
+		Reason:     holdReason,
+		PlacedBy:   holdActor,
+		PlacedAt:   time.Now().UTC(),
+	}
+	if err := store.PlaceLegalHold(ctx, hold); err != nil {
+		return err
+	}
+	fmt.Printf("Legal hold placed on incident %s\n", inc.Ref())
+	return nil
+}
+
+func runIncidentsRelease(cmd *cobra.Command, args []string) error {
+	if holdActor == "" {
+		return fmt.Errorf("--by is required when $USER is not set")
+	}
+
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	inc, err := resolveIncident(ctx, store, args[0])
+	if err != nil {
+		return err
+	}
+	if err := store.ReleaseLegalHold(ctx, inc.IncidentID, holdActor, time.Now().UTC()); err != nil {
+		return err
+	}
+	fmt.Printf("Legal hold on incident %s released; it is subject to retention again\n", inc.Ref())
+	return nil
+}
+
+func runIncidentsHolds(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	holds, err := store.ListLegalHolds(ctx, holdsIncludeAll)
+	if err != nil {
+		return err
+	}
+	if len(holds) == 0 {
+		fmt.Println("No legal holds")
+		return nil
+	}
+
+	fmt.Printf("%-36s %-20s %-16s %-20s %s\n", "INCIDENT", "PLACED (UTC)", "PLACED BY", "RELEASED (UTC)", "REASON")
+	for _, hold := range holds {
+		released := "-"
+		if !hold.Active() {
+			released = hold.ReleasedAt.UTC().Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-36s %-20s %-16s %-20s %s\n",
+			hold.IncidentID,
+			hold.PlacedAt.UTC().Format("2006-01-02 15:04:05"),
+			truncateString(hold.PlacedBy, 16),
+			released,
+			hold.Reason)
+	}
+	return nil
+}
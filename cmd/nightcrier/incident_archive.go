@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/rbias/nightcrier/internal/agent"
+	"github.com/rbias/nightcrier/internal/health"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// incidentArchives serves the health server's /admin/incidents/{id}/archive
+// endpoint from the incident workspaces. With a SQL state store the incident may
+// be given by UUID or display ID; without one, by the name of its workspace.
+type incidentArchives struct {
+	workspaces *agent.WorkspaceManager
+	store      storage.StateStore
+}
+
+func (a incidentArchives) IncidentArchive(ctx context.Context, id string) (string, io.WriterTo, error) {
+	ref := id
+	if a.store != nil {
+		inc, err := a.store.GetIncident(ctx, id)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to look up incident %s: %w", id, err)
+		}
+		if inc != nil {
+			ref = inc.Ref()
+		}
+	}
+
+	dir, err := a.workspaces.Find(ref)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil, fmt.Errorf("%w: no workspace for incident %s", health.ErrIncidentNotFound, id)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return ref + ".tar.gz", agent.WorkspaceArchive{Dir: dir, Prefix: ref}, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incidentfeed"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/rbias/nightcrier/internal/storage/postgres"
+	"github.com/spf13/cobra"
+)
+
+// listenerPollInterval is how often the incident history is polled when changes
+// arrive through LISTEN/NOTIFY, as a safety net for missed notifications
+const listenerPollInterval = time.Minute
+
+var (
+	// Incidents watch command flags
+	watchFormat string
+)
+
+var incidentsWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Follow incident changes made by every nightcrier instance",
+	Long: `Print incident changes as they are recorded by any nightcrier instance sharing
+the state store: new incidents, status and failure class changes, and labels.
+
+With a postgres state store changes arrive as soon as they are committed (LISTEN/
+NOTIFY), unless incident_feed.mode is "poll"; otherwise the incident history is
+polled every incident_feed.poll_interval_seconds. Only changes made after the
+command starts are printed. Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier incidents watch
+  nightcrier incidents watch --format json | jq .`,
+	Args: cobra.NoArgs,
+	RunE: runIncidentsWatch,
+}
+
+func init() {
+	incidentsWatchCmd.Flags().StringVar(&watchFormat, "format", "table", "Output format: table or json (one change per line)")
+	incidentsCmd.AddCommand(incidentsWatchCmd)
+}
+
+// startIncidentFeed follows the incident changes of store until ctx is done. With
+// a postgres state store in auto mode changes are received with LISTEN/NOTIFY,
+// falling back to polling when listening fails.
+func startIncidentFeed(ctx context.Context, cfg *config.Config, store storage.StateStore) *incidentfeed.Feed {
+	var listener incidentfeed.Listener
+	pollInterval := cfg.IncidentFeed.PollInterval()
+	if _, ok := store.(*postgres.Store); ok && cfg.IncidentFeed.Mode == config.IncidentFeedAuto {
+		changeListener, err := postgres.ListenChanges(ctx, postgresConnString(cfg))
+		if err != nil {
+			slog.Warn("following incident changes by polling", "reason", err, "poll_interval", pollInterval)
+		} else {
+			listener = changeListener
+			pollInterval = listenerPollInterval
+		}
+	}
+
+	feed := incidentfeed.New(store, listener, pollInterval)
+	go feed.Run(ctx)
+	return feed
+}
+
+// logIncidentChanges logs the changes of feed until ctx is done.
+func logIncidentChanges(ctx context.Context, feed *incidentfeed.Feed) {
+	changes, unsubscribe := feed.Subscribe()
+	defer unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case change := <-changes:
+			slog.Debug("incident changed",
+				"incident_id", change.IncidentID,
+				"field", change.Field,
+				"old_value", change.OldValue,
+				"new_value", change.NewValue,
+				"changed_at", change.ChangedAt)
+		}
+	}
+}
+
+// incidentChanges serves the changes of the incident feed on the health server.
+type incidentChanges struct {
+	feed *incidentfeed.Feed
+}
+
+func (c incidentChanges) SubscribeChanges() (<-chan interface{}, func()) {
+	changes, unsubscribe := c.feed.Subscribe()
+	out := make(chan interface{})
+	stop := make(chan struct{})
+	go func() {
+		defer close(out)
+		for change := range changes {
+			select {
+			case out <- change:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return out, func() {
+		close(stop)
+		unsubscribe()
+	}
+}
+
+func runIncidentsWatch(cmd *cobra.Command, args []string) error {
+	if watchFormat != "table" && watchFormat != "json" {
+		return fmt.Errorf("unknown format %q: must be table or json", watchFormat)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	cfg, store, err := loadIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	feed := startIncidentFeed(ctx, cfg, store)
+	changes, unsubscribe := feed.Subscribe()
+	defer unsubscribe()
+
+	enc := json.NewEncoder(os.Stdout)
+	if watchFormat == "table" {
+		fmt.Printf("%-20s %-36s %-24s %-20s %s\n", "CHANGED (UTC)", "INCIDENT", "FIELD", "FROM", "TO")
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			if dropped := feed.Dropped(); dropped > 0 {
+				fmt.Fprintf(os.Stderr, "%d changes were not printed because output fell behind\n", dropped)
+			}
+			return nil
+		case change := <-changes:
+			if watchFormat == "json" {
+				if err := enc.Encode(change); err != nil {
+					return err
+				}
+				continue
+			}
+			fmt.Printf("%-20s %-36s %-24s %-20s %s\n",
+				change.ChangedAt.UTC().Format("2006-01-02 15:04:05"),
+				change.IncidentID,
+				truncateString(change.Field, 24),
+				truncateString(orDash(change.OldValue), 20),
+				orDash(change.NewValue))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/health"
+	"github.com/rbias/nightcrier/internal/labels"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Incidents history command flags
+	historyAt     string
+	historyFormat string
+)
+
+var incidentsHistoryCmd = &cobra.Command{
+	Use:   "history <incident-id>",
+	Short: "Show an incident's status timeline and its state at a past time",
+	Long: `Show the recorded changes of an incident's status, failure class, and labels,
+and its status timeline with the time spent in each status, e.g. for a postmortem.
+
+With --at, also show the incident's state as of that time: its status, who it was
+assigned to (its team label), and its labels. Incidents created before the history
+was recorded have a timeline reconstructed from their creation and completion
+times. Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier incidents history 2f1c...
+  nightcrier incidents history prod-20240501-0007 --at 2024-05-01T14:30:00Z`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIncidentsHistory,
+}
+
+func init() {
+	incidentsHistoryCmd.Flags().StringVar(&historyAt, "at", "", "Show the incident's state as of this RFC 3339 time")
+	incidentsHistoryCmd.Flags().StringVar(&historyFormat, "format", "table", "Output format: table or json")
+	incidentsCmd.AddCommand(incidentsHistoryCmd)
+}
+
+// incidentHistoryView is the history of an incident as served by the incident
+// history API and printed by "incidents history --format json".
+type incidentHistoryView struct {
+	IncidentID string `json:"incident_id"`
+	DisplayID  string `json:"display_id,omitempty"`
+	// Timeline holds the status changes, oldest first
+	Timeline []*storage.IncidentChange `json:"timeline"`
+	// Changes holds every recorded change, oldest first
+	Changes []*storage.IncidentChange `json:"changes"`
+	// State is the incident's state at the requested time (omitted without one,
+	// or when the incident did not exist yet)
+	State *storage.IncidentState `json:"state,omitempty"`
+}
+
+// loadIncidentHistory looks up an incident by UUID or display ID and reads its
+// history, replaying it up to at when set. It returns nil when the incident does
+// not exist.
+func loadIncidentHistory(ctx context.Context, store storage.StateStore, id string, at *time.Time) (*incidentHistoryView, error) {
+	inc, err := store.GetIncident(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up incident %s: %w", id, err)
+	}
+	if inc == nil {
+		return nil, nil
+	}
+	changes, err := store.IncidentHistory(ctx, inc.IncidentID)
+	if err != nil {
+		return nil, err
+	}
+	view := &incidentHistoryView{
+		IncidentID: inc.IncidentID,
+		DisplayID:  inc.DisplayID,
+		Timeline:   storage.StatusTimeline(changes),
+		Changes:    changes,
+	}
+	if view.Timeline == nil {
+		view.Timeline = []*storage.IncidentChange{}
+	}
+	if view.Changes == nil {
+		view.Changes = []*storage.IncidentChange{}
+	}
+	if at != nil {
+		view.State = storage.IncidentStateAt(changes, *at)
+	}
+	return view, nil
+}
+
+// incidentHistory serves the incident history API from the state store.
+type incidentHistory struct {
+	store storage.StateStore
+}
+
+func (h incidentHistory) GetIncidentHistory(ctx context.Context, id string, at *time.Time) (interface{}, error) {
+	view, err := loadIncidentHistory(ctx, h.store, id, at)
+	if err != nil {
+		return nil, err
+	}
+	if view == nil {
+		return nil, fmt.Errorf("%w: %s", health.ErrIncidentNotFound, id)
+	}
+	return view, nil
+}
+
+func runIncidentsHistory(cmd *cobra.Command, args []string) error {
+	if historyFormat != "table" && historyFormat != "json" {
+		return fmt.Errorf("unknown format %q: must be table or json", historyFormat)
+	}
+	var at *time.Time
+	if historyAt != "" {
+		parsed, err := time.Parse(time.RFC3339, historyAt)
+		if err != nil {
+			return fmt.Errorf("invalid --at time %q: must be RFC 3339, e.g. 2024-05-01T14:00:00Z", historyAt)
+		}
+		at = &parsed
+	}
+
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	view, err := loadIncidentHistory(ctx, store, args[0], at)
+	if err != nil {
+		return err
+	}
+	if view == nil {
+		return fmt.Errorf("incident %s not found", args[0])
+	}
+
+	if historyFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(view)
+	}
+
+	fmt.Printf("Incident: %s\n", view.IncidentID)
+	if view.DisplayID != "" {
+		fmt.Printf("Display ID: %s\n", view.DisplayID)
+	}
+	if len(view.Changes) == 0 {
+		fmt.Println("\nNo history recorded")
+		return nil
+	}
+
+	fmt.Printf("\n%-20s %-14s %s\n", "STATUS SINCE (UTC)", "STATUS", "DURATION")
+	for i, change := range view.Timeline {
+		duration := "ongoing"
+		if i+1 < len(view.Timeline) {
+			duration = view.Timeline[i+1].ChangedAt.Sub(change.ChangedAt).Round(time.Second).String()
+		}
+		fmt.Printf("%-20s %-14s %s\n", change.ChangedAt.UTC().Format("2006-01-02 15:04:05"), change.NewValue, duration)
+	}
+
+	fmt.Printf("\n%-20s %-24s %-20s %s\n", "CHANGED (UTC)", "FIELD", "FROM", "TO")
+	for _, change := range view.Changes {
+		fmt.Printf("%-20s %-24s %-20s %s\n",
+			change.ChangedAt.UTC().Format("2006-01-02 15:04:05"),
+			truncateString(change.Field, 24),
+			truncateString(orDash(change.OldValue), 20),
+			orDash(change.NewValue))
+	}
+
+	if at != nil {
+		fmt.Printf("\nState at %s:\n", at.UTC().Format(time.RFC3339))
+		if view.State == nil {
+			fmt.Println("  The incident did not exist yet")
+			return nil
+		}
+		fmt.Printf("  Status:     %s (since %s)\n", view.State.Status, view.State.Since.UTC().Format("2006-01-02 15:04:05"))
+		fmt.Printf("  Assignment: %s\n", orDash(view.State.Assignment))
+		if view.State.FailureClass != "" {
+			fmt.Printf("  Failure:    %s\n", view.State.FailureClass)
+		}
+		fmt.Printf("  Labels:     %s\n", labels.Format(view.State.Labels))
+	}
+	return nil
+}
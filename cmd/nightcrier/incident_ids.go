@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// assignDisplayID gives a new incident its readable display ID, allocating the
+// sequence number from the state store (incident_ids). Without a SQL state store,
+// or when allocation fails, the incident keeps its UUID.
+func (p *eventProcessor) assignDisplayID(ctx context.Context, inc *incident.Incident) {
+	if !p.cfg.IncidentIDs.Structured() || p.stateStore == nil {
+		return
+	}
+	day := inc.CreatedAt.UTC()
+	seq, err := p.stateStore.NextIncidentSequence(ctx, inc.Cluster, day.Format("2006-01-02"))
+	if err != nil {
+		incident.Logger(ctx).Warn("failed to allocate display ID, using the incident UUID", "error", err)
+		return
+	}
+	inc.DisplayID = incident.FormatDisplayID(p.cfg.IncidentIDs.Prefix, day, inc.Cluster, seq)
+}
+
+// resolveIncident looks up an incident given on the command line by its UUID or
+// display ID.
+func resolveIncident(ctx context.Context, store storage.StateStore, id string) (*incident.Incident, error) {
+	inc, err := store.GetIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inc == nil {
+		return nil, fmt.Errorf("incident %s not found", id)
+	}
+	return inc, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// sequenceStore allocates display ID sequence numbers; other state store methods
+// are not expected.
+type sequenceStore struct {
+	storage.StateStore
+	next int
+	err  error
+}
+
+func (s *sequenceStore) NextIncidentSequence(ctx context.Context, cluster, day string) (int, error) {
+	s.next++
+	return s.next, s.err
+}
+
+func TestAssignDisplayID(t *testing.T) {
+	event := &events.FaultEvent{Cluster: "prod", FaultType: "OOMKilled"}
+	newIncident := func() *incident.Incident {
+		inc := incident.NewFromEvent("2f1c8e4a-uuid", event)
+		inc.CreatedAt = time.Date(2024, 6, 13, 10, 0, 0, 0, time.UTC)
+		return inc
+	}
+	structured := config.IncidentIDsConfig{Scheme: "structured", Prefix: "NC"}
+
+	store := &sequenceStore{next: 41}
+	p := &eventProcessor{cfg: &config.Config{IncidentIDs: structured}, stateStore: store}
+	inc := newIncident()
+	p.assignDisplayID(context.Background(), inc)
+	if inc.DisplayID != "NC-2024-0613-prod-0042" {
+		t.Errorf("DisplayID = %q, want NC-2024-0613-prod-0042", inc.DisplayID)
+	}
+
+	// The UUID is kept when the scheme is uuid, there is no store, or allocation fails
+	cases := map[string]*eventProcessor{
+		"uuid scheme":       {cfg: &config.Config{IncidentIDs: config.IncidentIDsConfig{Scheme: "uuid"}}, stateStore: store},
+		"no state store":    {cfg: &config.Config{IncidentIDs: structured}},
+		"allocation failed": {cfg: &config.Config{IncidentIDs: structured}, stateStore: &sequenceStore{err: errors.New("locked")}},
+	}
+	for name, p := range cases {
+		inc := newIncident()
+		p.assignDisplayID(context.Background(), inc)
+		if inc.DisplayID != "" || inc.Ref() != "2f1c8e4a-uuid" {
+			t.Errorf("%s: DisplayID = %q, want none", name, inc.DisplayID)
+		}
+	}
+}
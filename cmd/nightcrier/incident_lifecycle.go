@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/reporting"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/rbias/nightcrier/internal/webhooks"
+	"github.com/spf13/cobra"
+)
+
+// expiryInterval is how often open incidents are checked for expiry
+const expiryInterval = 15 * time.Minute
+
+var incidentsTransitionCmd = &cobra.Command{
+	Use:   "transition <incident-id> <status>",
+	Short: "Move an incident to another lifecycle status",
+	Long: `Move an incident to another lifecycle status, for example to resolve an
+investigation awaiting review or to record a proposed remediation. Only the
+transitions the incident lifecycle allows are accepted (see "Incident Lifecycle"
+in the README). Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier incidents transition prod-20240501-0007 remediation_proposed
+  nightcrier incidents transition prod-20240501-0007 resolved`,
+	Args: cobra.ExactArgs(2),
+	RunE: runIncidentsTransition,
+}
+
+func init() {
+	incidentsCmd.AddCommand(incidentsTransitionCmd)
+}
+
+// setStatus moves the in-memory incident to a status, logging a change the
+// lifecycle does not allow instead of making it. The state store records the
+// status when the incident is created or completed.
+func setStatus(ctx context.Context, inc *incident.Incident, status string) {
+	if err := inc.Transition(status, time.Now()); err != nil {
+		incident.Logger(ctx).Warn("incident status not changed", "status", status, "error", err)
+	}
+}
+
+// transitionIncident moves an incident to a status, records the change in the
+// state store, and emits the status_changed lifecycle webhook.
+func (p *eventProcessor) transitionIncident(ctx context.Context, inc *incident.Incident, status string) {
+	log := incident.Logger(ctx)
+	from := inc.Status
+	if from == status {
+		return
+	}
+	if err := inc.Transition(status, time.Now()); err != nil {
+		log.Warn("incident status not changed", "status", status, "error", err)
+		return
+	}
+	if p.stateStore != nil {
+		var startedAt *time.Time
+		if status == incident.StatusInvestigating {
+			startedAt = inc.StartedAt
+		}
+		if err := p.stateStore.UpdateIncidentStatus(ctx, inc.IncidentID, status, startedAt); err != nil {
+			log.Error("failed to update incident status in state store", "status", status, "error", err)
+		}
+	}
+	log.Debug("incident status changed", "from", from, "to", status)
+	p.emitLifecycle(ctx, webhooks.EventStatusChanged, inc, map[string]string{"from": from, "to": status})
+}
+
+// applyReviewPolicy holds a resolved investigation for human review when its
+// report's confidence or contradicted claims match the review policy.
+func (p *eventProcessor) applyReviewPolicy(ctx context.Context, inc *incident.Incident, workspacePath string) {
+	lifecycle := p.cfg.IncidentLifecycle
+	if lifecycle.ReviewConfidence == "" && !lifecycle.ReviewContradictedClaims {
+		return
+	}
+	_, confidence, _ := reporting.ExtractSummaryFromReport(workspacePath)
+	var contradicted int
+	if inc.Verification != nil {
+		contradicted = inc.Verification.Contradicted
+	}
+	if !lifecycle.NeedsReview(confidence, contradicted) {
+		return
+	}
+	inc.MarkAwaitingReview()
+	incident.Logger(ctx).Info("investigation held for review",
+		"confidence", confidence,
+		"contradicted_claims", contradicted)
+}
+
+// resolveReviewedIncident resolves an incident whose findings await a human,
+// once a reviewer accepted them. It reports whether the incident was resolved.
+func resolveReviewedIncident(ctx context.Context, store storage.StateStore, inc *incident.Incident) (bool, error) {
+	if inc.Status != incident.StatusAwaitingReview && inc.Status != incident.StatusRemediationProposed {
+		return false, nil
+	}
+	if err := inc.Transition(incident.StatusResolved, time.Now()); err != nil {
+		return false, fmt.Errorf("failed to resolve incident %s: %w", inc.Ref(), err)
+	}
+	if err := store.UpdateIncidentStatus(ctx, inc.IncidentID, incident.StatusResolved, nil); err != nil {
+		return false, fmt.Errorf("failed to resolve incident %s: %w", inc.Ref(), err)
+	}
+	return true, nil
+}
+
+// runIncidentExpiry expires incidents left open longer than the lifecycle's
+// expiry, every expiry interval until ctx is cancelled.
+func runIncidentExpiry(ctx context.Context, cfg config.IncidentLifecycleConfig, store storage.StateStore) {
+	ticker := time.NewTicker(expiryInterval)
+	defer ticker.Stop()
+	for {
+		expired, err := expireIncidents(ctx, store, time.Now().Add(-cfg.ExpireAfter()))
+		if err != nil {
+			slog.Error("incident expiry failed", "error", err)
+		}
+		if expired > 0 {
+			slog.Info("expired open incidents", "count", expired, "expire_after_hours", cfg.ExpireAfterHours)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// expireIncidents moves the incidents that entered an open status before cutoff
+// to expired, and returns how many it expired.
+func expireIncidents(ctx context.Context, store storage.StateStore, cutoff time.Time) (int, error) {
+	open, err := store.ListIncidents(ctx, &storage.IncidentFilters{
+		Status:        incident.ExpirableStatuses(),
+		CreatedBefore: &cutoff,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list open incidents: %w", err)
+	}
+	var expired int
+	for _, inc := range open {
+		if entered, ok := inc.StatusTimes[inc.Status]; ok && entered.After(cutoff) {
+			continue
+		}
+		err := store.UpdateIncidentStatus(ctx, inc.IncidentID, incident.StatusExpired, nil)
+		if errors.Is(err, incident.ErrInvalidTransition) {
+			// The incident moved on since it was listed
+			continue
+		}
+		if err != nil {
+			return expired, fmt.Errorf("failed to expire incident %s: %w", inc.Ref(), err)
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func runIncidentsTransition(cmd *cobra.Command, args []string) error {
+	status := args[1]
+	if !incident.ValidStatus(status) {
+		return fmt.Errorf("unknown status %q (must be one of %v)", status, incident.Statuses)
+	}
+
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	inc, err := resolveIncident(ctx, store, args[0])
+	if err != nil {
+		return err
+	}
+	if err := store.UpdateIncidentStatus(ctx, inc.IncidentID, status, nil); err != nil {
+		return fmt.Errorf("failed to update incident %s: %w", inc.Ref(), err)
+	}
+	fmt.Printf("Incident %s: %s -> %s\n", inc.Ref(), inc.Status, status)
+	return nil
+}
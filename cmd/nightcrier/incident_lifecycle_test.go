@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// lifecycleStore lists open incidents and records status changes; other state
+// store methods are not expected.
+type lifecycleStore struct {
+	storage.StateStore
+	incidents []*incident.Incident
+	changes   map[string]string
+}
+
+func (s *lifecycleStore) ListIncidents(ctx context.Context, filters *storage.IncidentFilters) ([]*incident.Incident, error) {
+	var matched []*incident.Incident
+	for _, inc := range s.incidents {
+		for _, status := range filters.Status {
+			if inc.Status == status && inc.CreatedAt.Before(*filters.CreatedBefore) {
+				matched = append(matched, inc)
+			}
+		}
+	}
+	return matched, nil
+}
+
+func (s *lifecycleStore) UpdateIncidentStatus(ctx context.Context, incidentID, status string, startedAt *time.Time) error {
+	for _, inc := range s.incidents {
+		if inc.IncidentID != incidentID {
+			continue
+		}
+		if err := incident.ValidateTransition(inc.Status, status); err != nil {
+			return err
+		}
+		if s.changes == nil {
+			s.changes = make(map[string]string)
+		}
+		s.changes[incidentID] = status
+	}
+	return nil
+}
+
+func TestExpireIncidents(t *testing.T) {
+	now := time.Now()
+	open := func(id, status string, created, entered time.Time) *incident.Incident {
+		return &incident.Incident{
+			IncidentID:  id,
+			Status:      status,
+			CreatedAt:   created,
+			StatusTimes: map[string]time.Time{status: entered},
+		}
+	}
+	store := &lifecycleStore{incidents: []*incident.Incident{
+		open("stale-review", incident.StatusAwaitingReview, now.Add(-72*time.Hour), now.Add(-48*time.Hour)),
+		open("recent-review", incident.StatusAwaitingReview, now.Add(-72*time.Hour), now.Add(-time.Hour)),
+		open("stale-pending", incident.StatusPending, now.Add(-48*time.Hour), now.Add(-48*time.Hour)),
+		open("resolved", incident.StatusResolved, now.Add(-48*time.Hour), now.Add(-48*time.Hour)),
+		open("new", incident.StatusReceived, now, now),
+	}}
+
+	expired, err := expireIncidents(context.Background(), store, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("expireIncidents() failed: %v", err)
+	}
+	if expired != 2 || store.changes["stale-review"] != incident.StatusExpired || store.changes["stale-pending"] != incident.StatusExpired {
+		t.Errorf("expireIncidents() = %d, changes %v; want the two stale incidents expired", expired, store.changes)
+	}
+}
+
+func TestResolveReviewedIncident(t *testing.T) {
+	inc := &incident.Incident{IncidentID: "inc-1", Status: incident.StatusAwaitingReview}
+	store := &lifecycleStore{incidents: []*incident.Incident{inc}}
+
+	resolved, err := resolveReviewedIncident(context.Background(), store, inc)
+	if err != nil || !resolved || inc.Status != incident.StatusResolved || store.changes["inc-1"] != incident.StatusResolved {
+		t.Errorf("resolveReviewedIncident() = %v, %v; incident %s, changes %v", resolved, err, inc.Status, store.changes)
+	}
+	if inc.StatusTimes[incident.StatusResolved].IsZero() {
+		t.Errorf("StatusTimes = %v, want the resolved time", inc.StatusTimes)
+	}
+
+	// Incidents not held for review are left alone
+	failed := &incident.Incident{IncidentID: "inc-2", Status: incident.StatusFailed}
+	if resolved, err := resolveReviewedIncident(context.Background(), store, failed); err != nil || resolved {
+		t.Errorf("resolveReviewedIncident() of a failed incident = %v, %v; want unchanged", resolved, err)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/health"
+	"github.com/rbias/nightcrier/internal/labels"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Incidents show command flags
+	showFormat string
+	showReport bool
+)
+
+var incidentsShowCmd = &cobra.Command{
+	Use:   "show <incident-id>",
+	Short: "Show an incident with its agent executions and reports",
+	Long: `Show everything recorded about an incident: its details and labels, every agent
+execution attempt with its exit code and resource usage, and the triage reports
+the agent produced. With --report, also print the latest report.
+
+The incident is looked up by UUID or display ID. Requires a sqlite or postgres
+state store.`,
+	Example: `  nightcrier incidents show NC-2026-0114-prod-0042
+  nightcrier incidents show NC-2026-0114-prod-0042 --report
+  nightcrier incidents show 2f1c... --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIncidentsShow,
+}
+
+func init() {
+	incidentsShowCmd.Flags().StringVar(&showFormat, "format", "table", "Output format: table or json")
+	incidentsShowCmd.Flags().BoolVar(&showReport, "report", false, "Also print the latest triage report")
+	incidentsCmd.AddCommand(incidentsShowCmd)
+}
+
+// incidentBundles serves the incident API from the state store.
+type incidentBundles struct {
+	store storage.StateStore
+}
+
+func (b incidentBundles) GetIncidentBundle(ctx context.Context, id string) (interface{}, error) {
+	bundle, err := b.store.GetIncidentBundle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if bundle == nil {
+		return nil, fmt.Errorf("%w: %s", health.ErrIncidentNotFound, id)
+	}
+	return bundle, nil
+}
+
+func runIncidentsShow(cmd *cobra.Command, args []string) error {
+	if showFormat != "table" && showFormat != "json" {
+		return fmt.Errorf("unknown format %q: must be table or json", showFormat)
+	}
+
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	bundle, err := store.GetIncidentBundle(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if bundle == nil {
+		return fmt.Errorf("incident %s not found", args[0])
+	}
+
+	if showFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(bundle)
+	}
+
+	inc := bundle.Incident
+	fmt.Printf("Incident:    %s\n", inc.IncidentID)
+	if inc.DisplayID != "" {
+		fmt.Printf("Display ID:  %s\n", inc.DisplayID)
+	}
+	if since, ok := inc.StatusTimes[inc.Status]; ok {
+		fmt.Printf("Status:      %s (since %s)\n", inc.Status, since.UTC().Format(time.RFC3339))
+	} else {
+		fmt.Printf("Status:      %s\n", inc.Status)
+	}
+	fmt.Printf("Cluster:     %s\n", inc.Cluster)
+	fmt.Printf("Namespace:   %s\n", orDash(inc.Namespace))
+	if inc.Resource != nil {
+		fmt.Printf("Resource:    %s/%s\n", inc.Resource.Kind, inc.Resource.Name)
+	}
+	fmt.Printf("Fault:       %s (%s)\n", inc.FaultType, inc.Severity)
+	fmt.Printf("Created:     %s\n", inc.CreatedAt.UTC().Format(time.RFC3339))
+	if inc.CompletedAt != nil {
+		fmt.Printf("Completed:   %s\n", inc.CompletedAt.UTC().Format(time.RFC3339))
+	}
+	if inc.FailureClass != "" || inc.FailureReason != "" {
+		fmt.Printf("Failure:     %s %s\n", inc.FailureClass, inc.FailureReason)
+	}
+	if inc.ParentIncidentID != "" {
+		fmt.Printf("Follows up:  %s\n", inc.ParentIncidentID)
+	}
+	fmt.Printf("Labels:      %s\n", labels.Format(inc.Labels))
+	if len(inc.Annotations) > 0 {
+		fmt.Printf("Annotations: %s\n", labels.Format(inc.Annotations))
+	}
+
+	if len(bundle.Executions) == 0 {
+		fmt.Println("\nNo agent executions recorded")
+	} else {
+		fmt.Printf("\n%-20s %-10s %-5s %-8s %-24s %s\n", "STARTED (UTC)", "DURATION", "EXIT", "CPU", "AGENT", "ERROR")
+		for _, exec := range bundle.Executions {
+			duration, exitCode, cpu := "running", "-", "-"
+			if exec.CompletedAt != nil {
+				duration = exec.CompletedAt.Sub(exec.StartedAt).Round(time.Second).String()
+			}
+			if exec.ExitCode != nil {
+				exitCode = strconv.Itoa(*exec.ExitCode)
+			}
+			if exec.Resources != nil {
+				cpu = fmt.Sprintf("%.1fs", exec.Resources.CPUSeconds)
+			}
+			fmt.Printf("%-20s %-10s %-5s %-8s %-24s %s\n",
+				exec.StartedAt.UTC().Format("2006-01-02 15:04:05"),
+				duration,
+				exitCode,
+				cpu,
+				truncateString(orDash(strings.TrimSpace(exec.AgentCLI+" "+exec.AgentModel)), 24),
+				orDash(exec.ErrorMessage))
+		}
+	}
+
+	if len(bundle.Reports) == 0 {
+		fmt.Println("\nNo triage reports recorded")
+		return nil
+	}
+	fmt.Printf("\n%-20s %-36s %s\n", "REPORT (UTC)", "EXECUTION", "SIZE")
+	for _, report := range bundle.Reports {
+		fmt.Printf("%-20s %-36s %s\n",
+			report.GeneratedAt.UTC().Format("2006-01-02 15:04:05"),
+			report.ExecutionID,
+			formatBytes(int64(len(report.ReportMarkdown))))
+	}
+	if showReport {
+		fmt.Printf("\n%s\n", bundle.Reports[len(bundle.Reports)-1].ReportMarkdown)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/labels"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Incidents command flags
+	incidentsLimit   int
+	incidentsCluster string
+	incidentsSource  string
+	incidentsStatus  []string
+	incidentsLabels  []string
+
+	// Incidents label command flags
+	labelAnnotations []string
+	labelRemove      []string
+)
+
+var incidentsCmd = &cobra.Command{
+	Use:   "incidents",
+	Short: "List recent incidents and their labels",
+	Long: `List the incidents kept in the SQL state store, newest first.
+
+Incidents can be filtered by cluster, event source, status, and labels. Labels are attached from
+cluster labels, incident_labels rules, the agent's findings, and manually with
+"nightcrier incidents label". Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier incidents --label team=payments
+  nightcrier incidents --cluster prod --status failed --limit 50
+  nightcrier incidents --source alerts`,
+	Args: cobra.NoArgs,
+	RunE: runIncidents,
+}
+
+var incidentsLabelCmd = &cobra.Command{
+	Use:   "label <incident-id> [key=value ...]",
+	Short: "Add or remove incident labels and annotations",
+	Long: `Add labels (key=value arguments) and annotations (--annotate key=value) to an
+incident, or remove them by key (--remove key). Existing keys are replaced.
+Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier incidents label 2f1c... team=payments cost-center=cc-4711
+  nightcrier incidents label 2f1c... --annotate note="Caused by the 14:00 deploy"
+  nightcrier incidents label 2f1c... --remove cost-center`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runIncidentsLabel,
+}
+
+func init() {
+	incidentsCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to config file (used to locate the state store)")
+	incidentsCmd.Flags().IntVar(&incidentsLimit, "limit", 20, "Number of incidents to show (0 for all)")
+	incidentsCmd.Flags().StringVar(&incidentsCluster, "cluster", "", "Only show incidents from this cluster")
+	incidentsCmd.Flags().StringVar(&incidentsSource, "source", "", "Only show incidents from this event source")
+	incidentsCmd.Flags().StringSliceVar(&incidentsStatus, "status", nil, "Only show incidents with this status (repeatable)")
+	incidentsCmd.Flags().StringArrayVarP(&incidentsLabels, "label", "l", nil, "Only show incidents with this key=value label (repeatable)")
+
+	incidentsLabelCmd.Flags().StringArrayVar(&labelAnnotations, "annotate", nil, "Add a key=value annotation (repeatable)")
+	incidentsLabelCmd.Flags().StringArrayVar(&labelRemove, "remove", nil, "Remove the label or annotation with this key (repeatable)")
+
+	incidentsCmd.AddCommand(incidentsLabelCmd)
+	rootCmd.AddCommand(incidentsCmd)
+}
+
+// attachConfiguredLabels adds the cluster's labels (with incident_labels.from_cluster)
+// and the labels of matching incident_labels rules to a new incident.
+func (p *eventProcessor) attachConfiguredLabels(inc *incident.Incident) {
+	if p.cfg.IncidentLabels.FromCluster {
+		for _, cl := range p.cfg.Clusters {
+			if cl.Name == inc.Cluster {
+				inc.AddLabels(cl.Labels, nil)
+				break
+			}
+		}
+	}
+	inc.ApplyLabelRules(p.cfg.IncidentLabels.LabelRules())
+}
+
+// attachAgentLabels adds the labels and annotations the agent recorded in the
+// workspace to the incident and the state store. Invalid label files are logged
+// and ignored; they never fail the investigation.
+func (p *eventProcessor) attachAgentLabels(ctx context.Context, inc *incident.Incident, workspacePath string) {
+	log := incident.Logger(ctx)
+	agentLabels, agentAnnotations, err := labels.ReadAgentFile(filepath.Join(workspacePath, labels.AgentFile))
+	if err != nil {
+		log.Warn("ignoring labels recorded by the agent", "error", err)
+		return
+	}
+	if len(agentLabels) == 0 && len(agentAnnotations) == 0 {
+		return
+	}
+
+	inc.AddLabels(agentLabels, agentAnnotations)
+	log.Info("attached labels from agent findings",
+		"labels", labels.Format(agentLabels),
+		"annotation_count", len(agentAnnotations))
+
+	if p.stateStore != nil {
+		if err := p.stateStore.SetIncidentLabels(ctx, inc.IncidentID, agentLabels, agentAnnotations); err != nil {
+			log.Error("failed to record agent labels in state store", "error", err)
+		}
+	}
+}
+
+// openIncidentStore loads the configuration and opens its SQL state store for the
+// incidents commands.
+func openIncidentStore(ctx context.Context) (storage.StateStore, error) {
+	_, store, err := loadIncidentStore(ctx)
+	return store, err
+}
+
+// loadIncidentStore is openIncidentStore for commands that also need the
+// configuration.
+func loadIncidentStore(ctx context.Context) (*config.Config, storage.StateStore, error) {
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	setupLogging("warn")
+
+	store, err := openStateStore(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if store == nil {
+		return nil, nil, fmt.Errorf("incident queries require a sqlite or postgres state store (state_storage.type is %q)", cfg.GetStateStorageType())
+	}
+	return cfg, store, nil
+}
+
+func runIncidents(cmd *cobra.Command, args []string) error {
+	labelFilter, err := labels.Parse(incidentsLabels)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	incidents, err := store.ListIncidents(ctx, &storage.IncidentFilters{
+		Status:  incidentsStatus,
+		Cluster: incidentsCluster,
+		Source:  incidentsSource,
+		Labels:  labelFilter,
+		Limit:   incidentsLimit,
+	})
+	if err != nil {
+		return err
+	}
+	if len(incidents) == 0 {
+		fmt.Println("No incidents found")
+		return nil
+	}
+
+	fmt.Printf("%-20s %-36s %-16s %-8s %-13s %-24s %s\n", "CREATED (UTC)", "INCIDENT", "CLUSTER", "SEVERITY", "STATUS", "FAULT", "LABELS")
+	for _, inc := range incidents {
+		fmt.Printf("%-20s %-36s %-16s %-8s %-13s %-24s %s\n",
+			inc.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+			inc.Ref(),
+			truncateString(inc.Cluster, 16),
+			inc.Severity,
+			inc.Status,
+			truncateString(inc.FaultType, 24),
+			labels.Format(inc.Labels))
+	}
+	return nil
+}
+
+func runIncidentsLabel(cmd *cobra.Command, args []string) error {
+	incidentID := args[0]
+	addLabels, err := labels.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	addAnnotations, err := labels.Parse(labelAnnotations)
+	if err != nil {
+		return err
+	}
+	if err := labels.Validate(addLabels, addAnnotations); err != nil {
+		return err
+	}
+	if len(addLabels) == 0 && len(addAnnotations) == 0 && len(labelRemove) == 0 {
+		return fmt.Errorf("nothing to change: pass key=value labels, --annotate, or --remove")
+	}
+
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	resolved, err := resolveIncident(ctx, store, incidentID)
+	if err != nil {
+		return err
+	}
+	incidentID = resolved.IncidentID
+
+	if len(labelRemove) > 0 {
+		if err := store.RemoveIncidentLabels(ctx, incidentID, labelRemove); err != nil {
+			return err
+		}
+	}
+	if len(addLabels) > 0 || len(addAnnotations) > 0 {
+		if err := store.SetIncidentLabels(ctx, incidentID, addLabels, addAnnotations); err != nil {
+			return err
+		}
+	}
+
+	inc, err := store.GetIncident(ctx, incidentID)
+	if err != nil {
+		return err
+	}
+	if inc == nil {
+		return fmt.Errorf("incident not found: %s", incidentID)
+	}
+	fmt.Printf("Labels:      %s\n", labels.Format(inc.Labels))
+	fmt.Printf("Annotations: %s\n", labels.Format(inc.Annotations))
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/metrics"
+)
+
+// latencyMetrics are the histograms of the event-to-notification latency, per
+// cluster and severity.
+type latencyMetrics struct {
+	stages *metrics.HistogramVec
+	total  *metrics.HistogramVec
+}
+
+// newLatencyMetrics registers the latency histograms.
+func newLatencyMetrics(registry *metrics.Registry) *latencyMetrics {
+	return &latencyMetrics{
+		stages: registry.Register(metrics.NewHistogramVec(
+			"nightcrier_incident_stage_duration_seconds",
+			"Duration of each stage between receiving a fault event and notifying about its incident.",
+			metrics.LatencyBuckets, "cluster", "severity", "stage")),
+		total: registry.Register(metrics.NewHistogramVec(
+			"nightcrier_event_to_notification_seconds",
+			"Time from receiving a fault event to notifying about its incident.",
+			metrics.LatencyBuckets, "cluster", "severity")),
+	}
+}
+
+// recordLatency completes the incident's latency breakdown with the end-to-end
+// duration since the fault event was received and exports it to the latency
+// histograms.
+func (p *eventProcessor) recordLatency(ctx context.Context, inc *incident.Incident, receivedAt time.Time) {
+	if inc.Latency == nil {
+		return
+	}
+	total := time.Since(receivedAt)
+	inc.Latency.SetTotal(total)
+
+	if p.latency != nil {
+		for _, stage := range inc.Latency.Stages() {
+			p.latency.stages.Observe(stage.Duration.Seconds(), inc.Cluster, inc.Severity, stage.Stage)
+		}
+		p.latency.total.Observe(total.Seconds(), inc.Cluster, inc.Severity)
+	}
+
+	attrs := []any{"total_ms", total.Milliseconds()}
+	for _, stage := range inc.Latency.Stages() {
+		attrs = append(attrs, stage.Stage+"_ms", stage.Duration.Milliseconds())
+	}
+	incident.Logger(ctx).Info("incident latency", attrs...)
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/labels"
+	"github.com/rbias/nightcrier/internal/webhooks"
+	"github.com/spf13/cobra"
+)
+
+// Labels recording who acknowledged and resolved an incident
+const (
+	acknowledgedByLabel = "acknowledged_by"
+	resolvedByLabel     = "resolved_by"
+)
+
+var (
+	// Incidents ack and resolve command flags
+	lifecycleActor string
+)
+
+var incidentsAckCmd = &cobra.Command{
+	Use:   "ack <incident-id>",
+	Short: "Acknowledge an incident",
+	Long: `Acknowledge an incident: record who took it with the acknowledged_by label and
+emit the "acknowledged" lifecycle webhook. Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier incidents ack prod-20240501-0007 --by alice`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runIncidentsLifecycle(args[0], webhooks.EventAcknowledged, acknowledgedByLabel)
+	},
+}
+
+var incidentsResolveCmd = &cobra.Command{
+	Use:   "resolve <incident-id>",
+	Short: "Mark an incident's fault as fixed",
+	Long: `Mark an incident's fault as fixed: record who fixed it with the resolved_by label
+and emit the "resolved" lifecycle webhook. An incident awaiting review or with a
+proposed remediation moves to resolved; other statuses are unchanged. Requires a
+sqlite or postgres state store.`,
+	Example: `  nightcrier incidents resolve prod-20240501-0007 --by alice`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runIncidentsLifecycle(args[0], webhooks.EventResolved, resolvedByLabel)
+	},
+}
+
+func init() {
+	incidentsAckCmd.Flags().StringVar(&lifecycleActor, "by", os.Getenv("USER"), "Who is acknowledging the incident")
+	incidentsResolveCmd.Flags().StringVar(&lifecycleActor, "by", os.Getenv("USER"), "Who fixed the fault")
+	incidentsCmd.AddCommand(incidentsAckCmd, incidentsResolveCmd)
+}
+
+// newWebhookClient returns the lifecycle webhook client, or nil when no endpoint
+// is configured.
+func newWebhookClient(cfg config.LifecycleWebhooksConfig) *webhooks.Client {
+	if !cfg.Enabled() {
+		return nil
+	}
+	return webhooks.New(cfg.WebhookEndpoints(), &http.Client{Timeout: cfg.Timeout()})
+}
+
+// webhookIncident returns the incident as carried in a webhook envelope.
+func webhookIncident(inc *incident.Incident) webhooks.Incident {
+	wi := webhooks.Incident{
+		ID:               inc.IncidentID,
+		DisplayID:        inc.DisplayID,
+		Status:           inc.Status,
+		Cluster:          inc.Cluster,
+		Namespace:        inc.Namespace,
+		FaultType:        inc.FaultType,
+		Severity:         inc.Severity,
+		ParentIncidentID: inc.ParentIncidentID,
+		Labels:           inc.Labels,
+		CreatedAt:        inc.CreatedAt.UTC(),
+	}
+	if inc.Resource != nil {
+		wi.Kind, wi.Name = inc.Resource.Kind, inc.Resource.Name
+	}
+	return wi
+}
+
+// webhookJob is a lifecycle event to deliver to one endpoint from the outbox.
+type webhookJob struct {
+	Endpoint string             `json:"endpoint"`
+	Event    *webhooks.Envelope `json:"event"`
+}
+
+// deliverWebhook is the outbox handler of webhook jobs. Events the receiver
+// rejected are dropped, since retrying them cannot succeed.
+func (p *eventProcessor) deliverWebhook(ctx context.Context, payload json.RawMessage) error {
+	var job webhookJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("failed to decode webhook: %w", err)
+	}
+	if p.webhooks == nil || job.Event == nil {
+		return nil
+	}
+	err := p.webhooks.Deliver(ctx, job.Endpoint, job.Event)
+	if errors.Is(err, webhooks.ErrRejected) {
+		slog.Warn("dropping rejected webhook",
+			"incident_id", job.Event.Incident.ID,
+			"webhook", job.Endpoint,
+			"event", job.Event.Type,
+			"error", err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	slog.Info("webhook delivered",
+		"incident_id", job.Event.Incident.ID,
+		"webhook", job.Endpoint,
+		"event", job.Event.Type)
+	return nil
+}
+
+// emitLifecycle queues a lifecycle event for every subscribed webhook endpoint,
+// delivering it directly when the outbox is unavailable. Failures are logged; they
+// never fail the investigation.
+func (p *eventProcessor) emitLifecycle(ctx context.Context, eventType string, inc *incident.Incident, data map[string]string) {
+	if p.webhooks == nil {
+		return
+	}
+	log := incident.Logger(ctx)
+	env := webhooks.NewEnvelope(eventType, webhookIncident(inc), data)
+	for _, endpoint := range p.webhooks.Targets(eventType) {
+		if p.outbox != nil {
+			err := p.outbox.Enqueue(outboxWebhook, webhookJob{Endpoint: endpoint, Event: env})
+			if err == nil {
+				log.Debug("webhook queued", "webhook", endpoint, "event", eventType)
+				continue
+			}
+			log.Warn("failed to queue webhook, sending directly", "webhook", endpoint, "error", err)
+		}
+		if err := p.webhooks.Deliver(ctx, endpoint, env); err != nil {
+			log.Error("failed to deliver webhook", "webhook", endpoint, "event", eventType, "error", err)
+		}
+	}
+}
+
+// emitOutcome emits the completed or failed event of a finished investigation.
+func (p *eventProcessor) emitOutcome(ctx context.Context, inc *incident.Incident, data map[string]string) {
+	if data == nil {
+		data = make(map[string]string)
+	}
+	if inc.ExitCode != nil {
+		data["exit_code"] = strconv.Itoa(*inc.ExitCode)
+	}
+	if incident.Investigated(inc.Status) {
+		p.emitLifecycle(ctx, webhooks.EventCompleted, inc, data)
+		return
+	}
+	data["failure_reason"] = inc.FailureReason
+	data["failure_class"] = inc.FailureClass
+	p.emitLifecycle(ctx, webhooks.EventFailed, inc, data)
+}
+
+func runIncidentsLifecycle(id, eventType, label string) error {
+	if lifecycleActor == "" {
+		return fmt.Errorf("--by is required when $USER is not set")
+	}
+	change := map[string]string{label: lifecycleActor}
+	if err := labels.Validate(change, nil); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	inc, err := resolveIncident(ctx, store, id)
+	if err != nil {
+		return err
+	}
+	if err := store.SetIncidentLabels(ctx, inc.IncidentID, change, nil); err != nil {
+		return err
+	}
+	inc.AddLabels(change, nil)
+	if eventType == webhooks.EventResolved {
+		if _, err := resolveReviewedIncident(ctx, store, inc); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Incident %s %s by %s\n", inc.Ref(), eventType, lifecycleActor)
+
+	return sendLifecycleWebhooks(ctx, newWebhookClient(cfg.LifecycleWebhooks), eventType, inc, lifecycleActor)
+}
+
+// sendLifecycleWebhooks delivers an event from a CLI command, directly rather than
+// through the outbox, reporting each endpoint's outcome.
+func sendLifecycleWebhooks(ctx context.Context, client *webhooks.Client, eventType string, inc *incident.Incident, actor string) error {
+	if client == nil {
+		return nil
+	}
+	env := webhooks.NewEnvelope(eventType, webhookIncident(inc), map[string]string{"by": actor})
+	var failed int
+	for _, endpoint := range client.Targets(eventType) {
+		if err := client.Deliver(ctx, endpoint, env); err != nil {
+			fmt.Fprintf(os.Stderr, "Webhook %s: %v\n", endpoint, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Webhook %s: delivered\n", endpoint)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d webhook deliveries failed", failed)
+	}
+	return nil
+}
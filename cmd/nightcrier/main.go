@@ -3,28 +3,66 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rbias/nightcrier/internal/agent"
+	"github.com/rbias/nightcrier/internal/aggregation"
+	"github.com/rbias/nightcrier/internal/budget"
 	"github.com/rbias/nightcrier/internal/cluster"
 	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/dedup"
+	"github.com/rbias/nightcrier/internal/dialer"
+	"github.com/rbias/nightcrier/internal/enrichment"
 	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/faultlock"
 	"github.com/rbias/nightcrier/internal/health"
 	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/incidentfeed"
+	"github.com/rbias/nightcrier/internal/incluster"
+	"github.com/rbias/nightcrier/internal/keypool"
+	"github.com/rbias/nightcrier/internal/knowledgebase"
+	"github.com/rbias/nightcrier/internal/labels"
+	"github.com/rbias/nightcrier/internal/logging"
+	"github.com/rbias/nightcrier/internal/metrics"
+	"github.com/rbias/nightcrier/internal/operator"
+	"github.com/rbias/nightcrier/internal/outbox"
+	"github.com/rbias/nightcrier/internal/ownership"
+	"github.com/rbias/nightcrier/internal/pacing"
+	"github.com/rbias/nightcrier/internal/pause"
+	"github.com/rbias/nightcrier/internal/postmortem"
+	"github.com/rbias/nightcrier/internal/providerstatus"
+	"github.com/rbias/nightcrier/internal/proxy"
 	"github.com/rbias/nightcrier/internal/reporting"
+	"github.com/rbias/nightcrier/internal/runbooks"
+	"github.com/rbias/nightcrier/internal/sampling"
+	"github.com/rbias/nightcrier/internal/searchindex"
+	"github.com/rbias/nightcrier/internal/servicenow"
+	"github.com/rbias/nightcrier/internal/severitypolicy"
+	"github.com/rbias/nightcrier/internal/shortener"
+	"github.com/rbias/nightcrier/internal/silence"
 	"github.com/rbias/nightcrier/internal/skills"
+	"github.com/rbias/nightcrier/internal/slackapp"
+	"github.com/rbias/nightcrier/internal/sources"
 	"github.com/rbias/nightcrier/internal/storage"
 	"github.com/rbias/nightcrier/internal/storage/postgres"
 	"github.com/rbias/nightcrier/internal/storage/sqlite"
+	"github.com/rbias/nightcrier/internal/topology"
+	"github.com/rbias/nightcrier/internal/verify"
+	"github.com/rbias/nightcrier/internal/webhooks"
 	"github.com/spf13/cobra"
 )
 
@@ -42,8 +80,20 @@ var (
 	logLevel      string
 	agentTimeout  int
 	healthPort    int
+	bannerFormat  string
 )
 
+// Startup banner formats (--banner)
+const (
+	bannerNone = "none"
+	bannerText = "text"
+	bannerJSON = "json"
+)
+
+// errOperatorConfigChanged ends the run when the operator resources change; the
+// non-zero exit makes the Deployment restart nightcrier with the new configuration.
+var errOperatorConfigChanged = errors.New("operator resources changed, restarting to apply them")
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -65,21 +115,28 @@ func init() {
 	// Configuration file flag
 	rootCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to config file (default: searches for config.yaml in ., ./configs, /etc/nightcrier)")
 
+	// Config profile flag, shared by the subcommands that load the configuration
+	rootCmd.PersistentFlags().String("profile", "", "Config profile: merges <config>.<profile>.yaml and clusters.<profile>.d/ over the config file (overrides CONFIG_PROFILE env var)")
+
 	// Override flags (take precedence over config file and env vars)
-	rootCmd.Flags().StringVar(&mcpEndpoint, "mcp-endpoint", "", "MCP server endpoint URL (overrides config file and K8S_CLUSTER_MCP_ENDPOINT env var)")
+	rootCmd.Flags().StringVar(&mcpEndpoint, "mcp-endpoint", "", "MCP server endpoint URL for single-cluster mode (overrides config file and K8S_CLUSTER_MCP_ENDPOINT env var)")
+	rootCmd.Flags().Bool("single-cluster", false, "Single-cluster compatibility mode: ignore the clusters array and use --mcp-endpoint with kubeconfig_path")
 	rootCmd.Flags().StringVar(&workspaceRoot, "workspace-root", "", "Workspace root directory (overrides config file and WORKSPACE_ROOT env var)")
 	rootCmd.Flags().StringVar(&scriptPath, "script-path", "", "Path to agent script")
 	rootCmd.Flags().StringVar(&logLevel, "log-level", "", "Log level: debug, info, warn, error (overrides config file and LOG_LEVEL env var)")
 	rootCmd.Flags().IntVar(&agentTimeout, "agent-timeout", 0, "Agent execution timeout in seconds (overrides config file and AGENT_TIMEOUT env var)")
+	rootCmd.Flags().StringVar(&bannerFormat, "banner", bannerText, "Startup banner: text (human-readable box), json (one line of structured configuration, secrets masked), or none")
+	rootCmd.Flags().Bool("strict-config", false, "Require every setting with a managed default to be set explicitly (overrides config file and STRICT_CONFIG env var)")
 
 	// Health monitoring flags
 	rootCmd.Flags().IntVar(&healthPort, "health-port", 8080, "Port for health monitoring HTTP endpoint (0 to disable)")
 
 	// Bind flags to viper for precedence handling
 	config.BindFlags(rootCmd.Flags())
+	config.BindFlags(rootCmd.PersistentFlags())
 }
 
-func run(cmd *cobra.Command, args []string) error {
+func run(cmd *cobra.Command, args []string) (runErr error) {
 	// Handle --version flag
 	versionFlag, _ := cmd.Flags().GetBool("version")
 	if versionFlag {
@@ -89,6 +146,12 @@ func run(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	switch bannerFormat {
+	case bannerNone, bannerText, bannerJSON:
+	default:
+		return fmt.Errorf("invalid --banner %q: must be none, text, or json", bannerFormat)
+	}
+
 	// Load configuration with precedence: flags > env vars > config file > defaults
 	cfg, err := config.LoadWithConfigFile(configFile)
 	if err != nil {
@@ -101,18 +164,106 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load tuning configuration: %w", err)
 	}
 
-	// Setup structured logging
-	setupLogging(cfg.LogLevel)
+	// Setup structured logging, with the per-component levels of the tuning
+	logHandler := setupLogging(cfg.LogLevel)
+	applyComponentLevels(logHandler, tuning)
+	if len(cfg.ConfigLayers) > 0 {
+		slog.Info("config overlays merged", "profile", cfg.Profile, "overlays", cfg.ConfigLayers)
+	}
 	slog.Info("tuning configuration loaded")
 
-	// Ensure skills are cached (non-fatal - agent will run triage itself if cloning fails)
-	if err := skills.EnsureSkillsCached(cfg.Skills.CacheDir); err != nil {
+	// The tuning can be changed at runtime (SIGHUP or the tuning API); components
+	// that honor runtime changes read it from the store
+	tuningStore := config.NewTuningStore("", tuning)
+	tuningStore.OnChange(func(t *config.TuningConfig) { applyComponentLevels(logHandler, t) })
+
+	// Operator mode: clusters and the investigation policy are declared as custom
+	// resources, and incidents are recorded as Incident resources
+	var operatorCtl *operator.Operator
+	var operatorSnapshot *operator.Snapshot
+	var incidentResources *operator.IncidentResources
+	if cfg.Operator.Enabled {
+		kubeClient, err := incluster.NewInClusterClient()
+		if err != nil {
+			return fmt.Errorf("failed to create Kubernetes client for operator mode: %w", err)
+		}
+		operatorCtl = operator.New(kubeClient, cfg.Operator)
+		operatorSnapshot, err = operatorCtl.Load(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to load operator resources: %w", err)
+		}
+		if err := operatorSnapshot.Apply(cfg); err != nil {
+			return err
+		}
+		if !cfg.Operator.DisableIncidentResources {
+			incidentResources = operator.NewIncidentResources(kubeClient, cfg.Operator.Namespace)
+		}
+		slog.Info("operator mode: configuration loaded from custom resources",
+			"namespace", cfg.Operator.Namespace,
+			"clusters", len(cfg.Clusters),
+			"policy", cfg.Operator.PolicyName,
+			"policy_found", operatorSnapshot.Policy != nil,
+			"incident_resources", incidentResources != nil)
+	}
+
+	if cfg.Offline.Enabled {
+		slog.Info("offline mode enabled: skills and runbooks are loaded from their cache directories only",
+			"skills_cache_dir", cfg.Skills.CacheDir)
+	}
+
+	// Ensure skill bundles are cached (non-fatal - agent will run triage itself if downloading fails)
+	skillsManifest, err := cfg.Skills.LoadManifest()
+	if err != nil {
+		return fmt.Errorf("failed to load skills manifest: %w", err)
+	}
+	skillsManager := skills.NewManager(cfg.Skills.CacheDir, skillsManifest.Bundles())
+	skillsManager.SetOffline(cfg.Offline.Enabled)
+
+	// Verify the agent image, and skill bundles as they are fetched, before agents run them
+	supplyChain := newSupplyChainVerifier(cfg)
+	agentImage, err := verifiedAgentImage(context.Background(), supplyChain, cfg.AgentImage)
+	if err != nil {
+		return err
+	}
+	if supplyChain != nil {
+		skillsManager.SetVerifier(supplyChain)
+		slog.Info("supply chain verification enabled",
+			"policy", cfg.SupplyChain.Policy,
+			"agent_image", agentImage,
+			"audit_log", cfg.SupplyChain.AuditLog)
+	}
+	// Read the agent CLI version and image digest investigations run with, and
+	// compare them with the pinned versions
+	runtimeProber := agent.NewRuntimeProber(!cfg.Offline.Enabled)
+	if err := checkAgentPinsAtStartup(context.Background(), cfg, runtimeProber, agentImage); err != nil {
+		return err
+	}
+
+	if err := skillsManager.Sync(context.Background(), false); err != nil {
 		slog.Warn("failed to ensure skills are cached - agent will run triage itself",
 			"error", err)
 	}
 
+	// Clone the runbooks repository, if runbooks come from git (non-fatal - investigations run without runbooks)
+	var runbooksManager *skills.Manager
+	if cfg.Runbooks.Git != "" {
+		runbooksManager = skills.NewManager(cfg.Runbooks.CacheDir, []skills.Bundle{cfg.Runbooks.Bundle()})
+		runbooksManager.SetOffline(cfg.Offline.Enabled)
+		if err := runbooksManager.Sync(context.Background(), false); err != nil {
+			slog.Warn("failed to clone runbooks repository - investigations will run without runbooks",
+				"error", err)
+		}
+	}
+
 	// Print startup banner
-	printStartupBanner(cfg, config.GetConfigFile())
+	switch bannerFormat {
+	case bannerText:
+		printStartupBanner(cfg, config.GetConfigFile())
+	case bannerJSON:
+		if err := printStartupBannerJSON(os.Stdout, cfg, config.GetConfigFile()); err != nil {
+			slog.Warn("failed to print startup banner", "error", err)
+		}
+	}
 
 	// Determine script path (CLI flag overrides config)
 	agentScript := scriptPath
@@ -138,59 +289,196 @@ func run(cmd *cobra.Command, args []string) error {
 		GlobalQueueSize:            cfg.GlobalQueueSize,
 		QueueOverflowPolicy:        cfg.QueueOverflowPolicy,
 		SSEReconnectInitialBackoff: cfg.SSEReconnectInitialBackoff,
+		SpillDir:                   cfg.QueueSpillDir,
+		SpillMaxEvents:             cfg.QueueSpillMaxEvents,
+		DecodeSpilledEvent:         decodeSpilledEvent,
+		FastStart:                  cfg.ClusterStartup.FastStart,
+		ValidationTimeout:          time.Duration(cfg.ClusterStartup.ValidationTimeoutSeconds) * time.Second,
+		ValidationConcurrency:      cfg.ClusterStartup.ValidationConcurrency,
+		LazyConnect:                cfg.ClusterStartup.LazyConnect(),
 	}
 	connectionMgr, err := cluster.NewConnectionManager(mgrConfig)
 	if err != nil {
 		return fmt.Errorf("failed to create connection manager: %w", err)
 	}
 
-	// Create and inject MCP clients for each cluster
+	// Triage can be paused globally or per cluster at runtime (admin API, triage
+	// pause command, Slack app). The switch is persisted, so a pause survives
+	// restarts.
+	pauses, err := pause.Open(cfg.PauseFile, clusterNames(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to load triage pause state: %w", err)
+	}
+	connectionMgr.SetPauseSwitch(pauses)
+	if state := pauses.State(); state.Global != nil {
+		slog.Warn("triage is paused on every cluster, no agents will be launched",
+			"paused_by", state.Global.PausedBy,
+			"reason", state.Global.Reason)
+	} else if paused := state.PausedClusters(); len(paused) > 0 {
+		slog.Warn("triage is paused on some clusters", "clusters", paused)
+	}
+
+	// Metrics served on /metrics by the health server
+	metricsRegistry := metrics.NewRegistry()
+
+	// Oversized/malformed incoming events are quarantined in one directory, and
+	// counted per cluster
+	eventQuarantine := events.NewQuarantine(cfg.QuarantineDir)
+	eventQuarantine.SetMetrics(events.NewQuarantineMetrics(metricsRegistry))
+
+	// Each cluster's event sources (its MCP server by default; Alertmanager,
+	// kubewatch, Kafka, or HTTP when configured) are merged by a pipeline into the
+	// stream the connection manager queues
+	mcpTransport := func() *http.Transport {
+		// Proxy-aware, with configured dialer (IP family, DNS)
+		transport := proxy.NewTransport(cfg.Proxy.MCPSettings())
+		transport.DialContext = dialer.DialContext(cfg.Network.DialerConfig())
+		return transport
+	}
+	sourceRegistry := sources.NewRegistry(sources.Options{
+		BufferSize: tuning.Events.ChannelBufferSize,
+		HTTPClient: &http.Client{Transport: mcpTransport()},
+	})
+	sourceRegistry.Register(cluster.SourceMCP, func(clusterName string, srcCfg cluster.SourceConfig) (sources.Source, error) {
+		mcpClient := events.NewClient(srcCfg.Endpoint, cfg.SubscribeMode, tuning)
+		// Per-source transport, with the connection read limit
+		mcpClient.SetHTTPClient(&http.Client{Transport: &events.ReadLimitTransport{
+			Base:           mcpTransport(),
+			BytesPerMinute: int64(tuning.Events.MaxConnectionReadBytesPerMinute),
+		}})
+		mcpClient.SetQuarantine(eventQuarantine.ForCluster(clusterName))
+		return sources.ClientSource(srcCfg.Name, cluster.SourceMCP, mcpClient), nil
+	})
 	for _, clusterCfg := range cfg.Clusters {
-		mcpClient := events.NewClient(clusterCfg.MCP.Endpoint, cfg.SubscribeMode, tuning)
-		if err := connectionMgr.SetClusterClient(clusterCfg.Name, mcpClient); err != nil {
+		pipeline, err := sources.NewPipeline(clusterCfg.Name, clusterCfg.EventSources(), sourceRegistry)
+		if err != nil {
+			return fmt.Errorf("failed to create event sources: %w", err)
+		}
+		if err := connectionMgr.SetClusterClient(clusterCfg.Name, pipeline); err != nil {
 			return fmt.Errorf("failed to set client for cluster %s: %w", clusterCfg.Name, err)
 		}
-		slog.Info("mcp client created for cluster",
-			"cluster", clusterCfg.Name,
-			"endpoint", clusterCfg.MCP.Endpoint)
+		for _, src := range pipeline.Sources() {
+			slog.Info("event source created for cluster",
+				"cluster", clusterCfg.Name,
+				"source", src.Name(),
+				"source_type", src.Type())
+		}
 	}
 
 	workspaceMgr := agent.NewWorkspaceManager(cfg.WorkspaceRoot)
+	if cfg.WorkspaceScratchDir != "" {
+		workspaceMgr = agent.NewScratchWorkspaceManager(cfg.WorkspaceRoot, cfg.WorkspaceScratchDir)
+		slog.Info("active workspaces use scratch directory", "scratch_dir", cfg.WorkspaceScratchDir)
+	}
 
-	// Create executors per cluster (each cluster has its own kubeconfig)
+	// Confine the agents' network access to the LLM endpoint and their cluster
+	egressPolicies, err := agentEgressPolicies(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Create executors per cluster (each cluster has its own kubeconfig and skill selection)
 	executors := make(map[string]*agent.Executor)
+	clusterSkills := make(map[string][]skills.Skill)
 	for _, clusterCfg := range cfg.Clusters {
+		clusterSkills[clusterCfg.Name] = skillsManifest.ForCluster(clusterCfg.Labels)
+		// Without a manifest every cluster gets the whole skills cache directory
+		var selectedSkills []string
+		if cfg.Skills.Manifest != "" {
+			selectedSkills = skills.Names(clusterSkills[clusterCfg.Name])
+		}
 		executors[clusterCfg.Name] = agent.NewExecutorWithConfig(agent.ExecutorConfig{
 			ScriptPath:           agentScript,
 			SystemPromptFile:     cfg.AgentSystemPromptFile,
 			AllowedTools:         cfg.AgentAllowedTools,
-			Model:                cfg.AgentModel,
+			Model:                cfg.EffectiveAgentModel(),
 			Timeout:              cfg.AgentTimeout,
 			AgentCLI:             cfg.AgentCLI,
-			AgentImage:           cfg.AgentImage,
+			AgentImage:           agentImage,
 			AdditionalPrompt:     cfg.AdditionalAgentPrompt,
+			ReportLanguage:       cfg.ReportLanguage,
 			Debug:                cfg.LogLevel == "debug",
 			Verbose:              cfg.AgentVerbose || cfg.LogLevel == "debug",
 			Kubeconfig:           clusterCfg.Triage.Kubeconfig,
 			SkillsCacheDir:       cfg.Skills.CacheDir,
+			SelectedSkills:       selectedSkills,
 			DisableTriagePreload: cfg.Skills.DisableTriagePreload,
+			ProxyEnv:             cfg.Proxy.LLMSettings().Environment(),
+			LLMProviderEnv:       cfg.LLMProviderEnvironment(),
+			HardeningEnv:         cfg.AgentHardening.Environment(),
+			EgressEnv:            cfg.AgentEgress.Environment(),
+			EgressPolicy:         egressPolicies[clusterCfg.Name],
+			Offline:              cfg.Offline.Enabled,
+			StreamProgress:       cfg.AgentStreamProgress,
+			SeverityTimeouts:     cfg.AgentSeverityTimeouts(),
+			DebugBundleTailBytes: cfg.AgentDebugBundle.TailBytes(),
+			DebugBundleEnv:       cfg.AgentDebugBundle.Environment,
 		}, tuning)
+		executors[clusterCfg.Name].SetTuningStore(tuningStore)
+		executors[clusterCfg.Name].SetRuntimeProber(runtimeProber)
 		slog.Info("executor created for cluster",
 			"cluster", clusterCfg.Name,
-			"kubeconfig", clusterCfg.Triage.Kubeconfig)
+			"kubeconfig", clusterCfg.Triage.Kubeconfig,
+			"skills", skills.Names(clusterSkills[clusterCfg.Name]))
 	}
 
-	// Create Slack notifier (optional - only if webhook URL configured)
-	var slackNotifier *reporting.SlackNotifier
-	if cfg.SlackWebhookURL != "" {
-		slackNotifier = reporting.NewSlackNotifier(cfg.SlackWebhookURL, tuning)
-		slog.Info("slack notifications enabled")
+	// Create chat notifiers (optional - only for configured webhook URLs). Their
+	// coalescers are deferred first, so they are flushed last on shutdown, after
+	// the outbox has delivered into them.
+	notifier, coalescers := newNotifier(cfg, tuningStore)
+	for _, coalescer := range coalescers {
+		defer coalescer.Flush()
+	}
+	if len(coalescers) > 0 {
+		slog.Info("notification coalescing enabled", "window_seconds", cfg.NotificationCoalescing.WindowSeconds)
+	}
+
+	// Send owned incidents to the owning team's destinations. Added after coalescing,
+	// so the team is notified of each of its incidents right away.
+	if len(cfg.Ownership.Routes) > 0 {
+		notifier = reporting.NewOwnerRouter(notifier, ownerRoutes(cfg, tuningStore))
+		slog.Info("owner notification routes enabled", "destinations", notifier.Name())
+	}
+
+	// Record lifecycle moments as Kubernetes Events on the nightcrier pod. Added
+	// after coalescing, so each moment stays its own event.
+	if cfg.KubeEvents.Enabled {
+		kubeClient, err := incluster.NewInClusterClient()
+		if err != nil {
+			return fmt.Errorf("failed to create Kubernetes client for events: %w", err)
+		}
+		kubeEvents := reporting.NewKubeEventNotifier(kubeClient, cfg.KubeEvents.Namespace, cfg.KubeEvents.PodName, cfg.KubeEvents.ResourceName)
+		if notifier == nil {
+			notifier = kubeEvents
+		} else {
+			notifier = reporting.MultiNotifier{notifier, kubeEvents}
+		}
+		slog.Info("kubernetes events enabled",
+			"namespace", cfg.KubeEvents.Namespace,
+			"pod", cfg.KubeEvents.PodName,
+			"resource", cfg.KubeEvents.ResourceName)
 	}
 
 	// Create circuit breaker with configured threshold
 	circuitBreaker := reporting.NewCircuitBreaker(cfg.FailureThresholdForAlert, tuning)
+	circuitBreaker.SetTuningStore(tuningStore)
 	slog.Info("circuit breaker initialized", "threshold", cfg.FailureThresholdForAlert)
 
+	// Create budget tracker (daily per-cluster investigation and spend limits)
+	budgetTracker, err := budget.NewTracker(cfg.Budget.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize budget tracker: %w", err)
+	}
+	for _, clusterCfg := range cfg.Clusters {
+		if limits := cfg.BudgetLimits(clusterCfg.Name); limits.Enabled() {
+			slog.Info("investigation budget configured",
+				"cluster", clusterCfg.Name,
+				"max_investigations_per_day", limits.MaxInvestigationsPerDay,
+				"max_spend_per_day_usd", limits.MaxSpendPerDay,
+				"estimated_cost_per_investigation_usd", limits.EstimatedCostPerInvestigation)
+		}
+	}
+
 	// Initialize artifact storage backend (for investigation reports and logs)
 	storageBackend, err := storage.NewStorage(cfg)
 	if err != nil {
@@ -215,14 +503,754 @@ func run(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
+	// Re-read tuning.yaml on SIGHUP
+	go reloadTuningOnHangup(ctx, tuningStore)
+
+	// Restart when the operator resources change, so the new configuration is
+	// applied by the Deployment restarting the pod
+	var restartErr error
+	if operatorCtl != nil {
+		go operatorCtl.Watch(ctx, time.Duration(cfg.Operator.ResyncSeconds)*time.Second, operatorSnapshot, func() {
+			restartErr = errOperatorConfigChanged
+			cancel()
+		})
+	}
+
+	// Outbound work (uploads, publishing, notifications) for investigations that
+	// already finished keeps running for shutdown_timeout after the signal, so a
+	// restart does not lose the notification for a just-finished investigation
+	shutdownTimeout := time.Duration(cfg.ShutdownTimeout) * time.Second
+	outboundCtx, cancelOutbound := context.WithCancel(context.Background())
+	defer cancelOutbound()
+	go func() {
+		<-ctx.Done()
+		select {
+		case <-time.After(shutdownTimeout):
+			slog.Warn("shutdown timeout reached, abandoning outbound work", "shutdown_timeout", shutdownTimeout)
+		case <-outboundCtx.Done():
+		}
+		cancelOutbound()
+	}()
+
+	// Periodically refresh skill bundles from their sources
+	if cfg.Skills.UpdateIntervalMinutes > 0 {
+		go skillsManager.Run(ctx, time.Duration(cfg.Skills.UpdateIntervalMinutes)*time.Minute)
+		slog.Info("skill bundle updates enabled", "interval_minutes", cfg.Skills.UpdateIntervalMinutes)
+	}
+
+	// Periodically pull the runbooks repository
+	if runbooksManager != nil && cfg.Runbooks.UpdateIntervalMinutes > 0 {
+		go runbooksManager.Run(ctx, time.Duration(cfg.Runbooks.UpdateIntervalMinutes)*time.Minute)
+	}
+
 	// Initialize state store (SQL persistence) based on configuration
-	var stateStore storage.StateStore
+	stateStore, err := openStateStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if stateStore != nil {
+		defer stateStore.Close()
+
+		// Record this run so restarts and configuration changes can be shown
+		// alongside incident history, logging the settings that changed since the
+		// previous run; the shutdown record is written on exit
+		runRecord, err := newRunRecord(cfg)
+		if err == nil {
+			if previous, err := stateStore.ListRuns(ctx, 1); err != nil {
+				slog.Warn("failed to load previous run for config diff", "error", err)
+			} else if len(previous) > 0 {
+				logConfigChanges(previous[0], runRecord)
+			}
+			err = stateStore.RecordRunStart(ctx, runRecord)
+		}
+		if err != nil {
+			slog.Warn("failed to record run start", "error", err)
+		} else {
+			slog.Info("run recorded", "run_id", runRecord.RunID, "config_hash", runRecord.ConfigHash)
+			defer func() { recordRunStop(stateStore, runRecord.RunID, runErr) }()
+		}
+	}
+
+	// Expire bulky artifacts (agent logs, session archives) earlier than reports
+	if cfg.ArtifactRetention.Enabled {
+		go runArtifactRetention(ctx, cfg, storageBackend, stateStore)
+		slog.Info("artifact retention enabled",
+			"report_days", cfg.ArtifactRetention.ReportDays,
+			"logs_days", cfg.ArtifactRetention.LogsDays,
+			"session_archive_days", cfg.ArtifactRetention.SessionArchiveDays,
+			"interval_minutes", cfg.ArtifactRetention.IntervalMinutes)
+	}
+
+	// Expire incidents left open (unprocessed or awaiting people) too long
+	if cfg.IncidentLifecycle.ExpireAfter() > 0 && stateStore != nil {
+		go runIncidentExpiry(ctx, cfg.IncidentLifecycle, stateStore)
+		slog.Info("incident expiry enabled", "expire_after_hours", cfg.IncidentLifecycle.ExpireAfterHours)
+	}
+
+	// Review incident history for fault types producing low-value investigations
+	// and suggest tuning through the notifiers and /health/noise
+	var noiseAnalysis *noiseAnalyzer
+	if cfg.NoiseAnalysis.Enabled && stateStore != nil {
+		noiseAnalysis = &noiseAnalyzer{cfg: cfg, store: stateStore, notifier: notifier}
+		go noiseAnalysis.Run(ctx)
+		slog.Info("noise analysis enabled",
+			"interval_hours", cfg.NoiseAnalysis.IntervalHours,
+			"window_days", cfg.NoiseAnalysis.WindowDays,
+			"min_incidents", cfg.NoiseAnalysis.MinIncidents)
+	}
+
+	// Warn before the credentials of the triage kubeconfigs expire
+	credentialExpiry := startCredentialExpiry(ctx, cfg, notifier)
+
+	// Keep a SQLite state store from bloating: checkpoint the WAL, vacuum, and alert
+	// on its size
+	if cfg.SQLiteMaintenance.Enabled {
+		if store, ok := stateStore.(*sqlite.Store); ok {
+			maintainer := &sqliteMaintainer{cfg: cfg.SQLiteMaintenance, store: store, notifier: notifier}
+			go maintainer.Run(ctx)
+			slog.Info("sqlite maintenance enabled",
+				"interval_minutes", cfg.SQLiteMaintenance.IntervalMinutes,
+				"vacuum_pages", cfg.SQLiteMaintenance.VacuumPages,
+				"alert_size_mb", cfg.SQLiteMaintenance.AlertSizeMB,
+				"alert_wal_mb", cfg.SQLiteMaintenance.AlertWALMB)
+		}
+	}
+
+	// Follow the incident changes made by every instance sharing the state store,
+	// for dashboards on the health server
+	var incidentFeed *incidentfeed.Feed
+	if cfg.IncidentFeed.Enabled && stateStore != nil {
+		incidentFeed = startIncidentFeed(ctx, cfg, stateStore)
+		go logIncidentChanges(ctx, incidentFeed)
+		slog.Info("incident feed enabled",
+			"mode", cfg.IncidentFeed.Mode,
+			"poll_interval_seconds", cfg.IncidentFeed.PollIntervalSeconds)
+	}
+
+	// Suspect LLM provider outages from status pages and recent failures, and hold
+	// investigations while they last
+	var providerStatus *providerstatus.Monitor
+	if cfg.ProviderStatus.Enabled {
+		providerStatus = startProviderStatus(ctx, cfg, circuitBreaker, notifier)
+		slog.Info("provider status monitor enabled",
+			"provider", cfg.ActiveLLMProvider(),
+			"interval_seconds", cfg.ProviderStatus.IntervalSeconds,
+			"indicator", cfg.ProviderStatus.Indicator,
+			"failure_threshold", cfg.ProviderStatus.FailureThreshold,
+			"keep_launching", cfg.ProviderStatus.KeepLaunching)
+	}
+
+	// Fleet-wide limits: dedup windows, launch pacing, and budgets are shared by
+	// every nightcrier process using the state store
+	sharedLimits := newSharedLimits(cfg, stateStore)
+	pacers := pacing.NewRegistry(cfg.PacingLimits())
+	if sharedLimits != nil {
+		budgetTracker.Share(sharedLimits)
+		pacers.Share(sharedLimits)
+		slog.Info("fleet-wide limits enabled",
+			"state_storage", cfg.GetStateStorageType(),
+			"adaptive_dedup", cfg.AdaptiveDedup.Enabled)
+	}
+
+	// Phase 3: Initialize connection manager (validates cluster permissions)
+	// This runs kubectl auth can-i checks for all clusters with triage enabled,
+	// several at a time; a cluster that fails is marked degraded and retried by its
+	// connection. With fast start, each cluster is validated by its own connection
+	// instead, so healthy clusters are processed while others are still validating.
+	if cfg.ClusterStartup.FastStart {
+		slog.Info("fast start enabled - clusters are validated as their connections start",
+			"connect", cfg.ClusterStartup.Connect,
+			"validation_timeout_seconds", cfg.ClusterStartup.ValidationTimeoutSeconds)
+	} else {
+		slog.Info("initializing connection manager - validating permissions",
+			"concurrency", cfg.ClusterStartup.ValidationConcurrency,
+			"validation_timeout_seconds", cfg.ClusterStartup.ValidationTimeoutSeconds)
+		summary, err := connectionMgr.Initialize(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to initialize connection manager: %w", err)
+		}
+		if cfg.ClusterStartup.RequireAllClusters && len(summary.Degraded) > 0 {
+			return fmt.Errorf("failed to initialize connection manager: %d cluster(s) failed permission validation: %v",
+				len(summary.Degraded), summary.DegradedClusters())
+		}
+	}
+
+	// Running investigations and the agent's current step, served by the health server
+	progressTracker := incident.NewProgressTracker()
+
+	// Event-to-notification latency histograms, agent failure counts, and queue
+	// overflow counts, served on /metrics
+	latency := newLatencyMetrics(metricsRegistry)
+	failureCounts := newFailureMetrics(metricsRegistry)
+	connectionMgr.SetOverflowMetrics(cluster.NewOverflowMetrics(metricsRegistry))
+
+	// Phase 4: Start health monitoring server if enabled
+	if healthPort > 0 {
+		healthServer := health.NewServer(connectionMgr, healthPort, cfg.HealthServer.ServerOptions())
+		healthServer.SetInvestigations(progressTracker)
+		healthServer.SetQueues(connectionMgr)
+		healthServer.SetMetrics(metricsRegistry)
+		if stateStore != nil {
+			healthServer.SetAgentResources(agentResources{stateStore})
+		}
+		if noiseAnalysis != nil {
+			healthServer.SetNoise(noiseAnalysis)
+		}
+		if credentialExpiry != nil {
+			healthServer.SetCredentials(credentialExpiry)
+		}
+		if err := healthServer.SetTuning(tuningAdmin{tuningStore}); err != nil {
+			slog.Info("tuning API disabled, use SIGHUP to reload tuning", "reason", err)
+		}
+		if err := healthServer.SetEventIngest(eventIngest{connectionMgr}); err != nil {
+			slog.Info("event ingest API disabled, backfill is unavailable", "reason", err)
+		}
+		if receiver := sourceRegistry.Receiver(); receiver.Len() > 0 {
+			if err := healthServer.SetSources(receiver); err != nil {
+				slog.Warn("push event sources receive no events", "reason", err)
+			}
+		}
+		if err := healthServer.SetTriagePause(pauses); err != nil {
+			slog.Info("triage pause API disabled, use the triage pause command", "reason", err)
+		}
+		if err := healthServer.SetIncidentArchives(incidentArchives{workspaceMgr, stateStore}); err != nil {
+			slog.Info("incident archive API disabled", "reason", err)
+		}
+		if stateStore != nil {
+			if err := healthServer.SetIncidentBundles(incidentBundles{stateStore}); err != nil {
+				slog.Info("incident API disabled, use the incidents show command", "reason", err)
+			}
+			if err := healthServer.SetIncidentHistory(incidentHistory{stateStore}); err != nil {
+				slog.Info("incident history API disabled, use the incidents history command", "reason", err)
+			}
+			if err := healthServer.SetInvestigationReviews(investigationReviews{stateStore}); err != nil {
+				slog.Info("investigation review API disabled, use the incidents review command", "reason", err)
+			}
+		}
+		if incidentFeed != nil {
+			if err := healthServer.SetIncidentChanges(incidentChanges{incidentFeed}); err != nil {
+				slog.Info("incident change stream disabled, use the incidents watch command", "reason", err)
+			}
+		}
+		scheme, host := "http", cfg.HealthServer.BindAddress
+		if cfg.HealthServer.TLSEnabled() {
+			scheme = "https"
+		}
+		if host == "" {
+			host = "localhost"
+		}
+		go func() {
+			slog.Info("starting health monitoring server",
+				"port", healthPort,
+				"endpoint", fmt.Sprintf("%s://%s/health/clusters", scheme, net.JoinHostPort(host, strconv.Itoa(healthPort))))
+			if err := healthServer.Start(); err != nil && err != http.ErrServerClosed {
+				slog.Error("health server failed", "error", err)
+			}
+		}()
+	} else {
+		slog.Info("health monitoring server disabled", "reason", "health-port=0")
+		if sourceRegistry.Receiver().Len() > 0 {
+			slog.Warn("push event sources receive no events", "reason", "health-port=0")
+		}
+	}
+
+	// Namespace silences live in the state store and are refreshed periodically, so
+	// silences created by other nightcrier processes are honored too
+	var silences *silence.Registry
+	if stateStore != nil {
+		silences = silence.NewRegistry(stateStore)
+		if err := silences.Refresh(ctx); err != nil {
+			slog.Warn("failed to load silences", "error", err)
+		}
+		go silences.Run(ctx, silence.DefaultRefreshInterval)
+	}
+
+	// Slack app: App Home and /nightcrier slash command over Socket Mode
+	if cfg.SlackApp.Enabled() {
+		app := slackapp.New(slackapp.Config{
+			AppToken:     cfg.SlackApp.AppToken,
+			BotToken:     cfg.SlackApp.BotToken,
+			UserTeams:    cfg.SlackApp.UserTeams,
+			AllowedUsers: cfg.SlackApp.AllowedUsers,
+			RecentWindow: time.Duration(cfg.SlackApp.RecentHours) * time.Hour,
+		}, stateStore, silences, proxy.NewTransport(cfg.Proxy.SlackSettings()))
+		app.SetTriagePause(pauses)
+		go app.Run(ctx)
+		slog.Info("slack app enabled",
+			"user_teams", len(cfg.SlackApp.UserTeams),
+			"allowed_users", len(cfg.SlackApp.AllowedUsers))
+		if len(cfg.SlackApp.AllowedUsers) == 0 {
+			slog.Warn("slack_app.allowed_users is empty, nobody can silence namespaces or pause triage from Slack")
+		}
+	}
+
+	// Start the ConnectionManager and get event channel
+	eventChan := connectionMgr.Start(ctx)
+	defer connectionMgr.Stop()
+
+	// Alert when a single cluster stays disconnected (partial outage), separately
+	// from the agent-failure circuit breaker
+	if cfg.ClusterDisconnectAlertSeconds > 0 && notifier != nil {
+		connectionAlerter := reporting.NewConnectionAlerter(notifier, time.Duration(cfg.ClusterDisconnectAlertSeconds)*time.Second)
+		go connectionAlerter.Run(ctx, reporting.DefaultConnectionCheckInterval, connectionMgr.ConnectionStates)
+		slog.Info("cluster connection alerts enabled", "threshold_seconds", cfg.ClusterDisconnectAlertSeconds)
+	}
+
+	// Alert when event queues fill up or drop events during incident storms
+	if (cfg.QueueAlertThresholdPercent > 0 || cfg.AlertOnDroppedEvents) && notifier != nil {
+		queueAlerter := reporting.NewQueueAlerter(notifier, cfg.QueueAlertThresholdPercent, cfg.AlertOnDroppedEvents, cfg.QueueOverflowPolicy)
+		go queueAlerter.Run(ctx, reporting.DefaultQueueCheckInterval, connectionMgr.QueueStats)
+		slog.Info("queue alerts enabled",
+			"threshold_percent", cfg.QueueAlertThresholdPercent,
+			"alert_on_dropped_events", cfg.AlertOnDroppedEvents)
+	}
+
+	slog.Info("connection manager started, processing events",
+		"cluster_count", len(cfg.Clusters))
+
+	// Global agent concurrency limit, with slots reserved for high-severity incidents
+	agentLimiter, err := agent.NewConcurrencyLimiter(cfg.MaxConcurrentAgents, cfg.ReservedAgentSlots)
+	if err != nil {
+		return fmt.Errorf("failed to create agent concurrency limiter: %w", err)
+	}
+	slog.Info("agent concurrency configured",
+		"max_concurrent_agents", cfg.MaxConcurrentAgents,
+		"reserved_agent_slots", cfg.ReservedAgentSlots,
+		"reserved_severity", cfg.ReservedSeverity)
+
+	// Investigation cache for identical fault signatures (disabled when TTL is 0)
+	investigationCache := incident.NewInvestigationCache(time.Duration(cfg.InvestigationCacheTTLSeconds) * time.Second)
+	if investigationCache.Enabled() {
+		slog.Info("investigation cache enabled", "ttl_seconds", cfg.InvestigationCacheTTLSeconds)
+	}
+
+	// API key pool: rotation and failover across multiple keys per provider
+	keyPool := keypool.New(cfg.APIKeys(), time.Duration(cfg.APIKeyCooldownSeconds)*time.Second)
+	for provider, keys := range cfg.APIKeys() {
+		if len(keys) > 1 {
+			slog.Info("API key rotation enabled",
+				"provider", provider,
+				"keys", keyPool.Len(provider),
+				"cooldown_seconds", cfg.APIKeyCooldownSeconds)
+		}
+	}
+
+	for provider, limits := range cfg.PacingLimits() {
+		slog.Info("LLM launch pacing configured",
+			"provider", provider,
+			"requests_per_minute", limits.RequestsPerMinute,
+			"tokens_per_minute", limits.TokensPerMinute,
+			"launch_interval", limits.Interval())
+	}
+
+	var runbookRegistry *runbooks.Registry
+	if cfg.Runbooks.Enabled() {
+		runbookRegistry = cfg.Runbooks.Registry()
+		slog.Info("runbook attachment enabled",
+			"path", cfg.Runbooks.Path(),
+			"rules", len(cfg.Runbooks.Rules))
+	}
+
+	ownerResolver := newOwnerResolver(cfg)
+	if ownerResolver != nil {
+		slog.Info("ownership lookup enabled",
+			"sources", ownerResolver.Sources(),
+			"routes", len(cfg.Ownership.Routes))
+	}
+
+	var reportShortener *shortener.Client
+	if cfg.URLShortener.Enabled() {
+		reportShortener = cfg.URLShortener.Client()
+		slog.Info("report URL shortening enabled", "shortener_url", cfg.URLShortener.URL)
+	}
+
+	verifier := newVerifier(cfg)
+	if verifier != nil {
+		slog.Info("output verification enabled",
+			"check_registries", cfg.Verification.CheckRegistries,
+			"timeout", cfg.Verification.Timeout())
+	}
+
+	enricher := newEnricher(cfg)
+	if enricher != nil {
+		slog.Info("MCP enrichment enabled for triage-disabled clusters",
+			"max_events", cfg.MCPEnrichment.MaxEvents,
+			"log_lines", cfg.MCPEnrichment.LogLines)
+	}
+
+	topologyCollectors := newTopologyCollectors(cfg)
+	if topologyCollectors != nil {
+		slog.Info("resource topology collection enabled",
+			"clusters", len(topologyCollectors),
+			"max_pods", cfg.Topology.MaxPods)
+	}
+
+	fallback, err := newFallbackTriage(cfg)
+	if err != nil {
+		return err
+	}
+	if fallback != nil {
+		slog.Info("rule-based fallback triage enabled",
+			"triggers", cfg.FallbackTriage.Triggers,
+			"custom_rules", len(cfg.FallbackTriage.Rules))
+	}
+
+	handlers, err := newFaultHandlers(cfg)
+	if err != nil {
+		return err
+	}
+
+	severityCollectors := newSeverityCollectors(cfg)
+	if cfg.SeverityPolicy.Enabled() {
+		slog.Info("severity policy enabled",
+			"rules", len(cfg.SeverityPolicy.Rules),
+			"clusters_with_signals", len(severityCollectors))
+	}
+
+	var postmortemPublisher *postmortem.Publisher
+	if cfg.Postmortem.Enabled() {
+		postmortemPublisher, err = postmortem.New(cfg.Postmortem.PublisherConfig(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to create postmortem publisher: %w", err)
+		}
+		slog.Info("postmortem publishing enabled",
+			"provider", cfg.Postmortem.Provider,
+			"repo", cfg.Postmortem.Repo,
+			"pull_requests", !cfg.Postmortem.DirectCommit)
+	}
+
+	var knowledgeBases []knowledgeBaseTarget
+	if kb := cfg.KnowledgeBase.Confluence; kb.Enabled() {
+		knowledgeBases = append(knowledgeBases, knowledgeBaseTarget{
+			publisher:   knowledgebase.NewConfluence(kb.PublisherConfig(), nil),
+			minSeverity: kb.MinSeverity,
+		})
+		slog.Info("confluence publishing enabled",
+			"space", kb.SpaceKey,
+			"min_severity", kb.MinSeverity)
+	}
+	if kb := cfg.KnowledgeBase.Notion; kb.Enabled() {
+		knowledgeBases = append(knowledgeBases, knowledgeBaseTarget{
+			publisher:   knowledgebase.NewNotion(kb.PublisherConfig(), nil),
+			minSeverity: kb.MinSeverity,
+		})
+		slog.Info("notion publishing enabled",
+			"database_id", kb.DatabaseID,
+			"min_severity", kb.MinSeverity)
+	}
+
+	var serviceNowClient *servicenow.Client
+	if cfg.ServiceNow.Enabled() {
+		dedupWindow := time.Duration(cfg.DedupWindowSeconds) * time.Second
+		serviceNowClient = servicenow.New(cfg.ServiceNow.ClientConfig(dedupWindow), nil)
+		slog.Info("servicenow integration enabled",
+			"instance", cfg.ServiceNow.InstanceURL,
+			"table", cfg.ServiceNow.Table,
+			"min_severity", cfg.ServiceNow.MinSeverity,
+			"dedup_window", dedupWindow)
+	}
+
+	var searchIndex *searchindex.Client
+	if cfg.SearchExport.Enabled() {
+		searchIndex = searchindex.New(cfg.SearchExport.ClientConfig(), nil)
+		slog.Info("search export enabled",
+			"url", cfg.SearchExport.URL,
+			"template", searchIndex.TemplateName())
+	}
+
+	webhookClient := newWebhookClient(cfg.LifecycleWebhooks)
+	if webhookClient != nil {
+		slog.Info("lifecycle webhooks enabled",
+			"endpoints", len(cfg.LifecycleWebhooks.Endpoints),
+			"timeout", cfg.LifecycleWebhooks.Timeout())
+	}
+
+	// Sampling: only one in N occurrences of noisy faults is investigated
+	var sampler *sampling.Sampler
+	if cfg.Sampling.Enabled() {
+		sampler = sampling.New(cfg.Sampling.SamplingRules(), cfg.Sampling.ResetAfter())
+		slog.Info("fault sampling enabled",
+			"rules", len(cfg.Sampling.Rules),
+			"reset_after_hours", cfg.Sampling.ResetAfterHours)
+	}
+
+	processor := &eventProcessor{
+		agentLimiter:       agentLimiter,
+		budgetTracker:      budgetTracker,
+		investigationCache: investigationCache,
+		workspaceMgr:       workspaceMgr,
+		markdownRenderer:   reporting.NewMarkdownRenderer(cfg.ReportRendering),
+		executors:          executors,
+		skillsManager:      skillsManager,
+		clusterSkills:      clusterSkills,
+		progress:           progressTracker,
+		runbooks:           runbookRegistry,
+		owners:             ownerResolver,
+		verifier:           verifier,
+		enricher:           enricher,
+		topology:           topologyCollectors,
+		severityCollectors: severityCollectors,
+		fallback:           fallback,
+		faultHandlers:      handlers,
+		shortener:          reportShortener,
+		postmortems:        postmortemPublisher,
+		knowledgeBases:     knowledgeBases,
+		serviceNow:         serviceNowClient,
+		searchIndex:        searchIndex,
+		webhooks:           webhookClient,
+		notifier:           notifier,
+		outbound:           outboundCtx,
+		storageBackend:     storageBackend,
+		stateStore:         stateStore,
+		incidentResources:  incidentResources,
+		circuitBreaker:     circuitBreaker,
+		providerStatus:     providerStatus,
+		keyPool:            keyPool,
+		pacers:             pacers,
+		pauses:             pauses,
+		latency:            latency,
+		failures:           failureCounts,
+		sampler:            sampler,
+		silences:           silences,
+		cfg:                cfg,
+		tuning:             tuningStore,
+	}
+
+	// Two investigations of the same fault never run at once, across retries,
+	// replays, and (with shared limits) processes
+	processor.faultLocks, err = newFaultLocker(cfg, sharedLimits)
+	if err != nil {
+		return err
+	}
+
+	// Notifications and retried uploads are delivered through a spooled outbox. It is
+	// drained after in-flight events finish (deferred calls run in reverse order);
+	// whatever is undelivered at the shutdown timeout is retried at the next startup.
+	outboxQueue, err := outbox.New(filepath.Join(cfg.WorkspaceRoot, ".outbox"), outbox.DefaultMaxAttempts, outbox.DefaultRetryDelay)
+	if err != nil {
+		return err
+	}
+	processor.registerOutboxHandlers(outboxQueue)
+	processor.outbox = outboxQueue
+	if recovered, err := outboxQueue.Recover(); err != nil {
+		slog.Error("failed to recover outbox", "error", err)
+	} else if recovered > 0 {
+		slog.Info("retrying outbound work from previous run", "jobs", recovered)
+	}
+	defer func() {
+		if left := outboxQueue.Drain(outboundCtx); left > 0 {
+			slog.Warn("outbound work left for retry at next startup", "jobs", left)
+		} else {
+			slog.Info("outbound work flushed")
+		}
+	}()
+
+	// Synthetic canary investigations validate the pipeline without real faults
+	if cfg.Canary.Enabled() {
+		go newCanaryMonitor(processor, connectionMgr.ConnectionStates).Run(ctx, time.Duration(cfg.Canary.IntervalMinutes)*time.Minute)
+		slog.Info("canary enabled",
+			"interval_minutes", cfg.Canary.IntervalMinutes,
+			"notify_success", cfg.Canary.NotifySuccess)
+	}
+
+	// Events are processed concurrently; the dispatcher bounds the events in flight,
+	// separately for priority events, and the agent limiter bounds how many
+	// investigations actually run. Wait for in-flight events before returning.
+	dispatcher := newEventDispatcher(ctx, processor.processEvent, cfg.MaxConcurrentAgents,
+		cfg.ReservedAgentSlots, tuning.Events.ChannelBufferSize, cfg.ReservedSeverity)
+	defer dispatcher.Wait()
+
+	// Faults in silenced namespaces are recorded without taking a dispatch slot
+	silencedRecorder := newSilencedRecorder(ctx, processor, tuning.Events.ChannelBufferSize)
+
+	// Adaptive dedup: repeats of a flapping fault are dropped within a window that
+	// grows with the fault's recurrence frequency
+	var deduplicator dedup.Deduplicator
+	if cfg.AdaptiveDedup.Enabled {
+		dedupCfg := dedup.Config{
+			MinWindow:    time.Duration(cfg.AdaptiveDedup.MinWindowSeconds) * time.Second,
+			MaxWindow:    time.Duration(cfg.AdaptiveDedup.MaxWindowSeconds) * time.Second,
+			GrowthFactor: cfg.AdaptiveDedup.GrowthFactor,
+		}
+		if sharedLimits != nil {
+			deduplicator = dedup.NewShared(dedupCfg, sharedLimits)
+		} else {
+			deduplicator = dedup.NewAdaptive(dedupCfg)
+		}
+		slog.Info("adaptive dedup enabled",
+			"min_window_seconds", cfg.AdaptiveDedup.MinWindowSeconds,
+			"max_window_seconds", cfg.AdaptiveDedup.MaxWindowSeconds,
+			"growth_factor", cfg.AdaptiveDedup.GrowthFactor)
+	}
+
+	// Node-level aggregation: pod faults on NotReady/pressured nodes are folded into
+	// one node-focused investigation. A nil channel disables the select case.
+	var nodeAggregator *aggregation.NodeAggregator
+	var aggregatedEvents <-chan aggregation.ClusterFault
+	if cfg.Aggregation.NodeEnabled {
+		nodeAggregator = aggregation.NewNodeAggregator(aggregation.NodeAggregatorConfig{
+			Window:   time.Duration(cfg.Aggregation.NodeWindowSeconds) * time.Second,
+			CoverFor: time.Duration(cfg.Aggregation.NodeCoverSeconds) * time.Second,
+		})
+		go nodeAggregator.Run(ctx)
+		aggregatedEvents = nodeAggregator.Output()
+		slog.Info("node-level aggregation enabled",
+			"window_seconds", cfg.Aggregation.NodeWindowSeconds,
+			"cover_seconds", cfg.Aggregation.NodeCoverSeconds)
+	}
+
+	// Namespace collapse: bursts of faults in one namespace become a single
+	// "namespace degradation" incident
+	var namespaceCollapser *aggregation.NamespaceCollapser
+	var collapsedEvents <-chan aggregation.ClusterFault
+	if cfg.Aggregation.NamespaceMaxFaults > 0 {
+		namespaceCollapser = aggregation.NewNamespaceCollapser(aggregation.NamespaceCollapserConfig{
+			MaxFaults: cfg.Aggregation.NamespaceMaxFaults,
+			Window:    time.Duration(cfg.Aggregation.NamespaceWindowMinutes) * time.Minute,
+		})
+		go namespaceCollapser.Run(ctx)
+		collapsedEvents = namespaceCollapser.Output()
+		slog.Info("namespace collapse enabled",
+			"max_faults", cfg.Aggregation.NamespaceMaxFaults,
+			"window_minutes", cfg.Aggregation.NamespaceWindowMinutes)
+	}
+
+	// Low-severity batching: minor faults are collected per cluster and investigated
+	// together in one agent run
+	var lowSeverityBatcher *aggregation.LowSeverityBatcher
+	var batchedEvents <-chan aggregation.ClusterFault
+	if cfg.Aggregation.LowSeverityBatchMinutes > 0 {
+		lowSeverityBatcher = aggregation.NewLowSeverityBatcher(aggregation.LowSeverityBatcherConfig{
+			MaxSeverity: cfg.Aggregation.LowSeverityMax,
+			Window:      time.Duration(cfg.Aggregation.LowSeverityBatchMinutes) * time.Minute,
+		})
+		go lowSeverityBatcher.Run(ctx)
+		batchedEvents = lowSeverityBatcher.Output()
+		slog.Info("low-severity batching enabled",
+			"batch_minutes", cfg.Aggregation.LowSeverityBatchMinutes,
+			"max_severity", cfg.Aggregation.LowSeverityMax)
+	}
+
+	// Latest permissions per cluster, for events emitted by aggregators
+	clusterPermissions := make(map[string]*cluster.ClusterPermissions)
+
+	// Event processing loop
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("shutting down...")
+			return restartErr
+
+		case agg := <-aggregatedEvents:
+			dispatcher.Dispatch(ctx, agg.Cluster, agg.Event, clusterPermissions[agg.Cluster])
+
+		case collapsed := <-collapsedEvents:
+			dispatcher.Dispatch(ctx, collapsed.Cluster, collapsed.Event, clusterPermissions[collapsed.Cluster])
+
+		case batched := <-batchedEvents:
+			dispatcher.Dispatch(ctx, batched.Cluster, batched.Event, clusterPermissions[batched.Cluster])
+
+		case event, ok := <-eventChan:
+			if !ok {
+				slog.Info("event channel closed")
+				return nil
+			}
+
+			// Type assert event from interface{} to map[string]interface{}
+			clusterEvent, ok := event.(map[string]interface{})
+			if !ok {
+				slog.Error("invalid event type received", "type", fmt.Sprintf("%T", event))
+				continue
+			}
+
+			// Extract cluster context
+			clusterName, ok := clusterEvent["ClusterName"].(string)
+			if !ok {
+				slog.Error("missing or invalid ClusterName in event")
+				continue
+			}
+
+			if _, ok := clusterEvent["Kubeconfig"].(string); !ok {
+				slog.Error("missing or invalid Kubeconfig in event", "cluster", clusterName)
+				continue
+			}
+
+			// Phase 3: Extract cluster permissions (may be nil if triage disabled)
+			permissions, _ := clusterEvent["Permissions"].(*cluster.ClusterPermissions)
+
+			// Extract the FaultEvent
+			faultEvent, ok := clusterEvent["Event"].(*events.FaultEvent)
+			if !ok {
+				slog.Error("missing or invalid Event in cluster event",
+					"cluster", clusterName,
+					"type", fmt.Sprintf("%T", clusterEvent["Event"]))
+				continue
+			}
+
+			clusterPermissions[clusterName] = permissions
+
+			// Drop repeats of a flapping fault within its dedup window
+			if deduplicator != nil {
+				signature := events.FaultSignature(clusterName, faultEvent)
+				decision := deduplicator.Check(signature)
+				if !decision.Allowed {
+					slog.Info("suppressed repeated fault within adaptive dedup window",
+						"cluster", clusterName,
+						"fault_id", faultEvent.FaultID,
+						"fault_type", faultEvent.FaultType,
+						"fault_signature", signature,
+						"suppressed", decision.Suppressed,
+						"window", decision.Window)
+					continue
+				}
+				if decision.Suppressed > 0 {
+					slog.Info("admitting repeated fault after adaptive dedup window",
+						"cluster", clusterName,
+						"fault_id", faultEvent.FaultID,
+						"fault_signature", signature,
+						"suppressed_since_last", decision.Suppressed,
+						"window", decision.Window)
+				}
+			}
+
+			// Faults in namespaces silenced from chat are not aggregated or
+			// investigated; they are recorded as suppressed incidents off the loop
+			if s := processor.matchSilence(clusterName, faultEvent); s != nil {
+				silencedRecorder.Record(silencedFault{clusterName: clusterName, event: faultEvent, silence: s})
+				continue
+			}
+
+			// Let the node aggregator absorb node faults and pod faults on degraded nodes
+			if nodeAggregator != nil && nodeAggregator.Submit(clusterName, faultEvent) {
+				continue
+			}
+
+			// Collapse namespace fault bursts into one degradation incident
+			if namespaceCollapser != nil && namespaceCollapser.Submit(clusterName, faultEvent) {
+				continue
+			}
+
+			// Hold low-severity faults for a combined investigation
+			if lowSeverityBatcher != nil && lowSeverityBatcher.Submit(clusterName, faultEvent) {
+				continue
+			}
+
+			// Process the event with cluster context (including permissions)
+			dispatcher.Dispatch(ctx, clusterName, faultEvent, permissions)
+		}
+	}
+}
+
+// openStateStore runs the database migrations and opens the configured SQL state
+// store. It returns nil when state is kept on the filesystem only.
+func openStateStore(ctx context.Context, cfg *config.Config) (storage.StateStore, error) {
 	storageType := cfg.GetStateStorageType()
 
 	switch storageType {
 	case "filesystem":
 		// No SQL backend needed for filesystem storage
 		slog.Info("state store disabled (using filesystem storage)")
+		return nil, nil
 
 	case "sqlite":
 		dbPath := cfg.StateStorage.SQLitePath
@@ -236,36 +1264,26 @@ func run(cmd *cobra.Command, args []string) error {
 			DatabaseType:   "sqlite",
 			DatabasePath:   dbPath,
 		}
+		if err := backupBeforeMigration(ctx, cfg, migrationCfg); err != nil {
+			return nil, err
+		}
 		if err := storage.RunMigrations(migrationCfg); err != nil {
-			return fmt.Errorf("failed to run SQLite migrations: %w", err)
+			return nil, fmt.Errorf("failed to run SQLite migrations: %w", err)
 		}
 
 		// Create SQLite store
 		sqliteCfg := &sqlite.Config{
 			Path: dbPath,
 		}
-		stateStore, err = sqlite.New(sqliteCfg)
+		stateStore, err := sqlite.New(sqliteCfg)
 		if err != nil {
-			return fmt.Errorf("failed to create SQLite store: %w", err)
+			return nil, fmt.Errorf("failed to create SQLite store: %w", err)
 		}
-		defer stateStore.Close()
 		slog.Info("SQLite state store initialized successfully")
+		return stateStore, nil
 
 	case "postgres":
-		var connStr string
-		if cfg.StateStorage.PostgresConnectionString != "" {
-			connStr = cfg.StateStorage.PostgresConnectionString
-		} else {
-			// URL-encode credentials to handle special characters
-			connStr = fmt.Sprintf(
-				"postgres://%s:%s@%s:%d/%s?sslmode=disable",
-				url.QueryEscape(cfg.StateStorage.PostgresUser),
-				url.QueryEscape(cfg.StateStorage.PostgresPassword),
-				cfg.StateStorage.PostgresHost,
-				cfg.StateStorage.PostgresPort,
-				cfg.StateStorage.PostgresDatabase,
-			)
-		}
+		connStr := postgresConnString(cfg)
 		migrationsPath := cfg.StateStorage.MigrationsPath
 
 		slog.Info("initializing PostgreSQL state store",
@@ -280,122 +1298,710 @@ func run(cmd *cobra.Command, args []string) error {
 			DatabaseType:   "postgres",
 			DatabaseURL:    connStr,
 		}
+		if err := backupBeforeMigration(ctx, cfg, migrationCfg); err != nil {
+			return nil, err
+		}
 		if err := storage.RunMigrations(migrationCfg); err != nil {
-			return fmt.Errorf("failed to run PostgreSQL migrations: %w", err)
+			return nil, fmt.Errorf("failed to run PostgreSQL migrations: %w", err)
 		}
 
 		// Create PostgreSQL store
 		postgresCfg := &postgres.Config{
 			ConnectionString: connStr,
 		}
-		stateStore, err = postgres.New(ctx, postgresCfg)
+		stateStore, err := postgres.New(ctx, postgresCfg)
 		if err != nil {
-			return fmt.Errorf("failed to create PostgreSQL store: %w", err)
+			return nil, fmt.Errorf("failed to create PostgreSQL store: %w", err)
 		}
-		defer stateStore.Close()
 		slog.Info("PostgreSQL state store initialized successfully")
+		return stateStore, nil
 
 	default:
-		return fmt.Errorf("unknown state storage type: %s", storageType)
+		return nil, fmt.Errorf("unknown state storage type: %s", storageType)
+	}
+}
+
+// postgresConnString returns the PostgreSQL connection string of the state store:
+// the configured connection string, or one built from the individual settings.
+func postgresConnString(cfg *config.Config) string {
+	if cfg.StateStorage.PostgresConnectionString != "" {
+		return cfg.StateStorage.PostgresConnectionString
+	}
+	// URL-encode credentials to handle special characters
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%d/%s?sslmode=disable",
+		url.QueryEscape(cfg.StateStorage.PostgresUser),
+		url.QueryEscape(cfg.StateStorage.PostgresPassword),
+		cfg.StateStorage.PostgresHost,
+		cfg.StateStorage.PostgresPort,
+		cfg.StateStorage.PostgresDatabase,
+	)
+}
+
+// newNotifier creates a notifier for each configured chat destination. It returns
+// nil when none is configured, a single notifier directly, and a MultiNotifier
+// that fans out to all of them otherwise. With notification routing configured, it
+// returns a routing matrix over the channels instead. With notification coalescing
+// enabled, related notifications are merged per channel (so incidents routed to
+// different channels are never merged) and the coalescers, which must be flushed
+// on shutdown, are returned too.
+func newNotifier(cfg *config.Config, tuningStore *config.TuningStore) (reporting.Notifier, []*reporting.Coalescer) {
+	var coalescers []*reporting.Coalescer
+	coalesce := func(n reporting.Notifier) reporting.Notifier {
+		if !cfg.NotificationCoalescing.Enabled {
+			return n
+		}
+		coalescer := reporting.NewCoalescer(n, cfg.NotificationCoalescing.Window())
+		coalescers = append(coalescers, coalescer)
+		return coalescer
+	}
+	if cfg.NotificationRouting.Enabled() {
+		return newRoutingMatrix(cfg, tuningStore, coalesce), coalescers
+	}
+
+	notifiers := chatNotifiers(cfg, tuningStore, chatDestinations{
+		slackWebhookURL:      cfg.SlackWebhookURL,
+		discordWebhookURL:    cfg.DiscordWebhookURL,
+		mattermostWebhookURL: cfg.MattermostWebhookURL,
+		mattermostChannel:    cfg.MattermostChannel,
+	})
+
+	if len(notifiers) == 0 {
+		return nil, nil
+	}
+	slog.Info("notifications enabled", "destinations", notifiers.Name())
+	if len(notifiers) == 1 {
+		return coalesce(notifiers[0]), coalescers
+	}
+	return coalesce(notifiers), coalescers
+}
+
+// newRoutingMatrix creates the notifiers of the notification routing channels and
+// the matrix routing notifications to them. coalesce wraps each channel's notifier.
+func newRoutingMatrix(cfg *config.Config, tuningStore *config.TuningStore, coalesce func(reporting.Notifier) reporting.Notifier) *reporting.RoutingMatrix {
+	channels := make(map[string]reporting.Notifier)
+	for name, channel := range cfg.NotificationChannels() {
+		channels[name] = coalesce(chatNotifiers(cfg, tuningStore, chatDestinations{
+			slackWebhookURL:      channel.SlackWebhookURL,
+			discordWebhookURL:    channel.DiscordWebhookURL,
+			mattermostWebhookURL: channel.MattermostWebhookURL,
+			mattermostChannel:    channel.MattermostChannel,
+		}))
+	}
+
+	rules := make([]reporting.RoutingRule, 0, len(cfg.NotificationRouting.Rules))
+	for _, rule := range cfg.NotificationRouting.Rules {
+		channelNames := make([]string, len(rule.Channels))
+		for i, name := range rule.Channels {
+			channelNames[i] = strings.ToLower(name)
+		}
+		rules = append(rules, reporting.RoutingRule{
+			Severities:    rule.Severity,
+			ClusterLabels: rule.ClusterLabels,
+			Statuses:      rule.Status,
+			Channels:      channelNames,
+		})
+	}
+
+	clusterLabels := make(map[string]map[string]string, len(cfg.Clusters))
+	for _, cl := range cfg.Clusters {
+		clusterLabels[cl.Name] = cl.Labels
+	}
+
+	matrix := reporting.NewRoutingMatrix(channels, cfg.DefaultNotificationChannels(), rules, clusterLabels)
+	slog.Info("notification routing enabled",
+		"channels", matrix.Name(),
+		"default", cfg.DefaultNotificationChannels(),
+		"rules", len(rules))
+	return matrix
+}
+
+// chatDestinations are the webhooks of a set of chat destinations.
+type chatDestinations struct {
+	slackWebhookURL      string
+	discordWebhookURL    string
+	mattermostWebhookURL string
+	mattermostChannel    string
+}
+
+// chatNotifiers creates a notifier for each destination that has a webhook.
+func chatNotifiers(cfg *config.Config, tuningStore *config.TuningStore, dest chatDestinations) reporting.MultiNotifier {
+	transport := proxy.NewTransport(cfg.Proxy.SlackSettings())
+	tuning := tuningStore.Current()
+
+	var notifiers reporting.MultiNotifier
+	if dest.slackWebhookURL != "" {
+		slack := reporting.NewSlackNotifier(dest.slackWebhookURL, tuning)
+		slack.SetTransport(transport)
+		slack.SetLanguage(cfg.ReportLanguage)
+		slack.SetTuningStore(tuningStore)
+		notifiers = append(notifiers, slack)
+	}
+	if dest.discordWebhookURL != "" {
+		discord := reporting.NewDiscordNotifier(dest.discordWebhookURL, tuning)
+		discord.SetTransport(transport)
+		discord.SetLanguage(cfg.ReportLanguage)
+		discord.SetTuningStore(tuningStore)
+		notifiers = append(notifiers, discord)
+	}
+	if dest.mattermostWebhookURL != "" {
+		mattermost := reporting.NewMattermostNotifier(dest.mattermostWebhookURL, tuning)
+		mattermost.Channel = dest.mattermostChannel
+		mattermost.Username = cfg.MattermostUsername
+		mattermost.SetTransport(transport)
+		mattermost.SetLanguage(cfg.ReportLanguage)
+		mattermost.SetTuningStore(tuningStore)
+		notifiers = append(notifiers, mattermost)
+	}
+	return notifiers
+}
+
+// eventProcessor holds the long-lived dependencies needed to process fault events.
+// Per-incident metadata (incident ID, cluster, fault ID) travels in the context as an
+// incident.IncidentContext instead of being threaded through every call.
+type eventProcessor struct {
+	agentLimiter       *agent.ConcurrencyLimiter
+	budgetTracker      *budget.Tracker
+	investigationCache *incident.InvestigationCache
+	faultLocks         *faultlock.Locker
+	workspaceMgr       *agent.WorkspaceManager
+	markdownRenderer   reporting.MarkdownRenderer
+	executors          map[string]*agent.Executor
+	skillsManager      *skills.Manager
+	clusterSkills      map[string][]skills.Skill
+	progress           *incident.ProgressTracker
+	runbooks           *runbooks.Registry
+	owners             *ownership.Resolver
+	verifier           *verify.Verifier
+	enricher           *enrichment.Enricher
+	topology           map[string]*topology.Collector
+	severityCollectors map[string]*severitypolicy.Collector
+	fallback           *fallbackTriage
+	faultHandlers      *faultHandlers
+	shortener          *shortener.Client
+	postmortems        *postmortem.Publisher
+	knowledgeBases     []knowledgeBaseTarget
+	serviceNow         *servicenow.Client
+	searchIndex        *searchindex.Client
+	webhooks           *webhooks.Client
+	notifier           reporting.Notifier
+	outbox             *outbox.Queue
+	// outbound stays alive for shutdown_timeout after the shutdown signal; it bounds
+	// the outbound work of investigations that finished before or during shutdown
+	outbound       context.Context
+	storageBackend storage.Storage
+	stateStore     storage.StateStore
+	// incidentResources records incidents as Incident resources (operator mode)
+	incidentResources *operator.IncidentResources
+	circuitBreaker    *reporting.CircuitBreaker
+	providerStatus    *providerstatus.Monitor
+	keyPool           *keypool.Pool
+	pacers            *pacing.Registry
+	pauses            *pause.Switch
+	latency           *latencyMetrics
+	failures          *metrics.CounterVec
+	sampler           *sampling.Sampler
+	silences          *silence.Registry
+	cfg               *config.Config
+	tuning            *config.TuningStore
+}
+
+// skillRefs returns the cached skills available to a cluster's agent, with their
+// manifest versions and cached revisions, for recording on the incident.
+func (p *eventProcessor) skillRefs(clusterName string) []incident.SkillRef {
+	var refs []incident.SkillRef
+	for _, s := range p.clusterSkills[clusterName] {
+		revision := p.skillsManager.Revision(s.Name)
+		if revision == "" {
+			continue // not cached, so not available to the agent
+		}
+		refs = append(refs, incident.SkillRef{
+			Name:     s.Name,
+			Version:  s.Version,
+			Revision: revision,
+		})
+	}
+	return refs
+}
+
+// publishPostmortem commits the investigation report and findings.json to the
+// postmortem repository and records the pull request URL on the incident.
+// Failures are logged; they never fail the investigation.
+func (p *eventProcessor) publishPostmortem(ctx context.Context, inc *incident.Incident, workspacePath, reportURL string) {
+	log := incident.Logger(ctx)
+
+	report, err := os.ReadFile(filepath.Join(workspacePath, "output", "investigation.md"))
+	if err != nil {
+		log.Warn("failed to read investigation report for postmortem", "error", err)
+		return
+	}
+	rootCause, confidence, err := reporting.ExtractSummaryFromReport(workspacePath)
+	if err != nil {
+		log.Debug("postmortem published without a report summary", "error", err)
+	}
+
+	findings := postmortem.Findings{
+		IncidentID:  inc.IncidentID,
+		Cluster:     inc.Cluster,
+		Namespace:   inc.Namespace,
+		Resource:    fmt.Sprintf("%s/%s", inc.Resource.Kind, inc.Resource.Name),
+		FaultType:   inc.FaultType,
+		Severity:    inc.Severity,
+		Status:      inc.Status,
+		RootCause:   rootCause,
+		Confidence:  confidence,
+		CompletedAt: *inc.CompletedAt,
+		ReportURL:   reportURL,
+	}
+	if inc.StartedAt != nil {
+		findings.StartedAt = *inc.StartedAt
+	}
+
+	url, err := p.postmortems.Publish(ctx, report, findings)
+	if err != nil {
+		log.Error("failed to publish postmortem", "error", err)
+		return
+	}
+	inc.PostmortemURL = url
+	log.Info("postmortem published", "url", url)
+}
+
+// Outbox job kinds
+const (
+	outboxNotification = "notification"
+	outboxUpload       = "upload"
+	outboxWebhook      = "webhook"
+)
+
+// uploadJob is an artifact upload to retry from the outbox.
+type uploadJob struct {
+	// IncidentID is the incident's storage key: its display ID, or its UUID
+	IncidentID    string         `json:"incidentId"`
+	WorkspacePath string         `json:"workspacePath"`
+	LogPaths      agent.LogPaths `json:"logPaths"`
+}
+
+// registerOutboxHandlers registers the delivery of each outbox job kind.
+func (p *eventProcessor) registerOutboxHandlers(q *outbox.Queue) {
+	// Webhook jobs are handled even without endpoints, so jobs queued under a
+	// previous configuration are drained
+	q.Handle(outboxWebhook, p.deliverWebhook)
+
+	q.Handle(outboxNotification, func(ctx context.Context, payload json.RawMessage) error {
+		var summary reporting.IncidentSummary
+		if err := json.Unmarshal(payload, &summary); err != nil {
+			return fmt.Errorf("failed to decode notification: %w", err)
+		}
+		if p.notifier == nil {
+			return nil
+		}
+		if err := p.notifier.SendIncidentNotification(&summary); err != nil {
+			return err
+		}
+		slog.Info("notification sent", "incident_id", summary.IncidentID, "cached", summary.CachedFrom != "")
+		return nil
+	})
+
+	q.Handle(outboxUpload, func(ctx context.Context, payload json.RawMessage) error {
+		var job uploadJob
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return fmt.Errorf("failed to decode upload: %w", err)
+		}
+		if p.storageBackend == nil {
+			return nil
+		}
+		artifacts, err := readIncidentArtifacts(job.WorkspacePath, job.IncidentID, job.LogPaths, p.markdownRenderer)
+		if err != nil {
+			// The workspace is gone; there is nothing left to upload
+			slog.Warn("dropping artifact upload retry", "incident_id", job.IncidentID, "error", err)
+			return nil
+		}
+		result, err := p.storageBackend.SaveIncident(ctx, job.IncidentID, artifacts)
+		if err != nil {
+			return err
+		}
+		slog.Info("incident artifacts saved to storage on retry",
+			"incident_id", job.IncidentID,
+			"report_url", result.ReportURL)
+
+		// Record the log URLs and artifact hashes as the original upload would have
+		incidentPath := filepath.Join(job.WorkspacePath, "incident.json")
+		var inc incident.Incident
+		if data, err := os.ReadFile(incidentPath); err == nil && json.Unmarshal(data, &inc) == nil {
+			inc.LogURLs = result.LogURLs
+			p.recordArtifactHashes(ctx, &inc, result.Hashes)
+			if err := inc.WriteToFile(incidentPath); err != nil {
+				slog.Warn("failed to update incident.json with log URLs", "incident_id", job.IncidentID, "error", err)
+			}
+		}
+		return nil
+	})
+}
+
+// sendNotification queues an incident notification in the outbox, falling back to
+// sending it directly when the outbox is unavailable.
+func (p *eventProcessor) sendNotification(ctx context.Context, summary *reporting.IncidentSummary) {
+	log := incident.Logger(ctx)
+	if p.outbox != nil {
+		err := p.outbox.Enqueue(outboxNotification, summary)
+		if err == nil {
+			log.Debug("notification queued")
+			return
+		}
+		log.Warn("failed to queue notification, sending directly", "error", err)
+	}
+	if err := p.notifier.SendIncidentNotification(summary); err != nil {
+		log.Error("failed to send notification", "error", err)
+	} else {
+		log.Info("notification sent", "cached", summary.CachedFrom != "")
+	}
+}
+
+// retryUpload queues a failed artifact upload in the outbox for retry.
+func (p *eventProcessor) retryUpload(ctx context.Context, incidentID, workspacePath string, logPaths agent.LogPaths) {
+	if p.outbox == nil {
+		return
+	}
+	log := incident.Logger(ctx)
+	job := uploadJob{IncidentID: incidentID, WorkspacePath: workspacePath, LogPaths: logPaths}
+	if err := p.outbox.Enqueue(outboxUpload, job); err != nil {
+		log.Error("failed to queue artifact upload for retry", "error", err)
+		return
+	}
+	log.Info("artifact upload queued for retry")
+}
+
+// outboundContext returns a context for the outbound work of a finished
+// investigation. It keeps the values of ctx (incident metadata) but ignores its
+// cancellation by the shutdown signal; it is cancelled only when the shutdown
+// timeout expires.
+func (p *eventProcessor) outboundContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.outbound == nil {
+		return ctx, func() {}
+	}
+	out, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(p.outbound, cancel)
+	return out, func() {
+		stop()
+		cancel()
+	}
+}
+
+// knowledgeBaseTarget is a knowledge base that resolved investigations at or above
+// a minimum severity are published to.
+type knowledgeBaseTarget struct {
+	publisher   knowledgebase.Publisher
+	minSeverity string
+}
+
+// publishToKnowledgeBases creates or updates the incident's page in each configured
+// knowledge base whose severity threshold it meets, and records the page URLs on the
+// incident. Failures are logged; they never fail the investigation.
+func (p *eventProcessor) publishToKnowledgeBases(ctx context.Context, inc *incident.Incident, workspacePath, reportURL string) {
+	log := incident.Logger(ctx)
+
+	var targets []knowledgeBaseTarget
+	for _, t := range p.knowledgeBases {
+		if t.minSeverity == "" || events.MeetsSeverity(inc.Severity, t.minSeverity) {
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	report, err := os.ReadFile(filepath.Join(workspacePath, "output", "investigation.md"))
+	if err != nil {
+		log.Warn("failed to read investigation report for knowledge base", "error", err)
+		return
+	}
+	rootCause, confidence, err := reporting.ExtractSummaryFromReport(workspacePath)
+	if err != nil {
+		log.Debug("knowledge base page published without a report summary", "error", err)
+	}
+
+	page := knowledgebase.Page{
+		IncidentID: inc.IncidentID,
+		Cluster:    inc.Cluster,
+		Namespace:  inc.Namespace,
+		Resource:   fmt.Sprintf("%s/%s", inc.Resource.Kind, inc.Resource.Name),
+		FaultType:  inc.FaultType,
+		Severity:   inc.Severity,
+		Status:     inc.Status,
+		RootCause:  rootCause,
+		Confidence: confidence,
+		ReportURL:  reportURL,
+		Markdown:   string(report),
+		HTML:       string(reporting.RenderMarkdownXHTML(p.markdownRenderer, report)),
+	}
+	if inc.CompletedAt != nil {
+		page.CompletedAt = *inc.CompletedAt
+	}
+
+	for _, t := range targets {
+		url, err := t.publisher.Publish(ctx, page)
+		if err != nil {
+			log.Error("failed to publish to knowledge base", "knowledge_base", t.publisher.Name(), "error", err)
+			continue
+		}
+		if inc.KnowledgeBaseURLs == nil {
+			inc.KnowledgeBaseURLs = make(map[string]string)
+		}
+		inc.KnowledgeBaseURLs[t.publisher.Name()] = url
+		log.Info("published to knowledge base", "knowledge_base", t.publisher.Name(), "url", url)
 	}
+}
 
-	// Phase 3: Initialize connection manager (validates cluster permissions)
-	// This runs kubectl auth can-i checks for all clusters with triage enabled
-	slog.Info("initializing connection manager - validating permissions")
-	initCtx, initCancel := context.WithTimeout(ctx, 30*time.Second)
-	defer initCancel()
-	if err := connectionMgr.Initialize(initCtx); err != nil {
-		return fmt.Errorf("failed to initialize connection manager: %w", err)
-	}
+// fileServiceNowRecord files a ServiceNow record for the fault, or updates the open
+// record when the fault recurred within the dedup window, and records the ticket on
+// the incident. Failures are logged; they never fail the investigation.
+func (p *eventProcessor) fileServiceNowRecord(ctx context.Context, inc *incident.Incident, rootCause, confidence, reportURL string) {
+	log := incident.Logger(ctx)
 
-	// Phase 4: Start health monitoring server if enabled
-	if healthPort > 0 {
-		healthServer := health.NewServer(connectionMgr, healthPort)
-		go func() {
-			slog.Info("starting health monitoring server",
-				"port", healthPort,
-				"endpoint", fmt.Sprintf("http://localhost:%d/health/clusters", healthPort))
-			if err := healthServer.Start(); err != nil && err != http.ErrServerClosed {
-				slog.Error("health server failed", "error", err)
-			}
-		}()
-	} else {
-		slog.Info("health monitoring server disabled", "reason", "health-port=0")
+	if p.cfg.ServiceNow.MinSeverity != "" && !events.MeetsSeverity(inc.Severity, p.cfg.ServiceNow.MinSeverity) {
+		return
 	}
 
-	// Start the ConnectionManager and get event channel
-	eventChan := connectionMgr.Start(ctx)
-	defer connectionMgr.Stop()
-
-	slog.Info("connection manager started, processing events",
-		"cluster_count", len(cfg.Clusters))
+	ticket, err := p.serviceNow.File(ctx, servicenow.Record{
+		Signature:  inc.FaultSignature,
+		IncidentID: inc.IncidentID,
+		Cluster:    inc.Cluster,
+		Namespace:  inc.Namespace,
+		Resource:   fmt.Sprintf("%s/%s", inc.Resource.Kind, inc.Resource.Name),
+		FaultType:  inc.FaultType,
+		Severity:   inc.Severity,
+		Status:     inc.Status,
+		RootCause:  rootCause,
+		Confidence: confidence,
+		ReportURL:  reportURL,
+	})
+	if err != nil {
+		log.Error("failed to file servicenow record", "error", err)
+		return
+	}
+	inc.ServiceNowNumber = ticket.Number
+	inc.ServiceNowURL = ticket.URL
+	log.Info("servicenow record filed",
+		"number", ticket.Number,
+		"updated", ticket.Updated,
+		"url", ticket.URL)
+}
 
-	// Event processing loop
-	for {
-		select {
-		case <-ctx.Done():
-			slog.Info("shutting down...")
-			return nil
+// runAgent executes the agent with an API key from the key pool; the caller paces
+// the first launch to the LLM provider's rate limits. When the agent output shows
+// the key was rate-limited or rejected, the key is benched and the run is retried,
+// paced, with the next healthy key, up to once per configured key. Retries keep
+// the agent slot while they are paced. The key that served the investigation is
+// recorded on the incident for auditing.
+func (p *eventProcessor) runAgent(ctx context.Context, executor *agent.Executor, inc *incident.Incident, workspacePath, facts string) (int, agent.LogPaths, error) {
+	log := incident.Logger(ctx)
+	pacer := p.pacers.For(p.cfg.ActiveLLMProvider())
+	priority := events.MeetsSeverity(inc.Severity, p.cfg.ReservedSeverity)
+
+	keyProvider := ""
+	if p.cfg.LLMProvider() == "" {
+		keyProvider = keypool.ProviderForAgent(p.cfg.AgentCLI)
+	}
+	useKeys := keyProvider != "" && p.keyPool.Len(keyProvider) > 0
 
-		case event, ok := <-eventChan:
-			if !ok {
-				slog.Info("event channel closed")
-				return nil
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			if err := pacer.Wait(ctx, priority); err != nil {
+				return -1, agent.LogPaths{}, fmt.Errorf("failed waiting for LLM rate limit pacing: %w", err)
 			}
+		}
 
-			// Type assert event from interface{} to map[string]interface{}
-			clusterEvent, ok := event.(map[string]interface{})
-			if !ok {
-				slog.Error("invalid event type received", "type", fmt.Sprintf("%T", event))
-				continue
+		runCtx := ctx
+		var key keypool.Key
+		if useKeys {
+			var err error
+			if key, err = p.keyPool.Select(keyProvider); err != nil {
+				return -1, agent.LogPaths{}, err
 			}
+			inc.APIKeyID = key.ID
+			runCtx = agent.WithEnv(ctx, key.Env())
+			log.Info("running agent with API key", "key_id", key.ID, "attempt", attempt)
+		}
 
-			// Extract cluster context
-			clusterName, ok := clusterEvent["ClusterName"].(string)
-			if !ok {
-				slog.Error("missing or invalid ClusterName in event")
-				continue
-			}
+		exitCode, logPaths, execErr := executor.ExecuteWithFacts(runCtx, workspacePath, inc.IncidentID, facts)
 
-			kubeconfig, ok := clusterEvent["Kubeconfig"].(string)
-			if !ok {
-				slog.Error("missing or invalid Kubeconfig in event", "cluster", clusterName)
-				continue
-			}
+		// Agent CLIs retry throttled requests themselves, so throttles can show up in
+		// the output of successful runs too; they still slow down future launches
+		outcome, detail := keypool.Classify(readAgentOutput(workspacePath))
+		if outcome == keypool.OutcomeRateLimited {
+			pacer.ObserveThrottle()
+		} else {
+			pacer.ObserveSuccess()
+		}
 
-			// Phase 3: Extract cluster permissions (may be nil if triage disabled)
-			permissions, _ := clusterEvent["Permissions"].(*cluster.ClusterPermissions)
+		succeeded := exitCode == 0 && execErr == nil
+		if !useKeys || succeeded || outcome == keypool.OutcomeOK {
+			return exitCode, logPaths, execErr
+		}
+		p.keyPool.Report(key, outcome, detail)
+		if attempt >= p.keyPool.Len(keyProvider) || p.keyPool.Healthy(keyProvider) == 0 || ctx.Err() != nil {
+			return exitCode, logPaths, execErr
+		}
+		log.Warn("failing over to next API key", "failed_key_id", key.ID, "detail", detail)
+	}
+}
 
-			// Extract the FaultEvent
-			faultEvent, ok := clusterEvent["Event"].(*events.FaultEvent)
-			if !ok {
-				slog.Error("missing or invalid Event in cluster event",
-					"cluster", clusterName,
-					"type", fmt.Sprintf("%T", clusterEvent["Event"]))
-				continue
+// trackProgress registers a running investigation with the progress tracker and
+// returns a context that reports the agent's steps (tool calls, reasoning) to it, so
+// the /health/investigations endpoint and the logs show what the agent is doing
+// instead of a black box until completion. Steps are only reported when
+// agent_stream_progress is enabled.
+func (p *eventProcessor) trackProgress(ctx context.Context, inc *incident.Incident) context.Context {
+	log := incident.Logger(ctx)
+	p.progress.Start(inc)
+
+	return agent.WithProgress(ctx, func(ev agent.ProgressEvent) {
+		switch ev.Kind {
+		case agent.ProgressToolStart:
+			log.Info("agent progress", "step", ev.Summary, "tool", ev.Tool)
+			p.progress.Update(inc.IncidentID, ev.Summary, true)
+		case agent.ProgressToolFinish:
+			if ev.Failed {
+				log.Debug("agent step failed", "step", ev.Summary, "tool", ev.Tool)
 			}
+		case agent.ProgressThinking:
+			p.progress.Update(inc.IncidentID, ev.Summary, false)
+		}
+	})
+}
 
-			// Get the executor for this cluster
-			executor, ok := executors[clusterName]
-			if !ok {
-				slog.Error("no executor found for cluster", "cluster", clusterName)
-				continue
-			}
+// readAgentOutput returns the tail of the agent log files in the workspace, used to
+// detect API key errors.
+func readAgentOutput(workspacePath string) string {
+	const maxTail = 64 * 1024
+	files, _ := filepath.Glob(filepath.Join(workspacePath, "logs", "*.log"))
+	var b strings.Builder
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if len(data) > maxTail {
+			data = data[len(data)-maxTail:]
+		}
+		b.Write(data)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
 
-			// Process the event with cluster context (including permissions)
-			if err := processEvent(ctx, faultEvent, clusterName, kubeconfig, permissions, workspaceMgr, executor, slackNotifier, storageBackend, stateStore, circuitBreaker, cfg, tuning); err != nil {
-				slog.Error("failed to process event",
-					"cluster", clusterName,
-					"fault_id", faultEvent.FaultID,
-					"error", err)
-			}
+// serveCachedInvestigation completes an incident using the report of a previous
+// investigation of the identical fault signature, re-notifying with a "cached" marker
+// instead of running the agent again.
+func (p *eventProcessor) serveCachedInvestigation(ctx context.Context, inc *incident.Incident, cached incident.CachedInvestigation) error {
+	log := incident.Logger(ctx)
+
+	completedAt := time.Now()
+	exitCode := 0
+	setStatus(ctx, inc, incident.StatusResolved)
+	inc.CompletedAt = &completedAt
+	inc.ExitCode = &exitCode
+	inc.CachedFrom = cached.IncidentID
+
+	log.Info("serving cached investigation for identical fault",
+		"fault_signature", inc.FaultSignature,
+		"cached_from", cached.IncidentID,
+		"cached_age", completedAt.Sub(cached.CompletedAt).Round(time.Second))
+
+	if p.stateStore != nil {
+		if err := p.stateStore.CompleteIncident(ctx, inc.IncidentID, inc.Status, exitCode, "", ""); err != nil {
+			log.Error("failed to complete incident in state store", "error", err)
+		}
+	}
+
+	if p.serviceNow != nil {
+		p.fileServiceNowRecord(ctx, inc, cached.RootCause, cached.Confidence, cached.ReportURL)
+	}
+	if p.searchIndex != nil {
+		p.exportToSearchIndex(ctx, inc, cached.ReportPath, cached.RootCause, cached.Confidence, cached.ReportURL)
+	}
+	p.emitOutcome(ctx, inc, map[string]string{"report_url": cached.ReportURL, "cached_from": cached.IncidentID})
+
+	if p.notifier != nil {
+		summary := &reporting.IncidentSummary{
+			IncidentID: inc.Ref(),
+			Cluster:    inc.Cluster,
+			Namespace:  inc.Namespace,
+			Resource:   fmt.Sprintf("%s/%s", inc.Resource.Kind, inc.Resource.Name),
+			Reason:     inc.FaultType,
+			Severity:   inc.Severity,
+			Labels:     inc.Labels,
+			Status:     inc.Status,
+			RootCause:  cached.RootCause,
+			Confidence: cached.Confidence,
+			ReportPath: cached.ReportPath,
+			ReportURL:  cached.ReportURL,
+			CachedFrom: cached.IncidentID,
 		}
+		setSummaryOwner(summary, inc)
+		p.sendNotification(ctx, summary)
 	}
 
 	return nil
 }
 
-func processEvent(ctx context.Context, event *events.FaultEvent, clusterName string, kubeconfig string, permissions *cluster.ClusterPermissions, workspaceMgr *agent.WorkspaceManager, executor *agent.Executor, slackNotifier *reporting.SlackNotifier, storageBackend storage.Storage, stateStore storage.StateStore, circuitBreaker *reporting.CircuitBreaker, cfg *config.Config, tuning *config.TuningConfig) error {
+// checkBudget charges one investigation against the cluster's daily budget.
+// It returns false when the budget is exhausted; the incident is then closed without
+// an investigation (or, with the budget fallback, triaged by rules) and a
+// budget-exhausted alert is sent once per cluster per day.
+func (p *eventProcessor) checkBudget(ctx context.Context, clusterName, incidentID string) bool {
+	log := incident.Logger(ctx)
+
+	decision, err := p.budgetTracker.Reserve(clusterName, p.cfg.BudgetLimits(clusterName))
+	if err != nil {
+		log.Error("budget bookkeeping failed - allowing investigation", "error", err)
+	}
+	if decision.Allowed {
+		return true
+	}
+
+	log.Warn("investigation skipped: budget exhausted",
+		"reason", decision.Reason,
+		"investigations_today", decision.Usage.Investigations,
+		"estimated_spend_usd", decision.Usage.EstimatedSpend)
+
+	// With the budget fallback, the incident is completed by rule-based triage
+	if p.stateStore != nil && !p.fallback.covers(config.FallbackBudgetExhausted) {
+		if err := p.stateStore.CompleteIncident(ctx, incidentID, incident.StatusFailed, -1, decision.Reason, ""); err != nil {
+			log.Error("failed to complete incident in state store", "error", err)
+		}
+	}
+
+	if decision.FirstDenial && p.notifier != nil {
+		alert := reporting.BudgetAlert{
+			Cluster:        clusterName,
+			Day:            budget.Day(time.Now()),
+			Reason:         decision.Reason,
+			Investigations: decision.Usage.Investigations,
+			EstimatedSpend: decision.Usage.EstimatedSpend,
+		}
+		if err := p.notifier.SendBudgetExhaustedAlert(ctx, alert); err != nil {
+			log.Error("failed to send budget exhausted alert", "error", err)
+		} else {
+			log.Info("budget exhausted alert sent")
+		}
+	}
+	return false
+}
+
+func (p *eventProcessor) processEvent(ctx context.Context, clusterName string, event *events.FaultEvent, permissions *cluster.ClusterPermissions) error {
+	// Get the executor for this cluster
+	executor, ok := p.executors[clusterName]
+	if !ok {
+		return fmt.Errorf("no executor found for cluster %s", clusterName)
+	}
+
+	// Faults in namespaces silenced from chat are only recorded
+	if s := p.matchSilence(clusterName, event); s != nil {
+		p.recordSilencedFault(ctx, silencedFault{clusterName: clusterName, event: event, silence: s})
+		return nil
+	}
+
 	// Create incident from event
 	incidentID := uuid.New().String()
 	inc := incident.NewFromEvent(incidentID, event)
@@ -403,49 +2009,195 @@ func processEvent(ctx context.Context, event *events.FaultEvent, clusterName str
 	// Override cluster name with the one from ClusterEvent (Phase 2: multi-cluster support)
 	inc.Cluster = clusterName
 
+	// Compute the severity from the severity policy before anything matches on it
+	p.applySeverityPolicy(ctx, inc)
+
+	// Attach labels from the cluster configuration and matching label rules
+	p.attachConfiguredLabels(inc)
+
+	// Give the incident its readable display ID
+	p.assignDisplayID(ctx, inc)
+
+	// Attach incident metadata to the context so downstream modules can log with it
+	ctx = incident.WithContext(ctx, incident.NewIncidentContext(inc))
+	log := incident.Logger(ctx)
+
+	// Look up the owning team, which labels the incident before the agent profile is
+	// selected and the incident is persisted
+	p.resolveOwner(ctx, inc)
+
+	// Select the agent model, turn limit, and timeout for the incident's priority
+	if name, profile, ok := p.cfg.AgentProfiles.Select(inc.Severity, inc.Cluster, inc.Namespace, inc.Labels); ok {
+		inc.AgentProfile = name
+		ctx = agent.WithProfile(ctx, agent.Profile{
+			Name:     name,
+			Model:    profile.Model,
+			MaxTurns: profile.MaxTurns,
+			Timeout:  profile.Timeout,
+		})
+		log.Info("selected agent profile", "agent_profile", name)
+	}
+
+	// Serve-only clusters never investigate and paused triage launches no agents,
+	// so their incidents stay pending
+	serveOnly := p.serveOnly(clusterName)
+	paused := p.triagePaused(clusterName)
+	if serveOnly || paused != nil {
+		setStatus(ctx, inc, incident.StatusPending)
+	}
+
+	// Investigate only one in N occurrences of sampled faults; the others are
+	// recorded as suppressed incidents
+	sampledOut := false
+	if p.sampler != nil && !serveOnly && paused == nil {
+		if sampledOut = p.sampleFault(ctx, inc, event); sampledOut {
+			setStatus(ctx, inc, incident.StatusSuppressed)
+		}
+	}
+
+	// Link a recurring fault to its earlier resolved incident
+	var prior *incident.PriorInvestigation
+	if !serveOnly && paused == nil && !sampledOut {
+		if prior = p.findPriorInvestigation(ctx, inc); prior != nil {
+			inc.ParentIncidentID = prior.IncidentID
+			log.Info("fault recurred after a resolved incident - creating follow-up",
+				"parent_incident_id", prior.IncidentID, "recurrences", len(prior.Chain))
+		}
+	}
+
 	// Persist incident to state store (SQL database)
-	if stateStore != nil {
-		if err := stateStore.CreateIncident(ctx, inc, event); err != nil {
-			slog.Error("failed to create incident in state store", "incident_id", incidentID, "error", err)
+	if p.stateStore != nil {
+		if err := p.stateStore.CreateIncident(ctx, inc, event); err != nil {
+			log.Error("failed to create incident in state store", "error", err)
 			// Continue processing - don't fail the incident if database write fails
 		}
 	}
+	p.emitLifecycle(ctx, webhooks.EventCreated, inc, nil)
+
+	// Record the incident as an Incident resource; its status is updated with the
+	// final state on every return path
+	var resourceResult *operator.IncidentResult
+	if p.incidentResources != nil {
+		p.incidentResources.Create(ctx, inc)
+		defer func() {
+			p.incidentResources.UpdateStatus(context.WithoutCancel(ctx), inc, resourceResult)
+		}()
+	}
 
-	slog.Info("processing fault event",
-		"incident_id", incidentID,
-		"fault_id", event.FaultID,
-		"cluster", clusterName,
-		"namespace", event.GetNamespace(),
+	log.Info("processing fault event",
 		"resource", fmt.Sprintf("%s/%s", event.GetResourceKind(), event.GetResourceName()),
-		"reason", event.GetReason(),
-		"severity", event.GetSeverity())
+		"reason", event.GetReason())
+
+	// Serve-only clusters record and announce fault events without investigating them
+	if serveOnly {
+		return p.recordServeOnlyEvent(ctx, inc, event)
+	}
+
+	// While triage is paused, fault events are recorded but not investigated
+	if paused != nil {
+		log.Info("triage paused - recorded fault event without investigation",
+			"paused_by", paused.PausedBy,
+			"pause_reason", paused.Reason)
+		return nil
+	}
+
+	// Occurrences left out by sampling are recorded but not investigated
+	if sampledOut {
+		log.Info("sampled fault - recorded occurrence without investigation",
+			"sampling_occurrence", inc.Annotations[samplingAnnotation])
+		return nil
+	}
 
 	// Phase 3: Check if triage is enabled for this cluster
 	// If permissions are nil, triage is disabled (triage.enabled=false in config)
 	if permissions == nil {
-		slog.Info("triage disabled for cluster - skipping agent execution",
-			"incident_id", incidentID,
-			"cluster", clusterName,
+		log.Info("triage disabled for cluster - skipping agent execution",
 			"reason", "triage.enabled=false or no kubeconfig")
-		// Event is logged but no investigation is performed
+		p.transitionIncident(ctx, inc, incident.StatusPending)
+		// Event is logged but no investigation is performed; the incident record can
+		// still carry the object's recent events and logs read through the MCP server
+		p.enrichIncident(ctx, inc)
 		return nil
 	}
 
 	// Phase 3: Check if cluster has minimum permissions for triage
 	if !permissions.MinimumPermissionsMet() {
-		slog.Warn("cluster has insufficient permissions for triage - proceeding anyway",
-			"incident_id", incidentID,
-			"cluster", clusterName,
+		log.Warn("cluster has insufficient permissions for triage - proceeding anyway",
 			"warnings", permissions.Warnings)
 		// We log a warning but still attempt triage - agent will see limited permissions
 	}
 
+	// Serve a cached report when an identical fault was investigated recently
+	inc.FaultSignature = events.FaultSignature(clusterName, event)
+	if cached, ok := p.investigationCache.Get(inc.FaultSignature); ok {
+		return p.serveCachedInvestigation(ctx, inc, cached)
+	}
+
+	// Wait for an investigation of the same fault already running (a retry, replay,
+	// or another process) to finish, then serve its report if it was cached
+	if p.faultLocks != nil {
+		lock, err := p.faultLocks.Acquire(ctx, inc.FaultSignature, faultLockWait(p.cfg), faultLockPoll, func() {
+			log.Info("waiting for the running investigation of the same fault",
+				"fault_signature", inc.FaultSignature)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to acquire fault lock: %w", err)
+		}
+		defer lock.Release()
+		if cached, ok := p.investigationCache.Get(inc.FaultSignature); ok {
+			return p.serveCachedInvestigation(ctx, inc, cached)
+		}
+	}
+
+	// Route the fault to its specialized handlers, which may resolve it without an
+	// agent or leave findings for the agent
+	handlerFindings, handled, err := p.runFaultHandlers(ctx, inc)
+	if err != nil {
+		return err
+	}
+	if handled {
+		return nil
+	}
+
+	// Without a usable API key, or while the circuit breaker is open, produce a
+	// rule-based report instead of running an agent
+	if trigger := p.fallbackTrigger(); trigger != "" {
+		return p.runFallbackTriage(ctx, inc, event, trigger)
+	}
+
+	// Refuse to investigate with an agent runtime that drifted from enforced pins
+	agentInfo := executor.AgentInfo(ctx)
+	if err := p.checkAgentDrift(ctx, agentInfo); err != nil {
+		return p.refuseDriftedAgent(ctx, inc, err)
+	}
+
+	// Enforce the cluster's daily investigation budget
+	if !p.checkBudget(ctx, clusterName, incidentID) {
+		if p.fallback.covers(config.FallbackBudgetExhausted) {
+			return p.runFallbackTriage(ctx, inc, event, config.FallbackBudgetExhausted)
+		}
+		setStatus(ctx, inc, incident.StatusFailed)
+		p.emitLifecycle(ctx, webhooks.EventFailed, inc, map[string]string{"failure_reason": fallbackReasons[config.FallbackBudgetExhausted]})
+		return nil
+	}
+
+	// Break down where the time from receiving the event to notifying goes
+	receivedAt := event.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = inc.CreatedAt
+	}
+	inc.Latency = &incident.Latency{}
+	inc.Latency.Record(incident.StageQueue, inc.CreatedAt.Sub(receivedAt))
+
 	// Create workspace
-	workspacePath, err := workspaceMgr.Create(incidentID)
+	workspacePath, err := p.workspaceMgr.Create(inc.Ref(), p.cfg.WorkspaceTemplates(clusterName)...)
 	if err != nil {
 		return fmt.Errorf("failed to create workspace: %w", err)
 	}
-	slog.Info("created workspace", "path", workspacePath)
+	log.Info("created workspace", "path", workspacePath)
+
+	// Record the skills available to the agent
+	inc.Skills = p.skillRefs(clusterName)
 
 	// Write incident.json with investigating status
 	incidentPath := filepath.Join(workspacePath, "incident.json")
@@ -453,6 +2205,60 @@ func processEvent(ctx context.Context, event *events.FaultEvent, clusterName str
 		return fmt.Errorf("failed to write incident context: %w", err)
 	}
 
+	// Convert the event fields into a structured facts section for the agent prompt,
+	// and keep a copy in the workspace alongside incident.json
+	facts := incident.BuildFacts(inc, event, time.Now()).Markdown()
+	if err := os.WriteFile(filepath.Join(workspacePath, "incident_facts.md"), []byte(facts), 0644); err != nil {
+		log.Warn("failed to write incident facts", "error", err)
+	}
+
+	// Attach organization runbooks for this fault type and resource kind, and point
+	// the agent at them in the prompt
+	if p.runbooks != nil {
+		kind := ""
+		if inc.Resource != nil {
+			kind = inc.Resource.Kind
+		}
+		attached, err := p.runbooks.Attach(workspacePath, inc.FaultType, kind)
+		if err != nil {
+			log.Warn("failed to attach runbooks", "error", err)
+		}
+		if len(attached) > 0 {
+			log.Info("attached runbooks to workspace", "runbooks", attached)
+			facts += "\n" + runbooks.PromptSection(attached)
+		}
+	}
+
+	// Tell the agent what the fault handlers found or changed
+	if handlerFindings != "" {
+		facts += "\n" + handlerFindings
+	}
+
+	// Tell the agent which team owns the workload
+	if inc.Owner != nil {
+		facts += "\n" + ownership.PromptSection(inc.Owner)
+	}
+
+	// Ask the agent to label the incident from its findings
+	if p.cfg.IncidentLabels.FromAgent {
+		facts += "\n" + labels.PromptSection()
+	}
+
+	// Ask the agent to record checkable claims for verification
+	if p.verifier != nil {
+		facts += "\n" + verify.PromptSection()
+	}
+
+	// Start a follow-up investigation from the previous root cause
+	if prior != nil {
+		facts += "\n" + prior.PromptSection()
+	}
+
+	// Map the objects around the affected resource for the agent and the report
+	if p.collectTopology(ctx, inc, workspacePath) {
+		facts += "\n" + topology.PromptSection()
+	}
+
 	// Phase 3: Write incident_cluster_permissions.json if permissions are available
 	// This informs the agent about what cluster access it has
 	if permissions != nil {
@@ -468,45 +2274,107 @@ func processEvent(ctx context.Context, event *events.FaultEvent, clusterName str
 		if err := encoder.Encode(permissions); err != nil {
 			return fmt.Errorf("failed to write permissions file: %w", err)
 		}
-		slog.Info("wrote cluster permissions to workspace",
+		log.Info("wrote cluster permissions to workspace",
 			"path", permsPath,
-			"cluster", clusterName,
 			"minimum_met", permissions.MinimumPermissionsMet())
 	} else {
-		slog.Info("no cluster permissions available (triage may be disabled)",
-			"cluster", clusterName)
+		log.Info("no cluster permissions available (triage may be disabled)")
+	}
+
+	// Wait for an agent slot. Incidents at or above the reserved severity may use the
+	// reserved slots and are admitted ahead of lower-severity incidents.
+	p.transitionIncident(ctx, inc, incident.StatusQueued)
+	priority := events.MeetsSeverity(inc.Severity, p.cfg.ReservedSeverity)
+	// Pace the first launch to the LLM provider's rate limits before taking a slot,
+	// so a paced wait does not hold a slot another incident could use; priority
+	// incidents are paced ahead of the others. Retries in runAgent are paced while
+	// holding the slot, as they continue the same investigation.
+	if err := p.pacers.For(p.cfg.ActiveLLMProvider()).Wait(ctx, priority); err != nil {
+		return fmt.Errorf("failed waiting for LLM rate limit pacing: %w", err)
+	}
+	waitStart := time.Now()
+	release, err := p.agentLimiter.Acquire(ctx, priority)
+	if err != nil {
+		return fmt.Errorf("failed to acquire agent slot: %w", err)
+	}
+	// The slot is released as soon as the agent run ends; this covers the returns
+	// before it
+	defer release()
+	if waited := time.Since(waitStart); waited > time.Second {
+		stats := p.agentLimiter.Stats()
+		log.Info("acquired agent slot after waiting",
+			"priority", priority,
+			"waited_seconds", int(waited.Seconds()),
+			"running", stats.Running,
+			"waiting_normal", stats.WaitingNormal,
+			"waiting_priority", stats.WaitingPriority)
 	}
 
-	// Mark agent start time
+	// Mark agent start time and update incident status to investigating
 	startedAt := time.Now()
 	inc.StartedAt = &startedAt
+	inc.Latency.Record(incident.StageAgentStart, startedAt.Sub(inc.CreatedAt))
+	p.transitionIncident(ctx, inc, incident.StatusInvestigating)
+	if p.incidentResources != nil {
+		p.incidentResources.UpdateStatus(ctx, inc, nil)
+	}
 
-	// Update incident status to investigating in state store
-	if stateStore != nil {
-		if err := stateStore.UpdateIncidentStatus(ctx, incidentID, incident.StatusInvestigating, &startedAt); err != nil {
-			slog.Error("failed to update incident status in state store", "incident_id", incidentID, "error", err)
-		}
+	inc.Agent = agentInfo.Runtime()
+	inc.Egress = executor.EgressPolicy()
+	p.emitLifecycle(ctx, webhooks.EventAgentStarted, inc, map[string]string{"agent_profile": inc.AgentProfile})
 
+	if p.stateStore != nil {
 		// Record agent execution start in state store
-		slog.Debug("recording agent execution start in state store", "incident_id", incidentID)
+		log.Debug("recording agent execution start in state store")
 		agentExec := &storage.AgentExecution{
-			ExecutionID:  incidentID, // Use incident ID as execution ID for now
-			IncidentID:   incidentID,
-			StartedAt:    startedAt,
-			CompletedAt:  nil,
-			ExitCode:     nil,
-			ErrorMessage: "",
-			LogPaths:     nil,
-		}
-		if err := stateStore.RecordAgentExecution(ctx, agentExec); err != nil {
-			slog.Error("failed to record agent execution start in state store", "incident_id", incidentID, "error", err)
+			ExecutionID:      incidentID, // Use incident ID as execution ID for now
+			IncidentID:       incidentID,
+			StartedAt:        startedAt,
+			CompletedAt:      nil,
+			ExitCode:         nil,
+			ErrorMessage:     "",
+			LogPaths:         nil,
+			AgentCLI:         agentInfo.CLI,
+			AgentImage:       agentInfo.Image,
+			AgentModel:       agentInfo.Model,
+			PromptVersion:    agentInfo.PromptVersion,
+			AgentCLIVersion:  agentInfo.CLIVersion,
+			AgentImageDigest: agentInfo.ImageDigest,
+		}
+		if err := p.stateStore.RecordAgentExecution(ctx, agentExec); err != nil {
+			log.Error("failed to record agent execution start in state store", "error", err)
 		} else {
-			slog.Info("agent execution start recorded in state store", "incident_id", incidentID, "execution_id", agentExec.ExecutionID)
+			log.Info("agent execution start recorded in state store", "execution_id", agentExec.ExecutionID)
 		}
 	}
 
-	// Execute agent
-	exitCode, logPaths, execErr := executor.Execute(ctx, workspacePath, incidentID)
+	// Execute agent, failing over to another API key if the selected one is rejected.
+	// The agent's steps are tracked and its resource usage sampled while it runs
+	// (see trackProgress and agent.WithResourceUsage).
+	var usage agent.ResourceUsage
+	runCtx := agent.WithResourceUsage(p.trackProgress(ctx, inc), &usage)
+	exitCode, logPaths, execErr := p.runAgent(runCtx, executor, inc, workspacePath, facts)
+	// Free the agent slot for the next incident; upload, publishing, and
+	// notification below do not need it
+	release()
+	p.progress.Finish(incidentID)
+	inc.Latency.Record(incident.StageAgent, time.Since(startedAt))
+
+	// Move the final artifacts off the scratch directory (workspace_scratch_dir);
+	// everything below reads the persistent workspace
+	offloaded, offloadedLogs, err := p.workspaceMgr.Offload(inc.Ref(), workspacePath, logPaths)
+	if err != nil {
+		log.Error("failed to off-load scratch workspace", "path", workspacePath, "error", err)
+	} else if offloaded != workspacePath {
+		log.Info("off-loaded workspace artifacts", "from", workspacePath, "path", offloaded)
+	}
+	workspacePath, logPaths = offloaded, offloadedLogs
+	incidentPath = filepath.Join(workspacePath, "incident.json")
+
+	// The investigation is over; a shutdown signal from here on must not cut off its
+	// state updates, uploads, publishing, and notification
+	ctx, cancelOutbound := p.outboundContext(ctx)
+	defer cancelOutbound()
 
 	// Update incident with completion info
 	inc.MarkCompleted(exitCode, execErr)
@@ -519,109 +2387,139 @@ func processEvent(ctx context.Context, event *events.FaultEvent, clusterName str
 	}
 
 	// Update agent execution with completion info in state store
-	if stateStore != nil {
-		slog.Debug("updating agent execution with completion info in state store", "incident_id", incidentID, "exit_code", exitCode)
+	if p.stateStore != nil {
+		log.Debug("updating agent execution with completion info in state store", "exit_code", exitCode)
 		completedAt := time.Now()
 		execErrMsg := ""
 		if execErr != nil {
 			execErrMsg = execErr.Error()
 		}
 		agentExec := &storage.AgentExecution{
-			ExecutionID:  incidentID, // Use incident ID as execution ID for now
-			IncidentID:   incidentID,
-			StartedAt:    startedAt,
-			CompletedAt:  &completedAt,
-			ExitCode:     &exitCode,
-			ErrorMessage: execErrMsg,
-			LogPaths:     inc.LogPaths,
-		}
-		if err := stateStore.RecordAgentExecution(ctx, agentExec); err != nil {
-			slog.Error("failed to update agent execution completion in state store", "incident_id", incidentID, "error", err)
+			ExecutionID:      incidentID, // Use incident ID as execution ID for now
+			IncidentID:       incidentID,
+			StartedAt:        startedAt,
+			CompletedAt:      &completedAt,
+			ExitCode:         &exitCode,
+			ErrorMessage:     execErrMsg,
+			LogPaths:         inc.LogPaths,
+			AgentCLI:         agentInfo.CLI,
+			AgentImage:       agentInfo.Image,
+			AgentModel:       agentInfo.Model,
+			PromptVersion:    agentInfo.PromptVersion,
+			AgentCLIVersion:  agentInfo.CLIVersion,
+			AgentImageDigest: agentInfo.ImageDigest,
+		}
+		if usage.Source != "" {
+			agentExec.Resources = &storage.AgentResourceUsage{
+				CPUSeconds:       usage.CPUSeconds,
+				PeakRSSBytes:     usage.PeakRSSBytes,
+				PeakProcesses:    usage.PeakProcesses,
+				DiskWrittenBytes: usage.DiskWrittenBytes,
+				Source:           usage.Source,
+			}
+		}
+		if err := p.stateStore.RecordAgentExecution(ctx, agentExec); err != nil {
+			log.Error("failed to update agent execution completion in state store", "error", err)
 		} else {
-			slog.Info("agent execution completion recorded in state store", "incident_id", incidentID, "execution_id", agentExec.ExecutionID)
+			log.Info("agent execution completion recorded in state store", "execution_id", agentExec.ExecutionID)
 		}
 	} else {
-		slog.Warn("stateStore is nil, skipping agent execution update", "incident_id", incidentID)
+		log.Debug("state store not configured, skipping agent execution update")
 	}
 
 	// Detect agent failures (exit code 0 but missing or invalid output)
-	agentFailed, failureReason := detectAgentFailure(workspacePath, exitCode, execErr, tuning)
+	agentFailed, failureReason := detectAgentFailure(workspacePath, exitCode, execErr, p.tuning.Current())
 	if agentFailed {
-		inc.Status = incident.StatusAgentFailed
-		inc.FailureReason = failureReason
-		slog.Warn("agent execution failed validation",
-			"incident_id", incidentID,
-			"reason", failureReason)
-
-		// Record failure in circuit breaker
-		circuitBreaker.RecordFailure(failureReason)
-		slog.Debug("circuit breaker: recorded failure",
-			"failure_count", circuitBreaker.GetFailureCount(),
-			"state", circuitBreaker.GetState())
+		inc.MarkAgentFailed()
+
+		// Classify the failure and record it in the failure metrics and circuit breaker
+		p.recordAgentFailure(ctx, inc, exitCode, failureReason, workspacePath)
+		log.Debug("circuit breaker: recorded failure",
+			"failure_count", p.circuitBreaker.GetFailureCount(),
+			"state", p.circuitBreaker.GetState())
 
 		// Check if we should send a system degraded alert
-		if circuitBreaker.ShouldAlert() {
-			stats := circuitBreaker.GetStats()
-			slog.Warn("circuit breaker threshold reached, system degraded",
+		if p.circuitBreaker.ShouldAlert() {
+			stats := p.circuitBreaker.GetStats()
+			log.Warn("circuit breaker threshold reached, system degraded",
 				"failure_count", stats.Count,
 				"duration", stats.Duration,
-				"recent_reasons", stats.RecentReasons)
+				"recent_reasons", stats.RecentReasons,
+				"failure_classes", stats.Classes)
 
-			// Send system degraded alert to Slack if configured and enabled
-			if slackNotifier != nil && cfg.NotifyOnAgentFailure {
-				if err := slackNotifier.SendSystemDegradedAlert(ctx, stats); err != nil {
-					slog.Error("failed to send system degraded alert", "error", err)
+			// Send system degraded alert if notifications are configured and enabled
+			if p.notifier != nil && p.cfg.NotifyOnAgentFailure {
+				if err := p.notifier.SendSystemDegradedAlert(ctx, stats); err != nil {
+					log.Error("failed to send system degraded alert", "error", err)
 				} else {
-					slog.Info("system degraded alert sent to slack",
+					log.Info("system degraded alert sent",
 						"failure_count", stats.Count,
 						"duration", stats.Duration)
 				}
 			} else {
-				if slackNotifier == nil {
-					slog.Debug("slack not configured, skipping system degraded alert")
+				if p.notifier == nil {
+					log.Debug("notifications not configured, skipping system degraded alert")
 				} else {
-					slog.Debug("system degraded alert disabled by configuration",
+					log.Debug("system degraded alert disabled by configuration",
 						"config", "notify_on_agent_failure=false")
 				}
 			}
 		}
 	} else {
 		// Record success in circuit breaker and get stats before reset
-		stats := circuitBreaker.GetStats()
-		needsRecoveryAlert := circuitBreaker.RecordSuccess()
-		slog.Debug("circuit breaker: recorded success",
+		stats := p.circuitBreaker.GetStats()
+		needsRecoveryAlert := p.circuitBreaker.RecordSuccess()
+		if p.providerStatus != nil {
+			p.providerStatus.RecordSuccess(p.cfg.ActiveLLMProvider())
+		}
+		log.Debug("circuit breaker: recorded success",
 			"needs_recovery_alert", needsRecoveryAlert)
 
 		// Send recovery alert if needed
 		if needsRecoveryAlert {
-			slog.Info("circuit breaker recovered, system returned to healthy state",
+			log.Info("circuit breaker recovered, system returned to healthy state",
 				"total_failures", stats.Count,
 				"total_downtime", stats.Duration)
 
-			// Send system recovered alert to Slack if configured and enabled
-			if slackNotifier != nil && cfg.NotifyOnAgentFailure {
-				if err := slackNotifier.SendSystemRecoveredAlert(ctx, stats); err != nil {
-					slog.Error("failed to send system recovered alert", "error", err)
+			// Send system recovered alert if notifications are configured and enabled
+			if p.notifier != nil && p.cfg.NotifyOnAgentFailure {
+				if err := p.notifier.SendSystemRecoveredAlert(ctx, stats); err != nil {
+					log.Error("failed to send system recovered alert", "error", err)
 				} else {
-					slog.Info("system recovered alert sent to slack",
+					log.Info("system recovered alert sent",
 						"total_failures", stats.Count,
 						"total_downtime", stats.Duration)
 				}
 			} else {
-				if slackNotifier == nil {
-					slog.Debug("slack not configured, skipping system recovered alert")
+				if p.notifier == nil {
+					log.Debug("notifications not configured, skipping system recovered alert")
 				} else {
-					slog.Debug("system recovered alert disabled by configuration",
+					log.Debug("system recovered alert disabled by configuration",
 						"config", "notify_on_agent_failure=false")
 				}
 			}
 		}
 	}
 
+	// Check the agent's claims against the live cluster
+	if p.verifier != nil && !agentFailed {
+		p.verifyFindings(ctx, inc, workspacePath)
+	}
+
+	// Attach the labels the agent assigned from its findings
+	if p.cfg.IncidentLabels.FromAgent && !agentFailed {
+		p.attachAgentLabels(ctx, inc, workspacePath)
+	}
+
+	// Hold the findings for human review when the review policy asks for it
+	if inc.Status == incident.StatusResolved {
+		p.applyReviewPolicy(ctx, inc, workspacePath)
+	}
+
 	// Mark incident as complete in state store
-	if stateStore != nil {
-		if err := stateStore.CompleteIncident(ctx, incidentID, exitCode, inc.FailureReason); err != nil {
-			slog.Error("failed to complete incident in state store", "incident_id", incidentID, "error", err)
+	if p.stateStore != nil {
+		if err := p.stateStore.CompleteIncident(ctx, incidentID, inc.Status, exitCode, inc.FailureReason, inc.FailureClass); err != nil {
+			log.Error("failed to complete incident in state store", "error", err)
 		}
 	}
 
@@ -635,21 +2533,20 @@ func processEvent(ctx context.Context, event *events.FaultEvent, clusterName str
 
 	// Save incident artifacts to storage
 	var reportURL string
-	if storageBackend != nil {
+	if p.storageBackend != nil {
 		// Skip storage upload for agent failures (missing/invalid output) unless configured otherwise
-		if inc.Status == incident.StatusAgentFailed && !cfg.UploadFailedInvestigations {
-			slog.Info("skipping storage upload due to agent failure",
-				"incident_id", incidentID,
+		if inc.Status == incident.StatusAgentFailed && !p.cfg.UploadFailedInvestigations {
+			log.Info("skipping storage upload due to agent failure",
 				"reason", inc.FailureReason,
 				"config", "upload_failed_investigations=false")
 		} else {
 			// Read the generated artifacts and convert markdown to HTML
-			artifacts, err := readIncidentArtifacts(workspacePath, incidentID, logPaths)
+			artifacts, err := readIncidentArtifacts(workspacePath, inc.Ref(), logPaths, p.markdownRenderer)
 			if err != nil {
-				slog.Warn("failed to read incident artifacts for storage", "error", err)
+				log.Warn("failed to read incident artifacts for storage", "error", err)
 			} else {
 				// Record triage report in state store
-				if stateStore != nil {
+				if p.stateStore != nil {
 					report := &storage.TriageReport{
 						ReportID:       uuid.New().String(),
 						IncidentID:     incidentID,
@@ -658,64 +2555,139 @@ func processEvent(ctx context.Context, event *events.FaultEvent, clusterName str
 						ReportMarkdown: string(artifacts.InvestigationMD),
 						ReportHTML:     string(artifacts.InvestigationHTML),
 					}
-					if err := stateStore.RecordTriageReport(ctx, report); err != nil {
-						slog.Error("failed to record triage report in state store", "incident_id", incidentID, "error", err)
+					if err := p.stateStore.RecordTriageReport(ctx, report); err != nil {
+						log.Error("failed to record triage report in state store", "error", err)
 					}
 				}
 
 				// Upload artifacts to storage (Azure or filesystem)
-				saveResult, err := storageBackend.SaveIncident(ctx, incidentID, artifacts)
+				uploadStart := time.Now()
+				saveResult, err := p.storageBackend.SaveIncident(ctx, inc.Ref(), artifacts)
+				inc.Latency.Record(incident.StageUpload, time.Since(uploadStart))
 				if err != nil {
-					slog.Error("failed to save incident to storage", "error", err)
+					log.Error("failed to save incident to storage", "error", err)
+					p.retryUpload(ctx, inc.Ref(), workspacePath, logPaths)
 				} else {
 					reportURL = saveResult.ReportURL
-					slog.Info("incident artifacts saved to storage",
-						"incident_id", incidentID,
+					log.Info("incident artifacts saved to storage",
 						"artifact_count", len(saveResult.ArtifactURLs),
 						"log_url_count", len(saveResult.LogURLs),
 						"report_url", reportURL)
+					reportURL = p.shortenReportURL(ctx, reportURL)
 
-					// Populate log URLs in incident from storage result
+					// Populate log URLs and artifact hashes in incident from storage result
 					inc.LogURLs = saveResult.LogURLs
+					p.recordArtifactHashes(ctx, inc, saveResult.Hashes)
 
-					// Update incident.json with log URLs
+					// Update incident.json with log URLs and artifact hashes
 					if err := inc.WriteToFile(incidentPath); err != nil {
-						slog.Warn("failed to update incident.json with log URLs", "error", err)
+						log.Warn("failed to update incident.json with log URLs", "error", err)
 					}
 				}
 			}
 		}
 	}
 
-	slog.Info("event processed",
-		"incident_id", incidentID,
+	// Publish the postmortem to the docs-as-code repository
+	if p.postmortems != nil && inc.Status == incident.StatusResolved {
+		p.publishPostmortem(ctx, inc, workspacePath, reportURL)
+		if err := inc.WriteToFile(incidentPath); err != nil {
+			log.Warn("failed to update incident.json with postmortem URL", "error", err)
+		}
+	}
+
+	// Publish to the organization's knowledge bases
+	if len(p.knowledgeBases) > 0 && inc.Status == incident.StatusResolved {
+		p.publishToKnowledgeBases(ctx, inc, workspacePath, reportURL)
+		if err := inc.WriteToFile(incidentPath); err != nil {
+			log.Warn("failed to update incident.json with knowledge base URLs", "error", err)
+		}
+	}
+
+	// File (or update) the ServiceNow record. Agent failures say nothing about the
+	// fault, so they are left to the circuit breaker alerts.
+	if p.serviceNow != nil && inc.Status != incident.StatusAgentFailed {
+		rootCause, confidence, err := reporting.ExtractSummaryFromReport(workspacePath)
+		if err != nil {
+			log.Debug("servicenow record filed without a report summary", "error", err)
+		}
+		p.fileServiceNowRecord(ctx, inc, rootCause, confidence, reportURL)
+		if err := inc.WriteToFile(incidentPath); err != nil {
+			log.Warn("failed to update incident.json with servicenow record", "error", err)
+		}
+	}
+
+	// Index the completed incident for organization-wide search
+	if p.searchIndex != nil {
+		var reportPath, rootCause, confidence string
+		if inc.Status != incident.StatusAgentFailed {
+			reportPath = filepath.Join(workspacePath, "output", "investigation.md")
+			rootCause, confidence, _ = reporting.ExtractSummaryFromReport(workspacePath)
+		}
+		p.exportToSearchIndex(ctx, inc, reportPath, rootCause, confidence, reportURL)
+	}
+
+	// Record the investigation's result in the Incident resource
+	if p.incidentResources != nil && incident.Investigated(inc.Status) {
+		rootCause, confidence, _ := reporting.ExtractSummaryFromReport(workspacePath)
+		resourceResult = &operator.IncidentResult{RootCause: rootCause, Confidence: confidence, ReportURL: reportURL}
+	}
+
+	log.Info("event processed",
 		"status", inc.Status,
 		"exit_code", exitCode,
 		"duration", duration)
+	p.emitOutcome(ctx, inc, map[string]string{"report_url": reportURL})
+
+	// Compare a follow-up's findings with the prior investigation's
+	var investigationDiff *incident.InvestigationDiff
+	if prior != nil && inc.Status == incident.StatusResolved {
+		investigationDiff = diffInvestigation(ctx, workspacePath, prior)
+	}
+
+	// Cache successful investigations so identical faults can reuse the report
+	if inc.Status == incident.StatusResolved && p.investigationCache.Enabled() {
+		rootCause, confidence, err := reporting.ExtractSummaryFromReport(workspacePath)
+		if err != nil {
+			log.Debug("not caching investigation without a readable summary", "error", err)
+		} else {
+			p.investigationCache.Put(incident.CachedInvestigation{
+				Signature:   inc.FaultSignature,
+				IncidentID:  inc.Ref(),
+				CompletedAt: *inc.CompletedAt,
+				RootCause:   rootCause,
+				Confidence:  confidence,
+				ReportPath:  filepath.Join(workspacePath, "output", "investigation.md"),
+				ReportURL:   reportURL,
+			})
+			log.Debug("investigation cached", "fault_signature", inc.FaultSignature)
+		}
+	}
 
-	// Send Slack notification if configured
-	if slackNotifier != nil {
+	// Send chat notification if configured
+	if p.notifier != nil {
 		// Always skip individual notifications for agent failures to prevent spam
 		// Circuit breaker will send aggregated alerts if configured
 		if inc.Status == incident.StatusAgentFailed {
-			slog.Info("skipping slack notification due to agent failure",
-				"incident_id", incidentID,
+			log.Info("skipping notification due to agent failure",
 				"reason", inc.FailureReason,
 				"note", "circuit breaker will send aggregated alert if threshold reached")
 		} else {
 			rootCause, confidence, err := reporting.ExtractSummaryFromReport(workspacePath)
 			if err != nil {
-				slog.Warn("failed to extract report summary for slack", "error", err)
+				log.Warn("failed to extract report summary for notification", "error", err)
 				rootCause = "See investigation report"
 				confidence = "UNKNOWN"
 			}
 
 			summary := &reporting.IncidentSummary{
-				IncidentID: incidentID,
+				IncidentID: inc.Ref(),
 				Cluster:    inc.Cluster,
 				Namespace:  inc.Namespace,
 				Resource:   fmt.Sprintf("%s/%s", inc.Resource.Kind, inc.Resource.Name),
 				Reason:     inc.FaultType,
+				Severity:   inc.Severity,
+				Labels:     inc.Labels,
 				Status:     inc.Status,
 				RootCause:  rootCause,
 				Confidence: confidence,
@@ -723,20 +2695,33 @@ func processEvent(ctx context.Context, event *events.FaultEvent, clusterName str
 				ReportPath: filepath.Join(workspacePath, "output", "investigation.md"),
 				ReportURL:  reportURL,
 			}
+			if prior != nil {
+				summary.FollowUpOf = prior.Chain
+			}
+			if investigationDiff != nil {
+				summary.Changes = investigationDiff.Summary()
+			}
+			setSummaryOwner(summary, inc)
 
-			slog.Info("sending slack notification",
-				"incident_id", incidentID,
+			log.Info("sending notification",
 				"report_url", reportURL,
 				"has_url", reportURL != "")
 
-			if err := slackNotifier.SendIncidentNotification(summary); err != nil {
-				slog.Error("failed to send slack notification", "error", err)
-			} else {
-				slog.Info("slack notification sent", "incident_id", incidentID)
-			}
+			notifyStart := time.Now()
+			p.sendNotification(ctx, summary)
+			inc.Latency.Record(incident.StageNotify, time.Since(notifyStart))
 		}
 	}
 
+	// Export the latency breakdown and keep it in incident.json
+	p.recordLatency(ctx, inc, receivedAt)
+	if err := inc.WriteToFile(incidentPath); err != nil {
+		log.Warn("failed to update incident.json with latency", "error", err)
+	}
+
+	// Listed among the recent results on /health/investigations (nightcrier top)
+	p.progress.Complete(inc)
+
 	return nil
 }
 
@@ -778,29 +2763,28 @@ func detectAgentFailure(workspacePath string, exitCode int, err error, tuning *c
 	return false, ""
 }
 
-func setupLogging(level string) {
-	var logLevel slog.Level
-	switch level {
-	case "debug":
-		logLevel = slog.LevelDebug
-	case "warn":
-		logLevel = slog.LevelWarn
-	case "error":
-		logLevel = slog.LevelError
-	default:
+// setupLogging installs the default logger at level and returns its handler, whose
+// per-component levels can be changed at runtime.
+func setupLogging(level string) *logging.Handler {
+	logLevel, err := logging.ParseLevel(level)
+	if err != nil {
 		logLevel = slog.LevelInfo
 	}
+	levels, _ := logging.NewLevels(logLevel, nil)
 
-	handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: logLevel,
+	// The text handler accepts every level; the logging handler filters by component
+	inner := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
 	})
+	handler := logging.NewHandler(inner, levels)
 	slog.SetDefault(slog.New(handler))
+	return handler
 }
 
 // readIncidentArtifacts reads the generated artifacts from the workspace for storage upload.
-// It also converts the markdown report to HTML for better browser rendering.
+// It also converts the markdown report to sanitized HTML for better browser rendering.
 // It reads agent logs if they exist.
-func readIncidentArtifacts(workspacePath, incidentID string, logPaths agent.LogPaths) (*storage.IncidentArtifacts, error) {
+func readIncidentArtifacts(workspacePath, incidentID string, logPaths agent.LogPaths, renderer reporting.MarkdownRenderer) (*storage.IncidentArtifacts, error) {
 	// Read incident.json
 	incidentPath := filepath.Join(workspacePath, "incident.json")
 	incidentJSON, err := os.ReadFile(incidentPath)
@@ -815,8 +2799,16 @@ func readIncidentArtifacts(workspacePath, incidentID string, logPaths agent.LogP
 		return nil, fmt.Errorf("failed to read investigation.md: %w", err)
 	}
 
-	// Convert markdown to HTML for better browser rendering
-	investigationHTML := reporting.ConvertMarkdownToHTML(investigationMD, incidentID)
+	// Convert markdown to HTML for better browser rendering, with the resource
+	// topology diagram when one was collected. The diagram is rendered again from
+	// topology.json rather than read from the workspace, so its labels are escaped.
+	var topologySVG []byte
+	if graph, err := topology.Read(workspacePath); err != nil {
+		slog.Warn("report rendered without resource topology", "error", err)
+	} else if graph != nil {
+		topologySVG = topology.RenderSVG(graph)
+	}
+	investigationHTML := reporting.RenderReportPage(renderer, investigationMD, incidentID, topologySVG)
 
 	// Read agent logs if they exist (logs are optional)
 	var agentLogs storage.AgentLogs
@@ -927,24 +2919,98 @@ func readIncidentArtifacts(workspacePath, incidentID string, logPaths agent.LogP
 	}, nil
 }
 
-// printStartupBanner displays configuration summary at startup
-func printStartupBanner(cfg *config.Config, configFile string) {
-	// Determine artifact storage mode (for reports/logs)
-	artifactStorage := "local_filesystem"
+// bannerStorage returns the artifact storage (reports and logs) and the state
+// storage (incident metadata) in use.
+func bannerStorage(cfg *config.Config) (artifactStorage, stateStorage string) {
+	artifactStorage = "local_filesystem"
 	if cfg.IsAzureStorageEnabled() {
 		artifactStorage = "azure_blob"
 	}
-
-	// Determine state storage mode (for incident metadata)
-	stateStorage := cfg.GetStateStorageType()
+	stateStorage = cfg.GetStateStorageType()
 	if stateStorage == "" {
 		stateStorage = "filesystem"
 	}
+	return artifactStorage, stateStorage
+}
 
-	// Determine slack status
-	slackStatus := "disabled"
+// notificationDestinations returns the configured notification webhooks.
+func notificationDestinations(cfg *config.Config) []string {
+	var destinations []string
 	if cfg.SlackWebhookURL != "" {
-		slackStatus = "enabled"
+		destinations = append(destinations, "slack")
+	}
+	if cfg.DiscordWebhookURL != "" {
+		destinations = append(destinations, "discord")
+	}
+	if cfg.MattermostWebhookURL != "" {
+		destinations = append(destinations, "mattermost")
+	}
+	return destinations
+}
+
+// startupInfo is the startup banner in JSON form (--banner=json).
+type startupInfo struct {
+	Version         string   `json:"version"`
+	BuildTime       string   `json:"build_time"`
+	GitCommit       string   `json:"git_commit"`
+	ConfigFile      string   `json:"config_file,omitempty"`
+	Profile         string   `json:"profile,omitempty"`
+	ConfigOverlays  []string `json:"config_overlays,omitempty"`
+	Clusters        []string `json:"clusters"`
+	ServeOnly       []string `json:"serve_only_clusters,omitempty"`
+	SingleCluster   bool     `json:"single_cluster"`
+	AgentCLI        string   `json:"agent_cli"`
+	AgentModel      string   `json:"agent_model"`
+	LLMProvider     string   `json:"llm_provider,omitempty"`
+	ArtifactStorage string   `json:"artifact_storage"`
+	StateStorage    string   `json:"state_storage"`
+	Notifications   []string `json:"notifications"`
+	// Config is the effective configuration as dotted keys, with secrets masked
+	// (see config.Config.Snapshot)
+	Config map[string]string `json:"config"`
+}
+
+// printStartupBannerJSON writes the startup configuration to w as one line of
+// JSON for automation, with secrets masked.
+func printStartupBannerJSON(w io.Writer, cfg *config.Config, configFile string) error {
+	artifactStorage, stateStorage := bannerStorage(cfg)
+	info := startupInfo{
+		Version:         Version,
+		BuildTime:       BuildTime,
+		GitCommit:       GitCommit,
+		ConfigFile:      configFile,
+		Profile:         cfg.Profile,
+		ConfigOverlays:  cfg.ConfigLayers,
+		Clusters:        []string{},
+		SingleCluster:   cfg.SingleCluster,
+		AgentCLI:        cfg.AgentCLI,
+		AgentModel:      cfg.EffectiveAgentModel(),
+		LLMProvider:     cfg.LLMProvider(),
+		ArtifactStorage: artifactStorage,
+		StateStorage:    stateStorage,
+		Notifications:   notificationDestinations(cfg),
+		Config:          cfg.Snapshot(),
+	}
+	for _, cl := range cfg.Clusters {
+		info.Clusters = append(info.Clusters, cl.Name)
+		if cl.ServeOnly {
+			info.ServeOnly = append(info.ServeOnly, cl.Name)
+		}
+	}
+	if info.Notifications == nil {
+		info.Notifications = []string{}
+	}
+	return json.NewEncoder(w).Encode(info)
+}
+
+// printStartupBanner displays configuration summary at startup
+func printStartupBanner(cfg *config.Config, configFile string) {
+	artifactStorage, stateStorage := bannerStorage(cfg)
+
+	// Determine notification destinations
+	notifyStatus := "disabled"
+	if destinations := notificationDestinations(cfg); len(destinations) > 0 {
+		notifyStatus = strings.Join(destinations, ", ")
 	}
 
 	// Mask sensitive values
@@ -960,19 +3026,47 @@ func printStartupBanner(cfg *config.Config, configFile string) {
 	fmt.Printf("║         Built:   %-45s║\n", truncateString(BuildTime, 45))
 	fmt.Println("╠═══════════════════════════════════════════════════════════════╣")
 	fmt.Printf("║  Config File:    %-45s ║\n", truncateString(configSource, 45))
+	if cfg.Profile != "" || len(cfg.ConfigLayers) > 0 {
+		profile := cfg.Profile
+		if profile == "" {
+			profile = "(none)"
+		}
+		fmt.Printf("║  Profile:        %-45s ║\n", truncateString(fmt.Sprintf("%s, %d overlay(s)", profile, len(cfg.ConfigLayers)), 45))
+	}
 	fmt.Println("╠═══════════════════════════════════════════════════════════════╣")
-	fmt.Printf("║  Clusters:       %-45s ║\n", fmt.Sprintf("%d configured", len(cfg.Clusters)))
+	clusterSummary := fmt.Sprintf("%d configured", len(cfg.Clusters))
+	if cfg.SingleCluster {
+		clusterSummary = fmt.Sprintf("single-cluster mode (%s)", cfg.SingleClusterName)
+	}
+	serveOnly := 0
+	for _, cl := range cfg.Clusters {
+		if cl.ServeOnly {
+			serveOnly++
+		}
+	}
+	if serveOnly > 0 {
+		clusterSummary += fmt.Sprintf(", %d serve-only", serveOnly)
+	}
+	fmt.Printf("║  Clusters:       %-45s ║\n", truncateString(clusterSummary, 45))
 	fmt.Printf("║  Subscribe Mode: %-45s ║\n", cfg.SubscribeMode)
 	fmt.Println("╠═══════════════════════════════════════════════════════════════╣")
 	fmt.Printf("║  Agent CLI:      %-45s ║\n", cfg.AgentCLI)
-	fmt.Printf("║  Agent Model:    %-45s ║\n", cfg.AgentModel)
+	fmt.Printf("║  Agent Model:    %-45s ║\n", truncateString(cfg.EffectiveAgentModel(), 45))
+	switch cfg.LLMProvider() {
+	case config.LLMProviderOpenAICompatible:
+		fmt.Printf("║  LLM Endpoint:   %-45s ║\n", truncateString(cfg.LLMEndpoint.BaseURL, 45))
+	case config.LLMProviderAzureOpenAI:
+		fmt.Printf("║  LLM Provider:   %-45s ║\n", truncateString("Azure OpenAI ("+cfg.AzureOpenAI.Endpoint+")", 45))
+	case config.LLMProviderBedrock:
+		fmt.Printf("║  LLM Provider:   %-45s ║\n", truncateString("AWS Bedrock ("+cfg.Bedrock.Region+")", 45))
+	}
 	fmt.Printf("║  Agent Timeout:  %-45s ║\n", fmt.Sprintf("%ds", cfg.AgentTimeout))
 	fmt.Printf("║  Allowed Tools:  %-45s ║\n", truncateString(cfg.AgentAllowedTools, 45))
 	fmt.Println("╠═══════════════════════════════════════════════════════════════╣")
 	fmt.Printf("║  Workspace Root:     %-41s ║\n", truncateString(cfg.WorkspaceRoot, 41))
 	fmt.Printf("║  Artifact Storage:   %-41s ║\n", artifactStorage)
 	fmt.Printf("║  State Storage:      %-41s ║\n", stateStorage)
-	fmt.Printf("║  Slack:              %-41s ║\n", slackStatus)
+	fmt.Printf("║  Notifications:      %-41s ║\n", notifyStatus)
 	fmt.Println("╠═══════════════════════════════════════════════════════════════╣")
 	fmt.Printf("║  Log Level:      %-45s ║\n", cfg.LogLevel)
 	fmt.Printf("║  Max Concurrent: %-45s ║\n", fmt.Sprintf("%d agents", cfg.MaxConcurrentAgents))
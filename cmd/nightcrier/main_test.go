@@ -1,12 +1,16 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/rbias/nightcrier/internal/cluster"
 	"github.com/rbias/nightcrier/internal/config"
 	"github.com/rbias/nightcrier/internal/reporting"
 )
@@ -451,8 +455,8 @@ func TestProcessEvent_IntegrationFlow(t *testing.T) {
 	t.Log("  5. Slack notification SKIPPED")
 	t.Log("")
 	t.Log("MANUAL TESTING:")
-	t.Log("  Run: go build -o runner ./cmd/runner")
-	t.Log("  Test success: ./runner -c configs/config-test.yaml")
+	t.Log("  Run: go build -o nightcrier ./cmd/nightcrier")
+	t.Log("  Test success: ./nightcrier -c configs/config-test.yaml")
 	t.Log("  Test failure: Modify agent script to exit 1 or simulate API failure")
 	t.Log("  Verify: Check logs for skip messages and incident.json status")
 }
@@ -1117,3 +1121,45 @@ func TestCircuitBreakerConfigInteraction(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintStartupBannerJSON(t *testing.T) {
+	cfg := &config.Config{
+		Clusters: []cluster.ClusterConfig{
+			{Name: "prod", MCP: cluster.MCPConfig{Endpoint: "http://prod:8080/mcp"}},
+			{Name: "staging", ServeOnly: true},
+		},
+		AgentCLI:        "claude",
+		AgentModel:      "sonnet",
+		AnthropicAPIKey: "sk-ant-xyzzy",
+		SlackWebhookURL: "https://hooks.slack.com/services/xyzzy",
+		WorkspaceRoot:   "/incidents",
+	}
+
+	var buf bytes.Buffer
+	if err := printStartupBannerJSON(&buf, cfg, "/etc/nightcrier/config.yaml"); err != nil {
+		t.Fatalf("printStartupBannerJSON() failed: %v", err)
+	}
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("banner is not one line of JSON: %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "xyzzy") {
+		t.Errorf("banner leaks a secret: %s", buf.String())
+	}
+
+	var info startupInfo
+	if err := json.Unmarshal(buf.Bytes(), &info); err != nil {
+		t.Fatalf("banner is not valid JSON: %v", err)
+	}
+	if info.ConfigFile != "/etc/nightcrier/config.yaml" || info.AgentCLI != "claude" || info.StateStorage != "filesystem" {
+		t.Errorf("ConfigFile, AgentCLI, StateStorage = %q, %q, %q", info.ConfigFile, info.AgentCLI, info.StateStorage)
+	}
+	if len(info.Clusters) != 2 || len(info.ServeOnly) != 1 || info.ServeOnly[0] != "staging" {
+		t.Errorf("Clusters, ServeOnly = %v, %v, want [prod staging], [staging]", info.Clusters, info.ServeOnly)
+	}
+	if len(info.Notifications) != 1 || info.Notifications[0] != "slack" {
+		t.Errorf("Notifications = %v, want [slack]", info.Notifications)
+	}
+	if info.Config["workspace_root"] != "/incidents" || info.Config["anthropic_api_key"] != "<redacted>" {
+		t.Errorf("Config workspace_root, anthropic_api_key = %q, %q", info.Config["workspace_root"], info.Config["anthropic_api_key"])
+	}
+}
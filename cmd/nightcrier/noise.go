@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/noise"
+	"github.com/rbias/nightcrier/internal/reporting"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Incidents noise command flags
+	noiseSince        time.Duration
+	noiseMinIncidents int
+	noiseFormat       string
+)
+
+var incidentsNoiseCmd = &cobra.Command{
+	Use:   "noise",
+	Short: "Suggest tuning for fault types producing low-value investigations",
+	Long: `Review the incident history for fault types that keep producing investigations
+of little value in a namespace, and suggest the tuning that would quiet them.
+
+Incidents are grouped by cluster, namespace, and fault type. A group with at least
+--min-incidents incidents, at least half of them low-value (a repeat of an
+identical fault on a resource already investigated in the period, or a failed
+investigation), gets a suggestion: a dedup window longer than the typical gap
+between the repeats, or a filter (a namespace silence or a higher
+severity_threshold) when the repeats are too far apart or the investigations
+failed. The same analysis runs periodically when noise_analysis is enabled.
+Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier incidents noise
+  nightcrier incidents noise --since 720h --min-incidents 20 --format json`,
+	Args: cobra.NoArgs,
+	RunE: runIncidentsNoise,
+}
+
+func init() {
+	incidentsNoiseCmd.Flags().DurationVar(&noiseSince, "since", 0, "Analyze the incidents created in this period (default noise_analysis.window_days)")
+	incidentsNoiseCmd.Flags().IntVar(&noiseMinIncidents, "min-incidents", 0, "Incidents a fault type must produce in a namespace to be flagged (default noise_analysis.min_incidents)")
+	incidentsNoiseCmd.Flags().StringVar(&noiseFormat, "format", "table", "Output format: table or json")
+	incidentsCmd.AddCommand(incidentsNoiseCmd)
+}
+
+func runIncidentsNoise(cmd *cobra.Command, args []string) error {
+	if noiseFormat != "table" && noiseFormat != "json" {
+		return fmt.Errorf("unknown format %q: must be table or json", noiseFormat)
+	}
+	if noiseSince < 0 || noiseMinIncidents < 0 {
+		return fmt.Errorf("--since and --min-incidents must be positive")
+	}
+
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	setupLogging("warn")
+	if noiseMinIncidents > 0 {
+		cfg.NoiseAnalysis.MinIncidents = noiseMinIncidents
+	}
+
+	ctx := context.Background()
+	store, err := openStateStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("incident queries require a sqlite or postgres state store (state_storage.type is %q)", cfg.GetStateStorageType())
+	}
+	defer store.Close()
+
+	window := cfg.NoiseAnalysis.Window()
+	if noiseSince > 0 {
+		window = noiseSince
+	}
+	report, err := analyzeNoise(ctx, cfg, store, window, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if noiseFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	if len(report.Suggestions) == 0 {
+		fmt.Printf("No noisy fault types in the %d incidents of the last %s\n", report.Incidents, window)
+		return nil
+	}
+	fmt.Printf("%-16s %-20s %-24s %9s %9s %7s %6s  %s\n", "CLUSTER", "NAMESPACE", "FAULT TYPE", "INCIDENTS", "LOW VALUE", "REPEATS", "FAILED", "SUGGESTION")
+	for _, s := range report.Suggestions {
+		fmt.Printf("%-16s %-20s %-24s %9d %9d %7d %6d  %s\n",
+			truncateString(orDash(s.Cluster), 16),
+			truncateString(orDash(s.Namespace), 20),
+			truncateString(orDash(s.FaultType), 24),
+			s.Incidents, s.LowValue, s.Repeats, s.Failed,
+			s.Action)
+	}
+	fmt.Println()
+	for _, s := range report.Suggestions {
+		fmt.Printf("- %s\n", s.Text)
+	}
+	return nil
+}
+
+// analyzeNoise runs the noise analysis over the incidents created within window
+// before now.
+func analyzeNoise(ctx context.Context, cfg *config.Config, store storage.StateStore, window time.Duration, now time.Time) (*noise.Report, error) {
+	since := now.Add(-window)
+	incidents, err := store.ListIncidents(ctx, &storage.IncidentFilters{CreatedAfter: &since})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list incidents: %w", err)
+	}
+	return noise.Analyze(incidents, since, now, noise.Options{
+		MinIncidents:   cfg.NoiseAnalysis.MinIncidents,
+		MaxSuggestions: cfg.NoiseAnalysis.MaxSuggestions,
+		DedupWindow:    time.Duration(cfg.DedupWindowSeconds) * time.Second,
+	}), nil
+}
+
+// noiseDigest renders the tuning suggestions of a noise analysis as a digest.
+func noiseDigest(report *noise.Report, window time.Duration) reporting.Digest {
+	items := make([]string, 0, len(report.Suggestions))
+	for _, s := range report.Suggestions {
+		items = append(items, s.Text)
+	}
+	return reporting.Digest{
+		Heading:  fmt.Sprintf("Nightcrier: %d tuning suggestions", len(report.Suggestions)),
+		Note:     fmt.Sprintf("From the %d incidents of the last %s. Run \"nightcrier incidents noise\" for details.", report.Incidents, window),
+		Count:    len(report.Suggestions),
+		Sections: []reporting.DigestSection{{Title: reporting.DigestSectionTuning, Items: items}},
+	}
+}
+
+// noiseAnalyzer runs the noise analysis periodically and keeps the latest report
+// for the /health/noise endpoint.
+type noiseAnalyzer struct {
+	cfg      *config.Config
+	store    storage.StateStore
+	notifier reporting.Notifier
+
+	mu     sync.Mutex
+	latest *noise.Report
+}
+
+// GetNoiseReport implements health.NoiseHealth. Before the first analysis it
+// returns an empty report.
+func (a *noiseAnalyzer) GetNoiseReport() interface{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.latest == nil {
+		return &noise.Report{Suggestions: []noise.Suggestion{}}
+	}
+	return a.latest
+}
+
+// Run analyzes the incident history now and every interval until ctx is done.
+// The periodic analyses send a digest of the suggestions when there are any; the
+// one at startup does not, so restarts do not repeat the digest.
+func (a *noiseAnalyzer) Run(ctx context.Context) {
+	a.analyze(ctx, false)
+	ticker := time.NewTicker(a.cfg.NoiseAnalysis.Interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.analyze(ctx, true)
+		}
+	}
+}
+
+func (a *noiseAnalyzer) analyze(ctx context.Context, notify bool) {
+	window := a.cfg.NoiseAnalysis.Window()
+	report, err := analyzeNoise(ctx, a.cfg, a.store, window, time.Now())
+	if err != nil {
+		slog.Error("noise analysis failed", "error", err)
+		return
+	}
+	a.mu.Lock()
+	a.latest = report
+	a.mu.Unlock()
+
+	slog.Info("noise analysis completed", "incidents", report.Incidents, "suggestions", len(report.Suggestions))
+	if !notify || len(report.Suggestions) == 0 || a.notifier == nil {
+		return
+	}
+	if err := a.notifier.SendDigest(ctx, noiseDigest(report, window)); err != nil {
+		slog.Error("failed to send tuning suggestions", "error", err)
+	}
+}
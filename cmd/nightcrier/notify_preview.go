@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/proxy"
+	"github.com/rbias/nightcrier/internal/reporting"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// previewWebhookURL is where previewed notifications are "sent"; the request never
+// leaves the process (see previewTransport).
+const previewWebhookURL = "https://notification-preview.invalid/webhook"
+
+var (
+	// Notification preview command flags
+	previewIncident  string
+	previewChannel   string
+	previewSend      bool
+	previewTargetURL string
+	previewReportURL string
+)
+
+var notifyPreviewCmd = &cobra.Command{
+	Use:   "notify-preview",
+	Short: "Render the notification for an existing incident",
+	Long: `Render the exact webhook payload nightcrier would send for an existing incident
+and print it, so notification formatting can be checked without waiting for a new
+fault. The notification is built from the incident and its report in the state
+store (sqlite or postgres required).
+
+With --send the notification is delivered to the channel's configured webhook,
+or to --webhook-url (e.g. a test channel) when given.
+
+Supported channels: slack, discord, mattermost. Teams and email are rejected:
+nightcrier has no Teams or email notifier, so there is no payload to preview.`,
+	Example: `  nightcrier notify-preview --incident NC-2024-0613-prod-0042
+  nightcrier notify-preview --incident 2f1c... --channel discord --send --webhook-url https://discord.com/api/webhooks/test/...`,
+	Args: cobra.NoArgs,
+	RunE: runNotifyPreview,
+}
+
+func init() {
+	notifyPreviewCmd.Flags().StringVar(&previewIncident, "incident", "", "Incident UUID or display ID (required)")
+	notifyPreviewCmd.Flags().StringVar(&previewChannel, "channel", "slack", "Notification channel: slack, discord, or mattermost (teams and email have no notifier)")
+	notifyPreviewCmd.Flags().BoolVar(&previewSend, "send", false, "Send the notification instead of printing it")
+	notifyPreviewCmd.Flags().StringVar(&previewTargetURL, "webhook-url", "", "Webhook to send to with --send (default: the channel's configured webhook)")
+	notifyPreviewCmd.Flags().StringVar(&previewReportURL, "report-url", "", "Report URL to show in the notification (report links are not stored with the incident)")
+	_ = notifyPreviewCmd.MarkFlagRequired("incident")
+
+	rootCmd.AddCommand(notifyPreviewCmd)
+}
+
+func runNotifyPreview(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	tuning, err := config.LoadTuning()
+	if err != nil {
+		return fmt.Errorf("failed to load tuning configuration: %w", err)
+	}
+	setupLogging("warn")
+	if err := checkPreviewChannel(previewChannel); err != nil {
+		return err
+	}
+
+	webhookURL := previewWebhookURL
+	if previewSend {
+		webhookURL = previewTargetURL
+		if webhookURL == "" {
+			webhookURL = configuredWebhook(cfg, previewChannel)
+		}
+		if webhookURL == "" {
+			return fmt.Errorf("no %s webhook is configured; pass --webhook-url", previewChannel)
+		}
+	}
+	notifier, err := newChannelNotifier(cfg, tuning, previewChannel, webhookURL)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	store, err := openStateStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("notification previews require a sqlite or postgres state store (state_storage.type is %q)", cfg.GetStateStorageType())
+	}
+	defer store.Close()
+
+	inc, err := resolveIncident(ctx, store, previewIncident)
+	if err != nil {
+		return err
+	}
+	summary, err := previewSummary(ctx, store, cfg, inc)
+	if err != nil {
+		return err
+	}
+	summary.ReportURL = previewReportURL
+
+	if previewSend {
+		if err := notifier.SendIncidentNotification(summary); err != nil {
+			return err
+		}
+		fmt.Printf("Sent %s notification for incident %s\n", previewChannel, inc.Ref())
+		return nil
+	}
+
+	payload, err := capturePayload(notifier, summary)
+	if err != nil {
+		return err
+	}
+	fmt.Println(payload)
+	return nil
+}
+
+// channelNotifier is a single-destination notifier whose HTTP transport can be
+// replaced.
+type channelNotifier interface {
+	reporting.Notifier
+	SetTransport(http.RoundTripper)
+}
+
+// checkPreviewChannel reports an error for a channel nightcrier cannot preview:
+// one without a notifier (teams, email) or an unknown one.
+func checkPreviewChannel(channel string) error {
+	switch strings.ToLower(channel) {
+	case "slack", "discord", "mattermost":
+		return nil
+	case "teams", "email":
+		return fmt.Errorf("nightcrier has no %s notifier, so there is no %s notification to preview (supported: slack, discord, mattermost)", strings.ToLower(channel), strings.ToLower(channel))
+	}
+	return fmt.Errorf("unsupported notification channel %q (supported: slack, discord, mattermost)", channel)
+}
+
+// newChannelNotifier creates the notifier for one channel, configured as the
+// daemon configures it (language, Mattermost channel and sender overrides).
+func newChannelNotifier(cfg *config.Config, tuning *config.TuningConfig, channel, webhookURL string) (channelNotifier, error) {
+	if err := checkPreviewChannel(channel); err != nil {
+		return nil, err
+	}
+	transport := proxy.NewTransport(cfg.Proxy.SlackSettings())
+	switch strings.ToLower(channel) {
+	case "slack":
+		n := reporting.NewSlackNotifier(webhookURL, tuning)
+		n.SetTransport(transport)
+		n.SetLanguage(cfg.ReportLanguage)
+		return n, nil
+	case "discord":
+		n := reporting.NewDiscordNotifier(webhookURL, tuning)
+		n.SetTransport(transport)
+		n.SetLanguage(cfg.ReportLanguage)
+		return n, nil
+	case "mattermost":
+		n := reporting.NewMattermostNotifier(webhookURL, tuning)
+		n.Channel = cfg.MattermostChannel
+		n.Username = cfg.MattermostUsername
+		n.SetTransport(transport)
+		n.SetLanguage(cfg.ReportLanguage)
+		return n, nil
+	}
+	return nil, checkPreviewChannel(channel)
+}
+
+// configuredWebhook returns the webhook configured for a channel.
+func configuredWebhook(cfg *config.Config, channel string) string {
+	switch strings.ToLower(channel) {
+	case "slack":
+		return cfg.SlackWebhookURL
+	case "discord":
+		return cfg.DiscordWebhookURL
+	case "mattermost":
+		return cfg.MattermostWebhookURL
+	}
+	return ""
+}
+
+// previewSummary builds the notification summary of a stored incident the way the
+// daemon builds it when the investigation completes: the root cause and confidence
+// come from the stored report and the follow-up chain from the parent links.
+// Incidents recorded on serve-only clusters preview as recorded fault events.
+func previewSummary(ctx context.Context, store storage.StateStore, cfg *config.Config, inc *incident.Incident) (*reporting.IncidentSummary, error) {
+	summary := &reporting.IncidentSummary{
+		IncidentID: inc.Ref(),
+		Cluster:    inc.Cluster,
+		Namespace:  inc.Namespace,
+		Reason:     inc.FaultType,
+		Severity:   inc.Severity,
+		Status:     inc.Status,
+		ReportPath: filepath.Join(cfg.WorkspaceRoot, inc.Ref(), "output", "investigation.md"),
+	}
+	if inc.Resource != nil {
+		summary.Resource = fmt.Sprintf("%s/%s", inc.Resource.Kind, inc.Resource.Name)
+	}
+	if inc.StartedAt != nil && inc.CompletedAt != nil {
+		summary.Duration = inc.CompletedAt.Sub(*inc.StartedAt)
+	}
+
+	if inc.Status == incident.StatusPending && (&eventProcessor{cfg: cfg}).serveOnly(inc.Cluster) {
+		summary.RecordedOnly = true
+		summary.FaultContext = inc.Context
+		summary.ReportPath = ""
+		return summary, nil
+	}
+
+	report, err := store.GetTriageReport(ctx, inc.IncidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load incident report: %w", err)
+	}
+	if report != nil {
+		summary.RootCause, summary.Confidence = reporting.ExtractSummary(report.ReportMarkdown)
+	}
+	if inc.ParentIncidentID != "" {
+		if parent, err := store.GetIncident(ctx, inc.ParentIncidentID); err == nil && parent != nil {
+			summary.FollowUpOf = followUpChain(ctx, store, parent)
+		}
+	}
+	return summary, nil
+}
+
+// capturePayload renders a notification without sending it and returns the
+// indented JSON payload the webhook would have received.
+func capturePayload(notifier channelNotifier, summary *reporting.IncidentSummary) (string, error) {
+	capture := &previewTransport{}
+	notifier.SetTransport(capture)
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		return "", err
+	}
+	if capture.body == nil {
+		return "", fmt.Errorf("the %s notifier produced no payload", notifier.Name())
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, capture.body, "", "  "); err != nil {
+		return string(capture.body), nil
+	}
+	return indented.String(), nil
+}
+
+// previewTransport records the webhook request body and answers it locally.
+type previewTransport struct {
+	body []byte
+}
+
+func (t *previewTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	t.body = body
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("ok")),
+		Request:    req,
+	}, nil
+}
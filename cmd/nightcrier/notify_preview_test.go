@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+func TestNotifyPreview(t *testing.T) {
+	event := &events.FaultEvent{
+		Cluster:   "prod",
+		Resource:  &events.ResourceInfo{Kind: "Pod", Name: "web", Namespace: "default"},
+		FaultType: "OOMKilled",
+	}
+	parent := incident.NewFromEvent("inc-1", event)
+	parent.DisplayID = "NC-2024-0612-prod-0007"
+	inc := incident.NewFromEvent("inc-2", event)
+	inc.DisplayID = "NC-2024-0613-prod-0042"
+	inc.Status = incident.StatusResolved
+	inc.ParentIncidentID = "inc-1"
+	started := time.Now().Add(-90 * time.Second)
+	completed := time.Now()
+	inc.StartedAt, inc.CompletedAt = &started, &completed
+
+	store := &historyStore{
+		incidents: []*incident.Incident{inc, parent},
+		reports: map[string]*storage.TriageReport{
+			"inc-2": {ReportMarkdown: "## Root Cause\n\nMemory limit too low.\n\n**Confidence Level**: HIGH\n"},
+		},
+	}
+	cfg := &config.Config{WorkspaceRoot: "/var/lib/nightcrier"}
+	tuning := &config.TuningConfig{}
+	tuning.Reporting.RootCauseTruncationLength = 300
+
+	summary, err := previewSummary(context.Background(), store, cfg, inc)
+	if err != nil {
+		t.Fatalf("previewSummary() error = %v", err)
+	}
+	if summary.IncidentID != "NC-2024-0613-prod-0042" || summary.RootCause != "Memory limit too low." || summary.Confidence != "HIGH" {
+		t.Errorf("summary = %+v, want the display ID and the stored root cause", summary)
+	}
+	if len(summary.FollowUpOf) != 1 || summary.FollowUpOf[0] != "NC-2024-0612-prod-0007" {
+		t.Errorf("FollowUpOf = %v, want the parent incident", summary.FollowUpOf)
+	}
+
+	for _, channel := range []string{"slack", "discord", "mattermost"} {
+		notifier, err := newChannelNotifier(cfg, tuning, channel, previewWebhookURL)
+		if err != nil {
+			t.Fatalf("newChannelNotifier(%s) error = %v", channel, err)
+		}
+		payload, err := capturePayload(notifier, summary)
+		if err != nil {
+			t.Fatalf("capturePayload(%s) error = %v", channel, err)
+		}
+		if !json.Valid([]byte(payload)) || !strings.Contains(payload, "Memory limit too low.") || !strings.Contains(payload, "NC-2024-0613-prod-0042") {
+			t.Errorf("%s payload does not describe the incident:\n%s", channel, payload)
+		}
+	}
+
+	// Teams and email have no notifier to render with
+	for _, channel := range []string{"teams", "email"} {
+		_, err := newChannelNotifier(cfg, tuning, channel, previewWebhookURL)
+		if err == nil || !strings.Contains(err.Error(), "no "+channel+" notifier") {
+			t.Errorf("newChannelNotifier(%s) error = %v, want the missing notifier reported", channel, err)
+		}
+	}
+	if _, err := newChannelNotifier(cfg, tuning, "pager", previewWebhookURL); err == nil {
+		t.Error("newChannelNotifier() should reject an unknown channel")
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/labels"
+	"github.com/rbias/nightcrier/internal/ownership"
+	"github.com/rbias/nightcrier/internal/reporting"
+)
+
+// ownershipHTTPTimeout bounds each request to an ownership source
+const ownershipHTTPTimeout = 5 * time.Second
+
+// newOwnerResolver returns the resolver for the configured ownership sources, or
+// nil when none is configured.
+func newOwnerResolver(cfg *config.Config) *ownership.Resolver {
+	if !cfg.Ownership.Enabled() {
+		return nil
+	}
+	endpoints := make(map[string]string, len(cfg.Clusters))
+	for _, cl := range cfg.Clusters {
+		endpoints[cl.Name] = cl.MCP.Endpoint
+	}
+	return cfg.Ownership.Resolver(endpoints, &http.Client{Timeout: ownershipHTTPTimeout})
+}
+
+// ownerRoutes creates the notifiers of the configured owner notification routes.
+func ownerRoutes(cfg *config.Config, tuningStore *config.TuningStore) []reporting.OwnerRoute {
+	routes := make([]reporting.OwnerRoute, 0, len(cfg.Ownership.Routes))
+	for _, route := range cfg.Ownership.Routes {
+		notifiers := chatNotifiers(cfg, tuningStore, chatDestinations{
+			slackWebhookURL:      route.SlackWebhookURL,
+			discordWebhookURL:    route.DiscordWebhookURL,
+			mattermostWebhookURL: route.MattermostWebhookURL,
+			mattermostChannel:    route.MattermostChannel,
+		})
+		routes = append(routes, reporting.OwnerRoute{
+			Team:      route.Team,
+			Notifier:  notifiers,
+			Exclusive: route.Exclusive,
+		})
+	}
+	return routes
+}
+
+// resolveOwner looks up the team and service owning the incident's workload and
+// records them on the incident. The owner's "team" and "service" labels are added
+// unless cluster labels or label rules already set them. Lookup failures are
+// logged by the resolver and never fail the incident.
+func (p *eventProcessor) resolveOwner(ctx context.Context, inc *incident.Incident) {
+	if p.owners == nil {
+		return
+	}
+	log := incident.Logger(ctx)
+
+	query := ownership.Query{Cluster: inc.Cluster, Namespace: inc.Namespace}
+	if inc.Resource != nil {
+		query.Kind, query.Name = inc.Resource.Kind, inc.Resource.Name
+	}
+	owner := p.owners.Resolve(ctx, query)
+	if owner == nil {
+		log.Debug("no owner found for workload", "workload", query.Workload())
+		return
+	}
+	inc.Owner = owner
+
+	ownerLabels := make(map[string]string)
+	for key, value := range owner.Labels() {
+		if _, set := inc.Labels[key]; !set {
+			ownerLabels[key] = value
+		}
+	}
+	if err := labels.Validate(ownerLabels, nil); err != nil {
+		log.Warn("owner not recorded as incident labels", "error", err)
+	} else {
+		inc.AddLabels(ownerLabels, nil)
+	}
+	log.Info("resolved workload owner",
+		"team", owner.Team,
+		"service", owner.Service,
+		"source", owner.Source)
+}
+
+// setSummaryOwner adds the incident's owner to a notification, which also selects
+// the owning team's notification route.
+func setSummaryOwner(summary *reporting.IncidentSummary, inc *incident.Incident) {
+	if inc.Owner == nil {
+		return
+	}
+	summary.Owner = inc.Owner.String()
+	summary.OwnerTeam = inc.Owner.Team
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/pause"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Triage pause command flags
+	pauseFile    string
+	pauseCluster string
+	pauseReason  string
+	pauseActor   string
+)
+
+var triageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Pause and resume triage",
+	Long: `Pause and resume triage globally or per cluster without restarting nightcrier.
+
+While triage is paused, fault events are still recorded and deduped, but no agents
+are launched; their incidents stay pending. The pause is persisted in pause_file
+and picked up by the running nightcrier process on its next event. Triage can also
+be paused through the health server's /admin/triage API and the Slack app.`,
+}
+
+var triagePauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause triage on a cluster, or on every cluster",
+	Example: `  nightcrier triage pause --reason "LLM provider outage"
+  nightcrier triage pause --cluster prod-east --reason "cluster upgrade"`,
+	Args: cobra.NoArgs,
+	RunE: runTriagePause,
+}
+
+var triageResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume triage on a cluster, or lift the global pause",
+	Example: `  nightcrier triage resume
+  nightcrier triage resume --cluster prod-east`,
+	Args: cobra.NoArgs,
+	RunE: runTriageResume,
+}
+
+var triageStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show where triage is paused",
+	Args:  cobra.NoArgs,
+	RunE:  runTriageStatus,
+}
+
+func init() {
+	triageCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to config file (used to locate the pause file and clusters)")
+	triageCmd.PersistentFlags().StringVar(&pauseFile, "file", "", "Pause file (overrides pause_file from the config file)")
+
+	triagePauseCmd.Flags().StringVar(&pauseCluster, "cluster", "", "Cluster to pause (default: every cluster)")
+	triagePauseCmd.Flags().StringVar(&pauseReason, "reason", "", "Reason for the pause")
+	triagePauseCmd.Flags().StringVar(&pauseActor, "by", os.Getenv("USER"), "Who is pausing triage")
+
+	triageResumeCmd.Flags().StringVar(&pauseCluster, "cluster", "", "Cluster to resume (default: lift the global pause)")
+
+	triageCmd.AddCommand(triagePauseCmd, triageResumeCmd, triageStatusCmd)
+	rootCmd.AddCommand(triageCmd)
+}
+
+// openPauseSwitch opens the pause switch of the --file flag, or of the pause_file
+// and clusters of the configuration.
+func openPauseSwitch() (*pause.Switch, error) {
+	if pauseFile != "" {
+		return pause.Open(pauseFile, nil)
+	}
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration (use --file to bypass): %w", err)
+	}
+	return pause.Open(cfg.PauseFile, clusterNames(cfg))
+}
+
+// clusterNames returns the names of the configured clusters.
+func clusterNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Clusters))
+	for _, cl := range cfg.Clusters {
+		names = append(names, cl.Name)
+	}
+	return names
+}
+
+func runTriagePause(cmd *cobra.Command, args []string) error {
+	pauses, err := openPauseSwitch()
+	if err != nil {
+		return err
+	}
+	if _, err := pauses.Pause(pauseCluster, pause.Pause{PausedBy: pauseActor, Reason: pauseReason}); err != nil {
+		return fmt.Errorf("failed to pause triage: %w", err)
+	}
+	if pauseCluster == "" {
+		fmt.Println("Triage paused on every cluster")
+	} else {
+		fmt.Printf("Triage paused on cluster %s\n", pauseCluster)
+	}
+	return nil
+}
+
+func runTriageResume(cmd *cobra.Command, args []string) error {
+	pauses, err := openPauseSwitch()
+	if err != nil {
+		return err
+	}
+	state, err := pauses.Resume(pauseCluster)
+	if errors.Is(err, pause.ErrNotPaused) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resume triage: %w", err)
+	}
+	if pauseCluster == "" {
+		fmt.Println("Global triage pause lifted")
+	} else {
+		fmt.Printf("Triage resumed on cluster %s\n", pauseCluster)
+	}
+	if paused := state.PausedClusters(); len(paused) > 0 {
+		fmt.Printf("Still paused: %v\n", paused)
+	}
+	return nil
+}
+
+func runTriageStatus(cmd *cobra.Command, args []string) error {
+	pauses, err := openPauseSwitch()
+	if err != nil {
+		return err
+	}
+	state := pauses.State()
+	if state.Global == nil && len(state.Clusters) == 0 {
+		fmt.Println("Triage is running on every cluster")
+		return nil
+	}
+
+	fmt.Printf("%-24s %-20s %-16s %s\n", "SCOPE", "PAUSED AT (UTC)", "BY", "REASON")
+	printPause := func(scope string, p *pause.Pause) {
+		by, reason := p.PausedBy, p.Reason
+		if by == "" {
+			by = "-"
+		}
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Printf("%-24s %-20s %-16s %s\n", scope, p.PausedAt.UTC().Format(time.DateTime), by, reason)
+	}
+	if state.Global != nil {
+		printPause("(all clusters)", state.Global)
+	}
+	for _, name := range state.PausedClusters() {
+		printPause(name, state.Clusters[name])
+	}
+	return nil
+}
+
+// triagePaused returns the pause in effect for a cluster, or nil when its triage
+// runs. A suspected outage of the agent's LLM provider pauses every cluster.
+func (p *eventProcessor) triagePaused(clusterName string) *pause.Pause {
+	if p.pauses != nil {
+		if paused := p.pauses.Paused(clusterName); paused != nil {
+			return paused
+		}
+	}
+	return p.providerOutagePause()
+}
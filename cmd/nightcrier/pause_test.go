@@ -0,0 +1,43 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/pause"
+)
+
+func TestTriagePaused(t *testing.T) {
+	cfg := &config.Config{Clusters: []cluster.ClusterConfig{
+		{Name: "prod", Triage: cluster.TriageConfig{Enabled: true}},
+		{Name: "staging", Triage: cluster.TriageConfig{Enabled: true}},
+	}}
+	p := &eventProcessor{cfg: cfg}
+	if p.triagePaused("prod") != nil || !p.triageEnabled("prod") {
+		t.Fatal("triage should run without a pause switch")
+	}
+
+	pauses, err := pause.Open(filepath.Join(t.TempDir(), "triage-pause.json"), clusterNames(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.pauses = pauses
+	if _, err := pauses.Pause("staging", pause.Pause{Reason: "upgrade"}); err != nil {
+		t.Fatal(err)
+	}
+	if p.triagePaused("prod") != nil || !p.triageEnabled("prod") {
+		t.Error("prod should not be paused")
+	}
+	if p.triagePaused("staging") == nil || p.triageEnabled("staging") {
+		t.Error("staging should be paused, with its canary agent stage skipped")
+	}
+
+	if _, err := pauses.Pause("", pause.Pause{Reason: "LLM outage"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.triagePaused("prod"); got == nil || got.Reason != "LLM outage" {
+		t.Errorf("triagePaused(prod) = %+v, want the global pause", got)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/pause"
+	"github.com/rbias/nightcrier/internal/providerstatus"
+	"github.com/rbias/nightcrier/internal/reporting"
+)
+
+// providerStatusPausedBy marks the pauses of a suspected provider outage
+const providerStatusPausedBy = "provider-status"
+
+// startProviderStatus starts the provider status monitor. When an outage of a
+// provider is suspected, the circuit breaker is opened with the outage noted and
+// the system degraded alert is sent; the circuit closes with the next successful
+// investigation after the outage ended.
+func startProviderStatus(ctx context.Context, cfg *config.Config, circuitBreaker *reporting.CircuitBreaker, notifier reporting.Notifier) *providerstatus.Monitor {
+	failureThreshold := cfg.ProviderStatus.FailureThreshold
+	if failureThreshold < 0 {
+		failureThreshold = 0
+	}
+
+	monitor := providerstatus.New(providerstatus.Options{
+		StatusPages:      providerStatusPages(cfg.ProviderStatus.StatusPages),
+		Indicator:        cfg.ProviderStatus.Indicator,
+		FailureThreshold: failureThreshold,
+		FailureWindow:    cfg.ProviderStatus.FailureWindow(),
+		OnChange: func(provider string, outage *providerstatus.Outage) {
+			if outage == nil {
+				circuitBreaker.ClearOutage()
+				slog.Info("provider outage no longer suspected", "provider", provider)
+				return
+			}
+
+			slog.Warn("provider outage suspected",
+				"provider", provider,
+				"signal", outage.Signal,
+				"description", outage.Description)
+			circuitBreaker.Trip(outage.String())
+			if !circuitBreaker.ShouldAlert() {
+				return
+			}
+			if notifier == nil || !cfg.NotifyOnAgentFailure {
+				slog.Debug("skipping system degraded alert for suspected provider outage")
+				return
+			}
+			if err := notifier.SendSystemDegradedAlert(ctx, circuitBreaker.GetStats()); err != nil {
+				slog.Error("failed to send system degraded alert", "error", err)
+			}
+		},
+	})
+	go monitor.Run(ctx, cfg.ProviderStatus.Interval())
+	return monitor
+}
+
+// providerStatusPages returns the default status pages with the configured ones
+// applied; an empty URL removes a provider's status page.
+func providerStatusPages(configured map[string]string) map[string]string {
+	pages := make(map[string]string, len(providerstatus.DefaultStatusPages))
+	for provider, url := range providerstatus.DefaultStatusPages {
+		pages[provider] = url
+	}
+	for provider, url := range configured {
+		if url == "" {
+			delete(pages, provider)
+			continue
+		}
+		pages[provider] = url
+	}
+	return pages
+}
+
+// providerOutagePause returns a pause while an outage of the agent's LLM provider
+// is suspected, so no agents are launched into it, or nil.
+func (p *eventProcessor) providerOutagePause() *pause.Pause {
+	if p.providerStatus == nil || p.cfg.ProviderStatus.KeepLaunching {
+		return nil
+	}
+	outage := p.providerStatus.Outage(p.cfg.ActiveLLMProvider())
+	if outage == nil {
+		return nil
+	}
+	return &pause.Pause{PausedBy: providerStatusPausedBy, Reason: outage.String(), PausedAt: outage.Since}
+}
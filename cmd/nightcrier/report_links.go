@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rbias/nightcrier/internal/incident"
+)
+
+// shortenReportURL returns the short link of a stored report when a URL shortener
+// is configured. When the shortener fails, the raw URL is returned so that
+// notifications still link to the report.
+func (p *eventProcessor) shortenReportURL(ctx context.Context, reportURL string) string {
+	if p.shortener == nil || reportURL == "" {
+		return reportURL
+	}
+	log := incident.Logger(ctx)
+	short, err := p.shortener.Shorten(ctx, reportURL)
+	if err != nil {
+		log.Warn("failed to shorten report URL - using the raw URL", "error", err)
+		return reportURL
+	}
+	if short != reportURL {
+		log.Info("shortened report URL", "short_url", short)
+	}
+	return short
+}
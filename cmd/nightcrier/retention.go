@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Artifacts prune command flags
+	artifactsPruneDryRun bool
+)
+
+var artifactsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Expire artifacts that outlived their class's retention",
+	Long: `Run one artifact retention pass now: delete the reports, agent logs, and session
+archives that outlived the retention of their class (artifact_retention), in the
+local workspaces and in artifact storage. Incidents under a legal hold are skipped.`,
+	Example: `  nightcrier artifacts prune --dry-run`,
+	Args:    cobra.NoArgs,
+	RunE:    runArtifactsPrune,
+}
+
+var artifactsLifecyclePolicyCmd = &cobra.Command{
+	Use:   "lifecycle-policy",
+	Short: "Print an Azure Storage lifecycle policy expiring artifacts by class",
+	Long: `Print an Azure Storage lifecycle management policy that deletes the artifact
+container's blobs with the retention of their class (artifact_retention), so that
+Azure expires them even while nightcrier is not running. The rules match the
+artifact_class blob index tag, set on upload when artifact_retention.azure_index_tags
+is enabled; blobs uploaded before that are only expired by "artifacts prune" and
+the periodic retention.`,
+	Example: `  nightcrier artifacts lifecycle-policy > policy.json
+  az storage account management-policy create --account-name <account> \
+    --resource-group <group> --policy @policy.json`,
+	Args: cobra.NoArgs,
+	RunE: runArtifactsLifecyclePolicy,
+}
+
+func init() {
+	artifactsPruneCmd.Flags().BoolVar(&artifactsPruneDryRun, "dry-run", false, "Count the expired artifacts without deleting them")
+	artifactsCmd.AddCommand(artifactsPruneCmd, artifactsLifecyclePolicyCmd)
+}
+
+// artifactPruners returns the backends retention runs over: the local workspaces,
+// and the artifact storage backend when it is not the workspace root itself.
+func artifactPruners(cfg *config.Config, backend storage.Storage) map[string]storage.Pruner {
+	pruners := map[string]storage.Pruner{
+		"workspace": storage.NewFilesystemStorage(cfg.WorkspaceRoot),
+	}
+	if pruner, ok := backend.(storage.Pruner); ok {
+		if _, local := backend.(*storage.FilesystemStorage); !local {
+			pruners["storage"] = pruner
+		}
+	}
+	return pruners
+}
+
+// heldIncidents returns the references (display IDs and UUIDs) of the incidents
+// under an active legal hold. Without a state store there are no holds.
+func heldIncidents(ctx context.Context, store storage.StateStore) (map[string]bool, error) {
+	held := make(map[string]bool)
+	if store == nil {
+		return held, nil
+	}
+	holds, err := store.ListLegalHolds(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list legal holds: %w", err)
+	}
+	for _, hold := range holds {
+		held[hold.IncidentID] = true
+		if inc, err := store.GetIncident(ctx, hold.IncidentID); err == nil && inc != nil {
+			held[inc.Ref()] = true
+		}
+	}
+	return held, nil
+}
+
+// pruneArtifacts runs one retention pass over every backend and returns the
+// results by backend. A pass is abandoned when the legal holds cannot be read.
+func pruneArtifacts(ctx context.Context, cfg *config.Config, backend storage.Storage, store storage.StateStore, dryRun bool) (map[string]storage.PruneResult, error) {
+	held, err := heldIncidents(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	policy := storage.RetentionPolicy(cfg.ArtifactRetention.Policy())
+	now := time.Now()
+	results := make(map[string]storage.PruneResult)
+	for name, pruner := range artifactPruners(cfg, backend) {
+		result, err := pruner.PruneArtifacts(ctx, policy, now, func(id string) bool { return held[id] }, dryRun)
+		results[name] = result
+		if err != nil {
+			return results, fmt.Errorf("failed to prune %s artifacts: %w", name, err)
+		}
+	}
+	return results, nil
+}
+
+// runArtifactRetention expires artifacts every retention interval until ctx is
+// cancelled.
+func runArtifactRetention(ctx context.Context, cfg *config.Config, backend storage.Storage, store storage.StateStore) {
+	ticker := time.NewTicker(cfg.ArtifactRetention.Interval())
+	defer ticker.Stop()
+	for {
+		results, err := pruneArtifacts(ctx, cfg, backend, store, false)
+		if err != nil {
+			slog.Error("artifact retention failed", "error", err)
+		}
+		for name, result := range results {
+			if result.Total() > 0 {
+				slog.Info("expired artifacts",
+					"backend", name,
+					"report", result.Deleted[storage.ClassReport],
+					"logs", result.Deleted[storage.ClassLogs],
+					"session_archive", result.Deleted[storage.ClassSessionArchive],
+					"bytes", result.Bytes)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runArtifactsPrune(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	setupLogging("warn")
+
+	backend, err := storage.NewStorage(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize artifact storage backend: %w", err)
+	}
+	store, err := openStateStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		defer store.Close()
+	}
+
+	results, err := pruneArtifacts(ctx, cfg, backend, store, artifactsPruneDryRun)
+	verb := "Deleted"
+	if artifactsPruneDryRun {
+		verb = "Would delete"
+	}
+	for name, result := range results {
+		fmt.Printf("%s: %s %d artifacts (%d bytes): %d report, %d logs, %d session_archive\n",
+			name, verb, result.Total(), result.Bytes,
+			result.Deleted[storage.ClassReport], result.Deleted[storage.ClassLogs], result.Deleted[storage.ClassSessionArchive])
+	}
+	return err
+}
+
+func runArtifactsLifecyclePolicy(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if !cfg.IsAzureStorageEnabled() {
+		return fmt.Errorf("lifecycle policies apply to Azure Blob Storage, which is not configured")
+	}
+	policy, err := storage.AzureLifecyclePolicy(cfg.GetAzureContainer(), storage.RetentionPolicy(cfg.ArtifactRetention.Policy()))
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(policy))
+	if !cfg.ArtifactRetention.AzureIndexTags {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: artifact_retention.azure_index_tags is disabled; uploaded blobs carry no artifact_class tag for these rules to match")
+	}
+	return nil
+}
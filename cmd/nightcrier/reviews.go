@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbias/nightcrier/internal/health"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// maxReviewCommentLength bounds a review comment, in bytes
+const maxReviewCommentLength = 8 * 1024
+
+var (
+	// Incidents review command flags
+	reviewVerdict  string
+	reviewComment  string
+	reviewReviewer string
+
+	// Incidents reviews and accuracy command flags
+	reviewsFormat  string
+	accuracySince  time.Duration
+	accuracyFormat string
+)
+
+var incidentsReviewCmd = &cobra.Command{
+	Use:   "review <incident-id>",
+	Short: "Review a completed investigation",
+	Long: `Append a review to a completed investigation: a comment, a verdict on the
+accuracy of the report (accurate, partially_accurate, or inaccurate), or both.
+An accurate or partially accurate verdict resolves an incident awaiting review.
+
+Reviews are stored with the incident. Verdicts are aggregated per agent model and
+prompt version by "incidents accuracy"; only the latest verdict on an incident
+counts, so a reviewer can revise it. Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier incidents review prod-20240501-0007 --verdict accurate
+  nightcrier incidents review prod-20240501-0007 --verdict partially-accurate \
+    --comment "Found the OOM but missed the memory leak in the sidecar" --by alice`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIncidentsReview,
+}
+
+var incidentsReviewsCmd = &cobra.Command{
+	Use:   "reviews <incident-id>",
+	Short: "List the reviews of an investigation",
+	Long: `List the review comments and verdicts of an incident's investigation, oldest
+first. Requires a sqlite or postgres state store.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIncidentsReviews,
+}
+
+var incidentsAccuracyCmd = &cobra.Command{
+	Use:   "accuracy",
+	Short: "Show investigation accuracy per agent model and prompt version",
+	Long: `Show how reviewers judged the investigations of each agent model and prompt
+version, from the latest verdict on each incident reviewed in the period.
+
+Accuracy scores accurate verdicts 1, partially accurate 0.5, and inaccurate 0.
+The prompt version is a hash of the system prompt and additional prompt the agent
+ran with, so a prompt change shows as a new row. Investigations without a recorded
+agent execution (rule-based triage, cached reports) have no model or prompt
+version. Requires a sqlite or postgres state store.`,
+	Example: `  nightcrier incidents accuracy
+  nightcrier incidents accuracy --since 168h --format json`,
+	Args: cobra.NoArgs,
+	RunE: runIncidentsAccuracy,
+}
+
+func init() {
+	incidentsReviewCmd.Flags().StringVar(&reviewVerdict, "verdict", "", "Verdict on the report: accurate, partially_accurate, or inaccurate")
+	incidentsReviewCmd.Flags().StringVar(&reviewComment, "comment", "", "Review comment")
+	incidentsReviewCmd.Flags().StringVar(&reviewReviewer, "by", os.Getenv("USER"), "Who is reviewing the investigation")
+	incidentsReviewsCmd.Flags().StringVar(&reviewsFormat, "format", "table", "Output format: table or json")
+	incidentsAccuracyCmd.Flags().DurationVar(&accuracySince, "since", 30*24*time.Hour, "Aggregate the verdicts given in this period")
+	incidentsAccuracyCmd.Flags().StringVar(&accuracyFormat, "format", "table", "Output format: table or json")
+	incidentsCmd.AddCommand(incidentsReviewCmd, incidentsReviewsCmd, incidentsAccuracyCmd)
+}
+
+// addInvestigationReview validates a review of an incident's investigation and
+// records it. It returns nil and no error when the incident does not exist. Input
+// errors wrap health.ErrInvalidReview, so the review API answers them with 400.
+func addInvestigationReview(ctx context.Context, store storage.StateStore, id, reviewer, verdict, comment string) (*incident.Incident, *storage.InvestigationReview, error) {
+	reviewer = strings.TrimSpace(reviewer)
+	comment = strings.TrimSpace(comment)
+	if reviewer == "" {
+		return nil, nil, fmt.Errorf("%w: a reviewer is required", health.ErrInvalidReview)
+	}
+	if verdict == "" && comment == "" {
+		return nil, nil, fmt.Errorf("%w: a verdict or a comment is required", health.ErrInvalidReview)
+	}
+	if len(comment) > maxReviewCommentLength {
+		return nil, nil, fmt.Errorf("%w: the comment is longer than %d bytes", health.ErrInvalidReview, maxReviewCommentLength)
+	}
+	if verdict != "" {
+		parsed, err := storage.ParseVerdict(verdict)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", health.ErrInvalidReview, err)
+		}
+		verdict = parsed
+	}
+
+	inc, err := store.GetIncident(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to look up incident %s: %w", id, err)
+	}
+	if inc == nil {
+		return nil, nil, nil
+	}
+	if inc.CompletedAt == nil {
+		return nil, nil, fmt.Errorf("%w: incident %s has no completed investigation", health.ErrInvalidReview, inc.Ref())
+	}
+
+	review := &storage.InvestigationReview{
+		ReviewID:   uuid.New().String(),
+		IncidentID: inc.IncidentID,
+		Reviewer:   reviewer,
+		CreatedAt:  time.Now().UTC(),
+		Verdict:    verdict,
+		Comment:    comment,
+	}
+	if err := store.AddInvestigationReview(ctx, review); err != nil {
+		return nil, nil, err
+	}
+	// A positive verdict accepts findings held for review
+	if verdict == storage.VerdictAccurate || verdict == storage.VerdictPartiallyAccurate {
+		if _, err := resolveReviewedIncident(ctx, store, inc); err != nil {
+			return nil, nil, err
+		}
+	}
+	return inc, review, nil
+}
+
+// investigationReviews serves the investigation review API from the state store.
+type investigationReviews struct {
+	store storage.StateStore
+}
+
+func (r investigationReviews) GetReviews(ctx context.Context, id string) (interface{}, error) {
+	inc, err := r.store.GetIncident(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up incident %s: %w", id, err)
+	}
+	if inc == nil {
+		return nil, fmt.Errorf("%w: %s", health.ErrIncidentNotFound, id)
+	}
+	reviews, err := r.store.InvestigationReviews(ctx, inc.IncidentID)
+	if err != nil {
+		return nil, err
+	}
+	if reviews == nil {
+		reviews = []*storage.InvestigationReview{}
+	}
+	return reviews, nil
+}
+
+func (r investigationReviews) AddReview(ctx context.Context, id string, req health.ReviewRequest) (interface{}, error) {
+	inc, review, err := addInvestigationReview(ctx, r.store, id, req.Reviewer, req.Verdict, req.Comment)
+	if err != nil {
+		return nil, err
+	}
+	if inc == nil {
+		return nil, fmt.Errorf("%w: %s", health.ErrIncidentNotFound, id)
+	}
+	return review, nil
+}
+
+func (r investigationReviews) GetReviewAccuracy(ctx context.Context, since time.Time) (interface{}, error) {
+	stats, err := r.store.ReviewAccuracy(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		stats = []*storage.ReviewAccuracy{}
+	}
+	return stats, nil
+}
+
+func runIncidentsReview(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	inc, review, err := addInvestigationReview(ctx, store, args[0], reviewReviewer, reviewVerdict, reviewComment)
+	if err != nil {
+		return err
+	}
+	if inc == nil {
+		return fmt.Errorf("incident %s not found", args[0])
+	}
+	fmt.Printf("Reviewed incident %s by %s", inc.Ref(), review.Reviewer)
+	if review.Verdict != "" {
+		fmt.Printf(": %s", review.Verdict)
+	}
+	fmt.Println()
+	return nil
+}
+
+func runIncidentsReviews(cmd *cobra.Command, args []string) error {
+	if reviewsFormat != "table" && reviewsFormat != "json" {
+		return fmt.Errorf("unknown format %q: must be table or json", reviewsFormat)
+	}
+
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	inc, err := resolveIncident(ctx, store, args[0])
+	if err != nil {
+		return err
+	}
+	reviews, err := store.InvestigationReviews(ctx, inc.IncidentID)
+	if err != nil {
+		return err
+	}
+
+	if reviewsFormat == "json" {
+		if reviews == nil {
+			reviews = []*storage.InvestigationReview{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reviews)
+	}
+
+	if len(reviews) == 0 {
+		fmt.Printf("No reviews of incident %s\n", inc.Ref())
+		return nil
+	}
+	fmt.Printf("%-20s %-16s %-20s %s\n", "REVIEWED (UTC)", "REVIEWER", "VERDICT", "COMMENT")
+	for _, review := range reviews {
+		fmt.Printf("%-20s %-16s %-20s %s\n",
+			review.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+			truncateString(review.Reviewer, 16),
+			orDash(review.Verdict),
+			orDash(review.Comment))
+	}
+	return nil
+}
+
+func runIncidentsAccuracy(cmd *cobra.Command, args []string) error {
+	if accuracyFormat != "table" && accuracyFormat != "json" {
+		return fmt.Errorf("unknown format %q: must be table or json", accuracyFormat)
+	}
+	if accuracySince <= 0 {
+		return fmt.Errorf("--since must be positive, got %s", accuracySince)
+	}
+
+	ctx := context.Background()
+	store, err := openIncidentStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	stats, err := store.ReviewAccuracy(ctx, time.Now().Add(-accuracySince))
+	if err != nil {
+		return err
+	}
+
+	if accuracyFormat == "json" {
+		if stats == nil {
+			stats = []*storage.ReviewAccuracy{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	if len(stats) == 0 {
+		fmt.Printf("No verdicts given in the last %s\n", accuracySince)
+		return nil
+	}
+	fmt.Printf("%-28s %-20s %8s %8s %8s %10s %8s\n", "MODEL", "PROMPT", "REVIEWED", "ACCURATE", "PARTIAL", "INACCURATE", "ACCURACY")
+	for _, st := range stats {
+		fmt.Printf("%-28s %-20s %8d %8d %8d %10d %7.0f%%\n",
+			truncateString(orDash(st.AgentModel), 28),
+			orDash(st.PromptVersion),
+			st.Reviewed, st.Accurate, st.PartiallyAccurate, st.Inaccurate,
+			st.Accuracy*100)
+	}
+	return nil
+}
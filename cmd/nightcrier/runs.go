@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// runStopTimeout bounds recording the shutdown record, which happens after the
+// main context is already cancelled
+const runStopTimeout = 5 * time.Second
+
+var (
+	// Runs command flags
+	runsLimit int
+)
+
+var runsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "Show recent nightcrier starts and stops",
+	Long: `Show the run records kept in the SQL state store.
+
+Each nightcrier process records its version, configuration hash, monitored clusters,
+and storage modes when it starts, and the time and reason when it stops. Comparing
+consecutive runs shows when nightcrier restarted and whether its version or
+configuration changed in between. Requires a sqlite or postgres state store.`,
+	RunE: runRuns,
+}
+
+func init() {
+	runsCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to config file (used to locate the state store)")
+	runsCmd.Flags().IntVar(&runsLimit, "limit", 20, "Number of runs to show (0 for all)")
+
+	rootCmd.AddCommand(runsCmd)
+}
+
+// newRunRecord describes the current process for the state store.
+func newRunRecord(cfg *config.Config) (*storage.RunRecord, error) {
+	configHash, err := cfg.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]string, 0, len(cfg.Clusters))
+	for _, cl := range cfg.Clusters {
+		clusters = append(clusters, cl.Name)
+	}
+
+	artifactStorage := "local_filesystem"
+	if cfg.IsAzureStorageEnabled() {
+		artifactStorage = "azure_blob"
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &storage.RunRecord{
+		RunID:           uuid.New().String(),
+		StartedAt:       time.Now().UTC(),
+		Version:         Version,
+		GitCommit:       GitCommit,
+		ConfigHash:      configHash,
+		ConfigSnapshot:  cfg.Snapshot(),
+		Clusters:        clusters,
+		ArtifactStorage: artifactStorage,
+		StateStorage:    cfg.GetStateStorageType(),
+		Hostname:        hostname,
+	}, nil
+}
+
+// logConfigChanges logs how the current run differs from the previous one: the
+// version, and which settings changed (with secrets redacted), so behavior shifts
+// after a restart can be traced to the settings that changed.
+func logConfigChanges(previous, current *storage.RunRecord) {
+	if previous == nil {
+		return
+	}
+	log := slog.With("previous_run_id", previous.RunID, "previous_started_at", previous.StartedAt)
+
+	if previous.Version != current.Version || previous.GitCommit != current.GitCommit {
+		log.Info("nightcrier version changed since previous run",
+			"old_version", previous.Version,
+			"new_version", current.Version,
+			"old_git_commit", previous.GitCommit,
+			"new_git_commit", current.GitCommit)
+	}
+
+	if previous.ConfigHash == current.ConfigHash {
+		log.Info("configuration unchanged since previous run")
+		return
+	}
+	if previous.ConfigSnapshot == nil {
+		log.Info("configuration changed since previous run (no snapshot recorded to compare against)",
+			"old_config_hash", previous.ConfigHash,
+			"new_config_hash", current.ConfigHash)
+		return
+	}
+
+	changes := config.DiffSnapshots(previous.ConfigSnapshot, current.ConfigSnapshot)
+	if len(changes) == 0 {
+		log.Info("configuration changed since previous run (only secret values differ)",
+			"old_config_hash", previous.ConfigHash,
+			"new_config_hash", current.ConfigHash)
+		return
+	}
+	log.Info("configuration changed since previous run",
+		"changed_settings", len(changes),
+		"old_config_hash", previous.ConfigHash,
+		"new_config_hash", current.ConfigHash)
+	for _, change := range changes {
+		log.Info("config setting changed", "key", change.Key, "old", change.Old, "new", change.New)
+	}
+}
+
+// recordRunStop records the shutdown of a run. A nil runErr is a clean shutdown.
+func recordRunStop(store storage.StateStore, runID string, runErr error) {
+	reason := "shutdown"
+	if runErr != nil {
+		reason = runErr.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), runStopTimeout)
+	defer cancel()
+	if err := store.RecordRunStop(ctx, runID, time.Now().UTC(), reason); err != nil {
+		slog.Warn("failed to record run shutdown", "run_id", runID, "error", err)
+	}
+}
+
+func runRuns(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadWithConfigFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	setupLogging("warn")
+
+	ctx := context.Background()
+	store, err := openStateStore(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("run records require a sqlite or postgres state store (state_storage.type is %q)", cfg.GetStateStorageType())
+	}
+	defer store.Close()
+
+	runs, err := store.ListRuns(ctx, runsLimit)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Println("No runs recorded")
+		return nil
+	}
+
+	fmt.Printf("%-20s %-20s %-12s %-10s %-30s %s\n", "STARTED (UTC)", "STOPPED (UTC)", "VERSION", "CONFIG", "EXIT REASON", "CLUSTERS")
+	for i, run := range runs {
+		stopped := "-"
+		if run.StoppedAt != nil {
+			stopped = run.StoppedAt.UTC().Format("2006-01-02 15:04:05")
+		} else if i > 0 {
+			// A later run started without this one recording its shutdown
+			stopped = "(no record)"
+		}
+
+		// Mark the configuration hash when it differs from the previous run
+		configHash := run.ConfigHash
+		if len(configHash) > 8 {
+			configHash = configHash[:8]
+		}
+		if i+1 < len(runs) && runs[i+1].ConfigHash != run.ConfigHash {
+			configHash += " *"
+		}
+
+		fmt.Printf("%-20s %-20s %-12s %-10s %-30s %s\n",
+			run.StartedAt.UTC().Format("2006-01-02 15:04:05"),
+			stopped,
+			truncateString(run.Version, 12),
+			configHash,
+			truncateString(run.ExitReason, 30),
+			strings.Join(run.Clusters, ","))
+	}
+	fmt.Println("\n* configuration changed since the previous run")
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/incident"
+)
+
+const (
+	// samplingLabel marks incidents of sampled faults "investigated" or "skipped",
+	// so "nightcrier incidents --label sampling=skipped" lists the occurrences that
+	// were only recorded
+	samplingLabel = "sampling"
+	// samplingAnnotation records the occurrence and the sampling rate
+	samplingAnnotation = "sampling-occurrence"
+)
+
+// sampleFault applies the sampling rules to a fault and labels its incident with
+// the decision. It returns true when the occurrence is only recorded, not
+// investigated.
+func (p *eventProcessor) sampleFault(ctx context.Context, inc *incident.Incident, event *events.FaultEvent) bool {
+	decision := p.sampler.Sample(events.FaultSignature(inc.Cluster, event), inc.FaultType, inc.Namespace)
+	if !decision.Sampled {
+		return false
+	}
+
+	value := "investigated"
+	if !decision.Investigate {
+		value = "skipped"
+	}
+	if inc.Labels == nil {
+		inc.Labels = make(map[string]string)
+	}
+	if inc.Annotations == nil {
+		inc.Annotations = make(map[string]string)
+	}
+	inc.Labels[samplingLabel] = value
+	inc.Annotations[samplingAnnotation] = fmt.Sprintf("occurrence %d, investigating 1 in %d", decision.Occurrence, decision.Rate)
+
+	incident.Logger(ctx).Debug("sampled fault",
+		"sampling", value,
+		"occurrence", decision.Occurrence,
+		"sampling_rate", decision.Rate)
+	return !decision.Investigate
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/searchindex"
+)
+
+// exportToSearchIndex indexes the completed incident, with the findings and the text
+// of its report at reportPath (empty when it has none), into OpenSearch or
+// Elasticsearch. Failures are logged; they never fail the investigation.
+func (p *eventProcessor) exportToSearchIndex(ctx context.Context, inc *incident.Incident, reportPath, rootCause, confidence, reportURL string) {
+	log := incident.Logger(ctx)
+
+	doc := searchindex.Document{
+		IncidentID:       inc.IncidentID,
+		DisplayID:        inc.DisplayID,
+		ParentIncidentID: inc.ParentIncidentID,
+		FaultSignature:   inc.FaultSignature,
+		Source:           inc.Source,
+		Cluster:          inc.Cluster,
+		Namespace:        inc.Namespace,
+		FaultType:        inc.FaultType,
+		Severity:         inc.Severity,
+		Status:           inc.Status,
+		FailureClass:     inc.FailureClass,
+		FallbackTriage:   inc.FallbackTriage,
+		CachedFrom:       inc.CachedFrom,
+		AgentProfile:     inc.AgentProfile,
+		Labels:           inc.Labels,
+		CreatedAt:        inc.CreatedAt,
+		StartedAt:        inc.StartedAt,
+		CompletedAt:      inc.CompletedAt,
+		RootCause:        rootCause,
+		Confidence:       confidence,
+		ReportURL:        reportURL,
+	}
+	if inc.Resource != nil {
+		doc.ResourceKind = inc.Resource.Kind
+		doc.ResourceName = inc.Resource.Name
+	}
+	if inc.Agent != nil {
+		doc.AgentCLI = inc.Agent.CLI
+	}
+	if inc.Owner != nil {
+		doc.OwnerTeam = inc.Owner.Team
+		doc.OwnerService = inc.Owner.Service
+	}
+	if inc.StartedAt != nil && inc.CompletedAt != nil {
+		doc.DurationSeconds = inc.CompletedAt.Sub(*inc.StartedAt).Seconds()
+	}
+	if inc.Verification != nil {
+		doc.ClaimsVerified = inc.Verification.Verified
+		doc.ClaimsContradicted = inc.Verification.Contradicted
+		doc.ClaimsUnverified = inc.Verification.Unverified
+	}
+
+	if reportPath != "" && !p.cfg.SearchExport.ExcludeReport {
+		report, err := os.ReadFile(reportPath)
+		if err != nil {
+			log.Warn("failed to read investigation report for search index", "error", err)
+		} else {
+			doc.Report = string(report)
+		}
+	}
+
+	if err := p.searchIndex.Index(ctx, doc); err != nil {
+		log.Error("failed to index incident for search", "error", err)
+		return
+	}
+	log.Debug("incident indexed for search", "index_prefix", p.cfg.SearchExport.IndexPrefix)
+}
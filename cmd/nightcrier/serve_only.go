@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/reporting"
+)
+
+// serveOnly reports whether a cluster only records its fault events, without
+// launching agents (cluster serve_only setting).
+func (p *eventProcessor) serveOnly(clusterName string) bool {
+	for _, cl := range p.cfg.Clusters {
+		if cl.Name == clusterName {
+			return cl.ServeOnly
+		}
+	}
+	return false
+}
+
+// recordServeOnlyEvent handles a fault event from a serve-only cluster. The
+// incident has already been recorded in the state store, where it stays pending;
+// a notification with the raw fault is sent instead of an investigation report.
+// No workspace is created and the budget, agent slots, and circuit breaker are
+// not touched.
+func (p *eventProcessor) recordServeOnlyEvent(ctx context.Context, inc *incident.Incident, event *events.FaultEvent) error {
+	log := incident.Logger(ctx)
+	log.Info("serve-only cluster - recorded fault event without investigation")
+
+	if p.notifier != nil {
+		summary := &reporting.IncidentSummary{
+			IncidentID:   inc.Ref(),
+			Cluster:      inc.Cluster,
+			Namespace:    inc.Namespace,
+			Resource:     fmt.Sprintf("%s/%s", inc.Resource.Kind, inc.Resource.Name),
+			Reason:       inc.FaultType,
+			Severity:     inc.Severity,
+			Labels:       inc.Labels,
+			Status:       inc.Status,
+			RecordedOnly: true,
+			FaultContext: event.GetContext(),
+		}
+		setSummaryOwner(summary, inc)
+		p.sendNotification(ctx, summary)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/reporting"
+)
+
+// summaryRecorder records incident notifications; other notifications are not expected.
+type summaryRecorder struct {
+	reporting.Notifier
+	summaries []*reporting.IncidentSummary
+}
+
+func (r *summaryRecorder) Name() string { return "recorder" }
+
+func (r *summaryRecorder) SendIncidentNotification(summary *reporting.IncidentSummary) error {
+	r.summaries = append(r.summaries, summary)
+	return nil
+}
+
+func TestServeOnly(t *testing.T) {
+	rec := &summaryRecorder{}
+	p := &eventProcessor{notifier: rec, cfg: &config.Config{Clusters: []cluster.ClusterConfig{
+		{Name: "prod", Triage: cluster.TriageConfig{Enabled: true}},
+		{Name: "staging", Triage: cluster.TriageConfig{Enabled: true}, ServeOnly: true},
+	}}}
+
+	if p.serveOnly("prod") || !p.serveOnly("staging") || p.serveOnly("unknown") {
+		t.Errorf("serveOnly() does not follow the cluster serve_only settings")
+	}
+	if !p.triageEnabled("prod") || p.triageEnabled("staging") {
+		t.Errorf("triageEnabled() must be false for serve-only clusters")
+	}
+
+	event := &events.FaultEvent{
+		Cluster:   "staging",
+		Resource:  &events.ResourceInfo{Kind: "Pod", Name: "web", Namespace: "default"},
+		FaultType: "CrashLoopBackOff",
+		Severity:  "critical",
+		Context:   "Back-off restarting failed container",
+	}
+	inc := incident.NewFromEvent("incident-1", event)
+	if err := p.recordServeOnlyEvent(context.Background(), inc, event); err != nil {
+		t.Fatalf("recordServeOnlyEvent() error = %v", err)
+	}
+
+	if len(rec.summaries) != 1 {
+		t.Fatalf("sent %d notifications, want 1", len(rec.summaries))
+	}
+	got := rec.summaries[0]
+	if !got.RecordedOnly || got.FaultContext != event.Context || got.Resource != "Pod/web" {
+		t.Errorf("summary = %+v, want a recorded-only notification of the raw event", got)
+	}
+}
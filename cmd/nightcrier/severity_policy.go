@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/severitypolicy"
+)
+
+// newSeverityCollectors returns the cluster signal collector of each cluster with
+// kubeconfig triage, or nil when no severity rule matches on cluster signals.
+func newSeverityCollectors(cfg *config.Config) map[string]*severitypolicy.Collector {
+	if !cfg.SeverityPolicy.Policy().NeedsCluster() {
+		return nil
+	}
+	collectors := make(map[string]*severitypolicy.Collector)
+	for _, cl := range cfg.Clusters {
+		if cl.Triage.Enabled && cl.Triage.Kubeconfig != "" {
+			collectors[cl.Name] = severitypolicy.NewCollector(cl.Triage.Kubeconfig)
+		}
+	}
+	return collectors
+}
+
+// applySeverityPolicy replaces the incident's upstream severity with the severity
+// computed by the first matching severity rule, and records the rule and the
+// upstream severity on the incident. Cluster signals that cannot be read are
+// logged; rules on them then do not match.
+func (p *eventProcessor) applySeverityPolicy(ctx context.Context, inc *incident.Incident) {
+	policy := p.cfg.SeverityPolicy.Policy()
+	if len(policy.Rules) == 0 {
+		return
+	}
+	log := slog.With("incident_id", inc.IncidentID, "cluster", inc.Cluster)
+
+	signals := severitypolicy.Signals{
+		FaultType: inc.FaultType,
+		Namespace: inc.Namespace,
+		Severity:  inc.Severity,
+	}
+	if inc.Resource != nil {
+		signals.Kind = inc.Resource.Kind
+	}
+	if collector := p.severityCollectors[inc.Cluster]; collector != nil && inc.Resource != nil {
+		collectCtx, cancel := context.WithTimeout(ctx, p.cfg.SeverityPolicy.Timeout())
+		cluster, err := collector.Collect(collectCtx, inc.Namespace, inc.Resource.Kind, inc.Resource.Name)
+		cancel()
+		if err != nil {
+			log.Warn("severity policy evaluated without some cluster signals", "error", err)
+		}
+		signals.Cluster = cluster
+	}
+
+	decision, ok := policy.Evaluate(signals)
+	if !ok {
+		return
+	}
+	inc.UpstreamSeverity = inc.Severity
+	inc.SeverityRule = decision.Rule
+	inc.Severity = decision.Severity
+	log.Info("severity set by severity policy",
+		"severity_rule", decision.Rule,
+		"severity", decision.Severity,
+		"upstream_severity", inc.UpstreamSeverity)
+}
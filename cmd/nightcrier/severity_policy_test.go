@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+)
+
+func TestApplySeverityPolicy(t *testing.T) {
+	cfg := &config.Config{SeverityPolicy: config.SeverityPolicyConfig{Rules: []config.SeverityRuleConfig{
+		{Name: "evictions", FaultTypes: []string{"Evicted"}, Severity: "WARNING"},
+		// Never matches: the cluster has no triage kubeconfig to read PDBs with
+		{Name: "prod-pdb", PDB: "present", Severity: "CRITICAL"},
+	}}}
+	if err := cfg.SeverityPolicy.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	p := &eventProcessor{cfg: cfg, severityCollectors: newSeverityCollectors(cfg)}
+
+	evicted := &incident.Incident{IncidentID: "a", FaultType: "Evicted", Severity: "CRITICAL", Resource: &incident.ResourceInfo{Kind: "Pod", Name: "api"}}
+	p.applySeverityPolicy(context.Background(), evicted)
+	if evicted.Severity != "WARNING" || evicted.UpstreamSeverity != "CRITICAL" || evicted.SeverityRule != "evictions" {
+		t.Errorf("evicted incident = severity %q, upstream %q, rule %q", evicted.Severity, evicted.UpstreamSeverity, evicted.SeverityRule)
+	}
+
+	crash := &incident.Incident{IncidentID: "b", FaultType: "CrashLoopBackOff", Severity: "ERROR", Resource: &incident.ResourceInfo{Kind: "Pod", Name: "api"}}
+	p.applySeverityPolicy(context.Background(), crash)
+	if crash.Severity != "ERROR" || crash.UpstreamSeverity != "" || crash.SeverityRule != "" {
+		t.Errorf("unmatched incident = severity %q, upstream %q, rule %q", crash.Severity, crash.UpstreamSeverity, crash.SeverityRule)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/fleet"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// newSharedLimits returns the fleet-wide limit state kept in the state store, or
+// nil when shared limits are disabled or there is no SQL state store.
+func newSharedLimits(cfg *config.Config, stateStore storage.StateStore) *fleet.State {
+	if !cfg.SharedLimits.Enabled || stateStore == nil {
+		return nil
+	}
+	return fleet.NewState(sharedStateBackend{stateStore})
+}
+
+// sharedStateBackend stores fleet-wide limit state in the SQL state store.
+type sharedStateBackend struct {
+	storage.StateStore
+}
+
+// GetSharedState implements fleet.Backend.
+func (b sharedStateBackend) GetSharedState(ctx context.Context, key string) (*fleet.Entry, error) {
+	state, err := b.StateStore.GetSharedState(ctx, key)
+	if err != nil || state == nil {
+		return nil, err
+	}
+	return &fleet.Entry{Value: state.Value, Version: state.Version, ExpiresAt: state.ExpiresAt}, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// silenceAnnotation records the namespace silence an incident was suppressed by
+const silenceAnnotation = "silence-id"
+
+// silencedFault is a fault event in a silenced namespace.
+type silencedFault struct {
+	clusterName string
+	event       *events.FaultEvent
+	silence     *storage.Silence
+}
+
+// matchSilence returns the namespace silence that covers a fault event, or nil
+// when its namespace is not silenced.
+func (p *eventProcessor) matchSilence(clusterName string, event *events.FaultEvent) *storage.Silence {
+	if p.silences == nil {
+		return nil
+	}
+	return p.silences.Match(clusterName, event.GetNamespace())
+}
+
+// recordSilencedFault records a fault in a silenced namespace as a suppressed
+// incident annotated with the silence ID. A silenced namespace can be noisy, so
+// recording is kept cheap: the incident gets no display ID, owner, or severity
+// policy lookups, and is only written to the state store.
+func (p *eventProcessor) recordSilencedFault(ctx context.Context, f silencedFault) {
+	inc := incident.NewFromEvent(uuid.New().String(), f.event)
+	inc.Cluster = f.clusterName
+	p.attachConfiguredLabels(inc)
+	if inc.Annotations == nil {
+		inc.Annotations = make(map[string]string)
+	}
+	inc.Annotations[silenceAnnotation] = f.silence.SilenceID
+
+	ctx = incident.WithContext(ctx, incident.NewIncidentContext(inc))
+	log := incident.Logger(ctx)
+	setStatus(ctx, inc, incident.StatusSuppressed)
+	if p.stateStore != nil {
+		if err := p.stateStore.CreateIncident(ctx, inc, f.event); err != nil {
+			log.Error("failed to create incident in state store", "error", err)
+		}
+	}
+	log.Info("suppressed fault in silenced namespace - recorded without investigation",
+		"silence_id", f.silence.SilenceID,
+		"silence_expires_at", f.silence.ExpiresAt)
+}
+
+// silencedRecorder records silenced faults in a goroutine of its own, so a noisy
+// silenced namespace never holds up the event loop. Faults arriving while its
+// queue is full are only logged.
+type silencedRecorder struct {
+	faults chan silencedFault
+}
+
+// newSilencedRecorder starts recording silenced faults, queueing up to queueSize,
+// until ctx is cancelled.
+func newSilencedRecorder(ctx context.Context, p *eventProcessor, queueSize int) *silencedRecorder {
+	r := &silencedRecorder{faults: make(chan silencedFault, queueSize)}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case f := <-r.faults:
+				p.recordSilencedFault(ctx, f)
+			}
+		}
+	}()
+	return r
+}
+
+// Record queues a silenced fault to be recorded, without blocking.
+func (r *silencedRecorder) Record(f silencedFault) {
+	select {
+	case r.faults <- f:
+	default:
+		slog.Warn("silenced fault not recorded, too many waiting",
+			"cluster", f.clusterName,
+			"fault_id", f.event.FaultID,
+			"silence_id", f.silence.SilenceID)
+	}
+}
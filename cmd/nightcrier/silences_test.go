@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/agent"
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/silence"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// silenceStore holds silences and records created incidents; other state store
+// methods are not expected.
+type silenceStore struct {
+	storage.StateStore
+	silences []*storage.Silence
+
+	mu      sync.Mutex
+	created []*incident.Incident
+}
+
+func (s *silenceStore) ListSilences(ctx context.Context, activeAt time.Time) ([]*storage.Silence, error) {
+	return s.silences, nil
+}
+
+func (s *silenceStore) CreateIncident(ctx context.Context, inc *incident.Incident, event *events.FaultEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.created = append(s.created, inc)
+	return nil
+}
+
+func (s *silenceStore) createdCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.created)
+}
+
+// newSilencedProcessor returns a processor with namespace "shop" silenced on every
+// cluster.
+func newSilencedProcessor(t *testing.T) (*eventProcessor, *silenceStore) {
+	t.Helper()
+	store := &silenceStore{silences: []*storage.Silence{{
+		SilenceID: "sil-1",
+		Namespace: "shop",
+		CreatedAt: time.Now().Add(-time.Minute),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}}}
+	silences := silence.NewRegistry(store)
+	if err := silences.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return &eventProcessor{
+		executors:  map[string]*agent.Executor{"prod": nil},
+		stateStore: store,
+		silences:   silences,
+		cfg: &config.Config{Clusters: []cluster.ClusterConfig{
+			{Name: "prod", Triage: cluster.TriageConfig{Enabled: true}},
+		}},
+	}, store
+}
+
+func TestProcessEvent_SilencedNamespace(t *testing.T) {
+	p, store := newSilencedProcessor(t)
+
+	// The permissions are never looked at: the agent run is skipped before triage
+	event := &events.FaultEvent{
+		Resource:  &events.ResourceInfo{Kind: "Pod", Name: "checkout-1", Namespace: "shop"},
+		FaultType: "CrashLoopBackOff",
+	}
+	if err := p.processEvent(context.Background(), "prod", event, nil); err != nil {
+		t.Fatalf("processEvent() error = %v", err)
+	}
+
+	if len(store.created) != 1 {
+		t.Fatalf("created %d incidents, want the silenced fault recorded", len(store.created))
+	}
+	inc := store.created[0]
+	if inc.Status != incident.StatusSuppressed || inc.Annotations[silenceAnnotation] != "sil-1" {
+		t.Errorf("incident status %q, annotations %v; want suppressed by sil-1", inc.Status, inc.Annotations)
+	}
+}
+
+func TestSilencedRecorder(t *testing.T) {
+	p, store := newSilencedProcessor(t)
+	event := &events.FaultEvent{
+		Resource:  &events.ResourceInfo{Kind: "Pod", Name: "checkout-1", Namespace: "shop"},
+		FaultType: "CrashLoopBackOff",
+	}
+	fault := silencedFault{clusterName: "prod", event: event, silence: p.matchSilence("prod", event)}
+	if fault.silence == nil {
+		t.Fatal("matchSilence() = nil, want the shop silence")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	newSilencedRecorder(ctx, p, 1).Record(fault)
+	deadline := time.Now().Add(5 * time.Second)
+	for store.createdCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if store.createdCount() != 1 {
+		t.Fatalf("created %d incidents, want the silenced fault recorded", store.createdCount())
+	}
+
+	// A full queue never blocks the event loop
+	stopped, stop := context.WithCancel(context.Background())
+	stop()
+	stalled := newSilencedRecorder(stopped, p, 1)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			stalled.Record(fault)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Record() blocked on a full queue")
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/reporting"
+	"github.com/rbias/nightcrier/internal/storage/sqlite"
+)
+
+// bytesPerMB converts the MB thresholds of sqlite_maintenance to bytes
+const bytesPerMB = 1 << 20
+
+// sqliteMaintenance is the outcome of one maintenance run.
+type sqliteMaintenance struct {
+	Before     *sqlite.Stats
+	After      *sqlite.Stats
+	Checkpoint *sqlite.CheckpointResult
+	FreedPages int64
+}
+
+// maintainSQLite checkpoints and truncates the WAL, frees up to vacuumPages
+// unused pages, and refreshes the query planner statistics.
+func maintainSQLite(ctx context.Context, store *sqlite.Store, vacuumPages int) (*sqliteMaintenance, error) {
+	before, err := store.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := store.Checkpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	freed, err := store.IncrementalVacuum(ctx, vacuumPages)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Optimize(ctx); err != nil {
+		return nil, err
+	}
+	after, err := store.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteMaintenance{Before: before, After: after, Checkpoint: checkpoint, FreedPages: freed}, nil
+}
+
+// sqliteSizeAlerts returns the size thresholds of cfg the database exceeds, keyed
+// by threshold.
+func sqliteSizeAlerts(cfg config.SQLiteMaintenanceConfig, stats *sqlite.Stats) map[string]string {
+	alerts := make(map[string]string)
+	if cfg.AlertSizeMB > 0 && stats.DatabaseBytes > int64(cfg.AlertSizeMB)*bytesPerMB {
+		alerts["size"] = fmt.Sprintf("The SQLite state store is %s, above the %dMB alert threshold (%s of it unused). Run \"nightcrier db maintain --vacuum\" to reclaim unused space, or expire old incidents.",
+			formatBytes(stats.DatabaseBytes), cfg.AlertSizeMB, formatBytes(stats.FreeBytes()))
+	}
+	if cfg.AlertWALMB > 0 && stats.WALBytes > int64(cfg.AlertWALMB)*bytesPerMB {
+		alerts["wal"] = fmt.Sprintf("The SQLite write-ahead log is %s after a checkpoint, above the %dMB alert threshold. Long-running readers are keeping it from being truncated.",
+			formatBytes(stats.WALBytes), cfg.AlertWALMB)
+	}
+	return alerts
+}
+
+// sqliteMaintainer runs the background maintenance of a SQLite state store and
+// alerts when the database or its WAL grows beyond the thresholds.
+type sqliteMaintainer struct {
+	cfg      config.SQLiteMaintenanceConfig
+	store    *sqlite.Store
+	notifier reporting.Notifier
+
+	// firing holds the alerts sent and not yet cleared, so an alert is sent once
+	// each time a threshold is crossed rather than every run
+	firing map[string]bool
+}
+
+// Run maintains the database now and every interval until ctx is done.
+func (m *sqliteMaintainer) Run(ctx context.Context) {
+	m.firing = make(map[string]bool)
+	m.maintain(ctx)
+	ticker := time.NewTicker(m.cfg.Interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.maintain(ctx)
+		}
+	}
+}
+
+func (m *sqliteMaintainer) maintain(ctx context.Context) {
+	result, err := maintainSQLite(ctx, m.store, m.cfg.VacuumPages)
+	if err != nil {
+		slog.Error("sqlite maintenance failed", "error", err)
+		return
+	}
+	log := slog.With(
+		"database_bytes", result.After.DatabaseBytes,
+		"wal_bytes", result.After.WALBytes,
+		"free_pages", result.After.FreePages,
+		"freed_pages", result.FreedPages,
+		"checkpointed_frames", result.Checkpoint.CheckpointedFrames)
+	if result.Checkpoint.Busy {
+		log.Warn("sqlite maintenance could not truncate the WAL, readers were active")
+	} else {
+		log.Info("sqlite maintenance completed")
+	}
+	if result.After.AutoVacuum != sqlite.AutoVacuumIncremental && result.After.FreePages > 0 {
+		slog.Info("sqlite database has unused pages that only a full vacuum reclaims, run \"nightcrier db maintain --vacuum\" once to enable incremental vacuuming",
+			"free_bytes", result.After.FreeBytes())
+	}
+
+	alerts := sqliteSizeAlerts(m.cfg, result.After)
+	var items []string
+	for key, text := range alerts {
+		if !m.firing[key] {
+			items = append(items, text)
+		}
+		slog.Warn("sqlite state store size alert", "alert", key, "message", text)
+	}
+	m.firing = make(map[string]bool, len(alerts))
+	for key := range alerts {
+		m.firing[key] = true
+	}
+	if len(items) == 0 || m.notifier == nil {
+		return
+	}
+	digest := reporting.Digest{
+		Heading:  "Nightcrier: state store size alert",
+		Note:     "Sent once each time a sqlite_maintenance threshold is crossed.",
+		Count:    len(items),
+		Sections: []reporting.DigestSection{{Title: reporting.DigestSectionStorage, Items: items}},
+	}
+	if err := m.notifier.SendDigest(ctx, digest); err != nil {
+		slog.Error("failed to send state store size alert", "error", err)
+	}
+}
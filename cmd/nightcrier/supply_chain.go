@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/supplychain"
+)
+
+// newSupplyChainVerifier returns the verifier of the agent image and skill
+// bundles, or nil when supply chain verification is off.
+func newSupplyChainVerifier(cfg *config.Config) *supplychain.Verifier {
+	if !cfg.SupplyChain.Enabled() {
+		return nil
+	}
+	return supplychain.New(cfg.SupplyChain.VerifierConfig())
+}
+
+// verifiedAgentImage verifies the agent image and returns the reference the agent
+// containers run: the image pinned to its verified digest, so a tag moved later
+// is not run, or the configured image when verification failed under the warn
+// policy. Under the enforce policy a failed verification is returned.
+func verifiedAgentImage(ctx context.Context, verifier *supplychain.Verifier, image string) (string, error) {
+	if verifier == nil {
+		return image, nil
+	}
+	result, err := verifier.VerifyImage(ctx, image)
+	if err != nil {
+		return "", err
+	}
+	if result.Error != "" || result.Digest == "" {
+		return image, nil
+	}
+	return supplychain.PinDigest(image, result.Digest), nil
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/health"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Top command flags
+	topServer   string
+	topToken    string
+	topInterval time.Duration
+)
+
+// topRequestTimeout bounds each request to the health server
+const topRequestTimeout = 5 * time.Second
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Monitor a running nightcrier in the terminal",
+	Long: `Show a live view of a running nightcrier: cluster connection states and event
+counts, event queue depths, running investigations with their elapsed time and the
+agent's current step, and the results of recent investigations.
+
+The view is read from the daemon's health server (/health/clusters,
+/health/queues, /health/investigations) and refreshed every --interval. Sections
+the daemon does not serve are left out. Press r to refresh now and q to quit.`,
+	Example: `  nightcrier top
+  nightcrier top --server https://nightcrier.example.com:8080 --token "$HEALTH_TOKEN"`,
+	Args: cobra.NoArgs,
+	RunE: runTop,
+}
+
+func init() {
+	topCmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to config file (used to locate the health server and its auth token)")
+	topCmd.Flags().StringVar(&topServer, "server", "", "Health server URL of the running daemon (default: derived from the health server configuration)")
+	topCmd.Flags().StringVar(&topToken, "token", "", "Health server auth token (default: health_server.auth_token from the config file)")
+	topCmd.Flags().DurationVar(&topInterval, "interval", 2*time.Second, "Refresh interval")
+	rootCmd.AddCommand(topCmd)
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	if topInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	server, token := topServer, topToken
+	if server == "" || token == "" {
+		cfg, err := config.LoadWithConfigFile(configFile)
+		switch {
+		case err != nil && server == "":
+			return fmt.Errorf("failed to load configuration (use --server to bypass): %w", err)
+		case err == nil:
+			if server == "" {
+				server = defaultHealthServerURL(cfg.HealthServer)
+			}
+			if token == "" {
+				token = cfg.HealthServer.AuthToken
+			}
+		}
+	}
+	// Log lines would corrupt the terminal UI
+	setupLogging("error")
+
+	client := &topClient{
+		server: strings.TrimSuffix(server, "/"),
+		token:  token,
+		http:   &http.Client{Timeout: topRequestTimeout},
+	}
+	_, err := tea.NewProgram(newTopModel(client, topInterval), tea.WithAltScreen()).Run()
+	return err
+}
+
+// topClient reads the health endpoints of a running daemon.
+type topClient struct {
+	server string
+	token  string
+	http   *http.Client
+}
+
+// topInvestigations is the /health/investigations response.
+type topInvestigations struct {
+	Investigations []incident.InvestigationProgress `json:"investigations"`
+	Recent         []incident.InvestigationResult   `json:"recent"`
+}
+
+// topSnapshot is one reading of the daemon's state. Queues and Investigations
+// are nil when the daemon does not serve them.
+type topSnapshot struct {
+	Health         health.HealthSummary
+	Queues         *cluster.QueueStats
+	Investigations *topInvestigations
+	FetchedAt      time.Time
+}
+
+// errNotServed means the daemon does not serve an endpoint
+var errNotServed = errors.New("endpoint not served")
+
+// snapshot reads the cluster health, which must be served, and the queues and
+// investigations, when served.
+func (c *topClient) snapshot(ctx context.Context) (*topSnapshot, error) {
+	snap := &topSnapshot{FetchedAt: time.Now()}
+	if err := c.get(ctx, "/health/clusters", &snap.Health); err != nil {
+		return nil, err
+	}
+
+	var queues cluster.QueueStats
+	switch err := c.get(ctx, "/health/queues", &queues); {
+	case err == nil:
+		snap.Queues = &queues
+	case !errors.Is(err, errNotServed):
+		return nil, err
+	}
+
+	var investigations topInvestigations
+	switch err := c.get(ctx, "/health/investigations", &investigations); {
+	case err == nil:
+		snap.Investigations = &investigations
+	case !errors.Is(err, errNotServed):
+		return nil, err
+	}
+	return snap, nil
+}
+
+// get decodes the JSON response of a health endpoint into v.
+func (c *topClient) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", c.server, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return errNotServed
+	case http.StatusUnauthorized:
+		return fmt.Errorf("the health server at %s rejected the auth token (use --token)", c.server)
+	default:
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("GET %s failed (status %d): %s", path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// topModel is the bubbletea model of the top view.
+type topModel struct {
+	client   *topClient
+	interval time.Duration
+
+	snapshot *topSnapshot
+	err      error // of the last refresh; the last good snapshot stays shown
+	fetching bool
+	width    int
+}
+
+// topSnapshotMsg carries the result of a refresh.
+type topSnapshotMsg struct {
+	snapshot *topSnapshot
+	err      error
+}
+
+// topTickMsg triggers the next refresh.
+type topTickMsg struct{}
+
+func newTopModel(client *topClient, interval time.Duration) *topModel {
+	return &topModel{client: client, interval: interval, fetching: true}
+}
+
+// Init implements tea.Model.
+func (m *topModel) Init() tea.Cmd {
+	return m.fetch()
+}
+
+// fetch reads a snapshot in the background.
+func (m *topModel) fetch() tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*topRequestTimeout)
+		defer cancel()
+		snapshot, err := client.snapshot(ctx)
+		return topSnapshotMsg{snapshot: snapshot, err: err}
+	}
+}
+
+// Update implements tea.Model.
+func (m *topModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "r":
+			if !m.fetching {
+				m.fetching = true
+				return m, m.fetch()
+			}
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case topSnapshotMsg:
+		m.fetching = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.snapshot = msg.snapshot
+		}
+		return m, tea.Tick(m.interval, func(time.Time) tea.Msg { return topTickMsg{} })
+	case topTickMsg:
+		if !m.fetching {
+			m.fetching = true
+			return m, m.fetch()
+		}
+	}
+	return m, nil
+}
+
+var (
+	topTitleStyle   = lipgloss.NewStyle().Bold(true)
+	topSectionStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+	topDimStyle     = lipgloss.NewStyle().Faint(true)
+	topGoodStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	topWarnStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	topBadStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// View implements tea.Model.
+func (m *topModel) View() string {
+	var b strings.Builder
+	b.WriteString(topTitleStyle.Render("nightcrier top") + "  " + topDimStyle.Render(m.client.server))
+	if m.snapshot != nil {
+		b.WriteString(topDimStyle.Render(fmt.Sprintf("  updated %s, every %s", m.snapshot.FetchedAt.Format("15:04:05"), m.interval)))
+	}
+	b.WriteString("\n")
+	if m.err != nil {
+		b.WriteString(topBadStyle.Render("error: "+m.err.Error()) + "\n")
+	}
+	if m.snapshot == nil {
+		if m.err == nil {
+			b.WriteString("\nconnecting...\n")
+		}
+		b.WriteString(topDimStyle.Render("\nr refresh  q quit") + "\n")
+		return b.String()
+	}
+
+	now := time.Now()
+	renderTopClusters(&b, m.snapshot)
+	if inv := m.snapshot.Investigations; inv != nil {
+		renderTopRunning(&b, inv.Investigations, now)
+		renderTopRecent(&b, inv.Recent, now)
+	}
+	b.WriteString(topDimStyle.Render("\nr refresh  q quit") + "\n")
+	return b.String()
+}
+
+// renderTopClusters writes the cluster connections with their queue depths.
+func renderTopClusters(b *strings.Builder, snap *topSnapshot) {
+	s := snap.Health.Summary
+	fmt.Fprintf(b, "\n%s  %d total, %d active, %d unhealthy",
+		topSectionStyle.Render("CLUSTERS"), s.Total, s.Active, s.Unhealthy)
+	if snap.Queues != nil {
+		fmt.Fprintf(b, ", global queue %d/%d", snap.Queues.GlobalDepth, snap.Queues.GlobalCapacity)
+	}
+	if snap.Health.TriagePausedGlobally != nil {
+		b.WriteString(", " + topWarnStyle.Render("triage paused"))
+	}
+	b.WriteString("\n")
+
+	queues := make(map[string]cluster.ClusterQueueStats)
+	if snap.Queues != nil {
+		for _, q := range snap.Queues.Clusters {
+			queues[q.Cluster] = q
+		}
+	}
+
+	fmt.Fprintf(b, "%-24s %-13s %8s %-10s %-9s %-8s %s\n", "NAME", "STATUS", "EVENTS", "LAST EVENT", "QUEUE", "TRIAGE", "ERROR")
+	for _, c := range snap.Health.Clusters {
+		lastEvent := "-"
+		if c.LastEvent != nil {
+			lastEvent = formatAge(time.Since(*c.LastEvent))
+		}
+		queue := "-"
+		if q, ok := queues[c.Name]; ok && q.Capacity > 0 {
+			queue = fmt.Sprintf("%d/%d", q.Depth, q.Capacity)
+			if q.Depth*10 >= q.Capacity*8 {
+				queue = topWarnStyle.Render(fmt.Sprintf("%-9s", queue))
+			}
+		}
+		triage := "off"
+		switch {
+		case c.TriagePaused:
+			triage = "paused"
+		case c.ServeOnly:
+			triage = "serve"
+		case c.TriageEnabled:
+			triage = "on"
+		}
+		fmt.Fprintf(b, "%-24s %s %8d %-10s %-9s %-8s %s\n",
+			truncateString(c.Name, 24), topStatusStyle(c.Status).Render(fmt.Sprintf("%-13s", c.Status)),
+			c.EventCount, lastEvent, queue, triage, truncateString(c.LastError, 60))
+	}
+}
+
+// renderTopRunning writes the running investigations.
+func renderTopRunning(b *strings.Builder, running []incident.InvestigationProgress, now time.Time) {
+	fmt.Fprintf(b, "\n%s  %d\n", topSectionStyle.Render("RUNNING INVESTIGATIONS"), len(running))
+	if len(running) == 0 {
+		b.WriteString(topDimStyle.Render("none") + "\n")
+		return
+	}
+	fmt.Fprintf(b, "%-36s %-16s %-28s %-22s %8s %5s %s\n", "INCIDENT", "CLUSTER", "RESOURCE", "FAULT", "ELAPSED", "STEPS", "PROGRESS")
+	for _, inv := range running {
+		fmt.Fprintf(b, "%-36s %-16s %-28s %-22s %8s %5d %s\n",
+			truncateString(inv.IncidentID, 36), truncateString(inv.Cluster, 16), truncateString(inv.Resource, 28),
+			truncateString(inv.FaultType, 22), formatElapsed(now.Sub(inv.StartedAt)), inv.Steps, truncateString(inv.Progress, 60))
+	}
+}
+
+// renderTopRecent writes the results of recent investigations.
+func renderTopRecent(b *strings.Builder, recent []incident.InvestigationResult, now time.Time) {
+	fmt.Fprintf(b, "\n%s\n", topSectionStyle.Render("RECENT RESULTS"))
+	if len(recent) == 0 {
+		b.WriteString(topDimStyle.Render("none yet") + "\n")
+		return
+	}
+	fmt.Fprintf(b, "%-10s %-36s %-16s %-28s %-13s %-20s %s\n", "COMPLETED", "INCIDENT", "CLUSTER", "RESOURCE", "STATUS", "FAILURE CLASS", "DURATION")
+	for _, r := range recent {
+		id := r.DisplayID
+		if id == "" {
+			id = r.IncidentID
+		}
+		duration := "-"
+		if r.DurationMs > 0 {
+			duration = formatElapsed(time.Duration(r.DurationMs) * time.Millisecond)
+		}
+		status := topGoodStyle
+		if r.Status != incident.StatusResolved {
+			status = topBadStyle
+		}
+		fmt.Fprintf(b, "%-10s %-36s %-16s %-28s %s %-20s %s\n",
+			formatAge(now.Sub(r.CompletedAt)), truncateString(id, 36), truncateString(r.Cluster, 16), truncateString(r.Resource, 28),
+			status.Render(fmt.Sprintf("%-13s", r.Status)), orDash(r.FailureClass), duration)
+	}
+}
+
+// topStatusStyle colors a cluster connection status.
+func topStatusStyle(status cluster.ConnectionStatus) lipgloss.Style {
+	switch status {
+	case cluster.StatusActive:
+		return topGoodStyle
+	case cluster.StatusFailed, cluster.StatusDisconnected, cluster.StatusDegraded:
+		return topBadStyle
+	default:
+		return topWarnStyle
+	}
+}
+
+// formatElapsed formats a duration to the second, e.g. "4m05s".
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// formatAge formats how long ago something happened, e.g. "4m05s ago".
+func formatAge(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return formatElapsed(d) + " ago"
+}
+
+// orDash returns s, or "-" when it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
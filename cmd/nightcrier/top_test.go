@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTopSnapshot(t *testing.T) {
+	started := time.Now().Add(-95 * time.Second).UTC().Format(time.RFC3339Nano)
+	completed := time.Now().Add(-10 * time.Minute).UTC().Format(time.RFC3339Nano)
+	responses := map[string]string{
+		"/health/clusters": `{"clusters":[{"name":"prod-east","status":"active","event_count":42,"triage_enabled":true},
+			{"name":"staging","status":"failed","error":"connection refused"}],
+			"summary":{"total":2,"active":1,"unhealthy":1}}`,
+		"/health/queues": `{"global_depth":3,"global_capacity":100,
+			"clusters":[{"cluster":"prod-east","depth":3,"capacity":10,"dropped":0}]}`,
+		"/health/investigations": `{"count":1,
+			"investigations":[{"incident_id":"inc-running","cluster":"prod-east","resource":"Pod/api-7f9c","fault_type":"CrashLoop","started_at":"` + started + `","progress":"checking pod logs","steps":4}],
+			"recent":[{"incident_id":"inc-done","display_id":"INC-0007","cluster":"prod-east","resource":"Pod/web-1","status":"agent_failed","failure_class":"provider_rate_limit","completed_at":"` + completed + `","duration_ms":61000}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := &topClient{server: server.URL, token: "secret", http: server.Client()}
+	snap, err := client.snapshot(context.Background())
+	if err != nil {
+		t.Fatalf("snapshot() error = %v", err)
+	}
+	if snap.Queues == nil || snap.Investigations == nil {
+		t.Fatalf("snapshot() = %+v, want queues and investigations", snap)
+	}
+
+	m := newTopModel(client, 2*time.Second)
+	m.Update(topSnapshotMsg{snapshot: snap})
+	view := m.View()
+	for _, want := range []string{"prod-east", "3/10", "connection refused", "inc-running", "1m35s", "checking pod logs", "INC-0007", "provider_rate_limit", "1m01s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() does not contain %q:\n%s", want, view)
+		}
+	}
+
+	// Queues and investigations are optional; the cluster health is not
+	delete(responses, "/health/queues")
+	delete(responses, "/health/investigations")
+	if snap, err = client.snapshot(context.Background()); err != nil || snap.Queues != nil || snap.Investigations != nil {
+		t.Errorf("snapshot() = %+v, %v, want cluster health only", snap, err)
+	}
+	client.token = "wrong"
+	if _, err := client.snapshot(context.Background()); err == nil || !strings.Contains(err.Error(), "auth token") {
+		t.Errorf("snapshot() error = %v, want an auth token error", err)
+	}
+}
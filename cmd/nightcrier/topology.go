@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/topology"
+)
+
+// newTopologyCollectors returns the topology collector of each cluster with
+// kubeconfig triage, or nil when topology collection is disabled.
+func newTopologyCollectors(cfg *config.Config) map[string]*topology.Collector {
+	if !cfg.Topology.Enabled {
+		return nil
+	}
+	collectors := make(map[string]*topology.Collector)
+	for _, cl := range cfg.Clusters {
+		if cl.Triage.Enabled && cl.Triage.Kubeconfig != "" {
+			collectors[cl.Name] = topology.NewCollector(cl.Triage.Kubeconfig, cfg.Topology.MaxPods)
+		}
+	}
+	return collectors
+}
+
+// collectTopology writes the topology around the incident's resource into the
+// workspace and reports whether it did. Collection problems are logged and never
+// fail the incident.
+func (p *eventProcessor) collectTopology(ctx context.Context, inc *incident.Incident, workspacePath string) bool {
+	collector := p.topology[inc.Cluster]
+	if collector == nil || inc.Resource == nil {
+		return false
+	}
+	log := incident.Logger(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Topology.Timeout())
+	defer cancel()
+	graph, err := collector.Collect(ctx, topology.Target{
+		Namespace: inc.Namespace,
+		Kind:      inc.Resource.Kind,
+		Name:      inc.Resource.Name,
+	})
+	if err != nil {
+		log.Warn("failed to collect resource topology", "error", err)
+		return false
+	}
+	if err := topology.Write(workspacePath, graph); err != nil {
+		log.Warn("failed to write resource topology", "error", err)
+		return false
+	}
+	log.Info("wrote resource topology to workspace",
+		"objects", len(graph.Nodes),
+		"warnings", len(graph.Warnings))
+	return true
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/logging"
+)
+
+// tuningAdmin serves the health server's tuning API from the tuning store.
+type tuningAdmin struct {
+	store *config.TuningStore
+}
+
+func (a tuningAdmin) GetTuning() interface{} {
+	return a.store.Current()
+}
+
+func (a tuningAdmin) PatchTuning(patch []byte) (interface{}, error) {
+	return a.store.Patch(patch)
+}
+
+func (a tuningAdmin) ReloadTuning() (interface{}, error) {
+	return a.store.Reload()
+}
+
+// reloadTuningOnHangup re-reads the tuning file whenever the process receives
+// SIGHUP, until ctx is done. An invalid file is logged and the tuning in effect is
+// kept.
+func reloadTuningOnHangup(ctx context.Context, store *config.TuningStore) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			update, err := store.Reload()
+			if err != nil {
+				slog.Error("failed to reload tuning, keeping the current tuning", "error", err)
+				continue
+			}
+			slog.Info("tuning reloaded",
+				"changes", len(update.Changes),
+				"restart_required", update.RestartRequired)
+		}
+	}
+}
+
+// applyComponentLevels sets the per-component log levels of the tuning on top of
+// the global log level. The tuning was validated, so its levels parse.
+func applyComponentLevels(handler *logging.Handler, tuning *config.TuningConfig) {
+	levels, err := logging.NewLevels(handler.Levels().Default, tuning.Logging.Levels)
+	if err != nil {
+		slog.Error("invalid component log levels, keeping the current levels", "error", err)
+		return
+	}
+	handler.SetLevels(levels)
+	if len(levels.Components) > 0 {
+		slog.Info("component log levels set", "levels", tuning.Logging.Levels)
+	}
+}
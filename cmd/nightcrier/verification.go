@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/verify"
+)
+
+// verificationHTTPTimeout bounds each request to an MCP server or image registry
+const verificationHTTPTimeout = 10 * time.Second
+
+// newVerifier returns the verifier checking agent findings against the clusters,
+// or nil when output verification is disabled.
+func newVerifier(cfg *config.Config) *verify.Verifier {
+	if !cfg.Verification.Enabled {
+		return nil
+	}
+	endpoints := make(map[string]string, len(cfg.Clusters))
+	for _, cl := range cfg.Clusters {
+		endpoints[cl.Name] = cl.MCP.Endpoint
+	}
+	return verify.NewVerifier(verify.Config{
+		Endpoints:       endpoints,
+		CheckRegistries: cfg.Verification.CheckRegistries,
+	}, &http.Client{Timeout: verificationHTTPTimeout})
+}
+
+// verifyFindings checks the claims the agent recorded in findings.json against
+// the incident's cluster, writes the results to verification.json, appends the
+// verification marks to the investigation report, and records the counts on the
+// incident. Verification problems are logged and never fail the incident.
+func (p *eventProcessor) verifyFindings(ctx context.Context, inc *incident.Incident, workspacePath string) {
+	log := incident.Logger(ctx)
+	claims, err := verify.ReadClaims(filepath.Join(workspacePath, verify.AgentFile))
+	if err != nil {
+		log.Warn("ignoring claims recorded by the agent", "error", err)
+		return
+	}
+	if len(claims) == 0 {
+		log.Debug("agent recorded no claims to verify")
+		return
+	}
+
+	verifyCtx, cancel := context.WithTimeout(ctx, p.cfg.Verification.Timeout())
+	defer cancel()
+	results := p.verifier.Verify(verifyCtx, inc.Cluster, claims)
+
+	if err := verify.WriteResults(filepath.Join(workspacePath, verify.ResultsFile), results); err != nil {
+		log.Warn("failed to write verification results", "error", err)
+	}
+	if err := verify.AnnotateReport(filepath.Join(workspacePath, "output", "investigation.md"), results); err != nil {
+		log.Warn("failed to annotate report with verification results", "error", err)
+	}
+
+	summary := verify.Summarize(results)
+	inc.Verification = &summary
+	log.Info("verified agent findings against cluster",
+		"claims", summary.Total(),
+		"verified", summary.Verified,
+		"contradicted", summary.Contradicted,
+		"unverified", summary.Unverified)
+}
@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Find returns the workspace directory of an incident: its persistent directory
+// under the workspace root, or, while the investigation still runs in a scratch
+// workspace, the scratch directory. It returns an error wrapping fs.ErrNotExist
+// when the incident has no workspace, including for IDs that are not a single
+// path element.
+func (w *WorkspaceManager) Find(incidentID string) (string, error) {
+	if incidentID == "" || incidentID == "." || incidentID == ".." || strings.ContainsAny(incidentID, `/\`) {
+		return "", fmt.Errorf("invalid incident ID %q: %w", incidentID, fs.ErrNotExist)
+	}
+	roots := []string{w.root}
+	if w.scratchRoot != "" {
+		roots = append(roots, w.scratchRoot)
+	}
+	for _, root := range roots {
+		dir := filepath.Join(root, incidentID)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("workspace of incident %s: %w", incidentID, fs.ErrNotExist)
+}
+
+// WorkspaceArchive streams a workspace as a gzip-compressed tar archive. It
+// implements io.WriterTo, so the archive can be written straight to an HTTP
+// response without being staged on disk.
+type WorkspaceArchive struct {
+	// Dir is the workspace directory
+	Dir string
+	// Prefix is the directory the entries are placed under in the archive
+	// (usually the incident ID)
+	Prefix string
+}
+
+// WriteTo writes the archive of the directories and regular files of the
+// workspace. Symlinks and special files are skipped, so the archive cannot
+// include files outside the workspace. A file written by a running agent is
+// archived with the size it had when it was reached.
+func (a WorkspaceArchive) WriteTo(dst io.Writer) (int64, error) {
+	counter := &countingWriter{w: dst}
+	gz := gzip.NewWriter(counter)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.WalkDir(a.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(a.Dir, p)
+		if err != nil {
+			return err
+		}
+		name := path.Join(a.Prefix, filepath.ToSlash(rel))
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     name + "/",
+				Mode:     int64(info.Mode().Perm()),
+				ModTime:  info.ModTime(),
+			})
+		case info.Mode().IsRegular():
+			return archiveFile(tw, p, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return counter.n, fmt.Errorf("failed to archive workspace %s: %w", a.Dir, err)
+	}
+	if err := tw.Close(); err != nil {
+		return counter.n, fmt.Errorf("failed to finish workspace archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return counter.n, fmt.Errorf("failed to finish workspace archive: %w", err)
+	}
+	return counter.n, nil
+}
+
+// archiveFile writes one regular file to the archive. The size is taken from the
+// opened file and the copy is bounded by it, so a file that grows while it is
+// archived does not corrupt the archive.
+func archiveFile(tw *tar.Writer, src, name string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(info.Mode().Perm()),
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+	}); err != nil {
+		return err
+	}
+	_, err = io.CopyN(tw, f, info.Size())
+	return err
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
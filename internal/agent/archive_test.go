@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkspaceManagerFind(t *testing.T) {
+	root, scratch := t.TempDir(), t.TempDir()
+	wm := NewScratchWorkspaceManager(root, scratch)
+	if err := os.Mkdir(filepath.Join(root, "done"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(scratch, "running"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if dir, err := wm.Find("done"); err != nil || dir != filepath.Join(root, "done") {
+		t.Errorf("Find(done) = %q, %v, want the persistent workspace", dir, err)
+	}
+	if dir, err := wm.Find("running"); err != nil || dir != filepath.Join(scratch, "running") {
+		t.Errorf("Find(running) = %q, %v, want the scratch workspace", dir, err)
+	}
+	for _, id := range []string{"missing", "..", "../" + filepath.Base(root), ""} {
+		if _, err := wm.Find(id); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Find(%q) error = %v, want fs.ErrNotExist", id, err)
+		}
+	}
+}
+
+func TestWorkspaceArchive(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"incident.json":           "{}",
+		"output/investigation.md": "# Report",
+		"scripts/collect.sh":      "#!/bin/sh",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(dir, "scripts/collect.sh"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/etc/passwd", filepath.Join(dir, "passwd")); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err := WorkspaceArchive{Dir: dir, Prefix: "INC-1"}.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d bytes, wrote %d", n, buf.Len())
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	modes := map[string]int64{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		files[header.Name] = string(data)
+		modes[header.Name] = header.Mode
+	}
+
+	want := map[string]string{
+		"INC-1/":                        "",
+		"INC-1/incident.json":           "{}",
+		"INC-1/output/":                 "",
+		"INC-1/output/investigation.md": "# Report",
+		"INC-1/scripts/":                "",
+		"INC-1/scripts/collect.sh":      "#!/bin/sh",
+	}
+	if len(files) != len(want) {
+		t.Errorf("archive entries = %v, want %v", files, want)
+	}
+	for name, content := range want {
+		if got, ok := files[name]; !ok || got != content {
+			t.Errorf("archive entry %s = %q (present %v), want %q", name, got, ok, content)
+		}
+	}
+	if modes["INC-1/scripts/collect.sh"] != 0700 {
+		t.Errorf("collect.sh mode = %o, want 0700", modes["INC-1/scripts/collect.sh"])
+	}
+}
@@ -0,0 +1,221 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DebugBundleFile is the path of the debug bundle in the workspace, next to the
+// agent logs so that it expires with them (artifact retention class "logs")
+const DebugBundleFile = "logs/debug-bundle.json"
+
+// kernelLogTimeout bounds reading the kernel log for a debug bundle
+const kernelLogTimeout = 5 * time.Second
+
+// maxKernelLogLines bounds the kernel log lines kept in a debug bundle
+const maxKernelLogLines = 50
+
+// kernelLogCommand reads the kernel ring buffer. Reading it needs CAP_SYSLOG when
+// kernel.dmesg_restrict is set; the bundle then records why it is missing.
+var kernelLogCommand = []string{"dmesg"}
+
+// kernelLogPattern selects the kernel log lines about killed or crashed processes
+var kernelLogPattern = regexp.MustCompile(`(?i)out of memory|oom[-_ ]?kill|killed process|memory cgroup|segfault|general protection|traps:`)
+
+// debugBundleEnv are the environment variables recorded in debug bundles: the
+// agent settings nightcrier passes to run-agent.sh, and the host basics. API keys,
+// provider credentials, and proxy URLs (which may embed credentials) are never
+// recorded.
+var debugBundleEnv = []string{
+	"INCIDENT_ID", "AGENT_CLI", "AGENT_IMAGE", "LLM_MODEL", "AGENT_ALLOWED_TOOLS",
+	"CONTAINER_TIMEOUT", "OUTPUT_FORMAT", "CONTAINER_NETWORK", "AGENT_MAX_TURNS",
+	"AGENT_VERBOSE", "DEBUG", "KUBECONFIG", "SKILLS_DIR", "SKILLS_SELECTED",
+	"DISABLE_TRIAGE_PRELOAD", "AGENT_HARDENING", "CONTAINER_USER", "CONTAINER_READ_ONLY",
+	"CONTAINER_TMPFS", "AGENT_OFFLINE", "LLM_PROVIDER", "NO_PROXY",
+	"PATH", "HOME", "USER", "HOSTNAME", "DOCKER_HOST",
+}
+
+// DebugBundle is the state captured when the agent process crashes or is killed,
+// to diagnose agent runtime issues (OOM kills, segfaults, aborted CLIs) that leave
+// no report behind. It is written to the workspace only and is not uploaded.
+type DebugBundle struct {
+	CapturedAt time.Time `json:"captured_at"`
+	IncidentID string    `json:"incident_id"`
+	// ExitCode is the agent script's exit code (-1 when it was killed by a signal)
+	ExitCode int `json:"exit_code"`
+	// Signal is the signal that killed the agent, directly or (exit codes 129-159)
+	// inside its container
+	Signal string `json:"signal,omitempty"`
+	// FailureClass is the canonical failure class, filled in once the failure is
+	// classified
+	FailureClass string `json:"failure_class,omitempty"`
+	// Stdout and Stderr are the tails of the agent's output
+	Stdout string `json:"stdout_tail"`
+	Stderr string `json:"stderr_tail"`
+	// Environment holds the allow-listed variables of the agent's environment
+	Environment map[string]string `json:"environment"`
+	// Resources is the resource usage sampled during the run, when sampling is on
+	Resources *ResourceUsage `json:"resources,omitempty"`
+	// ProcessCPUSeconds is the CPU time of the agent script and its waited-for
+	// children (not the container, with Docker)
+	ProcessCPUSeconds float64 `json:"process_cpu_seconds"`
+	// KernelLog holds the recent kernel log lines about killed or crashed processes
+	KernelLog []string `json:"kernel_log,omitempty"`
+	// KernelLogError says why the kernel log could not be read
+	KernelLogError string `json:"kernel_log_error,omitempty"`
+}
+
+// Write writes the bundle to the workspace (see DebugBundleFile).
+func (b *DebugBundle) Write(workspacePath string) error {
+	path := filepath.Join(workspacePath, DebugBundleFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create logs directory: %w", err)
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal debug bundle: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write debug bundle: %w", err)
+	}
+	return nil
+}
+
+// Output returns the captured output and kernel log lines, for failure
+// classification.
+func (b *DebugBundle) Output() string {
+	return b.Stdout + "\n" + b.Stderr + "\n" + strings.Join(b.KernelLog, "\n")
+}
+
+// ReadDebugBundle reads the workspace's debug bundle. It returns nil without an
+// error when the agent did not crash.
+func ReadDebugBundle(workspacePath string) (*DebugBundle, error) {
+	data, err := os.ReadFile(filepath.Join(workspacePath, DebugBundleFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read debug bundle: %w", err)
+	}
+	var b DebugBundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse debug bundle: %w", err)
+	}
+	return &b, nil
+}
+
+// crashSignal returns the signal that killed the agent, or "" when it exited on
+// its own. run-agent.sh and docker report a child or container killed by signal N
+// as exit code 128+N (137 for an OOM kill).
+func crashSignal(exitCode int, state *os.ProcessState) string {
+	if state != nil {
+		if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return status.Signal().String()
+		}
+	}
+	if exitCode > 128 && exitCode < 160 {
+		return syscall.Signal(exitCode - 128).String()
+	}
+	return ""
+}
+
+// filterEnv returns the values of the allow-listed variables in env ("NAME=value"
+// assignments, the last one winning).
+func filterEnv(env []string, allowed []string) map[string]string {
+	allow := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		allow[name] = true
+	}
+	values := make(map[string]string)
+	for _, kv := range env {
+		name, value, ok := strings.Cut(kv, "=")
+		if ok && allow[name] {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// readKernelLog returns the recent kernel log lines about killed or crashed
+// processes, the last maxKernelLogLines of them.
+func readKernelLog(ctx context.Context) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, kernelLogTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, kernelLogCommand[0], kernelLogCommand[1:]...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kernel log: %w", err)
+	}
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if line := scanner.Text(); kernelLogPattern.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > maxKernelLogLines {
+		lines = lines[len(lines)-maxKernelLogLines:]
+	}
+	return lines, nil
+}
+
+// tailBuffer is a writer keeping the last max bytes written to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func newTailBuffer(max int) *tailBuffer {
+	return &tailBuffer{max: max}
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.max {
+		t.buf = append(t.buf[:0], t.buf[len(t.buf)-t.max:]...)
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
+
+// captureDebugBundle collects the debug bundle of a crashed agent run.
+func (e *Executor) captureDebugBundle(ctx context.Context, incidentID string, exitCode int, signal string, cmd *exec.Cmd, stdout, stderr *tailBuffer, usage *ResourceUsage) *DebugBundle {
+	allowed := append(append([]string(nil), debugBundleEnv...), e.config.DebugBundleEnv...)
+	b := &DebugBundle{
+		CapturedAt:  time.Now().UTC(),
+		IncidentID:  incidentID,
+		ExitCode:    exitCode,
+		Signal:      signal,
+		Stdout:      stdout.String(),
+		Stderr:      stderr.String(),
+		Environment: filterEnv(cmd.Env, allowed),
+		Resources:   usage,
+	}
+	if cmd.ProcessState != nil {
+		b.ProcessCPUSeconds = (cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()).Seconds()
+	}
+	kernelLog, err := readKernelLog(ctx)
+	if err != nil {
+		b.KernelLogError = err.Error()
+	}
+	b.KernelLog = kernelLog
+	return b
+}
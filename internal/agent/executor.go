@@ -4,16 +4,22 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/egress"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/skills"
 )
 
 // ExecutorConfig holds configuration for the agent executor
@@ -22,21 +28,38 @@ type ExecutorConfig struct {
 	SystemPromptFile     string
 	AllowedTools         string
 	Model                string
-	Timeout              int    // seconds
-	AgentCLI             string // claude, codex, goose, gemini
-	AgentImage           string // Docker image for agent container
-	AdditionalPrompt     string // Optional additional context for the agent
-	Debug                bool   // Enable debug output in run-agent.sh
-	Verbose              bool   // Enable verbose agent output (shows thinking/tool usage)
-	Kubeconfig           string // Path to kubeconfig file for cluster access
-	SkillsCacheDir       string // Path to skills cache directory
-	DisableTriagePreload bool   // Disable preloading of triage scripts
+	Timeout              int            // seconds
+	AgentCLI             string         // claude, codex, goose, gemini
+	AgentImage           string         // Docker image for agent container
+	AdditionalPrompt     string         // Optional additional context for the agent
+	ReportLanguage       string         // Language for the investigation report (empty = English)
+	Debug                bool           // Enable debug output in run-agent.sh
+	Verbose              bool           // Enable verbose agent output (shows thinking/tool usage)
+	Kubeconfig           string         // Path to kubeconfig file for cluster access
+	SkillsCacheDir       string         // Path to skills cache directory
+	SelectedSkills       []string       // Skill bundles mounted for this cluster (nil = whole cache directory)
+	DisableTriagePreload bool           // Disable preloading of triage scripts
+	ProxyEnv             []string       // HTTP(S)_PROXY/NO_PROXY assignments for LLM API calls from the agent
+	LLMProviderEnv       []string       // LLM_PROVIDER and credential assignments for self-hosted or managed-cloud LLMs
+	HardeningEnv         []string       // AGENT_HARDENING and container user/filesystem assignments for least-privilege execution
+	EgressEnv            []string       // CONTAINER_NETWORK and proxy assignments confining the agent's network access (overrides ProxyEnv)
+	EgressPolicy         *egress.Policy // Network access the agent runs with under egress control, recorded on each incident
+	Offline              bool           // Air-gapped mode: disable the agent CLI's update checks and telemetry
+	StreamProgress       bool           // Stream structured agent output and report progress events (claude only)
+	SeverityTimeouts     map[string]int // Per-severity timeouts in seconds (uppercase severity keys), overriding Timeout
+	DebugBundleTailBytes int            // Agent output kept for the debug bundle written when the agent crashes (0 = no debug bundles)
+	DebugBundleEnv       []string       // Environment variables recorded in debug bundles, besides the built-in allow-list
 }
 
 // Executor runs the agent script in a workspace directory.
 type Executor struct {
 	config ExecutorConfig
 	tuning *config.TuningConfig
+	// liveTuning, when set, supersedes tuning so runtime tuning changes apply to
+	// the next run
+	liveTuning *config.TuningStore
+	// runtime, when set, reads the agent CLI version and image digest for AgentInfo
+	runtime *RuntimeProber
 }
 
 // LogPaths contains the paths to captured agent log files
@@ -216,6 +239,138 @@ func NewExecutorWithConfig(config ExecutorConfig, tuning *config.TuningConfig) *
 	}
 }
 
+// SetTuningStore makes the executor read its tuning (timeout buffer, output buffer
+// sizes) from store, so changes made at runtime apply to the next agent run.
+func (e *Executor) SetTuningStore(store *config.TuningStore) {
+	e.liveTuning = store
+}
+
+// SetRuntimeProber makes AgentInfo read the version of the agent CLI and the
+// digest of the agent image with prober.
+func (e *Executor) SetRuntimeProber(prober *RuntimeProber) {
+	e.runtime = prober
+}
+
+// EgressPolicy returns the network access the agent runs with under egress
+// control, or nil when its network access is not restricted.
+func (e *Executor) EgressPolicy() *egress.Policy {
+	return e.config.EgressPolicy
+}
+
+// currentTuning returns the tuning in effect.
+func (e *Executor) currentTuning() *config.TuningConfig {
+	if e.liveTuning != nil {
+		return e.liveTuning.Current()
+	}
+	return e.tuning
+}
+
+// envContextKey is the unexported context key for per-run environment assignments.
+type envContextKey struct{}
+
+// WithEnv returns a copy of ctx carrying extra environment assignments ("NAME=value")
+// for the agent run, e.g. the API key selected for this investigation. They take
+// precedence over the executor's own environment.
+func WithEnv(ctx context.Context, env ...string) context.Context {
+	return context.WithValue(ctx, envContextKey{}, append(envFromContext(ctx), env...))
+}
+
+// envFromContext returns the environment assignments stored in ctx, if any.
+func envFromContext(ctx context.Context) []string {
+	env, _ := ctx.Value(envContextKey{}).([]string)
+	return append([]string(nil), env...)
+}
+
+// Profile overrides the executor's model, turn limit, and timeout for one
+// investigation, e.g. a cheap fast model for routine faults and the strongest model
+// for production incidents. Zero fields keep the executor's settings.
+type Profile struct {
+	// Name identifies the profile in logs
+	Name     string
+	Model    string
+	MaxTurns int
+	// Timeout is in seconds and takes precedence over the per-severity timeouts
+	Timeout int
+}
+
+// profileContextKey is the unexported context key for the agent profile.
+type profileContextKey struct{}
+
+// WithProfile returns a copy of ctx carrying the agent profile for the run.
+func WithProfile(ctx context.Context, profile Profile) context.Context {
+	return context.WithValue(ctx, profileContextKey{}, profile)
+}
+
+// profileFromContext returns the agent profile stored in ctx, if any.
+func profileFromContext(ctx context.Context) Profile {
+	profile, _ := ctx.Value(profileContextKey{}).(Profile)
+	return profile
+}
+
+// modelFor returns the model for the run: the profile's model if set, otherwise
+// the configured Model.
+func (e *Executor) modelFor(ctx context.Context) string {
+	if model := profileFromContext(ctx).Model; model != "" {
+		return model
+	}
+	return e.config.Model
+}
+
+// AgentInfo identifies the agent an executor runs.
+type AgentInfo struct {
+	CLI   string
+	Image string
+	Model string
+	// PromptVersion identifies the configured prompts (see PromptVersion)
+	PromptVersion string
+	// CLIVersion and ImageDigest are read from the agent image when the executor
+	// has a runtime prober (see SetRuntimeProber); empty when they could not be
+	CLIVersion  string
+	ImageDigest string
+}
+
+// Runtime returns the agent runtime as recorded on the incident.
+func (i AgentInfo) Runtime() *incident.AgentRuntime {
+	return &incident.AgentRuntime{
+		CLI:           i.CLI,
+		CLIVersion:    i.CLIVersion,
+		Image:         i.Image,
+		ImageDigest:   i.ImageDigest,
+		Model:         i.Model,
+		PromptVersion: i.PromptVersion,
+	}
+}
+
+// AgentInfo returns the agent run for the investigation carried in ctx; the agent
+// profile may override the model.
+func (e *Executor) AgentInfo(ctx context.Context) AgentInfo {
+	info := AgentInfo{CLI: e.config.AgentCLI, Image: e.config.AgentImage, Model: e.modelFor(ctx)}
+	systemPrompt, err := e.readSystemPromptFile()
+	if err != nil {
+		slog.Warn("failed to read system prompt file for its version", "error", err)
+	}
+	info.PromptVersion = PromptVersion(systemPrompt, e.config.AdditionalPrompt)
+	if e.runtime != nil && e.config.AgentImage != "" {
+		info.ImageDigest, info.CLIVersion, err = e.runtime.Probe(ctx, e.config.AgentImage, e.config.AgentCLI)
+		if err != nil {
+			slog.Warn("failed to read agent runtime versions", "agent_image", e.config.AgentImage, "error", err)
+		}
+	}
+	return info
+}
+
+// PromptVersion identifies a system prompt and additional prompt pair, so review
+// verdicts can be compared across prompt changes: "sha256:" and the first 12 hex
+// digits of their hash. The per-incident parts of the prompt (incident facts,
+// report language) are not included. It returns "" when neither is set.
+func PromptVersion(systemPrompt, additionalPrompt string) string {
+	if systemPrompt == "" && additionalPrompt == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(systemPrompt + "\x00" + additionalPrompt))
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
+
 // Execute runs the agent script with the given incident ID in the workspace directory.
 // It returns the exit code, log file paths, and any error encountered.
 func (e *Executor) Execute(ctx context.Context, workspacePath string, incidentID string) (int, LogPaths, error) {
@@ -223,19 +378,98 @@ func (e *Executor) Execute(ctx context.Context, workspacePath string, incidentID
 	return e.ExecuteWithPrompt(ctx, workspacePath, incidentID, e.config.AdditionalPrompt)
 }
 
+// ExecuteWithFacts runs the agent with a structured incident facts section placed
+// ahead of the report language instruction and the configured additional prompt.
+func (e *Executor) ExecuteWithFacts(ctx context.Context, workspacePath string, incidentID string, facts string) (int, LogPaths, error) {
+	prompt := combinePrompt(facts, languageInstruction(e.config.ReportLanguage), e.config.AdditionalPrompt)
+	return e.ExecuteWithPrompt(ctx, workspacePath, incidentID, prompt)
+}
+
+// streamsProgress reports whether the configured agent CLI can stream structured
+// output for progress reporting. Only Claude's stream-json format is parsed.
+func (e *Executor) streamsProgress() bool {
+	return e.config.StreamProgress && (e.config.AgentCLI == "" || e.config.AgentCLI == "claude")
+}
+
+// skillSelected reports whether a skill bundle is mounted for this executor's cluster.
+func (e *Executor) skillSelected(name string) bool {
+	if e.config.SelectedSkills == nil {
+		return true
+	}
+	for _, s := range e.config.SelectedSkills {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// languageInstruction returns the prompt section asking for the report in the given
+// language, or "" for English. Section headings and the confidence level stay in
+// English because the Slack summary is extracted from them.
+func languageInstruction(language string) string {
+	if language == "" {
+		return ""
+	}
+	return fmt.Sprintf(`## Report Language
+
+Write output/investigation.md in %[1]s. All prose (summary, root cause, evidence
+explanations, recommendations) must be in %[1]s.
+
+Keep the following unchanged, in English:
+- Markdown section headings required by the report format (e.g. "## Root Cause")
+- The confidence level value (HIGH, MEDIUM, LOW)
+- Kubernetes resource names, field names, commands, and quoted log or event output`, language)
+}
+
+// combinePrompt joins prompt sections with a blank line, skipping empty sections.
+func combinePrompt(sections ...string) string {
+	var parts []string
+	for _, s := range sections {
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// timeoutFor returns the agent timeout in seconds for the incident carried in ctx:
+// the agent profile's timeout, else the timeout configured for its severity, else
+// the default Timeout.
+func (e *Executor) timeoutFor(ctx context.Context) int {
+	if timeout := profileFromContext(ctx).Timeout; timeout > 0 {
+		return timeout
+	}
+	if ic, ok := incident.FromContext(ctx); ok {
+		if timeout, ok := e.config.SeverityTimeouts[strings.ToUpper(strings.TrimSpace(ic.Severity))]; ok {
+			return timeout
+		}
+	}
+	return e.config.Timeout
+}
+
 // ExecuteWithPrompt runs the agent with a custom prompt
 func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string, incidentID string, prompt string) (int, LogPaths, error) {
-	slog.Info("executing agent",
+	// Tag all agent logs with the incident metadata carried in ctx (if any)
+	log := incident.Logger(ctx)
+	if _, ok := incident.FromContext(ctx); !ok {
+		log = log.With("incident_id", incidentID)
+	}
+
+	timeout := e.timeoutFor(ctx)
+	model := e.modelFor(ctx)
+	profile := profileFromContext(ctx)
+	log.Info("executing agent",
 		"script", e.config.ScriptPath,
 		"workspace", workspacePath,
-		"incident_id", incidentID,
 		"agent_cli", e.config.AgentCLI,
-		"model", e.config.Model,
-		"timeout", e.config.Timeout)
+		"model", model,
+		"timeout", timeout,
+		"agent_profile", profile.Name)
 
 	// Capture the combined prompt to prompt-sent.md before execution
-	if err := e.capturePrompt(workspacePath, incidentID, prompt); err != nil {
-		slog.Warn("failed to capture prompt for audit", "error", err)
+	if err := e.capturePrompt(workspacePath, incidentID, prompt, model); err != nil {
+		log.Warn("failed to capture prompt for audit", "error", err)
 		// Continue execution - prompt capture failure is not fatal
 	}
 
@@ -253,9 +487,9 @@ func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string,
 	// Build command args for run-agent.sh
 	args := []string{
 		"--workspace", workspacePath,
-		"--model", e.config.Model,
+		"--model", model,
 		"--allowed-tools", e.config.AllowedTools,
-		"--timeout", fmt.Sprintf("%d", e.config.Timeout),
+		"--timeout", fmt.Sprintf("%d", timeout),
 	}
 
 	// Add agent CLI selection if specified
@@ -292,7 +526,7 @@ func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string,
 	args = append(args, combinedPrompt)
 
 	// Create context with timeout using configured buffer from TuningConfig
-	timeoutWithBuffer := e.config.Timeout + e.tuning.Agent.TimeoutBufferSeconds
+	timeoutWithBuffer := timeout + e.currentTuning().Agent.TimeoutBufferSeconds
 	execCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutWithBuffer)*time.Second)
 	defer cancel()
 
@@ -309,20 +543,33 @@ func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string,
 		fmt.Sprintf("INCIDENT_ID=%s", incidentID),
 		fmt.Sprintf("AGENT_CLI=%s", e.config.AgentCLI),
 		fmt.Sprintf("AGENT_IMAGE=%s", e.config.AgentImage),
-		fmt.Sprintf("LLM_MODEL=%s", e.config.Model),
+		fmt.Sprintf("LLM_MODEL=%s", model),
 		fmt.Sprintf("AGENT_ALLOWED_TOOLS=%s", e.config.AllowedTools),
-		fmt.Sprintf("CONTAINER_TIMEOUT=%d", e.config.Timeout),
+		fmt.Sprintf("CONTAINER_TIMEOUT=%d", timeout),
 		fmt.Sprintf("OUTPUT_FORMAT=%s", "text"),
 		fmt.Sprintf("CONTAINER_NETWORK=%s", "host"),
 	)
 
+	// Limit conversation turns for the agent profile
+	if profile.MaxTurns > 0 {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("AGENT_MAX_TURNS=%d", profile.MaxTurns))
+	}
+
 	// Enable debug output in run-agent.sh when running in debug mode
 	if e.config.Debug {
 		cmd.Env = append(cmd.Env, "DEBUG=true")
 	}
 
+	// Stream structured output so progress can be reported while the agent runs.
+	// Claude only emits stream-json in verbose mode.
+	progress := progressFromContext(ctx)
+	streaming := progress != nil && e.streamsProgress()
+	if streaming {
+		cmd.Env = append(cmd.Env, "OUTPUT_FORMAT=stream-json")
+	}
+
 	// Enable verbose agent output (shows thinking and tool usage)
-	if e.config.Verbose {
+	if e.config.Verbose || streaming {
 		cmd.Env = append(cmd.Env, "AGENT_VERBOSE=true")
 	}
 
@@ -332,13 +579,53 @@ func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string,
 	}
 
 	// Skills configuration for context preloading
-	if e.config.SkillsCacheDir != "" {
+	if e.config.SkillsCacheDir != "" && (e.config.SelectedSkills == nil || len(e.config.SelectedSkills) > 0) {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("SKILLS_DIR=%s", e.config.SkillsCacheDir))
 	}
-	if e.config.DisableTriagePreload {
+	if len(e.config.SelectedSkills) > 0 {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("SKILLS_SELECTED=%s", strings.Join(e.config.SelectedSkills, ",")))
+	}
+	if e.config.DisableTriagePreload || !e.skillSelected(skills.K8sSkillName) {
 		cmd.Env = append(cmd.Env, "DISABLE_TRIAGE_PRELOAD=true")
 	}
 
+	// Proxy settings for LLM API calls (forwarded into the agent container by run-agent.sh)
+	cmd.Env = append(cmd.Env, e.config.ProxyEnv...)
+
+	// Self-hosted or managed-cloud LLM provider (mapped to the agent CLI's settings by run-agent.sh)
+	cmd.Env = append(cmd.Env, e.config.LLMProviderEnv...)
+
+	// Least-privilege container settings (applied by run-agent.sh)
+	cmd.Env = append(cmd.Env, e.config.HardeningEnv...)
+
+	// Egress control: an internal network and the allow-listing proxy, replacing the
+	// host network and the LLM proxy settings above
+	cmd.Env = append(cmd.Env, e.config.EgressEnv...)
+
+	// Air-gapped mode: no update checks or telemetry from inside the container
+	if e.config.Offline {
+		cmd.Env = append(cmd.Env, "AGENT_OFFLINE=true")
+	}
+
+	// Resource sampling reads the container's cgroup, found through the container ID
+	// run-agent.sh writes to CONTAINER_CIDFILE (docker refuses an existing file)
+	usageSink := resourceUsageFromContext(ctx)
+	sampleInterval := time.Duration(e.currentTuning().Agent.ResourceSampleIntervalSeconds) * time.Second
+	sampling := usageSink != nil && sampleInterval > 0
+	var cidFile string
+	if sampling {
+		if cidDir, err := os.MkdirTemp("", "nightcrier-cid-"); err != nil {
+			log.Warn("failed to create container ID directory, sampling agent processes only", "error", err)
+		} else {
+			defer os.RemoveAll(cidDir)
+			cidFile = filepath.Join(cidDir, "container.id")
+			cmd.Env = append(cmd.Env, fmt.Sprintf("CONTAINER_CIDFILE=%s", cidFile))
+		}
+	}
+
+	// Per-run settings (e.g. the API key selected for this investigation) win over the above
+	cmd.Env = append(cmd.Env, envFromContext(ctx)...)
+
 	// Capture stdout and stderr
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -365,6 +652,17 @@ func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string,
 		stdoutDest = io.Discard
 		stderrDest = io.Discard
 	}
+	if streaming {
+		stdoutDest = io.MultiWriter(stdoutDest, &progressWriter{parser: NewProgressParser(), report: progress})
+	}
+	// Keep the tail of the output for a debug bundle, should the agent crash
+	var stdoutTail, stderrTail *tailBuffer
+	if e.config.DebugBundleTailBytes > 0 {
+		stdoutTail = newTailBuffer(e.config.DebugBundleTailBytes)
+		stderrTail = newTailBuffer(e.config.DebugBundleTailBytes)
+		stdoutDest = io.MultiWriter(stdoutDest, stdoutTail)
+		stderrDest = io.MultiWriter(stderrDest, stderrTail)
+	}
 	stdoutTee := io.TeeReader(stdout, stdoutDest)
 	stderrTee := io.TeeReader(stderr, stderrDest)
 
@@ -375,11 +673,11 @@ func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string,
 
 	go func() {
 		defer wg.Done()
-		buf := make([]byte, e.tuning.IO.StdoutBufferSize)
+		buf := make([]byte, e.currentTuning().IO.StdoutBufferSize)
 		for {
 			n, err := stdoutTee.Read(buf)
 			if n > 0 {
-				slog.Info("agent stdout", "output", string(buf[:n]))
+				log.Info("agent stdout", "output", string(buf[:n]))
 			}
 			if err != nil {
 				break
@@ -389,11 +687,11 @@ func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string,
 
 	go func() {
 		defer wg.Done()
-		buf := make([]byte, e.tuning.IO.StderrBufferSize)
+		buf := make([]byte, e.currentTuning().IO.StderrBufferSize)
 		for {
 			n, err := stderrTee.Read(buf)
 			if n > 0 {
-				slog.Warn("agent stderr", "output", string(buf[:n]))
+				log.Warn("agent stderr", "output", string(buf[:n]))
 			}
 			if err != nil {
 				break
@@ -401,9 +699,42 @@ func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string,
 		}
 	}()
 
+	// Sample resource usage while the agent runs
+	var sampler *resourceSampler
+	stopSampling := func() {}
+	if sampling {
+		sampler = newResourceSampler(cmd.Process.Pid, cidFile)
+		samplingCtx, cancelSampling := context.WithCancel(ctx)
+		samplingDone := make(chan struct{})
+		go func() {
+			defer close(samplingDone)
+			sampler.run(samplingCtx, sampleInterval)
+		}()
+		stopSampling = func() {
+			cancelSampling()
+			<-samplingDone
+		}
+	}
+
 	// Wait for output goroutines to finish reading
 	wg.Wait()
 
+	// Take a last sample before the agent process is reaped
+	stopSampling()
+	var runUsage *ResourceUsage
+	if sampler != nil {
+		sampler.sample()
+		usage := sampler.usage()
+		runUsage = &usage
+		usageSink.Add(usage)
+		log.Info("agent resource usage",
+			"cpu_seconds", usage.CPUSeconds,
+			"peak_rss_bytes", usage.PeakRSSBytes,
+			"peak_processes", usage.PeakProcesses,
+			"disk_written_bytes", usage.DiskWrittenBytes,
+			"source", usage.Source)
+	}
+
 	// Wait for the command to complete
 	err = cmd.Wait()
 
@@ -412,7 +743,7 @@ func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string,
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			exitCode = exitErr.ExitCode()
-			slog.Info("agent script exited with non-zero code",
+			log.Info("agent script exited with non-zero code",
 				"exit_code", exitCode,
 				"error", err)
 		} else {
@@ -420,7 +751,21 @@ func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string,
 		}
 	}
 
-	slog.Info("agent script completed", "exit_code", exitCode)
+	// Capture a debug bundle when the agent crashed or was killed (not when it was
+	// stopped at its timeout)
+	if signal := crashSignal(exitCode, cmd.ProcessState); signal != "" && stdoutTail != nil && execCtx.Err() == nil {
+		bundle := e.captureDebugBundle(ctx, incidentID, exitCode, signal, cmd, stdoutTail, stderrTail, runUsage)
+		if err := bundle.Write(workspacePath); err != nil {
+			log.Warn("failed to write agent debug bundle", "error", err)
+		} else {
+			log.Warn("agent crashed, debug bundle captured",
+				"signal", signal,
+				"path", filepath.Join(workspacePath, DebugBundleFile),
+				"kernel_log_lines", len(bundle.KernelLog))
+		}
+	}
+
+	log.Info("agent script completed", "exit_code", exitCode)
 	if logCapture != nil {
 		return exitCode, logCapture.GetLogPaths(), nil
 	}
@@ -428,8 +773,9 @@ func (e *Executor) ExecuteWithPrompt(ctx context.Context, workspacePath string,
 }
 
 // capturePrompt writes the combined system + additional prompt to prompt-sent.md
-// for auditability and debugging. This is called before subprocess launch.
-func (e *Executor) capturePrompt(workspacePath string, incidentID string, additionalPrompt string) error {
+// for auditability and debugging, along with the model used for the run. This is
+// called before subprocess launch.
+func (e *Executor) capturePrompt(workspacePath string, incidentID string, additionalPrompt string, model string) error {
 	// Read system prompt file content
 	systemPromptContent, err := e.readSystemPromptFile()
 	if err != nil {
@@ -437,7 +783,7 @@ func (e *Executor) capturePrompt(workspacePath string, incidentID string, additi
 	}
 
 	// Generate the prompt-sent.md content
-	content := e.generatePromptSentContent(incidentID, systemPromptContent, additionalPrompt)
+	content := e.generatePromptSentContent(incidentID, systemPromptContent, additionalPrompt, model)
 
 	// Write to workspace
 	promptPath := filepath.Join(workspacePath, "prompt-sent.md")
@@ -465,7 +811,7 @@ func (e *Executor) readSystemPromptFile() (string, error) {
 }
 
 // generatePromptSentContent creates the markdown content for prompt-sent.md
-func (e *Executor) generatePromptSentContent(incidentID string, systemPrompt string, additionalPrompt string) string {
+func (e *Executor) generatePromptSentContent(incidentID string, systemPrompt string, additionalPrompt string, model string) string {
 	timestamp := time.Now().UTC().Format(time.RFC3339)
 
 	// Extract cluster name from kubeconfig path if available
@@ -495,7 +841,7 @@ func (e *Executor) generatePromptSentContent(incidentID string, systemPrompt str
 	content += fmt.Sprintf("- Incident ID: %s\n", incidentID)
 	content += fmt.Sprintf("- Cluster: %s\n", clusterName)
 	content += fmt.Sprintf("- Agent CLI: %s\n", e.config.AgentCLI)
-	content += fmt.Sprintf("- Model: %s\n", e.config.Model)
+	content += fmt.Sprintf("- Model: %s\n", model)
 	content += "\n"
 
 	content += "## System Prompt\n\n"
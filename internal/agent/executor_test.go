@@ -4,9 +4,11 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
 )
 
 // Helper function to create a test tuning config
@@ -435,3 +437,121 @@ func TestExecutorConfig_AllFieldsExplicit(t *testing.T) {
 		t.Error("ExecutorConfig.DisableTriagePreload should have no default")
 	}
 }
+
+func TestCombinePrompt(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections []string
+		want     string
+	}{
+		{"facts and prompt", []string{"## Incident Facts\n- a\n", "Focus on memory"}, "## Incident Facts\n- a\n\nFocus on memory"},
+		{"facts only", []string{"## Incident Facts", ""}, "## Incident Facts"},
+		{"prompt only", []string{"", "Focus on memory"}, "Focus on memory"},
+		{"empty", []string{"", "  "}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := combinePrompt(tt.sections...); got != tt.want {
+				t.Errorf("combinePrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguageInstruction(t *testing.T) {
+	if got := languageInstruction(""); got != "" {
+		t.Errorf("languageInstruction(\"\") = %q, want empty for English", got)
+	}
+
+	got := languageInstruction("Japanese")
+	if !strings.Contains(got, "Write output/investigation.md in Japanese") {
+		t.Errorf("instruction should name the language, got %q", got)
+	}
+	if !strings.Contains(got, "## Root Cause") {
+		t.Errorf("instruction should keep report headings in English, got %q", got)
+	}
+}
+
+func TestExecutor_SkillSelected(t *testing.T) {
+	all := NewExecutorWithConfig(ExecutorConfig{}, createTestTuning())
+	if !all.skillSelected("k8s4agents") {
+		t.Error("without a selection every skill should be mounted")
+	}
+
+	selected := NewExecutorWithConfig(ExecutorConfig{SelectedSkills: []string{"aws-helpers"}}, createTestTuning())
+	if selected.skillSelected("k8s4agents") || !selected.skillSelected("aws-helpers") {
+		t.Error("only the selected skills should be mounted")
+	}
+}
+
+func TestExecutor_TimeoutForSeverity(t *testing.T) {
+	e := NewExecutorWithConfig(ExecutorConfig{
+		Timeout:          300,
+		SeverityTimeouts: map[string]int{"CRITICAL": 1200, "WARNING": 120},
+	}, createTestTuning())
+
+	tests := []struct {
+		severity string
+		want     int
+	}{
+		{"CRITICAL", 1200},
+		{"critical", 1200},
+		{"WARNING", 120},
+		{"ERROR", 300},
+		{"", 300},
+	}
+	for _, tt := range tests {
+		ctx := incident.WithContext(context.Background(), &incident.IncidentContext{IncidentID: "inc-1", Severity: tt.severity})
+		if got := e.timeoutFor(ctx); got != tt.want {
+			t.Errorf("timeoutFor(severity %q) = %d, want %d", tt.severity, got, tt.want)
+		}
+	}
+
+	if got := e.timeoutFor(context.Background()); got != 300 {
+		t.Errorf("timeoutFor() without an incident = %d, want 300", got)
+	}
+}
+
+func TestExecutor_ProfileOverrides(t *testing.T) {
+	e := NewExecutorWithConfig(ExecutorConfig{
+		Model:            "sonnet",
+		Timeout:          300,
+		SeverityTimeouts: map[string]int{"CRITICAL": 1200},
+	}, createTestTuning())
+	ctx := incident.WithContext(context.Background(), &incident.IncidentContext{IncidentID: "inc-1", Severity: "CRITICAL"})
+
+	if got := e.modelFor(ctx); got != "sonnet" {
+		t.Errorf("modelFor() without a profile = %q, want sonnet", got)
+	}
+
+	deep := WithProfile(ctx, Profile{Name: "deep", Model: "opus", Timeout: 1800})
+	if got := e.modelFor(deep); got != "opus" {
+		t.Errorf("modelFor() = %q, want opus", got)
+	}
+	if got := e.timeoutFor(deep); got != 1800 {
+		t.Errorf("timeoutFor() = %d, want the profile timeout 1800", got)
+	}
+
+	// A profile without a timeout keeps the severity timeout
+	fast := WithProfile(ctx, Profile{Name: "fast", Model: "haiku"})
+	if got := e.timeoutFor(fast); got != 1200 {
+		t.Errorf("timeoutFor() = %d, want the severity timeout 1200", got)
+	}
+}
+
+func TestPromptVersion(t *testing.T) {
+	if got := PromptVersion("", ""); got != "" {
+		t.Errorf("PromptVersion() without prompts = %q, want empty", got)
+	}
+	v := PromptVersion("Investigate the fault.", "")
+	if !strings.HasPrefix(v, "sha256:") || len(v) != len("sha256:")+12 {
+		t.Errorf("PromptVersion() = %q, want sha256: and 12 hex digits", v)
+	}
+	if v != PromptVersion("Investigate the fault.", "") {
+		t.Error("PromptVersion() is not stable")
+	}
+	// Moving text between the two prompts is a different version
+	if PromptVersion("a", "b") == PromptVersion("ab", "") || PromptVersion("a", "b") == v {
+		t.Error("PromptVersion() does not distinguish different prompts")
+	}
+}
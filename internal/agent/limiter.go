@@ -0,0 +1,157 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ConcurrencyLimiter bounds the number of agents running at once and reserves a
+// portion of the capacity for high-priority (e.g. CRITICAL) incidents.
+//
+// Normal incidents may use at most total-reserved slots; priority incidents may use
+// every slot. When a slot frees up, waiting priority incidents are admitted first,
+// so a flood of low-severity investigations never delays a CRITICAL one.
+type ConcurrencyLimiter struct {
+	mu       sync.Mutex
+	total    int
+	reserved int
+	running  int
+	waiting  []*limiterWaiter
+}
+
+// limiterWaiter is a queued Acquire call.
+type limiterWaiter struct {
+	priority bool
+	ready    chan struct{}
+}
+
+// LimiterStats is a point-in-time snapshot of limiter usage.
+type LimiterStats struct {
+	Total           int `json:"total"`
+	Reserved        int `json:"reserved"`
+	Running         int `json:"running"`
+	WaitingNormal   int `json:"waiting_normal"`
+	WaitingPriority int `json:"waiting_priority"`
+}
+
+// NewConcurrencyLimiter creates a limiter with total slots, of which reserved are
+// kept free for priority acquisitions. reserved must be less than total.
+func NewConcurrencyLimiter(total, reserved int) (*ConcurrencyLimiter, error) {
+	if total < 1 {
+		return nil, fmt.Errorf("total concurrency must be >= 1, got %d", total)
+	}
+	if reserved < 0 || reserved >= total {
+		return nil, fmt.Errorf("reserved slots must be >= 0 and < total (%d), got %d", total, reserved)
+	}
+	return &ConcurrencyLimiter{total: total, reserved: reserved}, nil
+}
+
+// canRunLocked reports whether a new acquisition of the given class fits. Caller holds mu.
+func (l *ConcurrencyLimiter) canRunLocked(priority bool) bool {
+	if priority {
+		return l.running < l.total
+	}
+	return l.running < l.total-l.reserved
+}
+
+// Acquire blocks until a slot is available for the given priority class or ctx is done.
+// The returned release function must be called exactly once when the agent finishes.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context, priority bool) (func(), error) {
+	l.mu.Lock()
+	// Only bypass the queue if no one of equal or higher priority is already waiting
+	if l.canRunLocked(priority) && !l.hasWaitersLocked(priority) {
+		l.running++
+		l.mu.Unlock()
+		return l.releaseFunc(), nil
+	}
+
+	w := &limiterWaiter{priority: priority, ready: make(chan struct{})}
+	l.waiting = append(l.waiting, w)
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return l.releaseFunc(), nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		select {
+		case <-w.ready:
+			// Granted concurrently with cancellation - give the slot back
+			l.running--
+			l.admitLocked()
+		default:
+			l.removeWaiterLocked(w)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// hasWaitersLocked reports whether any waiter would be served before a new acquisition.
+func (l *ConcurrencyLimiter) hasWaitersLocked(priority bool) bool {
+	for _, w := range l.waiting {
+		if w.priority || !priority {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseFunc returns an idempotent function releasing one slot.
+func (l *ConcurrencyLimiter) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.running--
+			l.admitLocked()
+		})
+	}
+}
+
+// admitLocked grants slots to waiters, priority waiters first, in FIFO order within a class.
+func (l *ConcurrencyLimiter) admitLocked() {
+	for _, priority := range []bool{true, false} {
+		for i := 0; i < len(l.waiting); {
+			w := l.waiting[i]
+			if w.priority != priority {
+				i++
+				continue
+			}
+			if !l.canRunLocked(priority) {
+				break
+			}
+			l.running++
+			close(w.ready)
+			l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+		}
+	}
+}
+
+// removeWaiterLocked removes a waiter from the queue.
+func (l *ConcurrencyLimiter) removeWaiterLocked(target *limiterWaiter) {
+	for i, w := range l.waiting {
+		if w == target {
+			l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+			return
+		}
+	}
+}
+
+// Stats returns a snapshot of current limiter usage.
+func (l *ConcurrencyLimiter) Stats() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := LimiterStats{Total: l.total, Reserved: l.reserved, Running: l.running}
+	for _, w := range l.waiting {
+		if w.priority {
+			stats.WaitingPriority++
+		} else {
+			stats.WaitingNormal++
+		}
+	}
+	return stats
+}
@@ -0,0 +1,179 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewConcurrencyLimiter_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		reserved int
+		wantErr  bool
+	}{
+		{"valid without reservation", 5, 0, false},
+		{"valid with reservation", 5, 2, false},
+		{"zero total", 0, 0, true},
+		{"negative reserved", 5, -1, true},
+		{"reserved equals total", 3, 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConcurrencyLimiter(tt.total, tt.reserved)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewConcurrencyLimiter(%d, %d) error = %v, wantErr %v", tt.total, tt.reserved, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// acquireAsync starts an Acquire in a goroutine and returns a channel receiving its release func.
+func acquireAsync(t *testing.T, l *ConcurrencyLimiter, priority bool) <-chan func() {
+	t.Helper()
+	ch := make(chan func(), 1)
+	go func() {
+		release, err := l.Acquire(context.Background(), priority)
+		if err != nil {
+			t.Errorf("Acquire() error = %v", err)
+			return
+		}
+		ch <- release
+	}()
+	return ch
+}
+
+func waitForWaiters(t *testing.T, l *ConcurrencyLimiter, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		stats := l.Stats()
+		if stats.WaitingNormal+stats.WaitingPriority == n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d waiters, stats = %+v", n, l.Stats())
+}
+
+func TestConcurrencyLimiter_ReservedSlotsOnlyForPriority(t *testing.T) {
+	l, err := NewConcurrencyLimiter(3, 1)
+	if err != nil {
+		t.Fatalf("NewConcurrencyLimiter() error = %v", err)
+	}
+	ctx := context.Background()
+
+	// Two normal acquisitions fill the unreserved capacity
+	for i := 0; i < 2; i++ {
+		if _, err := l.Acquire(ctx, false); err != nil {
+			t.Fatalf("Acquire(normal) error = %v", err)
+		}
+	}
+
+	// A third normal acquisition must not take the reserved slot
+	normal := acquireAsync(t, l, false)
+	waitForWaiters(t, l, 1)
+
+	// A priority acquisition gets the reserved slot immediately
+	shortCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if _, err := l.Acquire(shortCtx, true); err != nil {
+		t.Fatalf("Acquire(priority) should use reserved slot, error = %v", err)
+	}
+
+	select {
+	case <-normal:
+		t.Fatal("normal acquisition should still be waiting")
+	default:
+	}
+
+	if got := l.Stats().Running; got != 3 {
+		t.Errorf("Running = %d, want 3", got)
+	}
+}
+
+func TestConcurrencyLimiter_PriorityAdmittedFirst(t *testing.T) {
+	l, err := NewConcurrencyLimiter(1, 0)
+	if err != nil {
+		t.Fatalf("NewConcurrencyLimiter() error = %v", err)
+	}
+
+	release, err := l.Acquire(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	normal := acquireAsync(t, l, false)
+	waitForWaiters(t, l, 1)
+	priority := acquireAsync(t, l, true)
+	waitForWaiters(t, l, 2)
+
+	release()
+
+	var releasePriority func()
+	select {
+	case releasePriority = <-priority:
+	case <-time.After(2 * time.Second):
+		t.Fatal("priority waiter was not admitted")
+	}
+	select {
+	case <-normal:
+		t.Fatal("normal waiter admitted before priority waiter finished")
+	default:
+	}
+
+	releasePriority()
+	select {
+	case r := <-normal:
+		r()
+	case <-time.After(2 * time.Second):
+		t.Fatal("normal waiter was not admitted")
+	}
+
+	if stats := l.Stats(); stats.Running != 0 {
+		t.Errorf("Running = %d, want 0", stats.Running)
+	}
+}
+
+func TestConcurrencyLimiter_ContextCancel(t *testing.T) {
+	l, err := NewConcurrencyLimiter(1, 0)
+	if err != nil {
+		t.Fatalf("NewConcurrencyLimiter() error = %v", err)
+	}
+
+	if _, err := l.Acquire(context.Background(), false); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx, true); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	stats := l.Stats()
+	if stats.WaitingPriority != 0 || stats.Running != 1 {
+		t.Errorf("stats after cancel = %+v, want no waiters and 1 running", stats)
+	}
+}
+
+func TestConcurrencyLimiter_ReleaseIdempotent(t *testing.T) {
+	l, err := NewConcurrencyLimiter(2, 0)
+	if err != nil {
+		t.Fatalf("NewConcurrencyLimiter() error = %v", err)
+	}
+
+	release, err := l.Acquire(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	release()
+	release()
+
+	if got := l.Stats().Running; got != 0 {
+		t.Errorf("Running = %d, want 0", got)
+	}
+}
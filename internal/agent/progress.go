@@ -0,0 +1,307 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressKind classifies a progress event parsed from the agent's output stream.
+type ProgressKind string
+
+const (
+	// ProgressToolStart is emitted when the agent calls a tool (runs a command,
+	// reads a file, ...)
+	ProgressToolStart ProgressKind = "tool_start"
+	// ProgressToolFinish is emitted when a tool call returns
+	ProgressToolFinish ProgressKind = "tool_finish"
+	// ProgressThinking is emitted when the agent reasons about its findings
+	ProgressThinking ProgressKind = "thinking"
+)
+
+// ProgressEvent describes one step of a running investigation.
+type ProgressEvent struct {
+	Kind ProgressKind
+	// Tool is the tool name for tool events, e.g. "Bash" or "Read"
+	Tool string
+	// Summary is a short human-readable description of the step, e.g.
+	// "checking pod logs" or "inspecting node"
+	Summary string
+	// Failed is set on tool_finish events when the tool reported an error
+	Failed bool
+	Time   time.Time
+}
+
+// ProgressFunc receives progress events while the agent runs. It is called from
+// the goroutine reading the agent's output and must not block.
+type ProgressFunc func(ProgressEvent)
+
+// progressContextKey is the unexported context key for the progress callback.
+type progressContextKey struct{}
+
+// WithProgress returns a copy of ctx carrying a callback for progress events of
+// the agent run. Progress is only parsed when the executor streams structured
+// output (see ExecutorConfig.StreamProgress).
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressContextKey{}, fn)
+}
+
+// progressFromContext returns the progress callback stored in ctx, if any.
+func progressFromContext(ctx context.Context) ProgressFunc {
+	fn, _ := ctx.Value(progressContextKey{}).(ProgressFunc)
+	return fn
+}
+
+// streamMessage is the subset of a Claude stream-json line used for progress.
+type streamMessage struct {
+	Type    string `json:"type"`
+	Message struct {
+		Content []streamContent `json:"content"`
+	} `json:"message"`
+}
+
+// streamContent is a content block of an assistant or user message.
+type streamContent struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	IsError   bool            `json:"is_error"`
+}
+
+// toolInput holds the tool input fields used to describe a tool call.
+type toolInput struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+	FilePath    string `json:"file_path"`
+	Pattern     string `json:"pattern"`
+	Skill       string `json:"skill"`
+}
+
+// ProgressParser turns the agent CLI's structured output stream (one JSON object
+// per line, as produced by "claude --output-format stream-json") into progress
+// events. Lines that are not JSON or carry nothing of interest are ignored, so
+// interleaved plain-text output from the wrapper script is harmless.
+type ProgressParser struct {
+	// tools maps tool call IDs to their start events so finish events can say
+	// which step completed
+	tools map[string]ProgressEvent
+	now   func() time.Time
+}
+
+// NewProgressParser creates a parser for one agent run.
+func NewProgressParser() *ProgressParser {
+	return &ProgressParser{
+		tools: make(map[string]ProgressEvent),
+		now:   time.Now,
+	}
+}
+
+// ParseLine returns the progress events carried by one line of agent output.
+func (p *ProgressParser) ParseLine(line []byte) []ProgressEvent {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return nil
+	}
+	var msg streamMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return nil
+	}
+
+	var events []ProgressEvent
+	for _, block := range msg.Message.Content {
+		switch {
+		case msg.Type == "assistant" && block.Type == "tool_use":
+			ev := ProgressEvent{
+				Kind:    ProgressToolStart,
+				Tool:    block.Name,
+				Summary: describeToolCall(block.Name, block.Input),
+				Time:    p.now(),
+			}
+			p.tools[block.ID] = ev
+			events = append(events, ev)
+
+		case msg.Type == "assistant" && block.Type == "thinking":
+			events = append(events, ProgressEvent{
+				Kind:    ProgressThinking,
+				Summary: "analyzing findings",
+				Time:    p.now(),
+			})
+
+		case msg.Type == "user" && block.Type == "tool_result":
+			start, ok := p.tools[block.ToolUseID]
+			if !ok {
+				continue
+			}
+			delete(p.tools, block.ToolUseID)
+			events = append(events, ProgressEvent{
+				Kind:    ProgressToolFinish,
+				Tool:    start.Tool,
+				Summary: start.Summary,
+				Failed:  block.IsError,
+				Time:    p.now(),
+			})
+		}
+	}
+	return events
+}
+
+// describeToolCall summarizes a tool call for humans, e.g. "checking pod logs".
+func describeToolCall(tool string, raw json.RawMessage) string {
+	var input toolInput
+	_ = json.Unmarshal(raw, &input)
+
+	switch tool {
+	case "Bash":
+		if summary := describeCommand(input.Command); summary != "" {
+			return summary
+		}
+		if input.Description != "" {
+			return strings.ToLower(input.Description[:1]) + input.Description[1:]
+		}
+		return "running a command"
+	case "Read":
+		if input.FilePath != "" {
+			return "reading " + filepath.Base(input.FilePath)
+		}
+		return "reading a file"
+	case "Write", "Edit":
+		if filepath.Base(input.FilePath) == "investigation.md" {
+			return "writing the report"
+		}
+		return "writing " + filepath.Base(input.FilePath)
+	case "Grep", "Glob":
+		return "searching files"
+	case "Skill":
+		if input.Skill != "" {
+			return "loading skill " + input.Skill
+		}
+		return "loading a skill"
+	default:
+		return "using " + tool
+	}
+}
+
+// kubectlResourceNames normalizes kubectl resource arguments to readable nouns.
+var kubectlResourceNames = map[string]string{
+	"po": "pod", "pod": "pod", "pods": "pods",
+	"no": "node", "node": "node", "nodes": "nodes",
+	"deploy": "deployment", "deployment": "deployment", "deployments": "deployments",
+	"svc": "service", "service": "service", "services": "services",
+	"ev": "events", "event": "events", "events": "events",
+	"pvc": "volume claim", "persistentvolumeclaim": "volume claim", "persistentvolumeclaims": "volume claims",
+	"rs": "replica set", "replicaset": "replica set", "replicasets": "replica sets",
+	"sts": "stateful set", "statefulset": "stateful set", "statefulsets": "stateful sets",
+	"ds": "daemon set", "daemonset": "daemon set", "daemonsets": "daemon sets",
+	"cm": "config map", "configmap": "config map", "configmaps": "config maps",
+	"ns": "namespace", "namespace": "namespace", "namespaces": "namespaces",
+	"ing": "ingress", "ingress": "ingress", "ingresses": "ingresses",
+	"hpa": "autoscaler", "horizontalpodautoscaler": "autoscaler",
+}
+
+// describeCommand summarizes common kubectl commands, or returns "" for commands
+// it does not recognize.
+func describeCommand(command string) string {
+	fields := strings.Fields(command)
+	for i, field := range fields {
+		if filepath.Base(field) != "kubectl" {
+			continue
+		}
+
+		// Positional arguments after kubectl, skipping flags and their values
+		var args []string
+		for j := i + 1; j < len(fields) && len(args) < 2; j++ {
+			f := fields[j]
+			if f == "|" || f == "&&" || f == ";" {
+				break
+			}
+			if strings.HasPrefix(f, "-") {
+				if !strings.Contains(f, "=") && (f == "-n" || f == "--namespace" || f == "-l" || f == "--selector" || f == "-o" || f == "--output" || f == "-c" || f == "--container" || f == "--context") {
+					j++
+				}
+				continue
+			}
+			args = append(args, f)
+		}
+		if len(args) == 0 {
+			return ""
+		}
+
+		resource := ""
+		if len(args) > 1 {
+			kind, _, _ := strings.Cut(strings.ToLower(args[1]), "/")
+			resource = kubectlResourceNames[kind]
+			if resource == "" {
+				resource = kind
+			}
+		}
+
+		switch args[0] {
+		case "logs":
+			return "checking pod logs"
+		case "describe":
+			if resource == "" {
+				return "inspecting resources"
+			}
+			return "inspecting " + resource
+		case "get":
+			if resource == "events" {
+				return "reviewing events"
+			}
+			if resource == "" {
+				return "listing resources"
+			}
+			return "listing " + resource
+		case "top":
+			return "checking resource usage"
+		case "auth":
+			return "checking permissions"
+		case "rollout":
+			return "checking rollout status"
+		default:
+			return "running kubectl " + args[0]
+		}
+	}
+	return ""
+}
+
+// progressWriter splits the agent's stdout into lines and reports the progress
+// events they carry. It is used as a tee destination, so it never fails a write.
+type progressWriter struct {
+	parser *ProgressParser
+	report ProgressFunc
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// maxProgressLineBytes bounds the buffered partial line; stream-json lines with
+// large tool results beyond this are dropped rather than buffered indefinitely
+const maxProgressLineBytes = 1 << 20
+
+// Write implements io.Writer.
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		for _, ev := range w.parser.ParseLine(w.buf[:i]) {
+			w.report(ev)
+		}
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) > maxProgressLineBytes {
+		w.buf = nil
+	}
+	return len(p), nil
+}
@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProgressParser_ToolCalls(t *testing.T) {
+	p := NewProgressParser()
+
+	start := p.ParseLine([]byte(`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"toolu_1","name":"Bash","input":{"command":"kubectl logs -n prod api-7d9 --tail=100"}}]}}`))
+	if len(start) != 1 {
+		t.Fatalf("ParseLine(tool_use) returned %d events, want 1", len(start))
+	}
+	if start[0].Kind != ProgressToolStart || start[0].Tool != "Bash" || start[0].Summary != "checking pod logs" {
+		t.Errorf("tool_use event = %+v", start[0])
+	}
+
+	finish := p.ParseLine([]byte(`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","is_error":true}]}}`))
+	if len(finish) != 1 {
+		t.Fatalf("ParseLine(tool_result) returned %d events, want 1", len(finish))
+	}
+	if finish[0].Kind != ProgressToolFinish || finish[0].Summary != "checking pod logs" || !finish[0].Failed {
+		t.Errorf("tool_result event = %+v", finish[0])
+	}
+
+	// A result for an unknown tool call is ignored
+	if evs := p.ParseLine([]byte(`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1"}]}}`)); len(evs) != 0 {
+		t.Errorf("duplicate tool_result produced events: %+v", evs)
+	}
+}
+
+func TestProgressParser_IgnoresOtherLines(t *testing.T) {
+	p := NewProgressParser()
+	lines := []string{
+		"",
+		"[INFO] starting agent container",
+		`{"type":"system","subtype":"init","tools":["Bash"]}`,
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"Looking at the pod"}]}}`,
+		`{"type":"result","subtype":"success"}`,
+		`{not json`,
+	}
+	for _, line := range lines {
+		if evs := p.ParseLine([]byte(line)); len(evs) != 0 {
+			t.Errorf("ParseLine(%q) = %+v, want no events", line, evs)
+		}
+	}
+
+	thinking := p.ParseLine([]byte(`{"type":"assistant","message":{"content":[{"type":"thinking","thinking":"the node is under memory pressure"}]}}`))
+	if len(thinking) != 1 || thinking[0].Kind != ProgressThinking {
+		t.Errorf("thinking block = %+v, want one thinking event", thinking)
+	}
+}
+
+func TestDescribeToolCall(t *testing.T) {
+	tests := []struct {
+		tool  string
+		input string
+		want  string
+	}{
+		{"Bash", `{"command":"kubectl describe node worker-3"}`, "inspecting node"},
+		{"Bash", `{"command":"kubectl -n prod describe po/api-7d9"}`, "inspecting pod"},
+		{"Bash", `{"command":"kubectl get events -n prod --sort-by=.lastTimestamp"}`, "reviewing events"},
+		{"Bash", `{"command":"kubectl get deploy -o yaml api"}`, "listing deployment"},
+		{"Bash", `{"command":"kubectl top pods -n prod"}`, "checking resource usage"},
+		{"Bash", `{"command":"KUBECONFIG=/k kubectl --context prod rollout status deploy/api"}`, "checking rollout status"},
+		{"Bash", `{"command":"curl -s http://api/healthz","description":"Check the health endpoint"}`, "check the health endpoint"},
+		{"Bash", `{"command":"ls /tmp"}`, "running a command"},
+		{"Read", `{"file_path":"/home/agent/incident.json"}`, "reading incident.json"},
+		{"Write", `{"file_path":"/home/agent/output/investigation.md"}`, "writing the report"},
+		{"Grep", `{"pattern":"OOMKilled"}`, "searching files"},
+		{"Skill", `{"skill":"k8s-troubleshooter"}`, "loading skill k8s-troubleshooter"},
+		{"WebFetch", `{}`, "using WebFetch"},
+	}
+	for _, tt := range tests {
+		if got := describeToolCall(tt.tool, []byte(tt.input)); got != tt.want {
+			t.Errorf("describeToolCall(%s, %s) = %q, want %q", tt.tool, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestProgressWriter_SplitsLines(t *testing.T) {
+	var got []string
+	w := &progressWriter{parser: NewProgressParser(), report: func(ev ProgressEvent) { got = append(got, ev.Summary) }}
+
+	stream := `{"type":"assistant","message":{"content":[{"type":"tool_use","id":"a","name":"Bash","input":{"command":"kubectl logs api"}}]}}` + "\n" +
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"b","name":"Bash","input":{"command":"kubectl describe node n1"}}]}}` + "\n"
+
+	// Deliver the stream in small chunks that split lines
+	for i := 0; i < len(stream); i += 17 {
+		end := i + 17
+		if end > len(stream) {
+			end = len(stream)
+		}
+		if n, err := w.Write([]byte(stream[i:end])); err != nil || n != end-i {
+			t.Fatalf("Write() = %d, %v", n, err)
+		}
+	}
+
+	if strings.Join(got, ";") != "checking pod logs;inspecting node" {
+		t.Errorf("progress = %v, want [checking pod logs inspecting node]", got)
+	}
+}
@@ -0,0 +1,365 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resource usage sources
+const (
+	// ResourceSourceCgroup means the usage was read from the agent container's cgroup
+	ResourceSourceCgroup = "cgroup"
+	// ResourceSourceProc means the usage was read from the agent process tree under
+	// /proc, because the container's cgroup could not be found. Only processes
+	// started by the agent script are seen; with Docker that excludes the container.
+	ResourceSourceProc = "proc"
+)
+
+// clockTicksPerSecond is USER_HZ, the unit of the CPU times in /proc/<pid>/stat.
+// It is 100 on every Linux platform nightcrier supports.
+const clockTicksPerSecond = 100
+
+// ResourceUsage is the resource consumption of an agent run, sampled while it
+// runs. Counters that could not be read are zero.
+type ResourceUsage struct {
+	// CPUSeconds is the user and system CPU time consumed
+	CPUSeconds float64 `json:"cpu_seconds"`
+	// PeakRSSBytes is the highest resident memory observed
+	PeakRSSBytes int64 `json:"peak_rss_bytes"`
+	// PeakProcesses is the highest number of processes observed
+	PeakProcesses int `json:"peak_processes"`
+	// DiskWrittenBytes is the number of bytes written to storage
+	DiskWrittenBytes int64 `json:"disk_written_bytes"`
+	// Source is where the usage was read from (ResourceSourceCgroup or
+	// ResourceSourceProc); empty when nothing could be sampled
+	Source string `json:"source,omitempty"`
+}
+
+// Add accumulates the usage of another run of the same investigation (e.g. after
+// failing over to another API key): time and writes add up, peaks keep the highest.
+func (u *ResourceUsage) Add(other ResourceUsage) {
+	u.CPUSeconds += other.CPUSeconds
+	u.DiskWrittenBytes += other.DiskWrittenBytes
+	if other.PeakRSSBytes > u.PeakRSSBytes {
+		u.PeakRSSBytes = other.PeakRSSBytes
+	}
+	if other.PeakProcesses > u.PeakProcesses {
+		u.PeakProcesses = other.PeakProcesses
+	}
+	if u.Source == "" {
+		u.Source = other.Source
+	}
+}
+
+// resourceUsageContextKey is the unexported context key for the resource usage sink.
+type resourceUsageContextKey struct{}
+
+// WithResourceUsage returns a copy of ctx carrying usage, to which the executor
+// adds the resource usage of every agent run made with the context.
+func WithResourceUsage(ctx context.Context, usage *ResourceUsage) context.Context {
+	return context.WithValue(ctx, resourceUsageContextKey{}, usage)
+}
+
+// resourceUsageFromContext returns the resource usage sink stored in ctx, if any.
+func resourceUsageFromContext(ctx context.Context) *ResourceUsage {
+	usage, _ := ctx.Value(resourceUsageContextKey{}).(*ResourceUsage)
+	return usage
+}
+
+// procKey identifies a process across samples: PIDs are reused, start times are not.
+type procKey struct {
+	pid       int
+	startTime uint64
+}
+
+// procCounters are the cumulative counters of one process at its last sample.
+type procCounters struct {
+	cpuTicks     uint64
+	writtenBytes int64
+}
+
+// resourceSampler samples the resource usage of a running agent. It reads the
+// agent container's cgroup once the container ID appears in cidFile, and falls
+// back to the process tree rooted at pid.
+type resourceSampler struct {
+	pid        int
+	cidFile    string
+	cgroupRoot string
+	procRoot   string
+
+	mu        sync.Mutex
+	cgroupDir string
+	cgroup    ResourceUsage
+	procs     map[procKey]procCounters
+	proc      ResourceUsage
+}
+
+// newResourceSampler returns a sampler of the agent started as pid.
+func newResourceSampler(pid int, cidFile string) *resourceSampler {
+	return &resourceSampler{
+		pid:        pid,
+		cidFile:    cidFile,
+		cgroupRoot: "/sys/fs/cgroup",
+		procRoot:   "/proc",
+		procs:      make(map[procKey]procCounters),
+	}
+}
+
+// run samples every interval until ctx is done.
+func (s *resourceSampler) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	s.sample()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sample()
+		}
+	}
+}
+
+// sample takes one sample of the cgroup and the process tree.
+func (s *resourceSampler) sample() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sampleCgroup()
+	s.sampleProcs()
+}
+
+// usage returns the usage sampled so far, preferring the container's cgroup.
+func (s *resourceSampler) usage() ResourceUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cgroup.Source != "" {
+		return s.cgroup
+	}
+	usage := s.proc
+	for _, counters := range s.procs {
+		usage.CPUSeconds += float64(counters.cpuTicks) / clockTicksPerSecond
+		usage.DiskWrittenBytes += counters.writtenBytes
+	}
+	if usage.PeakProcesses > 0 {
+		usage.Source = ResourceSourceProc
+	}
+	return usage
+}
+
+// sampleCgroup reads the cgroup v2 stats of the agent container. The stats are
+// cumulative, so the last successful read is kept once the container (started
+// with --rm) is gone.
+func (s *resourceSampler) sampleCgroup() {
+	if s.cgroupDir == "" {
+		s.cgroupDir = s.findCgroup()
+		if s.cgroupDir == "" {
+			return
+		}
+	}
+
+	usec, err := readKeyedValue(filepath.Join(s.cgroupDir, "cpu.stat"), "usage_usec")
+	if err != nil {
+		return
+	}
+	s.cgroup.Source = ResourceSourceCgroup
+	s.cgroup.CPUSeconds = float64(usec) / 1e6
+
+	// memory.peak and pids.peak need recent kernels; sampled current values are
+	// the fallback
+	if peak, err := readIntFile(filepath.Join(s.cgroupDir, "memory.peak")); err == nil {
+		s.cgroup.PeakRSSBytes = max(s.cgroup.PeakRSSBytes, peak)
+	} else if current, err := readIntFile(filepath.Join(s.cgroupDir, "memory.current")); err == nil {
+		s.cgroup.PeakRSSBytes = max(s.cgroup.PeakRSSBytes, current)
+	}
+	if peak, err := readIntFile(filepath.Join(s.cgroupDir, "pids.peak")); err == nil {
+		s.cgroup.PeakProcesses = max(s.cgroup.PeakProcesses, int(peak))
+	} else if current, err := readIntFile(filepath.Join(s.cgroupDir, "pids.current")); err == nil {
+		s.cgroup.PeakProcesses = max(s.cgroup.PeakProcesses, int(current))
+	}
+	if written, err := readIOWrittenBytes(filepath.Join(s.cgroupDir, "io.stat")); err == nil {
+		s.cgroup.DiskWrittenBytes = max(s.cgroup.DiskWrittenBytes, written)
+	}
+}
+
+// findCgroup returns the cgroup directory of the agent container, or "" while its
+// ID is not known or its cgroup cannot be found (cgroup v1, rootless Docker,
+// other runtimes).
+func (s *resourceSampler) findCgroup() string {
+	if s.cidFile == "" {
+		return ""
+	}
+	data, err := os.ReadFile(s.cidFile)
+	if err != nil {
+		return ""
+	}
+	id := strings.TrimSpace(string(data))
+	if id == "" {
+		return ""
+	}
+	// systemd and cgroupfs drivers
+	for _, dir := range []string{
+		filepath.Join(s.cgroupRoot, "system.slice", "docker-"+id+".scope"),
+		filepath.Join(s.cgroupRoot, "docker", id),
+	} {
+		if _, err := os.Stat(filepath.Join(dir, "cpu.stat")); err == nil {
+			return dir
+		}
+	}
+	return ""
+}
+
+// sampleProcs reads the process tree rooted at the agent process. Exited
+// processes keep the counters of their last sample.
+func (s *resourceSampler) sampleProcs() {
+	entries, err := os.ReadDir(s.procRoot)
+	if err != nil {
+		return
+	}
+
+	type procInfo struct {
+		key      procKey
+		ppid     int
+		rss      int64
+		cpuTicks uint64
+	}
+	byPID := make(map[int]procInfo)
+	children := make(map[int][]int)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		stat, err := os.ReadFile(filepath.Join(s.procRoot, entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		info, ok := parseProcStat(string(stat))
+		if !ok {
+			continue
+		}
+		byPID[pid] = procInfo{
+			key:      procKey{pid: pid, startTime: info.startTime},
+			ppid:     info.ppid,
+			rss:      info.rssPages * int64(os.Getpagesize()),
+			cpuTicks: info.utime + info.stime,
+		}
+		children[info.ppid] = append(children[info.ppid], pid)
+	}
+	if _, ok := byPID[s.pid]; !ok {
+		return
+	}
+
+	var rss int64
+	count := 0
+	queue := []int{s.pid}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		info := byPID[pid]
+		count++
+		rss += info.rss
+
+		counters := procCounters{cpuTicks: info.cpuTicks}
+		if written, err := readKeyedValue(filepath.Join(s.procRoot, strconv.Itoa(pid), "io"), "write_bytes"); err == nil {
+			counters.writtenBytes = written
+		} else {
+			counters.writtenBytes = s.procs[info.key].writtenBytes
+		}
+		s.procs[info.key] = counters
+		queue = append(queue, children[pid]...)
+	}
+	s.proc.PeakRSSBytes = max(s.proc.PeakRSSBytes, rss)
+	s.proc.PeakProcesses = max(s.proc.PeakProcesses, count)
+}
+
+// procStat holds the fields of /proc/<pid>/stat used for sampling.
+type procStat struct {
+	ppid      int
+	utime     uint64
+	stime     uint64
+	startTime uint64
+	rssPages  int64
+}
+
+// parseProcStat parses /proc/<pid>/stat. The command name may contain spaces and
+// parentheses, so fields are counted from its closing parenthesis.
+func parseProcStat(stat string) (procStat, bool) {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return procStat{}, false
+	}
+	// fields[0] is the state (field 3 in proc(5))
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 22 {
+		return procStat{}, false
+	}
+	ppid, err1 := strconv.Atoi(fields[1])
+	utime, err2 := strconv.ParseUint(fields[11], 10, 64)
+	stime, err3 := strconv.ParseUint(fields[12], 10, 64)
+	startTime, err4 := strconv.ParseUint(fields[19], 10, 64)
+	rss, err5 := strconv.ParseInt(fields[21], 10, 64)
+	for _, err := range []error{err1, err2, err3, err4, err5} {
+		if err != nil {
+			return procStat{}, false
+		}
+	}
+	return procStat{ppid: ppid, utime: utime, stime: stime, startTime: startTime, rssPages: rss}, true
+}
+
+// readIntFile reads a file holding a single integer, e.g. memory.peak.
+func readIntFile(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// readKeyedValue reads the value of key from a file of "key value" or
+// "key: value" lines, e.g. cpu.stat or /proc/<pid>/io.
+func readKeyedValue(path, key string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimSuffix(fields[0], ":") == key {
+			return strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, os.ErrNotExist
+}
+
+// readIOWrittenBytes sums the wbytes of every device in a cgroup io.stat file.
+func readIOWrittenBytes(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, line := range strings.Split(string(data), "\n") {
+		for _, field := range strings.Fields(line) {
+			if value, ok := strings.CutPrefix(field, "wbytes="); ok {
+				n, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return 0, err
+				}
+				total += n
+			}
+		}
+	}
+	return total, nil
+}
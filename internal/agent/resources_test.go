@@ -0,0 +1,175 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+// writeProcStat writes a /proc/<pid>/stat line with the fields the sampler reads.
+func writeProcStat(t *testing.T, procRoot string, pid, ppid int, utime, stime, startTime uint64, rssPages int64) {
+	t.Helper()
+	// Fields 3-24 of proc(5), in order
+	fields := []string{"S", fmt.Sprint(ppid), "1", "1", "0", "-1", "0", "0", "0", "0", "0",
+		fmt.Sprint(utime), fmt.Sprint(stime), "0", "0", "20", "0", "1", "0",
+		fmt.Sprint(startTime), "1000", fmt.Sprint(rssPages)}
+	writeTestFile(t, filepath.Join(procRoot, fmt.Sprint(pid), "stat"),
+		fmt.Sprintf("%d (agent (x) y) %s\n", pid, strings.Join(fields, " ")))
+}
+
+func TestParseProcStat(t *testing.T) {
+	stat, ok := parseProcStat("42 (my (odd) cmd) S 7 1 1 0 -1 0 0 0 0 0 150 50 0 0 20 0 1 0 9999 1000 25 rest")
+	if !ok {
+		t.Fatal("parseProcStat() failed on a command name with spaces and parentheses")
+	}
+	want := procStat{ppid: 7, utime: 150, stime: 50, startTime: 9999, rssPages: 25}
+	if stat != want {
+		t.Errorf("parseProcStat() = %+v, want %+v", stat, want)
+	}
+
+	if _, ok := parseProcStat("42 (truncated) S 7"); ok {
+		t.Error("parseProcStat() should reject a truncated line")
+	}
+}
+
+func TestResourceSampler_Cgroup(t *testing.T) {
+	root := t.TempDir()
+	cidFile := filepath.Join(t.TempDir(), "container.id")
+	sampler := newResourceSampler(1, cidFile)
+	sampler.cgroupRoot = root
+	sampler.procRoot = t.TempDir()
+
+	// No container ID yet
+	sampler.sample()
+	if usage := sampler.usage(); usage.Source != "" {
+		t.Errorf("usage() before the container started = %+v, want nothing sampled", usage)
+	}
+
+	writeTestFile(t, cidFile, "abc123\n")
+	dir := filepath.Join(root, "system.slice", "docker-abc123.scope")
+	writeTestFile(t, filepath.Join(dir, "cpu.stat"), "usage_usec 2500000\nuser_usec 2000000\nsystem_usec 500000\n")
+	writeTestFile(t, filepath.Join(dir, "memory.current"), "1048576\n")
+	writeTestFile(t, filepath.Join(dir, "pids.current"), "3\n")
+	writeTestFile(t, filepath.Join(dir, "io.stat"), "8:0 rbytes=10 wbytes=4096 rios=1 wios=1\n8:16 rbytes=0 wbytes=1024 rios=0 wios=1\n")
+	sampler.sample()
+
+	// Without memory.peak and pids.peak the highest sampled values are kept
+	writeTestFile(t, filepath.Join(dir, "memory.current"), "524288\n")
+	writeTestFile(t, filepath.Join(dir, "pids.current"), "1\n")
+	sampler.sample()
+
+	// The container (started with --rm) is gone: the last read stays
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	sampler.sample()
+
+	want := ResourceUsage{
+		CPUSeconds:       2.5,
+		PeakRSSBytes:     1048576,
+		PeakProcesses:    3,
+		DiskWrittenBytes: 5120,
+		Source:           ResourceSourceCgroup,
+	}
+	if usage := sampler.usage(); usage != want {
+		t.Errorf("usage() = %+v, want %+v", usage, want)
+	}
+}
+
+func TestResourceSampler_Proc(t *testing.T) {
+	procRoot := t.TempDir()
+	sampler := newResourceSampler(100, "")
+	sampler.cgroupRoot = t.TempDir()
+	sampler.procRoot = procRoot
+
+	writeProcStat(t, procRoot, 100, 1, 100, 50, 1000, 10)
+	writeProcStat(t, procRoot, 101, 100, 200, 100, 1001, 20)
+	writeTestFile(t, filepath.Join(procRoot, "101", "io"), "rchar: 10\nwchar: 20\nwrite_bytes: 8192\n")
+	// Not part of the agent's process tree
+	writeProcStat(t, procRoot, 200, 1, 9000, 9000, 5, 9000)
+	sampler.sample()
+
+	// The child exits; its counters from the last sample still count
+	if err := os.RemoveAll(filepath.Join(procRoot, "101")); err != nil {
+		t.Fatal(err)
+	}
+	writeProcStat(t, procRoot, 100, 1, 150, 50, 1000, 12)
+	sampler.sample()
+
+	usage := sampler.usage()
+	pageSize := int64(os.Getpagesize())
+	want := ResourceUsage{
+		CPUSeconds:       5, // (150+50 + 200+100) ticks
+		PeakRSSBytes:     30 * pageSize,
+		PeakProcesses:    2,
+		DiskWrittenBytes: 8192,
+		Source:           ResourceSourceProc,
+	}
+	if usage != want {
+		t.Errorf("usage() = %+v, want %+v", usage, want)
+	}
+}
+
+func TestResourceUsage_Add(t *testing.T) {
+	usage := ResourceUsage{CPUSeconds: 1, PeakRSSBytes: 500, PeakProcesses: 2, DiskWrittenBytes: 10, Source: ResourceSourceCgroup}
+	usage.Add(ResourceUsage{CPUSeconds: 2, PeakRSSBytes: 300, PeakProcesses: 5, DiskWrittenBytes: 20, Source: ResourceSourceProc})
+
+	want := ResourceUsage{CPUSeconds: 3, PeakRSSBytes: 500, PeakProcesses: 5, DiskWrittenBytes: 30, Source: ResourceSourceCgroup}
+	if usage != want {
+		t.Errorf("Add() = %+v, want %+v", usage, want)
+	}
+}
+
+func TestExecute_RecordsResourceUsage(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("no /proc filesystem")
+	}
+
+	// The script outlives one sample interval: the first sample may catch the
+	// process before exec and the last one after exit, both without memory
+	scriptPath := filepath.Join(t.TempDir(), "agent.sh")
+	script := `#!/usr/bin/env bash
+echo "$CONTAINER_CIDFILE" > "$(dirname "$0")/cidfile"
+sleep 1.3
+`
+	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to create test script: %v", err)
+	}
+
+	tuning := createTestTuning()
+	tuning.Agent.ResourceSampleIntervalSeconds = 1
+	executor := NewExecutorWithConfig(ExecutorConfig{
+		ScriptPath:       scriptPath,
+		AllowedTools:     "Read",
+		Model:            "sonnet",
+		Timeout:          10,
+		AdditionalPrompt: "Test",
+	}, tuning)
+
+	var usage ResourceUsage
+	ctx := WithResourceUsage(context.Background(), &usage)
+	if _, _, err := executor.Execute(ctx, t.TempDir(), "test-incident-usage"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if usage.Source != ResourceSourceProc || usage.PeakProcesses < 1 || usage.PeakRSSBytes <= 0 {
+		t.Errorf("usage = %+v, want the agent script sampled from /proc", usage)
+	}
+	cidFile, err := os.ReadFile(filepath.Join(filepath.Dir(scriptPath), "cidfile"))
+	if err != nil || strings.TrimSpace(string(cidFile)) == "" {
+		t.Errorf("CONTAINER_CIDFILE was not passed to the agent script (%v)", err)
+	}
+}
@@ -0,0 +1,150 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// runtimeProbeTimeout bounds reading the CLI version from the agent image
+const runtimeProbeTimeout = 60 * time.Second
+
+// cliBinaries maps the agent CLIs to the binary each runs in the agent image
+var cliBinaries = map[string]string{
+	"":       "claude",
+	"claude": "claude",
+	"codex":  "codex",
+	"goose":  "goose",
+	"gemini": "gemini",
+}
+
+// versionPattern matches the version in a CLI's --version output, e.g. "1.0.30"
+// in "1.0.30 (Claude Code)" or "0.1.2504" in "codex-cli 0.1.2504"
+var versionPattern = regexp.MustCompile(`\bv?(\d+\.\d+(?:\.\d+)?(?:[-+][0-9A-Za-z.-]+)?)\b`)
+
+// RuntimeProber reads the digest of the agent image and the version of the agent
+// CLI it contains, with docker. Versions are cached per image digest, so the agent
+// image is only started again once its tag moves to another image.
+type RuntimeProber struct {
+	// allowPull pulls images that are not present locally (false offline)
+	allowPull bool
+	// run runs docker and returns its standard output
+	run func(ctx context.Context, args ...string) ([]byte, error)
+
+	mu       sync.Mutex
+	versions map[string]string // image digest + CLI -> CLI version
+}
+
+// NewRuntimeProber returns a prober using the docker CLI. With allowPull, an image
+// not present locally is pulled before it is inspected.
+func NewRuntimeProber(allowPull bool) *RuntimeProber {
+	return &RuntimeProber{allowPull: allowPull, run: runDocker, versions: make(map[string]string)}
+}
+
+func runDocker(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker %s failed: %w (output: %s)", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// Probe returns the digest of image ("sha256:<hex>") and the version of the agent
+// CLI in it. A value that could not be read is empty and reported in the error.
+func (p *RuntimeProber) Probe(ctx context.Context, image, cli string) (digest, cliVersion string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, runtimeProbeTimeout)
+	defer cancel()
+
+	digest, err = p.imageDigest(ctx, image)
+	if err != nil {
+		return "", "", err
+	}
+
+	key := digest + "\x00" + cli
+	p.mu.Lock()
+	cliVersion, ok := p.versions[key]
+	p.mu.Unlock()
+	if ok {
+		return digest, cliVersion, nil
+	}
+
+	binary, ok := cliBinaries[cli]
+	if !ok {
+		return digest, "", fmt.Errorf("unknown agent CLI %q", cli)
+	}
+	out, err := p.run(ctx, "run", "--rm", "--network", "none", "--entrypoint", binary, image, "--version")
+	if err != nil {
+		return digest, "", fmt.Errorf("failed to read %s version: %w", binary, err)
+	}
+	cliVersion = ParseCLIVersion(string(out))
+	if cliVersion == "" {
+		return digest, "", fmt.Errorf("no version in %s --version output %q", binary, strings.TrimSpace(string(out)))
+	}
+
+	p.mu.Lock()
+	p.versions[key] = cliVersion
+	p.mu.Unlock()
+	return digest, cliVersion, nil
+}
+
+// imageDigest returns the digest of a local image, pulling it first when allowed
+// and missing. The registry digest of the image's repository is preferred, since
+// it is what registries, cosign, and pins refer to; images built locally have
+// only their image ID.
+func (p *RuntimeProber) imageDigest(ctx context.Context, image string) (string, error) {
+	inspect := []string{"image", "inspect", "--format", `{{.Id}}{{range .RepoDigests}} {{.}}{{end}}`, image}
+	out, err := p.run(ctx, inspect...)
+	if err != nil && p.allowPull {
+		if _, pullErr := p.run(ctx, "pull", "--quiet", image); pullErr != nil {
+			return "", fmt.Errorf("failed to inspect agent image %s: %w", image, err)
+		}
+		out, err = p.run(ctx, inspect...)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect agent image %s: %w", image, err)
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("docker returned no ID for agent image %s", image)
+	}
+	repository := imageRepository(image)
+	for _, repoDigest := range fields[1:] {
+		repo, digest, ok := strings.Cut(repoDigest, "@")
+		if ok && imageRepository(repo) == repository {
+			return digest, nil
+		}
+	}
+	if len(fields) > 1 {
+		if _, digest, ok := strings.Cut(fields[1], "@"); ok {
+			return digest, nil
+		}
+	}
+	return fields[0], nil
+}
+
+// imageRepository strips the tag and digest from an image reference.
+func imageRepository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if slash, colon := strings.LastIndex(image, "/"), strings.LastIndex(image, ":"); colon > slash {
+		image = image[:colon]
+	}
+	return image
+}
+
+// ParseCLIVersion returns the version in the output of an agent CLI's --version,
+// without a leading "v", or "" when it has none.
+func ParseCLIVersion(output string) string {
+	m := versionPattern.FindStringSubmatch(output)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
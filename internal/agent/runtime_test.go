@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseCLIVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"1.0.30 (Claude Code)\n", "1.0.30"},
+		{"codex-cli 0.1.2504\n", "0.1.2504"},
+		{"v0.1.5-beta.2", "0.1.5-beta.2"},
+		{"command not found", ""},
+	}
+	for _, tt := range tests {
+		if got := ParseCLIVersion(tt.output); got != tt.want {
+			t.Errorf("ParseCLIVersion(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+// fakeDocker answers docker commands and counts the agent image runs.
+type fakeDocker struct {
+	pulled bool
+	runs   int
+}
+
+func (f *fakeDocker) run(ctx context.Context, args ...string) ([]byte, error) {
+	switch args[0] {
+	case "image":
+		if !f.pulled {
+			return nil, fmt.Errorf("docker image failed: No such image")
+		}
+		return []byte("sha256:1111 mirror.example.com/agent@sha256:2222 registry.example.com/agent@sha256:3333\n"), nil
+	case "pull":
+		f.pulled = true
+		return nil, nil
+	case "run":
+		f.runs++
+		if !strings.Contains(strings.Join(args, " "), "--entrypoint claude") {
+			return nil, fmt.Errorf("unexpected command %v", args)
+		}
+		return []byte("1.0.30 (Claude Code)\n"), nil
+	}
+	return nil, fmt.Errorf("unexpected command %v", args)
+}
+
+func TestRuntimeProber_Probe(t *testing.T) {
+	docker := &fakeDocker{}
+	prober := NewRuntimeProber(false)
+	prober.run = docker.run
+
+	if _, _, err := prober.Probe(context.Background(), "registry.example.com/agent:v2", ""); err == nil {
+		t.Error("Probe() of a missing image without pulling succeeded, want error")
+	}
+
+	prober.allowPull = true
+	for i := 0; i < 2; i++ {
+		digest, version, err := prober.Probe(context.Background(), "registry.example.com/agent:v2", "")
+		if err != nil {
+			t.Fatalf("Probe() error = %v", err)
+		}
+		if digest != "sha256:3333" || version != "1.0.30" {
+			t.Errorf("Probe() = %q, %q, want the registry digest of the image's repository and 1.0.30", digest, version)
+		}
+	}
+	if docker.runs != 1 {
+		t.Errorf("agent image run %d times, want once per digest", docker.runs)
+	}
+}
@@ -2,13 +2,33 @@ package agent
 
 import (
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// workspaceArtifacts are the workspace entries kept when a scratch workspace is
+// off-loaded to the workspace root: the incident context nightcrier wrote, the
+// prompt, the agent's output, and the logs. Anything else the agent wrote while it
+// ran (caches, clones, intermediate files) is discarded with the scratch directory.
+var workspaceArtifacts = []string{
+	"incident.json",
+	"incident_facts.md",
+	"incident_cluster_permissions.json",
+	"prompt-sent.md",
+	"output",
+	"logs",
+}
+
 // WorkspaceManager manages incident workspace directories
 type WorkspaceManager struct {
 	root string
+
+	// scratchRoot, when set, holds active workspaces (e.g. a tmpfs mount); Offload
+	// moves their final artifacts under root once the agent has finished
+	scratchRoot string
 }
 
 // NewWorkspaceManager creates a new workspace manager with the given root directory
@@ -18,15 +38,173 @@ func NewWorkspaceManager(root string) *WorkspaceManager {
 	}
 }
 
+// NewScratchWorkspaceManager creates a workspace manager that places active
+// workspaces under scratchRoot (a tmpfs or other fast scratch directory) and
+// off-loads their final artifacts to root when the investigation finishes. This
+// keeps chatty agents' intermediate writes off the persistent disk.
+func NewScratchWorkspaceManager(root, scratchRoot string) *WorkspaceManager {
+	return &WorkspaceManager{
+		root:        root,
+		scratchRoot: scratchRoot,
+	}
+}
+
 // Create creates a workspace directory for the given incident ID
 // Returns the absolute path to the created workspace
-func (w *WorkspaceManager) Create(incidentID string) (string, error) {
-	workspacePath := filepath.Join(w.root, incidentID)
+//
+// The contents of each template directory (an organization README, contact list,
+// common scripts) are copied into the new workspace, in order, so files of a later
+// template replace those of an earlier one. Template files are not artifacts: they
+// are not off-loaded from a scratch workspace.
+func (w *WorkspaceManager) Create(incidentID string, templates ...string) (string, error) {
+	base := w.root
+	if w.scratchRoot != "" {
+		base = w.scratchRoot
+	}
+	workspacePath := filepath.Join(base, incidentID)
 
 	// Create the directory with 0700 permissions (owner read/write/execute only)
 	if err := os.MkdirAll(workspacePath, 0700); err != nil {
 		return "", fmt.Errorf("failed to create workspace directory: %w", err)
 	}
 
+	for _, template := range templates {
+		if err := copyTemplate(template, workspacePath); err != nil {
+			return "", fmt.Errorf("failed to copy workspace template %s: %w", template, err)
+		}
+	}
+
 	return workspacePath, nil
 }
+
+// copyTemplate copies the directories and regular files of a template directory
+// into dst, keeping their permissions. Symlinks, special files, and .git
+// directories are skipped.
+func copyTemplate(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode().IsRegular():
+			// Replace the file of an earlier template, even a read-only one
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		return nil
+	})
+}
+
+// Offload moves the final artifacts of a scratch workspace to the incident's
+// directory under the workspace root and removes the scratch workspace. It returns
+// the persistent workspace path and the log paths rewritten to point into it.
+// Without a scratch root the workspace is already persistent and is returned
+// unchanged. On error the scratch workspace is left in place.
+func (w *WorkspaceManager) Offload(incidentID, workspacePath string, logPaths LogPaths) (string, LogPaths, error) {
+	if w.scratchRoot == "" || filepath.Dir(workspacePath) != filepath.Clean(w.scratchRoot) {
+		return workspacePath, logPaths, nil
+	}
+
+	dest := filepath.Join(w.root, incidentID)
+	if err := os.MkdirAll(dest, 0700); err != nil {
+		return workspacePath, logPaths, fmt.Errorf("failed to create workspace directory: %w", err)
+	}
+	for _, name := range workspaceArtifacts {
+		src := filepath.Join(workspacePath, name)
+		if _, err := os.Lstat(src); os.IsNotExist(err) {
+			continue
+		}
+		if err := moveTree(src, filepath.Join(dest, name)); err != nil {
+			return workspacePath, logPaths, fmt.Errorf("failed to off-load %s: %w", name, err)
+		}
+	}
+	if err := os.RemoveAll(workspacePath); err != nil {
+		return dest, rebaseLogPaths(logPaths, workspacePath, dest), fmt.Errorf("failed to remove scratch workspace: %w", err)
+	}
+	return dest, rebaseLogPaths(logPaths, workspacePath, dest), nil
+}
+
+// rebaseLogPaths rewrites log paths under from to the same paths under to.
+func rebaseLogPaths(logPaths LogPaths, from, to string) LogPaths {
+	rebase := func(path string) string {
+		rel, err := filepath.Rel(from, path)
+		if path == "" || err != nil || strings.HasPrefix(rel, "..") {
+			return path
+		}
+		return filepath.Join(to, rel)
+	}
+	return LogPaths{
+		Stdout:   rebase(logPaths.Stdout),
+		Stderr:   rebase(logPaths.Stderr),
+		Combined: rebase(logPaths.Combined),
+	}
+}
+
+// moveTree moves a file or directory, copying it when src and dst are on
+// different filesystems (the usual case for a tmpfs scratch root).
+func moveTree(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		// Symlinks and special files are not artifacts
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// copyFile copies a regular file, creating dst with the given permissions.
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,161 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkspaceManagerCreate(t *testing.T) {
+	root := t.TempDir()
+	path, err := NewWorkspaceManager(root).Create("inc-1")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if path != filepath.Join(root, "inc-1") {
+		t.Errorf("Create() = %q, want it under the workspace root", path)
+	}
+	// Without a scratch root the workspace is already persistent
+	got, _, err := NewWorkspaceManager(root).Offload("inc-1", path, LogPaths{})
+	if err != nil || got != path {
+		t.Errorf("Offload() = %q, %v, want the workspace unchanged", got, err)
+	}
+}
+
+func TestWorkspaceManagerOffload(t *testing.T) {
+	root := t.TempDir()
+	scratch := t.TempDir()
+	mgr := NewScratchWorkspaceManager(root, scratch)
+
+	workspace, err := mgr.Create("inc-1")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if filepath.Dir(workspace) != scratch {
+		t.Fatalf("Create() = %q, want it under the scratch root", workspace)
+	}
+	files := map[string]string{
+		"incident.json":           "{}",
+		"output/investigation.md": "# Report",
+		"logs/agent-stdout.log":   "stdout",
+		"repo-clone/big.bin":      "scratch",
+		"notes.tmp":               "scratch",
+	}
+	for name, content := range files {
+		path := filepath.Join(workspace, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logs := LogPaths{Stdout: filepath.Join(workspace, "logs", "agent-stdout.log")}
+	dest, gotLogs, err := mgr.Offload("inc-1", workspace, logs)
+	if err != nil {
+		t.Fatalf("Offload() error = %v", err)
+	}
+	if dest != filepath.Join(root, "inc-1") {
+		t.Errorf("Offload() path = %q, want it under the workspace root", dest)
+	}
+	if gotLogs.Stdout != filepath.Join(dest, "logs", "agent-stdout.log") {
+		t.Errorf("Offload() stdout log = %q, want it in the persistent workspace", gotLogs.Stdout)
+	}
+	for _, name := range []string{"incident.json", "output/investigation.md", "logs/agent-stdout.log"} {
+		if _, err := os.Stat(filepath.Join(dest, name)); err != nil {
+			t.Errorf("artifact %s not off-loaded: %v", name, err)
+		}
+	}
+	for _, name := range []string{"repo-clone", "notes.tmp"} {
+		if _, err := os.Stat(filepath.Join(dest, name)); !os.IsNotExist(err) {
+			t.Errorf("scratch file %s should not be off-loaded", name)
+		}
+	}
+	if _, err := os.Stat(workspace); !os.IsNotExist(err) {
+		t.Error("scratch workspace should be removed")
+	}
+}
+
+func TestMoveTreeCopiesWhenRenameFails(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "output")
+	dst := filepath.Join(t.TempDir(), "output")
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "report.md"), []byte("report"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// A non-empty destination makes the rename fail, as a cross-filesystem move would
+	if err := os.MkdirAll(dst, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dst, "existing"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moveTree(src, dst); err != nil {
+		t.Fatalf("moveTree() error = %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dst, "sub", "report.md")); err != nil || string(data) != "report" {
+		t.Errorf("copied file = %q, %v, want report", data, err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("source should be removed after copying")
+	}
+}
+
+func TestWorkspaceManagerCreateFromTemplates(t *testing.T) {
+	global := t.TempDir()
+	cluster := t.TempDir()
+	templateFiles := map[string]map[string]string{
+		global: {
+			"README.md":         "# Org README",
+			"contacts.md":       "oncall: #sre",
+			"scripts/triage.sh": "#!/bin/sh",
+			".git/config":       "[core]",
+		},
+		cluster: {
+			"contacts.md": "oncall: #payments",
+		},
+	}
+	for dir, files := range templateFiles {
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0444); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := os.Chmod(filepath.Join(global, "scripts/triage.sh"), 0555); err != nil {
+		t.Fatal(err)
+	}
+
+	workspace, err := NewWorkspaceManager(t.TempDir()).Create("inc-1", global, cluster)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for name, want := range map[string]string{
+		"README.md":         "# Org README",
+		"contacts.md":       "oncall: #payments",
+		"scripts/triage.sh": "#!/bin/sh",
+	} {
+		got, err := os.ReadFile(filepath.Join(workspace, name))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(workspace, "scripts/triage.sh")); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("scripts/triage.sh mode = %v, %v, want it executable", info.Mode(), err)
+	}
+	if _, err := os.Stat(filepath.Join(workspace, ".git")); !os.IsNotExist(err) {
+		t.Errorf(".git was copied into the workspace (err = %v)", err)
+	}
+
+	if _, err := NewWorkspaceManager(t.TempDir()).Create("inc-2", filepath.Join(global, "missing")); err == nil {
+		t.Error("Create() with a missing template should fail")
+	}
+}
@@ -0,0 +1,67 @@
+package aggregation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// ClusterFault is a fault event together with the cluster it came from.
+type ClusterFault struct {
+	Cluster string
+	Event   *events.FaultEvent
+}
+
+// emitter delivers aggregated events to the event loop. After close, pending
+// events are discarded instead of blocking timer goroutines during shutdown.
+type emitter struct {
+	out  chan ClusterFault
+	done chan struct{}
+}
+
+func newEmitter() emitter {
+	return emitter{
+		out:  make(chan ClusterFault, 16),
+		done: make(chan struct{}),
+	}
+}
+
+// Output returns the channel on which aggregated events are delivered.
+func (e emitter) Output() <-chan ClusterFault {
+	return e.out
+}
+
+func (e emitter) emit(f ClusterFault) {
+	select {
+	case e.out <- f:
+	case <-e.done:
+	}
+}
+
+func (e emitter) close() {
+	close(e.done)
+}
+
+// highestSeverity returns the most severe of base and the severities of faults.
+func highestSeverity(base string, faults []*events.FaultEvent) string {
+	severity := base
+	for _, f := range faults {
+		if events.SeverityRank(f.Severity) > events.SeverityRank(severity) {
+			severity = f.Severity
+		}
+	}
+	return severity
+}
+
+// writeList writes up to limit lines, noting how many were omitted.
+func writeList(b *strings.Builder, lines []string, limit int) {
+	listed := lines
+	if len(listed) > limit {
+		listed = listed[:limit]
+	}
+	b.WriteString(strings.Join(listed, "\n"))
+	if len(lines) > len(listed) {
+		fmt.Fprintf(b, "\n- ... and %d more", len(lines)-len(listed))
+	}
+}
@@ -0,0 +1,166 @@
+package aggregation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// FaultTypeLowSeverityBatch is the fault type of batched low-severity events.
+const FaultTypeLowSeverityBatch = "LowSeverityBatch"
+
+// maxBatchContextLength bounds how much of each fault's context is quoted in the
+// batched context.
+const maxBatchContextLength = 200
+
+// LowSeverityBatcherConfig configures low-severity batching.
+type LowSeverityBatcherConfig struct {
+	// MaxSeverity is the most severe level that is batched (e.g. WARNING). More
+	// severe faults, and faults of unknown severity, are investigated individually.
+	MaxSeverity string
+
+	// Window is how long low-severity faults are collected after the first one
+	// before they are investigated together.
+	Window time.Duration
+
+	// MaxListedFaults caps the number of faults listed in the batched context.
+	// Zero defaults to 50.
+	MaxListedFaults int
+}
+
+// LowSeverityBatcher collects low-severity faults (minor warnings) per cluster for
+// a window and emits them as one event with a combined context, so a single agent
+// run investigates them together instead of one agent per warning. A window that
+// collected a single fault emits it unchanged.
+// It is safe for concurrent use.
+type LowSeverityBatcher struct {
+	emitter
+	cfg     LowSeverityBatcherConfig
+	mu      sync.Mutex
+	batches map[string][]*events.FaultEvent
+
+	// now and afterFunc are replaceable for tests
+	now       func() time.Time
+	afterFunc func(time.Duration, func()) *time.Timer
+}
+
+// NewLowSeverityBatcher creates a LowSeverityBatcher. Call Run to stop it on shutdown.
+func NewLowSeverityBatcher(cfg LowSeverityBatcherConfig) *LowSeverityBatcher {
+	if cfg.MaxListedFaults == 0 {
+		cfg.MaxListedFaults = 50
+	}
+	return &LowSeverityBatcher{
+		emitter:   newEmitter(),
+		cfg:       cfg,
+		batches:   make(map[string][]*events.FaultEvent),
+		now:       time.Now,
+		afterFunc: time.AfterFunc,
+	}
+}
+
+// Submit offers an event to the batcher. It returns true when the event was
+// added to the cluster's pending batch.
+func (b *LowSeverityBatcher) Submit(cluster string, event *events.FaultEvent) bool {
+	rank := events.SeverityRank(event.Severity)
+	if rank == 0 || rank > events.SeverityRank(b.cfg.MaxSeverity) {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pending, open := b.batches[cluster]
+	b.batches[cluster] = append(pending, event)
+	if !open {
+		b.afterFunc(b.cfg.Window, func() { b.flush(cluster) })
+		slog.Info("batching low-severity faults",
+			"cluster", cluster,
+			"max_severity", b.cfg.MaxSeverity,
+			"window", b.cfg.Window)
+	}
+	return true
+}
+
+// flush emits the cluster's pending batch.
+func (b *LowSeverityBatcher) flush(cluster string) {
+	b.mu.Lock()
+	batch := b.batches[cluster]
+	delete(b.batches, cluster)
+	b.mu.Unlock()
+
+	switch len(batch) {
+	case 0:
+		return
+	case 1:
+		b.emit(ClusterFault{Cluster: cluster, Event: batch[0]})
+		return
+	}
+
+	slog.Info("emitting batched low-severity faults for one investigation",
+		"cluster", cluster,
+		"batched_faults", len(batch))
+	b.emit(ClusterFault{Cluster: cluster, Event: b.buildBatchEvent(cluster, batch)})
+}
+
+// buildBatchEvent synthesizes the event investigating a batch of faults together.
+// The event is scoped to the batch's namespace when all faults share one, and to
+// the cluster otherwise.
+func (b *LowSeverityBatcher) buildBatchEvent(cluster string, batch []*events.FaultEvent) *events.FaultEvent {
+	now := b.now()
+	first := batch[0]
+
+	namespace := first.GetNamespace()
+	lines := make([]string, 0, len(batch))
+	for _, f := range batch {
+		if f.GetNamespace() != namespace {
+			namespace = ""
+		}
+		line := fmt.Sprintf("- %s/%s", f.GetResourceKind(), f.GetResourceName())
+		if ns := f.GetNamespace(); ns != "" {
+			line += " in " + ns
+		}
+		line += fmt.Sprintf(" (%s, %s)", f.FaultType, f.Severity)
+		if detail := strings.Join(strings.Fields(f.GetContext()), " "); detail != "" {
+			if runes := []rune(detail); len(runes) > maxBatchContextLength {
+				detail = string(runes[:maxBatchContextLength-3]) + "..."
+			}
+			line += ": " + detail
+		}
+		lines = append(lines, line)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Low-severity batch: %d faults at or below %s severity arrived within %s and are investigated together.\n",
+		len(batch), strings.ToUpper(b.cfg.MaxSeverity), b.cfg.Window)
+	sb.WriteString("Look for a common cause before treating them as unrelated.\n")
+	sb.WriteString("Faults:\n")
+	writeList(&sb, lines, b.cfg.MaxListedFaults)
+
+	resource := &events.ResourceInfo{APIVersion: "v1", Kind: "Namespace", Name: namespace, Namespace: namespace}
+	if namespace == "" {
+		resource = &events.ResourceInfo{Kind: "Cluster", Name: cluster}
+	}
+	return &events.FaultEvent{
+		FaultID:        fmt.Sprintf("low-severity-batch-%s-%s", cluster, first.FaultID),
+		ReceivedAt:     now,
+		SubscriptionID: first.SubscriptionID,
+		Cluster:        first.Cluster,
+		Resource:       resource,
+		FaultType:      FaultTypeLowSeverityBatch,
+		Severity:       highestSeverity(first.Severity, batch),
+		Context:        sb.String(),
+		Timestamp:      now.UTC().Format(time.RFC3339),
+	}
+}
+
+// Run waits until ctx is done. Once Run returns, pending batches are discarded
+// instead of blocking.
+func (b *LowSeverityBatcher) Run(ctx context.Context) {
+	defer b.close()
+	<-ctx.Done()
+}
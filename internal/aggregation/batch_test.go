@@ -0,0 +1,86 @@
+package aggregation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// newTestBatcher returns a batcher whose window timers never fire on their own.
+func newTestBatcher() *LowSeverityBatcher {
+	b := NewLowSeverityBatcher(LowSeverityBatcherConfig{MaxSeverity: "WARNING", Window: 15 * time.Minute})
+	b.now = func() time.Time { return time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC) }
+	b.afterFunc = func(time.Duration, func()) *time.Timer { return nil }
+	return b
+}
+
+func TestLowSeverityBatcher_BatchesMinorFaults(t *testing.T) {
+	b := newTestBatcher()
+
+	// Faults above the threshold, or of unknown severity, are investigated individually
+	for _, severity := range []string{"ERROR", "CRITICAL", ""} {
+		if b.Submit("prod", podFault("shop", "x", "", severity)) {
+			t.Errorf("%q fault should pass through", severity)
+		}
+	}
+
+	minor := podFault("shop", "a", "", "WARNING")
+	minor.Context = "Readiness probe failed:\n  HTTP 503"
+	for _, f := range []*events.FaultEvent{minor, podFault("billing", "b", "", "INFO"), podFault("shop", "c", "", "warning")} {
+		if !b.Submit("prod", f) {
+			t.Fatalf("fault %s should be batched", f.GetResourceName())
+		}
+	}
+
+	b.flush("prod")
+	select {
+	case out := <-b.Output():
+		ev := out.Event
+		if out.Cluster != "prod" || ev.FaultType != FaultTypeLowSeverityBatch {
+			t.Errorf("batch event = %s/%s", out.Cluster, ev.FaultType)
+		}
+		if ev.GetResourceKind() != "Cluster" || ev.GetResourceName() != "prod" {
+			t.Errorf("resource = %+v, want the cluster for faults in several namespaces", ev.Resource)
+		}
+		if ev.Severity != "WARNING" {
+			t.Errorf("Severity = %q, want WARNING", ev.Severity)
+		}
+		for _, want := range []string{
+			"3 faults at or below WARNING severity arrived within 15m0s",
+			"- Pod/a in shop (CrashLoopBackOff, WARNING): Readiness probe failed: HTTP 503",
+			"- Pod/b in billing (CrashLoopBackOff, INFO)",
+		} {
+			if !strings.Contains(ev.Context, want) {
+				t.Errorf("Context missing %q:\n%s", want, ev.Context)
+			}
+		}
+	default:
+		t.Fatal("expected batch event on Output")
+	}
+
+	// A new window opens after the flush
+	if !b.Submit("prod", podFault("shop", "d", "", "WARNING")) {
+		t.Error("fault after flush should open a new batch")
+	}
+}
+
+func TestLowSeverityBatcher_SingleFaultUnchanged(t *testing.T) {
+	b := newTestBatcher()
+	fault := podFault("shop", "a", "", "WARNING")
+	b.Submit("prod", fault)
+	b.Submit("staging", podFault("shop", "b", "", "WARNING"))
+	b.Submit("staging", podFault("shop", "c", "", "WARNING"))
+
+	b.flush("prod")
+	if out := <-b.Output(); out.Event != fault {
+		t.Errorf("single batched fault = %+v, want the original event", out.Event)
+	}
+
+	b.flush("staging")
+	out := <-b.Output()
+	if out.Event.GetResourceKind() != "Namespace" || out.Event.GetNamespace() != "shop" {
+		t.Errorf("resource = %+v, want the shared namespace", out.Event.Resource)
+	}
+}
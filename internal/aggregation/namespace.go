@@ -0,0 +1,222 @@
+package aggregation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// FaultTypeNamespaceDegradation is the fault type of collapsed namespace events.
+const FaultTypeNamespaceDegradation = "NamespaceDegradation"
+
+// NamespaceCollapserConfig configures namespace rate collapse.
+type NamespaceCollapserConfig struct {
+	// MaxFaults is the number of faults per namespace within Window that are still
+	// investigated individually. The next fault starts a collapse.
+	MaxFaults int
+
+	// Window is the sliding window for counting faults, and how long a collapse
+	// collects faults before the namespace degradation event is emitted.
+	Window time.Duration
+
+	// MaxListedResources caps the number of resources listed in the collapsed context.
+	// Zero defaults to 50.
+	MaxListedResources int
+}
+
+// namespaceKey identifies a namespace within a cluster.
+type namespaceKey struct {
+	cluster   string
+	namespace string
+}
+
+// namespaceState tracks recent faults for one namespace.
+type namespaceState struct {
+	recent     []time.Time // arrival times of faults within the sliding window, investigated individually
+	collapsing bool
+	collapsed  []*events.FaultEvent // faults absorbed into the pending degradation incident
+}
+
+// NamespaceCollapser collapses bursts of faults in one namespace (e.g. a bad deploy)
+// into a single "namespace degradation" event. The first MaxFaults faults within
+// Window are investigated individually; further faults are collected for Window and
+// emitted as one event listing every affected resource.
+// It is safe for concurrent use.
+type NamespaceCollapser struct {
+	emitter
+	cfg        NamespaceCollapserConfig
+	mu         sync.Mutex
+	namespaces map[namespaceKey]*namespaceState
+
+	// now and afterFunc are replaceable for tests
+	now       func() time.Time
+	afterFunc func(time.Duration, func()) *time.Timer
+}
+
+// NewNamespaceCollapser creates a NamespaceCollapser. Call Run to start pruning idle namespaces.
+func NewNamespaceCollapser(cfg NamespaceCollapserConfig) *NamespaceCollapser {
+	if cfg.MaxListedResources == 0 {
+		cfg.MaxListedResources = 50
+	}
+	return &NamespaceCollapser{
+		emitter:    newEmitter(),
+		cfg:        cfg,
+		namespaces: make(map[namespaceKey]*namespaceState),
+		now:        time.Now,
+		afterFunc:  time.AfterFunc,
+	}
+}
+
+// Submit offers an event to the collapser. It returns true when the event was
+// absorbed into a pending namespace degradation incident.
+func (c *NamespaceCollapser) Submit(cluster string, event *events.FaultEvent) bool {
+	namespace := event.GetNamespace()
+	if namespace == "" {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := namespaceKey{cluster: cluster, namespace: namespace}
+	state, ok := c.namespaces[key]
+	if !ok {
+		state = &namespaceState{}
+		c.namespaces[key] = state
+	}
+
+	if state.collapsing {
+		state.collapsed = append(state.collapsed, event)
+		return true
+	}
+
+	state.recent = c.withinWindow(state.recent)
+	if len(state.recent) < c.cfg.MaxFaults {
+		state.recent = append(state.recent, c.now())
+		return false
+	}
+
+	// Threshold exceeded: collapse further faults into one degradation incident
+	state.collapsing = true
+	state.collapsed = []*events.FaultEvent{event}
+	c.afterFunc(c.cfg.Window, func() { c.flush(key) })
+	slog.Warn("namespace fault rate exceeded, collapsing faults into one incident",
+		"cluster", cluster,
+		"namespace", namespace,
+		"max_faults", c.cfg.MaxFaults,
+		"window", c.cfg.Window)
+	return true
+}
+
+// withinWindow drops arrival times older than the window. Caller holds mu.
+func (c *NamespaceCollapser) withinWindow(arrivals []time.Time) []time.Time {
+	cutoff := c.now().Add(-c.cfg.Window)
+	kept := arrivals[:0]
+	for _, t := range arrivals {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// flush emits the namespace degradation event for a collapsing namespace.
+func (c *NamespaceCollapser) flush(key namespaceKey) {
+	c.mu.Lock()
+	state, ok := c.namespaces[key]
+	if !ok || !state.collapsing {
+		c.mu.Unlock()
+		return
+	}
+	event := c.buildDegradationEvent(key, state)
+	count := len(state.collapsed)
+
+	// Start counting afresh; the degradation incident covers everything so far
+	delete(c.namespaces, key)
+	c.mu.Unlock()
+
+	slog.Info("emitting namespace degradation incident",
+		"cluster", key.cluster,
+		"namespace", key.namespace,
+		"collapsed_faults", count)
+	c.emit(ClusterFault{Cluster: key.cluster, Event: event})
+}
+
+// buildDegradationEvent synthesizes the namespace degradation event. Caller holds mu.
+func (c *NamespaceCollapser) buildDegradationEvent(key namespaceKey, state *namespaceState) *events.FaultEvent {
+	now := c.now()
+	first := state.collapsed[0]
+
+	resources := make([]string, 0, len(state.collapsed))
+	seen := make(map[string]bool)
+	for _, f := range state.collapsed {
+		line := fmt.Sprintf("- %s/%s (%s)", f.GetResourceKind(), f.GetResourceName(), f.FaultType)
+		if !seen[line] {
+			seen[line] = true
+			resources = append(resources, line)
+		}
+	}
+	sort.Strings(resources)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Namespace degradation: %d faults in namespace %s exceeded the limit of %d per %s.\n",
+		len(state.recent)+len(state.collapsed), key.namespace, c.cfg.MaxFaults, c.cfg.Window)
+	fmt.Fprintf(&b, "%d fault(s) were investigated individually; the following %d were collapsed into this incident.\n",
+		len(state.recent), len(state.collapsed))
+	b.WriteString("Affected resources:\n")
+	writeList(&b, resources, c.cfg.MaxListedResources)
+
+	return &events.FaultEvent{
+		FaultID:        fmt.Sprintf("ns-degradation-%s-%s", key.namespace, first.FaultID),
+		ReceivedAt:     now,
+		SubscriptionID: first.SubscriptionID,
+		Cluster:        first.Cluster,
+		Resource: &events.ResourceInfo{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+			Name:       key.namespace,
+			Namespace:  key.namespace,
+		},
+		FaultType: FaultTypeNamespaceDegradation,
+		Severity:  highestSeverity(first.Severity, state.collapsed),
+		Context:   b.String(),
+		Timestamp: now.UTC().Format(time.RFC3339),
+	}
+}
+
+// Run prunes idle namespaces until ctx is done. Once Run returns, pending
+// degradation events are discarded instead of blocking.
+func (c *NamespaceCollapser) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	defer c.close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.prune()
+		}
+	}
+}
+
+// prune removes namespaces with no recent faults and no pending collapse.
+func (c *NamespaceCollapser) prune() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, state := range c.namespaces {
+		if state.collapsing {
+			continue
+		}
+		state.recent = c.withinWindow(state.recent)
+		if len(state.recent) == 0 {
+			delete(c.namespaces, key)
+		}
+	}
+}
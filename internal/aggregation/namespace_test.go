@@ -0,0 +1,101 @@
+package aggregation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCollapser(now *time.Time, maxFaults int) *NamespaceCollapser {
+	c := NewNamespaceCollapser(NamespaceCollapserConfig{MaxFaults: maxFaults, Window: 5 * time.Minute})
+	c.now = func() time.Time { return *now }
+	c.afterFunc = func(time.Duration, func()) *time.Timer { return nil }
+	return c
+}
+
+func TestNamespaceCollapser_CollapsesBurst(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := newTestCollapser(&now, 2)
+
+	// The first MaxFaults faults are investigated individually
+	for _, name := range []string{"a", "b"} {
+		if c.Submit("prod", podFault("shop", name, "", "ERROR")) {
+			t.Fatalf("fault %s should pass through", name)
+		}
+	}
+
+	// Further faults are collapsed
+	for _, name := range []string{"c", "d", "d"} {
+		if !c.Submit("prod", podFault("shop", name, "", "ERROR")) {
+			t.Fatalf("fault %s should be collapsed", name)
+		}
+	}
+	c.Submit("prod", podFault("shop", "e", "", "CRITICAL"))
+
+	// Other namespaces and clusters are unaffected
+	if c.Submit("prod", podFault("billing", "x", "", "ERROR")) {
+		t.Error("fault in another namespace should pass through")
+	}
+	if c.Submit("staging", podFault("shop", "x", "", "ERROR")) {
+		t.Error("fault in another cluster should pass through")
+	}
+
+	c.flush(namespaceKey{cluster: "prod", namespace: "shop"})
+
+	select {
+	case out := <-c.Output():
+		ev := out.Event
+		if ev.FaultType != FaultTypeNamespaceDegradation {
+			t.Errorf("FaultType = %q", ev.FaultType)
+		}
+		if ev.GetResourceKind() != "Namespace" || ev.GetResourceName() != "shop" || ev.GetNamespace() != "shop" {
+			t.Errorf("resource = %+v", ev.Resource)
+		}
+		if ev.Severity != "CRITICAL" {
+			t.Errorf("Severity = %q, want CRITICAL", ev.Severity)
+		}
+		for _, want := range []string{"6 faults in namespace shop", "the following 4 were collapsed", "- Pod/c (CrashLoopBackOff)", "- Pod/e (CrashLoopBackOff)"} {
+			if !strings.Contains(ev.Context, want) {
+				t.Errorf("Context missing %q:\n%s", want, ev.Context)
+			}
+		}
+		if strings.Count(ev.Context, "Pod/d ") != 1 {
+			t.Errorf("duplicate resources should be listed once:\n%s", ev.Context)
+		}
+	default:
+		t.Fatal("expected degradation event on Output")
+	}
+
+	// After the collapse is emitted, counting starts afresh
+	if c.Submit("prod", podFault("shop", "f", "", "ERROR")) {
+		t.Error("first fault after collapse should pass through")
+	}
+}
+
+func TestNamespaceCollapser_SlidingWindow(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := newTestCollapser(&now, 2)
+
+	c.Submit("prod", podFault("shop", "a", "", "ERROR"))
+	c.Submit("prod", podFault("shop", "b", "", "ERROR"))
+
+	// Older faults fall out of the window
+	now = now.Add(6 * time.Minute)
+	if c.Submit("prod", podFault("shop", "c", "", "ERROR")) {
+		t.Error("fault after window should pass through")
+	}
+
+	now = now.Add(6 * time.Minute)
+	c.prune()
+	if len(c.namespaces) != 0 {
+		t.Errorf("idle namespace should be pruned, have %d", len(c.namespaces))
+	}
+}
+
+func TestNamespaceCollapser_IgnoresClusterScoped(t *testing.T) {
+	now := time.Now()
+	c := newTestCollapser(&now, 0)
+	if c.Submit("prod", nodeFault("node-1", "NodeNotReady")) {
+		t.Error("cluster-scoped faults should never be collapsed")
+	}
+}
@@ -0,0 +1,223 @@
+// Package aggregation reduces investigation noise by folding many related fault
+// events into a single, broader investigation.
+//
+// Node aggregation: when a node reports a NotReady or resource-pressure condition,
+// the pod faults on that node are symptoms of the same root cause. Instead of
+// launching one investigation per pod, the node fault is held for a short window
+// while pod faults on the same node are collected, and a single node-focused event
+// listing every affected pod is emitted for investigation.
+package aggregation
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// nodeConditionMarkers identify fault types describing an unhealthy node condition.
+var nodeConditionMarkers = []string{"notready", "pressure", "unreachable", "networkunavailable"}
+
+// IsNodeCondition reports whether a fault describes a NotReady or pressure condition on a node.
+func IsNodeCondition(event *events.FaultEvent) bool {
+	if !strings.EqualFold(event.GetResourceKind(), "Node") {
+		return false
+	}
+	faultType := strings.ToLower(event.FaultType)
+	for _, marker := range nodeConditionMarkers {
+		if strings.Contains(faultType, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeAggregatorConfig configures node-level aggregation.
+type NodeAggregatorConfig struct {
+	// Window is how long a node fault is held to collect pod faults before the
+	// node-focused event is emitted.
+	Window time.Duration
+
+	// CoverFor is how long after the node event was emitted further pod faults on
+	// the node are considered covered by it and suppressed. Zero uses Window.
+	CoverFor time.Duration
+
+	// MaxListedPods caps the number of pods listed in the aggregated context.
+	// Zero defaults to 50.
+	MaxListedPods int
+}
+
+// nodeKey identifies a node within a cluster.
+type nodeKey struct {
+	cluster string
+	node    string
+}
+
+// nodeGroup collects pod faults for one degraded node.
+type nodeGroup struct {
+	nodeEvent *events.FaultEvent
+	pods      []*events.FaultEvent
+	flushed   bool
+	coverTill time.Time
+}
+
+// NodeAggregator groups pod faults under degraded nodes. Submit is called from the
+// event loop; aggregated events are delivered on the Output channel.
+// It is safe for concurrent use.
+type NodeAggregator struct {
+	emitter
+	cfg    NodeAggregatorConfig
+	mu     sync.Mutex
+	groups map[nodeKey]*nodeGroup
+
+	// now and afterFunc are replaceable for tests
+	now       func() time.Time
+	afterFunc func(time.Duration, func()) *time.Timer
+}
+
+// NewNodeAggregator creates a NodeAggregator. Call Run to start pruning expired groups.
+func NewNodeAggregator(cfg NodeAggregatorConfig) *NodeAggregator {
+	if cfg.CoverFor == 0 {
+		cfg.CoverFor = cfg.Window
+	}
+	if cfg.MaxListedPods == 0 {
+		cfg.MaxListedPods = 50
+	}
+	return &NodeAggregator{
+		emitter:   newEmitter(),
+		cfg:       cfg,
+		groups:    make(map[nodeKey]*nodeGroup),
+		now:       time.Now,
+		afterFunc: time.AfterFunc,
+	}
+}
+
+// Submit offers an event to the aggregator. It returns true when the event was
+// absorbed (held as a node fault or folded into a node group) and must not be
+// processed individually.
+func (a *NodeAggregator) Submit(cluster string, event *events.FaultEvent) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+
+	// A node condition opens (or refreshes) a group for that node
+	if IsNodeCondition(event) {
+		key := nodeKey{cluster: cluster, node: event.GetResourceName()}
+		if g, ok := a.groups[key]; ok && !g.flushed {
+			// Already collecting for this node; keep the first node event
+			return true
+		}
+		a.groups[key] = &nodeGroup{nodeEvent: event}
+		a.afterFunc(a.cfg.Window, func() { a.flush(key) })
+		slog.Info("node condition detected, aggregating pod faults",
+			"cluster", cluster,
+			"node", key.node,
+			"fault_type", event.FaultType,
+			"window", a.cfg.Window)
+		return true
+	}
+
+	// Pod faults on a degraded node are folded into the node investigation
+	node := event.GetNode()
+	if node == "" {
+		return false
+	}
+	g, ok := a.groups[nodeKey{cluster: cluster, node: node}]
+	if !ok {
+		return false
+	}
+	if !g.flushed {
+		g.pods = append(g.pods, event)
+		return true
+	}
+	if now.Before(g.coverTill) {
+		slog.Info("pod fault covered by recent node investigation",
+			"cluster", cluster,
+			"node", node,
+			"resource", fmt.Sprintf("%s/%s", event.GetNamespace(), event.GetResourceName()),
+			"fault_type", event.FaultType)
+		return true
+	}
+	return false
+}
+
+// flush emits the aggregated event for a node group.
+func (a *NodeAggregator) flush(key nodeKey) {
+	a.mu.Lock()
+	g, ok := a.groups[key]
+	if !ok || g.flushed {
+		a.mu.Unlock()
+		return
+	}
+	g.flushed = true
+	g.coverTill = a.now().Add(a.cfg.CoverFor)
+	event := a.buildNodeEvent(g)
+	podCount := len(g.pods)
+	a.mu.Unlock()
+
+	slog.Info("emitting node-focused investigation",
+		"cluster", key.cluster,
+		"node", key.node,
+		"affected_pods", podCount)
+	a.emit(ClusterFault{Cluster: key.cluster, Event: event})
+}
+
+// buildNodeEvent synthesizes the node-focused event from a group. Caller holds mu.
+func (a *NodeAggregator) buildNodeEvent(g *nodeGroup) *events.FaultEvent {
+	aggregated := *g.nodeEvent
+	aggregated.ReceivedAt = a.now()
+
+	aggregated.Severity = highestSeverity(aggregated.Severity, g.pods)
+
+	if len(g.pods) == 0 {
+		return &aggregated
+	}
+
+	pods := make([]string, 0, len(g.pods))
+	for _, pod := range g.pods {
+		pods = append(pods, fmt.Sprintf("- %s/%s %s (%s)",
+			pod.GetNamespace(), pod.GetResourceName(), pod.GetResourceKind(), pod.FaultType))
+	}
+	sort.Strings(pods)
+
+	var b strings.Builder
+	b.WriteString(aggregated.Context)
+	fmt.Fprintf(&b, "\n\nNode-level aggregation: %d pod fault(s) on this node were folded into this investigation.\nAffected pods:\n", len(pods))
+	writeList(&b, pods, a.cfg.MaxListedPods)
+	aggregated.Context = b.String()
+	return &aggregated
+}
+
+// Run prunes groups whose cover period has ended until ctx is done. Once Run
+// returns, pending aggregated events are discarded instead of blocking.
+func (a *NodeAggregator) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	defer a.close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.prune()
+		}
+	}
+}
+
+// prune removes flushed groups whose cover period has ended.
+func (a *NodeAggregator) prune() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	for key, g := range a.groups {
+		if g.flushed && !now.Before(g.coverTill) {
+			delete(a.groups, key)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package aggregation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+func nodeFault(node, faultType string) *events.FaultEvent {
+	return &events.FaultEvent{
+		FaultID:   "node-" + node,
+		Resource:  &events.ResourceInfo{Kind: "Node", Name: node},
+		FaultType: faultType,
+		Severity:  "WARNING",
+		Context:   "Node condition changed",
+	}
+}
+
+func podFault(namespace, name, node, severity string) *events.FaultEvent {
+	return &events.FaultEvent{
+		FaultID:   "pod-" + name,
+		Resource:  &events.ResourceInfo{Kind: "Pod", Namespace: namespace, Name: name, Node: node},
+		FaultType: "CrashLoopBackOff",
+		Severity:  severity,
+	}
+}
+
+// newTestAggregator returns an aggregator whose window timers never fire on their own.
+func newTestAggregator(now *time.Time) *NodeAggregator {
+	a := NewNodeAggregator(NodeAggregatorConfig{Window: time.Minute, CoverFor: 10 * time.Minute})
+	a.now = func() time.Time { return *now }
+	a.afterFunc = func(time.Duration, func()) *time.Timer { return nil }
+	return a
+}
+
+func TestIsNodeCondition(t *testing.T) {
+	tests := []struct {
+		event *events.FaultEvent
+		want  bool
+	}{
+		{nodeFault("n1", "NodeNotReady"), true},
+		{nodeFault("n1", "MemoryPressure"), true},
+		{nodeFault("n1", "DiskPressure"), true},
+		{nodeFault("n1", "CordonedNode"), false},
+		{podFault("default", "web", "n1", "ERROR"), false},
+	}
+	for _, tt := range tests {
+		if got := IsNodeCondition(tt.event); got != tt.want {
+			t.Errorf("IsNodeCondition(%s %s) = %v, want %v", tt.event.GetResourceKind(), tt.event.FaultType, got, tt.want)
+		}
+	}
+}
+
+func TestNodeAggregator_FoldsPodFaults(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := newTestAggregator(&now)
+
+	if !a.Submit("prod", nodeFault("node-1", "NodeNotReady")) {
+		t.Fatal("node condition should be absorbed")
+	}
+	if !a.Submit("prod", podFault("default", "web", "node-1", "ERROR")) {
+		t.Error("pod fault on degraded node should be absorbed")
+	}
+	if !a.Submit("prod", podFault("payments", "api", "node-1", "CRITICAL")) {
+		t.Error("pod fault on degraded node should be absorbed")
+	}
+	if a.Submit("prod", podFault("default", "db", "node-2", "ERROR")) {
+		t.Error("pod fault on healthy node should not be absorbed")
+	}
+	if a.Submit("staging", podFault("default", "web", "node-1", "ERROR")) {
+		t.Error("pod fault in another cluster should not be absorbed")
+	}
+	if a.Submit("prod", podFault("default", "web", "", "ERROR")) {
+		t.Error("pod fault without node should not be absorbed")
+	}
+
+	a.flush(nodeKey{cluster: "prod", node: "node-1"})
+
+	select {
+	case out := <-a.Output():
+		if out.Cluster != "prod" {
+			t.Errorf("Cluster = %q, want prod", out.Cluster)
+		}
+		if out.Event.GetResourceKind() != "Node" || out.Event.GetResourceName() != "node-1" {
+			t.Errorf("aggregated resource = %s/%s", out.Event.GetResourceKind(), out.Event.GetResourceName())
+		}
+		if out.Event.Severity != "CRITICAL" {
+			t.Errorf("Severity = %q, want highest pod severity CRITICAL", out.Event.Severity)
+		}
+		for _, want := range []string{"2 pod fault(s)", "default/web Pod (CrashLoopBackOff)", "payments/api Pod"} {
+			if !strings.Contains(out.Event.Context, want) {
+				t.Errorf("Context missing %q:\n%s", want, out.Event.Context)
+			}
+		}
+	default:
+		t.Fatal("expected aggregated event on Output")
+	}
+}
+
+func TestNodeAggregator_CoverPeriod(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := newTestAggregator(&now)
+
+	a.Submit("prod", nodeFault("node-1", "MemoryPressure"))
+	a.flush(nodeKey{cluster: "prod", node: "node-1"})
+	<-a.Output()
+
+	now = now.Add(5 * time.Minute)
+	if !a.Submit("prod", podFault("default", "late", "node-1", "ERROR")) {
+		t.Error("pod fault within cover period should be suppressed")
+	}
+
+	now = now.Add(10 * time.Minute)
+	if a.Submit("prod", podFault("default", "later", "node-1", "ERROR")) {
+		t.Error("pod fault after cover period should be processed individually")
+	}
+
+	a.prune()
+	if len(a.groups) != 0 {
+		t.Errorf("expected expired group to be pruned, have %d", len(a.groups))
+	}
+}
+
+func TestNodeAggregator_ListCap(t *testing.T) {
+	now := time.Now()
+	a := newTestAggregator(&now)
+	a.cfg.MaxListedPods = 2
+
+	a.Submit("prod", nodeFault("node-1", "NodeNotReady"))
+	for _, name := range []string{"a", "b", "c", "d"} {
+		a.Submit("prod", podFault("default", name, "node-1", "ERROR"))
+	}
+	a.flush(nodeKey{cluster: "prod", node: "node-1"})
+
+	out := <-a.Output()
+	if !strings.Contains(out.Event.Context, "... and 2 more") {
+		t.Errorf("Context should note truncated pods:\n%s", out.Event.Context)
+	}
+}
@@ -0,0 +1,165 @@
+package backfill
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// alertResourceLabels maps the labels identifying the alerting Kubernetes resource
+// to its kind, most specific first
+var alertResourceLabels = []struct {
+	label string
+	kind  string
+}{
+	{"pod", "Pod"},
+	{"deployment", "Deployment"},
+	{"statefulset", "StatefulSet"},
+	{"daemonset", "DaemonSet"},
+	{"job_name", "Job"},
+	{"persistentvolumeclaim", "PersistentVolumeClaim"},
+	{"node", "Node"},
+}
+
+// alertSeverities maps Prometheus alert severity labels to fault severities
+var alertSeverities = map[string]string{
+	"critical": events.SeverityCritical,
+	"error":    events.SeverityError,
+	"warning":  events.SeverityWarning,
+	"info":     events.SeverityInfo,
+}
+
+// AlertmanagerConfig configures an Alertmanager source.
+type AlertmanagerConfig struct {
+	// URL is the Alertmanager base URL, e.g. "http://alertmanager.monitoring:9093"
+	URL string
+	// Matchers filter the alerts, e.g. `cluster="prod"`
+	Matchers []string
+	// Token is sent as a bearer token when set
+	Token string
+}
+
+// AlertmanagerSource finds fault events in Alertmanager alerts. Alertmanager keeps
+// alerts only while they fire (and briefly after they resolve), so it finds the
+// faults of an outage that are still ongoing.
+type AlertmanagerSource struct {
+	config     AlertmanagerConfig
+	httpClient *http.Client
+}
+
+// NewAlertmanagerSource returns an Alertmanager source. A nil httpClient uses
+// http.DefaultClient.
+func NewAlertmanagerSource(config AlertmanagerConfig, httpClient *http.Client) *AlertmanagerSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &AlertmanagerSource{config: config, httpClient: httpClient}
+}
+
+// Name implements Source.
+func (s *AlertmanagerSource) Name() string {
+	return "alertmanager"
+}
+
+// Alert is the subset of an Alertmanager v2 alert (API or webhook payload) used to
+// derive a fault event.
+type Alert struct {
+	Fingerprint string            `json:"fingerprint"`
+	StartsAt    time.Time         `json:"startsAt"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// FaultEvents implements Source. Alerts that started outside the range are skipped.
+func (s *AlertmanagerSource) FaultEvents(ctx context.Context, from, to time.Time) ([]*events.FaultEvent, error) {
+	params := url.Values{}
+	for _, matcher := range s.config.Matchers {
+		params.Add("filter", matcher)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.config.URL+"/api/v2/alerts?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create alertmanager request: %w", err)
+	}
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alertmanager: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("alertmanager returned status %d: %s", resp.StatusCode, body)
+	}
+
+	var alerts []Alert
+	if err := json.NewDecoder(resp.Body).Decode(&alerts); err != nil {
+		return nil, fmt.Errorf("failed to decode alertmanager response: %w", err)
+	}
+
+	var found []*events.FaultEvent
+	for _, a := range alerts {
+		if !inRange(a.StartsAt, from, to) {
+			continue
+		}
+		found = append(found, AlertFaultEvent(a))
+	}
+	return found, nil
+}
+
+// AlertFaultEvent converts an alert to a fault event: the resource is taken from
+// the alert's Kubernetes labels, the fault type from alertname, and the severity
+// from the severity label.
+func AlertFaultEvent(a Alert) *events.FaultEvent {
+	resource := &events.ResourceInfo{Namespace: a.Labels["namespace"]}
+	for _, candidate := range alertResourceLabels {
+		if name := a.Labels[candidate.label]; name != "" {
+			resource.Kind, resource.Name = candidate.kind, name
+			break
+		}
+	}
+	if resource.Kind == "Node" {
+		resource.Node = resource.Name
+	} else {
+		resource.Node = a.Labels["node"]
+	}
+
+	severity, ok := alertSeverities[strings.ToLower(a.Labels["severity"])]
+	if !ok {
+		severity = events.SeverityWarning
+	}
+
+	description := a.Annotations["description"]
+	if description == "" {
+		description = a.Annotations["summary"]
+	}
+	if description == "" {
+		description = a.Annotations["message"]
+	}
+
+	id := a.Fingerprint
+	if id == "" {
+		id = faultID(a.Labels["alertname"], resource.Kind, resource.Namespace, resource.Name)
+	}
+
+	return &events.FaultEvent{
+		FaultID:   id,
+		Resource:  resource,
+		FaultType: a.Labels["alertname"],
+		Severity:  severity,
+		Context:   description,
+		Timestamp: a.StartsAt.UTC().Format(time.RFC3339Nano),
+		// Pass the alert's labels and annotations through as correlation keys
+		Labels:      a.Labels,
+		Annotations: a.Annotations,
+	}
+}
@@ -0,0 +1,63 @@
+package backfill
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+func TestAlertmanagerSource_FaultEvents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/alerts" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query()["filter"]; len(got) != 1 || got[0] != `cluster="prod"` {
+			t.Errorf("filter = %v, want [cluster=\"prod\"]", got)
+		}
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("Authorization = %q, want the bearer token", r.Header.Get("Authorization"))
+		}
+		_, _ = w.Write([]byte(`[
+			{"fingerprint": "abc", "startsAt": "2026-01-10T02:30:00Z",
+			 "labels": {"alertname": "KubePodCrashLooping", "severity": "critical", "namespace": "shop", "pod": "api-1", "node": "node-a"},
+			 "annotations": {"summary": "api-1 is crash looping"}},
+			{"fingerprint": "old", "startsAt": "2026-01-09T00:00:00Z", "labels": {"alertname": "Old"}},
+			{"startsAt": "2026-01-10T03:00:00Z", "labels": {"alertname": "KubeNodeNotReady", "node": "node-b", "severity": "page"}}
+		]`))
+	}))
+	defer srv.Close()
+
+	source := NewAlertmanagerSource(AlertmanagerConfig{URL: srv.URL, Matchers: []string{`cluster="prod"`}, Token: "tok"}, nil)
+	from := time.Date(2026, 1, 10, 2, 0, 0, 0, time.UTC)
+	found, err := source.FaultEvents(t.Context(), from, from.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("FaultEvents() error = %v", err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("FaultEvents() found %d events, want 2 (alert before the range skipped)", len(found))
+	}
+
+	pod := found[0]
+	if pod.FaultID != "abc" || pod.FaultType != "KubePodCrashLooping" || pod.Severity != events.SeverityCritical ||
+		pod.Context != "api-1 is crash looping" || pod.Timestamp != "2026-01-10T02:30:00Z" {
+		t.Errorf("pod alert event = %+v", pod)
+	}
+	if r := pod.Resource; r.Kind != "Pod" || r.Name != "api-1" || r.Namespace != "shop" || r.Node != "node-a" {
+		t.Errorf("pod alert resource = %+v", r)
+	}
+	if pod.Labels["alertname"] != "KubePodCrashLooping" || pod.Annotations["summary"] != "api-1 is crash looping" {
+		t.Errorf("pod alert labels = %v, annotations = %v, want the alert's passed through", pod.Labels, pod.Annotations)
+	}
+
+	node := found[1]
+	if node.FaultID == "" || node.Severity != events.SeverityWarning {
+		t.Errorf("node alert event = %+v, want a derived ID and WARNING for an unknown severity", node)
+	}
+	if r := node.Resource; r.Kind != "Node" || r.Name != "node-b" || r.Node != "node-b" {
+		t.Errorf("node alert resource = %+v", r)
+	}
+}
@@ -0,0 +1,163 @@
+package backfill
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// lokiPageSize is the number of log lines requested per Loki query
+const lokiPageSize = 1000
+
+// LokiConfig configures a Loki source.
+type LokiConfig struct {
+	// URL is the Loki base URL, e.g. "http://loki.monitoring:3100"
+	URL string
+	// Query is the LogQL query selecting the fault event log lines, e.g.
+	// `{app="kubernetes-mcp-server"} |= "faultType"`. Each line must be a fault
+	// event in JSON, or a JSON log record carrying one in its "event" or "data" field.
+	Query string
+	// Token is sent as a bearer token when set
+	Token string
+	// OrgID is sent as the X-Scope-OrgID tenant header when set
+	OrgID string
+}
+
+// LokiSource finds fault events in log lines stored in Loki, e.g. the fault
+// notifications logged by kubernetes-mcp-server.
+type LokiSource struct {
+	config     LokiConfig
+	httpClient *http.Client
+}
+
+// NewLokiSource returns a Loki source. A nil httpClient uses http.DefaultClient.
+func NewLokiSource(config LokiConfig, httpClient *http.Client) *LokiSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &LokiSource{config: config, httpClient: httpClient}
+}
+
+// Name implements Source.
+func (s *LokiSource) Name() string {
+	return "loki"
+}
+
+// lokiResponse is the subset of a Loki query_range response used for backfill.
+type lokiResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			// Values are [timestamp in nanoseconds, log line] pairs
+			Values [][2]string `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// FaultEvents implements Source. Lines that are not fault events are skipped.
+func (s *LokiSource) FaultEvents(ctx context.Context, from, to time.Time) ([]*events.FaultEvent, error) {
+	var found []*events.FaultEvent
+	start := from
+	for {
+		resp, err := s.query(ctx, start, to)
+		if err != nil {
+			return nil, err
+		}
+
+		lines := 0
+		var last time.Time
+		for _, stream := range resp.Data.Result {
+			for _, value := range stream.Values {
+				lines++
+				ns, err := strconv.ParseInt(value[0], 10, 64)
+				if err != nil {
+					continue
+				}
+				ts := time.Unix(0, ns)
+				if ts.After(last) {
+					last = ts
+				}
+				if event := parseLogLine(value[1]); event != nil {
+					if event.Timestamp == "" {
+						event.Timestamp = ts.UTC().Format(time.RFC3339Nano)
+					}
+					found = append(found, event)
+				}
+			}
+		}
+
+		// A full page means there may be more lines after the last one
+		if lines < lokiPageSize || last.IsZero() {
+			return found, nil
+		}
+		start = last.Add(time.Nanosecond)
+	}
+}
+
+// query runs one query_range request for the lines between start and end.
+func (s *LokiSource) query(ctx context.Context, start, end time.Time) (*lokiResponse, error) {
+	params := url.Values{}
+	params.Set("query", s.config.Query)
+	params.Set("start", strconv.FormatInt(start.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(end.UnixNano(), 10))
+	params.Set("limit", strconv.Itoa(lokiPageSize))
+	params.Set("direction", "forward")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.config.URL+"/loki/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create loki request: %w", err)
+	}
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	}
+	if s.config.OrgID != "" {
+		req.Header.Set("X-Scope-OrgID", s.config.OrgID)
+	}
+
+	httpResp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query loki: %w", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(httpResp.Body, 1024))
+		return nil, fmt.Errorf("loki returned status %d: %s", httpResp.StatusCode, body)
+	}
+
+	var resp lokiResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode loki response: %w", err)
+	}
+	if resp.Data.ResultType != "" && resp.Data.ResultType != "streams" {
+		return nil, fmt.Errorf("loki query returned %s instead of log lines; use a log query, not a metric query", resp.Data.ResultType)
+	}
+	return &resp, nil
+}
+
+// parseLogLine returns the fault event in a log line, or nil when it has none.
+func parseLogLine(line string) *events.FaultEvent {
+	var event events.FaultEvent
+	if err := json.Unmarshal([]byte(line), &event); err == nil && event.FaultType != "" {
+		return &event
+	}
+
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return nil
+	}
+	for _, field := range []string{"event", "data"} {
+		var nested events.FaultEvent
+		if raw, ok := record[field]; ok && json.Unmarshal(raw, &nested) == nil && nested.FaultType != "" {
+			return &nested
+		}
+	}
+	return nil
+}
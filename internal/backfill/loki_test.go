@@ -0,0 +1,78 @@
+package backfill
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLokiSource_FaultEvents(t *testing.T) {
+	base := time.Date(2026, 1, 10, 2, 0, 0, 0, time.UTC)
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/query_range" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("X-Scope-OrgID") != "tenant" {
+			t.Errorf("X-Scope-OrgID = %q, want tenant", r.Header.Get("X-Scope-OrgID"))
+		}
+		queries = append(queries, r.URL.Query().Get("start"))
+
+		// The first page is full, the second holds the remaining line
+		start, _ := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		var values [][2]string
+		if start == base.UnixNano() {
+			for i := 0; i < lokiPageSize; i++ {
+				values = append(values, [2]string{fmt.Sprint(base.Add(time.Duration(i) * time.Second).UnixNano()), "not json"})
+			}
+			values[0][1] = `{"faultId":"f1","faultType":"CrashLoop","severity":"ERROR","resource":{"kind":"Pod","name":"api"}}`
+			values[1][1] = `{"level":"info","event":{"faultId":"f2","faultType":"OOMKilled","timestamp":"2026-01-10T02:00:01Z"}}`
+		} else {
+			values = append(values, [2]string{fmt.Sprint(base.Add(time.Hour).UnixNano()), `{"data":{"faultId":"f3","faultType":"ImagePull"}}`})
+		}
+		resp := map[string]interface{}{
+			"status": "success",
+			"data": map[string]interface{}{
+				"resultType": "streams",
+				"result":     []interface{}{map[string]interface{}{"values": values}},
+			},
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	source := NewLokiSource(LokiConfig{URL: srv.URL, Query: `{app="mcp"}`, OrgID: "tenant"}, nil)
+	found, err := source.FaultEvents(t.Context(), base, base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("FaultEvents() error = %v", err)
+	}
+	if len(queries) != 2 {
+		t.Errorf("queries = %d, want 2 (one per page)", len(queries))
+	}
+	if len(found) != 3 {
+		t.Fatalf("FaultEvents() found %d events, want 3", len(found))
+	}
+	if found[0].FaultID != "f1" || found[0].Timestamp != "2026-01-10T02:00:00Z" {
+		t.Errorf("first event = %+v, want f1 stamped with its log line time", found[0])
+	}
+	if found[1].FaultID != "f2" || found[2].FaultID != "f3" {
+		t.Errorf("nested events = %s, %s, want f2, f3", found[1].FaultID, found[2].FaultID)
+	}
+}
+
+func TestLokiSource_RejectsMetricQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+	}))
+	defer srv.Close()
+
+	source := NewLokiSource(LokiConfig{URL: srv.URL, Query: `rate({app="mcp"}[5m])`}, nil)
+	if _, err := source.FaultEvents(t.Context(), time.Now().Add(-time.Hour), time.Now()); err == nil {
+		t.Error("FaultEvents() should reject a metric query")
+	}
+}
@@ -0,0 +1,125 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rbias/nightcrier/internal/events"
+	"go.yaml.in/yaml/v3"
+)
+
+// eventsListTool is the kubernetes-mcp-server tool listing Kubernetes events
+const eventsListTool = "events_list"
+
+// kubeEventTimeLayout is how kubernetes-mcp-server formats event timestamps
+// (Go's time.Time.String)
+const kubeEventTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// MCPSource finds fault events in the Kubernetes events listed by the cluster's
+// kubernetes-mcp-server: every Warning event becomes a fault. Kubernetes keeps
+// events for an hour by default (the API server's --event-ttl), so it only
+// reaches back that far.
+type MCPSource struct {
+	endpoint   string
+	namespace  string
+	httpClient *http.Client
+}
+
+// NewMCPSource returns a source listing the events of namespace (empty: all
+// namespaces) through the MCP server at endpoint. A nil httpClient uses
+// http.DefaultClient.
+func NewMCPSource(endpoint, namespace string, httpClient *http.Client) *MCPSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &MCPSource{endpoint: endpoint, namespace: namespace, httpClient: httpClient}
+}
+
+// Name implements Source.
+func (s *MCPSource) Name() string {
+	return "mcp"
+}
+
+// kubeEvent is a Kubernetes event as listed by kubernetes-mcp-server.
+type kubeEvent struct {
+	Namespace      string `yaml:"Namespace"`
+	Timestamp      string `yaml:"Timestamp"`
+	Type           string `yaml:"Type"`
+	Reason         string `yaml:"Reason"`
+	Message        string `yaml:"Message"`
+	InvolvedObject struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"Kind"`
+		Name       string `yaml:"Name"`
+	} `yaml:"InvolvedObject"`
+}
+
+// FaultEvents implements Source.
+func (s *MCPSource) FaultEvents(ctx context.Context, from, to time.Time) ([]*events.FaultEvent, error) {
+	client := mcp.NewClient(&mcp.Implementation{Name: "nightcrier-backfill", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{
+		Endpoint:   s.endpoint,
+		HTTPClient: s.httpClient,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
+	}
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      eventsListTool,
+		Arguments: map[string]any{"namespace": s.namespace},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", eventsListTool, err)
+	}
+	var text strings.Builder
+	for _, content := range result.Content {
+		if textContent, ok := content.(*mcp.TextContent); ok {
+			text.WriteString(textContent.Text)
+		}
+	}
+	if result.IsError {
+		return nil, fmt.Errorf("%s returned error: %s", eventsListTool, text.String())
+	}
+
+	return parseKubeEvents(text.String(), from, to)
+}
+
+// parseKubeEvents converts the Warning events of an events_list result that
+// occurred between from and to to fault events.
+func parseKubeEvents(text string, from, to time.Time) ([]*events.FaultEvent, error) {
+	var listed []kubeEvent
+	if err := yaml.Unmarshal([]byte(text), &listed); err != nil {
+		return nil, fmt.Errorf("failed to parse %s result: %w", eventsListTool, err)
+	}
+
+	var found []*events.FaultEvent
+	for _, ev := range listed {
+		if ev.Type != "Warning" {
+			continue
+		}
+		ts, err := time.Parse(kubeEventTimeLayout, ev.Timestamp)
+		if err != nil || !inRange(ts, from, to) {
+			continue
+		}
+		found = append(found, &events.FaultEvent{
+			FaultID: faultID(ev.InvolvedObject.Kind, ev.Namespace, ev.InvolvedObject.Name, ev.Reason, ev.Timestamp),
+			Resource: &events.ResourceInfo{
+				APIVersion: ev.InvolvedObject.APIVersion,
+				Kind:       ev.InvolvedObject.Kind,
+				Name:       ev.InvolvedObject.Name,
+				Namespace:  ev.Namespace,
+			},
+			FaultType: ev.Reason,
+			Severity:  events.SeverityWarning,
+			Context:   ev.Message,
+			Timestamp: ts.UTC().Format(time.RFC3339Nano),
+		})
+	}
+	return found, nil
+}
@@ -0,0 +1,57 @@
+package backfill
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseKubeEvents(t *testing.T) {
+	text := `- InvolvedObject:
+    Kind: Pod
+    Name: api-1
+    apiVersion: v1
+  Message: Back-off restarting failed container
+  Namespace: shop
+  Reason: BackOff
+  Timestamp: 2026-01-10 02:30:00 +0000 UTC
+  Type: Warning
+- InvolvedObject:
+    Kind: Pod
+    Name: api-1
+    apiVersion: v1
+  Message: Started container
+  Namespace: shop
+  Reason: Started
+  Timestamp: 2026-01-10 02:31:00 +0000 UTC
+  Type: Normal
+- InvolvedObject:
+    Kind: Pod
+    Name: db-0
+    apiVersion: v1
+  Message: Liveness probe failed
+  Namespace: shop
+  Reason: Unhealthy
+  Timestamp: 2026-01-09 23:00:00 +0000 UTC
+  Type: Warning
+`
+	from := time.Date(2026, 1, 10, 2, 0, 0, 0, time.UTC)
+	found, err := parseKubeEvents(text, from, from.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("parseKubeEvents() error = %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("parseKubeEvents() found %d events, want 1 (Normal and out-of-range events skipped)", len(found))
+	}
+	event := found[0]
+	if event.FaultType != "BackOff" || event.Context != "Back-off restarting failed container" ||
+		event.Timestamp != "2026-01-10T02:30:00Z" || event.FaultID == "" {
+		t.Errorf("event = %+v", event)
+	}
+	if r := event.Resource; r.Kind != "Pod" || r.Name != "api-1" || r.Namespace != "shop" || r.APIVersion != "v1" {
+		t.Errorf("resource = %+v", r)
+	}
+
+	if _, err := parseKubeEvents("not: [a list", from, from); err == nil {
+		t.Error("parseKubeEvents() should fail on invalid YAML")
+	}
+}
@@ -0,0 +1,66 @@
+// Package backfill finds the fault events of a past time range in an external
+// system (the cluster's MCP server, Loki, or Alertmanager), so investigations can
+// be run for faults that occurred while nightcrier was down.
+package backfill
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// Source finds past fault events of one cluster.
+type Source interface {
+	// Name identifies the source in logs and errors, e.g. "loki"
+	Name() string
+	// FaultEvents returns the fault events that occurred between from and to.
+	FaultEvents(ctx context.Context, from, to time.Time) ([]*events.FaultEvent, error)
+}
+
+// Prepare readies the events found by a source for investigation: it assigns them
+// to cluster, drops repeats of the same fault (keeping the first occurrence, by
+// fault signature), and orders them oldest first.
+func Prepare(cluster string, found []*events.FaultEvent) []*events.FaultEvent {
+	for _, event := range found {
+		event.Cluster = cluster
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return eventTime(found[i]).Before(eventTime(found[j]))
+	})
+
+	seen := make(map[string]bool)
+	prepared := make([]*events.FaultEvent, 0, len(found))
+	for _, event := range found {
+		signature := events.FaultSignature(cluster, event)
+		if seen[signature] {
+			continue
+		}
+		seen[signature] = true
+		prepared = append(prepared, event)
+	}
+	return prepared
+}
+
+// eventTime returns when a fault occurred, or the zero time when its timestamp
+// cannot be parsed.
+func eventTime(event *events.FaultEvent) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, event.Timestamp)
+	return t
+}
+
+// inRange reports whether t is within [from, to].
+func inRange(t, from, to time.Time) bool {
+	return !t.Before(from) && !t.After(to)
+}
+
+// faultID derives a stable fault ID from the parts identifying a fault condition,
+// for sources whose events carry none.
+func faultID(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,31 @@
+package backfill
+
+import (
+	"testing"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+func TestPrepare(t *testing.T) {
+	pod := func(name string) *events.ResourceInfo {
+		return &events.ResourceInfo{Kind: "Pod", Namespace: "default", Name: name}
+	}
+	found := []*events.FaultEvent{
+		{FaultID: "late", Resource: pod("api"), FaultType: "CrashLoop", Timestamp: "2026-01-10T03:00:00Z"},
+		{FaultID: "early", Resource: pod("api"), FaultType: "CrashLoop", Timestamp: "2026-01-10T02:00:00Z"},
+		{FaultID: "other", Resource: pod("db"), FaultType: "CrashLoop", Timestamp: "2026-01-10T02:30:00Z"},
+	}
+
+	prepared := Prepare("prod", found)
+
+	var ids []string
+	for _, event := range prepared {
+		ids = append(ids, event.FaultID)
+		if event.Cluster != "prod" {
+			t.Errorf("event %s cluster = %q, want prod", event.FaultID, event.Cluster)
+		}
+	}
+	if len(ids) != 2 || ids[0] != "early" || ids[1] != "other" {
+		t.Errorf("Prepare() = %v, want [early other] (oldest first, repeat dropped)", ids)
+	}
+}
@@ -0,0 +1,398 @@
+// Package budget enforces per-cluster daily limits on agent investigations.
+// A runaway cluster (crash-looping workloads, noisy alerts) can otherwise trigger
+// hundreds of LLM-backed investigations a day and exhaust the monthly AI budget.
+//
+// Each cluster may cap the number of investigations per day and the estimated LLM
+// spend per day. Spend is estimated from a configured cost per investigation because
+// the agent CLIs do not report token usage back to nightcrier.
+//
+// Usage is tracked per UTC day and persisted in the budget directory so that
+// restarts do not reset the counters. Operators can grant additional headroom for
+// the current day with the "nightcrier budget override" command, which writes an
+// overrides file that the running daemon re-reads on every check.
+//
+// In multi-instance deployments the usage can be kept in the fleet-wide state of
+// the SQL state store (see Tracker.Share), so the limits apply to the fleet.
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/fleet"
+)
+
+const (
+	// overridesFile holds operator overrides; it is written only by the CLI.
+	overridesFile = "overrides.json"
+
+	// sharedTimeout bounds one check against the fleet-wide usage
+	sharedTimeout = 5 * time.Second
+
+	// sharedTTL is how long a day's fleet-wide usage is kept
+	sharedTTL = 48 * time.Hour
+
+	// dayFormat is the layout used for budget days (UTC).
+	dayFormat = "2006-01-02"
+)
+
+// Limits are the daily budget limits for one cluster. Zero values mean unlimited.
+type Limits struct {
+	// MaxInvestigationsPerDay caps the number of agent investigations per UTC day.
+	MaxInvestigationsPerDay int
+
+	// MaxSpendPerDay caps the estimated LLM spend (USD) per UTC day.
+	MaxSpendPerDay float64
+
+	// EstimatedCostPerInvestigation is the estimated LLM spend (USD) charged for each investigation.
+	EstimatedCostPerInvestigation float64
+}
+
+// Enabled reports whether any limit is configured.
+func (l Limits) Enabled() bool {
+	return l.MaxInvestigationsPerDay > 0 || l.MaxSpendPerDay > 0
+}
+
+// Validate checks that the limits are non-negative.
+func (l Limits) Validate() error {
+	if l.MaxInvestigationsPerDay < 0 {
+		return fmt.Errorf("max_investigations_per_day must be >= 0, got %d", l.MaxInvestigationsPerDay)
+	}
+	if l.MaxSpendPerDay < 0 {
+		return fmt.Errorf("max_spend_per_day_usd must be >= 0, got %g", l.MaxSpendPerDay)
+	}
+	if l.EstimatedCostPerInvestigation < 0 {
+		return fmt.Errorf("estimated_cost_per_investigation_usd must be >= 0, got %g", l.EstimatedCostPerInvestigation)
+	}
+	if l.MaxSpendPerDay > 0 && l.EstimatedCostPerInvestigation == 0 {
+		return fmt.Errorf("max_spend_per_day_usd requires estimated_cost_per_investigation_usd to be set")
+	}
+	return nil
+}
+
+// Usage is the budget consumed by one cluster on one day.
+type Usage struct {
+	Investigations int     `json:"investigations"`
+	EstimatedSpend float64 `json:"estimated_spend_usd"`
+}
+
+// Override grants additional budget to a cluster for a single UTC day.
+type Override struct {
+	Cluster             string    `json:"cluster"`
+	Day                 string    `json:"day"`
+	ExtraInvestigations int       `json:"extra_investigations,omitempty"`
+	ExtraSpend          float64   `json:"extra_spend_usd,omitempty"`
+	Unlimited           bool      `json:"unlimited,omitempty"`
+	Reason              string    `json:"reason,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// Decision is the result of a budget check.
+type Decision struct {
+	// Allowed is true when the investigation may run (and has been charged).
+	Allowed bool
+
+	// Reason explains why the investigation was denied.
+	Reason string
+
+	// Usage is the cluster's usage for the day after this decision.
+	Usage Usage
+
+	// FirstDenial is true for the first denial of the day for this cluster, so
+	// callers can send a single budget-exhausted notification.
+	FirstDenial bool
+}
+
+// Tracker records per-cluster daily usage and enforces limits.
+// It is safe for concurrent use.
+type Tracker struct {
+	mu       sync.Mutex
+	dir      string
+	day      string
+	usage    map[string]*Usage
+	notified map[string]bool
+	// shared keeps usage fleet-wide; nil keeps it in this process
+	shared *fleet.State
+
+	// now is replaceable for tests
+	now func() time.Time
+}
+
+// NewTracker creates a Tracker persisting usage in dir, loading today's usage if present.
+func NewTracker(dir string) (*Tracker, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create budget directory: %w", err)
+	}
+	t := &Tracker{
+		dir: dir,
+		now: time.Now,
+	}
+	if err := t.rollover(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Day returns the UTC budget day for a point in time.
+func Day(ts time.Time) string {
+	return ts.UTC().Format(dayFormat)
+}
+
+// rollover switches to the current day's counters, loading them from disk. Caller holds mu (or is the constructor).
+func (t *Tracker) rollover() error {
+	day := Day(t.now())
+	if day == t.day {
+		return nil
+	}
+	usage, err := LoadUsage(t.dir, day)
+	if err != nil {
+		return err
+	}
+	t.day = day
+	t.usage = usage
+	t.notified = make(map[string]bool)
+	return nil
+}
+
+// Reserve checks the cluster's budget and, if allowed, charges one investigation.
+// Failures to read overrides or persist usage are returned alongside an allowed
+// decision; budget bookkeeping must never block an investigation on I/O errors.
+func (t *Tracker) Reserve(cluster string, limits Limits) (Decision, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := t.rollover(); err != nil {
+		return Decision{Allowed: true}, err
+	}
+
+	u := t.usage[cluster]
+	if u == nil {
+		u = &Usage{}
+		t.usage[cluster] = u
+	}
+
+	var override Override
+	if limits.Enabled() {
+		overrides, err := LoadOverrides(t.dir)
+		if err != nil {
+			return Decision{Allowed: true, Usage: *u}, err
+		}
+		override = overrides.For(cluster, t.day)
+	}
+
+	if t.shared != nil {
+		decision, err := t.reserveShared(cluster, limits, override)
+		if err == nil {
+			*u = decision.Usage
+			return decision, t.saveLocked()
+		}
+		slog.Warn("fleet-wide budget unavailable, charging per process",
+			"cluster", cluster,
+			"error", err)
+	}
+
+	if limits.Enabled() {
+		if reason := exceeded(*u, limits, override); reason != "" {
+			first := !t.notified[cluster]
+			t.notified[cluster] = true
+			return Decision{Reason: reason, Usage: *u, FirstDenial: first}, nil
+		}
+	}
+
+	u.Investigations++
+	u.EstimatedSpend += limits.EstimatedCostPerInvestigation
+	return Decision{Allowed: true, Usage: *u}, t.saveLocked()
+}
+
+// sharedUsage is the JSON document of a cluster's usage on one day in the
+// fleet-wide state.
+type sharedUsage struct {
+	Usage
+	// Notified is set by the first denial of the day
+	Notified bool `json:"notified,omitempty"`
+}
+
+// Share keeps usage in the fleet-wide state, so the limits apply to the
+// investigations of every nightcrier process using the same state store. Each
+// process still mirrors the usage it sees into its budget directory, and reads
+// overrides from there.
+func (t *Tracker) Share(state *fleet.State) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.shared = state
+}
+
+// reserveShared checks and charges the cluster's fleet-wide usage for the current
+// day. Caller holds mu.
+func (t *Tracker) reserveShared(cluster string, limits Limits, override Override) (Decision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+	defer cancel()
+
+	var decision Decision
+	err := fleet.Update(ctx, t.shared, "budget/"+t.day+"/"+cluster, sharedTTL, func(current *sharedUsage) (*sharedUsage, error) {
+		if current == nil {
+			current = &sharedUsage{}
+		}
+		if limits.Enabled() {
+			if reason := exceeded(current.Usage, limits, override); reason != "" {
+				decision = Decision{Reason: reason, Usage: current.Usage, FirstDenial: !current.Notified}
+				if current.Notified {
+					return nil, nil
+				}
+				current.Notified = true
+				return current, nil
+			}
+		}
+		current.Investigations++
+		current.EstimatedSpend += limits.EstimatedCostPerInvestigation
+		decision = Decision{Allowed: true, Usage: current.Usage}
+		return current, nil
+	})
+	return decision, err
+}
+
+// exceeded returns a non-empty reason when charging another investigation would exceed limits.
+func exceeded(u Usage, limits Limits, ov Override) string {
+	if ov.Unlimited {
+		return ""
+	}
+	if limit := limits.MaxInvestigationsPerDay; limit > 0 {
+		limit += ov.ExtraInvestigations
+		if u.Investigations+1 > limit {
+			return fmt.Sprintf("daily investigation budget exhausted (%d/%d)", u.Investigations, limit)
+		}
+	}
+	if limit := limits.MaxSpendPerDay; limit > 0 {
+		limit += ov.ExtraSpend
+		if u.EstimatedSpend+limits.EstimatedCostPerInvestigation > limit {
+			return fmt.Sprintf("daily estimated spend budget exhausted ($%.2f/$%.2f)", u.EstimatedSpend, limit)
+		}
+	}
+	return ""
+}
+
+// Usage returns the cluster's usage for the current day.
+func (t *Tracker) Usage(cluster string) Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.rollover(); err != nil {
+		return Usage{}
+	}
+	if u := t.usage[cluster]; u != nil {
+		return *u
+	}
+	return Usage{}
+}
+
+// saveLocked persists the current day's usage. Caller holds mu.
+func (t *Tracker) saveLocked() error {
+	return writeJSON(usagePath(t.dir, t.day), t.usage)
+}
+
+// usagePath returns the usage file for a day.
+func usagePath(dir, day string) string {
+	return filepath.Join(dir, fmt.Sprintf("usage-%s.json", day))
+}
+
+// LoadUsage reads the per-cluster usage for a day. A missing file yields empty usage.
+func LoadUsage(dir, day string) (map[string]*Usage, error) {
+	usage := make(map[string]*Usage)
+	data, err := os.ReadFile(usagePath(dir, day))
+	if errors.Is(err, os.ErrNotExist) {
+		return usage, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read budget usage: %w", err)
+	}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, fmt.Errorf("failed to parse budget usage: %w", err)
+	}
+	return usage, nil
+}
+
+// Overrides is the set of operator overrides.
+type Overrides []Override
+
+// For returns the combined override for a cluster and day.
+func (o Overrides) For(cluster, day string) Override {
+	combined := Override{Cluster: cluster, Day: day}
+	for _, ov := range o {
+		if ov.Cluster != cluster || ov.Day != day {
+			continue
+		}
+		combined.ExtraInvestigations += ov.ExtraInvestigations
+		combined.ExtraSpend += ov.ExtraSpend
+		combined.Unlimited = combined.Unlimited || ov.Unlimited
+	}
+	return combined
+}
+
+// LoadOverrides reads the overrides file from dir. A missing file yields no overrides.
+func LoadOverrides(dir string) (Overrides, error) {
+	data, err := os.ReadFile(filepath.Join(dir, overridesFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read budget overrides: %w", err)
+	}
+	var overrides Overrides
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse budget overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// AddOverride appends an override to the overrides file in dir. Overrides for
+// days before the override's day are pruned so the file does not grow forever.
+func AddOverride(dir string, ov Override) error {
+	if ov.Cluster == "" {
+		return fmt.Errorf("override cluster is required")
+	}
+	if _, err := time.Parse(dayFormat, ov.Day); err != nil {
+		return fmt.Errorf("invalid override day %q: must be YYYY-MM-DD", ov.Day)
+	}
+	if ov.ExtraInvestigations < 0 || ov.ExtraSpend < 0 {
+		return fmt.Errorf("override amounts must be >= 0")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create budget directory: %w", err)
+	}
+
+	existing, err := LoadOverrides(dir)
+	if err != nil {
+		return err
+	}
+	kept := make(Overrides, 0, len(existing)+1)
+	for _, e := range existing {
+		// Day strings in YYYY-MM-DD format sort chronologically
+		if e.Day >= ov.Day {
+			kept = append(kept, e)
+		}
+	}
+	kept = append(kept, ov)
+	return writeJSON(filepath.Join(dir, overridesFile), kept)
+}
+
+// writeJSON atomically writes v as indented JSON to path.
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
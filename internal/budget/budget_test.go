@@ -0,0 +1,226 @@
+package budget
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/fleet"
+)
+
+func newTestTracker(t *testing.T, now time.Time) *Tracker {
+	t.Helper()
+	tr, err := NewTracker(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+	tr.now = func() time.Time { return now }
+	tr.day = ""
+	if err := tr.rollover(); err != nil {
+		t.Fatalf("rollover() error = %v", err)
+	}
+	return tr
+}
+
+func TestTracker_InvestigationLimit(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tr := newTestTracker(t, now)
+	limits := Limits{MaxInvestigationsPerDay: 2}
+
+	for i := 0; i < 2; i++ {
+		d, err := tr.Reserve("prod", limits)
+		if err != nil {
+			t.Fatalf("Reserve() error = %v", err)
+		}
+		if !d.Allowed {
+			t.Fatalf("Reserve() #%d denied: %s", i+1, d.Reason)
+		}
+	}
+
+	d, err := tr.Reserve("prod", limits)
+	if err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if d.Allowed {
+		t.Fatal("third Reserve() should be denied")
+	}
+	if !d.FirstDenial {
+		t.Error("first denial should be flagged")
+	}
+	if !strings.Contains(d.Reason, "investigation budget exhausted") {
+		t.Errorf("Reason = %q", d.Reason)
+	}
+
+	d, _ = tr.Reserve("prod", limits)
+	if d.FirstDenial {
+		t.Error("second denial should not be flagged as first")
+	}
+
+	// Other clusters have their own budget
+	if d, _ := tr.Reserve("staging", limits); !d.Allowed {
+		t.Error("other cluster should not be affected")
+	}
+}
+
+func TestTracker_SpendLimit(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tr := newTestTracker(t, now)
+	limits := Limits{MaxSpendPerDay: 5, EstimatedCostPerInvestigation: 2}
+
+	for i := 0; i < 2; i++ {
+		if d, _ := tr.Reserve("prod", limits); !d.Allowed {
+			t.Fatalf("Reserve() #%d denied: %s", i+1, d.Reason)
+		}
+	}
+	d, _ := tr.Reserve("prod", limits)
+	if d.Allowed {
+		t.Fatal("Reserve() exceeding spend should be denied")
+	}
+	if d.Usage.EstimatedSpend != 4 {
+		t.Errorf("EstimatedSpend = %v, want 4", d.Usage.EstimatedSpend)
+	}
+}
+
+func TestTracker_UnlimitedWhenNoLimits(t *testing.T) {
+	tr := newTestTracker(t, time.Now())
+	for i := 0; i < 10; i++ {
+		if d, _ := tr.Reserve("prod", Limits{}); !d.Allowed {
+			t.Fatal("Reserve() without limits should always be allowed")
+		}
+	}
+	if got := tr.Usage("prod").Investigations; got != 10 {
+		t.Errorf("Investigations = %d, want 10", got)
+	}
+}
+
+func TestTracker_Override(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tr := newTestTracker(t, now)
+	limits := Limits{MaxInvestigationsPerDay: 1}
+
+	tr.Reserve("prod", limits)
+	if d, _ := tr.Reserve("prod", limits); d.Allowed {
+		t.Fatal("Reserve() should be denied before override")
+	}
+
+	if err := AddOverride(tr.dir, Override{Cluster: "prod", Day: "2025-03-01", ExtraInvestigations: 1}); err != nil {
+		t.Fatalf("AddOverride() error = %v", err)
+	}
+	if d, _ := tr.Reserve("prod", limits); !d.Allowed {
+		t.Fatalf("Reserve() should be allowed after override: %s", d.Reason)
+	}
+	if d, _ := tr.Reserve("prod", limits); d.Allowed {
+		t.Fatal("Reserve() should be denied once override is used up")
+	}
+
+	if err := AddOverride(tr.dir, Override{Cluster: "prod", Day: "2025-03-01", Unlimited: true}); err != nil {
+		t.Fatalf("AddOverride() error = %v", err)
+	}
+	if d, _ := tr.Reserve("prod", limits); !d.Allowed {
+		t.Fatal("Reserve() should be allowed with unlimited override")
+	}
+}
+
+func TestTracker_PersistsAndRollsOver(t *testing.T) {
+	dir := t.TempDir()
+	day1 := time.Date(2025, 3, 1, 23, 0, 0, 0, time.UTC)
+
+	tr, err := NewTracker(dir)
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+	tr.now = func() time.Time { return day1 }
+	tr.day = ""
+	tr.Reserve("prod", Limits{EstimatedCostPerInvestigation: 1.5})
+
+	// A new tracker (e.g. after restart) picks up the persisted usage
+	tr2, _ := NewTracker(dir)
+	tr2.now = func() time.Time { return day1 }
+	tr2.day = ""
+	if got := tr2.Usage("prod"); got.Investigations != 1 || got.EstimatedSpend != 1.5 {
+		t.Errorf("Usage after restart = %+v, want 1 investigation / 1.5 spend", got)
+	}
+
+	// The next day starts from zero
+	tr2.now = func() time.Time { return day1.Add(2 * time.Hour) }
+	if got := tr2.Usage("prod"); got.Investigations != 0 {
+		t.Errorf("Usage on next day = %+v, want zero", got)
+	}
+}
+
+func TestAddOverride_Validation(t *testing.T) {
+	dir := t.TempDir()
+	if err := AddOverride(dir, Override{Day: "2025-03-01"}); err == nil {
+		t.Error("AddOverride() without cluster should fail")
+	}
+	if err := AddOverride(dir, Override{Cluster: "prod", Day: "03/01/2025"}); err == nil {
+		t.Error("AddOverride() with invalid day should fail")
+	}
+}
+
+func TestAddOverride_PrunesOldDays(t *testing.T) {
+	dir := t.TempDir()
+	AddOverride(dir, Override{Cluster: "prod", Day: "2025-03-01", ExtraInvestigations: 1})
+	AddOverride(dir, Override{Cluster: "prod", Day: "2025-03-02", ExtraInvestigations: 2})
+
+	overrides, err := LoadOverrides(dir)
+	if err != nil {
+		t.Fatalf("LoadOverrides() error = %v", err)
+	}
+	if len(overrides) != 1 || overrides[0].Day != "2025-03-02" {
+		t.Errorf("overrides = %+v, want only 2025-03-02", overrides)
+	}
+}
+
+func TestLimits_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  Limits
+		wantErr bool
+	}{
+		{"empty", Limits{}, false},
+		{"investigations only", Limits{MaxInvestigationsPerDay: 10}, false},
+		{"spend with cost", Limits{MaxSpendPerDay: 10, EstimatedCostPerInvestigation: 0.5}, false},
+		{"spend without cost", Limits{MaxSpendPerDay: 10}, true},
+		{"negative investigations", Limits{MaxInvestigationsPerDay: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.limits.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTracker_SharedUsage(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	state := fleet.NewState(fleet.NewMemoryBackend())
+	first, second := newTestTracker(t, now), newTestTracker(t, now)
+	first.Share(state)
+	second.Share(state)
+	limits := Limits{MaxInvestigationsPerDay: 3, EstimatedCostPerInvestigation: 0.5}
+
+	// Three investigations across two processes exhaust the fleet-wide budget
+	for i, tr := range []*Tracker{first, second, first} {
+		if d, err := tr.Reserve("prod", limits); err != nil || !d.Allowed {
+			t.Fatalf("Reserve() #%d = %+v, %v, want allowed", i+1, d, err)
+		}
+	}
+	d, err := second.Reserve("prod", limits)
+	if err != nil || d.Allowed || !d.FirstDenial || d.Usage.Investigations != 3 {
+		t.Fatalf("Reserve() over budget = %+v, %v, want the first denial at 3 investigations", d, err)
+	}
+	if d, _ := first.Reserve("prod", limits); d.Allowed || d.FirstDenial {
+		t.Errorf("second denial = %+v, want denied without FirstDenial on another process", d)
+	}
+
+	// Each process mirrors the fleet-wide usage it saw into its budget directory
+	if got := second.Usage("prod"); got.Investigations != 3 || got.EstimatedSpend != 1.5 {
+		t.Errorf("mirrored usage = %+v, want 3 investigations, $1.50", got)
+	}
+	usage, err := LoadUsage(second.dir, Day(now))
+	if err != nil || usage["prod"] == nil || usage["prod"].Investigations != 3 {
+		t.Errorf("persisted usage = %+v, %v, want 3 investigations", usage["prod"], err)
+	}
+}
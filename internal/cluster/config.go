@@ -27,8 +27,68 @@ type ClusterConfig struct {
 	// MCP defines the MCP server connection settings for this cluster.
 	MCP MCPConfig `mapstructure:"mcp"`
 
+	// Sources lists the event sources feeding the cluster's fault events into the
+	// pipeline (dedup, filters, queue). Every source runs at the same time.
+	// Default: a single "mcp" source subscribed to the MCP server
+	Sources []SourceConfig `mapstructure:"sources"`
+
 	// Triage defines the triage agent settings for investigating incidents.
 	Triage TriageConfig `mapstructure:"triage"`
+
+	// ServeOnly records the cluster's fault events (state store and raw event
+	// notifications) without launching agents, even when triage is enabled. Use
+	// it to stage a rollout before trusting automated investigations.
+	// Default: false
+	ServeOnly bool `mapstructure:"serve_only"`
+
+	// Budget overrides the global daily investigation budget for this cluster.
+	// Non-zero fields take precedence over the global budget settings.
+	Budget BudgetConfig `mapstructure:"budget"`
+
+	// Canary overrides the global canary target for this cluster.
+	// Non-empty fields take precedence over the global canary settings.
+	Canary CanaryTarget `mapstructure:"canary"`
+
+	// WorkspaceTemplate is a directory copied into this cluster's new workspaces
+	// after the global workspace_template, e.g. the cluster's runbook links and
+	// contacts.
+	WorkspaceTemplate string `mapstructure:"workspace_template"`
+
+	// QueueOverflowPolicy overrides the global queue_overflow_policy for this
+	// cluster's events: drop, reject, drop-oldest, or spill.
+	// Default: "" (the global policy)
+	QueueOverflowPolicy string `mapstructure:"queue_overflow_policy"`
+}
+
+// BudgetConfig defines daily limits on agent investigations.
+// Zero values mean unlimited (or, for per-cluster settings, inherit the global value).
+type BudgetConfig struct {
+	// MaxInvestigationsPerDay caps the number of agent investigations per UTC day.
+	MaxInvestigationsPerDay int `mapstructure:"max_investigations_per_day"`
+
+	// MaxSpendPerDayUSD caps the estimated LLM spend per UTC day.
+	MaxSpendPerDayUSD float64 `mapstructure:"max_spend_per_day_usd"`
+
+	// EstimatedCostPerInvestigationUSD is the estimated LLM spend charged for each investigation.
+	EstimatedCostPerInvestigationUSD float64 `mapstructure:"estimated_cost_per_investigation_usd"`
+}
+
+// CanaryTarget designates the healthy test resource the synthetic canary
+// investigation examines in a cluster.
+type CanaryTarget struct {
+	// Namespace of the test resource
+	Namespace string `mapstructure:"namespace"`
+
+	// ResourceKind is the test resource's kind, e.g. "Deployment" or "Pod"
+	ResourceKind string `mapstructure:"resource_kind"`
+
+	// ResourceName is the test resource's name
+	ResourceName string `mapstructure:"resource_name"`
+}
+
+// IsSet reports whether a test resource is designated.
+func (t CanaryTarget) IsSet() bool {
+	return t.ResourceKind != "" && t.ResourceName != ""
 }
 
 // MCPConfig defines the MCP server connection settings.
@@ -44,6 +104,58 @@ type MCPConfig struct {
 	APIKey string `mapstructure:"api_key"`
 }
 
+// Event source types built into nightcrier (see the sources package)
+const (
+	SourceMCP          = "mcp"
+	SourceAlertmanager = "alertmanager"
+	SourceKubewatch    = "kubewatch"
+	SourceKafka        = "kafka"
+	SourceHTTP         = "http"
+)
+
+// SourceConfig configures one event source of a cluster.
+type SourceConfig struct {
+	// Name identifies the source in logs, on incidents, and in the receiver path
+	// of push sources (/sources/{cluster}/{name}). Defaults to the type.
+	Name string `mapstructure:"name"`
+
+	// Type is the source type: mcp, alertmanager, kubewatch, kafka, or http.
+	Type string `mapstructure:"type"`
+
+	// Endpoint is the MCP server URL (mcp; defaults to mcp.endpoint) or the Kafka
+	// REST proxy URL (kafka).
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Topic is the Kafka topic holding fault events (kafka).
+	Topic string `mapstructure:"topic"`
+
+	// ConsumerGroup is the Kafka consumer group (kafka).
+	// Default: "nightcrier"
+	ConsumerGroup string `mapstructure:"consumer_group"`
+}
+
+// EventSources returns the cluster's event sources with their defaults applied:
+// the configured sources, or a single MCP source when none are configured.
+func (c *ClusterConfig) EventSources() []SourceConfig {
+	if len(c.Sources) == 0 {
+		return []SourceConfig{{Name: SourceMCP, Type: SourceMCP, Endpoint: c.MCP.Endpoint}}
+	}
+	sources := make([]SourceConfig, len(c.Sources))
+	for i, src := range c.Sources {
+		if src.Name == "" {
+			src.Name = src.Type
+		}
+		if src.Type == SourceMCP && src.Endpoint == "" {
+			src.Endpoint = c.MCP.Endpoint
+		}
+		if src.Type == SourceKafka && src.ConsumerGroup == "" {
+			src.ConsumerGroup = "nightcrier"
+		}
+		sources[i] = src
+	}
+	return sources
+}
+
 // TriageConfig defines the triage agent settings for a cluster.
 // Triage can be enabled/disabled per cluster. When enabled, agents
 // investigate incidents using kubectl access to the cluster.
@@ -115,6 +227,29 @@ func (c *ClusterConfig) Validate() error {
 		}
 	}
 
+	// Validate event sources; the source types themselves are checked when the
+	// sources are created
+	names := make(map[string]bool)
+	for _, src := range c.EventSources() {
+		if src.Type == "" {
+			return fmt.Errorf("cluster %s: sources entry %q needs a type", c.Name, src.Name)
+		}
+		if !isValidClusterName(src.Name) {
+			return fmt.Errorf("cluster %s: source name %q is invalid: must contain only alphanumeric characters, hyphens, and underscores", c.Name, src.Name)
+		}
+		if names[src.Name] {
+			return fmt.Errorf("cluster %s: duplicate source name %q", c.Name, src.Name)
+		}
+		names[src.Name] = true
+		if src.Type == SourceKafka && (src.Endpoint == "" || src.Topic == "") {
+			return fmt.Errorf("cluster %s: kafka source %q needs an endpoint (the REST proxy URL) and a topic", c.Name, src.Name)
+		}
+	}
+
+	if c.QueueOverflowPolicy != "" && !ValidOverflowPolicy(c.QueueOverflowPolicy) {
+		return fmt.Errorf("cluster %s: invalid queue_overflow_policy %q: must be drop, reject, drop-oldest, or spill", c.Name, c.QueueOverflowPolicy)
+	}
+
 	// Validate labels (keys and values)
 	for key, value := range c.Labels {
 		if key == "" {
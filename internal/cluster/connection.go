@@ -24,8 +24,17 @@ const (
 	// StatusActive indicates the connection is fully operational and receiving events.
 	StatusActive ConnectionStatus = "active"
 
+	// StatusValidating indicates the cluster's permissions are being validated
+	// before its events are processed (fast start).
+	StatusValidating ConnectionStatus = "validating"
+
 	// StatusFailed indicates the connection has failed and may need reconnection.
 	StatusFailed ConnectionStatus = "failed"
+
+	// StatusDegraded indicates the cluster failed permission validation at startup;
+	// its events are held back while its connection retries the validation with the
+	// reconnect backoff.
+	StatusDegraded ConnectionStatus = "degraded"
 )
 
 // ClusterConnection manages the lifecycle of a single MCP connection.
@@ -49,6 +58,14 @@ type ClusterConnection struct {
 	// It is included in ClusterEvent so the agent knows what it can access.
 	permissions *ClusterPermissions
 
+	// validated is set once the cluster's permissions were validated (or skipped
+	// because triage is disabled); until then its events are held back.
+	validated bool
+
+	// degraded is set when the cluster failed permission validation in Initialize;
+	// its connection then validates it again before processing its events.
+	degraded bool
+
 	// status tracks the current connection state.
 	status ConnectionStatus
 
@@ -64,6 +81,14 @@ type ClusterConnection struct {
 	// retryCount tracks the number of consecutive reconnection attempts.
 	retryCount int
 
+	// droppedEvents counts events from this cluster dropped or rejected because the
+	// global queue was full.
+	droppedEvents int64
+
+	// unhealthySince records when the connection last stopped being active (or when
+	// it was created, if it has never been active). It is zero while active.
+	unhealthySince time.Time
+
 	// mu protects concurrent access to connection state.
 	mu sync.RWMutex
 }
@@ -81,8 +106,9 @@ type ClusterConnection struct {
 // Returns a new ClusterConnection ready to be started.
 func NewClusterConnection(config *ClusterConfig) *ClusterConnection {
 	return &ClusterConnection{
-		config: config,
-		status: StatusDisconnected,
+		config:         config,
+		status:         StatusDisconnected,
+		unhealthySince: time.Now(),
 	}
 }
 
@@ -106,6 +132,41 @@ func (c *ClusterConnection) SetPermissions(perms *ClusterPermissions) {
 	c.permissions = perms
 }
 
+// markValidated records that the cluster may process events.
+func (c *ClusterConnection) markValidated() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validated = true
+	c.degraded = false
+}
+
+// markDegraded records that the cluster failed permission validation at startup.
+func (c *ClusterConnection) markDegraded(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.degraded = true
+	c.status = StatusDegraded
+	c.lastError = err
+	if c.unhealthySince.IsZero() {
+		c.unhealthySince = time.Now()
+	}
+}
+
+// isDegraded reports whether the cluster failed permission validation at startup
+// and has not been validated since.
+func (c *ClusterConnection) isDegraded() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.degraded
+}
+
+// isValidated reports whether the cluster's permissions were validated.
+func (c *ClusterConnection) isValidated() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.validated
+}
+
 // GetPermissions returns a copy of the cluster permissions.
 // Returns nil if permissions have not been validated yet.
 //
@@ -2,14 +2,26 @@ package cluster
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"reflect"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/rbias/nightcrier/internal/metrics"
+	"github.com/rbias/nightcrier/internal/pause"
 )
 
+// ErrQueueFull is returned by Inject when the event queue has no room
+var ErrQueueFull = errors.New("event queue full")
+
+// defaultValidationConcurrency is how many clusters Initialize validates at once
+// when ManagerConfig.ValidationConcurrency is not set
+const defaultValidationConcurrency = 8
+
 // ConnectionManager orchestrates multiple cluster connections.
 // It manages the lifecycle of all MCP connections, fans in events from
 // all clusters into a single channel, and provides health monitoring.
@@ -37,9 +49,32 @@ type ConnectionManager struct {
 	queueOverflowPolicy        string
 	sseReconnectInitialBackoff int // seconds
 
-	// mu protects access to the connections map
+	// fastStart validates each cluster in its connection goroutine (see
+	// ManagerConfig.FastStart); lazyConnect applies to it. validationTimeout bounds
+	// each cluster's validation, validationConcurrency how many Initialize runs at
+	// once.
+	fastStart             bool
+	lazyConnect           bool
+	validationTimeout     time.Duration
+	validationConcurrency int
+
+	// spill holds events overflowing the global queue under the spill policy; nil
+	// when no cluster uses it
+	spill *spillStore
+	// decodeSpilled turns a spilled event back into the event type clients send
+	decodeSpilled func(data []byte, receivedAt time.Time) (interface{}, error)
+	// overflowMetrics counts events handled by the overflow policies; may be nil
+	overflowMetrics *metrics.CounterVec
+
+	// pauses reports which clusters have triage paused, for the health output
+	pauses *pause.Switch
+
+	// mu protects access to the connections map and stopped
 	mu sync.RWMutex
 
+	// stopped is set when Stop closed eventChan
+	stopped bool
+
 	// wg tracks running connection goroutines for graceful shutdown
 	wg sync.WaitGroup
 
@@ -59,6 +94,30 @@ type ManagerConfig struct {
 	GlobalQueueSize            int
 	QueueOverflowPolicy        string
 	SSEReconnectInitialBackoff int // seconds
+
+	// SpillDir and SpillMaxEvents configure the spill overflow policy: overflowing
+	// events are persisted to SpillDir, at most SpillMaxEvents at a time (0 for no
+	// limit). DecodeSpilledEvent turns a spilled event's JSON back into an
+	// *events.FaultEvent received at receivedAt.
+	SpillDir           string
+	SpillMaxEvents     int
+	DecodeSpilledEvent func(data []byte, receivedAt time.Time) (interface{}, error)
+
+	// FastStart skips Initialize: each cluster's permissions are validated by its
+	// own connection after Start, so events from healthy clusters are processed
+	// while others are still validating. A cluster failing validation is retried
+	// with the reconnect backoff. ValidationTimeout bounds each attempt.
+	FastStart         bool
+	ValidationTimeout time.Duration
+
+	// ValidationConcurrency is how many clusters Initialize validates at once
+	// (default 8).
+	ValidationConcurrency int
+
+	// LazyConnect opens a cluster's MCP subscription only once its permissions
+	// are validated. Otherwise (eager) it is opened at Start and its events wait
+	// in the subscription while the cluster is validated.
+	LazyConnect bool
 }
 
 // NewConnectionManager creates a new ConnectionManager with the given configuration.
@@ -109,9 +168,34 @@ func NewConnectionManager(cfg *ManagerConfig) (*ConnectionManager, error) {
 		globalQueueSize:            cfg.GlobalQueueSize,
 		queueOverflowPolicy:        cfg.QueueOverflowPolicy,
 		sseReconnectInitialBackoff: cfg.SSEReconnectInitialBackoff,
+		fastStart:                  cfg.FastStart,
+		lazyConnect:                cfg.LazyConnect,
+		validationTimeout:          cfg.ValidationTimeout,
+		validationConcurrency:      cfg.ValidationConcurrency,
+		decodeSpilled:              cfg.DecodeSpilledEvent,
 		ctx:                        ctx,
 		cancel:                     cancel,
 	}
+	if mgr.validationConcurrency <= 0 {
+		mgr.validationConcurrency = defaultValidationConcurrency
+	}
+
+	// Open the spill directory when the global or a cluster's policy spills
+	spills := cfg.QueueOverflowPolicy == OverflowSpill
+	for i := range cfg.Clusters {
+		spills = spills || cfg.Clusters[i].QueueOverflowPolicy == OverflowSpill
+	}
+	if spills {
+		spill, err := openSpillStore(cfg.SpillDir, cfg.SpillMaxEvents)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		mgr.spill = spill
+		if n := spill.len(); n > 0 {
+			slog.Info("spilled events from a previous run will be queued", "spilled", n, "dir", cfg.SpillDir)
+		}
+	}
 
 	// Create connections for each cluster
 	for i := range cfg.Clusters {
@@ -126,12 +210,19 @@ func NewConnectionManager(cfg *ManagerConfig) (*ConnectionManager, error) {
 		slog.Info("cluster connection created",
 			"cluster", cluster.Name,
 			"endpoint", cluster.MCP.Endpoint,
-			"triage_enabled", cluster.Triage.Enabled)
+			"triage_enabled", cluster.Triage.Enabled,
+			"serve_only", cluster.ServeOnly)
 	}
 
 	return mgr, nil
 }
 
+// SetPauseSwitch adds the triage pause state of each cluster to the health
+// output. Call before Start.
+func (cm *ConnectionManager) SetPauseSwitch(pauses *pause.Switch) {
+	cm.pauses = pauses
+}
+
 // SetClusterClient sets the event client for a specific cluster.
 // This must be called for each cluster before calling Start().
 //
@@ -152,6 +243,30 @@ func (cm *ConnectionManager) SetClusterClient(clusterName string, client interfa
 	return nil
 }
 
+// ValidationSummary reports the outcome of Initialize for every cluster.
+type ValidationSummary struct {
+	// Validated lists the clusters whose minimum permissions are met
+	Validated []string
+	// Insufficient lists the validated clusters missing permissions for full triage
+	Insufficient []string
+	// Skipped lists the clusters with triage disabled
+	Skipped []string
+	// Degraded maps each cluster that failed validation to its error
+	Degraded map[string]error
+	// Duration is how long the validation took
+	Duration time.Duration
+}
+
+// DegradedClusters returns the names of the degraded clusters, sorted.
+func (s *ValidationSummary) DegradedClusters() []string {
+	names := make([]string, 0, len(s.Degraded))
+	for name := range s.Degraded {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Initialize validates cluster permissions for all clusters with triage enabled.
 // This should be called after all clients are set but before Start().
 //
@@ -161,67 +276,168 @@ func (cm *ConnectionManager) SetClusterClient(clusterName string, client interfa
 //   - Sets permissions on the ClusterConnection
 //   - Logs warnings if minimum permissions not met
 //
-// Clusters with triage.enabled=false are skipped.
+// Clusters are validated concurrently, at most ValidationConcurrency at a time,
+// each bounded by ValidationTimeout. A cluster failing validation does not fail
+// Initialize: it is marked degraded, and its connection validates it again with
+// the reconnect backoff before processing its events.
+//
+// Clusters with triage.enabled=false are skipped. With FastStart, Initialize is
+// not called: each connection validates its own cluster after Start.
 //
 // Phase 3: Added for permission validation (design.md lines 269-304)
 //
 // Parameters:
-//   - ctx: Context for kubectl command execution (with timeout)
+//   - ctx: Context for kubectl command execution
 //
-// Returns error if validation fails for any cluster with triage enabled.
-func (cm *ConnectionManager) Initialize(ctx context.Context) error {
+// Returns a summary of the validation, and an error only if ctx ended before
+// every cluster was validated.
+func (cm *ConnectionManager) Initialize(ctx context.Context) (*ValidationSummary, error) {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
 	slog.Info("initializing connection manager - validating cluster permissions",
-		"cluster_count", len(cm.connections))
+		"cluster_count", len(cm.connections),
+		"concurrency", cm.validationConcurrency)
 
+	start := time.Now()
+	errs := make(map[string]error, len(cm.connections))
+	var errsMu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, cm.validationConcurrency)
 	for clusterName, conn := range cm.connections {
-		clusterConfig := conn.config
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(clusterName string, conn *ClusterConnection) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			validateCtx := ctx
+			if cm.validationTimeout > 0 {
+				var cancel context.CancelFunc
+				validateCtx, cancel = context.WithTimeout(ctx, cm.validationTimeout)
+				defer cancel()
+			}
+			if err := validateConnection(validateCtx, conn); err != nil {
+				errsMu.Lock()
+				errs[clusterName] = err
+				errsMu.Unlock()
+			}
+		}(clusterName, conn)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("cluster permission validation interrupted: %w", err)
+	}
 
-		// Skip validation if triage is disabled
-		if !clusterConfig.Triage.Enabled {
-			slog.Info("triage disabled for cluster",
+	summary := &ValidationSummary{Degraded: make(map[string]error), Duration: time.Since(start)}
+	for clusterName, conn := range cm.connections {
+		switch {
+		case errs[clusterName] != nil:
+			err := fmt.Errorf("permission validation failed: %w", errs[clusterName])
+			conn.markDegraded(err)
+			summary.Degraded[clusterName] = err
+			slog.Warn("cluster marked degraded - its connection will retry validation",
 				"cluster", clusterName,
-				"reason", "triage.enabled=false")
-			continue
+				"error", err)
+		case !conn.config.Triage.Enabled:
+			summary.Skipped = append(summary.Skipped, clusterName)
+		case !conn.GetPermissions().MinimumPermissionsMet():
+			summary.Insufficient = append(summary.Insufficient, clusterName)
+		default:
+			summary.Validated = append(summary.Validated, clusterName)
 		}
+	}
+	sort.Strings(summary.Validated)
+	sort.Strings(summary.Insufficient)
+	sort.Strings(summary.Skipped)
+
+	slog.Info("connection manager initialization complete",
+		"validated", len(summary.Validated),
+		"insufficient_permissions", len(summary.Insufficient),
+		"triage_disabled", len(summary.Skipped),
+		"degraded", len(summary.Degraded),
+		"degraded_clusters", summary.DegradedClusters(),
+		"duration_ms", summary.Duration.Milliseconds())
+	return summary, nil
+}
+
+// validateConnection validates a cluster's permissions, sets them on its
+// connection, and marks it validated. Clusters with triage disabled are marked
+// validated without checks.
+func validateConnection(ctx context.Context, conn *ClusterConnection) error {
+	clusterConfig := conn.config
+	clusterName := clusterConfig.Name
 
-		// Validate permissions
-		slog.Info("validating cluster permissions",
+	// Skip validation if triage is disabled
+	if !clusterConfig.Triage.Enabled {
+		slog.Info("triage disabled for cluster",
 			"cluster", clusterName,
-			"kubeconfig", clusterConfig.Triage.Kubeconfig)
+			"reason", "triage.enabled=false")
+		conn.markValidated()
+		return nil
+	}
 
-		perms, err := validateClusterPermissions(ctx, clusterConfig)
-		if err != nil {
-			return fmt.Errorf("cluster %s: permission validation failed: %w",
-				clusterName, err)
-		}
+	// Validate permissions
+	slog.Info("validating cluster permissions",
+		"cluster", clusterName,
+		"kubeconfig", clusterConfig.Triage.Kubeconfig)
 
-		// Set permissions on connection
-		conn.SetPermissions(perms)
+	perms, err := validateClusterPermissions(ctx, clusterConfig)
+	if err != nil {
+		return err
+	}
 
-		// Warn if minimum permissions not met (but don't fail)
-		if !perms.MinimumPermissionsMet() {
-			slog.Warn("cluster has insufficient permissions for full triage",
-				"cluster", clusterName,
-				"warnings", perms.Warnings)
-		} else {
-			slog.Info("cluster permissions validated successfully",
-				"cluster", clusterName,
-				"minimum_met", true,
-				"helm_access", perms.HelmAccessAvailable())
-		}
+	// Set permissions on connection
+	conn.SetPermissions(perms)
+	conn.markValidated()
+
+	// Warn if minimum permissions not met (but don't fail)
+	if !perms.MinimumPermissionsMet() {
+		slog.Warn("cluster has insufficient permissions for full triage",
+			"cluster", clusterName,
+			"warnings", perms.Warnings)
+	} else {
+		slog.Info("cluster permissions validated successfully",
+			"cluster", clusterName,
+			"minimum_met", true,
+			"helm_access", perms.HelmAccessAvailable())
+	}
+	return nil
+}
+
+// awaitValidation validates a fast-started or degraded cluster's permissions
+// before its events are processed. It returns immediately for clusters already
+// validated, and without FastStart for clusters Initialize validated.
+func (cm *ConnectionManager) awaitValidation(ctx context.Context, conn *ClusterConnection) error {
+	if conn.isValidated() || (!cm.fastStart && !conn.isDegraded()) {
+		return nil
 	}
+	cm.updateConnectionStatus(conn, StatusValidating, nil)
 
-	slog.Info("connection manager initialization complete")
+	validateCtx := ctx
+	if cm.validationTimeout > 0 {
+		var cancel context.CancelFunc
+		validateCtx, cancel = context.WithTimeout(ctx, cm.validationTimeout)
+		defer cancel()
+	}
+	if err := validateConnection(validateCtx, conn); err != nil {
+		return fmt.Errorf("permission validation failed: %w", err)
+	}
 	return nil
 }
 
 // Start begins managing all cluster connections and returns a read-only
 // channel for receiving cluster events. It spawns a goroutine for each
 // cluster connection to subscribe to its MCP server and fan events into
-// the global event channel.
+// the global event channel. With FastStart, each goroutine first validates
+// its cluster's permissions, so one slow or failing cluster does not hold back
+// the others.
 //
 // The fan-in pattern: each cluster goroutine subscribes to its MCP server
 // and wraps incoming FaultEvents in ClusterEvent wrappers that include
@@ -245,6 +461,12 @@ func (cm *ConnectionManager) Start(ctx context.Context) <-chan interface{} {
 		go cm.runConnection(ctx, clusterName, conn)
 	}
 
+	// Queue spilled events again as the queue frees up
+	if cm.spill != nil {
+		cm.wg.Add(1)
+		go cm.drainSpill(ctx)
+	}
+
 	return cm.eventChan
 }
 
@@ -278,8 +500,13 @@ func (cm *ConnectionManager) runConnection(ctx context.Context, clusterName stri
 					"cluster", clusterName,
 					"error", err)
 
-				// Update connection status
-				cm.updateConnectionStatus(conn, StatusFailed, err)
+				// Update connection status; a degraded cluster stays degraded until
+				// its validation succeeds
+				status := StatusFailed
+				if conn.isDegraded() {
+					status = StatusDegraded
+				}
+				cm.updateConnectionStatus(conn, status, err)
 
 				// Wait before reconnecting (simple backoff)
 				// TODO: Implement exponential backoff with jitter in future phase
@@ -304,9 +531,20 @@ func (cm *ConnectionManager) runConnection(ctx context.Context, clusterName stri
 // - client: *events.Client
 // - Subscribe returns: (<-chan *events.FaultEvent, error)
 func (cm *ConnectionManager) subscribeAndFanIn(ctx context.Context, clusterName string, conn *ClusterConnection) error {
+	// Lazy connections are opened once the cluster's permissions are validated
+	if cm.lazyConnect {
+		if err := cm.awaitValidation(ctx, conn); err != nil {
+			return err
+		}
+	}
+
 	// Update status to connecting
 	cm.updateConnectionStatus(conn, StatusConnecting, nil)
 
+	// The subscription ends with this attempt, also when validation fails below
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// Get the event client (stored as interface{})
 	eventClient := conn.client
 	if eventClient == nil {
@@ -338,14 +576,17 @@ func (cm *ConnectionManager) subscribeAndFanIn(ctx context.Context, clusterName
 	// The actual type is <-chan *events.FaultEvent
 	eventChanValue := results[0]
 
+	// Eager connections are warm while the cluster is validated: its events wait
+	// in the subscription until validation succeeds
+	if err := cm.awaitValidation(ctx, conn); err != nil {
+		return err
+	}
+
 	// Mark as active
 	cm.updateConnectionStatus(conn, StatusActive, nil)
 	slog.Info("cluster connection active",
 		"cluster", clusterName)
 
-	// Get cluster config for event wrapping
-	clusterConfig := conn.config
-
 	// Fan-in events to global channel using reflection to receive from the channel
 	// Events come in as *events.FaultEvent, we wrap them in a map structure
 	// that matches events.ClusterEvent fields to avoid importing events package
@@ -368,21 +609,7 @@ func (cm *ConnectionManager) subscribeAndFanIn(ctx context.Context, clusterName
 		}
 
 		// Extract the event (it's interface{} but actually *events.FaultEvent)
-		event := recv.Interface()
-		// Create ClusterEvent wrapper as a map
-		// This matches the structure of events.ClusterEvent:
-		//   ClusterName string
-		//   Kubeconfig  string
-		//   Permissions *ClusterPermissions  (Phase 3: added)
-		//   Labels      map[string]string
-		//   Event       *FaultEvent
-		clusterEvent := map[string]interface{}{
-			"ClusterName": clusterConfig.Name,
-			"Kubeconfig":  clusterConfig.Triage.Kubeconfig,
-			"Permissions": conn.GetPermissions(), // Phase 3: include permissions
-			"Labels":      clusterConfig.Labels,
-			"Event":       event,
-		}
+		clusterEvent := wrapEvent(conn, recv.Interface())
 
 		// Try to send to global channel
 		select {
@@ -398,23 +625,56 @@ func (cm *ConnectionManager) subscribeAndFanIn(ctx context.Context, clusterName
 			return ctx.Err()
 
 		default:
-			// Queue full, apply overflow policy
-			if cm.queueOverflowPolicy == "drop" {
-				slog.Warn("event queue full, dropping event",
-					"cluster", clusterName,
-					"policy", "drop")
-			} else {
-				// Reject policy - log and continue (can't block here)
-				slog.Warn("event queue full, event rejected",
-					"cluster", clusterName,
-					"policy", "reject")
-			}
+			// Queue full, apply the cluster's overflow policy
+			cm.overflow(conn, recv.Interface(), clusterEvent)
 		}
 	}
+}
 
-	// Event stream closed (likely disconnected)
-	cm.updateConnectionStatus(conn, StatusDisconnected, nil)
-	return fmt.Errorf("event stream closed")
+// wrapEvent wraps a cluster's fault event with its cluster context.
+// The map matches the structure of events.ClusterEvent:
+//
+//	ClusterName string
+//	Kubeconfig  string
+//	Permissions *ClusterPermissions  (Phase 3: added)
+//	Labels      map[string]string
+//	Event       *FaultEvent
+func wrapEvent(conn *ClusterConnection, event interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"ClusterName": conn.config.Name,
+		"Kubeconfig":  conn.config.Triage.Kubeconfig,
+		"Permissions": conn.GetPermissions(), // Phase 3: include permissions
+		"Labels":      conn.config.Labels,
+		"Event":       event,
+	}
+}
+
+// Inject queues a fault event for a cluster as if its MCP server had sent it, so
+// it is processed like a live event (dedup, aggregation, investigation). It is used
+// to backfill events that occurred while nightcrier was down. event should be an
+// *events.FaultEvent.
+//
+// Returns ErrQueueFull when the global event queue is full (the caller should
+// retry later), and an error when the cluster is unknown or the manager stopped.
+func (cm *ConnectionManager) Inject(clusterName string, event interface{}) error {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if cm.stopped {
+		return fmt.Errorf("connection manager stopped")
+	}
+	conn, ok := cm.connections[clusterName]
+	if !ok {
+		return fmt.Errorf("cluster not found: %s", clusterName)
+	}
+
+	select {
+	case cm.eventChan <- wrapEvent(conn, event):
+		slog.Debug("event injected", "cluster", clusterName)
+		return nil
+	default:
+		return ErrQueueFull
+	}
 }
 
 // updateConnectionStatus updates a connection's status and error state.
@@ -430,6 +690,14 @@ func (cm *ConnectionManager) updateConnectionStatus(conn *ClusterConnection, sta
 	} else if status == StatusActive {
 		conn.retryCount = 0
 	}
+
+	// Connecting and subscribing during a reconnect still count as unhealthy, so the
+	// outage is measured from when the connection stopped being active
+	if status == StatusActive {
+		conn.unhealthySince = time.Time{}
+	} else if conn.unhealthySince.IsZero() {
+		conn.unhealthySince = time.Now()
+	}
 }
 
 // updateLastEvent updates the last event timestamp and increments the event counter for a connection.
@@ -453,8 +721,11 @@ func (cm *ConnectionManager) Stop() {
 	// Wait for all connection goroutines to finish
 	cm.wg.Wait()
 
-	// Close the event channel
+	// Close the event channel; Inject checks stopped under the same lock
+	cm.mu.Lock()
+	cm.stopped = true
 	close(cm.eventChan)
+	cm.mu.Unlock()
 
 	slog.Info("connection manager stopped")
 }
@@ -484,6 +755,97 @@ func (cm *ConnectionManager) GetAllConnectionStatuses() map[string]ConnectionSta
 	return statuses
 }
 
+// ConnectionState is a point-in-time view of one cluster connection, used to alert
+// on clusters that stay disconnected.
+type ConnectionState struct {
+	Cluster string
+	Status  ConnectionStatus
+	// UnhealthySince is when the connection stopped being active; zero while active
+	UnhealthySince time.Time
+	// LastError is the most recent connection error, if any
+	LastError string
+}
+
+// ConnectionStates returns the state of every cluster connection, sorted by
+// cluster name.
+func (cm *ConnectionManager) ConnectionStates() []ConnectionState {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	states := make([]ConnectionState, 0, len(cm.connections))
+	for name, conn := range cm.connections {
+		conn.mu.RLock()
+		state := ConnectionState{
+			Cluster:        name,
+			Status:         conn.status,
+			UnhealthySince: conn.unhealthySince,
+		}
+		if conn.lastError != nil {
+			state.LastError = conn.lastError.Error()
+		}
+		conn.mu.RUnlock()
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Cluster < states[j].Cluster })
+	return states
+}
+
+// QueueStats is a point-in-time view of the event queues: the global fan-in queue
+// shared by all clusters and each cluster's own queue in its event client.
+type QueueStats struct {
+	GlobalDepth    int                 `json:"global_depth"`
+	GlobalCapacity int                 `json:"global_capacity"`
+	Spilled        int                 `json:"spilled"` // Events waiting in the spill directory
+	Clusters       []ClusterQueueStats `json:"clusters"`
+}
+
+// ClusterQueueStats describes one cluster's event queue.
+type ClusterQueueStats struct {
+	Cluster  string `json:"cluster"`
+	Depth    int    `json:"depth"`
+	Capacity int    `json:"capacity"`
+	// Dropped is the total number of this cluster's events lost so far, whether
+	// its own queue or the global queue was full
+	Dropped int64 `json:"dropped"`
+}
+
+// eventQueue is implemented by event clients that expose their queue depth and
+// drop counter (*events.Client, sources.Pipeline).
+type eventQueue interface {
+	QueueDepth() (depth, capacity int)
+	DroppedCount() int64
+}
+
+// QueueStats returns the depth, capacity, and drop counts of the event queues,
+// with clusters sorted by name.
+func (cm *ConnectionManager) QueueStats() QueueStats {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	stats := QueueStats{
+		GlobalDepth:    len(cm.eventChan),
+		GlobalCapacity: cap(cm.eventChan),
+		Clusters:       make([]ClusterQueueStats, 0, len(cm.connections)),
+	}
+	if cm.spill != nil {
+		stats.Spilled = cm.spill.len()
+	}
+	for name, conn := range cm.connections {
+		conn.mu.RLock()
+		cs := ClusterQueueStats{Cluster: name, Dropped: conn.droppedEvents}
+		client := conn.client
+		conn.mu.RUnlock()
+
+		if q, ok := client.(eventQueue); ok {
+			cs.Depth, cs.Capacity = q.QueueDepth()
+			cs.Dropped += q.DroppedCount()
+		}
+		stats.Clusters = append(stats.Clusters, cs)
+	}
+	sort.Slice(stats.Clusters, func(i, j int) bool { return stats.Clusters[i].Cluster < stats.Clusters[j].Cluster })
+	return stats
+}
+
 // GetHealth returns a complete health summary for all cluster connections.
 // This method is used by the health monitoring HTTP endpoint to provide
 // detailed status information including per-cluster health and aggregate statistics.
@@ -515,6 +877,14 @@ func (cm *ConnectionManager) GetHealth() interface{} {
 	activeCount := 0
 	unhealthyCount := 0
 	triageEnabledCount := 0
+	serveOnlyCount := 0
+	pausedCount := 0
+
+	// Read the pause switch once, so every cluster reports the same state
+	var pauses pause.State
+	if cm.pauses != nil {
+		pauses = cm.pauses.State()
+	}
 
 	// Collect health data for each cluster
 	for _, conn := range cm.connections {
@@ -524,15 +894,18 @@ func (cm *ConnectionManager) GetHealth() interface{} {
 		isHealthy := conn.status == StatusActive
 		if isHealthy {
 			activeCount++
-		} else if conn.status == StatusFailed || conn.status == StatusDisconnected {
+		} else if conn.status == StatusFailed || conn.status == StatusDisconnected || conn.status == StatusDegraded {
 			unhealthyCount++
 		}
 
-		// Check if triage is enabled
+		// Check if triage is enabled and whether agents are held back (serve-only)
 		triageEnabled := conn.config.Triage.Enabled
 		if triageEnabled {
 			triageEnabledCount++
 		}
+		if conn.config.ServeOnly {
+			serveOnlyCount++
+		}
 
 		// Build cluster health data
 		clusterHealth := map[string]interface{}{
@@ -540,6 +913,7 @@ func (cm *ConnectionManager) GetHealth() interface{} {
 			"status":         conn.status,
 			"event_count":    conn.eventCount,
 			"triage_enabled": triageEnabled,
+			"serve_only":     conn.config.ServeOnly,
 		}
 
 		// Add optional fields
@@ -548,6 +922,14 @@ func (cm *ConnectionManager) GetHealth() interface{} {
 			clusterHealth["last_event"] = &lastEvent
 		}
 
+		if p := pauses.For(conn.config.Name); p != nil {
+			pausedCount++
+			clusterHealth["triage_paused"] = true
+			clusterHealth["pause"] = p
+		} else {
+			clusterHealth["triage_paused"] = false
+		}
+
 		if conn.lastError != nil {
 			clusterHealth["error"] = conn.lastError.Error()
 		}
@@ -573,8 +955,13 @@ func (cm *ConnectionManager) GetHealth() interface{} {
 			"active":         activeCount,
 			"unhealthy":      unhealthyCount,
 			"triage_enabled": triageEnabledCount,
+			"serve_only":     serveOnlyCount,
+			"triage_paused":  pausedCount,
 		},
 	}
+	if pauses.Global != nil {
+		summary["triage_paused_globally"] = pauses.Global
+	}
 
 	return summary
 }
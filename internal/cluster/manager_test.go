@@ -0,0 +1,154 @@
+package cluster
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testClient stands in for *events.Client, sending its events on Subscribe.
+type testClient struct {
+	events     []*testEvent
+	subscribed atomic.Int32
+}
+
+func (c *testClient) Subscribe(ctx context.Context) (<-chan *testEvent, error) {
+	c.subscribed.Add(1)
+	ch := make(chan *testEvent, len(c.events))
+	for _, event := range c.events {
+		ch <- event
+	}
+	return ch, nil
+}
+
+func TestInitializeMarksFailingClustersDegraded(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+	clusters := []ClusterConfig{{Name: "healthy"}}
+	for _, name := range []string{"broken-1", "broken-2", "broken-3"} {
+		clusters = append(clusters, ClusterConfig{Name: name, Triage: TriageConfig{Enabled: true, Kubeconfig: missing}})
+	}
+	cm, err := NewConnectionManager(&ManagerConfig{
+		Clusters:                   clusters,
+		GlobalQueueSize:            10,
+		QueueOverflowPolicy:        OverflowDrop,
+		SSEReconnectInitialBackoff: 60,
+		ValidationTimeout:          5 * time.Second,
+		ValidationConcurrency:      2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := cm.Initialize(context.Background())
+	if err != nil {
+		t.Fatalf("Initialize() failed: %v", err)
+	}
+	if got := summary.DegradedClusters(); len(got) != 3 || got[0] != "broken-1" {
+		t.Errorf("degraded clusters = %v, want the three broken ones", got)
+	}
+	if len(summary.Skipped) != 1 || summary.Skipped[0] != "healthy" || len(summary.Validated) != 0 {
+		t.Errorf("summary = %+v, want the healthy cluster skipped (triage disabled)", summary)
+	}
+	statuses := cm.GetAllConnectionStatuses()
+	if statuses["broken-1"] != StatusDegraded || statuses["healthy"] == StatusDegraded {
+		t.Errorf("statuses = %v, want only the broken clusters degraded", statuses)
+	}
+
+	// A degraded cluster's events are held back while its connection retries
+	healthy := &testClient{events: []*testEvent{{ID: "ev-1"}}}
+	broken := &testClient{events: []*testEvent{{ID: "ev-2"}}}
+	cm.SetClusterClient("healthy", healthy)
+	for _, name := range []string{"broken-1", "broken-2", "broken-3"} {
+		cm.SetClusterClient(name, broken)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	eventChan := cm.Start(ctx)
+	defer func() {
+		cancel()
+		cm.wg.Wait()
+	}()
+	select {
+	case event := <-eventChan:
+		wrapped := event.(map[string]interface{})
+		if wrapped["ClusterName"] != "healthy" {
+			t.Fatalf("got an event of %v, want only the healthy cluster's", wrapped["ClusterName"])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event from the healthy cluster")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for cm.GetAllConnectionStatuses()["broken-2"] != StatusDegraded {
+		if time.Now().After(deadline) {
+			t.Fatalf("broken cluster status = %s, want degraded", cm.GetAllConnectionStatuses()["broken-2"])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case event := <-eventChan:
+		t.Errorf("got %v from a degraded cluster", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestFastStart(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		t.Run(map[bool]string{false: "eager", true: "lazy"}[lazy], func(t *testing.T) {
+			cm, err := NewConnectionManager(&ManagerConfig{
+				Clusters: []ClusterConfig{
+					{Name: "healthy"},
+					{Name: "broken", Triage: TriageConfig{Enabled: true, Kubeconfig: filepath.Join(t.TempDir(), "missing.yaml")}},
+				},
+				GlobalQueueSize:            10,
+				QueueOverflowPolicy:        OverflowDrop,
+				SSEReconnectInitialBackoff: 60,
+				FastStart:                  true,
+				ValidationTimeout:          5 * time.Second,
+				LazyConnect:                lazy,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			healthy := &testClient{events: []*testEvent{{ID: "ev-1"}}}
+			broken := &testClient{events: []*testEvent{{ID: "ev-2"}}}
+			cm.SetClusterClient("healthy", healthy)
+			cm.SetClusterClient("broken", broken)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			eventChan := cm.Start(ctx)
+			defer func() {
+				cancel()
+				cm.wg.Wait()
+			}()
+
+			// The healthy cluster's events flow while the broken one fails validation
+			select {
+			case event := <-eventChan:
+				wrapped := event.(map[string]interface{})
+				if wrapped["ClusterName"] != "healthy" {
+					t.Fatalf("got an event of %v, want only the healthy cluster's", wrapped["ClusterName"])
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no event from the healthy cluster")
+			}
+
+			deadline := time.Now().Add(5 * time.Second)
+			for cm.GetAllConnectionStatuses()["broken"] != StatusFailed {
+				if time.Now().After(deadline) {
+					t.Fatalf("broken cluster status = %s, want failed", cm.GetAllConnectionStatuses()["broken"])
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			select {
+			case event := <-eventChan:
+				t.Errorf("got %v from a cluster that failed validation", event)
+			default:
+			}
+			if got, want := broken.subscribed.Load(), map[bool]int32{false: 1, true: 0}[lazy]; got != want {
+				t.Errorf("broken cluster subscribed %d times, want %d", got, want)
+			}
+		})
+	}
+}
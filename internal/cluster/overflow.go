@@ -0,0 +1,332 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/metrics"
+)
+
+// Queue overflow policies, applied when a cluster's event arrives while the global
+// event queue is full
+const (
+	// OverflowDrop drops the new event
+	OverflowDrop = "drop"
+	// OverflowReject rejects the new event (equivalent to drop; the MCP server is
+	// not told, since its notifications cannot be refused)
+	OverflowReject = "reject"
+	// OverflowDropOldest evicts the oldest queued event to make room for the new
+	// one, keeping the newest events
+	OverflowDropOldest = "drop-oldest"
+	// OverflowSpill persists the new event to the spill directory; spilled events
+	// are queued again, oldest first, as the queue frees up
+	OverflowSpill = "spill"
+)
+
+// Outcomes counted by the overflow metric
+const (
+	overflowDropped  = "dropped"  // the new event was lost
+	overflowEvicted  = "evicted"  // a queued event was lost to make room
+	overflowSpilled  = "spilled"  // the new event was persisted to disk
+	overflowRestored = "restored" // a spilled event was queued again
+)
+
+// spillDrainInterval is how often spilled events are queued again
+const spillDrainInterval = time.Second
+
+// ValidOverflowPolicy reports whether policy is a queue overflow policy.
+func ValidOverflowPolicy(policy string) bool {
+	switch policy {
+	case OverflowDrop, OverflowReject, OverflowDropOldest, OverflowSpill:
+		return true
+	}
+	return false
+}
+
+// NewOverflowMetrics registers the counter of events affected by the queue
+// overflow policies, per cluster, policy, and outcome (dropped, evicted, spilled,
+// restored).
+func NewOverflowMetrics(registry *metrics.Registry) *metrics.CounterVec {
+	return registry.RegisterCounter(metrics.NewCounterVec(
+		"nightcrier_queue_overflow_events_total",
+		"Fault events handled by a queue overflow policy because the event queue was full, by outcome.",
+		"cluster", "policy", "outcome"))
+}
+
+// SetOverflowMetrics sets the counter overflow outcomes are recorded in (see
+// NewOverflowMetrics). It must be called before Start.
+func (cm *ConnectionManager) SetOverflowMetrics(counter *metrics.CounterVec) {
+	cm.overflowMetrics = counter
+}
+
+// overflowPolicy returns the overflow policy of a cluster: its own, or the global
+// queue_overflow_policy.
+func (cm *ConnectionManager) overflowPolicy(conn *ClusterConnection) string {
+	if conn.config.QueueOverflowPolicy != "" {
+		return conn.config.QueueOverflowPolicy
+	}
+	return cm.queueOverflowPolicy
+}
+
+// overflow applies a cluster's overflow policy to an event that found the global
+// queue full; event is the client's event and wrapped its queue entry.
+func (cm *ConnectionManager) overflow(conn *ClusterConnection, event, wrapped interface{}) {
+	clusterName := conn.config.Name
+	policy := cm.overflowPolicy(conn)
+
+	switch policy {
+	case OverflowDropOldest:
+		// Evict the oldest event; the consumer may also have freed a slot meanwhile
+		select {
+		case evicted := <-cm.eventChan:
+			cm.recordEviction(evicted, policy)
+		default:
+		}
+		select {
+		case cm.eventChan <- wrapped:
+			cm.updateLastEvent(conn)
+			return
+		default:
+		}
+	case OverflowSpill:
+		if cm.spill == nil {
+			break
+		}
+		err := cm.spill.put(clusterName, event)
+		if err == nil {
+			cm.countOverflow(clusterName, policy, overflowSpilled)
+			slog.Warn("event queue full, event spilled to disk",
+				"cluster", clusterName,
+				"policy", policy,
+				"spilled", cm.spill.len())
+			return
+		}
+		slog.Error("failed to spill event, dropping it", "cluster", clusterName, "error", err)
+	}
+
+	conn.mu.Lock()
+	conn.droppedEvents++
+	conn.mu.Unlock()
+	cm.countOverflow(clusterName, policy, overflowDropped)
+	if policy == OverflowReject {
+		// Reject policy - log and continue (can't block here)
+		slog.Warn("event queue full, event rejected",
+			"cluster", clusterName,
+			"policy", policy)
+		return
+	}
+	slog.Warn("event queue full, dropping event",
+		"cluster", clusterName,
+		"policy", policy)
+}
+
+// recordEviction counts a queued event evicted by the drop-oldest policy against
+// the cluster it came from.
+func (cm *ConnectionManager) recordEviction(evicted interface{}, policy string) {
+	wrapped, _ := evicted.(map[string]interface{})
+	clusterName, _ := wrapped["ClusterName"].(string)
+
+	cm.mu.RLock()
+	conn := cm.connections[clusterName]
+	cm.mu.RUnlock()
+	if conn != nil {
+		conn.mu.Lock()
+		conn.droppedEvents++
+		conn.mu.Unlock()
+	}
+	cm.countOverflow(clusterName, policy, overflowEvicted)
+	slog.Warn("event queue full, evicted oldest event",
+		"cluster", clusterName,
+		"policy", policy)
+}
+
+func (cm *ConnectionManager) countOverflow(clusterName, policy, outcome string) {
+	if cm.overflowMetrics != nil {
+		cm.overflowMetrics.Inc(clusterName, policy, outcome)
+	}
+}
+
+// drainSpill queues spilled events again, oldest first, whenever the global queue
+// has room, until ctx is cancelled. Spilled events survive restarts and are
+// drained after the next start.
+func (cm *ConnectionManager) drainSpill(ctx context.Context) {
+	defer cm.wg.Done()
+	ticker := time.NewTicker(spillDrainInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cm.restoreSpilled()
+		}
+	}
+}
+
+// restoreSpilled queues as many spilled events as the global queue has room for.
+func (cm *ConnectionManager) restoreSpilled() {
+	for len(cm.eventChan) < cap(cm.eventChan) {
+		entry, ok := cm.spill.oldest()
+		if !ok {
+			return
+		}
+		record, err := entry.read()
+		if err != nil {
+			slog.Error("failed to read spilled event, discarding it", "path", entry.path, "error", err)
+			cm.spill.remove(entry)
+			continue
+		}
+
+		cm.mu.RLock()
+		conn := cm.connections[record.Cluster]
+		cm.mu.RUnlock()
+		var event interface{}
+		if conn != nil && cm.decodeSpilled != nil {
+			event, err = cm.decodeSpilled(record.Event, record.SpilledAt)
+		}
+		if conn == nil || event == nil {
+			slog.Error("discarding spilled event", "path", entry.path, "cluster", record.Cluster, "error", err)
+			cm.spill.remove(entry)
+			continue
+		}
+
+		select {
+		case cm.eventChan <- wrapEvent(conn, event):
+		default:
+			return
+		}
+		cm.spill.remove(entry)
+		cm.updateLastEvent(conn)
+		cm.countOverflow(record.Cluster, cm.overflowPolicy(conn), overflowRestored)
+		slog.Info("spilled event queued",
+			"cluster", record.Cluster,
+			"spilled_at", record.SpilledAt,
+			"spilled", cm.spill.len())
+	}
+}
+
+// spillRecord is a spilled event as stored on disk.
+type spillRecord struct {
+	Cluster   string          `json:"cluster"`
+	SpilledAt time.Time       `json:"spilled_at"`
+	Event     json.RawMessage `json:"event"`
+}
+
+// spillEntry is one spilled event file.
+type spillEntry struct {
+	path string
+}
+
+func (e spillEntry) read() (*spillRecord, error) {
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spill file: %w", err)
+	}
+	var record spillRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse spill file: %w", err)
+	}
+	return &record, nil
+}
+
+// spillStore keeps overflowing events in a directory, one JSON file per event
+// named so that lexical order is arrival order.
+type spillStore struct {
+	dir       string
+	maxEvents int
+
+	mu      sync.Mutex
+	entries []spillEntry // oldest first
+	seq     uint64
+}
+
+// openSpillStore opens the spill directory, picking up events spilled before a
+// restart.
+func openSpillStore(dir string, maxEvents int) (*spillStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create spill directory: %w", err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spill directory: %w", err)
+	}
+	s := &spillStore{dir: dir, maxEvents: maxEvents}
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") {
+			s.entries = append(s.entries, spillEntry{path: filepath.Join(dir, f.Name())})
+		}
+	}
+	sort.Slice(s.entries, func(i, j int) bool { return s.entries[i].path < s.entries[j].path })
+	return s, nil
+}
+
+// put persists an event. It fails when the store already holds maxEvents.
+func (s *spillStore) put(clusterName string, event interface{}) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	now := time.Now().UTC()
+	data, err := json.Marshal(spillRecord{Cluster: clusterName, SpilledAt: now, Event: raw})
+	if err != nil {
+		return fmt.Errorf("failed to marshal spill record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxEvents > 0 && len(s.entries) >= s.maxEvents {
+		return fmt.Errorf("spill directory holds the maximum of %d events", s.maxEvents)
+	}
+	s.seq++
+	path := filepath.Join(s.dir, fmt.Sprintf("%020d-%06d-%s.json", now.UnixNano(), s.seq%1000000, clusterName))
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	s.entries = append(s.entries, spillEntry{path: path})
+	return nil
+}
+
+// oldest returns the oldest spilled event, if any.
+func (s *spillStore) oldest() (spillEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return spillEntry{}, false
+	}
+	return s.entries[0], true
+}
+
+// remove deletes a spilled event once it is queued or discarded.
+func (s *spillStore) remove(entry spillEntry) {
+	if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+		slog.Warn("failed to remove spill file", "path", entry.path, "error", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, e := range s.entries {
+		if e.path == entry.path {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// len returns the number of spilled events.
+func (s *spillStore) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
@@ -0,0 +1,120 @@
+package cluster
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/metrics"
+)
+
+// testEvent stands in for *events.FaultEvent.
+type testEvent struct {
+	ID         string    `json:"id"`
+	ReceivedAt time.Time `json:"-"`
+}
+
+func decodeTestEvent(data []byte, receivedAt time.Time) (interface{}, error) {
+	var event testEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+	event.ReceivedAt = receivedAt
+	return &event, nil
+}
+
+func newTestManager(t *testing.T, spillDir string, clusters ...ClusterConfig) *ConnectionManager {
+	t.Helper()
+	cm, err := NewConnectionManager(&ManagerConfig{
+		Clusters:            clusters,
+		GlobalQueueSize:     1,
+		QueueOverflowPolicy: OverflowDrop,
+		SpillDir:            spillDir,
+		DecodeSpilledEvent:  decodeTestEvent,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cm.cancel)
+	return cm
+}
+
+// queuedID returns the ID of the event at the head of the global queue.
+func queuedID(t *testing.T, cm *ConnectionManager) string {
+	t.Helper()
+	select {
+	case queued := <-cm.eventChan:
+		return queued.(map[string]interface{})["Event"].(*testEvent).ID
+	default:
+		t.Fatal("global queue is empty")
+		return ""
+	}
+}
+
+// fill queues an event of a cluster, applying its overflow policy when the
+// queue is full, as subscribeAndFanIn does.
+func fill(cm *ConnectionManager, clusterName, id string) {
+	conn := cm.connections[clusterName]
+	wrapped := wrapEvent(conn, &testEvent{ID: id})
+	select {
+	case cm.eventChan <- wrapped:
+	default:
+		cm.overflow(conn, &testEvent{ID: id}, wrapped)
+	}
+}
+
+func TestOverflowPolicies(t *testing.T) {
+	cm := newTestManager(t, "",
+		ClusterConfig{Name: "dev"},
+		ClusterConfig{Name: "prod", QueueOverflowPolicy: OverflowDropOldest})
+	counter := metrics.NewCounterVec("overflow", "", "cluster", "policy", "outcome")
+	cm.SetOverflowMetrics(counter)
+
+	// The global drop policy keeps the queued event
+	fill(cm, "dev", "dev-1")
+	fill(cm, "dev", "dev-2")
+	if got := queuedID(t, cm); got != "dev-1" {
+		t.Errorf("drop policy queued %s, want dev-1", got)
+	}
+
+	// The cluster's drop-oldest policy evicts it
+	fill(cm, "dev", "dev-3")
+	fill(cm, "prod", "prod-1")
+	if got := queuedID(t, cm); got != "prod-1" {
+		t.Errorf("drop-oldest policy queued %s, want prod-1", got)
+	}
+
+	stats := cm.QueueStats()
+	dropped := map[string]int64{}
+	for _, c := range stats.Clusters {
+		dropped[c.Cluster] = c.Dropped
+	}
+	// dev-2 was dropped and dev-3 evicted
+	if dropped["dev"] != 2 || dropped["prod"] != 0 {
+		t.Errorf("dropped = %v, want dev 2 and prod 0", dropped)
+	}
+}
+
+func TestOverflowSpill(t *testing.T) {
+	dir := t.TempDir()
+	cm := newTestManager(t, dir, ClusterConfig{Name: "prod", QueueOverflowPolicy: OverflowSpill})
+
+	fill(cm, "prod", "prod-1")
+	fill(cm, "prod", "prod-2")
+	fill(cm, "prod", "prod-3")
+	if got := cm.QueueStats().Spilled; got != 2 {
+		t.Fatalf("spilled = %d, want 2", got)
+	}
+
+	// Spilled events survive a restart and are queued oldest first
+	cm = newTestManager(t, dir, ClusterConfig{Name: "prod", QueueOverflowPolicy: OverflowSpill})
+	for _, want := range []string{"prod-2", "prod-3"} {
+		cm.restoreSpilled()
+		if got := queuedID(t, cm); got != want {
+			t.Errorf("restored %s, want %s", got, want)
+		}
+	}
+	if got := cm.QueueStats().Spilled; got != 0 {
+		t.Errorf("spilled after restore = %d, want 0", got)
+	}
+}
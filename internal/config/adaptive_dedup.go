@@ -0,0 +1,73 @@
+package config
+
+import "fmt"
+
+const (
+	// defaultAdaptiveDedupMinWindowSeconds is the minimum window when
+	// dedup_window_seconds is 0
+	defaultAdaptiveDedupMinWindowSeconds = 300
+	// defaultAdaptiveDedupMaxWindowSeconds caps the window of flapping faults (1 hour)
+	defaultAdaptiveDedupMaxWindowSeconds = 3600
+	// defaultAdaptiveDedupGrowthFactor doubles the window on every suppressed repeat
+	defaultAdaptiveDedupGrowthFactor = 2.0
+)
+
+// AdaptiveDedupConfig suppresses repeats of the same fault (same fault signature:
+// cluster, resource, fault type, and normalized fault description) within a
+// window that grows with the fault's recurrence frequency. Every repeat that
+// arrives within the window is dropped before aggregation and investigation and
+// multiplies the window by GrowthFactor, up to MaxWindowSeconds; a fault that
+// stays quiet for longer than its window starts over at MinWindowSeconds. A pod
+// crashing every 2 minutes is investigated once and then at most once an hour
+// with the defaults.
+type AdaptiveDedupConfig struct {
+	// Enabled turns on adaptive deduplication.
+	// Default: false
+	// Environment variable: ADAPTIVE_DEDUP_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// MinWindowSeconds is the window of a fault seen for the first time or after
+	// a quiet period.
+	// Default: dedup_window_seconds, or 300 when that is 0
+	// Environment variable: ADAPTIVE_DEDUP_MIN_WINDOW_SECONDS
+	MinWindowSeconds int `mapstructure:"min_window_seconds"`
+
+	// MaxWindowSeconds caps the window of a flapping fault.
+	// Default: 3600
+	// Environment variable: ADAPTIVE_DEDUP_MAX_WINDOW_SECONDS
+	MaxWindowSeconds int `mapstructure:"max_window_seconds"`
+
+	// GrowthFactor multiplies the window on every suppressed repeat (> 1).
+	// Default: 2
+	// Environment variable: ADAPTIVE_DEDUP_GROWTH_FACTOR
+	GrowthFactor float64 `mapstructure:"growth_factor"`
+}
+
+// Validate applies the defaults and checks the window bounds.
+func (a *AdaptiveDedupConfig) Validate(dedupWindowSeconds int) error {
+	if !a.Enabled {
+		return nil
+	}
+	if a.MinWindowSeconds == 0 {
+		a.MinWindowSeconds = dedupWindowSeconds
+		if a.MinWindowSeconds == 0 {
+			a.MinWindowSeconds = defaultAdaptiveDedupMinWindowSeconds
+		}
+	}
+	if a.MaxWindowSeconds == 0 {
+		a.MaxWindowSeconds = defaultAdaptiveDedupMaxWindowSeconds
+	}
+	if a.GrowthFactor == 0 {
+		a.GrowthFactor = defaultAdaptiveDedupGrowthFactor
+	}
+	if a.MinWindowSeconds < 0 {
+		return fmt.Errorf("adaptive_dedup.min_window_seconds must be positive, got %d", a.MinWindowSeconds)
+	}
+	if a.MaxWindowSeconds < a.MinWindowSeconds {
+		return fmt.Errorf("adaptive_dedup.max_window_seconds (%d) must be >= min_window_seconds (%d)", a.MaxWindowSeconds, a.MinWindowSeconds)
+	}
+	if a.GrowthFactor <= 1 {
+		return fmt.Errorf("adaptive_dedup.growth_factor must be greater than 1, got %g", a.GrowthFactor)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/egress"
+	"github.com/rbias/nightcrier/internal/keypool"
+)
+
+// defaultAgentEgressNetwork is the Docker network agent containers join under
+// egress control
+const defaultAgentEgressNetwork = "nightcrier-egress"
+
+// llmAPIHosts are the hosted LLM APIs of each provider
+var llmAPIHosts = map[string]string{
+	keypool.ProviderAnthropic: "api.anthropic.com:443",
+	keypool.ProviderOpenAI:    "api.openai.com:443",
+	keypool.ProviderGemini:    "generativelanguage.googleapis.com:443",
+}
+
+// AgentEgressConfig limits the agent container's network access to the LLM
+// endpoint and the Kubernetes API servers of its cluster. The container joins a
+// Docker network without a route to the outside (create it with "docker network
+// create --internal"), and its HTTP(S) traffic is sent to an egress proxy on that
+// network which only forwards to the allowed hosts. Nightcrier computes the
+// allow-list, writes it for the proxy (AllowlistFile), and records the policy on
+// every incident for security review. Run "nightcrier doctor" to check the network.
+type AgentEgressConfig struct {
+	// Enabled turns on egress control.
+	// Default: false
+	// Environment variable: AGENT_EGRESS_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// ProxyURL is the egress proxy on Network, e.g. "http://egress-proxy:3128".
+	// It replaces the proxy.llm settings for the agent.
+	// Environment variable: AGENT_EGRESS_PROXY_URL
+	ProxyURL string `mapstructure:"proxy_url"`
+
+	// Network is the internal Docker network the agent container joins instead of
+	// the host network.
+	// Default: "nightcrier-egress"
+	// Environment variable: AGENT_EGRESS_NETWORK
+	Network string `mapstructure:"network"`
+
+	// AllowedHosts are further destinations the agent may reach, as "host" (port
+	// 443) or "host:port", e.g. an artifact registry the skills download from.
+	// Config file only.
+	AllowedHosts []string `mapstructure:"allowed_hosts"`
+
+	// AllowlistFile is where the allowed host names of all clusters are written at
+	// startup, one per line, for the proxy to load (e.g. a squid dstdomain ACL).
+	// Empty keeps the allow-list in the proxy's own configuration.
+	// Environment variable: AGENT_EGRESS_ALLOWLIST_FILE
+	AllowlistFile string `mapstructure:"allowlist_file"`
+}
+
+// Environment returns the assignments passed to run-agent.sh to confine the agent
+// container, or nil when egress control is disabled. They override the proxy.llm
+// settings: every destination, the Kubernetes API included, goes through the proxy.
+func (e AgentEgressConfig) Environment() []string {
+	if !e.Enabled {
+		return nil
+	}
+	return []string{
+		"CONTAINER_NETWORK=" + e.Network,
+		"HTTP_PROXY=" + e.ProxyURL,
+		"HTTPS_PROXY=" + e.ProxyURL,
+		"NO_PROXY=",
+	}
+}
+
+// ExtraHosts returns AllowedHosts as "host:port".
+func (e AgentEgressConfig) ExtraHosts() []string {
+	hosts := make([]string, 0, len(e.AllowedHosts))
+	for _, h := range e.AllowedHosts {
+		if _, _, err := net.SplitHostPort(h); err != nil {
+			h = net.JoinHostPort(h, "443")
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts
+}
+
+// Validate applies defaults and checks the proxy and network.
+func (e *AgentEgressConfig) Validate() error {
+	if !e.Enabled {
+		return nil
+	}
+	if e.Network == "" {
+		e.Network = defaultAgentEgressNetwork
+	}
+	if e.ProxyURL == "" {
+		return fmt.Errorf("agent_egress.proxy_url is required (environment variable: AGENT_EGRESS_PROXY_URL)")
+	}
+	if err := validateHTTPURL("agent_egress.proxy_url", e.ProxyURL); err != nil {
+		return err
+	}
+	switch e.Network {
+	case "host", "none":
+		return fmt.Errorf("agent_egress.network must be an internal Docker network, got %q", e.Network)
+	}
+	for _, h := range e.AllowedHosts {
+		if h == "" || strings.ContainsAny(h, "/ ") {
+			return fmt.Errorf("agent_egress.allowed_hosts entries must be \"host\" or \"host:port\", got %q", h)
+		}
+	}
+	return nil
+}
+
+// LLMHosts returns the "host:port" destinations the agent talks to for the LLM:
+// the self-hosted or managed-cloud endpoint when configured, otherwise the hosted
+// API of the agent CLI's provider (goose: of every provider with an API key).
+func (c *Config) LLMHosts() []string {
+	hostOf := func(raw string) []string {
+		host, err := egress.HostPort(raw)
+		if err != nil {
+			return nil
+		}
+		return []string{host}
+	}
+	switch c.LLMProvider() {
+	case LLMProviderOpenAICompatible:
+		return hostOf(c.LLMEndpoint.BaseURL)
+	case LLMProviderAzureOpenAI:
+		return hostOf(c.AzureOpenAI.Endpoint)
+	case LLMProviderBedrock:
+		hosts := []string{fmt.Sprintf("bedrock-runtime.%s.amazonaws.com:443", c.Bedrock.Region)}
+		if c.Bedrock.RoleARN != "" {
+			hosts = append(hosts, fmt.Sprintf("sts.%s.amazonaws.com:443", c.Bedrock.Region))
+		}
+		return hosts
+	}
+	if provider := keypool.ProviderForAgent(c.AgentCLI); provider != "" {
+		return []string{llmAPIHosts[provider]}
+	}
+	keys := c.APIKeys()
+	var hosts []string
+	for _, provider := range []string{keypool.ProviderAnthropic, keypool.ProviderOpenAI, keypool.ProviderGemini} {
+		if len(keys[provider]) > 0 {
+			hosts = append(hosts, llmAPIHosts[provider])
+		}
+	}
+	return hosts
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Agent pinning policies
+const (
+	// AgentPinningWarn logs investigations whose agent runtime drifted from the pins
+	AgentPinningWarn = "warn"
+	// AgentPinningEnforce refuses to start, and refuses investigations, while the
+	// agent runtime differs from the pins
+	AgentPinningEnforce = "enforce"
+)
+
+// imageDigestPattern matches a pinned image digest
+var imageDigestPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// AgentPinningConfig pins the agent runtime investigations are expected to run
+// with. The agent CLI version and image digest are read from the agent image
+// with docker, and with the model recorded for every investigation (in
+// incident.json and the state store's agent executions) whether or not pins are
+// set. A runtime that differs from a pin, or whose pinned value cannot be read,
+// has drifted: the drift is logged, and under the enforce policy nightcrier
+// refuses to start and refuses investigations until the pins or the runtime are
+// updated.
+type AgentPinningConfig struct {
+	// CLIVersion is the expected agent CLI version, e.g. "1.0.30".
+	// Default: not pinned
+	// Environment variable: AGENT_PINNING_CLI_VERSION
+	CLIVersion string `mapstructure:"cli_version"`
+
+	// ImageDigest is the expected agent image digest ("sha256:<64 hex digits>").
+	// Default: not pinned
+	// Environment variable: AGENT_PINNING_IMAGE_DIGEST
+	ImageDigest string `mapstructure:"image_digest"`
+
+	// Model is the expected model. Agent profiles selecting another model drift.
+	// Default: not pinned
+	// Environment variable: AGENT_PINNING_MODEL
+	Model string `mapstructure:"model"`
+
+	// Policy is "warn" or "enforce".
+	// Default: "warn"
+	// Environment variable: AGENT_PINNING_POLICY
+	Policy string `mapstructure:"policy"`
+}
+
+// Enabled reports whether any version is pinned.
+func (a AgentPinningConfig) Enabled() bool {
+	return a.CLIVersion != "" || a.ImageDigest != "" || a.Model != ""
+}
+
+// Enforced reports whether drift refuses investigations.
+func (a AgentPinningConfig) Enforced() bool {
+	return a.Enabled() && a.Policy == AgentPinningEnforce
+}
+
+// Drift compares the agent runtime with the pins and describes each difference.
+// Empty values could not be read and drift from any pin.
+func (a AgentPinningConfig) Drift(cliVersion, imageDigest, model string) []string {
+	var drift []string
+	check := func(field, pinned, actual string) {
+		if pinned == "" || pinned == actual {
+			return
+		}
+		if actual == "" {
+			actual = "unknown"
+		}
+		drift = append(drift, fmt.Sprintf("%s is %s, pinned %s", field, actual, pinned))
+	}
+	check("agent CLI version", strings.TrimPrefix(a.CLIVersion, "v"), cliVersion)
+	check("agent image digest", a.ImageDigest, imageDigest)
+	check("model", a.Model, model)
+	return drift
+}
+
+// Validate applies the default policy and checks the pins.
+func (a *AgentPinningConfig) Validate() error {
+	if a.Policy == "" {
+		a.Policy = AgentPinningWarn
+	}
+	if a.Policy != AgentPinningWarn && a.Policy != AgentPinningEnforce {
+		return fmt.Errorf("agent_pinning.policy must be warn or enforce, got %q", a.Policy)
+	}
+	if a.ImageDigest != "" && !imageDigestPattern.MatchString(a.ImageDigest) {
+		return fmt.Errorf("agent_pinning.image_digest must be sha256:<64 hex digits>, got %q", a.ImageDigest)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/match"
+)
+
+// AgentProfilesConfig maps investigation priority to agent profiles, so routine
+// faults use a cheap fast model and production CRITICALs get the strongest model
+// and more time. Priority is described by severity, cluster, namespace, and
+// incident labels (e.g. a namespace tier or business-criticality label attached
+// by incident_labels). Config file only.
+type AgentProfilesConfig struct {
+	// Profiles are the named agent profiles, e.g. "fast" and "deep".
+	Profiles map[string]AgentProfileConfig `mapstructure:"profiles"`
+
+	// Rules select a profile per incident. The first matching rule wins; incidents
+	// matching no rule use agent_model, agent_timeout, and agent_timeout_by_severity.
+	Rules []AgentProfileRuleConfig `mapstructure:"rules"`
+}
+
+// AgentProfileConfig overrides the agent settings for an investigation. Zero
+// values keep the global settings.
+type AgentProfileConfig struct {
+	// Model is passed to the agent CLI like agent_model (e.g. "haiku", "opus")
+	Model string `mapstructure:"model"`
+
+	// MaxTurns limits the agent's conversation turns (0: agent CLI default)
+	MaxTurns int `mapstructure:"max_turns"`
+
+	// Timeout is the agent timeout in seconds, taking precedence over
+	// agent_timeout_by_severity (0: agent_timeout or the severity timeout)
+	Timeout int `mapstructure:"timeout"`
+}
+
+// AgentProfileRuleConfig selects an agent profile for matching incidents. A rule
+// matches when every set condition matches; an empty list matches any value.
+type AgentProfileRuleConfig struct {
+	Severities []string `mapstructure:"severities"`
+	Clusters   []string `mapstructure:"clusters"`
+	Namespaces []string `mapstructure:"namespaces"`
+
+	// Labels must all be present on the incident with the given values. Keys are
+	// lowercased when loaded.
+	Labels map[string]string `mapstructure:"labels"`
+
+	// Profile is the name of the profile to use
+	Profile string `mapstructure:"profile"`
+}
+
+// Enabled reports whether any profile rules are configured.
+func (a AgentProfilesConfig) Enabled() bool {
+	return len(a.Rules) > 0
+}
+
+// Select returns the profile of the first rule matching an incident, and its name.
+// ok is false when no rule matches.
+func (a AgentProfilesConfig) Select(severity, cluster, namespace string, labels map[string]string) (name string, profile AgentProfileConfig, ok bool) {
+	for _, rule := range a.Rules {
+		if rule.matches(severity, cluster, namespace, labels) {
+			name = strings.ToLower(rule.Profile)
+			return name, a.Profiles[name], true
+		}
+	}
+	return "", AgentProfileConfig{}, false
+}
+
+// matches reports whether the rule applies to an incident.
+func (r AgentProfileRuleConfig) matches(severity, cluster, namespace string, labels map[string]string) bool {
+	if !match.Any(r.Severities, severity) || !match.Any(r.Clusters, cluster) || !match.Any(r.Namespaces, namespace) {
+		return false
+	}
+	for key, value := range r.Labels {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks the profiles and that every rule names a defined profile.
+func (a AgentProfilesConfig) Validate() error {
+	for name, profile := range a.Profiles {
+		if profile.MaxTurns < 0 {
+			return fmt.Errorf("agent_profiles.profiles.%s.max_turns must be >= 0, got %d", name, profile.MaxTurns)
+		}
+		if profile.Timeout < 0 {
+			return fmt.Errorf("agent_profiles.profiles.%s.timeout must be >= 0, got %d", name, profile.Timeout)
+		}
+	}
+	for i, rule := range a.Rules {
+		if rule.Profile == "" {
+			return fmt.Errorf("agent_profiles.rules[%d]: profile is required", i)
+		}
+		if _, ok := a.Profiles[strings.ToLower(rule.Profile)]; !ok {
+			return fmt.Errorf("agent_profiles.rules[%d]: unknown profile %q", i, rule.Profile)
+		}
+		for _, severity := range rule.Severities {
+			if !validSeverities[strings.ToUpper(severity)] {
+				return fmt.Errorf("agent_profiles.rules[%d]: invalid severity '%s': must be one of DEBUG, INFO, WARNING, ERROR, CRITICAL", i, severity)
+			}
+		}
+	}
+	return nil
+}
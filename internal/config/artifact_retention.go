@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default retention of the bulky artifact classes
+const (
+	defaultRetentionLogsDays           = 30
+	defaultRetentionSessionArchiveDays = 7
+	defaultRetentionIntervalMinutes    = 60
+)
+
+// ArtifactRetentionConfig expires investigation artifacts by class: reports
+// (investigation.md, findings.json, incident.json) are kept long-term, while the
+// bulky agent logs and session archives expire earlier. Retention runs
+// periodically over the local workspaces and the artifact storage backend
+// (filesystem or Azure Blob Storage); incidents under a legal hold are skipped.
+//
+// For Azure, lifecycle management can expire blobs as well, even while nightcrier
+// is not running: enable AzureIndexTags and apply the policy printed by
+// "nightcrier artifacts lifecycle-policy".
+type ArtifactRetentionConfig struct {
+	// Enabled turns on periodic artifact retention.
+	// Default: false
+	// Environment variable: ARTIFACT_RETENTION_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// ReportDays is how long reports, findings, and the other small artifacts are
+	// kept. incident.json is always kept. 0 keeps them forever.
+	// Default: 0 (forever)
+	// Environment variable: ARTIFACT_RETENTION_REPORT_DAYS
+	ReportDays int `mapstructure:"report_days"`
+
+	// LogsDays is how long agent logs and the prompt sent to the agent are kept.
+	// 0 uses the default; -1 keeps them forever.
+	// Default: 30
+	// Environment variable: ARTIFACT_RETENTION_LOGS_DAYS
+	LogsDays int `mapstructure:"logs_days"`
+
+	// SessionArchiveDays is how long agent CLI session archives are kept. 0 uses
+	// the default; -1 keeps them forever.
+	// Default: 7
+	// Environment variable: ARTIFACT_RETENTION_SESSION_ARCHIVE_DAYS
+	SessionArchiveDays int `mapstructure:"session_archive_days"`
+
+	// IntervalMinutes is how often retention runs.
+	// Default: 60
+	// Environment variable: ARTIFACT_RETENTION_INTERVAL_MINUTES
+	IntervalMinutes int `mapstructure:"interval_minutes"`
+
+	// AzureIndexTags tags uploaded Azure blobs with their artifact class
+	// (artifact_class blob index tag), for the lifecycle management policy. Not
+	// supported on storage accounts with a hierarchical namespace.
+	// Default: false
+	// Environment variable: ARTIFACT_RETENTION_AZURE_INDEX_TAGS
+	AzureIndexTags bool `mapstructure:"azure_index_tags"`
+}
+
+// Policy returns the retention of each artifact class (see storage.RetentionPolicy).
+// Classes kept forever have no entry.
+func (r ArtifactRetentionConfig) Policy() map[string]time.Duration {
+	policy := make(map[string]time.Duration)
+	for class, days := range map[string]int{
+		"report":          r.ReportDays,
+		"logs":            r.LogsDays,
+		"session_archive": r.SessionArchiveDays,
+	} {
+		if days > 0 {
+			policy[class] = time.Duration(days) * 24 * time.Hour
+		}
+	}
+	return policy
+}
+
+// Interval returns how often retention runs.
+func (r ArtifactRetentionConfig) Interval() time.Duration {
+	return time.Duration(r.IntervalMinutes) * time.Minute
+}
+
+// Validate applies the defaults and checks the retention periods.
+func (r *ArtifactRetentionConfig) Validate() error {
+	if r.IntervalMinutes == 0 {
+		r.IntervalMinutes = defaultRetentionIntervalMinutes
+	}
+	if r.LogsDays == 0 {
+		r.LogsDays = defaultRetentionLogsDays
+	}
+	if r.SessionArchiveDays == 0 {
+		r.SessionArchiveDays = defaultRetentionSessionArchiveDays
+	}
+	if r.IntervalMinutes < 0 {
+		return fmt.Errorf("artifact_retention.interval_minutes must be positive, got %d", r.IntervalMinutes)
+	}
+	if r.ReportDays < 0 {
+		return fmt.Errorf("artifact_retention.report_days must be >= 0, got %d", r.ReportDays)
+	}
+	if r.LogsDays < -1 || r.SessionArchiveDays < -1 {
+		return fmt.Errorf("artifact_retention.logs_days and session_archive_days must be >= -1, got %d and %d", r.LogsDays, r.SessionArchiveDays)
+	}
+	return nil
+}
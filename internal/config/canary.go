@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+)
+
+// CanaryConfig configures the synthetic heartbeat investigation. Every interval,
+// each cluster's connection is checked and a trivial investigation of a designated
+// healthy test resource is run through the agent and saved to storage, so a broken
+// pipeline (MCP connection, agent, storage, notifications) is noticed even when no
+// real faults arrive. Clusters may override the test resource in their own canary
+// section.
+type CanaryConfig struct {
+	// IntervalMinutes is the time between canary runs. 0 disables the canary.
+	// Environment variable: CANARY_INTERVAL_MINUTES
+	IntervalMinutes int `mapstructure:"interval_minutes"`
+
+	// NotifySuccess also sends a short notification for every passing canary run,
+	// exercising the notification stage. By default only failures and recoveries
+	// are notified.
+	// Default: false
+	// Environment variable: CANARY_NOTIFY_SUCCESS
+	NotifySuccess bool `mapstructure:"notify_success"`
+
+	// Default test resource for every cluster. Clusters without a test resource
+	// (here or in their own canary section), or without triage enabled, only have
+	// their connection checked.
+	// Environment variables: CANARY_NAMESPACE, CANARY_RESOURCE_KIND, CANARY_RESOURCE_NAME
+	cluster.CanaryTarget `mapstructure:",squash"`
+}
+
+// Enabled reports whether the canary is configured to run.
+func (c CanaryConfig) Enabled() bool {
+	return c.IntervalMinutes > 0
+}
+
+// CanaryTarget returns the effective canary test resource for a cluster,
+// combining the global canary settings with the cluster's overrides.
+func (c *Config) CanaryTarget(clusterName string) cluster.CanaryTarget {
+	target := c.Canary.CanaryTarget
+	for _, cl := range c.Clusters {
+		if cl.Name != clusterName {
+			continue
+		}
+		if cl.Canary.Namespace != "" {
+			target.Namespace = cl.Canary.Namespace
+		}
+		if cl.Canary.ResourceKind != "" {
+			target.ResourceKind = cl.Canary.ResourceKind
+		}
+		if cl.Canary.ResourceName != "" {
+			target.ResourceName = cl.Canary.ResourceName
+		}
+	}
+	return target
+}
+
+// Validate checks the canary settings.
+func (c *CanaryConfig) Validate() error {
+	if c.IntervalMinutes < 0 {
+		return fmt.Errorf("canary.interval_minutes must be >= 0, got %d (environment variable: CANARY_INTERVAL_MINUTES)", c.IntervalMinutes)
+	}
+	if (c.ResourceKind == "") != (c.ResourceName == "") {
+		return fmt.Errorf("canary.resource_kind and canary.resource_name must be set together")
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package config
+
+import "fmt"
+
+// Cluster connection modes
+const (
+	// ConnectEager opens a cluster's MCP subscription as soon as its connection
+	// starts, before its permissions are validated (warm standby)
+	ConnectEager = "eager"
+	// ConnectLazy opens a cluster's MCP subscription once its permissions are
+	// validated
+	ConnectLazy = "lazy"
+)
+
+// defaultClusterValidationTimeoutSeconds bounds each cluster's permission
+// validation at startup
+const defaultClusterValidationTimeoutSeconds = 30
+
+// defaultClusterValidationConcurrency is how many clusters are validated at once
+// at startup
+const defaultClusterValidationConcurrency = 8
+
+// ClusterStartupConfig controls how cluster connections are brought up. By
+// default every triage-enabled cluster's permissions are validated before any
+// events are processed, several clusters at a time. A cluster that fails
+// validation is marked degraded and validated again by its connection with the
+// reconnect backoff, and a summary of the validation is logged; with
+// RequireAllClusters, startup fails instead. With FastStart, each cluster is
+// validated on its own: events from healthy clusters are processed immediately
+// while others are still validating.
+type ClusterStartupConfig struct {
+	// FastStart validates clusters independently instead of gating startup on all
+	// of them.
+	// Default: false
+	// Environment variable: CLUSTER_STARTUP_FAST_START
+	FastStart bool `mapstructure:"fast_start"`
+
+	// Connect is when a cluster's MCP subscription is opened with FastStart:
+	// "eager" opens it right away, so the connection is warm and events wait in it
+	// while permissions are validated; "lazy" opens it once the permissions are
+	// validated, so no MCP server is contacted for a cluster that cannot be
+	// triaged yet. Without FastStart, every cluster is validated before any
+	// connection is opened.
+	// Default: "eager"
+	// Environment variable: CLUSTER_STARTUP_CONNECT
+	Connect string `mapstructure:"connect"`
+
+	// ValidationTimeoutSeconds bounds the permission validation of each cluster
+	// (per attempt).
+	// Default: 30
+	// Environment variable: CLUSTER_STARTUP_VALIDATION_TIMEOUT_SECONDS
+	ValidationTimeoutSeconds int `mapstructure:"validation_timeout_seconds"`
+
+	// ValidationConcurrency is how many clusters are validated at once at startup.
+	// Default: 8
+	// Environment variable: CLUSTER_STARTUP_VALIDATION_CONCURRENCY
+	ValidationConcurrency int `mapstructure:"validation_concurrency"`
+
+	// RequireAllClusters fails startup when a cluster cannot be validated, instead
+	// of marking it degraded. Ignored with FastStart.
+	// Default: false
+	// Environment variable: CLUSTER_STARTUP_REQUIRE_ALL_CLUSTERS
+	RequireAllClusters bool `mapstructure:"require_all_clusters"`
+}
+
+// LazyConnect reports whether MCP subscriptions wait for permission validation.
+func (s ClusterStartupConfig) LazyConnect() bool {
+	return s.Connect == ConnectLazy
+}
+
+// Validate applies the defaults and checks the connection mode.
+func (s *ClusterStartupConfig) Validate() error {
+	if s.Connect == "" {
+		s.Connect = ConnectEager
+	}
+	if s.ValidationTimeoutSeconds == 0 {
+		s.ValidationTimeoutSeconds = defaultClusterValidationTimeoutSeconds
+	}
+	if s.ValidationConcurrency == 0 {
+		s.ValidationConcurrency = defaultClusterValidationConcurrency
+	}
+	if s.Connect != ConnectEager && s.Connect != ConnectLazy {
+		return fmt.Errorf("cluster_startup.connect must be %q or %q, got %q", ConnectEager, ConnectLazy, s.Connect)
+	}
+	if s.ValidationTimeoutSeconds < 0 {
+		return fmt.Errorf("cluster_startup.validation_timeout_seconds must be positive, got %d", s.ValidationTimeoutSeconds)
+	}
+	if s.ValidationConcurrency < 0 {
+		return fmt.Errorf("cluster_startup.validation_concurrency must be positive, got %d", s.ValidationConcurrency)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultCoalescingWindowSeconds is how long notifications are collected by default
+	defaultCoalescingWindowSeconds = 30
+	// maxCoalescingWindowSeconds bounds how long a notification can be held back
+	maxCoalescingWindowSeconds = 600
+)
+
+// NotificationCoalescingConfig merges notifications that fire within a short
+// window (circuit breaker alerts, cluster connection and queue alerts, budget and
+// canary alerts, incident notifications) into a single message with a section per
+// kind, reducing pager fatigue when one cause triggers many notifications. A
+// window holding a single notification delivers it unchanged.
+//
+// Notifications are held for up to the window before delivery. Coalesced
+// messages are delivered in the background: delivery failures are logged and not
+// retried by the notification outbox.
+type NotificationCoalescingConfig struct {
+	// Enabled turns on notification coalescing.
+	// Default: false
+	// Environment variable: NOTIFICATION_COALESCING_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// WindowSeconds is how long notifications are collected after the first one
+	// before they are delivered (1-600).
+	// Default: 30
+	// Environment variable: NOTIFICATION_COALESCING_WINDOW_SECONDS
+	WindowSeconds int `mapstructure:"window_seconds"`
+}
+
+// Window returns the coalescing window.
+func (n NotificationCoalescingConfig) Window() time.Duration {
+	return time.Duration(n.WindowSeconds) * time.Second
+}
+
+// Validate applies the default window and checks its range.
+func (n *NotificationCoalescingConfig) Validate() error {
+	if !n.Enabled {
+		return nil
+	}
+	if n.WindowSeconds == 0 {
+		n.WindowSeconds = defaultCoalescingWindowSeconds
+	}
+	if n.WindowSeconds < 1 || n.WindowSeconds > maxCoalescingWindowSeconds {
+		return fmt.Errorf("notification_coalescing.window_seconds must be between 1 and %d, got %d",
+			maxCoalescingWindowSeconds, n.WindowSeconds)
+	}
+	return nil
+}
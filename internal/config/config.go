@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -9,40 +10,138 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	"github.com/rbias/nightcrier/internal/budget"
 	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/dialer"
+	"github.com/rbias/nightcrier/internal/labels"
+	"github.com/rbias/nightcrier/internal/proxy"
+	"github.com/rbias/nightcrier/internal/skills"
 )
 
+// validSeverities are the accepted severity names, in uppercase.
+var validSeverities = map[string]bool{
+	"DEBUG": true, "INFO": true, "WARNING": true, "ERROR": true, "CRITICAL": true,
+}
+
 // Config holds the application configuration.
 type Config struct {
+	// Profile selects the environment overlay merged over the base config file
+	// (<name>.<profile>.yaml next to it) and its cluster overlay directory
+	// (clusters.<profile>.d); see layers.go for the precedence of the layers.
+	// Default: "" (base config file and clusters.d only)
+	// Environment variable: CONFIG_PROFILE
+	Profile string `mapstructure:"profile"`
+
+	// ConfigLayers are the overlay files merged over the base config file, in
+	// order. Set by LoadWithConfigFile.
+	ConfigLayers []string `mapstructure:"-"`
+
+	// StrictConfig requires every setting with a managed default (queue sizes,
+	// SSE backoffs, agent settings; see ManagedDefaults) to be set explicitly,
+	// failing validation otherwise, for deployments that want no implicit values.
+	// Default: false (managed defaults fill unset settings)
+	// Environment variable: STRICT_CONFIG
+	StrictConfig bool `mapstructure:"strict_config"`
+
 	// Cluster Configuration
 	Clusters      []cluster.ClusterConfig `mapstructure:"clusters"`
 	SubscribeMode string                  `mapstructure:"subscribe_mode"` // events, faults
 
+	// Single-cluster compatibility mode (replaces the legacy cmd/runner binary).
+	// When MCPEndpoint is set and no clusters are configured, or SingleCluster is
+	// true, a single cluster named SingleClusterName is synthesized from
+	// MCPEndpoint and KubeconfigPath before validation.
+	MCPEndpoint       string `mapstructure:"mcp_endpoint"`
+	SingleCluster     bool   `mapstructure:"single_cluster"`
+	SingleClusterName string `mapstructure:"single_cluster_name"`
+
 	// Workspace
 	WorkspaceRoot string `mapstructure:"workspace_root"`
 
+	// WorkspaceScratchDir places active workspaces on a fast scratch directory
+	// (typically a tmpfs mount). When the agent finishes, only the final artifacts
+	// are moved to the incident's directory under workspace_root; everything else
+	// the agent wrote is discarded. Empty creates workspaces directly under
+	// workspace_root.
+	// Default: "" (disabled)
+	WorkspaceScratchDir string `mapstructure:"workspace_scratch_dir"`
+
+	// WorkspaceTemplate is a directory whose contents (an organization README,
+	// contact list, common scripts) are copied into every new workspace. A
+	// cluster's workspace_template is copied after it, replacing files of the same
+	// name.
+	// Default: "" (disabled)
+	WorkspaceTemplate string `mapstructure:"workspace_template"`
+
+	// QuarantineDir stores incoming event payloads that were oversized or malformed
+	// Default: "{workspace_root}/quarantine"
+	QuarantineDir string `mapstructure:"quarantine_dir"`
+
+	// PauseFile persists the triage pause switch (global and per-cluster pauses set
+	// through the admin API, the pause CLI command, or the Slack app)
+	// Default: "{workspace_root}/triage-pause.json"
+	PauseFile string `mapstructure:"pause_file"`
+
 	// Logging
 	LogLevel string `mapstructure:"log_level"`
 
 	// Slack Integration
 	SlackWebhookURL string `mapstructure:"slack_webhook_url"`
 
+	// Discord Integration
+	DiscordWebhookURL string `mapstructure:"discord_webhook_url"`
+
+	// Mattermost Integration. Channel and username override the incoming
+	// webhook's defaults when the webhook allows it.
+	MattermostWebhookURL string `mapstructure:"mattermost_webhook_url"`
+	MattermostChannel    string `mapstructure:"mattermost_channel"`
+	MattermostUsername   string `mapstructure:"mattermost_username"`
+
 	// Agent Configuration
 	AgentScriptPath       string `mapstructure:"agent_script_path"`
 	AgentSystemPromptFile string `mapstructure:"agent_system_prompt_file"`
 	AgentAllowedTools     string `mapstructure:"agent_allowed_tools"`
 	AgentModel            string `mapstructure:"agent_model"`
 	AgentTimeout          int    `mapstructure:"agent_timeout"` // seconds
+	// AgentTimeoutBySeverity overrides AgentTimeout for incidents of the given
+	// severities (seconds), e.g. {CRITICAL: 1200, WARNING: 300}, so important incidents
+	// get deeper investigations. Severities without an entry use AgentTimeout.
+	AgentTimeoutBySeverity map[string]int `mapstructure:"agent_timeout_by_severity"`
 	AgentCLI              string `mapstructure:"agent_cli"`     // claude, codex, goose, gemini
 	AgentImage            string `mapstructure:"agent_image"`              // Docker image for agent container
 	AgentVerbose          bool   `mapstructure:"agent_verbose"`           // Enable verbose agent output
+	AgentStreamProgress   bool   `mapstructure:"agent_stream_progress"`   // Parse the agent's structured output stream for progress (claude only)
 	AdditionalAgentPrompt string `mapstructure:"additional_agent_prompt"` // Optional additional context for agent (cluster-specific SLOs, escalation info)
 
+	// ReportLanguage is the language investigation reports and Slack summaries are
+	// written in, as a language name ("Japanese") or ISO 639-1 code ("ja").
+	// Codes are normalized to names. Empty means English.
+	ReportLanguage string `mapstructure:"report_language"`
+
 	// LLM API Keys (optional - can also be set via environment)
 	AnthropicAPIKey string `mapstructure:"anthropic_api_key"`
 	OpenAIAPIKey    string `mapstructure:"openai_api_key"`
 	GeminiAPIKey    string `mapstructure:"gemini_api_key"`
 
+	// Additional API keys per provider. Keys are rotated round-robin; a rate-limited
+	// key is benched for APIKeyCooldownSeconds and a rejected (revoked) key is taken
+	// out of rotation, failing the investigation over to the next key.
+	AnthropicAPIKeys      []string `mapstructure:"anthropic_api_keys"`
+	OpenAIAPIKeys         []string `mapstructure:"openai_api_keys"`
+	GeminiAPIKeys         []string `mapstructure:"gemini_api_keys"`
+	APIKeyCooldownSeconds int      `mapstructure:"api_key_cooldown_seconds"`
+
+	// LLMRateLimits paces agent launches per LLM provider (anthropic, openai, gemini,
+	// openai-compatible, azure-openai, bedrock) to stay within its rate limits
+	LLMRateLimits map[string]LLMRateLimitConfig `mapstructure:"llm_rate_limits"`
+
+	// LLM Provider Configuration (optional, at most one)
+	// Points the agent at a self-hosted OpenAI-compatible endpoint (vLLM, Ollama, ...)
+	// or a managed-cloud LLM offering instead of the provider's public API
+	LLMEndpoint LLMEndpointConfig `mapstructure:"llm_endpoint"`
+	AzureOpenAI AzureOpenAIConfig `mapstructure:"azure_openai"`
+	Bedrock     BedrockConfig     `mapstructure:"bedrock"`
+
 	// Kubernetes Configuration
 	KubeconfigPath    string `mapstructure:"kubeconfig_path"`
 	KubernetesContext string `mapstructure:"kubernetes_context"`
@@ -50,17 +149,55 @@ type Config struct {
 	// Event Processing (Phase 1 additions)
 	SeverityThreshold   string `mapstructure:"severity_threshold"`
 	MaxConcurrentAgents int    `mapstructure:"max_concurrent_agents"`
+	// ReservedAgentSlots is the number of MaxConcurrentAgents slots kept free for
+	// incidents at or above ReservedSeverity, so a flood of low-severity investigations
+	// never blocks a CRITICAL one from starting immediately. 0 disables the reservation.
+	ReservedAgentSlots  int    `mapstructure:"reserved_agent_slots"`
+	ReservedSeverity    string `mapstructure:"reserved_severity"`
 	GlobalQueueSize     int    `mapstructure:"global_queue_size"`
 	ClusterQueueSize    int    `mapstructure:"cluster_queue_size"`
 	DedupWindowSeconds  int    `mapstructure:"dedup_window_seconds"`
 	QueueOverflowPolicy string `mapstructure:"queue_overflow_policy"`
 	ShutdownTimeout     int    `mapstructure:"shutdown_timeout"` // seconds
 
+	// QueueSpillDir persists events overflowing the global queue under the spill
+	// overflow policy (global or per cluster) until the queue has room for them
+	// Default: "{workspace_root}/queue-spill"
+	QueueSpillDir string `mapstructure:"queue_spill_dir"`
+	// QueueSpillMaxEvents caps the events held in the spill directory; further
+	// overflowing events are dropped
+	// Default: 10000
+	QueueSpillMaxEvents int `mapstructure:"queue_spill_max_events"`
+
+	// QueueAlertThresholdPercent alerts through the configured notifiers when the
+	// global queue or a cluster's queue reaches this percentage of its capacity.
+	// 0 disables utilization alerts.
+	QueueAlertThresholdPercent int `mapstructure:"queue_alert_threshold_percent"`
+	// AlertOnDroppedEvents alerts when events are lost because a queue was full
+	AlertOnDroppedEvents bool `mapstructure:"alert_on_dropped_events"`
+
+	// InvestigationCacheTTLSeconds serves a previous report instead of re-running the agent
+	// when an identical fault signature completed within this many seconds. 0 disables caching.
+	InvestigationCacheTTLSeconds int `mapstructure:"investigation_cache_ttl_seconds"`
+
 	// SSE/MCP Reconnection
 	SSEReconnectInitialBackoff int `mapstructure:"sse_reconnect_initial_backoff"` // seconds
 	SSEReconnectMaxBackoff     int `mapstructure:"sse_reconnect_max_backoff"`     // seconds
 	SSEReadTimeout             int `mapstructure:"sse_read_timeout"`              // seconds
 
+	// ClusterDisconnectAlertSeconds alerts through the configured notifiers when a
+	// single cluster's connection stays disconnected or failed this long, and again
+	// when it recovers. 0 disables cluster connection alerts.
+	ClusterDisconnectAlertSeconds int `mapstructure:"cluster_disconnect_alert_seconds"`
+
+	// ClusterStartup controls permission validation and MCP connections at startup
+	// (see ClusterStartupConfig)
+	ClusterStartup ClusterStartupConfig `mapstructure:"cluster_startup"`
+
+	// CredentialExpiry warns before the credentials of the triage kubeconfigs
+	// expire (see CredentialExpiryConfig)
+	CredentialExpiry CredentialExpiryConfig `mapstructure:"credential_expiry"`
+
 	// Azure Storage Configuration (optional - used when cloud storage is enabled)
 	AzureStorageConnectionString string `mapstructure:"azure_storage_connection_string"`
 	AzureStorageAccount          string `mapstructure:"azure_storage_account"`
@@ -78,10 +215,513 @@ type Config struct {
 	// SQLite (embedded), and PostgreSQL (centralized). Default: filesystem
 	StateStorage StateStorage `mapstructure:"state_storage"`
 
+	// Incident Lifecycle Configuration
+	// Holds investigations for human review and expires incidents left open
+	IncidentLifecycle IncidentLifecycleConfig `mapstructure:"incident_lifecycle"`
+
 	// Skills Configuration
 	// Configures where downloaded skills (like k8s4agents) are cached and
 	// whether to preload triage scripts
 	Skills SkillsConfig `mapstructure:"skills"`
+
+	// Runbooks Configuration
+	// Maps fault types and resource kinds to organization runbooks that are
+	// attached to the agent workspace and referenced in the prompt
+	Runbooks RunbooksConfig `mapstructure:"runbooks"`
+
+	// Postmortem Publishing Configuration
+	// Commits finished investigations to a Git repository and opens a pull request
+	Postmortem PostmortemConfig `mapstructure:"postmortem"`
+
+	// Knowledge Base Publishing Configuration
+	// Creates or updates a Confluence page or Notion database entry per resolved incident
+	KnowledgeBase KnowledgeBaseConfig `mapstructure:"knowledge_base"`
+
+	// ServiceNow Configuration
+	// Files a ServiceNow incident record per investigated fault
+	ServiceNow ServiceNowConfig `mapstructure:"servicenow"`
+
+	// Lifecycle Webhooks Configuration
+	// Signed webhooks posted at each incident lifecycle transition for external
+	// workflow engines
+	LifecycleWebhooks LifecycleWebhooksConfig `mapstructure:"lifecycle_webhooks"`
+
+	// Ownership Configuration
+	// Resolves the team and service owning the affected workload from annotations,
+	// a Backstage catalog, or a CMDB, and routes notifications to the owning team
+	Ownership OwnershipConfig `mapstructure:"ownership"`
+
+	// Slack App Configuration
+	// Socket Mode app with an App Home of open incidents and the /nightcrier slash
+	// command, backed by the state store
+	SlackApp SlackAppConfig `mapstructure:"slack_app"`
+
+	// Health Server Configuration
+	// Bind address, TLS, and authentication for the health monitoring endpoints
+	HealthServer HealthServerConfig `mapstructure:"health_server"`
+
+	// Canary Configuration
+	// Periodic synthetic investigations that validate the pipeline end to end
+	Canary CanaryConfig `mapstructure:"canary"`
+
+	// Incident Labels Configuration
+	// Labels and annotations attached to incidents from cluster labels, rules, and
+	// agent findings
+	IncidentLabels IncidentLabelsConfig `mapstructure:"incident_labels"`
+
+	// Agent Profiles Configuration
+	// Selects the agent model, turn limit, and timeout per incident by severity,
+	// cluster, namespace, and incident labels
+	AgentProfiles AgentProfilesConfig `mapstructure:"agent_profiles"`
+
+	// Agent Hardening Configuration
+	// Least-privilege execution of the agent container (unprivileged user, no
+	// capabilities, no-new-privileges, read-only root filesystem)
+	AgentHardening AgentHardeningConfig `mapstructure:"agent_hardening"`
+
+	// Agent Egress Configuration
+	// Limits the agent container's network access to the LLM endpoint and the
+	// Kubernetes API through an allow-listing egress proxy
+	AgentEgress AgentEgressConfig `mapstructure:"agent_egress"`
+
+	// Agent Debug Bundle Configuration
+	// State captured into the workspace when the agent crashes or is OOM-killed
+	AgentDebugBundle AgentDebugBundleConfig `mapstructure:"agent_debug_bundle"`
+
+	// Notification Coalescing Configuration
+	// Merges related notifications that fire within a short window into one message
+	NotificationCoalescing NotificationCoalescingConfig `mapstructure:"notification_coalescing"`
+
+	// Notification Routing Configuration
+	// Routes notifications to named channels by severity, cluster labels, and
+	// incident status
+	NotificationRouting NotificationRoutingConfig `mapstructure:"notification_routing"`
+
+	// Adaptive Dedup Configuration
+	// Suppresses repeats of flapping faults within a window that grows with their
+	// recurrence frequency
+	AdaptiveDedup AdaptiveDedupConfig `mapstructure:"adaptive_dedup"`
+
+	// Sampling Configuration
+	// Investigates one in N occurrences of noisy fault types or namespaces
+	Sampling SamplingConfig `mapstructure:"sampling"`
+
+	// Incident ID Configuration
+	// Readable incident display IDs (e.g. NC-2024-0613-prod-0042)
+	IncidentIDs IncidentIDsConfig `mapstructure:"incident_ids"`
+
+	// Report Rendering Configuration
+	// Markdown to HTML conversion of investigation reports, with HTML sanitization
+	ReportRendering ReportRenderingConfig `mapstructure:"report_rendering"`
+
+	// Follow-Up Incident Configuration
+	// Links recurring faults to their earlier resolved incident and investigation
+	FollowUp FollowUpConfig `mapstructure:"follow_up"`
+
+	// ArtifactRetention expires investigation artifacts by class (see
+	// ArtifactRetentionConfig)
+	ArtifactRetention ArtifactRetentionConfig `mapstructure:"artifact_retention"`
+
+	// Output Verification Configuration
+	// Checks the claims of the agent's findings against the cluster and marks them
+	// in the report
+	Verification VerificationConfig `mapstructure:"verification"`
+
+	// Topology Configuration
+	// Collects the graph of objects around the affected resource into the
+	// workspace and the HTML report
+	Topology TopologyConfig `mapstructure:"topology"`
+
+	// Noise Analysis Configuration
+	// Periodically reviews incident history and suggests tuning for noisy fault
+	// types
+	NoiseAnalysis NoiseAnalysisConfig `mapstructure:"noise_analysis"`
+
+	// SQLite Maintenance Configuration
+	// Checkpoints the WAL, vacuums, and monitors the size of a SQLite state store
+	SQLiteMaintenance SQLiteMaintenanceConfig `mapstructure:"sqlite_maintenance"`
+
+	// Incident Feed Configuration
+	// Follows the incident changes made by every instance sharing the state store
+	IncidentFeed IncidentFeedConfig `mapstructure:"incident_feed"`
+
+	// Provider Status Configuration
+	// Suspects LLM provider outages and holds investigations while they last
+	ProviderStatus ProviderStatusConfig `mapstructure:"provider_status"`
+
+	// Search Export Configuration
+	// Indexes completed incidents into OpenSearch or Elasticsearch
+	SearchExport SearchExportConfig `mapstructure:"search_export"`
+
+	// Severity Policy Configuration
+	// Computes incident severity from fault metadata and cluster signals
+	SeverityPolicy SeverityPolicyConfig `mapstructure:"severity_policy"`
+
+	// MCP Enrichment Configuration
+	// Records recent events and pod logs, read through the MCP server, on the
+	// incidents of clusters without kubeconfig triage
+	MCPEnrichment MCPEnrichmentConfig `mapstructure:"mcp_enrichment"`
+
+	// Fallback Triage Configuration
+	// Produces a rule-based report when no agent can run (no usable API key,
+	// budget exhausted, or circuit breaker open)
+	FallbackTriage FallbackTriageConfig `mapstructure:"fallback_triage"`
+
+	// Fault Handlers Configuration
+	// Routes specific fault types to specialized handlers (subprocess hooks or Go
+	// plugins) before or instead of the agent
+	FaultHandlers FaultHandlersConfig `mapstructure:"fault_handlers"`
+
+	// Supply Chain Configuration
+	// Verifies cosign signatures and provenance of the agent image and skill bundles
+	SupplyChain SupplyChainConfig `mapstructure:"supply_chain"`
+
+	// Agent Pinning Configuration
+	// Pins the agent CLI version, image digest, and model and detects drift
+	AgentPinning AgentPinningConfig `mapstructure:"agent_pinning"`
+
+	// URL Shortener Configuration
+	// Shortens report links in notifications and integrations
+	URLShortener URLShortenerConfig `mapstructure:"url_shortener"`
+
+	// Shared Limits Configuration
+	// Enforces dedup windows, launch pacing, and budgets across all processes
+	// sharing the state store
+	SharedLimits SharedLimitsConfig `mapstructure:"shared_limits"`
+
+	// Kubernetes Events Configuration
+	// Records lifecycle moments as Kubernetes Events on the nightcrier pod when in-cluster
+	KubeEvents KubeEventsConfig `mapstructure:"kube_events"`
+
+	// Operator Configuration
+	// Declares clusters and the investigation policy as custom resources and records
+	// incidents as Incident resources
+	Operator OperatorConfig `mapstructure:"operator"`
+
+	// Offline (Air-Gapped) Configuration
+	// Disables all external fetches and rejects settings that need internet egress
+	Offline OfflineConfig `mapstructure:"offline"`
+
+	// Proxy Configuration
+	// Configures HTTP proxies for outbound connections (MCP, Slack, Azure, LLM)
+	Proxy ProxyConfig `mapstructure:"proxy"`
+
+	// Network Configuration
+	// Configures how MCP endpoints are dialed (IP family, Happy Eyeballs, DNS)
+	Network NetworkConfig `mapstructure:"network"`
+
+	// Budget Configuration
+	// Daily limits on investigations and estimated LLM spend, per cluster
+	Budget BudgetConfig `mapstructure:"budget"`
+
+	// Aggregation Configuration
+	// Folds related fault events into a single broader investigation
+	Aggregation AggregationConfig `mapstructure:"aggregation"`
+}
+
+// AggregationConfig configures fault aggregation modes that reduce noise by
+// launching one broad investigation instead of many narrow ones.
+type AggregationConfig struct {
+	// NodeEnabled holds NotReady/pressure node faults for NodeWindowSeconds and folds
+	// pod faults on the same node into a single node-focused investigation
+	// Default: false
+	// Environment variable: AGGREGATION_NODE_ENABLED
+	NodeEnabled bool `mapstructure:"node_enabled"`
+
+	// NodeWindowSeconds is how long pod faults are collected after a node fault
+	// Default: 60
+	// Environment variable: AGGREGATION_NODE_WINDOW_SECONDS
+	NodeWindowSeconds int `mapstructure:"node_window_seconds"`
+
+	// NodeCoverSeconds is how long after the node investigation starts further pod
+	// faults on the node are suppressed as covered by it
+	// Default: same as node_window_seconds
+	// Environment variable: AGGREGATION_NODE_COVER_SECONDS
+	NodeCoverSeconds int `mapstructure:"node_cover_seconds"`
+
+	// NamespaceMaxFaults is the number of faults per namespace within
+	// NamespaceWindowMinutes that are investigated individually; further faults are
+	// collapsed into a single "namespace degradation" incident. 0 disables collapse.
+	// Default: 0
+	// Environment variable: AGGREGATION_NAMESPACE_MAX_FAULTS
+	NamespaceMaxFaults int `mapstructure:"namespace_max_faults"`
+
+	// NamespaceWindowMinutes is the window for counting namespace faults and for
+	// collecting faults into the degradation incident
+	// Default: 5
+	// Environment variable: AGGREGATION_NAMESPACE_WINDOW_MINUTES
+	NamespaceWindowMinutes int `mapstructure:"namespace_window_minutes"`
+
+	// LowSeverityBatchMinutes collects low-severity faults per cluster for this
+	// many minutes and investigates them together in a single agent run with a
+	// combined context. 0 disables batching.
+	// Default: 0
+	// Environment variable: AGGREGATION_LOW_SEVERITY_BATCH_MINUTES
+	LowSeverityBatchMinutes int `mapstructure:"low_severity_batch_minutes"`
+
+	// LowSeverityMax is the most severe level that is batched (DEBUG, INFO, WARNING,
+	// ERROR); more severe faults are investigated individually
+	// Default: "WARNING"
+	// Environment variable: AGGREGATION_LOW_SEVERITY_MAX
+	LowSeverityMax string `mapstructure:"low_severity_max"`
+}
+
+// Validate checks the aggregation configuration and applies defaults.
+func (a *AggregationConfig) Validate() error {
+	if a.NodeWindowSeconds < 0 {
+		return fmt.Errorf("aggregation.node_window_seconds must be >= 0, got %d", a.NodeWindowSeconds)
+	}
+	if a.NodeCoverSeconds < 0 {
+		return fmt.Errorf("aggregation.node_cover_seconds must be >= 0, got %d", a.NodeCoverSeconds)
+	}
+	if a.NamespaceMaxFaults < 0 {
+		return fmt.Errorf("aggregation.namespace_max_faults must be >= 0, got %d", a.NamespaceMaxFaults)
+	}
+	if a.NamespaceWindowMinutes < 0 {
+		return fmt.Errorf("aggregation.namespace_window_minutes must be >= 0, got %d", a.NamespaceWindowMinutes)
+	}
+	if a.NamespaceWindowMinutes == 0 {
+		a.NamespaceWindowMinutes = 5
+	}
+	if a.LowSeverityBatchMinutes < 0 {
+		return fmt.Errorf("aggregation.low_severity_batch_minutes must be >= 0, got %d", a.LowSeverityBatchMinutes)
+	}
+	if a.LowSeverityMax == "" {
+		a.LowSeverityMax = "WARNING"
+	}
+	switch strings.ToUpper(a.LowSeverityMax) {
+	case "DEBUG", "INFO", "WARNING", "WARN", "ERROR":
+	default:
+		return fmt.Errorf("aggregation.low_severity_max must be DEBUG, INFO, WARNING, or ERROR, got %q", a.LowSeverityMax)
+	}
+	if a.NodeWindowSeconds == 0 {
+		a.NodeWindowSeconds = 60
+	}
+	if a.NodeCoverSeconds == 0 {
+		a.NodeCoverSeconds = a.NodeWindowSeconds
+	}
+	return nil
+}
+
+// BudgetConfig configures daily investigation budgets. The limits apply to each
+// cluster individually; clusters may override them in their own budget section.
+type BudgetConfig struct {
+	// Dir stores daily usage counters and operator overrides
+	// Default: "{workspace_root}/budget"
+	// Environment variable: BUDGET_DIR
+	Dir string `mapstructure:"dir"`
+
+	// Default limits for every cluster (0 = unlimited)
+	// Environment variables: BUDGET_MAX_INVESTIGATIONS_PER_DAY, BUDGET_MAX_SPEND_PER_DAY_USD,
+	// BUDGET_ESTIMATED_COST_PER_INVESTIGATION_USD
+	cluster.BudgetConfig `mapstructure:",squash"`
+}
+
+// BudgetLimits returns the effective budget limits for a cluster, combining the
+// global budget with the cluster's overrides.
+func (c *Config) BudgetLimits(clusterName string) budget.Limits {
+	limits := budget.Limits{
+		MaxInvestigationsPerDay:       c.Budget.MaxInvestigationsPerDay,
+		MaxSpendPerDay:                c.Budget.MaxSpendPerDayUSD,
+		EstimatedCostPerInvestigation: c.Budget.EstimatedCostPerInvestigationUSD,
+	}
+	for _, cl := range c.Clusters {
+		if cl.Name != clusterName {
+			continue
+		}
+		if cl.Budget.MaxInvestigationsPerDay != 0 {
+			limits.MaxInvestigationsPerDay = cl.Budget.MaxInvestigationsPerDay
+		}
+		if cl.Budget.MaxSpendPerDayUSD != 0 {
+			limits.MaxSpendPerDay = cl.Budget.MaxSpendPerDayUSD
+		}
+		if cl.Budget.EstimatedCostPerInvestigationUSD != 0 {
+			limits.EstimatedCostPerInvestigation = cl.Budget.EstimatedCostPerInvestigationUSD
+		}
+	}
+	return limits
+}
+
+// WorkspaceTemplates returns the template directories copied into a new workspace
+// of a cluster: the global template, then the cluster's.
+func (c *Config) WorkspaceTemplates(clusterName string) []string {
+	var templates []string
+	if c.WorkspaceTemplate != "" {
+		templates = append(templates, c.WorkspaceTemplate)
+	}
+	for _, cl := range c.Clusters {
+		if cl.Name == clusterName && cl.WorkspaceTemplate != "" {
+			templates = append(templates, cl.WorkspaceTemplate)
+		}
+	}
+	return templates
+}
+
+// validateWorkspaceTemplates checks that the global and per-cluster workspace
+// templates are directories.
+func (c *Config) validateWorkspaceTemplates() error {
+	check := func(field, dir string) error {
+		if dir == "" {
+			return nil
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s must be a directory, got %s", field, dir)
+		}
+		return nil
+	}
+	if err := check("workspace_template", c.WorkspaceTemplate); err != nil {
+		return err
+	}
+	for _, cl := range c.Clusters {
+		if err := check(fmt.Sprintf("clusters[%s].workspace_template", cl.Name), cl.WorkspaceTemplate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBudgets checks the global and per-cluster budget limits.
+func (c *Config) validateBudgets() error {
+	for _, cl := range c.Clusters {
+		if err := c.BudgetLimits(cl.Name).Validate(); err != nil {
+			return fmt.Errorf("cluster %s: budget.%w", cl.Name, err)
+		}
+	}
+	return nil
+}
+
+// NetworkConfig configures dialing of MCP endpoints. Each reconnect discards pooled
+// keep-alive connections and resolves the endpoint again, so a changed load balancer
+// IP is picked up on the next reconnect.
+type NetworkConfig struct {
+	// IPFamily restricts MCP connections to "ipv4", "ipv6", or "any"
+	// Default: "any"
+	// Environment variable: NETWORK_IP_FAMILY
+	IPFamily string `mapstructure:"ip_family"`
+
+	// HappyEyeballsFallbackDelayMs is the delay before racing the fallback address family
+	// 0 uses the Go default (300ms); -1 disables Happy Eyeballs
+	// Environment variable: NETWORK_HAPPY_EYEBALLS_FALLBACK_DELAY_MS
+	HappyEyeballsFallbackDelayMs int `mapstructure:"happy_eyeballs_fallback_delay_ms"`
+
+	// DNSServer is an optional "host:port" DNS server used instead of the system resolver
+	// Environment variable: NETWORK_DNS_SERVER
+	DNSServer string `mapstructure:"dns_server"`
+
+	// DNSTimeoutSeconds bounds each query to DNSServer
+	// Default: 5
+	// Environment variable: NETWORK_DNS_TIMEOUT_SECONDS
+	DNSTimeoutSeconds int `mapstructure:"dns_timeout_seconds"`
+
+	// ConnectTimeoutSeconds bounds establishing a TCP connection to an MCP server
+	// Default: 30
+	// Environment variable: NETWORK_CONNECT_TIMEOUT_SECONDS
+	ConnectTimeoutSeconds int `mapstructure:"connect_timeout_seconds"`
+}
+
+// DialerConfig converts the network configuration into dialer settings.
+func (n NetworkConfig) DialerConfig() dialer.Config {
+	fallback := time.Duration(n.HappyEyeballsFallbackDelayMs) * time.Millisecond
+	if n.HappyEyeballsFallbackDelayMs < 0 {
+		fallback = -1
+	}
+	return dialer.Config{
+		IPFamily:       strings.ToLower(n.IPFamily),
+		FallbackDelay:  fallback,
+		DNSServer:      n.DNSServer,
+		DNSTimeout:     time.Duration(n.DNSTimeoutSeconds) * time.Second,
+		ConnectTimeout: time.Duration(n.ConnectTimeoutSeconds) * time.Second,
+	}
+}
+
+// Validate checks the network configuration.
+func (n NetworkConfig) Validate() error {
+	if err := n.DialerConfig().Validate(); err != nil {
+		return fmt.Errorf("network: %w", err)
+	}
+	if n.DNSTimeoutSeconds < 0 {
+		return fmt.Errorf("network.dns_timeout_seconds must be >= 0, got %d", n.DNSTimeoutSeconds)
+	}
+	if n.ConnectTimeoutSeconds < 0 {
+		return fmt.Errorf("network.connect_timeout_seconds must be >= 0, got %d", n.ConnectTimeoutSeconds)
+	}
+	return nil
+}
+
+// ProxyConfig configures HTTP proxies for outbound connections.
+// When nothing is configured, the standard HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// environment variables are honored. Per-destination proxies override the global
+// settings; the value "direct" disables proxying for that destination.
+type ProxyConfig struct {
+	// HTTPProxy is the global proxy for plain HTTP requests
+	// Environment variable: PROXY_HTTP_PROXY
+	HTTPProxy string `mapstructure:"http_proxy"`
+
+	// HTTPSProxy is the global proxy for HTTPS requests
+	// Environment variable: PROXY_HTTPS_PROXY
+	HTTPSProxy string `mapstructure:"https_proxy"`
+
+	// NoProxy is a comma-separated list of hosts, domains, and CIDRs that bypass the proxy
+	// Environment variable: PROXY_NO_PROXY
+	NoProxy string `mapstructure:"no_proxy"`
+
+	// MCP is an explicit proxy for MCP server connections
+	// Environment variable: PROXY_MCP
+	MCP string `mapstructure:"mcp"`
+
+	// Slack is an explicit proxy for chat webhook requests (Slack, Discord, Mattermost)
+	// Environment variable: PROXY_SLACK
+	Slack string `mapstructure:"slack"`
+
+	// Azure is an explicit proxy for Azure Blob Storage requests
+	// Environment variable: PROXY_AZURE
+	Azure string `mapstructure:"azure"`
+
+	// LLM is an explicit proxy passed to the agent container for LLM API calls
+	// Environment variable: PROXY_LLM
+	LLM string `mapstructure:"llm"`
+}
+
+// settingsFor builds proxy settings for a destination with the given explicit proxy URL.
+func (p ProxyConfig) settingsFor(explicit string) proxy.Settings {
+	return proxy.Settings{
+		ProxyURL:   explicit,
+		HTTPProxy:  p.HTTPProxy,
+		HTTPSProxy: p.HTTPSProxy,
+		NoProxy:    p.NoProxy,
+	}
+}
+
+// MCPSettings returns the proxy settings for MCP server connections.
+func (p ProxyConfig) MCPSettings() proxy.Settings { return p.settingsFor(p.MCP) }
+
+// SlackSettings returns the proxy settings for chat webhook requests.
+func (p ProxyConfig) SlackSettings() proxy.Settings { return p.settingsFor(p.Slack) }
+
+// AzureSettings returns the proxy settings for Azure Blob Storage requests.
+func (p ProxyConfig) AzureSettings() proxy.Settings { return p.settingsFor(p.Azure) }
+
+// LLMSettings returns the proxy settings passed to the agent for LLM API calls.
+func (p ProxyConfig) LLMSettings() proxy.Settings { return p.settingsFor(p.LLM) }
+
+// Validate checks that every configured proxy URL is well-formed.
+func (p ProxyConfig) Validate() error {
+	destinations := map[string]proxy.Settings{
+		"mcp":   p.MCPSettings(),
+		"slack": p.SlackSettings(),
+		"azure": p.AzureSettings(),
+		"llm":   p.LLMSettings(),
+	}
+	for name, settings := range destinations {
+		if err := settings.Validate(); err != nil {
+			return fmt.Errorf("proxy.%s: %w", name, err)
+		}
+	}
+	return nil
 }
 
 // StateStorage configures persistent state storage for incidents, agent executions, and triage reports.
@@ -138,6 +778,19 @@ type StateStorage struct {
 	// Default: "./migrations"
 	// Environment variable: STATE_STORAGE_MIGRATIONS_PATH
 	MigrationsPath string `mapstructure:"migrations_path"`
+
+	// BackupDir is where "nightcrier db backup" and the automatic pre-migration
+	// backups write their files
+	// Default: "{workspace_root}/backups"
+	// Environment variable: STATE_STORAGE_BACKUP_DIR
+	BackupDir string `mapstructure:"backup_dir"`
+
+	// SkipMigrationBackup disables the backup taken before pending schema
+	// migrations are applied to a database that already holds data. Without it, a
+	// failed backup stops startup before the schema is touched.
+	// Default: false
+	// Environment variable: STATE_STORAGE_SKIP_MIGRATION_BACKUP
+	SkipMigrationBackup bool `mapstructure:"skip_migration_backup"`
 }
 
 // SkillsConfig configures the skills subsystem for the agent.
@@ -155,62 +808,381 @@ type SkillsConfig struct {
 	// Default: false
 	// Environment variable: SKILLS_DISABLE_TRIAGE_PRELOAD
 	DisableTriagePreload bool `mapstructure:"disable_triage_preload"`
+
+	// Manifest is the path to a skills manifest file listing the available skills,
+	// their versions, and which clusters (by label) each is selected for. When set,
+	// it replaces Bundles. See skills.LoadManifest for the format.
+	// Environment variable: SKILLS_MANIFEST
+	Manifest string `mapstructure:"manifest"`
+
+	// Bundles lists the skill bundles downloaded into CacheDir. Each bundle is
+	// fetched from a git repository or a .tar.gz archive and mounted into the
+	// agent container under its name. Config file only.
+	// Default: the k8s4agents git repository
+	Bundles []SkillBundleConfig `mapstructure:"bundles"`
+
+	// UpdateIntervalMinutes is how often cached bundles are refreshed from their
+	// source. Bundles pinned by commit or checksum are only re-fetched when the pin
+	// changes. 0 disables updates; missing bundles are still downloaded at startup.
+	// Default: 0
+	// Environment variable: SKILLS_UPDATE_INTERVAL_MINUTES
+	UpdateIntervalMinutes int `mapstructure:"update_interval_minutes"`
 }
 
-// bindEnvVars binds environment variables to viper keys.
-// Environment variables use uppercase with underscores (e.g., WORKSPACE_ROOT).
-func bindEnvVars() {
-	// Map config keys to environment variable names
-	envBindings := map[string]string{
-		"subscribe_mode":                  "SUBSCRIBE_MODE",
-		"workspace_root":                  "WORKSPACE_ROOT",
-		"log_level":                       "LOG_LEVEL",
-		"slack_webhook_url":               "SLACK_WEBHOOK_URL",
-		"agent_script_path":               "AGENT_SCRIPT_PATH",
-		"agent_system_prompt_file":        "AGENT_SYSTEM_PROMPT_FILE",
-		"agent_allowed_tools":             "AGENT_ALLOWED_TOOLS",
-		"agent_model":                     "AGENT_MODEL",
-		"agent_timeout":                   "AGENT_TIMEOUT",
-		"agent_cli":                       "AGENT_CLI",
-		"agent_image":                     "AGENT_IMAGE",
-		"agent_verbose":                   "AGENT_VERBOSE",
-		"additional_agent_prompt":         "ADDITIONAL_AGENT_PROMPT",
-		"anthropic_api_key":               "ANTHROPIC_API_KEY",
-		"openai_api_key":                  "OPENAI_API_KEY",
-		"gemini_api_key":                  "GEMINI_API_KEY",
-		"kubeconfig_path":                 "KUBECONFIG_PATH",
-		"kubernetes_context":              "KUBERNETES_CONTEXT",
-		"severity_threshold":              "SEVERITY_THRESHOLD",
-		"max_concurrent_agents":           "MAX_CONCURRENT_AGENTS",
-		"global_queue_size":               "GLOBAL_QUEUE_SIZE",
-		"cluster_queue_size":              "CLUSTER_QUEUE_SIZE",
-		"dedup_window_seconds":            "DEDUP_WINDOW_SECONDS",
-		"queue_overflow_policy":           "QUEUE_OVERFLOW_POLICY",
-		"shutdown_timeout":                "SHUTDOWN_TIMEOUT_SECONDS",
-		"sse_reconnect_initial_backoff":   "SSE_RECONNECT_INITIAL_BACKOFF",
-		"sse_reconnect_max_backoff":       "SSE_RECONNECT_MAX_BACKOFF",
-		"sse_read_timeout":                "SSE_READ_TIMEOUT_SECONDS",
-		"azure_storage_connection_string": "AZURE_STORAGE_CONNECTION_STRING",
-		"azure_storage_account":           "AZURE_STORAGE_ACCOUNT",
-		"azure_storage_key":               "AZURE_STORAGE_KEY",
-		"azure_storage_container":         "AZURE_STORAGE_CONTAINER",
-		"azure_sas_expiry":                "AZURE_SAS_EXPIRY",
-		"notify_on_agent_failure":         "NOTIFY_ON_AGENT_FAILURE",
-		"failure_threshold_for_alert":     "FAILURE_THRESHOLD_FOR_ALERT",
-		"upload_failed_investigations":    "UPLOAD_FAILED_INVESTIGATIONS",
-		"state_storage.type":                                "STATE_STORAGE_TYPE",
-		"state_storage.sqlite_path":                         "STATE_STORAGE_SQLITE_PATH",
-		"state_storage.postgres_connection_string":          "STATE_STORAGE_POSTGRES_CONNECTION_STRING",
-		"state_storage.postgres_host":                       "STATE_STORAGE_POSTGRES_HOST",
-		"state_storage.postgres_port":                       "STATE_STORAGE_POSTGRES_PORT",
-		"state_storage.postgres_database":                   "STATE_STORAGE_POSTGRES_DATABASE",
-		"state_storage.postgres_user":                       "STATE_STORAGE_POSTGRES_USER",
-		"state_storage.postgres_password":                   "STATE_STORAGE_POSTGRES_PASSWORD",
-		"state_storage.migrations_path":                     "STATE_STORAGE_MIGRATIONS_PATH",
-		"skills.cache_dir":                                  "SKILLS_CACHE_DIR",
-		"skills.disable_triage_preload":                     "SKILLS_DISABLE_TRIAGE_PRELOAD",
+// SkillBundleConfig configures one skill bundle. Set either Git or URL.
+type SkillBundleConfig struct {
+	// Name is the bundle's directory under the skills cache directory
+	Name string `mapstructure:"name"`
+
+	// Git is the repository to clone; Ref optionally selects a branch, tag, or
+	// full commit hash (a commit hash pins and verifies the checkout)
+	Git string `mapstructure:"git"`
+	Ref string `mapstructure:"ref"`
+
+	// URL is a .tar.gz archive to download; SHA256 is its expected checksum
+	URL    string `mapstructure:"url"`
+	SHA256 string `mapstructure:"sha256"`
+
+	// Signature is the archive's cosign signature bundle, checked when
+	// supply_chain.policy is set (default: URL + ".sigstore.json")
+	Signature string `mapstructure:"signature"`
+}
+
+// SkillBundles returns the configured skill bundles, or the default k8s4agents
+// bundle when none are configured.
+func (s SkillsConfig) SkillBundles() []skills.Bundle {
+	if len(s.Bundles) == 0 {
+		return []skills.Bundle{skills.DefaultBundle()}
+	}
+	bundles := make([]skills.Bundle, 0, len(s.Bundles))
+	for _, b := range s.Bundles {
+		bundles = append(bundles, skills.Bundle{
+			Name:         b.Name,
+			GitURL:       b.Git,
+			Ref:          b.Ref,
+			TarballURL:   b.URL,
+			SHA256:       b.SHA256,
+			SignatureURL: b.Signature,
+		})
+	}
+	return bundles
+}
+
+// LoadManifest returns the skills manifest: the manifest file when one is
+// configured, otherwise the configured bundles available to every cluster.
+func (s SkillsConfig) LoadManifest() (*skills.Manifest, error) {
+	if s.Manifest != "" {
+		return skills.LoadManifest(s.Manifest)
+	}
+	m := skills.NewManifest(s.SkillBundles())
+	if err := m.Validate(); err != nil {
+		return nil, err
 	}
+	return m, nil
+}
 
+// Validate checks the skills manifest or bundles and the update interval.
+func (s SkillsConfig) Validate() error {
+	if s.UpdateIntervalMinutes < 0 {
+		return fmt.Errorf("skills.update_interval_minutes must be >= 0, got %d", s.UpdateIntervalMinutes)
+	}
+	if s.Manifest != "" && len(s.Bundles) > 0 {
+		return fmt.Errorf("skills.manifest and skills.bundles cannot both be set")
+	}
+	m, err := s.LoadManifest()
+	if err != nil {
+		return err
+	}
+	for _, b := range m.Bundles() {
+		if b.TarballURL != "" {
+			if err := validateHTTPURL("skill bundle url", b.TarballURL); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// envBindings maps config keys to the environment variables that set them. It is
+// the authoritative list of supported environment variables (see EnvVars).
+// Environment variables use uppercase with underscores (e.g., WORKSPACE_ROOT).
+var envBindings = map[string]string{
+	"profile":                         "CONFIG_PROFILE",
+	"strict_config":                   "STRICT_CONFIG",
+	"subscribe_mode":                  "SUBSCRIBE_MODE",
+	"mcp_endpoint":                    "K8S_CLUSTER_MCP_ENDPOINT",
+	"single_cluster":                  "SINGLE_CLUSTER",
+	"single_cluster_name":             "SINGLE_CLUSTER_NAME",
+	"workspace_root":                  "WORKSPACE_ROOT",
+	"workspace_scratch_dir":           "WORKSPACE_SCRATCH_DIR",
+	"workspace_template":              "WORKSPACE_TEMPLATE",
+	"quarantine_dir":                  "QUARANTINE_DIR",
+	"pause_file":                      "PAUSE_FILE",
+	"log_level":                       "LOG_LEVEL",
+	"slack_webhook_url":               "SLACK_WEBHOOK_URL",
+	"discord_webhook_url":             "DISCORD_WEBHOOK_URL",
+	"mattermost_webhook_url":          "MATTERMOST_WEBHOOK_URL",
+	"mattermost_channel":              "MATTERMOST_CHANNEL",
+	"mattermost_username":             "MATTERMOST_USERNAME",
+	"agent_script_path":               "AGENT_SCRIPT_PATH",
+	"agent_system_prompt_file":        "AGENT_SYSTEM_PROMPT_FILE",
+	"agent_allowed_tools":             "AGENT_ALLOWED_TOOLS",
+	"agent_model":                     "AGENT_MODEL",
+	"agent_timeout":                   "AGENT_TIMEOUT",
+	"agent_cli":                       "AGENT_CLI",
+	"agent_image":                     "AGENT_IMAGE",
+	"agent_verbose":                   "AGENT_VERBOSE",
+	"agent_stream_progress":           "AGENT_STREAM_PROGRESS",
+	"additional_agent_prompt":         "ADDITIONAL_AGENT_PROMPT",
+	"anthropic_api_key":               "ANTHROPIC_API_KEY",
+	"openai_api_key":                  "OPENAI_API_KEY",
+	"gemini_api_key":                  "GEMINI_API_KEY",
+	"kubeconfig_path":                 "KUBECONFIG_PATH",
+	"kubernetes_context":              "KUBERNETES_CONTEXT",
+	"severity_threshold":              "SEVERITY_THRESHOLD",
+	"max_concurrent_agents":           "MAX_CONCURRENT_AGENTS",
+	"reserved_agent_slots":            "RESERVED_AGENT_SLOTS",
+	"reserved_severity":               "RESERVED_SEVERITY",
+	"global_queue_size":               "GLOBAL_QUEUE_SIZE",
+	"cluster_queue_size":              "CLUSTER_QUEUE_SIZE",
+	"dedup_window_seconds":            "DEDUP_WINDOW_SECONDS",
+	"investigation_cache_ttl_seconds": "INVESTIGATION_CACHE_TTL_SECONDS",
+	"queue_alert_threshold_percent":   "QUEUE_ALERT_THRESHOLD_PERCENT",
+	"alert_on_dropped_events":         "ALERT_ON_DROPPED_EVENTS",
+	"queue_overflow_policy":           "QUEUE_OVERFLOW_POLICY",
+	"queue_spill_dir":                 "QUEUE_SPILL_DIR",
+	"queue_spill_max_events":          "QUEUE_SPILL_MAX_EVENTS",
+	"shutdown_timeout":                "SHUTDOWN_TIMEOUT_SECONDS",
+	"sse_reconnect_initial_backoff":   "SSE_RECONNECT_INITIAL_BACKOFF",
+	"sse_reconnect_max_backoff":       "SSE_RECONNECT_MAX_BACKOFF",
+	"sse_read_timeout":                "SSE_READ_TIMEOUT_SECONDS",
+	"cluster_disconnect_alert_seconds": "CLUSTER_DISCONNECT_ALERT_SECONDS",
+	"cluster_startup.fast_start":                 "CLUSTER_STARTUP_FAST_START",
+	"cluster_startup.connect":                    "CLUSTER_STARTUP_CONNECT",
+	"cluster_startup.validation_timeout_seconds": "CLUSTER_STARTUP_VALIDATION_TIMEOUT_SECONDS",
+	"cluster_startup.validation_concurrency":     "CLUSTER_STARTUP_VALIDATION_CONCURRENCY",
+	"cluster_startup.require_all_clusters":       "CLUSTER_STARTUP_REQUIRE_ALL_CLUSTERS",
+	"credential_expiry.warn_days":                "CREDENTIAL_EXPIRY_WARN_DAYS",
+	"credential_expiry.check_interval_minutes":   "CREDENTIAL_EXPIRY_CHECK_INTERVAL_MINUTES",
+	"azure_storage_connection_string": "AZURE_STORAGE_CONNECTION_STRING",
+	"azure_storage_account":           "AZURE_STORAGE_ACCOUNT",
+	"azure_storage_key":               "AZURE_STORAGE_KEY",
+	"azure_storage_container":         "AZURE_STORAGE_CONTAINER",
+	"azure_sas_expiry":                "AZURE_SAS_EXPIRY",
+	"notify_on_agent_failure":         "NOTIFY_ON_AGENT_FAILURE",
+	"failure_threshold_for_alert":     "FAILURE_THRESHOLD_FOR_ALERT",
+	"upload_failed_investigations":    "UPLOAD_FAILED_INVESTIGATIONS",
+	"state_storage.type":                                "STATE_STORAGE_TYPE",
+	"state_storage.sqlite_path":                         "STATE_STORAGE_SQLITE_PATH",
+	"state_storage.postgres_connection_string":          "STATE_STORAGE_POSTGRES_CONNECTION_STRING",
+	"state_storage.postgres_host":                       "STATE_STORAGE_POSTGRES_HOST",
+	"state_storage.postgres_port":                       "STATE_STORAGE_POSTGRES_PORT",
+	"state_storage.postgres_database":                   "STATE_STORAGE_POSTGRES_DATABASE",
+	"state_storage.postgres_user":                       "STATE_STORAGE_POSTGRES_USER",
+	"state_storage.postgres_password":                   "STATE_STORAGE_POSTGRES_PASSWORD",
+	"state_storage.migrations_path":                     "STATE_STORAGE_MIGRATIONS_PATH",
+	"state_storage.backup_dir":                          "STATE_STORAGE_BACKUP_DIR",
+	"state_storage.skip_migration_backup":               "STATE_STORAGE_SKIP_MIGRATION_BACKUP",
+	"incident_lifecycle.review_confidence":              "INCIDENT_LIFECYCLE_REVIEW_CONFIDENCE",
+	"incident_lifecycle.review_contradicted_claims":     "INCIDENT_LIFECYCLE_REVIEW_CONTRADICTED_CLAIMS",
+	"incident_lifecycle.expire_after_hours":             "INCIDENT_LIFECYCLE_EXPIRE_AFTER_HOURS",
+	"skills.cache_dir":                                  "SKILLS_CACHE_DIR",
+	"skills.disable_triage_preload":                     "SKILLS_DISABLE_TRIAGE_PRELOAD",
+	"skills.update_interval_minutes":                    "SKILLS_UPDATE_INTERVAL_MINUTES",
+	"skills.manifest":                                   "SKILLS_MANIFEST",
+	"runbooks.dir":                                      "RUNBOOKS_DIR",
+	"runbooks.git":                                      "RUNBOOKS_GIT",
+	"runbooks.ref":                                      "RUNBOOKS_GIT_REF",
+	"runbooks.cache_dir":                                "RUNBOOKS_CACHE_DIR",
+	"runbooks.update_interval_minutes":                  "RUNBOOKS_UPDATE_INTERVAL_MINUTES",
+	"postmortem.provider":                               "POSTMORTEM_PROVIDER",
+	"postmortem.api_url":                                "POSTMORTEM_API_URL",
+	"postmortem.repo":                                   "POSTMORTEM_REPO",
+	"postmortem.token":                                  "POSTMORTEM_GIT_TOKEN",
+	"postmortem.base_branch":                            "POSTMORTEM_BASE_BRANCH",
+	"postmortem.path":                                   "POSTMORTEM_PATH",
+	"postmortem.branch_prefix":                          "POSTMORTEM_BRANCH_PREFIX",
+	"postmortem.direct_commit":                          "POSTMORTEM_DIRECT_COMMIT",
+	"knowledge_base.confluence.base_url":                "CONFLUENCE_BASE_URL",
+	"knowledge_base.confluence.username":                "CONFLUENCE_USERNAME",
+	"knowledge_base.confluence.api_token":               "CONFLUENCE_API_TOKEN",
+	"knowledge_base.confluence.space_key":               "CONFLUENCE_SPACE_KEY",
+	"knowledge_base.confluence.parent_page_id":          "CONFLUENCE_PARENT_PAGE_ID",
+	"knowledge_base.confluence.min_severity":            "CONFLUENCE_MIN_SEVERITY",
+	"knowledge_base.notion.token":                       "NOTION_TOKEN",
+	"knowledge_base.notion.database_id":                 "NOTION_DATABASE_ID",
+	"knowledge_base.notion.min_severity":                "NOTION_MIN_SEVERITY",
+	"servicenow.instance_url":                           "SERVICENOW_INSTANCE_URL",
+	"servicenow.username":                               "SERVICENOW_USERNAME",
+	"servicenow.password":                               "SERVICENOW_PASSWORD",
+	"servicenow.table":                                  "SERVICENOW_TABLE",
+	"servicenow.assignment_group":                       "SERVICENOW_ASSIGNMENT_GROUP",
+	"servicenow.caller_id":                              "SERVICENOW_CALLER_ID",
+	"servicenow.category":                               "SERVICENOW_CATEGORY",
+	"servicenow.min_severity":                           "SERVICENOW_MIN_SEVERITY",
+	"lifecycle_webhooks.timeout_seconds":                "LIFECYCLE_WEBHOOKS_TIMEOUT_SECONDS",
+	"ownership.annotations":                             "OWNERSHIP_ANNOTATIONS",
+	"ownership.backstage.url":                           "OWNERSHIP_BACKSTAGE_URL",
+	"ownership.backstage.token":                         "OWNERSHIP_BACKSTAGE_TOKEN",
+	"ownership.cmdb.url":                                "OWNERSHIP_CMDB_URL",
+	"ownership.cmdb.token":                              "OWNERSHIP_CMDB_TOKEN",
+	"ownership.cache_minutes":                           "OWNERSHIP_CACHE_MINUTES",
+	"slack_app.app_token":                               "SLACK_APP_TOKEN",
+	"slack_app.bot_token":                               "SLACK_BOT_TOKEN",
+	"slack_app.recent_hours":                            "SLACK_APP_RECENT_HOURS",
+	"health_server.bind_address":                        "HEALTH_BIND_ADDRESS",
+	"health_server.tls_cert_file":                       "HEALTH_TLS_CERT_FILE",
+	"health_server.tls_key_file":                        "HEALTH_TLS_KEY_FILE",
+	"health_server.client_ca_file":                      "HEALTH_CLIENT_CA_FILE",
+	"health_server.auth_token":                          "HEALTH_AUTH_TOKEN",
+	"canary.interval_minutes":                           "CANARY_INTERVAL_MINUTES",
+	"canary.notify_success":                             "CANARY_NOTIFY_SUCCESS",
+	"canary.namespace":                                  "CANARY_NAMESPACE",
+	"canary.resource_kind":                              "CANARY_RESOURCE_KIND",
+	"canary.resource_name":                              "CANARY_RESOURCE_NAME",
+	"incident_labels.from_cluster":                      "INCIDENT_LABELS_FROM_CLUSTER",
+	"incident_labels.from_agent":                        "INCIDENT_LABELS_FROM_AGENT",
+	"agent_hardening.enabled":                           "AGENT_HARDENING",
+	"agent_hardening.user":                              "AGENT_HARDENING_USER",
+	"agent_hardening.allow_writable_root":               "AGENT_HARDENING_ALLOW_WRITABLE_ROOT",
+	"agent_hardening.tmpfs_paths":                       "AGENT_HARDENING_TMPFS_PATHS",
+	"agent_egress.enabled":                              "AGENT_EGRESS_ENABLED",
+	"agent_egress.proxy_url":                            "AGENT_EGRESS_PROXY_URL",
+	"agent_egress.network":                              "AGENT_EGRESS_NETWORK",
+	"agent_egress.allowlist_file":                       "AGENT_EGRESS_ALLOWLIST_FILE",
+	"agent_debug_bundle.tail_kb":                        "AGENT_DEBUG_BUNDLE_TAIL_KB",
+	"agent_debug_bundle.environment":                    "AGENT_DEBUG_BUNDLE_ENVIRONMENT",
+	"notification_coalescing.enabled":                   "NOTIFICATION_COALESCING_ENABLED",
+	"notification_coalescing.window_seconds":            "NOTIFICATION_COALESCING_WINDOW_SECONDS",
+	"verification.enabled":                              "VERIFICATION_ENABLED",
+	"verification.check_registries":                     "VERIFICATION_CHECK_REGISTRIES",
+	"verification.timeout_seconds":                      "VERIFICATION_TIMEOUT_SECONDS",
+	"topology.enabled":                                  "TOPOLOGY_ENABLED",
+	"topology.max_pods":                                 "TOPOLOGY_MAX_PODS",
+	"topology.timeout_seconds":                          "TOPOLOGY_TIMEOUT_SECONDS",
+	"noise_analysis.enabled":                            "NOISE_ANALYSIS_ENABLED",
+	"noise_analysis.interval_hours":                     "NOISE_ANALYSIS_INTERVAL_HOURS",
+	"noise_analysis.window_days":                        "NOISE_ANALYSIS_WINDOW_DAYS",
+	"noise_analysis.min_incidents":                      "NOISE_ANALYSIS_MIN_INCIDENTS",
+	"noise_analysis.max_suggestions":                    "NOISE_ANALYSIS_MAX_SUGGESTIONS",
+	"sqlite_maintenance.enabled":                        "SQLITE_MAINTENANCE_ENABLED",
+	"sqlite_maintenance.interval_minutes":               "SQLITE_MAINTENANCE_INTERVAL_MINUTES",
+	"sqlite_maintenance.vacuum_pages":                   "SQLITE_MAINTENANCE_VACUUM_PAGES",
+	"sqlite_maintenance.alert_size_mb":                  "SQLITE_MAINTENANCE_ALERT_SIZE_MB",
+	"sqlite_maintenance.alert_wal_mb":                   "SQLITE_MAINTENANCE_ALERT_WAL_MB",
+	"incident_feed.enabled":                             "INCIDENT_FEED_ENABLED",
+	"incident_feed.mode":                                "INCIDENT_FEED_MODE",
+	"incident_feed.poll_interval_seconds":               "INCIDENT_FEED_POLL_INTERVAL_SECONDS",
+	"provider_status.enabled":                           "PROVIDER_STATUS_ENABLED",
+	"provider_status.interval_seconds":                  "PROVIDER_STATUS_INTERVAL_SECONDS",
+	"provider_status.indicator":                         "PROVIDER_STATUS_INDICATOR",
+	"provider_status.failure_threshold":                 "PROVIDER_STATUS_FAILURE_THRESHOLD",
+	"provider_status.failure_window_minutes":            "PROVIDER_STATUS_FAILURE_WINDOW_MINUTES",
+	"provider_status.keep_launching":                    "PROVIDER_STATUS_KEEP_LAUNCHING",
+	"search_export.url":                                 "SEARCH_EXPORT_URL",
+	"search_export.api_key":                             "SEARCH_EXPORT_API_KEY",
+	"search_export.username":                            "SEARCH_EXPORT_USERNAME",
+	"search_export.password":                            "SEARCH_EXPORT_PASSWORD",
+	"search_export.index_prefix":                        "SEARCH_EXPORT_INDEX_PREFIX",
+	"search_export.exclude_report":                      "SEARCH_EXPORT_EXCLUDE_REPORT",
+	"severity_policy.timeout_seconds":                   "SEVERITY_POLICY_TIMEOUT_SECONDS",
+	"shared_limits.enabled":                             "SHARED_LIMITS_ENABLED",
+	"url_shortener.url":                                 "URL_SHORTENER_URL",
+	"url_shortener.method":                              "URL_SHORTENER_METHOD",
+	"url_shortener.token":                               "URL_SHORTENER_TOKEN",
+	"url_shortener.body_template":                       "URL_SHORTENER_BODY_TEMPLATE",
+	"url_shortener.response_field":                      "URL_SHORTENER_RESPONSE_FIELD",
+	"url_shortener.timeout_seconds":                     "URL_SHORTENER_TIMEOUT_SECONDS",
+	"adaptive_dedup.enabled":                            "ADAPTIVE_DEDUP_ENABLED",
+	"adaptive_dedup.min_window_seconds":                 "ADAPTIVE_DEDUP_MIN_WINDOW_SECONDS",
+	"adaptive_dedup.max_window_seconds":                 "ADAPTIVE_DEDUP_MAX_WINDOW_SECONDS",
+	"adaptive_dedup.growth_factor":                      "ADAPTIVE_DEDUP_GROWTH_FACTOR",
+	"sampling.reset_after_hours":                        "SAMPLING_RESET_AFTER_HOURS",
+	"incident_ids.scheme":                               "INCIDENT_ID_SCHEME",
+	"incident_ids.prefix":                               "INCIDENT_ID_PREFIX",
+	"report_rendering.renderer":                         "REPORT_RENDERER",
+	"follow_up.enabled":                                 "FOLLOW_UP_ENABLED",
+	"follow_up.lookback_hours":                          "FOLLOW_UP_LOOKBACK_HOURS",
+	"artifact_retention.enabled":                        "ARTIFACT_RETENTION_ENABLED",
+	"artifact_retention.report_days":                    "ARTIFACT_RETENTION_REPORT_DAYS",
+	"artifact_retention.logs_days":                      "ARTIFACT_RETENTION_LOGS_DAYS",
+	"artifact_retention.session_archive_days":           "ARTIFACT_RETENTION_SESSION_ARCHIVE_DAYS",
+	"artifact_retention.interval_minutes":               "ARTIFACT_RETENTION_INTERVAL_MINUTES",
+	"artifact_retention.azure_index_tags":               "ARTIFACT_RETENTION_AZURE_INDEX_TAGS",
+	"mcp_enrichment.enabled":                            "MCP_ENRICHMENT_ENABLED",
+	"mcp_enrichment.max_events":                         "MCP_ENRICHMENT_MAX_EVENTS",
+	"mcp_enrichment.log_lines":                          "MCP_ENRICHMENT_LOG_LINES",
+	"mcp_enrichment.timeout_seconds":                    "MCP_ENRICHMENT_TIMEOUT_SECONDS",
+	"fallback_triage.enabled":                           "FALLBACK_TRIAGE_ENABLED",
+	"fallback_triage.triggers":                          "FALLBACK_TRIAGE_TRIGGERS",
+	"fallback_triage.circuit_probe_seconds":             "FALLBACK_TRIAGE_CIRCUIT_PROBE_SECONDS",
+	"supply_chain.policy":                               "SUPPLY_CHAIN_POLICY",
+	"supply_chain.cosign_path":                          "SUPPLY_CHAIN_COSIGN_PATH",
+	"supply_chain.key":                                  "SUPPLY_CHAIN_KEY",
+	"supply_chain.certificate_identity_regexp":          "SUPPLY_CHAIN_CERTIFICATE_IDENTITY_REGEXP",
+	"supply_chain.certificate_oidc_issuer":              "SUPPLY_CHAIN_CERTIFICATE_OIDC_ISSUER",
+	"supply_chain.verify_provenance":                    "SUPPLY_CHAIN_VERIFY_PROVENANCE",
+	"supply_chain.audit_log":                            "SUPPLY_CHAIN_AUDIT_LOG",
+	"agent_pinning.cli_version":                         "AGENT_PINNING_CLI_VERSION",
+	"agent_pinning.image_digest":                        "AGENT_PINNING_IMAGE_DIGEST",
+	"agent_pinning.model":                               "AGENT_PINNING_MODEL",
+	"agent_pinning.policy":                              "AGENT_PINNING_POLICY",
+	"kube_events.enabled":                               "KUBE_EVENTS_ENABLED",
+	"kube_events.namespace":                             "KUBE_EVENTS_NAMESPACE",
+	"kube_events.pod_name":                              "KUBE_EVENTS_POD_NAME",
+	"kube_events.resource_name":                         "KUBE_EVENTS_RESOURCE_NAME",
+	"operator.enabled":                                  "OPERATOR_ENABLED",
+	"operator.namespace":                                "OPERATOR_NAMESPACE",
+	"operator.policy_name":                              "OPERATOR_POLICY_NAME",
+	"operator.resync_seconds":                           "OPERATOR_RESYNC_SECONDS",
+	"operator.disable_incident_resources":              "OPERATOR_DISABLE_INCIDENT_RESOURCES",
+	"offline.enabled":                                   "OFFLINE_MODE",
+	"offline.internal_domains":                          "OFFLINE_INTERNAL_DOMAINS",
+	"proxy.http_proxy":                                  "PROXY_HTTP_PROXY",
+	"proxy.https_proxy":                                 "PROXY_HTTPS_PROXY",
+	"proxy.no_proxy":                                    "PROXY_NO_PROXY",
+	"proxy.mcp":                                         "PROXY_MCP",
+	"proxy.slack":                                       "PROXY_SLACK",
+	"proxy.azure":                                       "PROXY_AZURE",
+	"proxy.llm":                                         "PROXY_LLM",
+	"network.ip_family":                                 "NETWORK_IP_FAMILY",
+	"network.happy_eyeballs_fallback_delay_ms":          "NETWORK_HAPPY_EYEBALLS_FALLBACK_DELAY_MS",
+	"network.dns_server":                                "NETWORK_DNS_SERVER",
+	"network.dns_timeout_seconds":                       "NETWORK_DNS_TIMEOUT_SECONDS",
+	"network.connect_timeout_seconds":                   "NETWORK_CONNECT_TIMEOUT_SECONDS",
+	"budget.dir":                                        "BUDGET_DIR",
+	"budget.max_investigations_per_day":                 "BUDGET_MAX_INVESTIGATIONS_PER_DAY",
+	"budget.max_spend_per_day_usd":                      "BUDGET_MAX_SPEND_PER_DAY_USD",
+	"budget.estimated_cost_per_investigation_usd":       "BUDGET_ESTIMATED_COST_PER_INVESTIGATION_USD",
+	"aggregation.node_enabled":                          "AGGREGATION_NODE_ENABLED",
+	"aggregation.node_window_seconds":                   "AGGREGATION_NODE_WINDOW_SECONDS",
+	"aggregation.node_cover_seconds":                    "AGGREGATION_NODE_COVER_SECONDS",
+	"aggregation.namespace_max_faults":                  "AGGREGATION_NAMESPACE_MAX_FAULTS",
+	"aggregation.namespace_window_minutes":              "AGGREGATION_NAMESPACE_WINDOW_MINUTES",
+	"aggregation.low_severity_batch_minutes":            "AGGREGATION_LOW_SEVERITY_BATCH_MINUTES",
+	"aggregation.low_severity_max":                      "AGGREGATION_LOW_SEVERITY_MAX",
+	"report_language":                                   "REPORT_LANGUAGE",
+	"anthropic_api_keys":                                "ANTHROPIC_API_KEYS",
+	"openai_api_keys":                                   "OPENAI_API_KEYS",
+	"gemini_api_keys":                                   "GEMINI_API_KEYS",
+	"api_key_cooldown_seconds":                          "API_KEY_COOLDOWN_SECONDS",
+	"llm_endpoint.base_url":                             "LLM_ENDPOINT_BASE_URL",
+	"llm_endpoint.model":                                "LLM_ENDPOINT_MODEL",
+	"llm_endpoint.api_key":                              "LLM_ENDPOINT_API_KEY",
+	"azure_openai.endpoint":                             "AZURE_OPENAI_ENDPOINT",
+	"azure_openai.deployment":                           "AZURE_OPENAI_DEPLOYMENT",
+	"azure_openai.api_key":                              "AZURE_OPENAI_API_KEY",
+	"azure_openai.api_version":                          "AZURE_OPENAI_API_VERSION",
+	"bedrock.region":                                    "BEDROCK_REGION",
+	"bedrock.access_key_id":                             "BEDROCK_ACCESS_KEY_ID",
+	"bedrock.secret_access_key":                         "BEDROCK_SECRET_ACCESS_KEY",
+	"bedrock.session_token":                             "BEDROCK_SESSION_TOKEN",
+	"bedrock.role_arn":                                  "BEDROCK_ROLE_ARN",
+	"bedrock.web_identity_token_file":                   "BEDROCK_WEB_IDENTITY_TOKEN_FILE",
+}
+
+// bindEnvVars binds environment variables to viper keys.
+func bindEnvVars() {
 	for key, envVar := range envBindings {
 		_ = viper.BindEnv(key, envVar)
 	}
@@ -222,8 +1194,12 @@ func BindFlags(flags *pflag.FlagSet) {
 	// Bind flags that match config keys
 	flagBindings := map[string]string{
 		"workspace-root":                "workspace_root",
+		"mcp-endpoint":                  "mcp_endpoint",
+		"single-cluster":                "single_cluster",
 		"log-level":                     "log_level",
 		"config":                        "config_file",
+		"profile":                       "profile",
+		"strict-config":                 "strict_config",
 		"agent-timeout":                 "agent_timeout",
 		"severity-threshold":            "severity_threshold",
 		"max-concurrent-agents":         "max_concurrent_agents",
@@ -243,7 +1219,8 @@ func BindFlags(flags *pflag.FlagSet) {
 // Load creates a Config by loading values with the following precedence:
 // 1. Command-line flags (highest priority)
 // 2. Environment variables
-// 3. Configuration file (lowest priority)
+// 3. Configuration file, with its profile and cluster overlays
+// 4. Managed defaults (lowest priority; not applied with strict_config)
 // All required fields must be provided through one of these sources.
 func Load() (*Config, error) {
 	return LoadWithConfigFile("")
@@ -275,11 +1252,30 @@ func LoadWithConfigFile(configFile string) (*Config, error) {
 		}
 	}
 
+	// Merge the profile and cluster overlays over the base config file
+	layers, err := mergeOverlays(viper.GetString("profile"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill settings with obvious defaults, unless strict_config requires them
+	applyManagedDefaults()
+
 	// Unmarshal into Config struct
 	var cfg Config
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	cfg.ConfigLayers = layers
+
+	// In operator mode clusters are declared as ClusterTarget resources, loaded
+	// after the configuration (see internal/operator)
+	if cfg.Operator.Enabled {
+		cfg.Clusters = nil
+	} else if err := cfg.applySingleClusterMode(); err != nil {
+		// Synthesize a cluster from mcp_endpoint for single-cluster deployments
+		return nil, err
+	}
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
@@ -289,6 +1285,48 @@ func LoadWithConfigFile(configFile string) (*Config, error) {
 	return &cfg, nil
 }
 
+// DefaultSingleClusterName is the cluster name used in single-cluster mode
+// when single_cluster_name is not configured.
+const DefaultSingleClusterName = "default"
+
+// applySingleClusterMode folds the legacy single-endpoint configuration into the
+// multi-cluster model. Single-cluster mode is active when single_cluster is true,
+// or implicitly when mcp_endpoint is set and no clusters array is configured.
+// In that mode the clusters array is replaced with one cluster whose triage
+// kubeconfig is kubeconfig_path (triage is disabled when no kubeconfig is set).
+// Running through the same orchestrator means fixes never need to land twice.
+func (c *Config) applySingleClusterMode() error {
+	if !c.SingleCluster && (c.MCPEndpoint == "" || len(c.Clusters) > 0) {
+		return nil
+	}
+
+	if c.MCPEndpoint == "" {
+		return fmt.Errorf("single_cluster mode requires mcp_endpoint (environment variable: K8S_CLUSTER_MCP_ENDPOINT, flag: --mcp-endpoint)")
+	}
+
+	name := c.SingleClusterName
+	if name == "" {
+		name = DefaultSingleClusterName
+	}
+
+	c.Clusters = []cluster.ClusterConfig{
+		{
+			Name: name,
+			MCP: cluster.MCPConfig{
+				Endpoint: c.MCPEndpoint,
+			},
+			Triage: cluster.TriageConfig{
+				Enabled:    c.KubeconfigPath != "",
+				Kubeconfig: c.KubeconfigPath,
+			},
+		},
+	}
+	c.SingleCluster = true
+	c.SingleClusterName = name
+
+	return nil
+}
+
 // Validate checks the configuration for required fields and valid values.
 func (c *Config) Validate() error {
 	// Helper function to format missing field errors
@@ -296,8 +1334,8 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("required field %q is missing (environment variable: %s). Please set it via environment variable, config file, or command-line flag. See configs/config.example.yaml for details", fieldName, envVar)
 	}
 
-	// Required: Clusters
-	if len(c.Clusters) == 0 {
+	// Required: Clusters (in operator mode, once loaded from ClusterTarget resources)
+	if len(c.Clusters) == 0 && !c.Operator.Enabled {
 		return fmt.Errorf("at least one cluster must be configured in the 'clusters' array")
 	}
 
@@ -328,6 +1366,34 @@ func (c *Config) Validate() error {
 		return missingFieldError("workspace_root", "WORKSPACE_ROOT")
 	}
 
+	if c.WorkspaceScratchDir != "" && filepath.Clean(c.WorkspaceScratchDir) == filepath.Clean(c.WorkspaceRoot) {
+		return fmt.Errorf("workspace_scratch_dir must differ from workspace_root, got %s. Set via WORKSPACE_SCRATCH_DIR environment variable or config file", c.WorkspaceScratchDir)
+	}
+
+	if err := c.validateWorkspaceTemplates(); err != nil {
+		return err
+	}
+
+	// Default quarantine directory lives under the workspace root
+	if c.QuarantineDir == "" {
+		c.QuarantineDir = filepath.Join(c.WorkspaceRoot, "quarantine")
+	}
+
+	// Default pause file lives under the workspace root
+	if c.PauseFile == "" {
+		c.PauseFile = filepath.Join(c.WorkspaceRoot, "triage-pause.json")
+	}
+
+	// Default queue spill directory lives under the workspace root
+	if c.QueueSpillDir == "" {
+		c.QueueSpillDir = filepath.Join(c.WorkspaceRoot, "queue-spill")
+	}
+
+	// Default budget directory lives under the workspace root
+	if c.Budget.Dir == "" {
+		c.Budget.Dir = filepath.Join(c.WorkspaceRoot, "budget")
+	}
+
 	// Required: Agent Configuration
 	if c.AgentScriptPath == "" {
 		return missingFieldError("agent_script_path", "AGENT_SCRIPT_PATH")
@@ -351,6 +1417,9 @@ func (c *Config) Validate() error {
 
 	// Note: AdditionalAgentPrompt is optional - system prompt drives investigation
 
+	// Optional: report language (codes such as "ja" become "Japanese")
+	c.ReportLanguage = NormalizeLanguage(c.ReportLanguage)
+
 	// Required: Event Processing
 	if c.SeverityThreshold == "" {
 		return missingFieldError("severity_threshold", "SEVERITY_THRESHOLD")
@@ -399,9 +1468,6 @@ func (c *Config) Validate() error {
 	}
 
 	// Validate severity threshold
-	validSeverities := map[string]bool{
-		"DEBUG": true, "INFO": true, "WARNING": true, "ERROR": true, "CRITICAL": true,
-	}
 	if !validSeverities[strings.ToUpper(c.SeverityThreshold)] {
 		return fmt.Errorf("invalid severity_threshold '%s': must be one of DEBUG, INFO, WARNING, ERROR, CRITICAL", c.SeverityThreshold)
 	}
@@ -410,6 +1476,15 @@ func (c *Config) Validate() error {
 	if c.MaxConcurrentAgents < 1 {
 		return fmt.Errorf("max_concurrent_agents must be >= 1, got %d. Set via MAX_CONCURRENT_AGENTS environment variable or config file", c.MaxConcurrentAgents)
 	}
+	if c.ReservedAgentSlots < 0 || c.ReservedAgentSlots >= c.MaxConcurrentAgents {
+		return fmt.Errorf("reserved_agent_slots must be >= 0 and < max_concurrent_agents (%d), got %d. Set via RESERVED_AGENT_SLOTS environment variable or config file", c.MaxConcurrentAgents, c.ReservedAgentSlots)
+	}
+	if c.ReservedSeverity == "" {
+		c.ReservedSeverity = "CRITICAL"
+	}
+	if !validSeverities[strings.ToUpper(c.ReservedSeverity)] {
+		return fmt.Errorf("invalid reserved_severity '%s': must be one of DEBUG, INFO, WARNING, ERROR, CRITICAL", c.ReservedSeverity)
+	}
 	if c.GlobalQueueSize < 1 {
 		return fmt.Errorf("global_queue_size must be >= 1, got %d. Set via GLOBAL_QUEUE_SIZE environment variable or config file", c.GlobalQueueSize)
 	}
@@ -419,17 +1494,37 @@ func (c *Config) Validate() error {
 	if c.DedupWindowSeconds < 0 {
 		return fmt.Errorf("dedup_window_seconds must be >= 0, got %d. Set via DEDUP_WINDOW_SECONDS environment variable or config file", c.DedupWindowSeconds)
 	}
+	if c.QueueAlertThresholdPercent < 0 || c.QueueAlertThresholdPercent > 100 {
+		return fmt.Errorf("queue_alert_threshold_percent must be between 0 and 100, got %d. Set via QUEUE_ALERT_THRESHOLD_PERCENT environment variable or config file", c.QueueAlertThresholdPercent)
+	}
+	if c.InvestigationCacheTTLSeconds < 0 {
+		return fmt.Errorf("investigation_cache_ttl_seconds must be >= 0, got %d. Set via INVESTIGATION_CACHE_TTL_SECONDS environment variable or config file", c.InvestigationCacheTTLSeconds)
+	}
 	if c.AgentTimeout < 1 {
 		return fmt.Errorf("agent_timeout must be >= 1, got %d. Set via AGENT_TIMEOUT environment variable or config file", c.AgentTimeout)
 	}
+	for severity, timeout := range c.AgentTimeoutBySeverity {
+		if !validSeverities[strings.ToUpper(severity)] {
+			return fmt.Errorf("invalid agent_timeout_by_severity severity '%s': must be one of DEBUG, INFO, WARNING, ERROR, CRITICAL", severity)
+		}
+		if timeout < 1 {
+			return fmt.Errorf("agent_timeout_by_severity.%s must be >= 1, got %d", severity, timeout)
+		}
+	}
 	if c.ShutdownTimeout < 1 {
 		return fmt.Errorf("shutdown_timeout must be >= 1, got %d. Set via SHUTDOWN_TIMEOUT_SECONDS environment variable or config file", c.ShutdownTimeout)
 	}
 
 	// Validate queue overflow policy
-	validPolicies := map[string]bool{"drop": true, "reject": true}
-	if !validPolicies[strings.ToLower(c.QueueOverflowPolicy)] {
-		return fmt.Errorf("invalid queue_overflow_policy '%s': must be 'drop' or 'reject'. Set via QUEUE_OVERFLOW_POLICY environment variable or config file", c.QueueOverflowPolicy)
+	c.QueueOverflowPolicy = strings.ToLower(c.QueueOverflowPolicy)
+	if !cluster.ValidOverflowPolicy(c.QueueOverflowPolicy) {
+		return fmt.Errorf("invalid queue_overflow_policy '%s': must be 'drop', 'reject', 'drop-oldest', or 'spill'. Set via QUEUE_OVERFLOW_POLICY environment variable or config file", c.QueueOverflowPolicy)
+	}
+	if c.QueueSpillMaxEvents == 0 {
+		c.QueueSpillMaxEvents = 10000
+	}
+	if c.QueueSpillMaxEvents < 0 {
+		return fmt.Errorf("queue_spill_max_events must be >= 1, got %d. Set via QUEUE_SPILL_MAX_EVENTS environment variable or config file", c.QueueSpillMaxEvents)
 	}
 
 	// Validate SSE reconnection settings
@@ -443,12 +1538,44 @@ func (c *Config) Validate() error {
 	if c.SSEReadTimeout < 1 {
 		return fmt.Errorf("sse_read_timeout must be >= 1, got %d. Set via SSE_READ_TIMEOUT_SECONDS environment variable or config file", c.SSEReadTimeout)
 	}
+	if c.ClusterDisconnectAlertSeconds < 0 {
+		return fmt.Errorf("cluster_disconnect_alert_seconds must be >= 0, got %d. Set via CLUSTER_DISCONNECT_ALERT_SECONDS environment variable or config file", c.ClusterDisconnectAlertSeconds)
+	}
+	if err := c.ClusterStartup.Validate(); err != nil {
+		return err
+	}
+	if err := c.CredentialExpiry.Validate(); err != nil {
+		return err
+	}
 
 	// Validate circuit breaker settings
 	if c.FailureThresholdForAlert < 1 {
 		return fmt.Errorf("failure_threshold_for_alert must be >= 1, got %d. Set via FAILURE_THRESHOLD_FOR_ALERT environment variable or config file", c.FailureThresholdForAlert)
 	}
 
+	// Validate API key rotation settings
+	if c.APIKeyCooldownSeconds < 0 {
+		return fmt.Errorf("api_key_cooldown_seconds must be >= 0, got %d", c.APIKeyCooldownSeconds)
+	}
+	if c.APIKeyCooldownSeconds == 0 {
+		c.APIKeyCooldownSeconds = 60
+	}
+
+	// Validate LLM rate limits used for launch pacing
+	if err := c.validateLLMRateLimits(); err != nil {
+		return err
+	}
+
+	// Validate the self-hosted or managed-cloud LLM provider, if any
+	if err := c.validateLLMProviders(); err != nil {
+		return err
+	}
+
+	// Validate fallback triage (before the API key check it can waive)
+	if err := c.FallbackTriage.Validate(); err != nil {
+		return err
+	}
+
 	// Require at least one LLM API key
 	if err := c.ValidateLLMAPIKeys(); err != nil {
 		return err
@@ -464,20 +1591,255 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	// Validate proxy configuration
+	if err := c.Proxy.Validate(); err != nil {
+		return err
+	}
+
+	// Validate network (dialer) configuration
+	if err := c.Network.Validate(); err != nil {
+		return err
+	}
+
+	// Validate budget configuration
+	if err := c.validateBudgets(); err != nil {
+		return err
+	}
+
+	// Validate aggregation configuration
+	if err := c.Aggregation.Validate(); err != nil {
+		return err
+	}
+
+	// Validate skill bundles
+	if err := c.Skills.Validate(); err != nil {
+		return err
+	}
+
+	// Validate runbooks
+	if err := c.Runbooks.Validate(); err != nil {
+		return err
+	}
+
+	// Validate postmortem publishing
+	if err := c.Postmortem.Validate(); err != nil {
+		return err
+	}
+
+	// Validate knowledge base publishing
+	if err := c.KnowledgeBase.Validate(); err != nil {
+		return err
+	}
+
+	// Validate ServiceNow integration
+	if err := c.ServiceNow.Validate(); err != nil {
+		return err
+	}
+
+	// Validate lifecycle webhooks
+	if err := c.LifecycleWebhooks.Validate(); err != nil {
+		return err
+	}
+
+	// Validate ownership lookup and notification routes
+	if err := c.Ownership.Validate(); err != nil {
+		return err
+	}
+
+	// Validate the Slack app
+	if err := c.SlackApp.Validate(); err != nil {
+		return err
+	}
+	if c.SlackApp.Enabled() && !c.IsSQLStorageEnabled() {
+		return fmt.Errorf("slack_app requires state_storage.type sqlite or postgres, got %q", c.GetStateStorageType())
+	}
+
+	// Validate health server security settings
+	if err := c.HealthServer.Validate(); err != nil {
+		return err
+	}
+
+	// Validate canary settings
+	if err := c.Canary.Validate(); err != nil {
+		return err
+	}
+
+	// Validate incident labels; cluster labels must also be valid incident labels
+	// when they are copied onto incidents
+	if err := c.IncidentLabels.Validate(); err != nil {
+		return err
+	}
+	if c.IncidentLabels.FromCluster {
+		for _, cl := range c.Clusters {
+			if err := labels.Validate(cl.Labels, nil); err != nil {
+				return fmt.Errorf("cluster %s: %w (required by incident_labels.from_cluster)", cl.Name, err)
+			}
+		}
+	}
+
+	// Validate agent profiles
+	if err := c.AgentProfiles.Validate(); err != nil {
+		return err
+	}
+
+	// Validate agent hardening
+	if err := c.AgentHardening.Validate(); err != nil {
+		return err
+	}
+
+	// Validate agent egress control
+	if err := c.AgentEgress.Validate(); err != nil {
+		return err
+	}
+
+	// Validate agent debug bundles
+	if err := c.AgentDebugBundle.Validate(); err != nil {
+		return err
+	}
+
+	// Validate notification coalescing
+	if err := c.NotificationCoalescing.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.validateNotificationRouting(); err != nil {
+		return err
+	}
+
+	// Validate adaptive dedup
+	if err := c.AdaptiveDedup.Validate(c.DedupWindowSeconds); err != nil {
+		return err
+	}
+
+	// Validate sampling
+	if err := c.Sampling.Validate(); err != nil {
+		return err
+	}
+
+	// Validate incident IDs
+	if err := c.IncidentIDs.Validate(); err != nil {
+		return err
+	}
+
+	// Validate report rendering
+	if err := c.ReportRendering.Validate(); err != nil {
+		return err
+	}
+
+	// Validate follow-up incident linking (after state storage is defaulted)
+	if err := c.FollowUp.Validate(c.StateStorage.Type); err != nil {
+		return err
+	}
+
+	if err := c.ArtifactRetention.Validate(); err != nil {
+		return err
+	}
+
+	// Validate output verification
+	if err := c.Verification.Validate(); err != nil {
+		return err
+	}
+
+	// Validate topology collection
+	if err := c.Topology.Validate(); err != nil {
+		return err
+	}
+
+	// Validate MCP enrichment
+	if err := c.MCPEnrichment.Validate(); err != nil {
+		return err
+	}
+
+	// Validate supply chain verification (after the workspace root is defaulted)
+	if err := c.SupplyChain.Validate(c.WorkspaceRoot); err != nil {
+		return err
+	}
+
+	// Validate agent runtime pins
+	if err := c.AgentPinning.Validate(); err != nil {
+		return err
+	}
+
+	// Validate the report link shortener
+	if err := c.URLShortener.Validate(); err != nil {
+		return err
+	}
+
+	// Validate fleet-wide limits (after state storage is defaulted)
+	if err := c.SharedLimits.Validate(c.StateStorage.Type); err != nil {
+		return err
+	}
+
+	// Validate noise analysis (after state storage is defaulted)
+	if err := c.NoiseAnalysis.Validate(c.StateStorage.Type); err != nil {
+		return err
+	}
+
+	// Validate SQLite maintenance (after state storage is defaulted)
+	if err := c.SQLiteMaintenance.Validate(c.StateStorage.Type); err != nil {
+		return err
+	}
+
+	// Validate the incident feed (after state storage is defaulted)
+	if err := c.IncidentFeed.Validate(c.StateStorage.Type); err != nil {
+		return err
+	}
+
+	// Validate the provider status monitor
+	if err := c.ProviderStatus.Validate(); err != nil {
+		return err
+	}
+
+	// Validate the OpenSearch/Elasticsearch export
+	if err := c.SearchExport.Validate(); err != nil {
+		return err
+	}
+
+	// Validate the severity policy rules
+	if err := c.SeverityPolicy.Validate(); err != nil {
+		return err
+	}
+
+	// Validate the fault handlers
+	if err := c.FaultHandlers.Validate(); err != nil {
+		return err
+	}
+
+	// Validate the incident lifecycle settings
+	if err := c.IncidentLifecycle.Validate(); err != nil {
+		return err
+	}
+
+	// Validate Kubernetes Events emission
+	if err := c.KubeEvents.Validate(); err != nil {
+		return err
+	}
+
+	// Validate operator mode
+	if err := c.Operator.Validate(); err != nil {
+		return err
+	}
+
+	// Validate offline mode (after all other settings are defaulted)
+	if err := c.validateOffline(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // ValidateLLMAPIKeys ensures at least one LLM API key is configured.
-// Returns an error if no API keys are found.
+// Returns an error if no API keys are found. Self-hosted and managed-cloud
+// providers (llm_endpoint, azure_openai, bedrock) carry their own credentials,
+// and with the no_api_key fallback trigger faults get rule-based triage instead.
 func (c *Config) ValidateLLMAPIKeys() error {
-	if c.AnthropicAPIKey != "" {
+	if c.LLMProvider() != "" || c.FallbackTriage.Triggered(FallbackNoAPIKey) {
 		return nil
 	}
-	if c.OpenAIAPIKey != "" {
-		return nil
-	}
-	if c.GeminiAPIKey != "" {
-		return nil
+	for _, keys := range c.APIKeys() {
+		if len(keys) > 0 {
+			return nil
+		}
 	}
 
 	return fmt.Errorf("at least one LLM API key is required: set ANTHROPIC_API_KEY, OPENAI_API_KEY, or GEMINI_API_KEY (via environment variable, config file, or command-line)")
@@ -488,6 +1850,17 @@ func GetConfigFile() string {
 	return viper.ConfigFileUsed()
 }
 
+// AgentSeverityTimeouts returns the per-severity agent timeouts keyed by uppercase
+// severity (the config loader lowercases map keys). Severities without an entry use
+// AgentTimeout.
+func (c *Config) AgentSeverityTimeouts() map[string]int {
+	timeouts := make(map[string]int, len(c.AgentTimeoutBySeverity))
+	for severity, timeout := range c.AgentTimeoutBySeverity {
+		timeouts[strings.ToUpper(severity)] = timeout
+	}
+	return timeouts
+}
+
 // IsAzureStorageEnabled detects if Azure storage is configured.
 // Returns true if AZURE_STORAGE_ACCOUNT or AZURE_STORAGE_CONNECTION_STRING is set.
 func (c *Config) IsAzureStorageEnabled() bool {
@@ -535,6 +1908,18 @@ func (c *Config) GetAzureSASExpiry() time.Duration {
 	return duration
 }
 
+// TagAzureArtifactClasses reports whether uploaded blobs are tagged with their
+// artifact class for lifecycle management (see storage.AzureRetentionConfig).
+func (c *Config) TagAzureArtifactClasses() bool {
+	return c.ArtifactRetention.AzureIndexTags
+}
+
+// GetAzureProxySettings returns the proxy settings for Azure Blob Storage requests.
+// This method is part of the AzureProxyConfig interface.
+func (c *Config) GetAzureProxySettings() proxy.Settings {
+	return c.Proxy.AzureSettings()
+}
+
 // ValidateAzureConfig validates Azure storage configuration if Azure storage is enabled.
 // Returns an error if Azure is enabled but required fields are missing or invalid.
 func (c *Config) ValidateAzureConfig() error {
@@ -643,6 +2028,11 @@ func (c *Config) ValidateStateStorage() error {
 		c.StateStorage.MigrationsPath = "./migrations"
 	}
 
+	// Set default backup directory if not specified
+	if c.StateStorage.BackupDir == "" {
+		c.StateStorage.BackupDir = filepath.Join(c.WorkspaceRoot, "backups")
+	}
+
 	// Validate SQLite configuration
 	if c.StateStorage.Type == "sqlite" {
 		// Set default SQLite path if not specified
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default credential expiry check settings
+const (
+	defaultCredentialExpiryWarnDays             = 14
+	defaultCredentialExpiryCheckIntervalMinutes = 360
+)
+
+// CredentialExpiryConfig configures the check of the credentials in each
+// triage-enabled cluster's kubeconfig. Client certificates and bearer tokens with
+// an expiry (JWTs with an "exp" claim) are checked at startup and every interval;
+// a cluster whose credential expires within WarnDays, or has expired, is alerted
+// through the configured notifiers, and again once the credential is renewed. The
+// state of every cluster is served on /health/credentials.
+type CredentialExpiryConfig struct {
+	// WarnDays is how many days before a credential expires its cluster is
+	// alerted. -1 disables the check.
+	// Default: 14
+	// Environment variable: CREDENTIAL_EXPIRY_WARN_DAYS
+	WarnDays int `mapstructure:"warn_days"`
+
+	// CheckIntervalMinutes is how often the kubeconfigs are checked, so rotated
+	// credentials are noticed without a restart.
+	// Default: 360
+	// Environment variable: CREDENTIAL_EXPIRY_CHECK_INTERVAL_MINUTES
+	CheckIntervalMinutes int `mapstructure:"check_interval_minutes"`
+}
+
+// Enabled reports whether credential expiry is checked.
+func (c CredentialExpiryConfig) Enabled() bool {
+	return c.WarnDays >= 0
+}
+
+// WarnBefore returns how long before a credential expires its cluster is alerted.
+func (c CredentialExpiryConfig) WarnBefore() time.Duration {
+	return time.Duration(c.WarnDays) * 24 * time.Hour
+}
+
+// CheckInterval returns how often the kubeconfigs are checked.
+func (c CredentialExpiryConfig) CheckInterval() time.Duration {
+	return time.Duration(c.CheckIntervalMinutes) * time.Minute
+}
+
+// Validate applies the defaults and checks the settings.
+func (c *CredentialExpiryConfig) Validate() error {
+	if c.WarnDays == 0 {
+		c.WarnDays = defaultCredentialExpiryWarnDays
+	}
+	if c.WarnDays < -1 {
+		return fmt.Errorf("credential_expiry.warn_days must be positive or -1 to disable, got %d", c.WarnDays)
+	}
+	if c.CheckIntervalMinutes == 0 {
+		c.CheckIntervalMinutes = defaultCredentialExpiryCheckIntervalMinutes
+	}
+	if c.CheckIntervalMinutes < 1 {
+		return fmt.Errorf("credential_expiry.check_interval_minutes must be positive, got %d", c.CheckIntervalMinutes)
+	}
+	return nil
+}
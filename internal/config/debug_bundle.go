@@ -0,0 +1,46 @@
+package config
+
+import "fmt"
+
+// defaultDebugBundleTailKB is the agent output kept for a debug bundle, per stream
+const defaultDebugBundleTailKB = 64
+
+// AgentDebugBundleConfig configures the debug bundle captured when the agent
+// process crashes or is OOM-killed: the tail of its stdout and stderr, an
+// allow-listed snapshot of its environment, its resource usage, and the kernel's
+// OOM and crash lines (when dmesg is readable). The bundle is written to
+// logs/debug-bundle.json in the incident workspace, marked with the failure class,
+// and is never uploaded. Agents stopped at their timeout get no bundle.
+type AgentDebugBundleConfig struct {
+	// TailKB is how much of the agent's stdout and stderr is kept, per stream, in
+	// KiB. 0 uses the default; -1 disables debug bundles.
+	// Default: 64
+	// Environment variable: AGENT_DEBUG_BUNDLE_TAIL_KB
+	TailKB int `mapstructure:"tail_kb"`
+
+	// Environment lists environment variables recorded besides the built-in
+	// allow-list (the agent settings nightcrier passes to run-agent.sh, PATH, HOME,
+	// and DOCKER_HOST). Never list variables holding credentials.
+	// Default: []
+	// Environment variable: AGENT_DEBUG_BUNDLE_ENVIRONMENT (comma-separated)
+	Environment []string `mapstructure:"environment"`
+}
+
+// TailBytes returns the output kept per stream, 0 when debug bundles are disabled.
+func (d AgentDebugBundleConfig) TailBytes() int {
+	if d.TailKB <= 0 {
+		return 0
+	}
+	return d.TailKB * 1024
+}
+
+// Validate applies the default and checks the tail size.
+func (d *AgentDebugBundleConfig) Validate() error {
+	if d.TailKB == 0 {
+		d.TailKB = defaultDebugBundleTailKB
+	}
+	if d.TailKB < -1 {
+		return fmt.Errorf("agent_debug_bundle.tail_kb must be >= -1, got %d", d.TailKB)
+	}
+	return nil
+}
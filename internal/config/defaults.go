@@ -0,0 +1,40 @@
+package config
+
+import "github.com/spf13/viper"
+
+// ManagedDefaults are the values of settings that have an obvious default, keyed
+// by config key. Unless strict_config is set they are applied as viper defaults,
+// so a minimal configuration (clusters, an LLM API key, and workspace_root) is
+// enough to start; any layer (config file, overlay, environment, flag) overrides
+// them. With strict_config every one of them must be set explicitly, as before
+// managed defaults existed.
+var ManagedDefaults = map[string]interface{}{
+	"subscribe_mode":                "faults",
+	"agent_script_path":             "./agent-container/run-agent.sh",
+	"agent_timeout":                 300,
+	"agent_model":                   "sonnet",
+	"agent_cli":                     "claude",
+	"agent_image":                   "nightcrier-agent:latest",
+	"severity_threshold":            "ERROR",
+	"max_concurrent_agents":         5,
+	"global_queue_size":             100,
+	"cluster_queue_size":            10,
+	"dedup_window_seconds":          300,
+	"queue_overflow_policy":         "drop",
+	"shutdown_timeout":              30,
+	"sse_reconnect_initial_backoff": 1,
+	"sse_reconnect_max_backoff":     60,
+	"sse_read_timeout":              120,
+	"failure_threshold_for_alert":   3,
+}
+
+// applyManagedDefaults registers ManagedDefaults as viper defaults, unless
+// strict_config is set.
+func applyManagedDefaults() {
+	if viper.GetBool("strict_config") {
+		return
+	}
+	for key, value := range ManagedDefaults {
+		viper.SetDefault(key, value)
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//go:generate go run ./gendocs
+
+// EnvVar describes one supported environment variable.
+type EnvVar struct {
+	// Name is the environment variable, e.g. "AGGREGATION_NODE_WINDOW_SECONDS"
+	Name string `json:"name"`
+	// Key is the config file key it sets, e.g. "aggregation.node_window_seconds"
+	Key string `json:"key"`
+	// Type is the Go type of the setting, e.g. "int" or "[]string". Lists are given
+	// as comma-separated values.
+	Type string `json:"type"`
+	// Default is the value used when the setting is not configured, as documented
+	// on the setting; empty when it has no default
+	Default string `json:"default,omitempty"`
+	// Secret is true for credentials, which belong in a Secret rather than a
+	// ConfigMap
+	Secret bool `json:"secret,omitempty"`
+	// Description is the setting's documentation
+	Description string `json:"description,omitempty"`
+}
+
+// envDoc is the documentation of a setting, taken from its doc comment by
+// gendocs (see env_docs_gen.go).
+type envDoc struct {
+	Default     string
+	Description string
+}
+
+// EnvVars returns every supported environment variable, sorted by name. The list
+// is generated from the env bindings and the Config struct's mapstructure tags, so
+// it always matches what Load reads; defaults and descriptions come from the
+// settings' doc comments and ManagedDefaults.
+func EnvVars() []EnvVar {
+	types := make(map[string]reflect.Type)
+	collectSettingTypes("", reflect.TypeOf(Config{}), types)
+
+	vars := make([]EnvVar, 0, len(envBindings))
+	for key, name := range envBindings {
+		v := EnvVar{
+			Name:        name,
+			Key:         key,
+			Secret:      isSecretSetting(key),
+			Default:     envDocs[key].Default,
+			Description: envDocs[key].Description,
+		}
+		if managed, ok := ManagedDefaults[key]; ok && v.Default == "" {
+			v.Default = fmt.Sprint(managed)
+		}
+		if t, ok := types[key]; ok {
+			v.Type = t.String()
+		}
+		vars = append(vars, v)
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// collectSettingTypes records the type of every setting below t, keyed by its
+// dotted config key. Lists and maps are leaves.
+func collectSettingTypes(path string, t reflect.Type, out map[string]reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		out[path] = t
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if opts == "squash" {
+			collectSettingTypes(path, field.Type, out)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		collectSettingTypes(joinSettingPath(path, name), field.Type, out)
+	}
+}
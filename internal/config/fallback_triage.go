@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/ruletriage"
+)
+
+// Fallback triage triggers
+const (
+	// FallbackNoAPIKey triggers when the agent's provider has no usable API key
+	// (none configured, or all rejected as invalid)
+	FallbackNoAPIKey = "no_api_key"
+	// FallbackBudgetExhausted triggers when an investigation budget denies the agent
+	FallbackBudgetExhausted = "budget_exhausted"
+	// FallbackCircuitOpen triggers while the circuit breaker is open
+	FallbackCircuitOpen = "circuit_open"
+)
+
+// defaultFallbackCircuitProbeSeconds is how often an agent still runs by default
+// while the circuit breaker is open
+const defaultFallbackCircuitProbeSeconds = 300
+
+// FallbackTriageConfig configures rule-based triage, used instead of an agent when
+// none can run: the agent's provider has no usable API key, the budget is
+// exhausted, or the circuit breaker is open. Nightcrier matches the fault type and
+// the fault's context (its description and, through the cluster's MCP server, the
+// object's recent events and the pod's log tail) against triage rules and records,
+// stores, and notifies a basic investigation report marked as rule-based, instead
+// of producing nothing.
+type FallbackTriageConfig struct {
+	// Enabled turns on rule-based fallback triage. With the no_api_key trigger,
+	// nightcrier also starts without any LLM API key.
+	// Default: false
+	// Environment variable: FALLBACK_TRIAGE_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// Triggers are the conditions that fall back to rule-based triage.
+	// Default: no_api_key, budget_exhausted, circuit_open
+	// Environment variable: FALLBACK_TRIAGE_TRIGGERS (comma-separated)
+	Triggers []string `mapstructure:"triggers"`
+
+	// CircuitProbeSeconds is how often an agent still investigates while the
+	// circuit breaker is open, so that a recovered agent closes the circuit.
+	// Faults in between get rule-based triage.
+	// Default: 300
+	// Environment variable: FALLBACK_TRIAGE_CIRCUIT_PROBE_SECONDS
+	CircuitProbeSeconds int `mapstructure:"circuit_probe_seconds"`
+
+	// Rules are triage rules tried before the built-in ones (config file only)
+	Rules []ruletriage.Rule `mapstructure:"rules"`
+}
+
+// Triggered reports whether a trigger falls back to rule-based triage.
+func (f FallbackTriageConfig) Triggered(trigger string) bool {
+	if !f.Enabled {
+		return false
+	}
+	for _, t := range f.Triggers {
+		if t == trigger {
+			return true
+		}
+	}
+	return false
+}
+
+// CircuitProbeInterval returns how often an agent runs while the circuit is open.
+func (f FallbackTriageConfig) CircuitProbeInterval() time.Duration {
+	return time.Duration(f.CircuitProbeSeconds) * time.Second
+}
+
+// Validate applies the defaults and checks the triggers and rules.
+func (f *FallbackTriageConfig) Validate() error {
+	if !f.Enabled {
+		return nil
+	}
+	if len(f.Triggers) == 0 {
+		f.Triggers = []string{FallbackNoAPIKey, FallbackBudgetExhausted, FallbackCircuitOpen}
+	}
+	for i, t := range f.Triggers {
+		t = strings.ToLower(strings.TrimSpace(t))
+		switch t {
+		case FallbackNoAPIKey, FallbackBudgetExhausted, FallbackCircuitOpen:
+		default:
+			return fmt.Errorf("fallback_triage.triggers: unknown trigger %q (must be %s, %s, or %s)",
+				t, FallbackNoAPIKey, FallbackBudgetExhausted, FallbackCircuitOpen)
+		}
+		f.Triggers[i] = t
+	}
+	if f.CircuitProbeSeconds == 0 {
+		f.CircuitProbeSeconds = defaultFallbackCircuitProbeSeconds
+	}
+	if f.CircuitProbeSeconds < 0 {
+		return fmt.Errorf("fallback_triage.circuit_probe_seconds must be positive, got %d", f.CircuitProbeSeconds)
+	}
+	for _, r := range f.Rules {
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("fallback_triage.rules: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/faulthandlers"
+)
+
+// FaultHandlersConfig routes specific fault types to specialized handlers, e.g. a
+// PVC-full fault to a storage-expansion helper. Matching handlers run in order
+// after the investigation cache, before rule-based fallback triage and the agent.
+// A "before" handler's findings are added to the agent prompt, or, when it reports
+// the fault resolved, its report replaces the investigation; an "instead"
+// handler's report is the investigation. When a handler fails, the agent runs as
+// usual. Handlers are subprocess hooks or Go plugins (see the faulthandlers
+// package). Config file only.
+type FaultHandlersConfig struct {
+	// Handlers are tried in order for each fault.
+	Handlers []FaultHandlerConfig `mapstructure:"handlers"`
+}
+
+// FaultHandlerConfig registers one handler.
+type FaultHandlerConfig struct {
+	// Name identifies the handler on the incident and in notifications
+	Name string `mapstructure:"name"`
+
+	// FaultTypes (event reasons) select the faults the handler processes; Kinds and
+	// Namespaces optionally narrow them. An empty list matches any value.
+	FaultTypes []string `mapstructure:"fault_types"`
+	Kinds      []string `mapstructure:"kinds"`
+	Namespaces []string `mapstructure:"namespaces"`
+
+	// Mode is "before" (run before the agent) or "instead" (replace the agent).
+	// Default: "before"
+	Mode string `mapstructure:"mode"`
+
+	// Command runs a subprocess hook, e.g. ["/opt/nightcrier/expand-pvc.sh",
+	// "--max", "100Gi"]. The fault is passed as JSON on stdin and the result read as
+	// JSON from stdout.
+	Command []string `mapstructure:"command"`
+
+	// Plugin is a Go plugin (.so) exporting NewHandler. Set Command or Plugin.
+	Plugin string `mapstructure:"plugin"`
+
+	// Settings are passed to the plugin, or to the hook as NIGHTCRIER_SETTING_<NAME>
+	// environment variables.
+	Settings map[string]string `mapstructure:"settings"`
+
+	// TimeoutSeconds bounds each run of the handler.
+	// Default: 120
+	TimeoutSeconds int `mapstructure:"timeout_seconds"`
+}
+
+// Specs returns the configured handlers.
+func (f FaultHandlersConfig) Specs() []faulthandlers.Spec {
+	specs := make([]faulthandlers.Spec, 0, len(f.Handlers))
+	for _, h := range f.Handlers {
+		specs = append(specs, faulthandlers.Spec{
+			Name:       h.Name,
+			FaultTypes: h.FaultTypes,
+			Kinds:      h.Kinds,
+			Namespaces: h.Namespaces,
+			Mode:       h.Mode,
+			Command:    h.Command,
+			Plugin:     h.Plugin,
+			Settings:   h.Settings,
+			Timeout:    time.Duration(h.TimeoutSeconds) * time.Second,
+		})
+	}
+	return specs
+}
+
+// Validate applies the defaults and checks the handlers. Plugins are loaded at
+// startup, not here.
+func (f *FaultHandlersConfig) Validate() error {
+	seen := make(map[string]bool)
+	for i := range f.Handlers {
+		h := &f.Handlers[i]
+		if h.Mode == "" {
+			h.Mode = faulthandlers.ModeBefore
+		}
+		if h.TimeoutSeconds < 0 {
+			return fmt.Errorf("fault_handlers.handlers[%d].timeout_seconds must be >= 0, got %d", i, h.TimeoutSeconds)
+		}
+		if seen[h.Name] {
+			return fmt.Errorf("fault_handlers.handlers: duplicate handler name %q", h.Name)
+		}
+		seen[h.Name] = true
+	}
+	for _, spec := range f.Specs() {
+		if err := spec.Validate(); err != nil {
+			return fmt.Errorf("fault_handlers.handlers: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Hash returns a SHA-256 fingerprint of the effective configuration (after
+// defaults, environment variables, and flags are applied). Two runs with the same
+// hash ran with identical settings; a different hash means something changed.
+// Secrets contribute to the hash, so rotating a credential changes it too.
+func (c *Config) Hash() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultFollowUpLookbackHours is how far back a resolved incident is linked by default (7 days)
+const defaultFollowUpLookbackHours = 168
+
+// FollowUpConfig links a recurring fault to its earlier investigation. When a
+// fault recurs on the same resource (same cluster, namespace, resource, and fault
+// type) after its incident was resolved, the new incident is created as a
+// follow-up of the most recent resolved one (parent_incident_id), the agent
+// prompt includes the previous root cause, and notifications show the chain of
+// earlier incidents.
+//
+// Requires a sqlite or postgres state store, which holds the earlier incidents
+// and their reports.
+type FollowUpConfig struct {
+	// Enabled turns on follow-up linking.
+	// Default: false
+	// Environment variable: FOLLOW_UP_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// LookbackHours is how long after an incident was created a recurrence is
+	// still linked to it.
+	// Default: 168 (7 days)
+	// Environment variable: FOLLOW_UP_LOOKBACK_HOURS
+	LookbackHours int `mapstructure:"lookback_hours"`
+}
+
+// Lookback returns the follow-up lookback window.
+func (f FollowUpConfig) Lookback() time.Duration {
+	return time.Duration(f.LookbackHours) * time.Hour
+}
+
+// Validate applies the default lookback and checks that the state store can hold
+// incident history.
+func (f *FollowUpConfig) Validate(stateStorageType string) error {
+	if !f.Enabled {
+		return nil
+	}
+	if f.LookbackHours == 0 {
+		f.LookbackHours = defaultFollowUpLookbackHours
+	}
+	if f.LookbackHours < 0 {
+		return fmt.Errorf("follow_up.lookback_hours must be positive, got %d", f.LookbackHours)
+	}
+	if stateStorageType != "sqlite" && stateStorageType != "postgres" {
+		return fmt.Errorf("follow_up requires a sqlite or postgres state store (state_storage.type is %q)", stateStorageType)
+	}
+	return nil
+}
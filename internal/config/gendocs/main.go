@@ -0,0 +1,212 @@
+// Command gendocs generates env_docs_gen.go: the default and description of every
+// configuration setting, taken from the doc comments of the Config struct and the
+// structs it contains. Run it with go generate in internal/config after changing a
+// setting's documentation.
+//
+// A setting's doc comment documents its default on a line starting with
+// "Default:" and its environment variable on a line starting with
+// "Environment variable:"; the remaining lines are its description.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// packages are parsed for struct definitions, keyed by the name they are
+// referred to by from the config package ("" for the config package itself)
+var packages = map[string]string{
+	"":        ".",
+	"cluster": "../cluster",
+}
+
+const outputFile = "env_docs_gen.go"
+
+type setting struct {
+	defaultValue string
+	description  string
+}
+
+func main() {
+	structs := make(map[string]*ast.StructType)
+	for pkg, dir := range packages {
+		if err := parseStructs(pkg, dir, structs); err != nil {
+			log.Fatalf("failed to parse %s: %v", dir, err)
+		}
+	}
+	root, ok := structs["Config"]
+	if !ok {
+		log.Fatal("Config struct not found; run gendocs from internal/config")
+	}
+
+	settings := make(map[string]setting)
+	walk("", "", root, structs, settings)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gendocs from the config doc comments. DO NOT EDIT.\n\n")
+	buf.WriteString("package config\n\n")
+	buf.WriteString("// envDocs documents each setting, keyed by its config key\n")
+	buf.WriteString("var envDocs = map[string]envDoc{\n")
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := settings[key]
+		fmt.Fprintf(&buf, "\t%q: {Default: %q, Description: %q},\n", key, s.defaultValue, s.description)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("failed to format generated code: %v", err)
+	}
+	if err := os.WriteFile(outputFile, src, 0644); err != nil {
+		log.Fatalf("failed to write %s: %v", outputFile, err)
+	}
+}
+
+// parseStructs adds the struct types declared in dir, qualified with pkg.
+func parseStructs(pkg, dir string, structs map[string]*ast.StructType) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") || filepath.Base(path) == outputFile {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					structs[qualify(pkg, ts.Name.Name)] = st
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// walk records the settings of st below path. pkg qualifies the struct names st
+// refers to.
+func walk(path, pkg string, st *ast.StructType, structs map[string]*ast.StructType, out map[string]setting) {
+	for _, field := range st.Fields.List {
+		var tag string
+		if field.Tag != nil {
+			unquoted, _ := strconv.Unquote(field.Tag.Value)
+			tag = reflect.StructTag(unquoted).Get("mapstructure")
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+
+		// Squashed fields are embedded and have no names
+		if opts == "squash" {
+			if nested, nestedPkg := lookup(field.Type, pkg, structs); nested != nil {
+				walk(path, nestedPkg, nested, structs, out)
+			}
+			continue
+		}
+
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			key := name
+			if key == "" {
+				key = strings.ToLower(ident.Name)
+			}
+			if path != "" {
+				key = path + "." + key
+			}
+			if nested, nestedPkg := lookup(field.Type, pkg, structs); nested != nil {
+				walk(key, nestedPkg, nested, structs, out)
+				continue
+			}
+			if s := parseDoc(ident.Name, field); s != (setting{}) {
+				out[key] = s
+			}
+		}
+	}
+}
+
+// lookup returns the struct a field type refers to, if it is one of the parsed
+// structs, and the package its own references are relative to.
+func lookup(expr ast.Expr, pkg string, structs map[string]*ast.StructType) (*ast.StructType, string) {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return lookup(t.X, pkg, structs)
+	case *ast.Ident:
+		return structs[qualify(pkg, t.Name)], pkg
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			return structs[qualify(x.Name, t.Sel.Name)], x.Name
+		}
+	case *ast.StructType:
+		return t, pkg
+	}
+	return nil, ""
+}
+
+// parseDoc extracts a setting's default and description from its doc comment and
+// trailing line comment. A doc comment that does not start with the field name is
+// a heading for a group of fields and is ignored.
+func parseDoc(name string, field *ast.Field) setting {
+	var s setting
+	var description []string
+	groups := []*ast.CommentGroup{field.Comment}
+	if field.Doc != nil && strings.HasPrefix(field.Doc.Text(), name+" ") {
+		groups = append([]*ast.CommentGroup{field.Doc}, groups...)
+	}
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, line := range strings.Split(group.Text(), "\n") {
+			line = strings.TrimSpace(line)
+			switch {
+			case line == "":
+			case strings.HasPrefix(line, "Default:"):
+				s.defaultValue = strings.TrimSpace(strings.TrimPrefix(line, "Default:"))
+				if unquoted, err := strconv.Unquote(s.defaultValue); err == nil {
+					s.defaultValue = unquoted
+				}
+			case strings.HasPrefix(line, "Environment variable"):
+			default:
+				description = append(description, line)
+			}
+		}
+	}
+	s.description = strings.Join(description, " ")
+	return s
+}
+
+func qualify(pkg, name string) string {
+	if pkg == "" {
+		return name
+	}
+	return pkg + "." + name
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// defaultAgentHardeningUser is the unprivileged "agent" user of the agent image.
+const defaultAgentHardeningUser = "1000:1000"
+
+// AgentHardeningConfig enables least-privilege execution of the agent container:
+// it runs as a dedicated unprivileged user with all capabilities dropped and
+// no-new-privileges set, and (by default) with a read-only root filesystem where
+// only the workspace output, logs, and the configured scratch paths are writable.
+// Run "nightcrier doctor" to check the settings and the host.
+type AgentHardeningConfig struct {
+	// Enabled turns on hardened execution.
+	// Default: false
+	// Environment variable: AGENT_HARDENING
+	Enabled bool `mapstructure:"enabled"`
+
+	// User is the container user as "UID" or "UID:GID". Root is rejected.
+	// Default: "1000:1000" (the agent image's "agent" user)
+	// Environment variable: AGENT_HARDENING_USER
+	User string `mapstructure:"user"`
+
+	// AllowWritableRoot keeps the container's root filesystem writable, for agent
+	// images that write outside the workspace and the scratch paths.
+	// Default: false
+	// Environment variable: AGENT_HARDENING_ALLOW_WRITABLE_ROOT
+	AllowWritableRoot bool `mapstructure:"allow_writable_root"`
+
+	// TmpfsPaths are container paths mounted as private in-memory scratch space
+	// when the root filesystem is read-only, e.g. the agent CLI's state directory.
+	// Default: ["/tmp"]
+	// Environment variable: AGENT_HARDENING_TMPFS_PATHS (comma-separated)
+	TmpfsPaths []string `mapstructure:"tmpfs_paths"`
+}
+
+// Environment returns the assignments passed to run-agent.sh to harden the agent
+// container, or nil when hardening is disabled.
+func (h AgentHardeningConfig) Environment() []string {
+	if !h.Enabled {
+		return nil
+	}
+	env := []string{
+		"AGENT_HARDENING=true",
+		"CONTAINER_USER=" + h.User,
+	}
+	if !h.AllowWritableRoot {
+		env = append(env,
+			"CONTAINER_READ_ONLY=true",
+			"CONTAINER_TMPFS="+strings.Join(h.TmpfsPaths, ","))
+	}
+	return env
+}
+
+// Validate applies defaults and rejects settings that would run the agent as root.
+func (h *AgentHardeningConfig) Validate() error {
+	if !h.Enabled {
+		return nil
+	}
+	if h.User == "" {
+		h.User = defaultAgentHardeningUser
+	}
+	if len(h.TmpfsPaths) == 0 {
+		h.TmpfsPaths = []string{"/tmp"}
+	}
+
+	ids := strings.Split(h.User, ":")
+	if len(ids) > 2 {
+		return fmt.Errorf("agent_hardening.user must be numeric UID or UID:GID, got %q", h.User)
+	}
+	for _, id := range ids {
+		n, err := strconv.ParseUint(id, 10, 32)
+		if err != nil {
+			return fmt.Errorf("agent_hardening.user must be numeric UID or UID:GID, got %q", h.User)
+		}
+		if n == 0 {
+			return fmt.Errorf("agent_hardening.user must not be root (UID or GID 0), got %q", h.User)
+		}
+	}
+
+	for _, p := range h.TmpfsPaths {
+		if !path.IsAbs(p) || strings.Contains(p, ",") {
+			return fmt.Errorf("agent_hardening.tmpfs_paths must be absolute container paths without commas, got %q", p)
+		}
+	}
+	return nil
+}
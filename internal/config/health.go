@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rbias/nightcrier/internal/health"
+)
+
+// HealthServerConfig secures the health monitoring HTTP server so it can be exposed
+// beyond localhost. The listening port is set with the --health-port flag; these
+// settings control the interface it binds to, TLS, and client authentication.
+type HealthServerConfig struct {
+	// BindAddress is the interface the health server listens on, e.g. "127.0.0.1"
+	// Default: "" (all interfaces)
+	// Environment variable: HEALTH_BIND_ADDRESS
+	BindAddress string `mapstructure:"bind_address"`
+
+	// TLSCertFile and TLSKeyFile enable HTTPS. Both must be set together.
+	// Environment variables: HEALTH_TLS_CERT_FILE, HEALTH_TLS_KEY_FILE
+	TLSCertFile string `mapstructure:"tls_cert_file"`
+	TLSKeyFile  string `mapstructure:"tls_key_file"`
+
+	// ClientCAFile enables mutual TLS: clients must present a certificate signed
+	// by a CA in this PEM bundle. Requires tls_cert_file and tls_key_file.
+	// Environment variable: HEALTH_CLIENT_CA_FILE
+	ClientCAFile string `mapstructure:"client_ca_file"`
+
+	// AuthToken requires requests to carry "Authorization: Bearer <token>"
+	// Default: "" (no token required)
+	// Environment variable: HEALTH_AUTH_TOKEN
+	AuthToken string `mapstructure:"auth_token"`
+}
+
+// TLSEnabled reports whether the health server serves HTTPS.
+func (h HealthServerConfig) TLSEnabled() bool {
+	return h.TLSCertFile != ""
+}
+
+// ServerOptions returns the settings for a health.Server.
+func (h HealthServerConfig) ServerOptions() health.Options {
+	return health.Options{
+		BindAddress:  h.BindAddress,
+		TLSCertFile:  h.TLSCertFile,
+		TLSKeyFile:   h.TLSKeyFile,
+		ClientCAFile: h.ClientCAFile,
+		AuthToken:    h.AuthToken,
+	}
+}
+
+// Validate checks the health server settings.
+func (h *HealthServerConfig) Validate() error {
+	if (h.TLSCertFile == "") != (h.TLSKeyFile == "") {
+		return fmt.Errorf("health_server.tls_cert_file and health_server.tls_key_file must be set together (environment variables: HEALTH_TLS_CERT_FILE, HEALTH_TLS_KEY_FILE)")
+	}
+	if h.ClientCAFile != "" && !h.TLSEnabled() {
+		return fmt.Errorf("health_server.client_ca_file requires health_server.tls_cert_file and health_server.tls_key_file")
+	}
+	for field, path := range map[string]string{
+		"health_server.tls_cert_file":  h.TLSCertFile,
+		"health_server.tls_key_file":   h.TLSKeyFile,
+		"health_server.client_ca_file": h.ClientCAFile,
+	} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("%s not accessible: %w", field, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Incident feed modes
+const (
+	// IncidentFeedAuto follows changes with PostgreSQL LISTEN/NOTIFY when the state
+	// store is postgres, and by polling otherwise
+	IncidentFeedAuto = "auto"
+	// IncidentFeedPoll always follows changes by polling the incident history
+	IncidentFeedPoll = "poll"
+)
+
+// defaultIncidentFeedPollIntervalSeconds is how often the incident history is
+// polled by default
+const defaultIncidentFeedPollIntervalSeconds = 5
+
+// IncidentFeedConfig configures the incident change feed, which follows the
+// incidents created and updated by every nightcrier instance sharing the state
+// store. The changes are logged, streamed to dashboards by the health server
+// (/health/incidents/stream), and printed by "nightcrier incidents watch".
+type IncidentFeedConfig struct {
+	// Enabled turns on the incident change feed.
+	// Default: false
+	// Environment variable: INCIDENT_FEED_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// Mode is how changes are followed: "auto" uses PostgreSQL LISTEN/NOTIFY with a
+	// postgres state store and polling otherwise; "poll" always polls, for
+	// connection poolers that do not support LISTEN.
+	// Default: "auto"
+	// Environment variable: INCIDENT_FEED_MODE
+	Mode string `mapstructure:"mode"`
+
+	// PollIntervalSeconds is how often the incident history is polled. With
+	// LISTEN/NOTIFY the history is only polled every minute, as a safety net.
+	// Default: 5
+	// Environment variable: INCIDENT_FEED_POLL_INTERVAL_SECONDS
+	PollIntervalSeconds int `mapstructure:"poll_interval_seconds"`
+}
+
+// PollInterval returns how often the incident history is polled.
+func (f IncidentFeedConfig) PollInterval() time.Duration {
+	return time.Duration(f.PollIntervalSeconds) * time.Second
+}
+
+// Validate applies the defaults and checks the settings. The defaults apply even
+// when the feed is disabled, for the incidents watch command.
+func (f *IncidentFeedConfig) Validate(stateStorageType string) error {
+	if f.Mode == "" {
+		f.Mode = IncidentFeedAuto
+	}
+	if f.PollIntervalSeconds == 0 {
+		f.PollIntervalSeconds = defaultIncidentFeedPollIntervalSeconds
+	}
+	if f.Mode != IncidentFeedAuto && f.Mode != IncidentFeedPoll {
+		return fmt.Errorf("incident_feed.mode must be %q or %q, got %q", IncidentFeedAuto, IncidentFeedPoll, f.Mode)
+	}
+	if f.PollIntervalSeconds < 1 {
+		return fmt.Errorf("incident_feed.poll_interval_seconds must be positive, got %d", f.PollIntervalSeconds)
+	}
+	if f.Enabled && stateStorageType != "sqlite" && stateStorageType != "postgres" {
+		return fmt.Errorf("incident_feed requires a sqlite or postgres state store (state_storage.type is %q)", stateStorageType)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Incident ID schemes
+const (
+	// IncidentIDSchemeStructured gives incidents readable IDs such as
+	// NC-2024-0613-prod-0042
+	IncidentIDSchemeStructured = "structured"
+	// IncidentIDSchemeUUID identifies incidents by UUID only
+	IncidentIDSchemeUUID = "uuid"
+)
+
+// incidentIDPrefixPattern restricts display ID prefixes to short upper-case tokens
+var incidentIDPrefixPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,9}$`)
+
+// IncidentIDsConfig selects how incidents are identified outside the state
+// store. With the structured scheme each incident gets a readable display ID,
+// <prefix>-<YYYY>-<MMDD>-<cluster>-<seq>, with the sequence allocated per cluster
+// and day by the state store. The display ID names the incident's workspace
+// directory and artifact storage path and is shown in notifications and report
+// URLs; the UUID stays the incident's internal ID, and CLI commands accept
+// either.
+//
+// Display IDs are allocated by the sqlite or postgres state store; with
+// filesystem state storage incidents keep their UUIDs.
+type IncidentIDsConfig struct {
+	// Scheme is "structured" or "uuid".
+	// Default: "structured"
+	// Environment variable: INCIDENT_ID_SCHEME
+	Scheme string `mapstructure:"scheme"`
+
+	// Prefix starts every display ID (1-10 upper-case letters and digits).
+	// Default: "NC"
+	// Environment variable: INCIDENT_ID_PREFIX
+	Prefix string `mapstructure:"prefix"`
+}
+
+// Structured reports whether incidents get readable display IDs.
+func (i IncidentIDsConfig) Structured() bool {
+	return i.Scheme == IncidentIDSchemeStructured
+}
+
+// Validate applies the defaults and checks the scheme and prefix.
+func (i *IncidentIDsConfig) Validate() error {
+	i.Scheme = strings.ToLower(strings.TrimSpace(i.Scheme))
+	if i.Scheme == "" {
+		i.Scheme = IncidentIDSchemeStructured
+	}
+	if i.Scheme != IncidentIDSchemeStructured && i.Scheme != IncidentIDSchemeUUID {
+		return fmt.Errorf("incident_ids.scheme must be %q or %q, got %q", IncidentIDSchemeStructured, IncidentIDSchemeUUID, i.Scheme)
+	}
+	if i.Prefix == "" {
+		i.Prefix = "NC"
+	}
+	if !incidentIDPrefixPattern.MatchString(i.Prefix) {
+		return fmt.Errorf("incident_ids.prefix must be 1-10 upper-case letters and digits starting with a letter, got %q", i.Prefix)
+	}
+	return nil
+}
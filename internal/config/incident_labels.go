@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/rbias/nightcrier/internal/labels"
+)
+
+// IncidentLabelsConfig configures the labels and annotations attached to incidents
+// automatically. Labels support team ownership and cost-center tagging and can be
+// used to filter incidents; annotations are free-form notes. Labels can also be
+// edited manually with "nightcrier incidents label".
+//
+// Sources are applied in order, later ones replacing the same key: the fault
+// event's own labels and annotations (set upstream, e.g. alert labels), cluster
+// labels, rules, the agent's findings, manual edits.
+type IncidentLabelsConfig struct {
+	// FromCluster copies each cluster's labels (clusters[].labels) onto its incidents.
+	// Default: false
+	// Environment variable: INCIDENT_LABELS_FROM_CLUSTER
+	FromCluster bool `mapstructure:"from_cluster"`
+
+	// FromAgent asks the agent to record labels and annotations derived from its
+	// findings in output/labels.json, and attaches them to the incident.
+	// Default: false
+	// Environment variable: INCIDENT_LABELS_FROM_AGENT
+	FromAgent bool `mapstructure:"from_agent"`
+
+	// Rules attach labels and annotations to incidents by fault type, resource kind,
+	// namespace, and severity. Config file only; keys are lowercased when loaded.
+	Rules []LabelRuleConfig `mapstructure:"rules"`
+}
+
+// LabelRuleConfig attaches labels and annotations to matching incidents.
+type LabelRuleConfig struct {
+	// FaultTypes, Kinds, Namespaces, and Severities select the incidents the rule
+	// applies to. An empty list matches any value.
+	FaultTypes []string `mapstructure:"fault_types"`
+	Kinds      []string `mapstructure:"kinds"`
+	Namespaces []string `mapstructure:"namespaces"`
+	Severities []string `mapstructure:"severities"`
+
+	Labels      map[string]string `mapstructure:"labels"`
+	Annotations map[string]string `mapstructure:"annotations"`
+}
+
+// LabelRules returns the configured rules.
+func (l IncidentLabelsConfig) LabelRules() []labels.Rule {
+	rules := make([]labels.Rule, 0, len(l.Rules))
+	for _, rule := range l.Rules {
+		rules = append(rules, labels.Rule{
+			FaultTypes:  rule.FaultTypes,
+			Kinds:       rule.Kinds,
+			Namespaces:  rule.Namespaces,
+			Severities:  rule.Severities,
+			Labels:      rule.Labels,
+			Annotations: rule.Annotations,
+		})
+	}
+	return rules
+}
+
+// Validate checks the label rules.
+func (l IncidentLabelsConfig) Validate() error {
+	for i, rule := range l.LabelRules() {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("incident_labels.rules[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// reviewConfidenceRank orders the confidence levels of investigation reports
+var reviewConfidenceRank = map[string]int{"LOW": 1, "MEDIUM": 2, "HIGH": 3}
+
+// IncidentLifecycleConfig configures the incident statuses that wait for people.
+// Investigations the review policy flags end awaiting_review instead of resolved,
+// and stay there until a reviewer resolves them ("incidents review" with a
+// positive verdict, "incidents resolve", or "incidents transition"); their reports
+// are not cached or published until then. Incidents left open (received, pending,
+// queued, awaiting_review, remediation_proposed) longer than ExpireAfterHours are
+// expired.
+type IncidentLifecycleConfig struct {
+	// ReviewConfidence holds investigations whose report states a confidence at
+	// or below this level (LOW, MEDIUM, or HIGH) for human review. Reports without
+	// a readable confidence are held too. Empty disables the confidence check.
+	// Default: "" (disabled)
+	// Environment variable: INCIDENT_LIFECYCLE_REVIEW_CONFIDENCE
+	ReviewConfidence string `mapstructure:"review_confidence"`
+
+	// ReviewContradictedClaims holds investigations with claims the live cluster
+	// contradicted (see verification) for human review.
+	// Default: false
+	// Environment variable: INCIDENT_LIFECYCLE_REVIEW_CONTRADICTED_CLAIMS
+	ReviewContradictedClaims bool `mapstructure:"review_contradicted_claims"`
+
+	// ExpireAfterHours expires incidents that stayed in an open status this long.
+	// 0 disables expiry.
+	// Default: 0 (disabled)
+	// Environment variable: INCIDENT_LIFECYCLE_EXPIRE_AFTER_HOURS
+	ExpireAfterHours int `mapstructure:"expire_after_hours"`
+}
+
+// NeedsReview reports whether an investigation with the report's confidence and
+// the number of contradicted claims is held for review.
+func (c IncidentLifecycleConfig) NeedsReview(confidence string, contradicted int) bool {
+	if c.ReviewContradictedClaims && contradicted > 0 {
+		return true
+	}
+	if c.ReviewConfidence == "" {
+		return false
+	}
+	return reviewConfidenceRank[strings.ToUpper(confidence)] <= reviewConfidenceRank[c.ReviewConfidence]
+}
+
+// ExpireAfter returns how long an incident may stay open, or 0 when incidents
+// do not expire.
+func (c IncidentLifecycleConfig) ExpireAfter() time.Duration {
+	return time.Duration(c.ExpireAfterHours) * time.Hour
+}
+
+// Validate checks the settings.
+func (c *IncidentLifecycleConfig) Validate() error {
+	c.ReviewConfidence = strings.ToUpper(strings.TrimSpace(c.ReviewConfidence))
+	if _, ok := reviewConfidenceRank[c.ReviewConfidence]; c.ReviewConfidence != "" && !ok {
+		return fmt.Errorf("incident_lifecycle.review_confidence must be LOW, MEDIUM, or HIGH, got %q", c.ReviewConfidence)
+	}
+	if c.ExpireAfterHours < 0 {
+		return fmt.Errorf("incident_lifecycle.expire_after_hours must be >= 0, got %d", c.ExpireAfterHours)
+	}
+	return nil
+}
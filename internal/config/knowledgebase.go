@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/knowledgebase"
+)
+
+// KnowledgeBaseConfig configures publishing resolved investigations to the
+// organization's knowledge base. Each target is enabled independently and can be
+// limited to incidents at or above a severity.
+type KnowledgeBaseConfig struct {
+	Confluence ConfluenceConfig `mapstructure:"confluence"`
+	Notion     NotionConfig     `mapstructure:"notion"`
+}
+
+// ConfluenceConfig configures publishing a Confluence page per incident.
+type ConfluenceConfig struct {
+	// BaseURL is the Confluence base URL, e.g. https://example.atlassian.net/wiki.
+	// Empty disables Confluence publishing.
+	// Environment variable: CONFLUENCE_BASE_URL
+	BaseURL string `mapstructure:"base_url"`
+
+	// Username is the account email for Confluence Cloud basic auth. Leave empty to
+	// send the API token as a bearer personal access token (Data Center).
+	// Environment variable: CONFLUENCE_USERNAME
+	Username string `mapstructure:"username"`
+
+	// APIToken is the Confluence API token or personal access token
+	// Environment variable: CONFLUENCE_API_TOKEN
+	APIToken string `mapstructure:"api_token"`
+
+	// SpaceKey is the space pages are created in
+	// Environment variable: CONFLUENCE_SPACE_KEY
+	SpaceKey string `mapstructure:"space_key"`
+
+	// ParentPageID nests incident pages under an existing page (optional)
+	// Environment variable: CONFLUENCE_PARENT_PAGE_ID
+	ParentPageID string `mapstructure:"parent_page_id"`
+
+	// MinSeverity publishes only incidents at or above this severity
+	// Default: "" (all severities)
+	// Environment variable: CONFLUENCE_MIN_SEVERITY
+	MinSeverity string `mapstructure:"min_severity"`
+}
+
+// NotionConfig configures publishing a Notion database entry per incident.
+type NotionConfig struct {
+	// Token is the Notion integration token. Empty disables Notion publishing.
+	// Environment variable: NOTION_TOKEN
+	Token string `mapstructure:"token"`
+
+	// DatabaseID is the database entries are created in. The integration must be
+	// shared with it; see knowledgebase.NotionConfig for the expected properties.
+	// Environment variable: NOTION_DATABASE_ID
+	DatabaseID string `mapstructure:"database_id"`
+
+	// MinSeverity publishes only incidents at or above this severity
+	// Default: "" (all severities)
+	// Environment variable: NOTION_MIN_SEVERITY
+	MinSeverity string `mapstructure:"min_severity"`
+}
+
+// Enabled reports whether Confluence publishing is configured.
+func (c ConfluenceConfig) Enabled() bool {
+	return c.BaseURL != ""
+}
+
+// PublisherConfig returns the settings for a knowledgebase.Confluence publisher.
+func (c ConfluenceConfig) PublisherConfig() knowledgebase.ConfluenceConfig {
+	return knowledgebase.ConfluenceConfig{
+		BaseURL:      c.BaseURL,
+		Username:     c.Username,
+		APIToken:     c.APIToken,
+		SpaceKey:     c.SpaceKey,
+		ParentPageID: c.ParentPageID,
+	}
+}
+
+// Enabled reports whether Notion publishing is configured.
+func (n NotionConfig) Enabled() bool {
+	return n.Token != ""
+}
+
+// PublisherConfig returns the settings for a knowledgebase.Notion publisher.
+func (n NotionConfig) PublisherConfig() knowledgebase.NotionConfig {
+	return knowledgebase.NotionConfig{
+		Token:      n.Token,
+		DatabaseID: n.DatabaseID,
+	}
+}
+
+// Validate checks the settings of each enabled knowledge base.
+func (k KnowledgeBaseConfig) Validate() error {
+	if k.Confluence.Enabled() {
+		if err := validateHTTPURL("knowledge_base.confluence.base_url", k.Confluence.BaseURL); err != nil {
+			return err
+		}
+		if k.Confluence.APIToken == "" {
+			return fmt.Errorf("knowledge_base.confluence.api_token is required when knowledge_base.confluence.base_url is set (environment variable: CONFLUENCE_API_TOKEN)")
+		}
+		if k.Confluence.SpaceKey == "" {
+			return fmt.Errorf("knowledge_base.confluence.space_key is required when knowledge_base.confluence.base_url is set (environment variable: CONFLUENCE_SPACE_KEY)")
+		}
+		if err := validateMinSeverity("knowledge_base.confluence.min_severity", k.Confluence.MinSeverity); err != nil {
+			return err
+		}
+	}
+	if k.Notion.Enabled() {
+		if k.Notion.DatabaseID == "" {
+			return fmt.Errorf("knowledge_base.notion.database_id is required when knowledge_base.notion.token is set (environment variable: NOTION_DATABASE_ID)")
+		}
+		if err := validateMinSeverity("knowledge_base.notion.min_severity", k.Notion.MinSeverity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMinSeverity checks an optional severity threshold.
+func validateMinSeverity(field, value string) error {
+	switch strings.ToUpper(value) {
+	case "", "DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL":
+		return nil
+	}
+	return fmt.Errorf("invalid %s '%s': must be one of DEBUG, INFO, WARNING, ERROR, CRITICAL", field, value)
+}
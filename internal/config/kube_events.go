@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// serviceAccountNamespaceFile holds the namespace of a pod's service account
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// kubeNamePattern matches a Kubernetes object name (DNS subdomain)
+var kubeNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+// KubeEventsConfig records nightcrier's lifecycle moments as Kubernetes Events when
+// nightcrier runs in-cluster, so triage activity shows up in `kubectl describe pod`
+// for the nightcrier pod: cluster connection lost and restored, circuit breaker
+// open and closed, and investigations completed or failed for a resource. When
+// ResourceName names a Nightcrier custom resource, its status conditions are kept
+// up to date as well. The pod's service account needs create on events and, for
+// conditions, patch on nightcriers/status.
+type KubeEventsConfig struct {
+	// Enabled turns on Kubernetes Event emission. Requires running in a pod.
+	// Default: false
+	// Environment variable: KUBE_EVENTS_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// Namespace is where events are recorded (the nightcrier pod's namespace).
+	// Default: POD_NAMESPACE, else the service account's namespace
+	// Environment variable: KUBE_EVENTS_NAMESPACE
+	Namespace string `mapstructure:"namespace"`
+
+	// PodName is the pod the events are attached to.
+	// Default: POD_NAME, else the hostname (the pod name in Kubernetes)
+	// Environment variable: KUBE_EVENTS_POD_NAME
+	PodName string `mapstructure:"pod_name"`
+
+	// ResourceName is the Nightcrier custom resource (nightcriers.nightcrier.io) whose
+	// status conditions are updated. Empty disables conditions; a resource that does
+	// not exist is skipped.
+	// Default: "" (no conditions)
+	// Environment variable: KUBE_EVENTS_RESOURCE_NAME
+	ResourceName string `mapstructure:"resource_name"`
+}
+
+// Validate applies the pod identity defaults and checks the names.
+func (k *KubeEventsConfig) Validate() error {
+	if !k.Enabled {
+		return nil
+	}
+	if k.Namespace == "" {
+		k.Namespace = os.Getenv("POD_NAMESPACE")
+	}
+	if k.Namespace == "" {
+		if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+			k.Namespace = strings.TrimSpace(string(data))
+		}
+	}
+	if k.PodName == "" {
+		k.PodName = os.Getenv("POD_NAME")
+	}
+	if k.PodName == "" {
+		k.PodName, _ = os.Hostname()
+	}
+
+	if k.Namespace == "" {
+		return fmt.Errorf("kube_events.namespace is required when not running in a pod (set it or POD_NAMESPACE)")
+	}
+	for field, value := range map[string]string{"namespace": k.Namespace, "pod_name": k.PodName, "resource_name": k.ResourceName} {
+		if value != "" && !kubeNamePattern.MatchString(value) {
+			return fmt.Errorf("kube_events.%s %q is not a valid Kubernetes name", field, value)
+		}
+	}
+	if k.PodName == "" {
+		return fmt.Errorf("kube_events.pod_name is required (set it or POD_NAME)")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import "strings"
+
+// languageNames maps ISO 639-1 codes to the language names used in prompts.
+var languageNames = map[string]string{
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"pl": "Polish",
+	"pt": "Portuguese",
+	"sv": "Swedish",
+	"zh": "Chinese",
+}
+
+// NormalizeLanguage returns the language name for an ISO 639-1 code or locale
+// ("ja", "de-DE", "pt_BR"). Names and unknown values are returned trimmed, with
+// the first letter upper-cased. English is returned as "" because it is the
+// default report language.
+func NormalizeLanguage(language string) string {
+	language = strings.TrimSpace(language)
+	if language == "" {
+		return ""
+	}
+	code := strings.ToLower(language)
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i]
+	}
+	if name, ok := languageNames[code]; ok {
+		language = name
+	} else {
+		r := []rune(language)
+		language = strings.ToUpper(string(r[0])) + string(r[1:])
+	}
+	if strings.EqualFold(language, "English") {
+		return ""
+	}
+	return language
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Config layering. The configuration is merged from these layers, lowest
+// precedence first:
+//
+//  1. The base config file (e.g. configs/config.yaml)
+//  2. The profile overlay next to it, <name>.<profile>.<ext> (e.g.
+//     configs/config.production.yaml), when a profile is selected
+//  3. Cluster overlays: every *.yaml file in clusters.d/ next to the base file,
+//     then in clusters.<profile>.d/ when a profile is selected, in file name order
+//  4. Environment variables
+//  5. Command-line flags
+//
+// Overlays are merged key by key: nested sections are merged, while scalar values
+// and lists (other than clusters) replace the lower layer's. Each cluster overlay
+// file holds one cluster entry; it is merged into the cluster of the same name, or
+// appended when there is none. Staging and production can so share one base
+// config and differ only in the keys they override.
+
+// clusterOverlayDir is the directory of cluster overlays shared by every profile
+const clusterOverlayDir = "clusters.d"
+
+// ProfileOverlayPath returns the profile overlay file of a base config file, e.g.
+// configs/config.production.yaml for configs/config.yaml and profile production.
+func ProfileOverlayPath(baseFile, profile string) string {
+	ext := filepath.Ext(baseFile)
+	return strings.TrimSuffix(baseFile, ext) + "." + profile + ext
+}
+
+// clusterOverlayDirs returns the cluster overlay directories of a base config
+// file, lowest precedence first.
+func clusterOverlayDirs(baseFile, profile string) []string {
+	dir := filepath.Dir(baseFile)
+	dirs := []string{filepath.Join(dir, clusterOverlayDir)}
+	if profile != "" {
+		dirs = append(dirs, filepath.Join(dir, "clusters."+profile+".d"))
+	}
+	return dirs
+}
+
+// mergeOverlays merges the profile overlay and the cluster overlays of the base
+// config file into viper, and returns the overlay files applied in order. A
+// selected profile requires a base config file and its profile overlay; cluster
+// overlay directories are optional.
+func mergeOverlays(profile string) ([]string, error) {
+	baseFile := viper.ConfigFileUsed()
+	if baseFile == "" {
+		if profile != "" {
+			return nil, fmt.Errorf("profile %q requires a config file to overlay", profile)
+		}
+		return nil, nil
+	}
+	if _, err := os.Stat(baseFile); err != nil {
+		// The base file was not found; ReadInConfig already tolerated that
+		if profile != "" {
+			return nil, fmt.Errorf("profile %q requires a config file to overlay: %w", profile, err)
+		}
+		return nil, nil
+	}
+
+	var layers []string
+	if profile != "" {
+		overlay := ProfileOverlayPath(baseFile, profile)
+		settings, err := readOverlay(overlay)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read overlay of profile %q: %w", profile, err)
+		}
+		if err := viper.MergeConfigMap(settings); err != nil {
+			return nil, fmt.Errorf("failed to merge overlay %s: %w", overlay, err)
+		}
+		layers = append(layers, overlay)
+	}
+
+	for _, dir := range clusterOverlayDirs(baseFile, profile) {
+		files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cluster overlays in %s: %w", dir, err)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			if err := mergeClusterOverlay(file); err != nil {
+				return nil, err
+			}
+			layers = append(layers, file)
+		}
+	}
+	return layers, nil
+}
+
+// readOverlay reads a config overlay file into a settings map.
+func readOverlay(path string) (map[string]interface{}, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	return v.AllSettings(), nil
+}
+
+// mergeClusterOverlay merges a cluster overlay file into the clusters list: into
+// the cluster of the same name, or as a new cluster.
+func mergeClusterOverlay(path string) error {
+	overlay, err := readOverlay(path)
+	if err != nil {
+		return fmt.Errorf("failed to read cluster overlay %s: %w", path, err)
+	}
+	name, _ := overlay["name"].(string)
+	if name == "" {
+		return fmt.Errorf("cluster overlay %s: name is required", path)
+	}
+
+	existing, _ := viper.Get("clusters").([]interface{})
+	clusters := make([]interface{}, 0, len(existing)+1)
+	merged := false
+	for _, entry := range existing {
+		cl, ok := toStringMap(entry)
+		if ok && cl["name"] == name {
+			entry = mergeSettings(cl, overlay)
+			merged = true
+		}
+		clusters = append(clusters, entry)
+	}
+	if !merged {
+		clusters = append(clusters, overlay)
+	}
+	if err := viper.MergeConfigMap(map[string]interface{}{"clusters": clusters}); err != nil {
+		return fmt.Errorf("failed to merge cluster overlay %s: %w", path, err)
+	}
+	return nil
+}
+
+// mergeSettings returns base with overlay merged in: nested maps are merged, other
+// values replaced.
+func mergeSettings(base, overlay map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		if om, ok := toStringMap(v); ok {
+			if bm, ok := toStringMap(merged[k]); ok {
+				merged[k] = mergeSettings(bm, om)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// toStringMap converts a decoded YAML mapping to map[string]interface{}.
+func toStringMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(m))
+		for k, val := range m {
+			converted[fmt.Sprint(k)] = val
+		}
+		return converted, true
+	}
+	return nil, false
+}
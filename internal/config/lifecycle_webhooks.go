@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/webhooks"
+)
+
+// defaultLifecycleWebhookTimeoutSeconds bounds each webhook delivery attempt by default
+const defaultLifecycleWebhookTimeoutSeconds = 10
+
+// LifecycleWebhooksConfig configures webhooks posted at each incident lifecycle
+// transition (created, agent_started, completed, failed, acknowledged, resolved),
+// so external workflow engines can orchestrate follow-up actions. Deliveries go
+// through the outbox and are retried like notifications.
+type LifecycleWebhooksConfig struct {
+	// Endpoints receive the events. Config file only.
+	Endpoints []LifecycleWebhookConfig `mapstructure:"endpoints"`
+
+	// TimeoutSeconds bounds each delivery attempt.
+	// Default: 10
+	// Environment variable: LIFECYCLE_WEBHOOKS_TIMEOUT_SECONDS
+	TimeoutSeconds int `mapstructure:"timeout_seconds"`
+}
+
+// LifecycleWebhookConfig is one receiver of lifecycle events.
+type LifecycleWebhookConfig struct {
+	// Name identifies the endpoint in logs and queued deliveries
+	Name string `mapstructure:"name"`
+
+	// URL receives the events with POST
+	URL string `mapstructure:"url"`
+
+	// Secret keys the HMAC-SHA256 signature sent in the X-Nightcrier-Signature
+	// header
+	Secret string `mapstructure:"secret"`
+
+	// Events limits the event types sent to the endpoint.
+	// Default: all event types
+	Events []string `mapstructure:"events"`
+}
+
+// Enabled reports whether any webhook endpoint is configured.
+func (w LifecycleWebhooksConfig) Enabled() bool {
+	return len(w.Endpoints) > 0
+}
+
+// Timeout returns the bound on each delivery attempt.
+func (w LifecycleWebhooksConfig) Timeout() time.Duration {
+	return time.Duration(w.TimeoutSeconds) * time.Second
+}
+
+// WebhookEndpoints returns the endpoints for a webhooks.Client.
+func (w LifecycleWebhooksConfig) WebhookEndpoints() []webhooks.Endpoint {
+	endpoints := make([]webhooks.Endpoint, 0, len(w.Endpoints))
+	for _, e := range w.Endpoints {
+		endpoints = append(endpoints, webhooks.Endpoint{Name: e.Name, URL: e.URL, Secret: e.Secret, Events: e.Events})
+	}
+	return endpoints
+}
+
+// Validate applies defaults and checks the endpoints.
+func (w *LifecycleWebhooksConfig) Validate() error {
+	if w.TimeoutSeconds == 0 {
+		w.TimeoutSeconds = defaultLifecycleWebhookTimeoutSeconds
+	}
+	if w.TimeoutSeconds < 0 {
+		return fmt.Errorf("lifecycle_webhooks.timeout_seconds must be positive, got %d", w.TimeoutSeconds)
+	}
+
+	names := make(map[string]bool)
+	for i, e := range w.Endpoints {
+		if e.Name == "" {
+			return fmt.Errorf("lifecycle_webhooks.endpoints[%d]: name is required", i)
+		}
+		if names[e.Name] {
+			return fmt.Errorf("lifecycle_webhooks.endpoints[%d]: duplicate name %q", i, e.Name)
+		}
+		names[e.Name] = true
+		if err := validateHTTPURL(fmt.Sprintf("lifecycle_webhooks.endpoints[%s].url", e.Name), e.URL); err != nil {
+			return err
+		}
+		if e.Secret == "" {
+			return fmt.Errorf("lifecycle_webhooks.endpoints[%s]: secret is required to sign deliveries", e.Name)
+		}
+		for _, event := range e.Events {
+			if !slices.Contains(webhooks.EventTypes, event) {
+				return fmt.Errorf("lifecycle_webhooks.endpoints[%s]: unknown event %q: must be one of %s",
+					e.Name, event, strings.Join(webhooks.EventTypes, ", "))
+			}
+		}
+	}
+	return nil
+}
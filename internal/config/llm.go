@@ -0,0 +1,368 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/keypool"
+	"github.com/rbias/nightcrier/internal/pacing"
+)
+
+// LLM provider identifiers passed to the agent script as LLM_PROVIDER.
+const (
+	LLMProviderOpenAICompatible = "openai-compatible"
+	LLMProviderAzureOpenAI      = "azure-openai"
+	LLMProviderBedrock          = "bedrock"
+)
+
+// LLMEndpointConfig points the agent at a self-hosted OpenAI-compatible endpoint
+// (vLLM, Ollama, LocalAI, ...) instead of a hosted LLM API, so air-gapped clusters
+// can run triage against local models. Only agent CLIs that speak the OpenAI API
+// (codex, goose) can use it.
+type LLMEndpointConfig struct {
+	// BaseURL is the OpenAI-compatible API base URL, e.g. "http://ollama.local:11434/v1".
+	// Empty disables the custom endpoint.
+	// Environment variable: LLM_ENDPOINT_BASE_URL
+	BaseURL string `mapstructure:"base_url"`
+
+	// Model is the model name served by the endpoint, e.g. "qwen2.5-coder:32b".
+	// Overrides agent_model when set.
+	// Environment variable: LLM_ENDPOINT_MODEL
+	Model string `mapstructure:"model"`
+
+	// APIKey is the endpoint's API key. Optional: many local servers do not check it.
+	// Environment variable: LLM_ENDPOINT_API_KEY
+	APIKey string `mapstructure:"api_key"`
+}
+
+// openAICompatibleAgents are the agent CLIs that can talk to an OpenAI-compatible endpoint.
+var openAICompatibleAgents = map[string]bool{"codex": true, "goose": true}
+
+// Enabled reports whether a custom endpoint is configured.
+func (l LLMEndpointConfig) Enabled() bool {
+	return l.BaseURL != ""
+}
+
+// Validate checks the endpoint configuration against the configured agent CLI.
+func (l LLMEndpointConfig) Validate(agentCLI string) error {
+	if !l.Enabled() {
+		if l.Model != "" || l.APIKey != "" {
+			return fmt.Errorf("llm_endpoint.base_url is required when llm_endpoint.model or llm_endpoint.api_key is set")
+		}
+		return nil
+	}
+	if err := validateHTTPURL("llm_endpoint.base_url", l.BaseURL); err != nil {
+		return err
+	}
+	if !openAICompatibleAgents[agentCLI] {
+		return fmt.Errorf("llm_endpoint requires an OpenAI-compatible agent_cli (codex or goose), got '%s'", agentCLI)
+	}
+	return nil
+}
+
+// Environment returns the variable assignments passed to the agent script. The
+// script forwards them to the agent CLI in its provider-specific form.
+func (l LLMEndpointConfig) Environment() []string {
+	if !l.Enabled() {
+		return nil
+	}
+	env := []string{
+		"LLM_PROVIDER=" + LLMProviderOpenAICompatible,
+		"LLM_BASE_URL=" + strings.TrimSuffix(l.BaseURL, "/"),
+	}
+	if l.APIKey != "" {
+		env = append(env, "LLM_API_KEY="+l.APIKey)
+	}
+	return env
+}
+
+// AzureOpenAIConfig runs the agent against an Azure OpenAI deployment.
+// Supported by the codex and goose agent CLIs.
+type AzureOpenAIConfig struct {
+	// Endpoint is the Azure OpenAI resource endpoint, e.g. "https://my-resource.openai.azure.com".
+	// Empty disables Azure OpenAI.
+	// Environment variable: AZURE_OPENAI_ENDPOINT
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Deployment is the model deployment name. It is used as the agent model.
+	// Environment variable: AZURE_OPENAI_DEPLOYMENT
+	Deployment string `mapstructure:"deployment"`
+
+	// APIKey is the Azure OpenAI resource key
+	// Environment variable: AZURE_OPENAI_API_KEY
+	APIKey string `mapstructure:"api_key"`
+
+	// APIVersion is the Azure OpenAI API version
+	// Default: "2025-04-01-preview"
+	// Environment variable: AZURE_OPENAI_API_VERSION
+	APIVersion string `mapstructure:"api_version"`
+}
+
+// Enabled reports whether Azure OpenAI is configured.
+func (a AzureOpenAIConfig) Enabled() bool {
+	return a.Endpoint != ""
+}
+
+// Validate checks the Azure OpenAI configuration against the configured agent CLI
+// and applies defaults.
+func (a *AzureOpenAIConfig) Validate(agentCLI string) error {
+	if !a.Enabled() {
+		return nil
+	}
+	if err := validateHTTPURL("azure_openai.endpoint", a.Endpoint); err != nil {
+		return err
+	}
+	if a.Deployment == "" {
+		return fmt.Errorf("azure_openai.deployment is required (environment variable: AZURE_OPENAI_DEPLOYMENT)")
+	}
+	if a.APIKey == "" {
+		return fmt.Errorf("azure_openai.api_key is required (environment variable: AZURE_OPENAI_API_KEY)")
+	}
+	if !openAICompatibleAgents[agentCLI] {
+		return fmt.Errorf("azure_openai requires agent_cli codex or goose, got '%s'", agentCLI)
+	}
+	if a.APIVersion == "" {
+		a.APIVersion = "2025-04-01-preview"
+	}
+	return nil
+}
+
+// Environment returns the variable assignments passed to the agent script.
+func (a AzureOpenAIConfig) Environment() []string {
+	if !a.Enabled() {
+		return nil
+	}
+	return []string{
+		"LLM_PROVIDER=" + LLMProviderAzureOpenAI,
+		"AZURE_OPENAI_ENDPOINT=" + strings.TrimSuffix(a.Endpoint, "/"),
+		"AZURE_OPENAI_DEPLOYMENT=" + a.Deployment,
+		"AZURE_OPENAI_API_KEY=" + a.APIKey,
+		"AZURE_OPENAI_API_VERSION=" + a.APIVersion,
+	}
+}
+
+// BedrockConfig runs the agent against AWS Bedrock. Credentials are either static
+// IAM keys or an IAM role assumed with a web identity token (e.g. EKS IRSA); when
+// neither is set the agent falls back to the AWS default credential chain.
+// Supported by the claude and goose agent CLIs.
+type BedrockConfig struct {
+	// Region is the AWS region hosting the Bedrock models. Setting it enables Bedrock.
+	// Environment variable: BEDROCK_REGION
+	Region string `mapstructure:"region"`
+
+	// AccessKeyID and SecretAccessKey are static IAM credentials
+	// Environment variables: BEDROCK_ACCESS_KEY_ID, BEDROCK_SECRET_ACCESS_KEY
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+
+	// SessionToken is the session token for temporary credentials
+	// Environment variable: BEDROCK_SESSION_TOKEN
+	SessionToken string `mapstructure:"session_token"`
+
+	// RoleARN is an IAM role assumed with WebIdentityTokenFile
+	// Environment variable: BEDROCK_ROLE_ARN
+	RoleARN string `mapstructure:"role_arn"`
+
+	// WebIdentityTokenFile is the projected service account token used to assume RoleARN.
+	// It is mounted read-only into the agent container.
+	// Environment variable: BEDROCK_WEB_IDENTITY_TOKEN_FILE
+	WebIdentityTokenFile string `mapstructure:"web_identity_token_file"`
+}
+
+// bedrockAgents are the agent CLIs that can use AWS Bedrock.
+var bedrockAgents = map[string]bool{"claude": true, "goose": true}
+
+// Enabled reports whether Bedrock is configured.
+func (b BedrockConfig) Enabled() bool {
+	return b.Region != ""
+}
+
+// Validate checks the Bedrock configuration against the configured agent CLI.
+func (b BedrockConfig) Validate(agentCLI string) error {
+	if !b.Enabled() {
+		return nil
+	}
+	if (b.AccessKeyID == "") != (b.SecretAccessKey == "") {
+		return fmt.Errorf("bedrock.access_key_id and bedrock.secret_access_key must be set together")
+	}
+	if b.RoleARN != "" && b.AccessKeyID != "" {
+		return fmt.Errorf("bedrock.role_arn and static bedrock.access_key_id credentials are mutually exclusive")
+	}
+	if b.RoleARN != "" && b.WebIdentityTokenFile == "" {
+		return fmt.Errorf("bedrock.web_identity_token_file is required (environment variable: BEDROCK_WEB_IDENTITY_TOKEN_FILE)")
+	}
+	if !bedrockAgents[agentCLI] {
+		return fmt.Errorf("bedrock requires agent_cli claude or goose, got '%s'", agentCLI)
+	}
+	return nil
+}
+
+// Environment returns the variable assignments passed to the agent script.
+func (b BedrockConfig) Environment() []string {
+	if !b.Enabled() {
+		return nil
+	}
+	env := []string{
+		"LLM_PROVIDER=" + LLMProviderBedrock,
+		"AWS_REGION=" + b.Region,
+	}
+	optional := []struct{ name, value string }{
+		{"AWS_ACCESS_KEY_ID", b.AccessKeyID},
+		{"AWS_SECRET_ACCESS_KEY", b.SecretAccessKey},
+		{"AWS_SESSION_TOKEN", b.SessionToken},
+		{"AWS_ROLE_ARN", b.RoleARN},
+		{"AWS_WEB_IDENTITY_TOKEN_FILE", b.WebIdentityTokenFile},
+	}
+	for _, v := range optional {
+		if v.value != "" {
+			env = append(env, v.name+"="+v.value)
+		}
+	}
+	return env
+}
+
+// LLMProvider returns the managed or self-hosted LLM provider in use, or "" when
+// the agent uses the provider's public API with an API key.
+func (c *Config) LLMProvider() string {
+	switch {
+	case c.LLMEndpoint.Enabled():
+		return LLMProviderOpenAICompatible
+	case c.AzureOpenAI.Enabled():
+		return LLMProviderAzureOpenAI
+	case c.Bedrock.Enabled():
+		return LLMProviderBedrock
+	}
+	return ""
+}
+
+// LLMProviderEnvironment returns the provider variable assignments passed to the agent script.
+func (c *Config) LLMProviderEnvironment() []string {
+	switch c.LLMProvider() {
+	case LLMProviderOpenAICompatible:
+		return c.LLMEndpoint.Environment()
+	case LLMProviderAzureOpenAI:
+		return c.AzureOpenAI.Environment()
+	case LLMProviderBedrock:
+		return c.Bedrock.Environment()
+	}
+	return nil
+}
+
+// EffectiveAgentModel returns the model the agent runs: the custom endpoint's model
+// or the Azure OpenAI deployment when configured, agent_model otherwise.
+func (c *Config) EffectiveAgentModel() string {
+	switch c.LLMProvider() {
+	case LLMProviderOpenAICompatible:
+		if c.LLMEndpoint.Model != "" {
+			return c.LLMEndpoint.Model
+		}
+	case LLMProviderAzureOpenAI:
+		return c.AzureOpenAI.Deployment
+	}
+	return c.AgentModel
+}
+
+// validateLLMProviders validates the configured LLM providers; at most one may be set.
+func (c *Config) validateLLMProviders() error {
+	enabled := 0
+	for _, on := range []bool{c.LLMEndpoint.Enabled(), c.AzureOpenAI.Enabled(), c.Bedrock.Enabled()} {
+		if on {
+			enabled++
+		}
+	}
+	if enabled > 1 {
+		return fmt.Errorf("only one of llm_endpoint, azure_openai, and bedrock may be configured")
+	}
+	if err := c.LLMEndpoint.Validate(c.AgentCLI); err != nil {
+		return err
+	}
+	if err := c.AzureOpenAI.Validate(c.AgentCLI); err != nil {
+		return err
+	}
+	return c.Bedrock.Validate(c.AgentCLI)
+}
+
+// validateHTTPURL checks that value is an absolute http:// or https:// URL.
+func validateHTTPURL(field, value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid %s '%s': must be an http:// or https:// URL", field, value)
+	}
+	return nil
+}
+
+// APIKeys returns the configured API keys per provider: the single *_api_key value
+// first, followed by the *_api_keys list. Empty entries are dropped.
+func (c *Config) APIKeys() map[string][]string {
+	keys := make(map[string][]string)
+	add := func(provider, single string, list []string) {
+		for _, k := range append([]string{single}, list...) {
+			if k = strings.TrimSpace(k); k != "" {
+				keys[provider] = append(keys[provider], k)
+			}
+		}
+	}
+	add(keypool.ProviderAnthropic, c.AnthropicAPIKey, c.AnthropicAPIKeys)
+	add(keypool.ProviderOpenAI, c.OpenAIAPIKey, c.OpenAIAPIKeys)
+	add(keypool.ProviderGemini, c.GeminiAPIKey, c.GeminiAPIKeys)
+	return keys
+}
+
+// LLMRateLimitConfig describes a provider's rate limits, used to pace agent launches.
+type LLMRateLimitConfig struct {
+	// RequestsPerMinute and TokensPerMinute are the provider limits for the account
+	// or deployment. 0 means unlimited.
+	RequestsPerMinute int `mapstructure:"requests_per_minute"`
+	TokensPerMinute   int `mapstructure:"tokens_per_minute"`
+
+	// RequestsPerInvestigation and TokensPerInvestigation estimate the usage of one
+	// investigation. Defaults: 25 requests, 150000 tokens.
+	RequestsPerInvestigation int `mapstructure:"requests_per_investigation"`
+	TokensPerInvestigation   int `mapstructure:"tokens_per_investigation"`
+}
+
+// ActiveLLMProvider returns the name of the LLM provider agents talk to, used to
+// key rate-limit pacing: a self-hosted or managed-cloud provider when configured,
+// otherwise the provider of the agent CLI (anthropic, openai, gemini), falling back
+// to the agent CLI name.
+func (c *Config) ActiveLLMProvider() string {
+	if provider := c.LLMProvider(); provider != "" {
+		return provider
+	}
+	if provider := keypool.ProviderForAgent(c.AgentCLI); provider != "" {
+		return provider
+	}
+	return c.AgentCLI
+}
+
+// PacingLimits returns the configured rate limits per provider.
+func (c *Config) PacingLimits() map[string]pacing.Limits {
+	limits := make(map[string]pacing.Limits, len(c.LLMRateLimits))
+	for provider, l := range c.LLMRateLimits {
+		limits[provider] = pacing.Limits{
+			RequestsPerMinute:        l.RequestsPerMinute,
+			TokensPerMinute:          l.TokensPerMinute,
+			RequestsPerInvestigation: l.RequestsPerInvestigation,
+			TokensPerInvestigation:   l.TokensPerInvestigation,
+		}
+	}
+	return limits
+}
+
+// validateLLMRateLimits checks that every rate limit value is non-negative.
+func (c *Config) validateLLMRateLimits() error {
+	for provider, l := range c.LLMRateLimits {
+		for field, v := range map[string]int{
+			"requests_per_minute":        l.RequestsPerMinute,
+			"tokens_per_minute":          l.TokensPerMinute,
+			"requests_per_investigation": l.RequestsPerInvestigation,
+			"tokens_per_investigation":   l.TokensPerInvestigation,
+		} {
+			if v < 0 {
+				return fmt.Errorf("llm_rate_limits.%s.%s must be >= 0, got %d", provider, field, v)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/enrichment"
+)
+
+const (
+	// defaultMCPEnrichmentTimeoutSeconds bounds collecting the context of a fault by default
+	defaultMCPEnrichmentTimeoutSeconds = 15
+	// maxMCPEnrichmentLogLines bounds the log tail kept per incident
+	maxMCPEnrichmentLogLines = 1000
+)
+
+// MCPEnrichmentConfig configures collecting fault context through the cluster's
+// MCP server for clusters without kubeconfig triage (triage.enabled: false). No
+// agent investigates their faults, so nightcrier itself calls the MCP server's
+// events_list and pods_log tools and records the affected object's recent events
+// and, for pods, the tail of its log as the "mcp-events" and "mcp-logs" incident
+// annotations.
+type MCPEnrichmentConfig struct {
+	// Enabled turns on MCP enrichment of incidents on triage-disabled clusters.
+	// Default: false
+	// Environment variable: MCP_ENRICHMENT_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// MaxEvents is how many of the object's most recent events are recorded.
+	// Default: 20
+	// Environment variable: MCP_ENRICHMENT_MAX_EVENTS
+	MaxEvents int `mapstructure:"max_events"`
+
+	// LogLines is how many lines of a pod's log are recorded (1-1000). Annotations
+	// are capped at 4096 characters, keeping the newest lines.
+	// Default: 50
+	// Environment variable: MCP_ENRICHMENT_LOG_LINES
+	LogLines int `mapstructure:"log_lines"`
+
+	// TimeoutSeconds bounds collecting the context of one fault.
+	// Default: 15
+	// Environment variable: MCP_ENRICHMENT_TIMEOUT_SECONDS
+	TimeoutSeconds int `mapstructure:"timeout_seconds"`
+}
+
+// Timeout returns the enrichment timeout.
+func (m MCPEnrichmentConfig) Timeout() time.Duration {
+	return time.Duration(m.TimeoutSeconds) * time.Second
+}
+
+// Validate applies the defaults and checks the limits.
+func (m *MCPEnrichmentConfig) Validate() error {
+	if !m.Enabled {
+		return nil
+	}
+	if m.MaxEvents == 0 {
+		m.MaxEvents = enrichment.DefaultMaxEvents
+	}
+	if m.LogLines == 0 {
+		m.LogLines = enrichment.DefaultLogLines
+	}
+	if m.TimeoutSeconds == 0 {
+		m.TimeoutSeconds = defaultMCPEnrichmentTimeoutSeconds
+	}
+	if m.MaxEvents < 1 {
+		return fmt.Errorf("mcp_enrichment.max_events must be positive, got %d", m.MaxEvents)
+	}
+	if m.LogLines < 1 || m.LogLines > maxMCPEnrichmentLogLines {
+		return fmt.Errorf("mcp_enrichment.log_lines must be between 1 and %d, got %d", maxMCPEnrichmentLogLines, m.LogLines)
+	}
+	if m.TimeoutSeconds < 1 {
+		return fmt.Errorf("mcp_enrichment.timeout_seconds must be positive, got %d", m.TimeoutSeconds)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default noise analysis settings
+const (
+	defaultNoiseIntervalHours  = 24
+	defaultNoiseWindowDays     = 7
+	defaultNoiseMinIncidents   = 10
+	defaultNoiseMaxSuggestions = 10
+)
+
+// NoiseAnalysisConfig configures the periodic review of incident history. Every
+// interval, the incidents of the window are grouped by cluster, namespace, and
+// fault type; groups whose investigations mostly repeated an identical fault on
+// the same resource or failed get a tuning suggestion (a longer dedup window, or a
+// filter). The suggestions are sent as a digest through the configured notifiers,
+// served on /health/noise, and shown by "nightcrier incidents noise". Requires a
+// sqlite or postgres state store.
+type NoiseAnalysisConfig struct {
+	// Enabled turns on the periodic noise analysis.
+	// Default: false
+	// Environment variable: NOISE_ANALYSIS_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// IntervalHours is how often the analysis runs and the digest is sent.
+	// Default: 24
+	// Environment variable: NOISE_ANALYSIS_INTERVAL_HOURS
+	IntervalHours int `mapstructure:"interval_hours"`
+
+	// WindowDays is the incident history analyzed.
+	// Default: 7
+	// Environment variable: NOISE_ANALYSIS_WINDOW_DAYS
+	WindowDays int `mapstructure:"window_days"`
+
+	// MinIncidents is the number of incidents a fault type must produce in a
+	// namespace within the window before it can be flagged as noisy.
+	// Default: 10
+	// Environment variable: NOISE_ANALYSIS_MIN_INCIDENTS
+	MinIncidents int `mapstructure:"min_incidents"`
+
+	// MaxSuggestions bounds the suggestions in a digest, noisiest first.
+	// Default: 10
+	// Environment variable: NOISE_ANALYSIS_MAX_SUGGESTIONS
+	MaxSuggestions int `mapstructure:"max_suggestions"`
+}
+
+// Interval returns how often the analysis runs.
+func (n NoiseAnalysisConfig) Interval() time.Duration {
+	return time.Duration(n.IntervalHours) * time.Hour
+}
+
+// Window returns the incident history analyzed.
+func (n NoiseAnalysisConfig) Window() time.Duration {
+	return time.Duration(n.WindowDays) * 24 * time.Hour
+}
+
+// Validate applies the defaults and checks the settings. The defaults apply even
+// when the periodic analysis is disabled, for the incidents noise command.
+func (n *NoiseAnalysisConfig) Validate(stateStorageType string) error {
+	for _, setting := range []struct {
+		name  string
+		value *int
+		def   int
+	}{
+		{"interval_hours", &n.IntervalHours, defaultNoiseIntervalHours},
+		{"window_days", &n.WindowDays, defaultNoiseWindowDays},
+		{"min_incidents", &n.MinIncidents, defaultNoiseMinIncidents},
+		{"max_suggestions", &n.MaxSuggestions, defaultNoiseMaxSuggestions},
+	} {
+		if *setting.value == 0 {
+			*setting.value = setting.def
+		}
+		if *setting.value < 1 {
+			return fmt.Errorf("noise_analysis.%s must be positive, got %d", setting.name, *setting.value)
+		}
+	}
+	if n.Enabled && stateStorageType != "sqlite" && stateStorageType != "postgres" {
+		return fmt.Errorf("noise_analysis requires a sqlite or postgres state store (state_storage.type is %q)", stateStorageType)
+	}
+	return nil
+}
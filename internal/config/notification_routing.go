@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultNotificationChannel names the channel made of the top-level
+// slack_webhook_url, discord_webhook_url, and mattermost_webhook_url when
+// notification routing is configured
+const defaultNotificationChannel = "default"
+
+// routableStatuses are the incident statuses notifications are sent for
+var routableStatuses = map[string]bool{
+	"pending": true, "suppressed": true, "awaiting_review": true, "resolved": true,
+	"failed": true, "agent_failed": true,
+}
+
+// NotificationRoutingConfig routes incident notifications to named channels by a
+// matrix of severity, cluster labels, and incident status, e.g. sending only
+// CRITICAL failures of prod clusters to the on-call channel and everything else to
+// the platform team's channel. Rules are tried in order and the first match
+// selects the channels; notifications matching no rule go to the default
+// channels. System alerts (circuit breaker, cluster connections, queues, budgets,
+// canary) always go to the default channels.
+//
+// The top-level slack_webhook_url, discord_webhook_url, and
+// mattermost_webhook_url form the channel named "default", which is also the
+// default route unless Default is set. Without channels and rules, notifications
+// go to the top-level webhooks as before.
+type NotificationRoutingConfig struct {
+	// Channels are the named notification destinations. Channel names are
+	// case-insensitive. Config file only.
+	Channels map[string]NotificationChannelConfig `mapstructure:"channels"`
+
+	// Default lists the channels of notifications matching no rule; an explicit
+	// empty list drops them. Default: ["default"] when the top-level webhooks are
+	// set. Config file only.
+	Default []string `mapstructure:"default"`
+
+	// Rules route matching notifications to their channels, first match wins.
+	// Config file only.
+	Rules []NotificationRouteRule `mapstructure:"rules"`
+}
+
+// NotificationChannelConfig is a named set of chat destinations.
+type NotificationChannelConfig struct {
+	SlackWebhookURL      string `mapstructure:"slack_webhook_url"`
+	DiscordWebhookURL    string `mapstructure:"discord_webhook_url"`
+	MattermostWebhookURL string `mapstructure:"mattermost_webhook_url"`
+	// MattermostChannel overrides the Mattermost webhook's channel
+	MattermostChannel string `mapstructure:"mattermost_channel"`
+}
+
+// empty reports whether the channel has no destination.
+func (n NotificationChannelConfig) empty() bool {
+	return n.SlackWebhookURL == "" && n.DiscordWebhookURL == "" && n.MattermostWebhookURL == ""
+}
+
+// NotificationRouteRule routes the notifications matching all of its criteria.
+// Empty criteria match everything.
+type NotificationRouteRule struct {
+	// Severity matches the fault severity (DEBUG, INFO, WARNING, ERROR, CRITICAL)
+	Severity []string `mapstructure:"severity"`
+	// ClusterLabels must all be set, with these values, in the cluster's labels
+	ClusterLabels map[string]string `mapstructure:"cluster_labels"`
+	// Status matches the incident status: resolved, failed, agent_failed, or
+	// pending (fault events recorded on serve-only clusters)
+	Status []string `mapstructure:"status"`
+	// Channels receive the matching notifications
+	Channels []string `mapstructure:"channels"`
+}
+
+// Enabled reports whether notification routing is configured.
+func (n NotificationRoutingConfig) Enabled() bool {
+	return len(n.Channels) > 0 || len(n.Rules) > 0 || n.Default != nil
+}
+
+// NotificationChannels returns the routing channels, including the "default"
+// channel made of the top-level webhooks when they are set.
+func (c *Config) NotificationChannels() map[string]NotificationChannelConfig {
+	channels := make(map[string]NotificationChannelConfig, len(c.NotificationRouting.Channels)+1)
+	for name, channel := range c.NotificationRouting.Channels {
+		channels[strings.ToLower(name)] = channel
+	}
+	legacy := NotificationChannelConfig{
+		SlackWebhookURL:      c.SlackWebhookURL,
+		DiscordWebhookURL:    c.DiscordWebhookURL,
+		MattermostWebhookURL: c.MattermostWebhookURL,
+		MattermostChannel:    c.MattermostChannel,
+	}
+	if !legacy.empty() {
+		channels[defaultNotificationChannel] = legacy
+	}
+	return channels
+}
+
+// DefaultNotificationChannels returns the channels of notifications matching no
+// routing rule.
+func (c *Config) DefaultNotificationChannels() []string {
+	if c.NotificationRouting.Default != nil {
+		return lowerAll(c.NotificationRouting.Default)
+	}
+	if _, ok := c.NotificationChannels()[defaultNotificationChannel]; ok {
+		return []string{defaultNotificationChannel}
+	}
+	return nil
+}
+
+// lowerAll returns the values lowercased.
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, v := range values {
+		lowered[i] = strings.ToLower(v)
+	}
+	return lowered
+}
+
+// validateNotificationRouting checks that channels have destinations with valid
+// URLs and that rules and defaults only name known channels.
+func (c *Config) validateNotificationRouting() error {
+	routing := c.NotificationRouting
+	if !routing.Enabled() {
+		return nil
+	}
+	for name, channel := range routing.Channels {
+		if strings.EqualFold(name, defaultNotificationChannel) && (c.SlackWebhookURL != "" || c.DiscordWebhookURL != "" || c.MattermostWebhookURL != "") {
+			return fmt.Errorf("notification_routing.channels.%s conflicts with the top-level webhooks, which form the %q channel", name, defaultNotificationChannel)
+		}
+		if channel.empty() {
+			return fmt.Errorf("notification_routing.channels.%s needs slack_webhook_url, discord_webhook_url, or mattermost_webhook_url", name)
+		}
+		for field, value := range map[string]string{
+			"slack_webhook_url":      channel.SlackWebhookURL,
+			"discord_webhook_url":    channel.DiscordWebhookURL,
+			"mattermost_webhook_url": channel.MattermostWebhookURL,
+		} {
+			if value == "" {
+				continue
+			}
+			if err := validateHTTPURL(fmt.Sprintf("notification_routing.channels.%s.%s", name, field), value); err != nil {
+				return err
+			}
+		}
+	}
+
+	channels := c.NotificationChannels()
+	checkChannels := func(field string, names []string) error {
+		for _, name := range names {
+			if _, ok := channels[strings.ToLower(name)]; !ok {
+				return fmt.Errorf("%s: unknown channel %q (known: %s)", field, name, strings.Join(sortedKeys(channels), ", "))
+			}
+		}
+		return nil
+	}
+	if err := checkChannels("notification_routing.default", routing.Default); err != nil {
+		return err
+	}
+	for i, rule := range routing.Rules {
+		field := fmt.Sprintf("notification_routing.rules[%d]", i)
+		if len(rule.Channels) == 0 {
+			return fmt.Errorf("%s.channels is required", field)
+		}
+		if err := checkChannels(field+".channels", rule.Channels); err != nil {
+			return err
+		}
+		for _, severity := range rule.Severity {
+			if !validSeverities[strings.ToUpper(severity)] {
+				return fmt.Errorf("%s.severity: invalid severity %q (want DEBUG, INFO, WARNING, ERROR, or CRITICAL)", field, severity)
+			}
+		}
+		for _, status := range rule.Status {
+			if !routableStatuses[strings.ToLower(status)] {
+				return fmt.Errorf("%s.status: invalid status %q (want resolved, failed, agent_failed, or pending)", field, status)
+			}
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a channel map in order.
+func sortedKeys(channels map[string]NotificationChannelConfig) []string {
+	keys := make([]string, 0, len(channels))
+	for name := range channels {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
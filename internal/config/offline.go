@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// internalSuffixes are DNS suffixes that never resolve on the public internet.
+var internalSuffixes = []string{".local", ".internal", ".lan", ".corp", ".home.arpa", ".svc", ".cluster.local"}
+
+// OfflineConfig enables air-gapped operation. Nightcrier makes no external fetches:
+// skill bundles and runbooks are only loaded from their (pre-seeded) cache
+// directories, periodic updates are refused, and the agent CLIs' update checks and
+// telemetry are turned off. Settings that need internet egress (hosted LLM APIs,
+// SaaS webhooks and integrations, cloud storage) fail validation, so the agent
+// must use an internal LLM endpoint (llm_endpoint, or azure_openai at an
+// internal host).
+type OfflineConfig struct {
+	// Enabled turns on offline mode.
+	// Default: false
+	// Environment variable: OFFLINE_MODE
+	Enabled bool `mapstructure:"enabled"`
+
+	// InternalDomains are DNS suffixes of hosts reachable without egress, e.g.
+	// "corp.example.com". Private and loopback IPs, single-label names, and .local,
+	// .internal, .lan, .corp, .home.arpa, and .svc names are always internal.
+	// Environment variable: OFFLINE_INTERNAL_DOMAINS (comma-separated)
+	InternalDomains []string `mapstructure:"internal_domains"`
+}
+
+// IsInternalHost reports whether host is reachable without internet egress.
+func (o OfflineConfig) IsInternalHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast()
+	}
+	if host == "localhost" || !strings.Contains(host, ".") {
+		return true
+	}
+	for _, suffix := range internalSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	for _, domain := range o.InternalDomains {
+		domain = strings.TrimPrefix(strings.ToLower(domain), ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInternalURL reports whether a URL's host is internal. Unparseable URLs are
+// treated as external.
+func (o OfflineConfig) isInternalURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	return o.IsInternalHost(u.Hostname())
+}
+
+// EgressRequirements lists the configured settings that need internet egress, as
+// "setting: reason". Hosts are classified with the offline internal domains.
+func (c *Config) EgressRequirements() []string {
+	o := c.Offline
+	var found []string
+	add := func(setting, reason string) {
+		found = append(found, setting+": "+reason)
+	}
+	checkURL := func(setting, raw string) {
+		if raw != "" && !o.isInternalURL(raw) {
+			add(setting, "external host "+hostOf(raw))
+		}
+	}
+
+	// LLM: the agent must reach an internal endpoint
+	switch c.LLMProvider() {
+	case LLMProviderOpenAICompatible:
+		checkURL("llm_endpoint.base_url", c.LLMEndpoint.BaseURL)
+	case LLMProviderAzureOpenAI:
+		checkURL("azure_openai.endpoint", c.AzureOpenAI.Endpoint)
+	case LLMProviderBedrock:
+		add("bedrock", "AWS Bedrock is a hosted LLM API; use llm_endpoint")
+	default:
+		add("agent_cli", fmt.Sprintf("%s uses its vendor's hosted LLM API; configure llm_endpoint", c.AgentCLI))
+	}
+
+	// Notifications and integrations
+	checkURL("slack_webhook_url", c.SlackWebhookURL)
+	checkURL("discord_webhook_url", c.DiscordWebhookURL)
+	checkURL("mattermost_webhook_url", c.MattermostWebhookURL)
+	checkURL("servicenow.instance_url", c.ServiceNow.InstanceURL)
+	checkURL("knowledge_base.confluence.base_url", c.KnowledgeBase.Confluence.BaseURL)
+	checkURL("ownership.backstage.url", c.Ownership.Backstage.URL)
+	checkURL("ownership.cmdb.url", c.Ownership.CMDB.URL)
+	checkURL("url_shortener.url", c.URLShortener.URL)
+	for i, route := range c.Ownership.Routes {
+		checkURL(fmt.Sprintf("ownership.routes[%d].slack_webhook_url", i), route.SlackWebhookURL)
+		checkURL(fmt.Sprintf("ownership.routes[%d].discord_webhook_url", i), route.DiscordWebhookURL)
+		checkURL(fmt.Sprintf("ownership.routes[%d].mattermost_webhook_url", i), route.MattermostWebhookURL)
+	}
+	for _, name := range sortedKeys(c.NotificationRouting.Channels) {
+		channel := c.NotificationRouting.Channels[name]
+		checkURL(fmt.Sprintf("notification_routing.channels.%s.slack_webhook_url", name), channel.SlackWebhookURL)
+		checkURL(fmt.Sprintf("notification_routing.channels.%s.discord_webhook_url", name), channel.DiscordWebhookURL)
+		checkURL(fmt.Sprintf("notification_routing.channels.%s.mattermost_webhook_url", name), channel.MattermostWebhookURL)
+	}
+	if c.Verification.Enabled && c.Verification.CheckRegistries {
+		add("verification.check_registries", "image tags are looked up in their registries, usually external hosts")
+	}
+	if c.SupplyChain.Enabled() && c.SupplyChain.Key == "" {
+		add("supply_chain", "keyless signatures are verified against the public Sigstore certificate authority and transparency log; use a key")
+	}
+	if c.SlackApp.Enabled() {
+		add("slack_app", "the Slack app connects to the hosted Slack API")
+	}
+	if c.KnowledgeBase.Notion.Enabled() {
+		add("knowledge_base.notion", "Notion is a hosted service")
+	}
+	if c.Postmortem.Enabled() {
+		if c.Postmortem.APIURL == "" {
+			add("postmortem.api_url", "defaults to the hosted "+c.Postmortem.Provider+" API")
+		} else {
+			checkURL("postmortem.api_url", c.Postmortem.APIURL)
+		}
+	}
+
+	// Artifact storage: Azure is only internal behind a custom (e.g. Azurite) endpoint
+	if c.IsAzureStorageEnabled() {
+		if endpoint := azureBlobEndpoint(c.AzureStorageConnectionString); endpoint != "" {
+			checkURL("azure_storage_connection_string", endpoint)
+		} else {
+			add("azure_storage", "Azure Blob Storage is a hosted service")
+		}
+	}
+
+	// Bundle updates fetch from the bundles' sources
+	if c.Skills.UpdateIntervalMinutes > 0 {
+		add("skills.update_interval_minutes", "periodic updates fetch skill bundles from their sources")
+	}
+	if c.Runbooks.UpdateIntervalMinutes > 0 {
+		add("runbooks.update_interval_minutes", "periodic updates fetch the runbooks repository")
+	}
+	return found
+}
+
+// validateOffline rejects settings that need egress when offline mode is enabled.
+func (c *Config) validateOffline() error {
+	if !c.Offline.Enabled {
+		return nil
+	}
+	found := c.EgressRequirements()
+	if len(found) == 0 {
+		return nil
+	}
+	return fmt.Errorf("offline mode is enabled but these settings require internet egress (add internal hosts to offline.internal_domains):\n  - %s",
+		strings.Join(found, "\n  - "))
+}
+
+// azureBlobEndpoint returns the BlobEndpoint of an Azure connection string, if set.
+func azureBlobEndpoint(connectionString string) string {
+	for _, part := range strings.Split(connectionString, ";") {
+		if key, value, ok := strings.Cut(part, "="); ok && strings.EqualFold(key, "BlobEndpoint") {
+			return value
+		}
+	}
+	return ""
+}
+
+// hostOf returns the host of a URL, or the URL itself when it cannot be parsed.
+func hostOf(raw string) string {
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return raw
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// defaultOperatorPolicyName is the InvestigationPolicy applied when none is named
+	defaultOperatorPolicyName = "default"
+	// defaultOperatorResyncSeconds is how often custom resources are checked for changes
+	defaultOperatorResyncSeconds = 30
+)
+
+// OperatorConfig runs nightcrier as a Kubernetes operator: cluster targets and
+// the investigation policy are declared as custom resources (ClusterTarget and
+// InvestigationPolicy, group nightcrier.io) in nightcrier's namespace instead of
+// in the config file, and every incident is recorded as an Incident resource whose
+// status follows the investigation. The clusters array of the config file is
+// ignored. When the declared resources change, nightcrier exits so its Deployment
+// restarts it with the new configuration.
+type OperatorConfig struct {
+	// Enabled turns on operator mode. Requires running in a pod with the CRDs
+	// from deploy/operator installed.
+	// Default: false
+	// Environment variable: OPERATOR_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// Namespace is where ClusterTarget, InvestigationPolicy, and Incident
+	// resources live.
+	// Default: POD_NAMESPACE, else the service account's namespace
+	// Environment variable: OPERATOR_NAMESPACE
+	Namespace string `mapstructure:"namespace"`
+
+	// PolicyName is the InvestigationPolicy to apply. When it does not exist, the
+	// config file's settings are used.
+	// Default: "default"
+	// Environment variable: OPERATOR_POLICY_NAME
+	PolicyName string `mapstructure:"policy_name"`
+
+	// ResyncSeconds is how often the resources are checked for changes.
+	// Default: 30
+	// Environment variable: OPERATOR_RESYNC_SECONDS
+	ResyncSeconds int `mapstructure:"resync_seconds"`
+
+	// DisableIncidentResources stops creating an Incident resource per incident
+	// (e.g. when the state store is the system of record and etcd churn matters).
+	// Default: false
+	// Environment variable: OPERATOR_DISABLE_INCIDENT_RESOURCES
+	DisableIncidentResources bool `mapstructure:"disable_incident_resources"`
+}
+
+// Validate applies the defaults and checks the operator settings.
+func (o *OperatorConfig) Validate() error {
+	if !o.Enabled {
+		return nil
+	}
+	if o.Namespace == "" {
+		o.Namespace = os.Getenv("POD_NAMESPACE")
+	}
+	if o.Namespace == "" {
+		if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+			o.Namespace = strings.TrimSpace(string(data))
+		}
+	}
+	if o.PolicyName == "" {
+		o.PolicyName = defaultOperatorPolicyName
+	}
+	if o.ResyncSeconds == 0 {
+		o.ResyncSeconds = defaultOperatorResyncSeconds
+	}
+
+	if o.Namespace == "" {
+		return fmt.Errorf("operator.namespace is required when not running in a pod (set it or POD_NAMESPACE)")
+	}
+	if !kubeNamePattern.MatchString(o.Namespace) {
+		return fmt.Errorf("operator.namespace %q is not a valid Kubernetes name", o.Namespace)
+	}
+	if !kubeNamePattern.MatchString(o.PolicyName) {
+		return fmt.Errorf("operator.policy_name %q is not a valid Kubernetes name", o.PolicyName)
+	}
+	if o.ResyncSeconds < 0 {
+		return fmt.Errorf("operator.resync_seconds must be positive, got %d", o.ResyncSeconds)
+	}
+	return nil
+}
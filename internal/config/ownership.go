@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/ownership"
+)
+
+// defaultOwnershipCacheMinutes is how long resolved owners are cached by default
+const defaultOwnershipCacheMinutes = 15
+
+// OwnershipConfig configures resolving the team and service owning the workload
+// affected by a fault. Sources are queried in order (annotations, Backstage, CMDB)
+// and the first that knows the workload wins. The owner is recorded on the
+// incident (incident.json and the "team" and "service" labels, unless already set
+// by cluster labels or label rules), included in the agent prompt and
+// notifications, and selects the team's notification route.
+type OwnershipConfig struct {
+	// Annotations reads owners from the annotations and labels of the affected
+	// object, its workload, and its namespace through the cluster's MCP server.
+	// Default: false
+	// Environment variable: OWNERSHIP_ANNOTATIONS
+	Annotations bool `mapstructure:"annotations"`
+
+	// TeamKeys, ServiceKeys, and ContactKeys are the annotation and label keys
+	// holding the owner, most specific first. Default: see ownership.DefaultTeamKeys
+	// and friends. Config file only.
+	TeamKeys    []string `mapstructure:"team_keys"`
+	ServiceKeys []string `mapstructure:"service_keys"`
+	ContactKeys []string `mapstructure:"contact_keys"`
+
+	// Backstage looks up the component owning the workload in a Backstage catalog
+	Backstage BackstageOwnershipConfig `mapstructure:"backstage"`
+
+	// CMDB looks up owners with an HTTP GET to a CMDB or ownership API
+	CMDB CMDBOwnershipConfig `mapstructure:"cmdb"`
+
+	// CacheMinutes is how long owners (and unknown owners) are cached per workload.
+	// Default: 15
+	// Environment variable: OWNERSHIP_CACHE_MINUTES
+	CacheMinutes int `mapstructure:"cache_minutes"`
+
+	// Routes send the incident notifications of owning teams to the teams' own
+	// destinations. Config file only.
+	Routes []OwnerRouteConfig `mapstructure:"routes"`
+}
+
+// BackstageOwnershipConfig configures the Backstage ownership source.
+type BackstageOwnershipConfig struct {
+	// URL is the Backstage base URL. Empty disables the source.
+	// Environment variable: OWNERSHIP_BACKSTAGE_URL
+	URL string `mapstructure:"url"`
+
+	// Token is a Backstage API token (optional)
+	// Environment variable: OWNERSHIP_BACKSTAGE_TOKEN
+	Token string `mapstructure:"token"`
+}
+
+// CMDBOwnershipConfig configures the CMDB ownership source.
+type CMDBOwnershipConfig struct {
+	// URL is the lookup URL, with the placeholders {cluster}, {namespace}, {kind},
+	// {name}, and {workload}. Empty disables the source.
+	// Environment variable: OWNERSHIP_CMDB_URL
+	URL string `mapstructure:"url"`
+
+	// Token is sent as a bearer token (optional)
+	// Environment variable: OWNERSHIP_CMDB_TOKEN
+	Token string `mapstructure:"token"`
+
+	// TeamField, ServiceField, ContactField, and URLField are the dotted paths of
+	// the owner fields in the JSON response. Defaults: "team", "service",
+	// "contact", "url". Config file only.
+	TeamField    string `mapstructure:"team_field"`
+	ServiceField string `mapstructure:"service_field"`
+	ContactField string `mapstructure:"contact_field"`
+	URLField     string `mapstructure:"url_field"`
+}
+
+// OwnerRouteConfig sends the incident notifications of an owning team to its own
+// chat destinations.
+type OwnerRouteConfig struct {
+	// Team is the owning team, compared case-insensitively
+	Team string `mapstructure:"team"`
+
+	// SlackWebhookURL, DiscordWebhookURL, and MattermostWebhookURL are the team's
+	// destinations; at least one is required. MattermostChannel overrides the
+	// webhook's channel.
+	SlackWebhookURL      string `mapstructure:"slack_webhook_url"`
+	DiscordWebhookURL    string `mapstructure:"discord_webhook_url"`
+	MattermostWebhookURL string `mapstructure:"mattermost_webhook_url"`
+	MattermostChannel    string `mapstructure:"mattermost_channel"`
+
+	// Exclusive sends the team's incidents only to the team's destinations instead
+	// of also to the default ones
+	Exclusive bool `mapstructure:"exclusive"`
+}
+
+// Enabled reports whether any ownership source is configured.
+func (o OwnershipConfig) Enabled() bool {
+	return o.Annotations || o.Backstage.URL != "" || o.CMDB.URL != ""
+}
+
+// Resolver returns the resolver querying the configured sources. endpoints maps
+// cluster names to their MCP server endpoints for the annotations source.
+func (o OwnershipConfig) Resolver(endpoints map[string]string, httpClient *http.Client) *ownership.Resolver {
+	var sources []ownership.Source
+	if o.Annotations {
+		sources = append(sources, ownership.NewAnnotationsSource(ownership.AnnotationsConfig{
+			Endpoints:   endpoints,
+			TeamKeys:    o.TeamKeys,
+			ServiceKeys: o.ServiceKeys,
+			ContactKeys: o.ContactKeys,
+		}, httpClient))
+	}
+	if o.Backstage.URL != "" {
+		sources = append(sources, ownership.NewBackstageSource(o.Backstage.URL, o.Backstage.Token, httpClient))
+	}
+	if o.CMDB.URL != "" {
+		sources = append(sources, ownership.NewCMDBSource(ownership.CMDBConfig{
+			URL:          o.CMDB.URL,
+			Token:        o.CMDB.Token,
+			TeamField:    o.CMDB.TeamField,
+			ServiceField: o.CMDB.ServiceField,
+			ContactField: o.CMDB.ContactField,
+			URLField:     o.CMDB.URLField,
+		}, httpClient))
+	}
+	return ownership.NewResolver(time.Duration(o.CacheMinutes)*time.Minute, sources...)
+}
+
+// Validate applies defaults and checks the sources and routes.
+func (o *OwnershipConfig) Validate() error {
+	if o.CacheMinutes == 0 {
+		o.CacheMinutes = defaultOwnershipCacheMinutes
+	}
+	if o.CacheMinutes < 0 {
+		return fmt.Errorf("ownership.cache_minutes must be positive, got %d", o.CacheMinutes)
+	}
+	if o.Backstage.URL != "" {
+		if err := validateHTTPURL("ownership.backstage.url", o.Backstage.URL); err != nil {
+			return err
+		}
+	}
+	if o.CMDB.URL != "" {
+		if err := validateHTTPURL("ownership.cmdb.url", o.CMDB.URL); err != nil {
+			return err
+		}
+	}
+	if len(o.Routes) > 0 && !o.Enabled() {
+		return fmt.Errorf("ownership.routes requires an ownership source (ownership.annotations, ownership.backstage.url, or ownership.cmdb.url)")
+	}
+
+	seen := make(map[string]bool)
+	for i, route := range o.Routes {
+		team := strings.ToLower(route.Team)
+		if team == "" {
+			return fmt.Errorf("ownership.routes[%d].team is required", i)
+		}
+		if seen[team] {
+			return fmt.Errorf("ownership.routes[%d]: duplicate route for team %q", i, route.Team)
+		}
+		seen[team] = true
+		if route.SlackWebhookURL == "" && route.DiscordWebhookURL == "" && route.MattermostWebhookURL == "" {
+			return fmt.Errorf("ownership.routes[%d] (team %s) needs slack_webhook_url, discord_webhook_url, or mattermost_webhook_url", i, route.Team)
+		}
+		for field, value := range map[string]string{
+			"slack_webhook_url":      route.SlackWebhookURL,
+			"discord_webhook_url":    route.DiscordWebhookURL,
+			"mattermost_webhook_url": route.MattermostWebhookURL,
+		} {
+			if value == "" {
+				continue
+			}
+			if err := validateHTTPURL(fmt.Sprintf("ownership.routes[%d].%s", i, field), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/rbias/nightcrier/internal/postmortem"
+)
+
+// PostmortemConfig configures publishing finished investigations to a Git
+// repository. The report and findings.json are committed under a dated path and a
+// pull request (GitHub) or merge request (GitLab) is opened for review.
+type PostmortemConfig struct {
+	// Provider is "github" or "gitlab". Empty disables publishing.
+	// Environment variable: POSTMORTEM_PROVIDER
+	Provider string `mapstructure:"provider"`
+
+	// APIURL overrides the provider API URL for GitHub Enterprise or self-managed GitLab
+	// Default: https://api.github.com or https://gitlab.com/api/v4
+	// Environment variable: POSTMORTEM_API_URL
+	APIURL string `mapstructure:"api_url"`
+
+	// Repo is "owner/name" on GitHub or the project path on GitLab (e.g. "sre/postmortems")
+	// Environment variable: POSTMORTEM_REPO
+	Repo string `mapstructure:"repo"`
+
+	// Token is an API token allowed to push branches and open pull requests
+	// Environment variable: POSTMORTEM_GIT_TOKEN
+	Token string `mapstructure:"token"`
+
+	// BaseBranch is the branch postmortems are merged into
+	// Default: "main"
+	// Environment variable: POSTMORTEM_BASE_BRANCH
+	BaseBranch string `mapstructure:"base_branch"`
+
+	// Path is the repository directory postmortems are written under, as
+	// <path>/<yyyy>/<mm>/<date>-<cluster>-<fault>-<id>/
+	// Default: "postmortems"
+	// Environment variable: POSTMORTEM_PATH
+	Path string `mapstructure:"path"`
+
+	// BranchPrefix prefixes the branch created for each postmortem
+	// Default: "nightcrier/postmortem-"
+	// Environment variable: POSTMORTEM_BRANCH_PREFIX
+	BranchPrefix string `mapstructure:"branch_prefix"`
+
+	// DirectCommit commits to BaseBranch instead of opening a pull request
+	// Default: false
+	// Environment variable: POSTMORTEM_DIRECT_COMMIT
+	DirectCommit bool `mapstructure:"direct_commit"`
+}
+
+// Enabled reports whether postmortem publishing is configured.
+func (p PostmortemConfig) Enabled() bool {
+	return p.Provider != ""
+}
+
+// PublisherConfig returns the settings for a postmortem.Publisher.
+func (p PostmortemConfig) PublisherConfig() postmortem.Config {
+	return postmortem.Config{
+		Provider:          p.Provider,
+		APIURL:            p.APIURL,
+		Repo:              p.Repo,
+		Token:             p.Token,
+		BaseBranch:        p.BaseBranch,
+		Path:              p.Path,
+		BranchPrefix:      p.BranchPrefix,
+		CreatePullRequest: !p.DirectCommit,
+	}
+}
+
+// Validate applies defaults and checks the publishing settings.
+func (p *PostmortemConfig) Validate() error {
+	if !p.Enabled() {
+		return nil
+	}
+	if p.Provider != postmortem.ProviderGitHub && p.Provider != postmortem.ProviderGitLab {
+		return fmt.Errorf("invalid postmortem.provider '%s': must be github or gitlab", p.Provider)
+	}
+	if p.Repo == "" {
+		return fmt.Errorf("postmortem.repo is required when postmortem.provider is set (environment variable: POSTMORTEM_REPO)")
+	}
+	if p.Token == "" {
+		return fmt.Errorf("postmortem.token is required when postmortem.provider is set (environment variable: POSTMORTEM_GIT_TOKEN)")
+	}
+	if p.APIURL != "" {
+		if err := validateHTTPURL("postmortem.api_url", p.APIURL); err != nil {
+			return err
+		}
+	}
+	if p.BaseBranch == "" {
+		p.BaseBranch = "main"
+	}
+	if p.Path == "" {
+		p.Path = "postmortems"
+	}
+	if p.BranchPrefix == "" {
+		p.BranchPrefix = "nightcrier/postmortem-"
+	}
+	return nil
+}
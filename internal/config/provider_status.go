@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Provider status defaults
+const (
+	defaultProviderStatusIntervalSeconds      = 60
+	defaultProviderStatusIndicator            = "major"
+	defaultProviderStatusFailureThreshold     = 3
+	defaultProviderStatusFailureWindowMinutes = 10
+)
+
+// ProviderStatusConfig configures the provider status monitor, which suspects an
+// outage of the agent's LLM provider (Anthropic, OpenAI) from the provider's
+// status page or from recent investigations failing with provider_unavailable.
+// While an outage is suspected the circuit breaker is opened, the degraded alert
+// notes "provider outage suspected", and new investigations are held pending
+// instead of burning retries blind.
+type ProviderStatusConfig struct {
+	// Enabled turns on the provider status monitor.
+	// Default: false
+	// Environment variable: PROVIDER_STATUS_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// IntervalSeconds is how often the status pages are checked.
+	// Default: 60
+	// Environment variable: PROVIDER_STATUS_INTERVAL_SECONDS
+	IntervalSeconds int `mapstructure:"interval_seconds"`
+
+	// StatusPages maps providers (anthropic, openai) to the URL of their
+	// Statuspage status API (/api/v2/status.json). An empty URL disables the
+	// status page of a provider.
+	// Default: the public status pages of Anthropic and OpenAI
+	// Config file only.
+	StatusPages map[string]string `mapstructure:"status_pages"`
+
+	// Indicator is the lowest status page indicator that suspects an outage:
+	// "minor", "major", or "critical".
+	// Default: "major"
+	// Environment variable: PROVIDER_STATUS_INDICATOR
+	Indicator string `mapstructure:"indicator"`
+
+	// FailureThreshold is the number of investigations failing with
+	// provider_unavailable within the failure window that suspects an outage.
+	// -1 disables the failure signal.
+	// Default: 3
+	// Environment variable: PROVIDER_STATUS_FAILURE_THRESHOLD
+	FailureThreshold int `mapstructure:"failure_threshold"`
+
+	// FailureWindowMinutes is how long provider failures count towards the
+	// threshold; the failure signal ends once none are left in the window.
+	// Default: 10
+	// Environment variable: PROVIDER_STATUS_FAILURE_WINDOW_MINUTES
+	FailureWindowMinutes int `mapstructure:"failure_window_minutes"`
+
+	// KeepLaunching keeps launching investigations during a suspected outage; only
+	// the circuit breaker and notifications reflect it.
+	// Default: false
+	// Environment variable: PROVIDER_STATUS_KEEP_LAUNCHING
+	KeepLaunching bool `mapstructure:"keep_launching"`
+}
+
+// Interval returns how often the status pages are checked.
+func (p ProviderStatusConfig) Interval() time.Duration {
+	return time.Duration(p.IntervalSeconds) * time.Second
+}
+
+// FailureWindow returns how long provider failures count towards the threshold.
+func (p ProviderStatusConfig) FailureWindow() time.Duration {
+	return time.Duration(p.FailureWindowMinutes) * time.Minute
+}
+
+// Validate applies the defaults and checks the settings.
+func (p *ProviderStatusConfig) Validate() error {
+	if p.IntervalSeconds == 0 {
+		p.IntervalSeconds = defaultProviderStatusIntervalSeconds
+	}
+	if p.Indicator == "" {
+		p.Indicator = defaultProviderStatusIndicator
+	}
+	if p.FailureThreshold == 0 {
+		p.FailureThreshold = defaultProviderStatusFailureThreshold
+	}
+	if p.FailureWindowMinutes == 0 {
+		p.FailureWindowMinutes = defaultProviderStatusFailureWindowMinutes
+	}
+
+	if p.IntervalSeconds < 1 {
+		return fmt.Errorf("provider_status.interval_seconds must be positive, got %d", p.IntervalSeconds)
+	}
+	switch p.Indicator {
+	case "minor", "major", "critical":
+	default:
+		return fmt.Errorf("provider_status.indicator must be minor, major, or critical, got %q", p.Indicator)
+	}
+	if p.FailureThreshold < -1 {
+		return fmt.Errorf("provider_status.failure_threshold must be positive or -1, got %d", p.FailureThreshold)
+	}
+	if p.FailureWindowMinutes < 1 {
+		return fmt.Errorf("provider_status.failure_window_minutes must be positive, got %d", p.FailureWindowMinutes)
+	}
+	return nil
+}
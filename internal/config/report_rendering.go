@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Report renderers
+const (
+	// ReportRendererGFM renders GitHub Flavored Markdown: tables, task lists,
+	// strikethrough, autolinks, and lists without a preceding blank line
+	ReportRendererGFM = "gfm"
+	// ReportRendererBasic renders the markdown dialect of earlier releases (no
+	// task lists)
+	ReportRendererBasic = "basic"
+)
+
+// ReportRenderingConfig selects how agent-produced investigation reports are
+// converted from markdown to HTML for the stored report page and for knowledge
+// base publishing. Whatever the renderer, the HTML is sanitized with a strict
+// allowlist of elements, attributes, and URL schemes: raw HTML in a report cannot
+// inject scripts, event handlers, styles, or javascript: links into the page
+// served from the dashboard or artifact storage.
+type ReportRenderingConfig struct {
+	// Renderer is "gfm" or "basic".
+	// Default: "gfm"
+	// Environment variable: REPORT_RENDERER
+	Renderer string `mapstructure:"renderer"`
+}
+
+// Validate applies the default renderer and checks it is known.
+func (r *ReportRenderingConfig) Validate() error {
+	r.Renderer = strings.ToLower(r.Renderer)
+	switch r.Renderer {
+	case "":
+		r.Renderer = ReportRendererGFM
+	case ReportRendererGFM, ReportRendererBasic:
+	default:
+		return fmt.Errorf("report_rendering.renderer must be %q or %q, got %q", ReportRendererGFM, ReportRendererBasic, r.Renderer)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rbias/nightcrier/internal/runbooks"
+	"github.com/rbias/nightcrier/internal/skills"
+)
+
+// runbooksBundleName is the directory of the runbooks git checkout under CacheDir.
+const runbooksBundleName = "runbooks"
+
+// RunbooksConfig configures organization-specific markdown runbooks that are
+// attached to investigations by fault type or resource kind. Runbooks come from a
+// local directory, or from a git repository cloned into CacheDir.
+type RunbooksConfig struct {
+	// Dir is the local runbooks directory. With Git set, it is the subdirectory of
+	// the repository holding the runbooks (default: the repository root).
+	// Environment variable: RUNBOOKS_DIR
+	Dir string `mapstructure:"dir"`
+
+	// Git is a repository containing the runbooks; Ref optionally selects a branch,
+	// tag, or commit.
+	// Environment variables: RUNBOOKS_GIT, RUNBOOKS_GIT_REF
+	Git string `mapstructure:"git"`
+	Ref string `mapstructure:"ref"`
+
+	// CacheDir is where the runbooks repository is cloned.
+	// Default: "./agent-home/runbooks"
+	// Environment variable: RUNBOOKS_CACHE_DIR
+	CacheDir string `mapstructure:"cache_dir"`
+
+	// UpdateIntervalMinutes is how often the runbooks repository is pulled.
+	// 0 disables updates. Default: 0
+	// Environment variable: RUNBOOKS_UPDATE_INTERVAL_MINUTES
+	UpdateIntervalMinutes int `mapstructure:"update_interval_minutes"`
+
+	// Rules map fault types and resource kinds to runbooks. A markdown file named
+	// after the fault type or kind (e.g. "CrashLoopBackOff.md") also matches
+	// without a rule. Config file only.
+	Rules []RunbookRuleConfig `mapstructure:"rules"`
+}
+
+// RunbookRuleConfig maps fault types and/or resource kinds to a runbook.
+type RunbookRuleConfig struct {
+	// FaultTypes and Kinds select the faults the runbook applies to. An empty list
+	// matches any value, but at least one must be set.
+	FaultTypes []string `mapstructure:"fault_types"`
+	Kinds      []string `mapstructure:"kinds"`
+
+	// Runbook is the markdown file, relative to the runbooks directory
+	Runbook string `mapstructure:"runbook"`
+}
+
+// Enabled reports whether a runbooks source is configured.
+func (r RunbooksConfig) Enabled() bool {
+	return r.Dir != "" || r.Git != ""
+}
+
+// Path returns the local directory holding the runbooks.
+func (r RunbooksConfig) Path() string {
+	if r.Git != "" {
+		return filepath.Join(r.CacheDir, runbooksBundleName, r.Dir)
+	}
+	return r.Dir
+}
+
+// Bundle returns the bundle for cloning the runbooks repository with a skills
+// manager. Only valid when Git is set.
+func (r RunbooksConfig) Bundle() skills.Bundle {
+	return skills.Bundle{Name: runbooksBundleName, GitURL: r.Git, Ref: r.Ref}
+}
+
+// Registry returns the runbook registry for the configured directory and rules.
+func (r RunbooksConfig) Registry() *runbooks.Registry {
+	rules := make([]runbooks.Rule, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		rules = append(rules, runbooks.Rule{
+			FaultTypes: rule.FaultTypes,
+			Kinds:      rule.Kinds,
+			Runbook:    rule.Runbook,
+		})
+	}
+	return runbooks.NewRegistry(r.Path(), rules)
+}
+
+// Validate applies defaults and checks the runbooks source and rules.
+func (r *RunbooksConfig) Validate() error {
+	if !r.Enabled() {
+		if len(r.Rules) > 0 {
+			return fmt.Errorf("runbooks.rules requires runbooks.dir or runbooks.git")
+		}
+		return nil
+	}
+	if r.UpdateIntervalMinutes < 0 {
+		return fmt.Errorf("runbooks.update_interval_minutes must be >= 0, got %d", r.UpdateIntervalMinutes)
+	}
+
+	if r.Git != "" {
+		if r.CacheDir == "" {
+			r.CacheDir = "./agent-home/runbooks"
+		}
+		if filepath.IsAbs(r.Dir) {
+			return fmt.Errorf("runbooks.dir must be relative to the repository when runbooks.git is set")
+		}
+		if err := r.Bundle().Validate(); err != nil {
+			return err
+		}
+	} else {
+		info, err := os.Stat(r.Dir)
+		if err != nil {
+			return fmt.Errorf("runbooks directory not accessible: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("runbooks.dir %s is not a directory", r.Dir)
+		}
+	}
+
+	for _, rule := range r.Rules {
+		if err := (runbooks.Rule{FaultTypes: rule.FaultTypes, Kinds: rule.Kinds, Runbook: rule.Runbook}).Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/sampling"
+)
+
+// defaultSamplingResetAfterHours is how long a sampled fault signature must stay
+// quiet before its count starts over
+const defaultSamplingResetAfterHours = 24
+
+// SamplingConfig investigates only one in N occurrences of known-noisy but
+// occasionally interesting faults, selected by fault type and namespace. Counts
+// are kept per fault signature: the first occurrence is investigated, then every
+// Nth. Every occurrence is still recorded as an incident (status pending for the
+// ones not investigated). The first matching rule applies.
+type SamplingConfig struct {
+	// Rules select the sampled faults and their rates. Config file only.
+	Rules []SamplingRuleConfig `mapstructure:"rules"`
+
+	// ResetAfterHours forgets the count of a signature that stayed quiet this long,
+	// so its next occurrence is investigated.
+	// Default: 24
+	// Environment variable: SAMPLING_RESET_AFTER_HOURS
+	ResetAfterHours int `mapstructure:"reset_after_hours"`
+}
+
+// SamplingRuleConfig samples matching faults.
+type SamplingRuleConfig struct {
+	// FaultTypes and Namespaces select the faults the rule applies to. An empty list
+	// matches any value.
+	FaultTypes []string `mapstructure:"fault_types"`
+	Namespaces []string `mapstructure:"namespaces"`
+
+	// Rate investigates one in Rate occurrences of each fault signature
+	Rate int `mapstructure:"rate"`
+}
+
+// Enabled reports whether any sampling rule is configured.
+func (s SamplingConfig) Enabled() bool {
+	return len(s.Rules) > 0
+}
+
+// SamplingRules returns the configured rules.
+func (s SamplingConfig) SamplingRules() []sampling.Rule {
+	rules := make([]sampling.Rule, 0, len(s.Rules))
+	for _, rule := range s.Rules {
+		rules = append(rules, sampling.Rule{
+			FaultTypes: rule.FaultTypes,
+			Namespaces: rule.Namespaces,
+			Rate:       rule.Rate,
+		})
+	}
+	return rules
+}
+
+// ResetAfter returns the reset period.
+func (s SamplingConfig) ResetAfter() time.Duration {
+	return time.Duration(s.ResetAfterHours) * time.Hour
+}
+
+// Validate applies the default reset period and checks the rules.
+func (s *SamplingConfig) Validate() error {
+	if s.ResetAfterHours == 0 {
+		s.ResetAfterHours = defaultSamplingResetAfterHours
+	}
+	if s.ResetAfterHours < 0 {
+		return fmt.Errorf("sampling.reset_after_hours must be positive, got %d", s.ResetAfterHours)
+	}
+	for i, rule := range s.SamplingRules() {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("sampling.rules[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
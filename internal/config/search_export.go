@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/searchindex"
+)
+
+// SearchExportConfig configures indexing completed incidents (metadata, findings,
+// and report text) into OpenSearch or Elasticsearch, for organization-wide search
+// and Kibana or OpenSearch Dashboards.
+type SearchExportConfig struct {
+	// URL is the cluster base URL, e.g. https://search.example.com:9200.
+	// Empty disables the export.
+	// Environment variable: SEARCH_EXPORT_URL
+	URL string `mapstructure:"url"`
+
+	// APIKey authenticates with an Elasticsearch API key (base64 "id:key")
+	// Environment variable: SEARCH_EXPORT_API_KEY
+	APIKey string `mapstructure:"api_key"`
+
+	// Username authenticates with basic auth, when no API key is set
+	// Environment variable: SEARCH_EXPORT_USERNAME
+	Username string `mapstructure:"username"`
+
+	// Password of the basic auth user
+	// Environment variable: SEARCH_EXPORT_PASSWORD
+	Password string `mapstructure:"password"`
+
+	// IndexPrefix is the prefix of the index template and the monthly indices
+	// (<prefix>-v<template version>-YYYY.MM)
+	// Default: "nightcrier-incidents"
+	// Environment variable: SEARCH_EXPORT_INDEX_PREFIX
+	IndexPrefix string `mapstructure:"index_prefix"`
+
+	// ExcludeReport leaves the report's markdown text out of the documents, which
+	// then hold the metadata and findings only (e.g. for small document size limits)
+	// Default: false
+	// Environment variable: SEARCH_EXPORT_EXCLUDE_REPORT
+	ExcludeReport bool `mapstructure:"exclude_report"`
+}
+
+// Enabled reports whether the search export is configured.
+func (s SearchExportConfig) Enabled() bool {
+	return s.URL != ""
+}
+
+// ClientConfig returns the settings for a searchindex.Client.
+func (s SearchExportConfig) ClientConfig() searchindex.Config {
+	return searchindex.Config{
+		URL:         s.URL,
+		APIKey:      s.APIKey,
+		Username:    s.Username,
+		Password:    s.Password,
+		IndexPrefix: s.IndexPrefix,
+	}
+}
+
+// Validate applies defaults and checks the search export settings.
+func (s *SearchExportConfig) Validate() error {
+	if !s.Enabled() {
+		return nil
+	}
+	if err := validateHTTPURL("search_export.url", s.URL); err != nil {
+		return err
+	}
+	if s.Password != "" && s.Username == "" {
+		return fmt.Errorf("search_export.username is required with search_export.password (environment variable: SEARCH_EXPORT_USERNAME)")
+	}
+	if s.IndexPrefix == "" {
+		s.IndexPrefix = searchindex.DefaultIndexPrefix
+	}
+	if s.IndexPrefix != strings.ToLower(s.IndexPrefix) || strings.ContainsAny(s.IndexPrefix, ` "*\<|,>/?#:`) {
+		return fmt.Errorf("search_export.index_prefix must be a lowercase index name, got %q", s.IndexPrefix)
+	}
+	return nil
+}
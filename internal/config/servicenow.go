@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/servicenow"
+)
+
+// ServiceNowConfig configures filing a ServiceNow incident record for each
+// investigated fault. Urgency and impact are mapped from the fault severity, and a
+// fault that recurs within dedup_window_seconds updates its existing record.
+type ServiceNowConfig struct {
+	// InstanceURL is the instance base URL, e.g. https://example.service-now.com.
+	// Empty disables ServiceNow integration.
+	// Environment variable: SERVICENOW_INSTANCE_URL
+	InstanceURL string `mapstructure:"instance_url"`
+
+	// Username of the integration user (needs the itil role)
+	// Environment variable: SERVICENOW_USERNAME
+	Username string `mapstructure:"username"`
+
+	// Password of the integration user
+	// Environment variable: SERVICENOW_PASSWORD
+	Password string `mapstructure:"password"`
+
+	// Table is the table records are filed in
+	// Default: "incident"
+	// Environment variable: SERVICENOW_TABLE
+	Table string `mapstructure:"table"`
+
+	// AssignmentGroup assigns new records to a group (sys_id or name, optional)
+	// Environment variable: SERVICENOW_ASSIGNMENT_GROUP
+	AssignmentGroup string `mapstructure:"assignment_group"`
+
+	// CallerID sets the caller of new records (sys_id or user name, optional)
+	// Environment variable: SERVICENOW_CALLER_ID
+	CallerID string `mapstructure:"caller_id"`
+
+	// Category sets the category of new records (optional)
+	// Environment variable: SERVICENOW_CATEGORY
+	Category string `mapstructure:"category"`
+
+	// MinSeverity files records only for faults at or above this severity
+	// Default: "" (all severities)
+	// Environment variable: SERVICENOW_MIN_SEVERITY
+	MinSeverity string `mapstructure:"min_severity"`
+}
+
+// Enabled reports whether ServiceNow integration is configured.
+func (s ServiceNowConfig) Enabled() bool {
+	return s.InstanceURL != ""
+}
+
+// ClientConfig returns the settings for a servicenow.Client. Recurrences within
+// dedupWindow update the existing record.
+func (s ServiceNowConfig) ClientConfig(dedupWindow time.Duration) servicenow.Config {
+	return servicenow.Config{
+		InstanceURL:     s.InstanceURL,
+		Username:        s.Username,
+		Password:        s.Password,
+		Table:           s.Table,
+		AssignmentGroup: s.AssignmentGroup,
+		CallerID:        s.CallerID,
+		Category:        s.Category,
+		DedupWindow:     dedupWindow,
+	}
+}
+
+// Validate applies defaults and checks the ServiceNow settings.
+func (s *ServiceNowConfig) Validate() error {
+	if !s.Enabled() {
+		return nil
+	}
+	if err := validateHTTPURL("servicenow.instance_url", s.InstanceURL); err != nil {
+		return err
+	}
+	if s.Username == "" || s.Password == "" {
+		return fmt.Errorf("servicenow.username and servicenow.password are required when servicenow.instance_url is set (environment variables: SERVICENOW_USERNAME, SERVICENOW_PASSWORD)")
+	}
+	if s.Table == "" {
+		s.Table = servicenow.DefaultTable
+	}
+	return validateMinSeverity("servicenow.min_severity", s.MinSeverity)
+}
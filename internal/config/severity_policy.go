@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/severitypolicy"
+)
+
+const (
+	// defaultSeverityPolicyTimeoutSeconds bounds reading the cluster signals by default
+	defaultSeverityPolicyTimeoutSeconds = 10
+	// maxSeverityPolicyTimeoutSeconds bounds how long the cluster signals can delay
+	// an incident
+	maxSeverityPolicyTimeoutSeconds = 60
+)
+
+// SeverityPolicyConfig configures computing incident severity from several signals
+// instead of trusting the raw severity of the upstream fault event. Rules are
+// evaluated in order and the first match sets the severity; the upstream severity
+// and the rule are recorded on the incident (upstreamSeverity, severityRule). Rules
+// on namespace labels, priority classes, and PodDisruptionBudgets read the cluster
+// with kubectl using the cluster's triage kubeconfig; on clusters without one those
+// rules never match.
+type SeverityPolicyConfig struct {
+	// Rules compute the severity of matching faults. Config file only.
+	Rules []SeverityRuleConfig `mapstructure:"rules"`
+
+	// TimeoutSeconds bounds reading the cluster signals of a fault (1-60).
+	// Default: 10
+	// Environment variable: SEVERITY_POLICY_TIMEOUT_SECONDS
+	TimeoutSeconds int `mapstructure:"timeout_seconds"`
+}
+
+// SeverityRuleConfig sets the severity of matching faults.
+type SeverityRuleConfig struct {
+	// Name identifies the rule on the incident
+	Name string `mapstructure:"name"`
+
+	// FaultTypes (event reasons), Kinds, Namespaces, and Severities (upstream
+	// severities) select the faults the rule applies to. An empty list matches any
+	// value.
+	FaultTypes []string `mapstructure:"fault_types"`
+	Kinds      []string `mapstructure:"kinds"`
+	Namespaces []string `mapstructure:"namespaces"`
+	Severities []string `mapstructure:"severities"`
+
+	// NamespaceLabels match the labels of the fault's namespace, e.g.
+	// {tier: production}; "*" matches any value of a label that is set
+	NamespaceLabels map[string]string `mapstructure:"namespace_labels"`
+
+	// PriorityClasses match the priority class of the pod, or of a workload's pods;
+	// "" matches pods without one
+	PriorityClasses []string `mapstructure:"priority_classes"`
+
+	// PDB matches pods covered ("present") or not covered ("absent") by a
+	// PodDisruptionBudget
+	PDB string `mapstructure:"pdb"`
+
+	// Severity is set on matching faults: DEBUG, INFO, WARNING, ERROR, or CRITICAL
+	Severity string `mapstructure:"severity"`
+}
+
+// Enabled reports whether any severity rules are configured.
+func (s SeverityPolicyConfig) Enabled() bool {
+	return len(s.Rules) > 0
+}
+
+// Policy returns the configured rules.
+func (s SeverityPolicyConfig) Policy() severitypolicy.Policy {
+	rules := make([]severitypolicy.Rule, 0, len(s.Rules))
+	for _, rule := range s.Rules {
+		rules = append(rules, severitypolicy.Rule{
+			Name:            rule.Name,
+			FaultTypes:      rule.FaultTypes,
+			Kinds:           rule.Kinds,
+			Namespaces:      rule.Namespaces,
+			Severities:      rule.Severities,
+			NamespaceLabels: rule.NamespaceLabels,
+			PriorityClasses: rule.PriorityClasses,
+			PDB:             rule.PDB,
+			Severity:        rule.Severity,
+		})
+	}
+	return severitypolicy.Policy{Rules: rules}
+}
+
+// Timeout returns how long reading the cluster signals of a fault may take.
+func (s SeverityPolicyConfig) Timeout() time.Duration {
+	return time.Duration(s.TimeoutSeconds) * time.Second
+}
+
+// Validate applies the defaults and checks the rules.
+func (s *SeverityPolicyConfig) Validate() error {
+	if s.TimeoutSeconds == 0 {
+		s.TimeoutSeconds = defaultSeverityPolicyTimeoutSeconds
+	}
+	if s.TimeoutSeconds < 1 || s.TimeoutSeconds > maxSeverityPolicyTimeoutSeconds {
+		return fmt.Errorf("severity_policy.timeout_seconds must be between 1 and %d, got %d",
+			maxSeverityPolicyTimeoutSeconds, s.TimeoutSeconds)
+	}
+	if err := s.Policy().Validate(); err != nil {
+		return fmt.Errorf("severity_policy.rules: %w", err)
+	}
+	return nil
+}
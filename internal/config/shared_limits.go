@@ -0,0 +1,28 @@
+package config
+
+import "fmt"
+
+// SharedLimitsConfig enforces the adaptive dedup windows, LLM launch pacing, and
+// investigation budgets fleet-wide instead of per process. The state is kept in
+// the SQL state store, so every nightcrier process pointed at the same PostgreSQL
+// database (or, on one host, the same SQLite file) shares it. When the state store
+// is unavailable, each process falls back to its own limits.
+//
+// Requires a sqlite or postgres state store.
+type SharedLimitsConfig struct {
+	// Enabled turns on fleet-wide limits.
+	// Default: false
+	// Environment variable: SHARED_LIMITS_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// Validate checks that the state store can hold the shared state.
+func (s *SharedLimitsConfig) Validate(stateStorageType string) error {
+	if !s.Enabled {
+		return nil
+	}
+	if stateStorageType != "sqlite" && stateStorageType != "postgres" {
+		return fmt.Errorf("shared_limits requires a sqlite or postgres state store (state_storage.type is %q)", stateStorageType)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultSlackAppRecentHours is how far back the App Home lists finished
+// investigations by default
+const defaultSlackAppRecentHours = 24
+
+// SlackAppConfig configures the Slack app, which connects to Slack over Socket
+// Mode (no public endpoint needed) in addition to the webhook notifications. Its
+// App Home tab lists the active and recent incidents of the viewing user's teams
+// and of all teams, and the /nightcrier slash command reports status and silences
+// namespaces. Incidents and silences are read from and written to the state
+// store, so the app requires state_storage.type sqlite or postgres.
+type SlackAppConfig struct {
+	// AppToken is the app-level token with the connections:write scope (xapp-...).
+	// Empty disables the Slack app.
+	// Environment variable: SLACK_APP_TOKEN
+	AppToken string `mapstructure:"app_token"`
+
+	// BotToken is the bot token used to publish the App Home (xoxb-...)
+	// Environment variable: SLACK_BOT_TOKEN
+	BotToken string `mapstructure:"bot_token"`
+
+	// UserTeams maps Slack user IDs to the owning teams (see ownership) whose
+	// incidents are shown first on their App Home and in /nightcrier status.
+	// Config file only.
+	UserTeams map[string][]string `mapstructure:"user_teams"`
+
+	// AllowedUsers are the Slack user IDs allowed to silence namespaces, lift
+	// silences, and pause triage. Empty allows nobody; the read-only commands stay
+	// available to every member of the workspace. Config file only.
+	AllowedUsers []string `mapstructure:"allowed_users"`
+
+	// RecentHours is how far back the App Home lists finished investigations.
+	// Default: 24
+	// Environment variable: SLACK_APP_RECENT_HOURS
+	RecentHours int `mapstructure:"recent_hours"`
+}
+
+// Enabled reports whether the Slack app is configured.
+func (s SlackAppConfig) Enabled() bool {
+	return s.AppToken != ""
+}
+
+// Validate applies defaults and checks the tokens. The state store requirement is
+// checked by Config.Validate, which knows the state storage type.
+func (s *SlackAppConfig) Validate() error {
+	if s.RecentHours == 0 {
+		s.RecentHours = defaultSlackAppRecentHours
+	}
+	if s.RecentHours < 0 {
+		return fmt.Errorf("slack_app.recent_hours must be positive, got %d", s.RecentHours)
+	}
+	if !s.Enabled() {
+		if s.BotToken != "" {
+			return fmt.Errorf("slack_app.bot_token is set but slack_app.app_token is not (SLACK_APP_TOKEN)")
+		}
+		return nil
+	}
+	if !strings.HasPrefix(s.AppToken, "xapp-") {
+		return fmt.Errorf("slack_app.app_token must be an app-level token (xapp-...)")
+	}
+	if s.BotToken == "" {
+		return fmt.Errorf("slack_app.bot_token is required when the Slack app is enabled (SLACK_BOT_TOKEN)")
+	}
+	if !strings.HasPrefix(s.BotToken, "xoxb-") {
+		return fmt.Errorf("slack_app.bot_token must be a bot token (xoxb-...)")
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// redactedValue replaces secret values in configuration snapshots
+const redactedValue = "<redacted>"
+
+// secretKeySuffixes identify settings whose values are credentials. A setting is
+// secret when its key (the last path segment) ends with one of these.
+var secretKeySuffixes = []string{
+	"password",
+	"token",
+	"api_key",
+	"api_keys",
+	"secret_access_key",
+	"storage_key",
+	"connection_string",
+	"webhook_url",
+	"secret",
+}
+
+// SettingChange is a setting whose value differs between two snapshots.
+type SettingChange struct {
+	// Key is the dotted configuration key, e.g. "servicenow.table" or
+	// "clusters[prod-east].triage.enabled"
+	Key string
+	// Old and New are the redacted values; Old is empty for a new setting and New
+	// is empty for a removed one
+	Old string
+	New string
+}
+
+// Snapshot flattens the effective configuration into dotted keys (the config file
+// keys) and string values, for recording and comparing configurations. Secrets are
+// replaced with "<redacted>" and credentials embedded in URLs are removed, so the
+// snapshot is safe to log and store. Clusters are keyed by name rather than
+// position so reordering them is not reported as a change.
+func (c *Config) Snapshot() map[string]string {
+	out := make(map[string]string)
+	flattenSetting("", reflect.ValueOf(c), out)
+	return out
+}
+
+// DiffSnapshots returns the settings that differ between two snapshots, sorted by
+// key. A secret that changed value is not detected because both values are
+// redacted; compare configuration hashes to detect any change.
+func DiffSnapshots(previous, current map[string]string) []SettingChange {
+	var changes []SettingChange
+	for key, value := range current {
+		if old, ok := previous[key]; !ok || old != value {
+			changes = append(changes, SettingChange{Key: key, Old: previous[key], New: value})
+		}
+	}
+	for key, old := range previous {
+		if _, ok := current[key]; !ok {
+			changes = append(changes, SettingChange{Key: key, Old: old})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// flattenSetting writes the leaf values below v into out, keyed by their path.
+func flattenSetting(path string, v reflect.Value, out map[string]string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			out[path] = ""
+			return
+		}
+		flattenSetting(path, v.Elem(), out)
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if name == "-" {
+				continue
+			}
+			if opts == "squash" {
+				flattenSetting(path, v.Field(i), out)
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			flattenSetting(joinSettingPath(path, name), v.Field(i), out)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if isSecretSetting(path) {
+			out[path] = redactSetting(v.Len() > 0)
+			return
+		}
+		if elem := v.Type().Elem(); elem.Kind() == reflect.Struct || (elem.Kind() == reflect.Ptr && elem.Elem().Kind() == reflect.Struct) {
+			for i := 0; i < v.Len(); i++ {
+				flattenSetting(fmt.Sprintf("%s[%s]", path, elementKey(v.Index(i), i)), v.Index(i), out)
+			}
+			return
+		}
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		out[path] = strings.Join(values, ",")
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			flattenSetting(joinSettingPath(path, fmt.Sprint(key.Interface())), v.MapIndex(key), out)
+		}
+
+	default:
+		value := fmt.Sprint(v.Interface())
+		if isSecretSetting(path) {
+			value = redactSetting(value != "")
+		}
+		out[path] = stripURLCredentials(value)
+	}
+}
+
+// elementKey identifies a list element by its Name field when it has one, so that
+// reordering a list of named items does not change the keys.
+func elementKey(v reflect.Value, index int) string {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return fmt.Sprint(index)
+		}
+		v = v.Elem()
+	}
+	if name := v.FieldByName("Name"); name.IsValid() && name.Kind() == reflect.String && name.String() != "" {
+		return name.String()
+	}
+	return fmt.Sprint(index)
+}
+
+// joinSettingPath appends a key segment to a dotted path.
+func joinSettingPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// isSecretSetting reports whether the last segment of a setting path names a secret.
+func isSecretSetting(path string) bool {
+	key := path[strings.LastIndex(path, ".")+1:]
+	for _, suffix := range secretKeySuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSetting returns the redacted form of a secret; unset secrets stay empty so
+// setting or clearing one still shows up in a diff.
+func redactSetting(set bool) string {
+	if set {
+		return redactedValue
+	}
+	return ""
+}
+
+// stripURLCredentials removes the password from URLs such as proxy URLs with
+// embedded credentials. Other values are returned unchanged.
+func stripURLCredentials(value string) string {
+	if !strings.Contains(value, "@") || !strings.Contains(value, "://") {
+		return value
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default SQLite maintenance settings
+const (
+	defaultSQLiteMaintenanceIntervalMinutes = 60
+	defaultSQLiteMaintenanceVacuumPages     = 1000
+	defaultSQLiteMaintenanceAlertWALMB      = 256
+)
+
+// SQLiteMaintenanceConfig configures the background maintenance of a SQLite
+// state store. Every interval the write-ahead log is checkpointed and truncated,
+// unused pages are returned to the filesystem with an incremental vacuum, and the
+// database and WAL sizes are checked against the alert thresholds; crossing one
+// sends an alert through the configured notifiers. "nightcrier db maintain" runs
+// the same maintenance on demand, and with --vacuum rebuilds the database once to
+// enable incremental vacuuming on databases created without it.
+type SQLiteMaintenanceConfig struct {
+	// Enabled turns on the background maintenance.
+	// Default: false
+	// Environment variable: SQLITE_MAINTENANCE_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// IntervalMinutes is how often the maintenance runs.
+	// Default: 60
+	// Environment variable: SQLITE_MAINTENANCE_INTERVAL_MINUTES
+	IntervalMinutes int `mapstructure:"interval_minutes"`
+
+	// VacuumPages bounds the pages an incremental vacuum frees per run, so a run
+	// never holds the write lock for long.
+	// Default: 1000
+	// Environment variable: SQLITE_MAINTENANCE_VACUUM_PAGES
+	VacuumPages int `mapstructure:"vacuum_pages"`
+
+	// AlertSizeMB alerts when the database file grows beyond this size. 0 disables
+	// the alert.
+	// Default: 0 (disabled)
+	// Environment variable: SQLITE_MAINTENANCE_ALERT_SIZE_MB
+	AlertSizeMB int `mapstructure:"alert_size_mb"`
+
+	// AlertWALMB alerts when the WAL is still larger than this after a checkpoint,
+	// which means long-running readers keep it from being truncated. -1 disables
+	// the alert.
+	// Default: 256
+	// Environment variable: SQLITE_MAINTENANCE_ALERT_WAL_MB
+	AlertWALMB int `mapstructure:"alert_wal_mb"`
+}
+
+// Interval returns how often the maintenance runs.
+func (m SQLiteMaintenanceConfig) Interval() time.Duration {
+	return time.Duration(m.IntervalMinutes) * time.Minute
+}
+
+// Validate applies the defaults and checks the settings. The defaults apply even
+// when the background maintenance is disabled, for the db maintain command.
+func (m *SQLiteMaintenanceConfig) Validate(stateStorageType string) error {
+	if m.IntervalMinutes == 0 {
+		m.IntervalMinutes = defaultSQLiteMaintenanceIntervalMinutes
+	}
+	if m.VacuumPages == 0 {
+		m.VacuumPages = defaultSQLiteMaintenanceVacuumPages
+	}
+	if m.AlertWALMB == 0 {
+		m.AlertWALMB = defaultSQLiteMaintenanceAlertWALMB
+	}
+	if m.IntervalMinutes < 1 {
+		return fmt.Errorf("sqlite_maintenance.interval_minutes must be positive, got %d", m.IntervalMinutes)
+	}
+	if m.VacuumPages < 1 {
+		return fmt.Errorf("sqlite_maintenance.vacuum_pages must be positive, got %d", m.VacuumPages)
+	}
+	if m.AlertSizeMB < 0 {
+		return fmt.Errorf("sqlite_maintenance.alert_size_mb must be >= 0, got %d", m.AlertSizeMB)
+	}
+	if m.AlertWALMB < -1 {
+		return fmt.Errorf("sqlite_maintenance.alert_wal_mb must be positive or -1, got %d", m.AlertWALMB)
+	}
+	if m.Enabled && stateStorageType != "sqlite" {
+		return fmt.Errorf("sqlite_maintenance requires a sqlite state store (state_storage.type is %q)", stateStorageType)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/rbias/nightcrier/internal/supplychain"
+)
+
+// SupplyChainConfig configures verifying the agent image and skill bundles with
+// cosign before they are run. The agent image's signature (and optionally its
+// SLSA provenance attestation) is verified at startup and the verified digest is
+// what the agent containers run; tarball skill bundles are verified against their
+// cosign signature bundle and git skill bundles must be pinned to a commit when
+// they are fetched. Every result is appended to the audit log. The cosign CLI
+// must be installed.
+type SupplyChainConfig struct {
+	// Policy is "off", "warn" (log and audit failed verifications, use the
+	// artifact anyway), or "enforce" (refuse to start with an unverified agent
+	// image and keep unverified skill bundles out of the cache).
+	// Default: "off"
+	// Environment variable: SUPPLY_CHAIN_POLICY
+	Policy string `mapstructure:"policy"`
+
+	// CosignPath is the cosign binary.
+	// Default: "cosign" on the PATH
+	// Environment variable: SUPPLY_CHAIN_COSIGN_PATH
+	CosignPath string `mapstructure:"cosign_path"`
+
+	// Key is the cosign public key (a file or a KMS URI such as
+	// awskms:///alias/agent-signing) the artifacts are signed with.
+	// Environment variable: SUPPLY_CHAIN_KEY
+	Key string `mapstructure:"key"`
+
+	// CertificateIdentityRegexp matches the signer identity of keyless (Sigstore)
+	// signatures, e.g. the CI workflow URL. Used when Key is empty.
+	// Environment variable: SUPPLY_CHAIN_CERTIFICATE_IDENTITY_REGEXP
+	CertificateIdentityRegexp string `mapstructure:"certificate_identity_regexp"`
+
+	// CertificateOIDCIssuer is the OIDC issuer of keyless signatures, e.g.
+	// https://token.actions.githubusercontent.com. Used when Key is empty.
+	// Environment variable: SUPPLY_CHAIN_CERTIFICATE_OIDC_ISSUER
+	CertificateOIDCIssuer string `mapstructure:"certificate_oidc_issuer"`
+
+	// VerifyProvenance also verifies the agent image's SLSA provenance attestation.
+	// Default: false
+	// Environment variable: SUPPLY_CHAIN_VERIFY_PROVENANCE
+	VerifyProvenance bool `mapstructure:"verify_provenance"`
+
+	// AuditLog is the JSON Lines file every verification result is appended to.
+	// Default: {workspace_root}/supply-chain-audit.jsonl
+	// Environment variable: SUPPLY_CHAIN_AUDIT_LOG
+	AuditLog string `mapstructure:"audit_log"`
+}
+
+// Enabled reports whether artifacts are verified.
+func (s SupplyChainConfig) Enabled() bool {
+	return s.Policy != "" && s.Policy != string(supplychain.PolicyOff)
+}
+
+// VerifierConfig returns the verifier configuration.
+func (s SupplyChainConfig) VerifierConfig() supplychain.Config {
+	return supplychain.Config{
+		Policy:                    supplychain.Policy(s.Policy),
+		CosignPath:                s.CosignPath,
+		Key:                       s.Key,
+		CertificateIdentityRegexp: s.CertificateIdentityRegexp,
+		CertificateOIDCIssuer:     s.CertificateOIDCIssuer,
+		Provenance:                s.VerifyProvenance,
+		AuditLog:                  s.AuditLog,
+	}
+}
+
+// Validate checks the policy and that a trusted signer is configured, and applies
+// the audit log default.
+func (s *SupplyChainConfig) Validate(workspaceRoot string) error {
+	if s.Policy == "" {
+		s.Policy = string(supplychain.PolicyOff)
+	}
+	switch supplychain.Policy(s.Policy) {
+	case supplychain.PolicyOff:
+		return nil
+	case supplychain.PolicyWarn, supplychain.PolicyEnforce:
+	default:
+		return fmt.Errorf("supply_chain.policy must be off, warn, or enforce, got %q", s.Policy)
+	}
+
+	keyless := s.CertificateIdentityRegexp != "" || s.CertificateOIDCIssuer != ""
+	switch {
+	case s.Key != "" && keyless:
+		return fmt.Errorf("supply_chain.key and the keyless certificate identity are mutually exclusive")
+	case s.Key == "" && (s.CertificateIdentityRegexp == "" || s.CertificateOIDCIssuer == ""):
+		return fmt.Errorf("supply_chain.policy %s requires supply_chain.key, or both certificate_identity_regexp and certificate_oidc_issuer for keyless signatures", s.Policy)
+	}
+	if s.AuditLog == "" {
+		s.AuditLog = filepath.Join(workspaceRoot, "supply-chain-audit.jsonl")
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultTopologyMaxPods bounds the pods of a workload in the topology by default
+	defaultTopologyMaxPods = 5
+	// maxTopologyMaxPods keeps the topology diagram readable
+	maxTopologyMaxPods = 20
+	// defaultTopologyTimeoutSeconds bounds topology collection by default
+	defaultTopologyTimeoutSeconds = 20
+	// maxTopologyTimeoutSeconds bounds how long topology collection can delay the agent
+	maxTopologyTimeoutSeconds = 120
+)
+
+// TopologyConfig configures the resource topology artifact. Before the agent
+// starts, the objects around the affected resource (its pods, their replica set
+// and deployment, the services selecting them, the ingresses routing to those
+// services, and their nodes) are read with kubectl using the cluster's triage
+// kubeconfig. The graph is written to topology.json and topology.svg in the
+// workspace and the diagram is embedded in the HTML report. Clusters without
+// kubeconfig triage get no topology.
+type TopologyConfig struct {
+	// Enabled turns on topology collection.
+	// Default: false
+	// Environment variable: TOPOLOGY_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// MaxPods bounds the pods of a workload in the graph; unhealthy pods are
+	// preferred (1-20).
+	// Default: 5
+	// Environment variable: TOPOLOGY_MAX_PODS
+	MaxPods int `mapstructure:"max_pods"`
+
+	// TimeoutSeconds bounds topology collection (1-120).
+	// Default: 20
+	// Environment variable: TOPOLOGY_TIMEOUT_SECONDS
+	TimeoutSeconds int `mapstructure:"timeout_seconds"`
+}
+
+// Timeout returns the topology collection timeout.
+func (t TopologyConfig) Timeout() time.Duration {
+	return time.Duration(t.TimeoutSeconds) * time.Second
+}
+
+// Validate applies the defaults and checks their ranges.
+func (t *TopologyConfig) Validate() error {
+	if !t.Enabled {
+		return nil
+	}
+	if t.MaxPods == 0 {
+		t.MaxPods = defaultTopologyMaxPods
+	}
+	if t.MaxPods < 1 || t.MaxPods > maxTopologyMaxPods {
+		return fmt.Errorf("topology.max_pods must be between 1 and %d, got %d", maxTopologyMaxPods, t.MaxPods)
+	}
+	if t.TimeoutSeconds == 0 {
+		t.TimeoutSeconds = defaultTopologyTimeoutSeconds
+	}
+	if t.TimeoutSeconds < 1 || t.TimeoutSeconds > maxTopologyTimeoutSeconds {
+		return fmt.Errorf("topology.timeout_seconds must be between 1 and %d, got %d",
+			maxTopologyTimeoutSeconds, t.TimeoutSeconds)
+	}
+	return nil
+}
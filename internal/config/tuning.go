@@ -5,60 +5,84 @@ import (
 	"os"
 
 	"github.com/spf13/viper"
+
+	"github.com/rbias/nightcrier/internal/logging"
 )
 
 // TuningConfig holds tunable operational parameters that control system behavior.
 // These parameters can be adjusted without changing core application configuration.
 type TuningConfig struct {
-	HTTP     HTTPTuning     `mapstructure:"http"`
-	Agent    AgentTuning    `mapstructure:"agent"`
-	Reporting ReportingTuning `mapstructure:"reporting"`
-	Events   EventsTuning   `mapstructure:"events"`
-	IO       IOTuning       `mapstructure:"io"`
+	HTTP     HTTPTuning     `mapstructure:"http" json:"http"`
+	Agent    AgentTuning    `mapstructure:"agent" json:"agent"`
+	Reporting ReportingTuning `mapstructure:"reporting" json:"reporting"`
+	Events   EventsTuning   `mapstructure:"events" json:"events"`
+	IO       IOTuning       `mapstructure:"io" json:"io"`
+	Logging  LoggingTuning  `mapstructure:"logging" json:"logging"`
 }
 
 // HTTPTuning contains HTTP client tuning parameters.
 type HTTPTuning struct {
 	// SlackTimeoutSeconds is the timeout for Slack webhook HTTP requests.
-	SlackTimeoutSeconds int `mapstructure:"slack_timeout_seconds"`
+	SlackTimeoutSeconds int `mapstructure:"slack_timeout_seconds" json:"slack_timeout_seconds"`
 }
 
 // AgentTuning contains agent runtime tuning parameters.
 type AgentTuning struct {
 	// TimeoutBufferSeconds is additional buffer time beyond the configured agent timeout
 	// to allow for graceful shutdown and cleanup.
-	TimeoutBufferSeconds int `mapstructure:"timeout_buffer_seconds"`
+	TimeoutBufferSeconds int `mapstructure:"timeout_buffer_seconds" json:"timeout_buffer_seconds"`
 
 	// InvestigationMinSizeBytes is the minimum size threshold for investigation output.
 	// Investigations smaller than this are considered potentially incomplete.
-	InvestigationMinSizeBytes int `mapstructure:"investigation_min_size_bytes"`
+	InvestigationMinSizeBytes int `mapstructure:"investigation_min_size_bytes" json:"investigation_min_size_bytes"`
+
+	// ResourceSampleIntervalSeconds is how often the CPU, memory, process count, and
+	// disk writes of a running agent are sampled. 0 disables resource sampling.
+	ResourceSampleIntervalSeconds int `mapstructure:"resource_sample_interval_seconds" json:"resource_sample_interval_seconds"`
 }
 
 // ReportingTuning contains reporting and notification tuning parameters.
 type ReportingTuning struct {
 	// RootCauseTruncationLength is the maximum length of root cause text in Slack notifications.
-	RootCauseTruncationLength int `mapstructure:"root_cause_truncation_length"`
+	RootCauseTruncationLength int `mapstructure:"root_cause_truncation_length" json:"root_cause_truncation_length"`
 
 	// FailureReasonsDisplayCount is the number of failure reasons to display in reports.
-	FailureReasonsDisplayCount int `mapstructure:"failure_reasons_display_count"`
+	FailureReasonsDisplayCount int `mapstructure:"failure_reasons_display_count" json:"failure_reasons_display_count"`
 
 	// MaxFailureReasonsTracked is the maximum number of failure reasons to track internally.
-	MaxFailureReasonsTracked int `mapstructure:"max_failure_reasons_tracked"`
+	MaxFailureReasonsTracked int `mapstructure:"max_failure_reasons_tracked" json:"max_failure_reasons_tracked"`
 }
 
 // EventsTuning contains event processing tuning parameters.
 type EventsTuning struct {
 	// ChannelBufferSize is the buffer size for event processing channels.
-	ChannelBufferSize int `mapstructure:"channel_buffer_size"`
+	ChannelBufferSize int `mapstructure:"channel_buffer_size" json:"channel_buffer_size"`
+
+	// MaxPayloadBytes is the maximum size of a single incoming fault event payload.
+	// Larger events are quarantined instead of processed. 0 disables the limit.
+	MaxPayloadBytes int `mapstructure:"max_payload_bytes" json:"max_payload_bytes"`
+
+	// MaxConnectionReadBytesPerMinute caps how many bytes are read from a single MCP
+	// connection per minute. Exceeding it drops the connection (it reconnects with backoff).
+	// 0 disables the limit.
+	MaxConnectionReadBytesPerMinute int `mapstructure:"max_connection_read_bytes_per_minute" json:"max_connection_read_bytes_per_minute"`
 }
 
 // IOTuning contains I/O tuning parameters for agent output capture.
 type IOTuning struct {
 	// StdoutBufferSize is the buffer size for capturing agent stdout.
-	StdoutBufferSize int `mapstructure:"stdout_buffer_size"`
+	StdoutBufferSize int `mapstructure:"stdout_buffer_size" json:"stdout_buffer_size"`
 
 	// StderrBufferSize is the buffer size for capturing agent stderr.
-	StderrBufferSize int `mapstructure:"stderr_buffer_size"`
+	StderrBufferSize int `mapstructure:"stderr_buffer_size" json:"stderr_buffer_size"`
+}
+
+// LoggingTuning contains per-component logging parameters.
+type LoggingTuning struct {
+	// Levels sets the log level of individual components (events, agent, storage,
+	// main, ...), overriding log_level for them, e.g. {"agent": "debug", "events":
+	// "warn"}. An empty level removes the override.
+	Levels map[string]string `mapstructure:"levels" json:"levels,omitempty"`
 }
 
 // defaultTuning returns a TuningConfig with sensible defaults.
@@ -69,8 +93,9 @@ func defaultTuning() *TuningConfig {
 			SlackTimeoutSeconds: 10,
 		},
 		Agent: AgentTuning{
-			TimeoutBufferSeconds:      60,
-			InvestigationMinSizeBytes: 100,
+			TimeoutBufferSeconds:          60,
+			InvestigationMinSizeBytes:     100,
+			ResourceSampleIntervalSeconds: 5,
 		},
 		Reporting: ReportingTuning{
 			RootCauseTruncationLength:  300,
@@ -78,7 +103,9 @@ func defaultTuning() *TuningConfig {
 			MaxFailureReasonsTracked:   5,
 		},
 		Events: EventsTuning{
-			ChannelBufferSize: 100,
+			ChannelBufferSize:               100,
+			MaxPayloadBytes:                 1048576,
+			MaxConnectionReadBytesPerMinute: 67108864,
 		},
 		IO: IOTuning{
 			StdoutBufferSize: 1024,
@@ -87,6 +114,12 @@ func defaultTuning() *TuningConfig {
 	}
 }
 
+// DefaultTuning returns the tuning defaults, for callers that embed nightcrier
+// without a tuning.yaml.
+func DefaultTuning() *TuningConfig {
+	return defaultTuning()
+}
+
 // setTuningDefaults configures default values for tuning parameters in viper.
 func setTuningDefaults() {
 	defaults := defaultTuning()
@@ -97,6 +130,7 @@ func setTuningDefaults() {
 	// Agent defaults
 	viper.SetDefault("agent.timeout_buffer_seconds", defaults.Agent.TimeoutBufferSeconds)
 	viper.SetDefault("agent.investigation_min_size_bytes", defaults.Agent.InvestigationMinSizeBytes)
+	viper.SetDefault("agent.resource_sample_interval_seconds", defaults.Agent.ResourceSampleIntervalSeconds)
 
 	// Reporting defaults
 	viper.SetDefault("reporting.root_cause_truncation_length", defaults.Reporting.RootCauseTruncationLength)
@@ -105,6 +139,8 @@ func setTuningDefaults() {
 
 	// Events defaults
 	viper.SetDefault("events.channel_buffer_size", defaults.Events.ChannelBufferSize)
+	viper.SetDefault("events.max_payload_bytes", defaults.Events.MaxPayloadBytes)
+	viper.SetDefault("events.max_connection_read_bytes_per_minute", defaults.Events.MaxConnectionReadBytesPerMinute)
 
 	// IO defaults
 	viper.SetDefault("io.stdout_buffer_size", defaults.IO.StdoutBufferSize)
@@ -131,10 +167,13 @@ func LoadTuningWithFile(tuningFile string) (*TuningConfig, error) {
 	v.SetDefault("http.slack_timeout_seconds", defaults.HTTP.SlackTimeoutSeconds)
 	v.SetDefault("agent.timeout_buffer_seconds", defaults.Agent.TimeoutBufferSeconds)
 	v.SetDefault("agent.investigation_min_size_bytes", defaults.Agent.InvestigationMinSizeBytes)
+	v.SetDefault("agent.resource_sample_interval_seconds", defaults.Agent.ResourceSampleIntervalSeconds)
 	v.SetDefault("reporting.root_cause_truncation_length", defaults.Reporting.RootCauseTruncationLength)
 	v.SetDefault("reporting.failure_reasons_display_count", defaults.Reporting.FailureReasonsDisplayCount)
 	v.SetDefault("reporting.max_failure_reasons_tracked", defaults.Reporting.MaxFailureReasonsTracked)
 	v.SetDefault("events.channel_buffer_size", defaults.Events.ChannelBufferSize)
+	v.SetDefault("events.max_payload_bytes", defaults.Events.MaxPayloadBytes)
+	v.SetDefault("events.max_connection_read_bytes_per_minute", defaults.Events.MaxConnectionReadBytesPerMinute)
 	v.SetDefault("io.stdout_buffer_size", defaults.IO.StdoutBufferSize)
 	v.SetDefault("io.stderr_buffer_size", defaults.IO.StderrBufferSize)
 
@@ -192,6 +231,9 @@ func (t *TuningConfig) Validate() error {
 	if t.Agent.InvestigationMinSizeBytes < 0 {
 		return fmt.Errorf("agent.investigation_min_size_bytes must be >= 0, got %d", t.Agent.InvestigationMinSizeBytes)
 	}
+	if t.Agent.ResourceSampleIntervalSeconds < 0 {
+		return fmt.Errorf("agent.resource_sample_interval_seconds must be >= 0, got %d", t.Agent.ResourceSampleIntervalSeconds)
+	}
 
 	// Reporting validations
 	if t.Reporting.RootCauseTruncationLength < 1 {
@@ -209,6 +251,12 @@ func (t *TuningConfig) Validate() error {
 	if t.Events.ChannelBufferSize < 1 {
 		return fmt.Errorf("events.channel_buffer_size must be >= 1, got %d", t.Events.ChannelBufferSize)
 	}
+	if t.Events.MaxPayloadBytes < 0 {
+		return fmt.Errorf("events.max_payload_bytes must be >= 0, got %d", t.Events.MaxPayloadBytes)
+	}
+	if t.Events.MaxConnectionReadBytesPerMinute < 0 {
+		return fmt.Errorf("events.max_connection_read_bytes_per_minute must be >= 0, got %d", t.Events.MaxConnectionReadBytesPerMinute)
+	}
 
 	// IO validations
 	if t.IO.StdoutBufferSize < 1 {
@@ -218,6 +266,19 @@ func (t *TuningConfig) Validate() error {
 		return fmt.Errorf("io.stderr_buffer_size must be >= 1, got %d", t.IO.StderrBufferSize)
 	}
 
+	// Logging validations
+	for component, level := range t.Logging.Levels {
+		if component == "" {
+			return fmt.Errorf("logging.levels: component name must not be empty")
+		}
+		if level == "" {
+			continue
+		}
+		if _, err := logging.ParseLevel(level); err != nil {
+			return fmt.Errorf("logging.levels.%s: %w", component, err)
+		}
+	}
+
 	return nil
 }
 
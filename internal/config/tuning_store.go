@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// restartOnlyTuning are the tuning settings read once at startup (HTTP clients and
+// event channels are sized when they are created). Changing them at runtime is
+// accepted but only takes effect after a restart.
+var restartOnlyTuning = map[string]bool{
+	"http.slack_timeout_seconds":                  true,
+	"events.channel_buffer_size":                  true,
+	"events.max_payload_bytes":                    true,
+	"events.max_connection_read_bytes_per_minute": true,
+}
+
+// TuningUpdate reports the outcome of a tuning change.
+type TuningUpdate struct {
+	// Tuning is the tuning now in effect
+	Tuning *TuningConfig `json:"tuning"`
+	// Changes are the settings that changed
+	Changes []SettingChange `json:"changes"`
+	// RestartRequired lists the changed settings that only take effect after a
+	// restart
+	RestartRequired []string `json:"restart_required,omitempty"`
+}
+
+// TuningStore holds the tuning in effect and lets it be replaced at runtime
+// (SIGHUP or the tuning API) without a restart. Components that honor runtime
+// changes read Current for every operation instead of keeping their own copy.
+// Replacements are validated first and swapped atomically, so readers always see a
+// complete, valid tuning.
+type TuningStore struct {
+	file    string
+	current atomic.Pointer[TuningConfig]
+	// mu serializes replacements so concurrent updates do not lose each other's
+	// changes
+	mu sync.Mutex
+	// onChange are called with every tuning swapped in
+	onChange []func(*TuningConfig)
+}
+
+// NewTuningStore holds initial as the tuning in effect. file is where Reload reads
+// from; empty searches the standard locations like LoadTuning.
+func NewTuningStore(file string, initial *TuningConfig) *TuningStore {
+	s := &TuningStore{file: file}
+	s.current.Store(initial)
+	return s
+}
+
+// Current returns the tuning in effect. It must not be modified.
+func (s *TuningStore) Current() *TuningConfig {
+	return s.current.Load()
+}
+
+// OnChange registers fn to be called with every tuning swapped in, for settings
+// applied by pushing them to a component rather than read through Current.
+func (s *TuningStore) OnChange(fn func(*TuningConfig)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = append(s.onChange, fn)
+}
+
+// Reload re-reads the tuning file and applies it.
+func (s *TuningStore) Reload() (*TuningUpdate, error) {
+	tuning, err := LoadTuningWithFile(s.file)
+	if err != nil {
+		return nil, err
+	}
+	return s.Set(tuning)
+}
+
+// Patch applies a JSON document containing the settings to change, e.g.
+// {"reporting": {"root_cause_truncation_length": 500}}, on top of the tuning in
+// effect. Unknown settings are rejected.
+func (s *TuningStore) Patch(patch []byte) (*TuningUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Decoding onto a copy keeps the settings the patch does not mention. Maps are
+	// copied too, since decoding adds to them in place.
+	updated := *s.current.Load()
+	updated.Logging.Levels = maps.Clone(updated.Logging.Levels)
+	dec := json.NewDecoder(bytes.NewReader(patch))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&updated); err != nil {
+		return nil, fmt.Errorf("invalid tuning patch: %w", err)
+	}
+	return s.swap(&updated)
+}
+
+// Set validates tuning and makes it the tuning in effect.
+func (s *TuningStore) Set(tuning *TuningConfig) (*TuningUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.swap(tuning)
+}
+
+// swap replaces the tuning in effect. The caller holds mu.
+func (s *TuningStore) swap(tuning *TuningConfig) (*TuningUpdate, error) {
+	if err := tuning.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tuning: %w", err)
+	}
+
+	previous := s.current.Swap(tuning)
+	update := &TuningUpdate{
+		Tuning:  tuning,
+		Changes: DiffSnapshots(tuningSnapshot(previous), tuningSnapshot(tuning)),
+	}
+	for _, change := range update.Changes {
+		if restartOnlyTuning[change.Key] {
+			update.RestartRequired = append(update.RestartRequired, change.Key)
+		}
+		slog.Info("tuning changed",
+			"setting", change.Key,
+			"old", change.Old,
+			"new", change.New,
+			"restart_required", restartOnlyTuning[change.Key])
+	}
+	for _, fn := range s.onChange {
+		fn(tuning)
+	}
+	return update, nil
+}
+
+// tuningSnapshot flattens tuning into dotted keys, like Config.Snapshot.
+func tuningSnapshot(tuning *TuningConfig) map[string]string {
+	out := make(map[string]string)
+	flattenSetting("", reflect.ValueOf(tuning), out)
+	return out
+}
@@ -25,6 +25,9 @@ func TestLoadTuning_WithDefaults(t *testing.T) {
 	if tuning.Agent.InvestigationMinSizeBytes != 100 {
 		t.Errorf("Agent.InvestigationMinSizeBytes = %d, want 100", tuning.Agent.InvestigationMinSizeBytes)
 	}
+	if tuning.Agent.ResourceSampleIntervalSeconds != 5 {
+		t.Errorf("Agent.ResourceSampleIntervalSeconds = %d, want 5", tuning.Agent.ResourceSampleIntervalSeconds)
+	}
 
 	// Verify Reporting defaults
 	if tuning.Reporting.RootCauseTruncationLength != 300 {
@@ -41,6 +44,12 @@ func TestLoadTuning_WithDefaults(t *testing.T) {
 	if tuning.Events.ChannelBufferSize != 100 {
 		t.Errorf("Events.ChannelBufferSize = %d, want 100", tuning.Events.ChannelBufferSize)
 	}
+	if tuning.Events.MaxPayloadBytes != 1048576 {
+		t.Errorf("Events.MaxPayloadBytes = %d, want 1048576", tuning.Events.MaxPayloadBytes)
+	}
+	if tuning.Events.MaxConnectionReadBytesPerMinute != 67108864 {
+		t.Errorf("Events.MaxConnectionReadBytesPerMinute = %d, want 67108864", tuning.Events.MaxConnectionReadBytesPerMinute)
+	}
 
 	// Verify IO defaults
 	if tuning.IO.StdoutBufferSize != 1024 {
@@ -511,6 +520,9 @@ func TestDefaultTuning(t *testing.T) {
 	if defaults.Agent.InvestigationMinSizeBytes != 100 {
 		t.Errorf("Agent.InvestigationMinSizeBytes = %d, want 100", defaults.Agent.InvestigationMinSizeBytes)
 	}
+	if defaults.Agent.ResourceSampleIntervalSeconds != 5 {
+		t.Errorf("Agent.ResourceSampleIntervalSeconds = %d, want 5", defaults.Agent.ResourceSampleIntervalSeconds)
+	}
 	if defaults.Reporting.RootCauseTruncationLength != 300 {
 		t.Errorf("Reporting.RootCauseTruncationLength = %d, want 300", defaults.Reporting.RootCauseTruncationLength)
 	}
@@ -598,3 +610,93 @@ io:
 		t.Errorf("IO.StderrBufferSize = %d, want 4096", tuning.IO.StderrBufferSize)
 	}
 }
+
+func TestTuningStore(t *testing.T) {
+	tmpDir := t.TempDir()
+	tuningPath := filepath.Join(tmpDir, "tuning.yaml")
+	if err := os.WriteFile(tuningPath, []byte("agent:\n  investigation_min_size_bytes: 500\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	initial := defaultTuning()
+	store := NewTuningStore(tuningPath, initial)
+
+	// Patching changes only the given settings
+	update, err := store.Patch([]byte(`{"reporting": {"root_cause_truncation_length": 120}, "events": {"channel_buffer_size": 500}}`))
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	current := store.Current()
+	if current.Reporting.RootCauseTruncationLength != 120 || current.Reporting.FailureReasonsDisplayCount != 3 {
+		t.Errorf("reporting = %+v, want only the truncation length changed", current.Reporting)
+	}
+	if initial.Reporting.RootCauseTruncationLength != 300 {
+		t.Error("Patch() modified the previous tuning instead of swapping in a copy")
+	}
+	if len(update.Changes) != 2 || len(update.RestartRequired) != 1 || update.RestartRequired[0] != "events.channel_buffer_size" {
+		t.Errorf("update = %+v, want two changes with the channel buffer size requiring a restart", update)
+	}
+
+	// Invalid values and unknown settings are rejected and the tuning is kept
+	for _, patch := range []string{
+		`{"reporting": {"root_cause_truncation_length": 0}}`,
+		`{"reporting": {"truncation": 10}}`,
+		`not json`,
+	} {
+		if _, err := store.Patch([]byte(patch)); err == nil {
+			t.Errorf("Patch(%s) should fail", patch)
+		}
+	}
+	if store.Current() != current {
+		t.Error("rejected patches must not replace the tuning")
+	}
+
+	// Reloading replaces the tuning with the file's
+	if _, err := store.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if store.Current().Agent.InvestigationMinSizeBytes != 500 || store.Current().Reporting.RootCauseTruncationLength != 300 {
+		t.Errorf("reloaded tuning = %+v", store.Current())
+	}
+}
+
+func TestTuningStore_LoggingLevels(t *testing.T) {
+	tmpDir := t.TempDir()
+	tuningPath := filepath.Join(tmpDir, "tuning.yaml")
+	if err := os.WriteFile(tuningPath, []byte("logging:\n  levels:\n    events: warn\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadTuningWithFile(tuningPath)
+	if err != nil {
+		t.Fatalf("LoadTuningWithFile() error = %v", err)
+	}
+	if loaded.Logging.Levels["events"] != "warn" {
+		t.Errorf("logging.levels = %v, want events at warn", loaded.Logging.Levels)
+	}
+
+	store := NewTuningStore(tuningPath, loaded)
+	var notified *TuningConfig
+	store.OnChange(func(tuning *TuningConfig) { notified = tuning })
+
+	update, err := store.Patch([]byte(`{"logging": {"levels": {"agent": "debug"}}}`))
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	levels := store.Current().Logging.Levels
+	if levels["agent"] != "debug" || levels["events"] != "warn" {
+		t.Errorf("logging.levels = %v, want agent added next to events", levels)
+	}
+	if _, ok := loaded.Logging.Levels["agent"]; ok {
+		t.Error("Patch() modified the previous tuning's levels instead of a copy")
+	}
+	if len(update.Changes) != 1 || update.Changes[0].Key != "logging.levels.agent" {
+		t.Errorf("changes = %+v, want logging.levels.agent", update.Changes)
+	}
+	if notified != store.Current() {
+		t.Error("OnChange was not called with the new tuning")
+	}
+
+	if _, err := store.Patch([]byte(`{"logging": {"levels": {"agent": "verbose"}}}`)); err == nil {
+		t.Error("Patch() with an unknown log level should fail")
+	}
+}
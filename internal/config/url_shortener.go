@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/shortener"
+)
+
+// defaultURLShortenerTimeoutSeconds bounds a shortener call by default
+const defaultURLShortenerTimeoutSeconds = 5
+
+// URLShortenerConfig shortens report links through an internal URL shortener
+// before they are sent in notifications, recorded in ServiceNow, or published to
+// postmortems and knowledge bases. Long storage links (Azure SAS URLs) become
+// short, stable links. When the shortener fails or is down, the raw URL is used.
+type URLShortenerConfig struct {
+	// URL is the shortener API endpoint. Empty disables shortening.
+	// Environment variable: URL_SHORTENER_URL
+	URL string `mapstructure:"url"`
+
+	// Method is the HTTP method of the shortener call.
+	// Default: "POST"
+	// Environment variable: URL_SHORTENER_METHOD
+	Method string `mapstructure:"method"`
+
+	// Token is sent as a bearer token (optional)
+	// Environment variable: URL_SHORTENER_TOKEN
+	Token string `mapstructure:"token"`
+
+	// BodyTemplate is the JSON request body; "{url}" is replaced with the long URL.
+	// Default: {"url": "{url}"}
+	// Environment variable: URL_SHORTENER_BODY_TEMPLATE
+	BodyTemplate string `mapstructure:"body_template"`
+
+	// ResponseField is the dotted path of the short URL in a JSON response; a
+	// plain-text response is used as the short URL.
+	// Default: "short_url"
+	// Environment variable: URL_SHORTENER_RESPONSE_FIELD
+	ResponseField string `mapstructure:"response_field"`
+
+	// TimeoutSeconds bounds each shortener call (1-60).
+	// Default: 5
+	// Environment variable: URL_SHORTENER_TIMEOUT_SECONDS
+	TimeoutSeconds int `mapstructure:"timeout_seconds"`
+}
+
+// Enabled reports whether a shortener is configured.
+func (u URLShortenerConfig) Enabled() bool {
+	return u.URL != ""
+}
+
+// Client returns the shortener client.
+func (u URLShortenerConfig) Client() *shortener.Client {
+	return shortener.New(shortener.Config{
+		URL:           u.URL,
+		Method:        u.Method,
+		Token:         u.Token,
+		BodyTemplate:  u.BodyTemplate,
+		ResponseField: u.ResponseField,
+	}, &http.Client{Timeout: time.Duration(u.TimeoutSeconds) * time.Second})
+}
+
+// Validate applies the defaults and checks the endpoint, method, and template.
+func (u *URLShortenerConfig) Validate() error {
+	if !u.Enabled() {
+		return nil
+	}
+	if err := validateHTTPURL("url_shortener.url", u.URL); err != nil {
+		return err
+	}
+	u.Method = strings.ToUpper(u.Method)
+	if u.Method == "" {
+		u.Method = http.MethodPost
+	}
+	if u.Method != http.MethodPost && u.Method != http.MethodPut {
+		return fmt.Errorf("url_shortener.method must be POST or PUT, got %q", u.Method)
+	}
+	if u.BodyTemplate != "" && !strings.Contains(u.BodyTemplate, "{url}") {
+		return fmt.Errorf("url_shortener.body_template must contain the {url} placeholder")
+	}
+	if u.TimeoutSeconds == 0 {
+		u.TimeoutSeconds = defaultURLShortenerTimeoutSeconds
+	}
+	if u.TimeoutSeconds < 1 || u.TimeoutSeconds > 60 {
+		return fmt.Errorf("url_shortener.timeout_seconds must be between 1 and 60, got %d", u.TimeoutSeconds)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultVerificationTimeoutSeconds bounds the verification pass by default
+	defaultVerificationTimeoutSeconds = 60
+	// maxVerificationTimeoutSeconds bounds how long verification can delay the report
+	maxVerificationTimeoutSeconds = 600
+)
+
+// VerificationConfig configures checking the agent's findings against the
+// cluster. The agent is asked to record the checkable claims its root cause rests
+// on (images, resource limits, field values, objects) in output/findings.json.
+// After a successful investigation each claim is checked through the cluster's
+// MCP server and marked verified, contradicted, or unverified in a "Verification"
+// section appended to the report; the results are written to
+// output/verification.json and counted in incident.json.
+type VerificationConfig struct {
+	// Enabled turns on output verification.
+	// Default: false
+	// Environment variable: VERIFICATION_ENABLED
+	Enabled bool `mapstructure:"enabled"`
+
+	// CheckRegistries looks image tags the agent cites up in their registries
+	// (anonymous pulls only). Registries are usually external hosts.
+	// Default: false
+	// Environment variable: VERIFICATION_CHECK_REGISTRIES
+	CheckRegistries bool `mapstructure:"check_registries"`
+
+	// TimeoutSeconds bounds the verification pass; unchecked claims stay unverified
+	// (1-600).
+	// Default: 60
+	// Environment variable: VERIFICATION_TIMEOUT_SECONDS
+	TimeoutSeconds int `mapstructure:"timeout_seconds"`
+}
+
+// Timeout returns the verification timeout.
+func (v VerificationConfig) Timeout() time.Duration {
+	return time.Duration(v.TimeoutSeconds) * time.Second
+}
+
+// Validate applies the default timeout and checks its range.
+func (v *VerificationConfig) Validate() error {
+	if !v.Enabled {
+		return nil
+	}
+	if v.TimeoutSeconds == 0 {
+		v.TimeoutSeconds = defaultVerificationTimeoutSeconds
+	}
+	if v.TimeoutSeconds < 1 || v.TimeoutSeconds > maxVerificationTimeoutSeconds {
+		return fmt.Errorf("verification.timeout_seconds must be between 1 and %d, got %d",
+			maxVerificationTimeoutSeconds, v.TimeoutSeconds)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+// Package credexpiry warns before the credentials in the triage kubeconfigs
+// expire, so triage does not silently break when credentials are rotated. It reads
+// the expiry of client certificates and of bearer tokens that are JWTs with an
+// "exp" claim (bound service account tokens, OIDC ID tokens). Credentials fetched
+// by exec or auth-provider plugins are refreshed by the plugin when kubectl runs,
+// and static tokens without an "exp" claim do not expire; both are reported
+// without an expiry.
+package credexpiry
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/kubeconfig"
+)
+
+// Credential kinds
+const (
+	KindClientCertificate = "client-certificate"
+	KindToken             = "token"
+	KindExec              = "exec"
+	KindAuthProvider      = "auth-provider"
+)
+
+// Credential states of a cluster
+const (
+	// StateOK is a cluster whose credentials are valid beyond the warning period
+	// (or do not expire)
+	StateOK = "ok"
+	// StateExpiring is a cluster with a credential expiring within the warning period
+	StateExpiring = "expiring"
+	// StateExpired is a cluster with an expired credential
+	StateExpired = "expired"
+	// StateError is a cluster whose kubeconfig or credentials could not be read
+	StateError = "error"
+)
+
+// Credential is one credential of a kubeconfig user.
+type Credential struct {
+	User string `json:"user"`
+	Kind string `json:"kind"`
+	// Subject is the certificate's common name or the token's "sub" claim
+	Subject string `json:"subject,omitempty"`
+	// ExpiresAt is nil for credentials that do not expire or whose expiry is not
+	// known
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Inspect returns the credentials of every user in the kubeconfig at path.
+func Inspect(path string) ([]Credential, error) {
+	kc, err := kubeconfig.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	var creds []Credential
+	for _, u := range kc.Users {
+		auth := u.User
+		if auth.ClientCertificateData != "" || auth.ClientCertificate != "" {
+			cred, err := inspectCertificate(kc, auth)
+			if err != nil {
+				return nil, fmt.Errorf("user %s: %w", u.Name, err)
+			}
+			cred.User = u.Name
+			creds = append(creds, cred)
+		}
+		if auth.Token != "" || auth.TokenFile != "" {
+			token := auth.Token
+			if token == "" {
+				data, err := os.ReadFile(kc.Path(auth.TokenFile))
+				if err != nil {
+					return nil, fmt.Errorf("user %s: failed to read token file: %w", u.Name, err)
+				}
+				token = strings.TrimSpace(string(data))
+			}
+			cred := inspectToken(token)
+			cred.User = u.Name
+			creds = append(creds, cred)
+		}
+		if auth.Exec != nil {
+			creds = append(creds, Credential{User: u.Name, Kind: KindExec})
+		}
+		if auth.AuthProvider != nil {
+			creds = append(creds, Credential{User: u.Name, Kind: KindAuthProvider})
+		}
+	}
+	return creds, nil
+}
+
+// inspectCertificate reads the expiry of a user's client certificate.
+func inspectCertificate(kc *kubeconfig.Config, auth kubeconfig.AuthInfo) (Credential, error) {
+	var data []byte
+	if auth.ClientCertificateData != "" {
+		decoded, err := base64.StdEncoding.DecodeString(auth.ClientCertificateData)
+		if err != nil {
+			return Credential{}, fmt.Errorf("invalid client-certificate-data: %w", err)
+		}
+		data = decoded
+	} else {
+		read, err := os.ReadFile(kc.Path(auth.ClientCertificate))
+		if err != nil {
+			return Credential{}, fmt.Errorf("failed to read client certificate: %w", err)
+		}
+		data = read
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Credential{}, fmt.Errorf("client certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to parse client certificate: %w", err)
+	}
+	notAfter := cert.NotAfter
+	return Credential{Kind: KindClientCertificate, Subject: cert.Subject.CommonName, ExpiresAt: &notAfter}, nil
+}
+
+// inspectToken reads the "exp" and "sub" claims of a JWT bearer token. Tokens that
+// are not JWTs have no known expiry.
+func inspectToken(token string) Credential {
+	cred := Credential{Kind: KindToken}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return cred
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return cred
+	}
+	var claims struct {
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return cred
+	}
+	cred.Subject = claims.Sub
+	if claims.Exp > 0 {
+		exp := time.Unix(claims.Exp, 0).UTC()
+		cred.ExpiresAt = &exp
+	}
+	return cred
+}
+
+// Status is the credential state of one cluster's triage kubeconfig.
+type Status struct {
+	Cluster    string `json:"cluster"`
+	Kubeconfig string `json:"kubeconfig"`
+	State      string `json:"state"`
+	// Expiring is the credential expiring first, if any credential expires
+	Expiring    *Credential  `json:"expiring,omitempty"`
+	Credentials []Credential `json:"credentials,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	CheckedAt   time.Time    `json:"checked_at"`
+}
+
+// ExpiresIn returns how long until the first credential expires (negative once
+// expired), or 0 when no credential expires.
+func (s Status) ExpiresIn(now time.Time) time.Duration {
+	if s.Expiring == nil {
+		return 0
+	}
+	return s.Expiring.ExpiresAt.Sub(now)
+}
+
+// Checker checks the credentials of the triage kubeconfigs and reports the state
+// of each cluster.
+type Checker struct {
+	kubeconfigs map[string]string // cluster -> kubeconfig path
+	warnBefore  time.Duration
+
+	// OnChange is called when a cluster's state changes, with its previous state
+	// ("" on the first check). Set before Run.
+	OnChange func(ctx context.Context, previous string, status Status)
+
+	now      func() time.Time
+	mu       sync.Mutex
+	statuses map[string]Status
+}
+
+// NewChecker returns a checker of the kubeconfig of each cluster, which reports a
+// cluster as expiring warnBefore its first credential expires.
+func NewChecker(kubeconfigs map[string]string, warnBefore time.Duration) *Checker {
+	return &Checker{
+		kubeconfigs: kubeconfigs,
+		warnBefore:  warnBefore,
+		now:         time.Now,
+		statuses:    make(map[string]Status),
+	}
+}
+
+// Run checks the credentials right away and then every interval until ctx is done.
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	c.Check(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Check(ctx)
+		}
+	}
+}
+
+// Check inspects every cluster's kubeconfig, records its state, and calls
+// OnChange for each cluster whose state changed.
+func (c *Checker) Check(ctx context.Context) {
+	now := c.now()
+	for cluster, path := range c.kubeconfigs {
+		status := c.inspect(cluster, path, now)
+
+		c.mu.Lock()
+		previous := c.statuses[cluster].State
+		c.statuses[cluster] = status
+		c.mu.Unlock()
+
+		log := slog.With("cluster", cluster, "kubeconfig", path)
+		switch status.State {
+		case StateExpiring, StateExpired:
+			log.Warn("cluster credentials need renewal",
+				"state", status.State,
+				"user", status.Expiring.User,
+				"kind", status.Expiring.Kind,
+				"expires_at", status.Expiring.ExpiresAt.Format(time.RFC3339))
+		case StateError:
+			log.Warn("failed to check cluster credentials", "error", status.Error)
+		}
+		if status.State != previous && c.OnChange != nil {
+			c.OnChange(ctx, previous, status)
+		}
+	}
+}
+
+// inspect returns the credential state of one kubeconfig.
+func (c *Checker) inspect(cluster, path string, now time.Time) Status {
+	status := Status{Cluster: cluster, Kubeconfig: path, State: StateOK, CheckedAt: now}
+	creds, err := Inspect(path)
+	if err != nil {
+		status.State = StateError
+		status.Error = err.Error()
+		return status
+	}
+	status.Credentials = creds
+	for i := range creds {
+		cred := &creds[i]
+		if cred.ExpiresAt != nil && (status.Expiring == nil || cred.ExpiresAt.Before(*status.Expiring.ExpiresAt)) {
+			status.Expiring = cred
+		}
+	}
+	if status.Expiring != nil {
+		switch left := status.ExpiresIn(now); {
+		case left <= 0:
+			status.State = StateExpired
+		case left <= c.warnBefore:
+			status.State = StateExpiring
+		}
+	}
+	return status
+}
+
+// Statuses returns the latest state of every checked cluster, sorted by cluster.
+func (c *Checker) Statuses() []Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	statuses := make([]Status, 0, len(c.statuses))
+	for _, s := range c.statuses {
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Cluster < statuses[j].Cluster })
+	return statuses
+}
+
+// GetCredentialsHealth implements health.CredentialsHealth.
+func (c *Checker) GetCredentialsHealth() interface{} {
+	statuses := c.Statuses()
+	attention := 0
+	for _, s := range statuses {
+		if s.State != StateOK {
+			attention++
+		}
+	}
+	return map[string]interface{}{
+		"warn_before_days": int(c.warnBefore.Hours() / 24),
+		"needs_attention":  attention,
+		"clusters":         statuses,
+	}
+}
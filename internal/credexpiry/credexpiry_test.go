@@ -0,0 +1,151 @@
+package credexpiry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCertificate returns a PEM client certificate for cn expiring at notAfter.
+func testCertificate(t *testing.T, cn string, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// testToken returns an unsigned JWT with the given claims.
+func testToken(claims string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"RS256"}`)) + "." + enc.EncodeToString([]byte(claims)) + ".c2ln"
+}
+
+// writeKubeconfig writes a kubeconfig with a certificate user, a token user, and
+// an exec user.
+func writeKubeconfig(t *testing.T, dir string, certExpiry, tokenExpiry time.Time) string {
+	t.Helper()
+	cert := testCertificate(t, "nightcrier-triage", certExpiry)
+	if err := os.WriteFile(filepath.Join(dir, "client.crt"), cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	token := testToken(fmt.Sprintf(`{"sub":"system:serviceaccount:nightcrier:triage","exp":%d}`, tokenExpiry.Unix()))
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+users:
+- name: cert
+  user:
+    client-certificate: client.crt
+- name: sa
+  user:
+    token: %s
+- name: eks
+  user:
+    exec:
+      command: aws
+`, token)
+	path := filepath.Join(dir, "kubeconfig")
+	if err := os.WriteFile(path, []byte(kubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestInspect(t *testing.T) {
+	certExpiry := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second).UTC()
+	tokenExpiry := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	path := writeKubeconfig(t, t.TempDir(), certExpiry, tokenExpiry)
+
+	creds, err := Inspect(path)
+	if err != nil {
+		t.Fatalf("Inspect() failed: %v", err)
+	}
+	if len(creds) != 3 {
+		t.Fatalf("Inspect() = %+v, want 3 credentials", creds)
+	}
+	if c := creds[0]; c.Kind != KindClientCertificate || c.Subject != "nightcrier-triage" || !c.ExpiresAt.Equal(certExpiry) {
+		t.Errorf("certificate = %+v, want nightcrier-triage expiring at %s", c, certExpiry)
+	}
+	if c := creds[1]; c.Kind != KindToken || c.Subject != "system:serviceaccount:nightcrier:triage" || !c.ExpiresAt.Equal(tokenExpiry) {
+		t.Errorf("token = %+v, want the service account expiring at %s", c, tokenExpiry)
+	}
+	if c := creds[2]; c.Kind != KindExec || c.ExpiresAt != nil {
+		t.Errorf("exec = %+v, want no expiry", c)
+	}
+
+	if c := inspectToken("static-token"); c.ExpiresAt != nil {
+		t.Errorf("inspectToken() of a static token = %+v, want no expiry", c)
+	}
+}
+
+func TestChecker(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	path := writeKubeconfig(t, dir, now.Add(60*24*time.Hour), now.Add(10*24*time.Hour))
+
+	checker := NewChecker(map[string]string{
+		"prod":    path,
+		"missing": filepath.Join(dir, "missing"),
+	}, 14*24*time.Hour)
+	checker.now = func() time.Time { return now }
+	var changes []string
+	checker.OnChange = func(ctx context.Context, previous string, status Status) {
+		changes = append(changes, fmt.Sprintf("%s:%s->%s", status.Cluster, previous, status.State))
+	}
+
+	checker.Check(context.Background())
+	statuses := checker.Statuses()
+	if len(statuses) != 2 || statuses[0].State != StateError || statuses[1].State != StateExpiring {
+		t.Fatalf("Statuses() = %+v, want missing in error and prod expiring", statuses)
+	}
+	if expiring := statuses[1].Expiring; expiring == nil || expiring.User != "sa" {
+		t.Errorf("expiring credential = %+v, want the service account token", expiring)
+	}
+	if len(changes) != 2 {
+		t.Errorf("changes = %v, want one per cluster on the first check", changes)
+	}
+
+	// No notification while the state stays the same
+	changes = nil
+	checker.Check(context.Background())
+	if len(changes) != 0 {
+		t.Errorf("changes = %v, want none", changes)
+	}
+
+	// The token expires
+	checker.now = func() time.Time { return now.Add(11 * 24 * time.Hour) }
+	checker.Check(context.Background())
+	if len(changes) != 1 || changes[0] != "prod:expiring->expired" {
+		t.Errorf("changes = %v, want prod expired", changes)
+	}
+
+	// The credentials are rotated
+	changes = nil
+	writeKubeconfig(t, dir, now.Add(90*24*time.Hour), now.Add(90*24*time.Hour))
+	checker.Check(context.Background())
+	if len(changes) != 1 || changes[0] != "prod:expired->ok" {
+		t.Errorf("changes = %v, want prod renewed", changes)
+	}
+}
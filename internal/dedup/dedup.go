@@ -0,0 +1,156 @@
+// Package dedup suppresses repeats of the same fault so flapping workloads do not
+// start an investigation on every recurrence.
+//
+// The Adaptive deduplicator keeps a window per fault signature. A fault is
+// admitted when no admitted occurrence of its signature falls within the window;
+// every repeat that is suppressed grows the window by a factor, up to a maximum.
+// A fault that recurs faster than its window therefore earns a longer one (a pod
+// crashing every 2 minutes climbs from 5 minutes to an hour), while a signature
+// that stays quiet for longer than its window starts over at the minimum.
+package dedup
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often signatures that have gone quiet are forgotten
+const sweepInterval = time.Minute
+
+// Config bounds the adaptive window.
+type Config struct {
+	// MinWindow is the window of a signature seen for the first time or after a
+	// quiet period.
+	MinWindow time.Duration
+	// MaxWindow caps the window of a flapping signature.
+	MaxWindow time.Duration
+	// GrowthFactor multiplies the window each time a repeat is suppressed.
+	GrowthFactor float64
+}
+
+// Decision is the outcome of checking one occurrence of a fault.
+type Decision struct {
+	// Allowed is true when the occurrence should be investigated.
+	Allowed bool
+	// Window is the signature's dedup window after this occurrence.
+	Window time.Duration
+	// Suppressed is the number of repeats suppressed since the last admitted
+	// occurrence (for an admitted occurrence, the repeats it follows up on).
+	Suppressed int
+}
+
+// signatureState tracks one fault signature. It is also the JSON document of a
+// signature in the fleet-wide state (see Shared).
+type signatureState struct {
+	Window       time.Duration `json:"window"`
+	LastSeen     time.Time     `json:"last_seen"`
+	LastAdmitted time.Time     `json:"last_admitted"`
+	Suppressed   int           `json:"suppressed"`
+}
+
+// check records an occurrence at now on a known signature and decides whether it
+// is admitted.
+func (s *signatureState) check(cfg Config, now time.Time) Decision {
+	// Quiet for longer than the window: start over
+	if now.Sub(s.LastSeen) >= s.Window {
+		s.Window = cfg.MinWindow
+	}
+	s.LastSeen = now
+
+	if now.Sub(s.LastAdmitted) < s.Window {
+		s.Suppressed++
+		s.Window = cfg.grow(s.Window)
+		return Decision{Allowed: false, Window: s.Window, Suppressed: s.Suppressed}
+	}
+
+	decision := Decision{Allowed: true, Window: s.Window, Suppressed: s.Suppressed}
+	s.LastAdmitted = now
+	s.Suppressed = 0
+	return decision
+}
+
+// newSignatureState returns the state of a signature first seen at now.
+func newSignatureState(cfg Config, now time.Time) *signatureState {
+	return &signatureState{Window: cfg.MinWindow, LastSeen: now, LastAdmitted: now}
+}
+
+// Adaptive is a deduplicator whose window per fault signature grows with the
+// signature's recurrence frequency. It is safe for concurrent use.
+type Adaptive struct {
+	cfg Config
+
+	mu         sync.Mutex
+	signatures map[string]*signatureState
+	lastSweep  time.Time
+
+	// now is replaceable for tests
+	now func() time.Time
+}
+
+// NewAdaptive creates an adaptive deduplicator.
+func NewAdaptive(cfg Config) *Adaptive {
+	return &Adaptive{
+		cfg:        cfg.withDefaults(),
+		signatures: make(map[string]*signatureState),
+		now:        time.Now,
+	}
+}
+
+// Check records an occurrence of the fault signature and reports whether it
+// should be investigated.
+func (a *Adaptive) Check(signature string) Decision {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	a.sweep(now)
+
+	s, ok := a.signatures[signature]
+	if !ok {
+		a.signatures[signature] = newSignatureState(a.cfg, now)
+		return Decision{Allowed: true, Window: a.cfg.MinWindow}
+	}
+	return s.check(a.cfg, now)
+}
+
+// Len returns the number of tracked fault signatures.
+func (a *Adaptive) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.signatures)
+}
+
+// withDefaults returns the config with the maximum window at least the minimum
+// and a growth factor above 1.
+func (c Config) withDefaults() Config {
+	if c.MaxWindow < c.MinWindow {
+		c.MaxWindow = c.MinWindow
+	}
+	if c.GrowthFactor <= 1 {
+		c.GrowthFactor = 2
+	}
+	return c
+}
+
+// grow returns the next window after a suppressed repeat.
+func (c Config) grow(window time.Duration) time.Duration {
+	next := time.Duration(float64(window) * c.GrowthFactor)
+	if next > c.MaxWindow || next < window {
+		return c.MaxWindow
+	}
+	return next
+}
+
+// sweep forgets signatures that have been quiet for longer than the maximum
+// window; their next occurrence would start over at the minimum window anyway.
+func (a *Adaptive) sweep(now time.Time) {
+	if now.Sub(a.lastSweep) < sweepInterval {
+		return
+	}
+	a.lastSweep = now
+	for signature, s := range a.signatures {
+		if now.Sub(s.LastSeen) >= a.cfg.MaxWindow {
+			delete(a.signatures, signature)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package dedup
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestAdaptive(cfg Config) (*Adaptive, *time.Time) {
+	a := NewAdaptive(cfg)
+	clock := time.Date(2024, 6, 13, 0, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return clock }
+	return a, &clock
+}
+
+func TestAdaptiveGrowsWindowForFlappingFault(t *testing.T) {
+	a, clock := newTestAdaptive(Config{MinWindow: 5 * time.Minute, MaxWindow: time.Hour, GrowthFactor: 2})
+
+	// A pod crashing every 2 minutes for 3 hours
+	var admitted []time.Duration
+	start := *clock
+	for i := 0; i < 90; i++ {
+		if d := a.Check("crashloop"); d.Allowed {
+			admitted = append(admitted, clock.Sub(start))
+		}
+		*clock = clock.Add(2 * time.Minute)
+	}
+
+	// The first occurrence is investigated, then the window climbs to the hour cap
+	if len(admitted) == 0 || admitted[0] != 0 {
+		t.Fatalf("admitted = %v, want the first occurrence admitted", admitted)
+	}
+	if len(admitted) > 5 {
+		t.Errorf("admitted %d of 90 occurrences (%v), want the window to grow", len(admitted), admitted)
+	}
+	for i := 2; i < len(admitted); i++ {
+		if gap := admitted[i] - admitted[i-1]; gap < time.Hour {
+			t.Errorf("admissions %v and %v are %v apart, want the 1h maximum window", admitted[i-1], admitted[i], gap)
+		}
+	}
+}
+
+func TestAdaptiveResetsAfterQuietPeriod(t *testing.T) {
+	a, clock := newTestAdaptive(Config{MinWindow: 5 * time.Minute, MaxWindow: time.Hour, GrowthFactor: 2})
+
+	a.Check("sig")
+	*clock = clock.Add(time.Minute)
+	if d := a.Check("sig"); d.Allowed || d.Window != 10*time.Minute || d.Suppressed != 1 {
+		t.Errorf("repeat = %+v, want suppressed with a 10m window", d)
+	}
+
+	// Quiet for longer than the window: the next occurrence is admitted at the minimum window
+	*clock = clock.Add(11 * time.Minute)
+	if d := a.Check("sig"); !d.Allowed || d.Window != 5*time.Minute || d.Suppressed != 1 {
+		t.Errorf("after quiet period = %+v, want admitted at the 5m window, reporting 1 suppressed repeat", d)
+	}
+
+	// A slow recurrence never grows the window
+	for i := 0; i < 5; i++ {
+		*clock = clock.Add(6 * time.Minute)
+		if d := a.Check("sig"); !d.Allowed || d.Window != 5*time.Minute {
+			t.Errorf("slow recurrence %d = %+v, want admitted at the 5m window", i, d)
+		}
+	}
+
+	// Other signatures are independent
+	if d := a.Check("other"); !d.Allowed {
+		t.Error("a new signature should be admitted")
+	}
+}
+
+func TestAdaptiveForgetsQuietSignatures(t *testing.T) {
+	a, clock := newTestAdaptive(Config{MinWindow: 5 * time.Minute, MaxWindow: time.Hour})
+	a.Check("a")
+	a.Check("b")
+	*clock = clock.Add(time.Hour)
+	a.Check("c")
+	if a.Len() != 1 {
+		t.Errorf("Len() = %d, want quiet signatures forgotten", a.Len())
+	}
+}
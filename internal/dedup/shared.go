@@ -0,0 +1,63 @@
+package dedup
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/fleet"
+)
+
+// sharedTimeout bounds one check against the fleet-wide state
+const sharedTimeout = 5 * time.Second
+
+// Deduplicator decides whether an occurrence of a fault should be investigated.
+type Deduplicator interface {
+	Check(signature string) Decision
+}
+
+// Shared is an adaptive deduplicator whose windows are shared by every nightcrier
+// process using the same state store, so a fault flapping on a cluster watched by
+// several processes is admitted once per window across the fleet. When the shared
+// state cannot be read or written, it falls back to a per-process Adaptive.
+type Shared struct {
+	cfg   Config
+	state *fleet.State
+	local *Adaptive
+}
+
+// NewShared creates a deduplicator keeping its windows in the shared state.
+func NewShared(cfg Config, state *fleet.State) *Shared {
+	return &Shared{
+		cfg:   cfg.withDefaults(),
+		state: state,
+		local: NewAdaptive(cfg),
+	}
+}
+
+// Check records an occurrence of the fault signature and reports whether it
+// should be investigated.
+func (s *Shared) Check(signature string) Decision {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+	defer cancel()
+
+	var decision Decision
+	// A signature quiet for longer than the maximum window starts over, so its
+	// state is kept that long
+	err := fleet.Update(ctx, s.state, "dedup/"+signature, s.cfg.MaxWindow, func(current *signatureState) (*signatureState, error) {
+		now := s.state.Now()
+		if current == nil {
+			decision = Decision{Allowed: true, Window: s.cfg.MinWindow}
+			return newSignatureState(s.cfg, now), nil
+		}
+		decision = current.check(s.cfg, now)
+		return current, nil
+	})
+	if err != nil {
+		slog.Warn("fleet-wide dedup unavailable, deduplicating per process",
+			"fault_signature", signature,
+			"error", err)
+		return s.local.Check(signature)
+	}
+	return decision
+}
@@ -0,0 +1,28 @@
+package dedup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/fleet"
+)
+
+func TestSharedWindowsAreFleetWide(t *testing.T) {
+	state := fleet.NewState(fleet.NewMemoryBackend())
+	cfg := Config{MinWindow: 5 * time.Minute, MaxWindow: time.Hour, GrowthFactor: 2}
+	first, second := NewShared(cfg, state), NewShared(cfg, state)
+
+	if d := first.Check("crashloop"); !d.Allowed {
+		t.Fatalf("first occurrence = %+v, want admitted", d)
+	}
+	// The same fault reported to another process within the window is suppressed
+	if d := second.Check("crashloop"); d.Allowed || d.Suppressed != 1 || d.Window != 10*time.Minute {
+		t.Errorf("repeat on another process = %+v, want suppressed with a 10m window", d)
+	}
+	if d := first.Check("crashloop"); d.Allowed || d.Suppressed != 2 || d.Window != 20*time.Minute {
+		t.Errorf("second repeat = %+v, want suppressed with a 20m window", d)
+	}
+	if d := second.Check("other"); !d.Allowed {
+		t.Errorf("other signature = %+v, want admitted", d)
+	}
+}
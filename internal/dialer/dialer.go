@@ -0,0 +1,124 @@
+// Package dialer builds network dialers for outbound MCP connections.
+// It adds control over IP family selection, Happy Eyeballs (RFC 6555) fallback,
+// and custom DNS resolvers so that nightcrier can reach dual-stack and IPv6-only
+// MCP endpoints and recover when an endpoint's address changes behind a load balancer.
+//
+// Go does not cache DNS results, so every new dial performs a fresh lookup; the
+// remaining piece of re-resolution is making sure pooled keep-alive connections to
+// an old address are discarded before reconnecting (see events.Client.Subscribe).
+package dialer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// IP family values for Config.IPFamily.
+const (
+	IPFamilyAny  = "any"
+	IPFamilyIPv4 = "ipv4"
+	IPFamilyIPv6 = "ipv6"
+)
+
+// Config describes how outbound connections are dialed.
+type Config struct {
+	// IPFamily restricts connections to "ipv4", "ipv6", or "any" (default).
+	IPFamily string
+
+	// FallbackDelay is the Happy Eyeballs delay before racing the fallback address family.
+	// Zero uses the Go default (300ms); a negative value disables Happy Eyeballs.
+	FallbackDelay time.Duration
+
+	// DNSServer is an optional "host:port" DNS server used instead of the system resolver.
+	DNSServer string
+
+	// DNSTimeout bounds each query to DNSServer. Zero defaults to 5 seconds.
+	DNSTimeout time.Duration
+
+	// ConnectTimeout bounds establishing a TCP connection. Zero defaults to 30 seconds.
+	ConnectTimeout time.Duration
+
+	// KeepAlive is the TCP keep-alive period. Zero defaults to 30 seconds.
+	KeepAlive time.Duration
+}
+
+// Validate checks the dialer configuration.
+func (c Config) Validate() error {
+	switch strings.ToLower(c.IPFamily) {
+	case "", IPFamilyAny, IPFamilyIPv4, IPFamilyIPv6:
+	default:
+		return fmt.Errorf("invalid ip_family %q: must be 'any', 'ipv4', or 'ipv6'", c.IPFamily)
+	}
+
+	if c.DNSServer != "" {
+		if _, _, err := net.SplitHostPort(c.DNSServer); err != nil {
+			return fmt.Errorf("invalid dns_server %q: must be host:port: %w", c.DNSServer, err)
+		}
+	}
+
+	return nil
+}
+
+// NewDialer returns a net.Dialer configured from c.
+func NewDialer(c Config) *net.Dialer {
+	connectTimeout := c.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = 30 * time.Second
+	}
+	keepAlive := c.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = 30 * time.Second
+	}
+
+	d := &net.Dialer{
+		Timeout:       connectTimeout,
+		KeepAlive:     keepAlive,
+		FallbackDelay: c.FallbackDelay,
+	}
+
+	if c.DNSServer != "" {
+		dnsTimeout := c.DNSTimeout
+		if dnsTimeout == 0 {
+			dnsTimeout = 5 * time.Second
+		}
+		server := c.DNSServer
+		d.Resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				rd := net.Dialer{Timeout: dnsTimeout}
+				return rd.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	return d
+}
+
+// DialContext returns a DialContext function suitable for http.Transport that
+// honors the configured IP family.
+func DialContext(c Config) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	d := NewDialer(c)
+	family := strings.ToLower(c.IPFamily)
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return d.DialContext(ctx, restrictNetwork(network, family), addr)
+	}
+}
+
+// restrictNetwork maps a generic network ("tcp") to a family-specific one ("tcp4"/"tcp6").
+func restrictNetwork(network, family string) string {
+	if network != "tcp" {
+		return network
+	}
+	switch family {
+	case IPFamilyIPv4:
+		return "tcp4"
+	case IPFamilyIPv6:
+		return "tcp6"
+	default:
+		return network
+	}
+}
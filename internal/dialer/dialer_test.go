@@ -0,0 +1,73 @@
+package dialer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"defaults", Config{}, false},
+		{"ipv6", Config{IPFamily: "ipv6"}, false},
+		{"invalid family", Config{IPFamily: "ipx"}, true},
+		{"dns server with port", Config{DNSServer: "[fd00::10]:53"}, false},
+		{"dns server without port", Config{DNSServer: "10.0.0.10"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRestrictNetwork(t *testing.T) {
+	if got := restrictNetwork("tcp", IPFamilyIPv4); got != "tcp4" {
+		t.Errorf("restrictNetwork(tcp, ipv4) = %q, want tcp4", got)
+	}
+	if got := restrictNetwork("tcp", IPFamilyIPv6); got != "tcp6" {
+		t.Errorf("restrictNetwork(tcp, ipv6) = %q, want tcp6", got)
+	}
+	if got := restrictNetwork("tcp", IPFamilyAny); got != "tcp" {
+		t.Errorf("restrictNetwork(tcp, any) = %q, want tcp", got)
+	}
+	if got := restrictNetwork("udp", IPFamilyIPv4); got != "udp" {
+		t.Errorf("restrictNetwork(udp, ipv4) = %q, want udp", got)
+	}
+}
+
+func TestNewDialer_CustomResolver(t *testing.T) {
+	d := NewDialer(Config{DNSServer: "127.0.0.1:53", FallbackDelay: -1})
+	if d.Resolver == nil || !d.Resolver.PreferGo {
+		t.Error("custom DNS server should install a Go resolver")
+	}
+	if d.FallbackDelay >= 0 {
+		t.Errorf("FallbackDelay = %v, want negative (Happy Eyeballs disabled)", d.FallbackDelay)
+	}
+	if d.Timeout != 30*time.Second {
+		t.Errorf("Timeout = %v, want default 30s", d.Timeout)
+	}
+}
+
+func TestDialContext_IPv4Loopback(t *testing.T) {
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on IPv4 loopback: %v", err)
+	}
+	defer ln.Close()
+
+	dial := DialContext(Config{IPFamily: IPFamilyIPv4})
+	conn, err := dial(context.Background(), "tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	conn.Close()
+}
@@ -0,0 +1,142 @@
+// Package egress describes the network access of agent containers under egress
+// control: the agent container joins a Docker network without a route to the
+// outside (docker network create --internal), and all of its HTTP(S) traffic goes
+// through an egress proxy on that network which only forwards to the LLM endpoint
+// and the Kubernetes API servers. The proxy enforces the allow-list; nightcrier
+// computes it, points the agent at the proxy, and records the policy on each
+// incident for security review.
+package egress
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/kubeconfig"
+)
+
+// ModeProxy is the egress mode confining the agent to an allow-listing proxy
+const ModeProxy = "proxy"
+
+// Policy is the network access an investigation's agent ran with.
+type Policy struct {
+	// Mode is the egress control mode (always "proxy" for now)
+	Mode string `json:"mode"`
+	// Network is the Docker network the agent container joined
+	Network string `json:"network"`
+	// Proxy is the egress proxy URL with any credentials redacted
+	Proxy string `json:"proxy"`
+	// AllowedHosts are the "host:port" destinations the proxy forwards to, sorted
+	AllowedHosts []string `json:"allowedHosts"`
+}
+
+// NewPolicy returns the policy of an agent on network sending its traffic through
+// proxyURL, allowed to reach hosts ("host:port", duplicates are dropped).
+func NewPolicy(network, proxyURL string, hosts ...[]string) *Policy {
+	p := &Policy{Mode: ModeProxy, Network: network, Proxy: redact(proxyURL)}
+	seen := make(map[string]bool)
+	for _, list := range hosts {
+		for _, h := range list {
+			if h != "" && !seen[h] {
+				seen[h] = true
+				p.AllowedHosts = append(p.AllowedHosts, h)
+			}
+		}
+	}
+	sort.Strings(p.AllowedHosts)
+	return p
+}
+
+// redact hides the password of a proxy URL.
+func redact(proxyURL string) string {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return proxyURL
+	}
+	return u.Redacted()
+}
+
+// HostPort returns the "host:port" an http(s) URL connects to, with the scheme's
+// default port when it has none.
+func HostPort(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid URL %q", rawURL)
+	}
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		default:
+			return "", fmt.Errorf("invalid URL %q: scheme must be http or https", rawURL)
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// KubeAPIHosts returns the "host:port" of every API server in a kubeconfig, so the
+// agent can reach its cluster whichever context it uses.
+func KubeAPIHosts(kubeconfigPath string) ([]string, error) {
+	kc, err := kubeconfig.Load(kubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	for _, server := range kc.Servers() {
+		host, err := HostPort(server)
+		if err != nil {
+			return nil, fmt.Errorf("kubeconfig %s: %w", kubeconfigPath, err)
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// WriteAllowlist writes the host names allowed by policies to path, one per line
+// and sorted, in the format of squid's dstdomain ACL files and tinyproxy's filter
+// files. The file is replaced atomically, so a proxy reloading it never reads a
+// partial list.
+func WriteAllowlist(path string, policies ...*Policy) error {
+	seen := make(map[string]bool)
+	var names []string
+	for _, p := range policies {
+		for _, hostPort := range p.AllowedHosts {
+			host, _, err := net.SplitHostPort(hostPort)
+			if err != nil {
+				host = hostPort
+			}
+			if !seen[host] {
+				seen[host] = true
+				names = append(names, host)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".allowlist-*")
+	if err != nil {
+		return fmt.Errorf("failed to write egress allow-list: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(strings.Join(names, "\n") + "\n"); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write egress allow-list: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write egress allow-list: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write egress allow-list: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write egress allow-list: %w", err)
+	}
+	return nil
+}
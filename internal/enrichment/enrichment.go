@@ -0,0 +1,264 @@
+// Package enrichment collects context about a fault through the cluster's
+// kubernetes-mcp-server: the recent Kubernetes events of the affected object and,
+// for pods, the tail of its logs. It is the fallback for clusters without
+// kubeconfig triage, whose incidents would otherwise only record the fault event.
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// eventsListTool is the kubernetes-mcp-server tool listing Kubernetes events
+	eventsListTool = "events_list"
+	// podsLogTool is the kubernetes-mcp-server tool returning a pod's logs
+	podsLogTool = "pods_log"
+
+	// kubeEventTimeLayout is how kubernetes-mcp-server formats event timestamps
+	// (Go's time.Time.String)
+	kubeEventTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+)
+
+// Annotations holding the collected context on the incident
+const (
+	EventsAnnotation = "mcp-events"
+	LogsAnnotation   = "mcp-logs"
+)
+
+// maxAnnotationLength is the longest annotation value the state store accepts
+const maxAnnotationLength = 4096
+
+// Default limits on the collected context
+const (
+	DefaultMaxEvents = 20
+	DefaultLogLines  = 50
+)
+
+// Config configures an enricher.
+type Config struct {
+	// Endpoints maps cluster names to their MCP server endpoints
+	Endpoints map[string]string
+	// MaxEvents is how many of the object's most recent events are kept.
+	// Default: DefaultMaxEvents.
+	MaxEvents int
+	// LogLines is how many lines of a pod's log are kept. Default: DefaultLogLines.
+	LogLines int
+}
+
+// Target identifies the object affected by a fault.
+type Target struct {
+	Cluster   string
+	Namespace string
+	Kind      string
+	Name      string
+}
+
+// Event is a Kubernetes event of the affected object.
+type Event struct {
+	Time    time.Time
+	Type    string
+	Reason  string
+	Message string
+}
+
+// String returns the event as one line, e.g.
+// "2024-06-13T10:00:00Z Warning BackOff: Back-off restarting failed container".
+func (ev Event) String() string {
+	return fmt.Sprintf("%s %s %s: %s", ev.Time.UTC().Format(time.RFC3339), ev.Type, ev.Reason, ev.Message)
+}
+
+// Result is the context collected about a fault.
+type Result struct {
+	// Events are the object's most recent events, oldest first
+	Events []Event
+	// Logs is the tail of the pod's log (of its previous container when the
+	// current one has not logged yet, e.g. in a crash loop); empty for other kinds
+	Logs string
+}
+
+// Empty reports whether nothing was collected.
+func (r *Result) Empty() bool {
+	return r == nil || (len(r.Events) == 0 && r.Logs == "")
+}
+
+// Annotations returns the result as incident annotations: one event per line
+// under EventsAnnotation and the log tail under LogsAnnotation, each cut to the
+// longest value the state store accepts (keeping the newest lines).
+func (r *Result) Annotations() map[string]string {
+	annotations := make(map[string]string)
+	if len(r.Events) > 0 {
+		lines := make([]string, len(r.Events))
+		for i, ev := range r.Events {
+			lines[i] = ev.String()
+		}
+		annotations[EventsAnnotation] = keepTail(strings.Join(lines, "\n"), maxAnnotationLength)
+	}
+	if r.Logs != "" {
+		annotations[LogsAnnotation] = keepTail(r.Logs, maxAnnotationLength)
+	}
+	return annotations
+}
+
+// Enricher collects fault context through the clusters' MCP servers.
+type Enricher struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// New returns an enricher. A nil httpClient uses http.DefaultClient.
+func New(config Config, httpClient *http.Client) *Enricher {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if config.MaxEvents <= 0 {
+		config.MaxEvents = DefaultMaxEvents
+	}
+	if config.LogLines <= 0 {
+		config.LogLines = DefaultLogLines
+	}
+	return &Enricher{config: config, httpClient: httpClient}
+}
+
+// Collect returns the recent events of the target and, for a pod, the tail of its
+// log. It returns nil when the cluster has no MCP endpoint. A failure of one tool
+// does not discard what the other returned; it is only returned when nothing was
+// collected.
+func (e *Enricher) Collect(ctx context.Context, t Target) (*Result, error) {
+	endpoint, ok := e.config.Endpoints[t.Cluster]
+	if !ok || endpoint == "" || t.Name == "" {
+		return nil, nil
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "nightcrier-enrichment", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{
+		Endpoint:   endpoint,
+		HTTPClient: e.httpClient,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
+	}
+	defer session.Close()
+
+	result := &Result{}
+	var errs []string
+	if result.Events, err = e.objectEvents(ctx, session, t); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if t.Kind == "Pod" {
+		if result.Logs, err = e.podLogs(ctx, session, t); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if result.Empty() && len(errs) > 0 {
+		return nil, fmt.Errorf("failed to collect fault context: %s", strings.Join(errs, "; "))
+	}
+	return result, nil
+}
+
+// kubeEvent is a Kubernetes event as listed by kubernetes-mcp-server.
+type kubeEvent struct {
+	Timestamp      string `yaml:"Timestamp"`
+	Type           string `yaml:"Type"`
+	Reason         string `yaml:"Reason"`
+	Message        string `yaml:"Message"`
+	InvolvedObject struct {
+		Kind string `yaml:"Kind"`
+		Name string `yaml:"Name"`
+	} `yaml:"InvolvedObject"`
+}
+
+// objectEvents returns the most recent events involving the target, oldest first.
+func (e *Enricher) objectEvents(ctx context.Context, session *mcp.ClientSession, t Target) ([]Event, error) {
+	text, err := callTool(ctx, session, eventsListTool, map[string]any{"namespace": t.Namespace})
+	if err != nil {
+		return nil, err
+	}
+	var listed []kubeEvent
+	if err := yaml.Unmarshal([]byte(text), &listed); err != nil {
+		return nil, fmt.Errorf("failed to parse %s result: %w", eventsListTool, err)
+	}
+
+	var found []Event
+	for _, ev := range listed {
+		if ev.InvolvedObject.Name != t.Name || (t.Kind != "" && ev.InvolvedObject.Kind != t.Kind) {
+			continue
+		}
+		ts, _ := time.Parse(kubeEventTimeLayout, ev.Timestamp)
+		found = append(found, Event{
+			Time:    ts,
+			Type:    ev.Type,
+			Reason:  ev.Reason,
+			Message: strings.TrimSpace(ev.Message),
+		})
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Time.Before(found[j].Time) })
+	if len(found) > e.config.MaxEvents {
+		found = found[len(found)-e.config.MaxEvents:]
+	}
+	return found, nil
+}
+
+// podLogs returns the tail of the pod's log, falling back to its previous
+// container when the current one has no log (a crash-looping container is often
+// waiting to restart).
+func (e *Enricher) podLogs(ctx context.Context, session *mcp.ClientSession, t Target) (string, error) {
+	args := map[string]any{"namespace": t.Namespace, "name": t.Name, "tail": e.config.LogLines}
+	logs, err := callTool(ctx, session, podsLogTool, args)
+	if err != nil || strings.TrimSpace(logs) == "" {
+		args["previous"] = true
+		if previous, prevErr := callTool(ctx, session, podsLogTool, args); prevErr == nil {
+			logs, err = previous, nil
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return lastLines(strings.TrimRight(logs, "\n"), e.config.LogLines), nil
+}
+
+// callTool calls an MCP tool and returns its text content.
+func callTool(ctx context.Context, session *mcp.ClientSession, tool string, args map[string]any) (string, error) {
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: tool, Arguments: args})
+	if err != nil {
+		return "", fmt.Errorf("failed to call %s: %w", tool, err)
+	}
+	var text strings.Builder
+	for _, content := range result.Content {
+		if textContent, ok := content.(*mcp.TextContent); ok {
+			text.WriteString(textContent.Text)
+		}
+	}
+	if result.IsError {
+		return "", fmt.Errorf("%s returned error: %s", tool, text.String())
+	}
+	return text.String(), nil
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// keepTail cuts s to its last max bytes, at a line boundary when there is one.
+func keepTail(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[len(s)-max:]
+	if i := strings.IndexByte(s, '\n'); i >= 0 && i < len(s)-1 {
+		s = s[i+1:]
+	}
+	return s
+}
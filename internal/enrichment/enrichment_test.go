@@ -0,0 +1,122 @@
+package enrichment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// eventsListArgs are the arguments of the events_list tool
+type eventsListArgs struct {
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// podsLogArgs are the arguments of the pods_log tool
+type podsLogArgs struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Tail      int    `json:"tail,omitempty"`
+	Previous  bool   `json:"previous,omitempty"`
+}
+
+// newFakeMCPServer serves events_list from events and pods_log from logs, keyed
+// by pod name (with a "previous/" prefix for the previous container).
+func newFakeMCPServer(t *testing.T, events string, logs map[string]string) *httptest.Server {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "fake-kubernetes-mcp-server", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: eventsListTool}, func(ctx context.Context, req *mcp.CallToolRequest, args eventsListArgs) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: events}}}, nil, nil
+	})
+	mcp.AddTool(server, &mcp.Tool{Name: podsLogTool}, func(ctx context.Context, req *mcp.CallToolRequest, args podsLogArgs) (*mcp.CallToolResult, any, error) {
+		key := args.Name
+		if args.Previous {
+			key = "previous/" + key
+		}
+		log, ok := logs[key]
+		if !ok {
+			return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "pod not found"}}}, nil, nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: log}}}, nil, nil
+	})
+	srv := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+const testEvents = `- InvolvedObject:
+    Kind: Pod
+    Name: api-1
+  Message: Back-off restarting failed container
+  Reason: BackOff
+  Timestamp: 2026-01-10 02:32:00 +0000 UTC
+  Type: Warning
+- InvolvedObject:
+    Kind: Pod
+    Name: api-1
+  Message: Started container api
+  Reason: Started
+  Timestamp: 2026-01-10 02:30:00 +0000 UTC
+  Type: Normal
+- InvolvedObject:
+    Kind: Pod
+    Name: db-0
+  Message: Liveness probe failed
+  Reason: Unhealthy
+  Timestamp: 2026-01-10 02:31:00 +0000 UTC
+  Type: Warning
+`
+
+func TestCollect(t *testing.T) {
+	srv := newFakeMCPServer(t, testEvents, map[string]string{
+		"api-1":          "",
+		"previous/api-1": "starting\nloading config\npanic: missing DATABASE_URL\n",
+	})
+	enricher := New(Config{Endpoints: map[string]string{"prod": srv.URL}, LogLines: 2}, nil)
+
+	result, err := enricher.Collect(context.Background(), Target{Cluster: "prod", Namespace: "shop", Kind: "Pod", Name: "api-1"})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(result.Events) != 2 || result.Events[0].Reason != "Started" || result.Events[1].Reason != "BackOff" {
+		t.Errorf("Collect() events = %+v, want the pod's two events, oldest first", result.Events)
+	}
+	if result.Logs != "loading config\npanic: missing DATABASE_URL" {
+		t.Errorf("Collect() logs = %q, want the last 2 lines of the previous container", result.Logs)
+	}
+
+	annotations := result.Annotations()
+	if !strings.HasPrefix(annotations[EventsAnnotation], "2026-01-10T02:30:00Z Normal Started: Started container api\n") {
+		t.Errorf("events annotation = %q", annotations[EventsAnnotation])
+	}
+	if annotations[LogsAnnotation] != result.Logs {
+		t.Errorf("logs annotation = %q, want %q", annotations[LogsAnnotation], result.Logs)
+	}
+
+	// A deployment has events but no logs of its own
+	result, err = enricher.Collect(context.Background(), Target{Cluster: "prod", Namespace: "shop", Kind: "Deployment", Name: "api"})
+	if err != nil || !result.Empty() {
+		t.Errorf("Collect(Deployment) = %+v, %v, want an empty result", result, err)
+	}
+
+	// Clusters without an MCP endpoint are skipped
+	if result, err := enricher.Collect(context.Background(), Target{Cluster: "staging", Kind: "Pod", Name: "api-1"}); result != nil || err != nil {
+		t.Errorf("Collect(unknown cluster) = %+v, %v, want nil", result, err)
+	}
+}
+
+func TestAnnotationsKeepNewestLines(t *testing.T) {
+	var b strings.Builder
+	for b.Len() <= maxAnnotationLength {
+		b.WriteString("log line\n")
+	}
+	b.WriteString("last line")
+	logs := (&Result{Logs: b.String()}).Annotations()[LogsAnnotation]
+	if len(logs) > maxAnnotationLength || !strings.HasSuffix(logs, "\nlast line") || !strings.HasPrefix(logs, "log line\n") {
+		t.Errorf("logs annotation is %d bytes and starts %q, want at most %d bytes of whole lines ending with the last line",
+			len(logs), logs[:20], maxAnnotationLength)
+	}
+}
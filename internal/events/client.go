@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -25,8 +26,24 @@ type Client struct {
 	mcpClient      *mcp.Client
 	session        *mcp.ClientSession
 	eventChan      chan *FaultEvent
+	bufferSize     int
+	maxPayload     int // maximum event payload size in bytes (0 = unlimited)
+	quarantine     *Quarantine
 	subscriptionID string
+	httpClient     *http.Client
 	mu             sync.Mutex
+
+	// chanMu protects eventChan and chanClosed. It is separate from mu so that
+	// notification handling never waits on a Subscribe call in progress.
+	chanMu     sync.Mutex
+	chanClosed bool
+
+	// dropped counts fault events dropped because the event channel was full or closed
+	dropped atomic.Int64
+
+	// quarantined counts incoming events this client quarantined or dropped as
+	// unprocessable
+	quarantined atomic.Int64
 }
 
 // NewClient creates a new MCP client for the given endpoint
@@ -43,6 +60,8 @@ func NewClient(endpoint, subscribeMode string, tuningConfig *config.TuningConfig
 		endpoint:      endpoint,
 		subscribeMode: subscribeMode,
 		eventChan:     eventChan,
+		bufferSize:    tuningConfig.Events.ChannelBufferSize,
+		maxPayload:    tuningConfig.Events.MaxPayloadBytes,
 	}
 
 	// Create MCP client with logging message handler to receive fault notifications
@@ -59,6 +78,42 @@ func NewClient(endpoint, subscribeMode string, tuningConfig *config.TuningConfig
 	return c
 }
 
+// SetHTTPClient sets the HTTP client used for the MCP transport (e.g. one configured
+// with proxy settings). It must be called before Subscribe. When unset, a default
+// client honoring the standard proxy environment variables is used.
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.httpClient = httpClient
+}
+
+// SetQuarantine sets where oversized and malformed event payloads are recorded
+// (see Quarantine.ForCluster). When unset, such events are still dropped but only
+// logged.
+func (c *Client) SetQuarantine(q *Quarantine) {
+	c.quarantine = q
+}
+
+// QuarantinedCount returns the number of incoming events this client quarantined
+// because they were oversized or malformed.
+func (c *Client) QuarantinedCount() int64 {
+	return c.quarantined.Load()
+}
+
+// DroppedCount returns the number of fault events this client dropped because its
+// event channel (the per-cluster queue) was full or closed.
+func (c *Client) DroppedCount() int64 {
+	return c.dropped.Load()
+}
+
+// QueueDepth returns the number of fault events waiting in the client's event
+// channel and the channel's capacity.
+func (c *Client) QueueDepth() (depth, capacity int) {
+	c.chanMu.Lock()
+	defer c.chanMu.Unlock()
+	return len(c.eventChan), cap(c.eventChan)
+}
+
 // handleLoggingMessage processes MCP log notifications
 // Fault events come as log messages with logger="kubernetes/{mode}" based on subscribe mode
 func (c *Client) handleLoggingMessage(ctx context.Context, req *mcp.LoggingMessageRequest) {
@@ -75,15 +130,27 @@ func (c *Client) handleLoggingMessage(ctx context.Context, req *mcp.LoggingMessa
 
 	slog.Debug("received fault notification", "level", params.Level, "logger", params.Logger)
 
-	// Log raw data for debugging
-	if rawJSON, err := json.Marshal(params.Data); err == nil {
-		slog.Debug("raw MCP data", "data", string(rawJSON))
+	rawJSON, err := json.Marshal(params.Data)
+	if err != nil {
+		c.quarantineEvent(QuarantineReasonMalformed, nil, fmt.Errorf("failed to marshal log data: %w", err))
+		return
 	}
 
+	// The transport already ended the stream on messages far over the limit;
+	// this catches payloads over it by less than the message envelope
+	if c.maxPayload > 0 && len(rawJSON) > c.maxPayload {
+		c.quarantineEvent(QuarantineReasonTooLarge, rawJSON,
+			fmt.Errorf("payload size %d exceeds max_payload_bytes %d", len(rawJSON), c.maxPayload))
+		return
+	}
+
+	// Log raw data for debugging
+	slog.Debug("raw MCP data", "data", string(rawJSON))
+
 	// Parse the fault event from the log data
-	faultEvent, err := parseFaultEvent(params.Data)
+	faultEvent, err := parseFaultEventJSON(rawJSON)
 	if err != nil {
-		slog.Error("failed to parse fault event", "error", err)
+		c.quarantineEvent(QuarantineReasonMalformed, rawJSON, err)
 		return
 	}
 
@@ -95,23 +162,41 @@ func (c *Client) handleLoggingMessage(ctx context.Context, req *mcp.LoggingMessa
 		"message", faultEvent.GetContext())
 
 	// Send to channel (non-blocking)
+	c.chanMu.Lock()
+	defer c.chanMu.Unlock()
+	if c.chanClosed {
+		c.dropped.Add(1)
+		slog.Warn("event channel closed, dropping event",
+			"cluster", faultEvent.Cluster,
+			"resource", faultEvent.GetResourceName())
+		return
+	}
 	select {
 	case c.eventChan <- faultEvent:
 	default:
+		c.dropped.Add(1)
 		slog.Warn("event channel full, dropping event",
 			"cluster", faultEvent.Cluster,
 			"resource", faultEvent.GetResourceName())
 	}
 }
 
-// parseFaultEvent converts the log data to a FaultEvent
-func parseFaultEvent(data any) (*FaultEvent, error) {
-	// The data comes as a map or can be marshaled to JSON
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal log data: %w", err)
+// quarantineEvent records an unprocessable payload and continues.
+func (c *Client) quarantineEvent(reason string, payload []byte, cause error) {
+	c.quarantined.Add(1)
+	if c.quarantine == nil {
+		slog.Error("dropping unprocessable fault event",
+			"endpoint", c.endpoint,
+			"reason", reason,
+			"size", len(payload),
+			"error", cause)
+		return
 	}
+	c.quarantine.Store(c.endpoint, reason, payload, c.maxPayload, cause)
+}
 
+// parseFaultEventJSON converts JSON-encoded log data to a FaultEvent
+func parseFaultEventJSON(jsonData []byte) (*FaultEvent, error) {
 	var faultEvent FaultEvent
 	if err := json.Unmarshal(jsonData, &faultEvent); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal fault event: %w", err)
@@ -134,10 +219,36 @@ func (c *Client) Subscribe(ctx context.Context) (<-chan *FaultEvent, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	// A previous session closed the event channel; start a fresh one for this session
+	c.chanMu.Lock()
+	if c.chanClosed {
+		c.eventChan = make(chan *FaultEvent, c.bufferSize)
+		c.chanClosed = false
+	}
+	c.chanMu.Unlock()
+
 	// Create Streamable HTTP transport using the configured endpoint as-is
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	// Drop pooled keep-alive connections from any previous session so the endpoint
+	// is resolved again; otherwise a reconnect can pin a dead IP behind a load balancer
+	httpClient.CloseIdleConnections()
+
+	// Oversized messages are cut off while they are read, before the SDK buffers them
+	limited := *httpClient
+	limited.Transport = &messageLimitTransport{
+		base:     httpClient.Transport,
+		maxBytes: c.maxPayload,
+		oversized: func(prefix []byte, cause error) {
+			c.quarantineEvent(QuarantineReasonTooLarge, prefix, cause)
+		},
+	}
 	transport := &mcp.StreamableClientTransport{
 		Endpoint:   c.endpoint,
-		HTTPClient: &http.Client{},
+		HTTPClient: &limited,
 	}
 
 	slog.Info("connecting to MCP server", "endpoint", c.endpoint)
@@ -145,7 +256,7 @@ func (c *Client) Subscribe(ctx context.Context) (<-chan *FaultEvent, error) {
 	// Connect to server
 	session, err := c.mcpClient.Connect(ctx, transport, nil)
 	if err != nil {
-		close(c.eventChan)
+		c.closeEventChan()
 		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
 	}
 	c.session = session
@@ -158,7 +269,7 @@ func (c *Client) Subscribe(ctx context.Context) (<-chan *FaultEvent, error) {
 	})
 	if err != nil {
 		c.session.Close()
-		close(c.eventChan)
+		c.closeEventChan()
 		return nil, fmt.Errorf("failed to set logging level: %w", err)
 	}
 
@@ -173,7 +284,7 @@ func (c *Client) Subscribe(ctx context.Context) (<-chan *FaultEvent, error) {
 	})
 	if err != nil {
 		c.session.Close()
-		close(c.eventChan)
+		c.closeEventChan()
 		return nil, fmt.Errorf("failed to subscribe to events: %w", err)
 	}
 
@@ -189,7 +300,7 @@ func (c *Client) Subscribe(ctx context.Context) (<-chan *FaultEvent, error) {
 	// Extract subscription ID from result
 	if result.IsError {
 		c.session.Close()
-		close(c.eventChan)
+		c.closeEventChan()
 		return nil, fmt.Errorf("events_subscribe returned error: %s", responseText)
 	}
 
@@ -212,6 +323,8 @@ func (c *Client) Subscribe(ctx context.Context) (<-chan *FaultEvent, error) {
 		c.Close()
 	}()
 
+	c.chanMu.Lock()
+	defer c.chanMu.Unlock()
 	return c.eventChan, nil
 }
 
@@ -225,13 +338,16 @@ func (c *Client) Close() {
 		c.session = nil
 	}
 
-	// Close channel if not already closed
-	select {
-	case _, ok := <-c.eventChan:
-		if ok {
-			close(c.eventChan)
-		}
-	default:
+	c.closeEventChan()
+}
+
+// closeEventChan closes the event channel if it is not already closed.
+func (c *Client) closeEventChan() {
+	c.chanMu.Lock()
+	defer c.chanMu.Unlock()
+
+	if !c.chanClosed {
 		close(c.eventChan)
+		c.chanClosed = true
 	}
 }
@@ -1,7 +1,9 @@
 package events
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/rbias/nightcrier/internal/config"
 )
@@ -139,3 +141,62 @@ func TestNewClient_DefaultSubscribeMode(t *testing.T) {
 		t.Errorf("expected channel capacity %d, got %d", bufferSize, cap(client.eventChan))
 	}
 }
+
+// TestClient_CloseIsIdempotent verifies that closing a client more than once
+// (session end and context cancellation both close it) does not panic.
+func TestClient_CloseIsIdempotent(t *testing.T) {
+	client := NewClient("http://localhost:8080/mcp", "faults", &config.TuningConfig{
+		Events: config.EventsTuning{ChannelBufferSize: 5},
+	})
+
+	client.Close()
+	client.Close()
+
+	if !client.chanClosed {
+		t.Error("expected event channel to be marked closed")
+	}
+	if _, ok := <-client.eventChan; ok {
+		t.Error("expected event channel to be closed")
+	}
+}
+
+// TestClient_SubscribeFailureAllowsReconnect verifies that a failed connection
+// attempt leaves the client usable for a later reconnect with a fresh channel.
+func TestClient_SubscribeFailureAllowsReconnect(t *testing.T) {
+	client := NewClient("http://127.0.0.1:1/mcp", "faults", &config.TuningConfig{
+		Events: config.EventsTuning{ChannelBufferSize: 5},
+	})
+	client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, err := client.Subscribe(ctx); err == nil {
+		t.Fatal("expected Subscribe to fail against an unreachable endpoint")
+	}
+	if !client.chanClosed {
+		t.Error("expected failed subscribe to close the fresh event channel")
+	}
+	if cap(client.eventChan) != 5 {
+		t.Errorf("expected recreated channel capacity 5, got %d", cap(client.eventChan))
+	}
+}
+
+// TestClient_CountsDroppedEvents verifies that events dropped because the event
+// channel is full are counted and reflected in the queue depth.
+func TestClient_CountsDroppedEvents(t *testing.T) {
+	client := NewClient("http://localhost:8383/mcp", "faults", &config.TuningConfig{
+		Events: config.EventsTuning{ChannelBufferSize: 2},
+	})
+
+	for i := 0; i < 5; i++ {
+		sendLogMessage(client, map[string]any{"faultId": "f-1"})
+	}
+
+	if depth, capacity := client.QueueDepth(); depth != 2 || capacity != 2 {
+		t.Errorf("QueueDepth() = %d, %d, want 2, 2", depth, capacity)
+	}
+	if client.DroppedCount() != 3 {
+		t.Errorf("DroppedCount() = %d, want 3", client.DroppedCount())
+	}
+}
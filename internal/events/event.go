@@ -16,6 +16,16 @@ type FaultEvent struct {
 	Severity       string        `json:"severity"`
 	Context        string        `json:"context"`             // Human-readable fault description
 	Timestamp      string        `json:"timestamp"`           // When fault occurred in K8s
+
+	// Labels and Annotations are set by upstream systems (e.g. the alert labels of
+	// an Alertmanager alert) and passed through to the incident, so correlation
+	// keys from upstream alerting survive the pipeline
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Source names the event source that received the fault (the cluster's
+	// sources entry, e.g. "mcp" or "alertmanager"); it is recorded on the incident
+	Source string `json:"source,omitempty"`
 }
 
 // ResourceInfo represents the Kubernetes resource involved in the fault
@@ -25,6 +35,7 @@ type ResourceInfo struct {
 	Name       string `json:"name"`
 	Namespace  string `json:"namespace,omitempty"`
 	UID        string `json:"uid,omitempty"` // Kubernetes resource UID (used in FaultID hash upstream)
+	Node       string `json:"node,omitempty"` // Node the resource is scheduled on (optional; used for node-level aggregation)
 }
 
 // Helper methods for convenient access
@@ -53,6 +64,14 @@ func (f *FaultEvent) GetNamespace() string {
 	return ""
 }
 
+// GetNode returns the node the resource is scheduled on, if reported
+func (f *FaultEvent) GetNode() string {
+	if f.Resource != nil {
+		return f.Resource.Node
+	}
+	return ""
+}
+
 // GetSeverity returns the fault severity
 func (f *FaultEvent) GetSeverity() string {
 	return f.Severity
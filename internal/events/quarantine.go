@@ -0,0 +1,279 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/metrics"
+)
+
+// Quarantine reasons recorded with each quarantined payload.
+const (
+	QuarantineReasonTooLarge  = "payload_too_large"
+	QuarantineReasonMalformed = "malformed"
+)
+
+// Quarantine stores incoming event payloads that could not be processed
+// (oversized or malformed) so they can be inspected later, and counts them.
+// A single pathological event must never wedge the client: quarantining is
+// best effort and failures to write are only logged.
+type Quarantine struct {
+	dir     string
+	cluster string
+	metrics *metrics.CounterVec
+	count   atomic.Int64
+}
+
+// NewQuarantine creates a Quarantine writing to dir. An empty dir disables
+// storing payloads, but quarantined events are still counted.
+func NewQuarantine(dir string) *Quarantine {
+	return &Quarantine{dir: dir}
+}
+
+// NewQuarantineMetrics registers the counter of quarantined events per cluster
+// and quarantine reason.
+func NewQuarantineMetrics(registry *metrics.Registry) *metrics.CounterVec {
+	return registry.RegisterCounter(metrics.NewCounterVec(
+		"nightcrier_events_quarantined_total",
+		"Incoming fault events quarantined because they were oversized or malformed, by reason.",
+		"cluster", "reason"))
+}
+
+// SetMetrics sets the counter quarantined events are recorded in (see
+// NewQuarantineMetrics). It must be called before ForCluster.
+func (q *Quarantine) SetMetrics(counter *metrics.CounterVec) {
+	q.metrics = counter
+}
+
+// ForCluster returns a Quarantine for one cluster's event sources. It stores
+// payloads in the same directory and records them in the same metrics, labelled
+// with the cluster, but counts only the cluster's own quarantined events.
+func (q *Quarantine) ForCluster(cluster string) *Quarantine {
+	return &Quarantine{dir: q.dir, cluster: cluster, metrics: q.metrics}
+}
+
+// Count returns the number of payloads quarantined so far.
+func (q *Quarantine) Count() int64 {
+	if q == nil {
+		return 0
+	}
+	return q.count.Load()
+}
+
+// Store records a quarantined payload. At most maxBytes of the payload are
+// written (0 writes everything) so an oversized event cannot fill the disk.
+func (q *Quarantine) Store(endpoint, reason string, payload []byte, maxBytes int, cause error) {
+	if q == nil {
+		return
+	}
+	total := q.count.Add(1)
+	if q.metrics != nil {
+		q.metrics.Inc(q.cluster, reason)
+	}
+
+	slog.Warn("quarantined incoming event",
+		"cluster", q.cluster,
+		"endpoint", endpoint,
+		"reason", reason,
+		"size", len(payload),
+		"quarantined_total", total,
+		"error", cause)
+
+	if q.dir == "" {
+		return
+	}
+
+	if err := os.MkdirAll(q.dir, 0755); err != nil {
+		slog.Error("failed to create quarantine directory", "dir", q.dir, "error", err)
+		return
+	}
+
+	if maxBytes > 0 && len(payload) > maxBytes {
+		payload = payload[:maxBytes]
+	}
+
+	name := fmt.Sprintf("%s-%s-%d.json", time.Now().UTC().Format("20060102T150405.000000000Z"), reason, total)
+	path := filepath.Join(q.dir, name)
+	if err := os.WriteFile(path, payload, 0600); err != nil {
+		slog.Error("failed to write quarantined payload", "path", path, "error", err)
+		return
+	}
+	slog.Info("quarantined payload stored", "path", path)
+}
+
+// ReadLimitTransport wraps an http.RoundTripper and caps the number of bytes read
+// from each response body per one-minute window. When a body exceeds the budget,
+// reads fail with ErrReadLimitExceeded, which ends the MCP stream so the
+// connection manager reconnects with backoff.
+type ReadLimitTransport struct {
+	Base           http.RoundTripper
+	BytesPerMinute int64
+}
+
+// ErrReadLimitExceeded is returned when a connection exceeds its read budget.
+var ErrReadLimitExceeded = errors.New("connection read limit exceeded")
+
+// RoundTrip implements http.RoundTripper.
+func (t *ReadLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp == nil || resp.Body == nil || t.BytesPerMinute <= 0 {
+		return resp, err
+	}
+	resp.Body = &limitedBody{
+		ReadCloser:  resp.Body,
+		limit:       t.BytesPerMinute,
+		windowStart: time.Now(),
+		host:        req.URL.Host,
+	}
+	return resp, nil
+}
+
+// CloseIdleConnections forwards to the base transport so re-resolution on
+// reconnect keeps working when the transport is wrapped.
+func (t *ReadLimitTransport) CloseIdleConnections() {
+	type closeIdler interface{ CloseIdleConnections() }
+	if ci, ok := t.Base.(closeIdler); ok {
+		ci.CloseIdleConnections()
+	}
+}
+
+// limitedBody enforces a per-minute read budget on a response body.
+type limitedBody struct {
+	io.ReadCloser
+	mu          sync.Mutex
+	limit       int64
+	read        int64
+	windowStart time.Time
+	host        string
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if time.Since(b.windowStart) >= time.Minute {
+		b.windowStart = time.Now()
+		b.read = 0
+	}
+	b.read += int64(n)
+	if b.read > b.limit {
+		slog.Warn("MCP connection exceeded read limit, dropping connection",
+			"host", b.host,
+			"bytes_per_minute_limit", b.limit)
+		return n, ErrReadLimitExceeded
+	}
+	return n, err
+}
+
+// messageEnvelopeBytes is the allowance, on top of max_payload_bytes, for the
+// JSON-RPC notification and SSE framing around an event payload.
+const messageEnvelopeBytes = 4096
+
+// ErrMessageTooLarge is returned when a single incoming message exceeds the
+// payload limit.
+var ErrMessageTooLarge = errors.New("incoming message exceeds max_payload_bytes")
+
+// messageLimitTransport wraps an http.RoundTripper and caps the size of each
+// message read from a response body, before the MCP SDK buffers and decodes it.
+// A message is an SSE event (ended by a blank line) or, for a plain JSON
+// response, the whole body. An oversized message is handed to oversized, and
+// reads fail with ErrMessageTooLarge, which ends the MCP stream so the
+// connection manager reconnects with backoff.
+type messageLimitTransport struct {
+	base      http.RoundTripper
+	maxBytes  int
+	oversized func(prefix []byte, cause error)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *messageLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp == nil || resp.Body == nil || t.maxBytes <= 0 {
+		return resp, err
+	}
+	resp.Body = &messageLimitBody{
+		ReadCloser:  resp.Body,
+		limit:       t.maxBytes + messageEnvelopeBytes,
+		keep:        t.maxBytes,
+		oversized:   t.oversized,
+		atLineStart: true,
+	}
+	return resp, nil
+}
+
+// CloseIdleConnections forwards to the base transport so re-resolution on
+// reconnect keeps working when the transport is wrapped.
+func (t *messageLimitTransport) CloseIdleConnections() {
+	type closeIdler interface{ CloseIdleConnections() }
+	if ci, ok := t.base.(closeIdler); ok {
+		ci.CloseIdleConnections()
+	}
+}
+
+// messageLimitBody enforces the message size limit on a response body. It keeps
+// the first keep bytes of the current message so an oversized one can be
+// quarantined.
+type messageLimitBody struct {
+	io.ReadCloser
+	limit       int
+	keep        int
+	oversized   func(prefix []byte, cause error)
+	size        int
+	prefix      []byte
+	atLineStart bool
+	failed      bool
+}
+
+func (b *messageLimitBody) Read(p []byte) (int, error) {
+	if b.failed {
+		return 0, ErrMessageTooLarge
+	}
+	n, err := b.ReadCloser.Read(p)
+	for i, c := range p[:n] {
+		switch c {
+		case '\r':
+			continue
+		case '\n':
+			if b.atLineStart {
+				// A blank line ends the SSE event
+				b.size = 0
+				b.prefix = b.prefix[:0]
+				continue
+			}
+			b.atLineStart = true
+		default:
+			b.atLineStart = false
+		}
+		b.size++
+		if len(b.prefix) < b.keep {
+			b.prefix = append(b.prefix, c)
+		}
+		if b.size > b.limit {
+			b.failed = true
+			if b.oversized != nil {
+				b.oversized(b.prefix, fmt.Errorf("message exceeds %d bytes: %w", b.limit, ErrMessageTooLarge))
+			}
+			b.prefix = nil
+			return i, ErrMessageTooLarge
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,172 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/metrics"
+)
+
+func newQuarantineTestClient(t *testing.T, maxPayload int) (*Client, string) {
+	t.Helper()
+	dir := t.TempDir()
+	client := NewClient("http://localhost:8080/mcp", "faults", &config.TuningConfig{
+		Events: config.EventsTuning{ChannelBufferSize: 5, MaxPayloadBytes: maxPayload},
+	})
+	client.SetQuarantine(NewQuarantine(dir))
+	return client, dir
+}
+
+func sendLogMessage(client *Client, data any) {
+	client.handleLoggingMessage(context.Background(), &mcp.LoggingMessageRequest{
+		Params: &mcp.LoggingMessageParams{
+			Logger: LoggerPrefix + "faults",
+			Level:  "info",
+			Data:   data,
+		},
+	})
+}
+
+// TestHandleLoggingMessage_QuarantinesOversizedPayload verifies that events larger
+// than max_payload_bytes are stored in quarantine and not forwarded.
+func TestHandleLoggingMessage_QuarantinesOversizedPayload(t *testing.T) {
+	client, dir := newQuarantineTestClient(t, 64)
+
+	sendLogMessage(client, map[string]any{
+		"faultId": "f-1",
+		"context": strings.Repeat("x", 500),
+	})
+
+	if client.QuarantinedCount() != 1 {
+		t.Errorf("QuarantinedCount() = %d, want 1", client.QuarantinedCount())
+	}
+	if len(client.eventChan) != 0 {
+		t.Errorf("oversized event should not be forwarded, channel has %d events", len(client.eventChan))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 quarantined file, got %d (err=%v)", len(entries), err)
+	}
+	if !strings.Contains(entries[0].Name(), QuarantineReasonTooLarge) {
+		t.Errorf("quarantine file %q should record the reason", entries[0].Name())
+	}
+	info, _ := entries[0].Info()
+	if info.Size() > 64 {
+		t.Errorf("quarantined payload should be truncated to 64 bytes, got %d", info.Size())
+	}
+}
+
+// TestHandleLoggingMessage_QuarantinesMalformedPayload verifies that events that
+// cannot be decoded are quarantined and processing continues.
+func TestHandleLoggingMessage_QuarantinesMalformedPayload(t *testing.T) {
+	client, _ := newQuarantineTestClient(t, 0)
+
+	sendLogMessage(client, "not an object")
+	sendLogMessage(client, map[string]any{"faultId": "f-2"})
+
+	if client.QuarantinedCount() != 1 {
+		t.Errorf("QuarantinedCount() = %d, want 1", client.QuarantinedCount())
+	}
+	if len(client.eventChan) != 1 {
+		t.Errorf("valid event after malformed one should be forwarded, channel has %d events", len(client.eventChan))
+	}
+}
+
+type staticRoundTripper struct{ body []byte }
+
+func (s staticRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(s.body))}, nil
+}
+
+// TestReadLimitTransport_EnforcesBudget verifies that a response body exceeding the
+// per-minute budget fails with ErrReadLimitExceeded.
+func TestReadLimitTransport_EnforcesBudget(t *testing.T) {
+	transport := &ReadLimitTransport{
+		Base:           staticRoundTripper{body: bytes.Repeat([]byte("a"), 1024)},
+		BytesPerMinute: 100,
+	}
+	req, _ := http.NewRequest(http.MethodGet, "http://mcp.example.com/mcp", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	if !errors.Is(err, ErrReadLimitExceeded) {
+		t.Errorf("ReadAll() error = %v, want ErrReadLimitExceeded", err)
+	}
+}
+
+// TestMessageLimitTransport_CutsOffOversizedMessage verifies that an SSE event
+// over the payload limit ends the stream while it is read, after the events
+// before it were passed through, and that its prefix is handed over for
+// quarantine.
+func TestMessageLimitTransport_CutsOffOversizedMessage(t *testing.T) {
+	small := "event: message\r\ndata: {\"jsonrpc\":\"2.0\"}\r\n\r\n"
+	large := "event: message\ndata: " + strings.Repeat("x", 64+messageEnvelopeBytes) + "\n\n"
+
+	var quarantined []byte
+	transport := &messageLimitTransport{
+		base:     staticRoundTripper{body: []byte(small + small + large + small)},
+		maxBytes: 64,
+		oversized: func(prefix []byte, cause error) {
+			quarantined = append([]byte(nil), prefix...)
+		},
+	}
+	req, _ := http.NewRequest(http.MethodGet, "http://mcp.example.com/mcp", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	read, err := io.ReadAll(resp.Body)
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("ReadAll() error = %v, want ErrMessageTooLarge", err)
+	}
+	if !strings.HasPrefix(string(read), small+small) || len(read) > len(small+small)+64+messageEnvelopeBytes {
+		t.Errorf("read %d bytes, want the two small events and at most the limit of the large one", len(read))
+	}
+	if len(quarantined) != 64 || !strings.HasPrefix(string(quarantined), "event: message") {
+		t.Errorf("quarantined prefix = %q, want the first 64 bytes of the large event", quarantined)
+	}
+}
+
+// TestQuarantine_ForClusterCountsPerCluster verifies that cluster quarantines
+// count their own events and record them in the shared metrics by cluster.
+func TestQuarantine_ForClusterCountsPerCluster(t *testing.T) {
+	registry := metrics.NewRegistry()
+	shared := NewQuarantine("")
+	shared.SetMetrics(NewQuarantineMetrics(registry))
+	prod := shared.ForCluster("prod")
+	staging := shared.ForCluster("staging")
+
+	prod.Store("http://prod/mcp", QuarantineReasonMalformed, []byte("{"), 0, nil)
+	prod.Store("http://prod/mcp", QuarantineReasonTooLarge, []byte("{}"), 0, nil)
+	staging.Store("http://staging/mcp", QuarantineReasonMalformed, []byte("{"), 0, nil)
+
+	if prod.Count() != 2 || staging.Count() != 1 {
+		t.Errorf("Count() = %d (prod), %d (staging); want 2, 1", prod.Count(), staging.Count())
+	}
+	var out bytes.Buffer
+	registry.WriteTo(&out)
+	for _, want := range []string{
+		`nightcrier_events_quarantined_total{cluster="prod",reason="malformed"} 1`,
+		`nightcrier_events_quarantined_total{cluster="prod",reason="payload_too_large"} 1`,
+		`nightcrier_events_quarantined_total{cluster="staging",reason="malformed"} 1`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics missing %q:\n%s", want, out.String())
+		}
+	}
+}
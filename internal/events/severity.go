@@ -0,0 +1,33 @@
+package events
+
+import "strings"
+
+// Severity levels in ascending order of importance.
+const (
+	SeverityDebug    = "DEBUG"
+	SeverityInfo     = "INFO"
+	SeverityWarning  = "WARNING"
+	SeverityError    = "ERROR"
+	SeverityCritical = "CRITICAL"
+)
+
+// severityRanks maps normalized severity names to their relative importance.
+var severityRanks = map[string]int{
+	SeverityDebug:    1,
+	SeverityInfo:     2,
+	SeverityWarning:  3,
+	"WARN":           3,
+	SeverityError:    4,
+	SeverityCritical: 5,
+}
+
+// SeverityRank returns the relative importance of a severity string (case-insensitive).
+// Unknown or empty severities rank 0, below DEBUG.
+func SeverityRank(severity string) int {
+	return severityRanks[strings.ToUpper(strings.TrimSpace(severity))]
+}
+
+// MeetsSeverity reports whether severity is at least as important as threshold.
+func MeetsSeverity(severity, threshold string) bool {
+	return SeverityRank(severity) >= SeverityRank(threshold)
+}
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// volatileTokens matches the parts of a fault description that change between
+// otherwise identical occurrences: numbers (restart counts, exit codes, durations)
+// and hex identifiers (container IDs, pod-template hashes).
+var volatileTokens = regexp.MustCompile(`[0-9a-f]{8,}|[0-9]+`)
+
+// FaultSignature returns a stable hash identifying an identical fault condition:
+// the same resource (by UID, or kind/namespace/name when the UID is missing), the
+// same fault type, and the same recent container state. The event does not carry
+// container image digests, so the container state is hashed from the fault
+// description with volatile tokens (counts, IDs, timestamps) removed.
+func FaultSignature(cluster string, f *FaultEvent) string {
+	resource := ""
+	if f.Resource != nil {
+		resource = f.Resource.UID
+		if resource == "" {
+			resource = strings.Join([]string{f.Resource.Kind, f.Resource.Namespace, f.Resource.Name}, "/")
+		}
+	}
+
+	h := sha256.New()
+	for _, part := range []string{cluster, resource, f.FaultType, ContainerStateHash(f.Context)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ContainerStateHash hashes a fault description after normalizing volatile tokens.
+func ContainerStateHash(context string) string {
+	normalized := volatileTokens.ReplaceAllString(strings.ToLower(strings.TrimSpace(context)), "#")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:8])
+}
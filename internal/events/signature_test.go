@@ -0,0 +1,54 @@
+package events
+
+import "testing"
+
+func TestFaultSignature_StableAcrossVolatileDetails(t *testing.T) {
+	base := &FaultEvent{
+		Resource:  &ResourceInfo{Kind: "Pod", Namespace: "default", Name: "web-7d9f8", UID: "uid-1"},
+		FaultType: "CrashLoopBackOff",
+		Context:   "Back-off restarting failed container app (restarts: 5, exit code 137)",
+	}
+	later := &FaultEvent{
+		FaultID:   "different-fault-id",
+		Resource:  base.Resource,
+		FaultType: base.FaultType,
+		Context:   "Back-off restarting failed container app (restarts: 12, exit code 137)",
+	}
+
+	if FaultSignature("prod", base) != FaultSignature("prod", later) {
+		t.Error("signatures should match when only counts differ")
+	}
+}
+
+func TestFaultSignature_DistinguishesFaults(t *testing.T) {
+	base := &FaultEvent{
+		Resource:  &ResourceInfo{Kind: "Pod", Namespace: "default", Name: "web", UID: "uid-1"},
+		FaultType: "CrashLoopBackOff",
+		Context:   "Back-off restarting failed container app",
+	}
+	sig := FaultSignature("prod", base)
+
+	tests := []struct {
+		name    string
+		cluster string
+		event   *FaultEvent
+	}{
+		{"different cluster", "staging", base},
+		{"different resource", "prod", &FaultEvent{Resource: &ResourceInfo{UID: "uid-2"}, FaultType: base.FaultType, Context: base.Context}},
+		{"different fault type", "prod", &FaultEvent{Resource: base.Resource, FaultType: "OOMKilled", Context: base.Context}},
+		{"different container state", "prod", &FaultEvent{Resource: base.Resource, FaultType: base.FaultType, Context: "Back-off restarting failed container sidecar"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if FaultSignature(tt.cluster, tt.event) == sig {
+				t.Error("signatures should differ")
+			}
+		})
+	}
+}
+
+func TestFaultSignature_NilResource(t *testing.T) {
+	if FaultSignature("prod", &FaultEvent{FaultType: "X"}) == "" {
+		t.Error("signature should not be empty for events without a resource")
+	}
+}
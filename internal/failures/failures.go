@@ -0,0 +1,129 @@
+// Package failures classifies agent failures into a small canonical taxonomy, so
+// failures can be counted, alerted on, and compared across agents and providers
+// instead of being told apart by free-text reasons. Classes are assigned by
+// ordered pattern rules over the agent's exit code, the failure reason nightcrier
+// recorded, and the tail of the agent's output; the first matching rule wins.
+package failures
+
+import (
+	"regexp"
+
+	"github.com/rbias/nightcrier/internal/keypool"
+)
+
+// Class is a canonical failure class.
+type Class string
+
+const (
+	// Timeout means the agent ran past its timeout and was stopped
+	Timeout Class = "timeout"
+	// ProviderRateLimit means the LLM provider throttled the agent's requests
+	ProviderRateLimit Class = "provider_rate_limit"
+	// ProviderUnavailable means the LLM provider failed the agent's requests with
+	// server errors or was overloaded, as during a provider outage
+	ProviderUnavailable Class = "provider_unavailable"
+	// ProviderAuth means the LLM provider rejected the agent's credentials
+	ProviderAuth Class = "provider_auth"
+	// MissingOutput means the agent finished without writing investigation.md
+	MissingOutput Class = "missing_output"
+	// TooSmall means investigation.md is smaller than the configured minimum
+	TooSmall Class = "too_small"
+	// KubectlDenied means the cluster refused the agent's kubectl requests (RBAC)
+	KubectlDenied Class = "kubectl_denied"
+	// OOMKilled means the agent container ran out of memory and was killed
+	OOMKilled Class = "oom_killed"
+	// Crashed means the agent was killed by a signal other than an OOM kill (e.g.
+	// a segmentation fault or an abort)
+	Crashed Class = "crashed"
+	// Unknown is the class of failures no rule matches
+	Unknown Class = "unknown"
+)
+
+// Classes lists every class, in the order the rules are evaluated, followed by
+// Unknown.
+var Classes = []Class{OOMKilled, Crashed, Timeout, ProviderAuth, ProviderRateLimit, ProviderUnavailable, KubectlDenied, MissingOutput, TooSmall, Unknown}
+
+// rule assigns a class to failures with one of its exit codes, a reason matching
+// its reason pattern, or output matching its output pattern or showing the API key
+// outcome (the key pool's patterns, so both agree on what a provider error is).
+type rule struct {
+	class      Class
+	exitCodes  []int
+	reason     *regexp.Regexp
+	output     *regexp.Regexp
+	keyOutcome keypool.Outcome
+}
+
+// rules are ordered from root causes to symptoms: an agent that was throttled
+// into writing no report is a provider_rate_limit failure, not missing_output.
+var rules = []rule{
+	{
+		// 137 is SIGKILL, which is how docker reports an OOM-killed container
+		class:     OOMKilled,
+		exitCodes: []int{137},
+		output:    regexp.MustCompile(`(?i)\boom[ -]?killed\b|out of memory|cannot allocate memory|javascript heap out of memory`),
+	},
+	{
+		// 128+N is a process killed by signal N: 132 SIGILL, 134 SIGABRT, 135 SIGBUS,
+		// 136 SIGFPE, 139 SIGSEGV
+		class:     Crashed,
+		exitCodes: []int{132, 134, 135, 136, 139},
+		reason:    regexp.MustCompile(`(?i)agent crashed`),
+		output:    regexp.MustCompile(`(?i)segmentation fault|core dumped|\bsegfault at\b|fatal error: unexpected signal`),
+	},
+	{
+		// 124 is the exit code of timeout(1), which run-agent.sh wraps docker in
+		class:     Timeout,
+		exitCodes: []int{124},
+		reason:    regexp.MustCompile(`(?i)context deadline exceeded|timed out|\btimeout\b`),
+		output:    regexp.MustCompile(`(?i)\btimed out after\b|context deadline exceeded`),
+	},
+	{class: ProviderAuth, keyOutcome: keypool.OutcomeInvalid},
+	{class: ProviderRateLimit, keyOutcome: keypool.OutcomeRateLimited},
+	{
+		// Anthropic reports overload as 529 and server errors as api_error; OpenAI
+		// as server_error
+		class:  ProviderUnavailable,
+		output: regexp.MustCompile(`(?i)overloaded_error|"type"\s*:\s*"(api_error|server_error)"|api error[ :=]*(500|502|503|504|529)\b|(status|status[ _]?code)[ :=]*529\b|the server had an error while processing your request`),
+	},
+	{
+		class:  KubectlDenied,
+		output: regexp.MustCompile(`(?i)error from server \(forbidden\)|\bis forbidden: user\b|you must be logged in to the server|error from server \(unauthorized\)`),
+	},
+	{class: MissingOutput, reason: regexp.MustCompile(`(?i)investigation\.md (file )?not found`)},
+	{class: TooSmall, reason: regexp.MustCompile(`(?i)investigation\.md too small`)},
+}
+
+// Classify returns the class of an agent failure from the agent's exit code, the
+// failure reason, and the agent's output (typically the tail of its logs).
+func Classify(exitCode int, reason, output string) Class {
+	for _, r := range rules {
+		if r.matches(exitCode, reason, output) {
+			return r.class
+		}
+	}
+	return Unknown
+}
+
+// matches reports whether a failure satisfies any of the rule's conditions.
+func (r rule) matches(exitCode int, reason, output string) bool {
+	for _, code := range r.exitCodes {
+		if exitCode == code {
+			return true
+		}
+	}
+	if r.reason != nil && r.reason.MatchString(reason) {
+		return true
+	}
+	if output == "" {
+		return false
+	}
+	if r.output != nil && r.output.MatchString(output) {
+		return true
+	}
+	if r.keyOutcome != keypool.OutcomeOK {
+		outcome, _ := keypool.Classify(output)
+		return outcome == r.keyOutcome
+	}
+	return false
+}
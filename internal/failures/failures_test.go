@@ -0,0 +1,37 @@
+package failures
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		exitCode int
+		reason   string
+		output   string
+		want     Class
+	}{
+		{"oom exit code", 137, "agent exited with non-zero code: 137", "", OOMKilled},
+		{"oom in output", 1, "agent exited with non-zero code: 1", "Container state: OOMKilled", OOMKilled},
+		{"oom in kernel log", -1, "agent crashed (signal: killed)", "Memory cgroup out of memory: Killed process 4242 (node)", OOMKilled},
+		{"segfault exit code", 139, "agent exited with non-zero code: 139", "", Crashed},
+		{"crashed by signal", -1, "agent crashed (signal: aborted)", "", Crashed},
+		{"timeout exit code", 124, "agent exited with non-zero code: 124", "", Timeout},
+		{"timeout error", -1, "agent execution error: context deadline exceeded", "", Timeout},
+		{"invalid key", 1, "agent exited with non-zero code: 1", `API Error: 401 {"type":"error","error":{"type":"authentication_error"}}`, ProviderAuth},
+		{"rate limited", 1, "agent exited with non-zero code: 1", "API Error: 429 rate_limit_error", ProviderRateLimit},
+		{"throttled into no report", 0, "investigation.md file not found", "Error: 429 Too Many Requests", ProviderRateLimit},
+		{"overloaded", 1, "agent exited with non-zero code: 1", `API Error: 529 {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`, ProviderUnavailable},
+		{"provider server error", 0, "investigation.md file not found", `API Error: 500 {"type":"error","error":{"type":"api_error","message":"Internal server error"}}`, ProviderUnavailable},
+		{"kubectl forbidden", 0, "investigation.md too small: 12 bytes (expected >= 100)", `Error from server (Forbidden): pods is forbidden: User "system:serviceaccount:nightcrier:reader" cannot list resource "pods"`, KubectlDenied},
+		{"missing output", 0, "investigation.md file not found", "investigation complete", MissingOutput},
+		{"too small", 0, "investigation.md too small: 12 bytes (expected >= 100)", "", TooSmall},
+		{"unknown", 2, "agent exited with non-zero code: 2", "docker: invalid reference format", Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.exitCode, tt.reason, tt.output); got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package faulthandlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// maxStderrInError bounds the stderr of a failed hook quoted in its error
+const maxStderrInError = 2048
+
+// Command is a subprocess hook. The fault is written to its stdin as a JSON Input,
+// and the hook prints a JSON Result on stdout; output that is not a JSON object is
+// taken as the summary, and no output hands the fault on unchanged. A non-zero exit
+// status fails the run. Besides its settings (NIGHTCRIER_SETTING_<NAME>), the hook's
+// environment carries KUBECONFIG, set to the cluster's triage kubeconfig, and
+// NIGHTCRIER_INCIDENT_ID, NIGHTCRIER_CLUSTER, NIGHTCRIER_NAMESPACE, NIGHTCRIER_KIND,
+// NIGHTCRIER_NAME, NIGHTCRIER_FAULT_TYPE, and NIGHTCRIER_WORKSPACE. The hook runs in
+// the incident workspace.
+type Command struct {
+	args     []string
+	settings map[string]string
+}
+
+// NewCommand returns a hook running args[0] with the remaining arguments.
+func NewCommand(args []string, settings map[string]string) *Command {
+	return &Command{args: args, settings: settings}
+}
+
+// Handle implements Handler.
+func (c *Command) Handle(ctx context.Context, in Input) (Result, error) {
+	input, err := json.Marshal(in)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to encode hook input: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, c.args[0], c.args[1:]...)
+	cmd.Dir = in.Workspace
+	cmd.Env = append(os.Environ(), c.environment(in)...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > maxStderrInError {
+			msg = msg[len(msg)-maxStderrInError:]
+		}
+		return Result{}, fmt.Errorf("hook %s failed: %w (stderr: %s)", c.args[0], err, msg)
+	}
+	return parseOutput(stdout.Bytes())
+}
+
+// environment returns the fault and settings variables of a hook run.
+func (c *Command) environment(in Input) []string {
+	env := []string{
+		"NIGHTCRIER_INCIDENT_ID=" + in.IncidentID,
+		"NIGHTCRIER_CLUSTER=" + in.Cluster,
+		"NIGHTCRIER_NAMESPACE=" + in.Namespace,
+		"NIGHTCRIER_KIND=" + in.Kind,
+		"NIGHTCRIER_NAME=" + in.Name,
+		"NIGHTCRIER_FAULT_TYPE=" + in.FaultType,
+		"NIGHTCRIER_WORKSPACE=" + in.Workspace,
+	}
+	if in.Kubeconfig != "" {
+		env = append(env, "KUBECONFIG="+in.Kubeconfig)
+	}
+	names := make([]string, 0, len(c.settings))
+	for name := range c.settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		key := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+		env = append(env, "NIGHTCRIER_SETTING_"+key+"="+c.settings[name])
+	}
+	return env
+}
+
+// parseOutput reads a hook's stdout: a JSON Result, plain text taken as the
+// summary, or nothing.
+func parseOutput(out []byte) (Result, error) {
+	text := strings.TrimSpace(string(out))
+	if !strings.HasPrefix(text, "{") {
+		return Result{Summary: text}, nil
+	}
+	var result Result
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		return Result{}, fmt.Errorf("failed to parse hook output: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,280 @@
+// Package faulthandlers routes specific fault types to specialized processing
+// paths. A handler is registered for fault types (and optionally resource kinds and
+// namespaces) and runs either before the generic agent, adding its findings to the
+// agent prompt or resolving the fault on its own, or instead of it, producing the
+// investigation report. Handlers are configured declaratively and implemented as
+// subprocess hooks (any executable speaking JSON on stdin and stdout) or Go plugins
+// (see Command and LoadPlugin).
+package faulthandlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/match"
+)
+
+// Handler modes
+const (
+	// ModeBefore runs the handler before the agent. Its summary is added to the
+	// agent prompt, unless it reports the fault resolved, which skips the agent.
+	ModeBefore = "before"
+	// ModeInstead runs the handler instead of the agent: its report is the
+	// investigation. The agent still runs when the handler fails or declines the
+	// fault (outcome "continue").
+	ModeInstead = "instead"
+)
+
+// Handler outcomes
+const (
+	// OutcomeContinue hands the fault on to the next handler and the agent
+	OutcomeContinue = "continue"
+	// OutcomeResolved ends processing: the handler's report is the investigation
+	OutcomeResolved = "resolved"
+)
+
+// defaultTimeout bounds a handler run without a configured timeout
+const defaultTimeout = 2 * time.Minute
+
+// Input describes the fault a handler processes. Subprocess hooks receive it as
+// JSON on stdin.
+type Input struct {
+	IncidentID string `json:"incident_id"`
+	Cluster    string `json:"cluster"`
+	Namespace  string `json:"namespace,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Name       string `json:"name,omitempty"`
+	FaultType  string `json:"fault_type"`
+	Severity   string `json:"severity,omitempty"`
+	// Context is the fault's description from the event
+	Context string `json:"context,omitempty"`
+	// Kubeconfig is the cluster's triage kubeconfig, empty when triage is not
+	// configured with one
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// Workspace is the incident workspace; files the handler writes there are kept
+	// with the incident's artifacts
+	Workspace string `json:"workspace"`
+}
+
+// Result is what a handler found or did. Subprocess hooks print it as JSON on
+// stdout.
+type Result struct {
+	// Outcome is OutcomeContinue or OutcomeResolved. Empty means resolved for
+	// ModeInstead handlers and continue for ModeBefore handlers.
+	Outcome string `json:"outcome,omitempty"`
+	// Summary is markdown describing what the handler found or changed, added to the
+	// agent prompt when the agent runs
+	Summary string `json:"summary,omitempty"`
+	// RootCause and Confidence (LOW, MEDIUM, HIGH) summarize a resolved fault in
+	// notifications
+	RootCause  string `json:"root_cause,omitempty"`
+	Confidence string `json:"confidence,omitempty"`
+	// Report is the markdown investigation report of a resolved fault; Summary is
+	// used when it is empty
+	Report string `json:"report,omitempty"`
+}
+
+// Handler processes the faults it is registered for.
+type Handler interface {
+	Handle(ctx context.Context, in Input) (Result, error)
+}
+
+// HandlerFunc adapts a function to the Handler interface.
+type HandlerFunc func(ctx context.Context, in Input) (Result, error)
+
+// Handle implements Handler.
+func (f HandlerFunc) Handle(ctx context.Context, in Input) (Result, error) {
+	return f(ctx, in)
+}
+
+// Spec registers a handler for the faults it matches. A spec matches when the
+// fault type is in FaultTypes, and the resource kind and namespace are in Kinds and
+// Namespaces (or those are empty). Comparisons are case-insensitive.
+type Spec struct {
+	Name       string
+	FaultTypes []string
+	Kinds      []string
+	Namespaces []string
+	// Mode is ModeBefore or ModeInstead (default ModeBefore)
+	Mode string
+	// Command runs a subprocess hook; Plugin loads a Go plugin. Exactly one is set
+	// for specs built by New.
+	Command []string
+	Plugin  string
+	// Settings configure the handler: they are passed to a plugin's factory, and to
+	// a subprocess hook as NIGHTCRIER_SETTING_<NAME> environment variables
+	Settings map[string]string
+	// Timeout bounds each run (default 2 minutes)
+	Timeout time.Duration
+}
+
+// Validate checks the spec's name, match, mode, and implementation.
+func (s Spec) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("fault handler name is required")
+	}
+	if len(s.FaultTypes) == 0 {
+		return fmt.Errorf("fault handler %s must set fault_types", s.Name)
+	}
+	switch s.Mode {
+	case "", ModeBefore, ModeInstead:
+	default:
+		return fmt.Errorf("fault handler %s has invalid mode %q (must be %s or %s)", s.Name, s.Mode, ModeBefore, ModeInstead)
+	}
+	if (len(s.Command) == 0) == (s.Plugin == "") {
+		return fmt.Errorf("fault handler %s must set exactly one of command or plugin", s.Name)
+	}
+	if s.Timeout < 0 {
+		return fmt.Errorf("fault handler %s has a negative timeout", s.Name)
+	}
+	return nil
+}
+
+// matches reports whether the spec applies to a fault.
+func (s Spec) matches(faultType, kind, namespace string) bool {
+	return match.Any(s.FaultTypes, faultType) && match.Any(s.Kinds, kind) && match.Any(s.Namespaces, namespace)
+}
+
+// Run records one handler run on the incident.
+type Run struct {
+	Handler    string `json:"handler"`
+	Mode       string `json:"mode"`
+	Outcome    string `json:"outcome,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// Registration is a handler registered for the faults its spec matches.
+type Registration struct {
+	Spec
+	handler Handler
+}
+
+// Run runs the handler on a fault within the spec's timeout, and returns the run
+// record with its result. The outcome is normalized for the handler's mode; a
+// failed run has no outcome.
+func (r *Registration) Run(ctx context.Context, in Input) (Run, Result) {
+	run := Run{Handler: r.Name, Mode: r.Mode}
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := r.handler.Handle(runCtx, in)
+	run.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		run.Error = err.Error()
+		return run, Result{}
+	}
+	switch result.Outcome {
+	case OutcomeContinue, OutcomeResolved:
+	case "":
+		result.Outcome = OutcomeContinue
+		if r.Mode == ModeInstead {
+			result.Outcome = OutcomeResolved
+		}
+	default:
+		run.Error = fmt.Sprintf("invalid outcome %q (must be %s or %s)", result.Outcome, OutcomeContinue, OutcomeResolved)
+		return run, Result{}
+	}
+	if result.Report == "" {
+		result.Report = result.Summary
+	}
+	run.Outcome = result.Outcome
+	return run, result
+}
+
+// Registry holds the registered handlers in registration order.
+type Registry struct {
+	registrations []*Registration
+}
+
+// New returns a registry of the handlers of the specs, starting subprocess hooks
+// for specs with a command and loading the Go plugins of the others.
+func New(specs []Spec) (*Registry, error) {
+	r := &Registry{}
+	for _, spec := range specs {
+		if err := spec.Validate(); err != nil {
+			return nil, err
+		}
+		var handler Handler
+		if len(spec.Command) > 0 {
+			handler = NewCommand(spec.Command, spec.Settings)
+		} else {
+			h, err := LoadPlugin(spec.Plugin, spec.Settings)
+			if err != nil {
+				return nil, fmt.Errorf("fault handler %s: %w", spec.Name, err)
+			}
+			handler = h
+		}
+		if err := r.Register(spec, handler); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds a handler for the faults the spec matches. The spec's Command and
+// Plugin are ignored. Names must be unique.
+func (r *Registry) Register(spec Spec, handler Handler) error {
+	if spec.Mode == "" {
+		spec.Mode = ModeBefore
+	}
+	for _, existing := range r.registrations {
+		if existing.Name == spec.Name {
+			return fmt.Errorf("fault handler %s is registered twice", spec.Name)
+		}
+	}
+	r.registrations = append(r.registrations, &Registration{Spec: spec, handler: handler})
+	return nil
+}
+
+// Match returns the handlers for a fault, in registration order.
+func (r *Registry) Match(faultType, kind, namespace string) []*Registration {
+	if r == nil {
+		return nil
+	}
+	var matched []*Registration
+	for _, reg := range r.registrations {
+		if reg.matches(faultType, kind, namespace) {
+			matched = append(matched, reg)
+		}
+	}
+	return matched
+}
+
+// Len returns the number of registered handlers.
+func (r *Registry) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.registrations)
+}
+
+// Finding is the summary of a handler that handed the fault on to the agent.
+type Finding struct {
+	Handler string
+	Summary string
+}
+
+// PromptSection returns the agent prompt section with the handlers' findings, or
+// "" when none reported anything.
+func PromptSection(findings []Finding) string {
+	var b strings.Builder
+	for _, f := range findings {
+		if strings.TrimSpace(f.Summary) == "" {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString("## Fault Handler Findings\n\n")
+			b.WriteString("Specialized handlers processed this fault before you. Take what they found or changed into account, and verify it rather than repeating their steps.\n")
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n%s\n", f.Handler, strings.TrimSpace(f.Summary))
+	}
+	return b.String()
+}
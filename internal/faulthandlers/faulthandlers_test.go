@@ -0,0 +1,158 @@
+package faulthandlers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistryMatch(t *testing.T) {
+	r := &Registry{}
+	noop := HandlerFunc(func(ctx context.Context, in Input) (Result, error) { return Result{}, nil })
+	for _, spec := range []Spec{
+		{Name: "pvc-expand", FaultTypes: []string{"PVCFull"}, Kinds: []string{"PersistentVolumeClaim"}},
+		{Name: "oom", FaultTypes: []string{"OOMKilled"}, Namespaces: []string{"prod"}, Mode: ModeInstead},
+		{Name: "storage-audit", FaultTypes: []string{"pvcfull", "ProvisioningFailed"}},
+	} {
+		if err := r.Register(spec, noop); err != nil {
+			t.Fatalf("Register(%s) failed: %v", spec.Name, err)
+		}
+	}
+	if err := r.Register(Spec{Name: "oom", FaultTypes: []string{"OOMKilled"}}, noop); err == nil {
+		t.Error("Register() of a duplicate name succeeded, want error")
+	}
+
+	names := func(regs []*Registration) string {
+		var n []string
+		for _, reg := range regs {
+			n = append(n, reg.Name+"/"+reg.Mode)
+		}
+		return strings.Join(n, " ")
+	}
+	tests := []struct {
+		faultType, kind, namespace string
+		want                       string
+	}{
+		{"PVCFull", "PersistentVolumeClaim", "db", "pvc-expand/before storage-audit/before"},
+		{"PVCFull", "Pod", "db", "storage-audit/before"},
+		{"OOMKilled", "Pod", "prod", "oom/instead"},
+		{"OOMKilled", "Pod", "staging", ""},
+		{"CrashLoopBackOff", "Pod", "prod", ""},
+	}
+	for _, tt := range tests {
+		if got := names(r.Match(tt.faultType, tt.kind, tt.namespace)); got != tt.want {
+			t.Errorf("Match(%s, %s, %s) = %q, want %q", tt.faultType, tt.kind, tt.namespace, got, tt.want)
+		}
+	}
+}
+
+func TestRegistrationRun(t *testing.T) {
+	handler := func(result Result, err error) Handler {
+		return HandlerFunc(func(ctx context.Context, in Input) (Result, error) { return result, err })
+	}
+	tests := []struct {
+		name        string
+		mode        string
+		handler     Handler
+		wantOutcome string
+		wantError   bool
+	}{
+		{"before defaults to continue", ModeBefore, handler(Result{Summary: "expanded"}, nil), OutcomeContinue, false},
+		{"instead defaults to resolved", ModeInstead, handler(Result{Report: "# Report"}, nil), OutcomeResolved, false},
+		{"instead declines", ModeInstead, handler(Result{Outcome: OutcomeContinue}, nil), OutcomeContinue, false},
+		{"before resolves", ModeBefore, handler(Result{Outcome: OutcomeResolved}, nil), OutcomeResolved, false},
+		{"invalid outcome", ModeBefore, handler(Result{Outcome: "done"}, nil), "", true},
+		{"failure", ModeInstead, handler(Result{}, errors.New("boom")), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := &Registration{Spec: Spec{Name: "h", Mode: tt.mode}, handler: tt.handler}
+			run, _ := reg.Run(context.Background(), Input{})
+			if run.Outcome != tt.wantOutcome || (run.Error != "") != tt.wantError {
+				t.Errorf("Run() = %+v, want outcome %q and error %v", run, tt.wantOutcome, tt.wantError)
+			}
+		})
+	}
+
+	// The summary stands in for a missing report
+	reg := &Registration{Spec: Spec{Name: "h", Mode: ModeInstead}, handler: handler(Result{Summary: "expanded"}, nil)}
+	if _, result := reg.Run(context.Background(), Input{}); result.Report != "expanded" {
+		t.Errorf("Report = %q, want the summary", result.Report)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	dir := t.TempDir()
+	hook := filepath.Join(dir, "hook.sh")
+	script := `#!/bin/sh
+input=$(cat)
+case "$NIGHTCRIER_FAULT_TYPE" in
+PVCFull)
+	echo "{\"outcome\":\"resolved\",\"summary\":\"expanded $NIGHTCRIER_NAME by $NIGHTCRIER_SETTING_GROW_BY in $(basename $PWD)\",\"root_cause\":\"volume full\"}"
+	;;
+Text)
+	echo "$input" | grep -q '"kubeconfig":"/kube/config"' && echo "saw $KUBECONFIG"
+	;;
+*)
+	echo "unsupported fault" >&2
+	exit 3
+	;;
+esac
+`
+	if err := os.WriteFile(hook, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewCommand([]string{hook}, map[string]string{"grow-by": "50%"})
+	in := Input{Name: "data-db-0", Kubeconfig: "/kube/config", Workspace: dir}
+
+	in.FaultType = "PVCFull"
+	result, err := cmd.Handle(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Handle() failed: %v", err)
+	}
+	if result.Outcome != OutcomeResolved || result.RootCause != "volume full" || result.Summary != "expanded data-db-0 by 50% in "+filepath.Base(dir) {
+		t.Errorf("Handle() = %+v, want the resolved JSON result", result)
+	}
+
+	in.FaultType = "Text"
+	if result, err := cmd.Handle(context.Background(), in); err != nil || result.Summary != "saw /kube/config" {
+		t.Errorf("Handle() = %+v, %v, want the plain text output as summary", result, err)
+	}
+
+	in.FaultType = "Other"
+	if _, err := cmd.Handle(context.Background(), in); err == nil || !strings.Contains(err.Error(), "unsupported fault") {
+		t.Errorf("Handle() error = %v, want the failure with stderr", err)
+	}
+}
+
+func TestPromptSection(t *testing.T) {
+	if got := PromptSection([]Finding{{Handler: "quiet"}}); got != "" {
+		t.Errorf("PromptSection() without summaries = %q, want empty", got)
+	}
+	got := PromptSection([]Finding{{Handler: "pvc-expand", Summary: "Expanded the claim to 20Gi."}})
+	if !strings.HasPrefix(got, "## Fault Handler Findings") || !strings.Contains(got, "### pvc-expand\n\nExpanded the claim to 20Gi.") {
+		t.Errorf("PromptSection() = %q", got)
+	}
+}
+
+func TestNewValidatesSpecs(t *testing.T) {
+	for _, spec := range []Spec{
+		{FaultTypes: []string{"PVCFull"}, Command: []string{"true"}},
+		{Name: "h", Command: []string{"true"}},
+		{Name: "h", FaultTypes: []string{"PVCFull"}},
+		{Name: "h", FaultTypes: []string{"PVCFull"}, Command: []string{"true"}, Plugin: "h.so"},
+		{Name: "h", FaultTypes: []string{"PVCFull"}, Command: []string{"true"}, Mode: "after"},
+		{Name: "h", FaultTypes: []string{"PVCFull"}, Plugin: filepath.Join(t.TempDir(), "missing.so")},
+	} {
+		if _, err := New([]Spec{spec}); err == nil {
+			t.Errorf("New() should reject %+v", spec)
+		}
+	}
+	r, err := New([]Spec{{Name: "h", FaultTypes: []string{"PVCFull"}, Command: []string{"true"}}})
+	if err != nil || r.Len() != 1 {
+		t.Errorf("New() = %v, %v, want one handler", r, err)
+	}
+}
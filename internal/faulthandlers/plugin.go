@@ -0,0 +1,44 @@
+package faulthandlers
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// PluginSymbol is the factory a Go plugin exports, of type PluginFactory:
+//
+//	func NewHandler(settings map[string]string) (faulthandlers.Handler, error)
+//
+// Plugins must be built with "go build -buildmode=plugin" by the same Go version
+// and against the same nightcrier source as the binary loading them. Go plugins are
+// only supported on Linux, macOS, and FreeBSD, and by binaries built with cgo.
+const PluginSymbol = "NewHandler"
+
+// PluginFactory creates a plugin's handler from its settings.
+type PluginFactory = func(settings map[string]string) (Handler, error)
+
+// LoadPlugin opens the Go plugin at path and creates its handler.
+func LoadPlugin(path string, settings map[string]string) (Handler, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin %s: %w", path, err)
+	}
+	sym, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s does not export %s: %w", path, PluginSymbol, err)
+	}
+	var factory PluginFactory
+	switch f := sym.(type) {
+	case PluginFactory:
+		factory = f
+	case *PluginFactory:
+		factory = *f
+	default:
+		return nil, fmt.Errorf("plugin %s: %s is a %T, want func(map[string]string) (faulthandlers.Handler, error)", path, PluginSymbol, sym)
+	}
+	handler, err := factory(settings)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s failed to create its handler: %w", path, err)
+	}
+	return handler, nil
+}
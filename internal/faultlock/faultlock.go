@@ -0,0 +1,208 @@
+// Package faultlock keeps two investigations of the same fault from running at
+// the same time, whether the duplicate comes from a retry, a replayed or
+// backfilled event, or another nightcrier process. A lock is keyed by fault
+// signature and taken at two levels:
+//
+//   - a lock file under the lock directory (flock), shared by every process using
+//     the same workspace root and released by the kernel if the process dies
+//   - with fleet-wide shared state, a lease in the state store, so processes on
+//     other hosts are excluded too. The lease is renewed while the lock is held and
+//     expires on its own if its holder dies.
+package faultlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/fleet"
+)
+
+const (
+	// leaseTTL is how long a fleet-wide lease outlives its last renewal
+	leaseTTL = 2 * time.Minute
+	// renewInterval is how often a held lease is renewed
+	renewInterval = 30 * time.Second
+	// sharedTimeout bounds one update of the fleet-wide lease
+	sharedTimeout = 5 * time.Second
+)
+
+// ErrTimeout is returned by Acquire when the lock stayed held for the whole wait.
+var ErrTimeout = errors.New("timed out waiting for the fault lock")
+
+// lease is the fleet-wide lock state of one fault signature.
+type lease struct {
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquired_at"`
+}
+
+// Locker grants per-fault processing locks. It is safe for concurrent use.
+type Locker struct {
+	dir    string
+	holder string
+	shared *fleet.State
+}
+
+// New returns a locker keeping its lock files in dir. holder identifies this
+// process in fleet-wide leases, e.g. "hostname/pid".
+func New(dir, holder string) (*Locker, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	return &Locker{dir: dir, holder: holder}, nil
+}
+
+// Share also takes locks as leases in the fleet-wide state. Call before the first
+// lock is taken.
+func (l *Locker) Share(state *fleet.State) {
+	l.shared = state
+}
+
+// Lock is a held fault lock.
+type Lock struct {
+	locker    *Locker
+	signature string
+	file      *os.File
+	stop      chan struct{}
+	once      sync.Once
+}
+
+// TryAcquire takes the lock of a fault signature, or returns nil when another
+// investigation holds it.
+func (l *Locker) TryAcquire(ctx context.Context, signature string) (*Lock, error) {
+	file, err := os.OpenFile(filepath.Join(l.dir, signature+".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to lock %s: %w", file.Name(), err)
+	}
+
+	lock := &Lock{locker: l, signature: signature, file: file, stop: make(chan struct{})}
+	if l.shared != nil {
+		acquired, err := l.takeLease(ctx, signature)
+		switch {
+		case err != nil:
+			// Like the other fleet-wide limits, fall back to the per-host lock
+			slog.Warn("fleet-wide fault lock unavailable, locking per host",
+				"fault_signature", signature,
+				"error", err)
+		case !acquired:
+			lock.unlockFile()
+			return nil, nil
+		default:
+			go lock.renew()
+		}
+	}
+	return lock, nil
+}
+
+// Acquire takes the lock of a fault signature, waiting up to wait for another
+// investigation holding it to finish. onWait, when set, is called once if the
+// lock is held elsewhere. It returns ErrTimeout when the wait runs out.
+func (l *Locker) Acquire(ctx context.Context, signature string, wait, poll time.Duration, onWait func()) (*Lock, error) {
+	deadline := time.Now().Add(wait)
+	for waited := false; ; waited = true {
+		lock, err := l.TryAcquire(ctx, signature)
+		if err != nil || lock != nil {
+			return lock, err
+		}
+		if !waited && onWait != nil {
+			onWait()
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(poll):
+		}
+	}
+}
+
+// Release releases the lock. It may be called more than once.
+func (lk *Lock) Release() {
+	lk.once.Do(func() {
+		close(lk.stop)
+		if lk.locker.shared != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+			defer cancel()
+			if err := lk.locker.releaseLease(ctx, lk.signature); err != nil {
+				slog.Warn("failed to release fleet-wide fault lock, it expires on its own",
+					"fault_signature", lk.signature,
+					"error", err)
+			}
+		}
+		lk.unlockFile()
+	})
+}
+
+func (lk *Lock) unlockFile() {
+	_ = syscall.Flock(int(lk.file.Fd()), syscall.LOCK_UN)
+	lk.file.Close()
+}
+
+// renew keeps the fleet-wide lease alive until the lock is released.
+func (lk *Lock) renew() {
+	ticker := time.NewTicker(renewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-lk.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), sharedTimeout)
+			if _, err := lk.locker.takeLease(ctx, lk.signature); err != nil {
+				slog.Warn("failed to renew fleet-wide fault lock", "fault_signature", lk.signature, "error", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// takeLease takes or renews the fleet-wide lease of a signature and reports
+// whether this process holds it.
+func (l *Locker) takeLease(ctx context.Context, signature string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, sharedTimeout)
+	defer cancel()
+
+	acquired := false
+	err := fleet.Update(ctx, l.shared, leaseKey(signature), leaseTTL, func(current *lease) (*lease, error) {
+		if current != nil && current.Holder != l.holder {
+			acquired = false
+			return nil, nil
+		}
+		acquired = true
+		if current != nil {
+			return current, nil
+		}
+		return &lease{Holder: l.holder, AcquiredAt: l.shared.Now().UTC()}, nil
+	})
+	return acquired, err
+}
+
+// releaseLease expires the fleet-wide lease of a signature if this process holds
+// it.
+func (l *Locker) releaseLease(ctx context.Context, signature string) error {
+	return fleet.Update(ctx, l.shared, leaseKey(signature), 0, func(current *lease) (*lease, error) {
+		if current == nil || current.Holder != l.holder {
+			return nil, nil
+		}
+		return current, nil
+	})
+}
+
+func leaseKey(signature string) string {
+	return "lock/" + signature
+}
@@ -0,0 +1,90 @@
+package faultlock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/fleet"
+)
+
+const signature = "5d41402abc4b2a76b9719d911017c592"
+
+func newLocker(t *testing.T, dir, holder string) *Locker {
+	t.Helper()
+	l, err := New(dir, holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestTryAcquire(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	first, second := newLocker(t, dir, "a"), newLocker(t, dir, "b")
+
+	lock, err := first.TryAcquire(ctx, signature)
+	if err != nil || lock == nil {
+		t.Fatalf("TryAcquire() = %v, %v, want the lock", lock, err)
+	}
+	// Another process on the host, or another incident in this one, is excluded
+	if other, err := second.TryAcquire(ctx, signature); err != nil || other != nil {
+		t.Fatalf("TryAcquire() of a held lock = %v, %v, want nil", other, err)
+	}
+	if other, err := second.TryAcquire(ctx, "another-fault"); err != nil || other == nil {
+		t.Fatalf("TryAcquire() of another fault = %v, %v, want the lock", other, err)
+	}
+
+	lock.Release()
+	lock.Release()
+	if other, err := second.TryAcquire(ctx, signature); err != nil || other == nil {
+		t.Fatalf("TryAcquire() after Release() = %v, %v, want the lock", other, err)
+	}
+}
+
+func TestTryAcquire_Shared(t *testing.T) {
+	ctx := context.Background()
+	state := fleet.NewState(fleet.NewMemoryBackend())
+	// Processes on different hosts share only the state store
+	first, second := newLocker(t, t.TempDir(), "host-a/1"), newLocker(t, t.TempDir(), "host-b/1")
+	first.Share(state)
+	second.Share(state)
+
+	lock, err := first.TryAcquire(ctx, signature)
+	if err != nil || lock == nil {
+		t.Fatalf("TryAcquire() = %v, %v, want the lock", lock, err)
+	}
+	if other, err := second.TryAcquire(ctx, signature); err != nil || other != nil {
+		t.Fatalf("TryAcquire() of a lease held by another host = %v, %v, want nil", other, err)
+	}
+	lock.Release()
+	if other, err := second.TryAcquire(ctx, signature); err != nil || other == nil {
+		t.Fatalf("TryAcquire() after the lease was released = %v, %v, want the lock", other, err)
+	}
+}
+
+func TestAcquire_Wait(t *testing.T) {
+	ctx := context.Background()
+	l := newLocker(t, t.TempDir(), "a")
+	held, err := l.TryAcquire(ctx, signature)
+	if err != nil || held == nil {
+		t.Fatal("failed to take the lock")
+	}
+
+	waits := 0
+	if _, err := l.Acquire(ctx, signature, 30*time.Millisecond, 10*time.Millisecond, func() { waits++ }); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Acquire() of a held lock error = %v, want ErrTimeout", err)
+	}
+	if waits != 1 {
+		t.Errorf("onWait called %d times, want 1", waits)
+	}
+
+	time.AfterFunc(20*time.Millisecond, held.Release)
+	lock, err := l.Acquire(ctx, signature, time.Second, 10*time.Millisecond, nil)
+	if err != nil || lock == nil {
+		t.Fatalf("Acquire() once released = %v, %v, want the lock", lock, err)
+	}
+	lock.Release()
+}
@@ -0,0 +1,143 @@
+// Package fleet shares limit state between the nightcrier processes of a
+// multi-instance deployment, so that dedup windows, launch pacing, and budget
+// counters are enforced fleet-wide rather than per process.
+//
+// State is kept in the SQL state store (use PostgreSQL for processes on different
+// hosts) as one JSON document per key and updated with optimistic concurrency:
+// an update reads the document and its version, computes the new document, and
+// stores it only if the version is unchanged, retrying otherwise. Keys expire so
+// that state of signatures, providers, and days that went quiet is pruned.
+package fleet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// maxAttempts bounds the compare-and-swap retries of one update
+	maxAttempts = 10
+
+	// pruneInterval is how often expired state is deleted
+	pruneInterval = 10 * time.Minute
+)
+
+// ErrContended is returned when an update lost the compare-and-swap race on every
+// attempt.
+var ErrContended = errors.New("shared state updated concurrently too often")
+
+// Entry is the state stored under one key.
+type Entry struct {
+	// Value is the state as a JSON document
+	Value []byte
+	// Version increases with every update, for compare-and-swap
+	Version int64
+	// ExpiresAt is when the state may be pruned
+	ExpiresAt time.Time
+}
+
+// Backend stores the shared state, usually in the SQL state store (see
+// storage.StateStore). GetSharedState returns nil when nothing is stored under key;
+// SwapSharedState stores value only if the stored version is still version (0:
+// nothing stored) and reports whether it did.
+type Backend interface {
+	GetSharedState(ctx context.Context, key string) (*Entry, error)
+	SwapSharedState(ctx context.Context, key string, version int64, value []byte, expiresAt time.Time) (bool, error)
+	PruneSharedState(ctx context.Context, before time.Time) (int, error)
+}
+
+// State updates shared limit state. It is safe for concurrent use.
+type State struct {
+	backend Backend
+
+	mu        sync.Mutex
+	lastPrune time.Time
+
+	// now is replaceable for tests
+	now func() time.Time
+}
+
+// NewState returns the shared state kept in backend.
+func NewState(backend Backend) *State {
+	return &State{backend: backend, now: time.Now}
+}
+
+// Now returns the current time, which callers use for the state they compute.
+func (s *State) Now() time.Time {
+	return s.now()
+}
+
+// Update atomically updates the JSON document stored under key. fn receives the
+// current document decoded into a new value (nil when none is stored or it
+// expired) and returns the document to store, or nil to leave the state as is.
+// The stored document expires after ttl.
+func Update[T any](ctx context.Context, s *State, key string, ttl time.Duration, fn func(current *T) (*T, error)) error {
+	s.maybePrune(ctx)
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		stored, err := s.backend.GetSharedState(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		now := s.now()
+		var current *T
+		var version int64
+		if stored != nil {
+			version = stored.Version
+			if now.Before(stored.ExpiresAt) {
+				current = new(T)
+				if err := json.Unmarshal(stored.Value, current); err != nil {
+					return fmt.Errorf("failed to parse shared state %s: %w", key, err)
+				}
+			}
+		}
+
+		next, err := fn(current)
+		if err != nil {
+			return err
+		}
+		if next == nil {
+			return nil
+		}
+		value, err := json.Marshal(next)
+		if err != nil {
+			return fmt.Errorf("failed to marshal shared state %s: %w", key, err)
+		}
+
+		swapped, err := s.backend.SwapSharedState(ctx, key, version, value, now.Add(ttl))
+		if err != nil {
+			return err
+		}
+		if swapped {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to update shared state %s: %w", key, ErrContended)
+}
+
+// maybePrune deletes expired state at most once per pruneInterval.
+func (s *State) maybePrune(ctx context.Context) {
+	s.mu.Lock()
+	now := s.now()
+	if now.Sub(s.lastPrune) < pruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+
+	pruned, err := s.backend.PruneSharedState(ctx, now)
+	if err != nil {
+		slog.Warn("failed to prune expired shared state", "error", err)
+		return
+	}
+	if pruned > 0 {
+		slog.Debug("pruned expired shared state", "keys", pruned)
+	}
+}
@@ -0,0 +1,117 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// conflictingBackend changes the stored state between a read and the following
+// swap, as a concurrent process would.
+type conflictingBackend struct {
+	*MemoryBackend
+	conflicts int
+}
+
+func (c *conflictingBackend) GetSharedState(ctx context.Context, key string) (*Entry, error) {
+	state, err := c.MemoryBackend.GetSharedState(ctx, key)
+	if state != nil && c.conflicts > 0 {
+		c.conflicts--
+		c.MemoryBackend.SwapSharedState(ctx, key, state.Version, state.Value, state.ExpiresAt)
+	}
+	return state, err
+}
+
+type counter struct {
+	N int `json:"n"`
+}
+
+func increment(ctx context.Context, s *State, key string) error {
+	return Update(ctx, s, key, time.Hour, func(c *counter) (*counter, error) {
+		if c == nil {
+			c = &counter{}
+		}
+		c.N++
+		return c, nil
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryBackend()
+	s := NewState(backend)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := increment(ctx, s, "count"); err != nil {
+				t.Errorf("Update() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := string(backend.states["count"].Value); got != `{"n":5}` {
+		t.Errorf("state after 5 concurrent increments = %s, want n=5", got)
+	}
+}
+
+func TestUpdateRetriesOnConflict(t *testing.T) {
+	ctx := context.Background()
+	backend := &conflictingBackend{MemoryBackend: NewMemoryBackend()}
+	s := NewState(backend)
+	if err := increment(ctx, s, "count"); err != nil {
+		t.Fatal(err)
+	}
+
+	backend.conflicts = 2
+	if err := increment(ctx, s, "count"); err != nil {
+		t.Fatalf("Update() after two conflicts error = %v", err)
+	}
+	if got := string(backend.states["count"].Value); got != `{"n":2}` {
+		t.Errorf("state = %s, want n=2", got)
+	}
+
+	backend.conflicts = maxAttempts
+	if err := increment(ctx, s, "count"); !errors.Is(err, ErrContended) {
+		t.Errorf("Update() with constant conflicts error = %v, want ErrContended", err)
+	}
+}
+
+func TestUpdateExpiry(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryBackend()
+	s := NewState(backend)
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if err := increment(ctx, s, "count"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Expired state reads as absent and is pruned on a later update
+	now = now.Add(2 * time.Hour)
+	if err := increment(ctx, s, "count"); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(backend.states["count"].Value); got != `{"n":1}` {
+		t.Errorf("state after expiry = %s, want n=1", got)
+	}
+
+	if err := increment(ctx, s, "other"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(3 * time.Hour)
+	if err := Update(ctx, s, "count", time.Hour, func(c *counter) (*counter, error) { return nil, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.states) != 0 {
+		t.Errorf("states after prune = %v, want none", backend.states)
+	}
+}
@@ -0,0 +1,55 @@
+package fleet
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryBackend keeps shared state in process memory. It shares nothing between
+// processes and is meant for tests of the packages using shared state.
+type MemoryBackend struct {
+	mu     sync.Mutex
+	states map[string]Entry
+}
+
+// NewMemoryBackend returns an empty in-memory backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{states: make(map[string]Entry)}
+}
+
+// GetSharedState implements Backend.
+func (m *MemoryBackend) GetSharedState(ctx context.Context, key string) (*Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.states[key]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// SwapSharedState implements Backend.
+func (m *MemoryBackend) SwapSharedState(ctx context.Context, key string, version int64, value []byte, expiresAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.states[key].Version != version {
+		return false, nil
+	}
+	m.states[key] = Entry{Value: value, Version: version + 1, ExpiresAt: expiresAt}
+	return true, nil
+}
+
+// PruneSharedState implements Backend.
+func (m *MemoryBackend) PruneSharedState(ctx context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pruned := 0
+	for key, state := range m.states {
+		if state.ExpiresAt.Before(before) {
+			delete(m.states, key)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
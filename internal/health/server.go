@@ -1,15 +1,43 @@
 package health
 
 import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"mime"
+	"net"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/pause"
 )
 
+// maxTuningPatchBytes bounds the body of a tuning change request
+const maxTuningPatchBytes = 64 * 1024
+
+// maxReviewBytes bounds the body of an investigation review
+const maxReviewBytes = 64 * 1024
+
+// defaultReviewAccuracyWindow is the period /admin/reviews/accuracy aggregates
+// verdicts over when no since parameter is given
+const defaultReviewAccuracyWindow = 30 * 24 * time.Hour
+
+// maxPauseRequestBytes bounds the body of a triage pause or resume request
+const maxPauseRequestBytes = 16 * 1024
+
+// maxIngestEventBytes bounds the body of an event ingest request
+const maxIngestEventBytes = 256 * 1024
+
 // ClusterHealth represents the health status of a single cluster connection.
 // Design reference: design.md lines 551-561
 type ClusterHealth struct {
@@ -20,6 +48,9 @@ type ClusterHealth struct {
 	RetryIn       string                       `json:"retry_in,omitempty"`
 	EventCount    int64                        `json:"event_count"`
 	TriageEnabled bool                         `json:"triage_enabled"`
+	ServeOnly     bool                         `json:"serve_only"`
+	TriagePaused  bool                         `json:"triage_paused"`
+	Pause         *pause.Pause                 `json:"pause,omitempty"`
 	Permissions   *cluster.ClusterPermissions  `json:"permissions,omitempty"`
 	Labels        map[string]string            `json:"labels,omitempty"`
 }
@@ -33,7 +64,11 @@ type HealthSummary struct {
 		Active        int `json:"active"`
 		Unhealthy     int `json:"unhealthy"`
 		TriageEnabled int `json:"triage_enabled"`
+		ServeOnly     int `json:"serve_only"`
+		TriagePaused  int `json:"triage_paused"`
 	} `json:"summary"`
+	// TriagePausedGlobally is set while triage is paused on every cluster
+	TriagePausedGlobally *pause.Pause `json:"triage_paused_globally,omitempty"`
 }
 
 // ConnectionManagerHealth defines the interface for accessing cluster health data.
@@ -44,10 +79,160 @@ type ConnectionManagerHealth interface {
 	GetHealth() interface{}
 }
 
+// InvestigationsHealth provides the in-flight investigations and their progress.
+// Like ConnectionManagerHealth, it returns interface{} to avoid importing the
+// incident package.
+type InvestigationsHealth interface {
+	GetInvestigations() interface{}
+}
+
+// QueuesHealth provides the depth of the event queues (see
+// cluster.ConnectionManager.QueueStats).
+type QueuesHealth interface {
+	QueueStats() cluster.QueueStats
+}
+
+// AgentResourcesHealth provides the resource usage of agent executions aggregated
+// per agent CLI, image, and model (see storage.StateStore.AgentResourceStats).
+type AgentResourcesHealth interface {
+	GetAgentResources(ctx context.Context, since time.Time) (interface{}, error)
+}
+
+// NoiseHealth provides the latest noise analysis with its tuning suggestions (see
+// the noise package).
+type NoiseHealth interface {
+	GetNoiseReport() interface{}
+}
+
+// CredentialsHealth provides the expiry state of the credentials in each cluster's
+// triage kubeconfig (see the credexpiry package).
+type CredentialsHealth interface {
+	GetCredentialsHealth() interface{}
+}
+
+// TuningAdmin reads and changes the tuning in effect (see config.TuningStore). Like
+// the other providers it returns interface{}, because the config package imports
+// this one.
+type TuningAdmin interface {
+	GetTuning() interface{}
+	// PatchTuning applies a JSON document with the settings to change
+	PatchTuning(patch []byte) (interface{}, error)
+	// ReloadTuning re-reads the tuning file
+	ReloadTuning() (interface{}, error)
+}
+
+// EventIngest queues fault events for investigation as if their cluster's MCP
+// server had sent them, e.g. to backfill the events of an outage. It takes the raw
+// JSON request body because the events package imports config, which imports this
+// one. IngestEvent returns cluster.ErrQueueFull when the event queue has no room.
+type EventIngest interface {
+	IngestEvent(body []byte) error
+}
+
+// ErrIncidentNotFound is returned by IncidentArchives when the incident or its
+// workspace does not exist.
+var ErrIncidentNotFound = errors.New("incident not found")
+
+// IncidentArchives provides the complete workspaces of incidents as archives.
+// IncidentArchive looks up an incident by UUID or display ID and returns the file
+// name of its archive and the archive itself, streamed by WriteTo. It returns
+// ErrIncidentNotFound when the incident has no workspace.
+type IncidentArchives interface {
+	IncidentArchive(ctx context.Context, id string) (string, io.WriterTo, error)
+}
+
+// IncidentHistory provides the recorded state changes of incidents (see
+// storage.StateStore.IncidentHistory). GetIncidentHistory looks up an incident by
+// UUID or display ID and returns its status timeline and changes, plus its state
+// as of at when at is set. It returns ErrIncidentNotFound when the incident does
+// not exist.
+type IncidentHistory interface {
+	GetIncidentHistory(ctx context.Context, id string, at *time.Time) (interface{}, error)
+}
+
+// IncidentBundles provides incidents with their agent executions and triage
+// reports (see storage.StateStore.GetIncidentBundle). GetIncidentBundle looks up
+// an incident by UUID or display ID and returns ErrIncidentNotFound when it does
+// not exist.
+type IncidentBundles interface {
+	GetIncidentBundle(ctx context.Context, id string) (interface{}, error)
+}
+
+// IncidentChanges provides the incident changes made by every nightcrier instance
+// sharing the state store (see the incidentfeed package). SubscribeChanges returns
+// a channel receiving each change as it is recorded, and a function ending the
+// subscription.
+type IncidentChanges interface {
+	SubscribeChanges() (<-chan interface{}, func())
+}
+
+// changeStreamKeepalive is how often the incident change stream sends a comment,
+// so proxies do not close an idle stream
+const changeStreamKeepalive = 15 * time.Second
+
+// ErrInvalidReview is returned by InvestigationReviews.AddReview when the review
+// is incomplete or the incident has no completed investigation to review.
+var ErrInvalidReview = errors.New("invalid review")
+
+// ReviewRequest is the body of a review posted to /admin/incidents/{id}/reviews.
+type ReviewRequest struct {
+	// Reviewer is who reviewed the investigation (required)
+	Reviewer string `json:"reviewer"`
+	// Verdict is accurate, partially_accurate, or inaccurate
+	Verdict string `json:"verdict,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// InvestigationReviews records and serves human reviews of completed
+// investigations (see storage.StateStore.AddInvestigationReview). Incidents are
+// looked up by UUID or display ID; ErrIncidentNotFound is returned when the
+// incident does not exist.
+type InvestigationReviews interface {
+	GetReviews(ctx context.Context, id string) (interface{}, error)
+	AddReview(ctx context.Context, id string, review ReviewRequest) (interface{}, error)
+	// GetReviewAccuracy aggregates the verdicts given since the given time per
+	// agent model and prompt version
+	GetReviewAccuracy(ctx context.Context, since time.Time) (interface{}, error)
+}
+
+// Options secures the health server for exposure beyond localhost.
+// The zero value listens on all interfaces over plain HTTP without authentication.
+type Options struct {
+	// BindAddress is the interface to listen on (empty: all interfaces)
+	BindAddress string
+
+	// TLSCertFile and TLSKeyFile enable HTTPS when set
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// ClientCAFile enables mutual TLS: clients must present a certificate
+	// signed by a CA in this PEM bundle
+	ClientCAFile string
+
+	// AuthToken requires requests to carry "Authorization: Bearer <token>"
+	AuthToken string
+}
+
 // Server provides HTTP health monitoring endpoints for cluster connections.
 type Server struct {
-	manager ConnectionManagerHealth
-	addr    string
+	manager        ConnectionManagerHealth
+	investigations InvestigationsHealth
+	queues         QueuesHealth
+	agentResources AgentResourcesHealth
+	noise          NoiseHealth
+	credentials    CredentialsHealth
+	tuning         TuningAdmin
+	eventIngest    EventIngest
+	sources        http.Handler
+	pauses         *pause.Switch
+	archives       IncidentArchives
+	history        IncidentHistory
+	bundles        IncidentBundles
+	changes        IncidentChanges
+	reviews        InvestigationReviews
+	metrics        http.Handler
+	addr           string
+	opts           Options
 }
 
 // NewServer creates a new health monitoring server.
@@ -55,17 +240,175 @@ type Server struct {
 // Parameters:
 //   - manager: The ConnectionManager to query for health status
 //   - port: The port to listen on (default: 8080)
+//   - opts: Bind address, TLS, and authentication settings
 //
 // Returns a new Server instance ready to be started.
-func NewServer(manager ConnectionManagerHealth, port int) *Server {
+func NewServer(manager ConnectionManagerHealth, port int, opts Options) *Server {
 	if port == 0 {
 		port = 8080
 	}
 
 	return &Server{
 		manager: manager,
-		addr:    fmt.Sprintf(":%d", port),
+		addr:    net.JoinHostPort(opts.BindAddress, strconv.Itoa(port)),
+		opts:    opts,
+	}
+}
+
+// SetInvestigations enables the /health/investigations endpoint, which lists the
+// running investigations with the agent's current step. Call before Start.
+func (s *Server) SetInvestigations(provider InvestigationsHealth) {
+	s.investigations = provider
+}
+
+// SetQueues enables the /health/queues endpoint, which reports the depth and
+// capacity of the global event queue and of each cluster's queue. Call before
+// Start.
+func (s *Server) SetQueues(provider QueuesHealth) {
+	s.queues = provider
+}
+
+// SetMetrics enables the /metrics endpoint, which serves metrics for Prometheus
+// to scrape (see the metrics package). Call before Start.
+func (s *Server) SetMetrics(handler http.Handler) {
+	s.metrics = handler
+}
+
+// SetAgentResources enables the /health/agents endpoint, which reports the
+// resource usage of recent agent executions per agent version. Call before Start.
+func (s *Server) SetAgentResources(provider AgentResourcesHealth) {
+	s.agentResources = provider
+}
+
+// SetNoise enables the /health/noise endpoint, which reports the latest noise
+// analysis and its tuning suggestions. Call before Start.
+func (s *Server) SetNoise(provider NoiseHealth) {
+	s.noise = provider
+}
+
+// SetCredentials enables the /health/credentials endpoint, which reports when the
+// credentials of each cluster's triage kubeconfig expire. Call before Start.
+func (s *Server) SetCredentials(provider CredentialsHealth) {
+	s.credentials = provider
+}
+
+// SetTuning enables the /admin/tuning endpoints, which show and change the tuning
+// in effect without a restart. Because they change behavior, they are only served
+// when requests are authenticated (auth token or mutual TLS); otherwise an error is
+// returned and the endpoints stay disabled. Call before Start.
+func (s *Server) SetTuning(admin TuningAdmin) error {
+	if s.opts.AuthToken == "" && s.opts.ClientCAFile == "" {
+		return fmt.Errorf("the tuning API requires health server authentication (auth_token or client_ca_file)")
+	}
+	s.tuning = admin
+	return nil
+}
+
+// SetEventIngest enables the /admin/events endpoint, which queues fault events for
+// investigation. Like the tuning API it is only served when requests are
+// authenticated; otherwise an error is returned and the endpoint stays disabled.
+// Call before Start.
+func (s *Server) SetEventIngest(ingest EventIngest) error {
+	if s.opts.AuthToken == "" && s.opts.ClientCAFile == "" {
+		return fmt.Errorf("the event ingest API requires health server authentication (auth_token or client_ca_file)")
+	}
+	s.eventIngest = ingest
+	return nil
+}
+
+// SetSources enables the /sources/{cluster}/{source} endpoints, which receive the
+// fault events of push sources (Alertmanager and kubewatch webhooks, plain HTTP).
+// Like the event ingest API they are only served when requests are authenticated;
+// otherwise an error is returned and the endpoints stay disabled. Call before
+// Start.
+func (s *Server) SetSources(handler http.Handler) error {
+	if s.opts.AuthToken == "" && s.opts.ClientCAFile == "" {
+		return fmt.Errorf("the event source receiver requires health server authentication (auth_token or client_ca_file)")
+	}
+	s.sources = handler
+	return nil
+}
+
+// SetTriagePause enables the /admin/triage endpoints, which pause and resume
+// triage globally or per cluster. Like the tuning API they are only served when
+// requests are authenticated; otherwise an error is returned and the endpoints
+// stay disabled. Call before Start.
+func (s *Server) SetTriagePause(pauses *pause.Switch) error {
+	if s.opts.AuthToken == "" && s.opts.ClientCAFile == "" {
+		return fmt.Errorf("the triage pause API requires health server authentication (auth_token or client_ca_file)")
+	}
+	s.pauses = pauses
+	return nil
+}
+
+// SetIncidentArchives enables the /admin/incidents/{id}/archive endpoint, which
+// streams the complete workspace of an incident as a tar.gz archive. Because
+// workspaces hold cluster data, it is only served when requests are authenticated;
+// otherwise an error is returned and the endpoint stays disabled. Call before
+// Start.
+func (s *Server) SetIncidentArchives(archives IncidentArchives) error {
+	if s.opts.AuthToken == "" && s.opts.ClientCAFile == "" {
+		return fmt.Errorf("the incident archive API requires health server authentication (auth_token or client_ca_file)")
+	}
+	s.archives = archives
+	return nil
+}
+
+// SetIncidentHistory enables the /admin/incidents/{id}/history endpoint, which
+// returns an incident's status timeline and its status and assignment at a past
+// time. Like the archive API it is only served when requests are authenticated;
+// otherwise an error is returned and the endpoint stays disabled. Call before
+// Start.
+func (s *Server) SetIncidentHistory(history IncidentHistory) error {
+	if s.opts.AuthToken == "" && s.opts.ClientCAFile == "" {
+		return fmt.Errorf("the incident history API requires health server authentication (auth_token or client_ca_file)")
 	}
+	s.history = history
+	return nil
+}
+
+// SetIncidentBundles enables the /admin/incidents/{id} endpoint, which returns an
+// incident with its agent executions and triage reports for a full incident page.
+// Because reports hold cluster data, it is only served when requests are
+// authenticated; otherwise an error is returned and the endpoint stays disabled.
+// Call before Start.
+func (s *Server) SetIncidentBundles(bundles IncidentBundles) error {
+	if s.opts.AuthToken == "" && s.opts.ClientCAFile == "" {
+		return fmt.Errorf("the incident API requires health server authentication (auth_token or client_ca_file)")
+	}
+	s.bundles = bundles
+	return nil
+}
+
+// SetIncidentChanges enables the /admin/incidents/changes endpoint, which streams
+// incident changes to dashboards as server-sent events. Like the history API it is
+// only served when requests are authenticated; otherwise an error is returned and
+// the endpoint stays disabled. Call before Start.
+func (s *Server) SetIncidentChanges(changes IncidentChanges) error {
+	if s.opts.AuthToken == "" && s.opts.ClientCAFile == "" {
+		return fmt.Errorf("the incident change stream requires health server authentication (auth_token or client_ca_file)")
+	}
+	s.changes = changes
+	return nil
+}
+
+// SetInvestigationReviews enables the /admin/incidents/{id}/reviews endpoint,
+// which records and lists review comments and verdicts on investigations, and the
+// /admin/reviews/accuracy endpoint, which aggregates the verdicts per agent model
+// and prompt version. Because reviews are attributed to the reviewer named in the
+// request, they are only served when requests are authenticated; otherwise an
+// error is returned and the endpoints stay disabled. Call before Start.
+func (s *Server) SetInvestigationReviews(reviews InvestigationReviews) error {
+	if s.opts.AuthToken == "" && s.opts.ClientCAFile == "" {
+		return fmt.Errorf("the investigation review API requires health server authentication (auth_token or client_ca_file)")
+	}
+	s.reviews = reviews
+	return nil
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	return s.addr
 }
 
 // Start begins serving health monitoring endpoints.
@@ -73,15 +416,164 @@ func NewServer(manager ConnectionManagerHealth, port int) *Server {
 //
 // Available endpoints:
 //   - GET /health/clusters - Returns detailed cluster health status
+//   - GET /health/investigations - Returns running investigations and their progress,
+//     and the results of recent ones (when SetInvestigations was called)
+//   - GET /health/queues - Returns the event queue depths (when SetQueues was called)
+//   - GET /metrics - Returns metrics in the Prometheus text format (when SetMetrics
+//     was called)
+//   - GET /health/agents?since=24h - Returns agent resource usage per agent version
+//     (when SetAgentResources was called)
+//   - GET /health/noise - Returns the latest noise analysis and tuning suggestions
+//     (when SetNoise was called)
+//   - GET /health/credentials - Returns the expiry of each cluster's triage
+//     credentials (when SetCredentials was called)
+//   - GET /admin/tuning - Returns the tuning in effect (when SetTuning was called)
+//   - PATCH /admin/tuning - Changes the tuning settings in the JSON body
+//   - POST /admin/tuning/reload - Re-reads tuning.yaml, like SIGHUP
+//   - POST /admin/events - Queues the fault event in the JSON body for investigation
+//     (when SetEventIngest was called)
+//   - POST /sources/{cluster}/{source} - Receives the events of a push source
+//     (when SetSources was called)
+//   - GET /admin/triage - Returns the triage pause state (when SetTriagePause was
+//     called)
+//   - POST /admin/triage/pause - Pauses triage of the cluster in the JSON body, or
+//     of every cluster
+//   - POST /admin/triage/resume - Resumes triage of the cluster in the JSON body,
+//     or lifts the global pause
+//   - GET /admin/incidents/{id} - Returns the incident with its agent executions
+//     and triage reports (when SetIncidentBundles was called)
+//   - GET /admin/incidents/{id}/archive - Streams the incident's workspace as a
+//     tar.gz archive (when SetIncidentArchives was called)
+//   - GET /admin/incidents/{id}/history?at=<RFC 3339> - Returns the incident's
+//     status timeline and state at a time (when SetIncidentHistory was called)
+//   - GET /admin/incidents/changes - Streams incident changes made by any instance
+//     as server-sent events (when SetIncidentChanges was called)
+//   - GET /admin/incidents/{id}/reviews - Returns the reviews of the incident's
+//     investigation (when SetInvestigationReviews was called)
+//   - POST /admin/incidents/{id}/reviews - Records the review in the JSON body
+//   - GET /admin/reviews/accuracy?since=720h - Returns the review verdicts per
+//     agent model and prompt version
 //
-// Parameters:
-//   - ctx: Context for shutdown coordination (currently unused, for future graceful shutdown)
+// When TLS is configured the server serves HTTPS only, and when a client CA is
+// configured every connection must present a verified client certificate.
 func (s *Server) Start() error {
+	server := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if s.opts.TLSCertFile == "" {
+		slog.Info("starting health server", "address", s.addr, "tls", false, "auth_token", s.opts.AuthToken != "")
+		return server.ListenAndServe()
+	}
+
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
+	server.TLSConfig = tlsConfig
+
+	slog.Info("starting health server", "address", s.addr, "tls", true,
+		"mtls", s.opts.ClientCAFile != "", "auth_token", s.opts.AuthToken != "")
+	return server.ListenAndServeTLS(s.opts.TLSCertFile, s.opts.TLSKeyFile)
+}
+
+// Handler returns the HTTP handler serving the health endpoints, wrapped in bearer
+// token authentication when a token is configured.
+func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health/clusters", s.handleClustersHealth)
+	if s.investigations != nil {
+		mux.HandleFunc("/health/investigations", s.handleInvestigations)
+	}
+	if s.queues != nil {
+		mux.HandleFunc("/health/queues", s.handleQueues)
+	}
+	if s.agentResources != nil {
+		mux.HandleFunc("/health/agents", s.handleAgentResources)
+	}
+	if s.noise != nil {
+		mux.HandleFunc("/health/noise", s.handleNoise)
+	}
+	if s.credentials != nil {
+		mux.HandleFunc("/health/credentials", s.handleCredentials)
+	}
+	if s.metrics != nil {
+		mux.Handle("/metrics", s.metrics)
+	}
+	if s.tuning != nil {
+		mux.HandleFunc("/admin/tuning", s.handleTuning)
+		mux.HandleFunc("/admin/tuning/reload", s.handleTuningReload)
+	}
+	if s.eventIngest != nil {
+		mux.HandleFunc("/admin/events", s.handleIngestEvent)
+	}
+	if s.sources != nil {
+		mux.Handle("/sources/", s.sources)
+	}
+	if s.pauses != nil {
+		mux.HandleFunc("/admin/triage", s.handleTriagePause)
+		mux.HandleFunc("/admin/triage/pause", s.handlePauseTriage)
+		mux.HandleFunc("/admin/triage/resume", s.handleResumeTriage)
+	}
+	if s.bundles != nil {
+		mux.HandleFunc("/admin/incidents/{id}", s.handleIncidentBundle)
+	}
+	if s.archives != nil {
+		mux.HandleFunc("/admin/incidents/{id}/archive", s.handleIncidentArchive)
+	}
+	if s.history != nil {
+		mux.HandleFunc("/admin/incidents/{id}/history", s.handleIncidentHistory)
+	}
+	if s.changes != nil {
+		mux.HandleFunc("/admin/incidents/changes", s.handleIncidentChanges)
+	}
+	if s.reviews != nil {
+		mux.HandleFunc("/admin/incidents/{id}/reviews", s.handleInvestigationReviews)
+		mux.HandleFunc("/admin/reviews/accuracy", s.handleReviewAccuracy)
+	}
 
-	slog.Info("starting health server", "address", s.addr)
-	return http.ListenAndServe(s.addr, mux)
+	if s.opts.AuthToken == "" {
+		return mux
+	}
+	return requireBearerToken(s.opts.AuthToken, mux)
+}
+
+// tlsConfig builds the TLS configuration, loading the client CA bundle for mutual
+// TLS when configured.
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.opts.ClientCAFile == "" {
+		return cfg, nil
+	}
+
+	pem, err := os.ReadFile(s.opts.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read health server client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("failed to parse health server client CA file %s: no PEM certificates found", s.opts.ClientCAFile)
+	}
+	cfg.ClientCAs = pool
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	return cfg, nil
+}
+
+// requireBearerToken rejects requests that do not carry the expected bearer token.
+// The comparison is constant-time so the token cannot be recovered by timing.
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	expected := []byte(token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="nightcrier"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
 }
 
 // handleClustersHealth handles GET /health/clusters requests.
@@ -95,6 +587,447 @@ func (s *Server) handleClustersHealth(w http.ResponseWriter, r *http.Request) {
 	// Get health summary from connection manager (returns interface{} due to import constraints)
 	health := s.manager.GetHealth()
 
+	writeJSON(w, health)
+}
+
+// handleInvestigations handles GET /health/investigations requests.
+// Returns JSON with the running investigations and the agent's current step.
+func (s *Server) handleInvestigations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, s.investigations.GetInvestigations())
+}
+
+// handleQueues handles GET /health/queues requests.
+// Returns JSON with the depth, capacity, and drop counts of the event queues.
+func (s *Server) handleQueues(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, s.queues.QueueStats())
+}
+
+// handleNoise handles GET /health/noise requests.
+// Returns JSON with the latest noise analysis and its tuning suggestions.
+func (s *Server) handleNoise(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, s.noise.GetNoiseReport())
+}
+
+// handleCredentials handles GET /health/credentials requests.
+// Returns JSON with the credential expiry state of each cluster.
+func (s *Server) handleCredentials(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, s.credentials.GetCredentialsHealth())
+}
+
+// defaultAgentResourcesWindow is the period /health/agents aggregates without a
+// since parameter
+const defaultAgentResourcesWindow = 24 * time.Hour
+
+// handleAgentResources handles GET /health/agents requests.
+// Returns JSON with the resource usage of the agent executions started within the
+// since period (a duration, default 24h), per agent CLI, image, and model.
+func (s *Server) handleAgentResources(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	window := defaultAgentResourcesWindow
+	if since := r.URL.Query().Get("since"); since != "" {
+		parsed, err := time.ParseDuration(since)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "since must be a positive duration, e.g. 24h", http.StatusBadRequest)
+			return
+		}
+		window = parsed
+	}
+
+	stats, err := s.agentResources.GetAgentResources(r.Context(), time.Now().Add(-window))
+	if err != nil {
+		slog.Error("failed to get agent resource usage", "error", err)
+		http.Error(w, "failed to get agent resource usage", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, stats)
+}
+
+// handleTuning handles /admin/tuning: GET returns the tuning in effect, PATCH
+// applies the settings in the JSON body and returns the resulting change.
+func (s *Server) handleTuning(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, s.tuning.GetTuning())
+	case http.MethodPatch:
+		patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxTuningPatchBytes))
+		if err != nil {
+			http.Error(w, "failed to read request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		update, err := s.tuning.PatchTuning(patch)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		slog.Info("tuning changed through the tuning API", "remote_addr", r.RemoteAddr)
+		writeJSON(w, update)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleTuningReload handles POST /admin/tuning/reload by re-reading the tuning
+// file.
+func (s *Server) handleTuningReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	update, err := s.tuning.ReloadTuning()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	slog.Info("tuning reloaded through the tuning API", "remote_addr", r.RemoteAddr)
+	writeJSON(w, update)
+}
+
+// handleIngestEvent handles POST /admin/events by queueing the fault event in the
+// body. It answers 202 Accepted once queued, and 503 with Retry-After when the
+// event queue is full so the client can back off.
+func (s *Server) handleIngestEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIngestEventBytes))
+	if err != nil {
+		http.Error(w, "failed to read request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.eventIngest.IngestEvent(body); err != nil {
+		if errors.Is(err, cluster.ErrQueueFull) {
+			w.Header().Set("Retry-After", "5")
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// pauseRequest is the body of a triage pause or resume request. An empty cluster
+// pauses or resumes triage globally.
+type pauseRequest struct {
+	Cluster  string `json:"cluster"`
+	Reason   string `json:"reason"`
+	PausedBy string `json:"paused_by"`
+}
+
+// readPauseRequest decodes the body of a triage pause or resume request, writing
+// an error response and returning false when it is invalid.
+func readPauseRequest(w http.ResponseWriter, r *http.Request) (pauseRequest, bool) {
+	var req pauseRequest
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return req, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPauseRequestBytes))
+	if err != nil {
+		http.Error(w, "failed to read request body: "+err.Error(), http.StatusBadRequest)
+		return req, false
+	}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+			return req, false
+		}
+	}
+	return req, true
+}
+
+// handleTriagePause handles GET /admin/triage by returning the pause state.
+func (s *Server) handleTriagePause(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, s.pauses.State())
+}
+
+// handlePauseTriage handles POST /admin/triage/pause and returns the resulting
+// pause state.
+func (s *Server) handlePauseTriage(w http.ResponseWriter, r *http.Request) {
+	req, ok := readPauseRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.PausedBy == "" {
+		req.PausedBy = "api"
+	}
+	state, err := s.pauses.Pause(req.Cluster, pause.Pause{PausedBy: req.PausedBy, Reason: req.Reason})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	slog.Info("triage paused through the admin API",
+		"cluster", req.Cluster,
+		"reason", req.Reason,
+		"paused_by", req.PausedBy,
+		"remote_addr", r.RemoteAddr)
+	writeJSON(w, state)
+}
+
+// handleResumeTriage handles POST /admin/triage/resume and returns the resulting
+// pause state. Resuming triage that is not paused answers 409 Conflict.
+func (s *Server) handleResumeTriage(w http.ResponseWriter, r *http.Request) {
+	req, ok := readPauseRequest(w, r)
+	if !ok {
+		return
+	}
+	state, err := s.pauses.Resume(req.Cluster)
+	if errors.Is(err, pause.ErrNotPaused) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	slog.Info("triage resumed through the admin API", "cluster", req.Cluster, "remote_addr", r.RemoteAddr)
+	writeJSON(w, state)
+}
+
+// handleIncidentArchive handles GET /admin/incidents/{id}/archive by streaming the
+// incident's workspace as a tar.gz attachment. The archive is written as it is
+// built, so an error after the first bytes can only abort the response.
+func (s *Server) handleIncidentArchive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.PathValue("id")
+	name, archive, err := s.archives.IncidentArchive(r.Context(), id)
+	if errors.Is(err, ErrIncidentNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.Error("failed to look up incident workspace", "incident_id", id, "error", err)
+		http.Error(w, "failed to look up incident workspace", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	w.WriteHeader(http.StatusOK)
+	written, err := archive.WriteTo(w)
+	if err != nil {
+		slog.Error("failed to stream incident archive",
+			"incident_id", id,
+			"bytes_written", written,
+			"error", err)
+		// Abort the response so the client does not take a truncated archive as
+		// complete
+		panic(http.ErrAbortHandler)
+	}
+	slog.Info("incident archive downloaded",
+		"incident_id", id,
+		"bytes", written,
+		"remote_addr", r.RemoteAddr)
+}
+
+// handleIncidentHistory handles GET /admin/incidents/{id}/history requests.
+// Returns JSON with the incident's recorded state changes and status timeline; with
+// at (an RFC 3339 time), also its state as of that time.
+func (s *Server) handleIncidentHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var at *time.Time
+	if value := r.URL.Query().Get("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "at must be an RFC 3339 time, e.g. 2024-05-01T14:00:00Z", http.StatusBadRequest)
+			return
+		}
+		at = &parsed
+	}
+
+	id := r.PathValue("id")
+	history, err := s.history.GetIncidentHistory(r.Context(), id, at)
+	if errors.Is(err, ErrIncidentNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.Error("failed to get incident history", "incident_id", id, "error", err)
+		http.Error(w, "failed to get incident history", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, history)
+}
+
+// handleIncidentBundle handles GET /admin/incidents/{id}.
+func (s *Server) handleIncidentBundle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.PathValue("id")
+	bundle, err := s.bundles.GetIncidentBundle(r.Context(), id)
+	if errors.Is(err, ErrIncidentNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.Error("failed to get incident", "incident_id", id, "error", err)
+		http.Error(w, "failed to get incident", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, bundle)
+}
+
+// handleIncidentChanges handles GET /admin/incidents/changes, streaming each
+// incident change as an "incident_change" server-sent event with the change as
+// JSON data, until the client disconnects.
+func (s *Server) handleIncidentChanges(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	changes, unsubscribe := s.changes.SubscribeChanges()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepalive := time.NewTicker(changeStreamKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(change)
+			if err != nil {
+				slog.Error("failed to encode incident change", "error", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: incident_change\ndata: %s\n\n", data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// handleInvestigationReviews handles /admin/incidents/{id}/reviews: GET returns
+// the incident's reviews, POST records the review in the JSON body.
+func (s *Server) handleInvestigationReviews(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var result interface{}
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		result, err = s.reviews.GetReviews(r.Context(), id)
+	case http.MethodPost:
+		var review ReviewRequest
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReviewBytes))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&review); err != nil {
+			http.Error(w, fmt.Sprintf("invalid review: %v", err), http.StatusBadRequest)
+			return
+		}
+		result, err = s.reviews.AddReview(r.Context(), id, review)
+		if err == nil {
+			slog.Info("investigation reviewed through the review API",
+				"incident_id", id,
+				"reviewer", review.Reviewer,
+				"verdict", review.Verdict,
+				"remote_addr", r.RemoteAddr)
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case errors.Is(err, ErrIncidentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrInvalidReview):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case err != nil:
+		slog.Error("failed to handle investigation reviews", "incident_id", id, "error", err)
+		http.Error(w, "failed to handle investigation reviews", http.StatusInternalServerError)
+	default:
+		writeJSON(w, result)
+	}
+}
+
+// handleReviewAccuracy handles GET /admin/reviews/accuracy requests. The optional
+// since parameter is a duration (default: 30 days).
+func (s *Server) handleReviewAccuracy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	window := defaultReviewAccuracyWindow
+	if since := r.URL.Query().Get("since"); since != "" {
+		parsed, err := time.ParseDuration(since)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "since must be a positive duration, e.g. 720h", http.StatusBadRequest)
+			return
+		}
+		window = parsed
+	}
+
+	stats, err := s.reviews.GetReviewAccuracy(r.Context(), time.Now().Add(-window))
+	if err != nil {
+		slog.Error("failed to get review accuracy", "error", err)
+		http.Error(w, "failed to get review accuracy", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, stats)
+}
+
+// writeJSON writes v as an indented JSON response.
+func writeJSON(w http.ResponseWriter, v interface{}) {
 	// Set response headers
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -102,7 +1035,7 @@ func (s *Server) handleClustersHealth(w http.ResponseWriter, r *http.Request) {
 	// Encode response - the interface{} will be marshaled as JSON
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(health); err != nil {
+	if err := encoder.Encode(v); err != nil {
 		slog.Error("failed to encode health response", "error", err)
 	}
 }
@@ -0,0 +1,745 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/pause"
+)
+
+type fakeManager struct{}
+
+func (fakeManager) GetHealth() interface{} {
+	return map[string]string{"status": "ok"}
+}
+
+func TestNewServer_Addr(t *testing.T) {
+	tests := []struct {
+		port int
+		bind string
+		want string
+	}{
+		{0, "", ":8080"},
+		{9090, "", ":9090"},
+		{9090, "127.0.0.1", "127.0.0.1:9090"},
+		{9090, "::1", "[::1]:9090"},
+	}
+	for _, tt := range tests {
+		s := NewServer(fakeManager{}, tt.port, Options{BindAddress: tt.bind})
+		if got := s.Addr(); got != tt.want {
+			t.Errorf("Addr() for port %d bind %q = %q, want %q", tt.port, tt.bind, got, tt.want)
+		}
+	}
+}
+
+func TestHandler_BearerToken(t *testing.T) {
+	s := NewServer(fakeManager{}, 8080, Options{AuthToken: "secret"})
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer nope", http.StatusUnauthorized},
+		{"wrong scheme", "Basic secret", http.StatusUnauthorized},
+		{"valid", "Bearer secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, ts.URL+"/health/clusters", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_NoTokenConfigured(t *testing.T) {
+	ts := httptest.NewServer(NewServer(fakeManager{}, 8080, Options{}).Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/health/clusters")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestTLSConfig_ClientCA(t *testing.T) {
+	s := NewServer(fakeManager{}, 8080, Options{TLSCertFile: "tls.crt", TLSKeyFile: "tls.key"})
+	cfg, err := s.tlsConfig()
+	if err != nil {
+		t.Fatalf("tlsConfig() error = %v", err)
+	}
+	if cfg.ClientAuth != tls.NoClientCert {
+		t.Errorf("ClientAuth = %v without a client CA, want NoClientCert", cfg.ClientAuth)
+	}
+
+	// Reuse the test server's certificate as the client CA bundle
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	defer ts.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, encodeCertPEM(ts.Certificate().Raw), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s.opts.ClientCAFile = caFile
+	cfg, err = s.tlsConfig()
+	if err != nil {
+		t.Fatalf("tlsConfig() error = %v", err)
+	}
+	if cfg.ClientAuth != tls.RequireAndVerifyClientCert || cfg.ClientCAs == nil {
+		t.Errorf("mutual TLS not required: ClientAuth = %v", cfg.ClientAuth)
+	}
+
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.tlsConfig(); err == nil {
+		t.Error("tlsConfig() should fail for a CA file without certificates")
+	}
+}
+
+func encodeCertPEM(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+type fakeInvestigations struct{}
+
+func (fakeInvestigations) GetInvestigations() interface{} {
+	return map[string]int{"count": 1}
+}
+
+func TestHandler_Investigations(t *testing.T) {
+	s := NewServer(fakeManager{}, 8080, Options{})
+	ts := httptest.NewServer(s.Handler())
+	resp, err := http.Get(ts.URL + "/health/investigations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	ts.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status without a provider = %d, want 404", resp.StatusCode)
+	}
+
+	s.SetInvestigations(fakeInvestigations{})
+	ts = httptest.NewServer(s.Handler())
+	defer ts.Close()
+	resp, err = http.Get(ts.URL + "/health/investigations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body map[string]int
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || body["count"] != 1 {
+		t.Errorf("status = %d body = %v, want 200 with count 1", resp.StatusCode, body)
+	}
+}
+
+type fakeQueues struct{}
+
+func (fakeQueues) QueueStats() cluster.QueueStats {
+	return cluster.QueueStats{GlobalDepth: 3, GlobalCapacity: 100, Clusters: []cluster.ClusterQueueStats{{Cluster: "prod", Depth: 2, Capacity: 50}}}
+}
+
+func TestHandler_Queues(t *testing.T) {
+	s := NewServer(fakeManager{}, 8080, Options{})
+	s.SetQueues(fakeQueues{})
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/health/queues")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body cluster.QueueStats
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || body.GlobalDepth != 3 || len(body.Clusters) != 1 || body.Clusters[0].Depth != 2 {
+		t.Errorf("status = %d body = %+v, want 200 with the queue stats", resp.StatusCode, body)
+	}
+}
+
+type fakeNoise struct{}
+
+func (fakeNoise) GetNoiseReport() interface{} {
+	return map[string]interface{}{"suggestions": []string{"consider a longer dedup window"}}
+}
+
+func TestHandler_Noise(t *testing.T) {
+	s := NewServer(fakeManager{}, 8080, Options{})
+	s.SetNoise(fakeNoise{})
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/health/noise")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Suggestions []string `json:"suggestions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || len(body.Suggestions) != 1 {
+		t.Errorf("status = %d body = %+v, want 200 with the suggestion", resp.StatusCode, body)
+	}
+}
+
+type fakeCredentials struct{}
+
+func (fakeCredentials) GetCredentialsHealth() interface{} {
+	return map[string]interface{}{"needs_attention": 1}
+}
+
+func TestHandler_Credentials(t *testing.T) {
+	s := NewServer(fakeManager{}, 8080, Options{})
+	s.SetCredentials(fakeCredentials{})
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/health/credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		NeedsAttention int `json:"needs_attention"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || body.NeedsAttention != 1 {
+		t.Errorf("status = %d body = %+v, want 200 with one cluster needing attention", resp.StatusCode, body)
+	}
+
+	resp, err = http.Post(ts.URL+"/health/credentials", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", resp.StatusCode)
+	}
+}
+
+type fakeAgentResources struct {
+	since time.Time
+}
+
+func (f *fakeAgentResources) GetAgentResources(ctx context.Context, since time.Time) (interface{}, error) {
+	f.since = since
+	return []map[string]string{{"agent_image": "agent:v1"}}, nil
+}
+
+func TestHandler_AgentResources(t *testing.T) {
+	provider := &fakeAgentResources{}
+	s := NewServer(fakeManager{}, 8080, Options{})
+	s.SetAgentResources(provider)
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/health/agents?since=2h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body []map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || len(body) != 1 || body[0]["agent_image"] != "agent:v1" {
+		t.Errorf("status = %d body = %v, want 200 with agent:v1", resp.StatusCode, body)
+	}
+	if window := time.Since(provider.since); window < 2*time.Hour || window > 2*time.Hour+time.Minute {
+		t.Errorf("aggregated over %v, want 2h", window)
+	}
+
+	resp, err = http.Get(ts.URL + "/health/agents?since=yesterday")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status with an invalid since = %d, want 400", resp.StatusCode)
+	}
+}
+
+type fakeTuning struct {
+	patched string
+}
+
+func (f *fakeTuning) GetTuning() interface{} {
+	return map[string]int{"buffer": 100}
+}
+
+func (f *fakeTuning) PatchTuning(patch []byte) (interface{}, error) {
+	if !json.Valid(patch) {
+		return nil, errors.New("invalid tuning patch")
+	}
+	f.patched = string(patch)
+	return map[string]int{"buffer": 200}, nil
+}
+
+func (f *fakeTuning) ReloadTuning() (interface{}, error) {
+	return map[string]int{"buffer": 100}, nil
+}
+
+func TestHandler_Tuning(t *testing.T) {
+	if err := NewServer(fakeManager{}, 8080, Options{}).SetTuning(&fakeTuning{}); err == nil {
+		t.Error("SetTuning() should require authentication")
+	}
+
+	tuning := &fakeTuning{}
+	s := NewServer(fakeManager{}, 8080, Options{AuthToken: "s3cret"})
+	if err := s.SetTuning(tuning); err != nil {
+		t.Fatalf("SetTuning() error = %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	request := func(method, path, body string, token string) int {
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := request(http.MethodPatch, "/admin/tuning", `{"io": {}}`, ""); code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated PATCH = %d, want 401", code)
+	}
+	if code := request(http.MethodGet, "/admin/tuning", "", "s3cret"); code != http.StatusOK {
+		t.Errorf("GET = %d, want 200", code)
+	}
+	if code := request(http.MethodPatch, "/admin/tuning", `{"io": {}}`, "s3cret"); code != http.StatusOK || tuning.patched != `{"io": {}}` {
+		t.Errorf("PATCH = %d (patched %q), want 200", code, tuning.patched)
+	}
+	if code := request(http.MethodPatch, "/admin/tuning", `{`, "s3cret"); code != http.StatusBadRequest {
+		t.Errorf("invalid PATCH = %d, want 400", code)
+	}
+	if code := request(http.MethodPost, "/admin/tuning/reload", "", "s3cret"); code != http.StatusOK {
+		t.Errorf("reload = %d, want 200", code)
+	}
+	if code := request(http.MethodGet, "/admin/tuning/reload", "", "s3cret"); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET reload = %d, want 405", code)
+	}
+}
+
+type fakeIngest struct {
+	full     bool
+	received []string
+}
+
+func (f *fakeIngest) IngestEvent(body []byte) error {
+	if f.full {
+		return cluster.ErrQueueFull
+	}
+	if !json.Valid(body) {
+		return errors.New("invalid event")
+	}
+	f.received = append(f.received, string(body))
+	return nil
+}
+
+func TestHandler_IngestEvent(t *testing.T) {
+	if err := NewServer(fakeManager{}, 8080, Options{}).SetEventIngest(&fakeIngest{}); err == nil {
+		t.Error("SetEventIngest() should require authentication")
+	}
+
+	ingest := &fakeIngest{}
+	s := NewServer(fakeManager{}, 8080, Options{AuthToken: "s3cret"})
+	if err := s.SetEventIngest(ingest); err != nil {
+		t.Fatalf("SetEventIngest() error = %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	post := func(body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/admin/events", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	if resp := post(`{"cluster": "prod"}`); resp.StatusCode != http.StatusAccepted || len(ingest.received) != 1 {
+		t.Errorf("POST = %d (received %d), want 202", resp.StatusCode, len(ingest.received))
+	}
+	if resp := post(`{`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid POST = %d, want 400", resp.StatusCode)
+	}
+	ingest.full = true
+	if resp := post(`{"cluster": "prod"}`); resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("POST with a full queue = %d (Retry-After %q), want 503 with Retry-After",
+			resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+}
+
+func TestHandler_Sources(t *testing.T) {
+	receiver := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	})
+	if err := NewServer(fakeManager{}, 8080, Options{}).SetSources(receiver); err == nil {
+		t.Error("SetSources() should require authentication")
+	}
+
+	s := NewServer(fakeManager{}, 8080, Options{AuthToken: "s3cret"})
+	if err := s.SetSources(receiver); err != nil {
+		t.Fatalf("SetSources() error = %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/sources/prod/alerts", strings.NewReader("{}"))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "/sources/prod/alerts" {
+		t.Errorf("POST /sources/prod/alerts = %d %q, want the receiver's response", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_TriagePause(t *testing.T) {
+	pauses, err := pause.Open(filepath.Join(t.TempDir(), "triage-pause.json"), []string{"prod", "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewServer(fakeManager{}, 8080, Options{}).SetTriagePause(pauses); err == nil {
+		t.Error("SetTriagePause() should require authentication")
+	}
+
+	s := NewServer(fakeManager{}, 8080, Options{AuthToken: "s3cret"})
+	if err := s.SetTriagePause(pauses); err != nil {
+		t.Fatalf("SetTriagePause() error = %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	request := func(method, path, body string) int {
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := request(http.MethodPost, "/admin/triage/pause", `{"cluster": "prod", "reason": "upgrade"}`); code != http.StatusOK {
+		t.Errorf("pause prod = %d, want 200", code)
+	}
+	if p := pauses.Paused("prod"); p == nil || p.Reason != "upgrade" || p.PausedBy != "api" {
+		t.Errorf("Paused(prod) = %+v, want the API's pause", p)
+	}
+	if code := request(http.MethodPost, "/admin/triage/pause", ``); code != http.StatusOK || pauses.State().Global == nil {
+		t.Errorf("global pause = %d, want 200 and a global pause", code)
+	}
+	if code := request(http.MethodPost, "/admin/triage/pause", `{"cluster": "dev"}`); code != http.StatusBadRequest {
+		t.Errorf("pause of an unknown cluster = %d, want 400", code)
+	}
+	if code := request(http.MethodGet, "/admin/triage", ""); code != http.StatusOK {
+		t.Errorf("GET = %d, want 200", code)
+	}
+	if code := request(http.MethodPost, "/admin/triage/resume", `{}`); code != http.StatusOK {
+		t.Errorf("global resume = %d, want 200", code)
+	}
+	if code := request(http.MethodPost, "/admin/triage/resume", `{"cluster": "staging"}`); code != http.StatusConflict {
+		t.Errorf("resume of a running cluster = %d, want 409", code)
+	}
+	if code := request(http.MethodGet, "/admin/triage/pause", ""); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET pause = %d, want 405", code)
+	}
+}
+
+type fakeArchives struct{}
+
+func (fakeArchives) IncidentArchive(ctx context.Context, id string) (string, io.WriterTo, error) {
+	if id != "INC-1" {
+		return "", nil, ErrIncidentNotFound
+	}
+	return id + ".tar.gz", strings.NewReader("archive"), nil
+}
+
+func TestHandler_IncidentArchive(t *testing.T) {
+	if err := NewServer(fakeManager{}, 8080, Options{}).SetIncidentArchives(fakeArchives{}); err == nil {
+		t.Error("SetIncidentArchives() should require authentication")
+	}
+
+	s := NewServer(fakeManager{}, 8080, Options{AuthToken: "s3cret"})
+	if err := s.SetIncidentArchives(fakeArchives{}); err != nil {
+		t.Fatalf("SetIncidentArchives() error = %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	get := func(path string) (*http.Response, string) {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	resp, body := get("/admin/incidents/INC-1/archive")
+	if resp.StatusCode != http.StatusOK || body != "archive" {
+		t.Errorf("GET = %d %q, want 200 with the archive", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("Content-Disposition"); got != `attachment; filename=INC-1.tar.gz` {
+		t.Errorf("Content-Disposition = %q, want an INC-1.tar.gz attachment", got)
+	}
+	if resp, _ := get("/admin/incidents/INC-2/archive"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET of an unknown incident = %d, want 404", resp.StatusCode)
+	}
+}
+
+type fakeHistory struct{}
+
+func (fakeHistory) GetIncidentHistory(ctx context.Context, id string, at *time.Time) (interface{}, error) {
+	if id != "INC-1" {
+		return nil, ErrIncidentNotFound
+	}
+	return map[string]interface{}{"incident_id": id, "at": at}, nil
+}
+
+func TestHandler_IncidentHistory(t *testing.T) {
+	if err := NewServer(fakeManager{}, 8080, Options{}).SetIncidentHistory(fakeHistory{}); err == nil {
+		t.Error("SetIncidentHistory() should require authentication")
+	}
+
+	s := NewServer(fakeManager{}, 8080, Options{AuthToken: "s3cret"})
+	if err := s.SetIncidentHistory(fakeHistory{}); err != nil {
+		t.Fatalf("SetIncidentHistory() error = %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	get := func(path string) (int, string) {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := get("/admin/incidents/INC-1/history?at=2024-05-01T14:30:00Z"); code != http.StatusOK || !strings.Contains(body, `"at": "2024-05-01T14:30:00Z"`) {
+		t.Errorf("GET = %d %s, want 200 with the state at the time", code, body)
+	}
+	if code, _ := get("/admin/incidents/INC-1/history?at=yesterday"); code != http.StatusBadRequest {
+		t.Errorf("GET with an invalid time = %d, want 400", code)
+	}
+	if code, _ := get("/admin/incidents/INC-2/history"); code != http.StatusNotFound {
+		t.Errorf("GET of an unknown incident = %d, want 404", code)
+	}
+}
+
+type fakeBundles struct{}
+
+func (fakeBundles) GetIncidentBundle(ctx context.Context, id string) (interface{}, error) {
+	if id != "INC-1" {
+		return nil, ErrIncidentNotFound
+	}
+	return map[string]interface{}{"incident": map[string]string{"incidentId": id}, "executions": []string{"exec-1"}}, nil
+}
+
+func TestHandler_IncidentBundle(t *testing.T) {
+	if err := NewServer(fakeManager{}, 8080, Options{}).SetIncidentBundles(fakeBundles{}); err == nil {
+		t.Error("SetIncidentBundles() should require authentication")
+	}
+
+	s := NewServer(fakeManager{}, 8080, Options{AuthToken: "s3cret"})
+	if err := s.SetIncidentBundles(fakeBundles{}); err != nil {
+		t.Fatalf("SetIncidentBundles() error = %v", err)
+	}
+	if err := s.SetIncidentHistory(fakeHistory{}); err != nil {
+		t.Fatalf("SetIncidentHistory() error = %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	get := func(path string) (int, string) {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := get("/admin/incidents/INC-1"); code != http.StatusOK || !strings.Contains(body, `"executions"`) {
+		t.Errorf("GET = %d %s, want 200 with the executions", code, body)
+	}
+	if code, _ := get("/admin/incidents/INC-2"); code != http.StatusNotFound {
+		t.Errorf("GET of an unknown incident = %d, want 404", code)
+	}
+	// The incident's sub-resources are routed to their own handlers
+	if code, body := get("/admin/incidents/INC-1/history"); code != http.StatusOK || !strings.Contains(body, `"incident_id"`) {
+		t.Errorf("GET history = %d %s, want 200 with the history", code, body)
+	}
+}
+
+type fakeChanges chan interface{}
+
+func (f fakeChanges) SubscribeChanges() (<-chan interface{}, func()) {
+	return f, func() {}
+}
+
+func TestHandler_IncidentChanges(t *testing.T) {
+	if err := NewServer(fakeManager{}, 8080, Options{}).SetIncidentChanges(make(fakeChanges)); err == nil {
+		t.Error("SetIncidentChanges() should require authentication")
+	}
+
+	changes := make(fakeChanges, 1)
+	s := NewServer(fakeManager{}, 8080, Options{AuthToken: "s3cret"})
+	if err := s.SetIncidentChanges(changes); err != nil {
+		t.Fatalf("SetIncidentChanges() error = %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/admin/incidents/changes", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || ct != "text/event-stream" {
+		t.Fatalf("GET = %d %s, want a 200 event stream", resp.StatusCode, ct)
+	}
+
+	changes <- map[string]string{"incident_id": "INC-1", "field": "status"}
+	close(changes)
+	body, _ := io.ReadAll(resp.Body)
+	if want := "event: incident_change\ndata: {\"field\":\"status\",\"incident_id\":\"INC-1\"}\n\n"; string(body) != want {
+		t.Errorf("stream = %q, want %q", body, want)
+	}
+}
+
+type fakeReviews struct {
+	added []ReviewRequest
+}
+
+func (f *fakeReviews) GetReviews(ctx context.Context, id string) (interface{}, error) {
+	if id != "INC-1" {
+		return nil, ErrIncidentNotFound
+	}
+	return f.added, nil
+}
+
+func (f *fakeReviews) AddReview(ctx context.Context, id string, review ReviewRequest) (interface{}, error) {
+	if id != "INC-1" {
+		return nil, ErrIncidentNotFound
+	}
+	if review.Verdict == "bogus" {
+		return nil, fmt.Errorf("%w: unknown verdict", ErrInvalidReview)
+	}
+	f.added = append(f.added, review)
+	return review, nil
+}
+
+func (f *fakeReviews) GetReviewAccuracy(ctx context.Context, since time.Time) (interface{}, error) {
+	return map[string]interface{}{"since": since.UTC().Format(time.RFC3339)}, nil
+}
+
+func TestHandler_InvestigationReviews(t *testing.T) {
+	if err := NewServer(fakeManager{}, 8080, Options{}).SetInvestigationReviews(&fakeReviews{}); err == nil {
+		t.Error("SetInvestigationReviews() should require authentication")
+	}
+
+	reviews := &fakeReviews{}
+	s := NewServer(fakeManager{}, 8080, Options{AuthToken: "s3cret"})
+	if err := s.SetInvestigationReviews(reviews); err != nil {
+		t.Fatalf("SetInvestigationReviews() error = %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	do := func(method, path, body string) (int, string) {
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(respBody)
+	}
+
+	if code, body := do(http.MethodPost, "/admin/incidents/INC-1/reviews", `{"reviewer":"alice","verdict":"accurate","comment":"spot on"}`); code != http.StatusOK {
+		t.Errorf("POST = %d %s, want 200", code, body)
+	}
+	if len(reviews.added) != 1 || reviews.added[0].Reviewer != "alice" || reviews.added[0].Comment != "spot on" {
+		t.Errorf("added = %+v, want alice's review", reviews.added)
+	}
+	if code, _ := do(http.MethodPost, "/admin/incidents/INC-1/reviews", `{"reviewer":"alice","verdict":"bogus"}`); code != http.StatusBadRequest {
+		t.Errorf("POST of an invalid review = %d, want 400", code)
+	}
+	if code, _ := do(http.MethodPost, "/admin/incidents/INC-1/reviews", `{"rating":5}`); code != http.StatusBadRequest {
+		t.Errorf("POST with an unknown field = %d, want 400", code)
+	}
+	if code, body := do(http.MethodGet, "/admin/incidents/INC-1/reviews", ""); code != http.StatusOK || !strings.Contains(body, "spot on") {
+		t.Errorf("GET = %d %s, want 200 with the review", code, body)
+	}
+	if code, _ := do(http.MethodGet, "/admin/incidents/INC-2/reviews", ""); code != http.StatusNotFound {
+		t.Errorf("GET of an unknown incident = %d, want 404", code)
+	}
+	if code, body := do(http.MethodGet, "/admin/reviews/accuracy?since=24h", ""); code != http.StatusOK || !strings.Contains(body, "since") {
+		t.Errorf("GET accuracy = %d %s, want 200", code, body)
+	}
+	if code, _ := do(http.MethodGet, "/admin/reviews/accuracy?since=-1h", ""); code != http.StatusBadRequest {
+		t.Errorf("GET accuracy with a negative window = %d, want 400", code)
+	}
+}
@@ -0,0 +1,92 @@
+package incident
+
+import (
+	"sync"
+	"time"
+)
+
+// CachedInvestigation is the outcome of a completed investigation, kept so that an
+// identical fault (same signature) can be answered without re-running the agent.
+type CachedInvestigation struct {
+	Signature   string
+	IncidentID  string
+	CompletedAt time.Time
+	RootCause   string
+	Confidence  string
+	ReportPath  string
+	ReportURL   string
+}
+
+// InvestigationCache stores completed investigations keyed by fault signature.
+// Entries expire after the configured TTL. The cache lives in memory only; after a
+// restart the first occurrence of each fault is investigated again.
+// It is safe for concurrent use.
+type InvestigationCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]CachedInvestigation
+
+	// now is replaceable for tests
+	now func() time.Time
+}
+
+// NewInvestigationCache creates a cache whose entries expire after ttl.
+// A zero or negative ttl disables caching.
+func NewInvestigationCache(ttl time.Duration) *InvestigationCache {
+	return &InvestigationCache{
+		ttl:     ttl,
+		entries: make(map[string]CachedInvestigation),
+		now:     time.Now,
+	}
+}
+
+// Enabled reports whether caching is active.
+func (c *InvestigationCache) Enabled() bool {
+	return c != nil && c.ttl > 0
+}
+
+// Get returns the cached investigation for a signature if it completed within the TTL.
+func (c *InvestigationCache) Get(signature string) (CachedInvestigation, bool) {
+	if !c.Enabled() {
+		return CachedInvestigation{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[signature]
+	if !ok {
+		return CachedInvestigation{}, false
+	}
+	if c.now().Sub(entry.CompletedAt) > c.ttl {
+		delete(c.entries, signature)
+		return CachedInvestigation{}, false
+	}
+	return entry, true
+}
+
+// Put stores a completed investigation and prunes expired entries.
+func (c *InvestigationCache) Put(entry CachedInvestigation) {
+	if !c.Enabled() || entry.Signature == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for sig, e := range c.entries {
+		if now.Sub(e.CompletedAt) > c.ttl {
+			delete(c.entries, sig)
+		}
+	}
+	c.entries[entry.Signature] = entry
+}
+
+// Len returns the number of cached investigations (including not yet pruned expired ones).
+func (c *InvestigationCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -0,0 +1,56 @@
+package incident
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInvestigationCache_HitWithinTTL(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := NewInvestigationCache(time.Hour)
+	c.now = func() time.Time { return now }
+
+	c.Put(CachedInvestigation{Signature: "sig", IncidentID: "inc-1", CompletedAt: now.Add(-30 * time.Minute), RootCause: "OOM"})
+
+	got, ok := c.Get("sig")
+	if !ok {
+		t.Fatal("Get() should hit within TTL")
+	}
+	if got.IncidentID != "inc-1" || got.RootCause != "OOM" {
+		t.Errorf("Get() = %+v", got)
+	}
+
+	if _, ok := c.Get("other"); ok {
+		t.Error("Get() should miss for unknown signature")
+	}
+}
+
+func TestInvestigationCache_Expiry(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := NewInvestigationCache(time.Hour)
+	c.now = func() time.Time { return now }
+
+	c.Put(CachedInvestigation{Signature: "old", CompletedAt: now.Add(-2 * time.Hour)})
+	if _, ok := c.Get("old"); ok {
+		t.Error("Get() should miss for expired entry")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, want expired entry removed", c.Len())
+	}
+}
+
+func TestInvestigationCache_Disabled(t *testing.T) {
+	c := NewInvestigationCache(0)
+	if c.Enabled() {
+		t.Error("cache with zero TTL should be disabled")
+	}
+	c.Put(CachedInvestigation{Signature: "sig", CompletedAt: time.Now()})
+	if _, ok := c.Get("sig"); ok {
+		t.Error("disabled cache should never hit")
+	}
+
+	var nilCache *InvestigationCache
+	if nilCache.Enabled() {
+		t.Error("nil cache should be disabled")
+	}
+}
@@ -0,0 +1,83 @@
+package incident
+
+import (
+	"context"
+	"log/slog"
+)
+
+// IncidentContext carries the identifying metadata of the incident being processed.
+// It travels through context.Context so that every module touched while handling an
+// incident (executor, storage, notifiers) can tag logs, metrics, and traces with the
+// same identifiers without threading them through every function signature.
+type IncidentContext struct {
+	IncidentID string
+	DisplayID  string
+	Cluster    string
+	FaultID    string
+	Namespace  string
+	Severity   string
+}
+
+// incidentContextKey is the unexported context key for IncidentContext values.
+type incidentContextKey struct{}
+
+// NewIncidentContext builds an IncidentContext from an incident record.
+func NewIncidentContext(inc *Incident) *IncidentContext {
+	return &IncidentContext{
+		IncidentID: inc.IncidentID,
+		DisplayID:  inc.DisplayID,
+		Cluster:    inc.Cluster,
+		FaultID:    inc.FaultID,
+		Namespace:  inc.Namespace,
+		Severity:   inc.Severity,
+	}
+}
+
+// WithContext returns a copy of ctx carrying the given IncidentContext.
+func WithContext(ctx context.Context, ic *IncidentContext) context.Context {
+	return context.WithValue(ctx, incidentContextKey{}, ic)
+}
+
+// FromContext returns the IncidentContext stored in ctx, if any.
+func FromContext(ctx context.Context) (*IncidentContext, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	ic, ok := ctx.Value(incidentContextKey{}).(*IncidentContext)
+	return ic, ok && ic != nil
+}
+
+// LogAttrs returns the incident metadata as slog key/value pairs.
+// Empty fields are omitted to keep log lines compact.
+func (ic *IncidentContext) LogAttrs() []any {
+	attrs := make([]any, 0, 12)
+	if ic.IncidentID != "" {
+		attrs = append(attrs, "incident_id", ic.IncidentID)
+	}
+	if ic.DisplayID != "" {
+		attrs = append(attrs, "display_id", ic.DisplayID)
+	}
+	if ic.Cluster != "" {
+		attrs = append(attrs, "cluster", ic.Cluster)
+	}
+	if ic.FaultID != "" {
+		attrs = append(attrs, "fault_id", ic.FaultID)
+	}
+	if ic.Namespace != "" {
+		attrs = append(attrs, "namespace", ic.Namespace)
+	}
+	if ic.Severity != "" {
+		attrs = append(attrs, "severity", ic.Severity)
+	}
+	return attrs
+}
+
+// Logger returns the default slog logger annotated with the incident metadata
+// found in ctx. If ctx carries no IncidentContext, the default logger is returned.
+func Logger(ctx context.Context) *slog.Logger {
+	ic, ok := FromContext(ctx)
+	if !ok {
+		return slog.Default()
+	}
+	return slog.Default().With(ic.LogAttrs()...)
+}
@@ -0,0 +1,50 @@
+package incident
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIncidentContext_RoundTrip(t *testing.T) {
+	inc := &Incident{
+		IncidentID: "inc-123",
+		FaultID:    "fault-456",
+		Cluster:    "prod-us-east-1",
+		Namespace:  "payments",
+		Severity:   "ERROR",
+	}
+
+	ctx := WithContext(context.Background(), NewIncidentContext(inc))
+
+	ic, ok := FromContext(ctx)
+	if !ok {
+		t.Fatal("FromContext() should find the incident context")
+	}
+	if ic.IncidentID != "inc-123" || ic.FaultID != "fault-456" || ic.Cluster != "prod-us-east-1" {
+		t.Errorf("FromContext() = %+v, want fields copied from incident", ic)
+	}
+}
+
+func TestIncidentContext_Missing(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("FromContext() should report false for a context without incident metadata")
+	}
+	if Logger(context.Background()) == nil {
+		t.Error("Logger() should fall back to the default logger")
+	}
+}
+
+func TestIncidentContext_LogAttrsOmitsEmptyFields(t *testing.T) {
+	ic := &IncidentContext{IncidentID: "inc-123", Cluster: "dev"}
+
+	attrs := ic.LogAttrs()
+	want := []any{"incident_id", "inc-123", "cluster", "dev"}
+	if len(attrs) != len(want) {
+		t.Fatalf("LogAttrs() = %v, want %v", attrs, want)
+	}
+	for i := range want {
+		if attrs[i] != want[i] {
+			t.Errorf("LogAttrs()[%d] = %v, want %v", i, attrs[i], want[i])
+		}
+	}
+}
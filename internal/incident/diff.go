@@ -0,0 +1,153 @@
+package incident
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Root cause comparison outcomes of an InvestigationDiff
+const (
+	RootCauseUnchanged = "unchanged"
+	// RootCauseReworded means the root causes differ in wording but share most of
+	// their terms
+	RootCauseReworded = "reworded"
+	RootCauseChanged  = "changed"
+	// RootCauseNew means the previous investigation has no root cause to compare
+	RootCauseNew = "new"
+)
+
+// rewordedSimilarity is the share of terms two root causes must have in common to
+// count as the same finding reworded
+const rewordedSimilarity = 0.6
+
+// confidenceRank orders the confidence levels of investigation reports
+var confidenceRank = map[string]int{"LOW": 1, "MEDIUM": 2, "HIGH": 3}
+
+// InvestigationDiff compares an investigation's findings with the previous
+// investigation of the same fault, so reviewers see what changed on a re-run.
+type InvestigationDiff struct {
+	PreviousIncident   string `json:"previous_incident"` // Display ID (or UUID)
+	PreviousRootCause  string `json:"previous_root_cause,omitempty"`
+	PreviousConfidence string `json:"previous_confidence,omitempty"`
+	RootCause          string `json:"root_cause"`
+	Confidence         string `json:"confidence"`
+	// RootCauseStatus is one of the RootCause* outcomes
+	RootCauseStatus string `json:"root_cause_status"`
+	// ConfidenceDelta is the change in confidence level, e.g. +2 from LOW to
+	// HIGH; 0 when either level is unknown
+	ConfidenceDelta int `json:"confidence_delta"`
+}
+
+// DiffInvestigations compares the root cause and confidence of a new
+// investigation with those of the prior one.
+func DiffInvestigations(prior PriorInvestigation, rootCause, confidence string) *InvestigationDiff {
+	ref := prior.DisplayID
+	if ref == "" {
+		ref = prior.IncidentID
+	}
+	diff := &InvestigationDiff{
+		PreviousIncident:   ref,
+		PreviousRootCause:  prior.RootCause,
+		PreviousConfidence: prior.Confidence,
+		RootCause:          rootCause,
+		Confidence:         confidence,
+	}
+
+	switch previous, current := terms(prior.RootCause), terms(rootCause); {
+	case len(previous) == 0:
+		diff.RootCauseStatus = RootCauseNew
+	case strings.Join(previous, " ") == strings.Join(current, " "):
+		diff.RootCauseStatus = RootCauseUnchanged
+	case similarity(previous, current) >= rewordedSimilarity:
+		diff.RootCauseStatus = RootCauseReworded
+	default:
+		diff.RootCauseStatus = RootCauseChanged
+	}
+
+	before, after := confidenceRank[strings.ToUpper(prior.Confidence)], confidenceRank[strings.ToUpper(confidence)]
+	if before > 0 && after > 0 {
+		diff.ConfidenceDelta = after - before
+	}
+	return diff
+}
+
+// Summary returns a one-line description of what changed, for notifications,
+// e.g. "Root cause changed since incident INC-12; confidence LOW → HIGH (+2)".
+func (d *InvestigationDiff) Summary() string {
+	var text string
+	switch d.RootCauseStatus {
+	case RootCauseUnchanged:
+		text = "Same root cause as incident " + d.PreviousIncident
+	case RootCauseReworded:
+		text = "Root cause reworded since incident " + d.PreviousIncident
+	case RootCauseChanged:
+		text = "Root cause changed since incident " + d.PreviousIncident
+	default:
+		text = "Incident " + d.PreviousIncident + " reported no root cause"
+	}
+	if d.PreviousConfidence == "" || d.Confidence == "" {
+		return text
+	}
+	if d.ConfidenceDelta == 0 {
+		return fmt.Sprintf("%s; confidence %s (unchanged)", text, d.Confidence)
+	}
+	return fmt.Sprintf("%s; confidence %s → %s (%+d)", text, d.PreviousConfidence, d.Confidence, d.ConfidenceDelta)
+}
+
+// Markdown renders the diff artifact written next to the investigation report.
+func (d *InvestigationDiff) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Investigation Diff\n\n")
+	fmt.Fprintf(&b, "Compared with the investigation of incident %s.\n\n", d.PreviousIncident)
+	fmt.Fprintf(&b, "- **Root cause:** %s\n", d.RootCauseStatus)
+	fmt.Fprintf(&b, "- **Confidence:** %s → %s (%+d)\n\n", orUnknown(d.PreviousConfidence), orUnknown(d.Confidence), d.ConfidenceDelta)
+	fmt.Fprintf(&b, "%s.\n\n", d.Summary())
+	writeQuoted(&b, "Previous root cause", d.PreviousRootCause)
+	writeQuoted(&b, "Current root cause", d.RootCause)
+	return b.String()
+}
+
+// writeQuoted writes a titled block quote, or nothing when text is empty.
+func writeQuoted(b *strings.Builder, title, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(b, "## %s\n\n", title)
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(b, "> %s\n", line)
+	}
+	b.WriteString("\n")
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "UNKNOWN"
+	}
+	return s
+}
+
+// terms returns the lowercased words of a text, ignoring punctuation.
+func terms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// similarity returns the Jaccard similarity of two sets of terms.
+func similarity(a, b []string) float64 {
+	set := make(map[string]int)
+	for _, t := range a {
+		set[t] |= 1
+	}
+	for _, t := range b {
+		set[t] |= 2
+	}
+	shared := 0
+	for _, in := range set {
+		if in == 3 {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(set))
+}
@@ -0,0 +1,52 @@
+package incident
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffInvestigations(t *testing.T) {
+	prior := PriorInvestigation{
+		IncidentID: "0b6e1c1e-5d1a-4c35-9a8e-6f0d7f3c2a10",
+		DisplayID:  "INC-12",
+		RootCause:  "The pod's memory limit is below its working set.",
+		Confidence: "LOW",
+	}
+	tests := []struct {
+		name       string
+		rootCause  string
+		confidence string
+		status     string
+		delta      int
+		summary    string
+	}{
+		{"unchanged", "The pod's memory limit is below its working set", "LOW", RootCauseUnchanged, 0,
+			"Same root cause as incident INC-12; confidence LOW (unchanged)"},
+		{"reworded", "The pod's memory limit is below its current working set.", "HIGH", RootCauseReworded, 2,
+			"Root cause reworded since incident INC-12; confidence LOW → HIGH (+2)"},
+		{"changed", "A node disk pressure eviction killed the pod.", "MEDIUM", RootCauseChanged, 1,
+			"Root cause changed since incident INC-12; confidence LOW → MEDIUM (+1)"},
+		{"unknown confidence", "A node disk pressure eviction killed the pod.", "", RootCauseChanged, 0,
+			"Root cause changed since incident INC-12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffInvestigations(prior, tt.rootCause, tt.confidence)
+			if diff.RootCauseStatus != tt.status || diff.ConfidenceDelta != tt.delta {
+				t.Errorf("DiffInvestigations() = %s %+d, want %s %+d", diff.RootCauseStatus, diff.ConfidenceDelta, tt.status, tt.delta)
+			}
+			if got := diff.Summary(); got != tt.summary {
+				t.Errorf("Summary() = %q, want %q", got, tt.summary)
+			}
+		})
+	}
+
+	// A prior incident without a report has nothing to compare
+	diff := DiffInvestigations(PriorInvestigation{IncidentID: "inc-1"}, "A node disk pressure eviction killed the pod.", "HIGH")
+	if diff.RootCauseStatus != RootCauseNew || diff.Summary() != "Incident inc-1 reported no root cause" {
+		t.Errorf("DiffInvestigations() without a prior report = %+v", diff)
+	}
+	if md := diff.Markdown(); !strings.Contains(md, "## Current root cause") || strings.Contains(md, "## Previous root cause") {
+		t.Errorf("Markdown() without a prior report:\n%s", md)
+	}
+}
@@ -0,0 +1,149 @@
+package incident
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// Fact is a single labelled fact about an incident.
+type Fact struct {
+	Label string
+	Value string
+}
+
+// Facts is the structured "Incident Facts" section given to the agent. Converting
+// event fields into a fixed set of labelled facts (instead of dumping raw JSON)
+// keeps the agent's interpretation consistent across event schema variants.
+type Facts struct {
+	Items   []Fact
+	Counts  []Fact
+	Details string // remaining lines of a multi-line fault description
+}
+
+var (
+	// countPattern matches "name: 12" / "name=12" pairs in fault descriptions.
+	countPattern = regexp.MustCompile(`(?i)\b([a-z][a-z _-]{1,30}?)\s*[:=]\s*(\d+)\b`)
+
+	// occurrencePattern matches the Kubernetes event style "(x5 over 10m)".
+	occurrencePattern = regexp.MustCompile(`\(x(\d+) over ([0-9a-z]+)\)`)
+
+	// timestampLayouts are the timestamp formats seen across event schema variants.
+	timestampLayouts = []string{time.RFC3339Nano, time.RFC3339, "2006-01-02 15:04:05Z07:00", "2006-01-02T15:04:05"}
+)
+
+// BuildFacts extracts the incident facts from an incident and its triggering event.
+// now is used to express the fault age.
+func BuildFacts(inc *Incident, event *events.FaultEvent, now time.Time) Facts {
+	var f Facts
+	add := func(label, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			f.Items = append(f.Items, Fact{Label: label, Value: value})
+		}
+	}
+
+	add("Incident ID", inc.IncidentID)
+	add("Cluster", inc.Cluster)
+	add("Namespace", inc.Namespace)
+	if inc.Resource != nil {
+		add("Resource", fmt.Sprintf("%s/%s", inc.Resource.Kind, inc.Resource.Name))
+		add("API Version", inc.Resource.APIVersion)
+		add("Resource UID", inc.Resource.UID)
+	}
+	add("Node", event.GetNode())
+	add("Reason", inc.FaultType)
+	add("Severity", strings.ToUpper(inc.Severity))
+	message, details := splitMessage(inc.Context)
+	add("Message", message)
+	f.Details = details
+
+	if occurred, ok := parseTimestamp(inc.Timestamp); ok {
+		add("Occurred At", fmt.Sprintf("%s (%s ago)", occurred.UTC().Format(time.RFC3339), now.Sub(occurred).Round(time.Second)))
+	} else {
+		add("Occurred At", inc.Timestamp)
+	}
+	if !event.ReceivedAt.IsZero() {
+		add("Received At", event.ReceivedAt.UTC().Format(time.RFC3339))
+	}
+	add("Fault ID", inc.FaultID)
+
+	f.Counts = extractCounts(inc.Context)
+	return f
+}
+
+// Markdown renders the facts as a prompt section.
+func (f Facts) Markdown() string {
+	var b strings.Builder
+	b.WriteString("## Incident Facts\n\n")
+	b.WriteString("The following facts were extracted from the triggering fault event. Treat them as authoritative.\n\n")
+	for _, item := range f.Items {
+		fmt.Fprintf(&b, "- **%s:** %s\n", item.Label, item.Value)
+	}
+	if len(f.Counts) > 0 {
+		b.WriteString("\n### Counts\n\n")
+		for _, c := range f.Counts {
+			fmt.Fprintf(&b, "- **%s:** %s\n", c.Label, c.Value)
+		}
+	}
+	if f.Details != "" {
+		b.WriteString("\n### Details\n\n")
+		b.WriteString(f.Details)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// extractCounts finds numeric counters (restarts, occurrences, ...) in a fault description.
+func extractCounts(context string) []Fact {
+	var counts []Fact
+	seen := make(map[string]bool)
+	add := func(label, value string) {
+		key := strings.ToLower(label)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		counts = append(counts, Fact{Label: label, Value: value})
+	}
+
+	for _, m := range occurrencePattern.FindAllStringSubmatch(context, -1) {
+		add("Occurrences", fmt.Sprintf("%s over %s", m[1], m[2]))
+	}
+	for _, m := range countPattern.FindAllStringSubmatch(context, -1) {
+		label := strings.TrimSpace(m[1])
+		if len(label) > 1 {
+			label = strings.ToUpper(label[:1]) + label[1:]
+		}
+		add(label, m[2])
+	}
+	return counts
+}
+
+// parseTimestamp parses the fault timestamp in any known format, including unix seconds.
+func parseTimestamp(raw string) (time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, true
+		}
+	}
+	if secs, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(secs, 0), true
+	}
+	return time.Time{}, false
+}
+
+// splitMessage splits a fault description into its first line and the remaining
+// lines, so multi-line contexts (e.g. aggregated events) do not swamp the facts list.
+func splitMessage(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	first, rest, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(first), strings.TrimSpace(rest)
+}
@@ -0,0 +1,107 @@
+package incident
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+func factValue(facts []Fact, label string) (string, bool) {
+	for _, f := range facts {
+		if f.Label == label {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+func TestBuildFacts(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	event := &events.FaultEvent{
+		FaultID:    "fault-1",
+		ReceivedAt: now.Add(-time.Minute),
+		Cluster:    "prod",
+		Resource: &events.ResourceInfo{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       "api-0",
+			Namespace:  "shop",
+			Node:       "node-a",
+		},
+		FaultType: "CrashLoopBackOff",
+		Severity:  "critical",
+		Context:   "Back-off restarting failed container (x5 over 10m)\nrestartCount: 7",
+		Timestamp: "2025-03-01T11:55:00Z",
+	}
+	inc := NewFromEvent("inc-1", event)
+
+	facts := BuildFacts(inc, event, now)
+
+	want := map[string]string{
+		"Incident ID": "inc-1",
+		"Namespace":   "shop",
+		"Resource":    "Pod/api-0",
+		"Node":        "node-a",
+		"Reason":      "CrashLoopBackOff",
+		"Severity":    "CRITICAL",
+		"Message":     "Back-off restarting failed container (x5 over 10m)",
+		"Occurred At": "2025-03-01T11:55:00Z (5m0s ago)",
+		"Fault ID":    "fault-1",
+	}
+	for label, value := range want {
+		if got, ok := factValue(facts.Items, label); !ok || got != value {
+			t.Errorf("fact %q = %q, want %q", label, got, value)
+		}
+	}
+	if _, ok := factValue(facts.Items, "Resource UID"); ok {
+		t.Error("empty fields should be omitted")
+	}
+
+	if got, _ := factValue(facts.Counts, "Occurrences"); got != "5 over 10m" {
+		t.Errorf("Occurrences = %q, want %q", got, "5 over 10m")
+	}
+	if got, _ := factValue(facts.Counts, "RestartCount"); got != "7" {
+		t.Errorf("RestartCount = %q, want 7", got)
+	}
+	if facts.Details != "restartCount: 7" {
+		t.Errorf("Details = %q", facts.Details)
+	}
+}
+
+func TestBuildFacts_TimestampVariants(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"2025-03-01T11:59:00.123456Z", "2025-03-01T11:59:00Z (1m0s ago)"},
+		{"2025-03-01 11:59:00+00:00", "2025-03-01T11:59:00Z (1m0s ago)"},
+		{"1740830340", "2025-03-01T11:59:00Z (1m0s ago)"},
+		{"yesterday", "yesterday"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			event := &events.FaultEvent{Timestamp: tt.raw}
+			facts := BuildFacts(NewFromEvent("inc-1", event), event, now)
+			if got, _ := factValue(facts.Items, "Occurred At"); got != tt.want {
+				t.Errorf("Occurred At = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFacts_Markdown(t *testing.T) {
+	facts := Facts{
+		Items:   []Fact{{Label: "Cluster", Value: "prod"}},
+		Counts:  []Fact{{Label: "Restarts", Value: "3"}},
+		Details: "Affected pods:\n- shop/api-0",
+	}
+	md := facts.Markdown()
+	for _, want := range []string{"## Incident Facts", "- **Cluster:** prod", "### Counts", "- **Restarts:** 3", "### Details", "- shop/api-0"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, md)
+		}
+	}
+}
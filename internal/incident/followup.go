@@ -0,0 +1,51 @@
+package incident
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PriorInvestigation is the earlier resolved incident of a fault that recurred. The
+// follow-up incident links to it (ParentIncidentID) and its investigation starts
+// from the previous root cause instead of from scratch.
+type PriorInvestigation struct {
+	IncidentID  string
+	DisplayID   string
+	CompletedAt time.Time
+	// RootCause and Confidence are taken from the prior incident's report; both
+	// are empty when it has none
+	RootCause  string
+	Confidence string
+	// Chain lists the earlier incidents of the fault by display ID (or UUID), most
+	// recent first
+	Chain []string
+}
+
+// PromptSection returns the agent prompt section describing the prior
+// investigation, so the agent can check whether the previous root cause still
+// applies and why its fix did not hold.
+func (p PriorInvestigation) PromptSection() string {
+	var b strings.Builder
+	b.WriteString("## Previous Investigation\n\n")
+	ref := p.DisplayID
+	if ref == "" {
+		ref = p.IncidentID
+	}
+	fmt.Fprintf(&b, "This fault recurred after incident %s was resolved (completed %s).",
+		ref, p.CompletedAt.UTC().Format(time.RFC3339))
+	if len(p.Chain) > 1 {
+		fmt.Fprintf(&b, " It has now occurred %d times; earlier incidents: %s.", len(p.Chain)+1, strings.Join(p.Chain, ", "))
+	}
+	b.WriteString("\n\n")
+	if p.RootCause != "" {
+		fmt.Fprintf(&b, "Previous root cause (%s confidence):\n\n", p.Confidence)
+		for _, line := range strings.Split(p.RootCause, "\n") {
+			fmt.Fprintf(&b, "> %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("Check whether the previous root cause still applies. If it does, explain why the ")
+	b.WriteString("earlier remediation did not hold; if it does not, say what changed.\n")
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package incident
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPriorInvestigationPromptSection(t *testing.T) {
+	completed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	prior := PriorInvestigation{
+		IncidentID:  "inc-2",
+		CompletedAt: completed,
+		RootCause:   "The pod's memory limit is below its working set.",
+		Confidence:  "HIGH",
+		Chain:       []string{"inc-2", "inc-1"},
+	}
+	section := prior.PromptSection()
+	for _, want := range []string{
+		"## Previous Investigation",
+		"incident inc-2 was resolved (completed 2024-05-01T12:00:00Z)",
+		"occurred 3 times; earlier incidents: inc-2, inc-1",
+		"Previous root cause (HIGH confidence)",
+		"> The pod's memory limit is below its working set.",
+	} {
+		if !strings.Contains(section, want) {
+			t.Errorf("PromptSection() missing %q:\n%s", want, section)
+		}
+	}
+
+	// A prior incident without a report only references the incident
+	section = PriorInvestigation{IncidentID: "inc-1", CompletedAt: completed, Chain: []string{"inc-1"}}.PromptSection()
+	if strings.Contains(section, "Previous root cause") || strings.Contains(section, "occurred") {
+		t.Errorf("PromptSection() without a report or chain:\n%s", section)
+	}
+}
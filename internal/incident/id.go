@@ -0,0 +1,46 @@
+package incident
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultDisplayIDPrefix starts readable incident IDs.
+const DefaultDisplayIDPrefix = "NC"
+
+// FormatDisplayID builds a readable incident ID from the day the incident was
+// created, its cluster, and its sequence number among the cluster's incidents
+// that day, e.g. NC-2024-0613-prod-0042. The cluster name is lowercased and
+// characters other than letters, digits, and '-' are replaced with '-'.
+func FormatDisplayID(prefix string, day time.Time, cluster string, seq int) string {
+	return fmt.Sprintf("%s-%s-%s-%04d", prefix, day.Format("2006-0102"), displayIDCluster(cluster), seq)
+}
+
+// displayIDCluster normalizes a cluster name for use in a display ID.
+func displayIDCluster(cluster string) string {
+	normalized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return '-'
+	}, cluster)
+	normalized = strings.Trim(normalized, "-")
+	if normalized == "" {
+		return "cluster"
+	}
+	return normalized
+}
+
+// Ref returns the ID the incident is known by outside the state store: its display
+// ID, or its UUID when it has none (e.g. without a SQL state store). Workspace
+// directories, artifact storage paths, report URLs, and notifications use it.
+func (i *Incident) Ref() string {
+	if i.DisplayID != "" {
+		return i.DisplayID
+	}
+	return i.IncidentID
+}
@@ -0,0 +1,36 @@
+package incident
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDisplayID(t *testing.T) {
+	day := time.Date(2024, 6, 13, 23, 59, 0, 0, time.UTC)
+	tests := []struct {
+		cluster string
+		seq     int
+		want    string
+	}{
+		{"prod", 42, "NC-2024-0613-prod-0042"},
+		{"EU West/1", 7, "NC-2024-0613-eu-west-1-0007"},
+		{"__", 1, "NC-2024-0613-cluster-0001"},
+		{"prod", 12345, "NC-2024-0613-prod-12345"},
+	}
+	for _, tt := range tests {
+		if got := FormatDisplayID(DefaultDisplayIDPrefix, day, tt.cluster, tt.seq); got != tt.want {
+			t.Errorf("FormatDisplayID(%q, %d) = %q, want %q", tt.cluster, tt.seq, got, tt.want)
+		}
+	}
+}
+
+func TestRef(t *testing.T) {
+	inc := &Incident{IncidentID: "2f1c8e4a-uuid"}
+	if got := inc.Ref(); got != "2f1c8e4a-uuid" {
+		t.Errorf("Ref() without display ID = %q, want the UUID", got)
+	}
+	inc.DisplayID = "NC-2024-0613-prod-0042"
+	if got := inc.Ref(); got != "NC-2024-0613-prod-0042" {
+		t.Errorf("Ref() = %q, want the display ID", got)
+	}
+}
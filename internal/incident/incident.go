@@ -3,19 +3,31 @@ package incident
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"time"
 
+	"github.com/rbias/nightcrier/internal/egress"
 	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/faulthandlers"
+	"github.com/rbias/nightcrier/internal/labels"
+	"github.com/rbias/nightcrier/internal/ownership"
+	"github.com/rbias/nightcrier/internal/verify"
 )
 
-// Status constants for incident lifecycle
+// Status constants for incident lifecycle (see lifecycle.go for the transitions)
 const (
-	StatusPending       = "pending"
-	StatusInvestigating = "investigating"
-	StatusResolved      = "resolved"
-	StatusFailed        = "failed"
-	StatusAgentFailed   = "agent_failed"
+	StatusReceived            = "received"             // Created from a fault event
+	StatusPending             = "pending"              // Recorded without an investigation (serve-only, paused triage)
+	StatusQueued              = "queued"               // Waiting for an agent slot
+	StatusInvestigating       = "investigating"        // The agent is running
+	StatusAwaitingReview      = "awaiting_review"      // Findings held for human review (see the review policy)
+	StatusRemediationProposed = "remediation_proposed" // A remediation was proposed and awaits approval
+	StatusResolved            = "resolved"
+	StatusFailed              = "failed"
+	StatusAgentFailed         = "agent_failed"
+	StatusSuppressed          = "suppressed" // Left out of investigation (sampling, namespace silences)
+	StatusExpired             = "expired"    // Left open longer than the expiry period
 )
 
 // Incident represents our investigation of a fault
@@ -25,19 +37,28 @@ type Incident struct {
 	FaultID    string `json:"faultId"` // Stable identifier from kubernetes-mcp-server
 
 	// Lifecycle
-	Status      string     `json:"status"`      // pending, investigating, resolved, failed, agent_failed
+	Status      string     `json:"status"` // One of Statuses
 	CreatedAt   time.Time  `json:"createdAt"`
 	StartedAt   *time.Time `json:"startedAt,omitempty"`
 	CompletedAt *time.Time `json:"completedAt,omitempty"`
 
+	// StatusTimes maps each status the incident entered to when it (last) entered it
+	StatusTimes map[string]time.Time `json:"statusTimes,omitempty"`
+
 	// Result (populated after agent runs)
 	ExitCode      *int   `json:"exitCode,omitempty"`
 	FailureReason string `json:"failureReason,omitempty"`
+	FailureClass  string `json:"failureClass,omitempty"` // Canonical class of FailureReason (see the failures package)
 
 	// Logs (populated after agent runs)
 	LogPaths map[string]string `json:"logPaths,omitempty"` // Local log file paths
 	LogURLs  map[string]string `json:"logUrls,omitempty"`  // Presigned URLs from storage
 
+	// ArtifactHashes maps the stored artifacts (paths relative to the incident's
+	// storage directory) to the SHA-256 of their stored contents. incident.json
+	// cannot record its own hash; it is kept in the state store with the others.
+	ArtifactHashes map[string]string `json:"artifactHashes,omitempty"`
+
 	// Context (flattened from triggering event)
 	Cluster   string        `json:"cluster"`
 	Namespace string        `json:"namespace"`
@@ -49,6 +70,82 @@ type Incident struct {
 
 	// Traceability (internal, not for agent)
 	TriggeringEventID string `json:"triggeringEventId,omitempty"`
+	FaultSignature    string `json:"faultSignature,omitempty"` // Identical-fault hash (see events.FaultSignature)
+	CachedFrom        string `json:"cachedFrom,omitempty"`     // Incident whose cached report was served instead of re-running the agent
+	APIKeyID          string `json:"apiKeyId,omitempty"`       // Fingerprint of the LLM API key that served the investigation (see keypool)
+	AgentProfile      string `json:"agentProfile,omitempty"`   // Agent profile (model, turns, timeout) selected for the incident's priority
+	ParentIncidentID  string `json:"parentIncidentId,omitempty"` // Earlier resolved incident of the same recurring fault (see follow_up)
+	DisplayID         string `json:"displayId,omitempty"`        // Readable ID (e.g. NC-2024-0613-prod-0042); IncidentID stays the internal UUID
+	FallbackTriage    string `json:"fallbackTriage,omitempty"`   // Why rule-based triage replaced the agent (no_api_key, budget_exhausted, circuit_open; see ruletriage)
+	Source            string `json:"source,omitempty"`           // Event source whose fault event opened the incident (e.g. mcp, alertmanager; see sources)
+	UpstreamSeverity  string `json:"upstreamSeverity,omitempty"` // Fault event severity replaced by the severity policy (see severitypolicy)
+	SeverityRule      string `json:"severityRule,omitempty"`     // Severity policy rule that set Severity
+
+	// Skills available to the agent for this investigation
+	Skills []SkillRef `json:"skills,omitempty"`
+
+	// Agent identifies the agent runtime the investigation ran with, so changes in
+	// investigation quality can be traced to agent upgrades
+	Agent *AgentRuntime `json:"agent,omitempty"`
+
+	// Egress is the network access the agent ran with under egress control (see
+	// the egress package); nil when it was not restricted
+	Egress *egress.Policy `json:"egress,omitempty"`
+
+	// FaultHandlers records the specialized handlers that processed the fault
+	// before or instead of the agent (see the faulthandlers package)
+	FaultHandlers []faulthandlers.Run `json:"faultHandlers,omitempty"`
+
+	// PostmortemURL is the pull request (or commit) publishing the investigation to Git
+	PostmortemURL string `json:"postmortemUrl,omitempty"`
+
+	// KnowledgeBaseURLs maps each knowledge base the investigation was published to
+	// (e.g. "confluence", "notion") to the page URL
+	KnowledgeBaseURLs map[string]string `json:"knowledgeBaseUrls,omitempty"`
+
+	// ServiceNowNumber and ServiceNowURL identify the ServiceNow record filed (or
+	// updated, for a recurrence) for the fault
+	ServiceNowNumber string `json:"serviceNowNumber,omitempty"`
+	ServiceNowURL    string `json:"serviceNowUrl,omitempty"`
+
+	// Labels are key-value pairs for filtering and ownership (e.g. team, cost
+	// center), attached from cluster labels, label rules, the agent's findings, or
+	// manually. Annotations are free-form notes that are stored but not filterable.
+	// Later sources override earlier ones: cluster, rule, agent, manual.
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Owner is the team and service owning the affected workload, resolved from
+	// annotations, a software catalog, or a CMDB (see the ownership package)
+	Owner *ownership.Owner `json:"owner,omitempty"`
+
+	// Verification counts the agent's claims checked against the live cluster
+	// (see the verify package); nil when verification is disabled or the agent
+	// recorded no claims
+	Verification *verify.Summary `json:"verification,omitempty"`
+
+	// Latency breaks down the time from receiving the fault event to notifying
+	// (see latency.go)
+	Latency *Latency `json:"latency,omitempty"`
+}
+
+// AgentRuntime records the agent CLI, container image, and model an
+// investigation ran with. Versions and digests that could not be read are empty.
+type AgentRuntime struct {
+	CLI        string `json:"cli,omitempty"`
+	CLIVersion string `json:"cliVersion,omitempty"` // Version reported by the CLI in the agent image
+	Image      string `json:"image,omitempty"`
+	// ImageDigest is the "sha256:<digest>" of the image the agent containers run
+	ImageDigest   string `json:"imageDigest,omitempty"`
+	Model         string `json:"model,omitempty"`
+	PromptVersion string `json:"promptVersion,omitempty"` // See agent.PromptVersion
+}
+
+// SkillRef records a skill bundle that was available to the agent
+type SkillRef struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`  // Version declared in the skills manifest
+	Revision string `json:"revision,omitempty"` // Git commit or "sha256:<digest>" of the cached bundle
 }
 
 // ResourceInfo represents the Kubernetes resource involved in the incident
@@ -67,8 +164,9 @@ func NewFromEvent(incidentID string, event *events.FaultEvent) *Incident {
 	incident := &Incident{
 		IncidentID:        incidentID,
 		FaultID:           event.FaultID,
-		Status:            StatusInvestigating,
+		Status:            StatusReceived,
 		CreatedAt:         now,
+		StatusTimes:       map[string]time.Time{StatusReceived: now},
 		Cluster:           event.Cluster,
 		Namespace:         event.GetNamespace(),
 		FaultType:         event.GetFaultType(),
@@ -76,11 +174,22 @@ func NewFromEvent(incidentID string, event *events.FaultEvent) *Incident {
 		Context:           event.GetContext(),
 		Timestamp:         event.GetTimestamp(),
 		TriggeringEventID: event.FaultID, // Use FaultID for traceability
+		Source:            event.Source,
 	}
 
 	// Flatten resource information from event
 	incident.Resource = extractResourceInfo(event)
 
+	// Pass the labels and annotations set upstream through to the incident. Keys
+	// nightcrier cannot store are dropped.
+	eventLabels, eventAnnotations, unusable := labels.Usable(event.Labels, event.Annotations)
+	incident.AddLabels(eventLabels, eventAnnotations)
+	if len(unusable) > 0 {
+		slog.Warn("dropped unusable fault event labels",
+			"fault_id", event.FaultID,
+			"keys", unusable)
+	}
+
 	return incident
 }
 
@@ -140,12 +249,34 @@ func (i *Incident) MarkCompleted(exitCode int, err error) {
 	i.ExitCode = &exitCode
 
 	if err != nil {
-		i.Status = StatusFailed
+		i.enter(StatusFailed, now)
 		i.FailureReason = err.Error()
 	} else if exitCode == 0 {
-		i.Status = StatusResolved
+		i.enter(StatusResolved, now)
 	} else {
-		i.Status = StatusFailed
+		i.enter(StatusFailed, now)
 		i.FailureReason = fmt.Sprintf("agent exited with code %d", exitCode)
 	}
 }
+
+// MarkAgentFailed reclassifies a completed investigation whose agent did not
+// produce a usable report as agent_failed.
+func (i *Incident) MarkAgentFailed() {
+	i.reclassify(StatusAgentFailed)
+}
+
+// MarkAwaitingReview holds the findings of a resolved investigation for human
+// review.
+func (i *Incident) MarkAwaitingReview() {
+	i.reclassify(StatusAwaitingReview)
+}
+
+// reclassify replaces the status MarkCompleted set, entered at completion.
+func (i *Incident) reclassify(status string) {
+	at := time.Now()
+	if i.CompletedAt != nil {
+		at = *i.CompletedAt
+	}
+	delete(i.StatusTimes, i.Status)
+	i.enter(status, at)
+}
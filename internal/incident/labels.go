@@ -0,0 +1,34 @@
+package incident
+
+import "github.com/rbias/nightcrier/internal/labels"
+
+// AddLabels merges labels and annotations into the incident, replacing the values
+// of keys it already has.
+func (i *Incident) AddLabels(labels, annotations map[string]string) {
+	if len(labels) > 0 && i.Labels == nil {
+		i.Labels = make(map[string]string, len(labels))
+	}
+	for key, value := range labels {
+		i.Labels[key] = value
+	}
+	if len(annotations) > 0 && i.Annotations == nil {
+		i.Annotations = make(map[string]string, len(annotations))
+	}
+	for key, value := range annotations {
+		i.Annotations[key] = value
+	}
+}
+
+// ApplyLabelRules adds the labels and annotations of every rule matching the
+// incident. Later rules take precedence over earlier ones for the same key.
+func (i *Incident) ApplyLabelRules(rules []labels.Rule) {
+	kind := ""
+	if i.Resource != nil {
+		kind = i.Resource.Kind
+	}
+	for _, rule := range rules {
+		if rule.Matches(i.FaultType, kind, i.Namespace, i.Severity) {
+			i.AddLabels(rule.Labels, rule.Annotations)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package incident
+
+import (
+	"testing"
+
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/labels"
+)
+
+func TestApplyLabelRules(t *testing.T) {
+	inc := &Incident{
+		Namespace: "payments",
+		FaultType: "CrashLoopBackOff",
+		Severity:  "CRITICAL",
+		Resource:  &ResourceInfo{Kind: "Pod"},
+		Labels:    map[string]string{"env": "prod", "team": "unknown"},
+	}
+	inc.ApplyLabelRules([]labels.Rule{
+		{Namespaces: []string{"payments"}, Labels: map[string]string{"team": "payments"}},
+		{Kinds: []string{"Deployment"}, Labels: map[string]string{"team": "platform"}},
+		{FaultTypes: []string{"crashloopbackoff"}, Annotations: map[string]string{"hint": "check recent deploys"}},
+	})
+
+	if inc.Labels["team"] != "payments" || inc.Labels["env"] != "prod" {
+		t.Errorf("Labels = %v, want team=payments with env=prod kept", inc.Labels)
+	}
+	if inc.Annotations["hint"] != "check recent deploys" {
+		t.Errorf("Annotations = %v", inc.Annotations)
+	}
+}
+
+func TestNewFromEvent_PassesEventLabelsThrough(t *testing.T) {
+	event := &events.FaultEvent{
+		FaultID:   "abc123",
+		Cluster:   "prod",
+		Resource:  &events.ResourceInfo{Kind: "Pod", Name: "web-1", Namespace: "shop"},
+		FaultType: "CrashLoopBackOff",
+		Labels: map[string]string{
+			"alertname":   "KubePodCrashLooping",
+			"correlation": "PD-4711",
+			"__name__":    "ALERTS",
+		},
+		Annotations: map[string]string{"runbook_url": "https://runbooks.example.com/crashloop"},
+	}
+	inc := NewFromEvent("inc-1", event)
+
+	if labels.Format(inc.Labels) != "alertname=KubePodCrashLooping,correlation=PD-4711" {
+		t.Errorf("Labels = %v, want the usable event labels", inc.Labels)
+	}
+	if inc.Annotations["runbook_url"] != "https://runbooks.example.com/crashloop" {
+		t.Errorf("Annotations = %v", inc.Annotations)
+	}
+
+	// Configured rules take precedence over the upstream labels
+	inc.ApplyLabelRules([]labels.Rule{{Labels: map[string]string{"alertname": "renamed"}}})
+	if inc.Labels["alertname"] != "renamed" {
+		t.Errorf("Labels = %v, want the rule's value", inc.Labels)
+	}
+}
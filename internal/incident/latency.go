@@ -0,0 +1,82 @@
+package incident
+
+import "time"
+
+// Latency stages, in pipeline order
+const (
+	// StageQueue is from receiving the fault event to starting to process it:
+	// time in the event queue and in aggregation holds
+	StageQueue = "queue"
+	// StageAgentStart is from starting to process the event to starting the agent:
+	// workspace preparation and waiting for an agent slot
+	StageAgentStart = "agent_start"
+	// StageAgent is the agent run, including API key failover
+	StageAgent = "agent"
+	// StageUpload is the upload of the artifacts to storage
+	StageUpload = "upload"
+	// StageNotify is sending the notification, or queueing it in the outbox
+	StageNotify = "notify"
+)
+
+// Latency records how long each stage between receiving a fault event and
+// notifying about its incident took, in milliseconds, so slow paths can be
+// diagnosed per incident. Stages that did not run (no storage, no notification)
+// are omitted.
+type Latency struct {
+	QueueMs      *int64 `json:"queueMs,omitempty"`
+	AgentStartMs *int64 `json:"agentStartMs,omitempty"`
+	AgentMs      *int64 `json:"agentMs,omitempty"`
+	UploadMs     *int64 `json:"uploadMs,omitempty"`
+	NotifyMs     *int64 `json:"notifyMs,omitempty"`
+	// TotalMs is from receiving the fault event to the end of the last stage
+	TotalMs *int64 `json:"totalMs,omitempty"`
+}
+
+// Record sets the duration of a stage. Unknown stages are ignored.
+func (l *Latency) Record(stage string, d time.Duration) {
+	ms := d.Milliseconds()
+	switch stage {
+	case StageQueue:
+		l.QueueMs = &ms
+	case StageAgentStart:
+		l.AgentStartMs = &ms
+	case StageAgent:
+		l.AgentMs = &ms
+	case StageUpload:
+		l.UploadMs = &ms
+	case StageNotify:
+		l.NotifyMs = &ms
+	}
+}
+
+// SetTotal sets the end-to-end duration.
+func (l *Latency) SetTotal(d time.Duration) {
+	ms := d.Milliseconds()
+	l.TotalMs = &ms
+}
+
+// Stages returns the recorded stage durations in pipeline order.
+func (l *Latency) Stages() []StageLatency {
+	var stages []StageLatency
+	for _, s := range []struct {
+		name string
+		ms   *int64
+	}{
+		{StageQueue, l.QueueMs},
+		{StageAgentStart, l.AgentStartMs},
+		{StageAgent, l.AgentMs},
+		{StageUpload, l.UploadMs},
+		{StageNotify, l.NotifyMs},
+	} {
+		if s.ms != nil {
+			stages = append(stages, StageLatency{Stage: s.name, Duration: time.Duration(*s.ms) * time.Millisecond})
+		}
+	}
+	return stages
+}
+
+// StageLatency is the duration of one stage.
+type StageLatency struct {
+	Stage    string
+	Duration time.Duration
+}
@@ -0,0 +1,28 @@
+package incident
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestLatency(t *testing.T) {
+	var l Latency
+	l.Record(StageQueue, 1500*time.Millisecond)
+	l.Record(StageAgent, 2*time.Minute)
+	l.Record("unknown", time.Second)
+	l.SetTotal(3 * time.Minute)
+
+	stages := l.Stages()
+	if len(stages) != 2 || stages[0].Stage != StageQueue || stages[1].Duration != 2*time.Minute {
+		t.Errorf("Stages() = %+v, want queue then agent", stages)
+	}
+
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"queueMs":1500,"agentMs":120000,"totalMs":180000}`; got != want {
+		t.Errorf("JSON = %s, want %s", got, want)
+	}
+}
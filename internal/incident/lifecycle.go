@@ -0,0 +1,108 @@
+package incident
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrInvalidTransition is returned for a status change the incident lifecycle does
+// not allow.
+var ErrInvalidTransition = errors.New("invalid incident status transition")
+
+// transitions lists the statuses each status may change to. An incident is
+// received, then recorded without an investigation (pending), left out of
+// investigation (suppressed), or queued for an agent slot; an investigation ends
+// resolved, failed, or agent_failed, or holds its findings for a human (awaiting
+// review, remediation proposed) until they resolve it. Incidents left open too
+// long expire. Cached, rule-based, and fault handler reports resolve an incident
+// straight from received.
+var transitions = map[string][]string{
+	StatusReceived:            {StatusPending, StatusQueued, StatusInvestigating, StatusSuppressed, StatusResolved, StatusFailed, StatusAgentFailed, StatusExpired},
+	StatusPending:             {StatusQueued, StatusInvestigating, StatusSuppressed, StatusResolved, StatusFailed, StatusExpired},
+	StatusQueued:              {StatusInvestigating, StatusSuppressed, StatusResolved, StatusFailed, StatusAgentFailed, StatusExpired},
+	StatusInvestigating:       {StatusAwaitingReview, StatusRemediationProposed, StatusResolved, StatusFailed, StatusAgentFailed},
+	StatusAwaitingReview:      {StatusRemediationProposed, StatusInvestigating, StatusResolved, StatusExpired},
+	StatusRemediationProposed: {StatusAwaitingReview, StatusResolved, StatusExpired},
+	StatusFailed:              {StatusResolved},
+	StatusAgentFailed:         {StatusResolved},
+	StatusResolved:            nil,
+	StatusSuppressed:          nil,
+	StatusExpired:             nil,
+}
+
+// Statuses lists the incident statuses in lifecycle order.
+var Statuses = []string{
+	StatusReceived, StatusPending, StatusQueued, StatusInvestigating,
+	StatusAwaitingReview, StatusRemediationProposed,
+	StatusResolved, StatusFailed, StatusAgentFailed, StatusSuppressed, StatusExpired,
+}
+
+// ValidStatus reports whether status is an incident status.
+func ValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// ValidateTransition checks that an incident may change from one status to
+// another. Staying in the same status is always allowed.
+func ValidateTransition(from, to string) error {
+	if !ValidStatus(to) {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidTransition, to)
+	}
+	if from == to {
+		return nil
+	}
+	if !slices.Contains(transitions[from], to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// IsTerminal reports whether an incident in the status can no longer change.
+func IsTerminal(status string) bool {
+	next, ok := transitions[status]
+	return ok && len(next) == 0
+}
+
+// Investigated reports whether an incident in the status has findings from its
+// investigation: it was resolved, or its findings await a human.
+func Investigated(status string) bool {
+	return status == StatusResolved || status == StatusAwaitingReview || status == StatusRemediationProposed
+}
+
+// ExpirableStatuses returns the statuses an incident left open too long expires
+// from.
+func ExpirableStatuses() []string {
+	var expirable []string
+	for _, status := range Statuses {
+		if slices.Contains(transitions[status], StatusExpired) {
+			expirable = append(expirable, status)
+		}
+	}
+	return expirable
+}
+
+// Transition changes the incident's status, recording when it entered the new
+// status. It returns ErrInvalidTransition, leaving the incident unchanged, when the
+// lifecycle does not allow the change.
+func (i *Incident) Transition(to string, at time.Time) error {
+	if err := ValidateTransition(i.Status, to); err != nil {
+		return err
+	}
+	i.enter(to, at)
+	return nil
+}
+
+// enter sets the incident's status and records when it entered it.
+func (i *Incident) enter(status string, at time.Time) {
+	if i.Status == status && !i.StatusTimes[status].IsZero() {
+		return
+	}
+	i.Status = status
+	if i.StatusTimes == nil {
+		i.StatusTimes = make(map[string]time.Time)
+	}
+	i.StatusTimes[status] = at
+}
@@ -0,0 +1,101 @@
+package incident
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		ok       bool
+	}{
+		{StatusReceived, StatusQueued, true},
+		{StatusReceived, StatusSuppressed, true},
+		{StatusReceived, StatusResolved, true},
+		{StatusQueued, StatusInvestigating, true},
+		{StatusInvestigating, StatusAwaitingReview, true},
+		{StatusAwaitingReview, StatusRemediationProposed, true},
+		{StatusRemediationProposed, StatusResolved, true},
+		{StatusPending, StatusExpired, true},
+		{StatusFailed, StatusResolved, true},
+		{StatusResolved, StatusResolved, true},
+		{StatusInvestigating, StatusQueued, false},
+		{StatusInvestigating, StatusExpired, false},
+		{StatusResolved, StatusAwaitingReview, false},
+		{StatusSuppressed, StatusQueued, false},
+		{StatusExpired, StatusResolved, false},
+		{StatusReceived, "closed", false},
+	}
+	for _, tt := range tests {
+		err := ValidateTransition(tt.from, tt.to)
+		if (err == nil) != tt.ok {
+			t.Errorf("ValidateTransition(%s, %s) = %v, want ok %v", tt.from, tt.to, err, tt.ok)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("ValidateTransition(%s, %s) = %v, want ErrInvalidTransition", tt.from, tt.to, err)
+		}
+	}
+}
+
+func TestLifecycleStatuses(t *testing.T) {
+	for _, status := range Statuses {
+		if !ValidStatus(status) {
+			t.Errorf("%s has no transitions", status)
+		}
+		for _, next := range transitions[status] {
+			if !slices.Contains(Statuses, next) {
+				t.Errorf("%s -> %s leads to an unlisted status", status, next)
+			}
+		}
+	}
+	if len(Statuses) != len(transitions) {
+		t.Errorf("Statuses lists %d statuses, transitions %d", len(Statuses), len(transitions))
+	}
+
+	for _, status := range []string{StatusResolved, StatusSuppressed, StatusExpired} {
+		if !IsTerminal(status) {
+			t.Errorf("IsTerminal(%s) = false, want true", status)
+		}
+	}
+	if IsTerminal(StatusAwaitingReview) || IsTerminal("closed") {
+		t.Error("IsTerminal() = true for a status with transitions or an unknown status")
+	}
+
+	want := []string{StatusReceived, StatusPending, StatusQueued, StatusAwaitingReview, StatusRemediationProposed}
+	if got := ExpirableStatuses(); !slices.Equal(got, want) {
+		t.Errorf("ExpirableStatuses() = %v, want %v", got, want)
+	}
+}
+
+func TestTransition(t *testing.T) {
+	created := time.Date(2024, 6, 13, 10, 0, 0, 0, time.UTC)
+	inc := &Incident{Status: StatusReceived, StatusTimes: map[string]time.Time{StatusReceived: created}}
+
+	queued := created.Add(time.Second)
+	if err := inc.Transition(StatusQueued, queued); err != nil {
+		t.Fatalf("Transition(queued) failed: %v", err)
+	}
+	// Staying in a status keeps the time it was entered
+	if err := inc.Transition(StatusQueued, queued.Add(time.Minute)); err != nil {
+		t.Fatalf("Transition(queued) again failed: %v", err)
+	}
+	if err := inc.Transition(StatusAwaitingReview, queued); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Transition(awaiting_review) from queued = %v, want ErrInvalidTransition", err)
+	}
+	if inc.Status != StatusQueued || !inc.StatusTimes[StatusQueued].Equal(queued) || !inc.StatusTimes[StatusReceived].Equal(created) {
+		t.Errorf("incident = %s, %v; want queued with both times", inc.Status, inc.StatusTimes)
+	}
+
+	inc.Status = StatusInvestigating
+	inc.MarkCompleted(0, nil)
+	if inc.Status != StatusResolved || inc.StatusTimes[StatusResolved].IsZero() {
+		t.Errorf("MarkCompleted() = %s, %v; want resolved with its time", inc.Status, inc.StatusTimes)
+	}
+	inc.MarkAwaitingReview()
+	if _, ok := inc.StatusTimes[StatusResolved]; ok || inc.Status != StatusAwaitingReview || !inc.StatusTimes[StatusAwaitingReview].Equal(*inc.CompletedAt) {
+		t.Errorf("MarkAwaitingReview() = %s, %v; want awaiting_review entered at completion", inc.Status, inc.StatusTimes)
+	}
+}
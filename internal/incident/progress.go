@@ -0,0 +1,179 @@
+package incident
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// InvestigationProgress describes a running investigation and its latest step.
+type InvestigationProgress struct {
+	IncidentID string    `json:"incident_id"`
+	Cluster    string    `json:"cluster"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Resource   string    `json:"resource,omitempty"`
+	FaultType  string    `json:"fault_type"`
+	Severity   string    `json:"severity"`
+	StartedAt  time.Time `json:"started_at"`
+
+	// Progress is the agent's current step, e.g. "checking pod logs". Empty until
+	// the agent reports its first step.
+	Progress string `json:"progress,omitempty"`
+	// Steps counts the tool calls the agent has made so far
+	Steps     int       `json:"steps"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// InvestigationResult describes a finished investigation.
+type InvestigationResult struct {
+	IncidentID   string    `json:"incident_id"`
+	DisplayID    string    `json:"display_id,omitempty"`
+	Cluster      string    `json:"cluster"`
+	Namespace    string    `json:"namespace,omitempty"`
+	Resource     string    `json:"resource,omitempty"`
+	FaultType    string    `json:"fault_type"`
+	Severity     string    `json:"severity"`
+	Status       string    `json:"status"`
+	FailureClass string    `json:"failure_class,omitempty"`
+	CompletedAt  time.Time `json:"completed_at"`
+	// DurationMs is the time from receiving the fault event to notifying about its
+	// incident, when known
+	DurationMs int64 `json:"duration_ms,omitempty"`
+}
+
+// maxRecentResults is the number of finished investigations the tracker keeps
+const maxRecentResults = 20
+
+// ProgressTracker keeps the progress of in-flight investigations so it can be
+// shown while the agent runs instead of only after it completes, along with the
+// results of the most recent investigations. It is safe for concurrent use.
+type ProgressTracker struct {
+	mu     sync.Mutex
+	active map[string]*InvestigationProgress
+	recent []InvestigationResult // oldest first
+}
+
+// NewProgressTracker creates an empty tracker.
+func NewProgressTracker() *ProgressTracker {
+	return &ProgressTracker{active: make(map[string]*InvestigationProgress)}
+}
+
+// Start begins tracking an investigation of inc.
+func (t *ProgressTracker) Start(inc *Incident) {
+	p := &InvestigationProgress{
+		IncidentID: inc.IncidentID,
+		Cluster:    inc.Cluster,
+		Namespace:  inc.Namespace,
+		FaultType:  inc.FaultType,
+		Severity:   inc.Severity,
+		StartedAt:  time.Now().UTC(),
+	}
+	if inc.Resource != nil {
+		p.Resource = inc.Resource.Kind + "/" + inc.Resource.Name
+	}
+	p.UpdatedAt = p.StartedAt
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active[inc.IncidentID] = p
+}
+
+// Update records the current step of an investigation. newStep counts the update
+// as a new tool call. Updates for untracked incidents are ignored.
+func (t *ProgressTracker) Update(incidentID, progress string, newStep bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.active[incidentID]
+	if !ok {
+		return
+	}
+	p.Progress = progress
+	if newStep {
+		p.Steps++
+	}
+	p.UpdatedAt = time.Now().UTC()
+}
+
+// Get returns the progress of an investigation, if it is being tracked.
+func (t *ProgressTracker) Get(incidentID string) (InvestigationProgress, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.active[incidentID]
+	if !ok {
+		return InvestigationProgress{}, false
+	}
+	return *p, true
+}
+
+// Finish stops tracking an investigation.
+func (t *ProgressTracker) Finish(incidentID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.active, incidentID)
+}
+
+// Complete records the result of a finished investigation, keeping the most recent
+// ones.
+func (t *ProgressTracker) Complete(inc *Incident) {
+	r := InvestigationResult{
+		IncidentID:   inc.IncidentID,
+		DisplayID:    inc.DisplayID,
+		Cluster:      inc.Cluster,
+		Namespace:    inc.Namespace,
+		FaultType:    inc.FaultType,
+		Severity:     inc.Severity,
+		Status:       inc.Status,
+		FailureClass: inc.FailureClass,
+		CompletedAt:  time.Now().UTC(),
+	}
+	if inc.CompletedAt != nil {
+		r.CompletedAt = inc.CompletedAt.UTC()
+	}
+	if inc.Resource != nil {
+		r.Resource = inc.Resource.Kind + "/" + inc.Resource.Name
+	}
+	if inc.Latency != nil && inc.Latency.TotalMs != nil {
+		r.DurationMs = *inc.Latency.TotalMs
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.recent = append(t.recent, r)
+	if len(t.recent) > maxRecentResults {
+		t.recent = t.recent[len(t.recent)-maxRecentResults:]
+	}
+}
+
+// Recent returns the results of the most recent investigations, newest first.
+func (t *ProgressTracker) Recent() []InvestigationResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]InvestigationResult, len(t.recent))
+	for i, r := range t.recent {
+		out[len(t.recent)-1-i] = r
+	}
+	return out
+}
+
+// Active returns the in-flight investigations, oldest first.
+func (t *ProgressTracker) Active() []InvestigationProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]InvestigationProgress, 0, len(t.active))
+	for _, p := range t.active {
+		out = append(out, *p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out
+}
+
+// GetInvestigations returns the in-flight investigations and the recent results
+// for the health server's /health/investigations endpoint.
+func (t *ProgressTracker) GetInvestigations() interface{} {
+	active := t.Active()
+	return struct {
+		Investigations []InvestigationProgress `json:"investigations"`
+		Count          int                     `json:"count"`
+		Recent         []InvestigationResult   `json:"recent"`
+	}{active, len(active), t.Recent()}
+}
@@ -0,0 +1,58 @@
+package incident
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestProgressTracker(t *testing.T) {
+	tr := NewProgressTracker()
+	inc := &Incident{IncidentID: "inc-1", Cluster: "prod", FaultType: "CrashLoop", Severity: "HIGH", Resource: &ResourceInfo{Kind: "Pod", Name: "api-1"}}
+	tr.Start(inc)
+
+	tr.Update("inc-1", "checking pod logs", true)
+	tr.Update("inc-1", "analyzing findings", false)
+	tr.Update("unknown", "ignored", true)
+
+	got, ok := tr.Get("inc-1")
+	if !ok {
+		t.Fatal("Get() should find a started investigation")
+	}
+	if got.Progress != "analyzing findings" || got.Steps != 1 || got.Resource != "Pod/api-1" {
+		t.Errorf("Get() = %+v", got)
+	}
+	if len(tr.Active()) != 1 {
+		t.Errorf("Active() = %d investigations, want 1", len(tr.Active()))
+	}
+
+	tr.Finish("inc-1")
+	if _, ok := tr.Get("inc-1"); ok {
+		t.Error("Get() should miss after Finish")
+	}
+	if len(tr.Active()) != 0 {
+		t.Error("Active() should be empty after Finish")
+	}
+}
+
+func TestProgressTracker_Recent(t *testing.T) {
+	tr := NewProgressTracker()
+	for i := 0; i < maxRecentResults+5; i++ {
+		tr.Complete(&Incident{IncidentID: fmt.Sprintf("inc-%d", i), Cluster: "prod", Status: StatusResolved})
+	}
+	failed := &Incident{IncidentID: "inc-failed", Status: StatusAgentFailed, FailureClass: "timeout", Resource: &ResourceInfo{Kind: "Pod", Name: "api-1"}}
+	failed.Latency = &Latency{}
+	failed.Latency.SetTotal(90 * time.Second)
+	tr.Complete(failed)
+
+	recent := tr.Recent()
+	if len(recent) != maxRecentResults {
+		t.Fatalf("Recent() = %d results, want %d", len(recent), maxRecentResults)
+	}
+	if got := recent[0]; got.IncidentID != "inc-failed" || got.FailureClass != "timeout" || got.Resource != "Pod/api-1" || got.DurationMs != 90000 {
+		t.Errorf("Recent()[0] = %+v, want the last result first", got)
+	}
+	if got := recent[len(recent)-1].IncidentID; got != "inc-6" {
+		t.Errorf("oldest kept result = %s, want inc-6", got)
+	}
+}
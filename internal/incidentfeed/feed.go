@@ -0,0 +1,203 @@
+// Package incidentfeed follows the incident changes made by every nightcrier
+// process sharing a state store, so each process and the clients of its health
+// server see incidents created and updated by peers without a manual refresh.
+//
+// Changes are read from the incident history, which every store records in the
+// same transaction as the change. With PostgreSQL a Listener delivers them as soon
+// as they are committed (LISTEN/NOTIFY); the feed also polls the history, which
+// is the only source with SQLite and catches up on notifications missed while the
+// listener was reconnecting.
+package incidentfeed
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+const (
+	// DefaultPollInterval is how often the history is polled without a listener
+	DefaultPollInterval = 5 * time.Second
+
+	// pollBatch bounds the changes read per query
+	pollBatch = 500
+
+	// pollOverlap is how far before the latest change seen each poll reads again,
+	// so changes committed late with an earlier timestamp are not missed
+	pollOverlap = time.Minute
+
+	// subscriberBuffer is the channel buffer of each subscriber
+	subscriberBuffer = 64
+)
+
+// Store reads the recorded incident changes (storage.StateStore).
+type Store interface {
+	IncidentChangesSince(ctx context.Context, since time.Time, limit int) ([]*storage.IncidentChange, error)
+}
+
+// Listener delivers incident changes as the state store publishes them. A nil
+// change asks the feed to poll, because notifications may have been missed or
+// carried no change. The channel is closed when the listener stops.
+type Listener interface {
+	Changes() <-chan *storage.IncidentChange
+}
+
+// Feed delivers every incident change once to each subscriber, oldest first.
+type Feed struct {
+	store        Store
+	listener     Listener
+	pollInterval time.Duration
+
+	mu          sync.Mutex
+	subscribers map[chan *storage.IncidentChange]struct{}
+	// cursor is the time of the latest change delivered
+	cursor time.Time
+	// seen holds the IDs of the changes delivered within pollOverlap of cursor
+	seen map[string]time.Time
+
+	dropped atomic.Int64
+}
+
+// New returns a feed reading the changes of store every pollInterval, and from
+// listener when it is not nil.
+func New(store Store, listener Listener, pollInterval time.Duration) *Feed {
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+	return &Feed{
+		store:        store,
+		listener:     listener,
+		pollInterval: pollInterval,
+		subscribers:  make(map[chan *storage.IncidentChange]struct{}),
+		seen:         make(map[string]time.Time),
+	}
+}
+
+// Subscribe returns a channel receiving the changes delivered from now on, and a
+// function ending the subscription. A subscriber that falls behind by more than
+// its buffer misses changes (see Dropped).
+func (f *Feed) Subscribe() (<-chan *storage.IncidentChange, func()) {
+	ch := make(chan *storage.IncidentChange, subscriberBuffer)
+	f.mu.Lock()
+	f.subscribers[ch] = struct{}{}
+	f.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			f.mu.Lock()
+			delete(f.subscribers, ch)
+			f.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Dropped returns the number of changes not delivered to subscribers that fell
+// behind.
+func (f *Feed) Dropped() int64 {
+	return f.dropped.Load()
+}
+
+// Run follows the changes recorded from now on until ctx is done.
+func (f *Feed) Run(ctx context.Context) {
+	start := time.Now()
+	f.mu.Lock()
+	f.cursor = start
+	f.mu.Unlock()
+	// The changes within the overlap before the start were made before the
+	// subscribers were interested; mark them as delivered
+	if changes, err := f.store.IncidentChangesSince(ctx, start.Add(-pollOverlap), pollBatch); err == nil {
+		f.mu.Lock()
+		for _, change := range changes {
+			f.seen[change.ID] = change.ChangedAt
+		}
+		f.mu.Unlock()
+	}
+
+	var notifications <-chan *storage.IncidentChange
+	if f.listener != nil {
+		notifications = f.listener.Changes()
+	}
+	ticker := time.NewTicker(f.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.poll(ctx)
+		case change, ok := <-notifications:
+			if !ok {
+				slog.Warn("incident change listener stopped, following changes by polling", "poll_interval", f.pollInterval)
+				notifications = nil
+				continue
+			}
+			if change == nil {
+				f.poll(ctx)
+				continue
+			}
+			f.deliver(change)
+		}
+	}
+}
+
+// poll reads the changes recorded since the cursor, minus the overlap, and
+// delivers the ones not delivered yet.
+func (f *Feed) poll(ctx context.Context) {
+	f.mu.Lock()
+	since := f.cursor.Add(-pollOverlap)
+	f.mu.Unlock()
+
+	for {
+		changes, err := f.store.IncidentChangesSince(ctx, since, pollBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Warn("failed to poll incident changes", "error", err)
+			}
+			return
+		}
+		for _, change := range changes {
+			f.deliver(change)
+		}
+		if len(changes) < pollBatch {
+			return
+		}
+		// Read on from the last change of a full batch
+		last := changes[len(changes)-1].ChangedAt
+		if !last.After(since) {
+			return
+		}
+		since = last
+	}
+}
+
+// deliver sends a change to the subscribers unless it was delivered before.
+func (f *Feed) deliver(change *storage.IncidentChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.seen[change.ID]; ok {
+		return
+	}
+	f.seen[change.ID] = change.ChangedAt
+	if change.ChangedAt.After(f.cursor) {
+		f.cursor = change.ChangedAt
+		for id, at := range f.seen {
+			if at.Before(f.cursor.Add(-pollOverlap)) {
+				delete(f.seen, id)
+			}
+		}
+	}
+
+	for ch := range f.subscribers {
+		select {
+		case ch <- change:
+		default:
+			f.dropped.Add(1)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package incidentfeed
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// fakeStore is an incident history shared by the "processes" of a test.
+type fakeStore struct {
+	mu      sync.Mutex
+	changes []*storage.IncidentChange
+}
+
+func (s *fakeStore) record(id string, at time.Time) *storage.IncidentChange {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	change := &storage.IncidentChange{ID: id, IncidentID: "inc-" + id, ChangedAt: at, Field: storage.HistoryStatus, NewValue: "investigating"}
+	s.changes = append(s.changes, change)
+	return change
+}
+
+func (s *fakeStore) IncidentChangesSince(ctx context.Context, since time.Time, limit int) ([]*storage.IncidentChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found []*storage.IncidentChange
+	for _, c := range s.changes {
+		if !c.ChangedAt.Before(since) && len(found) < limit {
+			found = append(found, c)
+		}
+	}
+	return found, nil
+}
+
+type fakeListener chan *storage.IncidentChange
+
+func (l fakeListener) Changes() <-chan *storage.IncidentChange { return l }
+
+// receive returns the IDs of the next n changes.
+func receive(t *testing.T, ch <-chan *storage.IncidentChange, n int) []string {
+	t.Helper()
+	var ids []string
+	for len(ids) < n {
+		select {
+		case change := <-ch:
+			ids = append(ids, change.ID)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %v, want %d changes", ids, n)
+		}
+	}
+	return ids
+}
+
+func expectNone(t *testing.T, ch <-chan *storage.IncidentChange) {
+	t.Helper()
+	select {
+	case change := <-ch:
+		t.Errorf("unexpected change %+v", change)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFeedPolling(t *testing.T) {
+	store := &fakeStore{}
+	store.record("before-start", time.Now().Add(-10*time.Second))
+
+	feed := New(store, nil, 10*time.Millisecond)
+	changes, unsubscribe := feed.Subscribe()
+	defer unsubscribe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go feed.Run(ctx)
+
+	time.Sleep(20 * time.Millisecond)
+	store.record("peer-1", time.Now())
+	// Committed late with an earlier timestamp
+	store.record("peer-2", time.Now().Add(-5*time.Second))
+
+	got := receive(t, changes, 2)
+	if fmt.Sprint(got) != "[peer-1 peer-2]" {
+		t.Errorf("changes = %v, want the peers' changes once, not the one before the start", got)
+	}
+	expectNone(t, changes)
+}
+
+func TestFeedListener(t *testing.T) {
+	store := &fakeStore{}
+	listener := make(fakeListener, 4)
+	// Polling is only the fallback here
+	feed := New(store, listener, time.Hour)
+	changes, unsubscribe := feed.Subscribe()
+	defer unsubscribe()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go feed.Run(ctx)
+
+	time.Sleep(20 * time.Millisecond)
+	notified := store.record("notified", time.Now())
+	listener <- notified
+	listener <- notified
+	if got := receive(t, changes, 1); got[0] != "notified" {
+		t.Errorf("changes = %v, want the notified change", got)
+	}
+	expectNone(t, changes)
+
+	// Missed while reconnecting: the nil notification makes the feed poll
+	store.record("missed", time.Now())
+	listener <- nil
+	if got := receive(t, changes, 1); got[0] != "missed" {
+		t.Errorf("changes = %v, want the missed change", got)
+	}
+}
+
+func TestFeedSlowSubscriber(t *testing.T) {
+	store := &fakeStore{}
+	feed := New(store, nil, time.Hour)
+	_, unsubscribe := feed.Subscribe()
+	for i := 0; i < subscriberBuffer+3; i++ {
+		feed.deliver(store.record(fmt.Sprint(i), time.Now()))
+	}
+	if feed.Dropped() != 3 {
+		t.Errorf("Dropped() = %d, want 3", feed.Dropped())
+	}
+	unsubscribe()
+	unsubscribe()
+}
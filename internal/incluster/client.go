@@ -0,0 +1,257 @@
+// Package incluster is a minimal Kubernetes API client for the pod nightcrier runs
+// in. It authenticates with the pod's service account and supports only what
+// nightcrier needs in its own cluster: Events, and listing, creating, and status
+// updates of its custom resources (operator mode). Triage access to the monitored clusters goes through MCP and the
+// agent's kubeconfig, never through this client.
+package incluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Service account credentials mounted into every pod
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	tokenFile         = serviceAccountDir + "/token"
+	caFile            = serviceAccountDir + "/ca.crt"
+)
+
+// requestTimeout bounds each API request
+const requestTimeout = 10 * time.Second
+
+var (
+	// ErrNotFound is returned when the object (or its resource type) does not exist.
+	ErrNotFound = errors.New("object not found")
+	// ErrAlreadyExists is returned when creating an object whose name is taken.
+	ErrAlreadyExists = errors.New("object already exists")
+)
+
+// Client calls the Kubernetes API server with a bearer token.
+type Client struct {
+	baseURL    string
+	token      func() (string, error)
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the API server at baseURL. token is called for
+// every request, so rotated (projected) service account tokens are picked up.
+func NewClient(baseURL string, token func() (string, error), httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+// NewInClusterClient creates a client from the environment Kubernetes gives every
+// pod: KUBERNETES_SERVICE_HOST/PORT and the mounted service account.
+func NewInClusterClient() (*Client, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("not running in a Kubernetes pod (KUBERNETES_SERVICE_HOST/PORT not set)")
+	}
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("failed to parse service account CA %s", caFile)
+	}
+	if _, err := readToken(); err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		},
+	}
+	return NewClient("https://"+net.JoinHostPort(host, port), readToken, httpClient), nil
+}
+
+// readToken reads the pod's service account token.
+func readToken() (string, error) {
+	data, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read service account token: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// ObjectReference identifies the object an Event is about.
+type ObjectReference struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	UID        string `json:"uid,omitempty"`
+}
+
+// Event types
+const (
+	EventTypeNormal  = "Normal"
+	EventTypeWarning = "Warning"
+)
+
+// maxEventMessageLength keeps event messages readable in kubectl describe
+const maxEventMessageLength = 1024
+
+// Event is a core/v1 Event to record.
+type Event struct {
+	InvolvedObject ObjectReference
+	// Type is EventTypeNormal or EventTypeWarning
+	Type string
+	// Reason is a short UpperCamelCase reason, e.g. "InvestigationCompleted"
+	Reason  string
+	Message string
+	// Component is the reporting component, e.g. "nightcrier"
+	Component string
+	// Instance is the reporting instance, e.g. the pod name
+	Instance string
+}
+
+// CreateEvent records an event in namespace.
+func (c *Client) CreateEvent(ctx context.Context, namespace string, event Event) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	message := event.Message
+	if len(message) > maxEventMessageLength {
+		message = message[:maxEventMessageLength-3] + "..."
+	}
+	body := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Event",
+		"metadata": map[string]string{
+			"generateName": event.Component + "-",
+			"namespace":    namespace,
+		},
+		"involvedObject":     event.InvolvedObject,
+		"type":               event.Type,
+		"reason":             event.Reason,
+		"message":            message,
+		"source":             map[string]string{"component": event.Component},
+		"reportingComponent": event.Component,
+		"reportingInstance":  event.Instance,
+		"firstTimestamp":     now,
+		"lastTimestamp":      now,
+		"count":              1,
+	}
+	path := fmt.Sprintf("/api/v1/namespaces/%s/events", url.PathEscape(namespace))
+	return c.do(ctx, http.MethodPost, path, "application/json", body, nil)
+}
+
+// Condition is a status condition of a custom resource.
+type Condition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"` // "True", "False", or "Unknown"
+	Reason             string `json:"reason"`
+	Message            string `json:"message"`
+	LastTransitionTime string `json:"lastTransitionTime"`
+}
+
+// Resource identifies a namespaced custom resource type.
+type Resource struct {
+	Group    string
+	Version  string
+	Resource string // plural, e.g. "nightcriers"
+}
+
+// PatchStatusConditions replaces status.conditions of the named object through its
+// status subresource. ErrNotFound is returned when the object (or its CRD) does
+// not exist.
+func (c *Client) PatchStatusConditions(ctx context.Context, resource Resource, namespace, name string, conditions []Condition) error {
+	return c.PatchStatus(ctx, resource, namespace, name, map[string]interface{}{"conditions": conditions})
+}
+
+// PatchStatus merges status into the status of the named object through its
+// status subresource. ErrNotFound is returned when the object (or its CRD) does
+// not exist.
+func (c *Client) PatchStatus(ctx context.Context, resource Resource, namespace, name string, status interface{}) error {
+	path := resource.path(namespace) + "/" + url.PathEscape(name) + "/status"
+	body := map[string]interface{}{"status": status}
+	return c.do(ctx, http.MethodPatch, path, "application/merge-patch+json", body, nil)
+}
+
+// List decodes the list of objects of resource in namespace into out, e.g. a
+// struct with an Items slice. ErrNotFound is returned when the CRD is not installed.
+func (c *Client) List(ctx context.Context, resource Resource, namespace string, out interface{}) error {
+	return c.do(ctx, http.MethodGet, resource.path(namespace), "", nil, out)
+}
+
+// Create creates obj, a complete object with apiVersion, kind, and metadata, in
+// namespace. ErrAlreadyExists is returned when an object of that name exists.
+func (c *Client) Create(ctx context.Context, resource Resource, namespace string, obj interface{}) error {
+	return c.do(ctx, http.MethodPost, resource.path(namespace), "application/json", obj, nil)
+}
+
+// path returns the collection path of the resource in namespace.
+func (r Resource) path(namespace string) string {
+	return fmt.Sprintf("/apis/%s/%s/namespaces/%s/%s", r.Group, r.Version, url.PathEscape(namespace), r.Resource)
+}
+
+// do sends a JSON request, checks the response status, and decodes the response
+// into out when it is not nil.
+func (c *Client) do(ctx context.Context, method, path, contentType string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != nil {
+		token, err := c.token()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Kubernetes API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode == http.StatusConflict && method == http.MethodPost:
+		return ErrAlreadyExists
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kubernetes API returned status %d for %s %s: %s", resp.StatusCode, method, path, strings.TrimSpace(string(detail)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", path, err)
+		}
+	}
+	return nil
+}
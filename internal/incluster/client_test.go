@@ -0,0 +1,128 @@
+package incluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateEvent(t *testing.T) {
+	var gotPath, gotAuth string
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, func() (string, error) { return "sa-token", nil }, nil)
+	err := client.CreateEvent(context.Background(), "monitoring", Event{
+		InvolvedObject: ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "monitoring", Name: "nightcrier-0"},
+		Type:           EventTypeWarning,
+		Reason:         "CircuitBreakerOpen",
+		Message:        "3 consecutive agent failures",
+		Component:      "nightcrier",
+		Instance:       "nightcrier-0",
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent() error = %v", err)
+	}
+
+	if gotPath != "/api/v1/namespaces/monitoring/events" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotAuth != "Bearer sa-token" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	involved, _ := got["involvedObject"].(map[string]interface{})
+	if got["reason"] != "CircuitBreakerOpen" || got["type"] != "Warning" || involved["name"] != "nightcrier-0" {
+		t.Errorf("event = %v", got)
+	}
+}
+
+func TestPatchStatusConditions(t *testing.T) {
+	var gotMethod, gotPath, gotContentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotContentType = r.Method, r.URL.Path, r.Header.Get("Content-Type")
+		if r.URL.Path == "/apis/nightcrier.io/v1alpha1/namespaces/monitoring/nightcriers/missing/status" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil, nil)
+	resource := Resource{Group: "nightcrier.io", Version: "v1alpha1", Resource: "nightcriers"}
+	conditions := []Condition{{Type: "AgentHealthy", Status: "False", Reason: "CircuitBreakerOpen"}}
+
+	if err := client.PatchStatusConditions(context.Background(), resource, "monitoring", "nightcrier", conditions); err != nil {
+		t.Fatalf("PatchStatusConditions() error = %v", err)
+	}
+	if gotMethod != http.MethodPatch || gotPath != "/apis/nightcrier.io/v1alpha1/namespaces/monitoring/nightcriers/nightcrier/status" || gotContentType != "application/merge-patch+json" {
+		t.Errorf("request = %s %s (%s)", gotMethod, gotPath, gotContentType)
+	}
+
+	err := client.PatchStatusConditions(context.Background(), resource, "monitoring", "missing", conditions)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing resource error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListAndCreate(t *testing.T) {
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/nightcrier.io/v1alpha1/namespaces/monitoring/incidents" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"items": [{"metadata": {"name": "nc-0001"}}]}`))
+		case http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &created)
+			if created["metadata"].(map[string]interface{})["name"] == "nc-0001" {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil, nil)
+	resource := Resource{Group: "nightcrier.io", Version: "v1alpha1", Resource: "incidents"}
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := client.List(context.Background(), resource, "monitoring", &list); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Metadata.Name != "nc-0001" {
+		t.Errorf("items = %+v", list.Items)
+	}
+
+	obj := map[string]interface{}{"metadata": map[string]interface{}{"name": "nc-0002"}}
+	if err := client.Create(context.Background(), resource, "monitoring", obj); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	obj = map[string]interface{}{"metadata": map[string]interface{}{"name": "nc-0001"}}
+	if err := client.Create(context.Background(), resource, "monitoring", obj); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("duplicate Create() error = %v, want ErrAlreadyExists", err)
+	}
+
+	other := Resource{Group: "nightcrier.io", Version: "v1alpha1", Resource: "clustertargets"}
+	if err := client.List(context.Background(), other, "monitoring", &list); !errors.Is(err, ErrNotFound) {
+		t.Errorf("uninstalled CRD error = %v, want ErrNotFound", err)
+	}
+}
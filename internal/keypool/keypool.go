@@ -0,0 +1,302 @@
+// Package keypool manages multiple LLM API keys per provider. Keys are handed out
+// round-robin so load is spread across them; a key that is rate-limited is benched
+// for a cooldown period, and a key that is rejected as invalid or revoked is taken
+// out of rotation until restart. Investigations fail over to the next usable key.
+package keypool
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Providers whose keys are managed by the pool.
+const (
+	ProviderAnthropic = "anthropic"
+	ProviderOpenAI    = "openai"
+	ProviderGemini    = "gemini"
+)
+
+// envVars maps each provider to the environment variable the agent CLI reads its key from.
+var envVars = map[string]string{
+	ProviderAnthropic: "ANTHROPIC_API_KEY",
+	ProviderOpenAI:    "OPENAI_API_KEY",
+	ProviderGemini:    "GEMINI_API_KEY",
+}
+
+// EnvVar returns the environment variable holding the API key for a provider.
+func EnvVar(provider string) string {
+	return envVars[provider]
+}
+
+// ProviderForAgent returns the provider whose key the given agent CLI uses, or ""
+// when the agent can use several providers (goose) and rotation does not apply.
+func ProviderForAgent(agentCLI string) string {
+	switch agentCLI {
+	case "claude":
+		return ProviderAnthropic
+	case "codex":
+		return ProviderOpenAI
+	case "gemini":
+		return ProviderGemini
+	}
+	return ""
+}
+
+// Status is the health of an API key.
+type Status string
+
+const (
+	// StatusHealthy keys are in rotation
+	StatusHealthy Status = "healthy"
+	// StatusRateLimited keys are benched until their cooldown ends
+	StatusRateLimited Status = "rate_limited"
+	// StatusInvalid keys were rejected (revoked, expired, or out of quota) and are not used again
+	StatusInvalid Status = "invalid"
+)
+
+// Key is an API key handed out for one agent run.
+type Key struct {
+	Provider string
+	// ID identifies the key in logs and audit records without revealing it,
+	// e.g. "anthropic-2:3f9a1c0d".
+	ID    string
+	Value string
+}
+
+// Env returns the environment assignment that makes the agent use this key.
+func (k Key) Env() string {
+	return EnvVar(k.Provider) + "=" + k.Value
+}
+
+// KeyHealth is a snapshot of one key's health.
+type KeyHealth struct {
+	Provider      string    `json:"provider"`
+	ID            string    `json:"id"`
+	Status        Status    `json:"status"`
+	CooldownUntil time.Time `json:"cooldown_until,omitempty"`
+	Uses          int       `json:"uses"`
+	Failures      int       `json:"failures"`
+	LastError     string    `json:"last_error,omitempty"`
+}
+
+// keyState tracks one key.
+type keyState struct {
+	key    Key
+	health KeyHealth
+}
+
+// Pool hands out API keys per provider. It is safe for concurrent use.
+type Pool struct {
+	mu       sync.Mutex
+	keys     map[string][]*keyState
+	next     map[string]int
+	cooldown time.Duration
+
+	// now is replaceable for tests
+	now func() time.Time
+}
+
+// New creates a pool from the configured keys per provider. Duplicate and empty
+// keys are ignored. cooldown is how long a rate-limited key is benched.
+func New(keys map[string][]string, cooldown time.Duration) *Pool {
+	p := &Pool{
+		keys:     make(map[string][]*keyState),
+		next:     make(map[string]int),
+		cooldown: cooldown,
+		now:      time.Now,
+	}
+	for provider, values := range keys {
+		seen := make(map[string]bool)
+		for _, value := range values {
+			if value == "" || seen[value] {
+				continue
+			}
+			seen[value] = true
+			id := fmt.Sprintf("%s-%d:%s", provider, len(p.keys[provider])+1, fingerprint(value))
+			p.keys[provider] = append(p.keys[provider], &keyState{
+				key:    Key{Provider: provider, ID: id, Value: value},
+				health: KeyHealth{Provider: provider, ID: id, Status: StatusHealthy},
+			})
+		}
+	}
+	return p
+}
+
+// fingerprint returns a short, non-reversible identifier for a key.
+func fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Len returns the number of keys configured for a provider.
+func (p *Pool) Len(provider string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.keys[provider])
+}
+
+// Healthy returns the number of keys for a provider that are currently in rotation.
+func (p *Pool) Healthy(provider string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	n := 0
+	for _, s := range p.keys[provider] {
+		if s.health.Status == StatusHealthy || (s.health.Status == StatusRateLimited && !now.Before(s.health.CooldownUntil)) {
+			n++
+		}
+	}
+	return n
+}
+
+// Usable returns the number of keys for a provider that have not been rejected as
+// invalid, including rate-limited keys still in their cooldown.
+func (p *Pool) Usable(provider string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, s := range p.keys[provider] {
+		if s.health.Status != StatusInvalid {
+			n++
+		}
+	}
+	return n
+}
+
+// Select returns the next usable key for a provider, rotating round-robin over
+// healthy keys. When every key is rate-limited, the one whose cooldown ends first
+// is returned. It returns an error when the provider has no keys or all of them
+// are invalid.
+func (p *Pool) Select(provider string) (Key, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	states := p.keys[provider]
+	if len(states) == 0 {
+		return Key{}, fmt.Errorf("no API keys configured for provider %s", provider)
+	}
+
+	now := p.now()
+	var soonest *keyState
+	for i := 0; i < len(states); i++ {
+		idx := (p.next[provider] + i) % len(states)
+		s := states[idx]
+		if s.health.Status == StatusRateLimited && !now.Before(s.health.CooldownUntil) {
+			s.health.Status = StatusHealthy
+			s.health.CooldownUntil = time.Time{}
+		}
+		switch s.health.Status {
+		case StatusHealthy:
+			p.next[provider] = idx + 1
+			s.health.Uses++
+			return s.key, nil
+		case StatusRateLimited:
+			if soonest == nil || s.health.CooldownUntil.Before(soonest.health.CooldownUntil) {
+				soonest = s
+			}
+		}
+	}
+	if soonest != nil {
+		soonest.health.Uses++
+		return soonest.key, nil
+	}
+	return Key{}, fmt.Errorf("all %d API keys for provider %s are invalid", len(states), provider)
+}
+
+// Report records the outcome of an agent run that used key.
+func (p *Pool) Report(key Key, outcome Outcome, detail string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := p.find(key)
+	if s == nil {
+		return
+	}
+	switch outcome {
+	case OutcomeRateLimited:
+		s.health.Status = StatusRateLimited
+		s.health.CooldownUntil = p.now().Add(p.cooldown)
+		s.health.Failures++
+		s.health.LastError = detail
+		slog.Warn("API key rate-limited, benching it",
+			"provider", key.Provider,
+			"key_id", key.ID,
+			"cooldown", p.cooldown,
+			"detail", detail)
+	case OutcomeInvalid:
+		s.health.Status = StatusInvalid
+		s.health.Failures++
+		s.health.LastError = detail
+		slog.Error("API key rejected, removing it from rotation",
+			"provider", key.Provider,
+			"key_id", key.ID,
+			"detail", detail)
+	}
+}
+
+// find returns the state for key. Caller holds mu.
+func (p *Pool) find(key Key) *keyState {
+	for _, s := range p.keys[key.Provider] {
+		if s.key.ID == key.ID {
+			return s
+		}
+	}
+	return nil
+}
+
+// Health returns a snapshot of every key's health, ordered by provider and ID.
+func (p *Pool) Health() []KeyHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var out []KeyHealth
+	for _, states := range p.keys {
+		for _, s := range states {
+			out = append(out, s.health)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Provider != out[j].Provider {
+			return out[i].Provider < out[j].Provider
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// Outcome classifies how an agent run went with respect to its API key.
+type Outcome int
+
+const (
+	// OutcomeOK means no key problem was detected
+	OutcomeOK Outcome = iota
+	// OutcomeRateLimited means the provider throttled the key
+	OutcomeRateLimited
+	// OutcomeInvalid means the provider rejected the key
+	OutcomeInvalid
+)
+
+var (
+	// rateLimitPattern matches provider rate-limit errors in agent output
+	rateLimitPattern = regexp.MustCompile(`(?i)rate_limit_error|rate[ -]limit(ed)? (exceeded|reached)|429 too many requests|(status|status[ _]?code|api error)[ :=]*429\b|resource_exhausted`)
+
+	// invalidKeyPattern matches provider authentication errors in agent output
+	invalidKeyPattern = regexp.MustCompile(`(?i)invalid x-api-key|authentication_error|invalid_api_key|incorrect api key|api key not valid|api_key_invalid|insufficient_quota|401 unauthorized|(status|status[ _]?code|api error)[ :=]*401\b`)
+)
+
+// Classify inspects agent output for API key errors. It returns the outcome and
+// the matching text.
+func Classify(output string) (Outcome, string) {
+	if m := invalidKeyPattern.FindString(output); m != "" {
+		return OutcomeInvalid, m
+	}
+	if m := rateLimitPattern.FindString(output); m != "" {
+		return OutcomeRateLimited, m
+	}
+	return OutcomeOK, ""
+}
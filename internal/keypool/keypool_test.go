@@ -0,0 +1,145 @@
+package keypool
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestPool(keys ...string) (*Pool, *time.Time) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	p := New(map[string][]string{ProviderAnthropic: keys}, time.Minute)
+	p.now = func() time.Time { return now }
+	return p, &now
+}
+
+func TestPool_RoundRobin(t *testing.T) {
+	p, _ := newTestPool("key-a", "key-b", "key-a", "")
+
+	if got := p.Len(ProviderAnthropic); got != 2 {
+		t.Fatalf("Len() = %d, want 2 (duplicates and empty keys dropped)", got)
+	}
+
+	var values []string
+	for i := 0; i < 4; i++ {
+		k, err := p.Select(ProviderAnthropic)
+		if err != nil {
+			t.Fatalf("Select() error = %v", err)
+		}
+		values = append(values, k.Value)
+	}
+	if got := strings.Join(values, ","); got != "key-a,key-b,key-a,key-b" {
+		t.Errorf("Select() sequence = %s, want round-robin", got)
+	}
+}
+
+func TestPool_RateLimitedKeyIsBenched(t *testing.T) {
+	p, now := newTestPool("key-a", "key-b")
+
+	a, _ := p.Select(ProviderAnthropic)
+	p.Report(a, OutcomeRateLimited, "rate_limit_error")
+	if got := p.Healthy(ProviderAnthropic); got != 1 {
+		t.Errorf("Healthy() = %d, want 1", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		if k, _ := p.Select(ProviderAnthropic); k.Value != "key-b" {
+			t.Fatalf("Select() = %s, want key-b while key-a cools down", k.Value)
+		}
+	}
+
+	// After the cooldown the key is back in rotation
+	*now = now.Add(2 * time.Minute)
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		k, _ := p.Select(ProviderAnthropic)
+		seen[k.Value] = true
+	}
+	if !seen["key-a"] {
+		t.Error("key-a should return to rotation after its cooldown")
+	}
+}
+
+func TestPool_AllRateLimitedReturnsSoonestCooldown(t *testing.T) {
+	p, now := newTestPool("key-a", "key-b")
+
+	a, _ := p.Select(ProviderAnthropic)
+	b, _ := p.Select(ProviderAnthropic)
+	p.Report(b, OutcomeRateLimited, "429")
+	*now = now.Add(10 * time.Second)
+	p.Report(a, OutcomeRateLimited, "429")
+
+	k, err := p.Select(ProviderAnthropic)
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if k.Value != "key-b" {
+		t.Errorf("Select() = %s, want key-b (earliest cooldown end)", k.Value)
+	}
+}
+
+func TestPool_InvalidKeysRemoved(t *testing.T) {
+	p, _ := newTestPool("key-a", "key-b")
+
+	a, _ := p.Select(ProviderAnthropic)
+	p.Report(a, OutcomeInvalid, "invalid x-api-key")
+	b, _ := p.Select(ProviderAnthropic)
+	if b.Value != "key-b" {
+		t.Fatalf("Select() = %s, want key-b", b.Value)
+	}
+	if n := p.Usable(ProviderAnthropic); n != 1 {
+		t.Errorf("Usable() = %d, want 1", n)
+	}
+	p.Report(b, OutcomeInvalid, "authentication_error")
+	if n := p.Usable(ProviderAnthropic); n != 0 {
+		t.Errorf("Usable() = %d, want 0", n)
+	}
+
+	if _, err := p.Select(ProviderAnthropic); err == nil {
+		t.Error("Select() should fail when every key is invalid")
+	}
+
+	health := p.Health()
+	if len(health) != 2 || health[0].Status != StatusInvalid || health[0].LastError != "invalid x-api-key" {
+		t.Errorf("Health() = %+v", health)
+	}
+}
+
+func TestPool_NoKeys(t *testing.T) {
+	p := New(nil, time.Minute)
+	if _, err := p.Select(ProviderOpenAI); err == nil {
+		t.Error("Select() should fail without keys")
+	}
+}
+
+func TestKey_IDDoesNotRevealKey(t *testing.T) {
+	p, _ := newTestPool("sk-ant-secret-value")
+	k, _ := p.Select(ProviderAnthropic)
+	if strings.Contains(k.ID, "secret") || !strings.HasPrefix(k.ID, "anthropic-1:") {
+		t.Errorf("ID = %q, want fingerprint without key material", k.ID)
+	}
+	if k.Env() != "ANTHROPIC_API_KEY=sk-ant-secret-value" {
+		t.Errorf("Env() = %q", k.Env())
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		output string
+		want   Outcome
+	}{
+		{`API Error: 401 {"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`, OutcomeInvalid},
+		{`Error: Incorrect API key provided: sk-...`, OutcomeInvalid},
+		{`You exceeded your current quota (insufficient_quota)`, OutcomeInvalid},
+		{`API Error: 429 {"type":"error","error":{"type":"rate_limit_error"}}`, OutcomeRateLimited},
+		{`status code: 429`, OutcomeRateLimited},
+		{`RESOURCE_EXHAUSTED: quota exceeded for requests per minute`, OutcomeRateLimited},
+		{`pod web-429 restarted 401 times`, OutcomeOK},
+		{`investigation complete`, OutcomeOK},
+	}
+	for _, tt := range tests {
+		if got, _ := Classify(tt.output); got != tt.want {
+			t.Errorf("Classify(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package knowledgebase
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ConfluenceConfig configures the Confluence publisher.
+type ConfluenceConfig struct {
+	// BaseURL is the Confluence base URL, e.g. "https://example.atlassian.net/wiki"
+	BaseURL string
+
+	// Username and APIToken authenticate with basic auth (Confluence Cloud). When
+	// Username is empty, APIToken is sent as a bearer personal access token
+	// (Confluence Data Center).
+	Username string
+	APIToken string
+
+	// SpaceKey is the space pages are created in
+	SpaceKey string
+
+	// ParentPageID optionally nests pages under an existing page
+	ParentPageID string
+}
+
+// Confluence publishes investigations as Confluence pages.
+type Confluence struct {
+	cfg ConfluenceConfig
+	api *apiClient
+}
+
+// NewConfluence creates a Confluence publisher.
+func NewConfluence(cfg ConfluenceConfig, httpClient *http.Client) *Confluence {
+	auth := "Bearer " + cfg.APIToken
+	if cfg.Username != "" {
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(cfg.Username+":"+cfg.APIToken))
+	}
+	return &Confluence{
+		cfg: cfg,
+		api: &apiClient{
+			baseURL: cfg.BaseURL,
+			headers: map[string]string{"Authorization": auth},
+			client:  defaultHTTPClient(httpClient),
+		},
+	}
+}
+
+// Name implements Publisher.
+func (c *Confluence) Name() string { return "confluence" }
+
+// confluencePage is the subset of the content API response that is used.
+type confluencePage struct {
+	ID      string `json:"id"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+	Links struct {
+		Base  string `json:"base"`
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+// Publish creates the incident's page, or updates it if a page with the same
+// title already exists in the space.
+func (c *Confluence) Publish(ctx context.Context, page Page) (string, error) {
+	title := page.Title()
+
+	var search struct {
+		Results []confluencePage `json:"results"`
+		Links   struct {
+			Base string `json:"base"`
+		} `json:"_links"`
+	}
+	query := url.Values{
+		"spaceKey": {c.cfg.SpaceKey},
+		"title":    {title},
+		"expand":   {"version"},
+	}
+	if err := c.api.do(ctx, http.MethodGet, "/rest/api/content?"+query.Encode(), nil, &search); err != nil {
+		return "", fmt.Errorf("failed to search for existing page: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"type":  "page",
+		"title": title,
+		"space": map[string]string{"key": c.cfg.SpaceKey},
+		"body": map[string]interface{}{
+			"storage": map[string]string{
+				"value":          c.storageBody(page),
+				"representation": "storage",
+			},
+		},
+	}
+	if c.cfg.ParentPageID != "" {
+		body["ancestors"] = []map[string]string{{"id": c.cfg.ParentPageID}}
+	}
+
+	var result confluencePage
+	if len(search.Results) > 0 {
+		existing := search.Results[0]
+		body["version"] = map[string]int{"number": existing.Version.Number + 1}
+		if err := c.api.do(ctx, http.MethodPut, "/rest/api/content/"+existing.ID, body, &result); err != nil {
+			return "", fmt.Errorf("failed to update page %s: %w", existing.ID, err)
+		}
+	} else {
+		if err := c.api.do(ctx, http.MethodPost, "/rest/api/content", body, &result); err != nil {
+			return "", fmt.Errorf("failed to create page: %w", err)
+		}
+	}
+
+	base := result.Links.Base
+	if base == "" {
+		base = strings.TrimSuffix(c.cfg.BaseURL, "/")
+	}
+	return base + result.Links.WebUI, nil
+}
+
+// storageBody renders the page body in Confluence storage format: a metadata
+// table followed by the investigation report.
+func (c *Confluence) storageBody(page Page) string {
+	var b strings.Builder
+	b.WriteString("<table><tbody>")
+	row := func(label, value string) {
+		fmt.Fprintf(&b, "<tr><th>%s</th><td>%s</td></tr>", label, html.EscapeString(value))
+	}
+	row("Incident ID", page.IncidentID)
+	row("Cluster", page.Cluster)
+	row("Namespace", page.Namespace)
+	row("Resource", page.Resource)
+	row("Fault", page.FaultType)
+	row("Severity", page.Severity)
+	row("Status", page.Status)
+	row("Confidence", page.Confidence)
+	row("Root Cause", page.RootCause)
+	if !page.CompletedAt.IsZero() {
+		row("Completed", page.CompletedAt.UTC().Format("2006-01-02 15:04:05 MST"))
+	}
+	if page.ReportURL != "" {
+		fmt.Fprintf(&b, `<tr><th>Report</th><td><a href="%s">%s</a></td></tr>`, html.EscapeString(page.ReportURL), html.EscapeString(page.ReportURL))
+	}
+	b.WriteString("</tbody></table>")
+	b.WriteString(page.HTML)
+	return b.String()
+}
@@ -0,0 +1,115 @@
+// Package knowledgebase publishes completed investigations to the knowledge bases
+// where organizations keep their incident history: a Confluence page or a Notion
+// database entry per incident. Publishing is idempotent per incident; publishing
+// the same incident again updates the existing page instead of creating another.
+package knowledgebase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Page is an investigation to publish.
+type Page struct {
+	IncidentID  string
+	Cluster     string
+	Namespace   string
+	Resource    string
+	FaultType   string
+	Severity    string
+	Status      string
+	RootCause   string
+	Confidence  string
+	ReportURL   string
+	CompletedAt time.Time
+
+	// Markdown is the investigation report; HTML is the same report rendered as an
+	// XHTML fragment for targets that take HTML
+	Markdown string
+	HTML     string
+}
+
+// Title returns the page title, which includes the incident ID so that it is
+// unique and can be used to find the page again.
+func (p Page) Title() string {
+	return fmt.Sprintf("Incident %s: %s on %s/%s", shortID(p.IncidentID), p.FaultType, p.Cluster, p.Resource)
+}
+
+// Publisher creates or updates the knowledge base entry for an incident.
+type Publisher interface {
+	// Name identifies the publisher in logs and on the incident, e.g. "confluence"
+	Name() string
+
+	// Publish creates or updates the entry and returns its URL
+	Publish(ctx context.Context, page Page) (string, error)
+}
+
+// shortID returns the first 8 characters of an incident ID.
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// apiClient sends JSON requests to a knowledge base API.
+type apiClient struct {
+	baseURL string
+	headers map[string]string
+	client  *http.Client
+}
+
+// do sends a JSON request and decodes a JSON response into out.
+func (c *apiClient) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.baseURL, "/")+endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned status %d: %s", method, endpoint, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// defaultHTTPClient returns the HTTP client used when none is provided.
+func defaultHTTPClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
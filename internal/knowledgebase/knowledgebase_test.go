@@ -0,0 +1,194 @@
+package knowledgebase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testPage() Page {
+	return Page{
+		IncidentID:  "3f9a1c0d-1111-2222-3333-444455556666",
+		Cluster:     "prod-east",
+		Namespace:   "payments",
+		Resource:    "Pod/api-7d9f",
+		FaultType:   "CrashLoopBackOff",
+		Severity:    "ERROR",
+		Status:      "resolved",
+		RootCause:   "Missing DATABASE_URL secret",
+		Confidence:  "HIGH",
+		ReportURL:   "https://reports.example.com/3f9a1c0d",
+		CompletedAt: time.Date(2025, 3, 1, 23, 30, 0, 0, time.UTC),
+		Markdown:    "# Investigation\n\nThe pod is crashing.\n\n## Root Cause\n\n- missing secret\n1. add the secret\n\n```sh\nkubectl get pods\n```\n",
+		HTML:        "<h1>Investigation</h1>",
+	}
+}
+
+func TestConfluence_CreatesPage(t *testing.T) {
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "sre@example.com" || pass != "token" {
+			t.Errorf("basic auth = %q %q %v", user, pass, ok)
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/wiki/rest/api/content":
+			if r.URL.Query().Get("spaceKey") != "SRE" || !strings.Contains(r.URL.Query().Get("title"), "3f9a1c0d") {
+				t.Errorf("search query = %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"results":[]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/wiki/rest/api/content":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Write([]byte(`{"id":"100","_links":{"base":"https://example.atlassian.net/wiki","webui":"/spaces/SRE/pages/100"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := NewConfluence(ConfluenceConfig{
+		BaseURL:      server.URL + "/wiki",
+		Username:     "sre@example.com",
+		APIToken:     "token",
+		SpaceKey:     "SRE",
+		ParentPageID: "42",
+	}, server.Client())
+	url, err := c.Publish(context.Background(), testPage())
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if url != "https://example.atlassian.net/wiki/spaces/SRE/pages/100" {
+		t.Errorf("url = %q", url)
+	}
+
+	ancestors, _ := created["ancestors"].([]interface{})
+	if len(ancestors) != 1 || ancestors[0].(map[string]interface{})["id"] != "42" {
+		t.Errorf("ancestors = %v", created["ancestors"])
+	}
+	storage := created["body"].(map[string]interface{})["storage"].(map[string]interface{})["value"].(string)
+	for _, want := range []string{"Missing DATABASE_URL secret", "<h1>Investigation</h1>", "prod-east"} {
+		if !strings.Contains(storage, want) {
+			t.Errorf("storage body missing %q: %s", want, storage)
+		}
+	}
+}
+
+func TestConfluence_UpdatesExistingPage(t *testing.T) {
+	var version float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer pat" {
+			t.Errorf("Authorization = %q", got)
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/content":
+			w.Write([]byte(`{"results":[{"id":"100","version":{"number":3}}]}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/rest/api/content/100":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			version = body["version"].(map[string]interface{})["number"].(float64)
+			w.Write([]byte(`{"id":"100","_links":{"webui":"/display/SRE/page"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := NewConfluence(ConfluenceConfig{BaseURL: server.URL, APIToken: "pat", SpaceKey: "SRE"}, server.Client())
+	url, err := c.Publish(context.Background(), testPage())
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if version != 4 {
+		t.Errorf("version = %v, want 4", version)
+	}
+	if url != server.URL+"/display/SRE/page" {
+		t.Errorf("url = %q", url)
+	}
+}
+
+func TestNotion_CreatesEntry(t *testing.T) {
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Notion-Version") == "" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/databases/db/query":
+			w.Write([]byte(`{"results":[]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/pages":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Write([]byte(`{"id":"page-1","url":"https://www.notion.so/page-1"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	n := NewNotion(NotionConfig{APIURL: server.URL, Token: "secret", DatabaseID: "db"}, server.Client())
+	url, err := n.Publish(context.Background(), testPage())
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if url != "https://www.notion.so/page-1" {
+		t.Errorf("url = %q", url)
+	}
+
+	props := created["properties"].(map[string]interface{})
+	severity := props["Severity"].(map[string]interface{})["select"].(map[string]interface{})["name"]
+	if severity != "ERROR" {
+		t.Errorf("Severity = %v", severity)
+	}
+	var types []string
+	for _, b := range created["children"].([]interface{}) {
+		types = append(types, b.(map[string]interface{})["type"].(string))
+	}
+	want := "heading_1 paragraph heading_2 bulleted_list_item numbered_list_item code"
+	if got := strings.Join(types, " "); got != want {
+		t.Errorf("blocks = %q, want %q", got, want)
+	}
+}
+
+func TestNotion_UpdatesExistingEntry(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/databases/db/query":
+			w.Write([]byte(`{"results":[{"id":"page-1","url":"https://www.notion.so/page-1"}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/blocks/page-1/children":
+			w.Write([]byte(`{"results":[{"id":"old-1"}],"has_more":false}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	n := NewNotion(NotionConfig{APIURL: server.URL, Token: "secret", DatabaseID: "db"}, server.Client())
+	if _, err := n.Publish(context.Background(), testPage()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	want := []string{
+		"POST /v1/databases/db/query",
+		"PATCH /v1/pages/page-1",
+		"GET /v1/blocks/page-1/children",
+		"DELETE /v1/blocks/old-1",
+		"PATCH /v1/blocks/page-1/children",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRichText_SplitsLongText(t *testing.T) {
+	parts := richText(strings.Repeat("a", notionTextLimit*2+1))
+	if len(parts) != 3 {
+		t.Errorf("len(richText) = %d, want 3", len(parts))
+	}
+}
@@ -0,0 +1,291 @@
+package knowledgebase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// DefaultNotionAPIURL is the Notion API base URL
+	DefaultNotionAPIURL = "https://api.notion.com"
+
+	// notionVersion is the Notion API version the requests are written against
+	notionVersion = "2022-06-28"
+
+	// notionTextLimit is the maximum length of one Notion rich text object
+	notionTextLimit = 2000
+
+	// notionBlocksPerRequest is the maximum number of blocks appended per request
+	notionBlocksPerRequest = 100
+)
+
+// NotionConfig configures the Notion publisher.
+//
+// The database must have these properties: "Name" (title), "Incident ID",
+// "Cluster", "Namespace", "Resource", and "Root Cause" (text), "Fault Type",
+// "Severity", "Status", and "Confidence" (select), "Report URL" (URL), and
+// "Completed" (date).
+type NotionConfig struct {
+	// APIURL is the Notion API base URL
+	APIURL string
+
+	// Token is the integration token; the integration must be shared with the database
+	Token string
+
+	// DatabaseID is the database incident entries are created in
+	DatabaseID string
+}
+
+// Notion publishes investigations as Notion database entries.
+type Notion struct {
+	cfg NotionConfig
+	api *apiClient
+}
+
+// NewNotion creates a Notion publisher.
+func NewNotion(cfg NotionConfig, httpClient *http.Client) *Notion {
+	if cfg.APIURL == "" {
+		cfg.APIURL = DefaultNotionAPIURL
+	}
+	return &Notion{
+		cfg: cfg,
+		api: &apiClient{
+			baseURL: cfg.APIURL,
+			headers: map[string]string{
+				"Authorization":  "Bearer " + cfg.Token,
+				"Notion-Version": notionVersion,
+			},
+			client: defaultHTTPClient(httpClient),
+		},
+	}
+}
+
+// Name implements Publisher.
+func (n *Notion) Name() string { return "notion" }
+
+// notionPage is the subset of the pages API response that is used.
+type notionPage struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// Publish creates the incident's database entry, or updates the entry with the
+// same Incident ID, replacing its content.
+func (n *Notion) Publish(ctx context.Context, page Page) (string, error) {
+	var query struct {
+		Results []notionPage `json:"results"`
+	}
+	filter := map[string]interface{}{
+		"filter": map[string]interface{}{
+			"property":  "Incident ID",
+			"rich_text": map[string]string{"equals": page.IncidentID},
+		},
+	}
+	if err := n.api.do(ctx, http.MethodPost, "/v1/databases/"+n.cfg.DatabaseID+"/query", filter, &query); err != nil {
+		return "", fmt.Errorf("failed to query database: %w", err)
+	}
+
+	blocks := markdownBlocks(page.Markdown)
+	if len(query.Results) == 0 {
+		first := blocks
+		if len(first) > notionBlocksPerRequest {
+			first = first[:notionBlocksPerRequest]
+		}
+		body := map[string]interface{}{
+			"parent":     map[string]string{"database_id": n.cfg.DatabaseID},
+			"properties": n.properties(page),
+			"children":   first,
+		}
+		var created notionPage
+		if err := n.api.do(ctx, http.MethodPost, "/v1/pages", body, &created); err != nil {
+			return "", fmt.Errorf("failed to create page: %w", err)
+		}
+		if err := n.appendBlocks(ctx, created.ID, blocks[len(first):]); err != nil {
+			return "", err
+		}
+		return created.URL, nil
+	}
+
+	existing := query.Results[0]
+	if err := n.api.do(ctx, http.MethodPatch, "/v1/pages/"+existing.ID, map[string]interface{}{"properties": n.properties(page)}, nil); err != nil {
+		return "", fmt.Errorf("failed to update page %s: %w", existing.ID, err)
+	}
+	if err := n.clearBlocks(ctx, existing.ID); err != nil {
+		return "", err
+	}
+	if err := n.appendBlocks(ctx, existing.ID, blocks); err != nil {
+		return "", err
+	}
+	return existing.URL, nil
+}
+
+// clearBlocks deletes the content blocks of a page.
+func (n *Notion) clearBlocks(ctx context.Context, pageID string) error {
+	for {
+		var children struct {
+			Results []struct {
+				ID string `json:"id"`
+			} `json:"results"`
+			HasMore bool `json:"has_more"`
+		}
+		if err := n.api.do(ctx, http.MethodGet, "/v1/blocks/"+pageID+"/children?page_size=100", nil, &children); err != nil {
+			return fmt.Errorf("failed to list page content: %w", err)
+		}
+		for _, child := range children.Results {
+			if err := n.api.do(ctx, http.MethodDelete, "/v1/blocks/"+child.ID, nil, nil); err != nil {
+				return fmt.Errorf("failed to delete page content: %w", err)
+			}
+		}
+		if !children.HasMore || len(children.Results) == 0 {
+			return nil
+		}
+	}
+}
+
+// appendBlocks appends content blocks to a page in batches.
+func (n *Notion) appendBlocks(ctx context.Context, pageID string, blocks []map[string]interface{}) error {
+	for len(blocks) > 0 {
+		batch := blocks
+		if len(batch) > notionBlocksPerRequest {
+			batch = batch[:notionBlocksPerRequest]
+		}
+		if err := n.api.do(ctx, http.MethodPatch, "/v1/blocks/"+pageID+"/children", map[string]interface{}{"children": batch}, nil); err != nil {
+			return fmt.Errorf("failed to append page content: %w", err)
+		}
+		blocks = blocks[len(batch):]
+	}
+	return nil
+}
+
+// properties returns the database properties for a page.
+func (n *Notion) properties(page Page) map[string]interface{} {
+	props := map[string]interface{}{
+		"Name":        map[string]interface{}{"title": richText(page.Title())},
+		"Incident ID": map[string]interface{}{"rich_text": richText(page.IncidentID)},
+		"Cluster":     map[string]interface{}{"rich_text": richText(page.Cluster)},
+		"Namespace":   map[string]interface{}{"rich_text": richText(page.Namespace)},
+		"Resource":    map[string]interface{}{"rich_text": richText(page.Resource)},
+		"Root Cause":  map[string]interface{}{"rich_text": richText(page.RootCause)},
+		"Fault Type":  selectValue(page.FaultType),
+		"Severity":    selectValue(page.Severity),
+		"Status":      selectValue(page.Status),
+		"Confidence":  selectValue(page.Confidence),
+	}
+	if page.ReportURL != "" {
+		props["Report URL"] = map[string]interface{}{"url": page.ReportURL}
+	}
+	if !page.CompletedAt.IsZero() {
+		props["Completed"] = map[string]interface{}{"date": map[string]string{"start": page.CompletedAt.UTC().Format("2006-01-02T15:04:05Z")}}
+	}
+	return props
+}
+
+// selectValue returns a select property value; empty values clear the property.
+func selectValue(value string) map[string]interface{} {
+	if value == "" {
+		return map[string]interface{}{"select": nil}
+	}
+	// Select option names cannot contain commas
+	return map[string]interface{}{"select": map[string]string{"name": strings.ReplaceAll(value, ",", " ")}}
+}
+
+// richText splits text into Notion rich text objects within the length limit.
+func richText(text string) []map[string]interface{} {
+	var out []map[string]interface{}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		n := len(runes)
+		if n > notionTextLimit {
+			n = notionTextLimit
+		}
+		out = append(out, map[string]interface{}{
+			"type": "text",
+			"text": map[string]string{"content": string(runes[:n])},
+		})
+		runes = runes[n:]
+	}
+	if out == nil {
+		out = []map[string]interface{}{}
+	}
+	return out
+}
+
+// block returns a Notion block of the given type with rich text content.
+func block(blockType, text string) map[string]interface{} {
+	return map[string]interface{}{
+		"object":  "block",
+		"type":    blockType,
+		blockType: map[string]interface{}{"rich_text": richText(text)},
+	}
+}
+
+// markdownBlocks converts markdown to Notion blocks. It handles headings, bullet
+// and numbered lists, fenced code, horizontal rules, and paragraphs; inline
+// formatting is kept as plain text.
+func markdownBlocks(md string) []map[string]interface{} {
+	var blocks []map[string]interface{}
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, block("paragraph", strings.Join(paragraph, " ")))
+			paragraph = nil
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			language := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			b := block("code", strings.Join(code, "\n"))
+			if language == "" {
+				language = "plain text"
+			}
+			b["code"].(map[string]interface{})["language"] = language
+			blocks = append(blocks, b)
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "### "):
+			flush()
+			blocks = append(blocks, block("heading_3", strings.TrimPrefix(trimmed, "### ")))
+		case strings.HasPrefix(trimmed, "## "):
+			flush()
+			blocks = append(blocks, block("heading_2", strings.TrimPrefix(trimmed, "## ")))
+		case strings.HasPrefix(trimmed, "# "):
+			flush()
+			blocks = append(blocks, block("heading_1", strings.TrimPrefix(trimmed, "# ")))
+		case trimmed == "---" || trimmed == "***":
+			flush()
+			blocks = append(blocks, map[string]interface{}{"object": "block", "type": "divider", "divider": map[string]interface{}{}})
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flush()
+			blocks = append(blocks, block("bulleted_list_item", trimmed[2:]))
+		case isNumberedItem(trimmed):
+			flush()
+			blocks = append(blocks, block("numbered_list_item", trimmed[strings.Index(trimmed, ". ")+2:]))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// isNumberedItem reports whether a line starts a numbered list item ("1. ").
+func isNumberedItem(line string) bool {
+	i := 0
+	for i < len(line) && line[i] >= '0' && line[i] <= '9' {
+		i++
+	}
+	return i > 0 && strings.HasPrefix(line[i:], ". ")
+}
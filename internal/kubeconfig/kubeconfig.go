@@ -0,0 +1,84 @@
+// Package kubeconfig reads the parts of a kubeconfig file nightcrier needs
+// without a Kubernetes client library: the API servers of its clusters and the
+// credentials of its users.
+package kubeconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Config is a parsed kubeconfig file.
+type Config struct {
+	Clusters []NamedCluster `yaml:"clusters"`
+	Users    []NamedUser    `yaml:"users"`
+
+	// dir is the directory of the kubeconfig file; relative paths in it are
+	// resolved against it
+	dir string
+}
+
+// NamedCluster is a cluster entry of a kubeconfig.
+type NamedCluster struct {
+	Name    string  `yaml:"name"`
+	Cluster Cluster `yaml:"cluster"`
+}
+
+// Cluster is the connection information of a cluster.
+type Cluster struct {
+	Server string `yaml:"server"`
+}
+
+// NamedUser is a user entry of a kubeconfig.
+type NamedUser struct {
+	Name string   `yaml:"name"`
+	User AuthInfo `yaml:"user"`
+}
+
+// AuthInfo is the credentials of a user. Exec and AuthProvider are only checked
+// for presence: their credentials are fetched by a plugin when kubectl runs.
+type AuthInfo struct {
+	ClientCertificate     string         `yaml:"client-certificate"`
+	ClientCertificateData string         `yaml:"client-certificate-data"`
+	Token                 string         `yaml:"token"`
+	TokenFile             string         `yaml:"tokenFile"`
+	Exec                  map[string]any `yaml:"exec"`
+	AuthProvider          map[string]any `yaml:"auth-provider"`
+}
+
+// Path resolves a path found in the kubeconfig: relative paths are relative to
+// the kubeconfig file, as kubectl resolves them.
+func (c *Config) Path(p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(c.dir, p)
+}
+
+// Load reads and parses the kubeconfig file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+	cfg := Config{dir: filepath.Dir(path)}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Servers returns the API server URLs of all clusters in the kubeconfig, in file
+// order. Clusters without a server are skipped.
+func (c *Config) Servers() []string {
+	var servers []string
+	for _, cl := range c.Clusters {
+		if cl.Cluster.Server != "" {
+			servers = append(servers, cl.Cluster.Server)
+		}
+	}
+	return servers
+}
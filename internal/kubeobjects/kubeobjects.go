@@ -0,0 +1,108 @@
+// Package kubeobjects reads Kubernetes objects through a cluster's MCP server
+// (the kubernetes-mcp-server resources_get tool), for the packages that look at
+// live objects (ownership annotations, claim verification), so they address and
+// decode objects the same way.
+package kubeobjects
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.yaml.in/yaml/v3"
+)
+
+// ResourcesGetTool is the kubernetes-mcp-server tool returning a Kubernetes object
+const ResourcesGetTool = "resources_get"
+
+// Ref identifies a Kubernetes object. An empty APIVersion is derived from the
+// kind with APIVersionOf.
+type Ref struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+// String returns "Kind namespace/name", or "Kind name" for cluster-scoped objects.
+func (r Ref) String() string {
+	if r.Namespace == "" {
+		return r.Kind + " " + r.Name
+	}
+	return r.Kind + " " + r.Namespace + "/" + r.Name
+}
+
+// APIVersionOf returns the API version of common kinds, "v1" for the others.
+func APIVersionOf(kind string) string {
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet":
+		return "apps/v1"
+	case "Job", "CronJob":
+		return "batch/v1"
+	case "Ingress", "NetworkPolicy":
+		return "networking.k8s.io/v1"
+	case "HorizontalPodAutoscaler":
+		return "autoscaling/v2"
+	}
+	return "v1"
+}
+
+// Reader reads objects over an MCP session.
+type Reader struct {
+	session *mcp.ClientSession
+}
+
+// Connect connects to the MCP server at endpoint, introducing itself as
+// clientName. A nil httpClient uses http.DefaultClient.
+func Connect(ctx context.Context, endpoint, clientName string, httpClient *http.Client) (*Reader, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: clientName, Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{
+		Endpoint:   endpoint,
+		HTTPClient: httpClient,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
+	}
+	return &Reader{session: session}, nil
+}
+
+// Close closes the MCP session.
+func (r *Reader) Close() {
+	r.session.Close()
+}
+
+// Get reads an object and decodes it into out. It returns false when the object
+// cannot be read (e.g. it does not exist or the MCP server may not read it).
+func (r *Reader) Get(ctx context.Context, ref Ref, out any) (bool, error) {
+	apiVersion := ref.APIVersion
+	if apiVersion == "" {
+		apiVersion = APIVersionOf(ref.Kind)
+	}
+	args := map[string]any{"apiVersion": apiVersion, "kind": ref.Kind, "name": ref.Name}
+	if ref.Namespace != "" {
+		args["namespace"] = ref.Namespace
+	}
+	result, err := r.session.CallTool(ctx, &mcp.CallToolParams{Name: ResourcesGetTool, Arguments: args})
+	if err != nil {
+		return false, fmt.Errorf("failed to call %s: %w", ResourcesGetTool, err)
+	}
+	if result.IsError {
+		return false, nil
+	}
+
+	var text strings.Builder
+	for _, content := range result.Content {
+		if textContent, ok := content.(*mcp.TextContent); ok {
+			text.WriteString(textContent.Text)
+		}
+	}
+	if err := yaml.Unmarshal([]byte(text.String()), out); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", ref, err)
+	}
+	return true, nil
+}
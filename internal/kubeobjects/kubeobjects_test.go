@@ -0,0 +1,77 @@
+package kubeobjects
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// resourcesGetArgs are the arguments of the resources_get tool
+type resourcesGetArgs struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// newFakeMCPServer serves resources_get from objects, keyed by
+// "apiVersion/kind/name", and records the requested namespaces.
+func newFakeMCPServer(t *testing.T, objects map[string]string, namespaces *[]string) *httptest.Server {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "fake-kubernetes-mcp-server", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: ResourcesGetTool}, func(ctx context.Context, req *mcp.CallToolRequest, args resourcesGetArgs) (*mcp.CallToolResult, any, error) {
+		*namespaces = append(*namespaces, args.Namespace)
+		object, ok := objects[args.APIVersion+"/"+args.Kind+"/"+args.Name]
+		if !ok {
+			return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "not found"}}}, nil, nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: object}}}, nil, nil
+	})
+	srv := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestReader_Get(t *testing.T) {
+	var namespaces []string
+	srv := newFakeMCPServer(t, map[string]string{
+		"apps/v1/Deployment/checkout":             "metadata:\n  name: checkout\n  labels:\n    team: payments\n",
+		"networking.k8s.io/v1/Ingress/storefront": "metadata:\n  name: storefront\n",
+		"v1/Pod/broken":                           "metadata: [",
+	}, &namespaces)
+	objects, err := Connect(context.Background(), srv.URL, "nightcrier-test", nil)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer objects.Close()
+
+	// The API version is derived from the kind
+	var object map[string]interface{}
+	found, err := objects.Get(context.Background(), Ref{Kind: "Deployment", Namespace: "shop", Name: "checkout"}, &object)
+	if err != nil || !found {
+		t.Fatalf("Get() = %v, %v; want the deployment", found, err)
+	}
+	if labels, _ := object["metadata"].(map[string]interface{})["labels"].(map[string]interface{}); labels["team"] != "payments" {
+		t.Errorf("Get() object = %v, want the decoded deployment", object)
+	}
+	if len(namespaces) != 1 || namespaces[0] != "shop" {
+		t.Errorf("requested namespaces %v, want shop", namespaces)
+	}
+
+	found, err = objects.Get(context.Background(), Ref{Kind: "Ingress", Namespace: "shop", Name: "storefront"}, &object)
+	if err != nil || !found {
+		t.Errorf("Get() ingress = %v, %v; want it found under networking.k8s.io/v1", found, err)
+	}
+
+	found, err = objects.Get(context.Background(), Ref{APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "shop", Name: "db"}, &object)
+	if err != nil || found {
+		t.Errorf("Get() missing object = %v, %v; want not found", found, err)
+	}
+
+	if _, err := objects.Get(context.Background(), Ref{Kind: "Pod", Namespace: "shop", Name: "broken"}, &object); err == nil {
+		t.Error("Get() of an unparseable object succeeded")
+	}
+}
@@ -0,0 +1,185 @@
+// Package labels validates and matches the labels and annotations attached to
+// incidents. Labels are key-value pairs used for filtering and ownership (e.g.
+// team or cost center); annotations are free-form notes that are stored but not
+// filterable.
+package labels
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/match"
+)
+
+// AgentFile is where the agent may record labels and annotations derived from its
+// findings, relative to the workspace. It holds a JSON object of the form
+// {"labels": {"team": "payments"}, "annotations": {"note": "..."}}.
+const AgentFile = "output/labels.json"
+
+const (
+	// maxLabelValueLength bounds label values, which are matched exactly in filters
+	maxLabelValueLength = 256
+	// maxAnnotationValueLength bounds annotation values, which are free-form notes
+	maxAnnotationValueLength = 4096
+)
+
+// keyPattern accepts keys like "team", "cost-center", or "example.com/owner"
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]{0,61}[A-Za-z0-9])?$`)
+
+// ValidateKey checks that key is usable as a label or annotation key: 1-63
+// letters, digits, '.', '_', '-' or '/', starting and ending with a letter or digit.
+func ValidateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("invalid label key %q: must be 1-63 letters, digits, '.', '_', '-' or '/', starting and ending with a letter or digit", key)
+	}
+	return nil
+}
+
+// Validate checks the keys and value lengths of labels and annotations.
+func Validate(labels, annotations map[string]string) error {
+	for key, value := range labels {
+		if err := ValidateKey(key); err != nil {
+			return err
+		}
+		if len(value) > maxLabelValueLength {
+			return fmt.Errorf("label %q value exceeds %d characters", key, maxLabelValueLength)
+		}
+	}
+	for key, value := range annotations {
+		if err := ValidateKey(key); err != nil {
+			return err
+		}
+		if len(value) > maxAnnotationValueLength {
+			return fmt.Errorf("annotation %q value exceeds %d characters", key, maxAnnotationValueLength)
+		}
+	}
+	return nil
+}
+
+// Usable returns the labels and annotations that pass Validate, and the keys of
+// those that do not, sorted. It is used for labels set by upstream systems (e.g.
+// Prometheus's "__name__"), which are kept where possible rather than rejected as
+// a whole.
+func Usable(labels, annotations map[string]string) (usableLabels, usableAnnotations map[string]string, unusable []string) {
+	keep := func(values map[string]string, maxLength int) map[string]string {
+		var kept map[string]string
+		for key, value := range values {
+			if ValidateKey(key) != nil || len(value) > maxLength {
+				unusable = append(unusable, key)
+				continue
+			}
+			if kept == nil {
+				kept = make(map[string]string, len(values))
+			}
+			kept[key] = value
+		}
+		return kept
+	}
+	usableLabels = keep(labels, maxLabelValueLength)
+	usableAnnotations = keep(annotations, maxAnnotationValueLength)
+	sort.Strings(unusable)
+	return usableLabels, usableAnnotations, unusable
+}
+
+// Rule attaches labels and annotations to incidents that match all of its
+// selectors. An empty selector matches any value; values are compared
+// case-insensitively.
+type Rule struct {
+	FaultTypes []string
+	Kinds      []string
+	Namespaces []string
+	Severities []string
+
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// Validate checks the rule's labels and annotations.
+func (r Rule) Validate() error {
+	if len(r.Labels) == 0 && len(r.Annotations) == 0 {
+		return fmt.Errorf("label rule must set labels or annotations")
+	}
+	return Validate(r.Labels, r.Annotations)
+}
+
+// Matches reports whether the rule applies to a fault.
+func (r Rule) Matches(faultType, kind, namespace, severity string) bool {
+	return match.Any(r.FaultTypes, faultType) &&
+		match.Any(r.Kinds, kind) &&
+		match.Any(r.Namespaces, namespace) &&
+		match.Any(r.Severities, severity)
+}
+
+// agentLabels is the content of AgentFile.
+type agentLabels struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// ReadAgentFile reads the labels and annotations the agent recorded at path.
+// A missing file is not an error; it returns no labels.
+func ReadAgentFile(path string) (labels, annotations map[string]string, err error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read agent labels: %w", err)
+	}
+
+	var parsed agentLabels
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse agent labels: %w", err)
+	}
+	if err := Validate(parsed.Labels, parsed.Annotations); err != nil {
+		return nil, nil, err
+	}
+	return parsed.Labels, parsed.Annotations, nil
+}
+
+// Parse parses "key=value" arguments into a map, e.g. from command-line flags.
+func Parse(args []string) (map[string]string, error) {
+	out := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid label %q: expected key=value", arg)
+		}
+		key = strings.TrimSpace(key)
+		if err := ValidateKey(key); err != nil {
+			return nil, err
+		}
+		out[key] = value
+	}
+	return out, nil
+}
+
+// Format renders labels as sorted "key=value" pairs separated by commas.
+func Format(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// PromptSection returns the agent prompt section asking the agent to record labels
+// and annotations derived from its findings in AgentFile.
+func PromptSection() string {
+	return `## Incident Labels
+
+If your findings identify the owning team, service, or other useful tags, record
+them in ` + AgentFile + ` as JSON, for example:
+
+    {"labels": {"team": "payments", "service": "checkout"}, "annotations": {"note": "Started after the 14:00 deploy"}}
+
+Label values are short identifiers used for filtering; annotations are free-form
+notes. Keys may contain letters, digits, '.', '_', '-' and '/'. Only record labels
+supported by evidence; omit the file if there are none.
+`
+}
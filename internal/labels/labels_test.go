@@ -0,0 +1,108 @@
+package labels
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateKey(t *testing.T) {
+	for _, key := range []string{"team", "cost-center", "example.com/owner", "a", "env_1"} {
+		if err := ValidateKey(key); err != nil {
+			t.Errorf("ValidateKey(%q) = %v, want nil", key, err)
+		}
+	}
+	for _, key := range []string{"", "-team", "team-", "has space", "a=b"} {
+		if err := ValidateKey(key); err == nil {
+			t.Errorf("ValidateKey(%q) = nil, want error", key)
+		}
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	rule := Rule{
+		Namespaces: []string{"payments"},
+		Severities: []string{"critical", "high"},
+		Labels:     map[string]string{"team": "payments"},
+	}
+	tests := []struct {
+		faultType, kind, namespace, severity string
+		want                                 bool
+	}{
+		{"CrashLoopBackOff", "Pod", "payments", "CRITICAL", true},
+		{"OOMKilled", "Deployment", "Payments", "HIGH", true},
+		{"CrashLoopBackOff", "Pod", "checkout", "CRITICAL", false},
+		{"CrashLoopBackOff", "Pod", "payments", "LOW", false},
+	}
+	for _, tt := range tests {
+		if got := rule.Matches(tt.faultType, tt.kind, tt.namespace, tt.severity); got != tt.want {
+			t.Errorf("Matches(%s, %s, %s, %s) = %v, want %v", tt.faultType, tt.kind, tt.namespace, tt.severity, got, tt.want)
+		}
+	}
+
+	if err := (Rule{Namespaces: []string{"payments"}}).Validate(); err == nil {
+		t.Error("Validate() should require labels or annotations")
+	}
+}
+
+func TestReadAgentFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "labels.json")
+
+	labels, annotations, err := ReadAgentFile(path)
+	if err != nil || labels != nil || annotations != nil {
+		t.Fatalf("missing file: got %v, %v, %v; want no labels and no error", labels, annotations, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"labels": {"team": "payments"}, "annotations": {"note": "deploy at 14:00"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	labels, annotations, err = ReadAgentFile(path)
+	if err != nil {
+		t.Fatalf("ReadAgentFile() error = %v", err)
+	}
+	if labels["team"] != "payments" || annotations["note"] != "deploy at 14:00" {
+		t.Errorf("ReadAgentFile() = %v, %v", labels, annotations)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"labels": {"bad key": "x"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadAgentFile(path); err == nil {
+		t.Error("ReadAgentFile() should reject invalid keys")
+	}
+}
+
+func TestParseAndFormat(t *testing.T) {
+	parsed, err := Parse([]string{"team=payments", "cost-center=cc-4711", "empty="})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, want := Format(parsed), "cost-center=cc-4711,empty=,team=payments"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+	if _, err := Parse([]string{"team"}); err == nil {
+		t.Error("Parse() should reject arguments without '='")
+	}
+}
+
+func TestUsable(t *testing.T) {
+	usableLabels, usableAnnotations, unusable := Usable(
+		map[string]string{"alertname": "KubePodCrashLooping", "__name__": "ALERTS", "team": strings.Repeat("x", 300)},
+		map[string]string{"runbook_url": "https://runbooks.example.com/crashloop"},
+	)
+	if Format(usableLabels) != "alertname=KubePodCrashLooping" {
+		t.Errorf("usable labels = %v", usableLabels)
+	}
+	if usableAnnotations["runbook_url"] == "" {
+		t.Errorf("usable annotations = %v", usableAnnotations)
+	}
+	if strings.Join(unusable, ",") != "__name__,team" {
+		t.Errorf("unusable = %v, want __name__ and the over-long team", unusable)
+	}
+
+	if l, a, u := Usable(nil, nil); l != nil || a != nil || u != nil {
+		t.Errorf("Usable(nil, nil) = %v, %v, %v", l, a, u)
+	}
+}
@@ -0,0 +1,155 @@
+// Package logging filters log records by the component that wrote them, so one
+// component's debug detail can be turned on without flooding the logs with every
+// other component's.
+//
+// A record's component is derived from the package of the code that logged it:
+// the top-level package under internal/ ("events", "agent", "storage" for
+// storage/sqlite and storage/postgres, ...), "main" for the nightcrier command
+// itself, and the last import path element for third-party packages. Components
+// need no registration and log calls need no changes.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ParseLevel parses a level name: debug, info, warn, or error.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q: must be debug, info, warn, or error", name)
+}
+
+// Levels are the minimum levels logged, by component.
+type Levels struct {
+	// Default applies to components without their own level
+	Default slog.Level
+	// Components holds the per-component levels
+	Components map[string]slog.Level
+	// min is the lowest of all levels
+	min slog.Level
+}
+
+// NewLevels parses the per-component levels on top of a default level. Components
+// with an empty level use the default.
+func NewLevels(def slog.Level, components map[string]string) (*Levels, error) {
+	levels := &Levels{Default: def, Components: make(map[string]slog.Level), min: def}
+	for component, name := range components {
+		if name == "" {
+			continue
+		}
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", component, err)
+		}
+		levels.Components[component] = level
+		levels.min = min(levels.min, level)
+	}
+	return levels, nil
+}
+
+// For returns the minimum level logged for a component.
+func (l *Levels) For(component string) slog.Level {
+	if level, ok := l.Components[component]; ok {
+		return level
+	}
+	return l.Default
+}
+
+// Handler is a slog.Handler that drops the records below their component's level
+// and passes the others to the wrapped handler. Its levels can be replaced at any
+// time; loggers derived with With share them.
+type Handler struct {
+	inner  slog.Handler
+	levels *atomic.Pointer[Levels]
+}
+
+// NewHandler wraps inner, which should accept every level, with the given levels.
+func NewHandler(inner slog.Handler, levels *Levels) *Handler {
+	h := &Handler{inner: inner, levels: new(atomic.Pointer[Levels])}
+	h.levels.Store(levels)
+	return h
+}
+
+// SetLevels replaces the levels in effect.
+func (h *Handler) SetLevels(levels *Levels) {
+	h.levels.Store(levels)
+}
+
+// Levels returns the levels in effect.
+func (h *Handler) Levels() *Levels {
+	return h.levels.Load()
+}
+
+// Enabled reports whether any component logs at level; the record's component is
+// only known once Handle sees its caller.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.levels.Load().min
+}
+
+// Handle passes the record on when its component logs at the record's level.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.levels.Load().For(callerComponent(r.PC)) {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{inner: h.inner.WithAttrs(attrs), levels: h.levels}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{inner: h.inner.WithGroup(name), levels: h.levels}
+}
+
+// components caches the component of each logging call site
+var components sync.Map // uintptr -> string
+
+// callerComponent returns the component of the code at pc.
+func callerComponent(pc uintptr) string {
+	if pc == 0 {
+		return ""
+	}
+	if component, ok := components.Load(pc); ok {
+		return component.(string)
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	component := Component(frame.Function)
+	components.Store(pc, component)
+	return component
+}
+
+// Component returns the component of a fully qualified function name, e.g.
+// "events" for "github.com/rbias/nightcrier/internal/events.(*Client).run".
+func Component(function string) string {
+	// The package path ends at the first dot after the last slash
+	path := function
+	lastSlash := strings.LastIndex(path, "/")
+	if dot := strings.Index(path[lastSlash+1:], "."); dot >= 0 {
+		path = path[:lastSlash+1+dot]
+	}
+
+	if _, rest, ok := strings.Cut(path, "/internal/"); ok {
+		component, _, _ := strings.Cut(rest, "/")
+		return component
+	}
+	if path == "main" || strings.Contains(path, "/cmd/") {
+		return "main"
+	}
+	return path[lastSlash+1:]
+}
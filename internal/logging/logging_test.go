@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestComponent(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{"github.com/rbias/nightcrier/internal/events.(*Client).run", "events"},
+		{"github.com/rbias/nightcrier/internal/storage/sqlite.(*Store).CreateIncident", "storage"},
+		{"github.com/rbias/nightcrier/internal/agent.(*Executor).Execute.func1", "agent"},
+		{"main.(*eventProcessor).processEvent", "main"},
+		{"github.com/rbias/nightcrier/cmd/nightcrier.runServe", "main"},
+		{"github.com/modelcontextprotocol/go-sdk/mcp.(*ClientSession).Wait", "mcp"},
+		{"log/slog.(*Logger).Info", "slog"},
+	}
+	for _, tt := range tests {
+		if got := Component(tt.function); got != tt.want {
+			t.Errorf("Component(%q) = %q, want %q", tt.function, got, tt.want)
+		}
+	}
+}
+
+func TestNewLevels(t *testing.T) {
+	levels, err := NewLevels(slog.LevelInfo, map[string]string{"events": "warn", "agent": "debug", "storage": ""})
+	if err != nil {
+		t.Fatalf("NewLevels() failed: %v", err)
+	}
+	if levels.For("events") != slog.LevelWarn || levels.For("agent") != slog.LevelDebug || levels.For("storage") != slog.LevelInfo {
+		t.Errorf("levels = %+v, want events warn, agent debug, and storage at the default", levels)
+	}
+	if _, err := NewLevels(slog.LevelInfo, map[string]string{"events": "verbose"}); err == nil {
+		t.Error("NewLevels() with an unknown level succeeded, want error")
+	}
+}
+
+func TestHandler_FiltersByComponent(t *testing.T) {
+	var buf bytes.Buffer
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	levels, _ := NewLevels(slog.LevelWarn, map[string]string{"logging": "debug"})
+	h := NewHandler(inner, levels)
+	logger := slog.New(h).With("cluster", "prod")
+
+	// Records logged here belong to the "logging" component
+	logger.Debug("component detail")
+	if !strings.Contains(buf.String(), "component detail") || !strings.Contains(buf.String(), "cluster=prod") {
+		t.Errorf("output = %q, want the debug record of the component at debug", buf.String())
+	}
+
+	buf.Reset()
+	levels, _ = NewLevels(slog.LevelDebug, map[string]string{"logging": "error"})
+	h.SetLevels(levels)
+	logger.Warn("below the component level")
+	if buf.Len() != 0 {
+		t.Errorf("output = %q, want the record dropped", buf.String())
+	}
+	if !h.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Enabled(debug) = false, want true while the default level is debug")
+	}
+}
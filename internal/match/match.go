@@ -0,0 +1,20 @@
+// Package match holds the matching helpers shared by the rule-based packages
+// (sampling, labels, runbooks, severity policy, fault handlers), so their rules
+// treat fault types, kinds, namespaces, and severities the same way.
+package match
+
+import "strings"
+
+// Any reports whether value equals one of values, ignoring case, or values is
+// empty. An empty list in a rule matches anything.
+func Any(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
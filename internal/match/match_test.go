@@ -0,0 +1,22 @@
+package match
+
+import "testing"
+
+func TestAny(t *testing.T) {
+	tests := []struct {
+		values []string
+		value  string
+		want   bool
+	}{
+		{nil, "OOMKilled", true},
+		{[]string{}, "", true},
+		{[]string{"OOMKilled", "CrashLoopBackOff"}, "oomkilled", true},
+		{[]string{"OOMKilled"}, "CrashLoopBackOff", false},
+		{[]string{"prod"}, "", false},
+	}
+	for _, tt := range tests {
+		if got := Any(tt.values, tt.value); got != tt.want {
+			t.Errorf("Any(%v, %q) = %v, want %v", tt.values, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHistogramVec(t *testing.T) {
+	registry := NewRegistry()
+	h := registry.Register(NewHistogramVec("test_duration_seconds", "Test durations.", []float64{1, 10}, "cluster", "stage"))
+	h.Observe(0.5, "prod", "agent")
+	h.Observe(5, "prod", "agent")
+	h.Observe(50, "prod", "agent")
+	h.Observe(2, `dev"1`, "queue")
+
+	rec := httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("GET /metrics = %d %q, want 200 text/plain", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	want := `# HELP test_duration_seconds Test durations.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{cluster="dev\"1",stage="queue",le="1"} 0
+test_duration_seconds_bucket{cluster="dev\"1",stage="queue",le="10"} 1
+test_duration_seconds_bucket{cluster="dev\"1",stage="queue",le="+Inf"} 1
+test_duration_seconds_sum{cluster="dev\"1",stage="queue"} 2
+test_duration_seconds_count{cluster="dev\"1",stage="queue"} 1
+test_duration_seconds_bucket{cluster="prod",stage="agent",le="1"} 1
+test_duration_seconds_bucket{cluster="prod",stage="agent",le="10"} 2
+test_duration_seconds_bucket{cluster="prod",stage="agent",le="+Inf"} 3
+test_duration_seconds_sum{cluster="prod",stage="agent"} 55.5
+test_duration_seconds_count{cluster="prod",stage="agent"} 3
+`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("metrics =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCounterVec(t *testing.T) {
+	registry := NewRegistry()
+	c := registry.RegisterCounter(NewCounterVec("test_failures_total", "Test failures.", "cluster", "class"))
+	c.Inc("prod", "timeout")
+	c.Inc("prod", "timeout")
+	c.Inc("dev", "oom_killed")
+
+	var b strings.Builder
+	if _, err := registry.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP test_failures_total Test failures.
+# TYPE test_failures_total counter
+test_failures_total{cluster="dev",class="oom_killed"} 1
+test_failures_total{cluster="prod",class="timeout"} 2
+`
+	if got := b.String(); got != want {
+		t.Errorf("metrics =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,175 @@
+// Package noise reviews incident history for fault types that keep producing
+// investigations of little value, and suggests the tuning that would quiet them:
+// a longer dedup window when the same fault keeps recurring on the same resource,
+// or a filter (a namespace silence or a higher severity threshold) when the
+// investigations mostly repeat or fail.
+package noise
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/incident"
+)
+
+// Suggested actions
+const (
+	// ActionDedupWindow suggests a longer dedup window: most investigations
+	// repeated an identical fault on a resource already investigated
+	ActionDedupWindow = "dedup_window"
+	// ActionFilter suggests filtering the fault type in the namespace: most
+	// investigations repeated or failed, too far apart for deduplication to help
+	ActionFilter = "filter"
+)
+
+// maxSuggestedDedupWindow is the longest dedup window suggested; faults repeating
+// further apart are better filtered than deduplicated
+const maxSuggestedDedupWindow = 24 * time.Hour
+
+// Options tunes the analysis.
+type Options struct {
+	// MinIncidents is the number of incidents a fault type must produce in a
+	// namespace before it is considered noisy
+	MinIncidents int
+	// MaxSuggestions bounds the suggestions, noisiest first (0 for no limit)
+	MaxSuggestions int
+	// DedupWindow is the dedup window in effect, quoted in suggestions
+	DedupWindow time.Duration
+}
+
+// Suggestion is the tuning suggested for one fault type in one namespace.
+type Suggestion struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	FaultType string `json:"fault_type"`
+	// Incidents is the number of incidents in the analyzed period
+	Incidents int `json:"incidents"`
+	// Resources is the number of distinct resources affected
+	Resources int `json:"resources"`
+	// Repeats are incidents of a resource already investigated in the period
+	Repeats int `json:"repeats"`
+	// Failed are incidents whose investigation failed
+	Failed int `json:"failed"`
+	// LowValue are incidents that repeated or failed
+	LowValue int `json:"low_value"`
+	// MedianRepeatGapSeconds is the median time between an incident and the
+	// previous one on the same resource
+	MedianRepeatGapSeconds int64 `json:"median_repeat_gap_seconds,omitempty"`
+	// Action is ActionDedupWindow or ActionFilter
+	Action string `json:"action"`
+	// Text describes the noise and the suggested tuning
+	Text string `json:"text"`
+}
+
+// Report is the result of one analysis.
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	// Since is the start of the analyzed period
+	Since time.Time `json:"since"`
+	// Incidents is the number of incidents analyzed
+	Incidents   int          `json:"incidents"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// groupKey identifies a fault type in a namespace.
+type groupKey struct {
+	cluster, namespace, faultType string
+}
+
+// Analyze groups the incidents by cluster, namespace, and fault type and suggests
+// tuning for the groups with at least MinIncidents incidents of which at least
+// half were low-value (a repeat of an identical fault on the same resource, or a
+// failed investigation). Suggestions are ordered by low-value incidents, most
+// first.
+func Analyze(incidents []*incident.Incident, since, now time.Time, opts Options) *Report {
+	groups := make(map[groupKey][]*incident.Incident)
+	for _, inc := range incidents {
+		k := groupKey{inc.Cluster, inc.Namespace, inc.FaultType}
+		groups[k] = append(groups[k], inc)
+	}
+
+	report := &Report{GeneratedAt: now, Since: since, Incidents: len(incidents), Suggestions: []Suggestion{}}
+	for k, group := range groups {
+		if len(group) < opts.MinIncidents {
+			continue
+		}
+		if s, ok := analyzeGroup(k, group, opts); ok {
+			report.Suggestions = append(report.Suggestions, s)
+		}
+	}
+	sort.Slice(report.Suggestions, func(i, j int) bool {
+		a, b := report.Suggestions[i], report.Suggestions[j]
+		if a.LowValue != b.LowValue {
+			return a.LowValue > b.LowValue
+		}
+		if a.Incidents != b.Incidents {
+			return a.Incidents > b.Incidents
+		}
+		return a.Cluster+"/"+a.Namespace+"/"+a.FaultType < b.Cluster+"/"+b.Namespace+"/"+b.FaultType
+	})
+	if opts.MaxSuggestions > 0 && len(report.Suggestions) > opts.MaxSuggestions {
+		report.Suggestions = report.Suggestions[:opts.MaxSuggestions]
+	}
+	return report
+}
+
+// analyzeGroup scores the incidents of one fault type in one namespace and
+// returns the suggested tuning, if the group is noisy.
+func analyzeGroup(k groupKey, group []*incident.Incident, opts Options) (Suggestion, bool) {
+	sort.Slice(group, func(i, j int) bool { return group[i].CreatedAt.Before(group[j].CreatedAt) })
+
+	s := Suggestion{Cluster: k.cluster, Namespace: k.namespace, FaultType: k.faultType, Incidents: len(group)}
+	lastSeen := make(map[string]time.Time)
+	var gaps []time.Duration
+	for _, inc := range group {
+		resource := ""
+		if inc.Resource != nil {
+			resource = inc.Resource.Kind + "/" + inc.Resource.Name
+		}
+		last, repeat := lastSeen[resource]
+		lastSeen[resource] = inc.CreatedAt
+		failed := inc.Status == incident.StatusFailed || inc.Status == incident.StatusAgentFailed
+		if repeat {
+			s.Repeats++
+			gaps = append(gaps, inc.CreatedAt.Sub(last))
+		}
+		if failed {
+			s.Failed++
+		}
+		if repeat || failed {
+			s.LowValue++
+		}
+	}
+	s.Resources = len(lastSeen)
+	if s.LowValue*2 < s.Incidents {
+		return Suggestion{}, false
+	}
+
+	var medianGap time.Duration
+	if len(gaps) > 0 {
+		sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+		medianGap = gaps[len(gaps)/2]
+		s.MedianRepeatGapSeconds = int64(medianGap / time.Second)
+	}
+
+	where := fmt.Sprintf("fault type %s in namespace %s (cluster %s)", orUnknown(s.FaultType), orUnknown(s.Namespace), orUnknown(s.Cluster))
+	if s.Repeats*2 >= s.Incidents && medianGap > 0 && medianGap <= maxSuggestedDedupWindow {
+		s.Action = ActionDedupWindow
+		s.Text = fmt.Sprintf("%s produced %d investigations, %d of them repeats of an identical fault on the same resource a median of %s apart; consider a dedup window longer than %s (currently %s)",
+			where, s.Incidents, s.Repeats, medianGap.Round(time.Second), medianGap.Round(time.Second), opts.DedupWindow.Round(time.Second))
+		return s, true
+	}
+	s.Action = ActionFilter
+	s.Text = fmt.Sprintf("%s produced %d investigations, %d of them low-value (%d repeats, %d failed); consider a filter: a namespace silence or a higher severity_threshold",
+		where, s.Incidents, s.LowValue, s.Repeats, s.Failed)
+	return s, true
+}
+
+// orUnknown substitutes a placeholder for empty names in suggestions.
+func orUnknown(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
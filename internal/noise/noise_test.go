@@ -0,0 +1,85 @@
+package noise
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/incident"
+)
+
+var start = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// newIncident returns an incident of the fault type on the pod, created at the
+// offset from start.
+func newIncident(namespace, faultType, pod, status string, at time.Duration) *incident.Incident {
+	return &incident.Incident{
+		Cluster:   "prod",
+		Namespace: namespace,
+		FaultType: faultType,
+		Status:    status,
+		CreatedAt: start.Add(at),
+		Resource:  &incident.ResourceInfo{Kind: "Pod", Name: pod},
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	var incidents []*incident.Incident
+	// The same pod crash-looping every 20 minutes: a longer dedup window helps
+	for i := 0; i < 12; i++ {
+		incidents = append(incidents, newIncident("batch", "CrashLoopBackOff", "report-job", incident.StatusResolved, time.Duration(i)*20*time.Minute))
+	}
+	// Failed investigations of distinct pods, days apart: a filter helps
+	for i := 0; i < 10; i++ {
+		status := incident.StatusAgentFailed
+		if i%5 == 0 {
+			status = incident.StatusResolved
+		}
+		incidents = append(incidents, newIncident("sandbox", "ImagePullBackOff", fmt.Sprintf("pod-%d", i), status, time.Duration(i)*48*time.Hour))
+	}
+	// Distinct, successful investigations are not noise
+	for i := 0; i < 10; i++ {
+		incidents = append(incidents, newIncident("shop", "OOMKilled", fmt.Sprintf("checkout-%d", i), incident.StatusResolved, time.Duration(i)*time.Hour))
+	}
+	// Too few incidents to judge
+	for i := 0; i < 3; i++ {
+		incidents = append(incidents, newIncident("web", "CrashLoopBackOff", "frontend", incident.StatusFailed, time.Duration(i)*time.Minute))
+	}
+
+	report := Analyze(incidents, start, start.Add(30*24*time.Hour), Options{MinIncidents: 5, DedupWindow: 5 * time.Minute})
+	if report.Incidents != len(incidents) {
+		t.Errorf("Incidents = %d, want %d", report.Incidents, len(incidents))
+	}
+	if len(report.Suggestions) != 2 {
+		t.Fatalf("Suggestions = %+v, want batch and sandbox", report.Suggestions)
+	}
+
+	dedup := report.Suggestions[0]
+	if dedup.Namespace != "batch" || dedup.Action != ActionDedupWindow || dedup.Repeats != 11 || dedup.LowValue != 11 || dedup.Resources != 1 {
+		t.Errorf("Suggestions[0] = %+v, want a longer dedup window for batch", dedup)
+	}
+	if dedup.MedianRepeatGapSeconds != 1200 || !strings.Contains(dedup.Text, "longer than 20m0s (currently 5m0s)") {
+		t.Errorf("Suggestions[0] = %+v, want the 20m repeat gap", dedup)
+	}
+
+	filter := report.Suggestions[1]
+	if filter.Namespace != "sandbox" || filter.Action != ActionFilter || filter.Failed != 8 || filter.Repeats != 0 || filter.Resources != 10 {
+		t.Errorf("Suggestions[1] = %+v, want a filter for sandbox", filter)
+	}
+	if !strings.Contains(filter.Text, "fault type ImagePullBackOff in namespace sandbox (cluster prod) produced 10 investigations, 8 of them low-value") {
+		t.Errorf("Suggestions[1].Text = %q", filter.Text)
+	}
+
+	limited := Analyze(incidents, start, start, Options{MinIncidents: 5, MaxSuggestions: 1})
+	if len(limited.Suggestions) != 1 || limited.Suggestions[0].Namespace != "batch" {
+		t.Errorf("Suggestions = %+v, want only the noisiest", limited.Suggestions)
+	}
+}
+
+func TestAnalyze_NoIncidents(t *testing.T) {
+	report := Analyze(nil, start, start, Options{MinIncidents: 1})
+	if report.Suggestions == nil || len(report.Suggestions) != 0 {
+		t.Errorf("Suggestions = %#v, want empty and non-nil for JSON", report.Suggestions)
+	}
+}
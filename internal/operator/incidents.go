@@ -0,0 +1,147 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/incluster"
+)
+
+// IncidentResult is the outcome of an investigation recorded in an Incident
+// resource's status.
+type IncidentResult struct {
+	RootCause  string
+	Confidence string
+	ReportURL  string
+}
+
+// IncidentResources records incidents as Incident resources. Failures are logged
+// and never fail the investigation: the state store and workspace remain the
+// system of record.
+type IncidentResources struct {
+	client    apiClient
+	namespace string
+}
+
+// NewIncidentResources creates Incident resources in namespace.
+func NewIncidentResources(client *incluster.Client, namespace string) *IncidentResources {
+	return &IncidentResources{client: client, namespace: namespace}
+}
+
+// Create records a new incident.
+func (r *IncidentResources) Create(ctx context.Context, inc *incident.Incident) {
+	obj := Incident{
+		APIVersion: Group + "/" + Version,
+		Kind:       "Incident",
+		Metadata: ObjectMeta{
+			Name:      ResourceName(inc.Ref()),
+			Namespace: r.namespace,
+			Labels: map[string]string{
+				"nightcrier.io/cluster":  labelValue(inc.Cluster),
+				"nightcrier.io/severity": labelValue(strings.ToLower(inc.Severity)),
+			},
+		},
+		Spec: IncidentSpec{
+			IncidentID:       inc.IncidentID,
+			DisplayID:        inc.DisplayID,
+			Cluster:          inc.Cluster,
+			Namespace:        inc.Namespace,
+			FaultType:        inc.FaultType,
+			Severity:         inc.Severity,
+			Context:          inc.Context,
+			FaultID:          inc.FaultID,
+			ParentIncidentID: inc.ParentIncidentID,
+			Labels:           inc.Labels,
+		},
+	}
+	if inc.Resource != nil {
+		obj.Spec.ResourceKind = inc.Resource.Kind
+		obj.Spec.ResourceName = inc.Resource.Name
+	}
+
+	err := r.client.Create(ctx, Incidents, r.namespace, obj)
+	if err != nil && !errors.Is(err, incluster.ErrAlreadyExists) {
+		slog.Warn("failed to create incident resource",
+			"incident_id", inc.IncidentID,
+			"error", err)
+		return
+	}
+	r.UpdateStatus(ctx, inc, nil)
+}
+
+// UpdateStatus records the incident's current status and, once the investigation
+// has finished, its result.
+func (r *IncidentResources) UpdateStatus(ctx context.Context, inc *incident.Incident, result *IncidentResult) {
+	status := IncidentStatus{
+		Phase:         Phase(inc.Status),
+		StartedAt:     inc.StartedAt,
+		CompletedAt:   inc.CompletedAt,
+		FailureReason: inc.FailureReason,
+		FailureClass:  inc.FailureClass,
+	}
+	if result != nil {
+		status.RootCause = result.RootCause
+		status.Confidence = result.Confidence
+		status.ReportURL = result.ReportURL
+	}
+	if err := r.client.PatchStatus(ctx, Incidents, r.namespace, ResourceName(inc.Ref()), status); err != nil {
+		slog.Warn("failed to update incident resource status",
+			"incident_id", inc.IncidentID,
+			"error", err)
+	}
+}
+
+// Phase returns the Incident resource phase of an incident status.
+func Phase(status string) string {
+	switch status {
+	case incident.StatusReceived:
+		return PhaseReceived
+	case incident.StatusPending:
+		return PhasePending
+	case incident.StatusQueued:
+		return PhaseQueued
+	case incident.StatusInvestigating:
+		return PhaseInvestigating
+	case incident.StatusAwaitingReview:
+		return PhaseAwaitingReview
+	case incident.StatusRemediationProposed:
+		return PhaseRemediationProposed
+	case incident.StatusResolved:
+		return PhaseResolved
+	case incident.StatusAgentFailed:
+		return PhaseAgentFailed
+	case incident.StatusSuppressed:
+		return PhaseSuppressed
+	case incident.StatusExpired:
+		return PhaseExpired
+	}
+	return PhaseFailed
+}
+
+// ResourceName returns the Incident resource name of an incident reference
+// (display ID or UUID): lowercase, with characters Kubernetes does not allow in
+// names replaced by hyphens.
+func ResourceName(ref string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return '-'
+	}, ref)
+	return strings.Trim(name, "-")
+}
+
+// labelValue truncates a value to the 63 characters allowed in a label value.
+func labelValue(value string) string {
+	value = ResourceName(value)
+	if len(value) > 63 {
+		value = strings.Trim(value[:63], "-")
+	}
+	return value
+}
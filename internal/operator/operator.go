@@ -0,0 +1,176 @@
+// Package operator runs nightcrier in operator mode: monitored clusters and the
+// investigation policy are declared as custom resources (ClusterTarget and
+// InvestigationPolicy) in nightcrier's namespace, and incidents are recorded as
+// Incident resources whose status follows the investigation. This lets the whole
+// deployment be managed with GitOps tooling.
+//
+// Declared resources are read at startup. Changes are detected by polling and
+// reported through Watch; nightcrier then exits so its Deployment restarts it with
+// the new configuration, which keeps every component's view of the clusters
+// consistent without hot-swapping connections.
+package operator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incluster"
+)
+
+// apiClient is the part of the in-cluster API client the operator uses.
+type apiClient interface {
+	List(ctx context.Context, resource incluster.Resource, namespace string, out interface{}) error
+	Create(ctx context.Context, resource incluster.Resource, namespace string, obj interface{}) error
+	PatchStatus(ctx context.Context, resource incluster.Resource, namespace, name string, status interface{}) error
+}
+
+// Operator reads nightcrier's configuration from custom resources.
+type Operator struct {
+	client     apiClient
+	namespace  string
+	policyName string
+}
+
+// New creates an operator for the resources in the configured namespace.
+func New(client *incluster.Client, cfg config.OperatorConfig) *Operator {
+	return newOperator(client, cfg)
+}
+
+func newOperator(client apiClient, cfg config.OperatorConfig) *Operator {
+	return &Operator{
+		client:     client,
+		namespace:  cfg.Namespace,
+		policyName: cfg.PolicyName,
+	}
+}
+
+// Snapshot is the configuration declared by the custom resources at one point in
+// time.
+type Snapshot struct {
+	// Clusters are the declared cluster targets, sorted by name
+	Clusters []cluster.ClusterConfig
+	// Policy is the applied investigation policy, nil when it does not exist
+	Policy *InvestigationPolicySpec
+	// Fingerprint changes whenever the declared configuration does
+	Fingerprint string
+}
+
+// Load reads the cluster targets and the investigation policy.
+func (o *Operator) Load(ctx context.Context) (*Snapshot, error) {
+	var targets struct {
+		Items []ClusterTarget `json:"items"`
+	}
+	if err := o.client.List(ctx, ClusterTargets, o.namespace, &targets); err != nil {
+		if errors.Is(err, incluster.ErrNotFound) {
+			return nil, fmt.Errorf("ClusterTarget CRD is not installed (apply deploy/operator/crds.yaml)")
+		}
+		return nil, fmt.Errorf("failed to list cluster targets: %w", err)
+	}
+
+	var policies struct {
+		Items []InvestigationPolicy `json:"items"`
+	}
+	if err := o.client.List(ctx, InvestigationPolicies, o.namespace, &policies); err != nil {
+		if errors.Is(err, incluster.ErrNotFound) {
+			return nil, fmt.Errorf("InvestigationPolicy CRD is not installed (apply deploy/operator/crds.yaml)")
+		}
+		return nil, fmt.Errorf("failed to list investigation policies: %w", err)
+	}
+
+	snapshot := &Snapshot{}
+	for _, target := range targets.Items {
+		snapshot.Clusters = append(snapshot.Clusters, target.ClusterConfig())
+	}
+	sort.Slice(snapshot.Clusters, func(i, j int) bool { return snapshot.Clusters[i].Name < snapshot.Clusters[j].Name })
+	for _, policy := range policies.Items {
+		if policy.Metadata.Name == o.policyName {
+			spec := policy.Spec
+			snapshot.Policy = &spec
+		}
+	}
+
+	fingerprint, err := json.Marshal(struct {
+		Clusters []cluster.ClusterConfig
+		Policy   *InvestigationPolicySpec
+	}{snapshot.Clusters, snapshot.Policy})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint operator configuration: %w", err)
+	}
+	sum := sha256.Sum256(fingerprint)
+	snapshot.Fingerprint = hex.EncodeToString(sum[:])
+	return snapshot, nil
+}
+
+// Apply replaces the configuration's clusters with the declared ones, applies the
+// policy's settings, and validates the result.
+func (s *Snapshot) Apply(cfg *config.Config) error {
+	if len(s.Clusters) == 0 {
+		return fmt.Errorf("no ClusterTarget resources found in namespace %s", cfg.Operator.Namespace)
+	}
+	cfg.Clusters = s.Clusters
+
+	if p := s.Policy; p != nil {
+		if p.SeverityThreshold != "" {
+			cfg.SeverityThreshold = p.SeverityThreshold
+		}
+		if p.MaxConcurrentAgents != 0 {
+			cfg.MaxConcurrentAgents = p.MaxConcurrentAgents
+		}
+		if p.AgentTimeoutSeconds != 0 {
+			cfg.AgentTimeout = p.AgentTimeoutSeconds
+		}
+		if p.FailureThresholdForAlert != 0 {
+			cfg.FailureThresholdForAlert = p.FailureThresholdForAlert
+		}
+		if p.ReportLanguage != "" {
+			cfg.ReportLanguage = p.ReportLanguage
+		}
+		if p.Budget != nil {
+			dir := cfg.Budget.Dir
+			cfg.Budget.BudgetConfig = p.Budget.config()
+			cfg.Budget.Dir = dir
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration from custom resources: %w", err)
+	}
+	return nil
+}
+
+// Watch checks the resources every interval and calls changed, once, when the
+// declared configuration no longer matches the snapshot. It returns when ctx is
+// done or after calling changed. Errors reading the resources are logged and
+// retried at the next interval.
+func (o *Operator) Watch(ctx context.Context, interval time.Duration, snapshot *Snapshot, changed func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := o.Load(ctx)
+			if err != nil {
+				slog.Warn("failed to check operator resources for changes", "error", err)
+				continue
+			}
+			if current.Fingerprint != snapshot.Fingerprint {
+				slog.Info("operator resources changed",
+					"namespace", o.namespace,
+					"clusters", len(current.Clusters))
+				changed()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/incluster"
+)
+
+// fakeAPI serves custom resources from memory.
+type fakeAPI struct {
+	mu       sync.Mutex
+	lists    map[string]string // resource -> JSON list
+	created  []interface{}
+	statuses map[string]interface{} // name -> last status patch
+}
+
+func (f *fakeAPI) List(ctx context.Context, resource incluster.Resource, namespace string, out interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	list, ok := f.lists[resource.Resource]
+	if !ok {
+		return incluster.ErrNotFound
+	}
+	return json.Unmarshal([]byte(list), out)
+}
+
+func (f *fakeAPI) Create(ctx context.Context, resource incluster.Resource, namespace string, obj interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created = append(f.created, obj)
+	return nil
+}
+
+func (f *fakeAPI) PatchStatus(ctx context.Context, resource incluster.Resource, namespace, name string, status interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.statuses == nil {
+		f.statuses = make(map[string]interface{})
+	}
+	f.statuses[name] = status
+	return nil
+}
+
+func (f *fakeAPI) setList(resource, list string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lists[resource] = list
+}
+
+const testTargets = `{"items": [
+	{"metadata": {"name": "staging"}, "spec": {"mcp": {"endpoint": "http://mcp.staging:8080"}, "serveOnly": true}},
+	{"metadata": {"name": "prod"}, "spec": {"environment": "production", "labels": {"team": "sre"},
+		"mcp": {"endpoint": "http://mcp.prod:8080"}, "budget": {"maxInvestigationsPerDay": 10}}}
+]}`
+
+const testPolicies = `{"items": [
+	{"metadata": {"name": "default"}, "spec": {"severityThreshold": "ERROR", "maxConcurrentAgents": 2, "budget": {"maxInvestigationsPerDay": 40}}},
+	{"metadata": {"name": "other"}, "spec": {"severityThreshold": "DEBUG"}}
+]}`
+
+func newTestAPI() *fakeAPI {
+	return &fakeAPI{lists: map[string]string{
+		"clustertargets":        testTargets,
+		"investigationpolicies": testPolicies,
+	}}
+}
+
+func testOperatorConfig() config.OperatorConfig {
+	return config.OperatorConfig{Enabled: true, Namespace: "nightcrier", PolicyName: "default", ResyncSeconds: 30}
+}
+
+// testConfig returns a valid operator-mode configuration without clusters.
+func testConfig(t *testing.T) *config.Config {
+	return &config.Config{
+		Operator:                   testOperatorConfig(),
+		SubscribeMode:              "faults",
+		WorkspaceRoot:              t.TempDir(),
+		AgentScriptPath:            "./run-agent.sh",
+		AgentTimeout:               300,
+		AgentModel:                 "sonnet",
+		AgentCLI:                   "claude",
+		AgentImage:                 "nightcrier-agent:latest",
+		SeverityThreshold:          "WARNING",
+		MaxConcurrentAgents:        5,
+		GlobalQueueSize:            100,
+		ClusterQueueSize:           10,
+		DedupWindowSeconds:         300,
+		QueueOverflowPolicy:        "drop",
+		ShutdownTimeout:            30,
+		SSEReconnectInitialBackoff: 1,
+		SSEReconnectMaxBackoff:     60,
+		SSEReadTimeout:             120,
+		FailureThresholdForAlert:   3,
+		AnthropicAPIKey:            "test-key",
+	}
+}
+
+func TestLoadAndApply(t *testing.T) {
+	op := newOperator(newTestAPI(), testOperatorConfig())
+	snapshot, err := op.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(snapshot.Clusters) != 2 || snapshot.Clusters[0].Name != "prod" || snapshot.Clusters[1].Name != "staging" {
+		t.Fatalf("clusters = %+v, want prod and staging", snapshot.Clusters)
+	}
+	prod := snapshot.Clusters[0]
+	if prod.MCP.Endpoint != "http://mcp.prod:8080" || prod.Labels["team"] != "sre" || prod.Budget.MaxInvestigationsPerDay != 10 {
+		t.Errorf("prod = %+v", prod)
+	}
+	if !snapshot.Clusters[1].ServeOnly {
+		t.Error("staging should be serve-only")
+	}
+	if snapshot.Policy == nil || snapshot.Policy.SeverityThreshold != "ERROR" {
+		t.Fatalf("policy = %+v, want the default policy", snapshot.Policy)
+	}
+
+	cfg := testConfig(t)
+	if err := snapshot.Apply(cfg); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if len(cfg.Clusters) != 2 || cfg.SeverityThreshold != "ERROR" || cfg.MaxConcurrentAgents != 2 {
+		t.Errorf("config = clusters %d, severity %s, agents %d; want the declared resources applied",
+			len(cfg.Clusters), cfg.SeverityThreshold, cfg.MaxConcurrentAgents)
+	}
+	if cfg.BudgetLimits("prod").MaxInvestigationsPerDay != 10 || cfg.BudgetLimits("staging").MaxInvestigationsPerDay != 40 {
+		t.Errorf("budgets = %+v / %+v, want the target override over the policy default",
+			cfg.BudgetLimits("prod"), cfg.BudgetLimits("staging"))
+	}
+}
+
+func TestLoadRequiresCRDs(t *testing.T) {
+	op := newOperator(&fakeAPI{lists: map[string]string{}}, testOperatorConfig())
+	if _, err := op.Load(context.Background()); err == nil {
+		t.Error("Load() should fail when the CRDs are not installed")
+	}
+}
+
+func TestApplyRequiresClusterTargets(t *testing.T) {
+	api := newTestAPI()
+	api.lists["clustertargets"] = `{"items": []}`
+	snapshot, err := newOperator(api, testOperatorConfig()).Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := snapshot.Apply(testConfig(t)); err == nil {
+		t.Error("Apply() should fail without cluster targets")
+	}
+}
+
+func TestWatchDetectsChanges(t *testing.T) {
+	api := newTestAPI()
+	op := newOperator(api, testOperatorConfig())
+	snapshot, err := op.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	changed := make(chan struct{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go op.Watch(ctx, 10*time.Millisecond, snapshot, func() { close(changed) })
+
+	// Unchanged resources are not reported
+	select {
+	case <-changed:
+		t.Fatal("changed called without a change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	api.setList("investigationpolicies", `{"items": [{"metadata": {"name": "default"}, "spec": {"severityThreshold": "CRITICAL"}}]}`)
+	select {
+	case <-changed:
+	case <-ctx.Done():
+		t.Fatal("policy change not detected")
+	}
+}
+
+func TestIncidentResources(t *testing.T) {
+	api := newTestAPI()
+	resources := &IncidentResources{client: api, namespace: "nightcrier"}
+
+	inc := &incident.Incident{
+		IncidentID: "2f1c0c1e-8a44-4f0e-9d0b-3a7c1e5f9b21",
+		DisplayID:  "NC-2024-0613-prod_east-0042",
+		Status:     incident.StatusInvestigating,
+		Cluster:    "prod_east",
+		Namespace:  "default",
+		Severity:   "ERROR",
+		FaultType:  "CrashLoopBackOff",
+		Resource:   &incident.ResourceInfo{Kind: "Pod", Name: "web-7d9f"},
+		Labels:     map[string]string{"alertname": "KubePodCrashLooping"},
+	}
+	resources.Create(context.Background(), inc)
+
+	if len(api.created) != 1 {
+		t.Fatalf("created %d resources, want 1", len(api.created))
+	}
+	created := api.created[0].(Incident)
+	if created.Metadata.Name != "nc-2024-0613-prod-east-0042" || created.Spec.ResourceName != "web-7d9f" || created.Metadata.Labels["nightcrier.io/cluster"] != "prod-east" ||
+		created.Spec.Labels["alertname"] != "KubePodCrashLooping" {
+		t.Errorf("created = %+v", created)
+	}
+
+	inc.Status = incident.StatusResolved
+	resources.UpdateStatus(context.Background(), inc, &IncidentResult{RootCause: "Bad image tag", Confidence: "HIGH"})
+	status := api.statuses["nc-2024-0613-prod-east-0042"].(IncidentStatus)
+	if status.Phase != PhaseResolved || status.RootCause != "Bad image tag" {
+		t.Errorf("status = %+v", status)
+	}
+}
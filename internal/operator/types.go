@@ -0,0 +1,180 @@
+package operator
+
+import (
+	"time"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/incluster"
+)
+
+// Group and version of nightcrier's custom resources (see deploy/operator).
+const (
+	Group   = "nightcrier.io"
+	Version = "v1alpha1"
+)
+
+// Custom resource types
+var (
+	ClusterTargets        = incluster.Resource{Group: Group, Version: Version, Resource: "clustertargets"}
+	InvestigationPolicies = incluster.Resource{Group: Group, Version: Version, Resource: "investigationpolicies"}
+	Incidents             = incluster.Resource{Group: Group, Version: Version, Resource: "incidents"}
+)
+
+// ObjectMeta is the part of a resource's metadata nightcrier reads and writes.
+type ObjectMeta struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Generation      int64             `json:"generation,omitempty"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+}
+
+// BudgetSpec declares daily investigation limits (0 = unlimited, or inherit for a
+// cluster target).
+type BudgetSpec struct {
+	MaxInvestigationsPerDay          int     `json:"maxInvestigationsPerDay,omitempty"`
+	MaxSpendPerDayUSD                float64 `json:"maxSpendPerDayUSD,omitempty"`
+	EstimatedCostPerInvestigationUSD float64 `json:"estimatedCostPerInvestigationUSD,omitempty"`
+}
+
+// config returns the budget as cluster budget settings.
+func (b *BudgetSpec) config() cluster.BudgetConfig {
+	if b == nil {
+		return cluster.BudgetConfig{}
+	}
+	return cluster.BudgetConfig{
+		MaxInvestigationsPerDay:          b.MaxInvestigationsPerDay,
+		MaxSpendPerDayUSD:                b.MaxSpendPerDayUSD,
+		EstimatedCostPerInvestigationUSD: b.EstimatedCostPerInvestigationUSD,
+	}
+}
+
+// ClusterTarget declares a monitored cluster. It carries the same settings as an
+// entry of the config file's clusters array; the resource name is the cluster name.
+type ClusterTarget struct {
+	Metadata ObjectMeta        `json:"metadata"`
+	Spec     ClusterTargetSpec `json:"spec"`
+}
+
+// ClusterTargetSpec is the desired configuration of a monitored cluster.
+type ClusterTargetSpec struct {
+	Environment string            `json:"environment,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	MCP         struct {
+		Endpoint string `json:"endpoint"`
+	} `json:"mcp"`
+	Triage struct {
+		Enabled bool `json:"enabled,omitempty"`
+		// Kubeconfig is the path of the triage kubeconfig inside the nightcrier pod,
+		// typically a mounted Secret
+		Kubeconfig         string `json:"kubeconfig,omitempty"`
+		AllowSecretsAccess bool   `json:"allowSecretsAccess,omitempty"`
+	} `json:"triage"`
+	ServeOnly bool        `json:"serveOnly,omitempty"`
+	Budget    *BudgetSpec `json:"budget,omitempty"`
+	Canary    *struct {
+		Namespace    string `json:"namespace"`
+		ResourceKind string `json:"resourceKind"`
+		ResourceName string `json:"resourceName"`
+	} `json:"canary,omitempty"`
+}
+
+// ClusterConfig returns the cluster configuration the target declares.
+func (t ClusterTarget) ClusterConfig() cluster.ClusterConfig {
+	cfg := cluster.ClusterConfig{
+		Name:        t.Metadata.Name,
+		Environment: t.Spec.Environment,
+		Labels:      t.Spec.Labels,
+		MCP:         cluster.MCPConfig{Endpoint: t.Spec.MCP.Endpoint},
+		Triage: cluster.TriageConfig{
+			Enabled:            t.Spec.Triage.Enabled,
+			Kubeconfig:         t.Spec.Triage.Kubeconfig,
+			AllowSecretsAccess: t.Spec.Triage.AllowSecretsAccess,
+		},
+		ServeOnly: t.Spec.ServeOnly,
+		Budget:    t.Spec.Budget.config(),
+	}
+	if c := t.Spec.Canary; c != nil {
+		cfg.Canary = cluster.CanaryTarget{Namespace: c.Namespace, ResourceKind: c.ResourceKind, ResourceName: c.ResourceName}
+	}
+	return cfg
+}
+
+// InvestigationPolicy declares how faults are investigated. Fields left unset keep
+// the config file's value.
+type InvestigationPolicy struct {
+	Metadata ObjectMeta              `json:"metadata"`
+	Spec     InvestigationPolicySpec `json:"spec"`
+}
+
+// InvestigationPolicySpec is the desired investigation policy.
+type InvestigationPolicySpec struct {
+	// SeverityThreshold is the minimum severity investigated (DEBUG to CRITICAL)
+	SeverityThreshold string `json:"severityThreshold,omitempty"`
+	// MaxConcurrentAgents caps the agents running at once
+	MaxConcurrentAgents int `json:"maxConcurrentAgents,omitempty"`
+	// AgentTimeoutSeconds bounds each investigation
+	AgentTimeoutSeconds int `json:"agentTimeoutSeconds,omitempty"`
+	// FailureThresholdForAlert is the number of consecutive agent failures that
+	// opens the circuit breaker
+	FailureThresholdForAlert int `json:"failureThresholdForAlert,omitempty"`
+	// ReportLanguage is the language of reports and notifications
+	ReportLanguage string `json:"reportLanguage,omitempty"`
+	// Budget is the default daily budget of every cluster
+	Budget *BudgetSpec `json:"budget,omitempty"`
+}
+
+// Incident phases, following the incident status
+const (
+	PhaseReceived            = "Received"
+	PhasePending             = "Pending"
+	PhaseQueued              = "Queued"
+	PhaseInvestigating       = "Investigating"
+	PhaseAwaitingReview      = "AwaitingReview"
+	PhaseRemediationProposed = "RemediationProposed"
+	PhaseResolved            = "Resolved"
+	PhaseFailed              = "Failed"
+	PhaseAgentFailed         = "AgentFailed"
+	PhaseSuppressed          = "Suppressed"
+	PhaseExpired             = "Expired"
+)
+
+// Incident records one incident as a resource.
+type Incident struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Metadata   ObjectMeta     `json:"metadata"`
+	Spec       IncidentSpec   `json:"spec"`
+	Status     IncidentStatus `json:"status,omitempty"`
+}
+
+// IncidentSpec describes the fault an incident is about. It does not change after
+// the incident is created.
+type IncidentSpec struct {
+	IncidentID       string `json:"incidentID"`
+	DisplayID        string `json:"displayID,omitempty"`
+	Cluster          string `json:"cluster"`
+	Namespace        string `json:"namespace,omitempty"`
+	ResourceKind     string `json:"resourceKind,omitempty"`
+	ResourceName     string `json:"resourceName,omitempty"`
+	FaultType        string `json:"faultType,omitempty"`
+	Severity         string `json:"severity,omitempty"`
+	Context          string `json:"context,omitempty"`
+	FaultID          string `json:"faultID,omitempty"`
+	ParentIncidentID string `json:"parentIncidentID,omitempty"`
+	// Labels are the incident's labels when it was created, including those
+	// passed through from the fault event
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// IncidentStatus follows the investigation.
+type IncidentStatus struct {
+	Phase         string     `json:"phase,omitempty"`
+	StartedAt     *time.Time `json:"startedAt,omitempty"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty"`
+	FailureReason string     `json:"failureReason,omitempty"`
+	FailureClass  string     `json:"failureClass,omitempty"`
+	RootCause     string     `json:"rootCause,omitempty"`
+	Confidence    string     `json:"confidence,omitempty"`
+	ReportURL     string     `json:"reportURL,omitempty"`
+}
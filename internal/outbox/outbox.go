@@ -0,0 +1,263 @@
+// Package outbox delivers outbound work (notifications, artifact uploads) that must
+// survive a restart. Each job is written to a spool directory before delivery is
+// attempted and removed once it succeeds, so work that is still pending when the
+// process stops is retried at the next startup instead of being lost.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Delivery defaults.
+const (
+	DefaultMaxAttempts = 5
+	DefaultRetryDelay  = 5 * time.Second
+)
+
+// Job is a unit of outbound work as stored in the spool directory.
+type Job struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// Handler delivers the payload of one job kind. A returned error schedules a retry.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Queue delivers jobs in the background, retrying failures with backoff. Jobs that
+// exhaust their attempts are moved to the spool's "failed" subdirectory.
+// It is safe for concurrent use.
+type Queue struct {
+	dir         string
+	maxAttempts int
+	retryDelay  time.Duration
+	handlers    map[string]Handler
+
+	// ctx is cancelled when the drain deadline passes; delivery stops and the
+	// remaining jobs stay in the spool
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	draining bool
+	pending  sync.WaitGroup
+}
+
+// New creates a queue spooling jobs in dir.
+func New(dir string, maxAttempts int, retryDelay time.Duration) (*Queue, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "failed"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Queue{
+		dir:         dir,
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+		handlers:    make(map[string]Handler),
+		ctx:         ctx,
+		cancel:      cancel,
+	}, nil
+}
+
+// Handle registers the handler for a job kind. Handlers must be registered before
+// jobs of that kind are enqueued or recovered.
+func (q *Queue) Handle(kind string, h Handler) {
+	q.handlers[kind] = h
+}
+
+// Enqueue persists a job and starts delivering it. While the queue is draining the
+// job is only persisted, to be delivered after the next startup.
+func (q *Queue) Enqueue(kind string, payload interface{}) error {
+	if _, ok := q.handlers[kind]; !ok {
+		return fmt.Errorf("no outbox handler for job kind %q", kind)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s job: %w", kind, err)
+	}
+	job := &Job{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		Payload:   data,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := q.save(job); err != nil {
+		return err
+	}
+	q.start(job)
+	return nil
+}
+
+// Recover starts delivering the jobs left in the spool by a previous run, oldest
+// first. It returns the number of recovered jobs.
+func (q *Queue) Recover() (int, error) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read outbox directory: %w", err)
+	}
+
+	var jobs []*Job
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(q.dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read outbox job %s: %w", entry.Name(), err)
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			slog.Warn("moving unreadable outbox job aside", "file", entry.Name(), "error", err)
+			os.Rename(path, filepath.Join(q.dir, "failed", entry.Name()))
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+	for _, job := range jobs {
+		q.start(job)
+	}
+	return len(jobs), nil
+}
+
+// Drain stops starting new deliveries and waits until pending jobs finish or ctx
+// is done, at which point delivery is abandoned and the remaining jobs stay in the
+// spool for the next startup. It returns the number of jobs left in the spool.
+func (q *Queue) Drain(ctx context.Context) int {
+	q.mu.Lock()
+	q.draining = true
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	q.cancel()
+	return q.Len()
+}
+
+// Len returns the number of jobs in the spool.
+func (q *Queue) Len() int {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			n++
+		}
+	}
+	return n
+}
+
+// start delivers a job in the background unless the queue is draining.
+func (q *Queue) start(job *Job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.draining {
+		return
+	}
+	q.pending.Add(1)
+	go func() {
+		defer q.pending.Done()
+		q.deliver(job)
+	}()
+}
+
+// deliver runs the job's handler until it succeeds, attempts are exhausted, or the
+// drain deadline passes.
+func (q *Queue) deliver(job *Job) {
+	log := slog.With("job_id", job.ID, "kind", job.Kind)
+	handler := q.handlers[job.Kind]
+	if handler == nil {
+		log.Error("no outbox handler for job kind; moving job aside")
+		q.fail(job)
+		return
+	}
+
+	delay := q.retryDelay
+	for {
+		err := handler(q.ctx, job.Payload)
+		if err == nil {
+			if err := os.Remove(q.path(job)); err != nil && !os.IsNotExist(err) {
+				log.Warn("failed to remove delivered outbox job", "error", err)
+			}
+			return
+		}
+		if q.ctx.Err() != nil {
+			log.Warn("outbox delivery interrupted by shutdown; job kept for retry", "error", err)
+			return
+		}
+
+		job.Attempts++
+		if job.Attempts >= q.maxAttempts {
+			log.Error("outbox delivery failed; giving up", "attempts", job.Attempts, "error", err)
+			q.fail(job)
+			return
+		}
+		log.Warn("outbox delivery failed; will retry", "attempts", job.Attempts, "retry_in", delay, "error", err)
+		if err := q.save(job); err != nil {
+			log.Warn("failed to record outbox delivery attempt", "error", err)
+		}
+
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// save writes a job to the spool atomically.
+func (q *Queue) save(job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox job: %w", err)
+	}
+	tmp := q.path(job) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write outbox job: %w", err)
+	}
+	if err := os.Rename(tmp, q.path(job)); err != nil {
+		return fmt.Errorf("failed to write outbox job: %w", err)
+	}
+	return nil
+}
+
+// fail moves a job to the failed subdirectory for manual inspection.
+func (q *Queue) fail(job *Job) {
+	if err := os.Rename(q.path(job), filepath.Join(q.dir, "failed", filepath.Base(q.path(job)))); err != nil && !os.IsNotExist(err) {
+		slog.Warn("failed to move outbox job aside", "job_id", job.ID, "error", err)
+	}
+}
+
+// path returns the spool file of a job.
+func (q *Queue) path(job *Job) string {
+	return filepath.Join(q.dir, job.Kind+"-"+job.ID+".json")
+}
@@ -0,0 +1,115 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueue_DeliversAndRemovesJob(t *testing.T) {
+	dir := t.TempDir()
+	q, err := New(dir, 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	delivered := make(chan string, 1)
+	q.Handle("notification", func(ctx context.Context, payload json.RawMessage) error {
+		var msg string
+		json.Unmarshal(payload, &msg)
+		delivered <- msg
+		return nil
+	})
+
+	if err := q.Enqueue("notification", "hello"); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if got := <-delivered; got != "hello" {
+		t.Errorf("delivered %q, want hello", got)
+	}
+	if left := q.Drain(context.Background()); left != 0 {
+		t.Errorf("Drain() left %d jobs, want 0", left)
+	}
+}
+
+func TestQueue_RetriesThenMovesJobAside(t *testing.T) {
+	dir := t.TempDir()
+	q, _ := New(dir, 3, time.Millisecond)
+
+	var attempts int32
+	q.Handle("upload", func(ctx context.Context, payload json.RawMessage) error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("storage unavailable")
+	})
+	if err := q.Enqueue("upload", map[string]string{"incidentId": "inc-1"}); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	q.Drain(context.Background())
+
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+	failed, _ := os.ReadDir(filepath.Join(dir, "failed"))
+	if len(failed) != 1 || q.Len() != 0 {
+		t.Errorf("failed jobs = %d, spool = %d; want the job moved to failed/", len(failed), q.Len())
+	}
+}
+
+func TestQueue_DrainDeadlineKeepsJobForNextStartup(t *testing.T) {
+	dir := t.TempDir()
+	q, _ := New(dir, 5, time.Hour)
+
+	// The destination is down for the whole shutdown window
+	q.Handle("notification", func(ctx context.Context, payload json.RawMessage) error {
+		return errors.New("webhook unreachable")
+	})
+	if err := q.Enqueue("notification", "lost?"); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if left := q.Drain(ctx); left != 1 {
+		t.Fatalf("Drain() left %d jobs, want 1", left)
+	}
+
+	// Work enqueued during shutdown is persisted but not started
+	if err := q.Enqueue("notification", "late"); err != nil {
+		t.Fatalf("Enqueue() while draining error = %v", err)
+	}
+	if q.Len() != 2 {
+		t.Errorf("spool = %d jobs, want 2", q.Len())
+	}
+
+	// The next process delivers both
+	next, _ := New(dir, 5, time.Millisecond)
+	delivered := make(chan string, 2)
+	next.Handle("notification", func(ctx context.Context, payload json.RawMessage) error {
+		var msg string
+		json.Unmarshal(payload, &msg)
+		delivered <- msg
+		return nil
+	})
+	recovered, err := next.Recover()
+	if err != nil || recovered != 2 {
+		t.Fatalf("Recover() = %d, %v; want 2 jobs", recovered, err)
+	}
+	if left := next.Drain(context.Background()); left != 0 {
+		t.Errorf("Drain() after recovery left %d jobs", left)
+	}
+	if len(delivered) != 2 {
+		t.Errorf("delivered %d recovered jobs, want 2", len(delivered))
+	}
+}
+
+func TestQueue_EnqueueUnknownKind(t *testing.T) {
+	q, _ := New(t.TempDir(), 1, time.Millisecond)
+	if err := q.Enqueue("fax", "x"); err == nil {
+		t.Error("Enqueue() should reject a kind without a handler")
+	}
+}
@@ -0,0 +1,135 @@
+package ownership
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rbias/nightcrier/internal/kubeobjects"
+)
+
+// Default annotation and label keys holding ownership, most specific first
+var (
+	DefaultTeamKeys    = []string{"nightcrier.io/team", "owner", "team"}
+	DefaultServiceKeys = []string{"nightcrier.io/service", "app.kubernetes.io/part-of", "service"}
+	DefaultContactKeys = []string{"nightcrier.io/contact", "contact"}
+)
+
+// AnnotationsConfig configures an annotations source.
+type AnnotationsConfig struct {
+	// Endpoints maps cluster names to their MCP server endpoints
+	Endpoints map[string]string
+	// TeamKeys, ServiceKeys, and ContactKeys are the annotation and label keys
+	// holding the owner, most specific first. Defaults: DefaultTeamKeys,
+	// DefaultServiceKeys, DefaultContactKeys.
+	TeamKeys    []string
+	ServiceKeys []string
+	ContactKeys []string
+}
+
+// AnnotationsSource reads owners from the annotations and labels of the affected
+// object, its workload (the Deployment or StatefulSet of a pod), and its
+// namespace, in that order, through the cluster's MCP server. The first object
+// naming a team wins; on each object the keys are tried in order, each in the
+// annotations and then in the labels.
+type AnnotationsSource struct {
+	config     AnnotationsConfig
+	httpClient *http.Client
+}
+
+// NewAnnotationsSource returns an annotations source. A nil httpClient uses
+// http.DefaultClient.
+func NewAnnotationsSource(config AnnotationsConfig, httpClient *http.Client) *AnnotationsSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if len(config.TeamKeys) == 0 {
+		config.TeamKeys = DefaultTeamKeys
+	}
+	if len(config.ServiceKeys) == 0 {
+		config.ServiceKeys = DefaultServiceKeys
+	}
+	if len(config.ContactKeys) == 0 {
+		config.ContactKeys = DefaultContactKeys
+	}
+	return &AnnotationsSource{config: config, httpClient: httpClient}
+}
+
+// Name implements Source.
+func (s *AnnotationsSource) Name() string {
+	return "annotations"
+}
+
+// objectMeta is the metadata of an object returned by resources_get.
+type objectMeta struct {
+	Metadata struct {
+		Labels      map[string]string `yaml:"labels"`
+		Annotations map[string]string `yaml:"annotations"`
+	} `yaml:"metadata"`
+}
+
+// Lookup implements Source.
+func (s *AnnotationsSource) Lookup(ctx context.Context, q Query) (*Owner, error) {
+	endpoint, ok := s.config.Endpoints[q.Cluster]
+	if !ok {
+		return nil, nil
+	}
+
+	objects, err := kubeobjects.Connect(ctx, endpoint, "nightcrier-ownership", s.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	defer objects.Close()
+
+	for _, ref := range objectsToCheck(q) {
+		var meta objectMeta
+		found, err := objects.Get(ctx, ref, &meta)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		owner := &Owner{
+			Team:    metaValue(&meta, s.config.TeamKeys),
+			Service: metaValue(&meta, s.config.ServiceKeys),
+			Contact: metaValue(&meta, s.config.ContactKeys),
+		}
+		if owner.Team != "" {
+			return owner, nil
+		}
+	}
+	return nil, nil
+}
+
+// objectsToCheck returns the objects whose metadata may name the owner of the
+// queried resource, most specific first.
+func objectsToCheck(q Query) []kubeobjects.Ref {
+	var refs []kubeobjects.Ref
+	if q.Kind != "" && q.Name != "" {
+		refs = append(refs, kubeobjects.Ref{Kind: q.Kind, Namespace: q.Namespace, Name: q.Name})
+	}
+	if workload := q.Workload(); q.Kind == "Pod" && workload != q.Name {
+		kind := "Deployment"
+		if statefulSetPodName.MatchString(q.Name) && !deploymentPodName.MatchString(q.Name) {
+			kind = "StatefulSet"
+		}
+		refs = append(refs, kubeobjects.Ref{Kind: kind, Namespace: q.Namespace, Name: workload})
+	}
+	if q.Namespace != "" {
+		refs = append(refs, kubeobjects.Ref{Kind: "Namespace", Name: q.Namespace})
+	}
+	return refs
+}
+
+// metaValue returns the value of the first key set in the annotations or labels.
+func metaValue(meta *objectMeta, keys []string) string {
+	for _, key := range keys {
+		if value := meta.Metadata.Annotations[key]; value != "" {
+			return value
+		}
+		if value := meta.Metadata.Labels[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
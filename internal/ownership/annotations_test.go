@@ -0,0 +1,63 @@
+package ownership
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rbias/nightcrier/internal/kubeobjects"
+)
+
+// resourcesGetArgs are the arguments of the resources_get tool
+type resourcesGetArgs struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// newFakeMCPServer serves resources_get from objects, keyed by "kind/name".
+func newFakeMCPServer(t *testing.T, objects map[string]string) *httptest.Server {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "fake-kubernetes-mcp-server", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: kubeobjects.ResourcesGetTool}, func(ctx context.Context, req *mcp.CallToolRequest, args resourcesGetArgs) (*mcp.CallToolResult, any, error) {
+		object, ok := objects[args.Kind+"/"+args.Name]
+		if !ok {
+			return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "not found"}}}, nil, nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: object}}}, nil, nil
+	})
+	srv := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAnnotationsSource_Lookup(t *testing.T) {
+	srv := newFakeMCPServer(t, map[string]string{
+		// The pod carries only the service; the deployment names the team
+		"Pod/checkout-7d9f8b6c5d-x2k4p": "metadata:\n  name: checkout-7d9f8b6c5d-x2k4p\n  labels:\n    app.kubernetes.io/part-of: checkout\n",
+		"Deployment/checkout":           "metadata:\n  name: checkout\n  labels:\n    team: web\n  annotations:\n    nightcrier.io/team: payments\n    nightcrier.io/contact: '#payments-oncall'\n",
+		"Namespace/search":              "metadata:\n  name: search\n  labels:\n    team: search\n",
+	})
+	source := NewAnnotationsSource(AnnotationsConfig{Endpoints: map[string]string{"prod": srv.URL}}, nil)
+
+	owner, err := source.Lookup(context.Background(), Query{Cluster: "prod", Namespace: "shop", Kind: "Pod", Name: "checkout-7d9f8b6c5d-x2k4p"})
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if owner == nil || owner.Team != "payments" || owner.Contact != "#payments-oncall" {
+		t.Errorf("Lookup() = %+v, want team payments from the deployment", owner)
+	}
+
+	owner, err = source.Lookup(context.Background(), Query{Cluster: "prod", Namespace: "search", Kind: "Pod", Name: "indexer-0"})
+	if err != nil || owner == nil || owner.Team != "search" {
+		t.Errorf("Lookup() from the namespace = %+v, %v, want search", owner, err)
+	}
+
+	owner, err = source.Lookup(context.Background(), Query{Cluster: "staging", Namespace: "shop", Kind: "Pod", Name: "x"})
+	if err != nil || owner != nil {
+		t.Errorf("Lookup() on a cluster without an endpoint = %+v, %v, want nil", owner, err)
+	}
+}
@@ -0,0 +1,135 @@
+package ownership
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Annotations the Backstage Kubernetes plugin uses to link components to workloads
+const (
+	backstageKubernetesID        = "backstage.io/kubernetes-id"
+	backstageKubernetesNamespace = "backstage.io/kubernetes-namespace"
+)
+
+// BackstageSource looks up owners in a Backstage software catalog. A component
+// owns a workload when its backstage.io/kubernetes-id annotation names the
+// workload; failing that, a component whose backstage.io/kubernetes-namespace
+// annotation names the namespace owns everything in it. The owner is the
+// component's spec.owner (its group) and the service is the component.
+type BackstageSource struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewBackstageSource returns a source querying the Backstage instance at baseURL,
+// authenticating with token when set. A nil httpClient uses http.DefaultClient.
+func NewBackstageSource(baseURL, token string, httpClient *http.Client) *BackstageSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &BackstageSource{baseURL: strings.TrimSuffix(baseURL, "/"), token: token, httpClient: httpClient}
+}
+
+// Name implements Source.
+func (s *BackstageSource) Name() string {
+	return "backstage"
+}
+
+// backstageEntity is the subset of a catalog entity used for ownership.
+type backstageEntity struct {
+	Metadata struct {
+		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		Owner string `json:"owner"`
+	} `json:"spec"`
+}
+
+// Lookup implements Source.
+func (s *BackstageSource) Lookup(ctx context.Context, q Query) (*Owner, error) {
+	byWorkload, err := s.components(ctx, "metadata.annotations."+backstageKubernetesID+"="+q.Workload())
+	if err != nil {
+		return nil, err
+	}
+	// A component naming another namespace tracks a different workload of the same name
+	for _, entity := range byWorkload {
+		if ns := entity.Metadata.Annotations[backstageKubernetesNamespace]; ns == "" || ns == q.Namespace {
+			return s.owner(entity), nil
+		}
+	}
+
+	if q.Namespace == "" {
+		return nil, nil
+	}
+	byNamespace, err := s.components(ctx, "metadata.annotations."+backstageKubernetesNamespace+"="+q.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(byNamespace) > 0 {
+		return s.owner(byNamespace[0]), nil
+	}
+	return nil, nil
+}
+
+// components lists the catalog components matching filter.
+func (s *BackstageSource) components(ctx context.Context, filter string) ([]backstageEntity, error) {
+	params := url.Values{}
+	params.Set("filter", "kind=component,"+filter)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/api/catalog/entities?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backstage request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query backstage catalog: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("backstage returned status %d: %s", resp.StatusCode, body)
+	}
+
+	var entities []backstageEntity
+	if err := json.NewDecoder(resp.Body).Decode(&entities); err != nil {
+		return nil, fmt.Errorf("failed to decode backstage response: %w", err)
+	}
+	return entities, nil
+}
+
+// owner converts a component to its owner.
+func (s *BackstageSource) owner(entity backstageEntity) *Owner {
+	namespace := entity.Metadata.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	return &Owner{
+		Team:    entityRefName(entity.Spec.Owner),
+		Service: entity.Metadata.Name,
+		URL:     fmt.Sprintf("%s/catalog/%s/component/%s", s.baseURL, namespace, entity.Metadata.Name),
+	}
+}
+
+// entityRefName returns the name of an entity reference such as
+// "group:default/payments".
+func entityRefName(ref string) string {
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	if i := strings.Index(ref, ":"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	return ref
+}
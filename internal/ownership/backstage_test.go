@@ -0,0 +1,62 @@
+package ownership
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBackstageSource_Lookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/catalog/entities" {
+			http.NotFound(w, r)
+			return
+		}
+		filter := r.URL.Query().Get("filter")
+		switch {
+		case strings.HasSuffix(filter, "backstage.io/kubernetes-id=checkout"):
+			_, _ = w.Write([]byte(`[
+				{"metadata": {"name": "checkout-staging", "annotations": {"backstage.io/kubernetes-namespace": "staging"}}, "spec": {"owner": "group:default/qa"}},
+				{"metadata": {"name": "checkout", "namespace": "shop", "annotations": {"backstage.io/kubernetes-namespace": "shop"}}, "spec": {"owner": "group:default/payments"}}
+			]`))
+		case strings.HasSuffix(filter, "backstage.io/kubernetes-namespace=search"):
+			_, _ = w.Write([]byte(`[{"metadata": {"name": "search-platform"}, "spec": {"owner": "search-team"}}]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer srv.Close()
+	source := NewBackstageSource(srv.URL+"/", "", nil)
+
+	owner, err := source.Lookup(context.Background(), Query{Namespace: "shop", Kind: "Pod", Name: "checkout-7d9f8b6c5d-x2k4p"})
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if owner == nil || owner.Team != "payments" || owner.Service != "checkout" || owner.URL != srv.URL+"/catalog/shop/component/checkout" {
+		t.Errorf("Lookup() by workload = %+v, want payments/checkout in namespace shop", owner)
+	}
+
+	owner, err = source.Lookup(context.Background(), Query{Namespace: "search", Kind: "Deployment", Name: "indexer"})
+	if err != nil || owner == nil || owner.Team != "search-team" || owner.Service != "search-platform" {
+		t.Errorf("Lookup() by namespace = %+v, %v, want search-team", owner, err)
+	}
+
+	owner, err = source.Lookup(context.Background(), Query{Namespace: "other", Kind: "Deployment", Name: "unknown"})
+	if err != nil || owner != nil {
+		t.Errorf("Lookup() of an unknown workload = %+v, %v, want nil", owner, err)
+	}
+}
+
+func TestEntityRefName(t *testing.T) {
+	for ref, want := range map[string]string{
+		"group:default/payments": "payments",
+		"group:payments":         "payments",
+		"payments":               "payments",
+	} {
+		if got := entityRefName(ref); got != want {
+			t.Errorf("entityRefName(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
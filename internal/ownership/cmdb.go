@@ -0,0 +1,139 @@
+package ownership
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CMDBConfig configures a CMDB source.
+type CMDBConfig struct {
+	// URL is the lookup URL. The placeholders {cluster}, {namespace}, {kind},
+	// {name}, and {workload} are replaced with the queried workload, e.g.
+	// "https://cmdb.example.com/api/owners?namespace={namespace}&app={workload}".
+	URL string
+	// Token is sent as a bearer token when set
+	Token string
+	// TeamField, ServiceField, ContactField, and URLField are the dotted paths of
+	// the owner fields in the JSON response, e.g. "result.assignment_group.name".
+	// A list in the path selects its first element. Defaults: "team", "service",
+	// "contact", "url".
+	TeamField    string
+	ServiceField string
+	ContactField string
+	URLField     string
+}
+
+// CMDBSource looks up owners with an HTTP GET to a CMDB or any ownership API
+// returning JSON. A 404 response, or a response without a team or service, means
+// the CMDB does not know the workload.
+type CMDBSource struct {
+	config     CMDBConfig
+	httpClient *http.Client
+}
+
+// NewCMDBSource returns a CMDB source. A nil httpClient uses http.DefaultClient.
+func NewCMDBSource(config CMDBConfig, httpClient *http.Client) *CMDBSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if config.TeamField == "" {
+		config.TeamField = "team"
+	}
+	if config.ServiceField == "" {
+		config.ServiceField = "service"
+	}
+	if config.ContactField == "" {
+		config.ContactField = "contact"
+	}
+	if config.URLField == "" {
+		config.URLField = "url"
+	}
+	return &CMDBSource{config: config, httpClient: httpClient}
+}
+
+// Name implements Source.
+func (s *CMDBSource) Name() string {
+	return "cmdb"
+}
+
+// Lookup implements Source.
+func (s *CMDBSource) Lookup(ctx context.Context, q Query) (*Owner, error) {
+	lookupURL := strings.NewReplacer(
+		"{cluster}", url.QueryEscape(q.Cluster),
+		"{namespace}", url.QueryEscape(q.Namespace),
+		"{kind}", url.QueryEscape(q.Kind),
+		"{name}", url.QueryEscape(q.Name),
+		"{workload}", url.QueryEscape(q.Workload()),
+	).Replace(s.config.URL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, lookupURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CMDB request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CMDB: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("CMDB returned status %d: %s", resp.StatusCode, body)
+	}
+
+	var document interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode CMDB response: %w", err)
+	}
+	owner := &Owner{
+		Team:    jsonField(document, s.config.TeamField),
+		Service: jsonField(document, s.config.ServiceField),
+		Contact: jsonField(document, s.config.ContactField),
+		URL:     jsonField(document, s.config.URLField),
+	}
+	if owner.Team == "" && owner.Service == "" {
+		return nil, nil
+	}
+	return owner, nil
+}
+
+// jsonField returns the string or number at a dotted path of a decoded JSON
+// document, descending into the first element of lists, or "" when absent.
+func jsonField(document interface{}, path string) string {
+	value := document
+	for _, key := range strings.Split(path, ".") {
+		if list, ok := value.([]interface{}); ok {
+			if len(list) == 0 {
+				return ""
+			}
+			value = list[0]
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = object[key]
+	}
+	if list, ok := value.([]interface{}); ok && len(list) > 0 {
+		value = list[0]
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
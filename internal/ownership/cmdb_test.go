@@ -0,0 +1,58 @@
+package ownership
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCMDBSource_Lookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("Authorization = %q, want the bearer token", r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("app") != "checkout" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("cluster") != "prod" || r.URL.Query().Get("ns") != "shop" {
+			t.Errorf("query = %s, want the cluster and namespace substituted", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"result": [{"support_group": {"name": "payments"}, "name": "checkout", "oncall": "payments@example.com"}]}`))
+	}))
+	defer srv.Close()
+
+	source := NewCMDBSource(CMDBConfig{
+		URL:          srv.URL + "/owners?cluster={cluster}&ns={namespace}&app={workload}",
+		Token:        "tok",
+		TeamField:    "result.support_group.name",
+		ServiceField: "result.name",
+		ContactField: "result.oncall",
+	}, nil)
+
+	owner, err := source.Lookup(context.Background(), Query{Cluster: "prod", Namespace: "shop", Kind: "Pod", Name: "checkout-7d9f8b6c5d-x2k4p"})
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if owner == nil || owner.Team != "payments" || owner.Service != "checkout" || owner.Contact != "payments@example.com" {
+		t.Errorf("Lookup() = %+v", owner)
+	}
+
+	owner, err = source.Lookup(context.Background(), Query{Cluster: "prod", Namespace: "shop", Kind: "Deployment", Name: "unknown"})
+	if err != nil || owner != nil {
+		t.Errorf("Lookup() of an unknown workload = %+v, %v, want nil (404)", owner, err)
+	}
+}
+
+func TestCMDBSource_LookupError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	source := NewCMDBSource(CMDBConfig{URL: srv.URL + "/owners/{namespace}"}, nil)
+	if _, err := source.Lookup(context.Background(), Query{Namespace: "shop"}); err == nil {
+		t.Error("Lookup() should fail on a server error")
+	}
+}
@@ -0,0 +1,213 @@
+// Package ownership resolves the team and service owning the workload affected by
+// a fault from the systems where organizations record ownership: annotations and
+// labels on the Kubernetes objects, a Backstage software catalog, or a CMDB API.
+// The owner is recorded on the incident, given to the agent, shown in
+// notifications, and used to route notifications to the owning team.
+package ownership
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Owner is the team and service owning a workload.
+type Owner struct {
+	Team    string `json:"team,omitempty"`
+	Service string `json:"service,omitempty"`
+	// Contact is an on-call alias, e-mail address, or chat channel
+	Contact string `json:"contact,omitempty"`
+	// URL links to the owner's entry in the catalog or CMDB
+	URL string `json:"url,omitempty"`
+	// Source is the source the owner was found in, e.g. "backstage"
+	Source string `json:"source"`
+}
+
+// String returns the owner for display, e.g. "payments (service checkout)".
+func (o *Owner) String() string {
+	text := o.Team
+	if text == "" {
+		text = "unknown team"
+	}
+	if o.Service != "" {
+		text += " (service " + o.Service + ")"
+	}
+	return text
+}
+
+// Query identifies the workload whose owner is looked up.
+type Query struct {
+	Cluster   string
+	Namespace string
+	Kind      string
+	Name      string
+}
+
+// Workload returns the name of the workload managing the queried resource: the
+// Deployment of a pod named "<deployment>-<hash>-<suffix>" or the StatefulSet of
+// a pod named "<statefulset>-<ordinal>". Catalogs and CMDBs record workloads, not
+// their pods. Other resources are returned unchanged.
+func (q Query) Workload() string {
+	if q.Kind != "Pod" {
+		return q.Name
+	}
+	if m := deploymentPodName.FindStringSubmatch(q.Name); m != nil {
+		return m[1]
+	}
+	if m := statefulSetPodName.FindStringSubmatch(q.Name); m != nil {
+		return m[1]
+	}
+	return q.Name
+}
+
+var (
+	// deploymentPodName matches pods of a ReplicaSet: a pod-template-hash and a
+	// random five-character suffix
+	deploymentPodName = regexp.MustCompile(`^(.+)-[a-z0-9]{6,10}-[a-z0-9]{5}$`)
+	// statefulSetPodName matches pods of a StatefulSet: an ordinal suffix
+	statefulSetPodName = regexp.MustCompile(`^(.+)-[0-9]+$`)
+)
+
+// Source looks up owners in one system.
+type Source interface {
+	// Name identifies the source in logs and on the owner, e.g. "backstage"
+	Name() string
+	// Lookup returns the owner of the queried workload, or nil when the source
+	// does not know it.
+	Lookup(ctx context.Context, q Query) (*Owner, error)
+}
+
+// lookupTimeout bounds the lookup of one workload across all sources
+const lookupTimeout = 10 * time.Second
+
+// Resolver looks up owners in its sources in order; the first source knowing the
+// workload wins. Results, including unknown owners, are cached per workload so
+// that repeated faults do not query the sources again.
+type Resolver struct {
+	sources []Source
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu    sync.Mutex
+	cache map[cacheKey]cacheEntry
+}
+
+// cacheKey identifies a workload in the cache
+type cacheKey struct {
+	cluster, namespace, kind, workload string
+}
+
+// cacheEntry is a cached lookup result; owner is nil for an unknown owner
+type cacheEntry struct {
+	owner   *Owner
+	expires time.Time
+}
+
+// NewResolver returns a resolver querying sources in order and caching results
+// for ttl (0 disables caching).
+func NewResolver(ttl time.Duration, sources ...Source) *Resolver {
+	return &Resolver{
+		sources: sources,
+		ttl:     ttl,
+		now:     time.Now,
+		cache:   make(map[cacheKey]cacheEntry),
+	}
+}
+
+// Sources returns the names of the resolver's sources, in lookup order.
+func (r *Resolver) Sources() []string {
+	names := make([]string, len(r.sources))
+	for i, source := range r.sources {
+		names[i] = source.Name()
+	}
+	return names
+}
+
+// Resolve returns the owner of the queried workload, or nil when no source knows
+// it. Source failures are logged and the next source is tried; they are not
+// cached, so the lookup is retried for the next fault.
+func (r *Resolver) Resolve(ctx context.Context, q Query) *Owner {
+	key := cacheKey{q.Cluster, q.Namespace, q.Kind, q.Workload()}
+	r.mu.Lock()
+	entry, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && r.now().Before(entry.expires) {
+		return entry.owner
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	var owner *Owner
+	failed := false
+	for _, source := range r.sources {
+		found, err := source.Lookup(ctx, q)
+		if err != nil {
+			slog.Warn("ownership lookup failed",
+				"source", source.Name(),
+				"cluster", q.Cluster,
+				"namespace", q.Namespace,
+				"workload", key.workload,
+				"error", err)
+			failed = true
+			continue
+		}
+		if found != nil {
+			found.Source = source.Name()
+			owner = found
+			break
+		}
+	}
+
+	if r.ttl > 0 && (owner != nil || !failed) {
+		r.mu.Lock()
+		r.cache[key] = cacheEntry{owner: owner, expires: r.now().Add(r.ttl)}
+		r.mu.Unlock()
+	}
+	return owner
+}
+
+// Labels returns the incident labels recording the owner: "team" and "service".
+func (o *Owner) Labels() map[string]string {
+	found := make(map[string]string, 2)
+	if o.Team != "" {
+		found["team"] = o.Team
+	}
+	if o.Service != "" {
+		found["service"] = o.Service
+	}
+	return found
+}
+
+// PromptSection returns the agent prompt section naming the owner of the affected
+// workload.
+func PromptSection(owner *Owner) string {
+	if owner == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## Ownership\n\n")
+	fmt.Fprintf(&b, "The affected workload is owned by team %s", valueOr(owner.Team, "(unknown)"))
+	if owner.Service != "" {
+		fmt.Fprintf(&b, " as part of the %s service", owner.Service)
+	}
+	fmt.Fprintf(&b, " (according to %s).", owner.Source)
+	if owner.Contact != "" {
+		fmt.Fprintf(&b, " Their contact is %s.", owner.Contact)
+	}
+	b.WriteString(" Address the recommended actions in your report to the owning team, and say so")
+	b.WriteString(" when the evidence shows the cause lies with another team's component.\n")
+	return b.String()
+}
+
+// valueOr returns value, or fallback when value is empty.
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
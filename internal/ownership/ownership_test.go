@@ -0,0 +1,109 @@
+package ownership
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuery_Workload(t *testing.T) {
+	tests := []struct {
+		kind, name, want string
+	}{
+		{"Pod", "checkout-api-7d9f8b6c5d-x2k4p", "checkout-api"},
+		{"Pod", "postgres-0", "postgres"},
+		{"Pod", "standalone", "standalone"},
+		{"Deployment", "checkout-api-0", "checkout-api-0"},
+	}
+	for _, tt := range tests {
+		if got := (Query{Kind: tt.kind, Name: tt.name}).Workload(); got != tt.want {
+			t.Errorf("Workload(%s %s) = %q, want %q", tt.kind, tt.name, got, tt.want)
+		}
+	}
+}
+
+// fakeSource returns a fixed owner or error and counts its lookups.
+type fakeSource struct {
+	name    string
+	owner   *Owner
+	err     error
+	lookups int
+}
+
+func (f *fakeSource) Name() string { return f.name }
+
+func (f *fakeSource) Lookup(ctx context.Context, q Query) (*Owner, error) {
+	f.lookups++
+	if f.owner == nil {
+		return nil, f.err
+	}
+	owner := *f.owner
+	return &owner, f.err
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	failing := &fakeSource{name: "annotations", err: errors.New("mcp unavailable")}
+	unknown := &fakeSource{name: "backstage"}
+	cmdb := &fakeSource{name: "cmdb", owner: &Owner{Team: "payments", Service: "checkout"}}
+	resolver := NewResolver(time.Minute, failing, unknown, cmdb)
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	resolver.now = func() time.Time { return now }
+
+	q := Query{Cluster: "prod", Namespace: "shop", Kind: "Pod", Name: "checkout-7d9f8b6c5d-x2k4p"}
+	owner := resolver.Resolve(context.Background(), q)
+	if owner == nil || owner.Team != "payments" || owner.Source != "cmdb" {
+		t.Fatalf("Resolve() = %+v, want payments from cmdb after the failing and unknown sources", owner)
+	}
+
+	// Another replica of the same workload is served from the cache
+	q.Name = "checkout-7d9f8b6c5d-abcde"
+	if owner := resolver.Resolve(context.Background(), q); owner == nil || owner.Team != "payments" {
+		t.Errorf("cached Resolve() = %+v, want payments", owner)
+	}
+	if cmdb.lookups != 1 {
+		t.Errorf("cmdb lookups = %d, want 1 (cached)", cmdb.lookups)
+	}
+
+	// The cache expires
+	now = now.Add(2 * time.Minute)
+	resolver.Resolve(context.Background(), q)
+	if cmdb.lookups != 2 {
+		t.Errorf("cmdb lookups after expiry = %d, want 2", cmdb.lookups)
+	}
+}
+
+func TestResolver_DoesNotCacheFailures(t *testing.T) {
+	failing := &fakeSource{name: "cmdb", err: errors.New("timeout")}
+	resolver := NewResolver(time.Hour, failing)
+	q := Query{Cluster: "prod", Namespace: "shop", Kind: "Deployment", Name: "api"}
+
+	if owner := resolver.Resolve(context.Background(), q); owner != nil {
+		t.Errorf("Resolve() = %+v, want nil", owner)
+	}
+	resolver.Resolve(context.Background(), q)
+	if failing.lookups != 2 {
+		t.Errorf("lookups = %d, want 2 (a failed lookup is retried)", failing.lookups)
+	}
+
+	unknown := &fakeSource{name: "cmdb"}
+	resolver = NewResolver(time.Hour, unknown)
+	resolver.Resolve(context.Background(), q)
+	resolver.Resolve(context.Background(), q)
+	if unknown.lookups != 1 {
+		t.Errorf("lookups = %d, want 1 (an unknown owner is cached)", unknown.lookups)
+	}
+}
+
+func TestPromptSection(t *testing.T) {
+	if PromptSection(nil) != "" {
+		t.Error("PromptSection(nil) should be empty")
+	}
+	section := PromptSection(&Owner{Team: "payments", Service: "checkout", Contact: "#payments-oncall", Source: "backstage"})
+	for _, want := range []string{"## Ownership", "team payments", "checkout service", "backstage", "#payments-oncall"} {
+		if !strings.Contains(section, want) {
+			t.Errorf("PromptSection() missing %q:\n%s", want, section)
+		}
+	}
+}
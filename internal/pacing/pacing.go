@@ -0,0 +1,290 @@
+// Package pacing spaces out agent launches per LLM provider so that concurrent
+// investigations stay within the provider's rate limits instead of all starting at
+// once and getting throttled together.
+//
+// Each provider has a Pacer. A configured requests-per-minute or tokens-per-minute
+// limit, divided by the estimated usage of one investigation, gives the minimum
+// interval between launches. Priority launches (high-severity incidents) are paced
+// among themselves and go ahead of the queued launches. Independently, every
+// observed throttle (HTTP 429 in agent output) pauses launches with an exponential
+// backoff that resets once an investigation completes without being throttled.
+package pacing
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/fleet"
+)
+
+const (
+	// defaultRequestsPerInvestigation estimates the LLM requests of one investigation
+	defaultRequestsPerInvestigation = 25
+	// defaultTokensPerInvestigation estimates the tokens of one investigation
+	defaultTokensPerInvestigation = 150000
+	// initialThrottleBackoff is the pause after the first observed throttle
+	initialThrottleBackoff = 15 * time.Second
+	// maxThrottleBackoff caps the pause after repeated throttles
+	maxThrottleBackoff = 5 * time.Minute
+	// sharedTimeout bounds one update of the fleet-wide schedule
+	sharedTimeout = 5 * time.Second
+	// sharedTTL is how long the fleet-wide schedule of an idle provider is kept;
+	// it outlasts any realistic queue of paced launches
+	sharedTTL = 24 * time.Hour
+)
+
+// Limits are a provider's rate limits and the estimated usage of one investigation.
+type Limits struct {
+	// RequestsPerMinute and TokensPerMinute are the provider limits. Zero means unlimited.
+	RequestsPerMinute int
+	TokensPerMinute   int
+
+	// RequestsPerInvestigation and TokensPerInvestigation estimate the usage of one
+	// investigation. Zero uses the package defaults.
+	RequestsPerInvestigation int
+	TokensPerInvestigation   int
+}
+
+// Interval returns the minimum time between agent launches implied by the limits.
+func (l Limits) Interval() time.Duration {
+	requests := l.RequestsPerInvestigation
+	if requests == 0 {
+		requests = defaultRequestsPerInvestigation
+	}
+	tokens := l.TokensPerInvestigation
+	if tokens == 0 {
+		tokens = defaultTokensPerInvestigation
+	}
+
+	var interval time.Duration
+	if l.RequestsPerMinute > 0 {
+		interval = time.Minute * time.Duration(requests) / time.Duration(l.RequestsPerMinute)
+	}
+	if l.TokensPerMinute > 0 {
+		if byTokens := time.Minute * time.Duration(tokens) / time.Duration(l.TokensPerMinute); byTokens > interval {
+			interval = byTokens
+		}
+	}
+	return interval
+}
+
+// schedule is the launch schedule of one provider. It is also the JSON document of
+// a provider in the fleet-wide state (see Registry.Share).
+type schedule struct {
+	Next         time.Time     `json:"next"`          // earliest start of the next launch
+	PriorityNext time.Time     `json:"priority_next"` // earliest start of the next priority launch
+	PausedUntil  time.Time     `json:"paused_until"`  // launches are held until then after a throttle
+	Backoff      time.Duration `json:"backoff"`
+}
+
+// reserve hands out the next launch slot, at least interval after the previous one.
+func (s *schedule) reserve(now time.Time, interval time.Duration) time.Time {
+	slot := now
+	if s.Next.After(slot) {
+		slot = s.Next
+	}
+	if s.PausedUntil.After(slot) {
+		slot = s.PausedUntil
+	}
+	s.Next = slot.Add(interval)
+	return slot
+}
+
+// reservePriority hands out a priority launch slot, at least interval after the
+// previous priority slot but ahead of the queued launches. The queued launches not
+// handed out yet move back by one interval, so launches keep to the rate over
+// time, though a launch already handed out may start less than interval after it.
+func (s *schedule) reservePriority(now time.Time, interval time.Duration) time.Time {
+	slot := now
+	if s.PriorityNext.After(slot) {
+		slot = s.PriorityNext
+	}
+	if s.PausedUntil.After(slot) {
+		slot = s.PausedUntil
+	}
+	s.PriorityNext = slot.Add(interval)
+	if s.Next.After(slot) {
+		s.Next = s.Next.Add(interval)
+	} else {
+		s.Next = slot.Add(interval)
+	}
+	return slot
+}
+
+// unreserve gives back a launch slot that was not used, restoring the schedule
+// from before it was reserved, unless a later slot was handed out since: that
+// caller keeps its place.
+func (s *schedule) unreserve(reserved, previous schedule) {
+	if s.Next.Equal(reserved.Next) && s.PriorityNext.Equal(reserved.PriorityNext) {
+		s.Next = previous.Next
+		s.PriorityNext = previous.PriorityNext
+	}
+}
+
+// throttle doubles the backoff (up to the maximum) and pauses launches for it.
+func (s *schedule) throttle(now time.Time) {
+	if s.Backoff == 0 {
+		s.Backoff = initialThrottleBackoff
+	} else if s.Backoff *= 2; s.Backoff > maxThrottleBackoff {
+		s.Backoff = maxThrottleBackoff
+	}
+	s.PausedUntil = now.Add(s.Backoff)
+}
+
+// Pacer schedules agent launches for one provider. It is safe for concurrent use.
+type Pacer struct {
+	provider string
+	interval time.Duration
+	// shared keeps the schedule fleet-wide; nil keeps it in this process
+	shared *fleet.State
+
+	mu    sync.Mutex
+	local schedule
+
+	// now and sleep are replaceable for tests
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+// NewPacer creates a Pacer for a provider.
+func NewPacer(provider string, limits Limits) *Pacer {
+	return &Pacer{
+		provider: provider,
+		interval: limits.Interval(),
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+}
+
+// sleepContext sleeps for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// update applies fn to the provider's schedule: the fleet-wide one when shared,
+// falling back to this process's schedule when the shared state is unavailable.
+func (p *Pacer) update(ctx context.Context, fn func(s *schedule, now time.Time)) {
+	if p.shared != nil {
+		sharedCtx, cancel := context.WithTimeout(ctx, sharedTimeout)
+		defer cancel()
+		err := fleet.Update(sharedCtx, p.shared, "pacing/"+p.provider, sharedTTL, func(current *schedule) (*schedule, error) {
+			if current == nil {
+				current = &schedule{}
+			}
+			fn(current, p.now())
+			return current, nil
+		})
+		if err == nil {
+			return
+		}
+		slog.Warn("fleet-wide launch pacing unavailable, pacing per process",
+			"provider", p.provider,
+			"error", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fn(&p.local, p.now())
+}
+
+// Wait blocks until the caller may launch an agent. Slots are handed out in call
+// order, each at least the configured interval after the previous one; priority
+// launches get theirs ahead of the others (see reservePriority). When ctx is done
+// before the slot, the slot is given back so it does not delay later callers.
+func (p *Pacer) Wait(ctx context.Context, priority bool) error {
+	var slot time.Time
+	var previous, reserved schedule
+	p.update(ctx, func(s *schedule, now time.Time) {
+		previous = *s
+		if priority {
+			slot = s.reservePriority(now, p.interval)
+		} else {
+			slot = s.reserve(now, p.interval)
+		}
+		reserved = *s
+	})
+
+	delay := slot.Sub(p.now())
+	if delay <= 0 {
+		return nil
+	}
+	slog.Info("pacing agent launch for LLM rate limits",
+		"provider", p.provider,
+		"priority", priority,
+		"delay", delay.Round(time.Second))
+	if err := p.sleep(ctx, delay); err != nil {
+		p.update(context.Background(), func(s *schedule, now time.Time) {
+			s.unreserve(reserved, previous)
+		})
+		return err
+	}
+	return nil
+}
+
+// ObserveThrottle records that the provider throttled an agent run, pausing new
+// launches with exponential backoff.
+func (p *Pacer) ObserveThrottle() {
+	var backoff time.Duration
+	p.update(context.Background(), func(s *schedule, now time.Time) {
+		s.throttle(now)
+		backoff = s.Backoff
+	})
+	slog.Warn("LLM provider throttled agent, pausing launches",
+		"provider", p.provider,
+		"backoff", backoff)
+}
+
+// ObserveSuccess records an agent run that was not throttled, resetting the backoff.
+func (p *Pacer) ObserveSuccess() {
+	p.update(context.Background(), func(s *schedule, now time.Time) {
+		s.Backoff = 0
+	})
+}
+
+// Registry holds the Pacer of every provider. It is safe for concurrent use.
+type Registry struct {
+	mu     sync.Mutex
+	limits map[string]Limits
+	pacers map[string]*Pacer
+	shared *fleet.State
+}
+
+// NewRegistry creates a registry with the configured limits per provider. Providers
+// without limits still get a Pacer so that throttles are backed off.
+func NewRegistry(limits map[string]Limits) *Registry {
+	return &Registry{
+		limits: limits,
+		pacers: make(map[string]*Pacer),
+	}
+}
+
+// For returns the Pacer for a provider, creating it on first use.
+func (r *Registry) For(provider string) *Pacer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pacers[provider]
+	if !ok {
+		p = NewPacer(provider, r.limits[provider])
+		p.shared = r.shared
+		r.pacers[provider] = p
+	}
+	return p
+}
+
+// Share keeps the launch schedules of all providers in the fleet-wide state, so
+// launches are paced across every nightcrier process using the same state store.
+// It must be called before the first call to For.
+func (r *Registry) Share(state *fleet.State) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shared = state
+}
@@ -0,0 +1,193 @@
+package pacing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/fleet"
+)
+
+func TestLimits_Interval(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits Limits
+		want   time.Duration
+	}{
+		{"unlimited", Limits{}, 0},
+		{"requests", Limits{RequestsPerMinute: 50, RequestsPerInvestigation: 25}, 30 * time.Second},
+		{"tokens stricter", Limits{RequestsPerMinute: 1000, TokensPerMinute: 100000, TokensPerInvestigation: 200000}, 2 * time.Minute},
+		{"default estimates", Limits{RequestsPerMinute: 100}, 15 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limits.Interval(); got != tt.want {
+				t.Errorf("Interval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestPacer returns a pacer with a fake clock that records requested sleeps.
+func newTestPacer(limits Limits) (*Pacer, *time.Time, *[]time.Duration) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	var sleeps []time.Duration
+	p := NewPacer("anthropic", limits)
+	p.now = func() time.Time { return now }
+	p.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return p, &now, &sleeps
+}
+
+func TestPacer_SpacesLaunches(t *testing.T) {
+	p, _, sleeps := newTestPacer(Limits{RequestsPerMinute: 60, RequestsPerInvestigation: 10})
+
+	for i := 0; i < 3; i++ {
+		if err := p.Wait(context.Background(), false); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	want := []time.Duration{10 * time.Second, 20 * time.Second}
+	if len(*sleeps) != len(want) || (*sleeps)[0] != want[0] || (*sleeps)[1] != want[1] {
+		t.Errorf("sleeps = %v, want %v (first launch immediate)", *sleeps, want)
+	}
+}
+
+func TestPacer_ThrottleBackoff(t *testing.T) {
+	p, now, sleeps := newTestPacer(Limits{})
+
+	p.ObserveThrottle()
+	p.Wait(context.Background(), false)
+	p.ObserveThrottle()
+	*now = now.Add(time.Second)
+	p.Wait(context.Background(), false)
+
+	want := []time.Duration{15 * time.Second, 29 * time.Second}
+	if len(*sleeps) != 2 || (*sleeps)[0] != want[0] || (*sleeps)[1] != want[1] {
+		t.Errorf("sleeps = %v, want %v (doubling backoff)", *sleeps, want)
+	}
+
+	// A clean run resets the backoff
+	p.ObserveSuccess()
+	*now = now.Add(time.Hour)
+	p.ObserveThrottle()
+	*sleeps = nil
+	p.Wait(context.Background(), false)
+	if len(*sleeps) != 1 || (*sleeps)[0] != 15*time.Second {
+		t.Errorf("sleeps after reset = %v, want [15s]", *sleeps)
+	}
+}
+
+func TestPacer_WaitHonorsContext(t *testing.T) {
+	p := NewPacer("openai", Limits{RequestsPerMinute: 1, RequestsPerInvestigation: 10})
+	p.Wait(context.Background(), false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.Wait(ctx, false); err == nil {
+		t.Error("Wait() should return the context error when cancelled")
+	}
+}
+
+func TestPacer_CancelledWaitGivesSlotBack(t *testing.T) {
+	p, _, sleeps := newTestPacer(Limits{RequestsPerMinute: 60, RequestsPerInvestigation: 10})
+	p.Wait(context.Background(), false)
+
+	cancelled := true
+	p.sleep = func(ctx context.Context, d time.Duration) error {
+		*sleeps = append(*sleeps, d)
+		if cancelled {
+			return context.Canceled
+		}
+		return nil
+	}
+	if err := p.Wait(context.Background(), false); err == nil {
+		t.Fatal("Wait() should return the sleep error")
+	}
+
+	// The next caller gets the slot the cancelled wait reserved
+	cancelled = false
+	p.Wait(context.Background(), false)
+	if len(*sleeps) != 2 || (*sleeps)[1] != 10*time.Second {
+		t.Errorf("sleeps = %v, want the given-back 10s slot", *sleeps)
+	}
+}
+
+func TestPacer_PriorityGoesAhead(t *testing.T) {
+	p, _, sleeps := newTestPacer(Limits{RequestsPerMinute: 60, RequestsPerInvestigation: 10})
+	for i := 0; i < 5; i++ {
+		p.Wait(context.Background(), false)
+	}
+
+	// Priority launches skip the 40s of queued launches, spaced among themselves
+	*sleeps = nil
+	p.Wait(context.Background(), true)
+	p.Wait(context.Background(), true)
+	if len(*sleeps) != 1 || (*sleeps)[0] != 10*time.Second {
+		t.Errorf("priority sleeps = %v, want [10s] (first priority launch immediate)", *sleeps)
+	}
+
+	// The queued launches not handed out yet moved back by one interval each
+	*sleeps = nil
+	p.Wait(context.Background(), false)
+	if len(*sleeps) != 1 || (*sleeps)[0] != 70*time.Second {
+		t.Errorf("sleeps = %v, want [70s]", *sleeps)
+	}
+}
+
+func TestRegistry_For(t *testing.T) {
+	r := NewRegistry(map[string]Limits{"anthropic": {RequestsPerMinute: 60, RequestsPerInvestigation: 30}})
+	if r.For("anthropic") != r.For("anthropic") {
+		t.Error("For() should return the same pacer for a provider")
+	}
+	if got := r.For("anthropic").interval; got != 30*time.Second {
+		t.Errorf("anthropic interval = %v, want 30s", got)
+	}
+	if got := r.For("gemini").interval; got != 0 {
+		t.Errorf("unconfigured provider interval = %v, want 0", got)
+	}
+}
+
+func TestRegistry_SharedSchedule(t *testing.T) {
+	state := fleet.NewState(fleet.NewMemoryBackend())
+	limits := map[string]Limits{"anthropic": {RequestsPerMinute: 60, RequestsPerInvestigation: 10}}
+
+	// Two processes pacing the same provider hand out slots from one schedule
+	var sleeps []time.Duration
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	var pacers []*Pacer
+	for i := 0; i < 2; i++ {
+		r := NewRegistry(limits)
+		r.Share(state)
+		p := r.For("anthropic")
+		p.now = func() time.Time { return now }
+		p.sleep = func(_ context.Context, d time.Duration) error {
+			sleeps = append(sleeps, d)
+			return nil
+		}
+		pacers = append(pacers, p)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := pacers[i%2].Wait(context.Background(), false); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if len(sleeps) != 2 || sleeps[0] != 10*time.Second || sleeps[1] != 20*time.Second {
+		t.Errorf("sleeps = %v, want [10s 20s] across both processes", sleeps)
+	}
+
+	// A throttle observed by one process pauses the other
+	pacers[0].ObserveThrottle()
+	now = now.Add(time.Minute)
+	sleeps = nil
+	pacers[1].ObserveThrottle()
+	if err := pacers[1].Wait(context.Background(), false); err != nil {
+		t.Fatal(err)
+	}
+	if len(sleeps) != 1 || sleeps[0] != 2*initialThrottleBackoff {
+		t.Errorf("sleeps after two throttles = %v, want the doubled backoff %v", sleeps, 2*initialThrottleBackoff)
+	}
+}
@@ -0,0 +1,225 @@
+// Package pause provides the runtime switch that pauses triage globally or per
+// cluster. While triage is paused, fault events are still recorded and deduped,
+// but no agents are launched. The switch is persisted to a JSON file, so a pause
+// survives restarts and can be changed by the CLI while nightcrier runs.
+package pause
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotPaused is returned when resuming triage that is not paused.
+var ErrNotPaused = errors.New("triage is not paused")
+
+// Pause records who paused triage, when, and why.
+type Pause struct {
+	PausedBy string    `json:"paused_by,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	PausedAt time.Time `json:"paused_at"`
+}
+
+// State is the persisted pause state.
+type State struct {
+	// Global pauses triage on every cluster
+	Global *Pause `json:"global,omitempty"`
+	// Clusters pauses triage on single clusters, by cluster name
+	Clusters map[string]*Pause `json:"clusters,omitempty"`
+}
+
+// For returns the pause in effect for a cluster (the global pause takes
+// precedence), or nil when its triage runs.
+func (s State) For(cluster string) *Pause {
+	if s.Global != nil {
+		return s.Global
+	}
+	return s.Clusters[cluster]
+}
+
+// PausedClusters returns the names of the clusters paused individually, sorted.
+func (s State) PausedClusters() []string {
+	names := make([]string, 0, len(s.Clusters))
+	for name := range s.Clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Switch is the pause switch backed by a file. Changes made through another
+// Switch on the same file (e.g. by the CLI) are picked up on the next read.
+type Switch struct {
+	path     string
+	clusters []string
+
+	mu      sync.Mutex
+	state   State
+	modTime time.Time
+}
+
+// Open returns the switch persisted at path, which need not exist yet. clusters
+// are the cluster names that may be paused (empty: any name).
+func Open(path string, clusters []string) (*Switch, error) {
+	s := &Switch{path: path, clusters: clusters}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Path returns the file the switch is persisted to.
+func (s *Switch) Path() string {
+	return s.path
+}
+
+// State returns the current pause state, re-reading the file when it changed.
+// When the file cannot be read, the last state read stays in effect.
+func (s *Switch) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.loadLocked()
+	return s.copyLocked()
+}
+
+// Paused returns the pause in effect for a cluster, or nil when its triage runs.
+func (s *Switch) Paused(cluster string) *Pause {
+	return s.State().For(cluster)
+}
+
+// Pause pauses triage on a cluster, or on every cluster when cluster is empty.
+// Pausing again replaces the reason.
+func (s *Switch) Pause(cluster string, p Pause) (State, error) {
+	if err := s.checkCluster(cluster); err != nil {
+		return State{}, err
+	}
+	if p.PausedAt.IsZero() {
+		p.PausedAt = time.Now()
+	}
+	p.PausedAt = p.PausedAt.UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return State{}, err
+	}
+	if cluster == "" {
+		s.state.Global = &p
+	} else {
+		if s.state.Clusters == nil {
+			s.state.Clusters = make(map[string]*Pause)
+		}
+		s.state.Clusters[cluster] = &p
+	}
+	if err := s.saveLocked(); err != nil {
+		return State{}, err
+	}
+	return s.copyLocked(), nil
+}
+
+// Resume resumes triage on a cluster, or lifts the global pause when cluster is
+// empty. A cluster paused individually stays paused when the global pause is
+// lifted. It returns ErrNotPaused when there was no such pause.
+func (s *Switch) Resume(cluster string) (State, error) {
+	if err := s.checkCluster(cluster); err != nil {
+		return State{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return State{}, err
+	}
+	if cluster == "" {
+		if s.state.Global == nil {
+			return State{}, ErrNotPaused
+		}
+		s.state.Global = nil
+	} else {
+		if _, ok := s.state.Clusters[cluster]; !ok {
+			return State{}, fmt.Errorf("cluster %s: %w", cluster, ErrNotPaused)
+		}
+		delete(s.state.Clusters, cluster)
+	}
+	if err := s.saveLocked(); err != nil {
+		return State{}, err
+	}
+	return s.copyLocked(), nil
+}
+
+// checkCluster rejects cluster names that are not configured.
+func (s *Switch) checkCluster(cluster string) error {
+	if cluster == "" || len(s.clusters) == 0 || slices.Contains(s.clusters, cluster) {
+		return nil
+	}
+	return fmt.Errorf("unknown cluster %q", cluster)
+}
+
+// loadLocked re-reads the file when it changed since the last read. A missing
+// file means nothing is paused. Caller holds mu.
+func (s *Switch) loadLocked() error {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.state, s.modTime = State{}, time.Time{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat pause file: %w", err)
+	}
+	if info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read pause file: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse pause file %s: %w", s.path, err)
+	}
+	s.state, s.modTime = state, info.ModTime()
+	return nil
+}
+
+// saveLocked atomically writes the state to the file. Caller holds mu.
+func (s *Switch) saveLocked() error {
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pause state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create pause file directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write pause file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace pause file: %w", err)
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// copyLocked returns a copy of the state the caller may keep. Caller holds mu.
+func (s *Switch) copyLocked() State {
+	state := State{Global: s.state.Global}
+	if len(s.state.Clusters) > 0 {
+		state.Clusters = make(map[string]*Pause, len(s.state.Clusters))
+		for name, p := range s.state.Clusters {
+			state.Clusters[name] = p
+		}
+	}
+	return state
+}
@@ -0,0 +1,106 @@
+package pause
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSwitch_PauseAndResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "triage-pause.json")
+	s, err := Open(path, []string{"prod", "staging"})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if p := s.Paused("prod"); p != nil {
+		t.Fatalf("Paused(prod) = %+v before any pause", p)
+	}
+
+	if _, err := s.Pause("prod", Pause{PausedBy: "alice", Reason: "maintenance"}); err != nil {
+		t.Fatalf("Pause(prod) error = %v", err)
+	}
+	if p := s.Paused("prod"); p == nil || p.Reason != "maintenance" || p.PausedAt.IsZero() {
+		t.Errorf("Paused(prod) = %+v, want the maintenance pause", p)
+	}
+	if p := s.Paused("staging"); p != nil {
+		t.Errorf("Paused(staging) = %+v, want nil", p)
+	}
+
+	state, err := s.Pause("", Pause{Reason: "LLM outage"})
+	if err != nil {
+		t.Fatalf("Pause(global) error = %v", err)
+	}
+	if state.For("staging") == nil || state.For("prod").Reason != "LLM outage" {
+		t.Errorf("global pause should take precedence, got %+v", state)
+	}
+
+	// Lifting the global pause keeps the cluster pause
+	state, err = s.Resume("")
+	if err != nil {
+		t.Fatalf("Resume(global) error = %v", err)
+	}
+	if state.For("staging") != nil || state.For("prod") == nil {
+		t.Errorf("after global resume got %+v, want only prod paused", state)
+	}
+	if got := state.PausedClusters(); len(got) != 1 || got[0] != "prod" {
+		t.Errorf("PausedClusters() = %v, want [prod]", got)
+	}
+
+	if _, err := s.Resume("prod"); err != nil {
+		t.Fatalf("Resume(prod) error = %v", err)
+	}
+	if _, err := s.Resume("prod"); !errors.Is(err, ErrNotPaused) {
+		t.Errorf("Resume(prod) again error = %v, want ErrNotPaused", err)
+	}
+	if _, err := s.Resume(""); !errors.Is(err, ErrNotPaused) {
+		t.Errorf("Resume(global) again error = %v, want ErrNotPaused", err)
+	}
+	if _, err := s.Pause("unknown", Pause{}); err == nil {
+		t.Error("Pause() should reject unknown clusters")
+	}
+}
+
+func TestSwitch_PicksUpChangesFromOtherSwitches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "triage-pause.json")
+	daemon, err := Open(path, nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	cli, err := Open(path, nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if _, err := cli.Pause("prod", Pause{Reason: "from the CLI"}); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if p := daemon.Paused("prod"); p == nil || p.Reason != "from the CLI" {
+		t.Errorf("Paused(prod) = %+v, want the CLI's pause", p)
+	}
+
+	// Persisted across restarts
+	restarted, err := Open(path, nil)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if restarted.Paused("prod") == nil {
+		t.Error("pause should survive a restart")
+	}
+
+	// A corrupt file keeps the last state read
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if daemon.Paused("prod") == nil {
+		t.Error("a corrupt file should keep the last state in effect")
+	}
+	if _, err := Open(path, nil); err == nil {
+		t.Error("Open() should fail on a corrupt file")
+	}
+}
@@ -0,0 +1,210 @@
+// Package postmortem publishes finished investigations to a Git repository so
+// postmortems land in a docs-as-code workflow. The investigation report and a
+// structured findings.json are committed under a dated path on a new branch, and a
+// pull request (GitHub) or merge request (GitLab) is opened through the provider's
+// REST API. No local clone is needed.
+package postmortem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// Supported Git hosting providers.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Default API endpoints for the hosted providers.
+const (
+	DefaultGitHubAPIURL = "https://api.github.com"
+	DefaultGitLabAPIURL = "https://gitlab.com/api/v4"
+)
+
+// Config configures where postmortems are published.
+type Config struct {
+	// Provider is "github" or "gitlab"
+	Provider string
+
+	// APIURL is the provider API base URL (for GitHub Enterprise or self-managed GitLab)
+	APIURL string
+
+	// Repo is "owner/name" on GitHub or the project path on GitLab
+	Repo string
+
+	// Token authenticates API requests and needs permission to push branches and
+	// open pull requests
+	Token string
+
+	// BaseBranch is the branch postmortems are merged into
+	BaseBranch string
+
+	// Path is the repository directory postmortems are written under
+	Path string
+
+	// BranchPrefix prefixes the branch created for each postmortem
+	BranchPrefix string
+
+	// CreatePullRequest opens a pull/merge request from a new branch. When false,
+	// files are committed directly to BaseBranch.
+	CreatePullRequest bool
+}
+
+// Findings is the structured summary of an investigation written as findings.json
+// alongside the report.
+type Findings struct {
+	IncidentID  string    `json:"incidentId"`
+	Cluster     string    `json:"cluster"`
+	Namespace   string    `json:"namespace"`
+	Resource    string    `json:"resource"`
+	FaultType   string    `json:"faultType"`
+	Severity    string    `json:"severity"`
+	Status      string    `json:"status"`
+	RootCause   string    `json:"rootCause"`
+	Confidence  string    `json:"confidence"`
+	StartedAt   time.Time `json:"startedAt"`
+	CompletedAt time.Time `json:"completedAt"`
+	ReportURL   string    `json:"reportUrl,omitempty"`
+}
+
+// file is a file committed to the repository.
+type file struct {
+	Path    string
+	Content []byte
+}
+
+// change is a set of files committed together, optionally proposed as a pull request.
+type change struct {
+	Branch  string
+	Message string
+	Title   string
+	Body    string
+	Files   []file
+}
+
+// provider commits a change through a Git hosting API and returns the URL of the
+// pull request (or of the commit when no pull request is requested).
+type provider interface {
+	publish(ctx context.Context, c change) (string, error)
+}
+
+// Publisher publishes postmortems to a Git repository.
+type Publisher struct {
+	cfg      Config
+	provider provider
+}
+
+// New creates a publisher for the configured provider.
+func New(cfg Config, httpClient *http.Client) (*Publisher, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	var p provider
+	switch cfg.Provider {
+	case ProviderGitHub:
+		if cfg.APIURL == "" {
+			cfg.APIURL = DefaultGitHubAPIURL
+		}
+		p = &githubProvider{cfg: cfg, client: httpClient}
+	case ProviderGitLab:
+		if cfg.APIURL == "" {
+			cfg.APIURL = DefaultGitLabAPIURL
+		}
+		p = &gitlabProvider{cfg: cfg, client: httpClient}
+	default:
+		return nil, fmt.Errorf("unsupported postmortem provider %q (must be github or gitlab)", cfg.Provider)
+	}
+	return &Publisher{cfg: cfg, provider: p}, nil
+}
+
+// Dir returns the dated repository directory for an investigation, e.g.
+// "postmortems/2025/03/2025-03-01-prod-crashloopbackoff-3f9a1c0d".
+func (p *Publisher) Dir(f Findings) string {
+	date := f.CompletedAt.UTC()
+	name := fmt.Sprintf("%s-%s-%s-%s", date.Format("2006-01-02"), slug(f.Cluster), slug(f.FaultType), shortID(f.IncidentID))
+	return path.Join(p.cfg.Path, date.Format("2006"), date.Format("01"), name)
+}
+
+// Publish commits the investigation report and findings.json and, if configured,
+// opens a pull request. It returns the pull request URL (or commit URL).
+func (p *Publisher) Publish(ctx context.Context, report []byte, f Findings) (string, error) {
+	findingsJSON, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal findings: %w", err)
+	}
+
+	dir := p.Dir(f)
+	title := fmt.Sprintf("Postmortem: %s on %s/%s (%s)", f.FaultType, f.Cluster, f.Resource, f.CompletedAt.UTC().Format("2006-01-02"))
+	c := change{
+		Branch:  p.cfg.BaseBranch,
+		Message: fmt.Sprintf("Add postmortem for incident %s", f.IncidentID),
+		Title:   title,
+		Body:    pullRequestBody(f, dir),
+		Files: []file{
+			{Path: path.Join(dir, "investigation.md"), Content: report},
+			{Path: path.Join(dir, "findings.json"), Content: append(findingsJSON, '\n')},
+		},
+	}
+	if p.cfg.CreatePullRequest {
+		c.Branch = p.cfg.BranchPrefix + shortID(f.IncidentID)
+	}
+
+	url, err := p.provider.publish(ctx, c)
+	if err != nil {
+		return "", fmt.Errorf("failed to publish postmortem to %s %s: %w", p.cfg.Provider, p.cfg.Repo, err)
+	}
+	return url, nil
+}
+
+// pullRequestBody summarizes the findings for reviewers.
+func pullRequestBody(f Findings, dir string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Automated postmortem for incident `%s`.\n\n", f.IncidentID)
+	fmt.Fprintf(&b, "| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Cluster | %s |\n", f.Cluster)
+	fmt.Fprintf(&b, "| Namespace | %s |\n", f.Namespace)
+	fmt.Fprintf(&b, "| Resource | %s |\n", f.Resource)
+	fmt.Fprintf(&b, "| Fault | %s (%s) |\n", f.FaultType, f.Severity)
+	fmt.Fprintf(&b, "| Confidence | %s |\n\n", f.Confidence)
+	fmt.Fprintf(&b, "**Root cause:** %s\n\n", f.RootCause)
+	fmt.Fprintf(&b, "Files are in `%s/`.", dir)
+	if f.ReportURL != "" {
+		fmt.Fprintf(&b, " [Rendered report](%s)", f.ReportURL)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// slug lower-cases s and replaces everything but letters and digits with '-'.
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	out := strings.TrimSuffix(b.String(), "-")
+	if out == "" {
+		return "unknown"
+	}
+	return out
+}
+
+// shortID returns the first 8 characters of an incident ID.
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,163 @@
+package postmortem
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFindings() Findings {
+	return Findings{
+		IncidentID:  "3f9a1c0d-1111-2222-3333-444455556666",
+		Cluster:     "prod-east",
+		Namespace:   "payments",
+		Resource:    "Pod/api-7d9f",
+		FaultType:   "CrashLoopBackOff",
+		Severity:    "ERROR",
+		Status:      "resolved",
+		RootCause:   "Missing DATABASE_URL secret",
+		Confidence:  "HIGH",
+		CompletedAt: time.Date(2025, 3, 1, 23, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestPublisher_Dir(t *testing.T) {
+	p, err := New(Config{Provider: ProviderGitHub, Path: "postmortems"}, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	want := "postmortems/2025/03/2025-03-01-prod-east-crashloopbackoff-3f9a1c0d"
+	if got := p.Dir(testFindings()); got != want {
+		t.Errorf("Dir() = %q, want %q", got, want)
+	}
+}
+
+func TestPublisher_GitHubPullRequest(t *testing.T) {
+	var calls []string
+	files := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if got := r.Header.Get("Authorization"); got != "Bearer gh-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/sre/docs/git/ref/heads/main":
+			w.Write([]byte(`{"object":{"sha":"abc123"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/sre/docs/git/refs":
+			if body["ref"] != "refs/heads/nightcrier/postmortem-3f9a1c0d" || body["sha"] != "abc123" {
+				t.Errorf("create ref body = %v", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/repos/sre/docs/contents/"):
+			content, _ := base64.StdEncoding.DecodeString(body["content"])
+			files[strings.TrimPrefix(r.URL.Path, "/repos/sre/docs/contents/")] = string(content)
+			if body["branch"] != "nightcrier/postmortem-3f9a1c0d" {
+				t.Errorf("content branch = %q", body["branch"])
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"commit":{"html_url":"https://github.com/sre/docs/commit/def"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/sre/docs/pulls":
+			if body["head"] != "nightcrier/postmortem-3f9a1c0d" || body["base"] != "main" || !strings.Contains(body["body"], "Missing DATABASE_URL") {
+				t.Errorf("pull request body = %v", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"html_url":"https://github.com/sre/docs/pull/42"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p, _ := New(Config{
+		Provider:          ProviderGitHub,
+		APIURL:            server.URL,
+		Repo:              "sre/docs",
+		Token:             "gh-token",
+		BaseBranch:        "main",
+		Path:              "postmortems",
+		BranchPrefix:      "nightcrier/postmortem-",
+		CreatePullRequest: true,
+	}, nil)
+
+	url, err := p.Publish(context.Background(), []byte("# Investigation"), testFindings())
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if url != "https://github.com/sre/docs/pull/42" {
+		t.Errorf("Publish() = %q, want pull request URL", url)
+	}
+
+	dir := "postmortems/2025/03/2025-03-01-prod-east-crashloopbackoff-3f9a1c0d"
+	if files[dir+"/investigation.md"] != "# Investigation" {
+		t.Errorf("investigation.md = %q", files[dir+"/investigation.md"])
+	}
+	var findings Findings
+	if err := json.Unmarshal([]byte(files[dir+"/findings.json"]), &findings); err != nil || findings.RootCause != "Missing DATABASE_URL secret" {
+		t.Errorf("findings.json = %q, %v", files[dir+"/findings.json"], err)
+	}
+	if len(calls) != 5 {
+		t.Errorf("calls = %v", calls)
+	}
+}
+
+func TestPublisher_GitLabDirectCommit(t *testing.T) {
+	var commit struct {
+		Branch      string `json:"branch"`
+		StartBranch string `json:"start_branch"`
+		Actions     []struct {
+			FilePath string `json:"file_path"`
+		} `json:"actions"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "gl-token" {
+			t.Errorf("PRIVATE-TOKEN = %q", r.Header.Get("PRIVATE-TOKEN"))
+		}
+		if r.URL.EscapedPath() != "/projects/sre%2Fdocs/repository/commits" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		json.NewDecoder(r.Body).Decode(&commit)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"web_url":"https://gitlab.com/sre/docs/-/commit/abc"}`))
+	}))
+	defer server.Close()
+
+	p, _ := New(Config{Provider: ProviderGitLab, APIURL: server.URL, Repo: "sre/docs", Token: "gl-token", BaseBranch: "main", Path: "pm"}, nil)
+	url, err := p.Publish(context.Background(), []byte("# Investigation"), testFindings())
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if url != "https://gitlab.com/sre/docs/-/commit/abc" {
+		t.Errorf("Publish() = %q", url)
+	}
+	if commit.Branch != "main" || commit.StartBranch != "" || len(commit.Actions) != 2 {
+		t.Errorf("commit = %+v, want direct commit of two files to main", commit)
+	}
+}
+
+func TestPublisher_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	p, _ := New(Config{Provider: ProviderGitHub, APIURL: server.URL, Repo: "sre/docs", BaseBranch: "main", CreatePullRequest: true, BranchPrefix: "pm-"}, nil)
+	_, err := p.Publish(context.Background(), []byte("# Investigation"), testFindings())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Publish() error = %v, want status 401", err)
+	}
+}
+
+func TestNew_UnsupportedProvider(t *testing.T) {
+	if _, err := New(Config{Provider: "bitbucket"}, nil); err == nil {
+		t.Error("New() should reject unsupported providers")
+	}
+}
@@ -0,0 +1,189 @@
+package postmortem
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// apiRequest sends a JSON request and decodes a JSON response into out.
+func apiRequest(ctx context.Context, client *http.Client, method, endpoint string, headers map[string]string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned status %d: %s", method, endpoint, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// githubProvider publishes through the GitHub REST API: it creates a branch from
+// the base branch, writes each file with the contents API, and opens a pull request.
+type githubProvider struct {
+	cfg    Config
+	client *http.Client
+}
+
+func (g *githubProvider) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	headers := map[string]string{
+		"Authorization":        "Bearer " + g.cfg.Token,
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	return apiRequest(ctx, g.client, method, strings.TrimSuffix(g.cfg.APIURL, "/")+"/repos/"+g.cfg.Repo+endpoint, headers, body, out)
+}
+
+func (g *githubProvider) publish(ctx context.Context, c change) (string, error) {
+	if c.Branch != g.cfg.BaseBranch {
+		var base struct {
+			Object struct {
+				SHA string `json:"sha"`
+			} `json:"object"`
+		}
+		if err := g.do(ctx, http.MethodGet, "/git/ref/heads/"+g.cfg.BaseBranch, nil, &base); err != nil {
+			return "", fmt.Errorf("failed to read base branch: %w", err)
+		}
+		ref := map[string]string{"ref": "refs/heads/" + c.Branch, "sha": base.Object.SHA}
+		if err := g.do(ctx, http.MethodPost, "/git/refs", ref, nil); err != nil {
+			return "", fmt.Errorf("failed to create branch: %w", err)
+		}
+	}
+
+	var commitURL string
+	for _, f := range c.Files {
+		body := map[string]string{
+			"message": c.Message,
+			"content": base64.StdEncoding.EncodeToString(f.Content),
+			"branch":  c.Branch,
+		}
+		var resp struct {
+			Commit struct {
+				HTMLURL string `json:"html_url"`
+			} `json:"commit"`
+		}
+		if err := g.do(ctx, http.MethodPut, "/contents/"+f.Path, body, &resp); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+		commitURL = resp.Commit.HTMLURL
+	}
+
+	if c.Branch == g.cfg.BaseBranch {
+		return commitURL, nil
+	}
+	pr := map[string]string{
+		"title": c.Title,
+		"head":  c.Branch,
+		"base":  g.cfg.BaseBranch,
+		"body":  c.Body,
+	}
+	var resp struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := g.do(ctx, http.MethodPost, "/pulls", pr, &resp); err != nil {
+		return "", fmt.Errorf("failed to open pull request: %w", err)
+	}
+	return resp.HTMLURL, nil
+}
+
+// gitlabProvider publishes through the GitLab REST API: a single commit creates
+// the branch and all files, then a merge request is opened.
+type gitlabProvider struct {
+	cfg    Config
+	client *http.Client
+}
+
+func (g *gitlabProvider) do(ctx context.Context, method, endpoint string, body, out interface{}) error {
+	headers := map[string]string{"PRIVATE-TOKEN": g.cfg.Token}
+	project := url.PathEscape(g.cfg.Repo)
+	return apiRequest(ctx, g.client, method, strings.TrimSuffix(g.cfg.APIURL, "/")+"/projects/"+project+endpoint, headers, body, out)
+}
+
+func (g *gitlabProvider) publish(ctx context.Context, c change) (string, error) {
+	type action struct {
+		Action   string `json:"action"`
+		FilePath string `json:"file_path"`
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	commit := struct {
+		Branch        string   `json:"branch"`
+		StartBranch   string   `json:"start_branch,omitempty"`
+		CommitMessage string   `json:"commit_message"`
+		Actions       []action `json:"actions"`
+	}{
+		Branch:        c.Branch,
+		CommitMessage: c.Message,
+	}
+	if c.Branch != g.cfg.BaseBranch {
+		commit.StartBranch = g.cfg.BaseBranch
+	}
+	for _, f := range c.Files {
+		commit.Actions = append(commit.Actions, action{
+			Action:   "create",
+			FilePath: f.Path,
+			Content:  base64.StdEncoding.EncodeToString(f.Content),
+			Encoding: "base64",
+		})
+	}
+
+	var commitResp struct {
+		WebURL string `json:"web_url"`
+	}
+	if err := g.do(ctx, http.MethodPost, "/repository/commits", commit, &commitResp); err != nil {
+		return "", fmt.Errorf("failed to commit files: %w", err)
+	}
+	if c.Branch == g.cfg.BaseBranch {
+		return commitResp.WebURL, nil
+	}
+
+	mr := map[string]interface{}{
+		"source_branch":        c.Branch,
+		"target_branch":        g.cfg.BaseBranch,
+		"title":                c.Title,
+		"description":          c.Body,
+		"remove_source_branch": true,
+	}
+	var mrResp struct {
+		WebURL string `json:"web_url"`
+	}
+	if err := g.do(ctx, http.MethodPost, "/merge_requests", mr, &mrResp); err != nil {
+		return "", fmt.Errorf("failed to open merge request: %w", err)
+	}
+	return mrResp.WebURL, nil
+}
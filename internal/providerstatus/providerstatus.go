@@ -0,0 +1,307 @@
+// Package providerstatus watches the health of the LLM providers agents call, so
+// a provider outage is recognized before every investigation fails on it. Two
+// signals are combined: the provider's public status page (Atlassian Statuspage,
+// which Anthropic and OpenAI use), and the failures of recent investigations
+// classified as provider_unavailable. Either one suspects an outage; callers then
+// stop launching agents for the provider instead of burning retries blind.
+package providerstatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/failures"
+)
+
+// Outage signals
+const (
+	// SignalStatusPage means the provider's status page reports an incident
+	SignalStatusPage = "status_page"
+	// SignalFailures means recent investigations failed on provider errors
+	SignalFailures = "failures"
+)
+
+// Statuspage indicators, from no incident to a critical one
+var indicatorLevels = map[string]int{"none": 0, "minor": 1, "major": 2, "critical": 3}
+
+// DefaultStatusPages are the Statuspage status APIs of the providers that publish
+// one, keyed by provider (see keypool).
+var DefaultStatusPages = map[string]string{
+	"anthropic": "https://status.anthropic.com/api/v2/status.json",
+	"openai":    "https://status.openai.com/api/v2/status.json",
+}
+
+// Outage is a suspected provider outage.
+type Outage struct {
+	Provider string `json:"provider"`
+	// Signal is what the outage is suspected from: SignalStatusPage or
+	// SignalFailures
+	Signal string `json:"signal"`
+	// Description explains the suspicion, e.g. the status page's description
+	Description string    `json:"description"`
+	Since       time.Time `json:"since"`
+}
+
+// String describes the outage for logs and notifications.
+func (o *Outage) String() string {
+	return fmt.Sprintf("provider outage suspected: %s (%s)", o.Provider, o.Description)
+}
+
+// Options configures a Monitor.
+type Options struct {
+	// StatusPages maps providers to the URL of their Statuspage status API
+	// (/api/v2/status.json)
+	StatusPages map[string]string
+	// Indicator is the lowest status page indicator suspecting an outage: minor,
+	// major, or critical (default major)
+	Indicator string
+	// FailureThreshold is the number of provider_unavailable failures within
+	// FailureWindow suspecting an outage (0: the failure signal is disabled)
+	FailureThreshold int
+	FailureWindow    time.Duration
+	// HTTPClient reads the status pages (default: a client with a 10s timeout)
+	HTTPClient *http.Client
+	// OnChange is called when an outage of a provider is first suspected (outage
+	// set) and when it is no longer suspected (outage nil)
+	OnChange func(provider string, outage *Outage)
+}
+
+// Monitor tracks the suspected outages of providers.
+type Monitor struct {
+	opts      Options
+	threshold int
+
+	mu sync.Mutex
+	// statusPage and failed hold the outages suspected from each signal
+	statusPage map[string]*Outage
+	failed     map[string]*Outage
+	// failures holds the times of the recent provider failures of each provider
+	failures map[string][]time.Time
+	// reported holds the outage last passed to OnChange for each provider
+	reported map[string]*Outage
+}
+
+// New returns a monitor with the given options.
+func New(opts Options) *Monitor {
+	if opts.Indicator == "" {
+		opts.Indicator = "major"
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Monitor{
+		opts:       opts,
+		threshold:  indicatorLevels[opts.Indicator],
+		statusPage: make(map[string]*Outage),
+		failed:     make(map[string]*Outage),
+		failures:   make(map[string][]time.Time),
+		reported:   make(map[string]*Outage),
+	}
+}
+
+// Run checks the status pages and expires the failure signal now and every
+// interval until ctx is done.
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	m.Check(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Check(ctx)
+		}
+	}
+}
+
+// Check reads the status pages and expires failure signals older than the
+// failure window. A status page that cannot be read keeps its last state.
+func (m *Monitor) Check(ctx context.Context) {
+	for provider, url := range m.opts.StatusPages {
+		indicator, description, err := m.readStatusPage(ctx, url)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Debug("failed to read provider status page", "provider", provider, "url", url, "error", err)
+			}
+			continue
+		}
+		m.mu.Lock()
+		if indicatorLevels[indicator] >= m.threshold && indicatorLevels[indicator] > 0 {
+			if m.statusPage[provider] == nil {
+				m.statusPage[provider] = &Outage{Provider: provider, Signal: SignalStatusPage, Since: time.Now()}
+			}
+			m.statusPage[provider].Description = fmt.Sprintf("status page: %s", description)
+		} else {
+			delete(m.statusPage, provider)
+		}
+		m.mu.Unlock()
+	}
+
+	m.mu.Lock()
+	for provider := range m.failed {
+		m.pruneFailuresLocked(provider, time.Now())
+	}
+	m.mu.Unlock()
+	m.notify()
+}
+
+// statusResponse is the part of a Statuspage status API response used
+type statusResponse struct {
+	Status struct {
+		Indicator   string `json:"indicator"`
+		Description string `json:"description"`
+	} `json:"status"`
+}
+
+func (m *Monitor) readStatusPage(ctx context.Context, url string) (string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := m.opts.HTTPClient.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read status page: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("status page returned %s", resp.Status)
+	}
+	var status statusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return "", "", fmt.Errorf("failed to decode status page: %w", err)
+	}
+	if _, ok := indicatorLevels[status.Status.Indicator]; !ok {
+		return "", "", fmt.Errorf("unknown status indicator %q", status.Status.Indicator)
+	}
+	return status.Status.Indicator, status.Status.Description, nil
+}
+
+// RecordFailure counts a failed investigation of an agent using provider.
+// Failures of classes other than provider_unavailable are ignored.
+func (m *Monitor) RecordFailure(provider string, class failures.Class) {
+	if m.opts.FailureThreshold <= 0 || class != failures.ProviderUnavailable {
+		return
+	}
+	now := time.Now()
+	m.mu.Lock()
+	m.failures[provider] = append(m.failures[provider], now)
+	m.pruneFailuresLocked(provider, now)
+	if count := len(m.failures[provider]); count >= m.opts.FailureThreshold && m.failed[provider] == nil {
+		m.failed[provider] = &Outage{
+			Provider:    provider,
+			Signal:      SignalFailures,
+			Description: fmt.Sprintf("%d investigations failed on provider errors within %s", count, m.opts.FailureWindow),
+			Since:       now,
+		}
+	}
+	m.mu.Unlock()
+	m.notify()
+}
+
+// RecordSuccess clears the failure signal of provider after an investigation
+// succeeded with it.
+func (m *Monitor) RecordSuccess(provider string) {
+	m.mu.Lock()
+	delete(m.failures, provider)
+	delete(m.failed, provider)
+	m.mu.Unlock()
+	m.notify()
+}
+
+// pruneFailuresLocked drops the failures older than the failure window; the
+// failure signal ends when none are left.
+func (m *Monitor) pruneFailuresLocked(provider string, now time.Time) {
+	recent := m.failures[provider][:0]
+	for _, at := range m.failures[provider] {
+		if now.Sub(at) < m.opts.FailureWindow {
+			recent = append(recent, at)
+		}
+	}
+	m.failures[provider] = recent
+	if len(recent) == 0 {
+		delete(m.failures, provider)
+		delete(m.failed, provider)
+	}
+}
+
+// Outage returns the suspected outage of provider, or nil. The status page takes
+// precedence over the failure signal.
+func (m *Monitor) Outage(provider string) *Outage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.outageLocked(provider)
+}
+
+func (m *Monitor) outageLocked(provider string) *Outage {
+	if outage := m.statusPage[provider]; outage != nil {
+		return outage
+	}
+	return m.failed[provider]
+}
+
+// Outages returns the suspected outages, by provider.
+func (m *Monitor) Outages() []*Outage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var outages []*Outage
+	for _, provider := range m.providersLocked() {
+		if outage := m.outageLocked(provider); outage != nil {
+			outages = append(outages, outage)
+		}
+	}
+	return outages
+}
+
+// providersLocked returns every provider with state, sorted.
+func (m *Monitor) providersLocked() []string {
+	seen := make(map[string]bool)
+	for _, outages := range []map[string]*Outage{m.statusPage, m.failed, m.reported} {
+		for provider := range outages {
+			seen[provider] = true
+		}
+	}
+	providers := make([]string, 0, len(seen))
+	for provider := range seen {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// notify calls OnChange for the providers whose outage started or ended since
+// the last call.
+func (m *Monitor) notify() {
+	type change struct {
+		provider string
+		outage   *Outage
+	}
+	var changes []change
+	m.mu.Lock()
+	for _, provider := range m.providersLocked() {
+		outage := m.outageLocked(provider)
+		if (outage == nil) == (m.reported[provider] == nil) {
+			continue
+		}
+		if outage == nil {
+			delete(m.reported, provider)
+		} else {
+			m.reported[provider] = outage
+		}
+		changes = append(changes, change{provider, outage})
+	}
+	m.mu.Unlock()
+
+	if m.opts.OnChange == nil {
+		return
+	}
+	for _, c := range changes {
+		m.opts.OnChange(c.provider, c.outage)
+	}
+}
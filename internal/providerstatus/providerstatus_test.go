@@ -0,0 +1,176 @@
+package providerstatus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/failures"
+)
+
+// statusPage serves a Statuspage status API whose indicator can be changed.
+type statusPage struct {
+	mu        sync.Mutex
+	indicator string
+}
+
+func (s *statusPage) set(indicator string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.indicator = indicator
+}
+
+func (s *statusPage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(w, `{"status":{"indicator":%q,"description":"indicator %s"}}`, s.indicator, s.indicator)
+}
+
+// changeRecorder records the OnChange calls of a monitor.
+type changeRecorder struct {
+	mu      sync.Mutex
+	changes []string
+}
+
+func (c *changeRecorder) onChange(provider string, outage *Outage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if outage == nil {
+		c.changes = append(c.changes, provider+" ended")
+		return
+	}
+	c.changes = append(c.changes, provider+" "+outage.Signal)
+}
+
+func (c *changeRecorder) get() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.changes...)
+}
+
+func TestStatusPageSignal(t *testing.T) {
+	page := &statusPage{indicator: "none"}
+	server := httptest.NewServer(page)
+	defer server.Close()
+
+	var changes changeRecorder
+	m := New(Options{StatusPages: map[string]string{"anthropic": server.URL}, OnChange: changes.onChange})
+	ctx := context.Background()
+
+	m.Check(ctx)
+	if m.Outage("anthropic") != nil {
+		t.Fatal("Outage() with no incident should be nil")
+	}
+
+	// A minor incident is below the default major indicator
+	page.set("minor")
+	m.Check(ctx)
+	if m.Outage("anthropic") != nil {
+		t.Fatal("Outage() with a minor incident should be nil")
+	}
+
+	page.set("major")
+	m.Check(ctx)
+	outage := m.Outage("anthropic")
+	if outage == nil || outage.Signal != SignalStatusPage || outage.Description != "status page: indicator major" {
+		t.Fatalf("Outage() with a major incident = %+v", outage)
+	}
+	if outages := m.Outages(); len(outages) != 1 {
+		t.Errorf("Outages() = %v, want the anthropic outage", outages)
+	}
+
+	// An unreadable status page keeps the last state
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	m.Check(ctx)
+	if m.Outage("anthropic") == nil {
+		t.Fatal("Outage() should survive an unreadable status page")
+	}
+
+	server.Config.Handler = page
+	page.set("none")
+	m.Check(ctx)
+	if m.Outage("anthropic") != nil {
+		t.Fatal("Outage() after the incident resolved should be nil")
+	}
+
+	want := []string{"anthropic status_page", "anthropic ended"}
+	if got := changes.get(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("OnChange calls = %v, want %v", got, want)
+	}
+}
+
+func TestStatusPageIndicator(t *testing.T) {
+	page := &statusPage{indicator: "minor"}
+	server := httptest.NewServer(page)
+	defer server.Close()
+
+	m := New(Options{StatusPages: map[string]string{"openai": server.URL}, Indicator: "minor"})
+	m.Check(context.Background())
+	if m.Outage("openai") == nil {
+		t.Error("Outage() with a minor incident and a minor indicator should be set")
+	}
+}
+
+func TestFailureSignal(t *testing.T) {
+	var changes changeRecorder
+	m := New(Options{FailureThreshold: 3, FailureWindow: time.Hour, OnChange: changes.onChange})
+
+	// Failures of other classes do not count
+	for i := 0; i < 5; i++ {
+		m.RecordFailure("anthropic", failures.Timeout)
+	}
+	m.RecordFailure("anthropic", failures.ProviderUnavailable)
+	m.RecordFailure("anthropic", failures.ProviderUnavailable)
+	if m.Outage("anthropic") != nil {
+		t.Fatal("Outage() below the failure threshold should be nil")
+	}
+
+	m.RecordFailure("anthropic", failures.ProviderUnavailable)
+	outage := m.Outage("anthropic")
+	if outage == nil || outage.Signal != SignalFailures {
+		t.Fatalf("Outage() at the failure threshold = %+v", outage)
+	}
+	if m.Outage("openai") != nil {
+		t.Error("Outage() of another provider should be nil")
+	}
+
+	m.RecordSuccess("anthropic")
+	if m.Outage("anthropic") != nil {
+		t.Fatal("Outage() after a success should be nil")
+	}
+
+	want := []string{"anthropic failures", "anthropic ended"}
+	if got := changes.get(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("OnChange calls = %v, want %v", got, want)
+	}
+}
+
+func TestFailureSignalExpires(t *testing.T) {
+	m := New(Options{FailureThreshold: 1, FailureWindow: 10 * time.Millisecond})
+	m.RecordFailure("openai", failures.ProviderUnavailable)
+	if m.Outage("openai") == nil {
+		t.Fatal("Outage() at the failure threshold should be set")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	m.Check(context.Background())
+	if m.Outage("openai") != nil {
+		t.Error("Outage() after the failure window should be nil")
+	}
+}
+
+func TestFailureSignalDisabled(t *testing.T) {
+	m := New(Options{FailureWindow: time.Hour})
+	for i := 0; i < 10; i++ {
+		m.RecordFailure("anthropic", failures.ProviderUnavailable)
+	}
+	if m.Outage("anthropic") != nil {
+		t.Error("Outage() with the failure signal disabled should be nil")
+	}
+}
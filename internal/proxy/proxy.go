@@ -0,0 +1,143 @@
+// Package proxy builds HTTP transports that honor nightcrier's proxy configuration.
+// Enterprise environments frequently only allow egress through an HTTP proxy, so
+// every outbound HTTP client (MCP connections, Slack webhooks, Azure Blob Storage)
+// is created through this package instead of relying on ad-hoc transports.
+//
+// Resolution order for a destination:
+//  1. An explicit per-destination proxy URL (e.g. proxy.mcp)
+//  2. The global proxy.http_proxy / proxy.https_proxy / proxy.no_proxy settings
+//  3. The standard HTTP_PROXY / HTTPS_PROXY / NO_PROXY environment variables
+//
+// The special value "direct" disables proxying for a destination even when a
+// global or environment proxy is configured.
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Direct is the per-destination value that bypasses all proxies.
+const Direct = "direct"
+
+// Settings describes the proxy behavior for one class of outbound destination.
+type Settings struct {
+	// ProxyURL is an explicit proxy for this destination (overrides the global settings).
+	// Use "direct" to disable proxying for this destination.
+	ProxyURL string
+
+	// HTTPProxy is the global proxy for plain HTTP requests.
+	HTTPProxy string
+
+	// HTTPSProxy is the global proxy for HTTPS requests.
+	HTTPSProxy string
+
+	// NoProxy is a comma-separated list of hosts, domains, and CIDRs that bypass the proxy.
+	NoProxy string
+}
+
+// Validate checks that all configured proxy URLs are parseable.
+func (s Settings) Validate() error {
+	for name, raw := range map[string]string{
+		"proxy_url":   s.ProxyURL,
+		"http_proxy":  s.HTTPProxy,
+		"https_proxy": s.HTTPSProxy,
+	} {
+		if raw == "" || strings.EqualFold(raw, Direct) {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", name, raw, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid %s %q: must be an absolute URL such as http://proxy.example.com:3128", name, raw)
+		}
+	}
+	return nil
+}
+
+// IsDirect reports whether proxying is explicitly disabled for this destination.
+func (s Settings) IsDirect() bool {
+	return strings.EqualFold(s.ProxyURL, Direct)
+}
+
+// ProxyFunc returns the function used as http.Transport.Proxy for these settings.
+func (s Settings) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	if s.IsDirect() {
+		return nil
+	}
+
+	// Explicit per-destination proxy applies to both schemes
+	if s.ProxyURL != "" {
+		cfg := &httpproxy.Config{
+			HTTPProxy:  s.ProxyURL,
+			HTTPSProxy: s.ProxyURL,
+			NoProxy:    s.NoProxy,
+		}
+		return requestProxyFunc(cfg)
+	}
+
+	// Global settings from nightcrier config
+	if s.HTTPProxy != "" || s.HTTPSProxy != "" {
+		cfg := &httpproxy.Config{
+			HTTPProxy:  s.HTTPProxy,
+			HTTPSProxy: s.HTTPSProxy,
+			NoProxy:    s.NoProxy,
+		}
+		return requestProxyFunc(cfg)
+	}
+
+	// Fall back to the standard environment variables
+	return http.ProxyFromEnvironment
+}
+
+// requestProxyFunc adapts an httpproxy.Config to the http.Transport.Proxy signature.
+func requestProxyFunc(cfg *httpproxy.Config) func(*http.Request) (*url.URL, error) {
+	fn := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return fn(req.URL)
+	}
+}
+
+// NewTransport returns a clone of http.DefaultTransport configured with these proxy settings.
+func NewTransport(s Settings) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = s.ProxyFunc()
+	return transport
+}
+
+// NewHTTPClient returns an http.Client using a proxy-aware transport.
+func NewHTTPClient(s Settings) *http.Client {
+	return &http.Client{Transport: NewTransport(s)}
+}
+
+// Environment returns HTTP_PROXY / HTTPS_PROXY / NO_PROXY assignments for child
+// processes (e.g. the agent container making LLM API calls). It returns nil when
+// no proxy is configured in nightcrier, leaving any inherited environment untouched.
+func (s Settings) Environment() []string {
+	if s.IsDirect() {
+		return []string{"HTTP_PROXY=", "HTTPS_PROXY=", "NO_PROXY="}
+	}
+
+	httpProxy, httpsProxy := s.HTTPProxy, s.HTTPSProxy
+	if s.ProxyURL != "" {
+		httpProxy, httpsProxy = s.ProxyURL, s.ProxyURL
+	}
+	if httpProxy == "" && httpsProxy == "" {
+		return nil
+	}
+
+	env := []string{
+		fmt.Sprintf("HTTP_PROXY=%s", httpProxy),
+		fmt.Sprintf("HTTPS_PROXY=%s", httpsProxy),
+	}
+	if s.NoProxy != "" {
+		env = append(env, fmt.Sprintf("NO_PROXY=%s", s.NoProxy))
+	}
+	return env
+}
@@ -0,0 +1,83 @@
+package proxy
+
+import (
+	"net/http"
+	"testing"
+)
+
+func proxyFor(t *testing.T, s Settings, target string) string {
+	t.Helper()
+	fn := s.ProxyFunc()
+	if fn == nil {
+		return ""
+	}
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	u, err := fn(req)
+	if err != nil {
+		t.Fatalf("proxy func returned error: %v", err)
+	}
+	if u == nil {
+		return ""
+	}
+	return u.String()
+}
+
+func TestProxyFunc_ExplicitOverridesGlobal(t *testing.T) {
+	s := Settings{
+		ProxyURL:   "http://mcp-proxy:3128",
+		HTTPSProxy: "http://global-proxy:3128",
+	}
+	if got := proxyFor(t, s, "https://mcp.example.com/mcp"); got != "http://mcp-proxy:3128" {
+		t.Errorf("proxy = %q, want explicit proxy", got)
+	}
+}
+
+func TestProxyFunc_GlobalWithNoProxy(t *testing.T) {
+	s := Settings{
+		HTTPProxy:  "http://global-proxy:3128",
+		HTTPSProxy: "http://global-proxy:3128",
+		NoProxy:    ".svc.cluster.local",
+	}
+	if got := proxyFor(t, s, "https://hooks.slack.com/services/x"); got != "http://global-proxy:3128" {
+		t.Errorf("proxy = %q, want global proxy", got)
+	}
+	if got := proxyFor(t, s, "http://mcp.monitoring.svc.cluster.local:8080/mcp"); got != "" {
+		t.Errorf("proxy = %q, want no proxy for NO_PROXY host", got)
+	}
+}
+
+func TestProxyFunc_Direct(t *testing.T) {
+	s := Settings{ProxyURL: "direct", HTTPSProxy: "http://global-proxy:3128"}
+	if s.ProxyFunc() != nil {
+		t.Error("direct settings should disable the transport proxy")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Settings{ProxyURL: "direct"}).Validate(); err != nil {
+		t.Errorf("direct should be valid: %v", err)
+	}
+	if err := (Settings{HTTPProxy: "proxy.example.com"}).Validate(); err == nil {
+		t.Error("proxy without scheme should be rejected")
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	if env := (Settings{}).Environment(); env != nil {
+		t.Errorf("Environment() = %v, want nil when no proxy configured", env)
+	}
+
+	env := Settings{ProxyURL: "http://llm-proxy:3128", NoProxy: "localhost"}.Environment()
+	want := []string{"HTTP_PROXY=http://llm-proxy:3128", "HTTPS_PROXY=http://llm-proxy:3128", "NO_PROXY=localhost"}
+	if len(env) != len(want) {
+		t.Fatalf("Environment() = %v, want %v", env, want)
+	}
+	for i := range want {
+		if env[i] != want[i] {
+			t.Errorf("Environment()[%d] = %q, want %q", i, env[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package reporting
+
+import (
+	"fmt"
+	"time"
+)
+
+// CanaryAlert reports the outcome of a synthetic canary investigation of a cluster.
+type CanaryAlert struct {
+	Cluster string
+	// IncidentID identifies the canary run's workspace and stored artifacts
+	IncidentID string
+	// Passed is set for passing runs (recoveries and, when enabled, every success)
+	Passed bool
+	// Recovered is set for the first passing run after a failure
+	Recovered bool
+	// Stage is the pipeline stage that failed: "mcp", "agent", "storage", or "notify"
+	Stage string
+	// Error describes the failure
+	Error string
+	// Duration is how long the canary run took
+	Duration time.Duration
+	// FailingSince is when the canary started failing (failures and recoveries)
+	FailingSince time.Time
+}
+
+// canaryAlertTitle returns the headline of a canary alert.
+func canaryAlertTitle(alert CanaryAlert) string {
+	switch {
+	case alert.Recovered:
+		return fmt.Sprintf("Canary Recovered: %s", alert.Cluster)
+	case alert.Passed:
+		return fmt.Sprintf("Canary Passed: %s", alert.Cluster)
+	default:
+		return fmt.Sprintf("Canary Failed: %s (%s)", alert.Cluster, alert.Stage)
+	}
+}
+
+// canaryAlertText explains a canary alert.
+func canaryAlertText(alert CanaryAlert) string {
+	switch {
+	case alert.Recovered:
+		return fmt.Sprintf("The investigation pipeline for this cluster is healthy again after failing since %s.",
+			alert.FailingSince.UTC().Format(time.RFC3339))
+	case alert.Passed:
+		return "The investigation pipeline for this cluster is healthy."
+	default:
+		return fmt.Sprintf("The synthetic investigation failed at the %s stage, so real faults from this cluster may not be investigated or reported. Error: %s",
+			alert.Stage, alert.Error)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/failures"
 )
 
 // Notification Circuit Breaker
@@ -37,7 +38,10 @@ type CircuitBreaker struct {
 	state             CircuitBreakerState
 	alerted           bool
 	failureReasons    []string
+	failureClasses    map[failures.Class]int
 	maxReasons        int
+	suspectedOutage   string
+	tuningFollower
 }
 
 // FailureStats contains statistics about failures for alert messages
@@ -47,6 +51,14 @@ type FailureStats struct {
 	LastFailureTime  time.Time
 	Duration         time.Duration
 	RecentReasons    []string
+
+	// Classes counts the failures of each class since the first failure.
+	// Failures recorded without a class are counted as unknown.
+	Classes map[failures.Class]int
+
+	// SuspectedOutage describes the provider outage that tripped the circuit
+	// breaker (see Trip), or is empty
+	SuspectedOutage string
 }
 
 // NewCircuitBreaker creates a new circuit breaker with the specified failure threshold
@@ -60,11 +72,24 @@ func NewCircuitBreaker(threshold int, tuning *config.TuningConfig) *CircuitBreak
 		state:          StateClosed,
 		maxReasons:     maxReasons,
 		failureReasons: make([]string, 0, maxReasons),
+		failureClasses: make(map[failures.Class]int),
 	}
 }
 
-// RecordFailure records an agent failure and updates the circuit breaker state
+// RecordFailure records an agent failure of unknown class and updates the
+// circuit breaker state
 func (cb *CircuitBreaker) RecordFailure(reason string) {
+	cb.recordFailure(failures.Unknown, reason)
+}
+
+// RecordClassifiedFailure records an agent failure with its canonical class and
+// updates the circuit breaker state. The recorded reason is prefixed with the
+// class, so alerts lead with the cause rather than free text.
+func (cb *CircuitBreaker) RecordClassifiedFailure(class failures.Class, reason string) {
+	cb.recordFailure(class, string(class)+": "+reason)
+}
+
+func (cb *CircuitBreaker) recordFailure(class failures.Class, reason string) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
@@ -77,11 +102,12 @@ func (cb *CircuitBreaker) RecordFailure(reason string) {
 
 	cb.failureCount++
 	cb.lastFailureTime = now
+	cb.failureClasses[class]++
 
 	// Store failure reason (keep only most recent ones)
 	cb.failureReasons = append(cb.failureReasons, reason)
-	if len(cb.failureReasons) > cb.maxReasons {
-		cb.failureReasons = cb.failureReasons[1:]
+	if max := cb.reasonsTracked(cb.maxReasons); len(cb.failureReasons) > max {
+		cb.failureReasons = cb.failureReasons[len(cb.failureReasons)-max:]
 	}
 
 	// Open circuit if threshold reached
@@ -90,13 +116,39 @@ func (cb *CircuitBreaker) RecordFailure(reason string) {
 	}
 }
 
+// Trip opens the circuit breaker without waiting for failures, because a provider
+// outage is suspected (see the providerstatus package). The outage is reported in
+// the failure statistics until ClearOutage; like failures, the circuit closes
+// with the next successful agent execution.
+func (cb *CircuitBreaker) Trip(outage string) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := time.Now()
+	if cb.firstFailureTime.IsZero() {
+		cb.firstFailureTime = now
+		cb.lastFailureTime = now
+	}
+	cb.suspectedOutage = outage
+	cb.state = StateOpen
+}
+
+// ClearOutage ends the suspected outage reported by Trip. The circuit stays open
+// until an agent execution succeeds.
+func (cb *CircuitBreaker) ClearOutage() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.suspectedOutage = ""
+}
+
 // RecordSuccess records a successful agent execution and returns whether a recovery alert is needed
 func (cb *CircuitBreaker) RecordSuccess() (needsRecoveryAlert bool) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	// If we were in an open state with failures, we need a recovery alert
-	needsRecoveryAlert = cb.state == StateOpen && cb.failureCount > 0 && cb.alerted
+	// If we were in an alerted open state (after failures or a Trip), we need a
+	// recovery alert
+	needsRecoveryAlert = cb.state == StateOpen && cb.alerted
 
 	// Reset all state
 	cb.failureCount = 0
@@ -105,6 +157,8 @@ func (cb *CircuitBreaker) RecordSuccess() (needsRecoveryAlert bool) {
 	cb.state = StateClosed
 	cb.alerted = false
 	cb.failureReasons = cb.failureReasons[:0]
+	clear(cb.failureClasses)
+	cb.suspectedOutage = ""
 
 	return needsRecoveryAlert
 }
@@ -135,6 +189,10 @@ func (cb *CircuitBreaker) GetStats() FailureStats {
 	// Copy reasons to avoid race conditions
 	reasons := make([]string, len(cb.failureReasons))
 	copy(reasons, cb.failureReasons)
+	classes := make(map[failures.Class]int, len(cb.failureClasses))
+	for class, count := range cb.failureClasses {
+		classes[class] = count
+	}
 
 	return FailureStats{
 		Count:            cb.failureCount,
@@ -142,6 +200,8 @@ func (cb *CircuitBreaker) GetStats() FailureStats {
 		LastFailureTime:  cb.lastFailureTime,
 		Duration:         duration,
 		RecentReasons:    reasons,
+		Classes:          classes,
+		SuspectedOutage:  cb.suspectedOutage,
 	}
 }
 
@@ -170,4 +230,6 @@ func (cb *CircuitBreaker) Reset() {
 	cb.state = StateClosed
 	cb.alerted = false
 	cb.failureReasons = cb.failureReasons[:0]
+	clear(cb.failureClasses)
+	cb.suspectedOutage = ""
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/failures"
 )
 
 func defaultTestTuning() *config.TuningConfig {
@@ -222,6 +223,34 @@ func TestGetStats(t *testing.T) {
 	}
 }
 
+func TestRecordClassifiedFailure(t *testing.T) {
+	cb := NewCircuitBreaker(5, defaultTestTuning())
+
+	cb.RecordClassifiedFailure(failures.ProviderRateLimit, "agent exited with non-zero code: 1")
+	cb.RecordClassifiedFailure(failures.ProviderRateLimit, "investigation.md file not found")
+	cb.RecordClassifiedFailure(failures.Timeout, "agent exited with non-zero code: 124")
+	cb.RecordFailure("unclassified")
+
+	stats := cb.GetStats()
+	want := map[failures.Class]int{failures.ProviderRateLimit: 2, failures.Timeout: 1, failures.Unknown: 1}
+	for class, count := range want {
+		if stats.Classes[class] != count {
+			t.Errorf("stats.Classes[%s] = %d, want %d", class, stats.Classes[class], count)
+		}
+	}
+	if got := stats.RecentReasons[2]; got != "timeout: agent exited with non-zero code: 124" {
+		t.Errorf("stats.RecentReasons[2] = %q, want the class prefixed", got)
+	}
+	if got := failureClassesText(stats); got != "provider_rate_limit (2), timeout (1), unknown (1)" {
+		t.Errorf("failureClassesText() = %q", got)
+	}
+
+	cb.RecordSuccess()
+	if stats := cb.GetStats(); len(stats.Classes) != 0 {
+		t.Errorf("stats.Classes after success = %v, want empty", stats.Classes)
+	}
+}
+
 func TestMaxReasons(t *testing.T) {
 	cb := NewCircuitBreaker(10, defaultTestTuning())
 
@@ -335,6 +364,47 @@ func TestReset(t *testing.T) {
 	}
 }
 
+func TestTrip(t *testing.T) {
+	cb := NewCircuitBreaker(3, defaultTestTuning())
+
+	// Trip opens the circuit without any failures
+	cb.Trip("anthropic: status page reports major outage")
+	if cb.GetState() != StateOpen {
+		t.Errorf("state after Trip = %d, want StateOpen (%d)", cb.GetState(), StateOpen)
+	}
+	if !cb.ShouldAlert() {
+		t.Error("ShouldAlert() = false after Trip, want true")
+	}
+	stats := cb.GetStats()
+	if stats.SuspectedOutage != "anthropic: status page reports major outage" {
+		t.Errorf("stats.SuspectedOutage = %q, want the outage", stats.SuspectedOutage)
+	}
+	if stats.FirstFailureTime.IsZero() {
+		t.Error("stats.FirstFailureTime is zero after Trip")
+	}
+
+	// ClearOutage drops the note but keeps the circuit open
+	cb.ClearOutage()
+	if got := cb.GetStats().SuspectedOutage; got != "" {
+		t.Errorf("stats.SuspectedOutage after ClearOutage = %q, want empty", got)
+	}
+	if cb.GetState() != StateOpen {
+		t.Errorf("state after ClearOutage = %d, want StateOpen (%d)", cb.GetState(), StateOpen)
+	}
+
+	// A success closes the circuit and clears the note
+	cb.Trip("openai: failures")
+	if !cb.RecordSuccess() {
+		t.Error("RecordSuccess() = false after alerted Trip, want true")
+	}
+	if cb.GetState() != StateClosed {
+		t.Errorf("state after success = %d, want StateClosed (%d)", cb.GetState(), StateClosed)
+	}
+	if got := cb.GetStats().SuspectedOutage; got != "" {
+		t.Errorf("stats.SuspectedOutage after success = %q, want empty", got)
+	}
+}
+
 func TestMultipleCycles(t *testing.T) {
 	cb := NewCircuitBreaker(2, defaultTestTuning())
 
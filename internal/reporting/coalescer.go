@@ -0,0 +1,320 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCoalesceWindow is how long a Coalescer collects notifications before
+// delivering them.
+const DefaultCoalesceWindow = 30 * time.Second
+
+const (
+	// maxDigestItems bounds the notifications listed per digest section; the rest
+	// are only counted
+	maxDigestItems = 10
+	// maxDigestTextLength bounds the free text (root causes, errors) quoted in a
+	// digest line
+	maxDigestTextLength = 120
+)
+
+// Digest section titles
+const (
+	DigestSectionSystem      = "Agent System"
+	DigestSectionConnections = "Cluster Connections"
+	DigestSectionCredentials = "Cluster Credentials"
+	DigestSectionQueues      = "Event Queues"
+	DigestSectionStorage     = "State Store"
+	DigestSectionCanary      = "Canary"
+	DigestSectionBudget      = "Investigation Budgets"
+	DigestSectionIncidents   = "Incidents"
+	DigestSectionTuning      = "Tuning Suggestions"
+)
+
+// digestSectionOrder lists the digest sections most urgent first: system-wide
+// problems usually explain the cluster-level and incident notifications below them.
+var digestSectionOrder = []string{
+	DigestSectionSystem,
+	DigestSectionConnections,
+	DigestSectionCredentials,
+	DigestSectionQueues,
+	DigestSectionStorage,
+	DigestSectionCanary,
+	DigestSectionBudget,
+	DigestSectionIncidents,
+	DigestSectionTuning,
+}
+
+// Digest is several notifications merged into a single message by a Coalescer, or
+// a periodic report (such as the noise analysis) sent as a single message.
+type Digest struct {
+	// Heading replaces the default headline of a merged digest
+	Heading string
+	// Note replaces the default footer of a merged digest
+	Note string
+	// Count is the number of notifications merged into the digest
+	Count int
+	// Window is how long the notifications were collected
+	Window time.Duration
+	// Sections group the notifications by kind, most urgent first
+	Sections []DigestSection
+}
+
+// DigestSection lists the notifications of one kind.
+type DigestSection struct {
+	Title string
+	// Items has one line per notification
+	Items []string
+	// Omitted is the number of notifications not listed in Items
+	Omitted int
+}
+
+// Title returns the headline of a digest.
+func (d Digest) Title() string {
+	if d.Heading != "" {
+		return d.Heading
+	}
+	return fmt.Sprintf("Nightcrier: %d related notifications", d.Count)
+}
+
+// Footer explains why the notifications were merged.
+func (d Digest) Footer() string {
+	if d.Note != "" {
+		return d.Note
+	}
+	return fmt.Sprintf("Notifications fired within %s of each other were merged into this message.", d.Window.Round(time.Second))
+}
+
+// Text lists the section's items one per line, each preceded by bullet.
+func (s DigestSection) Text(bullet string) string {
+	lines := make([]string, 0, len(s.Items)+1)
+	for _, item := range s.Items {
+		lines = append(lines, bullet+item)
+	}
+	if s.Omitted > 0 {
+		lines = append(lines, fmt.Sprintf("%sand %d more", bullet, s.Omitted))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// coalescedNotification is a notification held by a Coalescer.
+type coalescedNotification struct {
+	section string
+	item    string
+	// deliver sends the notification on its own, unmerged
+	deliver func(Notifier) error
+}
+
+// Coalescer merges notifications that fire within a short window into a single
+// digest message with a section per kind of notification, so an outage that trips
+// the circuit breaker, disconnects a cluster, and fails several investigations
+// pages once instead of many times.
+//
+// The first notification opens a window; every notification sent before it closes
+// is delivered together when it does. A window holding a single notification
+// delivers it unchanged. Delivery happens in the background, so the Send methods
+// return nil and delivery errors are logged. Call Flush on shutdown to deliver
+// notifications still being collected.
+type Coalescer struct {
+	next   Notifier
+	window time.Duration
+
+	mu      sync.Mutex
+	pending []coalescedNotification
+	timer   *time.Timer
+}
+
+// NewCoalescer creates a Coalescer that delivers through next, collecting
+// notifications for window (DefaultCoalesceWindow if zero).
+func NewCoalescer(next Notifier, window time.Duration) *Coalescer {
+	if window <= 0 {
+		window = DefaultCoalesceWindow
+	}
+	return &Coalescer{next: next, window: window}
+}
+
+// Name implements Notifier.
+func (c *Coalescer) Name() string { return c.next.Name() }
+
+// SendIncidentNotification implements Notifier.
+func (c *Coalescer) SendIncidentNotification(summary *IncidentSummary) error {
+	c.add(DigestSectionIncidents, incidentDigestItem(summary), func(n Notifier) error {
+		return n.SendIncidentNotification(summary)
+	})
+	return nil
+}
+
+// SendSystemDegradedAlert implements Notifier.
+func (c *Coalescer) SendSystemDegradedAlert(ctx context.Context, stats FailureStats) error {
+	ctx = context.WithoutCancel(ctx)
+	item := fmt.Sprintf("AI agent system degraded: %d failures in %s", stats.Count, stats.Duration.Round(time.Second))
+	if reasons := recentFailureReasons(stats, 1); len(reasons) > 0 {
+		item += " (last: " + truncateDigestText(reasons[0]) + ")"
+	}
+	c.add(DigestSectionSystem, item, func(n Notifier) error { return n.SendSystemDegradedAlert(ctx, stats) })
+	return nil
+}
+
+// SendSystemRecoveredAlert implements Notifier.
+func (c *Coalescer) SendSystemRecoveredAlert(ctx context.Context, stats FailureStats) error {
+	ctx = context.WithoutCancel(ctx)
+	item := fmt.Sprintf("AI agent system recovered after %d failures", stats.Count)
+	c.add(DigestSectionSystem, item, func(n Notifier) error { return n.SendSystemRecoveredAlert(ctx, stats) })
+	return nil
+}
+
+// SendBudgetExhaustedAlert implements Notifier.
+func (c *Coalescer) SendBudgetExhaustedAlert(ctx context.Context, alert BudgetAlert) error {
+	ctx = context.WithoutCancel(ctx)
+	item := fmt.Sprintf("%s: daily budget exhausted (%s); investigations skipped", alert.Cluster, alert.Reason)
+	c.add(DigestSectionBudget, item, func(n Notifier) error { return n.SendBudgetExhaustedAlert(ctx, alert) })
+	return nil
+}
+
+// SendClusterDisconnectedAlert implements Notifier.
+func (c *Coalescer) SendClusterDisconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	ctx = context.WithoutCancel(ctx)
+	item := fmt.Sprintf("%s: %s for %s (error: %s)", alert.Cluster, alert.Status, alert.Duration.Round(time.Second),
+		truncateDigestText(connectionErrorText(alert)))
+	c.add(DigestSectionConnections, item, func(n Notifier) error { return n.SendClusterDisconnectedAlert(ctx, alert) })
+	return nil
+}
+
+// SendClusterReconnectedAlert implements Notifier.
+func (c *Coalescer) SendClusterReconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	ctx = context.WithoutCancel(ctx)
+	item := fmt.Sprintf("%s: reconnected after %s", alert.Cluster, alert.Duration.Round(time.Second))
+	c.add(DigestSectionConnections, item, func(n Notifier) error { return n.SendClusterReconnectedAlert(ctx, alert) })
+	return nil
+}
+
+// SendQueueAlert implements Notifier.
+func (c *Coalescer) SendQueueAlert(ctx context.Context, alert QueueAlert) error {
+	ctx = context.WithoutCancel(ctx)
+	item := fmt.Sprintf("%s (%d/%d)", queueAlertTitle(alert), alert.Depth, alert.Capacity)
+	if alert.Dropped > 0 {
+		item += fmt.Sprintf(", %d events dropped", alert.Dropped)
+	}
+	c.add(DigestSectionQueues, item, func(n Notifier) error { return n.SendQueueAlert(ctx, alert) })
+	return nil
+}
+
+// SendCanaryAlert implements Notifier.
+func (c *Coalescer) SendCanaryAlert(ctx context.Context, alert CanaryAlert) error {
+	ctx = context.WithoutCancel(ctx)
+	item := canaryAlertTitle(alert)
+	if !alert.Passed && alert.Error != "" {
+		item += ": " + truncateDigestText(alert.Error)
+	}
+	c.add(DigestSectionCanary, item, func(n Notifier) error { return n.SendCanaryAlert(ctx, alert) })
+	return nil
+}
+
+// SendCredentialExpiryAlert implements Notifier.
+func (c *Coalescer) SendCredentialExpiryAlert(ctx context.Context, alert CredentialExpiryAlert) error {
+	ctx = context.WithoutCancel(ctx)
+	item := fmt.Sprintf("%s: credentials %s", alert.Cluster, alert.State)
+	if alert.State != CredentialsRenewed {
+		item += fmt.Sprintf(" (%s of %s, expires %s)", alert.Kind, alert.User, alert.expiresText())
+	}
+	c.add(DigestSectionCredentials, item, func(n Notifier) error { return n.SendCredentialExpiryAlert(ctx, alert) })
+	return nil
+}
+
+// SendDigest implements Notifier. Digests are delivered immediately.
+func (c *Coalescer) SendDigest(ctx context.Context, digest Digest) error {
+	return c.next.SendDigest(ctx, digest)
+}
+
+// add holds a notification, opening a window if none is open.
+func (c *Coalescer) add(section, item string, deliver func(Notifier) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = append(c.pending, coalescedNotification{section: section, item: item, deliver: deliver})
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.window, c.Flush)
+	}
+}
+
+// Flush delivers the notifications collected so far and closes the window: a
+// single notification unchanged, several as one digest.
+func (c *Coalescer) Flush() {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = nil
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.mu.Unlock()
+
+	var err error
+	switch len(pending) {
+	case 0:
+		return
+	case 1:
+		err = pending[0].deliver(c.next)
+	default:
+		err = c.next.SendDigest(context.Background(), buildDigest(pending, c.window))
+		slog.Info("coalesced notifications into one message", "notifications", len(pending))
+	}
+	if err != nil {
+		slog.Error("failed to send coalesced notifications", "notifications", len(pending), "error", err)
+	}
+}
+
+// buildDigest groups notifications into sections in digestSectionOrder, keeping
+// the order they were sent in within each section.
+func buildDigest(pending []coalescedNotification, window time.Duration) Digest {
+	digest := Digest{Count: len(pending), Window: window}
+	for _, title := range digestSectionOrder {
+		section := DigestSection{Title: title}
+		for _, n := range pending {
+			if n.section != title {
+				continue
+			}
+			if len(section.Items) == maxDigestItems {
+				section.Omitted++
+				continue
+			}
+			section.Items = append(section.Items, n.item)
+		}
+		if len(section.Items) > 0 {
+			digest.Sections = append(digest.Sections, section)
+		}
+	}
+	return digest
+}
+
+// incidentDigestItem summarizes an incident notification in one line.
+func incidentDigestItem(s *IncidentSummary) string {
+	item := fmt.Sprintf("%s: %s on %s in %s", s.Cluster, s.Reason, s.Resource, s.Namespace)
+	switch {
+	case s.RecordedOnly:
+		item += " (not investigated)"
+	case !s.Investigated():
+		item += " - investigation failed"
+	default:
+		item += fmt.Sprintf(" - %s (%s confidence)", truncateDigestText(s.RootCause), s.Confidence)
+		if s.AwaitingReview() {
+			item += ", " + s.Status
+		}
+	}
+	if s.ReportURL != "" {
+		item += " " + s.ReportURL
+	}
+	return item
+}
+
+// truncateDigestText shortens free text quoted in a digest line to
+// maxDigestTextLength characters.
+func truncateDigestText(text string) string {
+	if runes := []rune(text); len(runes) > maxDigestTextLength {
+		return string(runes[:maxDigestTextLength-3]) + "..."
+	}
+	return text
+}
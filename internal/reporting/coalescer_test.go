@@ -0,0 +1,115 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// digestRecorder records the digests it receives; other notifications go to the
+// embedded recordingNotifier.
+type digestRecorder struct {
+	recordingNotifier
+	mu      sync.Mutex
+	digests []Digest
+}
+
+func (r *digestRecorder) SendDigest(ctx context.Context, digest Digest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.digests = append(r.digests, digest)
+	return nil
+}
+
+func TestCoalescer_SingleNotificationUnchanged(t *testing.T) {
+	rec := &digestRecorder{}
+	c := NewCoalescer(rec, time.Hour)
+
+	if err := c.SendClusterDisconnectedAlert(context.Background(), ClusterConnectionAlert{Cluster: "prod"}); err != nil {
+		t.Fatalf("SendClusterDisconnectedAlert() error = %v", err)
+	}
+	if len(rec.calls) != 0 {
+		t.Fatalf("calls = %v, want the notification held until the window closes", rec.calls)
+	}
+
+	c.Flush()
+	if got := strings.Join(rec.calls, " "); got != "disconnected:prod" || len(rec.digests) != 0 {
+		t.Errorf("calls = %q, digests = %d; a lone notification must be delivered unchanged", got, len(rec.digests))
+	}
+
+	c.Flush()
+	if len(rec.calls) != 1 {
+		t.Errorf("calls = %v, want nothing delivered twice", rec.calls)
+	}
+}
+
+func TestCoalescer_MergesIntoSections(t *testing.T) {
+	rec := &digestRecorder{}
+	c := NewCoalescer(rec, time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < maxDigestItems+2; i++ {
+		c.SendIncidentNotification(&IncidentSummary{
+			Cluster: "prod", Namespace: "default", Resource: fmt.Sprintf("Pod/web-%d", i),
+			Reason: "CrashLoopBackOff", Status: "agent_failed",
+		})
+	}
+	c.SendClusterDisconnectedAlert(ctx, ClusterConnectionAlert{Cluster: "prod", Status: "failed", Duration: time.Minute})
+	c.SendSystemDegradedAlert(ctx, FailureStats{Count: 3, Duration: 2 * time.Minute, RecentReasons: []string{"API unreachable"}})
+	c.Flush()
+
+	if len(rec.calls) != 0 || len(rec.digests) != 1 {
+		t.Fatalf("calls = %v, digests = %d; want one digest", rec.calls, len(rec.digests))
+	}
+	digest := rec.digests[0]
+	if digest.Count != maxDigestItems+4 || digest.Title() != fmt.Sprintf("Nightcrier: %d related notifications", maxDigestItems+4) {
+		t.Errorf("count = %d, title = %q", digest.Count, digest.Title())
+	}
+
+	var titles []string
+	for _, section := range digest.Sections {
+		titles = append(titles, section.Title)
+	}
+	if got := strings.Join(titles, ","); got != "Agent System,Cluster Connections,Incidents" {
+		t.Fatalf("sections = %q, want system-wide alerts first", got)
+	}
+	if got := digest.Sections[0].Items[0]; got != "AI agent system degraded: 3 failures in 2m0s (last: API unreachable)" {
+		t.Errorf("system item = %q", got)
+	}
+	incidents := digest.Sections[2]
+	if len(incidents.Items) != maxDigestItems || incidents.Omitted != 2 {
+		t.Errorf("incident items = %d, omitted = %d", len(incidents.Items), incidents.Omitted)
+	}
+	if got := incidents.Items[0]; got != "prod: CrashLoopBackOff on Pod/web-0 in default - investigation failed" {
+		t.Errorf("incident item = %q", got)
+	}
+	if text := incidents.Text("- "); !strings.HasSuffix(text, "\n- and 2 more") {
+		t.Errorf("section text = %q, want the omitted notifications counted", text)
+	}
+}
+
+func TestCoalescer_FlushesAfterWindow(t *testing.T) {
+	rec := &digestRecorder{}
+	c := NewCoalescer(rec, 10*time.Millisecond)
+	ctx := context.Background()
+
+	c.SendBudgetExhaustedAlert(ctx, BudgetAlert{Cluster: "prod", Reason: "investigation limit"})
+	c.SendCanaryAlert(ctx, CanaryAlert{Cluster: "prod", Stage: "agent", Error: "timeout"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		rec.mu.Lock()
+		n := len(rec.digests)
+		rec.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("digest not delivered after the window closed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,119 @@
+package reporting
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+)
+
+// DefaultConnectionCheckInterval is how often the ConnectionAlerter checks the
+// cluster connections when run with Run.
+const DefaultConnectionCheckInterval = 15 * time.Second
+
+// ClusterConnectionAlert describes a cluster whose event stream connection has been
+// down longer than the alert threshold, or that recovered after such an outage.
+type ClusterConnectionAlert struct {
+	Cluster string
+	// Status is the connection status when the alert was raised, e.g. "failed"
+	Status string
+	// Since is when the connection stopped being active
+	Since time.Time
+	// Duration is how long the connection has been (or was) down
+	Duration time.Duration
+	// LastError is the most recent connection error, if any
+	LastError string
+}
+
+// ConnectionAlerter alerts when a single cluster's connection stays disconnected or
+// failed beyond a threshold, and again when it recovers. Unlike the CircuitBreaker,
+// which tracks agent failures across all clusters, it names the affected cluster so
+// a partial outage (one cluster of many silent) does not go unnoticed.
+type ConnectionAlerter struct {
+	notifier  Notifier
+	threshold time.Duration
+
+	mu sync.Mutex
+	// alerted holds the disconnect alert sent for each cluster that is still down
+	alerted map[string]ClusterConnectionAlert
+	now     func() time.Time
+}
+
+// NewConnectionAlerter creates an alerter that alerts through notifier when a
+// cluster connection has been down for threshold.
+func NewConnectionAlerter(notifier Notifier, threshold time.Duration) *ConnectionAlerter {
+	return &ConnectionAlerter{
+		notifier:  notifier,
+		threshold: threshold,
+		alerted:   make(map[string]ClusterConnectionAlert),
+		now:       time.Now,
+	}
+}
+
+// Run checks the connections returned by states every interval until ctx is done.
+func (a *ConnectionAlerter) Run(ctx context.Context, interval time.Duration, states func() []cluster.ConnectionState) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.Check(ctx, states())
+		}
+	}
+}
+
+// Check sends a disconnect alert for each cluster down longer than the threshold
+// that has not been alerted yet, and a recovery alert for each alerted cluster that
+// is active again. Each outage produces at most one alert of each kind.
+func (a *ConnectionAlerter) Check(ctx context.Context, states []cluster.ConnectionState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	for _, state := range states {
+		log := slog.With("cluster", state.Cluster)
+		previous, alerted := a.alerted[state.Cluster]
+
+		if state.Status == cluster.StatusActive {
+			if !alerted {
+				continue
+			}
+			delete(a.alerted, state.Cluster)
+			recovery := previous
+			recovery.Status = string(state.Status)
+			recovery.Duration = now.Sub(previous.Since)
+			if err := a.notifier.SendClusterReconnectedAlert(ctx, recovery); err != nil {
+				log.Error("failed to send cluster reconnected alert", "error", err)
+			} else {
+				log.Info("cluster reconnected alert sent", "outage_duration", recovery.Duration.Round(time.Second))
+			}
+			continue
+		}
+
+		if alerted || state.UnhealthySince.IsZero() || now.Sub(state.UnhealthySince) < a.threshold {
+			continue
+		}
+		alert := ClusterConnectionAlert{
+			Cluster:   state.Cluster,
+			Status:    string(state.Status),
+			Since:     state.UnhealthySince,
+			Duration:  now.Sub(state.UnhealthySince),
+			LastError: state.LastError,
+		}
+		// Mark the outage alerted even if delivery fails, so a notifier outage does not
+		// turn into an alert every check interval
+		a.alerted[state.Cluster] = alert
+		if err := a.notifier.SendClusterDisconnectedAlert(ctx, alert); err != nil {
+			log.Error("failed to send cluster disconnected alert", "error", err)
+		} else {
+			log.Warn("cluster disconnected alert sent",
+				"status", alert.Status,
+				"down_for", alert.Duration.Round(time.Second),
+				"last_error", alert.LastError)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package reporting
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+)
+
+func TestConnectionAlerter_AlertsOnceAndOnRecovery(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	rec := &recordingNotifier{name: "slack"}
+	a := NewConnectionAlerter(rec, 5*time.Minute)
+	a.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	down := []cluster.ConnectionState{
+		{Cluster: "prod-east", Status: cluster.StatusActive},
+		{Cluster: "prod-west", Status: cluster.StatusFailed, UnhealthySince: now.Add(-2 * time.Minute), LastError: "connection refused"},
+	}
+
+	// Below the threshold nothing is sent
+	a.Check(ctx, down)
+	if len(rec.calls) != 0 {
+		t.Fatalf("calls = %v, want none below the threshold", rec.calls)
+	}
+
+	// Past the threshold the disconnected cluster is alerted once
+	now = now.Add(4 * time.Minute)
+	a.Check(ctx, down)
+	now = now.Add(time.Minute)
+	a.Check(ctx, down)
+	if strings.Join(rec.calls, ",") != "disconnected:prod-west" {
+		t.Fatalf("calls = %v, want a single disconnected alert for prod-west", rec.calls)
+	}
+
+	// Recovery is alerted once the connection is active again
+	up := []cluster.ConnectionState{
+		{Cluster: "prod-east", Status: cluster.StatusActive},
+		{Cluster: "prod-west", Status: cluster.StatusActive},
+	}
+	a.Check(ctx, up)
+	a.Check(ctx, up)
+	if strings.Join(rec.calls, ",") != "disconnected:prod-west,reconnected:prod-west" {
+		t.Errorf("calls = %v, want disconnected then reconnected", rec.calls)
+	}
+}
+
+func TestConnectionAlerter_ShortOutageNotAlerted(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	rec := &recordingNotifier{name: "slack"}
+	a := NewConnectionAlerter(rec, 5*time.Minute)
+	a.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	a.Check(ctx, []cluster.ConnectionState{{Cluster: "prod", Status: cluster.StatusConnecting, UnhealthySince: now.Add(-time.Minute)}})
+	a.Check(ctx, []cluster.ConnectionState{{Cluster: "prod", Status: cluster.StatusActive}})
+	if len(rec.calls) != 0 {
+		t.Errorf("calls = %v, want no alerts for an outage shorter than the threshold", rec.calls)
+	}
+}
@@ -0,0 +1,73 @@
+package reporting
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Credential expiry alert states
+const (
+	CredentialsExpiring = "expiring"
+	CredentialsExpired  = "expired"
+	CredentialsRenewed  = "renewed"
+)
+
+// CredentialExpiryAlert reports that a cluster's triage credentials are about to
+// expire or have expired, or were renewed after such an alert (see the credexpiry
+// package).
+type CredentialExpiryAlert struct {
+	Cluster string
+	// State is CredentialsExpiring, CredentialsExpired, or CredentialsRenewed
+	State string
+	// Kubeconfig is the triage kubeconfig holding the credential
+	Kubeconfig string
+	// User, Kind, and Subject identify the credential expiring first, e.g. user
+	// "triage", kind "client-certificate", subject "nightcrier-triage"
+	User    string
+	Kind    string
+	Subject string
+	// ExpiresAt is when the credential expires; zero for renewed credentials that
+	// do not expire
+	ExpiresAt time.Time
+}
+
+// DaysLeft returns the whole days until the credential expires (negative once
+// expired).
+func (a CredentialExpiryAlert) DaysLeft(now time.Time) int {
+	return int(math.Floor(a.ExpiresAt.Sub(now).Hours() / 24))
+}
+
+// expiresText describes when the credential expires, or "never".
+func (a CredentialExpiryAlert) expiresText() string {
+	if a.ExpiresAt.IsZero() {
+		return "never"
+	}
+	return a.ExpiresAt.UTC().Format(time.RFC3339)
+}
+
+// credentialAlertTitle returns the headline of a credential expiry alert.
+func credentialAlertTitle(alert CredentialExpiryAlert) string {
+	switch alert.State {
+	case CredentialsRenewed:
+		return fmt.Sprintf("Cluster Credentials Renewed: %s", alert.Cluster)
+	case CredentialsExpired:
+		return fmt.Sprintf("Cluster Credentials Expired: %s", alert.Cluster)
+	default:
+		return fmt.Sprintf("Cluster Credentials Expiring: %s (%d days left)", alert.Cluster, alert.DaysLeft(time.Now()))
+	}
+}
+
+// credentialAlertText explains a credential expiry alert.
+func credentialAlertText(alert CredentialExpiryAlert) string {
+	switch alert.State {
+	case CredentialsRenewed:
+		return "The cluster's triage credentials were renewed; triage is no longer at risk."
+	case CredentialsExpired:
+		return fmt.Sprintf("The %s of user %s in %s expired at %s. Triage of this cluster fails until the credentials are renewed.",
+			alert.Kind, alert.User, alert.Kubeconfig, alert.expiresText())
+	default:
+		return fmt.Sprintf("The %s of user %s in %s expires at %s. Renew it before then, or triage of this cluster will fail.",
+			alert.Kind, alert.User, alert.Kubeconfig, alert.expiresText())
+	}
+}
@@ -0,0 +1,442 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/labels"
+)
+
+// Discord embed colors
+const (
+	discordColorGood    = 0x2EB67D
+	discordColorDanger  = 0xE01E5A
+	discordColorWarning = 0xECB22E
+)
+
+// discordFieldValueLimit is the maximum length of a Discord embed field value
+const discordFieldValueLimit = 1024
+
+// DiscordNotifier sends incident notifications to a Discord channel webhook
+type DiscordNotifier struct {
+	WebhookURL                 string
+	httpClient                 *http.Client
+	failureReasonsDisplayCount int
+	labels                     notificationLabels
+	tuningFollower
+}
+
+// DiscordMessage represents a Discord webhook message
+type DiscordMessage struct {
+	Username string         `json:"username,omitempty"`
+	Content  string         `json:"content,omitempty"`
+	Embeds   []DiscordEmbed `json:"embeds,omitempty"`
+}
+
+// DiscordEmbed represents a rich embed in a Discord message
+type DiscordEmbed struct {
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Color       int                 `json:"color,omitempty"`
+	Fields      []DiscordEmbedField `json:"fields,omitempty"`
+	Footer      *DiscordEmbedFooter `json:"footer,omitempty"`
+}
+
+// DiscordEmbedField represents a name/value field in an embed
+type DiscordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// DiscordEmbedFooter represents the footer of an embed
+type DiscordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// NewDiscordNotifier creates a new Discord notifier
+func NewDiscordNotifier(webhookURL string, tuning *config.TuningConfig) *DiscordNotifier {
+	return &DiscordNotifier{
+		WebhookURL: webhookURL,
+		httpClient: &http.Client{
+			Timeout: time.Duration(tuning.HTTP.SlackTimeoutSeconds) * time.Second,
+		},
+		failureReasonsDisplayCount: tuning.Reporting.FailureReasonsDisplayCount,
+		labels:                     englishLabels,
+	}
+}
+
+// Name implements Notifier.
+func (d *DiscordNotifier) Name() string { return "discord" }
+
+// SetTransport sets the HTTP transport used for webhook requests, e.g. one
+// configured with proxy settings. The configured timeout is preserved.
+func (d *DiscordNotifier) SetTransport(transport http.RoundTripper) {
+	d.httpClient.Transport = transport
+}
+
+// SetLanguage sets the language of incident notification labels so they match the
+// language of the investigation report. Languages without a translation use English.
+func (d *DiscordNotifier) SetLanguage(language string) {
+	d.labels = labelsFor(language)
+}
+
+// SendIncidentNotification sends a formatted incident notification to Discord
+func (d *DiscordNotifier) SendIncidentNotification(summary *IncidentSummary) error {
+	if d.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	title := "Kubernetes Incident Triage ✅"
+	color := discordColorGood
+	if !summary.Investigated() {
+		title = "Kubernetes Incident Triage ❌"
+		color = discordColorDanger
+	} else if summary.AwaitingReview() {
+		title = "Kubernetes Incident Triage 👀"
+		color = discordColorWarning
+	}
+	footer := fmt.Sprintf("Incident ID: %s | Duration: %s", summary.IncidentID, summary.Duration.Round(time.Second))
+	if summary.CachedFrom != "" {
+		title = strings.Replace(title, "Triage", "Triage (cached)", 1)
+		footer = fmt.Sprintf("Incident ID: %s | Cached report from incident %s", summary.IncidentID, summary.CachedFrom)
+	}
+	if summary.Fallback != "" {
+		title = strings.Replace(title, "Triage", "Triage (rule-based)", 1)
+		footer = fmt.Sprintf("Incident ID: %s | Rule-based report: %s", summary.IncidentID, summary.Fallback)
+	}
+	if summary.Handler != "" {
+		title = strings.Replace(title, "Triage", "Triage (fault handler)", 1)
+		footer = fmt.Sprintf("Incident ID: %s | Resolved by fault handler %s", summary.IncidentID, summary.Handler)
+	}
+	if len(summary.FollowUpOf) > 0 {
+		footer += " | " + followUpText(summary.FollowUpOf, "")
+	}
+	if summary.AwaitingReview() {
+		footer += " | Status: " + summary.Status
+	}
+
+	// Fault events from serve-only clusters have no findings yet
+	detail := DiscordEmbedField{Name: fmt.Sprintf(d.labels.RootCause, summary.Confidence), Value: discordValue(summary.RootCause)}
+	if summary.RecordedOnly {
+		title = "Kubernetes Fault Event (not investigated)"
+		color = discordColorWarning
+		footer = fmt.Sprintf("Incident ID: %s | Recorded without an investigation (serve-only cluster)", summary.IncidentID)
+		detail = DiscordEmbedField{Name: d.labels.Fault, Value: discordValue(summary.FaultContext)}
+	}
+	if summary.Owner != "" {
+		footer += " | Owner: " + summary.Owner
+	}
+	if len(summary.Labels) > 0 {
+		footer += " | Labels: " + labels.Format(summary.Labels)
+	}
+
+	embed := DiscordEmbed{
+		Title: title,
+		URL:   summary.ReportURL,
+		Color: color,
+		Fields: []DiscordEmbedField{
+			{Name: d.labels.Cluster, Value: discordValue(summary.Cluster), Inline: true},
+			{Name: d.labels.Namespace, Value: discordValue(summary.Namespace), Inline: true},
+			{Name: d.labels.Resource, Value: discordValue(summary.Resource), Inline: true},
+			{Name: d.labels.Reason, Value: discordValue(summary.Reason), Inline: true},
+			detail,
+		},
+		Footer: &DiscordEmbedFooter{Text: footer},
+	}
+	if summary.Changes != "" && !summary.RecordedOnly {
+		embed.Fields = append(embed.Fields, DiscordEmbedField{Name: "Changes", Value: discordValue(summary.Changes)})
+	}
+	if summary.ReportURL != "" {
+		embed.Fields = append(embed.Fields, DiscordEmbedField{
+			Name:  d.labels.ViewReport,
+			Value: summary.ReportURL,
+		})
+	} else if summary.ReportPath != "" {
+		embed.Fields = append(embed.Fields, DiscordEmbedField{
+			Name:  "Report",
+			Value: discordValue(summary.ReportPath),
+		})
+	}
+
+	return d.send(DiscordMessage{Embeds: []DiscordEmbed{embed}})
+}
+
+// SendSystemDegradedAlert sends a system-level degradation alert to Discord
+func (d *DiscordNotifier) SendSystemDegradedAlert(ctx context.Context, stats FailureStats) error {
+	if d.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	timeWindow := "N/A"
+	if stats.Duration > 0 {
+		timeWindow = stats.Duration.Round(time.Second).String()
+	}
+
+	reasonsText := "No failure details available"
+	if reasons := recentFailureReasons(stats, d.displayCount(d.failureReasonsDisplayCount)); len(reasons) > 0 {
+		reasonsText = "• " + strings.Join(reasons, "\n• ")
+	}
+
+	embed := DiscordEmbed{
+		Title:       "AI Agent System Degraded",
+		Description: "System degradation threshold reached. AI agent may be experiencing issues.",
+		Color:       discordColorWarning,
+		Fields: []DiscordEmbedField{
+			{Name: "Failure Count", Value: fmt.Sprintf("%d", stats.Count), Inline: true},
+			{Name: "Time Window", Value: timeWindow, Inline: true},
+			{Name: fmt.Sprintf("Sample Failure Reasons (last %d)", d.displayCount(d.failureReasonsDisplayCount)), Value: discordValue(reasonsText)},
+		},
+		Footer: &DiscordEmbedFooter{Text: fmt.Sprintf("First failure: %s | Last failure: %s",
+			stats.FirstFailureTime.Format("15:04:05"),
+			stats.LastFailureTime.Format("15:04:05"))},
+	}
+	if classes := failureClassesText(stats); classes != "" {
+		embed.Fields = append(embed.Fields, DiscordEmbedField{Name: "Failure Classes", Value: discordValue(classes)})
+	}
+	if stats.SuspectedOutage != "" {
+		embed.Fields = append(embed.Fields, DiscordEmbedField{Name: "Provider Outage Suspected", Value: discordValue(stats.SuspectedOutage)})
+	}
+
+	return d.send(DiscordMessage{Embeds: []DiscordEmbed{embed}})
+}
+
+// SendSystemRecoveredAlert sends a system recovery alert to Discord
+func (d *DiscordNotifier) SendSystemRecoveredAlert(ctx context.Context, stats FailureStats) error {
+	if d.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	downtime := "N/A"
+	if stats.Duration > 0 {
+		downtime = stats.Duration.Round(time.Second).String()
+	}
+
+	embed := DiscordEmbed{
+		Title:       "AI Agent System Recovered",
+		Description: "System has returned to healthy state. All agents operating normally.",
+		Color:       discordColorGood,
+		Fields: []DiscordEmbedField{
+			{Name: "Total Downtime", Value: downtime, Inline: true},
+			{Name: "Total Failures", Value: fmt.Sprintf("%d", stats.Count), Inline: true},
+		},
+	}
+
+	return d.send(DiscordMessage{Embeds: []DiscordEmbed{embed}})
+}
+
+// SendBudgetExhaustedAlert notifies Discord that a cluster's daily budget is exhausted
+func (d *DiscordNotifier) SendBudgetExhaustedAlert(ctx context.Context, alert BudgetAlert) error {
+	if d.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	embed := DiscordEmbed{
+		Title:       "Investigation Budget Exhausted",
+		Description: fmt.Sprintf("Further faults are recorded but not investigated. Grant more budget with `nightcrier budget override --cluster %s`.", alert.Cluster),
+		Color:       discordColorWarning,
+		Fields: []DiscordEmbedField{
+			{Name: "Cluster", Value: discordValue(alert.Cluster), Inline: true},
+			{Name: "Day (UTC)", Value: discordValue(alert.Day), Inline: true},
+			{Name: "Investigations", Value: fmt.Sprintf("%d", alert.Investigations), Inline: true},
+			{Name: "Estimated Spend", Value: fmt.Sprintf("$%.2f", alert.EstimatedSpend), Inline: true},
+			{Name: "Reason", Value: discordValue(alert.Reason)},
+		},
+	}
+
+	return d.send(DiscordMessage{Embeds: []DiscordEmbed{embed}})
+}
+
+// SendClusterDisconnectedAlert notifies Discord that a cluster's connection has been
+// down longer than the alert threshold
+func (d *DiscordNotifier) SendClusterDisconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	if d.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	embed := DiscordEmbed{
+		Title:       fmt.Sprintf("Cluster Disconnected: %s", alert.Cluster),
+		Description: "Faults from this cluster are not being received. Other clusters are unaffected. Nightcrier keeps reconnecting.",
+		Color:       discordColorDanger,
+		Fields: []DiscordEmbedField{
+			{Name: "Cluster", Value: discordValue(alert.Cluster), Inline: true},
+			{Name: "Status", Value: discordValue(alert.Status), Inline: true},
+			{Name: "Down Since", Value: alert.Since.UTC().Format(time.RFC3339), Inline: true},
+			{Name: "Down For", Value: alert.Duration.Round(time.Second).String(), Inline: true},
+			{Name: "Last Error", Value: discordValue(connectionErrorText(alert))},
+		},
+	}
+
+	return d.send(DiscordMessage{Embeds: []DiscordEmbed{embed}})
+}
+
+// SendClusterReconnectedAlert notifies Discord that a disconnected cluster recovered
+func (d *DiscordNotifier) SendClusterReconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	if d.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	embed := DiscordEmbed{
+		Title:       fmt.Sprintf("Cluster Reconnected: %s", alert.Cluster),
+		Description: "The cluster connection is active again. Faults raised while it was down may not have been received.",
+		Color:       discordColorGood,
+		Fields: []DiscordEmbedField{
+			{Name: "Cluster", Value: discordValue(alert.Cluster), Inline: true},
+			{Name: "Total Downtime", Value: alert.Duration.Round(time.Second).String(), Inline: true},
+		},
+	}
+
+	return d.send(DiscordMessage{Embeds: []DiscordEmbed{embed}})
+}
+
+// SendQueueAlert notifies Discord that an event queue is filling up or has dropped
+// events
+func (d *DiscordNotifier) SendQueueAlert(ctx context.Context, alert QueueAlert) error {
+	if d.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	color := discordColorWarning
+	if alert.Dropped > 0 {
+		color = discordColorDanger
+	}
+
+	embed := DiscordEmbed{
+		Title:       queueAlertTitle(alert),
+		Description: queueAlertText(alert),
+		Color:       color,
+		Fields: []DiscordEmbedField{
+			{Name: "Queue", Value: alert.QueueName(), Inline: true},
+			{Name: "Depth", Value: fmt.Sprintf("%d / %d (%d%%)", alert.Depth, alert.Capacity, alert.Utilization()), Inline: true},
+			{Name: "Dropped Events", Value: fmt.Sprintf("%d", alert.Dropped), Inline: true},
+			{Name: "Overflow Policy", Value: discordValue(alert.OverflowPolicy), Inline: true},
+		},
+	}
+
+	return d.send(DiscordMessage{Embeds: []DiscordEmbed{embed}})
+}
+
+// SendCanaryAlert notifies Discord of a failed, recovered, or passing canary run
+func (d *DiscordNotifier) SendCanaryAlert(ctx context.Context, alert CanaryAlert) error {
+	if d.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	color := discordColorDanger
+	if alert.Passed {
+		color = discordColorGood
+	}
+
+	fields := []DiscordEmbedField{
+		{Name: "Cluster", Value: discordValue(alert.Cluster), Inline: true},
+		{Name: "Duration", Value: alert.Duration.Round(time.Second).String(), Inline: true},
+	}
+	if !alert.Passed {
+		fields = append(fields, DiscordEmbedField{Name: "Failed Stage", Value: discordValue(alert.Stage), Inline: true})
+	}
+	if alert.IncidentID != "" {
+		fields = append(fields, DiscordEmbedField{Name: "Canary Run", Value: alert.IncidentID, Inline: true})
+	}
+
+	embed := DiscordEmbed{
+		Title:       canaryAlertTitle(alert),
+		Description: discordValue(canaryAlertText(alert)),
+		Color:       color,
+		Fields:      fields,
+	}
+
+	return d.send(DiscordMessage{Embeds: []DiscordEmbed{embed}})
+}
+
+// SendCredentialExpiryAlert notifies Discord that a cluster's triage credentials
+// are expiring or expired, or were renewed
+func (d *DiscordNotifier) SendCredentialExpiryAlert(ctx context.Context, alert CredentialExpiryAlert) error {
+	if d.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	color := discordColorWarning
+	switch alert.State {
+	case CredentialsExpired:
+		color = discordColorDanger
+	case CredentialsRenewed:
+		color = discordColorGood
+	}
+
+	embed := DiscordEmbed{
+		Title:       credentialAlertTitle(alert),
+		Description: discordValue(credentialAlertText(alert)),
+		Color:       color,
+		Fields: []DiscordEmbedField{
+			{Name: "Cluster", Value: discordValue(alert.Cluster), Inline: true},
+			{Name: "Credential", Value: discordValue(fmt.Sprintf("%s (%s)", alert.User, alert.Kind)), Inline: true},
+			{Name: "Expires", Value: alert.expiresText(), Inline: true},
+		},
+	}
+
+	return d.send(DiscordMessage{Embeds: []DiscordEmbed{embed}})
+}
+
+// SendDigest sends several related notifications to Discord as one embed with a
+// field per kind of notification
+func (d *DiscordNotifier) SendDigest(ctx context.Context, digest Digest) error {
+	if d.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	fields := make([]DiscordEmbedField, 0, len(digest.Sections))
+	for _, section := range digest.Sections {
+		fields = append(fields, DiscordEmbedField{Name: section.Title, Value: discordValue(section.Text("• "))})
+	}
+
+	embed := DiscordEmbed{
+		Title:  digest.Title(),
+		Color:  discordColorWarning,
+		Fields: fields,
+		Footer: &DiscordEmbedFooter{Text: digest.Footer()},
+	}
+
+	return d.send(DiscordMessage{Embeds: []DiscordEmbed{embed}})
+}
+
+// send sends a message to the Discord webhook
+func (d *DiscordNotifier) send(msg DiscordMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal discord message: %w", err)
+	}
+
+	resp, err := d.httpClient.Post(d.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to send discord notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Discord answers 204 No Content, or 200 when ?wait=true is set
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("discord webhook returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// discordValue makes a field value acceptable to Discord, which rejects empty
+// values and values over 1024 characters.
+func discordValue(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+	if runes := []rune(value); len(runes) > discordFieldValueLimit {
+		return string(runes[:discordFieldValueLimit-3]) + "..."
+	}
+	return value
+}
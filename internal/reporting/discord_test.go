@@ -0,0 +1,144 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscordNotifier_SendIncidentNotification(t *testing.T) {
+	var received DiscordMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode discord payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewDiscordNotifier(server.URL, defaultTestTuning())
+	summary := &IncidentSummary{
+		IncidentID: "incident-123",
+		Cluster:    "prod",
+		Namespace:  "",
+		Resource:   "Pod/web",
+		Reason:     "CrashLoopBackOff",
+		Status:     "failed",
+		RootCause:  strings.Repeat("x", 2000),
+		Confidence: "LOW",
+		Duration:   5 * time.Minute,
+		ReportURL:  "https://example.com/report",
+	}
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+
+	if len(received.Embeds) != 1 {
+		t.Fatalf("expected 1 embed, got %d", len(received.Embeds))
+	}
+	embed := received.Embeds[0]
+	if embed.Color != discordColorDanger || embed.URL != "https://example.com/report" {
+		t.Errorf("embed = %+v", embed)
+	}
+	if embed.Fields[1].Value != "-" {
+		t.Errorf("empty namespace value = %q, want placeholder", embed.Fields[1].Value)
+	}
+	if embed.Fields[4].Name != "Root Cause (LOW confidence)" || len(embed.Fields[4].Value) != discordFieldValueLimit {
+		t.Errorf("root cause field = %q (%d chars)", embed.Fields[4].Name, len(embed.Fields[4].Value))
+	}
+	if embed.Footer == nil || embed.Footer.Text != "Incident ID: incident-123 | Duration: 5m0s" {
+		t.Errorf("footer = %+v", embed.Footer)
+	}
+}
+
+func TestDiscordNotifier_AwaitingReview(t *testing.T) {
+	var received DiscordMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode discord payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewDiscordNotifier(server.URL, defaultTestTuning())
+	summary := &IncidentSummary{
+		IncidentID: "incident-123",
+		Cluster:    "prod",
+		Resource:   "Pod/web",
+		Reason:     "CrashLoopBackOff",
+		Status:     "awaiting_review",
+		RootCause:  "Readiness probe points at the wrong port",
+		Confidence: "LOW",
+		Duration:   5 * time.Minute,
+	}
+	if !summary.Investigated() || !summary.AwaitingReview() {
+		t.Fatalf("Investigated() = %v, AwaitingReview() = %v, want both", summary.Investigated(), summary.AwaitingReview())
+	}
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+
+	embed := received.Embeds[0]
+	if embed.Color != discordColorWarning || !strings.HasSuffix(embed.Title, "👀") {
+		t.Errorf("embed = %q (color %x), want the review marker", embed.Title, embed.Color)
+	}
+	if embed.Footer == nil || !strings.HasSuffix(embed.Footer.Text, "| Status: awaiting_review") {
+		t.Errorf("footer = %+v, want the status", embed.Footer)
+	}
+}
+
+func TestDiscordNotifier_Alerts(t *testing.T) {
+	var received []DiscordMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg DiscordMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		received = append(received, msg)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewDiscordNotifier(server.URL, defaultTestTuning())
+	stats := FailureStats{Count: 4, Duration: 2 * time.Minute, RecentReasons: []string{"a", "b", "c", "d"}}
+	ctx := context.Background()
+	if err := notifier.SendSystemDegradedAlert(ctx, stats); err != nil {
+		t.Fatalf("SendSystemDegradedAlert() error = %v", err)
+	}
+	if err := notifier.SendSystemRecoveredAlert(ctx, stats); err != nil {
+		t.Fatalf("SendSystemRecoveredAlert() error = %v", err)
+	}
+	if err := notifier.SendBudgetExhaustedAlert(ctx, BudgetAlert{Cluster: "prod", Reason: "limit"}); err != nil {
+		t.Fatalf("SendBudgetExhaustedAlert() error = %v", err)
+	}
+
+	if len(received) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(received))
+	}
+	degraded := received[0].Embeds[0]
+	if degraded.Title != "AI Agent System Degraded" || degraded.Fields[2].Value != "• b\n• c\n• d" {
+		t.Errorf("degraded embed = %+v", degraded)
+	}
+	if received[1].Embeds[0].Title != "AI Agent System Recovered" {
+		t.Errorf("recovered title = %q", received[1].Embeds[0].Title)
+	}
+	if received[2].Embeds[0].Title != "Investigation Budget Exhausted" {
+		t.Errorf("budget title = %q", received[2].Embeds[0].Title)
+	}
+}
+
+func TestDiscordNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message": "Invalid Form Body"}`))
+	}))
+	defer server.Close()
+
+	err := NewDiscordNotifier(server.URL, defaultTestTuning()).SendIncidentNotification(&IncidentSummary{Status: "resolved"})
+	if err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("expected status 400 error, got %v", err)
+	}
+}
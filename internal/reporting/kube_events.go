@@ -0,0 +1,322 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/incluster"
+)
+
+// kubeEventComponent is the reporting component of nightcrier's Kubernetes Events
+const kubeEventComponent = "nightcrier"
+
+// NightcrierResource is the custom resource whose status conditions the
+// KubeEventNotifier maintains.
+var NightcrierResource = incluster.Resource{Group: "nightcrier.io", Version: "v1alpha1", Resource: "nightcriers"}
+
+// Kubernetes Event reasons
+const (
+	KubeReasonInvestigationCompleted = "InvestigationCompleted"
+	KubeReasonInvestigationFailed    = "InvestigationFailed"
+	KubeReasonFaultRecorded          = "FaultRecorded"
+	KubeReasonCircuitBreakerOpen     = "CircuitBreakerOpen"
+	KubeReasonCircuitBreakerClosed   = "CircuitBreakerClosed"
+	KubeReasonClusterConnectionLost  = "ClusterConnectionLost"
+	KubeReasonClusterReconnected     = "ClusterConnectionRestored"
+	KubeReasonBudgetExhausted        = "BudgetExhausted"
+	KubeReasonEventsDropped          = "EventsDropped"
+	KubeReasonQueueBacklog           = "QueueBacklog"
+	KubeReasonCanaryFailed           = "CanaryFailed"
+	KubeReasonCanaryPassed           = "CanaryPassed"
+	KubeReasonCredentialsExpiring    = "ClusterCredentialsExpiring"
+	KubeReasonCredentialsRenewed     = "ClusterCredentialsRenewed"
+	KubeReasonNotificationDigest     = "NotificationDigest"
+)
+
+// Condition types maintained on the Nightcrier custom resource
+const (
+	// ConditionAgentHealthy is False while the agent-failure circuit breaker is open
+	ConditionAgentHealthy = "AgentHealthy"
+	// ConditionClustersConnected is False while any cluster connection is down
+	// beyond the disconnect alert threshold
+	ConditionClustersConnected = "ClustersConnected"
+)
+
+// kubeEventsClient is the part of the in-cluster API client the notifier uses.
+type kubeEventsClient interface {
+	CreateEvent(ctx context.Context, namespace string, event incluster.Event) error
+	PatchStatusConditions(ctx context.Context, resource incluster.Resource, namespace, name string, conditions []incluster.Condition) error
+}
+
+// KubeEventNotifier records notifications as Kubernetes Events on the nightcrier
+// pod, so triage activity is visible with `kubectl describe pod` and
+// `kubectl get events`. When a Nightcrier custom resource is named, its
+// AgentHealthy and ClustersConnected status conditions follow the circuit breaker
+// and cluster connection alerts; a resource that does not exist is skipped.
+type KubeEventNotifier struct {
+	client       kubeEventsClient
+	namespace    string
+	podName      string
+	resourceName string
+
+	mu sync.Mutex
+	// conditions holds the current status conditions by type
+	conditions map[string]incluster.Condition
+	// downClusters maps each disconnected cluster to its alert
+	downClusters map[string]ClusterConnectionAlert
+	// resourceMissing is set once the custom resource was not found, so the
+	// absence is logged once
+	resourceMissing bool
+	now             func() time.Time
+}
+
+// NewKubeEventNotifier creates a notifier that records events on pod podName in
+// namespace and, when resourceName is set, updates the status conditions of that
+// Nightcrier resource in the same namespace.
+func NewKubeEventNotifier(client *incluster.Client, namespace, podName, resourceName string) *KubeEventNotifier {
+	return newKubeEventNotifier(client, namespace, podName, resourceName)
+}
+
+func newKubeEventNotifier(client kubeEventsClient, namespace, podName, resourceName string) *KubeEventNotifier {
+	return &KubeEventNotifier{
+		client:       client,
+		namespace:    namespace,
+		podName:      podName,
+		resourceName: resourceName,
+		conditions:   make(map[string]incluster.Condition),
+		downClusters: make(map[string]ClusterConnectionAlert),
+		now:          time.Now,
+	}
+}
+
+// Name implements Notifier.
+func (k *KubeEventNotifier) Name() string { return "kubernetes-events" }
+
+// SendIncidentNotification implements Notifier. Completed investigations are
+// recorded as Normal events naming the resource and root cause; failed ones as
+// Warning events.
+func (k *KubeEventNotifier) SendIncidentNotification(summary *IncidentSummary) error {
+	target := kubeEventTarget(summary)
+	switch {
+	case summary.RecordedOnly:
+		return k.record(context.Background(), incluster.EventTypeNormal, KubeReasonFaultRecorded,
+			fmt.Sprintf("Fault %s recorded without investigation for %s: %s", summary.IncidentID, target, summary.FaultContext))
+	case summary.Investigated():
+		message := fmt.Sprintf("Investigation %s completed for %s", summary.IncidentID, target)
+		if summary.AwaitingReview() {
+			message = fmt.Sprintf("Investigation %s completed for %s, %s", summary.IncidentID, target, summary.Status)
+		}
+		if summary.Confidence != "" {
+			message += fmt.Sprintf(" (%s confidence)", summary.Confidence)
+		}
+		if summary.RootCause != "" {
+			message += ": " + summary.RootCause
+		}
+		if summary.ReportURL != "" {
+			message += " Report: " + summary.ReportURL
+		}
+		return k.record(context.Background(), incluster.EventTypeNormal, KubeReasonInvestigationCompleted, message)
+	}
+	return k.record(context.Background(), incluster.EventTypeWarning, KubeReasonInvestigationFailed,
+		fmt.Sprintf("Investigation %s for %s ended with status %s", summary.IncidentID, target, summary.Status))
+}
+
+// SendSystemDegradedAlert implements Notifier.
+func (k *KubeEventNotifier) SendSystemDegradedAlert(ctx context.Context, stats FailureStats) error {
+	message := fmt.Sprintf("Circuit breaker open after %d consecutive agent failures", stats.Count)
+	if stats.SuspectedOutage != "" {
+		message = "Circuit breaker open: provider outage suspected (" + stats.SuspectedOutage + ")"
+	}
+	if reasons := recentFailureReasons(stats, 1); len(reasons) > 0 {
+		message += "; last failure: " + reasons[0]
+	}
+	k.setCondition(ctx, ConditionAgentHealthy, "False", KubeReasonCircuitBreakerOpen, message)
+	return k.record(ctx, incluster.EventTypeWarning, KubeReasonCircuitBreakerOpen, message)
+}
+
+// SendSystemRecoveredAlert implements Notifier.
+func (k *KubeEventNotifier) SendSystemRecoveredAlert(ctx context.Context, stats FailureStats) error {
+	message := fmt.Sprintf("Circuit breaker closed: agent investigations are succeeding again after %d failures over %s",
+		stats.Count, stats.Duration.Round(time.Second))
+	k.setCondition(ctx, ConditionAgentHealthy, "True", KubeReasonCircuitBreakerClosed, message)
+	return k.record(ctx, incluster.EventTypeNormal, KubeReasonCircuitBreakerClosed, message)
+}
+
+// SendBudgetExhaustedAlert implements Notifier.
+func (k *KubeEventNotifier) SendBudgetExhaustedAlert(ctx context.Context, alert BudgetAlert) error {
+	return k.record(ctx, incluster.EventTypeWarning, KubeReasonBudgetExhausted,
+		fmt.Sprintf("Investigation budget for cluster %s exhausted for %s (%s); investigations are skipped", alert.Cluster, alert.Day, alert.Reason))
+}
+
+// SendClusterDisconnectedAlert implements Notifier.
+func (k *KubeEventNotifier) SendClusterDisconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	k.mu.Lock()
+	k.downClusters[alert.Cluster] = alert
+	k.mu.Unlock()
+	k.updateClustersCondition(ctx)
+
+	return k.record(ctx, incluster.EventTypeWarning, KubeReasonClusterConnectionLost,
+		fmt.Sprintf("Connection to cluster %s lost for %s (status %s, last error: %s)",
+			alert.Cluster, alert.Duration.Round(time.Second), alert.Status, connectionErrorText(alert)))
+}
+
+// SendClusterReconnectedAlert implements Notifier.
+func (k *KubeEventNotifier) SendClusterReconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	k.mu.Lock()
+	delete(k.downClusters, alert.Cluster)
+	k.mu.Unlock()
+	k.updateClustersCondition(ctx)
+
+	return k.record(ctx, incluster.EventTypeNormal, KubeReasonClusterReconnected,
+		fmt.Sprintf("Connection to cluster %s restored after %s", alert.Cluster, alert.Duration.Round(time.Second)))
+}
+
+// SendQueueAlert implements Notifier.
+func (k *KubeEventNotifier) SendQueueAlert(ctx context.Context, alert QueueAlert) error {
+	if alert.Dropped > 0 {
+		return k.record(ctx, incluster.EventTypeWarning, KubeReasonEventsDropped,
+			fmt.Sprintf("Event queue %s dropped %d fault events (policy %s)", alert.QueueName(), alert.Dropped, alert.OverflowPolicy))
+	}
+	return k.record(ctx, incluster.EventTypeWarning, KubeReasonQueueBacklog,
+		fmt.Sprintf("Event queue %s is at %d/%d (threshold %d%%)", alert.QueueName(), alert.Depth, alert.Capacity, alert.Threshold))
+}
+
+// SendCanaryAlert implements Notifier.
+func (k *KubeEventNotifier) SendCanaryAlert(ctx context.Context, alert CanaryAlert) error {
+	if alert.Passed {
+		return k.record(ctx, incluster.EventTypeNormal, KubeReasonCanaryPassed,
+			fmt.Sprintf("Canary investigation %s on cluster %s passed in %s", alert.IncidentID, alert.Cluster, alert.Duration.Round(time.Second)))
+	}
+	return k.record(ctx, incluster.EventTypeWarning, KubeReasonCanaryFailed,
+		fmt.Sprintf("Canary investigation %s on cluster %s failed at stage %s: %s", alert.IncidentID, alert.Cluster, alert.Stage, alert.Error))
+}
+
+// SendCredentialExpiryAlert implements Notifier.
+func (k *KubeEventNotifier) SendCredentialExpiryAlert(ctx context.Context, alert CredentialExpiryAlert) error {
+	if alert.State == CredentialsRenewed {
+		return k.record(ctx, incluster.EventTypeNormal, KubeReasonCredentialsRenewed,
+			fmt.Sprintf("Triage credentials of cluster %s renewed", alert.Cluster))
+	}
+	return k.record(ctx, incluster.EventTypeWarning, KubeReasonCredentialsExpiring,
+		fmt.Sprintf("Triage credentials of cluster %s %s: %s of user %s expires at %s",
+			alert.Cluster, alert.State, alert.Kind, alert.User, alert.expiresText()))
+}
+
+// SendDigest implements Notifier.
+func (k *KubeEventNotifier) SendDigest(ctx context.Context, digest Digest) error {
+	var parts []string
+	for _, section := range digest.Sections {
+		parts = append(parts, fmt.Sprintf("%s: %s", section.Title, strings.Join(section.Items, "; ")))
+	}
+	return k.record(ctx, incluster.EventTypeNormal, KubeReasonNotificationDigest,
+		digest.Title()+". "+strings.Join(parts, " | "))
+}
+
+// record creates an event on the nightcrier pod.
+func (k *KubeEventNotifier) record(ctx context.Context, eventType, reason, message string) error {
+	err := k.client.CreateEvent(ctx, k.namespace, incluster.Event{
+		InvolvedObject: incluster.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Namespace:  k.namespace,
+			Name:       k.podName,
+		},
+		Type:      eventType,
+		Reason:    reason,
+		Message:   message,
+		Component: kubeEventComponent,
+		Instance:  k.podName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record %s event: %w", reason, err)
+	}
+	return nil
+}
+
+// updateClustersCondition sets ClustersConnected from the disconnected clusters.
+func (k *KubeEventNotifier) updateClustersCondition(ctx context.Context) {
+	k.mu.Lock()
+	down := make([]string, 0, len(k.downClusters))
+	for name := range k.downClusters {
+		down = append(down, name)
+	}
+	k.mu.Unlock()
+	sort.Strings(down)
+
+	if len(down) == 0 {
+		k.setCondition(ctx, ConditionClustersConnected, "True", "AllConnected", "All cluster connections are active")
+		return
+	}
+	k.setCondition(ctx, ConditionClustersConnected, "False", KubeReasonClusterConnectionLost,
+		"Disconnected clusters: "+strings.Join(down, ", "))
+}
+
+// setCondition updates a status condition of the Nightcrier resource, when one is
+// configured. Failures are logged rather than returned: the event carries the same
+// information.
+func (k *KubeEventNotifier) setCondition(ctx context.Context, conditionType, status, reason, message string) {
+	if k.resourceName == "" {
+		return
+	}
+
+	k.mu.Lock()
+	if k.resourceMissing {
+		k.mu.Unlock()
+		return
+	}
+	transition := k.now().UTC().Format(time.RFC3339)
+	if prev, ok := k.conditions[conditionType]; ok && prev.Status == status {
+		transition = prev.LastTransitionTime
+	}
+	k.conditions[conditionType] = incluster.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: transition,
+	}
+	conditions := make([]incluster.Condition, 0, len(k.conditions))
+	for _, c := range k.conditions {
+		conditions = append(conditions, c)
+	}
+	k.mu.Unlock()
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].Type < conditions[j].Type })
+
+	err := k.client.PatchStatusConditions(ctx, NightcrierResource, k.namespace, k.resourceName, conditions)
+	switch {
+	case errors.Is(err, incluster.ErrNotFound):
+		k.mu.Lock()
+		k.resourceMissing = true
+		k.mu.Unlock()
+		slog.Info("nightcrier resource not found, skipping status conditions",
+			"namespace", k.namespace,
+			"resource", k.resourceName)
+	case err != nil:
+		slog.Warn("failed to update nightcrier resource conditions",
+			"resource", k.resourceName,
+			"condition", conditionType,
+			"error", err)
+	}
+}
+
+// kubeEventTarget describes the resource an incident is about, e.g.
+// "Pod/web-7d9f in namespace default on cluster prod".
+func kubeEventTarget(summary *IncidentSummary) string {
+	target := summary.Resource
+	if target == "" {
+		target = "unknown resource"
+	}
+	if summary.Namespace != "" {
+		target += " in namespace " + summary.Namespace
+	}
+	if summary.Cluster != "" {
+		target += " on cluster " + summary.Cluster
+	}
+	return target
+}
@@ -0,0 +1,110 @@
+package reporting
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/incluster"
+)
+
+// fakeKubeClient records the events and condition patches it receives.
+type fakeKubeClient struct {
+	events     []incluster.Event
+	patches    [][]incluster.Condition
+	missingCRD bool
+}
+
+func (f *fakeKubeClient) CreateEvent(ctx context.Context, namespace string, event incluster.Event) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeKubeClient) PatchStatusConditions(ctx context.Context, resource incluster.Resource, namespace, name string, conditions []incluster.Condition) error {
+	if f.missingCRD {
+		return incluster.ErrNotFound
+	}
+	f.patches = append(f.patches, conditions)
+	return nil
+}
+
+func TestKubeEventNotifierInvestigationEvents(t *testing.T) {
+	client := &fakeKubeClient{}
+	k := newKubeEventNotifier(client, "monitoring", "nightcrier-0", "")
+
+	_ = k.SendIncidentNotification(&IncidentSummary{
+		IncidentID: "NC-0042",
+		Cluster:    "prod",
+		Namespace:  "default",
+		Resource:   "Pod/web-7d9f",
+		Status:     "resolved",
+		RootCause:  "Image tag does not exist",
+		Confidence: "HIGH",
+	})
+	_ = k.SendIncidentNotification(&IncidentSummary{IncidentID: "NC-0043", Resource: "Pod/api-0", Status: "agent_failed"})
+
+	if len(client.events) != 2 {
+		t.Fatalf("got %d events, want 2", len(client.events))
+	}
+	completed := client.events[0]
+	if completed.Reason != KubeReasonInvestigationCompleted || completed.Type != incluster.EventTypeNormal {
+		t.Errorf("completed event = %+v", completed)
+	}
+	want := "Investigation NC-0042 completed for Pod/web-7d9f in namespace default on cluster prod (HIGH confidence): Image tag does not exist"
+	if completed.Message != want {
+		t.Errorf("message = %q, want %q", completed.Message, want)
+	}
+	if completed.InvolvedObject.Kind != "Pod" || completed.InvolvedObject.Name != "nightcrier-0" || completed.InvolvedObject.Namespace != "monitoring" {
+		t.Errorf("involved object = %+v, want the nightcrier pod", completed.InvolvedObject)
+	}
+	if failed := client.events[1]; failed.Reason != KubeReasonInvestigationFailed || failed.Type != incluster.EventTypeWarning {
+		t.Errorf("failed event = %+v", failed)
+	}
+	if len(client.patches) != 0 {
+		t.Errorf("conditions patched without a resource name: %v", client.patches)
+	}
+}
+
+func TestKubeEventNotifierConditions(t *testing.T) {
+	client := &fakeKubeClient{}
+	k := newKubeEventNotifier(client, "monitoring", "nightcrier-0", "nightcrier")
+	clock := time.Date(2024, 6, 13, 12, 0, 0, 0, time.UTC)
+	k.now = func() time.Time { return clock }
+
+	_ = k.SendClusterDisconnectedAlert(context.Background(), ClusterConnectionAlert{Cluster: "prod", Status: "failed", Duration: 5 * time.Minute})
+	_ = k.SendSystemDegradedAlert(context.Background(), FailureStats{Count: 3, RecentReasons: []string{"timeout"}})
+	clock = clock.Add(time.Hour)
+	_ = k.SendClusterReconnectedAlert(context.Background(), ClusterConnectionAlert{Cluster: "prod", Duration: time.Hour})
+
+	if got := client.events[0]; got.Reason != KubeReasonClusterConnectionLost || !strings.Contains(got.Message, "cluster prod lost") {
+		t.Errorf("disconnect event = %+v", got)
+	}
+	if got := client.events[1]; got.Reason != KubeReasonCircuitBreakerOpen || !strings.Contains(got.Message, "last failure: timeout") {
+		t.Errorf("circuit breaker event = %+v", got)
+	}
+
+	last := client.patches[len(client.patches)-1]
+	if len(last) != 2 {
+		t.Fatalf("conditions = %+v, want AgentHealthy and ClustersConnected", last)
+	}
+	agent, clusters := last[0], last[1]
+	if agent.Type != ConditionAgentHealthy || agent.Status != "False" || agent.LastTransitionTime != "2024-06-13T12:00:00Z" {
+		t.Errorf("AgentHealthy = %+v", agent)
+	}
+	if clusters.Type != ConditionClustersConnected || clusters.Status != "True" || clusters.LastTransitionTime != "2024-06-13T13:00:00Z" {
+		t.Errorf("ClustersConnected = %+v", clusters)
+	}
+}
+
+func TestKubeEventNotifierSkipsMissingResource(t *testing.T) {
+	client := &fakeKubeClient{missingCRD: true}
+	k := newKubeEventNotifier(client, "monitoring", "nightcrier-0", "nightcrier")
+
+	if err := k.SendSystemDegradedAlert(context.Background(), FailureStats{Count: 3}); err != nil {
+		t.Fatalf("SendSystemDegradedAlert() error = %v", err)
+	}
+	if !k.resourceMissing || len(client.events) != 1 {
+		t.Errorf("resourceMissing = %v, events = %d; want the event recorded and the resource skipped", k.resourceMissing, len(client.events))
+	}
+}
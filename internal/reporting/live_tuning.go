@@ -0,0 +1,41 @@
+package reporting
+
+import "github.com/rbias/nightcrier/internal/config"
+
+// tuningFollower lets a component follow runtime tuning changes. Components copy
+// their tuning values when they are created; once SetTuningStore is called, the
+// values in effect in the store are used instead.
+type tuningFollower struct {
+	liveTuning *config.TuningStore
+}
+
+// SetTuningStore makes the component read its reporting tuning (root cause
+// truncation, failure reasons shown and tracked) from store, so changes made at
+// runtime apply to the next notification. Call before use.
+func (f *tuningFollower) SetTuningStore(store *config.TuningStore) {
+	f.liveTuning = store
+}
+
+// truncationLength returns the root cause truncation length in effect.
+func (f *tuningFollower) truncationLength(configured int) int {
+	if f.liveTuning == nil {
+		return configured
+	}
+	return f.liveTuning.Current().Reporting.RootCauseTruncationLength
+}
+
+// displayCount returns the number of failure reasons shown in alerts.
+func (f *tuningFollower) displayCount(configured int) int {
+	if f.liveTuning == nil {
+		return configured
+	}
+	return f.liveTuning.Current().Reporting.FailureReasonsDisplayCount
+}
+
+// reasonsTracked returns the number of failure reasons kept.
+func (f *tuningFollower) reasonsTracked(configured int) int {
+	if f.liveTuning == nil {
+		return configured
+	}
+	return f.liveTuning.Current().Reporting.MaxFailureReasonsTracked
+}
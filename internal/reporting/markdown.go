@@ -1,28 +1,124 @@
 package reporting
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
 	"github.com/gomarkdown/markdown/html"
 	"github.com/gomarkdown/markdown/parser"
+	"github.com/rbias/nightcrier/internal/config"
+	xhtml "golang.org/x/net/html"
 )
 
-// ConvertMarkdownToHTML converts markdown content to a styled HTML page.
-// This is used to transform investigation.md into a human-readable HTML report.
-func ConvertMarkdownToHTML(markdownContent []byte, incidentID string) []byte {
-	// Create markdown parser with extensions
+// MarkdownRenderer converts agent-produced markdown reports to HTML fragments.
+// Renderers do not need to sanitize their output; ConvertMarkdownToHTML and
+// ConvertMarkdownToXHTML sanitize whatever a renderer produces.
+type MarkdownRenderer interface {
+	// Render converts a markdown document to an HTML fragment. With xhtml set the
+	// fragment must be well-formed XHTML (e.g. for Confluence storage format).
+	Render(markdown []byte, xhtml bool) []byte
+}
+
+// NewMarkdownRenderer returns the renderer selected by report_rendering.renderer.
+func NewMarkdownRenderer(cfg config.ReportRenderingConfig) MarkdownRenderer {
+	if cfg.Renderer == config.ReportRendererBasic {
+		return basicRenderer{}
+	}
+	return gfmRenderer{}
+}
+
+// DefaultMarkdownRenderer is the renderer used when none is configured.
+var DefaultMarkdownRenderer MarkdownRenderer = gfmRenderer{}
+
+// basicRenderer renders the markdown dialect of earlier releases.
+type basicRenderer struct{}
+
+func (basicRenderer) Render(markdownContent []byte, xhtml bool) []byte {
 	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.Strikethrough
+	return renderMarkdown(markdownContent, extensions, xhtml, nil)
+}
+
+// gfmRenderer renders GitHub Flavored Markdown: tables, task lists,
+// strikethrough, autolinks, and blocks that start without a preceding blank line.
+type gfmRenderer struct{}
+
+func (gfmRenderer) Render(markdownContent []byte, xhtml bool) []byte {
+	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.Strikethrough | parser.NoEmptyLineBeforeBlock
+	return renderMarkdown(markdownContent, extensions, xhtml, renderTaskListItem)
+}
+
+// renderMarkdown renders markdown with gomarkdown.
+func renderMarkdown(markdownContent []byte, extensions parser.Extensions, xhtml bool, hook html.RenderNodeFunc) []byte {
 	p := parser.NewWithExtensions(extensions)
 	doc := p.Parse(markdownContent)
 
-	// Create HTML renderer with options
 	htmlFlags := html.CommonFlags | html.HrefTargetBlank
-	opts := html.RendererOptions{Flags: htmlFlags}
-	renderer := html.NewRenderer(opts)
+	if xhtml {
+		htmlFlags = html.CommonFlags | html.UseXHTML
+	}
+	renderer := html.NewRenderer(html.RendererOptions{Flags: htmlFlags, RenderNodeHook: hook})
+	return markdown.Render(doc, renderer)
+}
+
+// renderTaskListItem renders the "[ ] " or "[x] " that starts a list item as a
+// disabled checkbox.
+func renderTaskListItem(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+	text, ok := node.(*ast.Text)
+	if !ok || !entering || !isTaskListMarker(text) {
+		return ast.GoToNext, false
+	}
+	if text.Literal[1] == ' ' {
+		io.WriteString(w, `<input type="checkbox" disabled="disabled" /> `)
+	} else {
+		io.WriteString(w, `<input type="checkbox" checked="checked" disabled="disabled" /> `)
+	}
+	html.EscapeHTML(w, bytes.TrimLeft(text.Literal[3:], " "))
+	return ast.GoToNext, true
+}
+
+// isTaskListMarker reports whether text starts a list item with a task marker.
+func isTaskListMarker(text *ast.Text) bool {
+	para, ok := text.GetParent().(*ast.Paragraph)
+	if !ok || ast.GetFirstChild(para) != ast.Node(text) {
+		return false
+	}
+	item, ok := para.GetParent().(*ast.ListItem)
+	if !ok || ast.GetFirstChild(item) != ast.Node(para) {
+		return false
+	}
+	lit := text.Literal
+	if len(lit) < 3 || lit[0] != '[' || lit[2] != ']' || (len(lit) > 3 && lit[3] != ' ') {
+		return false
+	}
+	return lit[1] == ' ' || lit[1] == 'x' || lit[1] == 'X'
+}
 
-	// Convert markdown to HTML
-	htmlContent := markdown.Render(doc, renderer)
+// ConvertMarkdownToHTML converts markdown content to a styled HTML page with the
+// default renderer.
+// This is used to transform investigation.md into a human-readable HTML report.
+func ConvertMarkdownToHTML(markdownContent []byte, incidentID string) []byte {
+	return RenderMarkdownPage(DefaultMarkdownRenderer, markdownContent, incidentID)
+}
+
+// RenderMarkdownPage converts markdown content to a styled HTML page with the
+// given renderer, or the default renderer when r is nil. The rendered report is
+// sanitized (see SanitizeHTML) before it is placed in the page.
+func RenderMarkdownPage(r MarkdownRenderer, markdownContent []byte, incidentID string) []byte {
+	return RenderReportPage(r, markdownContent, incidentID, nil)
+}
+
+// RenderReportPage is RenderMarkdownPage with the resource topology diagram
+// embedded below the report. The diagram is placed in the page as is, so it must
+// come from topology.RenderSVG, which escapes every label; nil omits the section.
+func RenderReportPage(r MarkdownRenderer, markdownContent []byte, incidentID string, topologySVG []byte) []byte {
+	if r == nil {
+		r = DefaultMarkdownRenderer
+	}
+	htmlContent := SanitizeHTML(r.Render(markdownContent, false))
+	incidentID = xhtml.EscapeString(incidentID)
 
 	// Wrap in full HTML document with styling
 	fullHTML := fmt.Sprintf(`<!DOCTYPE html>
@@ -143,6 +239,10 @@ func ConvertMarkdownToHTML(markdownContent []byte, incidentID string) []byte {
         a:hover {
             text-decoration: underline;
         }
+        .topology {
+            overflow-x: auto;
+            margin: 20px 0;
+        }
         .footer {
             margin-top: 40px;
             padding-top: 20px;
@@ -160,12 +260,37 @@ func ConvertMarkdownToHTML(markdownContent []byte, incidentID string) []byte {
             <h1>🔍 Kubernetes Incident Investigation</h1>
         </div>
         %s
+        %s
         <div class="footer">
             Generated by Nightcrier
         </div>
     </div>
 </body>
-</html>`, incidentID, incidentID, string(htmlContent))
+</html>`, incidentID, incidentID, string(htmlContent), topologySection(topologySVG))
 
 	return []byte(fullHTML)
 }
+
+// topologySection returns the report section holding the topology diagram, or
+// nothing without one.
+func topologySection(svg []byte) string {
+	if len(svg) == 0 {
+		return ""
+	}
+	return "<h2>Resource Topology</h2>\n        <div class=\"topology\">\n" + string(svg) + "        </div>"
+}
+
+// ConvertMarkdownToXHTML converts markdown content to an XHTML fragment without the
+// page wrapper, for embedding the report in other systems (e.g. Confluence storage format).
+func ConvertMarkdownToXHTML(markdownContent []byte) []byte {
+	return RenderMarkdownXHTML(DefaultMarkdownRenderer, markdownContent)
+}
+
+// RenderMarkdownXHTML converts markdown content to a sanitized XHTML fragment with
+// the given renderer, or the default renderer when r is nil.
+func RenderMarkdownXHTML(r MarkdownRenderer, markdownContent []byte) []byte {
+	if r == nil {
+		r = DefaultMarkdownRenderer
+	}
+	return SanitizeHTML(r.Render(markdownContent, true))
+}
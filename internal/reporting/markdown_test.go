@@ -0,0 +1,137 @@
+package reporting
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rbias/nightcrier/internal/config"
+)
+
+func TestGFMRenderer(t *testing.T) {
+	report := []byte(`## Remediation
+Steps:
+- [x] Raised the memory limit
+- [ ] Add an alert on restarts
+
+| Pod | Restarts |
+|-----|---------:|
+| web | 12 |
+
+~~not the cause~~
+`)
+	out := string(RenderMarkdownPage(NewMarkdownRenderer(config.ReportRenderingConfig{Renderer: "gfm"}), report, "NC-2024-0613-prod-0001"))
+
+	for _, want := range []string{
+		`<h2 id="remediation">Remediation</h2>`,
+		`<li><input type="checkbox" checked="checked" disabled="disabled"/> Raised the memory limit</li>`,
+		`<li><input type="checkbox" disabled="disabled"/> Add an alert on restarts</li>`,
+		`<th align="right">Restarts</th>`,
+		`<td>web</td>`,
+		`<del>not the cause</del>`,
+		`Incident ID: NC-2024-0613-prod-0001`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered report missing %q:\n%s", want, out)
+		}
+	}
+
+	// The basic renderer leaves task markers as text
+	basic := string(RenderMarkdownXHTML(NewMarkdownRenderer(config.ReportRenderingConfig{Renderer: "basic"}), report))
+	if strings.Contains(basic, "<input") || !strings.Contains(basic, "[x] Raised") {
+		t.Errorf("basic renderer should not render task lists:\n%s", basic)
+	}
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		notWant []string
+	}{
+		{
+			name:    "script removed with its content",
+			input:   `<p>before</p><script>alert(document.cookie)</script><p>after</p>`,
+			want:    `<p>before</p><p>after</p>`,
+			notWant: []string{"alert"},
+		},
+		{
+			name:    "event handlers and styles stripped",
+			input:   `<p onclick="steal()" style="display:none">text</p><img src="x" onerror="steal()"/>`,
+			want:    `<p>text</p><img src="x"/>`,
+			notWant: []string{"steal", "style"},
+		},
+		{
+			name:    "javascript links stripped",
+			input:   `<a href="java&#09;script:alert(1)" target="_blank">click</a><a href="https://example.com">ok</a>`,
+			want:    `<a target="_blank" rel="noopener noreferrer">click</a><a href="https://example.com">ok</a>`,
+			notWant: []string{"javascript"},
+		},
+		{
+			name:    "data images stripped",
+			input:   `<img src="data:image/svg+xml;base64,PHN2Zz4=" alt="x"/>`,
+			want:    `<img alt="x"/>`,
+			notWant: []string{"data:"},
+		},
+		{
+			name:    "unknown elements unwrapped",
+			input:   `<p><font color="red">warn <b>now</b></font></p>`,
+			want:    `<p>warn <b>now</b></p>`,
+			notWant: []string{"font", "color"},
+		},
+		{
+			name:    "svg and iframes removed",
+			input:   `<svg onload="steal()"><script>x()</script></svg><iframe src="https://evil"></iframe><p>ok</p>`,
+			want:    `<p>ok</p>`,
+			notWant: []string{"svg", "iframe", "steal"},
+		},
+		{
+			name:  "code language classes kept",
+			input: `<pre><code class="language-yaml">a: 1</code></pre><code class="x onmouseover">b</code>`,
+			want:  `<pre><code class="language-yaml">a: 1</code></pre><code>b</code>`,
+		},
+		{
+			name:    "form inputs other than checkboxes removed",
+			input:   `<input type="text" value="x"/><input type="checkbox" checked="checked" disabled="disabled"/>`,
+			want:    `<input type="checkbox" checked="checked" disabled="disabled"/>`,
+			notWant: []string{"text"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(SanitizeHTML([]byte(tt.input)))
+			if got != tt.want {
+				t.Errorf("SanitizeHTML() = %q, want %q", got, tt.want)
+			}
+			for _, bad := range tt.notWant {
+				if strings.Contains(got, bad) {
+					t.Errorf("SanitizeHTML() = %q, should not contain %q", got, bad)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertMarkdownToHTML_SanitizesRawHTML(t *testing.T) {
+	report := []byte("# Report\n\n<img src=x onerror=alert(1)>\n\n[link](javascript:alert(1))\n")
+	out := string(ConvertMarkdownToHTML(report, `<script>x</script>`))
+	for _, bad := range []string{"onerror", "javascript:", "<script>"} {
+		if strings.Contains(out, bad) {
+			t.Errorf("rendered page contains %q:\n%s", bad, out)
+		}
+	}
+	if !strings.Contains(out, "&lt;script&gt;") {
+		t.Error("incident ID should be escaped in the page")
+	}
+}
+
+func TestRenderReportPage_Topology(t *testing.T) {
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>` + "\n")
+	out := string(RenderReportPage(nil, []byte("# Report\n"), "INC-1", svg))
+	if !strings.Contains(out, "Resource Topology") || !strings.Contains(out, string(svg)) {
+		t.Errorf("rendered page does not embed the topology diagram:\n%s", out)
+	}
+	if strings.Contains(string(RenderMarkdownPage(nil, []byte("# Report\n"), "INC-1")), "Resource Topology") {
+		t.Error("rendered page without a diagram has a topology section")
+	}
+}
@@ -0,0 +1,439 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/labels"
+)
+
+// MattermostNotifier sends incident notifications to a Mattermost incoming webhook
+type MattermostNotifier struct {
+	WebhookURL string
+
+	// Channel overrides the webhook's default channel, and Username the displayed
+	// sender, when the webhook allows it
+	Channel  string
+	Username string
+
+	httpClient                 *http.Client
+	failureReasonsDisplayCount int
+	labels                     notificationLabels
+	tuningFollower
+}
+
+// MattermostMessage represents a Mattermost incoming webhook message
+type MattermostMessage struct {
+	Channel     string                 `json:"channel,omitempty"`
+	Username    string                 `json:"username,omitempty"`
+	Text        string                 `json:"text,omitempty"`
+	Attachments []MattermostAttachment `json:"attachments,omitempty"`
+}
+
+// MattermostAttachment represents a message attachment
+type MattermostAttachment struct {
+	Fallback  string            `json:"fallback"`
+	Color     string            `json:"color,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	TitleLink string            `json:"title_link,omitempty"`
+	Text      string            `json:"text,omitempty"`
+	Fields    []MattermostField `json:"fields,omitempty"`
+	Footer    string            `json:"footer,omitempty"`
+}
+
+// MattermostField represents a field in an attachment
+type MattermostField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// Mattermost attachment colors
+const (
+	mattermostColorGood    = "#2EB67D"
+	mattermostColorDanger  = "#E01E5A"
+	mattermostColorWarning = "#ECB22E"
+)
+
+// NewMattermostNotifier creates a new Mattermost notifier
+func NewMattermostNotifier(webhookURL string, tuning *config.TuningConfig) *MattermostNotifier {
+	return &MattermostNotifier{
+		WebhookURL: webhookURL,
+		httpClient: &http.Client{
+			Timeout: time.Duration(tuning.HTTP.SlackTimeoutSeconds) * time.Second,
+		},
+		failureReasonsDisplayCount: tuning.Reporting.FailureReasonsDisplayCount,
+		labels:                     englishLabels,
+	}
+}
+
+// Name implements Notifier.
+func (m *MattermostNotifier) Name() string { return "mattermost" }
+
+// SetTransport sets the HTTP transport used for webhook requests, e.g. one
+// configured with proxy settings. The configured timeout is preserved.
+func (m *MattermostNotifier) SetTransport(transport http.RoundTripper) {
+	m.httpClient.Transport = transport
+}
+
+// SetLanguage sets the language of incident notification labels so they match the
+// language of the investigation report. Languages without a translation use English.
+func (m *MattermostNotifier) SetLanguage(language string) {
+	m.labels = labelsFor(language)
+}
+
+// SendIncidentNotification sends a formatted incident notification to Mattermost
+func (m *MattermostNotifier) SendIncidentNotification(summary *IncidentSummary) error {
+	if m.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	title := "Kubernetes Incident Triage :white_check_mark:"
+	color := mattermostColorGood
+	if !summary.Investigated() {
+		title = "Kubernetes Incident Triage :x:"
+		color = mattermostColorDanger
+	} else if summary.AwaitingReview() {
+		title = "Kubernetes Incident Triage :eyes:"
+		color = mattermostColorWarning
+	}
+	footer := fmt.Sprintf("Incident ID: %s | Duration: %s", summary.IncidentID, summary.Duration.Round(time.Second))
+	if summary.CachedFrom != "" {
+		title = strings.Replace(title, "Triage", "Triage (cached)", 1)
+		footer = fmt.Sprintf("Incident ID: %s | Cached report from incident %s", summary.IncidentID, summary.CachedFrom)
+	}
+	if summary.Fallback != "" {
+		title = strings.Replace(title, "Triage", "Triage (rule-based)", 1)
+		footer = fmt.Sprintf("Incident ID: %s | Rule-based report: %s", summary.IncidentID, summary.Fallback)
+	}
+	if summary.Handler != "" {
+		title = strings.Replace(title, "Triage", "Triage (fault handler)", 1)
+		footer = fmt.Sprintf("Incident ID: %s | Resolved by fault handler %s", summary.IncidentID, summary.Handler)
+	}
+	if len(summary.FollowUpOf) > 0 {
+		footer += " | " + followUpText(summary.FollowUpOf, "")
+	}
+	if summary.AwaitingReview() {
+		footer += " | Status: " + summary.Status
+	}
+
+	// Fault events from serve-only clusters have no findings yet
+	text := fmt.Sprintf("**"+m.labels.RootCause+":**\n%s", summary.Confidence, summary.RootCause)
+	fallback := fmt.Sprintf("%s on %s/%s: %s", summary.Reason, summary.Cluster, summary.Resource, summary.RootCause)
+	if summary.Changes != "" {
+		text += "\n\n_" + summary.Changes + "_"
+	}
+	if summary.RecordedOnly {
+		title = "Kubernetes Fault Event (not investigated)"
+		color = mattermostColorWarning
+		footer = fmt.Sprintf("Incident ID: %s | Recorded without an investigation (serve-only cluster)", summary.IncidentID)
+		text = fmt.Sprintf("**%s:**\n%s", m.labels.Fault, summary.FaultContext)
+		fallback = fmt.Sprintf("%s on %s/%s (not investigated): %s", summary.Reason, summary.Cluster, summary.Resource, summary.FaultContext)
+	}
+	if summary.Owner != "" {
+		footer += " | Owner: " + summary.Owner
+	}
+	if len(summary.Labels) > 0 {
+		footer += " | Labels: " + labels.Format(summary.Labels)
+	}
+
+	if summary.ReportURL != "" {
+		text += fmt.Sprintf("\n\n[%s](%s)", m.labels.ViewReport, summary.ReportURL)
+	} else if summary.ReportPath != "" {
+		text += fmt.Sprintf("\n\nReport: `%s`", summary.ReportPath)
+	}
+
+	attachment := MattermostAttachment{
+		Fallback:  fallback,
+		Color:     color,
+		Title:     title,
+		TitleLink: summary.ReportURL,
+		Text:      text,
+		Fields: []MattermostField{
+			{Title: m.labels.Cluster, Value: summary.Cluster, Short: true},
+			{Title: m.labels.Namespace, Value: summary.Namespace, Short: true},
+			{Title: m.labels.Resource, Value: summary.Resource, Short: true},
+			{Title: m.labels.Reason, Value: summary.Reason, Short: true},
+		},
+		Footer: footer,
+	}
+
+	return m.send(MattermostMessage{Attachments: []MattermostAttachment{attachment}})
+}
+
+// SendSystemDegradedAlert sends a system-level degradation alert to Mattermost
+func (m *MattermostNotifier) SendSystemDegradedAlert(ctx context.Context, stats FailureStats) error {
+	if m.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	timeWindow := "N/A"
+	if stats.Duration > 0 {
+		timeWindow = stats.Duration.Round(time.Second).String()
+	}
+
+	reasonsText := "No failure details available"
+	if reasons := recentFailureReasons(stats, m.displayCount(m.failureReasonsDisplayCount)); len(reasons) > 0 {
+		reasonsText = "- " + strings.Join(reasons, "\n- ")
+	}
+
+	attachment := MattermostAttachment{
+		Fallback: fmt.Sprintf("AI Agent System Degraded: %d failures in %s", stats.Count, timeWindow),
+		Color:    mattermostColorWarning,
+		Title:    "AI Agent System Degraded",
+		Text:     fmt.Sprintf("**Sample Failure Reasons (last %d):**\n%s", m.displayCount(m.failureReasonsDisplayCount), reasonsText),
+		Fields: []MattermostField{
+			{Title: "Failure Count", Value: fmt.Sprintf("%d", stats.Count), Short: true},
+			{Title: "Time Window", Value: timeWindow, Short: true},
+		},
+		Footer: fmt.Sprintf("First failure: %s | Last failure: %s",
+			stats.FirstFailureTime.Format("15:04:05"),
+			stats.LastFailureTime.Format("15:04:05")),
+	}
+	if classes := failureClassesText(stats); classes != "" {
+		attachment.Fields = append(attachment.Fields, MattermostField{Title: "Failure Classes", Value: classes})
+	}
+	if stats.SuspectedOutage != "" {
+		attachment.Fields = append(attachment.Fields, MattermostField{Title: "Provider Outage Suspected", Value: stats.SuspectedOutage})
+	}
+
+	return m.send(MattermostMessage{Attachments: []MattermostAttachment{attachment}})
+}
+
+// SendSystemRecoveredAlert sends a system recovery alert to Mattermost
+func (m *MattermostNotifier) SendSystemRecoveredAlert(ctx context.Context, stats FailureStats) error {
+	if m.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	downtime := "N/A"
+	if stats.Duration > 0 {
+		downtime = stats.Duration.Round(time.Second).String()
+	}
+
+	attachment := MattermostAttachment{
+		Fallback: "AI Agent System Recovered",
+		Color:    mattermostColorGood,
+		Title:    "AI Agent System Recovered",
+		Text:     "System has returned to healthy state. All agents operating normally.",
+		Fields: []MattermostField{
+			{Title: "Total Downtime", Value: downtime, Short: true},
+			{Title: "Total Failures", Value: fmt.Sprintf("%d", stats.Count), Short: true},
+		},
+	}
+
+	return m.send(MattermostMessage{Attachments: []MattermostAttachment{attachment}})
+}
+
+// SendBudgetExhaustedAlert notifies Mattermost that a cluster's daily budget is exhausted
+func (m *MattermostNotifier) SendBudgetExhaustedAlert(ctx context.Context, alert BudgetAlert) error {
+	if m.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	attachment := MattermostAttachment{
+		Fallback: fmt.Sprintf("Investigation budget exhausted for cluster %s", alert.Cluster),
+		Color:    mattermostColorWarning,
+		Title:    "Investigation Budget Exhausted",
+		Text: fmt.Sprintf("**Reason:**\n%s\n\nFurther faults are recorded but not investigated. Grant more budget with `nightcrier budget override --cluster %s`.",
+			alert.Reason, alert.Cluster),
+		Fields: []MattermostField{
+			{Title: "Cluster", Value: alert.Cluster, Short: true},
+			{Title: "Day (UTC)", Value: alert.Day, Short: true},
+			{Title: "Investigations", Value: fmt.Sprintf("%d", alert.Investigations), Short: true},
+			{Title: "Estimated Spend", Value: fmt.Sprintf("$%.2f", alert.EstimatedSpend), Short: true},
+		},
+	}
+
+	return m.send(MattermostMessage{Attachments: []MattermostAttachment{attachment}})
+}
+
+// SendClusterDisconnectedAlert notifies Mattermost that a cluster's connection has
+// been down longer than the alert threshold
+func (m *MattermostNotifier) SendClusterDisconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	if m.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	attachment := MattermostAttachment{
+		Fallback: fmt.Sprintf("Cluster %s disconnected for %s", alert.Cluster, alert.Duration.Round(time.Second)),
+		Color:    mattermostColorDanger,
+		Title:    fmt.Sprintf("Cluster Disconnected: %s", alert.Cluster),
+		Text: fmt.Sprintf("**Last Error:**\n%s\n\nFaults from this cluster are not being received. Other clusters are unaffected. Nightcrier keeps reconnecting.",
+			connectionErrorText(alert)),
+		Fields: []MattermostField{
+			{Title: "Cluster", Value: alert.Cluster, Short: true},
+			{Title: "Status", Value: alert.Status, Short: true},
+			{Title: "Down Since", Value: alert.Since.UTC().Format(time.RFC3339), Short: true},
+			{Title: "Down For", Value: alert.Duration.Round(time.Second).String(), Short: true},
+		},
+	}
+
+	return m.send(MattermostMessage{Attachments: []MattermostAttachment{attachment}})
+}
+
+// SendClusterReconnectedAlert notifies Mattermost that a disconnected cluster
+// recovered
+func (m *MattermostNotifier) SendClusterReconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	if m.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	attachment := MattermostAttachment{
+		Fallback: fmt.Sprintf("Cluster %s reconnected", alert.Cluster),
+		Color:    mattermostColorGood,
+		Title:    fmt.Sprintf("Cluster Reconnected: %s", alert.Cluster),
+		Text:     "The cluster connection is active again. Faults raised while it was down may not have been received.",
+		Fields: []MattermostField{
+			{Title: "Cluster", Value: alert.Cluster, Short: true},
+			{Title: "Total Downtime", Value: alert.Duration.Round(time.Second).String(), Short: true},
+		},
+	}
+
+	return m.send(MattermostMessage{Attachments: []MattermostAttachment{attachment}})
+}
+
+// SendQueueAlert notifies Mattermost that an event queue is filling up or has
+// dropped events
+func (m *MattermostNotifier) SendQueueAlert(ctx context.Context, alert QueueAlert) error {
+	if m.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	color := mattermostColorWarning
+	if alert.Dropped > 0 {
+		color = mattermostColorDanger
+	}
+
+	attachment := MattermostAttachment{
+		Fallback: queueAlertTitle(alert),
+		Color:    color,
+		Title:    queueAlertTitle(alert),
+		Text:     queueAlertText(alert),
+		Fields: []MattermostField{
+			{Title: "Queue", Value: alert.QueueName(), Short: true},
+			{Title: "Depth", Value: fmt.Sprintf("%d / %d (%d%%)", alert.Depth, alert.Capacity, alert.Utilization()), Short: true},
+			{Title: "Dropped Events", Value: fmt.Sprintf("%d", alert.Dropped), Short: true},
+			{Title: "Overflow Policy", Value: alert.OverflowPolicy, Short: true},
+		},
+	}
+
+	return m.send(MattermostMessage{Attachments: []MattermostAttachment{attachment}})
+}
+
+// SendCanaryAlert notifies Mattermost of a failed, recovered, or passing canary run
+func (m *MattermostNotifier) SendCanaryAlert(ctx context.Context, alert CanaryAlert) error {
+	if m.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	color := mattermostColorDanger
+	if alert.Passed {
+		color = mattermostColorGood
+	}
+
+	fields := []MattermostField{
+		{Title: "Cluster", Value: alert.Cluster, Short: true},
+		{Title: "Duration", Value: alert.Duration.Round(time.Second).String(), Short: true},
+	}
+	if !alert.Passed {
+		fields = append(fields, MattermostField{Title: "Failed Stage", Value: alert.Stage, Short: true})
+	}
+	if alert.IncidentID != "" {
+		fields = append(fields, MattermostField{Title: "Canary Run", Value: alert.IncidentID, Short: true})
+	}
+
+	attachment := MattermostAttachment{
+		Fallback: canaryAlertTitle(alert),
+		Color:    color,
+		Title:    canaryAlertTitle(alert),
+		Text:     canaryAlertText(alert),
+		Fields:   fields,
+	}
+
+	return m.send(MattermostMessage{Attachments: []MattermostAttachment{attachment}})
+}
+
+// SendCredentialExpiryAlert notifies Mattermost that a cluster's triage
+// credentials are expiring or expired, or were renewed
+func (m *MattermostNotifier) SendCredentialExpiryAlert(ctx context.Context, alert CredentialExpiryAlert) error {
+	if m.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	color := mattermostColorWarning
+	switch alert.State {
+	case CredentialsExpired:
+		color = mattermostColorDanger
+	case CredentialsRenewed:
+		color = mattermostColorGood
+	}
+
+	attachment := MattermostAttachment{
+		Fallback: credentialAlertTitle(alert),
+		Color:    color,
+		Title:    credentialAlertTitle(alert),
+		Text:     credentialAlertText(alert),
+		Fields: []MattermostField{
+			{Title: "Cluster", Value: alert.Cluster, Short: true},
+			{Title: "Credential", Value: fmt.Sprintf("%s (%s)", alert.User, alert.Kind), Short: true},
+			{Title: "Expires", Value: alert.expiresText(), Short: true},
+		},
+	}
+
+	return m.send(MattermostMessage{Attachments: []MattermostAttachment{attachment}})
+}
+
+// SendDigest sends several related notifications to Mattermost as one attachment
+// with a section per kind of notification
+func (m *MattermostNotifier) SendDigest(ctx context.Context, digest Digest) error {
+	if m.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	sections := make([]string, 0, len(digest.Sections))
+	for _, section := range digest.Sections {
+		sections = append(sections, fmt.Sprintf("**%s:**\n%s", section.Title, section.Text("- ")))
+	}
+
+	attachment := MattermostAttachment{
+		Fallback: digest.Title(),
+		Color:    mattermostColorWarning,
+		Title:    digest.Title(),
+		Text:     strings.Join(sections, "\n\n"),
+		Footer:   digest.Footer(),
+	}
+
+	return m.send(MattermostMessage{Attachments: []MattermostAttachment{attachment}})
+}
+
+// send sends a message to the Mattermost webhook
+func (m *MattermostNotifier) send(msg MattermostMessage) error {
+	msg.Channel = m.Channel
+	msg.Username = m.Username
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mattermost message: %w", err)
+	}
+
+	resp, err := m.httpClient.Post(m.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to send mattermost notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("mattermost webhook returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMattermostNotifier_SendIncidentNotification(t *testing.T) {
+	var received MattermostMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode mattermost payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewMattermostNotifier(server.URL, defaultTestTuning())
+	notifier.Channel = "k8s-incidents"
+	notifier.SetLanguage("French")
+	summary := &IncidentSummary{
+		IncidentID: "incident-123",
+		Cluster:    "prod",
+		Namespace:  "default",
+		Resource:   "Pod/web",
+		Reason:     "CrashLoopBackOff",
+		Status:     "resolved",
+		RootCause:  "Configuration manquante",
+		Confidence: "HIGH",
+		ReportURL:  "https://example.com/report",
+		CachedFrom: "incident-001",
+	}
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+
+	if received.Channel != "k8s-incidents" {
+		t.Errorf("channel = %q", received.Channel)
+	}
+	att := received.Attachments[0]
+	if att.Title != "Kubernetes Incident Triage (cached) :white_check_mark:" || att.Color != mattermostColorGood {
+		t.Errorf("title/color = %q %q", att.Title, att.Color)
+	}
+	if att.Fields[2].Title != "Ressource" {
+		t.Errorf("resource label = %q, want French", att.Fields[2].Title)
+	}
+	if !strings.Contains(att.Text, "Cause racine (confiance : HIGH)") || !strings.Contains(att.Text, "[Voir le rapport](https://example.com/report)") {
+		t.Errorf("text = %q", att.Text)
+	}
+	if att.Footer != "Incident ID: incident-123 | Cached report from incident incident-001" {
+		t.Errorf("footer = %q", att.Footer)
+	}
+}
+
+func TestMattermostNotifier_Alerts(t *testing.T) {
+	var received []MattermostMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg MattermostMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		received = append(received, msg)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewMattermostNotifier(server.URL, defaultTestTuning())
+	stats := FailureStats{Count: 2, Duration: time.Minute, RecentReasons: []string{"timeout"}}
+	ctx := context.Background()
+	if err := notifier.SendSystemDegradedAlert(ctx, stats); err != nil {
+		t.Fatalf("SendSystemDegradedAlert() error = %v", err)
+	}
+	if err := notifier.SendSystemRecoveredAlert(ctx, stats); err != nil {
+		t.Fatalf("SendSystemRecoveredAlert() error = %v", err)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(received))
+	}
+	if att := received[0].Attachments[0]; att.Color != mattermostColorWarning || !strings.Contains(att.Text, "- timeout") {
+		t.Errorf("degraded attachment = %+v", att)
+	}
+	if att := received[1].Attachments[0]; att.Title != "AI Agent System Recovered" || att.Fields[0].Value != "1m0s" {
+		t.Errorf("recovered attachment = %+v", att)
+	}
+
+	// Empty webhook is a no-op
+	if err := NewMattermostNotifier("", defaultTestTuning()).SendSystemDegradedAlert(ctx, stats); err != nil {
+		t.Errorf("SendSystemDegradedAlert() with empty webhook should not error: %v", err)
+	}
+}
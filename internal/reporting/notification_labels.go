@@ -0,0 +1,74 @@
+package reporting
+
+// notificationLabels are the field labels of an incident notification. The root cause
+// text itself comes from investigation.md and is already in the report language.
+type notificationLabels struct {
+	Cluster    string
+	Namespace  string
+	Resource   string
+	Reason     string
+	RootCause  string // format with the confidence level, e.g. "Root Cause (%s confidence)"
+	Fault      string // heads the fault description of events recorded without an investigation
+	ViewReport string
+}
+
+// englishLabels are used when no report language is set or it has no translation.
+var englishLabels = notificationLabels{
+	Cluster:    "Cluster",
+	Namespace:  "Namespace",
+	Resource:   "Resource",
+	Reason:     "Reason",
+	RootCause:  "Root Cause (%s confidence)",
+	Fault:      "Fault",
+	ViewReport: "View Report",
+}
+
+// localizedLabels maps report language names (as normalized by the config) to
+// translated notification labels.
+var localizedLabels = map[string]notificationLabels{
+	"Japanese": {
+		Cluster:    "クラスター",
+		Namespace:  "ネームスペース",
+		Resource:   "リソース",
+		Reason:     "理由",
+		RootCause:  "根本原因 (確信度: %s)",
+		Fault:      "障害",
+		ViewReport: "レポートを表示",
+	},
+	"German": {
+		Cluster:    "Cluster",
+		Namespace:  "Namespace",
+		Resource:   "Ressource",
+		Reason:     "Grund",
+		RootCause:  "Ursache (Konfidenz: %s)",
+		Fault:      "Fehler",
+		ViewReport: "Bericht anzeigen",
+	},
+	"French": {
+		Cluster:    "Cluster",
+		Namespace:  "Namespace",
+		Resource:   "Ressource",
+		Reason:     "Raison",
+		RootCause:  "Cause racine (confiance : %s)",
+		Fault:      "Panne",
+		ViewReport: "Voir le rapport",
+	},
+	"Spanish": {
+		Cluster:    "Clúster",
+		Namespace:  "Namespace",
+		Resource:   "Recurso",
+		Reason:     "Motivo",
+		RootCause:  "Causa raíz (confianza: %s)",
+		Fault:      "Fallo",
+		ViewReport: "Ver informe",
+	},
+}
+
+// labelsFor returns the notification labels for a report language, falling back
+// to English.
+func labelsFor(language string) notificationLabels {
+	if labels, ok := localizedLabels[language]; ok {
+		return labels
+	}
+	return englishLabels
+}
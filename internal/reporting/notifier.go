@@ -0,0 +1,150 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/failures"
+)
+
+// Notifier delivers incident summaries and system alerts to a chat destination.
+// SlackNotifier, DiscordNotifier, and MattermostNotifier implement it.
+type Notifier interface {
+	// Name identifies the destination in logs, e.g. "slack"
+	Name() string
+
+	SendIncidentNotification(summary *IncidentSummary) error
+	SendSystemDegradedAlert(ctx context.Context, stats FailureStats) error
+	SendSystemRecoveredAlert(ctx context.Context, stats FailureStats) error
+	SendBudgetExhaustedAlert(ctx context.Context, alert BudgetAlert) error
+	SendClusterDisconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error
+	SendClusterReconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error
+	SendQueueAlert(ctx context.Context, alert QueueAlert) error
+	SendCanaryAlert(ctx context.Context, alert CanaryAlert) error
+	SendCredentialExpiryAlert(ctx context.Context, alert CredentialExpiryAlert) error
+
+	// SendDigest sends several notifications merged by a Coalescer as one message
+	SendDigest(ctx context.Context, digest Digest) error
+}
+
+// MultiNotifier sends every notification to each of its notifiers. A failure at
+// one destination does not prevent delivery to the others; the errors are joined.
+type MultiNotifier []Notifier
+
+// Name implements Notifier.
+func (m MultiNotifier) Name() string {
+	names := ""
+	for i, n := range m {
+		if i > 0 {
+			names += ","
+		}
+		names += n.Name()
+	}
+	return names
+}
+
+// SendIncidentNotification implements Notifier.
+func (m MultiNotifier) SendIncidentNotification(summary *IncidentSummary) error {
+	return m.each(func(n Notifier) error { return n.SendIncidentNotification(summary) })
+}
+
+// SendSystemDegradedAlert implements Notifier.
+func (m MultiNotifier) SendSystemDegradedAlert(ctx context.Context, stats FailureStats) error {
+	return m.each(func(n Notifier) error { return n.SendSystemDegradedAlert(ctx, stats) })
+}
+
+// SendSystemRecoveredAlert implements Notifier.
+func (m MultiNotifier) SendSystemRecoveredAlert(ctx context.Context, stats FailureStats) error {
+	return m.each(func(n Notifier) error { return n.SendSystemRecoveredAlert(ctx, stats) })
+}
+
+// SendBudgetExhaustedAlert implements Notifier.
+func (m MultiNotifier) SendBudgetExhaustedAlert(ctx context.Context, alert BudgetAlert) error {
+	return m.each(func(n Notifier) error { return n.SendBudgetExhaustedAlert(ctx, alert) })
+}
+
+// SendClusterDisconnectedAlert implements Notifier.
+func (m MultiNotifier) SendClusterDisconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	return m.each(func(n Notifier) error { return n.SendClusterDisconnectedAlert(ctx, alert) })
+}
+
+// SendClusterReconnectedAlert implements Notifier.
+func (m MultiNotifier) SendClusterReconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	return m.each(func(n Notifier) error { return n.SendClusterReconnectedAlert(ctx, alert) })
+}
+
+// SendQueueAlert implements Notifier.
+func (m MultiNotifier) SendQueueAlert(ctx context.Context, alert QueueAlert) error {
+	return m.each(func(n Notifier) error { return n.SendQueueAlert(ctx, alert) })
+}
+
+// SendCanaryAlert implements Notifier.
+func (m MultiNotifier) SendCanaryAlert(ctx context.Context, alert CanaryAlert) error {
+	return m.each(func(n Notifier) error { return n.SendCanaryAlert(ctx, alert) })
+}
+
+// SendCredentialExpiryAlert implements Notifier.
+func (m MultiNotifier) SendCredentialExpiryAlert(ctx context.Context, alert CredentialExpiryAlert) error {
+	return m.each(func(n Notifier) error { return n.SendCredentialExpiryAlert(ctx, alert) })
+}
+
+// SendDigest implements Notifier.
+func (m MultiNotifier) SendDigest(ctx context.Context, digest Digest) error {
+	return m.each(func(n Notifier) error { return n.SendDigest(ctx, digest) })
+}
+
+// each calls send for every notifier and joins the errors, prefixed with the
+// notifier name.
+func (m MultiNotifier) each(send func(Notifier) error) error {
+	var errs []error
+	for _, n := range m {
+		if err := send(n); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", n.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// connectionErrorText returns the last connection error of an alert for display.
+func connectionErrorText(alert ClusterConnectionAlert) string {
+	if alert.LastError == "" {
+		return "none recorded"
+	}
+	return alert.LastError
+}
+
+// recentFailureReasons returns the last n failure reasons of the stats.
+func recentFailureReasons(stats FailureStats, n int) []string {
+	reasons := stats.RecentReasons
+	if len(reasons) > n {
+		reasons = reasons[len(reasons)-n:]
+	}
+	return reasons
+}
+
+// failureClassesText formats the failure counts per class of the stats, most
+// frequent first, e.g. "provider_rate_limit (4), timeout (1)". It is empty when no
+// failures were recorded.
+func failureClassesText(stats FailureStats) string {
+	classes := make([]failures.Class, 0, len(stats.Classes))
+	for class, count := range stats.Classes {
+		if count > 0 {
+			classes = append(classes, class)
+		}
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		if stats.Classes[classes[i]] != stats.Classes[classes[j]] {
+			return stats.Classes[classes[i]] > stats.Classes[classes[j]]
+		}
+		return classes[i] < classes[j]
+	})
+
+	parts := make([]string, len(classes))
+	for i, class := range classes {
+		parts[i] = fmt.Sprintf("%s (%d)", class, stats.Classes[class])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,97 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recordingNotifier records the notifications it receives.
+type recordingNotifier struct {
+	name  string
+	err   error
+	calls []string
+}
+
+func (r *recordingNotifier) Name() string { return r.name }
+
+func (r *recordingNotifier) SendIncidentNotification(summary *IncidentSummary) error {
+	r.calls = append(r.calls, "incident:"+summary.IncidentID)
+	return r.err
+}
+
+func (r *recordingNotifier) SendSystemDegradedAlert(ctx context.Context, stats FailureStats) error {
+	r.calls = append(r.calls, "degraded")
+	return r.err
+}
+
+func (r *recordingNotifier) SendSystemRecoveredAlert(ctx context.Context, stats FailureStats) error {
+	r.calls = append(r.calls, "recovered")
+	return r.err
+}
+
+func (r *recordingNotifier) SendBudgetExhaustedAlert(ctx context.Context, alert BudgetAlert) error {
+	r.calls = append(r.calls, "budget:"+alert.Cluster)
+	return r.err
+}
+
+func (r *recordingNotifier) SendClusterDisconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	r.calls = append(r.calls, "disconnected:"+alert.Cluster)
+	return r.err
+}
+
+func (r *recordingNotifier) SendClusterReconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	r.calls = append(r.calls, "reconnected:"+alert.Cluster)
+	return r.err
+}
+
+func (r *recordingNotifier) SendQueueAlert(ctx context.Context, alert QueueAlert) error {
+	r.calls = append(r.calls, fmt.Sprintf("queue:%s:%d", alert.QueueName(), alert.Dropped))
+	return r.err
+}
+
+func (r *recordingNotifier) SendCanaryAlert(ctx context.Context, alert CanaryAlert) error {
+	r.calls = append(r.calls, fmt.Sprintf("canary:%s:%t", alert.Cluster, alert.Passed))
+	return r.err
+}
+
+func (r *recordingNotifier) SendCredentialExpiryAlert(ctx context.Context, alert CredentialExpiryAlert) error {
+	r.calls = append(r.calls, fmt.Sprintf("credentials:%s:%s", alert.Cluster, alert.State))
+	return r.err
+}
+
+func (r *recordingNotifier) SendDigest(ctx context.Context, digest Digest) error {
+	r.calls = append(r.calls, fmt.Sprintf("digest:%d", digest.Count))
+	return r.err
+}
+
+func TestMultiNotifier_FansOutAndJoinsErrors(t *testing.T) {
+	failing := &recordingNotifier{name: "discord", err: errors.New("webhook gone")}
+	working := &recordingNotifier{name: "mattermost"}
+	multi := MultiNotifier{failing, working}
+
+	if multi.Name() != "discord,mattermost" {
+		t.Errorf("Name() = %q", multi.Name())
+	}
+
+	err := multi.SendIncidentNotification(&IncidentSummary{IncidentID: "inc-1"})
+	if err == nil || !strings.Contains(err.Error(), "discord: webhook gone") {
+		t.Errorf("expected discord error, got %v", err)
+	}
+	if len(working.calls) != 1 || working.calls[0] != "incident:inc-1" {
+		t.Errorf("working notifier calls = %v; a failing notifier must not block others", working.calls)
+	}
+
+	failing.err = nil
+	ctx := context.Background()
+	if err := multi.SendBudgetExhaustedAlert(ctx, BudgetAlert{Cluster: "prod"}); err != nil {
+		t.Errorf("SendBudgetExhaustedAlert() error = %v", err)
+	}
+	multi.SendSystemDegradedAlert(ctx, FailureStats{})
+	multi.SendSystemRecoveredAlert(ctx, FailureStats{})
+	if got := strings.Join(working.calls, " "); got != "incident:inc-1 budget:prod degraded recovered" {
+		t.Errorf("calls = %q", got)
+	}
+}
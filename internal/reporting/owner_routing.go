@@ -0,0 +1,65 @@
+package reporting
+
+import (
+	"sort"
+	"strings"
+)
+
+// OwnerRoute delivers the incident notifications of one owning team.
+type OwnerRoute struct {
+	// Team is the owning team, compared case-insensitively
+	Team string
+	// Notifier delivers to the team's destinations
+	Notifier Notifier
+	// Exclusive sends the team's incidents only to the team, not also to the
+	// default destinations
+	Exclusive bool
+}
+
+// OwnerRouter sends incident notifications to the destinations of the incident's
+// owning team (IncidentSummary.OwnerTeam) in addition to, or for exclusive routes
+// instead of, the default notifier. System alerts and digests are not about one
+// team's workload and go to the default notifier only.
+type OwnerRouter struct {
+	// Notifier is the default notifier; it may be nil when only routes are configured
+	Notifier
+	routes map[string]OwnerRoute
+}
+
+// NewOwnerRouter returns a router delivering to the default notifier (nil: none)
+// and the routes.
+func NewOwnerRouter(defaultNotifier Notifier, routes []OwnerRoute) *OwnerRouter {
+	if defaultNotifier == nil {
+		defaultNotifier = MultiNotifier{}
+	}
+	byTeam := make(map[string]OwnerRoute, len(routes))
+	for _, route := range routes {
+		byTeam[strings.ToLower(route.Team)] = route
+	}
+	return &OwnerRouter{Notifier: defaultNotifier, routes: byTeam}
+}
+
+// Name implements Notifier.
+func (r *OwnerRouter) Name() string {
+	teams := make([]string, 0, len(r.routes))
+	for team := range r.routes {
+		teams = append(teams, "team:"+team)
+	}
+	sort.Strings(teams)
+	if name := r.Notifier.Name(); name != "" {
+		teams = append([]string{name}, teams...)
+	}
+	return strings.Join(teams, ",")
+}
+
+// SendIncidentNotification implements Notifier.
+func (r *OwnerRouter) SendIncidentNotification(summary *IncidentSummary) error {
+	route, ok := r.routes[strings.ToLower(summary.OwnerTeam)]
+	if !ok || summary.OwnerTeam == "" {
+		return r.Notifier.SendIncidentNotification(summary)
+	}
+	if route.Exclusive {
+		return route.Notifier.SendIncidentNotification(summary)
+	}
+	return MultiNotifier{r.Notifier, route.Notifier}.SendIncidentNotification(summary)
+}
@@ -0,0 +1,45 @@
+package reporting
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestOwnerRouter(t *testing.T) {
+	defaultNotifier := &recordingNotifier{name: "slack"}
+	payments := &recordingNotifier{name: "payments-slack"}
+	search := &recordingNotifier{name: "search-slack"}
+	router := NewOwnerRouter(defaultNotifier, []OwnerRoute{
+		{Team: "Payments", Notifier: payments},
+		{Team: "search", Notifier: search, Exclusive: true},
+	})
+
+	if got, want := router.Name(), "slack,team:payments,team:search"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+
+	for _, summary := range []*IncidentSummary{
+		{IncidentID: "unowned"},
+		{IncidentID: "other-team", OwnerTeam: "web"},
+		{IncidentID: "payments", OwnerTeam: "payments"},
+		{IncidentID: "search", OwnerTeam: "Search"},
+	} {
+		if err := router.SendIncidentNotification(summary); err != nil {
+			t.Fatalf("SendIncidentNotification(%s) error = %v", summary.IncidentID, err)
+		}
+	}
+	if err := router.SendDigest(context.Background(), Digest{}); err != nil {
+		t.Fatalf("SendDigest() error = %v", err)
+	}
+
+	if want := []string{"incident:unowned", "incident:other-team", "incident:payments", "digest:0"}; !reflect.DeepEqual(defaultNotifier.calls, want) {
+		t.Errorf("default notifier calls = %v, want %v", defaultNotifier.calls, want)
+	}
+	if want := []string{"incident:payments"}; !reflect.DeepEqual(payments.calls, want) {
+		t.Errorf("payments calls = %v, want %v", payments.calls, want)
+	}
+	if want := []string{"incident:search"}; !reflect.DeepEqual(search.calls, want) {
+		t.Errorf("search calls = %v, want %v (exclusive)", search.calls, want)
+	}
+}
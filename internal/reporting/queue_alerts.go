@@ -0,0 +1,190 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+)
+
+// DefaultQueueCheckInterval is how often the QueueAlerter samples the event queues
+// when run with Run.
+const DefaultQueueCheckInterval = 10 * time.Second
+
+// defaultDropAlertCooldown is the minimum time between dropped-event alerts for the
+// same queue. Drops in between are counted into the next alert.
+const defaultDropAlertCooldown = 15 * time.Minute
+
+// globalQueueName identifies the global fan-in queue in logs and alerts.
+const globalQueueName = "global"
+
+// QueueAlert describes an event queue that is filling up or dropping events.
+type QueueAlert struct {
+	// Cluster is the cluster whose queue the alert is about; empty for the global
+	// queue shared by all clusters
+	Cluster  string
+	Depth    int
+	Capacity int
+	// Threshold is the configured utilization threshold in percent
+	Threshold int
+	// Dropped is the number of events lost since the previous dropped-event alert
+	// for this queue; 0 for utilization alerts
+	Dropped int64
+	// OverflowPolicy is the configured global queue_overflow_policy
+	OverflowPolicy string
+}
+
+// QueueName returns the cluster name, or "global" for the global queue.
+func (a QueueAlert) QueueName() string {
+	if a.Cluster == "" {
+		return globalQueueName
+	}
+	return a.Cluster
+}
+
+// Utilization returns the queue depth as a percentage of its capacity.
+func (a QueueAlert) Utilization() int {
+	if a.Capacity == 0 {
+		return 0
+	}
+	return a.Depth * 100 / a.Capacity
+}
+
+// queueAlertState tracks the alerts already sent for one queue.
+type queueAlertState struct {
+	// high is set while the queue stays at or above the threshold after an alert
+	high bool
+	// reportedDrops is the drop counter value covered by previous alerts
+	reportedDrops int64
+	// lastDropAlert is when the last dropped-event alert was sent
+	lastDropAlert time.Time
+}
+
+// QueueAlerter alerts when the global queue or a cluster's queue reaches a
+// utilization threshold, and when events are dropped by the overflow policy, so
+// event loss during incident storms is visible instead of only logged.
+//
+// A utilization alert is sent once each time a queue crosses the threshold; it is
+// sent again only after the queue has drained below the threshold. Dropped-event
+// alerts are sent at most once per cooldown per queue and report every drop since
+// the previous alert.
+type QueueAlerter struct {
+	notifier Notifier
+	// threshold is the utilization percentage that triggers an alert; 0 disables
+	// utilization alerts
+	threshold int
+	// alertOnDrops enables dropped-event alerts
+	alertOnDrops   bool
+	overflowPolicy string
+	dropCooldown   time.Duration
+
+	mu     sync.Mutex
+	queues map[string]*queueAlertState
+	now    func() time.Time
+}
+
+// NewQueueAlerter creates an alerter that alerts through notifier when a queue
+// reaches thresholdPercent utilization (0 disables) and, when alertOnDrops is set,
+// when events are dropped.
+func NewQueueAlerter(notifier Notifier, thresholdPercent int, alertOnDrops bool, overflowPolicy string) *QueueAlerter {
+	return &QueueAlerter{
+		notifier:       notifier,
+		threshold:      thresholdPercent,
+		alertOnDrops:   alertOnDrops,
+		overflowPolicy: overflowPolicy,
+		dropCooldown:   defaultDropAlertCooldown,
+		queues:         make(map[string]*queueAlertState),
+		now:            time.Now,
+	}
+}
+
+// Run samples the queues returned by stats every interval until ctx is done.
+func (a *QueueAlerter) Run(ctx context.Context, interval time.Duration, stats func() cluster.QueueStats) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.Check(ctx, stats())
+		}
+	}
+}
+
+// Check compares a queue sample against the thresholds and sends any alerts due.
+func (a *QueueAlerter) Check(ctx context.Context, stats cluster.QueueStats) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// The global queue has no drop counter of its own; its drops are attributed to
+	// the cluster whose event was lost
+	a.checkQueue(ctx, QueueAlert{Depth: stats.GlobalDepth, Capacity: stats.GlobalCapacity}, 0)
+	for _, cs := range stats.Clusters {
+		a.checkQueue(ctx, QueueAlert{Cluster: cs.Cluster, Depth: cs.Depth, Capacity: cs.Capacity}, cs.Dropped)
+	}
+}
+
+// checkQueue sends the utilization and dropped-event alerts due for one queue.
+func (a *QueueAlerter) checkQueue(ctx context.Context, alert QueueAlert, dropped int64) {
+	state, ok := a.queues[alert.QueueName()]
+	if !ok {
+		state = &queueAlertState{}
+		a.queues[alert.QueueName()] = state
+	}
+	alert.Threshold = a.threshold
+	alert.OverflowPolicy = a.overflowPolicy
+	log := slog.With("queue", alert.QueueName())
+
+	if a.threshold > 0 && alert.Capacity > 0 {
+		if alert.Utilization() < a.threshold {
+			state.high = false
+		} else if !state.high {
+			state.high = true
+			a.send(ctx, log, alert)
+		}
+	}
+
+	if !a.alertOnDrops || dropped <= state.reportedDrops {
+		return
+	}
+	now := a.now()
+	if !state.lastDropAlert.IsZero() && now.Sub(state.lastDropAlert) < a.dropCooldown {
+		return
+	}
+	alert.Dropped = dropped - state.reportedDrops
+	state.reportedDrops = dropped
+	state.lastDropAlert = now
+	a.send(ctx, log, alert)
+}
+
+// send delivers a queue alert, logging the outcome.
+func (a *QueueAlerter) send(ctx context.Context, log *slog.Logger, alert QueueAlert) {
+	attrs := []any{"depth", alert.Depth, "capacity", alert.Capacity, "dropped", alert.Dropped}
+	if err := a.notifier.SendQueueAlert(ctx, alert); err != nil {
+		log.Error("failed to send queue alert", append(attrs, "error", err)...)
+		return
+	}
+	log.Warn("queue alert sent", attrs...)
+}
+
+// queueAlertTitle returns the headline of a queue alert.
+func queueAlertTitle(alert QueueAlert) string {
+	if alert.Dropped > 0 {
+		return fmt.Sprintf("Events Dropped: %s queue", alert.QueueName())
+	}
+	return fmt.Sprintf("Event Queue Filling Up: %s queue", alert.QueueName())
+}
+
+// queueAlertText explains the consequence of a queue alert.
+func queueAlertText(alert QueueAlert) string {
+	if alert.Dropped > 0 {
+		return fmt.Sprintf("%d fault events were lost because the queue was full (overflow policy: %s). They will not be investigated.",
+			alert.Dropped, alert.OverflowPolicy)
+	}
+	return fmt.Sprintf("The queue is at or above %d%% of its capacity. Further faults will be lost once it is full (overflow policy: %s).",
+		alert.Threshold, alert.OverflowPolicy)
+}
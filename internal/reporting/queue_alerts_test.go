@@ -0,0 +1,68 @@
+package reporting
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+)
+
+func queueStats(globalDepth, prodDepth int, prodDropped int64) cluster.QueueStats {
+	return cluster.QueueStats{
+		GlobalDepth:    globalDepth,
+		GlobalCapacity: 100,
+		Clusters: []cluster.ClusterQueueStats{
+			{Cluster: "prod", Depth: prodDepth, Capacity: 10, Dropped: prodDropped},
+		},
+	}
+}
+
+func TestQueueAlerter_Utilization(t *testing.T) {
+	rec := &recordingNotifier{name: "slack"}
+	a := NewQueueAlerter(rec, 80, false, "drop")
+	ctx := context.Background()
+
+	a.Check(ctx, queueStats(50, 5, 0))
+	if len(rec.calls) != 0 {
+		t.Fatalf("calls = %v, want none below the threshold", rec.calls)
+	}
+
+	// Crossing the threshold alerts once while the queue stays full
+	a.Check(ctx, queueStats(85, 9, 0))
+	a.Check(ctx, queueStats(90, 10, 0))
+	if strings.Join(rec.calls, ",") != "queue:global:0,queue:prod:0" {
+		t.Fatalf("calls = %v, want one alert per queue", rec.calls)
+	}
+
+	// Draining below the threshold re-arms the alert
+	a.Check(ctx, queueStats(10, 1, 0))
+	a.Check(ctx, queueStats(80, 1, 0))
+	if strings.Join(rec.calls, ",") != "queue:global:0,queue:prod:0,queue:global:0" {
+		t.Errorf("calls = %v, want the global queue alerted again", rec.calls)
+	}
+}
+
+func TestQueueAlerter_Drops(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	rec := &recordingNotifier{name: "slack"}
+	a := NewQueueAlerter(rec, 0, true, "drop")
+	a.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	a.Check(ctx, queueStats(0, 0, 0))
+	a.Check(ctx, queueStats(0, 10, 3))
+	if strings.Join(rec.calls, ",") != "queue:prod:3" {
+		t.Fatalf("calls = %v, want a drop alert for 3 events", rec.calls)
+	}
+
+	// Further drops within the cooldown are held back and reported together
+	now = now.Add(time.Minute)
+	a.Check(ctx, queueStats(0, 10, 7))
+	now = now.Add(defaultDropAlertCooldown)
+	a.Check(ctx, queueStats(0, 10, 12))
+	if strings.Join(rec.calls, ",") != "queue:prod:3,queue:prod:9" {
+		t.Errorf("calls = %v, want the held-back drops in the next alert", rec.calls)
+	}
+}
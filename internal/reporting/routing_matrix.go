@@ -0,0 +1,148 @@
+package reporting
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// RoutingRule selects the channels of the incident notifications it matches. Empty
+// criteria match everything.
+type RoutingRule struct {
+	// Severities match the incident severity, case-insensitively
+	Severities []string
+	// ClusterLabels must all be set, with these values, on the incident's cluster
+	ClusterLabels map[string]string
+	// Statuses match the incident status (resolved, failed, pending, ...)
+	Statuses []string
+	// Channels receive the matching notifications
+	Channels []string
+}
+
+// matches reports whether the rule matches a notification from a cluster with the
+// given labels.
+func (r RoutingRule) matches(summary *IncidentSummary, clusterLabels map[string]string) bool {
+	if len(r.Severities) > 0 && !containsFold(r.Severities, summary.Severity) {
+		return false
+	}
+	if len(r.Statuses) > 0 && !containsFold(r.Statuses, summary.Status) {
+		return false
+	}
+	for key, value := range r.ClusterLabels {
+		if actual, ok := clusterLabels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold reports whether values contains s, ignoring case.
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoutingMatrix routes incident notifications to named channels by severity,
+// cluster labels, and status. The first matching rule selects the channels;
+// notifications matching no rule go to the default channels. System alerts and
+// digests are not about one incident and go to the default channels.
+type RoutingMatrix struct {
+	channels      map[string]Notifier
+	defaults      []string
+	rules         []RoutingRule
+	clusterLabels map[string]map[string]string
+}
+
+// NewRoutingMatrix returns a routing matrix. channels maps channel names to their
+// notifiers, defaults names the channels of unmatched notifications (none: they
+// are dropped), and clusterLabels maps cluster names to their labels. Rules and
+// defaults must only name channels in channels.
+func NewRoutingMatrix(channels map[string]Notifier, defaults []string, rules []RoutingRule, clusterLabels map[string]map[string]string) *RoutingMatrix {
+	return &RoutingMatrix{channels: channels, defaults: defaults, rules: rules, clusterLabels: clusterLabels}
+}
+
+// Name implements Notifier.
+func (m *RoutingMatrix) Name() string {
+	names := make([]string, 0, len(m.channels))
+	for name, n := range m.channels {
+		names = append(names, name+":"+n.Name())
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// Route returns the channels receiving an incident notification.
+func (m *RoutingMatrix) Route(summary *IncidentSummary) []string {
+	labels := m.clusterLabels[summary.Cluster]
+	for _, rule := range m.rules {
+		if rule.matches(summary, labels) {
+			return rule.Channels
+		}
+	}
+	return m.defaults
+}
+
+// to returns the notifiers of the named channels.
+func (m *RoutingMatrix) to(channels []string) MultiNotifier {
+	notifiers := make(MultiNotifier, 0, len(channels))
+	for _, name := range channels {
+		if n, ok := m.channels[name]; ok {
+			notifiers = append(notifiers, n)
+		}
+	}
+	return notifiers
+}
+
+// SendIncidentNotification implements Notifier.
+func (m *RoutingMatrix) SendIncidentNotification(summary *IncidentSummary) error {
+	return m.to(m.Route(summary)).SendIncidentNotification(summary)
+}
+
+// SendSystemDegradedAlert implements Notifier.
+func (m *RoutingMatrix) SendSystemDegradedAlert(ctx context.Context, stats FailureStats) error {
+	return m.to(m.defaults).SendSystemDegradedAlert(ctx, stats)
+}
+
+// SendSystemRecoveredAlert implements Notifier.
+func (m *RoutingMatrix) SendSystemRecoveredAlert(ctx context.Context, stats FailureStats) error {
+	return m.to(m.defaults).SendSystemRecoveredAlert(ctx, stats)
+}
+
+// SendBudgetExhaustedAlert implements Notifier.
+func (m *RoutingMatrix) SendBudgetExhaustedAlert(ctx context.Context, alert BudgetAlert) error {
+	return m.to(m.defaults).SendBudgetExhaustedAlert(ctx, alert)
+}
+
+// SendClusterDisconnectedAlert implements Notifier.
+func (m *RoutingMatrix) SendClusterDisconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	return m.to(m.defaults).SendClusterDisconnectedAlert(ctx, alert)
+}
+
+// SendClusterReconnectedAlert implements Notifier.
+func (m *RoutingMatrix) SendClusterReconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	return m.to(m.defaults).SendClusterReconnectedAlert(ctx, alert)
+}
+
+// SendQueueAlert implements Notifier.
+func (m *RoutingMatrix) SendQueueAlert(ctx context.Context, alert QueueAlert) error {
+	return m.to(m.defaults).SendQueueAlert(ctx, alert)
+}
+
+// SendCanaryAlert implements Notifier.
+func (m *RoutingMatrix) SendCanaryAlert(ctx context.Context, alert CanaryAlert) error {
+	return m.to(m.defaults).SendCanaryAlert(ctx, alert)
+}
+
+// SendCredentialExpiryAlert implements Notifier.
+func (m *RoutingMatrix) SendCredentialExpiryAlert(ctx context.Context, alert CredentialExpiryAlert) error {
+	return m.to(m.defaults).SendCredentialExpiryAlert(ctx, alert)
+}
+
+// SendDigest implements Notifier.
+func (m *RoutingMatrix) SendDigest(ctx context.Context, digest Digest) error {
+	return m.to(m.defaults).SendDigest(ctx, digest)
+}
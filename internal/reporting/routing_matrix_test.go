@@ -0,0 +1,70 @@
+package reporting
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRoutingMatrix(t *testing.T) {
+	oncall := &recordingNotifier{name: "slack"}
+	platform := &recordingNotifier{name: "slack"}
+	staging := &recordingNotifier{name: "discord"}
+	matrix := NewRoutingMatrix(
+		map[string]Notifier{"oncall": oncall, "platform": platform, "staging": staging},
+		[]string{"platform"},
+		[]RoutingRule{
+			{Severities: []string{"CRITICAL"}, ClusterLabels: map[string]string{"env": "prod"}, Statuses: []string{"failed"}, Channels: []string{"oncall", "platform"}},
+			{ClusterLabels: map[string]string{"env": "staging"}, Channels: []string{"staging"}},
+		},
+		map[string]map[string]string{
+			"prod-east": {"env": "prod", "region": "east"},
+			"stage-1":   {"env": "staging"},
+		},
+	)
+
+	if got, want := matrix.Name(), "oncall:slack,platform:slack,staging:discord"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+
+	for _, summary := range []*IncidentSummary{
+		{IncidentID: "prod-critical-failed", Cluster: "prod-east", Severity: "critical", Status: "failed"},
+		{IncidentID: "prod-critical-resolved", Cluster: "prod-east", Severity: "CRITICAL", Status: "resolved"},
+		{IncidentID: "prod-warning-failed", Cluster: "prod-east", Severity: "WARNING", Status: "failed"},
+		{IncidentID: "staging-critical", Cluster: "stage-1", Severity: "CRITICAL", Status: "failed"},
+		{IncidentID: "unlabeled", Cluster: "dev", Severity: "CRITICAL", Status: "failed"},
+	} {
+		if err := matrix.SendIncidentNotification(summary); err != nil {
+			t.Fatalf("SendIncidentNotification(%s) error = %v", summary.IncidentID, err)
+		}
+	}
+	if err := matrix.SendCanaryAlert(context.Background(), CanaryAlert{Cluster: "prod-east"}); err != nil {
+		t.Fatalf("SendCanaryAlert() error = %v", err)
+	}
+
+	if want := []string{"incident:prod-critical-failed"}; !reflect.DeepEqual(oncall.calls, want) {
+		t.Errorf("oncall calls = %v, want %v", oncall.calls, want)
+	}
+	if want := []string{"incident:prod-critical-failed", "incident:prod-critical-resolved", "incident:prod-warning-failed", "incident:unlabeled", "canary:prod-east:false"}; !reflect.DeepEqual(platform.calls, want) {
+		t.Errorf("platform calls = %v, want %v", platform.calls, want)
+	}
+	if want := []string{"incident:staging-critical"}; !reflect.DeepEqual(staging.calls, want) {
+		t.Errorf("staging calls = %v, want %v", staging.calls, want)
+	}
+}
+
+func TestRoutingMatrix_NoDefaults(t *testing.T) {
+	oncall := &recordingNotifier{name: "slack"}
+	matrix := NewRoutingMatrix(map[string]Notifier{"oncall": oncall}, nil,
+		[]RoutingRule{{Severities: []string{"CRITICAL"}, Channels: []string{"oncall"}}}, nil)
+
+	if err := matrix.SendIncidentNotification(&IncidentSummary{IncidentID: "minor", Severity: "INFO"}); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+	if err := matrix.SendIncidentNotification(&IncidentSummary{IncidentID: "major", Severity: "CRITICAL"}); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+	if want := []string{"incident:major"}; !reflect.DeepEqual(oncall.calls, want) {
+		t.Errorf("oncall calls = %v, want %v (unmatched notifications dropped)", oncall.calls, want)
+	}
+}
@@ -0,0 +1,176 @@
+package reporting
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedElements maps the elements kept in sanitized report HTML to the
+// attributes they may carry. Other elements are unwrapped: their content is kept
+// as text and markup they contain is sanitized in turn.
+var allowedElements = map[atom.Atom][]string{
+	atom.P: nil, atom.Br: nil, atom.Hr: nil, atom.Div: nil, atom.Span: nil,
+	atom.H1: {"id"}, atom.H2: {"id"}, atom.H3: {"id"}, atom.H4: {"id"}, atom.H5: {"id"}, atom.H6: {"id"},
+	atom.Strong: nil, atom.B: nil, atom.Em: nil, atom.I: nil, atom.Del: nil, atom.S: nil,
+	atom.Sup: nil, atom.Sub: nil, atom.Code: {"class"}, atom.Pre: nil, atom.Blockquote: nil,
+	atom.Ul: nil, atom.Ol: {"start"}, atom.Li: nil, atom.Dl: nil, atom.Dt: nil, atom.Dd: nil,
+	atom.Table: nil, atom.Thead: nil, atom.Tbody: nil, atom.Tfoot: nil, atom.Tr: nil,
+	atom.Th: {"align"}, atom.Td: {"align"},
+	atom.A:       {"href", "title", "target"},
+	atom.Img:     {"src", "alt", "title"},
+	atom.Input:   {"type", "checked", "disabled"},
+	atom.Details: nil, atom.Summary: nil,
+}
+
+// droppedElements are removed together with their content.
+var droppedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Frame: true, atom.Frameset: true,
+	atom.Object: true, atom.Embed: true, atom.Applet: true, atom.Template: true, atom.Noscript: true,
+	atom.Form: true, atom.Textarea: true, atom.Select: true, atom.Button: true, atom.Title: true,
+	atom.Link: true, atom.Meta: true, atom.Base: true, atom.Head: true,
+}
+
+var (
+	safeIDPattern        = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	safeCodeClassPattern = regexp.MustCompile(`^language-[A-Za-z0-9_+#-]+$`)
+	digitsPattern        = regexp.MustCompile(`^[0-9]{1,9}$`)
+)
+
+// SanitizeHTML reduces an HTML fragment rendered from agent-produced markdown to a
+// strict allowlist of elements, attributes, and URL schemes, so raw HTML in a
+// report cannot run scripts or restyle the page it is served in (stored XSS).
+// Scripts, styles, frames, forms, and foreign (SVG/MathML) content are removed;
+// event handlers, style attributes, and links that are not http(s), mailto, or
+// relative are stripped. Void elements are self-closed, so the output is also
+// usable as XHTML.
+func SanitizeHTML(fragment []byte) []byte {
+	context := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(bytes.NewReader(fragment), context)
+	if err != nil {
+		return []byte("<pre>" + html.EscapeString(string(fragment)) + "</pre>")
+	}
+
+	root := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+	sanitizeChildren(root)
+
+	var buf bytes.Buffer
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return []byte("<pre>" + html.EscapeString(string(fragment)) + "</pre>")
+		}
+	}
+	return buf.Bytes()
+}
+
+// sanitizeChildren sanitizes the children of n in place.
+func sanitizeChildren(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch c.Type {
+		case html.TextNode:
+		case html.ElementNode:
+			attrs, allowed := allowedElements[c.DataAtom]
+			switch {
+			case c.Namespace != "" || droppedElements[c.DataAtom]:
+				n.RemoveChild(c)
+			case !allowed:
+				// Unwrap: sanitize the element's children and lift them into its place
+				sanitizeChildren(c)
+				for gc := c.FirstChild; gc != nil; gc = c.FirstChild {
+					c.RemoveChild(gc)
+					n.InsertBefore(gc, c)
+				}
+				n.RemoveChild(c)
+			case c.DataAtom == atom.Input && attrValue(c, "type") != "checkbox":
+				n.RemoveChild(c)
+			default:
+				c.Attr = sanitizeAttrs(c, attrs)
+				sanitizeChildren(c)
+			}
+		default:
+			// Comments, doctypes, and anything else
+			n.RemoveChild(c)
+		}
+		c = next
+	}
+}
+
+// sanitizeAttrs returns the attributes of n that are allowed for its element and
+// have safe values.
+func sanitizeAttrs(n *html.Node, allowed []string) []html.Attribute {
+	var kept []html.Attribute
+	for _, a := range n.Attr {
+		if a.Namespace != "" || !slices.Contains(allowed, a.Key) || !safeAttrValue(a.Key, a.Val) {
+			continue
+		}
+		kept = append(kept, html.Attribute{Key: a.Key, Val: a.Val})
+	}
+	// Links opened in a new tab must not get a handle on the report page
+	if n.DataAtom == atom.A && attrValue(n, "target") != "" {
+		kept = append(kept, html.Attribute{Key: "rel", Val: "noopener noreferrer"})
+	}
+	return kept
+}
+
+// safeAttrValue reports whether an allowed attribute's value is safe.
+func safeAttrValue(key, val string) bool {
+	switch key {
+	case "href":
+		return safeURL(val, "http", "https", "mailto")
+	case "src":
+		return safeURL(val, "http", "https")
+	case "id":
+		return safeIDPattern.MatchString(val)
+	case "class":
+		return safeCodeClassPattern.MatchString(val)
+	case "start":
+		return digitsPattern.MatchString(val)
+	case "align":
+		return val == "left" || val == "center" || val == "right"
+	case "target":
+		return val == "_blank"
+	case "type":
+		return val == "checkbox"
+	}
+	return true
+}
+
+// safeURL reports whether u is relative or uses one of the given schemes.
+// Whitespace and control characters, which browsers ignore inside a scheme
+// ("java\tscript:"), are removed before the scheme is checked.
+func safeURL(u string, schemes ...string) bool {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, u)
+	parsed, err := url.Parse(cleaned)
+	if err != nil {
+		return false
+	}
+	if parsed.Scheme == "" {
+		// A relative URL; reject anything that still looks like a scheme
+		return !strings.Contains(strings.SplitN(cleaned, "/", 2)[0], ":")
+	}
+	return slices.Contains(schemes, strings.ToLower(parsed.Scheme))
+}
+
+// attrValue returns the value of n's attribute key, or "" when it has none.
+func attrValue(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -13,14 +13,21 @@ import (
 	"time"
 
 	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/labels"
 )
 
+// slackSectionTextLimit is the maximum length of a Slack section block's text
+const slackSectionTextLimit = 3000
+
 // SlackNotifier sends incident notifications to Slack
 type SlackNotifier struct {
 	WebhookURL                   string
 	httpClient                   *http.Client
 	rootCauseTruncationLength    int
 	failureReasonsDisplayCount   int
+	labels                       notificationLabels
+	tuningFollower
 }
 
 // SlackMessage represents a Slack webhook message
@@ -71,6 +78,7 @@ type IncidentSummary struct {
 	Namespace  string
 	Resource   string
 	Reason     string
+	Severity   string // Fault severity (CRITICAL, ERROR, WARNING, ...), used for routing
 	Status     string
 	RootCause  string
 	Confidence string
@@ -78,6 +86,37 @@ type IncidentSummary struct {
 	ReportPath string
 	ReportURL  string
 	LogURLs    map[string]string // Maps log file names to their presigned URLs
+	CachedFrom string            // Set when the report was served from a previous identical investigation
+	FollowUpOf []string          // Earlier resolved incidents of a recurring fault, most recent first
+	Changes    string            // What changed since the prior investigation of a follow-up, e.g. "Root cause changed since incident INC-12"
+	Fallback   string            // Set when rule-based triage replaced the agent: why no agent ran, e.g. "budget exhausted"
+	Handler    string            // Set when a fault handler resolved the fault without an agent: the handler's name
+
+	// Owner is the owning team and service for display (see ownership.Owner) and
+	// OwnerTeam the team whose notification route receives the incident
+	Owner     string
+	OwnerTeam string
+
+	// Labels are the incident's labels, including those passed through from the
+	// fault event (e.g. upstream alert correlation keys)
+	Labels map[string]string
+
+	// Set for fault events recorded without an investigation (serve-only clusters);
+	// FaultContext, the event's fault description, is shown instead of a root cause
+	RecordedOnly bool
+	FaultContext string
+}
+
+// Investigated reports whether the investigation produced findings: it was
+// resolved, or its findings await a human (see incident.Investigated).
+func (s *IncidentSummary) Investigated() bool {
+	return incident.Investigated(s.Status)
+}
+
+// AwaitingReview reports whether the findings are held for a human to review or
+// to act on a proposed remediation.
+func (s *IncidentSummary) AwaitingReview() bool {
+	return s.Status == incident.StatusAwaitingReview || s.Status == incident.StatusRemediationProposed
 }
 
 // NewSlackNotifier creates a new Slack notifier
@@ -89,9 +128,25 @@ func NewSlackNotifier(webhookURL string, tuning *config.TuningConfig) *SlackNoti
 		},
 		rootCauseTruncationLength:  tuning.Reporting.RootCauseTruncationLength,
 		failureReasonsDisplayCount: tuning.Reporting.FailureReasonsDisplayCount,
+		labels:                     englishLabels,
 	}
 }
 
+// Name implements Notifier.
+func (s *SlackNotifier) Name() string { return "slack" }
+
+// SetTransport sets the HTTP transport used for webhook requests, e.g. one
+// configured with proxy settings. The configured timeout is preserved.
+func (s *SlackNotifier) SetTransport(transport http.RoundTripper) {
+	s.httpClient.Transport = transport
+}
+
+// SetLanguage sets the language of incident notification labels so they match the
+// language of the investigation report. Languages without a translation use English.
+func (s *SlackNotifier) SetLanguage(language string) {
+	s.labels = labelsFor(language)
+}
+
 // SendIncidentNotification sends a formatted incident notification to Slack
 func (s *SlackNotifier) SendIncidentNotification(summary *IncidentSummary) error {
 	if s.WebhookURL == "" {
@@ -102,9 +157,52 @@ func (s *SlackNotifier) SendIncidentNotification(summary *IncidentSummary) error
 	statusEmoji := ":white_check_mark:"
 	statusColor := "good"
 	// Check for resolved status (successful completion)
-	if summary.Status != "resolved" {
+	if !summary.Investigated() {
 		statusEmoji = ":x:"
 		statusColor = "danger"
+	} else if summary.AwaitingReview() {
+		statusEmoji = ":eyes:"
+		statusColor = "warning"
+	}
+
+	// Mark reports served from the investigation cache
+	header := fmt.Sprintf("Kubernetes Incident Triage %s", statusEmoji)
+	contextText := fmt.Sprintf("Incident ID: `%s` | Duration: %s", summary.IncidentID, summary.Duration.Round(time.Second))
+	if summary.CachedFrom != "" {
+		header = fmt.Sprintf("Kubernetes Incident Triage (cached) %s", statusEmoji)
+		contextText = fmt.Sprintf("Incident ID: `%s` | Cached report from incident `%s`", summary.IncidentID, summary.CachedFrom)
+	}
+	if summary.Fallback != "" {
+		header = fmt.Sprintf("Kubernetes Incident Triage (rule-based) %s", statusEmoji)
+		contextText = fmt.Sprintf("Incident ID: `%s` | Rule-based report: %s", summary.IncidentID, summary.Fallback)
+	}
+	if summary.Handler != "" {
+		header = fmt.Sprintf("Kubernetes Incident Triage (fault handler) %s", statusEmoji)
+		contextText = fmt.Sprintf("Incident ID: `%s` | Resolved by fault handler `%s`", summary.IncidentID, summary.Handler)
+	}
+	if len(summary.FollowUpOf) > 0 {
+		contextText += " | " + followUpText(summary.FollowUpOf, "`")
+	}
+	if summary.AwaitingReview() {
+		contextText += " | Status: " + summary.Status
+	}
+
+	// Fault events from serve-only clusters have no findings yet
+	detailText := fmt.Sprintf("*"+s.labels.RootCause+":*\n%s", summary.Confidence, summary.RootCause)
+	if summary.Changes != "" {
+		detailText += "\n\n_" + summary.Changes + "_"
+	}
+	if summary.RecordedOnly {
+		header = "Kubernetes Fault Event (not investigated)"
+		statusColor = "warning"
+		contextText = fmt.Sprintf("Incident ID: `%s` | Recorded without an investigation (serve-only cluster)", summary.IncidentID)
+		detailText = fmt.Sprintf("*%s:*\n%s", s.labels.Fault, summary.FaultContext)
+	}
+	if summary.Owner != "" {
+		contextText += " | Owner: " + summary.Owner
+	}
+	if len(summary.Labels) > 0 {
+		contextText += " | Labels: " + labels.Format(summary.Labels)
 	}
 
 	// Build the blocks
@@ -113,29 +211,29 @@ func (s *SlackNotifier) SendIncidentNotification(summary *IncidentSummary) error
 			Type: "header",
 			Text: &SlackText{
 				Type: "plain_text",
-				Text: fmt.Sprintf("Kubernetes Incident Triage %s", statusEmoji),
+				Text: header,
 			},
 		},
 		{
 			Type: "section",
 			Fields: []SlackText{
-				{Type: "mrkdwn", Text: fmt.Sprintf("*Cluster:*\n%s", summary.Cluster)},
-				{Type: "mrkdwn", Text: fmt.Sprintf("*Namespace:*\n%s", summary.Namespace)},
-				{Type: "mrkdwn", Text: fmt.Sprintf("*Resource:*\n%s", summary.Resource)},
-				{Type: "mrkdwn", Text: fmt.Sprintf("*Reason:*\n%s", summary.Reason)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*%s:*\n%s", s.labels.Cluster, summary.Cluster)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*%s:*\n%s", s.labels.Namespace, summary.Namespace)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*%s:*\n%s", s.labels.Resource, summary.Resource)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*%s:*\n%s", s.labels.Reason, summary.Reason)},
 			},
 		},
 		{
 			Type: "section",
 			Text: &SlackText{
 				Type: "mrkdwn",
-				Text: fmt.Sprintf("*Root Cause (%s confidence):*\n%s", summary.Confidence, summary.RootCause),
+				Text: detailText,
 			},
 		},
 		{
 			Type: "context",
 			Elements: []interface{}{
-				SlackElement{Type: "mrkdwn", Text: fmt.Sprintf("Incident ID: `%s` | Duration: %s", summary.IncidentID, summary.Duration.Round(time.Second))},
+				SlackElement{Type: "mrkdwn", Text: contextText},
 			},
 		},
 	}
@@ -149,7 +247,7 @@ func (s *SlackNotifier) SendIncidentNotification(summary *IncidentSummary) error
 					Type: "button",
 					Text: &SlackText{
 						Type: "plain_text",
-						Text: "View Report",
+						Text: s.labels.ViewReport,
 					},
 					URL: summary.ReportURL,
 				},
@@ -195,10 +293,7 @@ func (s *SlackNotifier) SendSystemDegradedAlert(ctx context.Context, stats Failu
 	failureCount := fmt.Sprintf("%d", stats.Count)
 
 	// Get the last N failure reasons (configured via tuning)
-	sampleReasons := stats.RecentReasons
-	if len(sampleReasons) > s.failureReasonsDisplayCount {
-		sampleReasons = sampleReasons[len(sampleReasons)-s.failureReasonsDisplayCount:]
-	}
+	sampleReasons := recentFailureReasons(stats, s.displayCount(s.failureReasonsDisplayCount))
 
 	// Format sample reasons as a bullet list
 	reasonsText := ""
@@ -213,6 +308,16 @@ func (s *SlackNotifier) SendSystemDegradedAlert(ctx context.Context, stats Failu
 	}
 
 	// Build the blocks
+	summaryFields := []SlackText{
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Failure Count:*\n%s", failureCount)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Time Window:*\n%s", timeWindow)},
+	}
+	if classes := failureClassesText(stats); classes != "" {
+		summaryFields = append(summaryFields, SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*Failure Classes:*\n%s", classes)})
+	}
+	if stats.SuspectedOutage != "" {
+		summaryFields = append(summaryFields, SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*Provider Outage Suspected:*\n%s", stats.SuspectedOutage)})
+	}
 	blocks := []SlackBlock{
 		{
 			Type: "header",
@@ -222,17 +327,14 @@ func (s *SlackNotifier) SendSystemDegradedAlert(ctx context.Context, stats Failu
 			},
 		},
 		{
-			Type: "section",
-			Fields: []SlackText{
-				{Type: "mrkdwn", Text: fmt.Sprintf("*Failure Count:*\n%s", failureCount)},
-				{Type: "mrkdwn", Text: fmt.Sprintf("*Time Window:*\n%s", timeWindow)},
-			},
+			Type:   "section",
+			Fields: summaryFields,
 		},
 		{
 			Type: "section",
 			Text: &SlackText{
 				Type: "mrkdwn",
-				Text: fmt.Sprintf("*Sample Failure Reasons (last %d):*\n%s", s.failureReasonsDisplayCount, reasonsText),
+				Text: fmt.Sprintf("*Sample Failure Reasons (last %d):*\n%s", s.displayCount(s.failureReasonsDisplayCount), reasonsText),
 			},
 		},
 		{
@@ -312,6 +414,364 @@ func (s *SlackNotifier) SendSystemRecoveredAlert(ctx context.Context, stats Fail
 	return s.send(msg)
 }
 
+// BudgetAlert describes a cluster whose daily investigation budget is exhausted.
+type BudgetAlert struct {
+	Cluster        string
+	Day            string
+	Reason         string
+	Investigations int
+	EstimatedSpend float64
+}
+
+// SendBudgetExhaustedAlert notifies Slack that a cluster's daily budget is exhausted
+// and further investigations are being skipped until the next day or an override.
+func (s *SlackNotifier) SendBudgetExhaustedAlert(ctx context.Context, alert BudgetAlert) error {
+	if s.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackText{
+				Type: "plain_text",
+				Text: "Investigation Budget Exhausted",
+			},
+		},
+		{
+			Type: "section",
+			Fields: []SlackText{
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Cluster:*\n%s", alert.Cluster)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Day (UTC):*\n%s", alert.Day)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Investigations:*\n%d", alert.Investigations)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Estimated Spend:*\n$%.2f", alert.EstimatedSpend)},
+			},
+		},
+		{
+			Type: "section",
+			Text: &SlackText{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*Reason:*\n%s", alert.Reason),
+			},
+		},
+		{
+			Type: "context",
+			Elements: []interface{}{
+				SlackElement{Type: "mrkdwn", Text: fmt.Sprintf("Further faults are recorded but not investigated. Grant more budget with `nightcrier budget override --cluster %s`.", alert.Cluster)},
+			},
+		},
+	}
+
+	msg := SlackMessage{
+		Blocks: blocks,
+		Attachments: []SlackAttachment{
+			{
+				Color:  "warning",
+				Footer: "Daily investigation budget reached.",
+			},
+		},
+	}
+
+	return s.send(msg)
+}
+
+// SendClusterDisconnectedAlert notifies Slack that a cluster's connection has been
+// down longer than the alert threshold, so its faults are not being received.
+func (s *SlackNotifier) SendClusterDisconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	if s.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackText{
+				Type: "plain_text",
+				Text: fmt.Sprintf("Cluster Disconnected: %s", alert.Cluster),
+			},
+		},
+		{
+			Type: "section",
+			Fields: []SlackText{
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Cluster:*\n%s", alert.Cluster)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Status:*\n%s", alert.Status)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Down Since:*\n%s", alert.Since.UTC().Format(time.RFC3339))},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Down For:*\n%s", alert.Duration.Round(time.Second))},
+			},
+		},
+		{
+			Type: "section",
+			Text: &SlackText{
+				Type: "mrkdwn",
+				Text: fmt.Sprintf("*Last Error:*\n```%s```", connectionErrorText(alert)),
+			},
+		},
+		{
+			Type: "context",
+			Elements: []interface{}{
+				SlackElement{Type: "mrkdwn", Text: "Faults from this cluster are not being received. Other clusters are unaffected. Nightcrier keeps reconnecting."},
+			},
+		},
+	}
+
+	msg := SlackMessage{
+		Blocks: blocks,
+		Attachments: []SlackAttachment{
+			{
+				Color:  "danger",
+				Footer: "Cluster connection alert.",
+			},
+		},
+	}
+
+	return s.send(msg)
+}
+
+// SendClusterReconnectedAlert notifies Slack that a cluster alerted as disconnected
+// is receiving events again.
+func (s *SlackNotifier) SendClusterReconnectedAlert(ctx context.Context, alert ClusterConnectionAlert) error {
+	if s.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackText{
+				Type: "plain_text",
+				Text: fmt.Sprintf("Cluster Reconnected: %s", alert.Cluster),
+			},
+		},
+		{
+			Type: "section",
+			Fields: []SlackText{
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Cluster:*\n%s", alert.Cluster)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Total Downtime:*\n%s", alert.Duration.Round(time.Second))},
+			},
+		},
+		{
+			Type: "context",
+			Elements: []interface{}{
+				SlackElement{Type: "mrkdwn", Text: "The cluster connection is active again. Faults raised while it was down may not have been received."},
+			},
+		},
+	}
+
+	msg := SlackMessage{
+		Blocks: blocks,
+		Attachments: []SlackAttachment{
+			{
+				Color:  "good",
+				Footer: "Cluster connection recovered.",
+			},
+		},
+	}
+
+	return s.send(msg)
+}
+
+// SendQueueAlert notifies Slack that an event queue is filling up or has dropped
+// events.
+func (s *SlackNotifier) SendQueueAlert(ctx context.Context, alert QueueAlert) error {
+	if s.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	color := "warning"
+	if alert.Dropped > 0 {
+		color = "danger"
+	}
+
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackText{
+				Type: "plain_text",
+				Text: queueAlertTitle(alert),
+			},
+		},
+		{
+			Type: "section",
+			Fields: []SlackText{
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Queue:*\n%s", alert.QueueName())},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Depth:*\n%d / %d (%d%%)", alert.Depth, alert.Capacity, alert.Utilization())},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Dropped Events:*\n%d", alert.Dropped)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Overflow Policy:*\n%s", alert.OverflowPolicy)},
+			},
+		},
+		{
+			Type: "context",
+			Elements: []interface{}{
+				SlackElement{Type: "mrkdwn", Text: queueAlertText(alert)},
+			},
+		},
+	}
+
+	msg := SlackMessage{
+		Blocks: blocks,
+		Attachments: []SlackAttachment{
+			{
+				Color:  color,
+				Footer: "Event queue alert.",
+			},
+		},
+	}
+
+	return s.send(msg)
+}
+
+// SendCanaryAlert notifies Slack of a failed, recovered, or (when enabled) passing
+// canary investigation.
+func (s *SlackNotifier) SendCanaryAlert(ctx context.Context, alert CanaryAlert) error {
+	if s.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	color := "danger"
+	if alert.Passed {
+		color = "good"
+	}
+
+	fields := []SlackText{
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Cluster:*\n%s", alert.Cluster)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Duration:*\n%s", alert.Duration.Round(time.Second))},
+	}
+	if !alert.Passed {
+		fields = append(fields, SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*Failed Stage:*\n%s", alert.Stage)})
+	}
+	if alert.IncidentID != "" {
+		fields = append(fields, SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*Canary Run:*\n%s", alert.IncidentID)})
+	}
+
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackText{
+				Type: "plain_text",
+				Text: canaryAlertTitle(alert),
+			},
+		},
+		{
+			Type:   "section",
+			Fields: fields,
+		},
+		{
+			Type: "context",
+			Elements: []interface{}{
+				SlackElement{Type: "mrkdwn", Text: canaryAlertText(alert)},
+			},
+		},
+	}
+
+	msg := SlackMessage{
+		Blocks: blocks,
+		Attachments: []SlackAttachment{
+			{
+				Color:  color,
+				Footer: "Synthetic canary investigation.",
+			},
+		},
+	}
+
+	return s.send(msg)
+}
+
+// SendCredentialExpiryAlert notifies Slack that a cluster's triage credentials
+// are expiring or expired, or were renewed.
+func (s *SlackNotifier) SendCredentialExpiryAlert(ctx context.Context, alert CredentialExpiryAlert) error {
+	if s.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	color := "warning"
+	switch alert.State {
+	case CredentialsExpired:
+		color = "danger"
+	case CredentialsRenewed:
+		color = "good"
+	}
+
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackText{
+				Type: "plain_text",
+				Text: credentialAlertTitle(alert),
+			},
+		},
+		{
+			Type: "section",
+			Fields: []SlackText{
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Cluster:*\n%s", alert.Cluster)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Credential:*\n%s (%s)", alert.User, alert.Kind)},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Expires:*\n%s", alert.expiresText())},
+				{Type: "mrkdwn", Text: fmt.Sprintf("*Kubeconfig:*\n%s", alert.Kubeconfig)},
+			},
+		},
+		{
+			Type: "context",
+			Elements: []interface{}{
+				SlackElement{Type: "mrkdwn", Text: credentialAlertText(alert)},
+			},
+		},
+	}
+
+	msg := SlackMessage{
+		Blocks: blocks,
+		Attachments: []SlackAttachment{
+			{
+				Color:  color,
+				Footer: "Cluster credential expiry check.",
+			},
+		},
+	}
+
+	return s.send(msg)
+}
+
+// SendDigest sends several related notifications to Slack as one message with a
+// section per kind of notification.
+func (s *SlackNotifier) SendDigest(ctx context.Context, digest Digest) error {
+	if s.WebhookURL == "" {
+		return nil // No webhook configured, skip silently
+	}
+
+	blocks := []SlackBlock{
+		{
+			Type: "header",
+			Text: &SlackText{
+				Type: "plain_text",
+				Text: digest.Title(),
+			},
+		},
+	}
+	for _, section := range digest.Sections {
+		text := fmt.Sprintf("*%s:*\n%s", section.Title, section.Text("• "))
+		if runes := []rune(text); len(runes) > slackSectionTextLimit {
+			text = string(runes[:slackSectionTextLimit-3]) + "..."
+		}
+		blocks = append(blocks, SlackBlock{
+			Type: "section",
+			Text: &SlackText{
+				Type: "mrkdwn",
+				Text: text,
+			},
+		})
+	}
+
+	msg := SlackMessage{
+		Blocks: blocks,
+		Attachments: []SlackAttachment{
+			{
+				Color:  "warning",
+				Footer: digest.Footer(),
+			},
+		},
+	}
+
+	return s.send(msg)
+}
+
 // send sends a message to the Slack webhook
 func (s *SlackNotifier) send(msg SlackMessage) error {
 	payload, err := json.Marshal(msg)
@@ -335,12 +795,27 @@ func (s *SlackNotifier) send(msg SlackMessage) error {
 
 // TruncateRootCause truncates the root cause text to the configured length
 func (s *SlackNotifier) TruncateRootCause(rootCause string) string {
-	if len(rootCause) > s.rootCauseTruncationLength {
-		return rootCause[:s.rootCauseTruncationLength-3] + "..."
+	limit := s.truncationLength(s.rootCauseTruncationLength)
+	if len(rootCause) > limit {
+		return rootCause[:limit-3] + "..."
 	}
 	return rootCause
 }
 
+// followUpText describes the chain of earlier incidents a follow-up incident
+// recurred after, each incident ID wrapped in quote.
+func followUpText(chain []string, quote string) string {
+	ids := make([]string, len(chain))
+	for i, id := range chain {
+		ids[i] = quote + id + quote
+	}
+	text := "Follow-up of incident " + ids[0]
+	if len(ids) > 1 {
+		text += fmt.Sprintf(" (recurrence %d; earlier: %s)", len(ids)+1, strings.Join(ids[1:], " ← "))
+	}
+	return text
+}
+
 // ExtractSummaryFromReport reads an investigation report and extracts key information
 func ExtractSummaryFromReport(workspacePath string) (rootCause, confidence string, err error) {
 	reportPath := filepath.Join(workspacePath, "output", "investigation.md")
@@ -350,7 +825,14 @@ func ExtractSummaryFromReport(workspacePath string) (rootCause, confidence strin
 		return "", "", fmt.Errorf("failed to read investigation report: %w", err)
 	}
 
-	lines := strings.Split(string(content), "\n")
+	rootCause, confidence = ExtractSummary(string(content))
+	return rootCause, confidence, nil
+}
+
+// ExtractSummary extracts the root cause and confidence level from the markdown of
+// an investigation report, e.g. one stored in the state store.
+func ExtractSummary(report string) (rootCause, confidence string) {
+	lines := strings.Split(report, "\n")
 
 	// Extract root cause and confidence from the report
 	inRootCause := false
@@ -400,5 +882,5 @@ func ExtractSummaryFromReport(workspacePath string) (rootCause, confidence strin
 		confidence = "UNKNOWN"
 	}
 
-	return rootCause, confidence, nil
+	return rootCause, confidence
 }
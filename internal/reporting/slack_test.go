@@ -3,6 +3,9 @@ package reporting
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -985,3 +988,360 @@ func TestSendSystemRecoveredAlert_HighFailureCount(t *testing.T) {
 		t.Errorf("SendSystemRecoveredAlert should not error: %v", err)
 	}
 }
+
+func TestSendBudgetExhaustedAlert(t *testing.T) {
+	var received SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode slack payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL, defaultTestTuning())
+	alert := BudgetAlert{
+		Cluster:        "prod-east",
+		Day:            "2025-03-01",
+		Reason:         "daily investigation budget exhausted (20/20)",
+		Investigations: 20,
+		EstimatedSpend: 10,
+	}
+	if err := notifier.SendBudgetExhaustedAlert(context.Background(), alert); err != nil {
+		t.Fatalf("SendBudgetExhaustedAlert() error = %v", err)
+	}
+
+	if len(received.Blocks) != 4 {
+		t.Fatalf("expected 4 blocks, got %d", len(received.Blocks))
+	}
+	if received.Blocks[0].Text.Text != "Investigation Budget Exhausted" {
+		t.Errorf("header = %q", received.Blocks[0].Text.Text)
+	}
+	if received.Blocks[1].Fields[0].Text != "*Cluster:*\nprod-east" {
+		t.Errorf("cluster field = %q", received.Blocks[1].Fields[0].Text)
+	}
+	if received.Blocks[1].Fields[3].Text != "*Estimated Spend:*\n$10.00" {
+		t.Errorf("spend field = %q", received.Blocks[1].Fields[3].Text)
+	}
+
+	// Empty webhook is a no-op
+	if err := NewSlackNotifier("", defaultTestTuning()).SendBudgetExhaustedAlert(context.Background(), alert); err != nil {
+		t.Errorf("SendBudgetExhaustedAlert() with empty webhook should not error: %v", err)
+	}
+}
+
+func TestSendClusterDisconnectedAlert(t *testing.T) {
+	var received SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode slack payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL, defaultTestTuning())
+	alert := ClusterConnectionAlert{
+		Cluster:   "prod-west",
+		Status:    "failed",
+		Since:     time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Duration:  6 * time.Minute,
+		LastError: "subscribe failed: connection refused",
+	}
+	if err := notifier.SendClusterDisconnectedAlert(context.Background(), alert); err != nil {
+		t.Fatalf("SendClusterDisconnectedAlert() error = %v", err)
+	}
+	if received.Blocks[0].Text.Text != "Cluster Disconnected: prod-west" {
+		t.Errorf("header = %q", received.Blocks[0].Text.Text)
+	}
+	if received.Blocks[1].Fields[3].Text != "*Down For:*\n6m0s" {
+		t.Errorf("duration field = %q", received.Blocks[1].Fields[3].Text)
+	}
+	if !strings.Contains(received.Blocks[2].Text.Text, "connection refused") {
+		t.Errorf("last error = %q", received.Blocks[2].Text.Text)
+	}
+
+	if err := notifier.SendClusterReconnectedAlert(context.Background(), alert); err != nil {
+		t.Fatalf("SendClusterReconnectedAlert() error = %v", err)
+	}
+	if received.Blocks[0].Text.Text != "Cluster Reconnected: prod-west" {
+		t.Errorf("header = %q", received.Blocks[0].Text.Text)
+	}
+}
+
+func TestSendIncidentNotification_CachedMarker(t *testing.T) {
+	var received SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode slack payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL, defaultTestTuning())
+	summary := &IncidentSummary{
+		IncidentID: "new-incident",
+		Cluster:    "prod",
+		Namespace:  "default",
+		Resource:   "Pod/web",
+		Reason:     "CrashLoopBackOff",
+		Status:     "resolved",
+		RootCause:  "Bad config",
+		Confidence: "HIGH",
+		CachedFrom: "original-incident",
+	}
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+
+	if got := received.Blocks[0].Text.Text; got != "Kubernetes Incident Triage (cached) :white_check_mark:" {
+		t.Errorf("header = %q, want cached marker", got)
+	}
+	ctxElem, ok := received.Blocks[3].Elements[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("unexpected context element type %T", received.Blocks[3].Elements[0])
+	}
+	if got := ctxElem["text"]; got != "Incident ID: `new-incident` | Cached report from incident `original-incident`" {
+		t.Errorf("context = %q", got)
+	}
+}
+
+func TestSendIncidentNotification_FallbackMarker(t *testing.T) {
+	var received SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode slack payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL, defaultTestTuning())
+	summary := &IncidentSummary{
+		IncidentID: "fallback-incident",
+		Cluster:    "prod",
+		Namespace:  "default",
+		Resource:   "Pod/web",
+		Reason:     "OOMKilled",
+		Status:     "resolved",
+		RootCause:  "The container was killed for exceeding its memory limit",
+		Confidence: "MEDIUM",
+		Fallback:   "budget exhausted",
+	}
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+
+	if got := received.Blocks[0].Text.Text; got != "Kubernetes Incident Triage (rule-based) :white_check_mark:" {
+		t.Errorf("header = %q, want rule-based marker", got)
+	}
+	ctxElem, ok := received.Blocks[3].Elements[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("unexpected context element type %T", received.Blocks[3].Elements[0])
+	}
+	if got := ctxElem["text"]; got != "Incident ID: `fallback-incident` | Rule-based report: budget exhausted" {
+		t.Errorf("context = %q", got)
+	}
+}
+
+func TestSendIncidentNotification_HandlerMarker(t *testing.T) {
+	var received SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode slack payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL, defaultTestTuning())
+	summary := &IncidentSummary{
+		IncidentID: "handled-incident",
+		Cluster:    "prod",
+		Namespace:  "db",
+		Resource:   "PersistentVolumeClaim/data-db-0",
+		Reason:     "PVCFull",
+		Status:     "resolved",
+		RootCause:  "The volume was full; it was expanded to 20Gi",
+		Confidence: "HIGH",
+		Handler:    "pvc-expand",
+	}
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+
+	if got := received.Blocks[0].Text.Text; got != "Kubernetes Incident Triage (fault handler) :white_check_mark:" {
+		t.Errorf("header = %q, want fault handler marker", got)
+	}
+	ctxElem, ok := received.Blocks[3].Elements[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("unexpected context element type %T", received.Blocks[3].Elements[0])
+	}
+	if got := ctxElem["text"]; got != "Incident ID: `handled-incident` | Resolved by fault handler `pvc-expand`" {
+		t.Errorf("context = %q", got)
+	}
+}
+
+func TestSendIncidentNotification_Labels(t *testing.T) {
+	var received SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode slack payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL, defaultTestTuning())
+	summary := &IncidentSummary{
+		IncidentID: "labeled-incident",
+		Cluster:    "prod",
+		Resource:   "Pod/web",
+		Reason:     "CrashLoopBackOff",
+		Status:     "resolved",
+		RootCause:  "Bad config",
+		Confidence: "HIGH",
+		Duration:   time.Minute,
+		Labels:     map[string]string{"alertname": "KubePodCrashLooping", "correlation": "PD-4711"},
+	}
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+
+	ctxElem, ok := received.Blocks[3].Elements[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("unexpected context element type %T", received.Blocks[3].Elements[0])
+	}
+	if got := ctxElem["text"]; got != "Incident ID: `labeled-incident` | Duration: 1m0s | Labels: alertname=KubePodCrashLooping,correlation=PD-4711" {
+		t.Errorf("context = %q", got)
+	}
+}
+
+func TestSendIncidentNotification_RecordedOnly(t *testing.T) {
+	var received SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode slack payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL, defaultTestTuning())
+	summary := &IncidentSummary{
+		IncidentID:   "recorded-incident",
+		Cluster:      "staging",
+		Namespace:    "default",
+		Resource:     "Pod/web",
+		Reason:       "CrashLoopBackOff",
+		Status:       "pending",
+		RecordedOnly: true,
+		FaultContext: "Back-off restarting failed container",
+	}
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+
+	if got := received.Blocks[0].Text.Text; got != "Kubernetes Fault Event (not investigated)" {
+		t.Errorf("header = %q", got)
+	}
+	if got := received.Blocks[2].Text.Text; got != "*Fault:*\nBack-off restarting failed container" {
+		t.Errorf("detail = %q, want the fault description", got)
+	}
+	if got := received.Attachments[0].Color; got != "warning" {
+		t.Errorf("color = %q, want warning", got)
+	}
+	ctxElem, ok := received.Blocks[3].Elements[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("unexpected context element type %T", received.Blocks[3].Elements[0])
+	}
+	if got := ctxElem["text"]; got != "Incident ID: `recorded-incident` | Recorded without an investigation (serve-only cluster)" {
+		t.Errorf("context = %q", got)
+	}
+}
+
+func TestSendIncidentNotification_FollowUp(t *testing.T) {
+	var received SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode slack payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL, defaultTestTuning())
+	summary := &IncidentSummary{
+		IncidentID: "incident-3",
+		Cluster:    "prod",
+		Namespace:  "default",
+		Resource:   "Pod/web",
+		Reason:     "OOMKilled",
+		Status:     "resolved",
+		RootCause:  "Memory limit too low",
+		Confidence: "HIGH",
+		Duration:   90 * time.Second,
+		FollowUpOf: []string{"incident-2", "incident-1"},
+		Changes:    "Root cause changed since incident incident-2; confidence LOW → HIGH (+2)",
+	}
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+
+	ctxElem, ok := received.Blocks[3].Elements[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("unexpected context element type %T", received.Blocks[3].Elements[0])
+	}
+	want := "Incident ID: `incident-3` | Duration: 1m30s | Follow-up of incident `incident-2` (recurrence 3; earlier: `incident-1`)"
+	if got := ctxElem["text"]; got != want {
+		t.Errorf("context = %q, want %q", got, want)
+	}
+	if got := received.Blocks[2].Text.Text; !strings.HasSuffix(got, "\n\n_Root cause changed since incident incident-2; confidence LOW → HIGH (+2)_") {
+		t.Errorf("root cause section = %q, want the changes since the prior investigation", got)
+	}
+}
+
+func TestSendIncidentNotification_LocalizedLabels(t *testing.T) {
+	var received SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode slack payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := NewSlackNotifier(server.URL, defaultTestTuning())
+	notifier.SetLanguage("German")
+	summary := &IncidentSummary{
+		IncidentID: "incident-1",
+		Cluster:    "prod",
+		Namespace:  "default",
+		Resource:   "Pod/web",
+		Reason:     "CrashLoopBackOff",
+		Status:     "resolved",
+		RootCause:  "Fehlerhafte Konfiguration",
+		Confidence: "HIGH",
+		ReportURL:  "https://example.com/report",
+	}
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+
+	if got := received.Blocks[1].Fields[2].Text; got != "*Ressource:*\nPod/web" {
+		t.Errorf("resource field = %q", got)
+	}
+	if got := received.Blocks[2].Text.Text; got != "*Ursache (Konfidenz: HIGH):*\nFehlerhafte Konfiguration" {
+		t.Errorf("root cause = %q", got)
+	}
+
+	// Languages without a translation fall back to English
+	notifier.SetLanguage("Klingon")
+	if err := notifier.SendIncidentNotification(summary); err != nil {
+		t.Fatalf("SendIncidentNotification() error = %v", err)
+	}
+	if got := received.Blocks[2].Text.Text; got != "*Root Cause (HIGH confidence):*\nFehlerhafte Konfiguration" {
+		t.Errorf("fallback root cause = %q", got)
+	}
+}
@@ -2,6 +2,7 @@ package reporting
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -301,3 +302,28 @@ func TestFailureReasonsDisplayCount(t *testing.T) {
 		})
 	}
 }
+
+func TestNotifiersFollowTuningStore(t *testing.T) {
+	tuning := config.DefaultTuning()
+	store := config.NewTuningStore("", tuning)
+
+	slack := NewSlackNotifier("https://hooks.slack.com/test", tuning)
+	slack.SetTuningStore(store)
+	cb := NewCircuitBreaker(3, tuning)
+	cb.SetTuningStore(store)
+	for i := 0; i < 5; i++ {
+		cb.RecordFailure(fmt.Sprintf("failure %d", i))
+	}
+
+	if _, err := store.Patch([]byte(`{"reporting": {"root_cause_truncation_length": 10, "failure_reasons_display_count": 1, "max_failure_reasons_tracked": 2}}`)); err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+
+	if got := slack.TruncateRootCause("a root cause longer than ten characters"); got != "a root ..." {
+		t.Errorf("TruncateRootCause() = %q, want the runtime truncation length applied", got)
+	}
+	cb.RecordFailure("failure 5")
+	if reasons := cb.GetStats().RecentReasons; len(reasons) != 2 || reasons[1] != "failure 5" {
+		t.Errorf("tracked reasons = %v, want the 2 most recent", reasons)
+	}
+}
@@ -0,0 +1,205 @@
+package ruletriage
+
+// DefaultRules are the built-in rules, tried after the configured ones. More
+// specific rules of a fault type come before its catch-all rule.
+var DefaultRules = []Rule{
+	// Crash loops
+	{
+		Name:       "crashloop-exec-format",
+		FaultTypes: []string{"CrashLoop"},
+		Match:      `exec format error`,
+		RootCause:  "The container's entrypoint cannot be executed on the node: the image was built for a different CPU architecture than the node's.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Compare the image's platform (docker manifest inspect) with the node's architecture",
+			"Build a multi-arch image or pin the pod to nodes of the image's architecture",
+		},
+	},
+	{
+		Name:       "crashloop-liveness-probe",
+		FaultTypes: []string{"CrashLoop"},
+		Match:      `liveness probe failed`,
+		RootCause:  "The kubelet restarts the container because its liveness probe fails: the application is not answering the probe in time, or the probe is misconfigured.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Check that the probe's port and path match the application",
+			"Raise initialDelaySeconds or add a startupProbe if the application starts slowly",
+		},
+	},
+	{
+		Name:       "crashloop-missing-config",
+		FaultTypes: []string{"CrashLoop"},
+		Match:      `no such file or directory|config(uration)? file.*not found|missing required (env|environment|config)`,
+		RootCause:  "The application exits at startup because a file, setting, or environment variable it requires is missing.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Check the mounted ConfigMaps and Secrets and the container's environment against what the application expects",
+			"Compare with the last working revision (kubectl rollout history)",
+		},
+	},
+	{
+		Name:       "crashloop-connection-refused",
+		FaultTypes: []string{"CrashLoop"},
+		Match:      `connection refused|no such host|i/o timeout|could not connect|dial tcp`,
+		RootCause:  "The application exits because it cannot reach a dependency (database, API, or service) at startup.",
+		Confidence: ConfidenceLow,
+		Remediation: []string{
+			"Check that the dependency named in the logs is running and its Service has endpoints",
+			"Check NetworkPolicies and DNS resolution from the pod's namespace",
+		},
+	},
+	{
+		Name:       "crashloop-application-error",
+		FaultTypes: []string{"CrashLoop"},
+		Match:      `panic:|fatal|traceback|exception|segmentation fault`,
+		RootCause:  "The application crashes with an error at runtime; see the log lines quoted as evidence.",
+		Confidence: ConfidenceLow,
+		Remediation: []string{
+			"Read the full log of the previous container (kubectl logs --previous)",
+			"Roll back if the crash started with a new revision",
+		},
+	},
+	{
+		Name:       "crashloop",
+		FaultTypes: []string{"CrashLoop"},
+		RootCause:  "The container keeps exiting and is restarted with back-off; its logs and events did not show why.",
+		Confidence: ConfidenceLow,
+		Remediation: []string{
+			"Read the log of the previous container (kubectl logs --previous) and its exit code (kubectl describe pod)",
+		},
+	},
+
+	// Memory
+	{
+		Name:       "oomkilled",
+		FaultTypes: []string{"OOMKill", "OOM"},
+		RootCause:  "The container was killed for exceeding its memory limit (OOMKilled): the limit is below the application's working set, or the application leaks memory.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Compare the container's memory usage (kubectl top pod) with its limit",
+			"Raise the memory limit, or look for a leak if usage grows until every kill",
+		},
+	},
+
+	// Images
+	{
+		Name:       "image-not-found",
+		FaultTypes: []string{"ImagePull", "ErrImage", "InvalidImage"},
+		Match:      `not found|manifest unknown|does not exist|invalid reference format`,
+		RootCause:  "The image or its tag does not exist in the registry.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Check the image name and tag in the pod spec for typos",
+			"Check that the tag was pushed (the build may have failed)",
+		},
+	},
+	{
+		Name:       "image-pull-unauthorized",
+		FaultTypes: []string{"ImagePull", "ErrImage"},
+		Match:      `unauthorized|denied|authentication required|forbidden|401|403`,
+		RootCause:  "The registry refuses the pull: the pod has no valid pull credentials for the image.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Check the pod's imagePullSecrets and the service account's pull secrets",
+			"Check that the pull secret's credentials have not expired",
+		},
+	},
+	{
+		Name:       "image-pull",
+		FaultTypes: []string{"ImagePull", "ErrImage"},
+		RootCause:  "The image cannot be pulled; the registry may be unreachable or rate limiting the node.",
+		Confidence: ConfidenceLow,
+		Remediation: []string{
+			"Check the pull error in the pod's events (kubectl describe pod)",
+			"Check the node's access to the registry",
+		},
+	},
+
+	// Container configuration
+	{
+		Name:       "missing-config-reference",
+		FaultTypes: []string{"CreateContainerConfig", "ContainerConfig"},
+		Match:      `(secret|configmap)s? .*not found|couldn't find key`,
+		RootCause:  "The container cannot be created because a Secret or ConfigMap (or a key in it) it references does not exist.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Create the missing Secret or ConfigMap in the pod's namespace, or fix the reference",
+		},
+	},
+
+	// Scheduling
+	{
+		Name:       "unschedulable-resources",
+		FaultTypes: []string{"Schedul", "Pending"},
+		Match:      `insufficient (cpu|memory|ephemeral-storage|nvidia|pods)`,
+		RootCause:  "The pod cannot be scheduled: no node has enough free resources for its requests.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Lower the pod's resource requests or add capacity (scale the node pool)",
+			"Check the cluster autoscaler's events if it should have added a node",
+		},
+	},
+	{
+		Name:       "unschedulable-volume",
+		FaultTypes: []string{"Schedul", "Pending"},
+		Match:      `unbound.*persistentvolumeclaim|volume node affinity conflict|waiting for first consumer`,
+		RootCause:  "The pod cannot be scheduled because its PersistentVolumeClaim is not bound or its volume is tied to another zone.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Check the PersistentVolumeClaim's status and storage class (kubectl describe pvc)",
+		},
+	},
+	{
+		Name:       "unschedulable-placement",
+		FaultTypes: []string{"Schedul", "Pending"},
+		Match:      `node affinity|node selector|untolerated taint|had taint`,
+		RootCause:  "The pod cannot be scheduled: no node satisfies its node selector or affinity, or the matching nodes carry taints it does not tolerate.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Compare the pod's nodeSelector, affinity, and tolerations with the nodes' labels and taints",
+		},
+	},
+
+	// Eviction and nodes
+	{
+		Name:       "evicted-disk-pressure",
+		FaultTypes: []string{"Evict"},
+		Match:      `ephemeral-storage|diskpressure|disk pressure|nodefs`,
+		RootCause:  "The pod was evicted because the node ran out of disk (ephemeral storage).",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Set ephemeral-storage requests and limits, and check what the pod writes to its filesystem",
+			"Check the node's disk usage (images, logs)",
+		},
+	},
+	{
+		Name:       "evicted-memory-pressure",
+		FaultTypes: []string{"Evict"},
+		Match:      `memory`,
+		RootCause:  "The pod was evicted because the node ran low on memory.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Set memory requests close to actual usage so the scheduler does not overcommit the node",
+		},
+	},
+	{
+		Name:       "node-not-ready",
+		FaultTypes: []string{"Node"},
+		RootCause:  "The node stopped reporting Ready: the kubelet is down or unreachable, or the node is under resource pressure.",
+		Confidence: ConfidenceLow,
+		Remediation: []string{
+			"Check the node's conditions (kubectl describe node) and the kubelet's logs",
+		},
+	},
+
+	// Probes
+	{
+		Name:       "readiness-probe",
+		Match:      `readiness probe failed`,
+		RootCause:  "The pod is not ready because its readiness probe fails, so it receives no traffic.",
+		Confidence: ConfidenceMedium,
+		Remediation: []string{
+			"Check that the probe's port and path match the application and what the probe returns",
+		},
+	},
+}
@@ -0,0 +1,250 @@
+// Package ruletriage is a deterministic triage engine used when no agent can run
+// (no usable LLM API key, the budget is exhausted, or the circuit breaker is
+// open). It matches the fault type and the fault's context (its description, the
+// object's recent events, and the pod's log tail) against rules and writes a basic
+// investigation report in the same format as the agents, so that the incident is
+// still recorded, stored, and notified with a probable cause and next steps.
+package ruletriage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Confidence levels of rule-based findings. A rule never claims HIGH confidence:
+// it has not examined the cluster.
+const (
+	ConfidenceMedium = "MEDIUM"
+	ConfidenceLow    = "LOW"
+)
+
+// maxEvidence bounds the context lines quoted as evidence for a finding
+const maxEvidence = 5
+
+// Rule maps a fault to a probable root cause.
+type Rule struct {
+	// Name identifies the rule in reports and logs
+	Name string `mapstructure:"name"`
+	// FaultTypes limits the rule to faults whose type contains one of these,
+	// case-insensitively (e.g. "CrashLoop" matches CrashLoopBackOff). Empty
+	// matches every fault type.
+	FaultTypes []string `mapstructure:"fault_types"`
+	// Match is a regular expression, matched case-insensitively against each line
+	// of the fault description, events, and logs. Empty matches every fault of
+	// the rule's types.
+	Match string `mapstructure:"match"`
+	// RootCause is the probable root cause reported when the rule matches
+	RootCause string `mapstructure:"root_cause"`
+	// Confidence is MEDIUM or LOW. Default: LOW.
+	Confidence string `mapstructure:"confidence"`
+	// Remediation lists suggested next steps
+	Remediation []string `mapstructure:"remediation"`
+}
+
+// Validate checks the rule's pattern and confidence.
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule name is required")
+	}
+	if r.RootCause == "" {
+		return fmt.Errorf("rule %s: root_cause is required", r.Name)
+	}
+	if _, err := regexp.Compile("(?i)" + r.Match); err != nil {
+		return fmt.Errorf("rule %s: invalid match pattern: %w", r.Name, err)
+	}
+	switch strings.ToUpper(r.Confidence) {
+	case "", ConfidenceLow, ConfidenceMedium:
+	default:
+		return fmt.Errorf("rule %s: confidence must be LOW or MEDIUM, got %q", r.Name, r.Confidence)
+	}
+	return nil
+}
+
+// Input is the fault and the context collected about it.
+type Input struct {
+	Cluster   string
+	Namespace string
+	Kind      string
+	Name      string
+	FaultType string
+	Severity  string
+	// Context is the fault event's description
+	Context string
+	// Events are the object's recent events, one per line, oldest first
+	Events []string
+	// Logs is the tail of the pod's log
+	Logs string
+}
+
+// lines returns every line of context, prefixed with its source.
+func (in Input) lines() []string {
+	var lines []string
+	for _, line := range strings.Split(in.Context, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, "fault: "+strings.TrimSpace(line))
+		}
+	}
+	for _, ev := range in.Events {
+		lines = append(lines, "event: "+ev)
+	}
+	for _, line := range strings.Split(in.Logs, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, "log: "+strings.TrimSpace(line))
+		}
+	}
+	return lines
+}
+
+// Finding is the outcome of rule-based triage.
+type Finding struct {
+	// Rule is the name of the matched rule, empty when none matched
+	Rule        string
+	RootCause   string
+	Confidence  string
+	Remediation []string
+	// Evidence are the context lines the rule matched
+	Evidence []string
+}
+
+// compiledRule is a rule with its compiled pattern.
+type compiledRule struct {
+	Rule
+	pattern *regexp.Regexp
+}
+
+// Engine matches faults against rules. It is safe for concurrent use.
+type Engine struct {
+	rules []compiledRule
+}
+
+// New returns an engine trying the given rules first, then the built-in rules.
+func New(rules []Rule) (*Engine, error) {
+	e := &Engine{}
+	for _, r := range append(append([]Rule(nil), rules...), DefaultRules...) {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+		e.rules = append(e.rules, compiledRule{Rule: r, pattern: regexp.MustCompile("(?i)" + r.Match)})
+	}
+	return e, nil
+}
+
+// Triage returns the finding of the first rule matching the fault, or a generic
+// LOW confidence finding when none does.
+func (e *Engine) Triage(in Input) Finding {
+	lines := in.lines()
+	for _, r := range e.rules {
+		if !matchesFaultType(r.FaultTypes, in.FaultType) {
+			continue
+		}
+		var evidence []string
+		for _, line := range lines {
+			if r.Match != "" && r.pattern.MatchString(line) && len(evidence) < maxEvidence {
+				evidence = append(evidence, line)
+			}
+		}
+		if r.Match != "" && len(evidence) == 0 {
+			continue
+		}
+		confidence := strings.ToUpper(r.Confidence)
+		if confidence == "" {
+			confidence = ConfidenceLow
+		}
+		return Finding{
+			Rule:        r.Name,
+			RootCause:   r.RootCause,
+			Confidence:  confidence,
+			Remediation: r.Remediation,
+			Evidence:    evidence,
+		}
+	}
+	return Finding{
+		RootCause:  fmt.Sprintf("No triage rule matched this %s fault; the cause could not be determined without an investigation.", in.FaultType),
+		Confidence: ConfidenceLow,
+		Remediation: []string{
+			"Review the events and logs below",
+			"Re-run the investigation once an agent is available",
+		},
+	}
+}
+
+func matchesFaultType(faultTypes []string, faultType string) bool {
+	if len(faultTypes) == 0 {
+		return true
+	}
+	faultType = strings.ToLower(faultType)
+	for _, t := range faultTypes {
+		if strings.Contains(faultType, strings.ToLower(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Report renders the investigation report of a finding. reason says why no agent
+// investigated the fault, e.g. "budget exhausted".
+func (f Finding) Report(in Input, reason string, generatedAt time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Incident Investigation: %s %s/%s\n\n", in.FaultType, in.Kind, in.Name)
+	fmt.Fprintf(&b, "> Rule-based triage: no agent investigated this fault (%s). ", reason)
+	b.WriteString("The findings below come from deterministic rules, not from examining the cluster.\n\n")
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "- **Cluster:** %s\n", in.Cluster)
+	if in.Namespace != "" {
+		fmt.Fprintf(&b, "- **Namespace:** %s\n", in.Namespace)
+	}
+	fmt.Fprintf(&b, "- **Resource:** %s/%s\n", in.Kind, in.Name)
+	fmt.Fprintf(&b, "- **Fault:** %s (%s)\n", in.FaultType, in.Severity)
+	rule := f.Rule
+	if rule == "" {
+		rule = "none"
+	}
+	fmt.Fprintf(&b, "- **Triage rule:** %s\n", rule)
+	fmt.Fprintf(&b, "- **Generated:** %s\n\n", generatedAt.UTC().Format(time.RFC3339))
+
+	b.WriteString("## Root Cause\n\n")
+	b.WriteString(f.RootCause + "\n\n")
+	fmt.Fprintf(&b, "**Confidence:** %s\n\n", f.Confidence)
+
+	if len(f.Evidence) > 0 {
+		b.WriteString("## Evidence\n\n")
+		for _, line := range f.Evidence {
+			fmt.Fprintf(&b, "- `%s`\n", strings.ReplaceAll(line, "`", "'"))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(f.Remediation) > 0 {
+		b.WriteString("## Recommended Next Steps\n\n")
+		for i, step := range f.Remediation {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Context\n\n")
+	if in.Context != "" {
+		b.WriteString("### Fault\n\n")
+		b.WriteString(fence(in.Context))
+	}
+	if len(in.Events) > 0 {
+		b.WriteString("### Events\n\n")
+		b.WriteString(fence(strings.Join(in.Events, "\n")))
+	}
+	if in.Logs != "" {
+		b.WriteString("### Logs\n\n")
+		b.WriteString(fence(in.Logs))
+	}
+	if in.Context == "" && len(in.Events) == 0 && in.Logs == "" {
+		b.WriteString("No context was available.\n")
+	}
+	return b.String()
+}
+
+// fence returns text as a fenced code block.
+func fence(text string) string {
+	return "```\n" + strings.ReplaceAll(strings.TrimRight(text, "\n"), "```", "'''") + "\n```\n\n"
+}
@@ -0,0 +1,95 @@
+package ruletriage
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTriage(t *testing.T) {
+	engine, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		input      Input
+		rule       string
+		confidence string
+	}{
+		{"oom", Input{FaultType: "OOMKilled"}, "oomkilled", ConfidenceMedium},
+		{"crash loop with a panic", Input{
+			FaultType: "CrashLoopBackOff",
+			Logs:      "starting server\npanic: runtime error: invalid memory address",
+		}, "crashloop-application-error", ConfidenceLow},
+		{"crash loop without context", Input{FaultType: "CrashLoop"}, "crashloop", ConfidenceLow},
+		{"image tag missing", Input{
+			FaultType: "ImagePull",
+			Events:    []string{`Warning Failed: Failed to pull image "web:1.2": manifest unknown`},
+		}, "image-not-found", ConfidenceMedium},
+		{"insufficient memory", Input{
+			FaultType: "FailedScheduling",
+			Context:   "0/3 nodes are available: 3 Insufficient memory.",
+		}, "unschedulable-resources", ConfidenceMedium},
+		{"unknown fault", Input{FaultType: "Mystery"}, "", ConfidenceLow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding := engine.Triage(tt.input)
+			if finding.Rule != tt.rule || finding.Confidence != tt.confidence {
+				t.Errorf("Triage() = rule %q %s, want %q %s", finding.Rule, finding.Confidence, tt.rule, tt.confidence)
+			}
+		})
+	}
+}
+
+func TestTriage_ConfiguredRulesFirst(t *testing.T) {
+	engine, err := New([]Rule{{
+		Name:       "payments-db",
+		FaultTypes: []string{"CrashLoop"},
+		Match:      `pq: password authentication failed`,
+		RootCause:  "The payments database credentials were rotated.",
+		Confidence: "medium",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	finding := engine.Triage(Input{FaultType: "CrashLoopBackOff", Logs: "fatal: pq: password authentication failed for user app"})
+	if finding.Rule != "payments-db" || finding.Confidence != ConfidenceMedium || len(finding.Evidence) != 1 {
+		t.Errorf("Triage() = %+v, want the configured rule", finding)
+	}
+
+	if _, err := New([]Rule{{Name: "bad", RootCause: "x", Match: "("}}); err == nil {
+		t.Error("New() with an invalid pattern should fail")
+	}
+	if _, err := New([]Rule{{Name: "sure", RootCause: "x", Confidence: "HIGH"}}); err == nil {
+		t.Error("New() with HIGH confidence should fail")
+	}
+}
+
+func TestReport(t *testing.T) {
+	engine, _ := New(nil)
+	input := Input{
+		Cluster:   "prod",
+		Namespace: "web",
+		Kind:      "Pod",
+		Name:      "web-7d9f",
+		FaultType: "OOMKilled",
+		Severity:  "ERROR",
+		Context:   "Container web was OOMKilled",
+		Events:    []string{"2026-10-16T10:00:00Z Warning BackOff: Back-off restarting failed container"},
+	}
+	report := engine.Triage(input).Report(input, "budget exhausted", time.Date(2026, 10, 16, 10, 5, 0, 0, time.UTC))
+	for _, want := range []string{
+		"no agent investigated this fault (budget exhausted)",
+		"- **Triage rule:** oomkilled",
+		"## Root Cause\n\nThe container was killed for exceeding its memory limit",
+		"**Confidence:** MEDIUM",
+		"## Recommended Next Steps",
+		"### Events",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Report() missing %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Package runbooks attaches organization-specific markdown runbooks to agent
+// investigations. A registry maps fault types and resource kinds to runbooks in a
+// local directory (or a git checkout of one). Matching runbooks are copied into the
+// incident workspace and listed in the agent prompt so the agent follows the
+// organization's procedure for known failure modes.
+package runbooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/match"
+)
+
+// WorkspaceDir is the workspace subdirectory runbooks are copied into. It is
+// mounted into the agent container at the same relative path.
+const WorkspaceDir = "runbooks"
+
+// Rule maps fault types and resource kinds to a runbook. A rule matches when the
+// fault type is in FaultTypes (or FaultTypes is empty) and the resource kind is in
+// Kinds (or Kinds is empty). Comparisons are case-insensitive.
+type Rule struct {
+	FaultTypes []string
+	Kinds      []string
+
+	// Runbook is the markdown file, relative to the registry directory
+	Runbook string
+}
+
+// Validate checks that the rule selects something and names a safe relative path.
+func (r Rule) Validate() error {
+	if len(r.FaultTypes) == 0 && len(r.Kinds) == 0 {
+		return fmt.Errorf("runbook rule for %q must set fault_types or kinds", r.Runbook)
+	}
+	return validatePath(r.Runbook)
+}
+
+// matches reports whether the rule applies to a fault type and resource kind.
+func (r Rule) matches(faultType, kind string) bool {
+	return match.Any(r.FaultTypes, faultType) && match.Any(r.Kinds, kind)
+}
+
+// validatePath checks that a runbook path is a relative markdown path that stays
+// inside the registry directory.
+func validatePath(path string) error {
+	if path == "" {
+		return fmt.Errorf("runbook path is required")
+	}
+	clean := filepath.Clean(filepath.FromSlash(path))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("runbook path %q must be relative to the runbooks directory", path)
+	}
+	if !strings.EqualFold(filepath.Ext(clean), ".md") {
+		return fmt.Errorf("runbook path %q must be a markdown (.md) file", path)
+	}
+	return nil
+}
+
+// Registry finds the runbooks for a fault. Besides the explicit rules, a markdown
+// file at the top of the directory named after the fault type or resource kind
+// (e.g. "CrashLoopBackOff.md" or "statefulset.md") matches by convention.
+type Registry struct {
+	dir   string
+	rules []Rule
+}
+
+// NewRegistry creates a registry for the runbooks in dir.
+func NewRegistry(dir string, rules []Rule) *Registry {
+	return &Registry{dir: dir, rules: rules}
+}
+
+// Match returns the runbooks for a fault type and resource kind, relative to the
+// registry directory: rule matches first, in rule order, followed by convention
+// matches. Runbooks that do not exist are skipped.
+func (r *Registry) Match(faultType, kind string) []string {
+	var matches []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		key := strings.ToLower(filepath.Clean(path))
+		if seen[key] {
+			return
+		}
+		if info, err := os.Stat(filepath.Join(r.dir, path)); err != nil || !info.Mode().IsRegular() {
+			return
+		}
+		seen[key] = true
+		matches = append(matches, path)
+	}
+
+	for _, rule := range r.rules {
+		if rule.matches(faultType, kind) {
+			add(rule.Runbook)
+		}
+	}
+
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return matches
+	}
+	for _, name := range []string{faultType, kind} {
+		if name == "" {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() && strings.EqualFold(e.Name(), name+".md") {
+				add(e.Name())
+			}
+		}
+	}
+	return matches
+}
+
+// Attach copies the runbooks for a fault into the workspace's runbooks directory
+// and returns their workspace-relative paths. Runbooks in subdirectories keep only
+// their file name; a later runbook with the same name is prefixed with its index.
+func (r *Registry) Attach(workspacePath, faultType, kind string) ([]string, error) {
+	matches := r.Match(faultType, kind)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	dest := filepath.Join(workspacePath, WorkspaceDir)
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create runbooks directory: %w", err)
+	}
+
+	var attached []string
+	used := make(map[string]bool)
+	for i, path := range matches {
+		data, err := os.ReadFile(filepath.Join(r.dir, path))
+		if err != nil {
+			return attached, fmt.Errorf("failed to read runbook %s: %w", path, err)
+		}
+		name := filepath.Base(path)
+		if used[strings.ToLower(name)] {
+			name = fmt.Sprintf("%d-%s", i+1, name)
+		}
+		used[strings.ToLower(name)] = true
+		if err := os.WriteFile(filepath.Join(dest, name), data, 0644); err != nil {
+			return attached, fmt.Errorf("failed to write runbook %s: %w", name, err)
+		}
+		attached = append(attached, filepath.ToSlash(filepath.Join(WorkspaceDir, name)))
+	}
+	return attached, nil
+}
+
+// PromptSection returns the agent prompt section referencing attached runbooks,
+// or "" when there are none.
+func PromptSection(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## Runbooks\n\n")
+	b.WriteString("Your organization has runbooks for this failure mode. Read them before investigating ")
+	b.WriteString("and follow their procedures; note in the report where your findings deviate from them.\n\n")
+	for _, p := range paths {
+		fmt.Fprintf(&b, "- %s\n", p)
+	}
+	return b.String()
+}
@@ -0,0 +1,123 @@
+package runbooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeRunbooks creates runbook files under a temporary directory.
+func writeRunbooks(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRegistry_Match(t *testing.T) {
+	dir := writeRunbooks(t, map[string]string{
+		"CrashLoopBackOff.md":    "# Crash loops",
+		"storage/pvc-pending.md": "# PVC pending",
+		"databases/postgres.md":  "# Postgres",
+		"statefulset.md":         "# StatefulSets",
+		"notes.txt":              "not a runbook",
+	})
+	r := NewRegistry(dir, []Rule{
+		{FaultTypes: []string{"FailedScheduling", "ProvisioningFailed"}, Runbook: "storage/pvc-pending.md"},
+		{FaultTypes: []string{"crashloopbackoff"}, Kinds: []string{"StatefulSet"}, Runbook: "databases/postgres.md"},
+		{FaultTypes: []string{"OOMKilled"}, Runbook: "missing.md"},
+	})
+
+	tests := []struct {
+		name      string
+		faultType string
+		kind      string
+		want      string
+	}{
+		{"rule by fault type", "FailedScheduling", "Pod", "storage/pvc-pending.md"},
+		{"rule and conventions", "CrashLoopBackOff", "StatefulSet", "databases/postgres.md,CrashLoopBackOff.md,statefulset.md"},
+		{"convention only", "CrashLoopBackOff", "Deployment", "CrashLoopBackOff.md"},
+		{"missing runbook skipped", "OOMKilled", "Pod", ""},
+		{"no match", "ImagePullBackOff", "Pod", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(r.Match(tt.faultType, tt.kind), ","); got != tt.want {
+				t.Errorf("Match() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_Attach(t *testing.T) {
+	dir := writeRunbooks(t, map[string]string{
+		"CrashLoopBackOff.md":  "# Crash loops",
+		"teams/a/crashloop.md": "# Team A",
+		"teams/b/crashloop.md": "# Team B",
+	})
+	r := NewRegistry(dir, []Rule{
+		{FaultTypes: []string{"CrashLoopBackOff"}, Runbook: "teams/a/crashloop.md"},
+		{FaultTypes: []string{"CrashLoopBackOff"}, Runbook: "teams/b/crashloop.md"},
+	})
+	workspace := t.TempDir()
+
+	attached, err := r.Attach(workspace, "CrashLoopBackOff", "Pod")
+	if err != nil {
+		t.Fatalf("Attach() error = %v", err)
+	}
+	want := "runbooks/crashloop.md,runbooks/2-crashloop.md,runbooks/CrashLoopBackOff.md"
+	if got := strings.Join(attached, ","); got != want {
+		t.Fatalf("Attach() = %s, want %s", got, want)
+	}
+	data, err := os.ReadFile(filepath.Join(workspace, "runbooks", "2-crashloop.md"))
+	if err != nil || string(data) != "# Team B" {
+		t.Errorf("2-crashloop.md = %q, %v", data, err)
+	}
+
+	section := PromptSection(attached)
+	if !strings.HasPrefix(section, "## Runbooks") || !strings.Contains(section, "- runbooks/CrashLoopBackOff.md") {
+		t.Errorf("PromptSection() = %q", section)
+	}
+
+	// Nothing is written when no runbook matches
+	empty := t.TempDir()
+	if attached, err := r.Attach(empty, "ImagePullBackOff", "Pod"); err != nil || attached != nil {
+		t.Errorf("Attach() = %v, %v, want nothing", attached, err)
+	}
+	if _, err := os.Stat(filepath.Join(empty, WorkspaceDir)); !os.IsNotExist(err) {
+		t.Error("runbooks directory should not be created without matches")
+	}
+	if PromptSection(nil) != "" {
+		t.Error("PromptSection(nil) should be empty")
+	}
+}
+
+func TestRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{"valid", Rule{FaultTypes: []string{"OOMKilled"}, Runbook: "memory/oom.md"}, false},
+		{"no selector", Rule{Runbook: "oom.md"}, true},
+		{"escapes directory", Rule{Kinds: []string{"Pod"}, Runbook: "../secrets.md"}, true},
+		{"absolute", Rule{Kinds: []string{"Pod"}, Runbook: "/etc/passwd.md"}, true},
+		{"not markdown", Rule{Kinds: []string{"Pod"}, Runbook: "pod.sh"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
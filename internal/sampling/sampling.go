@@ -0,0 +1,135 @@
+// Package sampling investigates only a fraction of the occurrences of known-noisy
+// faults. Rules select faults by fault type and namespace and give a rate N: of
+// the occurrences of each fault signature matching a rule, the first and then
+// every Nth is investigated, while the others are only recorded. Signatures that
+// stay quiet for longer than the reset period start over, so the first occurrence
+// after a quiet spell is always investigated.
+package sampling
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/match"
+)
+
+// sweepInterval is how often signatures that have gone quiet are forgotten
+const sweepInterval = time.Minute
+
+// Rule samples the faults it matches.
+type Rule struct {
+	// FaultTypes and Namespaces select the faults the rule applies to. An empty
+	// list matches any value.
+	FaultTypes []string
+	Namespaces []string
+
+	// Rate investigates one in Rate occurrences per fault signature
+	Rate int
+}
+
+// Validate checks the rule's rate.
+func (r Rule) Validate() error {
+	if r.Rate < 1 {
+		return fmt.Errorf("sampling rate must be at least 1, got %d", r.Rate)
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to a fault.
+func (r Rule) Matches(faultType, namespace string) bool {
+	return match.Any(r.FaultTypes, faultType) && match.Any(r.Namespaces, namespace)
+}
+
+// Decision is the outcome of sampling one occurrence of a fault.
+type Decision struct {
+	// Investigate is true when the occurrence should be investigated
+	Investigate bool
+	// Sampled is true when a rule matched the fault
+	Sampled bool
+	// Rate is the matching rule's rate
+	Rate int
+	// Occurrence counts the signature's occurrences since it was first seen or
+	// last reset, starting at 1
+	Occurrence int
+}
+
+// signatureState tracks one fault signature.
+type signatureState struct {
+	occurrences int
+	lastSeen    time.Time
+}
+
+// Sampler decides which occurrences of sampled faults are investigated. It is safe
+// for concurrent use.
+type Sampler struct {
+	rules      []Rule
+	resetAfter time.Duration
+
+	mu         sync.Mutex
+	signatures map[string]*signatureState
+	lastSweep  time.Time
+
+	// now is replaceable for tests
+	now func() time.Time
+}
+
+// New creates a sampler applying the first matching rule to each fault. Counts of
+// signatures quiet for resetAfter are forgotten.
+func New(rules []Rule, resetAfter time.Duration) *Sampler {
+	return &Sampler{
+		rules:      rules,
+		resetAfter: resetAfter,
+		signatures: make(map[string]*signatureState),
+		now:        time.Now,
+	}
+}
+
+// Sample records an occurrence of the fault signature and decides whether it is
+// investigated. Faults no rule matches are always investigated.
+func (s *Sampler) Sample(signature, faultType, namespace string) Decision {
+	var rule *Rule
+	for i := range s.rules {
+		if s.rules[i].Matches(faultType, namespace) {
+			rule = &s.rules[i]
+			break
+		}
+	}
+	if rule == nil {
+		return Decision{Investigate: true}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	state, ok := s.signatures[signature]
+	if !ok || now.Sub(state.lastSeen) >= s.resetAfter {
+		state = &signatureState{}
+		s.signatures[signature] = state
+	}
+	state.occurrences++
+	state.lastSeen = now
+
+	return Decision{
+		Investigate: (state.occurrences-1)%rule.Rate == 0,
+		Sampled:     true,
+		Rate:        rule.Rate,
+		Occurrence:  state.occurrences,
+	}
+}
+
+// sweep forgets signatures that have been quiet for longer than the reset period.
+func (s *Sampler) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for signature, state := range s.signatures {
+		if now.Sub(state.lastSeen) >= s.resetAfter {
+			delete(s.signatures, signature)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package sampling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSampler(t *testing.T) {
+	s := New([]Rule{
+		{FaultTypes: []string{"BackOff"}, Namespaces: []string{"batch"}, Rate: 3},
+		{FaultTypes: []string{"BackOff"}, Rate: 1},
+	}, time.Hour)
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	var investigated []int
+	for i := 1; i <= 7; i++ {
+		d := s.Sample("sig-a", "backoff", "batch")
+		if !d.Sampled || d.Rate != 3 || d.Occurrence != i {
+			t.Fatalf("Sample() = %+v, want occurrence %d sampled at rate 3", d, i)
+		}
+		if d.Investigate {
+			investigated = append(investigated, i)
+		}
+	}
+	if len(investigated) != 3 || investigated[0] != 1 || investigated[1] != 4 || investigated[2] != 7 {
+		t.Errorf("investigated occurrences = %v, want 1, 4, 7", investigated)
+	}
+
+	// Signatures are counted separately
+	if d := s.Sample("sig-b", "BackOff", "batch"); !d.Investigate || d.Occurrence != 1 {
+		t.Errorf("first occurrence of another signature = %+v, want investigated", d)
+	}
+
+	// The first matching rule applies; unmatched faults are always investigated
+	if d := s.Sample("sig-c", "BackOff", "web"); !d.Investigate || d.Rate != 1 {
+		t.Errorf("Sample() in another namespace = %+v, want the rate 1 rule", d)
+	}
+	if d := s.Sample("sig-d", "OOMKilled", "batch"); !d.Investigate || d.Sampled {
+		t.Errorf("Sample() of an unmatched fault = %+v, want investigated without sampling", d)
+	}
+
+	// A quiet signature starts over
+	s.Sample("sig-a", "BackOff", "batch")
+	now = now.Add(2 * time.Hour)
+	if d := s.Sample("sig-a", "BackOff", "batch"); !d.Investigate || d.Occurrence != 1 {
+		t.Errorf("Sample() after a quiet period = %+v, want the count reset", d)
+	}
+	if len(s.signatures) != 1 {
+		t.Errorf("tracked signatures = %d, want quiet ones swept", len(s.signatures))
+	}
+}
@@ -0,0 +1,257 @@
+// Package searchindex indexes completed incidents into OpenSearch or
+// Elasticsearch, so the organization's search and Kibana or OpenSearch Dashboards
+// can include triage results. Each incident is one document (metadata, findings,
+// and report text) keyed by incident ID, so re-indexing an incident replaces its
+// document. Documents are written to monthly indices created from a versioned
+// index template: when the mapping changes, TemplateVersion is bumped and new
+// documents go to new indices, while dashboards keep querying "<prefix>-*".
+package searchindex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TemplateVersion is the version of the index template and document mapping.
+const TemplateVersion = 1
+
+// DefaultIndexPrefix is the prefix of the index names.
+const DefaultIndexPrefix = "nightcrier-incidents"
+
+// Config configures the search index client.
+type Config struct {
+	// URL is the cluster base URL, e.g. "https://search.example.com:9200"
+	URL string
+
+	// APIKey authenticates with an Elasticsearch API key (the base64 encoded
+	// "id:key"); otherwise Username and Password authenticate with basic auth
+	APIKey   string
+	Username string
+	Password string
+
+	// IndexPrefix is the prefix of the index names and template (default:
+	// nightcrier-incidents)
+	IndexPrefix string
+}
+
+// Document is the indexed form of a completed incident.
+type Document struct {
+	IncidentID       string `json:"incident_id"`
+	DisplayID        string `json:"display_id,omitempty"`
+	ParentIncidentID string `json:"parent_incident_id,omitempty"`
+	FaultSignature   string `json:"fault_signature,omitempty"`
+	Source           string `json:"source,omitempty"`
+
+	Cluster      string `json:"cluster"`
+	Namespace    string `json:"namespace"`
+	ResourceKind string `json:"resource_kind,omitempty"`
+	ResourceName string `json:"resource_name,omitempty"`
+	FaultType    string `json:"fault_type"`
+	Severity     string `json:"severity"`
+
+	Status         string `json:"status"`
+	FailureClass   string `json:"failure_class,omitempty"`
+	FallbackTriage string `json:"fallback_triage,omitempty"`
+	CachedFrom     string `json:"cached_from,omitempty"`
+	AgentCLI       string `json:"agent_cli,omitempty"`
+	AgentProfile   string `json:"agent_profile,omitempty"`
+
+	OwnerTeam    string            `json:"owner_team,omitempty"`
+	OwnerService string            `json:"owner_service,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	DurationSeconds float64    `json:"duration_seconds,omitempty"`
+
+	// Findings of the investigation report
+	RootCause          string `json:"root_cause,omitempty"`
+	Confidence         string `json:"confidence,omitempty"`
+	ClaimsVerified     int    `json:"claims_verified,omitempty"`
+	ClaimsContradicted int    `json:"claims_contradicted,omitempty"`
+	ClaimsUnverified   int    `json:"claims_unverified,omitempty"`
+
+	ReportURL string `json:"report_url,omitempty"`
+	// Report is the report's markdown text
+	Report string `json:"report,omitempty"`
+
+	// TemplateVersion is the mapping version the document was indexed with
+	TemplateVersion int `json:"template_version"`
+}
+
+// Client indexes incident documents. The index template is installed before the
+// first document is indexed, and again after a failed attempt. It is safe for
+// concurrent use.
+type Client struct {
+	cfg    Config
+	client *http.Client
+
+	mu sync.Mutex
+	// templateReady is set once the index template is installed
+	templateReady bool
+}
+
+// New creates a search index client.
+func New(cfg Config, httpClient *http.Client) *Client {
+	if cfg.IndexPrefix == "" {
+		cfg.IndexPrefix = DefaultIndexPrefix
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{cfg: cfg, client: httpClient}
+}
+
+// TemplateName returns the name of the index template of the current version,
+// e.g. "nightcrier-incidents-v1".
+func (c *Client) TemplateName() string {
+	return fmt.Sprintf("%s-v%d", c.cfg.IndexPrefix, TemplateVersion)
+}
+
+// IndexName returns the index a document completed (or created) at t is written
+// to, e.g. "nightcrier-incidents-v1-2025.03".
+func (c *Client) IndexName(t time.Time) string {
+	return fmt.Sprintf("%s-%s", c.TemplateName(), t.UTC().Format("2006.01"))
+}
+
+// Index writes the document, replacing the incident's earlier document in the
+// same index.
+func (c *Client) Index(ctx context.Context, doc Document) error {
+	if err := c.ensureTemplate(ctx); err != nil {
+		return err
+	}
+
+	doc.TemplateVersion = TemplateVersion
+	at := doc.CreatedAt
+	if doc.CompletedAt != nil {
+		at = *doc.CompletedAt
+	}
+	path := "/" + c.IndexName(at) + "/_doc/" + url.PathEscape(doc.IncidentID)
+	if err := c.do(ctx, http.MethodPut, path, doc); err != nil {
+		return fmt.Errorf("failed to index incident %s: %w", doc.IncidentID, err)
+	}
+	return nil
+}
+
+// ensureTemplate installs the index template unless it already was.
+func (c *Client) ensureTemplate(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.templateReady {
+		return nil
+	}
+	if err := c.do(ctx, http.MethodPut, "/_index_template/"+c.TemplateName(), c.template()); err != nil {
+		return fmt.Errorf("failed to install index template %s: %w", c.TemplateName(), err)
+	}
+	c.templateReady = true
+	return nil
+}
+
+// template returns the composable index template (Elasticsearch 7.8+, OpenSearch
+// 1.0+) of the current version.
+func (c *Client) template() map[string]interface{} {
+	keyword := map[string]interface{}{"type": "keyword"}
+	date := map[string]interface{}{"type": "date"}
+	integer := map[string]interface{}{"type": "integer"}
+	text := map[string]interface{}{"type": "text"}
+
+	return map[string]interface{}{
+		"index_patterns": []string{c.TemplateName() + "-*"},
+		"priority":       100,
+		"version":        TemplateVersion,
+		"_meta":          map[string]interface{}{"managed_by": "nightcrier"},
+		"template": map[string]interface{}{
+			"mappings": map[string]interface{}{
+				"dynamic": false,
+				"dynamic_templates": []interface{}{
+					map[string]interface{}{
+						"labels": map[string]interface{}{
+							"path_match": "labels.*",
+							"mapping":    keyword,
+						},
+					},
+				},
+				"properties": map[string]interface{}{
+					"incident_id":        keyword,
+					"display_id":         keyword,
+					"parent_incident_id": keyword,
+					"fault_signature":    keyword,
+					"source":             keyword,
+					"cluster":            keyword,
+					"namespace":          keyword,
+					"resource_kind":      keyword,
+					"resource_name":      keyword,
+					"fault_type":         keyword,
+					"severity":           keyword,
+					"status":             keyword,
+					"failure_class":      keyword,
+					"fallback_triage":    keyword,
+					"cached_from":        keyword,
+					"agent_cli":          keyword,
+					"agent_profile":      keyword,
+					"owner_team":         keyword,
+					"owner_service":      keyword,
+					"labels":             map[string]interface{}{"type": "object", "dynamic": true},
+					"created_at":         date,
+					"started_at":         date,
+					"completed_at":       date,
+					"duration_seconds":   map[string]interface{}{"type": "double"},
+					"root_cause": map[string]interface{}{
+						"type":   "text",
+						"fields": map[string]interface{}{"keyword": map[string]interface{}{"type": "keyword", "ignore_above": 512}},
+					},
+					"confidence":          keyword,
+					"claims_verified":     integer,
+					"claims_contradicted": integer,
+					"claims_unverified":   integer,
+					"report_url":          map[string]interface{}{"type": "keyword", "index": false},
+					"report":              text,
+					"template_version":    integer,
+				},
+			},
+		},
+	}
+}
+
+// do sends a JSON request to the cluster.
+func (c *Client) do(ctx context.Context, method, path string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	endpoint := strings.TrimSuffix(c.cfg.URL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	switch {
+	case c.cfg.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+c.cfg.APIKey)
+	case c.cfg.Username != "":
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned status %d: %s", method, endpoint, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,140 @@
+package searchindex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCluster records the requests of a search cluster and fails the first
+// failTemplate template installs.
+type fakeCluster struct {
+	mu           sync.Mutex
+	calls        []string
+	bodies       map[string]map[string]interface{}
+	auth         []string
+	failTemplate int
+}
+
+func (f *fakeCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	call := r.Method + " " + r.URL.Path
+	f.calls = append(f.calls, call)
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	f.bodies[call] = body
+
+	if r.URL.Path == "/_index_template/nightcrier-incidents-v1" && f.failTemplate > 0 {
+		f.failTemplate--
+		http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte(`{"acknowledged":true}`))
+}
+
+func testDocument() Document {
+	created := time.Date(2025, 2, 28, 23, 50, 0, 0, time.UTC)
+	completed := time.Date(2025, 3, 1, 0, 5, 0, 0, time.UTC)
+	return Document{
+		IncidentID:   "3f9a1c0d-1111-2222-3333-444455556666",
+		Cluster:      "prod-east",
+		Namespace:    "payments",
+		ResourceKind: "Pod",
+		ResourceName: "api-7d9f",
+		FaultType:    "CrashLoopBackOff",
+		Severity:     "ERROR",
+		Status:       "resolved",
+		Labels:       map[string]string{"team": "payments"},
+		CreatedAt:    created,
+		CompletedAt:  &completed,
+		RootCause:    "Missing DATABASE_URL secret",
+		Confidence:   "HIGH",
+		Report:       "# Investigation\n\nThe pod is missing DATABASE_URL.",
+	}
+}
+
+func TestClient_Index(t *testing.T) {
+	cluster := &fakeCluster{bodies: make(map[string]map[string]interface{})}
+	server := httptest.NewServer(cluster)
+	defer server.Close()
+
+	c := New(Config{URL: server.URL + "/", Username: "nightcrier", Password: "secret"}, server.Client())
+	ctx := context.Background()
+	if err := c.Index(ctx, testDocument()); err != nil {
+		t.Fatalf("Index() error = %v", err)
+	}
+	if err := c.Index(ctx, testDocument()); err != nil {
+		t.Fatalf("second Index() error = %v", err)
+	}
+
+	// The template is installed once, and documents go to the month they completed in
+	wantCalls := []string{
+		"PUT /_index_template/nightcrier-incidents-v1",
+		"PUT /nightcrier-incidents-v1-2025.03/_doc/3f9a1c0d-1111-2222-3333-444455556666",
+		"PUT /nightcrier-incidents-v1-2025.03/_doc/3f9a1c0d-1111-2222-3333-444455556666",
+	}
+	if len(cluster.calls) != len(wantCalls) {
+		t.Fatalf("calls = %v, want %v", cluster.calls, wantCalls)
+	}
+	for i, call := range wantCalls {
+		if cluster.calls[i] != call {
+			t.Errorf("call %d = %q, want %q", i, cluster.calls[i], call)
+		}
+	}
+	if cluster.auth[0] == "" || cluster.auth[0][:6] != "Basic " {
+		t.Errorf("Authorization = %q, want basic auth", cluster.auth[0])
+	}
+
+	template := cluster.bodies[wantCalls[0]]
+	if template["version"] != float64(TemplateVersion) {
+		t.Errorf("template version = %v, want %d", template["version"], TemplateVersion)
+	}
+	if patterns, _ := template["index_patterns"].([]interface{}); len(patterns) != 1 || patterns[0] != "nightcrier-incidents-v1-*" {
+		t.Errorf("template index_patterns = %v", template["index_patterns"])
+	}
+
+	doc := cluster.bodies[wantCalls[1]]
+	if doc["root_cause"] != "Missing DATABASE_URL secret" || doc["template_version"] != float64(TemplateVersion) {
+		t.Errorf("document = %v", doc)
+	}
+	if labels, _ := doc["labels"].(map[string]interface{}); labels["team"] != "payments" {
+		t.Errorf("document labels = %v", doc["labels"])
+	}
+}
+
+func TestClient_IndexRetriesTemplate(t *testing.T) {
+	cluster := &fakeCluster{bodies: make(map[string]map[string]interface{}), failTemplate: 1}
+	server := httptest.NewServer(cluster)
+	defer server.Close()
+
+	c := New(Config{URL: server.URL, APIKey: "aWQ6a2V5"}, server.Client())
+	if err := c.Index(context.Background(), testDocument()); err == nil {
+		t.Fatal("Index() should fail when the template cannot be installed")
+	}
+	if err := c.Index(context.Background(), testDocument()); err != nil {
+		t.Fatalf("Index() after the cluster recovered error = %v", err)
+	}
+	if len(cluster.calls) != 3 {
+		t.Errorf("calls = %v, want a failed and a successful template install, then the document", cluster.calls)
+	}
+	if cluster.auth[0] != "ApiKey aWQ6a2V5" {
+		t.Errorf("Authorization = %q, want the API key", cluster.auth[0])
+	}
+}
+
+func TestClient_IndexName(t *testing.T) {
+	c := New(Config{URL: "http://localhost:9200", IndexPrefix: "triage"}, nil)
+	if got := c.TemplateName(); got != "triage-v1" {
+		t.Errorf("TemplateName() = %q, want triage-v1", got)
+	}
+	at := time.Date(2025, 12, 31, 23, 0, 0, 0, time.FixedZone("UTC-5", -5*3600))
+	if got := c.IndexName(at); got != "triage-v1-2026.01" {
+		t.Errorf("IndexName() = %q, want the UTC month triage-v1-2026.01", got)
+	}
+}
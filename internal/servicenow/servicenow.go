@@ -0,0 +1,282 @@
+// Package servicenow files ServiceNow incident records for investigated faults.
+// Urgency and impact are mapped from the fault severity and the investigation
+// report URL is attached to the record. When the same fault (by fault signature)
+// recurs within the dedup window, the existing record is updated with a work note
+// instead of filing a duplicate.
+package servicenow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTable is the ServiceNow table records are filed in.
+const DefaultTable = "incident"
+
+// Config configures the ServiceNow client.
+type Config struct {
+	// InstanceURL is the instance base URL, e.g. "https://example.service-now.com"
+	InstanceURL string
+
+	// Username and Password authenticate with basic auth
+	Username string
+	Password string
+
+	// Table is the table records are filed in (default: incident)
+	Table string
+
+	// AssignmentGroup, CallerID, and Category are optional record fields; each
+	// accepts a sys_id or display value
+	AssignmentGroup string
+	CallerID        string
+	Category        string
+
+	// DedupWindow is how long after the last occurrence a recurring fault updates
+	// its existing record instead of filing a new one. Zero always files a new record.
+	DedupWindow time.Duration
+}
+
+// Record is an investigated fault to file.
+type Record struct {
+	// Signature identifies recurrences of the same fault
+	Signature string
+
+	IncidentID string
+	Cluster    string
+	Namespace  string
+	Resource   string
+	FaultType  string
+	Severity   string
+	Status     string
+	RootCause  string
+	Confidence string
+	ReportURL  string
+}
+
+// Ticket identifies a filed ServiceNow record.
+type Ticket struct {
+	SysID  string
+	Number string
+	URL    string
+
+	// Updated is true when an existing record was updated for a recurrence
+	Updated bool
+}
+
+// openTicket is a filed record and the time its fault was last seen.
+type openTicket struct {
+	Ticket
+	urgency  int
+	lastSeen time.Time
+}
+
+// Client files and updates ServiceNow incident records. Filed records are tracked
+// in memory only; after a restart the next occurrence of a fault files a new record.
+// It is safe for concurrent use.
+type Client struct {
+	cfg    Config
+	client *http.Client
+
+	mu      sync.Mutex
+	tickets map[string]openTicket
+
+	// now is replaceable for tests
+	now func() time.Time
+}
+
+// New creates a ServiceNow client.
+func New(cfg Config, httpClient *http.Client) *Client {
+	if cfg.Table == "" {
+		cfg.Table = DefaultTable
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{
+		cfg:     cfg,
+		client:  httpClient,
+		tickets: make(map[string]openTicket),
+		now:     time.Now,
+	}
+}
+
+// UrgencyImpact maps a fault severity to ServiceNow urgency and impact
+// (1 = high, 2 = medium, 3 = low).
+func UrgencyImpact(severity string) (urgency, impact int) {
+	switch strings.ToUpper(strings.TrimSpace(severity)) {
+	case "CRITICAL":
+		return 1, 1
+	case "ERROR":
+		return 2, 2
+	case "WARNING", "WARN":
+		return 2, 3
+	default:
+		return 3, 3
+	}
+}
+
+// File files a record for the fault, or updates the open record when the same
+// fault recurred within the dedup window.
+func (c *Client) File(ctx context.Context, rec Record) (Ticket, error) {
+	now := c.now()
+	urgency, impact := UrgencyImpact(rec.Severity)
+
+	c.mu.Lock()
+	existing, ok := c.tickets[rec.Signature]
+	c.mu.Unlock()
+
+	if ok && rec.Signature != "" && c.cfg.DedupWindow > 0 && now.Sub(existing.lastSeen) <= c.cfg.DedupWindow {
+		body := map[string]string{"work_notes": recurrenceNote(rec)}
+		// Escalate when the recurrence is more severe; never downgrade
+		if urgency < existing.urgency {
+			body["urgency"] = fmt.Sprint(urgency)
+			body["impact"] = fmt.Sprint(impact)
+			existing.urgency = urgency
+		}
+		if err := c.do(ctx, http.MethodPatch, "/"+existing.SysID, body, nil); err != nil {
+			return Ticket{}, fmt.Errorf("failed to update ServiceNow record %s: %w", existing.Number, err)
+		}
+		existing.lastSeen = now
+		c.mu.Lock()
+		c.tickets[rec.Signature] = existing
+		c.mu.Unlock()
+
+		ticket := existing.Ticket
+		ticket.Updated = true
+		return ticket, nil
+	}
+
+	body := map[string]string{
+		"short_description":   shortDescription(rec),
+		"description":         description(rec),
+		"urgency":             fmt.Sprint(urgency),
+		"impact":              fmt.Sprint(impact),
+		"correlation_id":      rec.Signature,
+		"correlation_display": "nightcrier",
+	}
+	if c.cfg.AssignmentGroup != "" {
+		body["assignment_group"] = c.cfg.AssignmentGroup
+	}
+	if c.cfg.CallerID != "" {
+		body["caller_id"] = c.cfg.CallerID
+	}
+	if c.cfg.Category != "" {
+		body["category"] = c.cfg.Category
+	}
+
+	var resp struct {
+		Result struct {
+			SysID  string `json:"sys_id"`
+			Number string `json:"number"`
+		} `json:"result"`
+	}
+	if err := c.do(ctx, http.MethodPost, "", body, &resp); err != nil {
+		return Ticket{}, fmt.Errorf("failed to create ServiceNow record: %w", err)
+	}
+
+	ticket := Ticket{
+		SysID:  resp.Result.SysID,
+		Number: resp.Result.Number,
+		URL:    c.recordURL(resp.Result.SysID),
+	}
+	if rec.Signature != "" {
+		c.mu.Lock()
+		c.tickets[rec.Signature] = openTicket{Ticket: ticket, urgency: urgency, lastSeen: now}
+		c.pruneLocked(now)
+		c.mu.Unlock()
+	}
+	return ticket, nil
+}
+
+// pruneLocked drops tickets whose dedup window has passed. c.mu must be held.
+func (c *Client) pruneLocked(now time.Time) {
+	for sig, t := range c.tickets {
+		if now.Sub(t.lastSeen) > c.cfg.DedupWindow {
+			delete(c.tickets, sig)
+		}
+	}
+}
+
+// recordURL returns the browser URL of a record.
+func (c *Client) recordURL(sysID string) string {
+	target := fmt.Sprintf("%s.do?sys_id=%s", c.cfg.Table, sysID)
+	return strings.TrimSuffix(c.cfg.InstanceURL, "/") + "/nav_to.do?uri=" + url.QueryEscape(target)
+}
+
+// do sends a JSON request to the table API and decodes a JSON response into out.
+func (c *Client) do(ctx context.Context, method, suffix string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	endpoint := strings.TrimSuffix(c.cfg.InstanceURL, "/") + "/api/now/table/" + c.cfg.Table + suffix
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned status %d: %s", method, endpoint, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// shortDescription is the one-line record title.
+func shortDescription(rec Record) string {
+	return fmt.Sprintf("[%s] %s on %s/%s (%s)", rec.Severity, rec.FaultType, rec.Cluster, rec.Resource, rec.Namespace)
+}
+
+// description is the record body: the investigation findings and report link.
+func description(rec Record) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Kubernetes fault investigated by nightcrier.\n\n")
+	fmt.Fprintf(&b, "Cluster: %s\n", rec.Cluster)
+	fmt.Fprintf(&b, "Namespace: %s\n", rec.Namespace)
+	fmt.Fprintf(&b, "Resource: %s\n", rec.Resource)
+	fmt.Fprintf(&b, "Fault: %s\n", rec.FaultType)
+	fmt.Fprintf(&b, "Severity: %s\n", rec.Severity)
+	fmt.Fprintf(&b, "Investigation status: %s\n", rec.Status)
+	fmt.Fprintf(&b, "Incident ID: %s\n\n", rec.IncidentID)
+	if rec.RootCause != "" {
+		fmt.Fprintf(&b, "Root cause (%s confidence): %s\n\n", rec.Confidence, rec.RootCause)
+	}
+	if rec.ReportURL != "" {
+		fmt.Fprintf(&b, "Investigation report: %s\n", rec.ReportURL)
+	}
+	return b.String()
+}
+
+// recurrenceNote is the work note added when the fault recurs.
+func recurrenceNote(rec Record) string {
+	note := fmt.Sprintf("Fault recurred (%s, incident %s).", rec.Severity, rec.IncidentID)
+	if rec.RootCause != "" {
+		note += "\nRoot cause: " + rec.RootCause
+	}
+	if rec.ReportURL != "" {
+		note += "\nInvestigation report: " + rec.ReportURL
+	}
+	return note
+}
@@ -0,0 +1,134 @@
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRecord() Record {
+	return Record{
+		Signature:  "sig-1",
+		IncidentID: "3f9a1c0d-1111-2222-3333-444455556666",
+		Cluster:    "prod-east",
+		Namespace:  "payments",
+		Resource:   "Pod/api-7d9f",
+		FaultType:  "CrashLoopBackOff",
+		Severity:   "ERROR",
+		Status:     "resolved",
+		RootCause:  "Missing DATABASE_URL secret",
+		Confidence: "HIGH",
+		ReportURL:  "https://reports.example.com/3f9a1c0d",
+	}
+}
+
+func TestUrgencyImpact(t *testing.T) {
+	tests := []struct {
+		severity        string
+		urgency, impact int
+	}{
+		{"CRITICAL", 1, 1},
+		{"error", 2, 2},
+		{"WARNING", 2, 3},
+		{"INFO", 3, 3},
+		{"", 3, 3},
+	}
+	for _, tt := range tests {
+		u, i := UrgencyImpact(tt.severity)
+		if u != tt.urgency || i != tt.impact {
+			t.Errorf("UrgencyImpact(%q) = %d, %d, want %d, %d", tt.severity, u, i, tt.urgency, tt.impact)
+		}
+	}
+}
+
+func TestClient_FileCreatesThenUpdatesRecurrence(t *testing.T) {
+	var calls []string
+	var bodies []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if user, pass, ok := r.BasicAuth(); !ok || user != "nightcrier" || pass != "secret" {
+			t.Errorf("basic auth = %q %q %v", user, pass, ok)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		w.Write([]byte(`{"result":{"sys_id":"abc123","number":"INC0010001"}}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := New(Config{
+		InstanceURL:     server.URL,
+		Username:        "nightcrier",
+		Password:        "secret",
+		AssignmentGroup: "SRE",
+		DedupWindow:     5 * time.Minute,
+	}, server.Client())
+	c.now = func() time.Time { return now }
+
+	ticket, err := c.File(context.Background(), testRecord())
+	if err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+	if ticket.Number != "INC0010001" || ticket.Updated {
+		t.Errorf("ticket = %+v", ticket)
+	}
+	if !strings.Contains(ticket.URL, "incident.do%3Fsys_id%3Dabc123") {
+		t.Errorf("URL = %q", ticket.URL)
+	}
+	created := bodies[0]
+	if created["urgency"] != "2" || created["impact"] != "2" || created["assignment_group"] != "SRE" || created["correlation_id"] != "sig-1" {
+		t.Errorf("create body = %v", created)
+	}
+	if !strings.Contains(created["description"], "https://reports.example.com/3f9a1c0d") {
+		t.Errorf("description missing report URL: %q", created["description"])
+	}
+
+	// A more severe recurrence inside the window updates and escalates the record
+	now = now.Add(4 * time.Minute)
+	rec := testRecord()
+	rec.Severity = "CRITICAL"
+	ticket, err = c.File(context.Background(), rec)
+	if err != nil {
+		t.Fatalf("File() recurrence error = %v", err)
+	}
+	if !ticket.Updated || ticket.Number != "INC0010001" {
+		t.Errorf("recurrence ticket = %+v", ticket)
+	}
+	updated := bodies[1]
+	if updated["urgency"] != "1" || !strings.Contains(updated["work_notes"], "Fault recurred") {
+		t.Errorf("update body = %v", updated)
+	}
+
+	// Outside the window a new record is filed
+	now = now.Add(6 * time.Minute)
+	if ticket, err = c.File(context.Background(), testRecord()); err != nil || ticket.Updated {
+		t.Errorf("File() after window = %+v, %v", ticket, err)
+	}
+
+	want := []string{
+		"POST /api/now/table/incident",
+		"PATCH /api/now/table/incident/abc123",
+		"POST /api/now/table/incident",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestClient_FileError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"message":"User Not Authorized"}}`))
+	}))
+	defer server.Close()
+
+	c := New(Config{InstanceURL: server.URL, Username: "u", Password: "p"}, server.Client())
+	if _, err := c.File(context.Background(), testRecord()); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("File() error = %v, want status 403", err)
+	}
+}
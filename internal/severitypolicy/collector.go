@@ -0,0 +1,210 @@
+package severitypolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// runFunc runs kubectl with the given arguments and returns its standard output.
+type runFunc func(ctx context.Context, args ...string) ([]byte, error)
+
+// Collector reads the cluster signals of a fault with kubectl.
+type Collector struct {
+	run runFunc
+}
+
+// NewCollector returns a collector reading the cluster with the given kubeconfig.
+func NewCollector(kubeconfig string) *Collector {
+	return &Collector{run: kubectlRunner(kubeconfig)}
+}
+
+// kubectlRunner runs kubectl against the cluster of a kubeconfig.
+func kubectlRunner(kubeconfig string) runFunc {
+	return func(ctx context.Context, args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "kubectl", append([]string{"--kubeconfig", kubeconfig}, args...)...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("kubectl %s failed: %w (output: %s)", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+		}
+		return out, nil
+	}
+}
+
+// podTemplateKinds are the workload kinds whose pods are described by
+// spec.template
+var podTemplateKinds = map[string]bool{
+	"deployment":  true,
+	"statefulset": true,
+	"daemonset":   true,
+	"replicaset":  true,
+	"job":         true,
+}
+
+// podSpec holds the fields of a pod, or of a workload's pod template, the
+// collector reads.
+type podSpec struct {
+	Labels        map[string]string
+	PriorityClass string
+}
+
+// object holds the fields of a pod or workload the collector reads.
+type object struct {
+	Metadata struct {
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Spec struct {
+		PriorityClassName string `json:"priorityClassName"`
+		Template          *struct {
+			Metadata struct {
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+			Spec struct {
+				PriorityClassName string `json:"priorityClassName"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+// labelSelector is a Kubernetes label selector.
+type labelSelector struct {
+	MatchLabels      map[string]string    `json:"matchLabels"`
+	MatchExpressions []selectorExpression `json:"matchExpressions"`
+}
+
+// selectorExpression is a set-based requirement of a label selector.
+type selectorExpression struct {
+	Key      string   `json:"key"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+// matches reports whether the selector selects a pod with the given labels.
+func (s *labelSelector) matches(labels map[string]string) bool {
+	for key, value := range s.MatchLabels {
+		if labels[key] != value {
+			return false
+		}
+	}
+	for _, expr := range s.MatchExpressions {
+		value, ok := labels[expr.Key]
+		switch expr.Operator {
+		case "In":
+			if !ok || !slices.Contains(expr.Values, value) {
+				return false
+			}
+		case "NotIn":
+			if ok && slices.Contains(expr.Values, value) {
+				return false
+			}
+		case "Exists":
+			if !ok {
+				return false
+			}
+		case "DoesNotExist":
+			if ok {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Collect reads the cluster signals of a fault on the named resource. Signals that
+// cannot be read (e.g. because RBAC does not allow listing PodDisruptionBudgets)
+// stay unknown, and the errors reading them are returned with the others.
+func (c *Collector) Collect(ctx context.Context, namespace, kind, name string) (ClusterSignals, error) {
+	var signals ClusterSignals
+	if namespace == "" {
+		return signals, nil
+	}
+	var errs []error
+
+	out, err := c.run(ctx, "get", "namespace", namespace, "-o", "json")
+	if err == nil {
+		var ns object
+		if err = json.Unmarshal(out, &ns); err == nil {
+			signals.NamespaceLabels = ns.Metadata.Labels
+			if signals.NamespaceLabels == nil {
+				signals.NamespaceLabels = map[string]string{}
+			}
+		}
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to read namespace labels: %w", err))
+	}
+
+	pod, err := c.podSpec(ctx, namespace, kind, name)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if pod != nil {
+		signals.PriorityClass = pod.PriorityClass
+		signals.PriorityKnown = true
+		if covered, err := c.coveredByPDB(ctx, namespace, pod.Labels); err != nil {
+			errs = append(errs, err)
+		} else {
+			signals.HasPDB = &covered
+		}
+	}
+	return signals, errors.Join(errs...)
+}
+
+// podSpec reads the pod, or the pod template of a workload. It returns nil for
+// other kinds of resources.
+func (c *Collector) podSpec(ctx context.Context, namespace, kind, name string) (*podSpec, error) {
+	kind = strings.ToLower(kind)
+	if name == "" || (kind != "pod" && !podTemplateKinds[kind]) {
+		return nil, nil
+	}
+	out, err := c.run(ctx, "get", kind, name, "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s %s: %w", kind, name, err)
+	}
+	var obj object
+	if err := json.Unmarshal(out, &obj); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s: %w", kind, name, err)
+	}
+	if kind == "pod" {
+		return &podSpec{Labels: obj.Metadata.Labels, PriorityClass: obj.Spec.PriorityClassName}, nil
+	}
+	if obj.Spec.Template == nil {
+		return nil, fmt.Errorf("%s %s has no pod template", kind, name)
+	}
+	return &podSpec{Labels: obj.Spec.Template.Metadata.Labels, PriorityClass: obj.Spec.Template.Spec.PriorityClassName}, nil
+}
+
+// coveredByPDB reports whether a PodDisruptionBudget of the namespace selects pods
+// with the given labels.
+func (c *Collector) coveredByPDB(ctx context.Context, namespace string, labels map[string]string) (bool, error) {
+	out, err := c.run(ctx, "get", "poddisruptionbudgets", "-n", namespace, "-o", "json")
+	if err != nil {
+		return false, fmt.Errorf("failed to list poddisruptionbudgets: %w", err)
+	}
+	var list struct {
+		Items []struct {
+			Spec struct {
+				Selector *labelSelector `json:"selector"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return false, fmt.Errorf("failed to decode poddisruptionbudgets: %w", err)
+	}
+	for _, pdb := range list.Items {
+		// A PDB without a selector selects no pods; an empty selector selects all
+		if pdb.Spec.Selector != nil && pdb.Spec.Selector.matches(labels) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
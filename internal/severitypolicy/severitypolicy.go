@@ -0,0 +1,187 @@
+// Package severitypolicy computes incident severity from several signals instead
+// of trusting the raw severity string of the upstream fault event. Rules match on
+// the fault's event reason (fault type), resource kind, namespace, and upstream
+// severity, and on cluster signals read with kubectl: the namespace's labels (e.g.
+// a tier label), the pod's priority class, and whether a PodDisruptionBudget
+// covers the pod. The first matching rule sets the severity, and its name is
+// recorded on the incident.
+package severitypolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/match"
+)
+
+// PDB conditions of a rule
+const (
+	// PDBPresent matches pods covered by a PodDisruptionBudget
+	PDBPresent = "present"
+	// PDBAbsent matches pods no PodDisruptionBudget covers
+	PDBAbsent = "absent"
+)
+
+// AnyValue as a namespace label value matches any value of the label
+const AnyValue = "*"
+
+// severities are the severities a rule can set
+var severities = []string{"DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL"}
+
+// Signals are what rules match on. The cluster signals are unknown when they could
+// not be read; rules conditioned on an unknown signal do not match.
+type Signals struct {
+	FaultType string
+	Kind      string
+	Namespace string
+	// Severity is the upstream severity of the fault event
+	Severity string
+
+	Cluster ClusterSignals
+}
+
+// ClusterSignals are the signals read from the cluster.
+type ClusterSignals struct {
+	// NamespaceLabels are the labels of the fault's namespace (nil: unknown)
+	NamespaceLabels map[string]string
+	// PriorityClass is the priority class of the pod, or of the pods a workload
+	// runs ("" with PriorityKnown: none)
+	PriorityClass string
+	PriorityKnown bool
+	// HasPDB reports whether a PodDisruptionBudget covers the pod (nil: unknown)
+	HasPDB *bool
+}
+
+// Rule sets the severity of matching faults. Every condition set must hold; an
+// empty list or map matches any value.
+type Rule struct {
+	// Name identifies the rule on the incident
+	Name string
+
+	FaultTypes []string
+	Kinds      []string
+	Namespaces []string
+	// Severities match the upstream severity
+	Severities []string
+
+	// NamespaceLabels match the labels of the fault's namespace; the value "*"
+	// matches any value of a label that is set
+	NamespaceLabels map[string]string
+	// PriorityClasses match the pod's priority class; "" matches pods without one
+	PriorityClasses []string
+	// PDB is PDBPresent or PDBAbsent, or empty for either
+	PDB string
+
+	// Severity is the severity set on matching faults
+	Severity string
+}
+
+// Validate checks the rule.
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("severity rule must have a name")
+	}
+	if !isSeverity(r.Severity) {
+		return fmt.Errorf("severity rule %q: invalid severity %q: must be one of %s", r.Name, r.Severity, strings.Join(severities, ", "))
+	}
+	if r.PDB != "" && r.PDB != PDBPresent && r.PDB != PDBAbsent {
+		return fmt.Errorf("severity rule %q: pdb must be %q or %q, got %q", r.Name, PDBPresent, PDBAbsent, r.PDB)
+	}
+	return nil
+}
+
+// NeedsCluster reports whether the rule matches on cluster signals.
+func (r Rule) NeedsCluster() bool {
+	return len(r.NamespaceLabels) > 0 || len(r.PriorityClasses) > 0 || r.PDB != ""
+}
+
+// Matches reports whether the rule applies to the signals.
+func (r Rule) Matches(s Signals) bool {
+	if !match.Any(r.FaultTypes, s.FaultType) ||
+		!match.Any(r.Kinds, s.Kind) ||
+		!match.Any(r.Namespaces, s.Namespace) ||
+		!match.Any(r.Severities, s.Severity) {
+		return false
+	}
+	if len(r.NamespaceLabels) > 0 {
+		if s.Cluster.NamespaceLabels == nil {
+			return false
+		}
+		for key, want := range r.NamespaceLabels {
+			got, ok := s.Cluster.NamespaceLabels[key]
+			if !ok || (want != AnyValue && got != want) {
+				return false
+			}
+		}
+	}
+	if len(r.PriorityClasses) > 0 {
+		if !s.Cluster.PriorityKnown || !match.Any(r.PriorityClasses, s.Cluster.PriorityClass) {
+			return false
+		}
+	}
+	if r.PDB != "" {
+		if s.Cluster.HasPDB == nil || *s.Cluster.HasPDB != (r.PDB == PDBPresent) {
+			return false
+		}
+	}
+	return true
+}
+
+// Decision is the severity a policy computed for a fault.
+type Decision struct {
+	Severity string
+	// Rule is the name of the rule that set the severity
+	Rule string
+}
+
+// Policy is an ordered list of rules; the first matching rule applies.
+type Policy struct {
+	Rules []Rule
+}
+
+// Validate checks the rules and that their names are unique.
+func (p Policy) Validate() error {
+	names := make(map[string]bool, len(p.Rules))
+	for _, rule := range p.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("duplicate severity rule name %q", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	return nil
+}
+
+// NeedsCluster reports whether any rule matches on cluster signals, which are
+// then read before evaluating the policy.
+func (p Policy) NeedsCluster() bool {
+	for _, rule := range p.Rules {
+		if rule.NeedsCluster() {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate returns the decision of the first rule matching the signals, and false
+// when none does (the upstream severity stands).
+func (p Policy) Evaluate(s Signals) (Decision, bool) {
+	for _, rule := range p.Rules {
+		if rule.Matches(s) {
+			return Decision{Severity: strings.ToUpper(rule.Severity), Rule: rule.Name}, true
+		}
+	}
+	return Decision{}, false
+}
+
+// isSeverity reports whether value is a severity a rule can set.
+func isSeverity(value string) bool {
+	for _, s := range severities {
+		if strings.EqualFold(s, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package severitypolicy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestPolicy_Evaluate(t *testing.T) {
+	policy := Policy{Rules: []Rule{
+		{Name: "low-priority-oom", FaultTypes: []string{"OOMKilled"}, PriorityClasses: []string{"", "batch-low"}, Severity: "warning"},
+		{Name: "prod-tier", NamespaceLabels: map[string]string{"tier": "production"}, PDB: PDBPresent, Severity: "CRITICAL"},
+		{Name: "sandboxes", NamespaceLabels: map[string]string{"sandbox": AnyValue}, Severity: "INFO"},
+		{Name: "upstream-critical", Severities: []string{"CRITICAL"}, Kinds: []string{"Pod"}, Severity: "ERROR"},
+	}}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if !policy.NeedsCluster() {
+		t.Error("NeedsCluster() = false for rules on cluster signals")
+	}
+
+	prod := ClusterSignals{NamespaceLabels: map[string]string{"tier": "production"}, PriorityKnown: true, PriorityClass: "web-high", HasPDB: boolPtr(true)}
+	tests := []struct {
+		name    string
+		signals Signals
+		want    string
+		rule    string
+	}{
+		{"low priority OOM", Signals{FaultType: "oomkilled", Cluster: ClusterSignals{PriorityKnown: true}}, "WARNING", "low-priority-oom"},
+		{"OOM of unknown priority", Signals{FaultType: "OOMKilled"}, "", ""},
+		{"production with PDB", Signals{FaultType: "CrashLoopBackOff", Cluster: prod}, "CRITICAL", "prod-tier"},
+		{"production without PDB", Signals{FaultType: "CrashLoopBackOff", Cluster: ClusterSignals{NamespaceLabels: prod.NamespaceLabels, HasPDB: boolPtr(false)}}, "", ""},
+		{"sandbox of any name", Signals{Cluster: ClusterSignals{NamespaceLabels: map[string]string{"sandbox": "alice"}}}, "INFO", "sandboxes"},
+		{"upstream critical pod", Signals{Kind: "Pod", Severity: "critical"}, "ERROR", "upstream-critical"},
+		{"no rule", Signals{Kind: "Node", Severity: "CRITICAL"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, ok := policy.Evaluate(tt.signals)
+			if ok != (tt.want != "") || decision.Severity != tt.want || decision.Rule != tt.rule {
+				t.Errorf("Evaluate() = %+v, %v, want %s by %q", decision, ok, tt.want, tt.rule)
+			}
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	for _, invalid := range []Policy{
+		{Rules: []Rule{{Severity: "ERROR"}}},
+		{Rules: []Rule{{Name: "a", Severity: "SEVERE"}}},
+		{Rules: []Rule{{Name: "a", Severity: "ERROR", PDB: "yes"}}},
+		{Rules: []Rule{{Name: "a", Severity: "ERROR"}, {Name: "a", Severity: "INFO"}}},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Validate() should reject %+v", invalid)
+		}
+	}
+	if (Policy{Rules: []Rule{{Name: "a", FaultTypes: []string{"OOMKilled"}, Severity: "ERROR"}}}).NeedsCluster() {
+		t.Error("NeedsCluster() = true for rules on event fields only")
+	}
+}
+
+// fakeKubectl answers kubectl invocations from canned output by argument list.
+func fakeKubectl(responses map[string]string) runFunc {
+	return func(ctx context.Context, args ...string) ([]byte, error) {
+		key := strings.Join(args, " ")
+		out, ok := responses[key]
+		if !ok {
+			return nil, errors.New("forbidden: " + key)
+		}
+		return []byte(out), nil
+	}
+}
+
+func TestCollector_Collect(t *testing.T) {
+	c := &Collector{run: fakeKubectl(map[string]string{
+		"get namespace payments -o json": `{"metadata":{"labels":{"tier":"production"}}}`,
+		"get deployment api -n payments -o json": `{"spec":{"template":{"metadata":{"labels":{"app":"api","track":"stable"}},
+			"spec":{"priorityClassName":"web-high"}}}}`,
+		"get pod api-7d9f -n payments -o json": `{"metadata":{"labels":{"app":"worker"}},"spec":{}}`,
+		"get poddisruptionbudgets -n payments -o json": `{"items":[
+			{"spec":{}},
+			{"spec":{"selector":{"matchLabels":{"app":"api"},"matchExpressions":[{"key":"track","operator":"In","values":["stable"]}]}}}]}`,
+	})}
+	ctx := context.Background()
+
+	signals, err := c.Collect(ctx, "payments", "Deployment", "api")
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if signals.NamespaceLabels["tier"] != "production" || !signals.PriorityKnown || signals.PriorityClass != "web-high" {
+		t.Errorf("Collect() = %+v", signals)
+	}
+	if signals.HasPDB == nil || !*signals.HasPDB {
+		t.Errorf("HasPDB = %v, want true", signals.HasPDB)
+	}
+
+	signals, err = c.Collect(ctx, "payments", "Pod", "api-7d9f")
+	if err != nil {
+		t.Fatalf("Collect() of a pod error = %v", err)
+	}
+	if !signals.PriorityKnown || signals.PriorityClass != "" || signals.HasPDB == nil || *signals.HasPDB {
+		t.Errorf("Collect() of an uncovered pod = %+v", signals)
+	}
+
+	// Unreadable signals stay unknown
+	signals, err = c.Collect(ctx, "billing", "Pod", "db-0")
+	if err == nil {
+		t.Error("Collect() should report the signals it could not read")
+	}
+	if signals.NamespaceLabels != nil || signals.PriorityKnown || signals.HasPDB != nil {
+		t.Errorf("Collect() with unreadable signals = %+v, want unknown", signals)
+	}
+}
+
+func TestLabelSelector_Matches(t *testing.T) {
+	labels := map[string]string{"app": "api", "env": "prod"}
+	tests := []struct {
+		selector labelSelector
+		want     bool
+	}{
+		{labelSelector{}, true},
+		{labelSelector{MatchLabels: map[string]string{"app": "api"}}, true},
+		{labelSelector{MatchLabels: map[string]string{"app": "web"}}, false},
+		{labelSelector{MatchExpressions: []selectorExpression{{Key: "env", Operator: "NotIn", Values: []string{"prod"}}}}, false},
+		{labelSelector{MatchExpressions: []selectorExpression{{Key: "team", Operator: "DoesNotExist"}}}, true},
+		{labelSelector{MatchExpressions: []selectorExpression{{Key: "team", Operator: "Exists"}}}, false},
+	}
+	for i, tt := range tests {
+		if got := tt.selector.matches(labels); got != tt.want {
+			t.Errorf("selector %d matches() = %v, want %v", i, got, tt.want)
+		}
+	}
+}
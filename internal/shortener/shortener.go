@@ -0,0 +1,177 @@
+// Package shortener turns long report links (e.g. Azure SAS URLs several hundred
+// characters long) into short links through an internal URL shortener, so chat
+// and email notifications carry a readable, stable link.
+//
+// The shortener API is configurable: the long URL is sent in a request body built
+// from a template, and the short URL is read from a field of a JSON response, or
+// taken as the whole body of a plain-text response. Shortened links are cached per
+// long URL, so the same report always gets the same short link.
+package shortener
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultBodyTemplate is the request body sent to the shortener
+	DefaultBodyTemplate = `{"url": "{url}"}`
+
+	// DefaultResponseField is the JSON response field holding the short URL
+	DefaultResponseField = "short_url"
+
+	// maxResponseBytes bounds the shortener response read
+	maxResponseBytes = 64 * 1024
+
+	// maxCachedURLs bounds the cache of shortened links
+	maxCachedURLs = 1000
+)
+
+// Config configures a Client.
+type Config struct {
+	// URL is the shortener API endpoint
+	URL string
+	// Method is the HTTP method (default POST)
+	Method string
+	// Token is sent as a bearer token when set
+	Token string
+	// BodyTemplate is the request body; "{url}" is replaced with the long URL,
+	// JSON-escaped. Default: DefaultBodyTemplate.
+	BodyTemplate string
+	// ResponseField is the dotted path of the short URL in a JSON response.
+	// Default: DefaultResponseField.
+	ResponseField string
+}
+
+// Client shortens URLs through the configured shortener. It is safe for
+// concurrent use.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// New returns a client. A nil httpClient uses http.DefaultClient.
+func New(config Config, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if config.Method == "" {
+		config.Method = http.MethodPost
+	}
+	if config.BodyTemplate == "" {
+		config.BodyTemplate = DefaultBodyTemplate
+	}
+	if config.ResponseField == "" {
+		config.ResponseField = DefaultResponseField
+	}
+	return &Client{config: config, httpClient: httpClient, cache: make(map[string]string)}
+}
+
+// Shorten returns the short link for longURL. Links that are not http(s) URLs,
+// such as the local paths of filesystem storage, are returned unchanged.
+func (c *Client) Shorten(ctx context.Context, longURL string) (string, error) {
+	if !strings.HasPrefix(longURL, "http://") && !strings.HasPrefix(longURL, "https://") {
+		return longURL, nil
+	}
+
+	c.mu.Lock()
+	short, ok := c.cache[longURL]
+	c.mu.Unlock()
+	if ok {
+		return short, nil
+	}
+
+	short, err := c.shorten(ctx, longURL)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	if len(c.cache) >= maxCachedURLs {
+		c.cache = make(map[string]string)
+	}
+	c.cache[longURL] = short
+	c.mu.Unlock()
+	return short, nil
+}
+
+// shorten calls the shortener API.
+func (c *Client) shorten(ctx context.Context, longURL string) (string, error) {
+	escaped, err := json.Marshal(longURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode URL: %w", err)
+	}
+	body := strings.ReplaceAll(c.config.BodyTemplate, "{url}", string(escaped[1:len(escaped)-1]))
+
+	req, err := http.NewRequestWithContext(ctx, c.config.Method, c.config.URL, bytes.NewBufferString(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create shortener request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/plain")
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call URL shortener: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read shortener response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("URL shortener returned status %d: %s", resp.StatusCode, truncate(string(data), 200))
+	}
+
+	short := strings.TrimSpace(string(data))
+	if strings.HasPrefix(short, "{") {
+		var document interface{}
+		if err := json.Unmarshal(data, &document); err != nil {
+			return "", fmt.Errorf("failed to decode shortener response: %w", err)
+		}
+		short = jsonField(document, c.config.ResponseField)
+		if short == "" {
+			return "", fmt.Errorf("shortener response has no %q field", c.config.ResponseField)
+		}
+	}
+	if u, err := url.Parse(short); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("shortener returned an invalid URL %q", truncate(short, 200))
+	}
+	return short, nil
+}
+
+// jsonField returns the string at a dotted path of a decoded JSON document, or ""
+// when absent.
+func jsonField(document interface{}, path string) string {
+	value := document
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = object[key]
+	}
+	s, _ := value.(string)
+	return s
+}
+
+// truncate shortens s to at most n bytes for error messages.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
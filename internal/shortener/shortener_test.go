@@ -0,0 +1,88 @@
+package shortener
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const sasURL = "https://acct.blob.core.windows.net/incidents/prod/abc/investigation.html?sv=2024-01-01&se=2026-01-01&sig=a%2Bb%3D&sp=r"
+
+func TestShortenJSON(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("request = %s with auth %q, want POST with the bearer token", r.Method, r.Header.Get("Authorization"))
+		}
+		var body struct {
+			Long string `json:"long_url"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Long != sasURL {
+			t.Errorf("body long_url = %q, %v, want the SAS URL", body.Long, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"link": "https://go.example.com/r/x1"}}`))
+	}))
+	defer server.Close()
+
+	c := New(Config{
+		URL:           server.URL,
+		Token:         "secret",
+		BodyTemplate:  `{"long_url": "{url}", "domain": "go.example.com"}`,
+		ResponseField: "data.link",
+	}, server.Client())
+
+	for i := 0; i < 2; i++ {
+		short, err := c.Shorten(context.Background(), sasURL)
+		if err != nil {
+			t.Fatalf("Shorten() error = %v", err)
+		}
+		if short != "https://go.example.com/r/x1" {
+			t.Errorf("Shorten() = %q, want the short link", short)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("shortener called %d times, want the link cached after the first call", calls)
+	}
+}
+
+func TestShortenPlainText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("https://s.example.com/abc\n"))
+	}))
+	defer server.Close()
+
+	short, err := New(Config{URL: server.URL}, server.Client()).Shorten(context.Background(), sasURL)
+	if err != nil || short != "https://s.example.com/abc" {
+		t.Errorf("Shorten() = %q, %v, want the plain-text link", short, err)
+	}
+}
+
+func TestShortenFailures(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"error status":   func(w http.ResponseWriter, r *http.Request) { http.Error(w, "down", http.StatusBadGateway) },
+		"missing field":  func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`{"id": "abc"}`)) },
+		"not a URL":      func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("abc")) },
+		"invalid json":   func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`{"short_url": `)) },
+		"non-http short": func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`{"short_url": "ftp://x/y"}`)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			if short, err := New(Config{URL: server.URL}, server.Client()).Shorten(context.Background(), sasURL); err == nil {
+				t.Errorf("Shorten() = %q, want an error", short)
+			}
+		})
+	}
+}
+
+func TestShortenSkipsLocalPaths(t *testing.T) {
+	c := New(Config{URL: "http://127.0.0.1:1"}, nil)
+	short, err := c.Shorten(context.Background(), "/var/lib/nightcrier/incidents/abc/investigation.html")
+	if err != nil || short != "/var/lib/nightcrier/incidents/abc/investigation.html" {
+		t.Errorf("Shorten() of a local path = %q, %v, want it unchanged", short, err)
+	}
+}
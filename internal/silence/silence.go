@@ -0,0 +1,207 @@
+// Package silence suppresses the investigation of fault events in silenced
+// namespaces. Silences live in the state store, so every nightcrier process
+// sharing the store honors them; each process keeps the active silences in memory
+// and refreshes them periodically, so the event loop never waits on the database.
+package silence
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// MaxDuration is the longest a namespace can be silenced at once
+const MaxDuration = 7 * 24 * time.Hour
+
+// DefaultRefreshInterval is how often the active silences are reloaded from the
+// state store, picking up silences created or lifted by other processes
+const DefaultRefreshInterval = 30 * time.Second
+
+// Store is the part of the state store holding silences.
+type Store interface {
+	CreateSilence(ctx context.Context, silence *storage.Silence) error
+	LiftSilence(ctx context.Context, silenceID, liftedBy string, liftedAt time.Time) error
+	ListSilences(ctx context.Context, activeAt time.Time) ([]*storage.Silence, error)
+}
+
+// Registry creates, lifts, and matches silences. It is safe for concurrent use.
+type Registry struct {
+	store Store
+	now   func() time.Time
+
+	mu     sync.RWMutex
+	active []*storage.Silence
+}
+
+// NewRegistry returns a registry backed by the state store. Call Refresh (or Run)
+// to load the silences already in the store.
+func NewRegistry(store Store) *Registry {
+	return &Registry{store: store, now: time.Now}
+}
+
+// Refresh reloads the active silences from the state store.
+func (r *Registry) Refresh(ctx context.Context) error {
+	silences, err := r.store.ListSilences(ctx, r.now())
+	if err != nil {
+		return fmt.Errorf("failed to load silences: %w", err)
+	}
+	r.mu.Lock()
+	r.active = silences
+	r.mu.Unlock()
+	return nil
+}
+
+// Run refreshes the active silences every interval until the context is cancelled.
+func (r *Registry) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Refresh(ctx); err != nil {
+				slog.Warn("failed to refresh silences, keeping the previous ones", "error", err)
+			}
+		}
+	}
+}
+
+// Match returns the active silence covering a namespace of a cluster, or nil.
+func (r *Registry) Match(cluster, namespace string) *storage.Silence {
+	if namespace == "" {
+		return nil
+	}
+	now := r.now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, s := range r.active {
+		if s.Active(now) && s.Matches(cluster, namespace) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Active returns the silences in effect, soonest expiry first.
+func (r *Registry) Active() []*storage.Silence {
+	now := r.now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var silences []*storage.Silence
+	for _, s := range r.active {
+		if s.Active(now) {
+			silences = append(silences, s)
+		}
+	}
+	return silences
+}
+
+// Silence silences a target ("ns/<namespace>" or "<cluster>/ns/<namespace>") for
+// a duration and takes effect immediately in this process.
+func (r *Registry) Silence(ctx context.Context, target string, duration time.Duration, reason, createdBy string) (*storage.Silence, error) {
+	cluster, namespace, err := ParseTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	if duration <= 0 || duration > MaxDuration {
+		return nil, fmt.Errorf("silence duration must be between 1m and %s, got %s", FormatDuration(MaxDuration), duration)
+	}
+
+	now := r.now()
+	silence := &storage.Silence{
+		SilenceID: uuid.New().String()[:8],
+		Cluster:   cluster,
+		Namespace: namespace,
+		Reason:    reason,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		ExpiresAt: now.Add(duration),
+	}
+	if err := r.store.CreateSilence(ctx, silence); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.active = append(r.active, silence)
+	r.mu.Unlock()
+	slog.Info("namespace silenced",
+		"silence_id", silence.SilenceID,
+		"target", silence.Target(),
+		"expires_at", silence.ExpiresAt,
+		"created_by", createdBy,
+		"reason", reason)
+	return silence, nil
+}
+
+// Lift ends a silence before it expires.
+func (r *Registry) Lift(ctx context.Context, silenceID, liftedBy string) error {
+	now := r.now()
+	if err := r.store.LiftSilence(ctx, silenceID, liftedBy, now); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	kept := r.active[:0:0]
+	for _, s := range r.active {
+		if s.SilenceID != silenceID {
+			kept = append(kept, s)
+		}
+	}
+	r.active = kept
+	r.mu.Unlock()
+	slog.Info("silence lifted", "silence_id", silenceID, "lifted_by", liftedBy)
+	return nil
+}
+
+// ParseTarget parses a silence target: "ns/<namespace>" silences the namespace in
+// every cluster, "<cluster>/ns/<namespace>" in one cluster.
+func ParseTarget(target string) (cluster, namespace string, err error) {
+	parts := strings.Split(target, "/")
+	switch {
+	case len(parts) == 2 && parts[0] == "ns" && parts[1] != "":
+		return "", parts[1], nil
+	case len(parts) == 3 && parts[0] != "" && parts[1] == "ns" && parts[2] != "":
+		return parts[0], parts[2], nil
+	}
+	return "", "", fmt.Errorf("invalid silence target %q (want ns/<namespace> or <cluster>/ns/<namespace>)", target)
+}
+
+// ParseDuration parses a silence duration such as "90m", "2h", or "1d".
+func ParseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (e.g. 30m, 2h, 1d)", s)
+	}
+	return d, nil
+}
+
+// FormatDuration formats a duration for people, e.g. "45m", "1h30m", or "3d".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	hours, minutes := d/time.Hour, (d%time.Hour)/time.Minute
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
@@ -0,0 +1,161 @@
+package silence
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// memoryStore is an in-memory Store.
+type memoryStore struct {
+	silences map[string]*storage.Silence
+}
+
+func (m *memoryStore) CreateSilence(ctx context.Context, silence *storage.Silence) error {
+	copied := *silence
+	m.silences[silence.SilenceID] = &copied
+	return nil
+}
+
+func (m *memoryStore) LiftSilence(ctx context.Context, silenceID, liftedBy string, liftedAt time.Time) error {
+	s, ok := m.silences[silenceID]
+	if !ok || !s.Active(liftedAt) {
+		return fmt.Errorf("no active silence %s", silenceID)
+	}
+	s.LiftedAt, s.LiftedBy = &liftedAt, liftedBy
+	return nil
+}
+
+func (m *memoryStore) ListSilences(ctx context.Context, activeAt time.Time) ([]*storage.Silence, error) {
+	var active []*storage.Silence
+	for _, s := range m.silences {
+		if s.Active(activeAt) {
+			copied := *s
+			active = append(active, &copied)
+		}
+	}
+	return active, nil
+}
+
+func TestRegistry(t *testing.T) {
+	store := &memoryStore{silences: make(map[string]*storage.Silence)}
+	registry := NewRegistry(store)
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	registry.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	all, err := registry.Silence(ctx, "ns/payments", 2*time.Hour, "migration", "slack:U1")
+	if err != nil {
+		t.Fatalf("Silence() error = %v", err)
+	}
+	if _, err := registry.Silence(ctx, "prod/ns/search", 30*time.Minute, "", "slack:U2"); err != nil {
+		t.Fatalf("Silence() error = %v", err)
+	}
+
+	if s := registry.Match("staging", "payments"); s == nil || s.SilenceID != all.SilenceID {
+		t.Errorf("Match(staging, payments) = %+v, want the all-cluster silence", s)
+	}
+	if registry.Match("prod", "search") == nil {
+		t.Error("Match(prod, search) should match the cluster silence")
+	}
+	if registry.Match("staging", "search") != nil {
+		t.Error("Match(staging, search) should not match a silence of another cluster")
+	}
+
+	// Silences expire without a refresh
+	now = now.Add(time.Hour)
+	if registry.Match("prod", "search") != nil {
+		t.Error("Match() should not match an expired silence")
+	}
+	if got := len(registry.Active()); got != 1 {
+		t.Errorf("Active() = %d silences, want 1", got)
+	}
+
+	if err := registry.Lift(ctx, all.SilenceID, "slack:U3"); err != nil {
+		t.Fatalf("Lift() error = %v", err)
+	}
+	if registry.Match("prod", "payments") != nil {
+		t.Error("Match() should not match a lifted silence")
+	}
+
+	// A silence created by another process is picked up by Refresh
+	other := NewRegistry(store)
+	other.now = registry.now
+	if _, err := other.Silence(ctx, "ns/web", time.Hour, "", "slack:U4"); err != nil {
+		t.Fatalf("Silence() error = %v", err)
+	}
+	if err := registry.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if registry.Match("prod", "web") == nil {
+		t.Error("Match() should match a silence loaded by Refresh")
+	}
+}
+
+func TestRegistry_SilenceValidation(t *testing.T) {
+	registry := NewRegistry(&memoryStore{silences: make(map[string]*storage.Silence)})
+	for _, tt := range []struct {
+		target   string
+		duration time.Duration
+	}{
+		{"payments", time.Hour},
+		{"ns/payments", 0},
+		{"ns/payments", 8 * 24 * time.Hour},
+	} {
+		if _, err := registry.Silence(context.Background(), tt.target, tt.duration, "", "test"); err == nil {
+			t.Errorf("Silence(%q, %s) should fail", tt.target, tt.duration)
+		}
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		target, cluster, namespace string
+		wantErr                    bool
+	}{
+		{"ns/foo", "", "foo", false},
+		{"prod/ns/foo", "prod", "foo", false},
+		{"foo", "", "", true},
+		{"ns/", "", "", true},
+		{"prod/foo", "", "", true},
+		{"/ns/foo", "", "", true},
+	}
+	for _, tt := range tests {
+		cluster, namespace, err := ParseTarget(tt.target)
+		if (err != nil) != tt.wantErr || cluster != tt.cluster || namespace != tt.namespace {
+			t.Errorf("ParseTarget(%q) = %q, %q, %v", tt.target, cluster, namespace, err)
+		}
+	}
+}
+
+func TestParseAndFormatDuration(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"2h":  2 * time.Hour,
+		"90m": 90 * time.Minute,
+		"1d":  24 * time.Hour,
+	} {
+		got, err := ParseDuration(input)
+		if err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %s, %v, want %s", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "2", "xd", "0d"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) should fail", input)
+		}
+	}
+
+	for d, want := range map[time.Duration]string{
+		45 * time.Minute: "45m",
+		90 * time.Minute: "1h30m",
+		2 * time.Hour:    "2h",
+		72 * time.Hour:   "3d",
+	} {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,576 @@
+package skills
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultCacheDir is used when no cache directory is configured
+	defaultCacheDir = "./agent-home/skills"
+
+	// markerFile records where a tarball bundle came from and its checksum
+	markerFile = ".nightcrier-bundle.json"
+
+	// maxTarballSize caps the size of a downloaded tarball bundle
+	maxTarballSize = 256 << 20
+
+	// downloadTimeout bounds a single tarball download
+	downloadTimeout = 5 * time.Minute
+)
+
+var (
+	// bundleNamePattern restricts bundle names to a single safe path element
+	bundleNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+	// sha256Pattern matches a hex-encoded SHA-256 checksum
+	sha256Pattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+	// commitPattern matches a full git commit hash
+	commitPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// Bundle is a skill bundle downloaded into the skills cache directory. A bundle is
+// fetched either from a git repository or from a .tar.gz archive.
+type Bundle struct {
+	// Name is the bundle's directory under the cache directory
+	Name string
+
+	// GitURL is the repository to fetch. Ref optionally selects a branch, tag, or
+	// full commit hash; a commit hash pins the bundle and is verified after fetching.
+	GitURL string
+	Ref    string
+
+	// TarballURL is a .tar.gz archive to download. SHA256, when set, must match the
+	// archive and pins the bundle so it is not downloaded again.
+	TarballURL string
+	SHA256     string
+
+	// SignatureURL is the cosign signature bundle of the tarball, checked when
+	// supply chain verification is enabled. Default: TarballURL + ".sigstore.json".
+	SignatureURL string
+}
+
+// DefaultBundle returns the k8s4agents bundle used when no bundles are configured.
+func DefaultBundle() Bundle {
+	return Bundle{Name: K8sSkillName, GitURL: K8sSkillRepo}
+}
+
+// Validate checks that the bundle has a safe name and exactly one source.
+func (b Bundle) Validate() error {
+	if !bundleNamePattern.MatchString(b.Name) {
+		return fmt.Errorf("skill bundle name %q is invalid (use letters, digits, '.', '_' or '-')", b.Name)
+	}
+	if (b.GitURL == "") == (b.TarballURL == "") {
+		return fmt.Errorf("skill bundle %q must set exactly one of git or url", b.Name)
+	}
+	if b.SHA256 != "" {
+		if b.TarballURL == "" {
+			return fmt.Errorf("skill bundle %q: sha256 only applies to tarball bundles (pin git bundles with a commit ref)", b.Name)
+		}
+		if !sha256Pattern.MatchString(b.SHA256) {
+			return fmt.Errorf("skill bundle %q: sha256 must be 64 hex characters", b.Name)
+		}
+	}
+	if b.Ref != "" && b.GitURL == "" {
+		return fmt.Errorf("skill bundle %q: ref only applies to git bundles", b.Name)
+	}
+	if b.SignatureURL != "" && b.TarballURL == "" {
+		return fmt.Errorf("skill bundle %q: signature only applies to tarball bundles", b.Name)
+	}
+	return nil
+}
+
+// signatureURL returns the URL of the tarball's cosign signature bundle.
+func (b Bundle) signatureURL() string {
+	if b.SignatureURL != "" {
+		return b.SignatureURL
+	}
+	return b.TarballURL + ".sigstore.json"
+}
+
+// Verifier checks the provenance of fetched bundles before they replace the
+// cached copy (see the supplychain package). A returned error rejects the bundle.
+type Verifier interface {
+	// VerifyArchive checks a downloaded tarball against its signature bundle (nil
+	// when none could be downloaded)
+	VerifyArchive(ctx context.Context, name, archivePath string, signature []byte) error
+	// VerifyCommit checks a git bundle checked out at commit
+	VerifyCommit(ctx context.Context, name, ref, commit string) error
+}
+
+// bundleMarker is written into tarball bundles to detect whether they are current.
+type bundleMarker struct {
+	Source string `json:"source"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manager downloads skill bundles into the cache directory and keeps them up to
+// date. The cache directory is mounted into the agent container, so every bundle
+// is visible to the agent under its name. It is safe for concurrent use.
+type Manager struct {
+	cacheDir   string
+	bundles    []Bundle
+	httpClient *http.Client
+
+	// offline restricts syncs to bundles already in the cache directory
+	offline bool
+
+	// verifier, when set, checks fetched bundles before they are cached
+	verifier Verifier
+
+	// mu serializes syncs so a periodic update never races a startup download
+	mu sync.Mutex
+
+	// revisions holds the cached revision of each bundle as of the last sync
+	revMu     sync.RWMutex
+	revisions map[string]string
+}
+
+// NewManager creates a manager for the given bundles. If cacheDir is empty, it
+// defaults to "./agent-home/skills".
+func NewManager(cacheDir string, bundles []Bundle) *Manager {
+	if cacheDir == "" {
+		cacheDir = defaultCacheDir
+	}
+	return &Manager{
+		cacheDir:   cacheDir,
+		bundles:    bundles,
+		httpClient: &http.Client{Timeout: downloadTimeout},
+		revisions:  make(map[string]string),
+	}
+}
+
+// SetOffline makes the manager load bundles from the cache directory only, for
+// air-gapped operation: Sync never fetches, and reports bundles that have not been
+// copied into the cache. Call before the first Sync.
+func (m *Manager) SetOffline(offline bool) {
+	m.offline = offline
+}
+
+// SetVerifier makes the manager check every bundle it fetches with v before the
+// bundle replaces the cached copy. Bundles already cached are not re-checked.
+// Call before the first Sync.
+func (m *Manager) SetVerifier(v Verifier) {
+	m.verifier = v
+}
+
+// Sync makes sure every bundle is present in the cache directory. Missing bundles
+// are always fetched; when update is true, existing bundles are refreshed as well.
+// A bundle that fails to download keeps its previously cached copy. Errors for
+// individual bundles are joined into the returned error.
+func (m *Manager) Sync(ctx context.Context, update bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(m.cacheDir, 0755); err != nil {
+		return fmt.Errorf("failed to create skills cache directory: %w", err)
+	}
+
+	var errs []error
+	for _, b := range m.bundles {
+		var err error
+		if m.offline {
+			if _, statErr := os.Stat(m.bundlePath(b)); statErr != nil {
+				err = fmt.Errorf("not in the cache and offline mode is enabled; copy the bundle to %s", m.bundlePath(b))
+			}
+		} else if b.GitURL != "" {
+			err = m.syncGit(ctx, b, update)
+		} else {
+			err = m.syncTarball(ctx, b, update)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("skill bundle %s: %w", b.Name, err))
+		}
+		m.recordRevision(ctx, b)
+	}
+	return errors.Join(errs...)
+}
+
+// Revision returns the cached revision of a bundle as of the last sync: the git
+// commit for git bundles or the archive SHA-256 for tarball bundles. It returns ""
+// for bundles that are not cached.
+func (m *Manager) Revision(name string) string {
+	m.revMu.RLock()
+	defer m.revMu.RUnlock()
+	return m.revisions[name]
+}
+
+// recordRevision reads and stores the revision of a cached bundle.
+func (m *Manager) recordRevision(ctx context.Context, b Bundle) {
+	path := m.bundlePath(b)
+	var revision string
+	if b.GitURL != "" {
+		// Check for .git first so a plain directory never reports an enclosing repository's commit
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			if out, err := exec.CommandContext(ctx, "git", "-C", path, "rev-parse", "HEAD").Output(); err == nil {
+				revision = strings.TrimSpace(string(out))
+			}
+		}
+	} else if marker, err := readMarker(path); err == nil {
+		revision = "sha256:" + marker.SHA256
+	}
+
+	m.revMu.Lock()
+	defer m.revMu.Unlock()
+	if revision == "" {
+		delete(m.revisions, b.Name)
+		return
+	}
+	m.revisions[b.Name] = revision
+}
+
+// Run refreshes the bundles every interval until ctx is cancelled. Failures are
+// logged and retried on the next tick.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Sync(ctx, true); err != nil {
+				slog.Warn("failed to update skill bundles, keeping cached copies",
+					"error", err)
+			}
+		}
+	}
+}
+
+// bundlePath returns the directory of a bundle in the cache.
+func (m *Manager) bundlePath(b Bundle) string {
+	return filepath.Join(m.cacheDir, b.Name)
+}
+
+// syncGit clones a git bundle, or fetches the configured ref into an existing clone.
+func (m *Manager) syncGit(ctx context.Context, b Bundle, update bool) error {
+	path := m.bundlePath(b)
+	ref := b.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if !update {
+			slog.Debug("skill bundle already cached", "bundle", b.Name, "path", path)
+			return nil
+		}
+		if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+			// Not a git checkout (e.g. copied in by hand); leave it alone
+			slog.Warn("skill bundle is not a git checkout, skipping update",
+				"bundle", b.Name,
+				"path", path)
+			return nil
+		}
+		// Check the fetched commit before it replaces the checked out one
+		if err := runGit(ctx, path, "fetch", "--quiet", "--depth", "1", b.GitURL, ref); err != nil {
+			return err
+		}
+		if err := m.verifyGit(ctx, b, path, "FETCH_HEAD"); err != nil {
+			return err
+		}
+		if err := runGit(ctx, path, "checkout", "--quiet", "--force", "--detach", "FETCH_HEAD"); err != nil {
+			return err
+		}
+		if err := verifyCommit(ctx, path, b.Ref); err != nil {
+			return err
+		}
+		slog.Info("skill bundle updated", "bundle", b.Name, "repo", b.GitURL, "ref", ref)
+		return nil
+	}
+
+	slog.Info("skill bundle not found, cloning",
+		"bundle", b.Name,
+		"repo", b.GitURL,
+		"ref", ref,
+		"target", path)
+
+	tmp, err := os.MkdirTemp(m.cacheDir, "."+b.Name+".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := runGit(ctx, tmp, "init", "--quiet"); err != nil {
+		return err
+	}
+	if err := fetchGit(ctx, tmp, b.GitURL, ref); err != nil {
+		return err
+	}
+	if err := verifyCommit(ctx, tmp, b.Ref); err != nil {
+		return err
+	}
+	if err := m.verifyGit(ctx, b, tmp, "HEAD"); err != nil {
+		return err
+	}
+	if err := replaceDir(tmp, path); err != nil {
+		return err
+	}
+	slog.Info("skill bundle cached", "bundle", b.Name, "path", path)
+	return nil
+}
+
+// fetchGit fetches ref from url into the repository at dir and checks it out.
+func fetchGit(ctx context.Context, dir, url, ref string) error {
+	if err := runGit(ctx, dir, "fetch", "--quiet", "--depth", "1", url, ref); err != nil {
+		return err
+	}
+	return runGit(ctx, dir, "checkout", "--quiet", "--force", "--detach", "FETCH_HEAD")
+}
+
+// verifyCommit checks that the checkout at dir is at ref when ref is a full
+// commit hash. Branch and tag refs are not verified.
+func verifyCommit(ctx context.Context, dir, ref string) error {
+	if !commitPattern.MatchString(ref) {
+		return nil
+	}
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return fmt.Errorf("failed to read checked out commit: %w", err)
+	}
+	if head := strings.TrimSpace(string(out)); head != ref {
+		return fmt.Errorf("integrity check failed: checked out commit %s, want %s", head, ref)
+	}
+	return nil
+}
+
+// verifyGit passes the commit of rev in the repository at dir to the verifier, if
+// any.
+func (m *Manager) verifyGit(ctx context.Context, b Bundle, dir, rev string) error {
+	if m.verifier == nil {
+		return nil
+	}
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "rev-parse", rev).Output()
+	if err != nil {
+		return fmt.Errorf("failed to read fetched commit: %w", err)
+	}
+	return m.verifier.VerifyCommit(ctx, b.Name, b.Ref, strings.TrimSpace(string(out)))
+}
+
+// runGit runs a git command in dir.
+func runGit(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// syncTarball downloads and extracts a tarball bundle. A bundle pinned by checksum
+// is only downloaded when it is missing or the pin changed.
+func (m *Manager) syncTarball(ctx context.Context, b Bundle, update bool) error {
+	path := m.bundlePath(b)
+	if marker, err := readMarker(path); err == nil {
+		current := marker.Source == b.TarballURL && (b.SHA256 == "" || strings.EqualFold(marker.SHA256, b.SHA256))
+		pinned := b.SHA256 != ""
+		if current && (!update || pinned) {
+			slog.Debug("skill bundle already cached", "bundle", b.Name, "path", path)
+			return nil
+		}
+	}
+
+	slog.Info("downloading skill bundle",
+		"bundle", b.Name,
+		"url", b.TarballURL,
+		"target", path)
+
+	archive, err := os.CreateTemp(m.cacheDir, "."+b.Name+".download-")
+	if err != nil {
+		return fmt.Errorf("failed to create download file: %w", err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	sum, err := m.download(ctx, b.TarballURL, archive)
+	if err != nil {
+		return err
+	}
+	if b.SHA256 != "" && !strings.EqualFold(sum, b.SHA256) {
+		return fmt.Errorf("integrity check failed: archive sha256 %s, want %s", sum, strings.ToLower(b.SHA256))
+	}
+	if m.verifier != nil {
+		if err := m.verifier.VerifyArchive(ctx, b.Name, archive.Name(), m.downloadSignature(ctx, b)); err != nil {
+			return err
+		}
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind archive: %w", err)
+	}
+
+	tmp, err := os.MkdirTemp(m.cacheDir, "."+b.Name+".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := extractTarGz(archive, tmp); err != nil {
+		return err
+	}
+	root := archiveRoot(tmp)
+	if err := writeMarker(root, bundleMarker{Source: b.TarballURL, SHA256: sum}); err != nil {
+		return err
+	}
+	if err := replaceDir(root, path); err != nil {
+		return err
+	}
+	slog.Info("skill bundle cached", "bundle", b.Name, "path", path, "sha256", sum)
+	return nil
+}
+
+// downloadSignature returns the tarball's signature bundle, or nil when it cannot
+// be downloaded.
+func (m *Manager) downloadSignature(ctx context.Context, b Bundle) []byte {
+	var signature bytes.Buffer
+	if _, err := m.download(ctx, b.signatureURL(), &signature); err != nil {
+		slog.Warn("failed to download skill bundle signature",
+			"bundle", b.Name,
+			"url", b.signatureURL(),
+			"error", err)
+		return nil
+	}
+	return signature.Bytes()
+}
+
+// download writes url to w and returns the hex SHA-256 of the content.
+func (m *Manager) download(ctx context.Context, url string, w io.Writer) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download archive: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download archive: HTTP %d", resp.StatusCode)
+	}
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, h), io.LimitReader(resp.Body, maxTarballSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to download archive: %w", err)
+	}
+	if n > maxTarballSize {
+		return "", fmt.Errorf("archive exceeds %d bytes", maxTarballSize)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// extractTarGz extracts a .tar.gz archive into dir. Entries that would escape dir
+// are rejected; entries other than directories and regular files are skipped.
+func extractTarGz(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if name == "." {
+			continue
+		}
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %q escapes the bundle directory", hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, hdr.FileInfo().Mode().Perm())
+			if err != nil {
+				return fmt.Errorf("failed to create file: %w", err)
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return fmt.Errorf("failed to extract %s: %w", hdr.Name, err)
+			}
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("failed to extract %s: %w", hdr.Name, err)
+			}
+		default:
+			slog.Debug("skipping unsupported archive entry",
+				"entry", hdr.Name,
+				"type", string(hdr.Typeflag))
+		}
+	}
+}
+
+// archiveRoot returns the single top-level directory of an extracted archive (as
+// in GitHub release tarballs), or dir itself when the archive has several entries.
+func archiveRoot(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return dir
+	}
+	return filepath.Join(dir, entries[0].Name())
+}
+
+// replaceDir moves src to dst, replacing any existing dst.
+func replaceDir(src, dst string) error {
+	if err := os.RemoveAll(dst); err != nil {
+		return fmt.Errorf("failed to remove previous bundle: %w", err)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("failed to install bundle: %w", err)
+	}
+	return nil
+}
+
+// readMarker reads the marker of a cached tarball bundle.
+func readMarker(path string) (bundleMarker, error) {
+	var marker bundleMarker
+	data, err := os.ReadFile(filepath.Join(path, markerFile))
+	if err != nil {
+		return marker, err
+	}
+	err = json.Unmarshal(data, &marker)
+	return marker, err
+}
+
+// writeMarker records the source and checksum of a tarball bundle.
+func writeMarker(path string, marker bundleMarker) error {
+	data, err := json.Marshal(marker)
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle marker: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(path, markerFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write bundle marker: %w", err)
+	}
+	return nil
+}
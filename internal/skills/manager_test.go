@@ -0,0 +1,285 @@
+package skills
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// makeTarGz builds a .tar.gz archive from name -> content entries.
+func makeTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("failed to write tar header: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write tar content: %v", err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// serveArchive serves archive and counts downloads.
+func serveArchive(t *testing.T, archive []byte) (*httptest.Server, *int32) {
+	t.Helper()
+	var downloads int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&downloads, 1)
+		w.Write(archive)
+	}))
+	t.Cleanup(server.Close)
+	return server, &downloads
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestManager_TarballBundle(t *testing.T) {
+	archive := makeTarGz(t, map[string]string{
+		"runbooks-1.0/SKILL.md":          "# Runbooks",
+		"runbooks-1.0/scripts/triage.sh": "#!/bin/sh\n",
+	})
+	server, downloads := serveArchive(t, archive)
+	cacheDir := t.TempDir()
+
+	m := NewManager(cacheDir, []Bundle{{Name: "runbooks", TarballURL: server.URL, SHA256: sha256Hex(archive)}})
+	if err := m.Sync(context.Background(), false); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	// The archive's single top-level directory becomes the bundle directory
+	data, err := os.ReadFile(filepath.Join(cacheDir, "runbooks", "SKILL.md"))
+	if err != nil || string(data) != "# Runbooks" {
+		t.Fatalf("SKILL.md = %q, %v", data, err)
+	}
+	info, err := os.Stat(filepath.Join(cacheDir, "runbooks", "scripts", "triage.sh"))
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("triage.sh should be extracted executable: %v, %v", info, err)
+	}
+
+	if got := m.Revision("runbooks"); got != "sha256:"+sha256Hex(archive) {
+		t.Errorf("Revision() = %q, want archive checksum", got)
+	}
+
+	// A bundle pinned by checksum is not downloaded again, even on update
+	if err := m.Sync(context.Background(), true); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if got := atomic.LoadInt32(downloads); got != 1 {
+		t.Errorf("downloads = %d, want 1", got)
+	}
+}
+
+func TestManager_TarballChecksumMismatch(t *testing.T) {
+	archive := makeTarGz(t, map[string]string{"SKILL.md": "tampered"})
+	server, _ := serveArchive(t, archive)
+	cacheDir := t.TempDir()
+
+	m := NewManager(cacheDir, []Bundle{{Name: "runbooks", TarballURL: server.URL, SHA256: strings.Repeat("0", 64)}})
+	err := m.Sync(context.Background(), false)
+	if err == nil || !strings.Contains(err.Error(), "integrity check failed") {
+		t.Fatalf("Sync() error = %v, want integrity failure", err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "runbooks")); !os.IsNotExist(err) {
+		t.Error("bundle should not be installed when the checksum does not match")
+	}
+}
+
+// fakeVerifier records the signatures it is asked to check and rejects them all
+// when reject is set.
+type fakeVerifier struct {
+	signatures []string
+	reject     bool
+}
+
+func (f *fakeVerifier) VerifyArchive(ctx context.Context, name, archivePath string, signature []byte) error {
+	f.signatures = append(f.signatures, string(signature))
+	if f.reject {
+		return errors.New("rejected")
+	}
+	return nil
+}
+
+func (f *fakeVerifier) VerifyCommit(ctx context.Context, name, ref, commit string) error {
+	return nil
+}
+
+func TestManager_TarballVerifier(t *testing.T) {
+	archive := makeTarGz(t, map[string]string{"SKILL.md": "# Runbooks"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".sigstore.json") {
+			w.Write([]byte("signature bundle"))
+			return
+		}
+		w.Write(archive)
+	}))
+	t.Cleanup(server.Close)
+	cacheDir := t.TempDir()
+
+	verifier := &fakeVerifier{reject: true}
+	m := NewManager(cacheDir, []Bundle{{Name: "runbooks", TarballURL: server.URL + "/runbooks.tar.gz"}})
+	m.SetVerifier(verifier)
+	if err := m.Sync(context.Background(), false); err == nil {
+		t.Fatal("Sync() should fail when the verifier rejects the archive")
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "runbooks")); !os.IsNotExist(err) {
+		t.Error("a rejected bundle should not be installed")
+	}
+	if len(verifier.signatures) != 1 || verifier.signatures[0] != "signature bundle" {
+		t.Errorf("verifier got signatures %q, want the downloaded signature bundle", verifier.signatures)
+	}
+
+	verifier.reject = false
+	if err := m.Sync(context.Background(), false); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "runbooks", "SKILL.md")); err != nil {
+		t.Errorf("verified bundle should be installed: %v", err)
+	}
+}
+
+func TestManager_Offline(t *testing.T) {
+	archive := makeTarGz(t, map[string]string{"SKILL.md": "# Runbooks"})
+	server, downloads := serveArchive(t, archive)
+	cacheDir := t.TempDir()
+
+	m := NewManager(cacheDir, []Bundle{{Name: "runbooks", TarballURL: server.URL}})
+	m.SetOffline(true)
+	err := m.Sync(context.Background(), true)
+	if err == nil || !strings.Contains(err.Error(), "offline mode") {
+		t.Fatalf("Sync() error = %v, want missing bundle in offline mode", err)
+	}
+
+	// A pre-seeded bundle is used as is
+	if err := os.MkdirAll(filepath.Join(cacheDir, "runbooks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Sync(context.Background(), true); err != nil {
+		t.Fatalf("Sync() with pre-seeded bundle error = %v", err)
+	}
+	if got := atomic.LoadInt32(downloads); got != 0 {
+		t.Errorf("downloads = %d, want none in offline mode", got)
+	}
+}
+
+func TestManager_TarballRejectsPathTraversal(t *testing.T) {
+	archive := makeTarGz(t, map[string]string{"../escape.sh": "#!/bin/sh\n"})
+	server, _ := serveArchive(t, archive)
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+
+	m := NewManager(cacheDir, []Bundle{{Name: "evil", TarballURL: server.URL}})
+	if err := m.Sync(context.Background(), false); err == nil {
+		t.Fatal("Sync() should reject archive entries outside the bundle directory")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(cacheDir), "escape.sh")); !os.IsNotExist(err) {
+		t.Error("archive entry escaped the cache directory")
+	}
+}
+
+func TestManager_GitBundle(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	// Build a local source repository
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(content string) string {
+		if err := os.WriteFile(filepath.Join(repo, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "SKILL.md")
+		git("commit", "--quiet", "-m", content)
+		return git("rev-parse", "HEAD")
+	}
+	git("init", "--quiet")
+	first := commit("v1")
+
+	cacheDir := t.TempDir()
+	m := NewManager(cacheDir, []Bundle{{Name: "skill", GitURL: "file://" + repo}})
+	if err := m.Sync(context.Background(), false); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	readSkill := func() string {
+		data, _ := os.ReadFile(filepath.Join(cacheDir, "skill", "SKILL.md"))
+		return string(data)
+	}
+	if got := readSkill(); got != "v1" {
+		t.Fatalf("SKILL.md = %q, want v1", got)
+	}
+
+	// Without update the cached copy is kept; with update the new commit is fetched
+	commit("v2")
+	m.Sync(context.Background(), false)
+	if got := readSkill(); got != "v1" {
+		t.Errorf("SKILL.md = %q, want v1 without update", got)
+	}
+	if err := m.Sync(context.Background(), true); err != nil {
+		t.Fatalf("Sync(update) error = %v", err)
+	}
+	if got := readSkill(); got != "v2" {
+		t.Errorf("SKILL.md = %q, want v2 after update", got)
+	}
+	if got := m.Revision("skill"); got != git("rev-parse", "HEAD") {
+		t.Errorf("Revision() = %q, want the fetched commit", got)
+	}
+
+	// A commit ref pins the bundle
+	pinned := NewManager(t.TempDir(), []Bundle{{Name: "skill", GitURL: "file://" + repo, Ref: first}})
+	git("config", "uploadpack.allowReachableSHA1InWant", "true")
+	if err := pinned.Sync(context.Background(), false); err != nil {
+		t.Fatalf("Sync(pinned) error = %v", err)
+	}
+}
+
+func TestBundle_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		bundle  Bundle
+		wantErr bool
+	}{
+		{"git", Bundle{Name: "k8s4agents", GitURL: K8sSkillRepo, Ref: "main"}, false},
+		{"tarball", Bundle{Name: "runbooks", TarballURL: "https://example.com/r.tar.gz", SHA256: strings.Repeat("a", 64)}, false},
+		{"no source", Bundle{Name: "x"}, true},
+		{"both sources", Bundle{Name: "x", GitURL: K8sSkillRepo, TarballURL: "https://example.com/r.tar.gz"}, true},
+		{"unsafe name", Bundle{Name: "../x", GitURL: K8sSkillRepo}, true},
+		{"bad checksum", Bundle{Name: "x", TarballURL: "https://example.com/r.tar.gz", SHA256: "abc"}, true},
+		{"checksum on git", Bundle{Name: "x", GitURL: K8sSkillRepo, SHA256: strings.Repeat("a", 64)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.bundle.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
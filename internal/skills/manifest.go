@@ -0,0 +1,157 @@
+package skills
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+// Skill is a manifest entry: a bundle plus the metadata used to select it for a
+// cluster and to record it on incidents.
+type Skill struct {
+	Bundle
+
+	// Description says what the skill provides (triage scripts, runbooks, vendor helpers)
+	Description string
+
+	// Version is the declared version recorded on incidents, e.g. "1.4.0"
+	Version string
+
+	// ClusterLabels restricts the skill to clusters whose labels contain every
+	// key/value pair. An empty selector makes the skill available to all clusters.
+	ClusterLabels map[string]string
+}
+
+// Matches reports whether the skill is selected for a cluster with the given labels.
+func (s Skill) Matches(labels map[string]string) bool {
+	for key, value := range s.ClusterLabels {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Manifest lists the skills available to agents.
+type Manifest struct {
+	Skills []Skill
+}
+
+// manifestEntry is the on-disk form of a manifest skill.
+type manifestEntry struct {
+	Name          string            `mapstructure:"name"`
+	Description   string            `mapstructure:"description"`
+	Version       string            `mapstructure:"version"`
+	Git           string            `mapstructure:"git"`
+	Ref           string            `mapstructure:"ref"`
+	URL           string            `mapstructure:"url"`
+	SHA256        string            `mapstructure:"sha256"`
+	Signature     string            `mapstructure:"signature"`
+	ClusterLabels map[string]string `mapstructure:"cluster_labels"`
+}
+
+// NewManifest creates a manifest of bundles that are available to every cluster.
+func NewManifest(bundles []Bundle) *Manifest {
+	m := &Manifest{}
+	for _, b := range bundles {
+		m.Skills = append(m.Skills, Skill{Bundle: b})
+	}
+	return m
+}
+
+// LoadManifest reads a skills manifest file (YAML or JSON) of the form:
+//
+//	skills:
+//	  - name: k8s4agents
+//	    description: Kubernetes triage scripts
+//	    git: https://github.com/randybias/k8s4agents
+//	    ref: main
+//	  - name: aws-helpers
+//	    version: 1.2.0
+//	    url: https://artifacts.example.com/skills/aws-helpers-1.2.0.tar.gz
+//	    sha256: 9f86d081...
+//	    signature: https://artifacts.example.com/skills/aws-helpers-1.2.0.tar.gz.sigstore.json
+//	    cluster_labels:
+//	      cloud: aws
+//
+// The manifest is validated before it is returned.
+func LoadManifest(path string) (*Manifest, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read skills manifest %s: %w", path, err)
+	}
+
+	var entries []manifestEntry
+	if err := v.UnmarshalKey("skills", &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse skills manifest %s: %w", path, err)
+	}
+
+	m := &Manifest{}
+	for _, e := range entries {
+		m.Skills = append(m.Skills, Skill{
+			Bundle: Bundle{
+				Name:         e.Name,
+				GitURL:       e.Git,
+				Ref:          e.Ref,
+				TarballURL:   e.URL,
+				SHA256:       e.SHA256,
+				SignatureURL: e.Signature,
+			},
+			Description:   e.Description,
+			Version:       e.Version,
+			ClusterLabels: e.ClusterLabels,
+		})
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid skills manifest %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Validate checks every skill and that skill names are unique.
+func (m *Manifest) Validate() error {
+	seen := make(map[string]bool)
+	for _, s := range m.Skills {
+		if err := s.Bundle.Validate(); err != nil {
+			return err
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("skill bundle name %q is used more than once", s.Name)
+		}
+		seen[s.Name] = true
+	}
+	return nil
+}
+
+// Bundles returns the bundles of every skill, for downloading.
+func (m *Manifest) Bundles() []Bundle {
+	bundles := make([]Bundle, 0, len(m.Skills))
+	for _, s := range m.Skills {
+		bundles = append(bundles, s.Bundle)
+	}
+	return bundles
+}
+
+// ForCluster returns the skills selected for a cluster with the given labels,
+// ordered by name.
+func (m *Manifest) ForCluster(labels map[string]string) []Skill {
+	var selected []Skill
+	for _, s := range m.Skills {
+		if s.Matches(labels) {
+			selected = append(selected, s)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Name < selected[j].Name })
+	return selected
+}
+
+// Names returns the names of skills.
+func Names(skills []Skill) []string {
+	names := make([]string, 0, len(skills))
+	for _, s := range skills {
+		names = append(names, s.Name)
+	}
+	return names
+}
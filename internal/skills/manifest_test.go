@@ -0,0 +1,83 @@
+package skills
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "skills.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+	return path
+}
+
+func TestLoadManifest(t *testing.T) {
+	path := writeManifest(t, `
+skills:
+  - name: k8s4agents
+    description: Kubernetes triage scripts
+    git: https://github.com/randybias/k8s4agents
+    ref: main
+  - name: aws-helpers
+    version: 1.2.0
+    url: https://artifacts.example.com/skills/aws-helpers-1.2.0.tar.gz
+    sha256: `+strings.Repeat("a", 64)+`
+    cluster_labels:
+      cloud: aws
+  - name: prod-runbooks
+    url: https://artifacts.example.com/skills/prod-runbooks.tar.gz
+    cluster_labels:
+      cloud: aws
+      tier: production
+`)
+
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	if len(m.Skills) != 3 || m.Skills[1].Version != "1.2.0" || m.Skills[1].TarballURL == "" {
+		t.Fatalf("Skills = %+v", m.Skills)
+	}
+	if len(m.Bundles()) != 3 {
+		t.Errorf("Bundles() = %d, want 3", len(m.Bundles()))
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{"no labels", nil, "k8s4agents"},
+		{"aws staging", map[string]string{"cloud": "aws", "tier": "staging"}, "aws-helpers,k8s4agents"},
+		{"aws production", map[string]string{"cloud": "aws", "tier": "production"}, "aws-helpers,k8s4agents,prod-runbooks"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(Names(m.ForCluster(tt.labels)), ","); got != tt.want {
+				t.Errorf("ForCluster() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadManifest_Invalid(t *testing.T) {
+	path := writeManifest(t, `
+skills:
+  - name: runbooks
+    url: https://example.com/a.tar.gz
+  - name: runbooks
+    url: https://example.com/b.tar.gz
+`)
+	if _, err := LoadManifest(path); err == nil {
+		t.Error("LoadManifest() should reject duplicate skill names")
+	}
+
+	if _, err := LoadManifest(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadManifest() should fail for a missing file")
+	}
+}
@@ -0,0 +1,82 @@
+package slackapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultAPIURL is the base URL of the Slack Web API
+const DefaultAPIURL = "https://slack.com/api/"
+
+// apiResponse is the envelope of every Slack Web API response.
+type apiResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// connectionsOpenResponse is the response of apps.connections.open.
+type connectionsOpenResponse struct {
+	apiResponse
+	URL string `json:"url"`
+}
+
+// apiClient calls Slack Web API methods.
+type apiClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// call posts a request to a Web API method with a token and decodes the response
+// into out, which must embed apiResponse. A nil request posts an empty form.
+func (c *apiClient) call(ctx context.Context, method, token string, request, out interface{}) error {
+	var body io.Reader = strings.NewReader("")
+	contentType := "application/x-www-form-urlencoded"
+	if request != nil {
+		payload, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s request: %w", method, err)
+		}
+		body = bytes.NewReader(payload)
+		contentType = "application/json; charset=utf-8"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.baseURL, "/")+"/"+method, body)
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned status %d: %s", method, resp.StatusCode, text)
+	}
+
+	var envelope apiResponse
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", method, err)
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if !envelope.OK {
+		return fmt.Errorf("%s failed: %s", method, envelope.Error)
+	}
+	if out != nil {
+		if err := json.Unmarshal(raw, out); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", method, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+// Package slackapp provides a Slack app connected over Socket Mode. Its App Home
+// tab lists active and recent incidents grouped by owning team, and the
+// /nightcrier slash command reports status, silences namespaces, and pauses
+// triage. Socket Mode keeps a WebSocket open to Slack, so nightcrier needs no
+// public endpoint. Incidents and silences live in the state store.
+package slackapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/pause"
+	"github.com/rbias/nightcrier/internal/silence"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// Reconnect backoff after a failed or dropped Socket Mode connection
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 2 * time.Minute
+)
+
+// handlerTimeout bounds the work done for one Slack event or command
+const handlerTimeout = 30 * time.Second
+
+// Config configures the Slack app.
+type Config struct {
+	// AppToken is the app-level token used to open Socket Mode connections (xapp-...)
+	AppToken string
+	// BotToken is the bot token used to call Web API methods (xoxb-...)
+	BotToken string
+	// APIURL is the Web API base URL (default DefaultAPIURL)
+	APIURL string
+	// UserTeams maps Slack user IDs to the owning teams whose incidents they see
+	// first
+	UserTeams map[string][]string
+	// AllowedUsers may silence namespaces, lift silences, and pause triage (empty:
+	// nobody)
+	AllowedUsers []string
+	// RecentWindow is how far back finished investigations are listed
+	RecentWindow time.Duration
+}
+
+// IncidentStore is the part of the state store the app reads incidents from.
+type IncidentStore interface {
+	ListIncidents(ctx context.Context, filters *storage.IncidentFilters) ([]*incident.Incident, error)
+}
+
+// App is the Slack app.
+type App struct {
+	config    Config
+	incidents IncidentStore
+	silences  *silence.Registry
+	pauses    *pause.Switch
+	api       *apiClient
+	proxy     func(*http.Request) (*url.URL, error)
+	now       func() time.Time
+}
+
+// New returns a Slack app. A nil transport uses http.DefaultTransport; its proxy
+// is also used for the Socket Mode connection.
+func New(config Config, incidents IncidentStore, silences *silence.Registry, transport *http.Transport) *App {
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+	if transport == nil {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	return &App{
+		config:    config,
+		incidents: incidents,
+		silences:  silences,
+		api:       &apiClient{baseURL: config.APIURL, httpClient: &http.Client{Transport: transport, Timeout: handlerTimeout}},
+		proxy:     transport.Proxy,
+		now:       time.Now,
+	}
+}
+
+// SetTriagePause enables the pause and resume commands, which pause triage
+// globally or per cluster, and shows where triage is paused. Call before Run.
+func (a *App) SetTriagePause(pauses *pause.Switch) {
+	a.pauses = pauses
+}
+
+// Run keeps a Socket Mode connection open and handles Slack events and commands
+// until the context is cancelled, reconnecting with backoff when the connection
+// fails or Slack asks for a reconnect.
+func (a *App) Run(ctx context.Context) {
+	delay := minReconnectDelay
+	for ctx.Err() == nil {
+		connected, err := a.serve(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			delay = minReconnectDelay
+		}
+		if err != nil {
+			slog.Warn("slack app connection lost, reconnecting", "error", err, "delay", delay)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxReconnectDelay)
+	}
+}
+
+// serve runs one Socket Mode connection. It reports whether Slack accepted the
+// connection, and returns when the connection fails, Slack asks for a reconnect,
+// or the context is cancelled.
+func (a *App) serve(ctx context.Context) (bool, error) {
+	var opened connectionsOpenResponse
+	if err := a.api.call(ctx, "apps.connections.open", a.config.AppToken, nil, &opened); err != nil {
+		return false, err
+	}
+	ws, err := dialWebSocket(ctx, opened.URL, a.proxy)
+	if err != nil {
+		return false, err
+	}
+	conn := &socketConn{ws: ws}
+
+	// Unblock the read below on shutdown
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			ws.Close()
+		case <-done:
+			ws.Close()
+		}
+	}()
+
+	connected := false
+	for {
+		env, err := conn.receive()
+		if err != nil {
+			if ctx.Err() != nil {
+				return connected, nil
+			}
+			return connected, fmt.Errorf("failed to read from Slack: %w", err)
+		}
+		switch env.Type {
+		case "hello":
+			connected = true
+			slog.Info("slack app connected")
+		case "disconnect":
+			slog.Info("slack app reconnecting at Slack's request", "reason", env.Reason)
+			return connected, nil
+		default:
+			go a.handle(ctx, conn, env)
+		}
+	}
+}
+
+// handle acknowledges and handles one envelope.
+func (a *App) handle(ctx context.Context, conn *socketConn, env *envelope) {
+	ctx, cancel := context.WithTimeout(ctx, handlerTimeout)
+	defer cancel()
+
+	switch env.Type {
+	case "slash_commands":
+		var cmd slashCommand
+		if err := json.Unmarshal(env.Payload, &cmd); err != nil {
+			slog.Warn("invalid slack slash command", "error", err)
+			a.acknowledge(conn, env, nil)
+			return
+		}
+		// The response is the ack payload, so it must be ready within 3 seconds
+		replyCtx, cancelReply := context.WithTimeout(ctx, 2500*time.Millisecond)
+		defer cancelReply()
+		a.acknowledge(conn, env, a.handleCommand(replyCtx, cmd))
+
+	case "events_api":
+		a.acknowledge(conn, env, nil)
+		var callback eventCallback
+		if err := json.Unmarshal(env.Payload, &callback); err != nil {
+			slog.Warn("invalid slack event", "error", err)
+			return
+		}
+		if callback.Event.Type == "app_home_opened" && callback.Event.Tab == "home" {
+			if err := a.publishHome(ctx, callback.Event.User); err != nil {
+				slog.Warn("failed to publish slack app home", "user", callback.Event.User, "error", err)
+			}
+		}
+
+	default:
+		if env.EnvelopeID != "" {
+			a.acknowledge(conn, env, nil)
+		}
+	}
+}
+
+// acknowledge acks an envelope with an optional response payload.
+func (a *App) acknowledge(conn *socketConn, env *envelope, payload interface{}) {
+	if err := conn.send(ack{EnvelopeID: env.EnvelopeID, Payload: payload}); err != nil {
+		slog.Warn("failed to acknowledge slack envelope", "type", env.Type, "error", err)
+	}
+}
+
+// eventCallback is the payload of an events_api envelope.
+type eventCallback struct {
+	Event struct {
+		Type string `json:"type"`
+		User string `json:"user"`
+		Tab  string `json:"tab"`
+	} `json:"event"`
+}
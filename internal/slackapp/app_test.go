@@ -0,0 +1,359 @@
+package slackapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/pause"
+	"github.com/rbias/nightcrier/internal/silence"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// fakeStore is an in-memory incident and silence store.
+type fakeStore struct {
+	mu        sync.Mutex
+	incidents []*incident.Incident
+	silences  map[string]*storage.Silence
+}
+
+func newFakeStore(incidents ...*incident.Incident) *fakeStore {
+	return &fakeStore{incidents: incidents, silences: make(map[string]*storage.Silence)}
+}
+
+func (f *fakeStore) ListIncidents(ctx context.Context, filters *storage.IncidentFilters) ([]*incident.Incident, error) {
+	var matched []*incident.Incident
+	for _, inc := range f.incidents {
+		statusMatches := false
+		for _, status := range filters.Status {
+			statusMatches = statusMatches || inc.Status == status
+		}
+		if statusMatches && (filters.CreatedAfter == nil || inc.CreatedAt.After(*filters.CreatedAfter)) {
+			matched = append(matched, inc)
+		}
+	}
+	return matched, nil
+}
+
+func (f *fakeStore) CreateSilence(ctx context.Context, s *storage.Silence) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copied := *s
+	f.silences[s.SilenceID] = &copied
+	return nil
+}
+
+func (f *fakeStore) LiftSilence(ctx context.Context, silenceID, liftedBy string, liftedAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.silences[silenceID]
+	if !ok || !s.Active(liftedAt) {
+		return fmt.Errorf("no active silence %s", silenceID)
+	}
+	s.LiftedAt, s.LiftedBy = &liftedAt, liftedBy
+	return nil
+}
+
+func (f *fakeStore) ListSilences(ctx context.Context, activeAt time.Time) ([]*storage.Silence, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var active []*storage.Silence
+	for _, s := range f.silences {
+		if s.Active(activeAt) {
+			active = append(active, s)
+		}
+	}
+	return active, nil
+}
+
+// testIncidents returns active and recent incidents of two teams and one unowned.
+func testIncidents(now time.Time) []*incident.Incident {
+	return []*incident.Incident{
+		{IncidentID: "i1", DisplayID: "NC-1", Status: incident.StatusInvestigating, Cluster: "prod", Namespace: "shop", FaultType: "CrashLoop", Severity: "critical",
+			Resource: &incident.ResourceInfo{Kind: "Pod", Name: "checkout-1"}, CreatedAt: now.Add(-10 * time.Minute), Labels: map[string]string{"team": "payments"}},
+		{IncidentID: "i2", DisplayID: "NC-2", Status: incident.StatusPending, Cluster: "prod", Namespace: "search", FaultType: "OOMKilled", Severity: "warning",
+			CreatedAt: now.Add(-2 * time.Minute), Labels: map[string]string{"team": "Search"}},
+		{IncidentID: "i3", DisplayID: "NC-3", Status: incident.StatusPending, Cluster: "prod", Namespace: "misc", FaultType: "ImagePull", Severity: "info",
+			CreatedAt: now.Add(-time.Minute)},
+		{IncidentID: "i4", DisplayID: "NC-4", Status: incident.StatusResolved, Cluster: "prod", Namespace: "shop", FaultType: "CrashLoop", Severity: "critical",
+			CreatedAt: now.Add(-3 * time.Hour), Labels: map[string]string{"team": "payments"}},
+		{IncidentID: "i5", DisplayID: "NC-5", Status: incident.StatusResolved, Cluster: "prod", Namespace: "shop", FaultType: "CrashLoop", Severity: "critical",
+			CreatedAt: now.Add(-48 * time.Hour), Labels: map[string]string{"team": "payments"}},
+	}
+}
+
+func newTestApp(store *fakeStore, config Config) *App {
+	if config.RecentWindow == 0 {
+		config.RecentWindow = 24 * time.Hour
+	}
+	return New(config, store, silence.NewRegistry(store), nil)
+}
+
+func TestHandleCommand_Status(t *testing.T) {
+	store := newFakeStore(testIncidents(time.Now())...)
+	app := newTestApp(store, Config{UserTeams: map[string][]string{"U1": {"Payments"}}})
+
+	resp := app.handleCommand(context.Background(), slashCommand{Command: "/nightcrier", Text: "status", UserID: "U1"})
+	if resp.ResponseType != "ephemeral" {
+		t.Errorf("ResponseType = %q, want ephemeral", resp.ResponseType)
+	}
+	for _, want := range []string{"3 active investigations", "1 finished in the last 24h", ":star: *payments* (1)", "NC-1", "Other teams: search: 1, unowned: 1", "No namespaces are silenced"} {
+		if !strings.Contains(resp.Text, want) {
+			t.Errorf("status response missing %q:\n%s", want, resp.Text)
+		}
+	}
+}
+
+func TestHandleCommand_Silence(t *testing.T) {
+	store := newFakeStore()
+	app := newTestApp(store, Config{AllowedUsers: []string{"U1"}})
+	ctx := context.Background()
+
+	resp := app.handleCommand(ctx, slashCommand{Command: "/nightcrier", Text: "silence ns/foo 2h noisy deploy", UserID: "U1"})
+	if resp.ResponseType != "in_channel" || !strings.Contains(resp.Text, "silenced ns/foo for 2h") {
+		t.Fatalf("silence response = %+v", resp)
+	}
+	if s := app.silences.Match("prod", "foo"); s == nil || s.Reason != "noisy deploy" || s.CreatedBy != "slack:U1" {
+		t.Fatalf("Match(prod, foo) = %+v, want the new silence", s)
+	}
+	if len(store.silences) != 1 {
+		t.Errorf("stored silences = %d, want 1", len(store.silences))
+	}
+	silenceID := app.silences.Match("prod", "foo").SilenceID
+
+	if resp := app.handleCommand(ctx, slashCommand{Text: "silences", UserID: "U2"}); !strings.Contains(resp.Text, silenceID) || !strings.Contains(resp.Text, "<@U1>") {
+		t.Errorf("silences response = %q, want the silence", resp.Text)
+	}
+
+	for _, text := range []string{"silence ns/foo", "silence foo 2h", "silence ns/foo forever", "silence ns/foo 30d"} {
+		if resp := app.handleCommand(ctx, slashCommand{Text: text, UserID: "U1"}); resp.ResponseType != "ephemeral" {
+			t.Errorf("%q should be rejected, got %+v", text, resp)
+		}
+	}
+	if resp := app.handleCommand(ctx, slashCommand{Text: "unsilence " + silenceID, UserID: "U2"}); !strings.Contains(resp.Text, "not allowed") {
+		t.Errorf("unsilence by a user outside allowed_users = %q, want refusal", resp.Text)
+	}
+
+	if resp := app.handleCommand(ctx, slashCommand{Text: "unsilence " + silenceID, UserID: "U1"}); resp.ResponseType != "in_channel" {
+		t.Fatalf("unsilence response = %+v", resp)
+	}
+	if app.silences.Match("prod", "foo") != nil {
+		t.Error("the silence should be lifted")
+	}
+	if resp := app.handleCommand(ctx, slashCommand{Text: "unsilence " + silenceID, UserID: "U1"}); !strings.Contains(resp.Text, "Could not lift") {
+		t.Errorf("lifting twice = %q, want an error", resp.Text)
+	}
+}
+
+func TestHandleCommand_Pause(t *testing.T) {
+	store := newFakeStore(testIncidents(time.Now())...)
+	app := newTestApp(store, Config{AllowedUsers: []string{"U1"}})
+	ctx := context.Background()
+
+	if resp := app.handleCommand(ctx, slashCommand{Text: "pause all", UserID: "U1"}); !strings.Contains(resp.Text, "not available") {
+		t.Errorf("pause without a pause switch = %q, want unavailable", resp.Text)
+	}
+
+	pauses, err := pause.Open(filepath.Join(t.TempDir(), "triage-pause.json"), []string{"prod", "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.SetTriagePause(pauses)
+
+	if resp := app.handleCommand(ctx, slashCommand{Text: "pause prod", UserID: "U2"}); !strings.Contains(resp.Text, "not allowed") {
+		t.Errorf("pause by a user outside allowed_users = %q, want refusal", resp.Text)
+	}
+	resp := app.handleCommand(ctx, slashCommand{Text: "pause prod cluster upgrade", UserID: "U1"})
+	if resp.ResponseType != "in_channel" || !strings.Contains(resp.Text, "paused triage on cluster prod") {
+		t.Fatalf("pause response = %+v", resp)
+	}
+	if p := pauses.Paused("prod"); p == nil || p.Reason != "cluster upgrade" || p.PausedBy != "slack:U1" {
+		t.Errorf("Paused(prod) = %+v, want the Slack pause", p)
+	}
+	if resp := app.handleCommand(ctx, slashCommand{Text: "pause dev", UserID: "U1"}); !strings.Contains(resp.Text, "unknown cluster") {
+		t.Errorf("pause of an unknown cluster = %q, want an error", resp.Text)
+	}
+
+	status := app.handleCommand(ctx, slashCommand{Text: "status", UserID: "U1"})
+	if !strings.Contains(status.Text, "Triage paused on prod") || !strings.Contains(status.Text, "<@U1>") {
+		t.Errorf("status response should show the pause:\n%s", status.Text)
+	}
+	blocks, err := app.homeBlocks(ctx, "U1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := json.Marshal(blocks); !strings.Contains(string(raw), "Triage paused on prod") {
+		t.Errorf("home should show the pause:\n%s", raw)
+	}
+
+	if resp := app.handleCommand(ctx, slashCommand{Text: "resume all", UserID: "U1"}); !strings.Contains(resp.Text, "Could not resume") {
+		t.Errorf("resume all without a global pause = %q, want an error", resp.Text)
+	}
+	if resp := app.handleCommand(ctx, slashCommand{Text: "resume prod", UserID: "U1"}); resp.ResponseType != "in_channel" {
+		t.Fatalf("resume response = %+v", resp)
+	}
+	if pauses.Paused("prod") != nil {
+		t.Error("triage on prod should be resumed")
+	}
+}
+
+func TestHandleCommand_NoAllowedUsers(t *testing.T) {
+	app := newTestApp(newFakeStore(), Config{})
+	ctx := context.Background()
+	for _, text := range []string{"silence ns/foo 2h", "pause prod"} {
+		if resp := app.handleCommand(ctx, slashCommand{Text: text, UserID: "U1"}); !strings.Contains(resp.Text, "not allowed") {
+			t.Errorf("%q without allowed_users = %q, want refusal", text, resp.Text)
+		}
+	}
+	if app.silences.Match("prod", "foo") != nil {
+		t.Error("no silence should be created without allowed_users")
+	}
+}
+
+func TestHandleCommand_Help(t *testing.T) {
+	app := newTestApp(newFakeStore(), Config{})
+	for _, text := range []string{"", "help", "bogus"} {
+		resp := app.handleCommand(context.Background(), slashCommand{Command: "/nc", Text: text, UserID: "U1"})
+		if !strings.Contains(resp.Text, "`/nc silence ns/<namespace> <duration> [reason]`") {
+			t.Errorf("%q response = %q, want usage", text, resp.Text)
+		}
+	}
+}
+
+func TestHomeBlocks(t *testing.T) {
+	store := newFakeStore(testIncidents(time.Now())...)
+	app := newTestApp(store, Config{UserTeams: map[string][]string{"U1": {"search"}}})
+
+	blocks, err := app.homeBlocks(context.Background(), "U1")
+	if err != nil {
+		t.Fatalf("homeBlocks() error = %v", err)
+	}
+	raw, _ := json.Marshal(blocks)
+	view := string(raw)
+
+	// The user's team comes first among the active investigations
+	search := strings.Index(view, ":star: *search* (1)")
+	payments := strings.Index(view, "*payments* (1)")
+	unowned := strings.Index(view, "*unowned* (1)")
+	if search < 0 || payments < 0 || unowned < 0 || !(search < payments && payments < unowned) {
+		t.Errorf("want search, payments, unowned in order, got:\n%s", view)
+	}
+	if !strings.Contains(view, "NC-4") || strings.Contains(view, "NC-5") {
+		t.Errorf("want NC-4 as recently finished and not NC-5 (outside the window):\n%s", view)
+	}
+}
+
+// fakeSlack serves apps.connections.open, views.publish, and a Socket Mode
+// WebSocket that sends the given envelopes and collects the acks.
+type fakeSlack struct {
+	server    *httptest.Server
+	envelopes []envelope
+	acks      chan map[string]interface{}
+	published chan map[string]interface{}
+}
+
+func newFakeSlack(t *testing.T, envelopes ...envelope) *fakeSlack {
+	t.Helper()
+	f := &fakeSlack{
+		envelopes: envelopes,
+		acks:      make(chan map[string]interface{}, 10),
+		published: make(chan map[string]interface{}, 10),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/apps.connections.open", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xapp-test" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "invalid_auth"}`))
+			return
+		}
+		wsURL := "ws" + strings.TrimPrefix(f.server.URL, "http") + "/socket"
+		_, _ = fmt.Fprintf(w, `{"ok": true, "url": %q}`, wsURL)
+	})
+	mux.HandleFunc("/api/views.publish", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			t.Errorf("views.publish Authorization = %q, want the bot token", r.Header.Get("Authorization"))
+		}
+		var request map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		f.published <- request
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+	mux.Handle("/socket", websocket.Handler(func(ws *websocket.Conn) {
+		// Each envelope is sent after the previous one was acknowledged
+		_ = websocket.JSON.Send(ws, envelope{Type: "hello"})
+		for _, env := range f.envelopes {
+			if err := websocket.JSON.Send(ws, env); err != nil {
+				return
+			}
+			var a map[string]interface{}
+			if err := websocket.JSON.Receive(ws, &a); err != nil {
+				return
+			}
+			f.acks <- a
+		}
+		var ignored map[string]interface{}
+		_ = websocket.JSON.Receive(ws, &ignored)
+	}))
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func TestRun_SocketMode(t *testing.T) {
+	slack := newFakeSlack(t,
+		envelope{Type: "slash_commands", EnvelopeID: "env-cmd", Payload: json.RawMessage(`{"command": "/nightcrier", "text": "silence ns/foo 2h", "user_id": "U1"}`)},
+		envelope{Type: "events_api", EnvelopeID: "env-home", Payload: json.RawMessage(`{"event": {"type": "app_home_opened", "user": "U1", "tab": "home"}}`)},
+	)
+	store := newFakeStore(testIncidents(time.Now())...)
+	app := newTestApp(store, Config{AppToken: "xapp-test", BotToken: "xoxb-test", APIURL: slack.server.URL + "/api/", AllowedUsers: []string{"U1"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		app.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	acks := make(map[string]map[string]interface{})
+	for len(acks) < 2 {
+		select {
+		case a := <-slack.acks:
+			acks[a["envelope_id"].(string)] = a
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for acks, got %v", acks)
+		}
+	}
+	payload, _ := acks["env-cmd"]["payload"].(map[string]interface{})
+	if text, _ := payload["text"].(string); !strings.Contains(text, "silenced ns/foo") {
+		t.Errorf("slash command ack payload = %v, want the silence confirmation", acks["env-cmd"])
+	}
+	if app.silences.Match("prod", "foo") == nil {
+		t.Error("the slash command should have silenced ns/foo")
+	}
+
+	select {
+	case request := <-slack.published:
+		if request["user_id"] != "U1" {
+			t.Errorf("views.publish user_id = %v, want U1", request["user_id"])
+		}
+		view, _ := json.Marshal(request["view"])
+		if !strings.Contains(string(view), `"type":"home"`) || !strings.Contains(string(view), "NC-1") || !strings.Contains(string(view), "ns/foo") {
+			t.Errorf("published view = %s, want the home tab with incidents and the silence", view)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for views.publish")
+	}
+}
@@ -0,0 +1,243 @@
+package slackapp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/pause"
+	"github.com/rbias/nightcrier/internal/silence"
+)
+
+// slashCommand is the payload of a slash_commands envelope.
+type slashCommand struct {
+	Command string `json:"command"`
+	Text    string `json:"text"`
+	UserID  string `json:"user_id"`
+}
+
+// commandResponse is the message a slash command answers with.
+type commandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// ephemeral returns a response only the invoking user sees.
+func ephemeral(format string, args ...interface{}) *commandResponse {
+	return &commandResponse{ResponseType: "ephemeral", Text: fmt.Sprintf(format, args...)}
+}
+
+// inChannel returns a response posted to the channel, for changes others should
+// know about.
+func inChannel(format string, args ...interface{}) *commandResponse {
+	return &commandResponse{ResponseType: "in_channel", Text: fmt.Sprintf(format, args...)}
+}
+
+// commandHelp describes the subcommands of the slash command
+const commandHelp = "*Usage:*\n" +
+	"`%[1]s status` – active investigations (your teams first) and silences\n" +
+	"`%[1]s silence ns/<namespace> <duration> [reason]` – stop investigating a namespace, e.g. `silence ns/payments 2h deploy`\n" +
+	"`%[1]s silence <cluster>/ns/<namespace> <duration> [reason]` – silence a namespace in one cluster\n" +
+	"`%[1]s silences` – list active silences\n" +
+	"`%[1]s unsilence <silence-id>` – lift a silence\n" +
+	"`%[1]s pause all|<cluster> [reason]` – stop launching agents everywhere or on one cluster; faults are still recorded\n" +
+	"`%[1]s resume all|<cluster>` – resume triage"
+
+// handleCommand runs a slash command and returns its response.
+func (a *App) handleCommand(ctx context.Context, cmd slashCommand) *commandResponse {
+	if cmd.Command == "" {
+		cmd.Command = "/nightcrier"
+	}
+	args := strings.Fields(cmd.Text)
+	if len(args) == 0 {
+		return ephemeral(commandHelp, cmd.Command)
+	}
+
+	switch args[0] {
+	case "status":
+		return a.statusCommand(ctx, cmd.UserID)
+	case "silences":
+		return a.silencesCommand()
+	case "silence":
+		if !a.allowed(cmd.UserID) {
+			return ephemeral(":no_entry: You are not allowed to silence namespaces.")
+		}
+		if len(args) < 3 {
+			return ephemeral("Usage: `%s silence ns/<namespace> <duration> [reason]`", cmd.Command)
+		}
+		duration, err := silence.ParseDuration(args[2])
+		if err != nil {
+			return ephemeral(":warning: %v", err)
+		}
+		reason := strings.Join(args[3:], " ")
+		s, err := a.silences.Silence(ctx, args[1], duration, reason, "slack:"+cmd.UserID)
+		if err != nil {
+			slog.Warn("slack silence command failed", "user", cmd.UserID, "target", args[1], "error", err)
+			return ephemeral(":warning: Could not silence %s: %v", args[1], err)
+		}
+		return inChannel(":no_bell: <@%s> silenced %s for %s (silence `%s`). Faults there are not investigated until %s.",
+			cmd.UserID, s.Target(), silence.FormatDuration(duration), s.SilenceID, s.ExpiresAt.UTC().Format("Jan 2 15:04 MST"))
+	case "unsilence":
+		if !a.allowed(cmd.UserID) {
+			return ephemeral(":no_entry: You are not allowed to lift silences.")
+		}
+		if len(args) != 2 {
+			return ephemeral("Usage: `%s unsilence <silence-id>`", cmd.Command)
+		}
+		if err := a.silences.Lift(ctx, args[1], "slack:"+cmd.UserID); err != nil {
+			return ephemeral(":warning: Could not lift silence %s: %v", args[1], err)
+		}
+		return inChannel(":bell: <@%s> lifted silence `%s`.", cmd.UserID, args[1])
+	case "pause", "resume":
+		if !a.allowed(cmd.UserID) {
+			return ephemeral(":no_entry: You are not allowed to pause triage.")
+		}
+		if a.pauses == nil {
+			return ephemeral(":warning: Pausing triage is not available.")
+		}
+		if args[0] == "resume" {
+			if len(args) != 2 {
+				return ephemeral("Usage: `%s resume all|<cluster>`", cmd.Command)
+			}
+			return a.resumeCommand(args[1], cmd.UserID)
+		}
+		if len(args) < 2 {
+			return ephemeral("Usage: `%s pause all|<cluster> [reason]`", cmd.Command)
+		}
+		return a.pauseCommand(args[1], strings.Join(args[2:], " "), cmd.UserID)
+	case "help":
+		return ephemeral(commandHelp, cmd.Command)
+	}
+	return ephemeral("Unknown command `%s`.\n%s", args[0], fmt.Sprintf(commandHelp, cmd.Command))
+}
+
+// pauseScope returns the cluster a pause or resume command targets ("" for all)
+// and how to describe it.
+func pauseScope(target string) (string, string) {
+	if target == "all" {
+		return "", "every cluster"
+	}
+	return target, "cluster " + target
+}
+
+// pauseCommand pauses triage on a cluster or, for "all", on every cluster.
+func (a *App) pauseCommand(target, reason, userID string) *commandResponse {
+	cluster, scope := pauseScope(target)
+	if _, err := a.pauses.Pause(cluster, pause.Pause{PausedBy: "slack:" + userID, Reason: reason}); err != nil {
+		slog.Warn("slack pause command failed", "user", userID, "cluster", cluster, "error", err)
+		return ephemeral(":warning: Could not pause triage on %s: %v", scope, err)
+	}
+	text := fmt.Sprintf(":double_vertical_bar: <@%s> paused triage on %s. Faults are recorded, but no agents are launched until triage is resumed.", userID, scope)
+	if reason != "" {
+		text += " Reason: " + reason
+	}
+	return inChannel("%s", text)
+}
+
+// resumeCommand resumes triage on a cluster or, for "all", lifts the global pause.
+func (a *App) resumeCommand(target, userID string) *commandResponse {
+	cluster, scope := pauseScope(target)
+	state, err := a.pauses.Resume(cluster)
+	if err != nil {
+		return ephemeral(":warning: Could not resume triage on %s: %v", scope, err)
+	}
+	text := fmt.Sprintf(":arrow_forward: <@%s> resumed triage on %s.", userID, scope)
+	if paused := state.PausedClusters(); len(paused) > 0 {
+		text += " Still paused: " + strings.Join(paused, ", ")
+	}
+	return inChannel("%s", text)
+}
+
+// pauseLines describes where triage is paused, or returns nil when it runs
+// everywhere.
+func (a *App) pauseLines(now time.Time) []string {
+	if a.pauses == nil {
+		return nil
+	}
+	state := a.pauses.State()
+	var lines []string
+	describe := func(scope string, p *pause.Pause) string {
+		line := fmt.Sprintf(":double_vertical_bar: Triage paused on %s for %s", scope, silence.FormatDuration(now.Sub(p.PausedAt)))
+		if p.PausedBy != "" {
+			line += " by " + slackUser(p.PausedBy)
+		}
+		if p.Reason != "" {
+			line += " – " + p.Reason
+		}
+		return line
+	}
+	if state.Global != nil {
+		lines = append(lines, describe("every cluster", state.Global))
+	}
+	for _, name := range state.PausedClusters() {
+		lines = append(lines, describe(name, state.Clusters[name]))
+	}
+	return lines
+}
+
+// statusCommand summarizes the active investigations, the user's teams first, and
+// the active silences.
+func (a *App) statusCommand(ctx context.Context, userID string) *commandResponse {
+	groups, err := a.listIncidents(ctx)
+	if err != nil {
+		slog.Warn("slack status command failed", "user", userID, "error", err)
+		return ephemeral(":warning: Could not load incidents: %v", err)
+	}
+	now := a.now()
+	teams := a.userTeams(userID)
+
+	var b strings.Builder
+	for _, line := range a.pauseLines(now) {
+		b.WriteString(line + "\n")
+	}
+	fmt.Fprintf(&b, "*%d active investigations*, %d finished in the last %s\n",
+		groups.activeCount, groups.recentCount, formatWindow(a.config.RecentWindow))
+	for _, team := range teams {
+		if active := groups.active[team]; len(active) > 0 {
+			fmt.Fprintf(&b, "\n:star: *%s* (%d)\n%s\n", team, len(active), incidentLines(active, now))
+		}
+	}
+	var others []string
+	for _, team := range orderedTeams(groups.active, nil) {
+		if !slices.Contains(teams, team) {
+			others = append(others, fmt.Sprintf("%s: %d", team, len(groups.active[team])))
+		}
+	}
+	if len(others) > 0 {
+		fmt.Fprintf(&b, "\nOther teams: %s\n", strings.Join(others, ", "))
+	}
+
+	silences := a.silences.Active()
+	if len(silences) == 0 {
+		b.WriteString("\nNo namespaces are silenced.")
+	} else {
+		fmt.Fprintf(&b, "\n*%d silenced namespaces*\n", len(silences))
+		for _, s := range silences {
+			b.WriteString(silenceLine(s, now) + "\n")
+		}
+	}
+	return ephemeral("%s", strings.TrimSpace(b.String()))
+}
+
+// silencesCommand lists the active silences.
+func (a *App) silencesCommand() *commandResponse {
+	silences := a.silences.Active()
+	if len(silences) == 0 {
+		return ephemeral("No namespaces are silenced.")
+	}
+	now := a.now()
+	lines := make([]string, 0, len(silences))
+	for _, s := range silences {
+		lines = append(lines, silenceLine(s, now))
+	}
+	return ephemeral("%s", strings.Join(lines, "\n"))
+}
+
+// allowed reports whether a user may silence namespaces, lift silences, and pause
+// triage. Only the allowed users may; with none configured, nobody may.
+func (a *App) allowed(userID string) bool {
+	return slices.Contains(a.config.AllowedUsers, userID)
+}
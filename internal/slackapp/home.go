@@ -0,0 +1,122 @@
+package slackapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/incident"
+)
+
+// maxTeamSections bounds the team sections per list; a view holds at most 100
+// blocks
+const maxTeamSections = 40
+
+// block is a Block Kit block.
+type block map[string]interface{}
+
+// headerBlock returns a header block.
+func headerBlock(text string) block {
+	return block{"type": "header", "text": block{"type": "plain_text", "text": text}}
+}
+
+// sectionBlock returns a section block with mrkdwn text.
+func sectionBlock(text string) block {
+	return block{"type": "section", "text": block{"type": "mrkdwn", "text": text}}
+}
+
+// contextBlock returns a context block with mrkdwn text.
+func contextBlock(text string) block {
+	return block{"type": "context", "elements": []block{{"type": "mrkdwn", "text": text}}}
+}
+
+// dividerBlock returns a divider block.
+func dividerBlock() block {
+	return block{"type": "divider"}
+}
+
+// publishHome publishes the App Home of a user.
+func (a *App) publishHome(ctx context.Context, userID string) error {
+	blocks, err := a.homeBlocks(ctx, userID)
+	if err != nil {
+		blocks = []block{sectionBlock(fmt.Sprintf(":warning: Could not load incidents: %v", err))}
+	}
+	request := map[string]interface{}{
+		"user_id": userID,
+		"view":    map[string]interface{}{"type": "home", "blocks": blocks},
+	}
+	return a.api.call(ctx, "views.publish", a.config.BotToken, request, nil)
+}
+
+// homeBlocks builds the App Home of a user: where triage is paused, active
+// incidents per team, the user's teams first, recently finished investigations,
+// and active silences.
+func (a *App) homeBlocks(ctx context.Context, userID string) ([]block, error) {
+	groups, err := a.listIncidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := a.now()
+	silences := a.silences.Active()
+	teams := a.userTeams(userID)
+
+	summary := fmt.Sprintf("%d active · %d finished in the last %s · %d active silences · updated %s",
+		groups.activeCount, groups.recentCount, formatWindow(a.config.RecentWindow), len(silences),
+		now.UTC().Format("15:04 MST"))
+	if len(teams) > 0 {
+		summary += " · your teams: " + strings.Join(teams, ", ")
+	}
+	blocks := []block{headerBlock("Nightcrier incidents"), contextBlock(summary)}
+	if paused := a.pauseLines(now); len(paused) > 0 {
+		blocks = append(blocks, sectionBlock(strings.Join(paused, "\n")))
+	}
+
+	blocks = append(blocks, dividerBlock(), headerBlock("Active investigations"))
+	blocks = append(blocks, teamSections(groups.active, teams, now)...)
+	if groups.activeCount == 0 {
+		blocks = append(blocks, sectionBlock("Nothing under investigation."))
+	}
+
+	blocks = append(blocks, dividerBlock(), headerBlock("Recently finished"))
+	blocks = append(blocks, teamSections(groups.recent, teams, now)...)
+	if groups.recentCount == 0 {
+		blocks = append(blocks, sectionBlock("No investigations finished recently."))
+	}
+
+	if len(silences) > 0 {
+		var lines []string
+		for _, s := range silences {
+			lines = append(lines, silenceLine(s, now))
+		}
+		blocks = append(blocks, dividerBlock(), headerBlock("Silenced namespaces"), sectionBlock(strings.Join(lines, "\n")))
+	}
+	return blocks, nil
+}
+
+// teamSections returns one section per team, the user's teams first and marked.
+func teamSections(groups map[string][]*incident.Incident, userTeams []string, now time.Time) []block {
+	mine := make(map[string]bool, len(userTeams))
+	for _, team := range userTeams {
+		mine[team] = true
+	}
+	var blocks []block
+	teams := orderedTeams(groups, userTeams)
+	for i, team := range teams {
+		if i == maxTeamSections {
+			blocks = append(blocks, contextBlock(fmt.Sprintf("…and %d more teams", len(teams)-maxTeamSections)))
+			break
+		}
+		title := fmt.Sprintf("*%s* (%d)", team, len(groups[team]))
+		if mine[team] {
+			title = ":star: " + title
+		}
+		blocks = append(blocks, sectionBlock(title+"\n"+incidentLines(groups[team], now)))
+	}
+	return blocks
+}
+
+// formatWindow formats the recent window in hours, e.g. "24h".
+func formatWindow(d time.Duration) string {
+	return fmt.Sprintf("%dh", int(d.Hours()))
+}
@@ -0,0 +1,186 @@
+package slackapp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/silence"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// Listing limits
+const (
+	// maxListedIncidents bounds the incidents loaded per query
+	maxListedIncidents = 200
+	// maxGroupIncidents bounds the incidents shown per team
+	maxGroupIncidents = 10
+)
+
+// unownedTeam groups incidents without a resolved owner
+const unownedTeam = "unowned"
+
+// incidentGroups are the active and recently finished incidents, grouped by
+// owning team.
+type incidentGroups struct {
+	// active are the open incidents: waiting for, under, or awaiting review after
+	// investigation
+	active map[string][]*incident.Incident
+	// recent are the investigations finished within the recent window
+	recent map[string][]*incident.Incident
+	// activeCount and recentCount are the totals across teams
+	activeCount, recentCount int
+}
+
+// listIncidents loads the active and recent incidents from the state store.
+func (a *App) listIncidents(ctx context.Context) (*incidentGroups, error) {
+	active, err := a.incidents.ListIncidents(ctx, &storage.IncidentFilters{
+		Status: []string{incident.StatusReceived, incident.StatusPending, incident.StatusQueued, incident.StatusInvestigating, incident.StatusAwaitingReview, incident.StatusRemediationProposed},
+		Limit:  maxListedIncidents,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active incidents: %w", err)
+	}
+	since := a.now().Add(-a.config.RecentWindow)
+	recent, err := a.incidents.ListIncidents(ctx, &storage.IncidentFilters{
+		Status:       []string{incident.StatusResolved, incident.StatusFailed, incident.StatusAgentFailed},
+		CreatedAfter: &since,
+		Limit:        maxListedIncidents,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent incidents: %w", err)
+	}
+	return &incidentGroups{
+		active:      groupByTeam(active),
+		recent:      groupByTeam(recent),
+		activeCount: len(active),
+		recentCount: len(recent),
+	}, nil
+}
+
+// groupByTeam groups incidents by owning team, newest first within each team.
+func groupByTeam(incidents []*incident.Incident) map[string][]*incident.Incident {
+	groups := make(map[string][]*incident.Incident)
+	for _, inc := range incidents {
+		team := teamOf(inc)
+		groups[team] = append(groups[team], inc)
+	}
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool { return group[i].CreatedAt.After(group[j].CreatedAt) })
+	}
+	return groups
+}
+
+// teamOf returns the owning team of an incident: its "team" label (set from the
+// resolved owner or by cluster labels and label rules), or unownedTeam.
+func teamOf(inc *incident.Incident) string {
+	if team := inc.Labels["team"]; team != "" {
+		return strings.ToLower(team)
+	}
+	if inc.Owner != nil && inc.Owner.Team != "" {
+		return strings.ToLower(inc.Owner.Team)
+	}
+	return unownedTeam
+}
+
+// userTeams returns the configured teams of a Slack user, lowercased.
+func (a *App) userTeams(userID string) []string {
+	var teams []string
+	for _, team := range a.config.UserTeams[userID] {
+		teams = append(teams, strings.ToLower(team))
+	}
+	return teams
+}
+
+// orderedTeams returns the teams of the groups: the user's teams first, then the
+// other teams alphabetically, then unowned incidents.
+func orderedTeams(groups map[string][]*incident.Incident, first []string) []string {
+	seen := make(map[string]bool)
+	var teams []string
+	for _, team := range first {
+		if len(groups[team]) > 0 && !seen[team] {
+			teams = append(teams, team)
+			seen[team] = true
+		}
+	}
+	var others []string
+	for team := range groups {
+		if !seen[team] && team != unownedTeam {
+			others = append(others, team)
+		}
+	}
+	sort.Strings(others)
+	teams = append(teams, others...)
+	if len(groups[unownedTeam]) > 0 && !seen[unownedTeam] {
+		teams = append(teams, unownedTeam)
+	}
+	return teams
+}
+
+// incidentLine formats an incident as one mrkdwn line.
+func incidentLine(inc *incident.Incident, now time.Time) string {
+	id := inc.DisplayID
+	if id == "" {
+		id = inc.IncidentID
+	}
+	resource := inc.Namespace
+	if inc.Resource != nil {
+		resource = fmt.Sprintf("%s/%s %s", inc.Namespace, inc.Resource.Kind, inc.Resource.Name)
+	}
+	return fmt.Sprintf("%s `%s` *%s* %s on %s · %s · %s %s ago",
+		statusEmoji(inc.Status), id, inc.Severity, inc.FaultType, inc.Cluster, resource,
+		inc.Status, silence.FormatDuration(now.Sub(inc.CreatedAt)))
+}
+
+// incidentLines formats up to maxGroupIncidents incidents, noting how many more
+// there are.
+func incidentLines(incidents []*incident.Incident, now time.Time) string {
+	var lines []string
+	for i, inc := range incidents {
+		if i == maxGroupIncidents {
+			lines = append(lines, fmt.Sprintf("_…and %d more_", len(incidents)-maxGroupIncidents))
+			break
+		}
+		lines = append(lines, incidentLine(inc, now))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// statusEmoji returns the emoji for an incident status.
+func statusEmoji(status string) string {
+	switch status {
+	case incident.StatusReceived, incident.StatusPending, incident.StatusQueued:
+		return ":hourglass_flowing_sand:"
+	case incident.StatusInvestigating:
+		return ":mag:"
+	case incident.StatusAwaitingReview, incident.StatusRemediationProposed:
+		return ":eyes:"
+	case incident.StatusResolved:
+		return ":white_check_mark:"
+	case incident.StatusSuppressed, incident.StatusExpired:
+		return ":heavy_minus_sign:"
+	}
+	return ":x:"
+}
+
+// silenceLine formats an active silence as one mrkdwn line.
+func silenceLine(s *storage.Silence, now time.Time) string {
+	line := fmt.Sprintf(":no_bell: `%s` %s for %s more (by %s)",
+		s.SilenceID, s.Target(), silence.FormatDuration(s.ExpiresAt.Sub(now)), slackUser(s.CreatedBy))
+	if s.Reason != "" {
+		line += ": " + s.Reason
+	}
+	return line
+}
+
+// slackUser formats a silence creator; Slack users ("slack:<user ID>") become
+// mentions.
+func slackUser(createdBy string) string {
+	if userID, ok := strings.CutPrefix(createdBy, "slack:"); ok {
+		return "<@" + userID + ">"
+	}
+	return createdBy
+}
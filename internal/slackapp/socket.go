@@ -0,0 +1,197 @@
+package slackapp
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// envelope is a Socket Mode message from Slack. Messages with an envelope ID must
+// be acknowledged within 3 seconds, or Slack retries them.
+type envelope struct {
+	Type       string          `json:"type"`
+	EnvelopeID string          `json:"envelope_id,omitempty"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	Reason     string          `json:"reason,omitempty"`
+}
+
+// ack acknowledges an envelope, optionally with a response payload (slash
+// commands answer with the message to show).
+type ack struct {
+	EnvelopeID string      `json:"envelope_id"`
+	Payload    interface{} `json:"payload,omitempty"`
+}
+
+// socketConn is a Socket Mode connection. Acks are sent from concurrent handlers,
+// so sends are serialized.
+type socketConn struct {
+	ws *websocket.Conn
+	mu sync.Mutex
+}
+
+// receive reads the next envelope.
+func (c *socketConn) receive() (*envelope, error) {
+	var env envelope
+	if err := websocket.JSON.Receive(c.ws, &env); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
+
+// send acknowledges an envelope.
+func (c *socketConn) send(a ack) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return websocket.JSON.Send(c.ws, a)
+}
+
+// dialTimeout bounds opening a Socket Mode connection, proxy tunnel and handshakes
+// included
+const dialTimeout = 30 * time.Second
+
+// dialWebSocket opens a WebSocket connection, through the HTTP proxy that
+// proxyFunc selects for the URL (nil: direct). Proxies are tunneled with CONNECT.
+func dialWebSocket(ctx context.Context, rawURL string, proxyFunc func(*http.Request) (*url.URL, error)) (*websocket.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	config, err := websocket.NewConfig(rawURL, "https://slack.com")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Socket Mode URL: %w", err)
+	}
+
+	var proxyURL *url.URL
+	if proxyFunc != nil {
+		// Proxy settings are keyed by the HTTP scheme of the WebSocket scheme
+		target := *config.Location
+		target.Scheme = map[string]string{"wss": "https", "ws": "http"}[target.Scheme]
+		proxyURL, err = proxyFunc(&http.Request{URL: &target})
+		if err != nil {
+			return nil, fmt.Errorf("failed to select proxy: %w", err)
+		}
+	}
+	if proxyURL == nil {
+		ws, err := config.DialContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to Slack: %w", err)
+		}
+		return ws, nil
+	}
+
+	host := config.Location.Host
+	if config.Location.Port() == "" {
+		port := "443"
+		if config.Location.Scheme == "ws" {
+			port = "80"
+		}
+		host = net.JoinHostPort(config.Location.Hostname(), port)
+	}
+	conn, err := connectTunnel(ctx, proxyURL, host)
+	if err != nil {
+		return nil, err
+	}
+	// The WebSocket handshake does not take a context
+	release := bindConn(ctx, conn)
+	if config.Location.Scheme == "wss" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: config.Location.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed TLS handshake with Slack: %w", err)
+		}
+		conn = tlsConn
+	}
+	ws, err := websocket.NewClient(config, conn)
+	if err := release(err); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to Slack: %w", err)
+	}
+	return ws, nil
+}
+
+// bindConn makes blocking I/O on conn end with ctx: it sets ctx's deadline on
+// conn and closes conn when ctx is done. The returned function undoes both once
+// the I/O is over; given the I/O's error, it returns that error, or ctx's error
+// when ctx ended the I/O.
+func bindConn(ctx context.Context, conn net.Conn) func(error) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	return func(err error) error {
+		if !stop() {
+			return ctx.Err()
+		}
+		conn.SetDeadline(time.Time{})
+		return err
+	}
+}
+
+// connectTunnel opens a CONNECT tunnel to host through an HTTP proxy.
+func connectTunnel(ctx context.Context, proxyURL *url.URL, host string) (net.Conn, error) {
+	proxyHost := proxyURL.Host
+	if proxyURL.Port() == "" {
+		proxyHost = net.JoinHostPort(proxyURL.Hostname(), "80")
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", proxyHost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy: %w", err)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: host},
+		Host:   host,
+		Header: make(http.Header),
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		req.SetBasicAuth(user.Username(), password)
+		req.Header.Set("Proxy-Authorization", req.Header.Get("Authorization"))
+		req.Header.Del("Authorization")
+	}
+	// A proxy that accepts the connection but never answers must not hang the
+	// connect loop
+	release := bindConn(ctx, conn)
+	if err := req.Write(conn); err != nil {
+		release(nil)
+		conn.Close()
+		return nil, fmt.Errorf("failed to send CONNECT to proxy: %w", err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err = release(err); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read proxy response: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy refused CONNECT to %s: %s", host, resp.Status)
+	}
+	if reader.Buffered() > 0 {
+		// The tunnel's first bytes arrived with the proxy response
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a tunnel whose first bytes were read along with the proxy
+// response.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
@@ -0,0 +1,155 @@
+package slackapp
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// newConnectProxy serves an HTTP proxy that tunnels CONNECT requests carrying
+// the given basic credentials, and counts the tunnels it opened.
+func newConnectProxy(t *testing.T, user, password string) (*url.URL, *atomic.Int32) {
+	t.Helper()
+	var tunnels atomic.Int32
+	credentials := "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Proxy-Authorization") != credentials {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		client, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		tunnels.Add(1)
+		_, _ = io.WriteString(client, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() {
+			_, _ = io.Copy(upstream, client)
+			upstream.Close()
+		}()
+		_, _ = io.Copy(client, upstream)
+		client.Close()
+	}))
+	t.Cleanup(server.Close)
+
+	proxyURL, _ := url.Parse(server.URL)
+	proxyURL.User = url.UserPassword(user, password)
+	return proxyURL, &tunnels
+}
+
+// newEchoSocket serves a WebSocket that echoes JSON envelopes.
+func newEchoSocket(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		var env envelope
+		for websocket.JSON.Receive(ws, &env) == nil {
+			if websocket.JSON.Send(ws, env) != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/socket"
+}
+
+func TestDialWebSocket_ThroughProxy(t *testing.T) {
+	proxyURL, tunnels := newConnectProxy(t, "nightcrier", "s3cret")
+	socketURL := newEchoSocket(t)
+
+	var selected string
+	proxyFunc := func(req *http.Request) (*url.URL, error) {
+		selected = req.URL.String()
+		return proxyURL, nil
+	}
+	ws, err := dialWebSocket(context.Background(), socketURL, proxyFunc)
+	if err != nil {
+		t.Fatalf("dialWebSocket() error = %v", err)
+	}
+	defer ws.Close()
+
+	if tunnels.Load() != 1 {
+		t.Errorf("proxy opened %d tunnels, want 1", tunnels.Load())
+	}
+	if !strings.HasPrefix(selected, "http://") {
+		t.Errorf("proxy selected for %q, want the HTTP scheme of the ws URL", selected)
+	}
+
+	conn := &socketConn{ws: ws}
+	if err := conn.send(ack{EnvelopeID: "env-1"}); err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+	env, err := conn.receive()
+	if err != nil || env.EnvelopeID != "env-1" {
+		t.Errorf("receive() = %+v, %v; want the echoed envelope", env, err)
+	}
+}
+
+func TestDialWebSocket_ProxyRefusesConnect(t *testing.T) {
+	proxyURL, tunnels := newConnectProxy(t, "nightcrier", "s3cret")
+	proxyURL.User = url.UserPassword("nightcrier", "wrong")
+	socketURL := newEchoSocket(t)
+
+	_, err := dialWebSocket(context.Background(), socketURL, http.ProxyURL(proxyURL))
+	if err == nil || !strings.Contains(err.Error(), "407") {
+		t.Errorf("dialWebSocket() error = %v, want the proxy refusal", err)
+	}
+	if tunnels.Load() != 0 {
+		t.Errorf("proxy opened %d tunnels, want none", tunnels.Load())
+	}
+}
+
+func TestDialWebSocket_ProxyNeverAnswers(t *testing.T) {
+	// The proxy accepts connections and reads the CONNECT, but never replies
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+			go func() { _, _ = io.Copy(io.Discard, conn) }()
+		}
+	}()
+	proxyURL := &url.URL{Scheme: "http", Host: listener.Addr().String()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := dialWebSocket(ctx, "ws://slack.invalid/socket", http.ProxyURL(proxyURL))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("dialWebSocket() succeeded through a silent proxy")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("dialWebSocket() did not return when the context expired")
+	}
+}
@@ -0,0 +1,221 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// Kafka REST proxy (v2 API) content types
+const (
+	kafkaContentType = "application/vnd.kafka.v2+json"
+	kafkaJSONRecords = "application/vnd.kafka.json.v2+json"
+)
+
+// kafkaPollInterval is the pause between record polls that returned nothing
+const kafkaPollInterval = time.Second
+
+// KafkaSource consumes fault events from a Kafka topic through a Kafka REST proxy
+// (the Confluent v2 API), so no Kafka client library is needed. Each record value
+// is a fault event in the format of the event ingest API. Each subscription
+// creates a consumer instance in the consumer group, which is deleted when the
+// subscription ends.
+type KafkaSource struct {
+	cfg        cluster.SourceConfig
+	client     *http.Client
+	bufferSize int
+
+	mu sync.Mutex
+	// ch is the channel of the current subscription; nil while not subscribed
+	ch chan *events.FaultEvent
+}
+
+// NewKafkaSource returns a Kafka source for cfg, reaching the REST proxy at
+// cfg.Endpoint with client.
+func NewKafkaSource(cfg cluster.SourceConfig, client *http.Client, bufferSize int) *KafkaSource {
+	return &KafkaSource{
+		cfg:        cfg,
+		client:     client,
+		bufferSize: bufferSize,
+	}
+}
+
+// Name implements Source.
+func (s *KafkaSource) Name() string { return s.cfg.Name }
+
+// Type implements Source.
+func (s *KafkaSource) Type() string { return cluster.SourceKafka }
+
+// QueueDepth returns the events waiting in the source's buffer and its capacity.
+func (s *KafkaSource) QueueDepth() (depth, capacity int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ch == nil {
+		return 0, s.bufferSize
+	}
+	return len(s.ch), cap(s.ch)
+}
+
+// Subscribe implements Source. It creates a consumer instance subscribed to the
+// topic and polls its records until ctx is done or the proxy fails.
+func (s *KafkaSource) Subscribe(ctx context.Context) (<-chan *events.FaultEvent, error) {
+	baseURI, err := s.createConsumer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subscription := map[string][]string{"topics": {s.cfg.Topic}}
+	if err := s.do(ctx, http.MethodPost, baseURI+"/subscription", subscription, nil); err != nil {
+		s.deleteConsumer(baseURI)
+		return nil, fmt.Errorf("failed to subscribe to topic %s: %w", s.cfg.Topic, err)
+	}
+
+	ch := make(chan *events.FaultEvent, s.bufferSize)
+	s.mu.Lock()
+	s.ch = ch
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.deleteConsumer(baseURI)
+			s.mu.Lock()
+			if s.ch == ch {
+				s.ch = nil
+			}
+			s.mu.Unlock()
+			close(ch)
+		}()
+		s.poll(ctx, baseURI, ch)
+	}()
+	return ch, nil
+}
+
+// createConsumer creates a consumer instance in the consumer group and returns
+// its base URI.
+func (s *KafkaSource) createConsumer(ctx context.Context) (string, error) {
+	request := map[string]string{
+		"name":              fmt.Sprintf("nightcrier-%d", time.Now().UnixNano()),
+		"format":            "json",
+		"auto.offset.reset": "latest",
+	}
+	var created struct {
+		InstanceID string `json:"instance_id"`
+		BaseURI    string `json:"base_uri"`
+	}
+	endpoint := strings.TrimSuffix(s.cfg.Endpoint, "/") + "/consumers/" + s.cfg.ConsumerGroup
+	if err := s.do(ctx, http.MethodPost, endpoint, request, &created); err != nil {
+		return "", fmt.Errorf("failed to create kafka consumer: %w", err)
+	}
+	if created.BaseURI == "" {
+		return "", fmt.Errorf("failed to create kafka consumer: response has no base_uri")
+	}
+	return strings.TrimSuffix(created.BaseURI, "/"), nil
+}
+
+// deleteConsumer deletes a consumer instance, so the group rebalances at once
+// instead of after the consumer times out.
+func (s *KafkaSource) deleteConsumer(baseURI string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.do(ctx, http.MethodDelete, baseURI, nil, nil); err != nil {
+		slog.Debug("failed to delete kafka consumer", "source", s.cfg.Name, "error", err)
+	}
+}
+
+// poll fetches records until ctx is done or a fetch fails. Records that are not
+// valid fault events are logged and skipped.
+func (s *KafkaSource) poll(ctx context.Context, baseURI string, ch chan<- *events.FaultEvent) {
+	for {
+		var records []struct {
+			Topic     string          `json:"topic"`
+			Partition int             `json:"partition"`
+			Offset    int64           `json:"offset"`
+			Value     json.RawMessage `json:"value"`
+		}
+		if err := s.do(ctx, http.MethodGet, baseURI+"/records", nil, &records); err != nil {
+			if ctx.Err() == nil {
+				slog.Warn("failed to fetch kafka records", "source", s.cfg.Name, "topic", s.cfg.Topic, "error", err)
+			}
+			return
+		}
+
+		for _, record := range records {
+			found, err := decodeHTTP(record.Value)
+			if err != nil {
+				slog.Warn("skipping kafka record that is not a fault event",
+					"source", s.cfg.Name,
+					"topic", record.Topic,
+					"partition", record.Partition,
+					"offset", record.Offset,
+					"error", err)
+				continue
+			}
+			for _, event := range found {
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+
+		if len(records) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(kafkaPollInterval):
+			}
+		}
+	}
+}
+
+// do sends a request to the REST proxy and decodes the JSON response into out
+// when it is not nil.
+func (s *KafkaSource) do(ctx context.Context, method, url string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", kafkaContentType)
+	}
+	if method == http.MethodGet {
+		req.Header.Set("Accept", kafkaJSONRecords)
+	} else {
+		req.Header.Set("Accept", kafkaContentType)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned %d: %s", method, url, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// queueSource is implemented by sources exposing their event buffer (the MCP
+// client, push and Kafka sources).
+type queueSource interface {
+	QueueDepth() (depth, capacity int)
+}
+
+// dropSource is implemented by sources counting the events they dropped (the MCP
+// client).
+type dropSource interface {
+	DroppedCount() int64
+}
+
+// Pipeline merges the fault events of a cluster's sources into one stream. It is
+// the event client the connection manager subscribes to, so the events of every
+// source go through the same queue, dedup, and filters. Each event is stamped with
+// the name of its source.
+type Pipeline struct {
+	cluster string
+	sources []Source
+}
+
+// NewPipeline creates the sources of a cluster from their configuration (see
+// cluster.ClusterConfig.EventSources).
+func NewPipeline(clusterName string, configs []cluster.SourceConfig, registry *Registry) (*Pipeline, error) {
+	p := &Pipeline{cluster: clusterName}
+	for _, cfg := range configs {
+		src, err := registry.New(clusterName, cfg)
+		if err != nil {
+			return nil, err
+		}
+		p.sources = append(p.sources, src)
+	}
+	if len(p.sources) == 0 {
+		return nil, fmt.Errorf("cluster %s has no event sources", clusterName)
+	}
+	return p, nil
+}
+
+// Sources returns the pipeline's sources.
+func (p *Pipeline) Sources() []Source {
+	return p.sources
+}
+
+// Subscribe subscribes to every source and returns the merged stream. When one
+// source's stream ends, the others are stopped and the merged stream is closed,
+// so the connection manager reconnects the whole pipeline with its backoff.
+func (p *Pipeline) Subscribe(ctx context.Context) (<-chan *events.FaultEvent, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	streams := make([]<-chan *events.FaultEvent, len(p.sources))
+	for i, src := range p.sources {
+		stream, err := src.Subscribe(ctx)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("source %s: %w", src.Name(), err)
+		}
+		streams[i] = stream
+	}
+
+	merged := make(chan *events.FaultEvent)
+	done := make(chan struct{}, len(p.sources))
+	for i, src := range p.sources {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			p.forward(ctx, cancel, src, streams[i], merged)
+		}()
+	}
+	go func() {
+		for range p.sources {
+			<-done
+		}
+		cancel()
+		close(merged)
+	}()
+	return merged, nil
+}
+
+// forward stamps the events of one source and sends them to the merged stream
+// until ctx is done or the source's stream ends, which stops the pipeline.
+func (p *Pipeline) forward(ctx context.Context, stop context.CancelFunc, src Source, stream <-chan *events.FaultEvent, merged chan<- *events.FaultEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-stream:
+			if !ok {
+				if ctx.Err() == nil {
+					slog.Warn("event source stream ended, reconnecting the cluster's sources",
+						"cluster", p.cluster,
+						"source", src.Name(),
+						"source_type", src.Type())
+				}
+				stop()
+				return
+			}
+			event.Source = src.Name()
+			if event.ReceivedAt.IsZero() {
+				event.ReceivedAt = time.Now()
+			}
+			select {
+			case merged <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// QueueDepth returns the events waiting in the sources' buffers and their total
+// capacity.
+func (p *Pipeline) QueueDepth() (depth, capacity int) {
+	for _, src := range p.sources {
+		if q, ok := src.(queueSource); ok {
+			d, c := q.QueueDepth()
+			depth += d
+			capacity += c
+		}
+	}
+	return depth, capacity
+}
+
+// DroppedCount returns the number of events the sources dropped.
+func (p *Pipeline) DroppedCount() int64 {
+	var dropped int64
+	for _, src := range p.sources {
+		if d, ok := src.(dropSource); ok {
+			dropped += d.DroppedCount()
+		}
+	}
+	return dropped
+}
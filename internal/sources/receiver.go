@@ -0,0 +1,330 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/backfill"
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// maxPushBytes bounds the body of a push request
+const maxPushBytes = 4 << 20
+
+// pushRetryAfterSeconds is the Retry-After sent while a push source cannot take
+// events (not subscribed yet, or its buffer is full)
+const pushRetryAfterSeconds = "5"
+
+var (
+	// errNotSubscribed is returned by pushSource.push while the cluster's
+	// pipeline is not subscribed (starting or reconnecting)
+	errNotSubscribed = errors.New("source is not receiving events yet")
+	// errBufferFull is returned by pushSource.push when the source's buffer has no
+	// room for the events
+	errBufferFull = errors.New("source buffer full")
+)
+
+// decodeFunc turns a push request body into fault events.
+type decodeFunc func(body []byte) ([]*events.FaultEvent, error)
+
+// pushDecoders are the built-in push source types
+var pushDecoders = map[string]decodeFunc{
+	cluster.SourceAlertmanager: decodeAlertmanager,
+	cluster.SourceKubewatch:    decodeKubewatch,
+	cluster.SourceHTTP:         decodeHTTP,
+}
+
+// pushSource is a source whose events are pushed to the Receiver over HTTP.
+type pushSource struct {
+	name       string
+	typ        string
+	decode     decodeFunc
+	bufferSize int
+
+	mu sync.Mutex
+	// ch is the channel of the current subscription; nil while not subscribed
+	ch chan *events.FaultEvent
+}
+
+func newPushSource(name, typ string, decode decodeFunc, bufferSize int) *pushSource {
+	return &pushSource{name: name, typ: typ, decode: decode, bufferSize: bufferSize}
+}
+
+// Name implements Source.
+func (s *pushSource) Name() string { return s.name }
+
+// Type implements Source.
+func (s *pushSource) Type() string { return s.typ }
+
+// Subscribe implements Source. Pushed events are accepted until ctx is done.
+func (s *pushSource) Subscribe(ctx context.Context) (<-chan *events.FaultEvent, error) {
+	ch := make(chan *events.FaultEvent, s.bufferSize)
+	s.mu.Lock()
+	s.ch = ch
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.ch == ch {
+			s.ch = nil
+		}
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// QueueDepth returns the events waiting in the source's buffer and its capacity.
+func (s *pushSource) QueueDepth() (depth, capacity int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ch == nil {
+		return 0, s.bufferSize
+	}
+	return len(s.ch), cap(s.ch)
+}
+
+// push queues events for the current subscription and returns how many were
+// queued before the buffer filled up.
+func (s *pushSource) push(found []*events.FaultEvent) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ch == nil {
+		return 0, errNotSubscribed
+	}
+	for i, event := range found {
+		select {
+		case s.ch <- event:
+		default:
+			return i, errBufferFull
+		}
+	}
+	return len(found), nil
+}
+
+// Receiver serves the push sources at POST /sources/{cluster}/{source}, e.g. as
+// the webhook receiver URL of Alertmanager or kubewatch. Requests whose events
+// cannot be queued get 503 with Retry-After, so senders that retry (Alertmanager
+// does) deliver them later.
+type Receiver struct {
+	mu      sync.RWMutex
+	sources map[string]*pushSource // keyed by "cluster/source"
+}
+
+// NewReceiver returns a receiver without sources.
+func NewReceiver() *Receiver {
+	return &Receiver{sources: make(map[string]*pushSource)}
+}
+
+// add serves a push source of a cluster.
+func (r *Receiver) add(clusterName string, src *pushSource) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := clusterName + "/" + src.name
+	if _, exists := r.sources[key]; exists {
+		return fmt.Errorf("duplicate push source %s", key)
+	}
+	r.sources[key] = src
+	return nil
+}
+
+// Len returns the number of push sources served.
+func (r *Receiver) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.sources)
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	clusterName, name, ok := strings.Cut(strings.TrimPrefix(req.URL.Path, "/sources/"), "/")
+	r.mu.RLock()
+	src := r.sources[clusterName+"/"+name]
+	r.mu.RUnlock()
+	if !ok || src == nil {
+		http.Error(w, "unknown event source", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxPushBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	found, err := src.decode(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	now := time.Now()
+	for _, event := range found {
+		event.Cluster = clusterName
+		event.ReceivedAt = now
+	}
+
+	queued, err := src.push(found)
+	if err != nil {
+		slog.Warn("push source could not queue events",
+			"cluster", clusterName,
+			"source", name,
+			"queued", queued,
+			"rejected", len(found)-queued,
+			"error", err)
+		w.Header().Set("Retry-After", pushRetryAfterSeconds)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]int{"accepted": queued})
+}
+
+// decodeAlertmanager decodes an Alertmanager webhook notification. Only firing
+// alerts become fault events.
+func decodeAlertmanager(body []byte) ([]*events.FaultEvent, error) {
+	var notification struct {
+		Alerts []struct {
+			backfill.Alert
+			Status string `json:"status"`
+		} `json:"alerts"`
+	}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("invalid alertmanager notification: %w", err)
+	}
+	var found []*events.FaultEvent
+	for _, a := range notification.Alerts {
+		if a.Status != "firing" {
+			continue
+		}
+		found = append(found, backfill.AlertFaultEvent(a.Alert))
+	}
+	return found, nil
+}
+
+// kubewatchKinds maps the resource kinds kubewatch reports to Kubernetes kinds
+var kubewatchKinds = map[string]string{
+	"pod":                   "Pod",
+	"deployment":            "Deployment",
+	"replicaset":            "ReplicaSet",
+	"statefulset":           "StatefulSet",
+	"daemonset":             "DaemonSet",
+	"job":                   "Job",
+	"cronjob":               "CronJob",
+	"service":               "Service",
+	"node":                  "Node",
+	"namespace":             "Namespace",
+	"persistentvolume":      "PersistentVolume",
+	"persistentvolumeclaim": "PersistentVolumeClaim",
+	"ingress":               "Ingress",
+	"configmap":             "ConfigMap",
+	"secret":                "Secret",
+	"event":                 "Event",
+}
+
+// decodeKubewatch decodes a kubewatch webhook message. Deletions and Kubernetes
+// event reasons are warnings; creations and updates are informational, so the
+// severity threshold usually filters them.
+func decodeKubewatch(body []byte) ([]*events.FaultEvent, error) {
+	var msg struct {
+		EventMeta struct {
+			Kind      string `json:"kind"`
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+			Reason    string `json:"reason"`
+		} `json:"eventmeta"`
+		Text string    `json:"text"`
+		Time time.Time `json:"time"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("invalid kubewatch message: %w", err)
+	}
+	meta := msg.EventMeta
+	if meta.Kind == "" || meta.Name == "" || meta.Reason == "" {
+		return nil, fmt.Errorf("invalid kubewatch message: eventmeta kind, name, and reason are required")
+	}
+
+	normalized := strings.ToLower(strings.ReplaceAll(meta.Kind, " ", ""))
+	kind, ok := kubewatchKinds[normalized]
+	if !ok {
+		kind = strings.ToUpper(normalized[:1]) + normalized[1:]
+	}
+	severity := events.SeverityWarning
+	switch strings.ToLower(meta.Reason) {
+	case "created", "updated":
+		severity = events.SeverityInfo
+	}
+	at := msg.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	resource := &events.ResourceInfo{Kind: kind, Name: meta.Name, Namespace: meta.Namespace}
+	if kind == "Node" {
+		resource.Node = meta.Name
+	}
+	return []*events.FaultEvent{{
+		FaultID:   faultID(kind, meta.Namespace, meta.Name, meta.Reason),
+		Resource:  resource,
+		FaultType: meta.Reason,
+		Severity:  severity,
+		Context:   msg.Text,
+		Timestamp: at.UTC().Format(time.RFC3339Nano),
+	}}, nil
+}
+
+// decodeHTTP decodes one fault event, or a JSON array of them, in the format of
+// the event ingest API.
+func decodeHTTP(body []byte) ([]*events.FaultEvent, error) {
+	var found []*events.FaultEvent
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &found); err != nil {
+			return nil, fmt.Errorf("invalid fault events: %w", err)
+		}
+	} else {
+		var event events.FaultEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("invalid fault event: %w", err)
+		}
+		found = []*events.FaultEvent{&event}
+	}
+	for _, event := range found {
+		if event == nil || event.FaultType == "" {
+			return nil, fmt.Errorf("invalid fault event: faultType is required")
+		}
+		if event.FaultID == "" {
+			event.FaultID = faultID(event.FaultType, event.GetResourceKind(), event.GetNamespace(), event.GetResourceName())
+		}
+		if event.Timestamp == "" {
+			event.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return found, nil
+}
+
+// faultID derives a stable fault ID from the parts identifying a fault condition,
+// for sources whose events carry none.
+func faultID(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
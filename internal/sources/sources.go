@@ -0,0 +1,168 @@
+// Package sources generalizes where fault events come from. Each cluster has one
+// or more event sources (its MCP server, Alertmanager or kubewatch webhooks, a
+// Kafka topic, or plain HTTP), created from the cluster's sources configuration
+// by a Registry of source types. A Pipeline merges the events of a cluster's
+// sources into the single stream the connection manager queues, so every source
+// shares the same dedup, filters, and queue, and stamps each event with the name
+// of its source, which is recorded on the incident.
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// defaultBufferSize is the event buffer of push and Kafka sources when Options
+// sets none
+const defaultBufferSize = 100
+
+// Source produces the fault events of one cluster.
+type Source interface {
+	// Name identifies the source (its sources entry), e.g. "alerts"
+	Name() string
+	// Type is the source type, e.g. "alertmanager"
+	Type() string
+	// Subscribe starts receiving fault events. The channel is closed when ctx is
+	// done or the source's stream ends; the pipeline then subscribes again.
+	Subscribe(ctx context.Context) (<-chan *events.FaultEvent, error)
+}
+
+// Factory creates a source of a cluster from its configuration.
+type Factory func(clusterName string, cfg cluster.SourceConfig) (Source, error)
+
+// Options configures the built-in source types.
+type Options struct {
+	// BufferSize is the event buffer of each push and Kafka source
+	BufferSize int
+	// HTTPClient is used by Kafka sources to reach the REST proxy; nil uses
+	// http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// Registry maps source types to the factories creating them. NewRegistry
+// registers the built-in push and Kafka types; the "mcp" type is registered by
+// the caller, since its client needs the MCP transport settings.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+	receiver  *Receiver
+}
+
+// NewRegistry returns a registry with the alertmanager, kubewatch, http, and
+// kafka source types registered. Push sources (alertmanager, kubewatch, http)
+// are served by the registry's Receiver.
+func NewRegistry(opts Options) *Registry {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = defaultBufferSize
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	r := &Registry{factories: make(map[string]Factory), receiver: NewReceiver()}
+	for typ, decode := range pushDecoders {
+		r.Register(typ, r.pushFactory(typ, decode, opts.BufferSize))
+	}
+	r.Register(cluster.SourceKafka, func(clusterName string, cfg cluster.SourceConfig) (Source, error) {
+		return NewKafkaSource(cfg, opts.HTTPClient, opts.BufferSize), nil
+	})
+	return r
+}
+
+// Register adds or replaces the factory of a source type.
+func (r *Registry) Register(typ string, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[typ] = factory
+}
+
+// Types returns the registered source types, sorted.
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]string, 0, len(r.factories))
+	for typ := range r.factories {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// New creates a source of a cluster with the factory of its type.
+func (r *Registry) New(clusterName string, cfg cluster.SourceConfig) (Source, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[cfg.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("cluster %s: source %q has unknown type %q (known types: %v)", clusterName, cfg.Name, cfg.Type, r.Types())
+	}
+	src, err := factory(clusterName, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: failed to create source %q: %w", clusterName, cfg.Name, err)
+	}
+	return src, nil
+}
+
+// Receiver returns the HTTP handler serving the push sources created by the
+// registry.
+func (r *Registry) Receiver() *Receiver {
+	return r.receiver
+}
+
+// pushFactory returns the factory of a push source type, which adds each source
+// to the registry's receiver.
+func (r *Registry) pushFactory(typ string, decode decodeFunc, bufferSize int) Factory {
+	return func(clusterName string, cfg cluster.SourceConfig) (Source, error) {
+		src := newPushSource(cfg.Name, typ, decode, bufferSize)
+		if err := r.receiver.add(clusterName, src); err != nil {
+			return nil, err
+		}
+		return src, nil
+	}
+}
+
+// Subscriber is an event client that can back a source, such as the MCP client
+// (events.Client).
+type Subscriber interface {
+	Subscribe(ctx context.Context) (<-chan *events.FaultEvent, error)
+}
+
+// ClientSource adapts an event client to a Source with the given name and type.
+// The client's queue depth and drop counter, when it has them, are reported by
+// the pipeline.
+func ClientSource(name, typ string, client Subscriber) Source {
+	return &clientSource{name: name, typ: typ, client: client}
+}
+
+type clientSource struct {
+	name   string
+	typ    string
+	client Subscriber
+}
+
+func (s *clientSource) Name() string { return s.name }
+
+func (s *clientSource) Type() string { return s.typ }
+
+func (s *clientSource) Subscribe(ctx context.Context) (<-chan *events.FaultEvent, error) {
+	return s.client.Subscribe(ctx)
+}
+
+func (s *clientSource) QueueDepth() (depth, capacity int) {
+	if q, ok := s.client.(queueSource); ok {
+		return q.QueueDepth()
+	}
+	return 0, 0
+}
+
+func (s *clientSource) DroppedCount() int64 {
+	if d, ok := s.client.(dropSource); ok {
+		return d.DroppedCount()
+	}
+	return 0
+}
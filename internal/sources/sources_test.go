@@ -0,0 +1,266 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rbias/nightcrier/internal/cluster"
+	"github.com/rbias/nightcrier/internal/events"
+)
+
+// staticSource emits its events and then keeps the stream open until ctx is done.
+type staticSource struct {
+	name   string
+	events []*events.FaultEvent
+}
+
+func (s *staticSource) Name() string { return s.name }
+func (s *staticSource) Type() string { return "static" }
+
+func (s *staticSource) Subscribe(ctx context.Context) (<-chan *events.FaultEvent, error) {
+	ch := make(chan *events.FaultEvent, len(s.events))
+	for _, e := range s.events {
+		ch <- e
+	}
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func newTestRegistry() *Registry {
+	reg := NewRegistry(Options{BufferSize: 4})
+	reg.Register("static", func(clusterName string, cfg cluster.SourceConfig) (Source, error) {
+		return &staticSource{name: cfg.Name, events: []*events.FaultEvent{{FaultID: cfg.Name + "-1", FaultType: "Test"}}}, nil
+	})
+	return reg
+}
+
+func TestPipelineMergesSources(t *testing.T) {
+	reg := newTestRegistry()
+	p, err := NewPipeline("prod", []cluster.SourceConfig{
+		{Name: "first", Type: "static"},
+		{Name: "second", Type: "static"},
+	}, reg)
+	if err != nil {
+		t.Fatalf("NewPipeline() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := p.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	got := make(map[string]string)
+	for len(got) < 2 {
+		select {
+		case event := <-stream:
+			got[event.FaultID] = event.Source
+			if event.ReceivedAt.IsZero() {
+				t.Errorf("event %s has no ReceivedAt", event.FaultID)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for events, got %v", got)
+		}
+	}
+	if got["first-1"] != "first" || got["second-1"] != "second" {
+		t.Errorf("events not stamped with their source: %v", got)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-stream:
+		if ok {
+			t.Error("expected the merged stream to close")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("merged stream not closed after cancel")
+	}
+}
+
+func TestPipelineUnknownType(t *testing.T) {
+	_, err := NewPipeline("prod", []cluster.SourceConfig{{Name: "x", Type: "carrier-pigeon"}}, newTestRegistry())
+	if err == nil || !strings.Contains(err.Error(), "unknown type") {
+		t.Fatalf("expected an unknown type error, got %v", err)
+	}
+}
+
+func TestReceiver(t *testing.T) {
+	reg := NewRegistry(Options{BufferSize: 1})
+	p, err := NewPipeline("prod", []cluster.SourceConfig{{Name: "hooks", Type: cluster.SourceHTTP}}, reg)
+	if err != nil {
+		t.Fatalf("NewPipeline() error = %v", err)
+	}
+	receiver := reg.Receiver()
+	if receiver.Len() != 1 {
+		t.Fatalf("receiver serves %d sources, want 1", receiver.Len())
+	}
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return rec
+	}
+	event := `{"faultType":"OOMKilled","resource":{"kind":"Pod","name":"api","namespace":"shop"}}`
+
+	// Not subscribed yet
+	if rec := post("/sources/prod/hooks", event); rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("before subscribe: status = %d, Retry-After = %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := p.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	if rec := post("/sources/prod/missing", event); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown source: status = %d, want 404", rec.Code)
+	}
+	if rec := post("/sources/prod/hooks", `{"resource":{}}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid event: status = %d, want 400", rec.Code)
+	}
+	if rec := post("/sources/prod/hooks", event); rec.Code != http.StatusOK {
+		t.Fatalf("valid event: status = %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	select {
+	case got := <-stream:
+		if got.Cluster != "prod" || got.Source != "hooks" || got.FaultType != "OOMKilled" || got.FaultID == "" {
+			t.Errorf("unexpected event %+v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the pushed event")
+	}
+}
+
+func TestDecodeAlertmanager(t *testing.T) {
+	body := `{"alerts":[
+		{"status":"firing","labels":{"alertname":"KubePodCrashLooping","namespace":"shop","pod":"api-1","severity":"critical"},"startsAt":"2024-06-01T10:00:00Z","fingerprint":"abc"},
+		{"status":"resolved","labels":{"alertname":"KubePodCrashLooping","namespace":"shop","pod":"api-2"},"startsAt":"2024-06-01T10:00:00Z","fingerprint":"def"}
+	]}`
+	found, err := decodeAlertmanager([]byte(body))
+	if err != nil {
+		t.Fatalf("decodeAlertmanager() error = %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("decoded %d events, want only the firing alert", len(found))
+	}
+	if found[0].FaultType != "KubePodCrashLooping" || found[0].GetNamespace() != "shop" {
+		t.Errorf("unexpected event %+v", found[0])
+	}
+}
+
+func TestDecodeKubewatch(t *testing.T) {
+	tests := []struct {
+		body         string
+		wantKind     string
+		wantSeverity string
+		wantErr      bool
+	}{
+		{`{"eventmeta":{"kind":"pod","name":"api","namespace":"shop","reason":"deleted"},"text":"pod deleted"}`, "Pod", events.SeverityWarning, false},
+		{`{"eventmeta":{"kind":"deployment","name":"api","namespace":"shop","reason":"Updated"}}`, "Deployment", events.SeverityInfo, false},
+		{`{"eventmeta":{"kind":"node","name":"node-1","reason":"NodeNotReady"}}`, "Node", events.SeverityWarning, false},
+		{`{"eventmeta":{"kind":"pod"}}`, "", "", true},
+	}
+	for _, tt := range tests {
+		found, err := decodeKubewatch([]byte(tt.body))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("decodeKubewatch(%s) expected an error", tt.body)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("decodeKubewatch(%s) error = %v", tt.body, err)
+			continue
+		}
+		if found[0].GetResourceKind() != tt.wantKind || found[0].Severity != tt.wantSeverity {
+			t.Errorf("decodeKubewatch(%s) = kind %s severity %s, want %s %s",
+				tt.body, found[0].GetResourceKind(), found[0].Severity, tt.wantKind, tt.wantSeverity)
+		}
+	}
+}
+
+func TestDecodeHTTPArray(t *testing.T) {
+	found, err := decodeHTTP([]byte(`[{"faultType":"A"},{"faultType":"B","faultId":"fixed"}]`))
+	if err != nil {
+		t.Fatalf("decodeHTTP() error = %v", err)
+	}
+	if len(found) != 2 || found[0].FaultID == "" || found[1].FaultID != "fixed" || found[0].Timestamp == "" {
+		t.Errorf("unexpected events %+v %+v", found[0], found[1])
+	}
+}
+
+func TestKafkaSource(t *testing.T) {
+	var mu sync.Mutex
+	var served bool
+	var deleted bool
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/consumers/group":
+			_ = json.NewEncoder(w).Encode(map[string]string{"instance_id": "i1", "base_uri": srv.URL + "/consumers/group/instances/i1"})
+		case r.Method == http.MethodPost && r.URL.Path == "/consumers/group/instances/i1/subscription":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), "faults") {
+				t.Errorf("subscription %s does not name the topic", body)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/consumers/group/instances/i1/records":
+			if served {
+				_, _ = w.Write([]byte("[]"))
+				return
+			}
+			served = true
+			_, _ = w.Write([]byte(`[
+				{"topic":"faults","partition":0,"offset":1,"value":{"faultType":"OOMKilled","resource":{"kind":"Pod","name":"api"}}},
+				{"topic":"faults","partition":0,"offset":2,"value":"not an event"}
+			]`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/consumers/group/instances/i1":
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	src := NewKafkaSource(cluster.SourceConfig{Name: "bus", Type: cluster.SourceKafka, Endpoint: srv.URL, Topic: "faults", ConsumerGroup: "group"}, srv.Client(), 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := src.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	select {
+	case event := <-stream:
+		if event.FaultType != "OOMKilled" || event.GetResourceName() != "api" {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the kafka record")
+	}
+
+	cancel()
+	for range stream {
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !deleted {
+		t.Error("expected the consumer instance to be deleted")
+	}
+}
@@ -4,7 +4,10 @@ package storage
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
@@ -19,6 +22,8 @@ type AzureStorage struct {
 	accountKey  string
 	container   string
 	sasExpiry   time.Duration
+	// tagClasses sets the artifact_class blob index tag on uploads
+	tagClasses bool
 }
 
 // AzureStorageConfig holds configuration for Azure Blob Storage.
@@ -33,6 +38,13 @@ type AzureStorageConfig struct {
 	Container string
 	// SASExpiry is the duration for SAS token expiration (default: 168h / 7 days)
 	SASExpiry time.Duration
+	// HTTPClient is an optional HTTP client for blob requests (e.g. configured with a proxy)
+	HTTPClient *http.Client
+	// TagArtifactClasses sets the artifact_class blob index tag on every uploaded
+	// blob, so that lifecycle management rules can expire artifacts by class (see
+	// AzureLifecyclePolicy). Blob index tags are not supported on accounts with a
+	// hierarchical namespace.
+	TagArtifactClasses bool
 }
 
 // NewAzureStorage creates a new Azure Blob Storage client.
@@ -56,9 +68,16 @@ func NewAzureStorage(cfg *AzureStorageConfig) (*AzureStorage, error) {
 	var accountName, accountKey string
 	var err error
 
+	// Route requests through the provided HTTP client (proxy support)
+	var clientOptions *azblob.ClientOptions
+	if cfg.HTTPClient != nil {
+		clientOptions = &azblob.ClientOptions{}
+		clientOptions.Transport = cfg.HTTPClient
+	}
+
 	// Try connection string first
 	if cfg.ConnectionString != "" {
-		client, err = azblob.NewClientFromConnectionString(cfg.ConnectionString, nil)
+		client, err = azblob.NewClientFromConnectionString(cfg.ConnectionString, clientOptions)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Azure client from connection string: %w", err)
 		}
@@ -76,7 +95,7 @@ func NewAzureStorage(cfg *AzureStorageConfig) (*AzureStorage, error) {
 			return nil, fmt.Errorf("failed to create shared key credential: %w", err)
 		}
 		serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", accountName)
-		client, err = azblob.NewClientWithSharedKeyCredential(serviceURL, credential, nil)
+		client, err = azblob.NewClientWithSharedKeyCredential(serviceURL, credential, clientOptions)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Azure client with shared key: %w", err)
 		}
@@ -90,6 +109,7 @@ func NewAzureStorage(cfg *AzureStorageConfig) (*AzureStorage, error) {
 		accountKey:  accountKey,
 		container:   cfg.Container,
 		sasExpiry:   sasExpiry,
+		tagClasses:  cfg.TagArtifactClasses,
 	}, nil
 }
 
@@ -147,9 +167,14 @@ func (a *AzureStorage) uploadBlob(ctx context.Context, blobPath string, data []b
 		BlobContentDisposition: stringPtr("inline"), // Render in browser instead of download
 	}
 
-	_, err := blobClient.UploadBuffer(ctx, data, &azblob.UploadBufferOptions{
+	options := &azblob.UploadBufferOptions{
 		HTTPHeaders: httpHeaders,
-	})
+	}
+	if a.tagClasses {
+		_, name, _ := strings.Cut(blobPath, "/")
+		options.Tags = map[string]string{artifactClassTag: ArtifactClass(name)}
+	}
+	_, err := blobClient.UploadBuffer(ctx, data, options)
 	if err != nil {
 		return fmt.Errorf("failed to upload blob %s: %w", blobPath, err)
 	}
@@ -369,6 +394,22 @@ func (a *AzureStorage) generateSASURL(blobPath string, expiry time.Time) (string
 	return sasURL, nil
 }
 
+// ReadArtifact downloads a stored artifact of an incident.
+func (a *AzureStorage) ReadArtifact(ctx context.Context, incidentID, name string) ([]byte, error) {
+	blobPath := fmt.Sprintf("%s/%s", incidentID, name)
+	resp, err := a.client.DownloadStream(ctx, a.container, blobPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download blob %s: %w", blobPath, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download blob %s: %w", blobPath, err)
+	}
+	return data, nil
+}
+
 // SaveIncident implements the Storage interface for Azure Blob Storage.
 // It uploads all incident artifacts to Azure and returns SAS URLs for access.
 func (a *AzureStorage) SaveIncident(ctx context.Context, incidentID string, artifacts *IncidentArtifacts) (*SaveResult, error) {
@@ -392,6 +433,7 @@ func (a *AzureStorage) SaveIncident(ctx context.Context, incidentID string, arti
 		ArtifactURLs: make(map[string]string),
 		LogURLs:      make(map[string]string),
 		ExpiresAt:    expiresAt,
+		Hashes:       make(map[string]string),
 	}
 
 	// Upload each artifact and generate SAS URLs
@@ -412,6 +454,7 @@ func (a *AzureStorage) SaveIncident(ctx context.Context, incidentID string, arti
 			lastError = err
 			continue // Continue with other artifacts
 		}
+		result.Hashes[filename] = HashArtifact(data)
 
 		// Generate SAS URL
 		sasURL, err := a.generateSASURL(blobPath, expiresAt)
@@ -447,6 +490,7 @@ func (a *AzureStorage) SaveIncident(ctx context.Context, incidentID string, arti
 			lastError = err
 			continue // Continue with other logs
 		}
+		result.Hashes["logs/"+filename] = HashArtifact(data)
 
 		// Generate SAS URL
 		sasURL, err := a.generateSASURL(blobPath, expiresAt)
@@ -467,6 +511,8 @@ func (a *AzureStorage) SaveIncident(ctx context.Context, incidentID string, arti
 			log.Printf("Error uploading claude session archive for incident %s: %v", incidentID, err)
 			lastError = err
 		} else {
+			result.Hashes["logs/claude-session.tar.gz"] = HashArtifact(artifacts.ClaudeSessionArchive)
+
 			// Generate SAS URL for session archive
 			sasURL, err := a.generateSASURL(blobPath, expiresAt)
 			if err != nil {
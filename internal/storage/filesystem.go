@@ -136,5 +136,18 @@ func (fs *FilesystemStorage) SaveIncident(ctx context.Context, incidentID string
 		ArtifactURLs: artifactURLs,
 		LogURLs:      logURLs,
 		ExpiresAt:    time.Time{},
+		Hashes:       artifacts.Hashes(),
 	}, nil
 }
+
+// ReadArtifact reads a stored artifact back from the incident directory.
+func (fs *FilesystemStorage) ReadArtifact(ctx context.Context, incidentID, name string) ([]byte, error) {
+	if !filepath.IsLocal(incidentID) || !filepath.IsLocal(filepath.FromSlash(name)) {
+		return nil, fmt.Errorf("invalid artifact path %s/%s", incidentID, name)
+	}
+	data, err := os.ReadFile(filepath.Join(fs.workspaceRoot, incidentID, filepath.FromSlash(name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact %s: %w", name, err)
+	}
+	return data, nil
+}
@@ -435,3 +435,54 @@ func TestFilesystemStorageSaveIncidentZeroExpiresAt(t *testing.T) {
 		}
 	}
 }
+
+// TestFilesystemStorageArtifactHashes verifies that saved artifacts are hashed and
+// that modified artifacts fail verification when read back.
+func TestFilesystemStorageArtifactHashes(t *testing.T) {
+	tmpDir := t.TempDir()
+	fs := NewFilesystemStorage(tmpDir)
+	ctx := context.Background()
+
+	artifacts := &IncidentArtifacts{
+		IncidentJSON:      []byte(`{"incidentId":"test-001"}`),
+		InvestigationMD:   []byte("# Investigation Report"),
+		InvestigationHTML: []byte("<h1>Investigation Report</h1>"),
+		AgentLogs:         AgentLogs{Combined: []byte("agent output")},
+	}
+	result, err := fs.SaveIncident(ctx, "test-001", artifacts)
+	if err != nil {
+		t.Fatalf("SaveIncident failed: %v", err)
+	}
+	if len(result.Hashes) != 4 {
+		t.Fatalf("expected 4 hashes, got %v", result.Hashes)
+	}
+	if got := result.Hashes["logs/agent-full.log"]; got != HashArtifact([]byte("agent output")) {
+		t.Errorf("unexpected hash for logs/agent-full.log: %s", got)
+	}
+
+	for _, name := range SortedArtifactNames(result.Hashes) {
+		data, err := fs.ReadArtifact(ctx, "test-001", name)
+		if err != nil {
+			t.Fatalf("ReadArtifact(%s) failed: %v", name, err)
+		}
+		if err := VerifyArtifact(name, data, result.Hashes[name]); err != nil {
+			t.Errorf("unmodified artifact failed verification: %v", err)
+		}
+	}
+
+	reportPath := filepath.Join(tmpDir, "test-001", "investigation.md")
+	if err := os.WriteFile(reportPath, []byte("# Edited Report"), 0600); err != nil {
+		t.Fatalf("failed to modify report: %v", err)
+	}
+	data, err := fs.ReadArtifact(ctx, "test-001", "investigation.md")
+	if err != nil {
+		t.Fatalf("ReadArtifact failed: %v", err)
+	}
+	if err := VerifyArtifact("investigation.md", data, result.Hashes["investigation.md"]); err == nil {
+		t.Error("expected verification of a modified artifact to fail")
+	}
+
+	if _, err := fs.ReadArtifact(ctx, "test-001", "../other/incident.json"); err == nil {
+		t.Error("expected ReadArtifact to reject paths outside the incident directory")
+	}
+}
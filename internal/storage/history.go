@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"strings"
+	"time"
+)
+
+// Fields of incident state recorded in the incident history
+const (
+	// HistoryStatus is the incident status (see incident.Statuses)
+	HistoryStatus = "status"
+	// HistoryFailureClass is the canonical class of an agent failure
+	HistoryFailureClass = "failure_class"
+	// historyLabelPrefix prefixes the name of a label in HistoryLabel fields
+	historyLabelPrefix = "label:"
+)
+
+// AssignmentLabel is the label naming the team that owns an incident, set from
+// the ownership lookup, cluster labels, or manual edits; the incident's assignment
+// at a time is its value.
+const AssignmentLabel = "team"
+
+// HistoryLabel returns the history field of a label.
+func HistoryLabel(name string) string {
+	return historyLabelPrefix + name
+}
+
+// IncidentChange is one recorded change of an incident's state.
+type IncidentChange struct {
+	// ID identifies the change (its history_id)
+	ID string `json:"id,omitempty"`
+	// IncidentID is the changed incident
+	IncidentID string `json:"incident_id"`
+	// ChangedAt is when the change was made
+	ChangedAt time.Time `json:"changed_at"`
+	// Field is the changed field: HistoryStatus, HistoryFailureClass, or a label
+	// (see HistoryLabel)
+	Field string `json:"field"`
+	// OldValue is the value before the change (empty: unset)
+	OldValue string `json:"old_value,omitempty"`
+	// NewValue is the value after the change (empty: removed)
+	NewValue string `json:"new_value,omitempty"`
+}
+
+// Label returns the name of the label the change is about, or "" when it changed
+// another field.
+func (c *IncidentChange) Label() string {
+	name, ok := strings.CutPrefix(c.Field, historyLabelPrefix)
+	if !ok {
+		return ""
+	}
+	return name
+}
+
+// IncidentState is the state of an incident at a point in time, replayed from
+// its history.
+type IncidentState struct {
+	IncidentID string    `json:"incident_id"`
+	At         time.Time `json:"at"`
+	// Status is the incident's status at the time
+	Status string `json:"status"`
+	// FailureClass is the failure class recorded by the time
+	FailureClass string `json:"failure_class,omitempty"`
+	// Since is when the status last changed before the time
+	Since time.Time `json:"since"`
+	// Assignment is the team the incident was assigned to (its AssignmentLabel)
+	Assignment string `json:"assignment,omitempty"`
+	// Labels are the incident's labels at the time
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// IncidentStateAt replays an incident's history, ordered by ChangedAt as returned
+// by StateStore.IncidentHistory, up to and including the given time. It returns
+// nil when the incident had no recorded state yet.
+func IncidentStateAt(changes []*IncidentChange, at time.Time) *IncidentState {
+	var state *IncidentState
+	for _, change := range changes {
+		if change.ChangedAt.After(at) {
+			break
+		}
+		if state == nil {
+			state = &IncidentState{IncidentID: change.IncidentID, At: at, Labels: make(map[string]string)}
+		}
+		switch change.Field {
+		case HistoryStatus:
+			state.Status = change.NewValue
+			state.Since = change.ChangedAt
+		case HistoryFailureClass:
+			state.FailureClass = change.NewValue
+		default:
+			if name := change.Label(); name != "" {
+				if change.NewValue == "" {
+					delete(state.Labels, name)
+				} else {
+					state.Labels[name] = change.NewValue
+				}
+			}
+		}
+	}
+	if state != nil {
+		state.Assignment = state.Labels[AssignmentLabel]
+	}
+	return state
+}
+
+// StatusTimeline returns the status changes of an incident's history, in order.
+func StatusTimeline(changes []*IncidentChange) []*IncidentChange {
+	var timeline []*IncidentChange
+	for _, change := range changes {
+		if change.Field == HistoryStatus {
+			timeline = append(timeline, change)
+		}
+	}
+	return timeline
+}
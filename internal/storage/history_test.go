@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIncidentStateAt(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)
+	changes := []*IncidentChange{
+		{IncidentID: "inc-1", ChangedAt: t0, Field: HistoryStatus, NewValue: "investigating"},
+		{IncidentID: "inc-1", ChangedAt: t0, Field: HistoryLabel("team"), NewValue: "platform"},
+		{IncidentID: "inc-1", ChangedAt: t0.Add(5 * time.Minute), Field: HistoryLabel("team"), OldValue: "platform", NewValue: "payments"},
+		{IncidentID: "inc-1", ChangedAt: t0.Add(5 * time.Minute), Field: HistoryLabel("env"), NewValue: "prod"},
+		{IncidentID: "inc-1", ChangedAt: t0.Add(10 * time.Minute), Field: HistoryStatus, OldValue: "investigating", NewValue: "failed"},
+		{IncidentID: "inc-1", ChangedAt: t0.Add(10 * time.Minute), Field: HistoryFailureClass, NewValue: "timeout"},
+		{IncidentID: "inc-1", ChangedAt: t0.Add(20 * time.Minute), Field: HistoryLabel("team"), OldValue: "payments"},
+	}
+
+	tests := []struct {
+		name       string
+		at         time.Time
+		wantNil    bool
+		status     string
+		since      time.Time
+		assignment string
+		class      string
+		labels     int
+	}{
+		{name: "before creation", at: t0.Add(-time.Second), wantNil: true},
+		{name: "at creation", at: t0, status: "investigating", since: t0, assignment: "platform", labels: 1},
+		{name: "after reassignment", at: t0.Add(7 * time.Minute), status: "investigating", since: t0, assignment: "payments", labels: 2},
+		{name: "after failure", at: t0.Add(15 * time.Minute), status: "failed", since: t0.Add(10 * time.Minute), assignment: "payments", class: "timeout", labels: 2},
+		{name: "after unassignment", at: t0.Add(time.Hour), status: "failed", since: t0.Add(10 * time.Minute), class: "timeout", labels: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := IncidentStateAt(changes, tt.at)
+			if tt.wantNil {
+				if state != nil {
+					t.Errorf("IncidentStateAt() = %+v, want nil", state)
+				}
+				return
+			}
+			if state == nil {
+				t.Fatal("IncidentStateAt() = nil")
+			}
+			if state.Status != tt.status || !state.Since.Equal(tt.since) || state.Assignment != tt.assignment ||
+				state.FailureClass != tt.class || len(state.Labels) != tt.labels {
+				t.Errorf("IncidentStateAt() = %+v, want status %s since %s, assignment %q, class %q, %d labels",
+					state, tt.status, tt.since, tt.assignment, tt.class, tt.labels)
+			}
+		})
+	}
+
+	if timeline := StatusTimeline(changes); len(timeline) != 2 || timeline[1].NewValue != "failed" {
+		t.Errorf("StatusTimeline() = %v, want the two status changes", timeline)
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// ArtifactReader is implemented by storage backends that can read stored
+// artifacts back, for verifying and exporting them.
+type ArtifactReader interface {
+	// ReadArtifact returns the stored artifact at name, a path relative to the
+	// incident's storage directory as used by Hashes (e.g. "logs/agent-full.log").
+	ReadArtifact(ctx context.Context, incidentID, name string) ([]byte, error)
+}
+
+// HashArtifact returns the hex-encoded SHA-256 hash of an artifact's contents.
+func HashArtifact(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyArtifact checks an artifact's contents against its recorded hash.
+func VerifyArtifact(name string, data []byte, want string) error {
+	if got := HashArtifact(data); got != want {
+		return fmt.Errorf("artifact %s has been modified: sha256 is %s, recorded %s", name, got, want)
+	}
+	return nil
+}
+
+// Files returns the non-empty artifacts keyed by their path relative to the
+// incident's storage directory. Agent logs and the session archive are stored
+// under logs/.
+func (a *IncidentArtifacts) Files() map[string][]byte {
+	all := map[string][]byte{
+		"incident.json":                     a.IncidentJSON,
+		"investigation.md":                  a.InvestigationMD,
+		"investigation.html":                a.InvestigationHTML,
+		"incident_cluster_permissions.json": a.ClusterPermissionsJSON,
+		"prompt-sent.md":                    a.PromptSent,
+		"logs/agent-stdout.log":             a.AgentLogs.Stdout,
+		"logs/agent-stderr.log":             a.AgentLogs.Stderr,
+		"logs/agent-full.log":               a.AgentLogs.Combined,
+		"logs/agent-commands-executed.log":  a.AgentLogs.CommandsExecuted,
+		"logs/claude-session.tar.gz":        a.ClaudeSessionArchive,
+	}
+	files := make(map[string][]byte, len(all))
+	for name, data := range all {
+		if len(data) > 0 {
+			files[name] = data
+		}
+	}
+	return files
+}
+
+// Hashes returns the SHA-256 hash of every non-empty artifact, keyed like Files.
+func (a *IncidentArtifacts) Hashes() map[string]string {
+	hashes := make(map[string]string)
+	for name, data := range a.Files() {
+		hashes[name] = HashArtifact(data)
+	}
+	return hashes
+}
+
+// SortedArtifactNames returns the artifact names of a hash map in sorted order.
+func SortedArtifactNames(hashes map[string]string) []string {
+	names := make([]string, 0, len(hashes))
+	for name := range hashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -2,14 +2,19 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/database/sqlite3"
-	"github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
 // MigrationConfig holds configuration for database migrations
@@ -39,26 +44,15 @@ func RunMigrations(cfg *MigrationConfig) error {
 		return fmt.Errorf("failed to create migration driver: %w", err)
 	}
 
-	// Create file source for migrations
-	migrationsPath := cfg.MigrationsPath
-	if !filepath.IsAbs(migrationsPath) {
-		// Convert to absolute path if relative
-		absPath, err := filepath.Abs(migrationsPath)
-		if err != nil {
-			return fmt.Errorf("failed to resolve migrations path: %w", err)
-		}
-		migrationsPath = absPath
-	}
-
-	sourceURL := fmt.Sprintf("file://%s", migrationsPath)
-	sourceInstance, err := (&file.File{}).Open(sourceURL)
+	// Open the migrations of the database type
+	sourceInstance, err := openMigrationSource(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to open migrations source: %w", err)
+		return err
 	}
 
 	// Create migration instance
 	m, err := migrate.NewWithInstance(
-		"file",
+		"iofs",
 		sourceInstance,
 		cfg.DatabaseType,
 		driver,
@@ -91,26 +85,15 @@ func RollbackMigrations(cfg *MigrationConfig, steps int) error {
 		return fmt.Errorf("failed to create migration driver: %w", err)
 	}
 
-	// Create file source for migrations
-	migrationsPath := cfg.MigrationsPath
-	if !filepath.IsAbs(migrationsPath) {
-		// Convert to absolute path if relative
-		absPath, err := filepath.Abs(migrationsPath)
-		if err != nil {
-			return fmt.Errorf("failed to resolve migrations path: %w", err)
-		}
-		migrationsPath = absPath
-	}
-
-	sourceURL := fmt.Sprintf("file://%s", migrationsPath)
-	sourceInstance, err := (&file.File{}).Open(sourceURL)
+	// Open the migrations of the database type
+	sourceInstance, err := openMigrationSource(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to open migrations source: %w", err)
+		return err
 	}
 
 	// Create migration instance
 	m, err := migrate.NewWithInstance(
-		"file",
+		"iofs",
 		sourceInstance,
 		cfg.DatabaseType,
 		driver,
@@ -150,26 +133,15 @@ func GetMigrationVersion(cfg *MigrationConfig) (uint, bool, error) {
 		return 0, false, fmt.Errorf("failed to create migration driver: %w", err)
 	}
 
-	// Create file source for migrations
-	migrationsPath := cfg.MigrationsPath
-	if !filepath.IsAbs(migrationsPath) {
-		// Convert to absolute path if relative
-		absPath, err := filepath.Abs(migrationsPath)
-		if err != nil {
-			return 0, false, fmt.Errorf("failed to resolve migrations path: %w", err)
-		}
-		migrationsPath = absPath
-	}
-
-	sourceURL := fmt.Sprintf("file://%s", migrationsPath)
-	sourceInstance, err := (&file.File{}).Open(sourceURL)
+	// Open the migrations of the database type
+	sourceInstance, err := openMigrationSource(cfg)
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to open migrations source: %w", err)
+		return 0, false, err
 	}
 
 	// Create migration instance
 	m, err := migrate.NewWithInstance(
-		"file",
+		"iofs",
 		sourceInstance,
 		cfg.DatabaseType,
 		driver,
@@ -187,6 +159,68 @@ func GetMigrationVersion(cfg *MigrationConfig) (uint, bool, error) {
 	return version, dirty, nil
 }
 
+// migrationDialects are the database types a migration can be specific to
+var migrationDialects = []string{"sqlite", "postgres"}
+
+// openMigrationSource opens the migrations directory for the configured database
+// type. Most migrations are portable SQL shared by both database types; schema
+// changes that cannot be written portably (e.g. altering a CHECK constraint, which
+// SQLite only supports by rebuilding the table) come as one migration per database
+// type, named after it: 000017_incident_lifecycle.sqlite.up.sql and
+// 000017_incident_lifecycle.postgres.up.sql. The migrations of the other database
+// types are hidden from the source.
+func openMigrationSource(cfg *MigrationConfig) (source.Driver, error) {
+	migrationsPath, err := filepath.Abs(cfg.MigrationsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve migrations path: %w", err)
+	}
+	if _, err := os.Stat(migrationsPath); err != nil {
+		return nil, fmt.Errorf("failed to open migrations source: %w", err)
+	}
+	sourceInstance, err := iofs.New(dialectFS{FS: os.DirFS(migrationsPath), dbType: cfg.DatabaseType}, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migrations source: %w", err)
+	}
+	return sourceInstance, nil
+}
+
+// dialectFS is a migrations directory without the migrations specific to database
+// types other than dbType.
+type dialectFS struct {
+	fs.FS
+	dbType string
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (d dialectFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(d.FS, name)
+	if err != nil {
+		return nil, err
+	}
+	kept := entries[:0]
+	for _, entry := range entries {
+		if dialect := migrationDialect(entry.Name()); dialect == "" || dialect == d.dbType {
+			kept = append(kept, entry)
+		}
+	}
+	return kept, nil
+}
+
+// migrationDialect returns the database type a migration file is specific to, or
+// "" for a portable migration.
+func migrationDialect(name string) string {
+	for _, direction := range []string{".up.", ".down."} {
+		if base, _, ok := strings.Cut(name, direction); ok {
+			for _, dialect := range migrationDialects {
+				if strings.HasSuffix(base, "."+dialect) {
+					return dialect
+				}
+			}
+		}
+	}
+	return ""
+}
+
 // openDatabase opens a database connection based on the configuration
 func openDatabase(cfg *MigrationConfig) (*sql.DB, error) {
 	switch cfg.DatabaseType {
@@ -236,3 +270,37 @@ func createMigrationDriver(db *sql.DB, dbType string) (database.Driver, error) {
 		return nil, fmt.Errorf("unsupported database type: %s", dbType)
 	}
 }
+
+// PendingMigrations returns the database's current schema version and the latest
+// version available in the migrations directory. The database needs migrating
+// when latest is greater than current; current is 0 for an empty database.
+func PendingMigrations(cfg *MigrationConfig) (current, latest uint, err error) {
+	current, _, err = GetMigrationVersion(cfg)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	source, err := openMigrationSource(cfg)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if errors.Is(err, fs.ErrNotExist) {
+		return current, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	for {
+		latest = version
+		version, err = source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return current, latest, nil
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+	}
+}
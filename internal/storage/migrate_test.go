@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite" // SQLite driver
+)
+
+func TestPendingMigrations(t *testing.T) {
+	cfg := &MigrationConfig{
+		MigrationsPath: "../../migrations",
+		DatabaseType:   "sqlite",
+		DatabasePath:   filepath.Join(t.TempDir(), "nightcrier.db"),
+	}
+
+	current, latest, err := PendingMigrations(cfg)
+	if err != nil {
+		t.Fatalf("PendingMigrations() error = %v", err)
+	}
+	if current != 0 || latest == 0 {
+		t.Errorf("PendingMigrations() of an empty database = %d, %d, want 0 and the latest migration", current, latest)
+	}
+
+	if err := RunMigrations(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if current, after, err := PendingMigrations(cfg); err != nil || current != latest || after != latest {
+		t.Errorf("PendingMigrations() after migrating = %d, %d, %v, want both %d", current, after, err, latest)
+	}
+}
+
+func TestIncidentLifecycleMigration(t *testing.T) {
+	cfg := &MigrationConfig{
+		MigrationsPath: "../../migrations",
+		DatabaseType:   "sqlite",
+		DatabasePath:   filepath.Join(t.TempDir(), "nightcrier.db"),
+	}
+	if err := RunMigrations(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", cfg.DatabasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`
+		INSERT INTO fault_events (fault_id, subscription_id, cluster, received_at, fault_type, severity, context, timestamp)
+		VALUES ('f1', 's1', 'prod', CURRENT_TIMESTAMP, 'OOMKilled', 'ERROR', 'oom', 'now');
+		INSERT INTO incidents (incident_id, fault_id, status, created_at, cluster, fault_type, severity, context, timestamp, display_id)
+		VALUES ('i1', 'f1', 'awaiting_review', CURRENT_TIMESTAMP, 'prod', 'OOMKilled', 'ERROR', 'oom', 'now', 'NC-1')`); err != nil {
+		t.Fatalf("inserting an incident awaiting review failed: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE incidents SET status = 'closed'`); err == nil {
+		t.Error("the status constraint accepted an unknown status")
+	}
+
+	// Rolling back maps the lifecycle statuses to the earlier ones
+	if err := RollbackMigrations(cfg, 1); err != nil {
+		t.Fatal(err)
+	}
+	var status, displayID string
+	if err := db.QueryRow(`SELECT status, display_id FROM incidents WHERE incident_id = 'i1'`).Scan(&status, &displayID); err != nil {
+		t.Fatal(err)
+	}
+	if status != "resolved" || displayID != "NC-1" {
+		t.Errorf("incident after rollback = %s, %s, want resolved and its display ID kept", status, displayID)
+	}
+}
+
+func TestMigrationDialect(t *testing.T) {
+	tests := map[string]string{
+		"000016_incident_source.up.sql":               "",
+		"000017_incident_lifecycle.sqlite.up.sql":     "sqlite",
+		"000017_incident_lifecycle.postgres.down.sql": "postgres",
+		"000018_sqlite_tuning.up.sql":                 "",
+	}
+	for name, want := range tests {
+		if got := migrationDialect(name); got != want {
+			t.Errorf("migrationDialect(%s) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Dump writes a backup of the database to dest by running pg_dump in its custom
+// format, which pg_restore can restore selectively. pg_dump must be on the PATH
+// and should be at least as new as the server.
+func Dump(ctx context.Context, connStr, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("backup file %s already exists", dest)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	if err := runTool(ctx, "pg_dump", connStr, "--format=custom", "--no-owner", "--file", dest); err != nil {
+		os.Remove(dest)
+		return err
+	}
+	if err := os.Chmod(dest, 0600); err != nil {
+		return fmt.Errorf("failed to restrict backup permissions: %w", err)
+	}
+	return nil
+}
+
+// RestoreDump replaces the database's objects with those in a pg_dump backup by
+// running pg_restore with --clean. Nightcrier must not be running against the
+// database.
+func RestoreDump(ctx context.Context, connStr, src string) error {
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("refusing to restore: %w", err)
+	}
+	return runTool(ctx, "pg_restore", connStr, "--clean", "--if-exists", "--no-owner", "--single-transaction", src)
+}
+
+// runTool runs a PostgreSQL client tool against the database. The password is
+// passed in PGPASSWORD rather than on the command line, where other users could
+// read it from the process list.
+func runTool(ctx context.Context, tool, connStr string, args ...string) error {
+	path, err := exec.LookPath(tool)
+	if err != nil {
+		return fmt.Errorf("%s not found on PATH; install the PostgreSQL client tools: %w", tool, err)
+	}
+	dbURL, password, err := splitPassword(connStr)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, path, append([]string{"--dbname", dbURL}, args...)...)
+	cmd.Env = os.Environ()
+	if password != "" {
+		cmd.Env = append(cmd.Env, "PGPASSWORD="+password)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %w: %s", tool, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// splitPassword removes the password from a postgres:// connection URL.
+func splitPassword(connStr string) (string, string, error) {
+	u, err := url.Parse(connStr)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		return "", "", fmt.Errorf("invalid PostgreSQL connection URL")
+	}
+	if u.User == nil {
+		return connStr, "", nil
+	}
+	password, _ := u.User.Password()
+	u.User = url.User(u.User.Username())
+	return u.String(), password, nil
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// ChangeChannel is the notification channel on which every recorded incident
+// change is published (NOTIFY) when its transaction commits.
+const ChangeChannel = "nightcrier_incident_changes"
+
+// maxNotifyPayload stays below PostgreSQL's 8000 byte limit on notification
+// payloads. Larger changes are published without a payload, and listeners read
+// them from the incident history instead.
+const maxNotifyPayload = 7900
+
+// Listener reconnect and keepalive settings
+const (
+	listenerMinReconnect = time.Second
+	listenerMaxReconnect = time.Minute
+	listenerPingInterval = 90 * time.Second
+)
+
+// changeNotification returns the notification payload of an incident change: the
+// change as JSON, or "" when it does not fit.
+func changeNotification(change *storage.IncidentChange) string {
+	payload, err := json.Marshal(change)
+	if err != nil || len(payload) > maxNotifyPayload {
+		return ""
+	}
+	return string(payload)
+}
+
+// decodeChangeNotification returns the incident change of a notification, or nil
+// when the notification has none: an empty or invalid payload, or the nil
+// notification the listener sends after reconnecting.
+func decodeChangeNotification(n *pq.Notification) *storage.IncidentChange {
+	if n == nil || n.Extra == "" {
+		return nil
+	}
+	var change storage.IncidentChange
+	if err := json.Unmarshal([]byte(n.Extra), &change); err != nil || change.ID == "" {
+		return nil
+	}
+	return &change
+}
+
+// ChangeListener receives the incident changes published on ChangeChannel by every
+// process using the database, as soon as they are committed (LISTEN). It keeps
+// its own connection and reconnects when the connection is lost.
+type ChangeListener struct {
+	listener *pq.Listener
+	changes  chan *storage.IncidentChange
+}
+
+// ListenChanges starts listening for incident changes until ctx is done.
+func ListenChanges(ctx context.Context, connString string) (*ChangeListener, error) {
+	listener := pq.NewListener(connString, listenerMinReconnect, listenerMaxReconnect, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+			slog.Warn("incident change listener disconnected, reconnecting", "error", err)
+		case pq.ListenerEventReconnected:
+			slog.Info("incident change listener reconnected")
+		}
+	})
+	if err := listener.Listen(ChangeChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen for incident changes: %w", err)
+	}
+
+	l := &ChangeListener{listener: listener, changes: make(chan *storage.IncidentChange, 100)}
+	go l.run(ctx)
+	return l, nil
+}
+
+// Changes implements incidentfeed.Listener. A nil change means notifications may
+// have been missed (after a reconnect) or carried no payload; the feed then reads
+// the incident history. The channel is closed when the listener stops.
+func (l *ChangeListener) Changes() <-chan *storage.IncidentChange {
+	return l.changes
+}
+
+func (l *ChangeListener) run(ctx context.Context) {
+	defer close(l.changes)
+	defer l.listener.Close()
+
+	ping := time.NewTicker(listenerPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-l.listener.Notify:
+			select {
+			case l.changes <- decodeChangeNotification(n):
+			case <-ctx.Done():
+				return
+			}
+		case <-ping.C:
+			// Detects a dead connection that would otherwise wait for a notification
+			// forever
+			go func() {
+				if err := l.listener.Ping(); err != nil {
+					slog.Debug("incident change listener ping failed", "error", err)
+				}
+			}()
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+func TestChangeNotification(t *testing.T) {
+	change := &storage.IncidentChange{
+		ID:         "c1",
+		IncidentID: "i1",
+		ChangedAt:  time.Date(2026, 1, 14, 10, 0, 0, 0, time.UTC),
+		Field:      storage.HistoryStatus,
+		OldValue:   "pending",
+		NewValue:   "investigating",
+	}
+	got := decodeChangeNotification(&pq.Notification{Channel: ChangeChannel, Extra: changeNotification(change)})
+	if got == nil || *got != *change {
+		t.Errorf("decoded %+v, want %+v", got, change)
+	}
+
+	large := *change
+	large.NewValue = strings.Repeat("x", maxNotifyPayload)
+	if payload := changeNotification(&large); payload != "" {
+		t.Errorf("changeNotification() of an oversized change = %d bytes, want no payload", len(payload))
+	}
+
+	for _, n := range []*pq.Notification{nil, {Extra: ""}, {Extra: "{"}, {Extra: `{"incident_id":"i1"}`}} {
+		if got := decodeChangeNotification(n); got != nil {
+			t.Errorf("decodeChangeNotification(%+v) = %+v, want nil", n, got)
+		}
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/rbias/nightcrier/internal/events"
 	"github.com/rbias/nightcrier/internal/incident"
@@ -141,10 +143,11 @@ func (s *Store) CreateIncident(ctx context.Context, inc *incident.Incident, even
 		INSERT INTO incidents (
 			incident_id, fault_id, triggering_event_id,
 			status, created_at, started_at, completed_at,
-			exit_code, failure_reason,
+			exit_code, failure_reason, failure_class,
 			cluster, namespace, fault_type, severity, context, timestamp,
-			resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`,
+			resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid,
+			parent_incident_id, display_id, source
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)`,
 		inc.IncidentID,
 		inc.FaultID,
 		nullStringValue(inc.TriggeringEventID),
@@ -154,6 +157,7 @@ func (s *Store) CreateIncident(ctx context.Context, inc *incident.Incident, even
 		inc.CompletedAt,
 		inc.ExitCode,
 		nullStringValue(inc.FailureReason),
+		nullStringValue(inc.FailureClass),
 		inc.Cluster,
 		nullStringValue(inc.Namespace),
 		inc.FaultType,
@@ -165,11 +169,24 @@ func (s *Store) CreateIncident(ctx context.Context, inc *incident.Incident, even
 		nullString(inc.Resource, func(r *incident.ResourceInfo) string { return r.Name }),
 		nullString(inc.Resource, func(r *incident.ResourceInfo) string { return r.Namespace }),
 		nullString(inc.Resource, func(r *incident.ResourceInfo) string { return r.UID }),
+		nullStringValue(inc.ParentIncidentID),
+		nullStringValue(inc.DisplayID),
+		nullStringValue(inc.Source),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert incident: %w", err)
 	}
 
+	// Insert labels and annotations attached at creation
+	if err := upsertLabels(ctx, tx, inc.IncidentID, inc.Labels, inc.Annotations); err != nil {
+		return err
+	}
+
+	// Record the initial state in the incident's history
+	if err := recordCreation(ctx, tx, inc); err != nil {
+		return err
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -179,11 +196,30 @@ func (s *Store) CreateIncident(ctx context.Context, inc *incident.Incident, even
 }
 
 // UpdateIncidentStatus updates the status of an existing incident.
-// The startedAt timestamp is set when transitioning to investigating status.
+// The startedAt timestamp is set when transitioning to investigating status; nil keeps it.
+// Changes the incident lifecycle does not allow are rejected.
 func (s *Store) UpdateIncidentStatus(ctx context.Context, incidentID string, status string, startedAt *time.Time) error {
-	result, err := s.db.ExecContext(ctx, `
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldStatus string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM incidents WHERE incident_id = $1 FOR UPDATE`, incidentID).Scan(&oldStatus)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("incident not found: %s", incidentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up incident: %w", err)
+	}
+	if err := incident.ValidateTransition(oldStatus, status); err != nil {
+		return fmt.Errorf("incident %s: %w", incidentID, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE incidents
-		SET status = $1, started_at = $2
+		SET status = $1, started_at = COALESCE($2, started_at)
 		WHERE incident_id = $3`,
 		status,
 		startedAt,
@@ -193,50 +229,74 @@ func (s *Store) UpdateIncidentStatus(ctx context.Context, incidentID string, sta
 		return fmt.Errorf("failed to update incident status: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("incident not found: %s", incidentID)
+	if err := recordChange(ctx, tx, incidentID, time.Now(), storage.HistoryStatus, oldStatus, status); err != nil {
+		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
 // CompleteIncident marks an incident as complete with final result information.
-// This updates the incident record with completion time, exit code, and failure reason.
-func (s *Store) CompleteIncident(ctx context.Context, incidentID string, exitCode int, failureReason string) error {
+// This updates the incident record with the final status (empty: by exit code and
+// failure reason), completion time, exit code, and failure reason and class.
+func (s *Store) CompleteIncident(ctx context.Context, incidentID, status string, exitCode int, failureReason, failureClass string) error {
 	now := time.Now()
 
-	// Determine status based on exit code and failure reason
-	status := incident.StatusResolved
-	if failureReason != "" || exitCode != 0 {
-		status = incident.StatusFailed
+	// Without a final status, determine it based on exit code and failure reason
+	if status == "" {
+		status = incident.StatusResolved
+		if failureReason != "" || exitCode != 0 {
+			status = incident.StatusFailed
+		}
 	}
 
-	result, err := s.db.ExecContext(ctx, `
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldStatus string
+	var oldFailureClass sql.NullString
+	err = tx.QueryRowContext(ctx, `SELECT status, failure_class FROM incidents WHERE incident_id = $1 FOR UPDATE`, incidentID).Scan(&oldStatus, &oldFailureClass)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("incident not found: %s", incidentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up incident: %w", err)
+	}
+	if err := incident.ValidateTransition(oldStatus, status); err != nil {
+		return fmt.Errorf("incident %s: %w", incidentID, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE incidents
-		SET status = $1, completed_at = $2, exit_code = $3, failure_reason = $4
-		WHERE incident_id = $5`,
+		SET status = $1, completed_at = $2, exit_code = $3, failure_reason = $4, failure_class = $5
+		WHERE incident_id = $6`,
 		status,
 		now,
 		exitCode,
 		nullStringValue(failureReason),
+		nullStringValue(failureClass),
 		incidentID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to complete incident: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if err := recordChange(ctx, tx, incidentID, now, storage.HistoryStatus, oldStatus, status); err != nil {
+		return err
 	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("incident not found: %s", incidentID)
+	if err := recordChange(ctx, tx, incidentID, now, storage.HistoryFailureClass, oldFailureClass.String, failureClass); err != nil {
+		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
@@ -253,16 +313,42 @@ func (s *Store) RecordAgentExecution(ctx context.Context, exec *storage.AgentExe
 		logPathsJSON = sql.NullString{String: string(data), Valid: true}
 	}
 
+	// Resource usage columns stay NULL until the usage is sampled
+	var cpuSeconds sql.NullFloat64
+	var peakRSS, peakProcesses, diskWritten sql.NullInt64
+	var resourceSource sql.NullString
+	if r := exec.Resources; r != nil {
+		cpuSeconds = sql.NullFloat64{Float64: r.CPUSeconds, Valid: true}
+		peakRSS = sql.NullInt64{Int64: r.PeakRSSBytes, Valid: true}
+		peakProcesses = sql.NullInt64{Int64: int64(r.PeakProcesses), Valid: true}
+		diskWritten = sql.NullInt64{Int64: r.DiskWrittenBytes, Valid: true}
+		resourceSource = sql.NullString{String: r.Source, Valid: true}
+	}
+
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO agent_executions (
 			execution_id, incident_id, started_at, completed_at,
-			exit_code, error_message, log_paths
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			exit_code, error_message, log_paths,
+			agent_cli, agent_image, agent_model, prompt_version,
+			agent_cli_version, agent_image_digest,
+			cpu_seconds, peak_rss_bytes, peak_processes, disk_written_bytes, resource_source
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 		ON CONFLICT (execution_id) DO UPDATE SET
 			completed_at = EXCLUDED.completed_at,
 			exit_code = EXCLUDED.exit_code,
 			error_message = EXCLUDED.error_message,
-			log_paths = EXCLUDED.log_paths`,
+			log_paths = EXCLUDED.log_paths,
+			agent_cli = EXCLUDED.agent_cli,
+			agent_image = EXCLUDED.agent_image,
+			agent_model = EXCLUDED.agent_model,
+			prompt_version = EXCLUDED.prompt_version,
+			agent_cli_version = EXCLUDED.agent_cli_version,
+			agent_image_digest = EXCLUDED.agent_image_digest,
+			cpu_seconds = EXCLUDED.cpu_seconds,
+			peak_rss_bytes = EXCLUDED.peak_rss_bytes,
+			peak_processes = EXCLUDED.peak_processes,
+			disk_written_bytes = EXCLUDED.disk_written_bytes,
+			resource_source = EXCLUDED.resource_source`,
 		exec.ExecutionID,
 		exec.IncidentID,
 		exec.StartedAt,
@@ -270,6 +356,17 @@ func (s *Store) RecordAgentExecution(ctx context.Context, exec *storage.AgentExe
 		exec.ExitCode,
 		nullStringValue(exec.ErrorMessage),
 		logPathsJSON,
+		nullStringValue(exec.AgentCLI),
+		nullStringValue(exec.AgentImage),
+		nullStringValue(exec.AgentModel),
+		nullStringValue(exec.PromptVersion),
+		nullStringValue(exec.AgentCLIVersion),
+		nullStringValue(exec.AgentImageDigest),
+		cpuSeconds,
+		peakRSS,
+		peakProcesses,
+		diskWritten,
+		resourceSource,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert/update agent_execution: %w", err)
@@ -278,6 +375,50 @@ func (s *Store) RecordAgentExecution(ctx context.Context, exec *storage.AgentExe
 	return nil
 }
 
+// AgentResourceStats aggregates the resource usage of the agent executions
+// started since the given time, per agent CLI, image, and model.
+func (s *Store) AgentResourceStats(ctx context.Context, since time.Time) ([]*storage.AgentResourceStats, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			COALESCE(agent_cli, ''), COALESCE(agent_image, ''), COALESCE(agent_model, ''),
+			COUNT(*),
+			AVG(cpu_seconds), MAX(cpu_seconds),
+			AVG(peak_rss_bytes)::BIGINT, MAX(peak_rss_bytes),
+			MAX(peak_processes),
+			AVG(disk_written_bytes)::BIGINT, MAX(disk_written_bytes)
+		FROM agent_executions
+		WHERE resource_source IS NOT NULL AND started_at >= $1
+		GROUP BY 1, 2, 3
+		ORDER BY AVG(cpu_seconds) DESC`,
+		since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query agent resource stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*storage.AgentResourceStats
+	for rows.Next() {
+		var st storage.AgentResourceStats
+		if err := rows.Scan(
+			&st.AgentCLI, &st.AgentImage, &st.AgentModel,
+			&st.Executions,
+			&st.AvgCPUSeconds, &st.MaxCPUSeconds,
+			&st.AvgPeakRSSBytes, &st.MaxPeakRSSBytes,
+			&st.MaxPeakProcesses,
+			&st.AvgDiskWrittenBytes, &st.MaxDiskWrittenBytes,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan agent resource stats row: %w", err)
+		}
+		stats = append(stats, &st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agent resource stats: %w", err)
+	}
+
+	return stats, nil
+}
+
 // RecordTriageReport stores the investigation report generated by the agent.
 func (s *Store) RecordTriageReport(ctx context.Context, report *storage.TriageReport) error {
 	_, err := s.db.ExecContext(ctx, `
@@ -299,85 +440,70 @@ func (s *Store) RecordTriageReport(ctx context.Context, report *storage.TriageRe
 	return nil
 }
 
-// GetIncident retrieves an incident by its ID.
-func (s *Store) GetIncident(ctx context.Context, incidentID string) (*incident.Incident, error) {
-	row := s.db.QueryRowContext(ctx, `
-		SELECT
-			incident_id, fault_id, triggering_event_id,
-			status, created_at, started_at, completed_at,
-			exit_code, failure_reason,
-			cluster, namespace, fault_type, severity, context, timestamp,
-			resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid
-		FROM incidents
-		WHERE incident_id = $1`,
-		incidentID,
-	)
-
-	inc := &incident.Incident{}
-	var triggeringEventID, failureReason, namespace sql.NullString
-	var startedAt, completedAt sql.NullTime
-	var exitCode sql.NullInt64
-	var resourceAPIVersion, resourceKind, resourceName, resourceNamespace, resourceUID sql.NullString
-
-	err := row.Scan(
-		&inc.IncidentID,
-		&inc.FaultID,
-		&triggeringEventID,
-		&inc.Status,
-		&inc.CreatedAt,
-		&startedAt,
-		&completedAt,
-		&exitCode,
-		&failureReason,
-		&inc.Cluster,
-		&namespace,
-		&inc.FaultType,
-		&inc.Severity,
-		&inc.Context,
-		&inc.Timestamp,
-		&resourceAPIVersion,
-		&resourceKind,
-		&resourceName,
-		&resourceNamespace,
-		&resourceUID,
+// GetTriageReport returns the most recent investigation report of an incident.
+// Returns nil if the incident has no report.
+func (s *Store) GetTriageReport(ctx context.Context, incidentID string) (*storage.TriageReport, error) {
+	var report storage.TriageReport
+	var reportHTML sql.NullString
+	err := s.db.QueryRowContext(ctx, `
+		SELECT report_id, incident_id, execution_id, generated_at, report_markdown, report_html
+		FROM triage_reports
+		WHERE incident_id = $1
+		ORDER BY generated_at DESC
+		LIMIT 1`, incidentID).Scan(
+		&report.ReportID,
+		&report.IncidentID,
+		&report.ExecutionID,
+		&report.GeneratedAt,
+		&report.ReportMarkdown,
+		&reportHTML,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("incident not found: %s", incidentID)
+		return nil, nil
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to query incident: %w", err)
+		return nil, fmt.Errorf("failed to get triage report: %w", err)
 	}
+	report.ReportHTML = reportHTML.String
+	return &report, nil
+}
 
-	// Convert nullable fields
-	if triggeringEventID.Valid {
-		inc.TriggeringEventID = triggeringEventID.String
-	}
-	if failureReason.Valid {
-		inc.FailureReason = failureReason.String
-	}
-	if namespace.Valid {
-		inc.Namespace = namespace.String
-	}
-	if startedAt.Valid {
-		inc.StartedAt = &startedAt.Time
+// NextIncidentSequence allocates the next sequence number of a cluster's
+// incidents on a day, starting at 1.
+func (s *Store) NextIncidentSequence(ctx context.Context, cluster, day string) (int, error) {
+	var seq int
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO incident_sequences (cluster, day, last_seq) VALUES ($1, $2, 1)
+		ON CONFLICT (cluster, day) DO UPDATE SET last_seq = incident_sequences.last_seq + 1
+		RETURNING last_seq`, cluster, day).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to allocate incident sequence: %w", err)
 	}
-	if completedAt.Valid {
-		inc.CompletedAt = &completedAt.Time
+	return seq, nil
+}
+
+// GetIncident retrieves an incident by its ID or display ID.
+func (s *Store) GetIncident(ctx context.Context, incidentID string) (*incident.Incident, error) {
+	var row incidentRow
+	err := s.db.QueryRowContext(ctx, `
+		SELECT `+incidentColumns+`
+		FROM incidents i
+		WHERE i.incident_id = $1 OR i.display_id = $1`,
+		incidentID,
+	).Scan(row.dest()...)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("incident not found: %s", incidentID)
 	}
-	if exitCode.Valid {
-		exitCodeInt := int(exitCode.Int64)
-		inc.ExitCode = &exitCodeInt
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident: %w", err)
 	}
 
-	// Reconstruct resource info
-	if resourceKind.Valid && resourceName.Valid {
-		inc.Resource = &incident.ResourceInfo{
-			APIVersion: resourceAPIVersion.String,
-			Kind:       resourceKind.String,
-			Name:       resourceName.String,
-			Namespace:  resourceNamespace.String,
-			UID:        resourceUID.String,
-		}
+	inc := row.incident()
+	if err := s.loadLabels(ctx, []*incident.Incident{inc}); err != nil {
+		return nil, err
+	}
+	if err := s.loadStatusTimes(ctx, []*incident.Incident{inc}); err != nil {
+		return nil, err
 	}
 
 	return inc, nil
@@ -394,9 +520,10 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 		SELECT
 			incident_id, fault_id, triggering_event_id,
 			status, created_at, started_at, completed_at,
-			exit_code, failure_reason,
+			exit_code, failure_reason, failure_class,
 			cluster, namespace, fault_type, severity, context, timestamp,
-			resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid
+			resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid,
+			parent_incident_id, display_id, source
 		FROM incidents
 		WHERE 1=1`
 
@@ -429,6 +556,32 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 		args = append(args, filters.Severity)
 		argIndex++
 	}
+	if filters.ResourceKind != "" {
+		query += fmt.Sprintf(" AND resource_kind = $%d", argIndex)
+		args = append(args, filters.ResourceKind)
+		argIndex++
+	}
+	if filters.ResourceName != "" {
+		query += fmt.Sprintf(" AND resource_name = $%d", argIndex)
+		args = append(args, filters.ResourceName)
+		argIndex++
+	}
+	if filters.ParentIncidentID != "" {
+		query += fmt.Sprintf(" AND parent_incident_id = $%d", argIndex)
+		args = append(args, filters.ParentIncidentID)
+		argIndex++
+	}
+	if filters.Source != "" {
+		query += fmt.Sprintf(" AND source = $%d", argIndex)
+		args = append(args, filters.Source)
+		argIndex++
+	}
+	for _, name := range sortedKeys(filters.Labels) {
+		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM incident_labels l WHERE l.incident_id = incidents.incident_id AND l.kind = $%d AND l.name = $%d AND l.value = $%d)",
+			argIndex, argIndex+1, argIndex+2)
+		args = append(args, kindLabel, name, filters.Labels[name])
+		argIndex += 3
+	}
 	if filters.CreatedAfter != nil {
 		query += fmt.Sprintf(" AND created_at > $%d", argIndex)
 		args = append(args, filters.CreatedAfter)
@@ -466,6 +619,7 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 		var startedAt, completedAt sql.NullTime
 		var exitCode sql.NullInt64
 		var resourceAPIVersion, resourceKind, resourceName, resourceNamespace, resourceUID sql.NullString
+		var failureClass, parentIncidentID, displayID, source sql.NullString
 
 		err := rows.Scan(
 			&inc.IncidentID,
@@ -477,6 +631,7 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 			&completedAt,
 			&exitCode,
 			&failureReason,
+			&failureClass,
 			&inc.Cluster,
 			&namespace,
 			&inc.FaultType,
@@ -488,6 +643,9 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 			&resourceName,
 			&resourceNamespace,
 			&resourceUID,
+			&parentIncidentID,
+			&displayID,
+			&source,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan incident: %w", err)
@@ -500,6 +658,9 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 		if failureReason.Valid {
 			inc.FailureReason = failureReason.String
 		}
+		if failureClass.Valid {
+			inc.FailureClass = failureClass.String
+		}
 		if namespace.Valid {
 			inc.Namespace = namespace.String
 		}
@@ -525,6 +686,16 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 			}
 		}
 
+		if parentIncidentID.Valid {
+			inc.ParentIncidentID = parentIncidentID.String
+		}
+		if displayID.Valid {
+			inc.DisplayID = displayID.String
+		}
+		if source.Valid {
+			inc.Source = source.String
+		}
+
 		incidents = append(incidents, inc)
 	}
 
@@ -532,9 +703,835 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 		return nil, fmt.Errorf("error iterating incidents: %w", err)
 	}
 
+	if err := s.loadLabels(ctx, incidents); err != nil {
+		return nil, err
+	}
+	if err := s.loadStatusTimes(ctx, incidents); err != nil {
+		return nil, err
+	}
+
 	return incidents, nil
 }
 
+// SetIncidentLabels adds labels and annotations to an existing incident,
+// replacing the values of keys it already has.
+func (s *Store) SetIncidentLabels(ctx context.Context, incidentID string, labels, annotations map[string]string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM incidents WHERE incident_id = $1 FOR UPDATE`, incidentID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("incident not found: %s", incidentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up incident: %w", err)
+	}
+
+	oldLabels, err := currentLabels(ctx, tx, incidentID)
+	if err != nil {
+		return err
+	}
+
+	if err := upsertLabels(ctx, tx, incidentID, labels, annotations); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, name := range sortedKeys(labels) {
+		if err := recordChange(ctx, tx, incidentID, now, storage.HistoryLabel(name), oldLabels[name], labels[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RemoveIncidentLabels removes the labels and annotations with the given keys
+// from an incident.
+func (s *Store) RemoveIncidentLabels(ctx context.Context, incidentID string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	oldLabels, err := currentLabels(ctx, tx, incidentID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM incident_labels WHERE incident_id = $1 AND name = ANY($2)`,
+		incidentID, pq.Array(keys))
+	if err != nil {
+		return fmt.Errorf("failed to remove incident labels: %w", err)
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		if err := recordChange(ctx, tx, incidentID, now, storage.HistoryLabel(key), oldLabels[key], ""); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// IncidentHistory returns the recorded changes of an incident's state, oldest
+// first.
+func (s *Store) IncidentHistory(ctx context.Context, incidentID string) ([]*storage.IncidentChange, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT incident_id, changed_at, field, old_value, new_value
+		FROM incident_history
+		WHERE incident_id = $1
+		ORDER BY changed_at`,
+		incidentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*storage.IncidentChange
+	for rows.Next() {
+		var change storage.IncidentChange
+		var oldValue, newValue sql.NullString
+		if err := rows.Scan(&change.IncidentID, &change.ChangedAt, &change.Field, &oldValue, &newValue); err != nil {
+			return nil, fmt.Errorf("failed to scan incident history row: %w", err)
+		}
+		change.OldValue, change.NewValue = oldValue.String, newValue.String
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate incident history: %w", err)
+	}
+	return changes, nil
+}
+
+// IncidentChangesSince returns up to limit incident changes recorded at or after
+// since, oldest first.
+func (s *Store) IncidentChangesSince(ctx context.Context, since time.Time, limit int) ([]*storage.IncidentChange, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT history_id, incident_id, changed_at, field, old_value, new_value
+		FROM incident_history
+		WHERE changed_at >= $1
+		ORDER BY changed_at
+		LIMIT $2`,
+		since, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*storage.IncidentChange
+	for rows.Next() {
+		var change storage.IncidentChange
+		var oldValue, newValue sql.NullString
+		if err := rows.Scan(&change.ID, &change.IncidentID, &change.ChangedAt, &change.Field, &oldValue, &newValue); err != nil {
+			return nil, fmt.Errorf("failed to scan incident change: %w", err)
+		}
+		change.OldValue, change.NewValue = oldValue.String, newValue.String
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate incident changes: %w", err)
+	}
+	return changes, nil
+}
+
+// AddInvestigationReview records a review of an incident's investigation.
+func (s *Store) AddInvestigationReview(ctx context.Context, review *storage.InvestigationReview) error {
+	var exists int
+	err := s.db.QueryRowContext(ctx, `SELECT 1 FROM incidents WHERE incident_id = $1`, review.IncidentID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("incident not found: %s", review.IncidentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up incident: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO investigation_reviews (review_id, incident_id, reviewer, created_at, verdict, comment)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		review.ReviewID, review.IncidentID, review.Reviewer, review.CreatedAt,
+		nullStringValue(review.Verdict), nullStringValue(review.Comment),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert investigation review: %w", err)
+	}
+	return nil
+}
+
+// InvestigationReviews returns the reviews of an incident, oldest first.
+func (s *Store) InvestigationReviews(ctx context.Context, incidentID string) ([]*storage.InvestigationReview, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT review_id, incident_id, reviewer, created_at, verdict, comment
+		FROM investigation_reviews
+		WHERE incident_id = $1
+		ORDER BY created_at`,
+		incidentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query investigation reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []*storage.InvestigationReview
+	for rows.Next() {
+		var review storage.InvestigationReview
+		var verdict, comment sql.NullString
+		if err := rows.Scan(&review.ReviewID, &review.IncidentID, &review.Reviewer, &review.CreatedAt, &verdict, &comment); err != nil {
+			return nil, fmt.Errorf("failed to scan investigation review row: %w", err)
+		}
+		review.Verdict, review.Comment = verdict.String, comment.String
+		reviews = append(reviews, &review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate investigation reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// ReviewAccuracy aggregates the latest verdict on each incident reviewed since the
+// given time per agent model and prompt version.
+func (s *Store) ReviewAccuracy(ctx context.Context, since time.Time) ([]*storage.ReviewAccuracy, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT COALESCE(e.agent_model, ''), COALESCE(e.prompt_version, ''), r.verdict, COUNT(*)
+		FROM investigation_reviews r
+		LEFT JOIN agent_executions e ON e.incident_id = r.incident_id
+		WHERE r.verdict IS NOT NULL AND r.created_at >= $1
+			AND r.created_at = (
+				SELECT MAX(latest.created_at) FROM investigation_reviews latest
+				WHERE latest.incident_id = r.incident_id AND latest.verdict IS NOT NULL
+			)
+		GROUP BY 1, 2, 3`,
+		since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review accuracy: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []storage.VerdictCount
+	for rows.Next() {
+		var c storage.VerdictCount
+		if err := rows.Scan(&c.AgentModel, &c.PromptVersion, &c.Verdict, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan review accuracy row: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate review accuracy: %w", err)
+	}
+	return storage.AggregateAccuracy(counts), nil
+}
+
+// RecordArtifactHashes records the SHA-256 hashes of an incident's stored
+// artifacts, replacing previously recorded hashes of the same artifacts.
+func (s *Store) RecordArtifactHashes(ctx context.Context, incidentID string, hashes map[string]string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	recordedAt := time.Now()
+	for name, hash := range hashes {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO artifact_hashes (incident_id, name, sha256, recorded_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (incident_id, name) DO UPDATE SET sha256 = excluded.sha256, recorded_at = excluded.recorded_at
+		`, incidentID, name, hash, recordedAt)
+		if err != nil {
+			return fmt.Errorf("failed to record artifact hash: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetArtifactHashes returns the recorded artifact hashes of an incident.
+func (s *Store) GetArtifactHashes(ctx context.Context, incidentID string) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT name, sha256 FROM artifact_hashes WHERE incident_id = $1`, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query artifact hashes: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var name, hash string
+		if err := rows.Scan(&name, &hash); err != nil {
+			return nil, fmt.Errorf("failed to scan artifact hash: %w", err)
+		}
+		hashes[name] = hash
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate artifact hashes: %w", err)
+	}
+	return hashes, nil
+}
+
+// PlaceLegalHold places a legal hold on an incident.
+func (s *Store) PlaceLegalHold(ctx context.Context, hold *storage.LegalHold) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM incidents WHERE incident_id = $1`, hold.IncidentID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("incident not found: %s", hold.IncidentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up incident: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx,
+		`SELECT 1 FROM legal_holds WHERE incident_id = $1 AND released_at IS NULL`,
+		hold.IncidentID).Scan(&exists)
+	if err == nil {
+		return fmt.Errorf("incident %s is already under legal hold", hold.IncidentID)
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to look up legal hold: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO legal_holds (hold_id, incident_id, reason, placed_by, placed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, hold.HoldID, hold.IncidentID, hold.Reason, hold.PlacedBy, hold.PlacedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert legal hold: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ReleaseLegalHold releases the active legal hold on an incident.
+func (s *Store) ReleaseLegalHold(ctx context.Context, incidentID, releasedBy string, releasedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE legal_holds SET released_at = $1, released_by = $2
+		WHERE incident_id = $3 AND released_at IS NULL
+	`, releasedAt, releasedBy, incidentID)
+	if err != nil {
+		return fmt.Errorf("failed to release legal hold: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("incident %s is not under legal hold", incidentID)
+	}
+	return nil
+}
+
+// ListLegalHolds returns legal holds, newest first.
+func (s *Store) ListLegalHolds(ctx context.Context, includeReleased bool) ([]*storage.LegalHold, error) {
+	query := `
+		SELECT hold_id, incident_id, reason, placed_by, placed_at, released_at, released_by
+		FROM legal_holds
+	`
+	if !includeReleased {
+		query += " WHERE released_at IS NULL"
+	}
+	query += " ORDER BY placed_at DESC"
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list legal holds: %w", err)
+	}
+	defer rows.Close()
+
+	var holds []*storage.LegalHold
+	for rows.Next() {
+		var hold storage.LegalHold
+		var releasedAt sql.NullTime
+		var releasedBy sql.NullString
+		err := rows.Scan(&hold.HoldID, &hold.IncidentID, &hold.Reason, &hold.PlacedBy, &hold.PlacedAt, &releasedAt, &releasedBy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan legal hold row: %w", err)
+		}
+		if releasedAt.Valid {
+			hold.ReleasedAt = &releasedAt.Time
+		}
+		hold.ReleasedBy = releasedBy.String
+		holds = append(holds, &hold)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate legal holds: %w", err)
+	}
+	return holds, nil
+}
+
+// CreateSilence records a silence.
+func (s *Store) CreateSilence(ctx context.Context, silence *storage.Silence) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO silences (silence_id, cluster, namespace, reason, created_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, silence.SilenceID, silence.Cluster, silence.Namespace, silence.Reason, silence.CreatedBy, silence.CreatedAt, silence.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert silence: %w", err)
+	}
+	return nil
+}
+
+// LiftSilence ends a silence before it expires.
+func (s *Store) LiftSilence(ctx context.Context, silenceID, liftedBy string, liftedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE silences SET lifted_at = $1, lifted_by = $2
+		WHERE silence_id = $3 AND lifted_at IS NULL AND expires_at > $4
+	`, liftedAt, liftedBy, silenceID, liftedAt)
+	if err != nil {
+		return fmt.Errorf("failed to lift silence: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("no active silence %s", silenceID)
+	}
+	return nil
+}
+
+// ListSilences returns the silences active at the given time, soonest expiry first.
+func (s *Store) ListSilences(ctx context.Context, activeAt time.Time) ([]*storage.Silence, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT silence_id, cluster, namespace, reason, created_by, created_at, expires_at
+		FROM silences
+		WHERE lifted_at IS NULL AND expires_at > $1
+		ORDER BY expires_at ASC
+	`, activeAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+	defer rows.Close()
+
+	var silences []*storage.Silence
+	for rows.Next() {
+		var silence storage.Silence
+		err := rows.Scan(&silence.SilenceID, &silence.Cluster, &silence.Namespace, &silence.Reason,
+			&silence.CreatedBy, &silence.CreatedAt, &silence.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan silence row: %w", err)
+		}
+		silences = append(silences, &silence)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate silences: %w", err)
+	}
+	return silences, nil
+}
+
+// GetSharedState returns the shared state stored under key, or nil when none is stored.
+func (s *Store) GetSharedState(ctx context.Context, key string) (*storage.SharedState, error) {
+	state := &storage.SharedState{Key: key}
+	var value string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT value, version, expires_at FROM shared_state WHERE state_key = $1
+	`, key).Scan(&value, &state.Version, &state.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared state: %w", err)
+	}
+	state.Value = []byte(value)
+	return state, nil
+}
+
+// SwapSharedState stores value under key if the stored version is still version.
+func (s *Store) SwapSharedState(ctx context.Context, key string, version int64, value []byte, expiresAt time.Time) (bool, error) {
+	var result sql.Result
+	var err error
+	if version == 0 {
+		result, err = s.db.ExecContext(ctx, `
+			INSERT INTO shared_state (state_key, value, version, expires_at)
+			VALUES ($1, $2, 1, $3)
+			ON CONFLICT (state_key) DO NOTHING
+		`, key, string(value), expiresAt)
+	} else {
+		result, err = s.db.ExecContext(ctx, `
+			UPDATE shared_state SET value = $1, version = version + 1, expires_at = $2
+			WHERE state_key = $3 AND version = $4
+		`, string(value), expiresAt, key, version)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to store shared state: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows == 1, nil
+}
+
+// PruneSharedState deletes the shared state that expired before the given time.
+func (s *Store) PruneSharedState(ctx context.Context, before time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM shared_state WHERE expires_at < $1
+	`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune shared state: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
+
+// Label kinds stored in the incident_labels table
+const (
+	kindLabel      = "label"
+	kindAnnotation = "annotation"
+)
+
+// upsertLabels inserts or updates the labels and annotations of an incident.
+func upsertLabels(ctx context.Context, tx *sql.Tx, incidentID string, labels, annotations map[string]string) error {
+	for kind, values := range map[string]map[string]string{kindLabel: labels, kindAnnotation: annotations} {
+		for name, value := range values {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO incident_labels (incident_id, kind, name, value)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (incident_id, kind, name) DO UPDATE SET value = EXCLUDED.value
+			`, incidentID, kind, name, value)
+			if err != nil {
+				return fmt.Errorf("failed to insert incident %s: %w", kind, err)
+			}
+		}
+	}
+	return nil
+}
+
+// recordChange records a change of an incident's state in its history, unless the
+// value stayed the same.
+func recordChange(ctx context.Context, tx *sql.Tx, incidentID string, at time.Time, field, oldValue, newValue string) error {
+	if oldValue == newValue {
+		return nil
+	}
+	change := &storage.IncidentChange{
+		ID:         uuid.New().String(),
+		IncidentID: incidentID,
+		ChangedAt:  at,
+		Field:      field,
+		OldValue:   oldValue,
+		NewValue:   newValue,
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO incident_history (history_id, incident_id, changed_at, field, old_value, new_value)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		change.ID,
+		incidentID,
+		at,
+		field,
+		nullStringValue(oldValue),
+		nullStringValue(newValue),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record incident history: %w", err)
+	}
+
+	// Publish the change to the instances listening on ChangeChannel. The
+	// notification is delivered when the transaction commits, and not at all when
+	// it rolls back.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, ChangeChannel, changeNotification(change)); err != nil {
+		return fmt.Errorf("failed to publish incident change: %w", err)
+	}
+	return nil
+}
+
+// recordCreation records the initial status, failure class, and labels of a new
+// incident in its history, at its creation time.
+func recordCreation(ctx context.Context, tx *sql.Tx, inc *incident.Incident) error {
+	if err := recordChange(ctx, tx, inc.IncidentID, inc.CreatedAt, storage.HistoryStatus, "", inc.Status); err != nil {
+		return err
+	}
+	if err := recordChange(ctx, tx, inc.IncidentID, inc.CreatedAt, storage.HistoryFailureClass, "", inc.FailureClass); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(inc.Labels) {
+		if err := recordChange(ctx, tx, inc.IncidentID, inc.CreatedAt, storage.HistoryLabel(name), "", inc.Labels[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentLabels returns the labels (not annotations) an incident has now.
+func currentLabels(ctx context.Context, tx *sql.Tx, incidentID string) (map[string]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT name, value FROM incident_labels WHERE incident_id = $1 AND kind = $2`, incidentID, kindLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load incident labels: %w", err)
+	}
+	defer rows.Close()
+
+	labels := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan incident label row: %w", err)
+		}
+		labels[name] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incident label rows: %w", err)
+	}
+	return labels, nil
+}
+
+// loadLabels populates the labels and annotations of the given incidents.
+func (s *Store) loadLabels(ctx context.Context, incidents []*incident.Incident) error {
+	if len(incidents) == 0 {
+		return nil
+	}
+	byID := make(map[string]*incident.Incident, len(incidents))
+	ids := make([]string, 0, len(incidents))
+	for _, inc := range incidents {
+		byID[inc.IncidentID] = inc
+		ids = append(ids, inc.IncidentID)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT incident_id, kind, name, value FROM incident_labels WHERE incident_id = ANY($1)`,
+		pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to load incident labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var incidentID, kind, name, value string
+		if err := rows.Scan(&incidentID, &kind, &name, &value); err != nil {
+			return fmt.Errorf("failed to scan incident label row: %w", err)
+		}
+		inc := byID[incidentID]
+		if kind == kindAnnotation {
+			inc.AddLabels(nil, map[string]string{name: value})
+		} else {
+			inc.AddLabels(map[string]string{name: value}, nil)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating incident labels: %w", err)
+	}
+	return nil
+}
+
+// loadStatusTimes populates the status times of the given incidents from their
+// history: when each incident last entered each status.
+func (s *Store) loadStatusTimes(ctx context.Context, incidents []*incident.Incident) error {
+	if len(incidents) == 0 {
+		return nil
+	}
+	byID := make(map[string]*incident.Incident, len(incidents))
+	ids := make([]string, 0, len(incidents))
+	for _, inc := range incidents {
+		byID[inc.IncidentID] = inc
+		ids = append(ids, inc.IncidentID)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT incident_id, new_value, changed_at FROM incident_history
+		WHERE field = $1 AND new_value IS NOT NULL AND incident_id = ANY($2)
+		ORDER BY changed_at`,
+		storage.HistoryStatus, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to load incident status times: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var incidentID, status string
+		var at time.Time
+		if err := rows.Scan(&incidentID, &status, &at); err != nil {
+			return fmt.Errorf("failed to scan incident status time row: %w", err)
+		}
+		inc := byID[incidentID]
+		if inc.StatusTimes == nil {
+			inc.StatusTimes = make(map[string]time.Time)
+		}
+		// Rows are ordered by time, so a status entered again keeps its latest time
+		inc.StatusTimes[status] = at
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating incident status times: %w", err)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in sorted order, so generated queries are stable.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RecordRunStart records the startup of a nightcrier process.
+// The cluster list is stored as a JSON array.
+func (s *Store) RecordRunStart(ctx context.Context, run *storage.RunRecord) error {
+	clustersJSON, err := json.Marshal(run.Clusters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run clusters: %w", err)
+	}
+	var snapshot sql.NullString
+	if run.ConfigSnapshot != nil {
+		snapshotJSON, err := json.Marshal(run.ConfigSnapshot)
+		if err != nil {
+			return fmt.Errorf("failed to marshal run config snapshot: %w", err)
+		}
+		snapshot = sql.NullString{String: string(snapshotJSON), Valid: true}
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO runs (
+			run_id, started_at, version, git_commit, config_hash, config_snapshot,
+			clusters, artifact_storage, state_storage, hostname
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		run.RunID,
+		run.StartedAt,
+		run.Version,
+		run.GitCommit,
+		run.ConfigHash,
+		snapshot,
+		string(clustersJSON),
+		run.ArtifactStorage,
+		run.StateStorage,
+		run.Hostname,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record run start: %w", err)
+	}
+
+	return nil
+}
+
+// RecordRunStop records the shutdown of a nightcrier process.
+func (s *Store) RecordRunStop(ctx context.Context, runID string, stoppedAt time.Time, exitReason string) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE runs
+		SET stopped_at = $1, exit_reason = $2
+		WHERE run_id = $3
+	`, stoppedAt, exitReason, runID)
+	if err != nil {
+		return fmt.Errorf("failed to record run stop: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("run not found: %s", runID)
+	}
+
+	return nil
+}
+
+// ListRuns returns the most recent run records, newest first.
+// A limit of 0 returns all runs.
+func (s *Store) ListRuns(ctx context.Context, limit int) ([]*storage.RunRecord, error) {
+	query := `
+		SELECT
+			run_id, started_at, stopped_at, version, git_commit, config_hash, config_snapshot,
+			clusters, artifact_storage, state_storage, hostname, exit_reason
+		FROM runs
+		ORDER BY started_at DESC
+	`
+	args := []interface{}{}
+	if limit > 0 {
+		query += " LIMIT $1"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*storage.RunRecord
+	for rows.Next() {
+		var run storage.RunRecord
+		var stoppedAt sql.NullTime
+		var gitCommit, hostname, exitReason, snapshotJSON sql.NullString
+		var clustersJSON string
+
+		err := rows.Scan(
+			&run.RunID,
+			&run.StartedAt,
+			&stoppedAt,
+			&run.Version,
+			&gitCommit,
+			&run.ConfigHash,
+			&snapshotJSON,
+			&clustersJSON,
+			&run.ArtifactStorage,
+			&run.StateStorage,
+			&hostname,
+			&exitReason,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan run row: %w", err)
+		}
+
+		// Handle nullable fields
+		if stoppedAt.Valid {
+			run.StoppedAt = &stoppedAt.Time
+		}
+		run.GitCommit = gitCommit.String
+		run.Hostname = hostname.String
+		run.ExitReason = exitReason.String
+		if err := json.Unmarshal([]byte(clustersJSON), &run.Clusters); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal run clusters: %w", err)
+		}
+		if snapshotJSON.Valid && snapshotJSON.String != "" {
+			if err := json.Unmarshal([]byte(snapshotJSON.String), &run.ConfigSnapshot); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal run config snapshot: %w", err)
+			}
+		}
+
+		runs = append(runs, &run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating run rows: %w", err)
+	}
+
+	return runs, nil
+}
+
 // Close releases any resources held by the StateStore.
 func (s *Store) Close() error {
 	if s.db != nil {
@@ -169,8 +169,8 @@ func TestCreateIncident(t *testing.T) {
 		if retrieved.FaultID != faultID {
 			t.Errorf("expected fault ID %s, got %s", faultID, retrieved.FaultID)
 		}
-		if retrieved.Status != incident.StatusInvestigating {
-			t.Errorf("expected status %s, got %s", incident.StatusInvestigating, retrieved.Status)
+		if retrieved.Status != incident.StatusReceived {
+			t.Errorf("expected status %s, got %s", incident.StatusReceived, retrieved.Status)
 		}
 	})
 
@@ -196,6 +196,218 @@ func TestCreateIncident(t *testing.T) {
 	})
 }
 
+// TestIncidentLabels verifies storing, merging, filtering, and removing labels.
+func TestIncidentLabels(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t, ctx)
+	defer cleanupTestStore(t, store)
+
+	team := uuid.New().String()
+	event := createTestEvent(uuid.New().String())
+	inc := createTestIncident(uuid.New().String(), event)
+	inc.AddLabels(map[string]string{"team": team, "env": "prod"}, nil)
+	if err := store.CreateIncident(ctx, inc, event); err != nil {
+		t.Fatalf("failed to create incident: %v", err)
+	}
+
+	if err := store.SetIncidentLabels(ctx, inc.IncidentID,
+		map[string]string{"env": "staging"},
+		map[string]string{"note": "caused by deploy"}); err != nil {
+		t.Fatalf("failed to set labels: %v", err)
+	}
+	if err := store.SetIncidentLabels(ctx, uuid.New().String(), map[string]string{"team": team}, nil); err == nil {
+		t.Error("expected error when labeling an unknown incident")
+	}
+
+	listed, err := store.ListIncidents(ctx, &storage.IncidentFilters{Labels: map[string]string{"team": team, "env": "staging"}})
+	if err != nil {
+		t.Fatalf("failed to list incidents: %v", err)
+	}
+	if len(listed) != 1 || listed[0].Annotations["note"] != "caused by deploy" {
+		t.Fatalf("expected the labeled incident with its annotation, got %v", listed)
+	}
+
+	if err := store.RemoveIncidentLabels(ctx, inc.IncidentID, []string{"env"}); err != nil {
+		t.Fatalf("failed to remove labels: %v", err)
+	}
+	retrieved, err := store.GetIncident(ctx, inc.IncidentID)
+	if err != nil {
+		t.Fatalf("failed to retrieve incident: %v", err)
+	}
+	if _, ok := retrieved.Labels["env"]; ok || retrieved.Labels["team"] != team {
+		t.Errorf("expected only the team label after removal, got %v", retrieved.Labels)
+	}
+}
+
+// TestArtifactHashes verifies recording and replacing artifact hashes.
+func TestArtifactHashes(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t, ctx)
+	defer cleanupTestStore(t, store)
+
+	event := createTestEvent(uuid.New().String())
+	inc := createTestIncident(uuid.New().String(), event)
+	if err := store.CreateIncident(ctx, inc, event); err != nil {
+		t.Fatalf("failed to create incident: %v", err)
+	}
+
+	if err := store.RecordArtifactHashes(ctx, inc.IncidentID, map[string]string{
+		"investigation.md":    "aaaa",
+		"logs/agent-full.log": "bbbb",
+	}); err != nil {
+		t.Fatalf("failed to record artifact hashes: %v", err)
+	}
+	if err := store.RecordArtifactHashes(ctx, inc.IncidentID, map[string]string{"investigation.md": "cccc"}); err != nil {
+		t.Fatalf("failed to replace artifact hash: %v", err)
+	}
+
+	hashes, err := store.GetArtifactHashes(ctx, inc.IncidentID)
+	if err != nil {
+		t.Fatalf("failed to get artifact hashes: %v", err)
+	}
+	if len(hashes) != 2 || hashes["investigation.md"] != "cccc" || hashes["logs/agent-full.log"] != "bbbb" {
+		t.Errorf("unexpected artifact hashes: %v", hashes)
+	}
+}
+
+// TestLegalHolds verifies placing, listing, and releasing legal holds.
+func TestLegalHolds(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t, ctx)
+	defer cleanupTestStore(t, store)
+
+	event := createTestEvent(uuid.New().String())
+	inc := createTestIncident(uuid.New().String(), event)
+	if err := store.CreateIncident(ctx, inc, event); err != nil {
+		t.Fatalf("failed to create incident: %v", err)
+	}
+
+	hold := &storage.LegalHold{
+		HoldID:     uuid.New().String(),
+		IncidentID: inc.IncidentID,
+		Reason:     "matter 2024-17",
+		PlacedBy:   "legal",
+		PlacedAt:   time.Now().UTC(),
+	}
+	if err := store.PlaceLegalHold(ctx, hold); err != nil {
+		t.Fatalf("failed to place legal hold: %v", err)
+	}
+	hold.HoldID = uuid.New().String()
+	if err := store.PlaceLegalHold(ctx, hold); err == nil {
+		t.Error("expected error when holding an incident already under hold")
+	}
+
+	holds, err := store.ListLegalHolds(ctx, false)
+	if err != nil {
+		t.Fatalf("failed to list legal holds: %v", err)
+	}
+	found := false
+	for _, h := range holds {
+		found = found || h.IncidentID == inc.IncidentID
+	}
+	if !found {
+		t.Errorf("expected the active hold on %s in %+v", inc.IncidentID, holds)
+	}
+
+	if err := store.ReleaseLegalHold(ctx, inc.IncidentID, "legal", time.Now().UTC()); err != nil {
+		t.Fatalf("failed to release legal hold: %v", err)
+	}
+	if err := store.ReleaseLegalHold(ctx, inc.IncidentID, "legal", time.Now().UTC()); err == nil {
+		t.Error("expected error when releasing an incident that is not under hold")
+	}
+}
+
+// TestSilences verifies creating, listing, and lifting silences.
+func TestSilences(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t, ctx)
+	defer cleanupTestStore(t, store)
+
+	now := time.Now().UTC()
+	silence := &storage.Silence{
+		SilenceID: uuid.New().String(),
+		Cluster:   "prod",
+		Namespace: "payments",
+		Reason:    "migration",
+		CreatedBy: "slack:U1",
+		CreatedAt: now,
+		ExpiresAt: now.Add(2 * time.Hour),
+	}
+	if err := store.CreateSilence(ctx, silence); err != nil {
+		t.Fatalf("failed to create silence: %v", err)
+	}
+
+	silences, err := store.ListSilences(ctx, now)
+	if err != nil {
+		t.Fatalf("failed to list silences: %v", err)
+	}
+	found := false
+	for _, s := range silences {
+		found = found || s.SilenceID == silence.SilenceID
+	}
+	if !found {
+		t.Errorf("expected silence %s in %+v", silence.SilenceID, silences)
+	}
+	if silences, _ := store.ListSilences(ctx, now.Add(3*time.Hour)); len(silences) != 0 {
+		for _, s := range silences {
+			if s.SilenceID == silence.SilenceID {
+				t.Errorf("expected silence %s to have expired", silence.SilenceID)
+			}
+		}
+	}
+
+	if err := store.LiftSilence(ctx, silence.SilenceID, "slack:U2", now.Add(time.Minute)); err != nil {
+		t.Fatalf("failed to lift silence: %v", err)
+	}
+	if err := store.LiftSilence(ctx, silence.SilenceID, "slack:U2", now.Add(time.Minute)); err == nil {
+		t.Error("expected error when lifting a lifted silence")
+	}
+}
+
+// TestRunRecords verifies recording run startup and shutdown.
+func TestRunRecords(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t, ctx)
+	defer cleanupTestStore(t, store)
+
+	run := &storage.RunRecord{
+		RunID:           uuid.New().String(),
+		StartedAt:       time.Now().UTC(),
+		Version:         "1.0.0",
+		ConfigHash:      "hash-1",
+		Clusters:        []string{"prod-east"},
+		ArtifactStorage: "local_filesystem",
+		StateStorage:    "postgres",
+	}
+	if err := store.RecordRunStart(ctx, run); err != nil {
+		t.Fatalf("failed to record run start: %v", err)
+	}
+	if err := store.RecordRunStop(ctx, run.RunID, time.Now().UTC(), "shutdown"); err != nil {
+		t.Fatalf("failed to record run stop: %v", err)
+	}
+	if err := store.RecordRunStop(ctx, uuid.New().String(), time.Now(), "shutdown"); err == nil {
+		t.Error("expected error when stopping an unknown run")
+	}
+
+	runs, err := store.ListRuns(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to list runs: %v", err)
+	}
+	for _, r := range runs {
+		if r.RunID != run.RunID {
+			continue
+		}
+		if r.StoppedAt == nil || r.ExitReason != "shutdown" {
+			t.Errorf("expected stop time and reason, got %+v", r)
+		}
+		if len(r.Clusters) != 1 || r.Clusters[0] != "prod-east" {
+			t.Errorf("expected clusters [prod-east], got %v", r.Clusters)
+		}
+		return
+	}
+	t.Errorf("run %s not found in ListRuns", run.RunID)
+}
+
 // TestUpdateIncidentStatus verifies incident status updates.
 func TestUpdateIncidentStatus(t *testing.T) {
 	ctx := context.Background()
@@ -259,7 +471,7 @@ func TestCompleteIncident(t *testing.T) {
 		}
 
 		// Complete incident
-		err := store.CompleteIncident(ctx, incidentID, 0, "")
+		err := store.CompleteIncident(ctx, incidentID, "", 0, "", "")
 		if err != nil {
 			t.Fatalf("failed to complete incident: %v", err)
 		}
@@ -293,7 +505,7 @@ func TestCompleteIncident(t *testing.T) {
 
 		// Complete incident with failure
 		failureReason := "agent execution failed"
-		err := store.CompleteIncident(ctx, incidentID, 1, failureReason)
+		err := store.CompleteIncident(ctx, incidentID, "", 1, failureReason, "timeout")
 		if err != nil {
 			t.Fatalf("failed to complete incident: %v", err)
 		}
@@ -309,6 +521,9 @@ func TestCompleteIncident(t *testing.T) {
 		if retrieved.FailureReason != failureReason {
 			t.Errorf("expected failure reason %s, got %s", failureReason, retrieved.FailureReason)
 		}
+		if retrieved.FailureClass != "timeout" {
+			t.Errorf("expected failure class timeout, got %s", retrieved.FailureClass)
+		}
 	})
 }
 
@@ -428,9 +643,57 @@ func TestRecordTriageReport(t *testing.T) {
 		if err != nil {
 			t.Fatalf("failed to record report: %v", err)
 		}
+
+		got, err := store.GetTriageReport(ctx, incidentID)
+		if err != nil {
+			t.Fatalf("failed to get report: %v", err)
+		}
+		if got == nil || got.ReportID != reportID || got.ReportMarkdown != report.ReportMarkdown {
+			t.Errorf("GetTriageReport() = %+v, want report %s", got, reportID)
+		}
+	})
+
+	t.Run("no report", func(t *testing.T) {
+		got, err := store.GetTriageReport(ctx, uuid.New().String())
+		if err != nil || got != nil {
+			t.Errorf("GetTriageReport() = %+v, %v, want nil, nil", got, err)
+		}
 	})
 }
 
+// TestIncidentDisplayIDs verifies display ID sequence allocation and lookup.
+func TestIncidentDisplayIDs(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t, ctx)
+	defer cleanupTestStore(t, store)
+
+	cluster := "cluster-" + uuid.New().String()[:8]
+	for want := 1; want <= 2; want++ {
+		seq, err := store.NextIncidentSequence(ctx, cluster, "2024-06-13")
+		if err != nil {
+			t.Fatalf("NextIncidentSequence() error = %v", err)
+		}
+		if seq != want {
+			t.Errorf("NextIncidentSequence() = %d, want %d", seq, want)
+		}
+	}
+
+	incidentID := uuid.New().String()
+	event := createTestEvent(uuid.New().String())
+	inc := createTestIncident(incidentID, event)
+	inc.DisplayID = "NC-2024-0613-" + cluster + "-0001"
+	if err := store.CreateIncident(ctx, inc, event); err != nil {
+		t.Fatalf("failed to create incident: %v", err)
+	}
+	got, err := store.GetIncident(ctx, inc.DisplayID)
+	if err != nil {
+		t.Fatalf("GetIncident(display ID) error = %v", err)
+	}
+	if got.IncidentID != incidentID || got.DisplayID != inc.DisplayID {
+		t.Errorf("GetIncident(display ID) = %+v, want %s", got, incidentID)
+	}
+}
+
 // TestListIncidents verifies incident listing with filters.
 func TestListIncidents(t *testing.T) {
 	ctx := context.Background()
@@ -753,3 +1016,114 @@ func BenchmarkGetIncident(b *testing.B) {
 		}
 	}
 }
+
+func TestSplitPassword(t *testing.T) {
+	dbURL, password, err := splitPassword("postgres://nightcrier:s3cr%40t@db:5432/nightcrier?sslmode=require")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dbURL != "postgres://nightcrier@db:5432/nightcrier?sslmode=require" || password != "s3cr@t" {
+		t.Errorf("splitPassword() = %q, %q, want the URL without its password", dbURL, password)
+	}
+	if _, _, err := splitPassword("host=db user=nightcrier"); err == nil {
+		t.Error("splitPassword() should reject a key-value connection string")
+	}
+}
+
+// TestIncidentHistory verifies recording state changes and replaying them.
+func TestIncidentHistory(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t, ctx)
+	defer cleanupTestStore(t, store)
+
+	event := createTestEvent(uuid.New().String())
+	inc := createTestIncident(uuid.New().String(), event)
+	inc.CreatedAt = time.Now().Add(-time.Minute)
+	inc.AddLabels(map[string]string{"team": "platform"}, nil)
+	if err := store.CreateIncident(ctx, inc, event); err != nil {
+		t.Fatalf("failed to create incident: %v", err)
+	}
+	created := time.Now()
+
+	if err := store.SetIncidentLabels(ctx, inc.IncidentID, map[string]string{"team": "payments"}, nil); err != nil {
+		t.Fatalf("failed to set labels: %v", err)
+	}
+	reassigned := time.Now()
+	if err := store.CompleteIncident(ctx, inc.IncidentID, "", 0, "", ""); err != nil {
+		t.Fatalf("failed to complete incident: %v", err)
+	}
+
+	changes, err := store.IncidentHistory(ctx, inc.IncidentID)
+	if err != nil {
+		t.Fatalf("failed to read incident history: %v", err)
+	}
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes (status and team at creation, reassignment, completion), got %d", len(changes))
+	}
+
+	if state := storage.IncidentStateAt(changes, created); state == nil || state.Assignment != "platform" {
+		t.Errorf("expected the incident assigned to platform after creation, got %+v", state)
+	}
+	state := storage.IncidentStateAt(changes, reassigned)
+	if state == nil || state.Status != incident.StatusReceived || state.Assignment != "payments" {
+		t.Errorf("expected the received incident assigned to payments, got %+v", state)
+	}
+	if state := storage.IncidentStateAt(changes, time.Now()); state == nil || state.Status != incident.StatusResolved {
+		t.Errorf("expected the resolved incident, got %+v", state)
+	}
+}
+
+func TestInvestigationReviews(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStore(t, ctx)
+	defer cleanupTestStore(t, store)
+
+	event := createTestEvent(uuid.New().String())
+	inc := createTestIncident(uuid.New().String(), event)
+	if err := store.CreateIncident(ctx, inc, event); err != nil {
+		t.Fatalf("failed to create incident: %v", err)
+	}
+	exec := &storage.AgentExecution{
+		ExecutionID: inc.IncidentID, IncidentID: inc.IncidentID, StartedAt: time.Now(),
+		AgentModel: "sonnet", PromptVersion: "sha256:abc",
+	}
+	if err := store.RecordAgentExecution(ctx, exec); err != nil {
+		t.Fatalf("failed to record agent execution: %v", err)
+	}
+
+	now := time.Now()
+	for i, review := range []*storage.InvestigationReview{
+		{Reviewer: "alice", CreatedAt: now.Add(-time.Minute), Verdict: storage.VerdictInaccurate, Comment: "Wrong container"},
+		{Reviewer: "alice", CreatedAt: now, Verdict: storage.VerdictAccurate},
+	} {
+		review.ReviewID, review.IncidentID = uuid.New().String(), inc.IncidentID
+		if err := store.AddInvestigationReview(ctx, review); err != nil {
+			t.Fatalf("AddInvestigationReview(%d) error = %v", i, err)
+		}
+	}
+
+	reviews, err := store.InvestigationReviews(ctx, inc.IncidentID)
+	if err != nil {
+		t.Fatalf("InvestigationReviews() error = %v", err)
+	}
+	if len(reviews) != 2 || reviews[0].Comment != "Wrong container" {
+		t.Errorf("InvestigationReviews() = %+v, want 2 reviews oldest first", reviews)
+	}
+
+	stats, err := store.ReviewAccuracy(ctx, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ReviewAccuracy() error = %v", err)
+	}
+	var found bool
+	for _, st := range stats {
+		if st.AgentModel == "sonnet" && st.PromptVersion == "sha256:abc" {
+			found = true
+			if st.Accurate < 1 || st.Inaccurate != 0 {
+				t.Errorf("ReviewAccuracy() = %+v, want only the latest (accurate) verdict counted", st)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("ReviewAccuracy() = %+v, missing sonnet sha256:abc", stats)
+	}
+}
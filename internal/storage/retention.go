@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+)
+
+// Artifact classes of tiered retention. Reports are small and worth keeping;
+// agent logs and session archives are bulky and mostly useful while an incident
+// is fresh, so they can expire earlier.
+const (
+	// ClassReport covers the report, findings, incident.json, and everything else
+	// not in the other classes
+	ClassReport = "report"
+	// ClassLogs covers the agent logs under logs/ and the prompt sent to the agent
+	ClassLogs = "logs"
+	// ClassSessionArchive covers the agent CLI's session archive
+	ClassSessionArchive = "session_archive"
+)
+
+// ArtifactClasses lists the artifact classes, in the order they are reported.
+var ArtifactClasses = []string{ClassReport, ClassLogs, ClassSessionArchive}
+
+// artifactClassTag is the blob index tag recording an Azure blob's artifact class,
+// matched by lifecycle management rules (see AzureLifecyclePolicy)
+const artifactClassTag = "artifact_class"
+
+// ArtifactClass returns the retention class of an artifact, by its path relative
+// to the incident's directory (e.g. "logs/agent-full.log").
+func ArtifactClass(name string) string {
+	name = filepath.ToSlash(name)
+	switch {
+	case path.Base(name) == "claude-session.tar.gz":
+		return ClassSessionArchive
+	case strings.HasPrefix(name, "logs/"), path.Base(name) == "prompt-sent.md":
+		return ClassLogs
+	}
+	return ClassReport
+}
+
+// RetentionPolicy maps artifact classes to how long their artifacts are kept.
+// A class without an entry, or with zero, is kept forever.
+type RetentionPolicy map[string]time.Duration
+
+// Expired reports whether an artifact written at modTime has outlived its class's
+// retention at now.
+func (p RetentionPolicy) Expired(name string, modTime, now time.Time) bool {
+	keep := p[ArtifactClass(name)]
+	return keep > 0 && now.Sub(modTime) > keep
+}
+
+// PruneResult counts the artifacts deleted by a retention pass.
+type PruneResult struct {
+	// Deleted counts the deleted artifacts per class
+	Deleted map[string]int
+	// Bytes is the total size of the deleted artifacts
+	Bytes int64
+}
+
+// Total returns the number of deleted artifacts.
+func (r PruneResult) Total() int {
+	total := 0
+	for _, n := range r.Deleted {
+		total += n
+	}
+	return total
+}
+
+func (r *PruneResult) add(name string, size int64) {
+	if r.Deleted == nil {
+		r.Deleted = make(map[string]int)
+	}
+	r.Deleted[ArtifactClass(name)]++
+	r.Bytes += size
+}
+
+// Pruner is implemented by storage backends that can expire stored artifacts by
+// class.
+type Pruner interface {
+	// PruneArtifacts deletes the artifacts that outlived their class's retention
+	// at now. Incidents for which held returns true (legal holds) are skipped. With
+	// dryRun, the artifacts are counted but not deleted.
+	PruneArtifacts(ctx context.Context, policy RetentionPolicy, now time.Time, held func(incidentID string) bool, dryRun bool) (PruneResult, error)
+}
+
+// PruneArtifacts expires artifacts in the incident directories under the
+// workspace root: the stored artifacts and the incident's workspace files alike.
+// Only directories holding an incident.json are incident directories; the
+// quarantine, spill, and budget directories next to them are left alone. A file's
+// age is its modification time.
+func (fs *FilesystemStorage) PruneArtifacts(ctx context.Context, policy RetentionPolicy, now time.Time, held func(incidentID string) bool, dryRun bool) (PruneResult, error) {
+	var result PruneResult
+	entries, err := os.ReadDir(fs.workspaceRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return result, fmt.Errorf("failed to read workspace root: %w", err)
+	}
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		incidentID := entry.Name()
+		incidentDir := filepath.Join(fs.workspaceRoot, incidentID)
+		if !entry.IsDir() || (held != nil && held(incidentID)) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(incidentDir, "incident.json")); err != nil {
+			continue
+		}
+		if err := pruneIncidentDir(incidentDir, policy, now, dryRun, &result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// pruneIncidentDir deletes the expired files of one incident directory.
+func pruneIncidentDir(incidentDir string, policy RetentionPolicy, now time.Time, dryRun bool, result *PruneResult) error {
+	return filepath.WalkDir(incidentDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to walk %s: %w", incidentDir, err)
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(incidentDir, p)
+		if err != nil {
+			return err
+		}
+		// The workspace keeps the agent's report under output/
+		name := strings.TrimPrefix(filepath.ToSlash(rel), "output/")
+		if name == "incident.json" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", p, err)
+		}
+		if !policy.Expired(name, info.ModTime(), now) {
+			return nil
+		}
+		if !dryRun {
+			if err := os.Remove(p); err != nil {
+				return fmt.Errorf("failed to remove expired artifact %s: %w", p, err)
+			}
+		}
+		result.add(name, info.Size())
+		return nil
+	})
+}
+
+// PruneArtifacts deletes the blobs that outlived their class's retention. A
+// blob's age is its last modification time. Blobs are named
+// "<incident-id>/<artifact>"; incident.json is never deleted.
+func (a *AzureStorage) PruneArtifacts(ctx context.Context, policy RetentionPolicy, now time.Time, held func(incidentID string) bool, dryRun bool) (PruneResult, error) {
+	var result PruneResult
+	pager := a.client.NewListBlobsFlatPager(a.container, &container.ListBlobsFlatOptions{})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return result, fmt.Errorf("failed to list blobs: %w", err)
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil || item.Properties == nil || item.Properties.LastModified == nil {
+				continue
+			}
+			incidentID, name, ok := strings.Cut(*item.Name, "/")
+			if !ok || name == "incident.json" || (held != nil && held(incidentID)) {
+				continue
+			}
+			if !policy.Expired(name, *item.Properties.LastModified, now) {
+				continue
+			}
+			if !dryRun {
+				if _, err := a.client.DeleteBlob(ctx, a.container, *item.Name, nil); err != nil {
+					return result, fmt.Errorf("failed to delete expired blob %s: %w", *item.Name, err)
+				}
+			}
+			var size int64
+			if item.Properties.ContentLength != nil {
+				size = *item.Properties.ContentLength
+			}
+			result.add(name, size)
+		}
+	}
+	return result, nil
+}
+
+// AzureLifecyclePolicy returns an Azure Storage lifecycle management policy that
+// deletes the container's blobs by artifact class, matching the blob index tag
+// set on upload when artifact class tagging is enabled. Apply it with:
+//
+//	az storage account management-policy create --account-name <account> \
+//	  --resource-group <group> --policy @policy.json
+//
+// Azure then expires artifacts even while nightcrier is not running. Classes kept
+// forever get no rule.
+func AzureLifecyclePolicy(containerName string, policy RetentionPolicy) ([]byte, error) {
+	type tagMatch struct {
+		Name  string `json:"name"`
+		Op    string `json:"op"`
+		Value string `json:"value"`
+	}
+	type rule struct {
+		Enabled    bool   `json:"enabled"`
+		Name       string `json:"name"`
+		Type       string `json:"type"`
+		Definition struct {
+			Actions struct {
+				BaseBlob struct {
+					Delete struct {
+						DaysAfterModificationGreaterThan int `json:"daysAfterModificationGreaterThan"`
+					} `json:"delete"`
+				} `json:"baseBlob"`
+			} `json:"actions"`
+			Filters struct {
+				BlobTypes      []string   `json:"blobTypes"`
+				PrefixMatch    []string   `json:"prefixMatch"`
+				BlobIndexMatch []tagMatch `json:"blobIndexMatch"`
+			} `json:"filters"`
+		} `json:"definition"`
+	}
+
+	rules := []rule{}
+	for _, class := range ArtifactClasses {
+		keep := policy[class]
+		if keep <= 0 {
+			continue
+		}
+		days := int(keep.Hours() / 24)
+		if days < 1 {
+			return nil, fmt.Errorf("retention of %s is %s; Azure lifecycle rules count whole days", class, keep)
+		}
+		var r rule
+		r.Enabled = true
+		r.Name = "nightcrier-" + strings.ReplaceAll(class, "_", "-")
+		r.Type = "Lifecycle"
+		r.Definition.Actions.BaseBlob.Delete.DaysAfterModificationGreaterThan = days
+		r.Definition.Filters.BlobTypes = []string{"blockBlob"}
+		r.Definition.Filters.PrefixMatch = []string{containerName + "/"}
+		r.Definition.Filters.BlobIndexMatch = []tagMatch{{Name: artifactClassTag, Op: "==", Value: class}}
+		rules = append(rules, r)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	return json.MarshalIndent(map[string]interface{}{"rules": rules}, "", "  ")
+}
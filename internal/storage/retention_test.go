@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArtifactClass(t *testing.T) {
+	tests := map[string]string{
+		"investigation.md":           ClassReport,
+		"findings.json":              ClassReport,
+		"incident.json":              ClassReport,
+		"index.html":                 ClassReport,
+		"prompt-sent.md":             ClassLogs,
+		"logs/agent-full.log":        ClassLogs,
+		"logs/agent-stderr.log":      ClassLogs,
+		"logs/claude-session.tar.gz": ClassSessionArchive,
+	}
+	for name, want := range tests {
+		if got := ArtifactClass(name); got != want {
+			t.Errorf("ArtifactClass(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFilesystemStorage_PruneArtifacts(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	old := now.Add(-10 * 24 * time.Hour)
+
+	write := func(rel string, modTime time.Time) string {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	report := write("inc-1/output/investigation.md", old)
+	findings := write("inc-1/output/findings.json", old)
+	incidentJSON := write("inc-1/incident.json", old)
+	oldLog := write("inc-1/logs/agent-full.log", old)
+	session := write("inc-1/logs/claude-session.tar.gz", old)
+	freshLog := write("inc-2/logs/agent-full.log", now)
+	write("inc-2/incident.json", now)
+	heldLog := write("inc-3/logs/agent-full.log", old)
+	write("inc-3/incident.json", old)
+	// Not an incident directory: no incident.json
+	spilled := write("queue-spill/logs/event.log", old)
+
+	policy := RetentionPolicy{
+		ClassLogs:           7 * 24 * time.Hour,
+		ClassSessionArchive: 24 * time.Hour,
+	}
+	held := func(id string) bool { return id == "inc-3" }
+	fs := NewFilesystemStorage(root)
+
+	result, err := fs.PruneArtifacts(context.Background(), policy, now, held, true)
+	if err != nil {
+		t.Fatalf("PruneArtifacts(dry run) failed: %v", err)
+	}
+	if result.Total() != 2 {
+		t.Errorf("dry run counted %d artifacts, want 2", result.Total())
+	}
+	if _, err := os.Stat(oldLog); err != nil {
+		t.Errorf("dry run deleted %s", oldLog)
+	}
+
+	result, err = fs.PruneArtifacts(context.Background(), policy, now, held, false)
+	if err != nil {
+		t.Fatalf("PruneArtifacts() failed: %v", err)
+	}
+	if result.Deleted[ClassLogs] != 1 || result.Deleted[ClassSessionArchive] != 1 || result.Bytes != 8 {
+		t.Errorf("PruneArtifacts() = %+v, want 1 log and 1 session archive (8 bytes)", result)
+	}
+	for _, path := range []string{oldLog, session} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s was not deleted", path)
+		}
+	}
+	for _, path := range []string{report, findings, incidentJSON, freshLog, heldLog, spilled} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s was deleted", path)
+		}
+	}
+}
+
+func TestAzureLifecyclePolicy(t *testing.T) {
+	data, err := AzureLifecyclePolicy("incidents", RetentionPolicy{
+		ClassLogs:           30 * 24 * time.Hour,
+		ClassSessionArchive: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("AzureLifecyclePolicy() failed: %v", err)
+	}
+
+	var policy struct {
+		Rules []struct {
+			Name       string `json:"name"`
+			Definition struct {
+				Actions struct {
+					BaseBlob struct {
+						Delete struct {
+							Days int `json:"daysAfterModificationGreaterThan"`
+						} `json:"delete"`
+					} `json:"baseBlob"`
+				} `json:"actions"`
+				Filters struct {
+					PrefixMatch    []string `json:"prefixMatch"`
+					BlobIndexMatch []struct {
+						Name  string `json:"name"`
+						Value string `json:"value"`
+					} `json:"blobIndexMatch"`
+				} `json:"filters"`
+			} `json:"definition"`
+		} `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		t.Fatalf("policy is not valid JSON: %v", err)
+	}
+	// The report class is kept forever and gets no rule
+	if len(policy.Rules) != 2 {
+		t.Fatalf("got %d rules, want 2: %s", len(policy.Rules), data)
+	}
+	logs := policy.Rules[0]
+	if logs.Name != "nightcrier-logs" || logs.Definition.Actions.BaseBlob.Delete.Days != 30 {
+		t.Errorf("rule = %s after %d days, want nightcrier-logs after 30", logs.Name, logs.Definition.Actions.BaseBlob.Delete.Days)
+	}
+	if logs.Definition.Filters.PrefixMatch[0] != "incidents/" || logs.Definition.Filters.BlobIndexMatch[0].Value != ClassLogs {
+		t.Errorf("filters = %+v, want the container prefix and the logs class tag", logs.Definition.Filters)
+	}
+
+	if _, err := AzureLifecyclePolicy("incidents", RetentionPolicy{ClassLogs: time.Hour}); err == nil || !strings.Contains(err.Error(), "whole days") {
+		t.Errorf("AzureLifecyclePolicy() with sub-day retention = %v, want error", err)
+	}
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Verdicts of an investigation review on the accuracy of the report
+const (
+	VerdictAccurate          = "accurate"
+	VerdictPartiallyAccurate = "partially_accurate"
+	VerdictInaccurate        = "inaccurate"
+)
+
+// Verdicts lists the review verdicts, from best to worst.
+var Verdicts = []string{VerdictAccurate, VerdictPartiallyAccurate, VerdictInaccurate}
+
+// verdictScores are the accuracy scores of the verdicts
+var verdictScores = map[string]float64{
+	VerdictAccurate:          1,
+	VerdictPartiallyAccurate: 0.5,
+	VerdictInaccurate:        0,
+}
+
+// ParseVerdict normalizes a verdict given by a reviewer: case, dashes, and spaces
+// are ignored, so "Partially accurate" and "partially-accurate" are accepted.
+func ParseVerdict(s string) (string, error) {
+	verdict := strings.NewReplacer("-", "_", " ", "_").Replace(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(Verdicts, verdict) {
+		return "", fmt.Errorf("unknown verdict %q: must be one of %s", s, strings.Join(Verdicts, ", "))
+	}
+	return verdict, nil
+}
+
+// InvestigationReview is a human review of a completed investigation: a comment,
+// a verdict on the report's accuracy, or both.
+type InvestigationReview struct {
+	ReviewID   string `json:"review_id"`
+	IncidentID string `json:"incident_id"`
+	// Reviewer is who reviewed the investigation
+	Reviewer  string    `json:"reviewer"`
+	CreatedAt time.Time `json:"created_at"`
+	// Verdict is one of Verdicts, or empty for a comment only
+	Verdict string `json:"verdict,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// ReviewAccuracy aggregates the review verdicts on the investigations of one agent
+// model and prompt version, so a model or prompt change that degrades the reports
+// stands out against the others. Only the latest verdict on each incident counts.
+type ReviewAccuracy struct {
+	// AgentModel and PromptVersion identify the agent that investigated; both are
+	// empty for rule-based triage and for incidents without a recorded execution
+	AgentModel    string `json:"agent_model"`
+	PromptVersion string `json:"prompt_version"`
+	// Reviewed is the number of investigations with a verdict
+	Reviewed          int `json:"reviewed"`
+	Accurate          int `json:"accurate"`
+	PartiallyAccurate int `json:"partially_accurate"`
+	Inaccurate        int `json:"inaccurate"`
+	// Accuracy is the average score of the verdicts: 1 for accurate, 0.5 for
+	// partially accurate, and 0 for inaccurate
+	Accuracy float64 `json:"accuracy"`
+}
+
+// VerdictCount is the number of investigations of an agent model and prompt
+// version whose latest verdict is Verdict, as counted by the state stores.
+type VerdictCount struct {
+	AgentModel    string
+	PromptVersion string
+	Verdict       string
+	Count         int
+}
+
+// AggregateAccuracy folds verdict counts into the accuracy of each agent model and
+// prompt version, ordered by model and prompt version.
+func AggregateAccuracy(counts []VerdictCount) []*ReviewAccuracy {
+	type key struct{ model, prompt string }
+	byAgent := make(map[key]*ReviewAccuracy)
+	var stats []*ReviewAccuracy
+	for _, c := range counts {
+		score, ok := verdictScores[c.Verdict]
+		if !ok {
+			continue
+		}
+		k := key{c.AgentModel, c.PromptVersion}
+		st := byAgent[k]
+		if st == nil {
+			st = &ReviewAccuracy{AgentModel: c.AgentModel, PromptVersion: c.PromptVersion}
+			byAgent[k] = st
+			stats = append(stats, st)
+		}
+		switch c.Verdict {
+		case VerdictAccurate:
+			st.Accurate += c.Count
+		case VerdictPartiallyAccurate:
+			st.PartiallyAccurate += c.Count
+		case VerdictInaccurate:
+			st.Inaccurate += c.Count
+		}
+		// Accumulate the score sum; it is divided once all counts are in
+		st.Accuracy += score * float64(c.Count)
+		st.Reviewed += c.Count
+	}
+	for _, st := range stats {
+		st.Accuracy /= float64(st.Reviewed)
+	}
+	slices.SortFunc(stats, func(a, b *ReviewAccuracy) int {
+		if c := strings.Compare(a.AgentModel, b.AgentModel); c != 0 {
+			return c
+		}
+		return strings.Compare(a.PromptVersion, b.PromptVersion)
+	})
+	return stats
+}
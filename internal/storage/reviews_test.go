@@ -0,0 +1,38 @@
+package storage
+
+import "testing"
+
+func TestParseVerdict(t *testing.T) {
+	for input, want := range map[string]string{
+		"accurate":           VerdictAccurate,
+		"Partially accurate": VerdictPartiallyAccurate,
+		"partially-accurate": VerdictPartiallyAccurate,
+		" INACCURATE ":       VerdictInaccurate,
+	} {
+		if got, err := ParseVerdict(input); err != nil || got != want {
+			t.Errorf("ParseVerdict(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseVerdict("meh"); err == nil {
+		t.Error("ParseVerdict(meh) succeeded, want error")
+	}
+}
+
+func TestAggregateAccuracy(t *testing.T) {
+	stats := AggregateAccuracy([]VerdictCount{
+		{AgentModel: "sonnet", PromptVersion: "sha256:bbb", Verdict: VerdictAccurate, Count: 3},
+		{AgentModel: "sonnet", PromptVersion: "sha256:bbb", Verdict: VerdictInaccurate, Count: 1},
+		{AgentModel: "opus", PromptVersion: "sha256:aaa", Verdict: VerdictPartiallyAccurate, Count: 2},
+		{AgentModel: "opus", PromptVersion: "sha256:aaa", Verdict: "bogus", Count: 5},
+	})
+	if len(stats) != 2 {
+		t.Fatalf("AggregateAccuracy() = %d stats, want 2", len(stats))
+	}
+	opus, sonnet := stats[0], stats[1]
+	if opus.AgentModel != "opus" || opus.Reviewed != 2 || opus.PartiallyAccurate != 2 || opus.Accuracy != 0.5 {
+		t.Errorf("opus = %+v, want 2 partially accurate reviews scoring 0.5", opus)
+	}
+	if sonnet.Reviewed != 4 || sonnet.Accurate != 3 || sonnet.Inaccurate != 1 || sonnet.Accuracy != 0.75 {
+		t.Errorf("sonnet = %+v, want 3 accurate and 1 inaccurate scoring 0.75", sonnet)
+	}
+}
@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Backup writes a consistent copy of the database at dbPath to dest while the
+// database stays in use. It uses VACUUM INTO, which copies the database as of one
+// read transaction (including changes still in the WAL) into a new, compacted
+// file. dest must not exist.
+func Backup(ctx context.Context, dbPath, dest string) error {
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("backup file %s already exists", dest)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)", dbPath))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.ExecContext(ctx, `VACUUM INTO ?`, dest); err != nil {
+		os.Remove(dest)
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	if err := os.Chmod(dest, 0600); err != nil {
+		return fmt.Errorf("failed to restrict backup permissions: %w", err)
+	}
+	return nil
+}
+
+// Verify checks that the file at path is an intact SQLite database.
+func Verify(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRowContext(ctx, `PRAGMA integrity_check`).Scan(&result); err != nil {
+		return fmt.Errorf("failed to check %s: %w", path, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("%s failed the integrity check: %s", path, result)
+	}
+	return nil
+}
+
+// Restore replaces the database at dbPath with the backup at src. The backup is
+// verified first, and the current database, when there is one, is backed up next
+// to it (returned as previous) so a restore can be undone. Nightcrier must not be
+// running against the database.
+func Restore(ctx context.Context, src, dbPath string) (previous string, err error) {
+	if err := Verify(ctx, src); err != nil {
+		return "", fmt.Errorf("refusing to restore: %w", err)
+	}
+
+	if _, err := os.Stat(dbPath); err == nil {
+		previous = fmt.Sprintf("%s.pre-restore-%s", dbPath, time.Now().UTC().Format("20060102T150405Z"))
+		if err := Backup(ctx, dbPath, previous); err != nil {
+			return "", fmt.Errorf("failed to save the current database before restoring: %w", err)
+		}
+	}
+
+	// Copy next to the database and rename over it, so the database is never half
+	// written
+	tmp := dbPath + ".restoring"
+	if err := copyFile(src, tmp); err != nil {
+		os.Remove(tmp)
+		return previous, fmt.Errorf("failed to copy backup: %w", err)
+	}
+	if err := os.Rename(tmp, dbPath); err != nil {
+		os.Remove(tmp)
+		return previous, fmt.Errorf("failed to replace database: %w", err)
+	}
+	// The WAL and shared-memory files belong to the replaced database
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !os.IsNotExist(err) {
+			return previous, fmt.Errorf("failed to remove %s: %w", dbPath+suffix, err)
+		}
+	}
+	return previous, nil
+}
+
+// copyFile copies src to dst with owner-only permissions.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
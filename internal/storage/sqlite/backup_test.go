@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "nightcrier.db")
+
+	exec := func(query string) {
+		t.Helper()
+		db, err := sql.Open("sqlite", dbPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if _, err := db.Exec(query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	count := func(path string) int {
+		t.Helper()
+		db, err := sql.Open("sqlite", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM incidents`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	exec(`CREATE TABLE incidents (id TEXT PRIMARY KEY); INSERT INTO incidents VALUES ('a'), ('b')`)
+
+	backup := filepath.Join(dir, "backups", "nightcrier.db")
+	if err := Backup(ctx, dbPath, backup); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if err := Verify(ctx, backup); err != nil {
+		t.Errorf("Verify() of the backup = %v", err)
+	}
+	if err := Backup(ctx, dbPath, backup); err == nil {
+		t.Error("Backup() over an existing file should fail")
+	}
+
+	// History lost after the backup, e.g. by a bad migration
+	exec(`DELETE FROM incidents`)
+
+	previous, err := Restore(ctx, backup, dbPath)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := count(dbPath); got != 2 {
+		t.Errorf("incidents after restore = %d, want 2", got)
+	}
+	if previous == "" || count(previous) != 0 {
+		t.Errorf("Restore() previous = %q, want a copy of the replaced database", previous)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.db")
+	if err := os.WriteFile(corrupt, []byte("not a database"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Restore(ctx, corrupt, dbPath); err == nil {
+		t.Error("Restore() of a corrupt backup should fail")
+	}
+	if got := count(dbPath); got != 2 {
+		t.Errorf("incidents after a refused restore = %d, want 2", got)
+	}
+}
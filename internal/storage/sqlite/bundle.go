@@ -0,0 +1,253 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// incidentColumns are the columns of an incident, read with incidentRow from the
+// incidents table aliased as i
+const incidentColumns = `
+	i.incident_id, i.fault_id, i.triggering_event_id,
+	i.status, i.created_at, i.started_at, i.completed_at,
+	i.exit_code, i.failure_reason, i.failure_class,
+	i.cluster, i.namespace, i.fault_type, i.severity, i.context, i.timestamp,
+	i.resource_api_version, i.resource_kind, i.resource_name, i.resource_namespace, i.resource_uid,
+	i.parent_incident_id, i.display_id, i.source`
+
+// incidentRow scans the incidentColumns of a row.
+type incidentRow struct {
+	inc                    incident.Incident
+	startedAt, completedAt sql.NullTime
+	exitCode               sql.NullInt64
+	failureReason          sql.NullString
+	resourceAPIVersion     sql.NullString
+	resourceKind           sql.NullString
+	resourceName           sql.NullString
+	resourceNamespace      sql.NullString
+	resourceUID            sql.NullString
+	failureClass           sql.NullString
+	parentIncidentID       sql.NullString
+	displayID              sql.NullString
+	source                 sql.NullString
+}
+
+// dest returns the scan destinations of incidentColumns.
+func (r *incidentRow) dest() []interface{} {
+	return []interface{}{
+		&r.inc.IncidentID,
+		&r.inc.FaultID,
+		&r.inc.TriggeringEventID,
+		&r.inc.Status,
+		&r.inc.CreatedAt,
+		&r.startedAt,
+		&r.completedAt,
+		&r.exitCode,
+		&r.failureReason,
+		&r.failureClass,
+		&r.inc.Cluster,
+		&r.inc.Namespace,
+		&r.inc.FaultType,
+		&r.inc.Severity,
+		&r.inc.Context,
+		&r.inc.Timestamp,
+		&r.resourceAPIVersion,
+		&r.resourceKind,
+		&r.resourceName,
+		&r.resourceNamespace,
+		&r.resourceUID,
+		&r.parentIncidentID,
+		&r.displayID,
+		&r.source,
+	}
+}
+
+// incident returns the scanned incident, without its labels.
+func (r *incidentRow) incident() *incident.Incident {
+	inc := r.inc
+
+	// Handle nullable fields
+	if r.startedAt.Valid {
+		inc.StartedAt = &r.startedAt.Time
+	}
+	if r.completedAt.Valid {
+		inc.CompletedAt = &r.completedAt.Time
+	}
+	if r.exitCode.Valid {
+		exitCodeInt := int(r.exitCode.Int64)
+		inc.ExitCode = &exitCodeInt
+	}
+	inc.FailureReason = r.failureReason.String
+	inc.FailureClass = r.failureClass.String
+
+	// Reconstruct resource info if any fields are present
+	if r.resourceKind.Valid || r.resourceName.Valid {
+		inc.Resource = &incident.ResourceInfo{
+			APIVersion: r.resourceAPIVersion.String,
+			Kind:       r.resourceKind.String,
+			Name:       r.resourceName.String,
+			Namespace:  r.resourceNamespace.String,
+			UID:        r.resourceUID.String,
+		}
+	}
+
+	inc.ParentIncidentID = r.parentIncidentID.String
+	inc.DisplayID = r.displayID.String
+	inc.Source = r.source.String
+	return &inc
+}
+
+// GetIncidentBundle returns an incident with its agent executions and triage
+// reports, oldest first, read with a single query joining the three tables. The
+// join yields one row per execution and report pair, which stays small since an
+// incident has a handful of executions at most. Returns nil if the incident is
+// not found.
+func (s *Store) GetIncidentBundle(ctx context.Context, incidentID string) (*storage.IncidentBundle, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+incidentColumns+`,
+			e.execution_id, e.started_at, e.completed_at, e.exit_code, e.error_message,
+			e.log_paths, e.agent_cli, e.agent_image, e.agent_model, e.prompt_version,
+			e.agent_cli_version, e.agent_image_digest,
+			e.cpu_seconds, e.peak_rss_bytes, e.peak_processes, e.disk_written_bytes, e.resource_source,
+			r.report_id, r.execution_id, r.generated_at, r.report_markdown, r.report_html
+		FROM incidents i
+		LEFT JOIN agent_executions e ON e.incident_id = i.incident_id
+		LEFT JOIN triage_reports r ON r.incident_id = i.incident_id
+		WHERE i.incident_id = ? OR i.display_id = ?
+		ORDER BY e.started_at, r.generated_at
+	`, incidentID, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident bundle: %w", err)
+	}
+	defer rows.Close()
+
+	var bundle *storage.IncidentBundle
+	executions := make(map[string]bool)
+	reports := make(map[string]bool)
+	for rows.Next() {
+		var inc incidentRow
+		var exec executionRow
+		var report reportRow
+		dest := append(inc.dest(), exec.dest()...)
+		if err := rows.Scan(append(dest, report.dest()...)...); err != nil {
+			return nil, fmt.Errorf("failed to scan incident bundle row: %w", err)
+		}
+		if bundle == nil {
+			bundle = &storage.IncidentBundle{Incident: inc.incident()}
+		}
+		if exec.executionID.Valid && !executions[exec.executionID.String] {
+			executions[exec.executionID.String] = true
+			execution, err := exec.execution(bundle.Incident.IncidentID)
+			if err != nil {
+				return nil, err
+			}
+			bundle.Executions = append(bundle.Executions, execution)
+		}
+		if report.reportID.Valid && !reports[report.reportID.String] {
+			reports[report.reportID.String] = true
+			bundle.Reports = append(bundle.Reports, report.report(bundle.Incident.IncidentID))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incident bundle: %w", err)
+	}
+	if bundle == nil {
+		return nil, nil
+	}
+
+	if err := s.loadLabels(ctx, []*incident.Incident{bundle.Incident}); err != nil {
+		return nil, err
+	}
+	if err := s.loadStatusTimes(ctx, []*incident.Incident{bundle.Incident}); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// executionRow scans the agent execution columns of a bundle row, all of which
+// are NULL when the incident has no execution.
+type executionRow struct {
+	executionID                                     sql.NullString
+	startedAt, completedAt                          sql.NullTime
+	exitCode                                        sql.NullInt64
+	errorMessage, logPaths                          sql.NullString
+	agentCLI, agentImage, agentModel, promptVersion sql.NullString
+	agentCLIVersion, agentImageDigest               sql.NullString
+	cpuSeconds                                      sql.NullFloat64
+	peakRSS, peakProcesses, diskWritten             sql.NullInt64
+	resourceSource                                  sql.NullString
+}
+
+func (r *executionRow) dest() []interface{} {
+	return []interface{}{
+		&r.executionID, &r.startedAt, &r.completedAt, &r.exitCode, &r.errorMessage,
+		&r.logPaths, &r.agentCLI, &r.agentImage, &r.agentModel, &r.promptVersion,
+		&r.agentCLIVersion, &r.agentImageDigest,
+		&r.cpuSeconds, &r.peakRSS, &r.peakProcesses, &r.diskWritten, &r.resourceSource,
+	}
+}
+
+func (r *executionRow) execution(incidentID string) (*storage.AgentExecution, error) {
+	exec := &storage.AgentExecution{
+		ExecutionID:      r.executionID.String,
+		IncidentID:       incidentID,
+		StartedAt:        r.startedAt.Time,
+		ErrorMessage:     r.errorMessage.String,
+		AgentCLI:         r.agentCLI.String,
+		AgentImage:       r.agentImage.String,
+		AgentModel:       r.agentModel.String,
+		PromptVersion:    r.promptVersion.String,
+		AgentCLIVersion:  r.agentCLIVersion.String,
+		AgentImageDigest: r.agentImageDigest.String,
+	}
+	if r.completedAt.Valid {
+		exec.CompletedAt = &r.completedAt.Time
+	}
+	if r.exitCode.Valid {
+		exitCode := int(r.exitCode.Int64)
+		exec.ExitCode = &exitCode
+	}
+	if r.logPaths.String != "" {
+		if err := json.Unmarshal([]byte(r.logPaths.String), &exec.LogPaths); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal log paths of execution %s: %w", exec.ExecutionID, err)
+		}
+	}
+	if r.resourceSource.Valid {
+		exec.Resources = &storage.AgentResourceUsage{
+			CPUSeconds:       r.cpuSeconds.Float64,
+			PeakRSSBytes:     r.peakRSS.Int64,
+			PeakProcesses:    int(r.peakProcesses.Int64),
+			DiskWrittenBytes: r.diskWritten.Int64,
+			Source:           r.resourceSource.String,
+		}
+	}
+	return exec, nil
+}
+
+// reportRow scans the triage report columns of a bundle row, all of which are
+// NULL when the incident has no report.
+type reportRow struct {
+	reportID, executionID      sql.NullString
+	generatedAt                sql.NullTime
+	reportMarkdown, reportHTML sql.NullString
+}
+
+func (r *reportRow) dest() []interface{} {
+	return []interface{}{&r.reportID, &r.executionID, &r.generatedAt, &r.reportMarkdown, &r.reportHTML}
+}
+
+func (r *reportRow) report(incidentID string) *storage.TriageReport {
+	return &storage.TriageReport{
+		ReportID:       r.reportID.String,
+		IncidentID:     incidentID,
+		ExecutionID:    r.executionID.String,
+		GeneratedAt:    r.generatedAt.Time,
+		ReportMarkdown: r.reportMarkdown.String,
+		ReportHTML:     r.reportHTML.String,
+	}
+}
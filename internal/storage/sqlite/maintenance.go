@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// Auto-vacuum modes reported by PRAGMA auto_vacuum
+const (
+	AutoVacuumNone        = "none"
+	AutoVacuumFull        = "full"
+	AutoVacuumIncremental = "incremental"
+)
+
+// autoVacuumModes maps the values of PRAGMA auto_vacuum to their names
+var autoVacuumModes = map[int]string{0: AutoVacuumNone, 1: AutoVacuumFull, 2: AutoVacuumIncremental}
+
+// Stats describes the size of the database and of its write-ahead log.
+type Stats struct {
+	// DatabaseBytes is the size of the database file
+	DatabaseBytes int64 `json:"database_bytes"`
+	// WALBytes is the size of the write-ahead log file
+	WALBytes int64 `json:"wal_bytes"`
+	// PageSize is the database page size in bytes
+	PageSize int64 `json:"page_size"`
+	// Pages is the number of pages in the database file
+	Pages int64 `json:"pages"`
+	// FreePages is the number of unused pages, which a vacuum returns to the
+	// filesystem
+	FreePages int64 `json:"free_pages"`
+	// AutoVacuum is the auto-vacuum mode: none, full, or incremental
+	AutoVacuum string `json:"auto_vacuum"`
+}
+
+// FreeBytes returns the size of the unused pages.
+func (s *Stats) FreeBytes() int64 {
+	return s.FreePages * s.PageSize
+}
+
+// CheckpointResult reports the outcome of a WAL checkpoint.
+type CheckpointResult struct {
+	// Busy is true when readers or writers kept the checkpoint from completing; the
+	// WAL could not be truncated
+	Busy bool `json:"busy"`
+	// WALFrames is the number of frames in the WAL
+	WALFrames int `json:"wal_frames"`
+	// CheckpointedFrames is the number of frames copied into the database
+	CheckpointedFrames int `json:"checkpointed_frames"`
+}
+
+// Stats returns the size of the database and of its WAL. File sizes are zero for
+// an in-memory database.
+func (s *Store) Stats(ctx context.Context) (*Stats, error) {
+	stats := &Stats{}
+	var autoVacuum int
+	for _, pragma := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"page_size", &stats.PageSize},
+		{"page_count", &stats.Pages},
+		{"freelist_count", &stats.FreePages},
+		{"auto_vacuum", &autoVacuum},
+	} {
+		if err := s.db.QueryRowContext(ctx, "PRAGMA "+pragma.name).Scan(pragma.value); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", pragma.name, err)
+		}
+	}
+	stats.AutoVacuum = autoVacuumModes[autoVacuum]
+
+	if s.path != ":memory:" {
+		info, err := os.Stat(s.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat database: %w", err)
+		}
+		stats.DatabaseBytes = info.Size()
+		if info, err := os.Stat(s.path + "-wal"); err == nil {
+			stats.WALBytes = info.Size()
+		}
+	}
+	return stats, nil
+}
+
+// Checkpoint copies the WAL into the database and truncates it. SQLite checkpoints
+// automatically, but an automatic checkpoint never shrinks the WAL file and cannot
+// complete while readers are active, so under steady load the WAL grows without
+// bound. A checkpoint waits up to the busy timeout for other connections and
+// reports Busy when it could not complete.
+func (s *Store) Checkpoint(ctx context.Context) (*CheckpointResult, error) {
+	var busy int
+	result := &CheckpointResult{}
+	if err := s.db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &result.WALFrames, &result.CheckpointedFrames); err != nil {
+		return nil, fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+	result.Busy = busy != 0
+	return result, nil
+}
+
+// IncrementalVacuum returns up to maxPages unused pages to the filesystem (all of
+// them when maxPages is 0) and returns the number of pages freed. It only works on
+// databases in incremental auto-vacuum mode (see Vacuum); otherwise it frees
+// nothing.
+func (s *Store) IncrementalVacuum(ctx context.Context, maxPages int) (int64, error) {
+	before, err := s.Stats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if before.AutoVacuum != AutoVacuumIncremental || before.FreePages == 0 {
+		return 0, nil
+	}
+	// The pragma frees one page per step, so its rows must be read to the end
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf("PRAGMA incremental_vacuum(%d)", maxPages))
+	if err != nil {
+		return 0, fmt.Errorf("failed to vacuum incrementally: %w", err)
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to vacuum incrementally: %w", err)
+	}
+	after, err := s.Stats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return before.FreePages - after.FreePages, nil
+}
+
+// Vacuum rebuilds the database, returning every unused page to the filesystem,
+// and switches it to incremental auto-vacuum so IncrementalVacuum can reclaim
+// space from then on. It rewrites the whole database and blocks writers while it
+// runs, and needs free disk space for a copy of the database.
+func (s *Store) Vacuum(ctx context.Context) error {
+	// auto_vacuum only takes effect with the VACUUM on the same connection
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA auto_vacuum = INCREMENTAL"); err != nil {
+		return fmt.Errorf("failed to enable incremental auto-vacuum: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
+
+// Optimize lets SQLite refresh the query planner statistics of tables whose
+// contents changed significantly.
+func (s *Store) Optimize(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, "PRAGMA optimize"); err != nil {
+		return fmt.Errorf("failed to optimize database: %w", err)
+	}
+	return nil
+}
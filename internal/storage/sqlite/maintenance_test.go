@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMaintenance(t *testing.T) {
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	cfg.Path = filepath.Join(t.TempDir(), "nightcrier.db")
+	store, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer store.Close()
+
+	var mode string
+	if err := store.db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&mode); err != nil || mode != "wal" {
+		t.Fatalf("journal_mode = %q (%v), want wal", mode, err)
+	}
+
+	// Fill a table, then delete the rows to leave free pages behind
+	if _, err := store.db.ExecContext(ctx, `CREATE TABLE blobs (data TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	payload := strings.Repeat("x", 4096)
+	for i := 0; i < 200; i++ {
+		if _, err := store.db.ExecContext(ctx, `INSERT INTO blobs VALUES (?)`, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before, err := store.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if before.WALBytes == 0 || before.AutoVacuum != AutoVacuumNone {
+		t.Errorf("Stats() before checkpoint = %+v, want a WAL and no auto-vacuum", before)
+	}
+
+	result, err := store.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("Checkpoint() error = %v", err)
+	}
+	if result.Busy {
+		t.Errorf("Checkpoint() = %+v, want it to complete", result)
+	}
+	if stats, _ := store.Stats(ctx); stats.WALBytes != 0 {
+		t.Errorf("WAL is %d bytes after a truncating checkpoint, want 0", stats.WALBytes)
+	}
+
+	if _, err := store.db.ExecContext(ctx, `DELETE FROM blobs`); err != nil {
+		t.Fatal(err)
+	}
+	// Without incremental auto-vacuum nothing is freed
+	if freed, err := store.IncrementalVacuum(ctx, 0); err != nil || freed != 0 {
+		t.Errorf("IncrementalVacuum() without auto-vacuum = %d, %v; want 0", freed, err)
+	}
+
+	if err := store.Vacuum(ctx); err != nil {
+		t.Fatalf("Vacuum() error = %v", err)
+	}
+	stats, err := store.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.AutoVacuum != AutoVacuumIncremental || stats.FreePages != 0 {
+		t.Errorf("Stats() after Vacuum = %+v, want incremental auto-vacuum and no free pages", stats)
+	}
+
+	// Free pages again, and reclaim them incrementally
+	for i := 0; i < 100; i++ {
+		if _, err := store.db.ExecContext(ctx, `INSERT INTO blobs VALUES (?)`, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.db.ExecContext(ctx, `DELETE FROM blobs`); err != nil {
+		t.Fatal(err)
+	}
+	freed, err := store.IncrementalVacuum(ctx, 10)
+	if err != nil {
+		t.Fatalf("IncrementalVacuum() error = %v", err)
+	}
+	if freed != 10 {
+		t.Errorf("IncrementalVacuum(10) freed %d pages, want 10", freed)
+	}
+	if err := store.Optimize(ctx); err != nil {
+		t.Errorf("Optimize() error = %v", err)
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	_ "modernc.org/sqlite" // SQLite driver
 
 	"github.com/rbias/nightcrier/internal/events"
@@ -22,6 +25,8 @@ import (
 // and WAL mode for improved concurrency performance.
 type Store struct {
 	db *sql.DB
+	// path is the database file, or ":memory:"
+	path string
 }
 
 // Config holds configuration options for the SQLite store.
@@ -87,10 +92,16 @@ func New(cfg *Config) (*Store, error) {
 		dbPath = absPath
 	}
 
+	busyTimeout := cfg.BusyTimeout
+	if busyTimeout == 0 {
+		busyTimeout = DefaultConfig().BusyTimeout
+	}
+
 	// Open database connection with pragmas
-	// Enable WAL mode and busy timeout in connection string
-	dsn := fmt.Sprintf("file:%s?_journal_mode=WAL&_busy_timeout=%d&_foreign_keys=on",
-		dbPath, int(cfg.BusyTimeout.Milliseconds()))
+	// Enable WAL mode and busy timeout in connection string. The driver applies
+	// _pragma parameters to every new connection of the pool.
+	dsn := fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=busy_timeout(%d)&_pragma=foreign_keys(1)",
+		dbPath, int(busyTimeout.Milliseconds()))
 
 	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
@@ -110,7 +121,7 @@ func New(cfg *Config) (*Store, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &Store{db: db}, nil
+	return &Store{db: db, path: dbPath}, nil
 }
 
 // CreateIncident creates a new incident from a fault event.
@@ -157,10 +168,11 @@ func (s *Store) CreateIncident(ctx context.Context, inc *incident.Incident, even
 		INSERT INTO incidents (
 			incident_id, fault_id, triggering_event_id,
 			status, created_at, started_at, completed_at,
-			exit_code, failure_reason,
+			exit_code, failure_reason, failure_class,
 			cluster, namespace, fault_type, severity, context, timestamp,
-			resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid,
+			parent_incident_id, display_id, source
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		inc.IncidentID,
 		inc.FaultID,
@@ -171,6 +183,7 @@ func (s *Store) CreateIncident(ctx context.Context, inc *incident.Incident, even
 		inc.CompletedAt,
 		inc.ExitCode,
 		inc.FailureReason,
+		sql.NullString{String: inc.FailureClass, Valid: inc.FailureClass != ""},
 		inc.Cluster,
 		inc.Namespace,
 		inc.FaultType,
@@ -182,11 +195,24 @@ func (s *Store) CreateIncident(ctx context.Context, inc *incident.Incident, even
 		safeResourceField(inc.Resource, func(r *incident.ResourceInfo) string { return r.Name }),
 		safeResourceField(inc.Resource, func(r *incident.ResourceInfo) string { return r.Namespace }),
 		safeResourceField(inc.Resource, func(r *incident.ResourceInfo) string { return r.UID }),
+		sql.NullString{String: inc.ParentIncidentID, Valid: inc.ParentIncidentID != ""},
+		sql.NullString{String: inc.DisplayID, Valid: inc.DisplayID != ""},
+		sql.NullString{String: inc.Source, Valid: inc.Source != ""},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert incident: %w", err)
 	}
 
+	// Insert labels and annotations attached at creation
+	if err := upsertLabels(ctx, tx, inc.IncidentID, inc.Labels, inc.Annotations); err != nil {
+		return err
+	}
+
+	// Record the initial state in the incident's history
+	if err := recordCreation(ctx, tx, inc); err != nil {
+		return err
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -196,58 +222,100 @@ func (s *Store) CreateIncident(ctx context.Context, inc *incident.Incident, even
 }
 
 // UpdateIncidentStatus updates the status of an existing incident.
-// This is called during state transitions (pending -> investigating, investigating -> resolved, etc.).
-// The startedAt timestamp is set when transitioning to investigating status.
+// This is called during state transitions (received -> queued, queued -> investigating, etc.).
+// The startedAt timestamp is set when transitioning to investigating status; nil keeps it.
+// Changes the incident lifecycle does not allow are rejected.
 func (s *Store) UpdateIncidentStatus(ctx context.Context, incidentID string, status string, startedAt *time.Time) error {
-	result, err := s.db.ExecContext(ctx, `
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldStatus string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM incidents WHERE incident_id = ?`, incidentID).Scan(&oldStatus)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("incident not found: %s", incidentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up incident: %w", err)
+	}
+	if err := incident.ValidateTransition(oldStatus, status); err != nil {
+		return fmt.Errorf("incident %s: %w", incidentID, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE incidents
-		SET status = ?, started_at = ?
+		SET status = ?, started_at = COALESCE(?, started_at)
 		WHERE incident_id = ?
 	`, status, startedAt, incidentID)
 	if err != nil {
 		return fmt.Errorf("failed to update incident status: %w", err)
 	}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-	if rows == 0 {
-		return fmt.Errorf("incident not found: %s", incidentID)
+	if err := recordChange(ctx, tx, incidentID, time.Now(), storage.HistoryStatus, oldStatus, status); err != nil {
+		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
 // CompleteIncident marks an incident as complete with final result information.
 // This is called when the agent finishes execution (success or failure).
-// Records the exit code, completion time, and any failure reason.
-func (s *Store) CompleteIncident(ctx context.Context, incidentID string, exitCode int, failureReason string) error {
+// Records the final status (empty: by exit code), the exit code, completion time,
+// and any failure reason and class.
+func (s *Store) CompleteIncident(ctx context.Context, incidentID, status string, exitCode int, failureReason, failureClass string) error {
 	now := time.Now()
 
-	// Determine status based on exit code
-	status := incident.StatusResolved
-	if exitCode != 0 {
-		status = incident.StatusFailed
+	// Without a final status, determine it based on exit code
+	if status == "" {
+		status = incident.StatusResolved
+		if exitCode != 0 {
+			status = incident.StatusFailed
+		}
 	}
 
-	result, err := s.db.ExecContext(ctx, `
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldStatus string
+	var oldFailureClass sql.NullString
+	err = tx.QueryRowContext(ctx, `SELECT status, failure_class FROM incidents WHERE incident_id = ?`, incidentID).Scan(&oldStatus, &oldFailureClass)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("incident not found: %s", incidentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up incident: %w", err)
+	}
+	if err := incident.ValidateTransition(oldStatus, status); err != nil {
+		return fmt.Errorf("incident %s: %w", incidentID, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE incidents
-		SET status = ?, completed_at = ?, exit_code = ?, failure_reason = ?
+		SET status = ?, completed_at = ?, exit_code = ?, failure_reason = ?, failure_class = ?
 		WHERE incident_id = ?
-	`, status, now, exitCode, failureReason, incidentID)
+	`, status, now, exitCode, failureReason, sql.NullString{String: failureClass, Valid: failureClass != ""}, incidentID)
 	if err != nil {
 		return fmt.Errorf("failed to complete incident: %w", err)
 	}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if err := recordChange(ctx, tx, incidentID, now, storage.HistoryStatus, oldStatus, status); err != nil {
+		return err
 	}
-	if rows == 0 {
-		return fmt.Errorf("incident not found: %s", incidentID)
+	if err := recordChange(ctx, tx, incidentID, now, storage.HistoryFailureClass, oldFailureClass.String, failureClass); err != nil {
+		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
@@ -265,17 +333,37 @@ func (s *Store) RecordAgentExecution(ctx context.Context, exec *storage.AgentExe
 		}
 	}
 
+	// Resource usage columns stay NULL until the usage is sampled
+	var cpuSeconds, peakRSS, peakProcesses, diskWritten, resourceSource interface{}
+	if r := exec.Resources; r != nil {
+		cpuSeconds, peakRSS, peakProcesses, diskWritten, resourceSource =
+			r.CPUSeconds, r.PeakRSSBytes, r.PeakProcesses, r.DiskWrittenBytes, r.Source
+	}
+
 	_, err = s.db.ExecContext(ctx, `
 		INSERT INTO agent_executions (
 			execution_id, incident_id,
 			started_at, completed_at, exit_code, error_message,
-			log_paths
-		) VALUES (?, ?, ?, ?, ?, ?, ?)
+			log_paths, agent_cli, agent_image, agent_model, prompt_version,
+			agent_cli_version, agent_image_digest,
+			cpu_seconds, peak_rss_bytes, peak_processes, disk_written_bytes, resource_source
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(execution_id) DO UPDATE SET
 			completed_at = excluded.completed_at,
 			exit_code = excluded.exit_code,
 			error_message = excluded.error_message,
-			log_paths = excluded.log_paths
+			log_paths = excluded.log_paths,
+			agent_cli = excluded.agent_cli,
+			agent_image = excluded.agent_image,
+			agent_model = excluded.agent_model,
+			prompt_version = excluded.prompt_version,
+			agent_cli_version = excluded.agent_cli_version,
+			agent_image_digest = excluded.agent_image_digest,
+			cpu_seconds = excluded.cpu_seconds,
+			peak_rss_bytes = excluded.peak_rss_bytes,
+			peak_processes = excluded.peak_processes,
+			disk_written_bytes = excluded.disk_written_bytes,
+			resource_source = excluded.resource_source
 	`,
 		exec.ExecutionID,
 		exec.IncidentID,
@@ -284,6 +372,17 @@ func (s *Store) RecordAgentExecution(ctx context.Context, exec *storage.AgentExe
 		exec.ExitCode,
 		exec.ErrorMessage,
 		logPathsJSON,
+		exec.AgentCLI,
+		exec.AgentImage,
+		exec.AgentModel,
+		exec.PromptVersion,
+		exec.AgentCLIVersion,
+		exec.AgentImageDigest,
+		cpuSeconds,
+		peakRSS,
+		peakProcesses,
+		diskWritten,
+		resourceSource,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to record agent execution: %w", err)
@@ -292,6 +391,52 @@ func (s *Store) RecordAgentExecution(ctx context.Context, exec *storage.AgentExe
 	return nil
 }
 
+// AgentResourceStats aggregates the resource usage of the agent executions
+// started since the given time, per agent CLI, image, and model.
+func (s *Store) AgentResourceStats(ctx context.Context, since time.Time) ([]*storage.AgentResourceStats, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			COALESCE(agent_cli, ''), COALESCE(agent_image, ''), COALESCE(agent_model, ''),
+			COUNT(*),
+			AVG(cpu_seconds), MAX(cpu_seconds),
+			AVG(peak_rss_bytes), MAX(peak_rss_bytes),
+			MAX(peak_processes),
+			AVG(disk_written_bytes), MAX(disk_written_bytes)
+		FROM agent_executions
+		WHERE resource_source IS NOT NULL AND started_at >= ?
+		GROUP BY 1, 2, 3
+		ORDER BY AVG(cpu_seconds) DESC
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query agent resource stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*storage.AgentResourceStats
+	for rows.Next() {
+		var st storage.AgentResourceStats
+		var avgRSS, avgDisk float64
+		if err := rows.Scan(
+			&st.AgentCLI, &st.AgentImage, &st.AgentModel,
+			&st.Executions,
+			&st.AvgCPUSeconds, &st.MaxCPUSeconds,
+			&avgRSS, &st.MaxPeakRSSBytes,
+			&st.MaxPeakProcesses,
+			&avgDisk, &st.MaxDiskWrittenBytes,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan agent resource stats row: %w", err)
+		}
+		st.AvgPeakRSSBytes = int64(avgRSS)
+		st.AvgDiskWrittenBytes = int64(avgDisk)
+		stats = append(stats, &st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agent resource stats: %w", err)
+	}
+
+	return stats, nil
+}
+
 // RecordTriageReport stores the investigation report generated by the agent.
 // This is called after the agent produces investigation.md output.
 // The report content is stored in markdown format.
@@ -317,89 +462,73 @@ func (s *Store) RecordTriageReport(ctx context.Context, report *storage.TriageRe
 	return nil
 }
 
-// GetIncident retrieves an incident by its ID.
-// Returns nil if the incident is not found.
-func (s *Store) GetIncident(ctx context.Context, incidentID string) (*incident.Incident, error) {
-	var inc incident.Incident
-	var startedAt, completedAt sql.NullTime
-	var exitCode sql.NullInt64
-	var failureReason sql.NullString
-	var resourceAPIVersion, resourceKind, resourceName, resourceNamespace, resourceUID sql.NullString
-
+// GetTriageReport returns the most recent investigation report of an incident.
+// Returns nil if the incident has no report.
+func (s *Store) GetTriageReport(ctx context.Context, incidentID string) (*storage.TriageReport, error) {
+	var report storage.TriageReport
+	var reportHTML sql.NullString
 	err := s.db.QueryRowContext(ctx, `
-		SELECT
-			incident_id, fault_id, triggering_event_id,
-			status, created_at, started_at, completed_at,
-			exit_code, failure_reason,
-			cluster, namespace, fault_type, severity, context, timestamp,
-			resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid
-		FROM incidents
+		SELECT report_id, incident_id, execution_id, generated_at, report_markdown, report_html
+		FROM triage_reports
 		WHERE incident_id = ?
-	`, incidentID).Scan(
-		&inc.IncidentID,
-		&inc.FaultID,
-		&inc.TriggeringEventID,
-		&inc.Status,
-		&inc.CreatedAt,
-		&startedAt,
-		&completedAt,
-		&exitCode,
-		&failureReason,
-		&inc.Cluster,
-		&inc.Namespace,
-		&inc.FaultType,
-		&inc.Severity,
-		&inc.Context,
-		&inc.Timestamp,
-		&resourceAPIVersion,
-		&resourceKind,
-		&resourceName,
-		&resourceNamespace,
-		&resourceUID,
+		ORDER BY generated_at DESC
+		LIMIT 1`, incidentID).Scan(
+		&report.ReportID,
+		&report.IncidentID,
+		&report.ExecutionID,
+		&report.GeneratedAt,
+		&report.ReportMarkdown,
+		&reportHTML,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get incident: %w", err)
+		return nil, fmt.Errorf("failed to get triage report: %w", err)
 	}
+	report.ReportHTML = reportHTML.String
+	return &report, nil
+}
 
-	// Handle nullable fields
-	if startedAt.Valid {
-		inc.StartedAt = &startedAt.Time
-	}
-	if completedAt.Valid {
-		inc.CompletedAt = &completedAt.Time
+// NextIncidentSequence allocates the next sequence number of a cluster's
+// incidents on a day, starting at 1.
+func (s *Store) NextIncidentSequence(ctx context.Context, cluster, day string) (int, error) {
+	var seq int
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO incident_sequences (cluster, day, last_seq) VALUES (?, ?, 1)
+		ON CONFLICT (cluster, day) DO UPDATE SET last_seq = incident_sequences.last_seq + 1
+		RETURNING last_seq`, cluster, day).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to allocate incident sequence: %w", err)
 	}
-	if exitCode.Valid {
-		exitCodeInt := int(exitCode.Int64)
-		inc.ExitCode = &exitCodeInt
+	return seq, nil
+}
+
+// GetIncident retrieves an incident by its ID or display ID.
+// Returns nil if the incident is not found.
+func (s *Store) GetIncident(ctx context.Context, incidentID string) (*incident.Incident, error) {
+	var row incidentRow
+	err := s.db.QueryRowContext(ctx, `
+		SELECT `+incidentColumns+`
+		FROM incidents i
+		WHERE i.incident_id = ? OR i.display_id = ?
+	`, incidentID, incidentID).Scan(row.dest()...)
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-	if failureReason.Valid {
-		inc.FailureReason = failureReason.String
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incident: %w", err)
 	}
 
-	// Reconstruct resource info if any fields are present
-	if resourceKind.Valid || resourceName.Valid {
-		inc.Resource = &incident.ResourceInfo{}
-		if resourceAPIVersion.Valid {
-			inc.Resource.APIVersion = resourceAPIVersion.String
-		}
-		if resourceKind.Valid {
-			inc.Resource.Kind = resourceKind.String
-		}
-		if resourceName.Valid {
-			inc.Resource.Name = resourceName.String
-		}
-		if resourceNamespace.Valid {
-			inc.Resource.Namespace = resourceNamespace.String
-		}
-		if resourceUID.Valid {
-			inc.Resource.UID = resourceUID.String
-		}
+	inc := row.incident()
+	if err := s.loadLabels(ctx, []*incident.Incident{inc}); err != nil {
+		return nil, err
+	}
+	if err := s.loadStatusTimes(ctx, []*incident.Incident{inc}); err != nil {
+		return nil, err
 	}
 
-	return &inc, nil
+	return inc, nil
 }
 
 // ListIncidents returns incidents matching the provided filters.
@@ -410,9 +539,10 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 		SELECT
 			incident_id, fault_id, triggering_event_id,
 			status, created_at, started_at, completed_at,
-			exit_code, failure_reason,
+			exit_code, failure_reason, failure_class,
 			cluster, namespace, fault_type, severity, context, timestamp,
-			resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid
+			resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid,
+			parent_incident_id, display_id, source
 		FROM incidents
 		WHERE 1=1
 	`
@@ -447,6 +577,26 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 			query += " AND severity = ?"
 			args = append(args, filters.Severity)
 		}
+		if filters.ResourceKind != "" {
+			query += " AND resource_kind = ?"
+			args = append(args, filters.ResourceKind)
+		}
+		if filters.ResourceName != "" {
+			query += " AND resource_name = ?"
+			args = append(args, filters.ResourceName)
+		}
+		if filters.ParentIncidentID != "" {
+			query += " AND parent_incident_id = ?"
+			args = append(args, filters.ParentIncidentID)
+		}
+		if filters.Source != "" {
+			query += " AND source = ?"
+			args = append(args, filters.Source)
+		}
+		for _, name := range sortedKeys(filters.Labels) {
+			query += " AND EXISTS (SELECT 1 FROM incident_labels l WHERE l.incident_id = incidents.incident_id AND l.kind = ? AND l.name = ? AND l.value = ?)"
+			args = append(args, kindLabel, name, filters.Labels[name])
+		}
 		if filters.CreatedAfter != nil {
 			query += " AND created_at > ?"
 			args = append(args, *filters.CreatedAfter)
@@ -485,6 +635,7 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 		var exitCode sql.NullInt64
 		var failureReason sql.NullString
 		var resourceAPIVersion, resourceKind, resourceName, resourceNamespace, resourceUID sql.NullString
+		var failureClass, parentIncidentID, displayID, source sql.NullString
 
 		err := rows.Scan(
 			&inc.IncidentID,
@@ -496,6 +647,7 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 			&completedAt,
 			&exitCode,
 			&failureReason,
+			&failureClass,
 			&inc.Cluster,
 			&inc.Namespace,
 			&inc.FaultType,
@@ -507,6 +659,9 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 			&resourceName,
 			&resourceNamespace,
 			&resourceUID,
+			&parentIncidentID,
+			&displayID,
+			&source,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan incident row: %w", err)
@@ -526,6 +681,9 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 		if failureReason.Valid {
 			inc.FailureReason = failureReason.String
 		}
+		if failureClass.Valid {
+			inc.FailureClass = failureClass.String
+		}
 
 		// Reconstruct resource info if any fields are present
 		if resourceKind.Valid || resourceName.Valid {
@@ -547,6 +705,16 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 			}
 		}
 
+		if parentIncidentID.Valid {
+			inc.ParentIncidentID = parentIncidentID.String
+		}
+		if displayID.Valid {
+			inc.DisplayID = displayID.String
+		}
+		if source.Valid {
+			inc.Source = source.String
+		}
+
 		incidents = append(incidents, &inc)
 	}
 
@@ -554,9 +722,821 @@ func (s *Store) ListIncidents(ctx context.Context, filters *storage.IncidentFilt
 		return nil, fmt.Errorf("error iterating incident rows: %w", err)
 	}
 
+	if err := s.loadLabels(ctx, incidents); err != nil {
+		return nil, err
+	}
+	if err := s.loadStatusTimes(ctx, incidents); err != nil {
+		return nil, err
+	}
+
 	return incidents, nil
 }
 
+// SetIncidentLabels adds labels and annotations to an existing incident,
+// replacing the values of keys it already has.
+func (s *Store) SetIncidentLabels(ctx context.Context, incidentID string, labels, annotations map[string]string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM incidents WHERE incident_id = ?`, incidentID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("incident not found: %s", incidentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up incident: %w", err)
+	}
+
+	oldLabels, err := currentLabels(ctx, tx, incidentID)
+	if err != nil {
+		return err
+	}
+
+	if err := upsertLabels(ctx, tx, incidentID, labels, annotations); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, name := range sortedKeys(labels) {
+		if err := recordChange(ctx, tx, incidentID, now, storage.HistoryLabel(name), oldLabels[name], labels[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RemoveIncidentLabels removes the labels and annotations with the given keys
+// from an incident.
+func (s *Store) RemoveIncidentLabels(ctx context.Context, incidentID string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	oldLabels, err := currentLabels(ctx, tx, incidentID)
+	if err != nil {
+		return err
+	}
+
+	query := "DELETE FROM incident_labels WHERE incident_id = ? AND name IN (" + placeholders(len(keys)) + ")"
+	args := []interface{}{incidentID}
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to remove incident labels: %w", err)
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		if err := recordChange(ctx, tx, incidentID, now, storage.HistoryLabel(key), oldLabels[key], ""); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// IncidentHistory returns the recorded changes of an incident's state, oldest
+// first.
+func (s *Store) IncidentHistory(ctx context.Context, incidentID string) ([]*storage.IncidentChange, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT incident_id, changed_at, field, old_value, new_value
+		FROM incident_history
+		WHERE incident_id = ?
+		ORDER BY changed_at
+	`, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*storage.IncidentChange
+	for rows.Next() {
+		var change storage.IncidentChange
+		var oldValue, newValue sql.NullString
+		if err := rows.Scan(&change.IncidentID, &change.ChangedAt, &change.Field, &oldValue, &newValue); err != nil {
+			return nil, fmt.Errorf("failed to scan incident history row: %w", err)
+		}
+		change.OldValue, change.NewValue = oldValue.String, newValue.String
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate incident history: %w", err)
+	}
+	return changes, nil
+}
+
+// IncidentChangesSince returns up to limit incident changes recorded at or after
+// since, oldest first.
+func (s *Store) IncidentChangesSince(ctx context.Context, since time.Time, limit int) ([]*storage.IncidentChange, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT history_id, incident_id, changed_at, field, old_value, new_value
+		FROM incident_history
+		WHERE changed_at >= ?
+		ORDER BY changed_at
+		LIMIT ?
+	`, since.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*storage.IncidentChange
+	for rows.Next() {
+		var change storage.IncidentChange
+		var oldValue, newValue sql.NullString
+		if err := rows.Scan(&change.ID, &change.IncidentID, &change.ChangedAt, &change.Field, &oldValue, &newValue); err != nil {
+			return nil, fmt.Errorf("failed to scan incident change: %w", err)
+		}
+		change.OldValue, change.NewValue = oldValue.String, newValue.String
+		changes = append(changes, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate incident changes: %w", err)
+	}
+	return changes, nil
+}
+
+// AddInvestigationReview records a review of an incident's investigation. Times
+// are stored in UTC so that they compare correctly as text.
+func (s *Store) AddInvestigationReview(ctx context.Context, review *storage.InvestigationReview) error {
+	var exists int
+	err := s.db.QueryRowContext(ctx, `SELECT 1 FROM incidents WHERE incident_id = ?`, review.IncidentID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("incident not found: %s", review.IncidentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up incident: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO investigation_reviews (review_id, incident_id, reviewer, created_at, verdict, comment)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, review.ReviewID, review.IncidentID, review.Reviewer, review.CreatedAt.UTC(),
+		sql.NullString{String: review.Verdict, Valid: review.Verdict != ""},
+		sql.NullString{String: review.Comment, Valid: review.Comment != ""})
+	if err != nil {
+		return fmt.Errorf("failed to insert investigation review: %w", err)
+	}
+	return nil
+}
+
+// InvestigationReviews returns the reviews of an incident, oldest first.
+func (s *Store) InvestigationReviews(ctx context.Context, incidentID string) ([]*storage.InvestigationReview, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT review_id, incident_id, reviewer, created_at, verdict, comment
+		FROM investigation_reviews
+		WHERE incident_id = ?
+		ORDER BY created_at
+	`, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query investigation reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []*storage.InvestigationReview
+	for rows.Next() {
+		var review storage.InvestigationReview
+		var verdict, comment sql.NullString
+		if err := rows.Scan(&review.ReviewID, &review.IncidentID, &review.Reviewer, &review.CreatedAt, &verdict, &comment); err != nil {
+			return nil, fmt.Errorf("failed to scan investigation review row: %w", err)
+		}
+		review.Verdict, review.Comment = verdict.String, comment.String
+		reviews = append(reviews, &review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate investigation reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// ReviewAccuracy aggregates the latest verdict on each incident reviewed since the
+// given time per agent model and prompt version.
+func (s *Store) ReviewAccuracy(ctx context.Context, since time.Time) ([]*storage.ReviewAccuracy, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT COALESCE(e.agent_model, ''), COALESCE(e.prompt_version, ''), r.verdict, COUNT(*)
+		FROM investigation_reviews r
+		LEFT JOIN agent_executions e ON e.incident_id = r.incident_id
+		WHERE r.verdict IS NOT NULL AND r.created_at >= ?
+			AND r.created_at = (
+				SELECT MAX(latest.created_at) FROM investigation_reviews latest
+				WHERE latest.incident_id = r.incident_id AND latest.verdict IS NOT NULL
+			)
+		GROUP BY 1, 2, 3
+	`, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review accuracy: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []storage.VerdictCount
+	for rows.Next() {
+		var c storage.VerdictCount
+		if err := rows.Scan(&c.AgentModel, &c.PromptVersion, &c.Verdict, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan review accuracy row: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate review accuracy: %w", err)
+	}
+	return storage.AggregateAccuracy(counts), nil
+}
+
+// RecordArtifactHashes records the SHA-256 hashes of an incident's stored
+// artifacts, replacing previously recorded hashes of the same artifacts.
+func (s *Store) RecordArtifactHashes(ctx context.Context, incidentID string, hashes map[string]string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	recordedAt := time.Now()
+	for name, hash := range hashes {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO artifact_hashes (incident_id, name, sha256, recorded_at)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT(incident_id, name) DO UPDATE SET sha256 = excluded.sha256, recorded_at = excluded.recorded_at
+		`, incidentID, name, hash, recordedAt)
+		if err != nil {
+			return fmt.Errorf("failed to record artifact hash: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetArtifactHashes returns the recorded artifact hashes of an incident.
+func (s *Store) GetArtifactHashes(ctx context.Context, incidentID string) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT name, sha256 FROM artifact_hashes WHERE incident_id = ?`, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query artifact hashes: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var name, hash string
+		if err := rows.Scan(&name, &hash); err != nil {
+			return nil, fmt.Errorf("failed to scan artifact hash: %w", err)
+		}
+		hashes[name] = hash
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate artifact hashes: %w", err)
+	}
+	return hashes, nil
+}
+
+// PlaceLegalHold places a legal hold on an incident.
+func (s *Store) PlaceLegalHold(ctx context.Context, hold *storage.LegalHold) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM incidents WHERE incident_id = ?`, hold.IncidentID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("incident not found: %s", hold.IncidentID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up incident: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx,
+		`SELECT 1 FROM legal_holds WHERE incident_id = ? AND released_at IS NULL`,
+		hold.IncidentID).Scan(&exists)
+	if err == nil {
+		return fmt.Errorf("incident %s is already under legal hold", hold.IncidentID)
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to look up legal hold: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO legal_holds (hold_id, incident_id, reason, placed_by, placed_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, hold.HoldID, hold.IncidentID, hold.Reason, hold.PlacedBy, hold.PlacedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert legal hold: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ReleaseLegalHold releases the active legal hold on an incident.
+func (s *Store) ReleaseLegalHold(ctx context.Context, incidentID, releasedBy string, releasedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE legal_holds SET released_at = ?, released_by = ?
+		WHERE incident_id = ? AND released_at IS NULL
+	`, releasedAt, releasedBy, incidentID)
+	if err != nil {
+		return fmt.Errorf("failed to release legal hold: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("incident %s is not under legal hold", incidentID)
+	}
+	return nil
+}
+
+// ListLegalHolds returns legal holds, newest first.
+func (s *Store) ListLegalHolds(ctx context.Context, includeReleased bool) ([]*storage.LegalHold, error) {
+	query := `
+		SELECT hold_id, incident_id, reason, placed_by, placed_at, released_at, released_by
+		FROM legal_holds
+	`
+	if !includeReleased {
+		query += " WHERE released_at IS NULL"
+	}
+	query += " ORDER BY placed_at DESC"
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list legal holds: %w", err)
+	}
+	defer rows.Close()
+
+	var holds []*storage.LegalHold
+	for rows.Next() {
+		var hold storage.LegalHold
+		var releasedAt sql.NullTime
+		var releasedBy sql.NullString
+		err := rows.Scan(&hold.HoldID, &hold.IncidentID, &hold.Reason, &hold.PlacedBy, &hold.PlacedAt, &releasedAt, &releasedBy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan legal hold row: %w", err)
+		}
+		if releasedAt.Valid {
+			hold.ReleasedAt = &releasedAt.Time
+		}
+		hold.ReleasedBy = releasedBy.String
+		holds = append(holds, &hold)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate legal holds: %w", err)
+	}
+	return holds, nil
+}
+
+// CreateSilence records a silence. Times are stored in UTC so that they compare
+// correctly as text.
+func (s *Store) CreateSilence(ctx context.Context, silence *storage.Silence) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO silences (silence_id, cluster, namespace, reason, created_by, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, silence.SilenceID, silence.Cluster, silence.Namespace, silence.Reason, silence.CreatedBy, silence.CreatedAt.UTC(), silence.ExpiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to insert silence: %w", err)
+	}
+	return nil
+}
+
+// LiftSilence ends a silence before it expires.
+func (s *Store) LiftSilence(ctx context.Context, silenceID, liftedBy string, liftedAt time.Time) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE silences SET lifted_at = ?, lifted_by = ?
+		WHERE silence_id = ? AND lifted_at IS NULL AND expires_at > ?
+	`, liftedAt.UTC(), liftedBy, silenceID, liftedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to lift silence: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("no active silence %s", silenceID)
+	}
+	return nil
+}
+
+// ListSilences returns the silences active at the given time, soonest expiry first.
+func (s *Store) ListSilences(ctx context.Context, activeAt time.Time) ([]*storage.Silence, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT silence_id, cluster, namespace, reason, created_by, created_at, expires_at
+		FROM silences
+		WHERE lifted_at IS NULL AND expires_at > ?
+		ORDER BY expires_at ASC
+	`, activeAt.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list silences: %w", err)
+	}
+	defer rows.Close()
+
+	var silences []*storage.Silence
+	for rows.Next() {
+		var silence storage.Silence
+		err := rows.Scan(&silence.SilenceID, &silence.Cluster, &silence.Namespace, &silence.Reason,
+			&silence.CreatedBy, &silence.CreatedAt, &silence.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan silence row: %w", err)
+		}
+		silences = append(silences, &silence)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate silences: %w", err)
+	}
+	return silences, nil
+}
+
+// GetSharedState returns the shared state stored under key, or nil when none is stored.
+func (s *Store) GetSharedState(ctx context.Context, key string) (*storage.SharedState, error) {
+	state := &storage.SharedState{Key: key}
+	var value string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT value, version, expires_at FROM shared_state WHERE state_key = ?
+	`, key).Scan(&value, &state.Version, &state.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared state: %w", err)
+	}
+	state.Value = []byte(value)
+	return state, nil
+}
+
+// SwapSharedState stores value under key if the stored version is still version. Times are stored in UTC so that they compare
+// correctly as text.
+func (s *Store) SwapSharedState(ctx context.Context, key string, version int64, value []byte, expiresAt time.Time) (bool, error) {
+	var result sql.Result
+	var err error
+	if version == 0 {
+		result, err = s.db.ExecContext(ctx, `
+			INSERT INTO shared_state (state_key, value, version, expires_at)
+			VALUES (?, ?, 1, ?)
+			ON CONFLICT (state_key) DO NOTHING
+		`, key, string(value), expiresAt.UTC())
+	} else {
+		result, err = s.db.ExecContext(ctx, `
+			UPDATE shared_state SET value = ?, version = version + 1, expires_at = ?
+			WHERE state_key = ? AND version = ?
+		`, string(value), expiresAt.UTC(), key, version)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to store shared state: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows == 1, nil
+}
+
+// PruneSharedState deletes the shared state that expired before the given time.
+func (s *Store) PruneSharedState(ctx context.Context, before time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM shared_state WHERE expires_at < ?
+	`, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune shared state: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
+
+// Label kinds stored in the incident_labels table
+const (
+	kindLabel      = "label"
+	kindAnnotation = "annotation"
+)
+
+// upsertLabels inserts or updates the labels and annotations of an incident.
+func upsertLabels(ctx context.Context, tx *sql.Tx, incidentID string, labels, annotations map[string]string) error {
+	for kind, values := range map[string]map[string]string{kindLabel: labels, kindAnnotation: annotations} {
+		for name, value := range values {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO incident_labels (incident_id, kind, name, value)
+				VALUES (?, ?, ?, ?)
+				ON CONFLICT(incident_id, kind, name) DO UPDATE SET value = excluded.value
+			`, incidentID, kind, name, value)
+			if err != nil {
+				return fmt.Errorf("failed to insert incident %s: %w", kind, err)
+			}
+		}
+	}
+	return nil
+}
+
+// recordChange records a change of an incident's state in its history, unless the
+// value stayed the same. Times are stored in UTC so that they order correctly as
+// text.
+func recordChange(ctx context.Context, tx *sql.Tx, incidentID string, at time.Time, field, oldValue, newValue string) error {
+	if oldValue == newValue {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO incident_history (history_id, incident_id, changed_at, field, old_value, new_value)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, uuid.New().String(), incidentID, at.UTC(), field,
+		sql.NullString{String: oldValue, Valid: oldValue != ""},
+		sql.NullString{String: newValue, Valid: newValue != ""})
+	if err != nil {
+		return fmt.Errorf("failed to record incident history: %w", err)
+	}
+	return nil
+}
+
+// recordCreation records the initial status, failure class, and labels of a new
+// incident in its history, at its creation time.
+func recordCreation(ctx context.Context, tx *sql.Tx, inc *incident.Incident) error {
+	if err := recordChange(ctx, tx, inc.IncidentID, inc.CreatedAt, storage.HistoryStatus, "", inc.Status); err != nil {
+		return err
+	}
+	if err := recordChange(ctx, tx, inc.IncidentID, inc.CreatedAt, storage.HistoryFailureClass, "", inc.FailureClass); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(inc.Labels) {
+		if err := recordChange(ctx, tx, inc.IncidentID, inc.CreatedAt, storage.HistoryLabel(name), "", inc.Labels[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentLabels returns the labels (not annotations) an incident has now.
+func currentLabels(ctx context.Context, tx *sql.Tx, incidentID string) (map[string]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT name, value FROM incident_labels WHERE incident_id = ? AND kind = ?`, incidentID, kindLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load incident labels: %w", err)
+	}
+	defer rows.Close()
+
+	labels := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan incident label row: %w", err)
+		}
+		labels[name] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incident label rows: %w", err)
+	}
+	return labels, nil
+}
+
+// loadLabels populates the labels and annotations of the given incidents.
+func (s *Store) loadLabels(ctx context.Context, incidents []*incident.Incident) error {
+	if len(incidents) == 0 {
+		return nil
+	}
+	byID := make(map[string]*incident.Incident, len(incidents))
+	args := make([]interface{}, 0, len(incidents))
+	for _, inc := range incidents {
+		byID[inc.IncidentID] = inc
+		args = append(args, inc.IncidentID)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT incident_id, kind, name, value FROM incident_labels WHERE incident_id IN ("+placeholders(len(args))+")",
+		args...)
+	if err != nil {
+		return fmt.Errorf("failed to load incident labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var incidentID, kind, name, value string
+		if err := rows.Scan(&incidentID, &kind, &name, &value); err != nil {
+			return fmt.Errorf("failed to scan incident label row: %w", err)
+		}
+		inc := byID[incidentID]
+		if kind == kindAnnotation {
+			inc.AddLabels(nil, map[string]string{name: value})
+		} else {
+			inc.AddLabels(map[string]string{name: value}, nil)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating incident label rows: %w", err)
+	}
+	return nil
+}
+
+// loadStatusTimes populates the status times of the given incidents from their
+// history: when each incident last entered each status.
+func (s *Store) loadStatusTimes(ctx context.Context, incidents []*incident.Incident) error {
+	if len(incidents) == 0 {
+		return nil
+	}
+	byID := make(map[string]*incident.Incident, len(incidents))
+	args := []interface{}{storage.HistoryStatus}
+	for _, inc := range incidents {
+		byID[inc.IncidentID] = inc
+		args = append(args, inc.IncidentID)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT incident_id, new_value, changed_at FROM incident_history WHERE field = ? AND new_value IS NOT NULL AND incident_id IN ("+placeholders(len(args)-1)+") ORDER BY changed_at",
+		args...)
+	if err != nil {
+		return fmt.Errorf("failed to load incident status times: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var incidentID, status string
+		var at time.Time
+		if err := rows.Scan(&incidentID, &status, &at); err != nil {
+			return fmt.Errorf("failed to scan incident status time row: %w", err)
+		}
+		inc := byID[incidentID]
+		if inc.StatusTimes == nil {
+			inc.StatusTimes = make(map[string]time.Time)
+		}
+		// Rows are ordered by time, so a status entered again keeps its latest time
+		inc.StatusTimes[status] = at
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating incident status time rows: %w", err)
+	}
+	return nil
+}
+
+// placeholders returns n comma-separated "?" query placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// sortedKeys returns the keys of m in sorted order, so generated queries are stable.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RecordRunStart records the startup of a nightcrier process.
+// The cluster list is stored as a JSON array.
+func (s *Store) RecordRunStart(ctx context.Context, run *storage.RunRecord) error {
+	clustersJSON, err := json.Marshal(run.Clusters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run clusters: %w", err)
+	}
+	var snapshot sql.NullString
+	if run.ConfigSnapshot != nil {
+		snapshotJSON, err := json.Marshal(run.ConfigSnapshot)
+		if err != nil {
+			return fmt.Errorf("failed to marshal run config snapshot: %w", err)
+		}
+		snapshot = sql.NullString{String: string(snapshotJSON), Valid: true}
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO runs (
+			run_id, started_at, version, git_commit, config_hash, config_snapshot,
+			clusters, artifact_storage, state_storage, hostname
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		run.RunID,
+		run.StartedAt,
+		run.Version,
+		run.GitCommit,
+		run.ConfigHash,
+		snapshot,
+		string(clustersJSON),
+		run.ArtifactStorage,
+		run.StateStorage,
+		run.Hostname,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record run start: %w", err)
+	}
+
+	return nil
+}
+
+// RecordRunStop records the shutdown of a nightcrier process.
+func (s *Store) RecordRunStop(ctx context.Context, runID string, stoppedAt time.Time, exitReason string) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE runs
+		SET stopped_at = ?, exit_reason = ?
+		WHERE run_id = ?
+	`, stoppedAt, exitReason, runID)
+	if err != nil {
+		return fmt.Errorf("failed to record run stop: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("run not found: %s", runID)
+	}
+
+	return nil
+}
+
+// ListRuns returns the most recent run records, newest first.
+// A limit of 0 returns all runs.
+func (s *Store) ListRuns(ctx context.Context, limit int) ([]*storage.RunRecord, error) {
+	query := `
+		SELECT
+			run_id, started_at, stopped_at, version, git_commit, config_hash, config_snapshot,
+			clusters, artifact_storage, state_storage, hostname, exit_reason
+		FROM runs
+		ORDER BY started_at DESC
+	`
+	args := []interface{}{}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*storage.RunRecord
+	for rows.Next() {
+		var run storage.RunRecord
+		var stoppedAt sql.NullTime
+		var gitCommit, hostname, exitReason, snapshotJSON sql.NullString
+		var clustersJSON string
+
+		err := rows.Scan(
+			&run.RunID,
+			&run.StartedAt,
+			&stoppedAt,
+			&run.Version,
+			&gitCommit,
+			&run.ConfigHash,
+			&snapshotJSON,
+			&clustersJSON,
+			&run.ArtifactStorage,
+			&run.StateStorage,
+			&hostname,
+			&exitReason,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan run row: %w", err)
+		}
+
+		// Handle nullable fields
+		if stoppedAt.Valid {
+			run.StoppedAt = &stoppedAt.Time
+		}
+		run.GitCommit = gitCommit.String
+		run.Hostname = hostname.String
+		run.ExitReason = exitReason.String
+		if err := json.Unmarshal([]byte(clustersJSON), &run.Clusters); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal run clusters: %w", err)
+		}
+		if snapshotJSON.Valid && snapshotJSON.String != "" {
+			if err := json.Unmarshal([]byte(snapshotJSON.String), &run.ConfigSnapshot); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal run config snapshot: %w", err)
+			}
+		}
+
+		runs = append(runs, &run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating run rows: %w", err)
+	}
+
+	return runs, nil
+}
+
 // Close releases resources held by the store.
 // Should be called during application shutdown.
 func (s *Store) Close() error {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -78,6 +79,7 @@ CREATE TABLE IF NOT EXISTS incidents (
     completed_at TIMESTAMP,
     exit_code INTEGER,
     failure_reason TEXT,
+    failure_class TEXT,
     cluster TEXT NOT NULL,
     namespace TEXT,
     fault_type TEXT NOT NULL,
@@ -89,8 +91,11 @@ CREATE TABLE IF NOT EXISTS incidents (
     resource_name TEXT,
     resource_namespace TEXT,
     resource_uid TEXT,
+    parent_incident_id TEXT,
+    display_id TEXT,
+    source TEXT,
     FOREIGN KEY (fault_id) REFERENCES fault_events(fault_id),
-    CONSTRAINT chk_incidents_status CHECK (status IN ('pending', 'investigating', 'resolved', 'failed', 'agent_failed')),
+    CONSTRAINT chk_incidents_status CHECK (status IN ('received', 'pending', 'queued', 'investigating', 'awaiting_review', 'remediation_proposed', 'resolved', 'failed', 'agent_failed', 'suppressed', 'expired')),
     CONSTRAINT chk_incidents_cluster CHECK (cluster <> ''),
     CONSTRAINT chk_incidents_fault_type CHECK (fault_type <> '')
 );
@@ -112,6 +117,17 @@ CREATE TABLE IF NOT EXISTS agent_executions (
     exit_code INTEGER,
     error_message TEXT,
     log_paths TEXT,
+    agent_cli TEXT,
+    agent_image TEXT,
+    agent_model TEXT,
+    cpu_seconds REAL,
+    peak_rss_bytes BIGINT,
+    peak_processes INTEGER,
+    disk_written_bytes BIGINT,
+    resource_source TEXT,
+    prompt_version TEXT,
+    agent_cli_version TEXT,
+    agent_image_digest TEXT,
     FOREIGN KEY (incident_id) REFERENCES incidents(incident_id),
     CONSTRAINT chk_agent_executions_incident_id CHECK (incident_id <> '')
 );
@@ -119,6 +135,16 @@ CREATE TABLE IF NOT EXISTS agent_executions (
 CREATE INDEX IF NOT EXISTS idx_agent_executions_incident_id ON agent_executions(incident_id);
 CREATE INDEX IF NOT EXISTS idx_agent_executions_started_at ON agent_executions(started_at);
 
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incidents_display_id ON incidents(display_id);
+
+-- incident_sequences allocates display ID sequence numbers
+CREATE TABLE IF NOT EXISTS incident_sequences (
+    cluster TEXT NOT NULL,
+    day TEXT NOT NULL,
+    last_seq INTEGER NOT NULL,
+    PRIMARY KEY (cluster, day)
+);
+
 -- triage_reports table stores the investigation reports generated by agents
 CREATE TABLE IF NOT EXISTS triage_reports (
     report_id TEXT PRIMARY KEY,
@@ -136,6 +162,99 @@ CREATE TABLE IF NOT EXISTS triage_reports (
 CREATE INDEX IF NOT EXISTS idx_triage_reports_incident_id ON triage_reports(incident_id);
 CREATE INDEX IF NOT EXISTS idx_triage_reports_execution_id ON triage_reports(execution_id);
 CREATE INDEX IF NOT EXISTS idx_triage_reports_generated_at ON triage_reports(generated_at);
+
+-- runs table records nightcrier process lifetimes
+CREATE TABLE IF NOT EXISTS runs (
+    run_id TEXT PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    stopped_at TIMESTAMP,
+    version TEXT NOT NULL,
+    git_commit TEXT,
+    config_hash TEXT NOT NULL,
+    clusters TEXT NOT NULL,
+    artifact_storage TEXT NOT NULL,
+    state_storage TEXT NOT NULL,
+    hostname TEXT,
+    exit_reason TEXT,
+    config_snapshot TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_runs_started_at ON runs(started_at);
+
+-- incident_labels table holds incident labels and annotations
+CREATE TABLE IF NOT EXISTS incident_labels (
+    incident_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (incident_id, kind, name),
+    FOREIGN KEY (incident_id) REFERENCES incidents(incident_id)
+);
+
+-- artifact_hashes table holds the SHA-256 hashes of stored artifacts
+CREATE TABLE IF NOT EXISTS artifact_hashes (
+    incident_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    sha256 TEXT NOT NULL,
+    recorded_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (incident_id, name),
+    FOREIGN KEY (incident_id) REFERENCES incidents(incident_id)
+);
+
+-- legal_holds table holds legal holds placed on incidents
+CREATE TABLE IF NOT EXISTS legal_holds (
+    hold_id TEXT PRIMARY KEY,
+    incident_id TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    placed_by TEXT NOT NULL,
+    placed_at TIMESTAMP NOT NULL,
+    released_at TIMESTAMP,
+    released_by TEXT,
+    FOREIGN KEY (incident_id) REFERENCES incidents(incident_id)
+);
+
+-- silences table holds namespace silences
+CREATE TABLE IF NOT EXISTS silences (
+    silence_id TEXT PRIMARY KEY,
+    cluster TEXT NOT NULL DEFAULT '',
+    namespace TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    lifted_at TIMESTAMP,
+    lifted_by TEXT
+);
+
+-- shared_state table holds fleet-wide limit state
+CREATE TABLE IF NOT EXISTS shared_state (
+    state_key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    version BIGINT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- incident_history table holds the changes of incident state
+CREATE TABLE IF NOT EXISTS incident_history (
+    history_id TEXT PRIMARY KEY,
+    incident_id TEXT NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    field TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    FOREIGN KEY (incident_id) REFERENCES incidents(incident_id)
+);
+
+-- investigation_reviews table holds human reviews of investigations
+CREATE TABLE IF NOT EXISTS investigation_reviews (
+    review_id TEXT PRIMARY KEY,
+    incident_id TEXT NOT NULL,
+    reviewer TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    verdict TEXT,
+    comment TEXT,
+    FOREIGN KEY (incident_id) REFERENCES incidents(incident_id)
+);
 `
 	_, err := db.Exec(schema)
 	return err
@@ -320,6 +439,48 @@ func TestUpdateIncidentStatus_NotFound(t *testing.T) {
 	}
 }
 
+func TestUpdateIncidentStatus_Lifecycle(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	event := createTestEvent("fault-lifecycle")
+	inc := createTestIncident("inc-lifecycle", event)
+	if err := store.CreateIncident(ctx, inc, event); err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+
+	startedAt := time.Now()
+	if err := store.UpdateIncidentStatus(ctx, inc.IncidentID, incident.StatusQueued, nil); err != nil {
+		t.Fatalf("UpdateIncidentStatus(queued) error = %v", err)
+	}
+	if err := store.UpdateIncidentStatus(ctx, inc.IncidentID, incident.StatusInvestigating, &startedAt); err != nil {
+		t.Fatalf("UpdateIncidentStatus(investigating) error = %v", err)
+	}
+	if err := store.CompleteIncident(ctx, inc.IncidentID, incident.StatusAwaitingReview, 0, "", ""); err != nil {
+		t.Fatalf("CompleteIncident(awaiting_review) error = %v", err)
+	}
+
+	// The lifecycle rejects going back to the queue; the start time is kept
+	err := store.UpdateIncidentStatus(ctx, inc.IncidentID, incident.StatusQueued, nil)
+	if !errors.Is(err, incident.ErrInvalidTransition) {
+		t.Errorf("UpdateIncidentStatus(queued) error = %v, want ErrInvalidTransition", err)
+	}
+
+	retrieved, err := store.GetIncident(ctx, inc.IncidentID)
+	if err != nil {
+		t.Fatalf("GetIncident() error = %v", err)
+	}
+	if retrieved.Status != incident.StatusAwaitingReview || retrieved.StartedAt == nil || retrieved.CompletedAt == nil {
+		t.Errorf("incident = %s, started %v, completed %v; want awaiting_review with both times", retrieved.Status, retrieved.StartedAt, retrieved.CompletedAt)
+	}
+	for _, status := range []string{incident.StatusReceived, incident.StatusQueued, incident.StatusInvestigating, incident.StatusAwaitingReview} {
+		if retrieved.StatusTimes[status].IsZero() {
+			t.Errorf("StatusTimes = %v, missing %s", retrieved.StatusTimes, status)
+		}
+	}
+}
+
 func TestCompleteIncident(t *testing.T) {
 	store := setupTestStore(t)
 	defer store.Close()
@@ -338,6 +499,7 @@ func TestCompleteIncident(t *testing.T) {
 		name          string
 		exitCode      int
 		failureReason string
+		failureClass  string
 		wantStatus    string
 	}{
 		{
@@ -349,6 +511,7 @@ func TestCompleteIncident(t *testing.T) {
 			name:          "failed completion",
 			exitCode:      1,
 			failureReason: "agent failed",
+			failureClass:  "unknown",
 			wantStatus:    incident.StatusFailed,
 		},
 	}
@@ -364,7 +527,7 @@ func TestCompleteIncident(t *testing.T) {
 			}
 
 			// Complete incident
-			err = store.CompleteIncident(ctx, testInc.IncidentID, tt.exitCode, tt.failureReason)
+			err = store.CompleteIncident(ctx, testInc.IncidentID, "", tt.exitCode, tt.failureReason, tt.failureClass)
 			if err != nil {
 				t.Fatalf("CompleteIncident() error = %v", err)
 			}
@@ -389,6 +552,9 @@ func TestCompleteIncident(t *testing.T) {
 			if tt.failureReason != "" && retrieved.FailureReason != tt.failureReason {
 				t.Errorf("FailureReason = %v, want %v", retrieved.FailureReason, tt.failureReason)
 			}
+			if retrieved.FailureClass != tt.failureClass {
+				t.Errorf("FailureClass = %v, want %v", retrieved.FailureClass, tt.failureClass)
+			}
 		})
 	}
 }
@@ -548,6 +714,100 @@ func TestRecordTriageReport(t *testing.T) {
 	if markdown != report.ReportMarkdown {
 		t.Errorf("ReportMarkdown = %v, want %v", markdown, report.ReportMarkdown)
 	}
+
+	// The most recent report is returned
+	latest := &storage.TriageReport{
+		ReportID:       "report-002",
+		IncidentID:     inc.IncidentID,
+		ExecutionID:    exec.ExecutionID,
+		GeneratedAt:    report.GeneratedAt.Add(time.Minute),
+		ReportMarkdown: "# Investigation Report\n\nRerun",
+	}
+	if err := store.RecordTriageReport(ctx, latest); err != nil {
+		t.Fatalf("RecordTriageReport() error = %v", err)
+	}
+	got, err := store.GetTriageReport(ctx, inc.IncidentID)
+	if err != nil {
+		t.Fatalf("GetTriageReport() error = %v", err)
+	}
+	if got == nil || got.ReportID != latest.ReportID || got.ReportMarkdown != latest.ReportMarkdown {
+		t.Errorf("GetTriageReport() = %+v, want report-002", got)
+	}
+	if got, err := store.GetTriageReport(ctx, "inc-missing"); err != nil || got != nil {
+		t.Errorf("GetTriageReport(missing) = %+v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestGetIncidentBundle(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	event := createTestEvent("fault-bundle")
+	inc := createTestIncident("inc-bundle", event)
+	if err := store.CreateIncident(ctx, inc, event); err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+	if err := store.SetIncidentLabels(ctx, inc.IncidentID, map[string]string{"team": "payments"}, nil); err != nil {
+		t.Fatalf("SetIncidentLabels() error = %v", err)
+	}
+
+	bundle, err := store.GetIncidentBundle(ctx, inc.IncidentID)
+	if err != nil {
+		t.Fatalf("GetIncidentBundle() error = %v", err)
+	}
+	if bundle == nil || bundle.Incident.IncidentID != inc.IncidentID || len(bundle.Executions) != 0 || len(bundle.Reports) != 0 {
+		t.Fatalf("GetIncidentBundle() = %+v, want the incident without executions or reports", bundle)
+	}
+
+	// A failed attempt and a retry, each with a report
+	started := time.Now().Add(-time.Hour).UTC()
+	exitCode := 1
+	for i, exec := range []*storage.AgentExecution{
+		{ExecutionID: "exec-1", StartedAt: started, ExitCode: &exitCode, ErrorMessage: "timeout"},
+		{ExecutionID: "exec-2", StartedAt: started.Add(10 * time.Minute), LogPaths: map[string]string{"stdout": "/logs/stdout.log"},
+			Resources: &storage.AgentResourceUsage{CPUSeconds: 12.5, PeakRSSBytes: 1 << 20, Source: "cgroup"}},
+	} {
+		exec.IncidentID = inc.IncidentID
+		if err := store.RecordAgentExecution(ctx, exec); err != nil {
+			t.Fatalf("RecordAgentExecution() error = %v", err)
+		}
+		report := &storage.TriageReport{
+			ReportID:       fmt.Sprintf("report-%d", i+1),
+			IncidentID:     inc.IncidentID,
+			ExecutionID:    exec.ExecutionID,
+			GeneratedAt:    exec.StartedAt.Add(5 * time.Minute),
+			ReportMarkdown: fmt.Sprintf("# Attempt %d", i+1),
+		}
+		if err := store.RecordTriageReport(ctx, report); err != nil {
+			t.Fatalf("RecordTriageReport() error = %v", err)
+		}
+	}
+
+	bundle, err = store.GetIncidentBundle(ctx, inc.IncidentID)
+	if err != nil {
+		t.Fatalf("GetIncidentBundle() error = %v", err)
+	}
+	if bundle.Incident.Labels["team"] != "payments" {
+		t.Errorf("Incident.Labels = %v, want the team label", bundle.Incident.Labels)
+	}
+	if len(bundle.Executions) != 2 || bundle.Executions[0].ExecutionID != "exec-1" || bundle.Executions[1].ExecutionID != "exec-2" {
+		t.Fatalf("Executions = %+v, want exec-1 and exec-2 once each, oldest first", bundle.Executions)
+	}
+	first, retry := bundle.Executions[0], bundle.Executions[1]
+	if first.ExitCode == nil || *first.ExitCode != 1 || first.ErrorMessage != "timeout" || first.Resources != nil {
+		t.Errorf("Executions[0] = %+v, want the failed attempt", first)
+	}
+	if retry.ExitCode != nil || retry.LogPaths["stdout"] != "/logs/stdout.log" || retry.Resources == nil || retry.Resources.CPUSeconds != 12.5 {
+		t.Errorf("Executions[1] = %+v, want the running retry with its logs and resources", retry)
+	}
+	if len(bundle.Reports) != 2 || bundle.Reports[0].ReportID != "report-1" || bundle.Reports[1].ReportMarkdown != "# Attempt 2" {
+		t.Errorf("Reports = %+v, want report-1 and report-2 once each, oldest first", bundle.Reports)
+	}
+
+	if bundle, err := store.GetIncidentBundle(ctx, "nonexistent"); err != nil || bundle != nil {
+		t.Errorf("GetIncidentBundle(nonexistent) = %+v, %v, want nil, nil", bundle, err)
+	}
 }
 
 func TestGetIncident_NotFound(t *testing.T) {
@@ -734,6 +994,86 @@ func TestConcurrentAccess(t *testing.T) {
 	}
 }
 
+func TestRunRecords(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	first := time.Now().Add(-time.Hour).UTC()
+	runs := []*storage.RunRecord{
+		{
+			RunID:           "run-1",
+			StartedAt:       first,
+			Version:         "1.0.0",
+			GitCommit:       "abc123",
+			ConfigHash:      "hash-1",
+			ConfigSnapshot:  map[string]string{"log_level": "info"},
+			Clusters:        []string{"prod-east", "prod-west"},
+			ArtifactStorage: "local_filesystem",
+			StateStorage:    "sqlite",
+			Hostname:        "node-a",
+		},
+		{
+			RunID:           "run-2",
+			StartedAt:       first.Add(30 * time.Minute),
+			Version:         "1.1.0",
+			ConfigHash:      "hash-2",
+			Clusters:        []string{"prod-east"},
+			ArtifactStorage: "azure_blob",
+			StateStorage:    "sqlite",
+		},
+	}
+	for _, run := range runs {
+		if err := store.RecordRunStart(ctx, run); err != nil {
+			t.Fatalf("RecordRunStart(%s) error = %v", run.RunID, err)
+		}
+	}
+	if err := store.RecordRunStop(ctx, "run-1", first.Add(29*time.Minute), "shutdown"); err != nil {
+		t.Fatalf("RecordRunStop() error = %v", err)
+	}
+	if err := store.RecordRunStop(ctx, "missing", time.Now(), "shutdown"); err == nil {
+		t.Error("RecordRunStop() should fail for an unknown run")
+	}
+
+	got, err := store.ListRuns(ctx, 0)
+	if err != nil {
+		t.Fatalf("ListRuns() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ListRuns() returned %d runs, want 2", len(got))
+	}
+	if got[0].RunID != "run-2" || got[1].RunID != "run-1" {
+		t.Errorf("ListRuns() order = %s, %s; want newest first", got[0].RunID, got[1].RunID)
+	}
+	if got[0].StoppedAt != nil || got[0].ExitReason != "" {
+		t.Errorf("running run has stop data: %+v", got[0])
+	}
+	stopped := got[1]
+	if stopped.StoppedAt == nil || stopped.ExitReason != "shutdown" {
+		t.Errorf("stopped run = %+v, want stop time and reason", stopped)
+	}
+	if stopped.GitCommit != "abc123" || stopped.Hostname != "node-a" || stopped.ConfigHash != "hash-1" {
+		t.Errorf("stopped run fields = %+v", stopped)
+	}
+	if stopped.ConfigSnapshot["log_level"] != "info" {
+		t.Errorf("ConfigSnapshot = %v, want log_level=info", stopped.ConfigSnapshot)
+	}
+	if got[0].ConfigSnapshot != nil {
+		t.Errorf("ConfigSnapshot = %v for a run recorded without one, want nil", got[0].ConfigSnapshot)
+	}
+	if len(stopped.Clusters) != 2 || stopped.Clusters[1] != "prod-west" {
+		t.Errorf("Clusters = %v, want [prod-east prod-west]", stopped.Clusters)
+	}
+
+	limited, err := store.ListRuns(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListRuns(1) error = %v", err)
+	}
+	if len(limited) != 1 || limited[0].RunID != "run-2" {
+		t.Errorf("ListRuns(1) = %v, want only run-2", limited)
+	}
+}
+
 func TestClose(t *testing.T) {
 	store := setupTestStore(t)
 
@@ -749,3 +1089,601 @@ func TestClose(t *testing.T) {
 		t.Error("GetIncident() should fail after Close()")
 	}
 }
+
+func TestIncidentLabels(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	payments := createTestIncident("inc-payments", createTestEvent("fault-payments"))
+	payments.AddLabels(map[string]string{"team": "payments", "env": "prod"}, nil)
+	other := createTestIncident("inc-other", createTestEvent("fault-other"))
+	other.AddLabels(map[string]string{"team": "platform", "env": "prod"}, nil)
+	for _, inc := range []*incident.Incident{payments, other} {
+		if err := store.CreateIncident(ctx, inc, createTestEvent(inc.FaultID)); err != nil {
+			t.Fatalf("CreateIncident() error = %v", err)
+		}
+	}
+
+	// Labels added later are merged, replacing existing keys
+	if err := store.SetIncidentLabels(ctx, "inc-payments",
+		map[string]string{"env": "staging", "cost-center": "cc-4711"},
+		map[string]string{"note": "caused by deploy"}); err != nil {
+		t.Fatalf("SetIncidentLabels() error = %v", err)
+	}
+	if err := store.SetIncidentLabels(ctx, "inc-missing", map[string]string{"team": "x"}, nil); err == nil {
+		t.Error("SetIncidentLabels() should fail for an unknown incident")
+	}
+
+	got, err := store.GetIncident(ctx, "inc-payments")
+	if err != nil {
+		t.Fatalf("GetIncident() error = %v", err)
+	}
+	if got.Labels["team"] != "payments" || got.Labels["env"] != "staging" || got.Labels["cost-center"] != "cc-4711" {
+		t.Errorf("Labels = %v", got.Labels)
+	}
+	if got.Annotations["note"] != "caused by deploy" {
+		t.Errorf("Annotations = %v", got.Annotations)
+	}
+
+	// Every label in the filter must match
+	listed, err := store.ListIncidents(ctx, &storage.IncidentFilters{Labels: map[string]string{"env": "prod"}})
+	if err != nil {
+		t.Fatalf("ListIncidents() error = %v", err)
+	}
+	if len(listed) != 1 || listed[0].IncidentID != "inc-other" || listed[0].Labels["team"] != "platform" {
+		t.Errorf("ListIncidents(env=prod) = %v, want only inc-other with its labels", listed)
+	}
+	listed, err = store.ListIncidents(ctx, &storage.IncidentFilters{Labels: map[string]string{"team": "payments", "cost-center": "cc-4711"}})
+	if err != nil {
+		t.Fatalf("ListIncidents() error = %v", err)
+	}
+	if len(listed) != 1 || listed[0].IncidentID != "inc-payments" {
+		t.Errorf("ListIncidents(team=payments,cost-center=cc-4711) returned %d incidents, want inc-payments", len(listed))
+	}
+
+	if err := store.RemoveIncidentLabels(ctx, "inc-payments", []string{"cost-center", "note"}); err != nil {
+		t.Fatalf("RemoveIncidentLabels() error = %v", err)
+	}
+	got, err = store.GetIncident(ctx, "inc-payments")
+	if err != nil {
+		t.Fatalf("GetIncident() error = %v", err)
+	}
+	if _, ok := got.Labels["cost-center"]; ok || len(got.Annotations) != 0 {
+		t.Errorf("after removal Labels = %v, Annotations = %v", got.Labels, got.Annotations)
+	}
+}
+
+func TestArtifactHashes(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	inc := createTestIncident("inc-hashes", createTestEvent("fault-hashes"))
+	if err := store.CreateIncident(ctx, inc, createTestEvent(inc.FaultID)); err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+
+	if err := store.RecordArtifactHashes(ctx, "inc-hashes", map[string]string{
+		"investigation.md":    "aaaa",
+		"logs/agent-full.log": "bbbb",
+	}); err != nil {
+		t.Fatalf("RecordArtifactHashes() error = %v", err)
+	}
+	// A re-upload replaces the hash of the same artifact
+	if err := store.RecordArtifactHashes(ctx, "inc-hashes", map[string]string{"investigation.md": "cccc"}); err != nil {
+		t.Fatalf("RecordArtifactHashes() error = %v", err)
+	}
+
+	hashes, err := store.GetArtifactHashes(ctx, "inc-hashes")
+	if err != nil {
+		t.Fatalf("GetArtifactHashes() error = %v", err)
+	}
+	if len(hashes) != 2 || hashes["investigation.md"] != "cccc" || hashes["logs/agent-full.log"] != "bbbb" {
+		t.Errorf("GetArtifactHashes() = %v", hashes)
+	}
+
+	hashes, err = store.GetArtifactHashes(ctx, "inc-missing")
+	if err != nil || len(hashes) != 0 {
+		t.Errorf("GetArtifactHashes(unknown) = %v, %v; want empty", hashes, err)
+	}
+}
+
+func TestLegalHolds(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	inc := createTestIncident("inc-held", createTestEvent("fault-held"))
+	if err := store.CreateIncident(ctx, inc, createTestEvent(inc.FaultID)); err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+
+	placedAt := time.Now().Add(-time.Hour)
+	hold := &storage.LegalHold{HoldID: "hold-1", IncidentID: "inc-held", Reason: "matter 2024-17", PlacedBy: "legal", PlacedAt: placedAt}
+	if err := store.PlaceLegalHold(ctx, hold); err != nil {
+		t.Fatalf("PlaceLegalHold() error = %v", err)
+	}
+	if err := store.PlaceLegalHold(ctx, &storage.LegalHold{HoldID: "hold-2", IncidentID: "inc-held", PlacedAt: time.Now()}); err == nil {
+		t.Error("PlaceLegalHold() should fail for an incident already under hold")
+	}
+	if err := store.PlaceLegalHold(ctx, &storage.LegalHold{HoldID: "hold-3", IncidentID: "inc-missing", PlacedAt: time.Now()}); err == nil {
+		t.Error("PlaceLegalHold() should fail for an unknown incident")
+	}
+
+	holds, err := store.ListLegalHolds(ctx, false)
+	if err != nil {
+		t.Fatalf("ListLegalHolds() error = %v", err)
+	}
+	if len(holds) != 1 || holds[0].IncidentID != "inc-held" || holds[0].Reason != "matter 2024-17" || !holds[0].Active() {
+		t.Fatalf("ListLegalHolds() = %+v, want the active hold", holds)
+	}
+
+	if err := store.ReleaseLegalHold(ctx, "inc-held", "legal", time.Now()); err != nil {
+		t.Fatalf("ReleaseLegalHold() error = %v", err)
+	}
+	if err := store.ReleaseLegalHold(ctx, "inc-held", "legal", time.Now()); err == nil {
+		t.Error("ReleaseLegalHold() should fail when no hold is active")
+	}
+
+	if holds, _ := store.ListLegalHolds(ctx, false); len(holds) != 0 {
+		t.Errorf("ListLegalHolds(active) after release = %+v, want none", holds)
+	}
+	holds, err = store.ListLegalHolds(ctx, true)
+	if err != nil {
+		t.Fatalf("ListLegalHolds() error = %v", err)
+	}
+	if len(holds) != 1 || holds[0].Active() || holds[0].ReleasedBy != "legal" {
+		t.Errorf("ListLegalHolds(all) = %+v, want the released hold", holds)
+	}
+
+	// A released incident can be held again
+	if err := store.PlaceLegalHold(ctx, &storage.LegalHold{HoldID: "hold-4", IncidentID: "inc-held", Reason: "new matter", PlacedBy: "legal", PlacedAt: time.Now()}); err != nil {
+		t.Errorf("PlaceLegalHold() after release error = %v", err)
+	}
+}
+
+func TestFollowUpIncidents(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	parent := createTestIncident("inc-parent", createTestEvent("fault-parent"))
+	if err := store.CreateIncident(ctx, parent, createTestEvent(parent.FaultID)); err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+	child := createTestIncident("inc-child", createTestEvent("fault-child"))
+	child.ParentIncidentID = parent.IncidentID
+	if err := store.CreateIncident(ctx, child, createTestEvent(child.FaultID)); err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+
+	got, err := store.GetIncident(ctx, "inc-child")
+	if err != nil {
+		t.Fatalf("GetIncident() error = %v", err)
+	}
+	if got.ParentIncidentID != "inc-parent" {
+		t.Errorf("ParentIncidentID = %q, want inc-parent", got.ParentIncidentID)
+	}
+	if got, _ := store.GetIncident(ctx, "inc-parent"); got.ParentIncidentID != "" {
+		t.Errorf("ParentIncidentID = %q, want none", got.ParentIncidentID)
+	}
+
+	children, err := store.ListIncidents(ctx, &storage.IncidentFilters{ParentIncidentID: "inc-parent"})
+	if err != nil {
+		t.Fatalf("ListIncidents() error = %v", err)
+	}
+	if len(children) != 1 || children[0].IncidentID != "inc-child" || children[0].ParentIncidentID != "inc-parent" {
+		t.Errorf("ListIncidents(parent) = %+v, want inc-child", children)
+	}
+
+	same, err := store.ListIncidents(ctx, &storage.IncidentFilters{ResourceKind: "Pod", ResourceName: "test-pod"})
+	if err != nil {
+		t.Fatalf("ListIncidents() error = %v", err)
+	}
+	if len(same) != 2 {
+		t.Errorf("ListIncidents(resource) returned %d incidents, want 2", len(same))
+	}
+	if other, _ := store.ListIncidents(ctx, &storage.IncidentFilters{ResourceKind: "Pod", ResourceName: "other-pod"}); len(other) != 0 {
+		t.Errorf("ListIncidents(other resource) = %+v, want none", other)
+	}
+}
+
+func TestIncidentDisplayIDs(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	for want := 1; want <= 3; want++ {
+		seq, err := store.NextIncidentSequence(ctx, "prod", "2024-06-13")
+		if err != nil {
+			t.Fatalf("NextIncidentSequence() error = %v", err)
+		}
+		if seq != want {
+			t.Errorf("NextIncidentSequence() = %d, want %d", seq, want)
+		}
+	}
+	// Sequences are per cluster and day
+	if seq, _ := store.NextIncidentSequence(ctx, "staging", "2024-06-13"); seq != 1 {
+		t.Errorf("NextIncidentSequence(other cluster) = %d, want 1", seq)
+	}
+	if seq, _ := store.NextIncidentSequence(ctx, "prod", "2024-06-14"); seq != 1 {
+		t.Errorf("NextIncidentSequence(next day) = %d, want 1", seq)
+	}
+
+	inc := createTestIncident("inc-display", createTestEvent("fault-display"))
+	inc.DisplayID = "NC-2024-0613-prod-0001"
+	if err := store.CreateIncident(ctx, inc, createTestEvent(inc.FaultID)); err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+	got, err := store.GetIncident(ctx, "NC-2024-0613-prod-0001")
+	if err != nil {
+		t.Fatalf("GetIncident(display ID) error = %v", err)
+	}
+	if got == nil || got.IncidentID != "inc-display" || got.DisplayID != inc.DisplayID {
+		t.Errorf("GetIncident(display ID) = %+v, want inc-display", got)
+	}
+
+	dup := createTestIncident("inc-dup", createTestEvent("fault-dup"))
+	dup.DisplayID = inc.DisplayID
+	if err := store.CreateIncident(ctx, dup, createTestEvent(dup.FaultID)); err == nil {
+		t.Error("CreateIncident() should reject a duplicate display ID")
+	}
+}
+
+func TestIncidentSource(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	alert := createTestIncident("inc-alert", createTestEvent("fault-alert"))
+	alert.Source = "alertmanager"
+	mcp := createTestIncident("inc-mcp", createTestEvent("fault-mcp"))
+	for _, inc := range []*incident.Incident{alert, mcp} {
+		if err := store.CreateIncident(ctx, inc, createTestEvent(inc.FaultID)); err != nil {
+			t.Fatalf("CreateIncident() error = %v", err)
+		}
+	}
+
+	if got, err := store.GetIncident(ctx, "inc-alert"); err != nil || got.Source != "alertmanager" {
+		t.Errorf("GetIncident() = %+v, %v, want source alertmanager", got, err)
+	}
+	if got, _ := store.GetIncident(ctx, "inc-mcp"); got.Source != "" {
+		t.Errorf("Source = %q, want none", got.Source)
+	}
+	found, err := store.ListIncidents(ctx, &storage.IncidentFilters{Source: "alertmanager"})
+	if err != nil {
+		t.Fatalf("ListIncidents() error = %v", err)
+	}
+	if len(found) != 1 || found[0].IncidentID != "inc-alert" || found[0].Source != "alertmanager" {
+		t.Errorf("ListIncidents(source) = %+v, want inc-alert", found)
+	}
+}
+
+func TestAgentResourceStats(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	record := func(id, image string, startedAt time.Time, usage *storage.AgentResourceUsage) {
+		t.Helper()
+		event := createTestEvent("fault-" + id)
+		if err := store.CreateIncident(ctx, createTestIncident(id, event), event); err != nil {
+			t.Fatalf("CreateIncident() error = %v", err)
+		}
+		exec := &storage.AgentExecution{
+			ExecutionID: id,
+			IncidentID:  id,
+			StartedAt:   startedAt,
+			AgentCLI:    "claude",
+			AgentImage:  image,
+			AgentModel:  "sonnet",
+			// Recorded, but not part of the resource stats
+			AgentCLIVersion:  "1.0.30",
+			AgentImageDigest: "sha256:" + image,
+		}
+		if err := store.RecordAgentExecution(ctx, exec); err != nil {
+			t.Fatalf("RecordAgentExecution(start) error = %v", err)
+		}
+		// Usage is recorded on completion
+		exec.Resources = usage
+		if err := store.RecordAgentExecution(ctx, exec); err != nil {
+			t.Fatalf("RecordAgentExecution(completion) error = %v", err)
+		}
+	}
+
+	record("inc-v1-a", "agent:v1", now.Add(-time.Hour), &storage.AgentResourceUsage{CPUSeconds: 10, PeakRSSBytes: 100, PeakProcesses: 4, DiskWrittenBytes: 1000, Source: "cgroup"})
+	record("inc-v1-b", "agent:v1", now.Add(-time.Hour), &storage.AgentResourceUsage{CPUSeconds: 20, PeakRSSBytes: 300, PeakProcesses: 6, DiskWrittenBytes: 3000, Source: "cgroup"})
+	record("inc-v2", "agent:v2", now.Add(-time.Hour), &storage.AgentResourceUsage{CPUSeconds: 90, PeakRSSBytes: 900, PeakProcesses: 9, DiskWrittenBytes: 9000, Source: "proc"})
+	// Not sampled, and too old: both skipped
+	record("inc-unsampled", "agent:v1", now.Add(-time.Hour), nil)
+	record("inc-old", "agent:v1", now.Add(-48*time.Hour), &storage.AgentResourceUsage{CPUSeconds: 500, Source: "cgroup"})
+
+	stats, err := store.AgentResourceStats(ctx, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("AgentResourceStats() error = %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("AgentResourceStats() returned %d agents, want 2: %+v", len(stats), stats)
+	}
+
+	// Highest average CPU first
+	if stats[0].AgentImage != "agent:v2" || stats[0].Executions != 1 || stats[0].AvgCPUSeconds != 90 {
+		t.Errorf("stats[0] = %+v, want agent:v2 with 1 execution and 90 CPU seconds", stats[0])
+	}
+	v1 := stats[1]
+	if v1.AgentImage != "agent:v1" || v1.AgentCLI != "claude" || v1.AgentModel != "sonnet" {
+		t.Errorf("stats[1] identifies %s/%s/%s, want claude/agent:v1/sonnet", v1.AgentCLI, v1.AgentImage, v1.AgentModel)
+	}
+	if v1.Executions != 2 || v1.AvgCPUSeconds != 15 || v1.MaxCPUSeconds != 20 {
+		t.Errorf("stats[1] CPU = %d executions, avg %v, max %v; want 2, 15, 20", v1.Executions, v1.AvgCPUSeconds, v1.MaxCPUSeconds)
+	}
+	if v1.AvgPeakRSSBytes != 200 || v1.MaxPeakRSSBytes != 300 || v1.MaxPeakProcesses != 6 {
+		t.Errorf("stats[1] memory = avg %d, max %d, procs %d; want 200, 300, 6", v1.AvgPeakRSSBytes, v1.MaxPeakRSSBytes, v1.MaxPeakProcesses)
+	}
+	if v1.AvgDiskWrittenBytes != 2000 || v1.MaxDiskWrittenBytes != 3000 {
+		t.Errorf("stats[1] disk = avg %d, max %d; want 2000, 3000", v1.AvgDiskWrittenBytes, v1.MaxDiskWrittenBytes)
+	}
+}
+
+// TestSilences verifies creating, listing, expiring, and lifting silences.
+func TestSilences(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	for _, silence := range []*storage.Silence{
+		{SilenceID: "sil-long", Namespace: "payments", Reason: "migration", CreatedBy: "slack:U1", CreatedAt: now, ExpiresAt: now.Add(2 * time.Hour)},
+		{SilenceID: "sil-short", Cluster: "prod", Namespace: "search", CreatedBy: "slack:U2", CreatedAt: now, ExpiresAt: now.Add(30 * time.Minute)},
+		{SilenceID: "sil-expired", Namespace: "web", CreatedBy: "slack:U1", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+	} {
+		if err := store.CreateSilence(ctx, silence); err != nil {
+			t.Fatalf("CreateSilence(%s) error = %v", silence.SilenceID, err)
+		}
+	}
+
+	silences, err := store.ListSilences(ctx, now)
+	if err != nil {
+		t.Fatalf("ListSilences() error = %v", err)
+	}
+	if len(silences) != 2 || silences[0].SilenceID != "sil-short" || silences[1].SilenceID != "sil-long" {
+		t.Fatalf("ListSilences() = %+v, want the two active silences, soonest expiry first", silences)
+	}
+	if silences[0].Cluster != "prod" || silences[1].Reason != "migration" || !silences[1].ExpiresAt.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("ListSilences() = %+v, %+v, want the stored fields", silences[0], silences[1])
+	}
+
+	if err := store.LiftSilence(ctx, "sil-long", "slack:U3", now.Add(time.Minute)); err != nil {
+		t.Fatalf("LiftSilence() error = %v", err)
+	}
+	if err := store.LiftSilence(ctx, "sil-long", "slack:U3", now.Add(time.Minute)); err == nil {
+		t.Error("LiftSilence() should fail for a lifted silence")
+	}
+	if err := store.LiftSilence(ctx, "sil-expired", "slack:U3", now); err == nil {
+		t.Error("LiftSilence() should fail for an expired silence")
+	}
+
+	silences, err = store.ListSilences(ctx, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("ListSilences() error = %v", err)
+	}
+	if len(silences) != 1 || silences[0].SilenceID != "sil-short" {
+		t.Errorf("ListSilences() after lift = %+v, want only sil-short", silences)
+	}
+}
+
+// TestSharedState verifies compare-and-swap updates and pruning of shared state.
+func TestSharedState(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	state, err := store.GetSharedState(ctx, "dedup/abc")
+	if err != nil || state != nil {
+		t.Fatalf("GetSharedState() of a missing key = %+v, %v, want nil", state, err)
+	}
+
+	if ok, err := store.SwapSharedState(ctx, "dedup/abc", 0, []byte(`{"n":1}`), now.Add(time.Hour)); err != nil || !ok {
+		t.Fatalf("SwapSharedState() insert = %v, %v, want true", ok, err)
+	}
+	if ok, err := store.SwapSharedState(ctx, "dedup/abc", 0, []byte(`{"n":9}`), now.Add(time.Hour)); err != nil || ok {
+		t.Fatalf("SwapSharedState() second insert = %v, %v, want false", ok, err)
+	}
+
+	state, err = store.GetSharedState(ctx, "dedup/abc")
+	if err != nil {
+		t.Fatalf("GetSharedState() error = %v", err)
+	}
+	if string(state.Value) != `{"n":1}` || state.Version != 1 || !state.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("GetSharedState() = %+v, want the inserted state at version 1", state)
+	}
+
+	if ok, err := store.SwapSharedState(ctx, "dedup/abc", 1, []byte(`{"n":2}`), now.Add(2*time.Hour)); err != nil || !ok {
+		t.Fatalf("SwapSharedState() update = %v, %v, want true", ok, err)
+	}
+	if ok, err := store.SwapSharedState(ctx, "dedup/abc", 1, []byte(`{"n":3}`), now.Add(2*time.Hour)); err != nil || ok {
+		t.Fatalf("SwapSharedState() with a stale version = %v, %v, want false", ok, err)
+	}
+	if state, _ := store.GetSharedState(ctx, "dedup/abc"); string(state.Value) != `{"n":2}` || state.Version != 2 {
+		t.Errorf("GetSharedState() after update = %+v, want value n=2 at version 2", state)
+	}
+
+	if ok, err := store.SwapSharedState(ctx, "budget/old", 0, []byte(`{}`), now.Add(-time.Minute)); err != nil || !ok {
+		t.Fatalf("SwapSharedState() expired insert = %v, %v", ok, err)
+	}
+	pruned, err := store.PruneSharedState(ctx, now)
+	if err != nil || pruned != 1 {
+		t.Fatalf("PruneSharedState() = %d, %v, want 1", pruned, err)
+	}
+	if state, _ := store.GetSharedState(ctx, "budget/old"); state != nil {
+		t.Errorf("GetSharedState() after prune = %+v, want nil", state)
+	}
+}
+
+func TestIncidentHistory(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	inc := createTestIncident("inc-history", createTestEvent("fault-history"))
+	inc.CreatedAt = time.Now().Add(-time.Minute)
+	inc.AddLabels(map[string]string{"team": "platform"}, nil)
+	if err := store.CreateIncident(ctx, inc, createTestEvent(inc.FaultID)); err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+	created := time.Now()
+
+	// Reassign the incident and start investigating it
+	if err := store.SetIncidentLabels(ctx, "inc-history", map[string]string{"team": "payments"}, map[string]string{"note": "paged"}); err != nil {
+		t.Fatalf("SetIncidentLabels() error = %v", err)
+	}
+	if err := store.UpdateIncidentStatus(ctx, "inc-history", incident.StatusInvestigating, &created); err != nil {
+		t.Fatalf("UpdateIncidentStatus() error = %v", err)
+	}
+	reassigned := time.Now()
+	if err := store.CompleteIncident(ctx, "inc-history", "", 1, "agent timed out", "timeout"); err != nil {
+		t.Fatalf("CompleteIncident() error = %v", err)
+	}
+	if err := store.RemoveIncidentLabels(ctx, "inc-history", []string{"team", "note"}); err != nil {
+		t.Fatalf("RemoveIncidentLabels() error = %v", err)
+	}
+
+	changes, err := store.IncidentHistory(ctx, "inc-history")
+	if err != nil {
+		t.Fatalf("IncidentHistory() error = %v", err)
+	}
+	var fields []string
+	for _, change := range changes {
+		fields = append(fields, change.Field+"="+change.NewValue)
+	}
+	want := []string{"status=received", "label:team=platform", "label:team=payments", "status=investigating", "status=failed", "failure_class=timeout", "label:team="}
+	if len(changes) != len(want) {
+		t.Fatalf("IncidentHistory() = %v, want %v", fields, want)
+	}
+	// Changes made at the same time (creation, completion) may come in any order
+	for _, w := range want {
+		found := false
+		for _, f := range fields {
+			found = found || f == w
+		}
+		if !found {
+			t.Errorf("IncidentHistory() = %v, missing %s", fields, w)
+		}
+	}
+	if last := changes[len(changes)-1]; last.Field != "label:team" || last.OldValue != "payments" || last.NewValue != "" {
+		t.Errorf("last change = %+v, want the removal of the team label", last)
+	}
+
+	if state := storage.IncidentStateAt(changes, inc.CreatedAt.Add(-time.Second)); state != nil {
+		t.Errorf("state before creation = %+v, want nil", state)
+	}
+	state := storage.IncidentStateAt(changes, created)
+	if state == nil || state.Status != incident.StatusReceived || state.Assignment != "platform" {
+		t.Errorf("state after creation = %+v, want received and assigned to platform", state)
+	}
+	state = storage.IncidentStateAt(changes, reassigned)
+	if state == nil || state.Status != incident.StatusInvestigating || state.Assignment != "payments" {
+		t.Errorf("state after reassignment = %+v, want investigating and assigned to payments", state)
+	}
+	state = storage.IncidentStateAt(changes, time.Now())
+	if state == nil || state.Status != incident.StatusFailed || state.FailureClass != "timeout" || state.Assignment != "" {
+		t.Errorf("current state = %+v, want failed (timeout) and unassigned", state)
+	}
+
+	changes, err = store.IncidentHistory(ctx, "inc-missing")
+	if err != nil || len(changes) != 0 {
+		t.Errorf("IncidentHistory(unknown) = %v, %v, want no changes", changes, err)
+	}
+}
+
+func TestIncidentChangesSince(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	inc := createTestIncident("inc-changes", createTestEvent("fault-changes"))
+	inc.CreatedAt = time.Now().Add(-time.Hour)
+	if err := store.CreateIncident(ctx, inc, createTestEvent(inc.FaultID)); err != nil {
+		t.Fatalf("CreateIncident() error = %v", err)
+	}
+	since := time.Now()
+	if err := store.SetIncidentLabels(ctx, "inc-changes", map[string]string{"team": "payments"}, nil); err != nil {
+		t.Fatalf("SetIncidentLabels() error = %v", err)
+	}
+	if err := store.CompleteIncident(ctx, "inc-changes", "", 0, "", ""); err != nil {
+		t.Fatalf("CompleteIncident() error = %v", err)
+	}
+
+	changes, err := store.IncidentChangesSince(ctx, since, 10)
+	if err != nil {
+		t.Fatalf("IncidentChangesSince() error = %v", err)
+	}
+	if len(changes) != 2 || changes[0].NewValue != "payments" || changes[1].NewValue != incident.StatusResolved {
+		t.Fatalf("IncidentChangesSince() = %+v, want the two changes after creation", changes)
+	}
+	if changes[0].ID == "" || changes[0].ID == changes[1].ID {
+		t.Errorf("changes have IDs %q and %q, want distinct IDs", changes[0].ID, changes[1].ID)
+	}
+
+	if limited, err := store.IncidentChangesSince(ctx, inc.CreatedAt, 1); err != nil || len(limited) != 1 || limited[0].NewValue != incident.StatusReceived {
+		t.Errorf("IncidentChangesSince(limit 1) = %+v, %v; want the creation", limited, err)
+	}
+}
+func TestInvestigationReviews(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	for _, id := range []string{"inc-review-1", "inc-review-2"} {
+		inc := createTestIncident(id, createTestEvent("fault-"+id))
+		if err := store.CreateIncident(ctx, inc, createTestEvent(inc.FaultID)); err != nil {
+			t.Fatalf("CreateIncident() error = %v", err)
+		}
+		exec := &storage.AgentExecution{
+			ExecutionID: id, IncidentID: id, StartedAt: time.Now(),
+			AgentModel: "sonnet", PromptVersion: "sha256:abc",
+		}
+		if err := store.RecordAgentExecution(ctx, exec); err != nil {
+			t.Fatalf("RecordAgentExecution() error = %v", err)
+		}
+	}
+
+	now := time.Now()
+	reviews := []*storage.InvestigationReview{
+		{ReviewID: "r1", IncidentID: "inc-review-1", Reviewer: "alice", CreatedAt: now.Add(-3 * time.Minute), Verdict: storage.VerdictInaccurate},
+		{ReviewID: "r2", IncidentID: "inc-review-1", Reviewer: "bob", CreatedAt: now.Add(-2 * time.Minute), Comment: "Missed the OOM kill"},
+		{ReviewID: "r3", IncidentID: "inc-review-1", Reviewer: "alice", CreatedAt: now.Add(-time.Minute), Verdict: storage.VerdictPartiallyAccurate},
+		{ReviewID: "r4", IncidentID: "inc-review-2", Reviewer: "bob", CreatedAt: now, Verdict: storage.VerdictAccurate},
+	}
+	for _, review := range reviews {
+		if err := store.AddInvestigationReview(ctx, review); err != nil {
+			t.Fatalf("AddInvestigationReview() error = %v", err)
+		}
+	}
+	if err := store.AddInvestigationReview(ctx, &storage.InvestigationReview{ReviewID: "r5", IncidentID: "missing", Reviewer: "bob", CreatedAt: now}); err == nil {
+		t.Error("AddInvestigationReview() for a missing incident succeeded, want error")
+	}
+
+	got, err := store.InvestigationReviews(ctx, "inc-review-1")
+	if err != nil {
+		t.Fatalf("InvestigationReviews() error = %v", err)
+	}
+	if len(got) != 3 || got[0].ReviewID != "r1" || got[1].Comment != "Missed the OOM kill" || got[1].Verdict != "" {
+		t.Errorf("InvestigationReviews() = %+v, want r1, r2, r3 in order", got)
+	}
+
+	// Only the latest verdict on each incident counts
+	stats, err := store.ReviewAccuracy(ctx, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ReviewAccuracy() error = %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("ReviewAccuracy() = %d stats, want 1", len(stats))
+	}
+	if st := stats[0]; st.AgentModel != "sonnet" || st.PromptVersion != "sha256:abc" || st.Reviewed != 2 || st.Inaccurate != 0 || st.Accuracy != 0.75 {
+		t.Errorf("ReviewAccuracy() = %+v, want 2 reviewed scoring 0.75", st)
+	}
+}
@@ -19,33 +19,149 @@ type StateStore interface {
 	CreateIncident(ctx context.Context, inc *incident.Incident, event *events.FaultEvent) error
 
 	// UpdateIncidentStatus updates the status of an existing incident.
-	// This is called during state transitions (pending -> investigating, investigating -> resolved, etc.).
-	// The startedAt timestamp is set when transitioning to investigating status.
+	// This is called during state transitions (received -> queued, queued -> investigating, etc.).
+	// The startedAt timestamp is set when transitioning to investigating status; nil keeps it.
+	// Changes the incident lifecycle does not allow fail with incident.ErrInvalidTransition.
 	UpdateIncidentStatus(ctx context.Context, incidentID string, status string, startedAt *time.Time) error
 
 	// CompleteIncident marks an incident as complete with final result information.
 	// This is called when the agent finishes execution (success or failure).
-	// Records the exit code, completion time, and any failure reason.
-	CompleteIncident(ctx context.Context, incidentID string, exitCode int, failureReason string) error
+	// Records the final status (empty: resolved for exit code 0, otherwise failed),
+	// the exit code, completion time, and any failure reason and its canonical class
+	// (see the failures package). The status change is validated like UpdateIncidentStatus.
+	CompleteIncident(ctx context.Context, incidentID, status string, exitCode int, failureReason, failureClass string) error
 
 	// RecordAgentExecution records details of an agent execution attempt.
 	// This is called when starting and completing agent execution.
 	// Links the execution to its parent incident.
 	RecordAgentExecution(ctx context.Context, exec *AgentExecution) error
 
+	// AgentResourceStats aggregates the resource usage of the agent executions
+	// started since the given time, per agent CLI, image, and model, ordered by
+	// average CPU time (highest first). Executions without sampled usage are skipped.
+	AgentResourceStats(ctx context.Context, since time.Time) ([]*AgentResourceStats, error)
+
 	// RecordTriageReport stores the investigation report generated by the agent.
 	// This is called after the agent produces investigation.md output.
 	// The report content is stored in markdown format.
 	RecordTriageReport(ctx context.Context, report *TriageReport) error
 
-	// GetIncident retrieves an incident by its ID (optional for initial implementation).
+	// GetTriageReport returns the most recent investigation report of an incident,
+	// or nil when the incident has no report.
+	// Follow-up investigations use it to quote the previous root cause.
+	GetTriageReport(ctx context.Context, incidentID string) (*TriageReport, error)
+
+	// NextIncidentSequence allocates the next sequence number of a cluster's
+	// incidents on a day (YYYY-MM-DD), starting at 1. It is used to build readable
+	// display IDs and is safe to call from concurrent processes.
+	NextIncidentSequence(ctx context.Context, cluster, day string) (int, error)
+
+	// GetIncident retrieves an incident by its ID or display ID (optional for initial implementation).
 	// This supports future query and dashboard features.
 	GetIncident(ctx context.Context, incidentID string) (*incident.Incident, error)
 
+	// GetIncidentBundle returns an incident, looked up by its ID or display ID, with
+	// its agent executions and triage reports, oldest first, in one call.
+	// Dashboards and the CLI use it to show a full incident page without a query
+	// per execution and report. Returns nil if the incident is not found.
+	GetIncidentBundle(ctx context.Context, incidentID string) (*IncidentBundle, error)
+
 	// ListIncidents returns incidents matching the provided filters (optional for initial implementation).
 	// This supports future query and dashboard features.
 	ListIncidents(ctx context.Context, filters *IncidentFilters) ([]*incident.Incident, error)
 
+	// SetIncidentLabels adds labels and annotations to an existing incident,
+	// replacing the values of keys it already has.
+	// This is called for labels attached after creation (agent findings, manual edits).
+	SetIncidentLabels(ctx context.Context, incidentID string, labels, annotations map[string]string) error
+
+	// RemoveIncidentLabels removes the labels and annotations with the given keys
+	// from an incident. Keys the incident does not have are ignored.
+	RemoveIncidentLabels(ctx context.Context, incidentID string, keys []string) error
+
+	// IncidentHistory returns the recorded changes of an incident's status,
+	// failure class, and labels, oldest first. CreateIncident, UpdateIncidentStatus,
+	// CompleteIncident, SetIncidentLabels, and RemoveIncidentLabels record them in
+	// the same transaction as the change. Replay them with IncidentStateAt.
+	IncidentHistory(ctx context.Context, incidentID string) ([]*IncidentChange, error)
+
+	// IncidentChangesSince returns up to limit incident changes recorded at or after
+	// the given time, by any process sharing the store, oldest first. The incident
+	// feed polls it to follow the changes made by peers.
+	IncidentChangesSince(ctx context.Context, since time.Time, limit int) ([]*IncidentChange, error)
+
+	// AddInvestigationReview records a review comment and/or verdict on an
+	// incident's investigation. It returns an error when the incident does not
+	// exist.
+	AddInvestigationReview(ctx context.Context, review *InvestigationReview) error
+
+	// InvestigationReviews returns the reviews of an incident, oldest first.
+	InvestigationReviews(ctx context.Context, incidentID string) ([]*InvestigationReview, error)
+
+	// ReviewAccuracy aggregates the latest verdict on each incident reviewed since
+	// the given time per agent model and prompt version (see AggregateAccuracy).
+	ReviewAccuracy(ctx context.Context, since time.Time) ([]*ReviewAccuracy, error)
+
+	// RecordArtifactHashes records the SHA-256 hashes of an incident's stored
+	// artifacts, keyed by path relative to the incident's storage directory.
+	// This is called after every successful upload; re-uploads replace the hashes.
+	RecordArtifactHashes(ctx context.Context, incidentID string, hashes map[string]string) error
+
+	// GetArtifactHashes returns the recorded artifact hashes of an incident, or an
+	// empty map when none were recorded.
+	GetArtifactHashes(ctx context.Context, incidentID string) (map[string]string, error)
+
+	// PlaceLegalHold places a legal hold on an incident, exempting its workspace,
+	// artifacts, and rows from retention and garbage collection until released.
+	// Fails when the incident does not exist or is already under an active hold.
+	PlaceLegalHold(ctx context.Context, hold *LegalHold) error
+
+	// ReleaseLegalHold releases the active legal hold on an incident.
+	// Fails when the incident is not under an active hold.
+	ReleaseLegalHold(ctx context.Context, incidentID, releasedBy string, releasedAt time.Time) error
+
+	// ListLegalHolds returns legal holds, newest first. Released holds are
+	// included only when includeReleased is set.
+	// Retention and garbage collection must skip incidents with an active hold.
+	ListLegalHolds(ctx context.Context, includeReleased bool) ([]*LegalHold, error)
+
+	// CreateSilence records a silence suppressing the investigation of faults in a
+	// namespace until it expires.
+	CreateSilence(ctx context.Context, silence *Silence) error
+
+	// LiftSilence ends a silence before it expires.
+	// Fails when the silence does not exist or is no longer active at liftedAt.
+	LiftSilence(ctx context.Context, silenceID, liftedBy string, liftedAt time.Time) error
+
+	// ListSilences returns the silences active at the given time (not lifted and
+	// expiring after it), soonest expiry first.
+	ListSilences(ctx context.Context, activeAt time.Time) ([]*Silence, error)
+
+	// GetSharedState returns the shared limit state stored under key, or nil when
+	// none is stored. Expired state is returned as well; callers treat it as absent.
+	GetSharedState(ctx context.Context, key string) (*SharedState, error)
+
+	// SwapSharedState stores value under key if the stored version is still version
+	// (0: nothing stored), incrementing the version. It returns false when another
+	// process changed the state first, so the caller can re-read and retry.
+	SwapSharedState(ctx context.Context, key string, version int64, value []byte, expiresAt time.Time) (bool, error)
+
+	// PruneSharedState deletes the shared state that expired before the given time
+	// and returns the number of deleted keys.
+	PruneSharedState(ctx context.Context, before time.Time) (int, error)
+
+	// RecordRunStart records the startup of a nightcrier process.
+	// This is called once at startup, after the state store is initialized.
+	RecordRunStart(ctx context.Context, run *RunRecord) error
+
+	// RecordRunStop records the shutdown of a nightcrier process.
+	// This is called on exit with the reason the process stopped.
+	RecordRunStop(ctx context.Context, runID string, stoppedAt time.Time, exitReason string) error
+
+	// ListRuns returns the most recent run records, newest first.
+	// A limit of 0 returns all runs.
+	ListRuns(ctx context.Context, limit int) ([]*RunRecord, error)
+
 	// Close releases any resources held by the StateStore.
 	// Should be called during application shutdown.
 	Close() error
@@ -54,40 +170,212 @@ type StateStore interface {
 // AgentExecution represents a single agent execution attempt for an incident.
 type AgentExecution struct {
 	// ExecutionID is the unique identifier for this execution attempt
-	ExecutionID string
+	ExecutionID string `json:"execution_id"`
 	// IncidentID links this execution to its parent incident
-	IncidentID string
+	IncidentID string `json:"incident_id"`
 	// StartedAt is when the agent execution began
-	StartedAt time.Time
+	StartedAt time.Time `json:"started_at"`
 	// CompletedAt is when the agent execution finished (nil if still running)
-	CompletedAt *time.Time
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
 	// ExitCode is the agent process exit code (nil if still running)
-	ExitCode *int
+	ExitCode *int `json:"exit_code,omitempty"`
 	// ErrorMessage contains any error that occurred during execution
-	ErrorMessage string
+	ErrorMessage string `json:"error_message,omitempty"`
 	// LogPaths contains filesystem paths to log files
-	LogPaths map[string]string
+	LogPaths map[string]string `json:"log_paths,omitempty"`
+	// AgentCLI, AgentImage, and AgentModel identify the agent that ran
+	AgentCLI   string `json:"agent_cli,omitempty"`
+	AgentImage string `json:"agent_image,omitempty"`
+	AgentModel string `json:"agent_model,omitempty"`
+	// PromptVersion identifies the configured prompts the agent ran with (see
+	// agent.PromptVersion)
+	PromptVersion string `json:"prompt_version,omitempty"`
+	// AgentCLIVersion and AgentImageDigest are the CLI version and image digest
+	// read from the agent image (empty when they could not be read)
+	AgentCLIVersion  string `json:"agent_cli_version,omitempty"`
+	AgentImageDigest string `json:"agent_image_digest,omitempty"`
+	// Resources is the sampled resource usage of the agent (nil while running or
+	// when it was not sampled)
+	Resources *AgentResourceUsage `json:"resources,omitempty"`
+}
+
+// AgentResourceUsage is the resource consumption of an agent execution.
+type AgentResourceUsage struct {
+	// CPUSeconds is the user and system CPU time consumed
+	CPUSeconds float64 `json:"cpu_seconds"`
+	// PeakRSSBytes is the highest resident memory observed
+	PeakRSSBytes int64 `json:"peak_rss_bytes"`
+	// PeakProcesses is the highest number of processes observed
+	PeakProcesses int `json:"peak_processes"`
+	// DiskWrittenBytes is the number of bytes written to storage
+	DiskWrittenBytes int64 `json:"disk_written_bytes"`
+	// Source is where the usage was read from ("cgroup" or "proc")
+	Source string `json:"source"`
+}
+
+// AgentResourceStats aggregates the resource usage of the executions of one agent
+// (CLI, image, and model), so a resource-hungry agent version stands out against
+// the others.
+type AgentResourceStats struct {
+	AgentCLI   string `json:"agent_cli"`
+	AgentImage string `json:"agent_image"`
+	AgentModel string `json:"agent_model"`
+	// Executions is the number of executions with sampled resource usage
+	Executions int `json:"executions"`
+	// AvgCPUSeconds and MaxCPUSeconds summarize the CPU time per execution
+	AvgCPUSeconds float64 `json:"avg_cpu_seconds"`
+	MaxCPUSeconds float64 `json:"max_cpu_seconds"`
+	// AvgPeakRSSBytes and MaxPeakRSSBytes summarize the peak memory per execution
+	AvgPeakRSSBytes int64 `json:"avg_peak_rss_bytes"`
+	MaxPeakRSSBytes int64 `json:"max_peak_rss_bytes"`
+	// MaxPeakProcesses is the highest process count of any execution
+	MaxPeakProcesses int `json:"max_peak_processes"`
+	// AvgDiskWrittenBytes and MaxDiskWrittenBytes summarize the disk writes per
+	// execution
+	AvgDiskWrittenBytes int64 `json:"avg_disk_written_bytes"`
+	MaxDiskWrittenBytes int64 `json:"max_disk_written_bytes"`
 }
 
 // TriageReport represents the investigation report generated by the agent.
 type TriageReport struct {
 	// ReportID is the unique identifier for this report
-	ReportID string
+	ReportID string `json:"report_id"`
 	// IncidentID links this report to its parent incident
-	IncidentID string
+	IncidentID string `json:"incident_id"`
 	// ExecutionID links this report to the agent execution that generated it
-	ExecutionID string
+	ExecutionID string `json:"execution_id"`
 	// GeneratedAt is when the report was created
-	GeneratedAt time.Time
+	GeneratedAt time.Time `json:"generated_at"`
 	// ReportMarkdown is the full investigation report in markdown format
-	ReportMarkdown string
+	ReportMarkdown string `json:"report_markdown"`
 	// ReportHTML is the HTML-rendered version of the report (optional)
-	ReportHTML string
+	ReportHTML string `json:"report_html,omitempty"`
+}
+
+// IncidentBundle is an incident with everything recorded about its
+// investigation, as shown on a full incident page.
+type IncidentBundle struct {
+	// Incident is the incident, with its labels and annotations
+	Incident *incident.Incident `json:"incident"`
+	// Executions holds the agent execution attempts, oldest first
+	Executions []*AgentExecution `json:"executions"`
+	// Reports holds the triage reports, oldest first
+	Reports []*TriageReport `json:"reports"`
+}
+
+// RunRecord represents one nightcrier process lifetime, from startup to shutdown.
+// Consecutive records show when nightcrier restarted and whether its version or
+// configuration changed in between.
+type RunRecord struct {
+	// RunID is the unique identifier for this run
+	RunID string
+	// StartedAt is when the process started
+	StartedAt time.Time
+	// StoppedAt is when the process shut down (nil while running, or if the
+	// process died without recording its shutdown)
+	StoppedAt *time.Time
+	// Version is the nightcrier release version
+	Version string
+	// GitCommit is the commit the binary was built from
+	GitCommit string
+	// ConfigHash is the SHA-256 fingerprint of the effective configuration
+	ConfigHash string
+	// ConfigSnapshot is the effective configuration with secrets redacted, keyed
+	// by config file key (nil for runs recorded before snapshots were kept)
+	ConfigSnapshot map[string]string
+	// Clusters lists the names of the monitored clusters
+	Clusters []string
+	// ArtifactStorage is the artifact storage backend (local_filesystem, azure_blob)
+	ArtifactStorage string
+	// StateStorage is the state storage backend (sqlite, postgres)
+	StateStorage string
+	// Hostname is the host the process ran on
+	Hostname string
+	// ExitReason is why the process stopped, e.g. "shutdown" or the fatal error
+	ExitReason string
+}
+
+// LegalHold is a legal hold placed on an incident.
+type LegalHold struct {
+	// HoldID is the unique identifier for this hold
+	HoldID string
+	// IncidentID is the incident under hold
+	IncidentID string
+	// Reason is why the hold was placed, e.g. the matter or ticket number
+	Reason string
+	// PlacedBy identifies who placed the hold
+	PlacedBy string
+	// PlacedAt is when the hold was placed
+	PlacedAt time.Time
+	// ReleasedAt is when the hold was released (nil while active)
+	ReleasedAt *time.Time
+	// ReleasedBy identifies who released the hold
+	ReleasedBy string
+}
+
+// Active reports whether the hold has not been released.
+func (h *LegalHold) Active() bool {
+	return h.ReleasedAt == nil
+}
+
+// Silence suppresses the investigation of fault events in a namespace, in one
+// cluster or in all clusters, until it expires or is lifted.
+type Silence struct {
+	// SilenceID is the unique identifier for this silence
+	SilenceID string
+	// Cluster limits the silence to one cluster (empty: all clusters)
+	Cluster string
+	// Namespace is the silenced Kubernetes namespace
+	Namespace string
+	// Reason is why the namespace was silenced (optional)
+	Reason string
+	// CreatedBy identifies who created the silence, e.g. "slack:U024BE7LH"
+	CreatedBy string
+	// CreatedAt is when the silence was created
+	CreatedAt time.Time
+	// ExpiresAt is when the silence ends
+	ExpiresAt time.Time
+	// LiftedAt is when the silence was lifted before expiring (nil otherwise)
+	LiftedAt *time.Time
+	// LiftedBy identifies who lifted the silence
+	LiftedBy string
+}
+
+// Active reports whether the silence is in effect at the given time.
+func (s *Silence) Active(now time.Time) bool {
+	return s.LiftedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Matches reports whether the silence covers a namespace of a cluster.
+func (s *Silence) Matches(cluster, namespace string) bool {
+	return s.Namespace == namespace && (s.Cluster == "" || s.Cluster == cluster)
+}
+
+// Target returns the silenced namespace as "ns/<namespace>", prefixed with
+// "<cluster>/" when the silence is limited to one cluster.
+func (s *Silence) Target() string {
+	if s.Cluster == "" {
+		return "ns/" + s.Namespace
+	}
+	return s.Cluster + "/ns/" + s.Namespace
+}
+
+// SharedState is limit state shared by every nightcrier process using the state
+// store, e.g. the dedup window of a fault signature or a cluster's budget usage.
+type SharedState struct {
+	// Key identifies the state, e.g. "dedup/<signature>"
+	Key string
+	// Value is the state as a JSON document
+	Value []byte
+	// Version increases with every update, for compare-and-swap
+	Version int64
+	// ExpiresAt is when the state may be pruned
+	ExpiresAt time.Time
 }
 
 // IncidentFilters defines filters for querying incidents.
 type IncidentFilters struct {
-	// Status filters by incident status (pending, investigating, resolved, failed)
+	// Status filters by incident status (see incident.Statuses)
 	Status []string
 	// Cluster filters by cluster name
 	Cluster string
@@ -97,6 +385,16 @@ type IncidentFilters struct {
 	FaultType string
 	// Severity filters by severity level
 	Severity string
+	// ResourceKind and ResourceName filter by the incident's resource
+	ResourceKind string
+	ResourceName string
+	// ParentIncidentID filters by the incident a follow-up incident is linked to
+	ParentIncidentID string
+	// Source filters by the event source whose fault event opened the incident
+	Source string
+	// Labels filters by incident labels; an incident must have every key with
+	// the given value
+	Labels map[string]string
 	// CreatedAfter filters incidents created after this time
 	CreatedAfter *time.Time
 	// CreatedBefore filters incidents created before this time
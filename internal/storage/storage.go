@@ -4,7 +4,10 @@ package storage
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
+
+	"github.com/rbias/nightcrier/internal/proxy"
 )
 
 // Storage defines the interface for persisting incident artifacts to local or cloud storage.
@@ -57,6 +60,9 @@ type SaveResult struct {
 	LogURLs map[string]string
 	// ExpiresAt is when the URLs expire (relevant for cloud storage with SAS tokens)
 	ExpiresAt time.Time
+	// Hashes maps the stored artifacts to the SHA-256 hashes of their contents,
+	// keyed by path relative to the incident's storage directory (see IncidentArtifacts.Files)
+	Hashes map[string]string
 }
 
 // StorageConfig represents the configuration needed to initialize storage backends.
@@ -79,6 +85,18 @@ type AzureConfig interface {
 	GetAzureSASExpiry() time.Duration
 }
 
+// AzureProxyConfig is optionally implemented by AzureConfig values that provide
+// proxy settings for Azure Blob Storage requests.
+type AzureProxyConfig interface {
+	GetAzureProxySettings() proxy.Settings
+}
+
+// AzureRetentionConfig is optionally implemented by AzureConfig values that tag
+// uploaded blobs with their artifact class, for lifecycle management rules.
+type AzureRetentionConfig interface {
+	TagAzureArtifactClasses() bool
+}
+
 // NewStorage creates and returns a Storage implementation based on the provided configuration.
 // It detects the storage mode (Azure, filesystem, etc.) from the configuration.
 // If AZURE_STORAGE_ACCOUNT or AZURE_STORAGE_CONNECTION_STRING is set, Azure storage is used.
@@ -96,6 +114,17 @@ func NewStorage(cfg StorageConfig) (Storage, error) {
 			return nil, fmt.Errorf("Azure storage enabled but config doesn't implement AzureConfig interface")
 		}
 
+		// Use proxy-aware HTTP client when proxy settings are available
+		var httpClient *http.Client
+		if proxyCfg, ok := cfg.(AzureProxyConfig); ok {
+			httpClient = proxy.NewHTTPClient(proxyCfg.GetAzureProxySettings())
+		}
+
+		var tagClasses bool
+		if retentionCfg, ok := cfg.(AzureRetentionConfig); ok {
+			tagClasses = retentionCfg.TagAzureArtifactClasses()
+		}
+
 		// Create Azure storage backend
 		azureStorage, err := NewAzureStorage(&AzureStorageConfig{
 			ConnectionString: azureCfg.GetAzureConnectionString(),
@@ -103,6 +132,9 @@ func NewStorage(cfg StorageConfig) (Storage, error) {
 			AccountKey:       azureCfg.GetAzureKey(),
 			Container:        azureCfg.GetAzureContainer(),
 			SASExpiry:        azureCfg.GetAzureSASExpiry(),
+			HTTPClient:       httpClient,
+
+			TagArtifactClasses: tagClasses,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to initialize Azure storage: %w", err)
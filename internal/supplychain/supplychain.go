@@ -0,0 +1,333 @@
+// Package supplychain verifies the agent image and skill bundles before they are
+// run: cosign signatures (key-based or keyless), that the signed digest is the one
+// that runs, and optionally the image's SLSA provenance attestation. Every
+// verification is appended to a JSON Lines audit log. Under the enforce policy a
+// failed verification rejects the artifact; under warn it is logged and audited
+// only.
+package supplychain
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy says what a failed verification does.
+type Policy string
+
+const (
+	// PolicyOff disables verification
+	PolicyOff Policy = "off"
+	// PolicyWarn logs and audits failed verifications but uses the artifact
+	PolicyWarn Policy = "warn"
+	// PolicyEnforce rejects artifacts that fail verification
+	PolicyEnforce Policy = "enforce"
+)
+
+// Kinds of verified artifacts
+const (
+	KindImage = "image"
+	KindSkill = "skill"
+)
+
+// Status is the outcome of one check.
+type Status string
+
+const (
+	StatusVerified Status = "verified"
+	StatusFailed   Status = "failed"
+	// StatusSkipped means the check does not apply, e.g. signatures of git bundles
+	StatusSkipped Status = "skipped"
+)
+
+// commitPattern matches a full git commit hash
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Config configures a verifier. Either Key or the certificate identity and issuer
+// (keyless signing) must be set.
+type Config struct {
+	Policy Policy
+	// CosignPath is the cosign binary. Default: "cosign" on the PATH.
+	CosignPath string
+	// Key is the public key (a file or a KMS URI) the artifacts are signed with
+	Key string
+	// CertificateIdentityRegexp and CertificateOIDCIssuer identify the keyless
+	// signer, e.g. a CI workflow and https://token.actions.githubusercontent.com
+	CertificateIdentityRegexp string
+	CertificateOIDCIssuer     string
+	// Provenance also verifies the image's SLSA provenance attestation
+	Provenance bool
+	// AuditLog is the JSON Lines file verification results are appended to
+	AuditLog string
+}
+
+// Result is the audit record of one verification.
+type Result struct {
+	Time       time.Time `json:"time"`
+	Kind       string    `json:"kind"`
+	Subject    string    `json:"subject"` // Image reference or skill bundle name
+	Digest     string    `json:"digest,omitempty"`
+	Signature  Status    `json:"signature"`
+	Provenance Status    `json:"provenance,omitempty"`
+	Policy     Policy    `json:"policy"`
+	// Allowed reports whether the artifact is used
+	Allowed bool   `json:"allowed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Verifier verifies artifacts with cosign. It is safe for concurrent use.
+type Verifier struct {
+	config Config
+	// run executes cosign and returns its standard output
+	run func(ctx context.Context, name string, args ...string) ([]byte, error)
+	mu  sync.Mutex // serializes audit log writes
+}
+
+// New returns a verifier.
+func New(config Config) *Verifier {
+	if config.CosignPath == "" {
+		config.CosignPath = "cosign"
+	}
+	return &Verifier{config: config, run: runCommand}
+}
+
+// VerifyImage verifies the signature of an image and, when configured, its SLSA
+// provenance. The result's Digest is the signed manifest digest; pin the image to
+// it (see PinDigest) so a tag moved after verification is not run. The returned
+// error is non-nil only when the enforce policy rejects the image.
+func (v *Verifier) VerifyImage(ctx context.Context, image string) (*Result, error) {
+	result := v.newResult(KindImage, image)
+	return result, v.finish(result, v.verifyImage(ctx, image, result))
+}
+
+func (v *Verifier) verifyImage(ctx context.Context, image string, result *Result) error {
+	out, err := v.run(ctx, v.config.CosignPath, append(append([]string{"verify", "--output", "json"}, v.identityArgs()...), image)...)
+	if err != nil {
+		result.Signature = StatusFailed
+		return fmt.Errorf("signature verification of %s failed: %w", image, err)
+	}
+	digest, err := signedDigest(out)
+	if err != nil {
+		result.Signature = StatusFailed
+		return err
+	}
+	result.Signature, result.Digest = StatusVerified, digest
+	if pinned := pinnedDigest(image); pinned != "" && pinned != digest {
+		return fmt.Errorf("image %s is pinned to %s but the signature covers %s", image, pinned, digest)
+	}
+
+	if v.config.Provenance {
+		args := append([]string{"verify-attestation", "--type", "slsaprovenance"}, v.identityArgs()...)
+		if _, err := v.run(ctx, v.config.CosignPath, append(args, PinDigest(image, digest))...); err != nil {
+			result.Provenance = StatusFailed
+			return fmt.Errorf("SLSA provenance verification of %s failed: %w", image, err)
+		}
+		result.Provenance = StatusVerified
+	}
+	return nil
+}
+
+// VerifyArchive verifies a downloaded skill bundle archive against its cosign
+// signature bundle (nil when none could be downloaded). The returned error is
+// non-nil only when the enforce policy rejects the archive.
+func (v *Verifier) VerifyArchive(ctx context.Context, name, archivePath string, signature []byte) error {
+	result := v.newResult(KindSkill, name)
+	return v.finish(result, v.verifyArchive(ctx, archivePath, signature, result))
+}
+
+func (v *Verifier) verifyArchive(ctx context.Context, archivePath string, signature []byte, result *Result) error {
+	digest, err := fileDigest(archivePath)
+	if err != nil {
+		result.Signature = StatusFailed
+		return err
+	}
+	result.Digest = digest
+	if len(signature) == 0 {
+		result.Signature = StatusFailed
+		return fmt.Errorf("no signature bundle was found for the archive")
+	}
+
+	sigFile, err := os.CreateTemp(filepath.Dir(archivePath), ".signature-")
+	if err != nil {
+		result.Signature = StatusFailed
+		return fmt.Errorf("failed to create signature file: %w", err)
+	}
+	defer os.Remove(sigFile.Name())
+	_, err = sigFile.Write(signature)
+	if closeErr := sigFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		result.Signature = StatusFailed
+		return fmt.Errorf("failed to write signature file: %w", err)
+	}
+
+	args := append(append([]string{"verify-blob", "--bundle", sigFile.Name()}, v.identityArgs()...), archivePath)
+	if _, err := v.run(ctx, v.config.CosignPath, args...); err != nil {
+		result.Signature = StatusFailed
+		return fmt.Errorf("signature verification failed: %w", err)
+	}
+	result.Signature = StatusVerified
+	return nil
+}
+
+// VerifyCommit checks a git skill bundle checked out at commit. Git bundles carry
+// no cosign signature, so they are verified by their digest only: the manifest
+// must pin them to the commit (ref set to the full commit hash). The returned
+// error is non-nil only when the enforce policy rejects the bundle.
+func (v *Verifier) VerifyCommit(ctx context.Context, name, ref, commit string) error {
+	result := v.newResult(KindSkill, name)
+	result.Signature, result.Digest = StatusSkipped, "git:"+commit
+	var err error
+	if !commitPattern.MatchString(ref) || ref != commit {
+		err = fmt.Errorf("git bundle is not pinned to a commit (set its ref to a full commit hash)")
+	}
+	return v.finish(result, err)
+}
+
+// newResult starts the audit record of a verification.
+func (v *Verifier) newResult(kind, subject string) *Result {
+	return &Result{Time: time.Now().UTC(), Kind: kind, Subject: subject, Policy: v.config.Policy}
+}
+
+// finish applies the policy to a verification, logs and audits it, and returns
+// the error rejecting the artifact, if any.
+func (v *Verifier) finish(result *Result, err error) error {
+	result.Allowed = err == nil || v.config.Policy != PolicyEnforce
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if auditErr := v.audit(result); auditErr != nil {
+		slog.Error("failed to write supply chain audit record", "audit_log", v.config.AuditLog, "error", auditErr)
+	}
+
+	attrs := []any{"kind", result.Kind, "subject", result.Subject, "digest", result.Digest, "policy", result.Policy}
+	switch {
+	case err == nil:
+		slog.Info("supply chain verification passed", attrs...)
+		return nil
+	case result.Allowed:
+		slog.Warn("supply chain verification failed, using the artifact (policy warn)", append(attrs, "error", err)...)
+		return nil
+	}
+	slog.Error("supply chain verification failed, rejecting the artifact", append(attrs, "error", err)...)
+	return fmt.Errorf("supply chain policy rejected %s %s: %w", result.Kind, result.Subject, err)
+}
+
+// audit appends a result to the audit log.
+func (v *Verifier) audit(result *Result) error {
+	if v.config.AuditLog == "" {
+		return nil
+	}
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(v.config.AuditLog), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(v.config.AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// identityArgs returns the cosign arguments naming the trusted signer.
+func (v *Verifier) identityArgs() []string {
+	if v.config.Key != "" {
+		return []string{"--key", v.config.Key}
+	}
+	return []string{
+		"--certificate-identity-regexp", v.config.CertificateIdentityRegexp,
+		"--certificate-oidc-issuer", v.config.CertificateOIDCIssuer,
+	}
+}
+
+// signature is the part of a "cosign verify" payload naming the signed image.
+type signature struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// signedDigest returns the manifest digest covered by the signatures cosign
+// verified.
+func signedDigest(output []byte) (string, error) {
+	var signatures []signature
+	if err := json.Unmarshal(bytes.TrimSpace(output), &signatures); err != nil {
+		return "", fmt.Errorf("failed to parse cosign verify output: %w", err)
+	}
+	for _, s := range signatures {
+		if digest := s.Critical.Image.DockerManifestDigest; digest != "" {
+			return digest, nil
+		}
+	}
+	return "", fmt.Errorf("cosign verify output names no image digest")
+}
+
+// pinnedDigest returns the digest an image reference is pinned to, if any.
+func pinnedDigest(image string) string {
+	if _, digest, ok := strings.Cut(image, "@"); ok {
+		return digest
+	}
+	return ""
+}
+
+// PinDigest returns the image reference pinned to digest, dropping any tag, e.g.
+// "ghcr.io/org/agent@sha256:...".
+func PinDigest(image, digest string) string {
+	repository, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	return repository + "@" + digest
+}
+
+// fileDigest returns the "sha256:<hex>" digest of a file.
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash archive: %w", err)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// runCommand runs a command and returns its standard output; the error includes
+// its standard error.
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w: %s", filepath.Base(name), args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
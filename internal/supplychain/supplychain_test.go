@@ -0,0 +1,152 @@
+package supplychain
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testDigest = "sha256:4a5e8a2f2d1c7b3e9f0a6d5c4b3a29181716151413121110f0e0d0c0b0a09080"
+
+// fakeCosign answers cosign subcommands from results keyed by subcommand and
+// records the arguments it was called with.
+type fakeCosign struct {
+	outputs map[string]string
+	errs    map[string]error
+	calls   [][]string
+}
+
+func (f *fakeCosign) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, args)
+	if err := f.errs[args[0]]; err != nil {
+		return nil, err
+	}
+	return []byte(f.outputs[args[0]]), nil
+}
+
+func newTestVerifier(t *testing.T, config Config, cosign *fakeCosign) *Verifier {
+	t.Helper()
+	config.AuditLog = filepath.Join(t.TempDir(), "audit.jsonl")
+	v := New(config)
+	v.run = cosign.run
+	return v
+}
+
+// readAudit returns the records of a verifier's audit log.
+func readAudit(t *testing.T, v *Verifier) []Result {
+	t.Helper()
+	f, err := os.Open(v.config.AuditLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var results []Result
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Result
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func TestVerifyImage(t *testing.T) {
+	cosign := &fakeCosign{outputs: map[string]string{
+		"verify": `[{"critical":{"identity":{"docker-reference":"ghcr.io/org/agent"},"image":{"docker-manifest-digest":"` + testDigest + `"},"type":"cosign container image signature"}}]`,
+	}}
+	v := newTestVerifier(t, Config{Policy: PolicyEnforce, Key: "cosign.pub", Provenance: true}, cosign)
+
+	result, err := v.VerifyImage(context.Background(), "ghcr.io/org/agent:1.4")
+	if err != nil {
+		t.Fatalf("VerifyImage() error = %v", err)
+	}
+	if result.Digest != testDigest || result.Signature != StatusVerified || result.Provenance != StatusVerified || !result.Allowed {
+		t.Errorf("VerifyImage() = %+v, want verified signature and provenance", result)
+	}
+	if got := strings.Join(cosign.calls[1], " "); got != "verify-attestation --type slsaprovenance --key cosign.pub ghcr.io/org/agent@"+testDigest {
+		t.Errorf("provenance verified with %q, want the pinned digest", got)
+	}
+
+	// A reference pinned to another digest than the signed one is rejected
+	if _, err := v.VerifyImage(context.Background(), "ghcr.io/org/agent@sha256:0000"); err == nil {
+		t.Error("VerifyImage() of a mismatched digest pin should fail under enforce")
+	}
+
+	audit := readAudit(t, v)
+	if len(audit) != 2 || !audit[0].Allowed || audit[1].Allowed || audit[1].Error == "" {
+		t.Errorf("audit log = %+v, want an allowed and a rejected record", audit)
+	}
+}
+
+func TestVerifyImage_WarnPolicy(t *testing.T) {
+	cosign := &fakeCosign{errs: map[string]error{"verify": errors.New("no matching signatures")}}
+	v := newTestVerifier(t, Config{Policy: PolicyWarn, CertificateIdentityRegexp: "^https://github.com/org/", CertificateOIDCIssuer: "https://token.actions.githubusercontent.com"}, cosign)
+
+	result, err := v.VerifyImage(context.Background(), "ghcr.io/org/agent:1.4")
+	if err != nil {
+		t.Fatalf("VerifyImage() under warn error = %v, want the image allowed", err)
+	}
+	if result.Signature != StatusFailed || !result.Allowed || !strings.Contains(result.Error, "no matching signatures") {
+		t.Errorf("VerifyImage() = %+v, want a failed but allowed result", result)
+	}
+	if got := strings.Join(cosign.calls[0], " "); !strings.Contains(got, "--certificate-identity-regexp ^https://github.com/org/ --certificate-oidc-issuer https://token.actions.githubusercontent.com") {
+		t.Errorf("cosign called with %q, want the keyless identity", got)
+	}
+}
+
+func TestVerifyArchive(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if err := os.WriteFile(archive, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cosign := &fakeCosign{}
+	v := newTestVerifier(t, Config{Policy: PolicyEnforce, Key: "cosign.pub"}, cosign)
+
+	if err := v.VerifyArchive(context.Background(), "aws-helpers", archive, []byte(`{"mediaType":"application/vnd.dev.sigstore.bundle+json"}`)); err != nil {
+		t.Fatalf("VerifyArchive() error = %v", err)
+	}
+	if args := cosign.calls[0]; args[0] != "verify-blob" || args[len(args)-1] != archive {
+		t.Errorf("cosign called with %v, want verify-blob of the archive", args)
+	}
+	if err := v.VerifyArchive(context.Background(), "aws-helpers", archive, nil); err == nil {
+		t.Error("VerifyArchive() without a signature should fail under enforce")
+	}
+
+	audit := readAudit(t, v)
+	if len(audit) != 2 || audit[0].Signature != StatusVerified || !strings.HasPrefix(audit[0].Digest, "sha256:") || audit[1].Signature != StatusFailed {
+		t.Errorf("audit log = %+v", audit)
+	}
+}
+
+func TestVerifyCommit(t *testing.T) {
+	v := newTestVerifier(t, Config{Policy: PolicyEnforce, Key: "cosign.pub"}, &fakeCosign{})
+	commit := "0123456789abcdef0123456789abcdef01234567"
+	if err := v.VerifyCommit(context.Background(), "k8s4agents", commit, commit); err != nil {
+		t.Errorf("VerifyCommit() of a pinned bundle error = %v", err)
+	}
+	if err := v.VerifyCommit(context.Background(), "k8s4agents", "main", commit); err == nil {
+		t.Error("VerifyCommit() of a branch should fail under enforce")
+	}
+}
+
+func TestPinDigest(t *testing.T) {
+	tests := map[string]string{
+		"ghcr.io/org/agent:1.4":         "ghcr.io/org/agent@" + testDigest,
+		"ghcr.io/org/agent":             "ghcr.io/org/agent@" + testDigest,
+		"registry:5000/agent:latest":    "registry:5000/agent@" + testDigest,
+		"ghcr.io/org/agent@sha256:0000": "ghcr.io/org/agent@" + testDigest,
+		"registry:5000/agent":           "registry:5000/agent@" + testDigest,
+	}
+	for image, want := range tests {
+		if got := PinDigest(image, testDigest); got != want {
+			t.Errorf("PinDigest(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
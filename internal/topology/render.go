@@ -0,0 +1,130 @@
+package topology
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+)
+
+// Diagram geometry, in pixels
+const (
+	boxWidth  = 180
+	boxHeight = 46
+	columnGap = 50
+	rowGap    = 14
+	padding   = 10
+	// maxLabelRunes truncates object names to fit their box
+	maxLabelRunes = 24
+)
+
+// columnOf places each kind in a column of the diagram, left to right from the
+// traffic entry point to the nodes. Other kinds share the workload column.
+var columnOf = map[string]int{
+	"Ingress":     0,
+	"Service":     1,
+	"Deployment":  2,
+	"StatefulSet": 2,
+	"DaemonSet":   2,
+	"CronJob":     2,
+	"ReplicaSet":  3,
+	"Job":         3,
+	"Pod":         4,
+	"Node":        5,
+}
+
+// workloadColumn is the column of kinds not in columnOf
+const workloadColumn = 2
+
+// box is the position of a node in the diagram.
+type box struct {
+	x, y int
+}
+
+// RenderSVG renders the graph as a standalone SVG diagram with one column per
+// layer (ingresses, services, workloads, replica sets, pods, nodes). The affected
+// resource is outlined and unhealthy objects are shaded. Every label is escaped,
+// so the diagram is safe to embed in an HTML page.
+func RenderSVG(g *Graph) []byte {
+	// Assign the non-empty layers to consecutive columns
+	var layers [6][]*Node
+	for _, n := range g.Nodes {
+		col, ok := columnOf[n.Kind]
+		if !ok {
+			col = workloadColumn
+		}
+		layers[col] = append(layers[col], n)
+	}
+	boxes := make(map[string]box, len(g.Nodes))
+	columns, rows := 0, 0
+	for _, layer := range layers {
+		if len(layer) == 0 {
+			continue
+		}
+		for row, n := range layer {
+			boxes[n.ID] = box{
+				x: padding + columns*(boxWidth+columnGap),
+				y: padding + row*(boxHeight+rowGap),
+			}
+		}
+		columns++
+		rows = max(rows, len(layer))
+	}
+	width := 2*padding + columns*boxWidth + max(columns-1, 0)*columnGap
+	height := 2*padding + rows*boxHeight + max(rows-1, 0)*rowGap
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="-apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Arial, sans-serif">`+"\n",
+		width, height, width, height)
+
+	// Edges first, so the boxes are drawn over them
+	for _, e := range g.Edges {
+		from, okFrom := boxes[e.From]
+		to, okTo := boxes[e.To]
+		if !okFrom || !okTo {
+			continue
+		}
+		if from.x > to.x {
+			from, to = to, from
+		}
+		x1, x2 := from.x+boxWidth, to.x
+		if from.x == to.x {
+			x1, x2 = from.x+boxWidth/2, to.x+boxWidth/2
+		}
+		fmt.Fprintf(&b, `  <line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#adb5bd" stroke-width="1.5"><title>%s</title></line>`+"\n",
+			x1, from.y+boxHeight/2, x2, to.y+boxHeight/2, html.EscapeString(e.Relation))
+	}
+
+	for _, n := range g.Nodes {
+		pos := boxes[n.ID]
+		fill, stroke, strokeWidth := "#ffffff", "#ced4da", "1"
+		if n.Unhealthy {
+			fill = "#fdecea"
+		}
+		if n.ID == g.Root {
+			stroke, strokeWidth = "#007bff", "2.5"
+		}
+		kind := n.Kind
+		if n.Status != "" {
+			kind += " · " + n.Status
+		}
+		fmt.Fprintf(&b, `  <g><title>%s</title>`+"\n", html.EscapeString(n.ID))
+		fmt.Fprintf(&b, `    <rect x="%d" y="%d" width="%d" height="%d" rx="4" fill="%s" stroke="%s" stroke-width="%s"/>`+"\n",
+			pos.x, pos.y, boxWidth, boxHeight, fill, stroke, strokeWidth)
+		fmt.Fprintf(&b, `    <text x="%d" y="%d" font-size="11" fill="#666">%s</text>`+"\n",
+			pos.x+8, pos.y+17, html.EscapeString(truncate(kind)))
+		fmt.Fprintf(&b, `    <text x="%d" y="%d" font-size="13" fill="#212529">%s</text>`+"\n",
+			pos.x+8, pos.y+35, html.EscapeString(truncate(n.Name)))
+		b.WriteString("  </g>\n")
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+// truncate shortens a label to fit its box.
+func truncate(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxLabelRunes {
+		return s
+	}
+	return string(runes[:maxLabelRunes-1]) + "…"
+}
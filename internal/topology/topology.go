@@ -0,0 +1,573 @@
+// Package topology collects the graph of Kubernetes objects around an incident's
+// affected resource: the pods it runs as, their owners up to the deployment, the
+// services selecting them, the ingresses routing to those services, and the nodes
+// the pods run on. The graph is written into the investigation workspace as
+// topology.json, rendered as an SVG diagram, and embedded in the HTML report, so
+// responders see where the failing object sits without running kubectl.
+package topology
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Workspace files written by Write
+const (
+	// JSONFile holds the graph
+	JSONFile = "topology.json"
+	// SVGFile holds the rendered diagram
+	SVGFile = "topology.svg"
+)
+
+// Edge relations
+const (
+	// RelationOwns links an owner to the object it controls (Deployment to
+	// ReplicaSet, ReplicaSet to Pod)
+	RelationOwns = "owns"
+	// RelationSelects links a Service to a Pod its selector matches
+	RelationSelects = "selects"
+	// RelationRoutesTo links an Ingress to a Service backend
+	RelationRoutesTo = "routes_to"
+	// RelationRunsOn links a Pod to the Node it is scheduled on
+	RelationRunsOn = "runs_on"
+)
+
+// defaultMaxPods bounds the pods of a workload in the graph when the collector
+// has no limit
+const defaultMaxPods = 5
+
+// maxOwnerDepth bounds the owner chain followed from an object
+const maxOwnerDepth = 4
+
+// Graph is the topology around an affected resource.
+type Graph struct {
+	// Root is the ID of the affected resource
+	Root        string    `json:"root"`
+	Nodes       []*Node   `json:"nodes"`
+	Edges       []Edge    `json:"edges"`
+	CollectedAt time.Time `json:"collected_at"`
+	// Warnings lists the parts of the topology that could not be collected, e.g.
+	// because RBAC does not allow listing ingresses
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Node is a Kubernetes object in the graph.
+type Node struct {
+	// ID is "<kind>/<namespace>/<name>", or "<kind>/<name>" for cluster-scoped
+	// objects
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Status summarizes the object's state: a pod's phase, a workload's ready
+	// replicas, or a node's readiness
+	Status string `json:"status,omitempty"`
+	// Unhealthy is set for failed or pending pods, workloads with fewer ready
+	// replicas than desired, and nodes that are not ready
+	Unhealthy bool `json:"unhealthy,omitempty"`
+}
+
+// Edge is a relation between two nodes of the graph.
+type Edge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"`
+}
+
+// Node returns the node with the given ID, or nil.
+func (g *Graph) Node(id string) *Node {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+// nodeID returns the ID of an object.
+func nodeID(kind, namespace, name string) string {
+	if namespace == "" {
+		return kind + "/" + name
+	}
+	return kind + "/" + namespace + "/" + name
+}
+
+// Target identifies the affected resource.
+type Target struct {
+	Namespace string
+	Kind      string
+	Name      string
+}
+
+// runFunc runs kubectl with the given arguments and returns its standard output.
+type runFunc func(ctx context.Context, args ...string) ([]byte, error)
+
+// Collector reads the topology with kubectl.
+type Collector struct {
+	run     runFunc
+	maxPods int
+}
+
+// NewCollector returns a collector reading the cluster with the given kubeconfig,
+// including up to maxPods pods of a workload (0: 5).
+func NewCollector(kubeconfig string, maxPods int) *Collector {
+	return newCollector(kubectlRunner(kubeconfig), maxPods)
+}
+
+func newCollector(run runFunc, maxPods int) *Collector {
+	if maxPods <= 0 {
+		maxPods = defaultMaxPods
+	}
+	return &Collector{run: run, maxPods: maxPods}
+}
+
+// kubectlRunner runs kubectl against the cluster of a kubeconfig.
+func kubectlRunner(kubeconfig string) runFunc {
+	return func(ctx context.Context, args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "kubectl", append([]string{"--kubeconfig", kubeconfig}, args...)...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("kubectl %s failed: %w (output: %s)", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+		}
+		return out, nil
+	}
+}
+
+// object holds the fields of a Kubernetes object the collector reads.
+type object struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name            string            `json:"name"`
+		Namespace       string            `json:"namespace"`
+		Labels          map[string]string `json:"labels"`
+		OwnerReferences []struct {
+			Kind       string `json:"kind"`
+			Name       string `json:"name"`
+			Controller bool   `json:"controller"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+	Spec struct {
+		// NodeName is the node a pod is scheduled on
+		NodeName string `json:"nodeName"`
+		// Selector is a service's label map, or a workload's label selector
+		Selector json.RawMessage `json:"selector"`
+		Replicas *int            `json:"replicas"`
+		// Rules and DefaultBackend are an ingress's backends
+		Rules []struct {
+			HTTP *struct {
+				Paths []struct {
+					Backend ingressBackend `json:"backend"`
+				} `json:"paths"`
+			} `json:"http"`
+		} `json:"rules"`
+		DefaultBackend *ingressBackend `json:"defaultBackend"`
+	} `json:"spec"`
+	Status struct {
+		Phase         string `json:"phase"`
+		ReadyReplicas int    `json:"readyReplicas"`
+		// DesiredNumberScheduled and NumberReady are a daemon set's replicas
+		DesiredNumberScheduled int `json:"desiredNumberScheduled"`
+		NumberReady            int `json:"numberReady"`
+		Conditions             []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+type ingressBackend struct {
+	Service *struct {
+		Name string `json:"name"`
+	} `json:"service"`
+}
+
+// serviceNames returns the services an ingress routes to.
+func (o *object) serviceNames() []string {
+	var names []string
+	add := func(b *ingressBackend) {
+		if b != nil && b.Service != nil && b.Service.Name != "" && !slices.Contains(names, b.Service.Name) {
+			names = append(names, b.Service.Name)
+		}
+	}
+	add(o.Spec.DefaultBackend)
+	for _, rule := range o.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			add(&path.Backend)
+		}
+	}
+	return names
+}
+
+// selector returns the labels a service or workload selects pods by, or nil.
+// Workload selectors with match expressions are reduced to their match labels.
+func (o *object) selector() map[string]string {
+	if len(o.Spec.Selector) == 0 {
+		return nil
+	}
+	if o.Kind == "Service" {
+		var labels map[string]string
+		json.Unmarshal(o.Spec.Selector, &labels)
+		return labels
+	}
+	var ls struct {
+		MatchLabels map[string]string `json:"matchLabels"`
+	}
+	json.Unmarshal(o.Spec.Selector, &ls)
+	return ls.MatchLabels
+}
+
+// node returns the graph node of the object, with its status.
+func (o *object) node() *Node {
+	n := &Node{
+		ID:        nodeID(o.Kind, o.Metadata.Namespace, o.Metadata.Name),
+		Kind:      o.Kind,
+		Namespace: o.Metadata.Namespace,
+		Name:      o.Metadata.Name,
+	}
+	switch o.Kind {
+	case "Pod":
+		n.Status = o.Status.Phase
+		n.Unhealthy = o.Status.Phase != "Running" && o.Status.Phase != "Succeeded"
+	case "Deployment", "ReplicaSet", "StatefulSet":
+		desired := 1
+		if o.Spec.Replicas != nil {
+			desired = *o.Spec.Replicas
+		}
+		n.Status = fmt.Sprintf("%d/%d ready", o.Status.ReadyReplicas, desired)
+		n.Unhealthy = o.Status.ReadyReplicas < desired
+	case "DaemonSet":
+		n.Status = fmt.Sprintf("%d/%d ready", o.Status.NumberReady, o.Status.DesiredNumberScheduled)
+		n.Unhealthy = o.Status.NumberReady < o.Status.DesiredNumberScheduled
+	case "Node":
+		n.Status = "NotReady"
+		for _, c := range o.Status.Conditions {
+			if c.Type == "Ready" && c.Status == "True" {
+				n.Status = "Ready"
+			}
+		}
+		n.Unhealthy = n.Status != "Ready"
+	}
+	return n
+}
+
+// matches reports whether every selector label is set on labels.
+func matches(selector, labels map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// labelSelector formats a selector for kubectl -l.
+func labelSelector(selector map[string]string) string {
+	pairs := make([]string, 0, len(selector))
+	for k, v := range selector {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// builder accumulates the graph while the collector walks the cluster.
+type builder struct {
+	graph *Graph
+	edges map[Edge]bool
+}
+
+func (b *builder) addNode(n *Node) {
+	if b.graph.Node(n.ID) == nil {
+		b.graph.Nodes = append(b.graph.Nodes, n)
+	}
+}
+
+func (b *builder) addEdge(from, to, relation string) {
+	e := Edge{From: from, To: to, Relation: relation}
+	if !b.edges[e] {
+		b.edges[e] = true
+		b.graph.Edges = append(b.graph.Edges, e)
+	}
+}
+
+func (b *builder) warn(format string, args ...interface{}) {
+	b.graph.Warnings = append(b.graph.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Collect builds the topology around the target. Only a failure to read the
+// target itself is an error; parts of the topology that cannot be read are
+// recorded as warnings of the graph.
+func (c *Collector) Collect(ctx context.Context, target Target) (*Graph, error) {
+	root, err := c.get(ctx, target.Kind, target.Namespace, target.Name)
+	if err != nil {
+		return nil, err
+	}
+	b := &builder{graph: &Graph{CollectedAt: time.Now().UTC()}, edges: make(map[Edge]bool)}
+	rootNode := root.node()
+	b.graph.Root = rootNode.ID
+	b.addNode(rootNode)
+
+	// The pods the affected resource runs as
+	var pods []*object
+	switch root.Kind {
+	case "Pod":
+		pods = []*object{root}
+	case "Node":
+		pods = c.listPods(ctx, b, "", "--all-namespaces", "--field-selector", "spec.nodeName="+root.Metadata.Name)
+	default:
+		if selector := root.selector(); len(selector) > 0 {
+			pods = c.listPods(ctx, b, root.Metadata.Namespace, "-l", labelSelector(selector))
+		}
+	}
+
+	c.addOwners(ctx, b, root, 0)
+	nodes := make(map[string]bool)
+	for _, pod := range pods {
+		podNode := pod.node()
+		b.addNode(podNode)
+		c.addOwners(ctx, b, pod, 0)
+		if name := pod.Spec.NodeName; name != "" {
+			if !nodes[name] {
+				nodes[name] = true
+				node, err := c.get(ctx, "Node", "", name)
+				if err != nil {
+					b.warn("node %s: %v", name, err)
+					b.addNode(&Node{ID: nodeID("Node", "", name), Kind: "Node", Name: name})
+				} else {
+					b.addNode(node.node())
+				}
+			}
+			b.addEdge(podNode.ID, nodeID("Node", "", name), RelationRunsOn)
+		}
+	}
+
+	if root.Metadata.Namespace != "" && root.Kind != "Node" {
+		services := c.addServices(ctx, b, root, pods)
+		c.addIngresses(ctx, b, root.Metadata.Namespace, services)
+	}
+	return b.graph, nil
+}
+
+// get reads an object.
+func (c *Collector) get(ctx context.Context, kind, namespace, name string) (*object, error) {
+	args := []string{"get", strings.ToLower(kind), name, "-o", "json"}
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	out, err := c.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var obj object
+	if err := json.Unmarshal(out, &obj); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s: %w", kind, name, err)
+	}
+	return &obj, nil
+}
+
+// list lists the objects of a kind in a namespace.
+func (c *Collector) list(ctx context.Context, resource, namespace string, args ...string) ([]*object, error) {
+	all := []string{"get", resource, "-o", "json"}
+	if namespace != "" {
+		all = append(all, "-n", namespace)
+	}
+	out, err := c.run(ctx, append(all, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []*object `json:"items"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", resource, err)
+	}
+	for _, item := range list.Items {
+		// Items of typed lists may omit their kind
+		if item.Kind == "" {
+			item.Kind = kindOf(resource)
+		}
+	}
+	return list.Items, nil
+}
+
+// kindOf returns the kind of the resources the collector lists.
+func kindOf(resource string) string {
+	switch resource {
+	case "pods":
+		return "Pod"
+	case "services":
+		return "Service"
+	case "ingresses":
+		return "Ingress"
+	}
+	return resource
+}
+
+// listPods lists up to maxPods pods, preferring unhealthy ones.
+func (c *Collector) listPods(ctx context.Context, b *builder, namespace string, args ...string) []*object {
+	pods, err := c.list(ctx, "pods", namespace, args...)
+	if err != nil {
+		b.warn("pods: %v", err)
+		return nil
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		ui, uj := pods[i].node().Unhealthy, pods[j].node().Unhealthy
+		if ui != uj {
+			return ui
+		}
+		return pods[i].Metadata.Name < pods[j].Metadata.Name
+	})
+	if len(pods) > c.maxPods {
+		b.warn("showing %d of %d pods", c.maxPods, len(pods))
+		pods = pods[:c.maxPods]
+	}
+	return pods
+}
+
+// addOwners adds the controller chain of an object, e.g. a pod's replica set and
+// its deployment.
+func (c *Collector) addOwners(ctx context.Context, b *builder, obj *object, depth int) {
+	if depth >= maxOwnerDepth || len(obj.Metadata.OwnerReferences) == 0 {
+		return
+	}
+	ref := obj.Metadata.OwnerReferences[0]
+	for _, r := range obj.Metadata.OwnerReferences {
+		if r.Controller {
+			ref = r
+			break
+		}
+	}
+	childID := nodeID(obj.Kind, obj.Metadata.Namespace, obj.Metadata.Name)
+	ownerID := nodeID(ref.Kind, obj.Metadata.Namespace, ref.Name)
+	if b.graph.Node(ownerID) != nil {
+		b.addEdge(ownerID, childID, RelationOwns)
+		return
+	}
+
+	owner, err := c.get(ctx, ref.Kind, obj.Metadata.Namespace, ref.Name)
+	if err != nil {
+		b.warn("%s %s: %v", ref.Kind, ref.Name, err)
+		b.addNode(&Node{ID: ownerID, Kind: ref.Kind, Namespace: obj.Metadata.Namespace, Name: ref.Name})
+		b.addEdge(ownerID, childID, RelationOwns)
+		return
+	}
+	b.addNode(owner.node())
+	b.addEdge(ownerID, childID, RelationOwns)
+	c.addOwners(ctx, b, owner, depth+1)
+}
+
+// addServices adds the services selecting the pods, or the affected resource when
+// it has no pods, and returns the names of the services in the graph.
+func (c *Collector) addServices(ctx context.Context, b *builder, root *object, pods []*object) []string {
+	var names []string
+	if root.Kind == "Service" {
+		names = append(names, root.Metadata.Name)
+	}
+	services, err := c.list(ctx, "services", root.Metadata.Namespace)
+	if err != nil {
+		b.warn("services: %v", err)
+		return names
+	}
+	targets := pods
+	if len(targets) == 0 {
+		targets = []*object{root}
+	}
+	for _, svc := range services {
+		selector := svc.selector()
+		svcID := nodeID("Service", svc.Metadata.Namespace, svc.Metadata.Name)
+		for _, target := range targets {
+			if !matches(selector, target.Metadata.Labels) {
+				continue
+			}
+			b.addNode(svc.node())
+			b.addEdge(svcID, nodeID(target.Kind, target.Metadata.Namespace, target.Metadata.Name), RelationSelects)
+			if !slices.Contains(names, svc.Metadata.Name) {
+				names = append(names, svc.Metadata.Name)
+			}
+		}
+	}
+	return names
+}
+
+// addIngresses adds the ingresses routing to the services.
+func (c *Collector) addIngresses(ctx context.Context, b *builder, namespace string, services []string) {
+	if len(services) == 0 {
+		return
+	}
+	ingresses, err := c.list(ctx, "ingresses", namespace)
+	if err != nil {
+		b.warn("ingresses: %v", err)
+		return
+	}
+	for _, ing := range ingresses {
+		ingID := nodeID("Ingress", ing.Metadata.Namespace, ing.Metadata.Name)
+		for _, name := range ing.serviceNames() {
+			if !slices.Contains(services, name) {
+				continue
+			}
+			b.addNode(ing.node())
+			b.addEdge(ingID, nodeID("Service", namespace, name), RelationRoutesTo)
+		}
+	}
+}
+
+// Write writes the graph and its diagram into a workspace directory.
+func Write(dir string, g *Graph) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal topology: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, JSONFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", JSONFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, SVGFile), RenderSVG(g), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", SVGFile, err)
+	}
+	return nil
+}
+
+// Read reads the graph from a workspace directory. It returns nil and no error
+// when the workspace has no topology.
+func Read(dir string) (*Graph, error) {
+	data, err := os.ReadFile(filepath.Join(dir, JSONFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", JSONFile, err)
+	}
+	var g Graph
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", JSONFile, err)
+	}
+	return &g, nil
+}
+
+// PromptSection tells the agent about the topology in the workspace.
+func PromptSection() string {
+	return `## Resource Topology
+
+` + JSONFile + ` in the workspace holds the objects around the affected resource,
+collected when the incident was created: its pods, their owners up to the
+deployment, the services selecting them, the ingresses routing to those services,
+and the nodes the pods run on, with each object's status. Start from it to see
+which related objects are unhealthy instead of listing them again.
+`
+}
@@ -0,0 +1,150 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeCluster answers kubectl commands from canned output, keyed by the joined
+// arguments.
+type fakeCluster map[string]string
+
+func (f fakeCluster) run(ctx context.Context, args ...string) ([]byte, error) {
+	out, ok := f[strings.Join(args, " ")]
+	if !ok {
+		return nil, fmt.Errorf("kubectl %s failed: Error from server (Forbidden)", strings.Join(args, " "))
+	}
+	return []byte(out), nil
+}
+
+var shopCluster = fakeCluster{
+	"get pod checkout-7d9f-abcde -o json -n shop": `{"kind": "Pod",
+		"metadata": {"name": "checkout-7d9f-abcde", "namespace": "shop", "labels": {"app": "checkout", "pod-template-hash": "7d9f"},
+			"ownerReferences": [{"kind": "ReplicaSet", "name": "checkout-7d9f", "controller": true}]},
+		"spec": {"nodeName": "worker-1"},
+		"status": {"phase": "Pending"}}`,
+	"get replicaset checkout-7d9f -o json -n shop": `{"kind": "ReplicaSet",
+		"metadata": {"name": "checkout-7d9f", "namespace": "shop",
+			"ownerReferences": [{"kind": "Deployment", "name": "checkout", "controller": true}]},
+		"spec": {"replicas": 2, "selector": {"matchLabels": {"app": "checkout", "pod-template-hash": "7d9f"}}},
+		"status": {"readyReplicas": 1}}`,
+	"get deployment checkout -o json -n shop": `{"kind": "Deployment",
+		"metadata": {"name": "checkout", "namespace": "shop"},
+		"spec": {"replicas": 2, "selector": {"matchLabels": {"app": "checkout"}}},
+		"status": {"readyReplicas": 1}}`,
+	"get node worker-1 -o json": `{"kind": "Node",
+		"metadata": {"name": "worker-1"},
+		"status": {"conditions": [{"type": "Ready", "status": "True"}]}}`,
+	"get services -o json -n shop": `{"items": [
+		{"metadata": {"name": "checkout", "namespace": "shop"}, "spec": {"selector": {"app": "checkout"}}},
+		{"metadata": {"name": "payments", "namespace": "shop"}, "spec": {"selector": {"app": "payments"}}},
+		{"metadata": {"name": "external", "namespace": "shop"}, "spec": {}}]}`,
+	"get pods -o json -n shop -l app=checkout": `{"items": [
+		{"metadata": {"name": "checkout-7d9f-zzzzz", "namespace": "shop", "labels": {"app": "checkout"},
+			"ownerReferences": [{"kind": "ReplicaSet", "name": "checkout-7d9f", "controller": true}]},
+			"spec": {"nodeName": "worker-1"}, "status": {"phase": "Running"}},
+		{"metadata": {"name": "checkout-7d9f-abcde", "namespace": "shop", "labels": {"app": "checkout"},
+			"ownerReferences": [{"kind": "ReplicaSet", "name": "checkout-7d9f", "controller": true}]},
+			"spec": {"nodeName": "worker-1"}, "status": {"phase": "Pending"}}]}`,
+	// Listing ingresses is forbidden
+}
+
+func TestCollect_Pod(t *testing.T) {
+	shop := fakeCluster{}
+	for k, v := range shopCluster {
+		shop[k] = v
+	}
+	shop["get ingresses -o json -n shop"] = `{"items": [
+		{"metadata": {"name": "storefront", "namespace": "shop"},
+		 "spec": {"rules": [{"http": {"paths": [{"backend": {"service": {"name": "checkout"}}}, {"backend": {"service": {"name": "payments"}}}]}}]}}]}`
+
+	g, err := newCollector(shop.run, 0).Collect(context.Background(), Target{Namespace: "shop", Kind: "Pod", Name: "checkout-7d9f-abcde"})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if g.Root != "Pod/shop/checkout-7d9f-abcde" {
+		t.Errorf("Root = %q, want the pod", g.Root)
+	}
+	wantEdges := []Edge{
+		{From: "ReplicaSet/shop/checkout-7d9f", To: "Pod/shop/checkout-7d9f-abcde", Relation: RelationOwns},
+		{From: "Deployment/shop/checkout", To: "ReplicaSet/shop/checkout-7d9f", Relation: RelationOwns},
+		{From: "Pod/shop/checkout-7d9f-abcde", To: "Node/worker-1", Relation: RelationRunsOn},
+		{From: "Service/shop/checkout", To: "Pod/shop/checkout-7d9f-abcde", Relation: RelationSelects},
+		{From: "Ingress/shop/storefront", To: "Service/shop/checkout", Relation: RelationRoutesTo},
+	}
+	if len(g.Edges) != len(wantEdges) {
+		t.Fatalf("Edges = %+v, want %+v", g.Edges, wantEdges)
+	}
+	for i, e := range wantEdges {
+		if g.Edges[i] != e {
+			t.Errorf("Edges[%d] = %+v, want %+v", i, g.Edges[i], e)
+		}
+	}
+	if g.Node("Service/shop/payments") != nil {
+		t.Error("the graph holds a service not selecting the pod")
+	}
+	if n := g.Node("Pod/shop/checkout-7d9f-abcde"); !n.Unhealthy || n.Status != "Pending" {
+		t.Errorf("pod = %+v, want pending and unhealthy", n)
+	}
+	if n := g.Node("Deployment/shop/checkout"); n.Status != "1/2 ready" || !n.Unhealthy {
+		t.Errorf("deployment = %+v, want 1/2 ready and unhealthy", n)
+	}
+	if n := g.Node("Node/worker-1"); n.Status != "Ready" || n.Unhealthy {
+		t.Errorf("node = %+v, want ready", n)
+	}
+	if len(g.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want none", g.Warnings)
+	}
+}
+
+func TestCollect_Deployment(t *testing.T) {
+	g, err := newCollector(shopCluster.run, 1).Collect(context.Background(), Target{Namespace: "shop", Kind: "Deployment", Name: "checkout"})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	// The unhealthy pod is preferred over the running one
+	if g.Node("Pod/shop/checkout-7d9f-abcde") == nil || g.Node("Pod/shop/checkout-7d9f-zzzzz") != nil {
+		t.Errorf("Nodes = %+v, want only the pending pod", g.Nodes)
+	}
+	if g.Node("ReplicaSet/shop/checkout-7d9f") == nil || g.Node("Service/shop/checkout") == nil {
+		t.Errorf("Nodes = %+v, want the replica set and service", g.Nodes)
+	}
+	warnings := strings.Join(g.Warnings, "\n")
+	if !strings.Contains(warnings, "showing 1 of 2 pods") || !strings.Contains(warnings, "ingresses") {
+		t.Errorf("Warnings = %v, want the pod limit and the forbidden ingress list", g.Warnings)
+	}
+}
+
+func TestCollect_MissingTarget(t *testing.T) {
+	if _, err := newCollector(shopCluster.run, 0).Collect(context.Background(), Target{Namespace: "shop", Kind: "Pod", Name: "gone"}); err == nil {
+		t.Error("Collect() of a missing resource succeeded, want error")
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	g := &Graph{
+		Root:  "Pod/shop/<script>",
+		Nodes: []*Node{{ID: "Pod/shop/<script>", Kind: "Pod", Namespace: "shop", Name: "<script>alert(1)</script>", Status: "Failed", Unhealthy: true}},
+	}
+	dir := t.TempDir()
+	if err := Write(dir, g); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	read, err := Read(dir)
+	if err != nil || read == nil || read.Root != g.Root || len(read.Nodes) != 1 {
+		t.Fatalf("Read() = %+v, %v, want the written graph", read, err)
+	}
+	if none, err := Read(t.TempDir()); none != nil || err != nil {
+		t.Errorf("Read() of an empty workspace = %+v, %v, want nil", none, err)
+	}
+
+	svg := string(RenderSVG(g))
+	if strings.Contains(svg, "<script>") {
+		t.Errorf("RenderSVG() did not escape the object name: %s", svg)
+	}
+	if !strings.Contains(svg, `stroke="#007bff"`) || !strings.Contains(svg, `fill="#fdecea"`) {
+		t.Errorf("RenderSVG() = %s, want the root outlined and shaded unhealthy", svg)
+	}
+}
@@ -0,0 +1,257 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rbias/nightcrier/internal/kubeobjects"
+)
+
+// objectReader reads objects through a cluster's MCP server, connecting on first
+// use.
+type objectReader struct {
+	objects *kubeobjects.Reader
+	err     error
+}
+
+// newObjectReader connects to the MCP server at endpoint. A failed connection is
+// reported by every read.
+func newObjectReader(ctx context.Context, endpoint string, httpClient *http.Client) *objectReader {
+	if endpoint == "" {
+		return &objectReader{err: fmt.Errorf("no MCP endpoint for the cluster")}
+	}
+	objects, err := kubeobjects.Connect(ctx, endpoint, "nightcrier-verify", httpClient)
+	if err != nil {
+		return &objectReader{err: err}
+	}
+	return &objectReader{objects: objects}
+}
+
+// close closes the MCP session.
+func (r *objectReader) close() {
+	if r.objects != nil {
+		r.objects.Close()
+	}
+}
+
+// get returns an object, or nil when it does not exist (or the MCP server may not
+// read it).
+func (r *objectReader) get(ctx context.Context, claim Claim) (map[string]interface{}, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	ref := kubeobjects.Ref{APIVersion: claim.APIVersion, Kind: claim.Kind, Namespace: claim.Namespace, Name: claim.Name}
+	var object map[string]interface{}
+	found, err := r.objects.Get(ctx, ref, &object)
+	if err != nil || !found {
+		return nil, err
+	}
+	return object, nil
+}
+
+// verifyObjectClaim checks an object, image, resource, or field claim.
+func verifyObjectClaim(ctx context.Context, objects *objectReader, claim Claim) Result {
+	if claim.Kind == "" || claim.Name == "" {
+		return unverified(claim, "no kind and name given")
+	}
+	object, err := objects.get(ctx, claim)
+	if err != nil {
+		return unverified(claim, "%v", err)
+	}
+
+	if claim.Type == ClaimObject {
+		switch {
+		case object != nil && claim.exists():
+			return verified(claim, "found in the cluster")
+		case object == nil && !claim.exists():
+			return verified(claim, "not found in the cluster")
+		case object != nil:
+			return contradicted(claim, "the object exists")
+		}
+		return contradicted(claim, "the object was not found")
+	}
+	if object == nil {
+		return unverified(claim, "%s was not found", claim.object())
+	}
+
+	switch claim.Type {
+	case ClaimImage:
+		return verifyImage(object, claim)
+	case ClaimResource:
+		return verifyResource(object, claim)
+	}
+	return verifyField(object, claim)
+}
+
+// verifyImage checks that a container of the object runs the claimed image.
+func verifyImage(object map[string]interface{}, claim Claim) Result {
+	containers := containersOf(object)
+	if len(containers) == 0 {
+		return unverified(claim, "%s has no containers", claim.object())
+	}
+	var live []string
+	for _, c := range containers {
+		name, _ := c["name"].(string)
+		if claim.Container != "" && name != claim.Container {
+			continue
+		}
+		image, _ := c["image"].(string)
+		if sameImage(image, claim.Image) {
+			return verified(claim, "container %s runs %s", name, image)
+		}
+		live = append(live, image)
+	}
+	if len(live) == 0 {
+		return unverified(claim, "no container %q", claim.Container)
+	}
+	return contradicted(claim, "live image: %s", strings.Join(live, ", "))
+}
+
+// verifyResource checks a container's resource request or limit.
+func verifyResource(object map[string]interface{}, claim Claim) Result {
+	section, resource, ok := strings.Cut(claim.Resource, ".")
+	if !ok || (section != "limits" && section != "requests") {
+		return unverified(claim, "resource must be limits.<name> or requests.<name>")
+	}
+	containers := containersOf(object)
+	var container map[string]interface{}
+	for _, c := range containers {
+		if name, _ := c["name"].(string); name == claim.Container || (claim.Container == "" && len(containers) == 1) {
+			container = c
+			break
+		}
+	}
+	if container == nil {
+		return unverified(claim, "no container %q", claim.Container)
+	}
+
+	value, found := lookupPath(container, "resources."+section+"."+resource)
+	if !found {
+		if claim.Value == "" || claim.Value == "none" {
+			return verified(claim, "no %s set", claim.Resource)
+		}
+		return contradicted(claim, "no %s set", claim.Resource)
+	}
+	live := fmt.Sprint(value)
+	if sameQuantity(live, claim.Value) {
+		return verified(claim, "live %s: %s", claim.Resource, live)
+	}
+	return contradicted(claim, "live %s: %s", claim.Resource, live)
+}
+
+// verifyField checks the value of a field.
+func verifyField(object map[string]interface{}, claim Claim) Result {
+	if claim.Path == "" {
+		return unverified(claim, "no path given")
+	}
+	value, found := lookupPath(object, claim.Path)
+	if !found {
+		return contradicted(claim, "%s is not set", claim.Path)
+	}
+	live := fmt.Sprint(value)
+	if live == claim.Value || sameQuantity(live, claim.Value) {
+		return verified(claim, "live value: %s", live)
+	}
+	return contradicted(claim, "live value: %s", live)
+}
+
+// containersOf returns the containers and init containers of a pod or of the pod
+// template of a workload.
+func containersOf(object map[string]interface{}) []map[string]interface{} {
+	var containers []map[string]interface{}
+	for _, specPath := range []string{"spec", "spec.template.spec", "spec.jobTemplate.spec.template.spec"} {
+		for _, field := range []string{"containers", "initContainers"} {
+			list, _ := lookupPath(object, specPath+"."+field)
+			items, _ := list.([]interface{})
+			for _, item := range items {
+				if c, ok := item.(map[string]interface{}); ok {
+					containers = append(containers, c)
+				}
+			}
+		}
+		if len(containers) > 0 {
+			return containers
+		}
+	}
+	return nil
+}
+
+// lookupPath returns the value at a dotted path, selecting list elements by index.
+func lookupPath(value interface{}, path string) (interface{}, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// sameImage reports whether two image references name the same image, treating
+// Docker Hub defaults ("nginx" is "docker.io/library/nginx:latest") as equal.
+func sameImage(a, b string) bool {
+	return normalizeImage(a) == normalizeImage(b)
+}
+
+// normalizeImage returns the fully qualified form of an image reference.
+func normalizeImage(image string) string {
+	ref := parseImage(strings.TrimSpace(image))
+	return ref.registry + "/" + ref.repository + ref.separator + ref.reference
+}
+
+// quantitySuffixes are the multipliers of Kubernetes quantity suffixes
+var quantitySuffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40}, {"Pi", 1 << 50},
+	{"k", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12}, {"P", 1e15},
+	{"m", 1e-3},
+}
+
+// parseQuantity parses a Kubernetes quantity such as "256Mi", "0.5", or "500m".
+func parseQuantity(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	multiplier := 1.0
+	for _, q := range quantitySuffixes {
+		if strings.HasSuffix(s, q.suffix) {
+			s, multiplier = strings.TrimSuffix(s, q.suffix), q.multiplier
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value * multiplier, true
+}
+
+// sameQuantity reports whether two quantities are equal, e.g. "1Gi" and "1024Mi"
+// or "0.5" and "500m".
+func sameQuantity(a, b string) bool {
+	qa, okA := parseQuantity(a)
+	qb, okB := parseQuantity(b)
+	if !okA || !okB {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+	diff := qa - qb
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= 1e-9*max(qa, qb, 1)
+}
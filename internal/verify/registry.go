@@ -0,0 +1,193 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Docker Hub's registry host and the host its images are named with
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+	dockerHubName     = "docker.io"
+)
+
+// manifestMediaTypes are the manifest types accepted from registries
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// imageRef is a parsed image reference.
+type imageRef struct {
+	registry   string // e.g. "docker.io", "ghcr.io", "localhost:5000"
+	repository string // e.g. "library/nginx"
+	separator  string // ":" before a tag, "@" before a digest
+	reference  string // tag or digest
+}
+
+// parseImage parses an image reference, applying the Docker Hub defaults: no
+// registry means docker.io, single-name repositories are under library/, and no
+// tag means latest.
+func parseImage(image string) imageRef {
+	ref := imageRef{registry: dockerHubName, separator: ":", reference: "latest"}
+	name := image
+	if at := strings.Index(name, "@"); at >= 0 {
+		name, ref.separator, ref.reference = name[:at], "@", name[at+1:]
+	} else if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		name, ref.reference = name[:colon], name[colon+1:]
+	}
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.registry, name = first, rest
+	}
+	if ref.registry == dockerHubName && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.repository = name
+	return ref
+}
+
+// registryClient looks up image manifests with the registry HTTP API (v2), using
+// anonymous bearer tokens when the registry asks for them.
+type registryClient struct {
+	httpClient *http.Client
+	// scheme is "https" except in tests
+	scheme string
+}
+
+// exists reports whether the image's tag or digest exists in its registry.
+func (c *registryClient) exists(ctx context.Context, image string) (bool, error) {
+	ref := parseImage(image)
+	host := ref.registry
+	if host == dockerHubName {
+		host = dockerHubRegistry
+	}
+	scheme := c.scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	manifestURL := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", scheme, host, ref.repository, ref.reference)
+
+	resp, err := c.headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := c.anonymousToken(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return false, err
+		}
+		if resp, err = c.headManifest(ctx, manifestURL, token); err != nil {
+			return false, err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return false, fmt.Errorf("registry %s requires credentials", ref.registry)
+	}
+	return false, fmt.Errorf("registry %s returned status %d", ref.registry, resp.StatusCode)
+}
+
+// headManifest sends a HEAD request for a manifest.
+func (c *registryClient) headManifest(ctx context.Context, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry request: %w", err)
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query registry: %w", err)
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// anonymousToken fetches an anonymous pull token from the realm of a
+// `Bearer realm="...",service="...",scope="..."` challenge.
+func (c *registryClient) anonymousToken(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("registry requires %s authentication", scheme)
+	}
+	values := parseChallenge(params)
+	realm := values["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry auth challenge has no realm")
+	}
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if values[key] != "" {
+			query.Set(key, values[key])
+		}
+	}
+	tokenURL := realm
+	if len(query) > 0 {
+		tokenURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create registry token request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get registry token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token endpoint returned status %d", resp.StatusCode)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// parseChallenge parses the comma-separated key="value" parameters of an
+// authentication challenge.
+func parseChallenge(params string) map[string]string {
+	values := make(map[string]string)
+	for params != "" {
+		key, rest, ok := strings.Cut(params, "=")
+		if !ok {
+			break
+		}
+		key = strings.TrimSpace(key)
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				break
+			}
+			value, rest = rest[1:end+1], rest[end+2:]
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+			rest = "," + rest
+		}
+		values[strings.ToLower(key)] = value
+		params = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+	}
+	return values
+}
@@ -0,0 +1,74 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseImage(t *testing.T) {
+	for image, want := range map[string]string{
+		"nginx":                             "docker.io/library/nginx:latest",
+		"envoyproxy/envoy:v1.30":            "docker.io/envoyproxy/envoy:v1.30",
+		"ghcr.io/org/app:1.0":               "ghcr.io/org/app:1.0",
+		"localhost:5000/app":                "localhost:5000/app:latest",
+		"registry.example.com:443/team/app": "registry.example.com:443/team/app:latest",
+		"app@sha256:abc":                    "docker.io/library/app@sha256:abc",
+	} {
+		if got := normalizeImage(image); got != want {
+			t.Errorf("normalizeImage(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestRegistryClient_Exists(t *testing.T) {
+	var registry *httptest.Server
+	registry = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if r.URL.Query().Get("scope") != "repository:team/app:pull" {
+				http.Error(w, "bad scope", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"token": "anon"}`)
+		case r.Header.Get("Authorization") != "Bearer anon":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:team/app:pull"`, registry.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.Method == http.MethodHead && r.URL.Path == "/v2/team/app/manifests/v1":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "http://")
+
+	client := &registryClient{httpClient: registry.Client(), scheme: "http"}
+	if exists, err := client.exists(context.Background(), host+"/team/app:v1"); err != nil || !exists {
+		t.Errorf("exists(v1) = %v, %v, want true", exists, err)
+	}
+	if exists, err := client.exists(context.Background(), host+"/team/app:v2"); err != nil || exists {
+		t.Errorf("exists(v2) = %v, %v, want false", exists, err)
+	}
+
+	no := false
+	verifier := NewVerifier(Config{CheckRegistries: true}, nil)
+	verifier.registry = client
+	results := verifier.Verify(context.Background(), "prod", []Claim{
+		{Type: ClaimImageExists, Image: host + "/team/app:v2", Exists: &no},
+		{Type: ClaimImageExists, Image: host + "/team/app:v2"},
+	})
+	if results[0].Status != StatusVerified || results[1].Status != StatusContradicted {
+		t.Errorf("Verify() = %+v, want verified then contradicted", results)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	got := parseChallenge(`realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"`)
+	if got["realm"] != "https://auth.docker.io/token" || got["service"] != "registry.docker.io" || got["scope"] != "repository:library/nginx:pull" {
+		t.Errorf("parseChallenge() = %v", got)
+	}
+}
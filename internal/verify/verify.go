@@ -0,0 +1,351 @@
+// Package verify checks the claims an agent records in output/findings.json
+// against the live cluster, e.g. that the image a workload is said to run is the
+// image in its spec, that a quoted memory limit matches the live limit, or that a
+// cited image tag exists in its registry. Each claim is marked verified,
+// contradicted, or unverified, and the marks are appended to the investigation
+// report, so readers can tell which findings were checked.
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// AgentFile is where the agent records the checkable claims of its findings,
+// relative to the workspace
+const AgentFile = "output/findings.json"
+
+// ResultsFile is where the verification results are written, relative to the
+// workspace
+const ResultsFile = "output/verification.json"
+
+// maxClaims bounds the claims checked per investigation
+const maxClaims = 50
+
+// Claim types
+const (
+	// ClaimObject claims an object exists (or, with exists: false, does not)
+	ClaimObject = "object"
+	// ClaimImage claims a container of an object runs an image
+	ClaimImage = "image"
+	// ClaimImageExists claims an image tag exists in its registry (or, with
+	// exists: false, does not)
+	ClaimImageExists = "image_exists"
+	// ClaimResource claims a container's resource request or limit, e.g.
+	// "limits.memory" is "256Mi"
+	ClaimResource = "resource"
+	// ClaimField claims a field of an object, by dotted path, has a value
+	ClaimField = "field"
+)
+
+// Result statuses
+const (
+	StatusVerified     = "verified"
+	StatusContradicted = "contradicted"
+	StatusUnverified   = "unverified"
+)
+
+// Claim is a checkable statement from the agent's findings.
+type Claim struct {
+	// Type is object, image, image_exists, resource, or field
+	Type string `json:"type"`
+	// Statement is the claim as worded in the report
+	Statement string `json:"statement,omitempty"`
+
+	// The object the claim is about; APIVersion defaults by kind
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+	// Container selects the container of image and resource claims (empty: any
+	// container for image claims, the only container for resource claims)
+	Container string `json:"container,omitempty"`
+
+	// Image is the image of image and image_exists claims
+	Image string `json:"image,omitempty"`
+	// Exists is the claimed existence for object and image_exists claims
+	// (default: true)
+	Exists *bool `json:"exists,omitempty"`
+	// Resource is "limits.<name>" or "requests.<name>" for resource claims
+	Resource string `json:"resource,omitempty"`
+	// Path is the dotted field path of field claims, e.g. "spec.replicas"; list
+	// elements are selected by index
+	Path string `json:"path,omitempty"`
+	// Value is the claimed value of resource and field claims
+	Value string `json:"value,omitempty"`
+}
+
+// exists returns the claimed existence.
+func (c Claim) exists() bool {
+	return c.Exists == nil || *c.Exists
+}
+
+// object returns "Kind namespace/name" for messages.
+func (c Claim) object() string {
+	if c.Namespace == "" {
+		return c.Kind + " " + c.Name
+	}
+	return c.Kind + " " + c.Namespace + "/" + c.Name
+}
+
+// Describe returns the claim's statement, or a description of what it claims.
+func (c Claim) Describe() string {
+	if c.Statement != "" {
+		return c.Statement
+	}
+	switch c.Type {
+	case ClaimObject:
+		if !c.exists() {
+			return c.object() + " does not exist"
+		}
+		return c.object() + " exists"
+	case ClaimImage:
+		return fmt.Sprintf("%s runs image %s", c.object(), c.Image)
+	case ClaimImageExists:
+		if !c.exists() {
+			return fmt.Sprintf("image %s does not exist", c.Image)
+		}
+		return fmt.Sprintf("image %s exists", c.Image)
+	case ClaimResource:
+		return fmt.Sprintf("%s has %s %s", c.object(), c.Resource, c.Value)
+	case ClaimField:
+		return fmt.Sprintf("%s has %s = %s", c.object(), c.Path, c.Value)
+	}
+	return c.Type + " claim"
+}
+
+// Result is the verification result of one claim.
+type Result struct {
+	Claim  Claim  `json:"claim"`
+	Status string `json:"status"`
+	// Detail explains the result, e.g. the live value of a contradicted claim
+	Detail string `json:"detail,omitempty"`
+}
+
+// Summary counts the verification results of an investigation.
+type Summary struct {
+	Verified     int `json:"verified"`
+	Contradicted int `json:"contradicted"`
+	Unverified   int `json:"unverified"`
+}
+
+// Total returns the number of claims checked.
+func (s Summary) Total() int {
+	return s.Verified + s.Contradicted + s.Unverified
+}
+
+// Summarize counts the results by status.
+func Summarize(results []Result) Summary {
+	var s Summary
+	for _, r := range results {
+		switch r.Status {
+		case StatusVerified:
+			s.Verified++
+		case StatusContradicted:
+			s.Contradicted++
+		default:
+			s.Unverified++
+		}
+	}
+	return s
+}
+
+// findingsFile is the layout of AgentFile.
+type findingsFile struct {
+	Claims []Claim `json:"claims"`
+}
+
+// ReadClaims reads the claims the agent recorded. A missing file yields no claims.
+func ReadClaims(path string) ([]Claim, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read findings: %w", err)
+	}
+	var findings findingsFile
+	if err := json.Unmarshal(data, &findings); err != nil {
+		return nil, fmt.Errorf("failed to parse findings: %w", err)
+	}
+	if len(findings.Claims) > maxClaims {
+		findings.Claims = findings.Claims[:maxClaims]
+	}
+	return findings.Claims, nil
+}
+
+// Config configures a Verifier.
+type Config struct {
+	// Endpoints maps cluster names to their MCP server endpoints
+	Endpoints map[string]string
+	// CheckRegistries looks image_exists claims up in the image registries;
+	// otherwise they stay unverified
+	CheckRegistries bool
+}
+
+// Verifier checks claims against a cluster through its MCP server, and image tags
+// against their registries.
+type Verifier struct {
+	config     Config
+	httpClient *http.Client
+	registry   *registryClient
+}
+
+// NewVerifier returns a verifier. A nil httpClient uses http.DefaultClient.
+func NewVerifier(config Config, httpClient *http.Client) *Verifier {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Verifier{
+		config:     config,
+		httpClient: httpClient,
+		registry:   &registryClient{httpClient: httpClient},
+	}
+}
+
+// Verify checks the claims against a cluster. Claims that cannot be checked (the
+// cluster is unreachable, the claim is malformed) are unverified; Verify itself
+// never fails.
+func (v *Verifier) Verify(ctx context.Context, cluster string, claims []Claim) []Result {
+	results := make([]Result, 0, len(claims))
+	var objects *objectReader
+	defer func() {
+		if objects != nil {
+			objects.close()
+		}
+	}()
+
+	for _, claim := range claims {
+		var result Result
+		switch claim.Type {
+		case ClaimImageExists:
+			result = v.verifyImageExists(ctx, claim)
+		case ClaimObject, ClaimImage, ClaimResource, ClaimField:
+			if objects == nil {
+				objects = newObjectReader(ctx, v.config.Endpoints[cluster], v.httpClient)
+			}
+			result = verifyObjectClaim(ctx, objects, claim)
+		default:
+			result = unverified(claim, "unknown claim type %q", claim.Type)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// verifyImageExists looks an image tag up in its registry.
+func (v *Verifier) verifyImageExists(ctx context.Context, claim Claim) Result {
+	if claim.Image == "" {
+		return unverified(claim, "no image given")
+	}
+	if !v.config.CheckRegistries {
+		return unverified(claim, "registry checks are disabled")
+	}
+	exists, err := v.registry.exists(ctx, claim.Image)
+	if err != nil {
+		return unverified(claim, "%v", err)
+	}
+	if exists == claim.exists() {
+		if exists {
+			return verified(claim, "found in the registry")
+		}
+		return verified(claim, "not found in the registry")
+	}
+	if exists {
+		return contradicted(claim, "the tag exists in the registry")
+	}
+	return contradicted(claim, "the tag was not found in the registry")
+}
+
+// verified, contradicted, and unverified build results.
+func verified(claim Claim, format string, args ...interface{}) Result {
+	return Result{Claim: claim, Status: StatusVerified, Detail: fmt.Sprintf(format, args...)}
+}
+
+func contradicted(claim Claim, format string, args ...interface{}) Result {
+	return Result{Claim: claim, Status: StatusContradicted, Detail: fmt.Sprintf(format, args...)}
+}
+
+func unverified(claim Claim, format string, args ...interface{}) Result {
+	return Result{Claim: claim, Status: StatusUnverified, Detail: fmt.Sprintf(format, args...)}
+}
+
+// WriteResults writes the results as JSON to path.
+func WriteResults(path string, results []Result) error {
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"summary": Summarize(results),
+		"results": results,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal verification results: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write verification results: %w", err)
+	}
+	return nil
+}
+
+// resultMarks are the report marks of the result statuses
+var resultMarks = map[string]string{
+	StatusVerified:     "✅ **Verified**",
+	StatusContradicted: "❌ **Contradicted**",
+	StatusUnverified:   "⚠️ **Unverified**",
+}
+
+// ReportSection returns the "## Verification" section marking each claim.
+func ReportSection(results []Result) string {
+	s := Summarize(results)
+	var b strings.Builder
+	b.WriteString("## Verification\n\n")
+	fmt.Fprintf(&b, "Nightcrier checked %d claims of this report against the live cluster: %d verified, %d contradicted, %d unverified.\n\n",
+		s.Total(), s.Verified, s.Contradicted, s.Unverified)
+	for _, r := range results {
+		line := fmt.Sprintf("- %s: %s", resultMarks[r.Status], r.Claim.Describe())
+		if r.Detail != "" {
+			line += " (" + r.Detail + ")"
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// AnnotateReport appends the verification section to the report at path.
+func AnnotateReport(path string, results []Result) error {
+	report, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read report: %w", err)
+	}
+	annotated := strings.TrimRight(string(report), "\n") + "\n\n" + ReportSection(results)
+	if err := os.WriteFile(path, []byte(annotated), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// PromptSection returns the prompt section asking the agent to record the
+// checkable claims of its findings.
+func PromptSection() string {
+	return `## Verifiable Findings
+
+Nightcrier checks the claims in your report against the live cluster and marks
+them verified or contradicted. Record the concrete, checkable claims your root
+cause rests on in ` + AgentFile + ` as JSON, for example:
+
+    {"claims": [
+      {"type": "image", "kind": "Deployment", "namespace": "shop", "name": "checkout", "container": "app", "image": "registry.example.com/checkout:v1.4.2", "statement": "checkout was rolled out with v1.4.2"},
+      {"type": "image_exists", "image": "registry.example.com/checkout:v1.4.3", "exists": false},
+      {"type": "resource", "kind": "Deployment", "namespace": "shop", "name": "checkout", "container": "app", "resource": "limits.memory", "value": "256Mi"},
+      {"type": "field", "kind": "Deployment", "namespace": "shop", "name": "checkout", "path": "spec.replicas", "value": "1"},
+      {"type": "object", "kind": "ConfigMap", "namespace": "shop", "name": "checkout-config", "exists": false}
+    ]}
+
+Claim types: object, image, image_exists, resource (limits.<name> or
+requests.<name>), and field (dotted path, list elements by index). Only record
+claims about the current state; omit the file if there are none.
+`
+}
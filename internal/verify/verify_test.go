@@ -0,0 +1,171 @@
+package verify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rbias/nightcrier/internal/kubeobjects"
+)
+
+// resourcesGetArgs are the arguments of the resources_get tool
+type resourcesGetArgs struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// newFakeMCPServer serves resources_get from objects, keyed by "kind/name".
+func newFakeMCPServer(t *testing.T, objects map[string]string) *httptest.Server {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "fake-kubernetes-mcp-server", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: kubeobjects.ResourcesGetTool}, func(ctx context.Context, req *mcp.CallToolRequest, args resourcesGetArgs) (*mcp.CallToolResult, any, error) {
+		object, ok := objects[args.Kind+"/"+args.Name]
+		if !ok {
+			return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "not found"}}}, nil, nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: object}}}, nil, nil
+	})
+	srv := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+const checkoutDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: checkout
+  namespace: shop
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+        - name: app
+          image: registry.example.com/checkout:v1.4.2
+          resources:
+            limits:
+              memory: 256Mi
+              cpu: 500m
+        - name: proxy
+          image: envoyproxy/envoy:v1.30
+`
+
+func TestVerifier_Verify(t *testing.T) {
+	srv := newFakeMCPServer(t, map[string]string{"Deployment/checkout": checkoutDeployment})
+	verifier := NewVerifier(Config{Endpoints: map[string]string{"prod": srv.URL}}, nil)
+	no := false
+
+	object := Claim{Kind: "Deployment", Namespace: "shop", Name: "checkout"}
+	with := func(c Claim) Claim {
+		c.Kind, c.Namespace, c.Name = object.Kind, object.Namespace, object.Name
+		return c
+	}
+	claims := []Claim{
+		with(Claim{Type: ClaimObject}),
+		{Type: ClaimObject, Kind: "ConfigMap", Namespace: "shop", Name: "checkout-config", Exists: &no},
+		with(Claim{Type: ClaimImage, Container: "app", Image: "registry.example.com/checkout:v1.4.2"}),
+		with(Claim{Type: ClaimImage, Image: "docker.io/envoyproxy/envoy:v1.30"}),
+		with(Claim{Type: ClaimImage, Container: "app", Image: "registry.example.com/checkout:v1.4.3"}),
+		with(Claim{Type: ClaimResource, Container: "app", Resource: "limits.memory", Value: "0.25Gi"}),
+		with(Claim{Type: ClaimResource, Container: "app", Resource: "limits.cpu", Value: "1"}),
+		with(Claim{Type: ClaimField, Path: "spec.replicas", Value: "1"}),
+		with(Claim{Type: ClaimField, Path: "spec.template.spec.containers.1.name", Value: "sidecar"}),
+		with(Claim{Type: ClaimResource, Resource: "limits.memory", Value: "256Mi"}), // two containers, none named
+		{Type: ClaimImage, Kind: "Deployment", Namespace: "shop", Name: "cart", Image: "cart:v1"},
+		{Type: ClaimImageExists, Image: "registry.example.com/checkout:v1.4.3", Exists: &no}, // registry checks disabled
+		{Type: "opinion", Statement: "the deploy was rushed"},
+	}
+	want := []string{
+		StatusVerified, StatusVerified, StatusVerified, StatusVerified, StatusContradicted,
+		StatusVerified, StatusContradicted, StatusVerified, StatusContradicted,
+		StatusUnverified, StatusUnverified, StatusUnverified, StatusUnverified,
+	}
+
+	results := verifier.Verify(context.Background(), "prod", claims)
+	if len(results) != len(want) {
+		t.Fatalf("Verify() returned %d results, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("claim %d (%s): status = %s (%s), want %s", i, r.Claim.Describe(), r.Status, r.Detail, want[i])
+		}
+	}
+	if !strings.Contains(results[4].Detail, "v1.4.2") {
+		t.Errorf("contradicted image detail = %q, want the live image", results[4].Detail)
+	}
+
+	// An unreachable cluster leaves the claims unverified
+	for _, r := range verifier.Verify(context.Background(), "staging", claims[:2]) {
+		if r.Status != StatusUnverified {
+			t.Errorf("claim on a cluster without an endpoint = %s, want unverified", r.Status)
+		}
+	}
+}
+
+func TestReadClaimsAndAnnotateReport(t *testing.T) {
+	dir := t.TempDir()
+	if claims, err := ReadClaims(filepath.Join(dir, "findings.json")); err != nil || claims != nil {
+		t.Fatalf("ReadClaims() of a missing file = %v, %v, want no claims", claims, err)
+	}
+	findings := filepath.Join(dir, "findings.json")
+	if err := os.WriteFile(findings, []byte(`{"claims": [{"type": "field", "kind": "Deployment", "name": "x", "path": "spec.replicas", "value": "3"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	claims, err := ReadClaims(findings)
+	if err != nil || len(claims) != 1 || claims[0].Path != "spec.replicas" {
+		t.Fatalf("ReadClaims() = %+v, %v", claims, err)
+	}
+
+	report := filepath.Join(dir, "investigation.md")
+	if err := os.WriteFile(report, []byte("## Root Cause\n\nToo few replicas.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	results := []Result{
+		{Claim: claims[0], Status: StatusContradicted, Detail: "live value: 1"},
+		{Claim: Claim{Type: ClaimImage, Kind: "Deployment", Name: "x", Image: "x:v1", Statement: "x runs v1"}, Status: StatusVerified},
+	}
+	if err := AnnotateReport(report, results); err != nil {
+		t.Fatalf("AnnotateReport() error = %v", err)
+	}
+	annotated, _ := os.ReadFile(report)
+	for _, want := range []string{
+		"Too few replicas.\n\n## Verification",
+		"1 verified, 1 contradicted, 0 unverified",
+		"❌ **Contradicted**: Deployment x has spec.replicas = 3 (live value: 1)",
+		"✅ **Verified**: x runs v1",
+	} {
+		if !strings.Contains(string(annotated), want) {
+			t.Errorf("annotated report missing %q:\n%s", want, annotated)
+		}
+	}
+
+	if err := WriteResults(filepath.Join(dir, "verification.json"), results); err != nil {
+		t.Fatalf("WriteResults() error = %v", err)
+	}
+}
+
+func TestSameQuantity(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want bool
+	}{
+		{"256Mi", "256Mi", true},
+		{"1Gi", "1024Mi", true},
+		{"500m", "0.5", true},
+		{"1", "1000m", true},
+		{"256Mi", "256M", false},
+		{"abc", "abc", true},
+		{"abc", "abd", false},
+	} {
+		if got := sameQuantity(tc.a, tc.b); got != tc.want {
+			t.Errorf("sameQuantity(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Package webhooks delivers incident lifecycle events to external workflow engines
+// (n8n, Temporal, StackStorm, or any HTTP receiver). Every event is posted as the
+// same JSON envelope, signed with HMAC-SHA256 over the delivery timestamp and body
+// so receivers can authenticate it and reject replays.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lifecycle event types
+const (
+	// EventCreated is emitted when an incident is created from a fault event
+	EventCreated = "created"
+	// EventAgentStarted is emitted when the agent starts investigating
+	EventAgentStarted = "agent_started"
+	// EventCompleted is emitted when an investigation produced a report (agent,
+	// cached, or rule-based)
+	EventCompleted = "completed"
+	// EventFailed is emitted when an incident was closed without a report: the
+	// agent failed or the investigation budget was exhausted
+	EventFailed = "failed"
+	// EventAcknowledged is emitted when a responder acknowledges the incident
+	EventAcknowledged = "acknowledged"
+	// EventResolved is emitted when a responder marks the fault as fixed
+	EventResolved = "resolved"
+	// EventStatusChanged is emitted when the incident moves to another lifecycle
+	// status before its investigation completes (queued, investigating); the data
+	// carries the previous and new status as "from" and "to"
+	EventStatusChanged = "status_changed"
+)
+
+// EventTypes lists the lifecycle event types, in lifecycle order.
+var EventTypes = []string{EventCreated, EventStatusChanged, EventAgentStarted, EventCompleted, EventFailed, EventAcknowledged, EventResolved}
+
+// EnvelopeVersion is the version of the envelope format. It changes only when
+// fields are removed or change meaning; new fields may be added at any time.
+const EnvelopeVersion = "1"
+
+// Headers of a delivery
+const (
+	// HeaderEvent carries the event type
+	HeaderEvent = "X-Nightcrier-Event"
+	// HeaderDelivery carries the event ID, the same for every retry of the event
+	HeaderDelivery = "X-Nightcrier-Delivery"
+	// HeaderTimestamp carries the Unix time the delivery was signed at
+	HeaderTimestamp = "X-Nightcrier-Timestamp"
+	// HeaderSignature carries "sha256=" and the hex HMAC-SHA256 of
+	// "<timestamp>.<body>" keyed with the endpoint's secret
+	HeaderSignature = "X-Nightcrier-Signature"
+)
+
+// ErrRejected is returned by Deliver when the receiver refused the event with a
+// client error other than 408 or 429; retrying the same event cannot succeed.
+var ErrRejected = errors.New("webhook rejected")
+
+// Envelope is the JSON document posted for every lifecycle event.
+type Envelope struct {
+	// ID identifies the event; receivers use it to drop duplicate deliveries
+	ID string `json:"id"`
+	// Type is the lifecycle event type (see EventTypes)
+	Type    string `json:"type"`
+	Version string `json:"version"`
+	// OccurredAt is when the transition happened
+	OccurredAt time.Time `json:"occurred_at"`
+	Incident   Incident  `json:"incident"`
+	// Data holds the event's details, e.g. the report URL of a completed
+	// investigation or who acknowledged the incident
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// Incident is the incident an event is about, as of the event.
+type Incident struct {
+	ID        string `json:"id"`
+	DisplayID string `json:"display_id,omitempty"`
+	Status    string `json:"status"`
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name,omitempty"`
+	FaultType string `json:"fault_type"`
+	Severity  string `json:"severity"`
+	// ParentIncidentID links a follow-up to the incident of the earlier occurrence
+	ParentIncidentID string            `json:"parent_incident_id,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
+
+// NewEnvelope returns the envelope of an event about an incident, occurring now.
+// Empty data values are dropped.
+func NewEnvelope(eventType string, inc Incident, data map[string]string) *Envelope {
+	env := &Envelope{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Version:    EnvelopeVersion,
+		OccurredAt: time.Now().UTC(),
+		Incident:   inc,
+	}
+	for key, value := range data {
+		if value == "" {
+			continue
+		}
+		if env.Data == nil {
+			env.Data = make(map[string]string)
+		}
+		env.Data[key] = value
+	}
+	return env
+}
+
+// Endpoint is a receiver of lifecycle events.
+type Endpoint struct {
+	// Name identifies the endpoint in logs and queued deliveries
+	Name string
+	// URL receives the events with POST
+	URL string
+	// Secret keys the HMAC signature; empty sends unsigned events
+	Secret string
+	// Events are the event types sent to the endpoint (empty: all)
+	Events []string
+}
+
+// Wants reports whether the endpoint subscribes to an event type.
+func (e Endpoint) Wants(eventType string) bool {
+	return len(e.Events) == 0 || slices.Contains(e.Events, eventType)
+}
+
+// Sign returns the signature of a body delivered at timestamp (Unix seconds).
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a delivery, for receivers
+// written in Go. Deliveries signed more than tolerance before or after now are
+// rejected as replays.
+func Verify(secret, timestampHeader, signatureHeader string, body []byte, now time.Time, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s header %q", HeaderTimestamp, timestampHeader)
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("delivery timestamp is %s off, beyond the %s tolerance", age.Round(time.Second), tolerance)
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signatureHeader)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// Client delivers envelopes to the configured endpoints.
+type Client struct {
+	endpoints  []Endpoint
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// New returns a client for the endpoints. A nil httpClient uses
+// http.DefaultClient.
+func New(endpoints []Endpoint, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{endpoints: endpoints, httpClient: httpClient, now: time.Now}
+}
+
+// Targets returns the names of the endpoints subscribed to an event type.
+func (c *Client) Targets(eventType string) []string {
+	var names []string
+	for _, endpoint := range c.endpoints {
+		if endpoint.Wants(eventType) {
+			names = append(names, endpoint.Name)
+		}
+	}
+	return names
+}
+
+// Deliver posts an envelope to the named endpoint. An error wrapping ErrRejected
+// means the receiver refused the event; other errors may succeed on retry.
+func (c *Client) Deliver(ctx context.Context, endpointName string, env *Envelope) error {
+	idx := slices.IndexFunc(c.endpoints, func(e Endpoint) bool { return e.Name == endpointName })
+	if idx < 0 {
+		return fmt.Errorf("%w: unknown webhook endpoint %q", ErrRejected, endpointName)
+	}
+	endpoint := c.endpoints[idx]
+
+	body, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	timestamp := c.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "nightcrier-webhooks/"+EnvelopeVersion)
+	req.Header.Set(HeaderEvent, env.Type)
+	req.Header.Set(HeaderDelivery, env.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	if endpoint.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, body))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook to %s: %w", endpoint.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("webhook %s returned status %d: %s", endpoint.Name, resp.StatusCode, strings.TrimSpace(string(msg)))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %w", ErrRejected, err)
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testEnvelope() *Envelope {
+	return NewEnvelope(EventCompleted, Incident{
+		ID:        "3f9a1c0d-1111-2222-3333-444455556666",
+		Status:    "resolved",
+		Cluster:   "prod-east",
+		FaultType: "CrashLoopBackOff",
+		Severity:  "ERROR",
+	}, map[string]string{"report_url": "https://reports.example.com/3f9a1c0d", "cached_from": ""})
+}
+
+func TestNewEnvelope(t *testing.T) {
+	env := testEnvelope()
+	if env.ID == "" || env.Version != EnvelopeVersion || env.OccurredAt.IsZero() {
+		t.Errorf("envelope = %+v, want an ID, version, and occurrence time", env)
+	}
+	if _, ok := env.Data["cached_from"]; ok || len(env.Data) != 1 {
+		t.Errorf("Data = %v, want empty values dropped", env.Data)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"created"}`)
+	now := time.Unix(1714572000, 0)
+	sig := Sign("xyzzy", now.Unix(), body)
+
+	if err := Verify("xyzzy", "1714572000", sig, body, now.Add(time.Minute), 5*time.Minute); err != nil {
+		t.Errorf("Verify() failed: %v", err)
+	}
+	if err := Verify("plugh", "1714572000", sig, body, now, 5*time.Minute); err == nil {
+		t.Error("Verify() with the wrong secret succeeded, want error")
+	}
+	if err := Verify("xyzzy", "1714572000", sig, []byte(`{"type":"failed"}`), now, 5*time.Minute); err == nil {
+		t.Error("Verify() of a modified body succeeded, want error")
+	}
+	if err := Verify("xyzzy", "1714572000", sig, body, now.Add(10*time.Minute), 5*time.Minute); err == nil {
+		t.Error("Verify() of a stale delivery succeeded, want error")
+	}
+}
+
+func TestEndpointWants(t *testing.T) {
+	all := Endpoint{Name: "all"}
+	some := Endpoint{Name: "some", Events: []string{EventCreated}}
+	if !all.Wants(EventFailed) || !some.Wants(EventCreated) || some.Wants(EventFailed) {
+		t.Error("Wants() did not honor the subscribed events")
+	}
+
+	c := New([]Endpoint{all, some}, nil)
+	if got := c.Targets(EventFailed); len(got) != 1 || got[0] != "all" {
+		t.Errorf("Targets(failed) = %v, want [all]", got)
+	}
+}
+
+func TestClient_DeliverSignsEnvelope(t *testing.T) {
+	env := testEnvelope()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(HeaderEvent) != EventCompleted || r.Header.Get(HeaderDelivery) != env.ID {
+			t.Errorf("event headers = %q %q", r.Header.Get(HeaderEvent), r.Header.Get(HeaderDelivery))
+		}
+		if err := Verify("xyzzy", r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, time.Now(), time.Minute); err != nil {
+			t.Errorf("Verify() failed: %v", err)
+		}
+		var got Envelope
+		if err := json.Unmarshal(body, &got); err != nil || got.Incident.Cluster != "prod-east" {
+			t.Errorf("body = %s (%v)", body, err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := New([]Endpoint{{Name: "n8n", URL: server.URL, Secret: "xyzzy"}}, server.Client())
+	if err := c.Deliver(context.Background(), "n8n", env); err != nil {
+		t.Fatalf("Deliver() failed: %v", err)
+	}
+}
+
+func TestClient_DeliverErrors(t *testing.T) {
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", status)
+	}))
+	defer server.Close()
+	c := New([]Endpoint{{Name: "n8n", URL: server.URL, Secret: "xyzzy"}}, server.Client())
+
+	if err := c.Deliver(context.Background(), "n8n", testEnvelope()); !errors.Is(err, ErrRejected) {
+		t.Errorf("Deliver() on 400 = %v, want ErrRejected", err)
+	}
+	for _, status = range []int{http.StatusTooManyRequests, http.StatusBadGateway} {
+		if err := c.Deliver(context.Background(), "n8n", testEnvelope()); err == nil || errors.Is(err, ErrRejected) {
+			t.Errorf("Deliver() on %d = %v, want a retryable error", status, err)
+		}
+	}
+	if err := c.Deliver(context.Background(), "zapier", testEnvelope()); !errors.Is(err, ErrRejected) {
+		t.Errorf("Deliver() to an unknown endpoint = %v, want ErrRejected", err)
+	}
+}
@@ -0,0 +1,4 @@
+-- Rollback run records
+
+DROP INDEX IF EXISTS idx_runs_started_at;
+DROP TABLE IF EXISTS runs;
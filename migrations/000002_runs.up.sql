@@ -0,0 +1,34 @@
+-- runs table records each nightcrier process lifetime (startup and shutdown) so
+-- restarts and configuration changes can be shown alongside incident history
+CREATE TABLE IF NOT EXISTS runs (
+    -- Primary key
+    run_id TEXT PRIMARY KEY,
+
+    -- Lifecycle timestamps
+    started_at TIMESTAMP NOT NULL,
+    stopped_at TIMESTAMP,
+
+    -- Build information
+    version TEXT NOT NULL,
+    git_commit TEXT,
+
+    -- Configuration fingerprint (SHA-256 of the effective configuration)
+    config_hash TEXT NOT NULL,
+
+    -- Monitored clusters (JSON array of cluster names)
+    clusters TEXT NOT NULL,
+
+    -- Storage modes
+    artifact_storage TEXT NOT NULL,
+    state_storage TEXT NOT NULL,
+
+    -- Host the process ran on
+    hostname TEXT,
+
+    -- Why the process stopped, e.g. "shutdown" or the fatal error. NULL together
+    -- with stopped_at while running, or when the process died without recording
+    -- its shutdown (crash, OOM kill)
+    exit_reason TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_runs_started_at ON runs(started_at);
@@ -0,0 +1,3 @@
+-- Rollback run configuration snapshots
+
+ALTER TABLE runs DROP COLUMN config_snapshot;
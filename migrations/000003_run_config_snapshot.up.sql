@@ -0,0 +1,3 @@
+-- Store the redacted configuration of each run (JSON object of config keys to
+-- values) so startup can log which settings changed since the previous run
+ALTER TABLE runs ADD COLUMN config_snapshot TEXT;
@@ -0,0 +1,4 @@
+-- Rollback incident labels and annotations
+
+DROP INDEX IF EXISTS idx_incident_labels_name_value;
+DROP TABLE IF EXISTS incident_labels;
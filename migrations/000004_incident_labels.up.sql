@@ -0,0 +1,23 @@
+-- incident_labels holds the labels (filterable key-value pairs, e.g. team or cost
+-- center) and annotations (free-form notes) attached to incidents from cluster
+-- labels, label rules, agent findings, and manual edits
+CREATE TABLE IF NOT EXISTS incident_labels (
+    incident_id TEXT NOT NULL,
+
+    -- "label" or "annotation"
+    kind TEXT NOT NULL,
+
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+
+    PRIMARY KEY (incident_id, kind, name),
+
+    -- Foreign key
+    FOREIGN KEY (incident_id) REFERENCES incidents(incident_id),
+
+    -- Constraints
+    CONSTRAINT chk_incident_labels_kind CHECK (kind IN ('label', 'annotation'))
+);
+
+-- Supports filtering incidents by label
+CREATE INDEX IF NOT EXISTS idx_incident_labels_name_value ON incident_labels(kind, name, value);
@@ -0,0 +1,3 @@
+-- Rollback artifact hashes
+
+DROP TABLE IF EXISTS artifact_hashes;
@@ -0,0 +1,18 @@
+-- artifact_hashes holds the SHA-256 hash of every stored incident artifact, so
+-- downloads and exports can prove the artifacts were not modified after the fact
+CREATE TABLE IF NOT EXISTS artifact_hashes (
+    incident_id TEXT NOT NULL,
+
+    -- Path relative to the incident's storage directory, e.g. "logs/agent-full.log"
+    name TEXT NOT NULL,
+
+    -- Hex-encoded SHA-256 of the stored contents
+    sha256 TEXT NOT NULL,
+
+    recorded_at TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (incident_id, name),
+
+    -- Foreign key
+    FOREIGN KEY (incident_id) REFERENCES incidents(incident_id)
+);
@@ -0,0 +1,4 @@
+-- Rollback legal holds
+
+DROP INDEX IF EXISTS idx_legal_holds_incident_id;
+DROP TABLE IF EXISTS legal_holds;
@@ -0,0 +1,18 @@
+-- legal_holds records legal holds placed on incidents. An incident under an active
+-- hold (released_at IS NULL) is exempt from retention and garbage collection: its
+-- workspace, artifacts, and rows are kept until the hold is released. Released
+-- holds are kept as an audit trail.
+CREATE TABLE IF NOT EXISTS legal_holds (
+    hold_id TEXT PRIMARY KEY,
+    incident_id TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    placed_by TEXT NOT NULL,
+    placed_at TIMESTAMP NOT NULL,
+    released_at TIMESTAMP,
+    released_by TEXT,
+
+    -- Foreign key
+    FOREIGN KEY (incident_id) REFERENCES incidents(incident_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_legal_holds_incident_id ON legal_holds(incident_id);
@@ -0,0 +1,5 @@
+-- Rollback follow-up incident links
+
+DROP INDEX IF EXISTS idx_incidents_parent_incident_id;
+
+ALTER TABLE incidents DROP COLUMN parent_incident_id;
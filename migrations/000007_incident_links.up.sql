@@ -0,0 +1,5 @@
+-- Link follow-up incidents to the earlier resolved incident of the same recurring
+-- fault (see follow_up), forming a chain of investigations.
+ALTER TABLE incidents ADD COLUMN parent_incident_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_incidents_parent_incident_id ON incidents(parent_incident_id);
@@ -0,0 +1,7 @@
+-- Rollback readable incident IDs
+
+DROP TABLE IF EXISTS incident_sequences;
+
+DROP INDEX IF EXISTS idx_incidents_display_id;
+
+ALTER TABLE incidents DROP COLUMN display_id;
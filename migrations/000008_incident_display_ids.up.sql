@@ -0,0 +1,15 @@
+-- Readable incident IDs (e.g. NC-2024-0613-prod-0042) used in workspace paths,
+-- notifications, and report URLs. incident_id stays the internal UUID.
+ALTER TABLE incidents ADD COLUMN display_id TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incidents_display_id ON incidents(display_id);
+
+-- incident_sequences allocates the per-cluster, per-day sequence numbers of
+-- display IDs
+CREATE TABLE IF NOT EXISTS incident_sequences (
+    cluster TEXT NOT NULL,
+    day TEXT NOT NULL,
+    last_seq INTEGER NOT NULL,
+
+    PRIMARY KEY (cluster, day)
+);
@@ -0,0 +1,10 @@
+-- Rollback agent execution resource usage
+
+ALTER TABLE agent_executions DROP COLUMN resource_source;
+ALTER TABLE agent_executions DROP COLUMN disk_written_bytes;
+ALTER TABLE agent_executions DROP COLUMN peak_processes;
+ALTER TABLE agent_executions DROP COLUMN peak_rss_bytes;
+ALTER TABLE agent_executions DROP COLUMN cpu_seconds;
+ALTER TABLE agent_executions DROP COLUMN agent_model;
+ALTER TABLE agent_executions DROP COLUMN agent_image;
+ALTER TABLE agent_executions DROP COLUMN agent_cli;
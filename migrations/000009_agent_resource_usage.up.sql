@@ -0,0 +1,11 @@
+-- Resource usage of agent executions (sampled from the agent container's cgroup
+-- or the agent process tree) and the agent that ran, so resource-hungry agent
+-- versions stand out when executions are aggregated
+ALTER TABLE agent_executions ADD COLUMN agent_cli TEXT;
+ALTER TABLE agent_executions ADD COLUMN agent_image TEXT;
+ALTER TABLE agent_executions ADD COLUMN agent_model TEXT;
+ALTER TABLE agent_executions ADD COLUMN cpu_seconds REAL;
+ALTER TABLE agent_executions ADD COLUMN peak_rss_bytes BIGINT;
+ALTER TABLE agent_executions ADD COLUMN peak_processes INTEGER;
+ALTER TABLE agent_executions ADD COLUMN disk_written_bytes BIGINT;
+ALTER TABLE agent_executions ADD COLUMN resource_source TEXT;
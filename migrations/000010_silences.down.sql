@@ -0,0 +1,4 @@
+-- Rollback silences
+
+DROP INDEX IF EXISTS idx_silences_expires_at;
+DROP TABLE IF EXISTS silences;
@@ -0,0 +1,17 @@
+-- silences suppress the investigation of fault events in a namespace (optionally
+-- limited to one cluster) until they expire or are lifted. They are created from
+-- chat commands and shared by every nightcrier process using the state store.
+-- Expired and lifted silences are kept as an audit trail.
+CREATE TABLE IF NOT EXISTS silences (
+    silence_id TEXT PRIMARY KEY,
+    cluster TEXT NOT NULL DEFAULT '',
+    namespace TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    lifted_at TIMESTAMP,
+    lifted_by TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_silences_expires_at ON silences(expires_at);
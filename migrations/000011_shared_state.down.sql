@@ -0,0 +1,4 @@
+-- Rollback shared state
+
+DROP INDEX IF EXISTS idx_shared_state_expires_at;
+DROP TABLE IF EXISTS shared_state;
@@ -0,0 +1,12 @@
+-- shared_state holds limit state shared by every nightcrier process using the
+-- state store (fleet-wide dedup windows, launch pacing, budget counters). Values
+-- are JSON documents updated with compare-and-swap on the version column; expired
+-- rows are pruned periodically.
+CREATE TABLE IF NOT EXISTS shared_state (
+    state_key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    version BIGINT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_shared_state_expires_at ON shared_state(expires_at);
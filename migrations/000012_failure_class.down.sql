@@ -0,0 +1,5 @@
+-- Rollback agent failure classes
+
+DROP INDEX IF EXISTS idx_incidents_failure_class;
+
+ALTER TABLE incidents DROP COLUMN failure_class;
@@ -0,0 +1,7 @@
+-- Canonical class of an agent failure (timeout, provider_rate_limit,
+-- provider_auth, missing_output, too_small, kubectl_denied, oom_killed, unknown),
+-- assigned by pattern rules over the free-text failure_reason and the agent's
+-- output so failures can be counted by cause
+ALTER TABLE incidents ADD COLUMN failure_class TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_incidents_failure_class ON incidents(failure_class);
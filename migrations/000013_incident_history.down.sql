@@ -0,0 +1,5 @@
+-- Rollback incident history
+
+DROP INDEX IF EXISTS idx_incident_history_incident_id;
+
+DROP TABLE IF EXISTS incident_history;
@@ -0,0 +1,46 @@
+-- incident_history records every change of an incident's status, failure class,
+-- and labels (the team label is its assignment), so the state of an incident at
+-- any past time can be reconstructed and rendered as a status timeline for
+-- postmortems. Rows are only ever inserted; the incidents and incident_labels
+-- tables keep the current state.
+CREATE TABLE IF NOT EXISTS incident_history (
+    history_id TEXT PRIMARY KEY,
+    incident_id TEXT NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+
+    -- "status", "failure_class", or "label:<name>"
+    field TEXT NOT NULL,
+
+    -- NULL when the field was unset before (old_value) or removed (new_value)
+    old_value TEXT,
+    new_value TEXT,
+
+    -- Foreign key
+    FOREIGN KEY (incident_id) REFERENCES incidents(incident_id)
+);
+
+-- Supports reading the history of an incident in order
+CREATE INDEX IF NOT EXISTS idx_incident_history_incident_id ON incident_history(incident_id, changed_at);
+
+-- Backfill the history of existing incidents from their current state. Completed
+-- incidents are assumed to have been investigating from creation until they
+-- completed; labels are assumed to have been attached at creation.
+INSERT INTO incident_history (history_id, incident_id, changed_at, field, old_value, new_value)
+SELECT 'backfill-created-' || incident_id, incident_id, created_at, 'status', NULL,
+       CASE WHEN completed_at IS NULL THEN status ELSE 'investigating' END
+FROM incidents;
+
+INSERT INTO incident_history (history_id, incident_id, changed_at, field, old_value, new_value)
+SELECT 'backfill-completed-' || incident_id, incident_id, completed_at, 'status', 'investigating', status
+FROM incidents
+WHERE completed_at IS NOT NULL;
+
+INSERT INTO incident_history (history_id, incident_id, changed_at, field, old_value, new_value)
+SELECT 'backfill-failure-class-' || incident_id, incident_id, completed_at, 'failure_class', NULL, failure_class
+FROM incidents
+WHERE completed_at IS NOT NULL AND failure_class IS NOT NULL;
+
+INSERT INTO incident_history (history_id, incident_id, changed_at, field, old_value, new_value)
+SELECT 'backfill-label-' || l.incident_id || '-' || l.name, l.incident_id, i.created_at, 'label:' || l.name, NULL, l.value
+FROM incident_labels l JOIN incidents i ON i.incident_id = l.incident_id
+WHERE l.kind = 'label';
@@ -0,0 +1,5 @@
+-- Rollback investigation reviews
+
+DROP INDEX IF EXISTS idx_investigation_reviews_incident;
+DROP TABLE IF EXISTS investigation_reviews;
+ALTER TABLE agent_executions DROP COLUMN prompt_version;
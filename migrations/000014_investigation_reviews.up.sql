@@ -0,0 +1,24 @@
+-- The version of the prompt an agent execution ran with (a hash of the system
+-- prompt file and the additional prompt), so investigation quality can be tracked
+-- per model and prompt version
+ALTER TABLE agent_executions ADD COLUMN prompt_version TEXT;
+
+-- investigation_reviews records human review comments on completed investigations,
+-- each optionally with a verdict on the report's accuracy. Reviews are only ever
+-- inserted; the latest verdict on an incident counts toward the accuracy metrics.
+CREATE TABLE IF NOT EXISTS investigation_reviews (
+    review_id TEXT PRIMARY KEY,
+    incident_id TEXT NOT NULL,
+    reviewer TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+
+    -- "accurate", "partially_accurate", "inaccurate", or NULL for a comment only
+    verdict TEXT,
+    comment TEXT,
+
+    -- Foreign key
+    FOREIGN KEY (incident_id) REFERENCES incidents(incident_id)
+);
+
+-- Supports reading the reviews of an incident in order
+CREATE INDEX IF NOT EXISTS idx_investigation_reviews_incident ON investigation_reviews(incident_id, created_at);
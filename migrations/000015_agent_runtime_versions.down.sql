@@ -0,0 +1,4 @@
+-- Rollback agent runtime versions
+
+ALTER TABLE agent_executions DROP COLUMN agent_image_digest;
+ALTER TABLE agent_executions DROP COLUMN agent_cli_version;
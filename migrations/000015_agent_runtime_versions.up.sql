@@ -0,0 +1,4 @@
+-- The agent CLI version and agent image digest an agent execution ran with, so
+-- changes in investigation quality can be traced to agent upgrades
+ALTER TABLE agent_executions ADD COLUMN agent_cli_version TEXT;
+ALTER TABLE agent_executions ADD COLUMN agent_image_digest TEXT;
@@ -0,0 +1,4 @@
+-- Rollback incident source
+
+DROP INDEX IF EXISTS idx_incidents_source;
+ALTER TABLE incidents DROP COLUMN source;
@@ -0,0 +1,4 @@
+-- The event source (e.g. mcp, alertmanager, kafka) whose fault event opened the
+-- incident, for clusters feeding several sources into the pipeline
+ALTER TABLE incidents ADD COLUMN source TEXT;
+CREATE INDEX IF NOT EXISTS idx_incidents_source ON incidents(source);
@@ -0,0 +1,7 @@
+-- Rollback incident lifecycle statuses: statuses added by the lifecycle map to the
+-- nearest earlier one (open statuses to pending, held findings to resolved).
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS chk_incidents_status;
+UPDATE incidents SET status = 'pending' WHERE status IN ('received', 'queued', 'suppressed', 'expired');
+UPDATE incidents SET status = 'resolved' WHERE status IN ('awaiting_review', 'remediation_proposed');
+ALTER TABLE incidents ADD CONSTRAINT chk_incidents_status
+    CHECK (status IN ('pending', 'investigating', 'resolved', 'failed', 'agent_failed'));
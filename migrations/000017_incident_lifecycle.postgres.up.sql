@@ -0,0 +1,6 @@
+-- Extend the incident status to the full lifecycle (see the incident package):
+-- received, queued, awaiting_review, remediation_proposed, suppressed, and expired
+-- join the existing statuses.
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS chk_incidents_status;
+ALTER TABLE incidents ADD CONSTRAINT chk_incidents_status
+    CHECK (status IN ('received', 'pending', 'queued', 'investigating', 'awaiting_review', 'remediation_proposed', 'resolved', 'failed', 'agent_failed', 'suppressed', 'expired'));
@@ -0,0 +1,82 @@
+-- Extend the incident status to the full lifecycle (see the incident package):
+-- received, queued, awaiting_review, remediation_proposed, suppressed, and expired
+-- join the existing statuses. SQLite cannot alter a CHECK constraint, so the
+-- incidents table is rebuilt with the new constraint and its indexes recreated.
+-- Foreign keys of the other tables refer to the table by name and keep working.
+CREATE TABLE incidents_new (
+    -- Primary key
+    incident_id TEXT PRIMARY KEY,
+
+    -- Links to fault event
+    fault_id TEXT NOT NULL,
+    triggering_event_id TEXT,
+
+    -- Lifecycle timestamps
+    status TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+
+    -- Result information
+    exit_code INTEGER,
+    failure_reason TEXT,
+
+    -- Context from event (denormalized for query performance)
+    cluster TEXT NOT NULL,
+    namespace TEXT,
+    fault_type TEXT NOT NULL,
+    severity TEXT NOT NULL,
+    context TEXT NOT NULL,
+    timestamp TEXT NOT NULL,
+
+    -- Resource information (denormalized)
+    resource_api_version TEXT,
+    resource_kind TEXT,
+    resource_name TEXT,
+    resource_namespace TEXT,
+    resource_uid TEXT,
+    parent_incident_id TEXT,
+    display_id TEXT,
+    failure_class TEXT,
+    source TEXT,
+
+    -- Foreign key
+    FOREIGN KEY (fault_id) REFERENCES fault_events(fault_id),
+
+    -- Constraints
+    CONSTRAINT chk_incidents_status CHECK (status IN ('received', 'pending', 'queued', 'investigating', 'awaiting_review', 'remediation_proposed', 'resolved', 'failed', 'agent_failed', 'suppressed', 'expired')),
+    CONSTRAINT chk_incidents_cluster CHECK (cluster <> ''),
+    CONSTRAINT chk_incidents_fault_type CHECK (fault_type <> '')
+);
+
+INSERT INTO incidents_new (
+    incident_id, fault_id, triggering_event_id,
+    status, created_at, started_at, completed_at,
+    exit_code, failure_reason,
+    cluster, namespace, fault_type, severity, context, timestamp,
+    resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid,
+    parent_incident_id, display_id, failure_class, source
+)
+SELECT
+    incident_id, fault_id, triggering_event_id,
+    status, created_at, started_at, completed_at,
+    exit_code, failure_reason,
+    cluster, namespace, fault_type, severity, context, timestamp,
+    resource_api_version, resource_kind, resource_name, resource_namespace, resource_uid,
+    parent_incident_id, display_id, failure_class, source
+FROM incidents;
+
+DROP TABLE incidents;
+ALTER TABLE incidents_new RENAME TO incidents;
+
+CREATE INDEX IF NOT EXISTS idx_incidents_fault_id ON incidents(fault_id);
+CREATE INDEX IF NOT EXISTS idx_incidents_status ON incidents(status);
+CREATE INDEX IF NOT EXISTS idx_incidents_cluster ON incidents(cluster);
+CREATE INDEX IF NOT EXISTS idx_incidents_created_at ON incidents(created_at);
+CREATE INDEX IF NOT EXISTS idx_incidents_namespace ON incidents(namespace);
+CREATE INDEX IF NOT EXISTS idx_incidents_fault_type ON incidents(fault_type);
+CREATE INDEX IF NOT EXISTS idx_incidents_severity ON incidents(severity);
+CREATE INDEX IF NOT EXISTS idx_incidents_parent_incident_id ON incidents(parent_incident_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incidents_display_id ON incidents(display_id);
+CREATE INDEX IF NOT EXISTS idx_incidents_failure_class ON incidents(failure_class);
+CREATE INDEX IF NOT EXISTS idx_incidents_source ON incidents(source);
@@ -0,0 +1,154 @@
+package nightcrier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rbias/nightcrier/internal/agent"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/reporting"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+// Source delivers fault events to an Engine. Run sends events on out until ctx is
+// cancelled or the source is exhausted; the engine stops the source by cancelling
+// ctx. An error other than ctx's is logged and the source is not restarted.
+type Source interface {
+	Run(ctx context.Context, out chan<- *FaultEvent) error
+}
+
+// SourceFunc adapts a function to the Source interface.
+type SourceFunc func(ctx context.Context, out chan<- *FaultEvent) error
+
+// Run implements Source.
+func (f SourceFunc) Run(ctx context.Context, out chan<- *FaultEvent) error {
+	return f(ctx, out)
+}
+
+// ChannelSource returns a source that forwards the events received on events, for
+// services that already have fault events in hand (e.g. from their own informers).
+// The source finishes when events is closed.
+func ChannelSource(events <-chan *FaultEvent) Source {
+	return SourceFunc(func(ctx context.Context, out chan<- *FaultEvent) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case event, ok := <-events:
+				if !ok {
+					return nil
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+	})
+}
+
+// Executor investigates an incident. Investigate runs in the incident's workspace,
+// where incident.json and incident_facts.md have already been written, and must
+// leave its report at output/investigation.md. facts is the Markdown rendering of
+// the incident facts. The returned exit code is 0 for a successful investigation.
+type Executor interface {
+	Investigate(ctx context.Context, inc *Incident, workspacePath, facts string) (int, error)
+}
+
+// ExecutorFunc adapts a function to the Executor interface.
+type ExecutorFunc func(ctx context.Context, inc *Incident, workspacePath, facts string) (int, error)
+
+// Investigate implements Executor.
+func (f ExecutorFunc) Investigate(ctx context.Context, inc *Incident, workspacePath, facts string) (int, error) {
+	return f(ctx, inc, workspacePath, facts)
+}
+
+// agentExecutor runs the AI agent container the way the nightcrier daemon does.
+type agentExecutor struct {
+	executor *agent.Executor
+}
+
+// AgentExecutor returns an executor that runs the configured AI agent. Tuning
+// values (timeouts, output size checks) are read from tuning.yaml when present
+// and fall back to the defaults otherwise.
+func AgentExecutor(cfg AgentConfig) (Executor, error) {
+	tuning, err := config.LoadTuning()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tuning configuration: %w", err)
+	}
+	return &agentExecutor{executor: agent.NewExecutorWithConfig(cfg, tuning)}, nil
+}
+
+// Investigate implements Executor.
+func (a *agentExecutor) Investigate(ctx context.Context, inc *Incident, workspacePath, facts string) (int, error) {
+	exitCode, _, err := a.executor.ExecuteWithFacts(ctx, workspacePath, inc.IncidentID, facts)
+	return exitCode, err
+}
+
+// Notifier is told about every finished investigation.
+type Notifier interface {
+	Notify(ctx context.Context, result *Result) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface.
+type NotifierFunc func(ctx context.Context, result *Result) error
+
+// Notify implements Notifier.
+func (f NotifierFunc) Notify(ctx context.Context, result *Result) error {
+	return f(ctx, result)
+}
+
+// webhookNotifier posts results with one of nightcrier's built-in chat notifiers.
+type webhookNotifier struct {
+	notifier reporting.Notifier
+}
+
+// SlackNotifier returns a notifier that posts to a Slack incoming webhook.
+func SlackNotifier(webhookURL string) Notifier {
+	return &webhookNotifier{notifier: reporting.NewSlackNotifier(webhookURL, config.DefaultTuning())}
+}
+
+// DiscordNotifier returns a notifier that posts to a Discord webhook.
+func DiscordNotifier(webhookURL string) Notifier {
+	return &webhookNotifier{notifier: reporting.NewDiscordNotifier(webhookURL, config.DefaultTuning())}
+}
+
+// MattermostNotifier returns a notifier that posts to a Mattermost incoming webhook.
+func MattermostNotifier(webhookURL string) Notifier {
+	return &webhookNotifier{notifier: reporting.NewMattermostNotifier(webhookURL, config.DefaultTuning())}
+}
+
+// Notify implements Notifier.
+func (w *webhookNotifier) Notify(ctx context.Context, result *Result) error {
+	inc := result.Incident
+	summary := &reporting.IncidentSummary{
+		IncidentID: inc.Ref(),
+		Cluster:    inc.Cluster,
+		Namespace:  inc.Namespace,
+		Reason:     inc.FaultType,
+		Severity:   inc.Severity,
+		Labels:     inc.Labels,
+		Status:     inc.Status,
+		RootCause:  result.RootCause,
+		Confidence: result.Confidence,
+		ReportPath: result.ReportPath,
+		ReportURL:  result.ReportURL,
+	}
+	if inc.Resource != nil {
+		summary.Resource = fmt.Sprintf("%s/%s", inc.Resource.Kind, inc.Resource.Name)
+	}
+	if inc.StartedAt != nil && inc.CompletedAt != nil {
+		summary.Duration = inc.CompletedAt.Sub(*inc.StartedAt)
+	}
+	if err := w.notifier.SendIncidentNotification(summary); err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", w.notifier.Name(), err)
+	}
+	return nil
+}
+
+// FilesystemStorage returns storage that keeps investigation artifacts under root,
+// one directory per incident.
+func FilesystemStorage(root string) Storage {
+	return storage.NewFilesystemStorage(root)
+}
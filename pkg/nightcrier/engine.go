@@ -0,0 +1,327 @@
+// Package nightcrier embeds the nightcrier triage pipeline in other Go services.
+//
+// An Engine receives fault events from one or more sources, opens an incident for
+// each, has an executor investigate it in a dedicated workspace, persists the
+// report, and tells notifiers about the result:
+//
+//	executor, err := nightcrier.AgentExecutor(nightcrier.AgentConfig{...})
+//	engine, err := nightcrier.New(
+//		nightcrier.WithSource(nightcrier.ChannelSource(faults)),
+//		nightcrier.WithExecutor(executor),
+//		nightcrier.WithWorkspaceRoot("/var/lib/nightcrier/incidents"),
+//		nightcrier.WithStorage(nightcrier.FilesystemStorage("/var/lib/nightcrier/reports")),
+//		nightcrier.WithNotifier(nightcrier.SlackNotifier(webhookURL)),
+//	)
+//	if err := engine.Start(ctx); err != nil { ... }
+//	defer engine.Stop(context.Background())
+//
+// The Engine is the core pipeline only. Features of the nightcrier daemon that
+// depend on its configuration file and state store (MCP subscriptions, event
+// aggregation and dedup, budgets, circuit breakers, the web UI) are not part of it.
+package nightcrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbias/nightcrier/internal/agent"
+	"github.com/rbias/nightcrier/internal/config"
+	"github.com/rbias/nightcrier/internal/events"
+	"github.com/rbias/nightcrier/internal/incident"
+	"github.com/rbias/nightcrier/internal/reporting"
+	"github.com/rbias/nightcrier/internal/storage"
+)
+
+type (
+	// FaultEvent is a fault reported for a Kubernetes resource.
+	FaultEvent = events.FaultEvent
+	// ResourceInfo identifies the resource a fault event is about.
+	ResourceInfo = events.ResourceInfo
+	// Incident is the record of one investigation.
+	Incident = incident.Incident
+	// Storage persists investigation artifacts.
+	Storage = storage.Storage
+	// IncidentArtifacts are the files of one investigation handed to Storage.
+	IncidentArtifacts = storage.IncidentArtifacts
+	// SaveResult describes where Storage put an investigation's artifacts.
+	SaveResult = storage.SaveResult
+	// AgentConfig configures the AI agent run by AgentExecutor.
+	AgentConfig = agent.ExecutorConfig
+)
+
+// Incident statuses reported in Result.Incident.Status.
+const (
+	StatusResolved    = incident.StatusResolved
+	StatusFailed      = incident.StatusFailed
+	StatusAgentFailed = incident.StatusAgentFailed
+)
+
+// Result is the outcome of one investigation, passed to every notifier.
+type Result struct {
+	// Incident is the finished incident; Status tells whether the investigation succeeded
+	Incident *Incident
+	// RootCause and Confidence are taken from the report's summary, when it has one
+	RootCause  string
+	Confidence string
+	// ReportPath is the report in the incident workspace
+	ReportPath string
+	// ReportURL is where storage put the report (a path for filesystem storage)
+	ReportURL string
+}
+
+// Engine runs the triage pipeline. Create it with New, then Start and Stop it; an
+// Engine cannot be restarted once stopped.
+type Engine struct {
+	sources            []Source
+	executor           Executor
+	notifiers          []Notifier
+	storage            Storage
+	workspaceRoot      string
+	workspaceTemplates []string
+	severityThreshold  string
+	maxConcurrent      int
+	minReportSize      int
+	logger             *slog.Logger
+
+	workspaces *agent.WorkspaceManager
+
+	mu      sync.Mutex
+	started bool
+	stopped bool
+	// cancelSources stops the sources and the dispatcher
+	cancelSources context.CancelFunc
+	// cancelInvestigations aborts in-flight investigations
+	cancelInvestigations context.CancelFunc
+	// dispatched is closed when the dispatcher has handed off its last event
+	dispatched chan struct{}
+	inFlight   sync.WaitGroup
+}
+
+// New creates an Engine. WithExecutor, WithWorkspaceRoot, and at least one
+// WithSource are required.
+func New(opts ...Option) (*Engine, error) {
+	e := &Engine{
+		maxConcurrent: defaultMaxConcurrent,
+		minReportSize: config.DefaultTuning().Agent.InvestigationMinSizeBytes,
+		logger:        slog.Default(),
+	}
+	for _, opt := range opts {
+		if err := opt(e); err != nil {
+			return nil, fmt.Errorf("invalid engine option: %w", err)
+		}
+	}
+	if e.executor == nil {
+		return nil, fmt.Errorf("an executor is required (WithExecutor)")
+	}
+	if len(e.sources) == 0 {
+		return nil, fmt.Errorf("at least one source is required (WithSource)")
+	}
+	if e.workspaceRoot == "" {
+		return nil, fmt.Errorf("a workspace root is required (WithWorkspaceRoot)")
+	}
+	e.workspaces = agent.NewWorkspaceManager(e.workspaceRoot)
+	return e, nil
+}
+
+// Start starts the sources and begins investigating their events. It returns
+// once the pipeline is running; ctx bounds the whole run, like a call to Stop.
+func (e *Engine) Start(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.started {
+		return fmt.Errorf("engine already started")
+	}
+	if err := os.MkdirAll(e.workspaceRoot, 0700); err != nil {
+		return fmt.Errorf("failed to create workspace root: %w", err)
+	}
+	e.started = true
+
+	sourceCtx, cancelSources := context.WithCancel(ctx)
+	investigationCtx, cancelInvestigations := context.WithCancel(ctx)
+	e.cancelSources = cancelSources
+	e.cancelInvestigations = cancelInvestigations
+	e.dispatched = make(chan struct{})
+
+	faults := make(chan *FaultEvent)
+	var running sync.WaitGroup
+	for _, source := range e.sources {
+		running.Add(1)
+		go func(source Source) {
+			defer running.Done()
+			if err := source.Run(sourceCtx, faults); err != nil && !errors.Is(err, context.Canceled) {
+				e.logger.Error("fault source stopped", "error", err)
+			}
+		}(source)
+	}
+	go func() {
+		running.Wait()
+		close(faults)
+	}()
+
+	go e.dispatch(sourceCtx, investigationCtx, faults)
+	return nil
+}
+
+// Stop stops the sources and waits for in-flight investigations to finish. When
+// ctx expires first the remaining investigations are cancelled and ctx's error is
+// returned.
+func (e *Engine) Stop(ctx context.Context) error {
+	e.mu.Lock()
+	if !e.started {
+		e.mu.Unlock()
+		return fmt.Errorf("engine not started")
+	}
+	if e.stopped {
+		e.mu.Unlock()
+		return nil
+	}
+	e.stopped = true
+	e.mu.Unlock()
+
+	e.cancelSources()
+	done := make(chan struct{})
+	go func() {
+		<-e.dispatched
+		e.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		e.cancelInvestigations()
+		return nil
+	case <-ctx.Done():
+		e.cancelInvestigations()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// dispatch starts an investigation for each event, at most maxConcurrent at once.
+func (e *Engine) dispatch(sourceCtx, investigationCtx context.Context, faults <-chan *FaultEvent) {
+	defer close(e.dispatched)
+	slots := make(chan struct{}, e.maxConcurrent)
+	for event := range faults {
+		if event == nil {
+			continue
+		}
+		if e.severityThreshold != "" && !events.MeetsSeverity(event.Severity, e.severityThreshold) {
+			e.logger.Debug("fault below severity threshold, skipping",
+				"fault_id", event.FaultID,
+				"severity", event.Severity,
+				"threshold", e.severityThreshold)
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-sourceCtx.Done():
+			// Stopping: events still queued are not investigated
+			e.logger.Debug("engine stopping, dropping fault", "fault_id", event.FaultID)
+			continue
+		}
+		e.inFlight.Add(1)
+		go func(event *FaultEvent) {
+			defer func() {
+				<-slots
+				e.inFlight.Done()
+			}()
+			if _, err := e.Investigate(investigationCtx, event); err != nil {
+				e.logger.Error("investigation failed",
+					"fault_id", event.FaultID,
+					"error", err)
+			}
+		}(event)
+	}
+}
+
+// Investigate runs one event through the pipeline synchronously and returns the
+// result it passed to the notifiers. It is what the engine runs for each source
+// event, and can be called directly (without Start) by services that schedule
+// investigations themselves. An error is returned only when the pipeline itself
+// fails; a failed investigation is reported in the result's incident status.
+func (e *Engine) Investigate(ctx context.Context, event *FaultEvent) (*Result, error) {
+	inc := incident.NewFromEvent(uuid.New().String(), event)
+	log := e.logger.With("incident_id", inc.IncidentID, "cluster", inc.Cluster)
+
+	workspacePath, err := e.workspaces.Create(inc.Ref(), e.workspaceTemplates...)
+	if err != nil {
+		return nil, err
+	}
+	incidentPath := filepath.Join(workspacePath, "incident.json")
+	facts := incident.BuildFacts(inc, event, time.Now()).Markdown()
+	if err := os.WriteFile(filepath.Join(workspacePath, "incident_facts.md"), []byte(facts), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write incident facts: %w", err)
+	}
+	startedAt := time.Now()
+	inc.StartedAt = &startedAt
+	if err := inc.Transition(incident.StatusInvestigating, startedAt); err != nil {
+		return nil, err
+	}
+	if err := inc.WriteToFile(incidentPath); err != nil {
+		return nil, err
+	}
+
+	log.Info("starting investigation",
+		"fault_type", inc.FaultType,
+		"namespace", inc.Namespace)
+	exitCode, execErr := e.executor.Investigate(ctx, inc, workspacePath, facts)
+	inc.MarkCompleted(exitCode, execErr)
+
+	reportPath := filepath.Join(workspacePath, "output", "investigation.md")
+	report, readErr := os.ReadFile(reportPath)
+	if inc.Status == incident.StatusResolved {
+		switch {
+		case readErr != nil:
+			inc.MarkAgentFailed()
+			inc.FailureReason = "investigation.md file not found"
+		case len(report) < e.minReportSize:
+			inc.MarkAgentFailed()
+			inc.FailureReason = fmt.Sprintf("investigation.md too small: %d bytes (expected >= %d)", len(report), e.minReportSize)
+		}
+	}
+	if err := inc.WriteToFile(incidentPath); err != nil {
+		return nil, err
+	}
+	log.Info("investigation finished",
+		"status", inc.Status,
+		"failure_reason", inc.FailureReason)
+
+	result := &Result{Incident: inc}
+	if inc.Status == incident.StatusResolved {
+		result.ReportPath = reportPath
+		result.RootCause, result.Confidence, _ = reporting.ExtractSummaryFromReport(workspacePath)
+	}
+
+	if e.storage != nil {
+		incidentJSON, err := os.ReadFile(incidentPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read incident file: %w", err)
+		}
+		artifacts := &IncidentArtifacts{IncidentJSON: incidentJSON}
+		if result.ReportPath != "" {
+			artifacts.InvestigationMD = report
+			artifacts.InvestigationHTML = reporting.ConvertMarkdownToHTML(report, inc.Ref())
+		}
+		saved, err := e.storage.SaveIncident(ctx, inc.Ref(), artifacts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save incident: %w", err)
+		}
+		if result.ReportPath != "" {
+			result.ReportURL = saved.ReportURL
+		}
+	}
+
+	for _, notifier := range e.notifiers {
+		if err := notifier.Notify(ctx, result); err != nil {
+			log.Warn("failed to notify", "error", err)
+		}
+	}
+	return result, nil
+}
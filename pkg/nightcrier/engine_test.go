@@ -0,0 +1,177 @@
+package nightcrier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testReport = `# Investigation
+
+## Root Cause
+
+The container image tag does not exist in the registry.
+
+**Confidence Level:** HIGH
+
+## Evidence
+
+The pod has been in ImagePullBackOff since the deployment was updated.
+`
+
+// reportingExecutor writes a fixed report, as a successful agent would.
+func reportingExecutor(report string) Executor {
+	return ExecutorFunc(func(ctx context.Context, inc *Incident, workspacePath, facts string) (int, error) {
+		if !strings.Contains(facts, inc.Resource.Name) {
+			return 1, nil
+		}
+		if err := os.MkdirAll(filepath.Join(workspacePath, "output"), 0700); err != nil {
+			return 1, err
+		}
+		return 0, os.WriteFile(filepath.Join(workspacePath, "output", "investigation.md"), []byte(report), 0600)
+	})
+}
+
+// recordingNotifier collects the results it is notified about.
+type recordingNotifier struct {
+	mu      sync.Mutex
+	results []*Result
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, result *Result) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, result)
+	return nil
+}
+
+func (r *recordingNotifier) wait(t *testing.T, n int) []*Result {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mu.Lock()
+		got := len(r.results)
+		r.mu.Unlock()
+		if got >= n {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.results) < n {
+		t.Fatalf("got %d notifications, want %d", len(r.results), n)
+	}
+	return r.results
+}
+
+func testFault(name, severity string) *FaultEvent {
+	return &FaultEvent{
+		FaultID:   "fault-" + name,
+		Cluster:   "prod",
+		FaultType: "ImagePullBackOff",
+		Severity:  severity,
+		Context:   "Back-off pulling image",
+		Resource:  &ResourceInfo{Kind: "Pod", Name: name, Namespace: "default"},
+	}
+}
+
+func TestEngineInvestigatesSourceEvents(t *testing.T) {
+	root := t.TempDir()
+	faults := make(chan *FaultEvent, 2)
+	notifier := &recordingNotifier{}
+
+	engine, err := New(
+		WithSource(ChannelSource(faults)),
+		WithExecutor(reportingExecutor(testReport)),
+		WithNotifier(notifier),
+		WithStorage(FilesystemStorage(filepath.Join(root, "reports"))),
+		WithWorkspaceRoot(filepath.Join(root, "incidents")),
+		WithSeverityThreshold("ERROR"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := engine.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := engine.Start(context.Background()); err == nil {
+		t.Error("second Start() should fail")
+	}
+
+	faults <- testFault("debug-pod", "INFO")
+	faults <- testFault("web-7d9f", "ERROR")
+	results := notifier.wait(t, 1)
+	if err := engine.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want only the ERROR fault investigated", len(results))
+	}
+	result := results[0]
+	if result.Incident.Status != StatusResolved {
+		t.Fatalf("status = %q (%s), want resolved", result.Incident.Status, result.Incident.FailureReason)
+	}
+	if result.Incident.Cluster != "prod" || result.Incident.Resource.Name != "web-7d9f" {
+		t.Errorf("incident = %+v, want the web-7d9f fault on prod", result.Incident)
+	}
+	if !strings.Contains(result.RootCause, "image tag does not exist") || result.Confidence != "HIGH" {
+		t.Errorf("root cause = %q, confidence = %q", result.RootCause, result.Confidence)
+	}
+	if _, err := os.Stat(result.ReportURL); err != nil {
+		t.Errorf("stored report %q: %v", result.ReportURL, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "incidents", result.Incident.IncidentID, "incident_facts.md")); err != nil {
+		t.Errorf("incident facts not written to the workspace: %v", err)
+	}
+}
+
+func TestEngineReportsMissingReportAsAgentFailure(t *testing.T) {
+	executor := ExecutorFunc(func(ctx context.Context, inc *Incident, workspacePath, facts string) (int, error) {
+		return 0, nil
+	})
+	engine, err := New(
+		WithSource(ChannelSource(nil)),
+		WithExecutor(executor),
+		WithWorkspaceRoot(t.TempDir()),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	result, err := engine.Investigate(context.Background(), testFault("api-0", "ERROR"))
+	if err != nil {
+		t.Fatalf("Investigate() error = %v", err)
+	}
+	if result.Incident.Status != StatusAgentFailed || result.ReportPath != "" {
+		t.Errorf("result = %+v, want agent_failed with no report", result)
+	}
+}
+
+func TestNewRequiresExecutorSourceAndWorkspace(t *testing.T) {
+	source := WithSource(ChannelSource(nil))
+	executor := WithExecutor(reportingExecutor(testReport))
+	workspace := WithWorkspaceRoot(t.TempDir())
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"no executor", []Option{source, workspace}},
+		{"no source", []Option{executor, workspace}},
+		{"no workspace root", []Option{source, executor}},
+		{"bad concurrency", []Option{source, executor, workspace, WithMaxConcurrent(0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.opts...); err == nil {
+				t.Error("New() should fail")
+			}
+		})
+	}
+}
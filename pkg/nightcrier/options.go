@@ -0,0 +1,125 @@
+package nightcrier
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// defaultMaxConcurrent is the number of investigations an Engine runs at once
+// unless WithMaxConcurrent says otherwise.
+const defaultMaxConcurrent = 5
+
+// Option configures an Engine.
+type Option func(*Engine) error
+
+// WithSource adds a fault event source. It may be given more than once; events from
+// all sources feed the same pipeline.
+func WithSource(source Source) Option {
+	return func(e *Engine) error {
+		if source == nil {
+			return fmt.Errorf("source cannot be nil")
+		}
+		e.sources = append(e.sources, source)
+		return nil
+	}
+}
+
+// WithExecutor sets the executor that investigates incidents (required). Use
+// AgentExecutor for the AI agent container nightcrier itself runs.
+func WithExecutor(executor Executor) Option {
+	return func(e *Engine) error {
+		if executor == nil {
+			return fmt.Errorf("executor cannot be nil")
+		}
+		e.executor = executor
+		return nil
+	}
+}
+
+// WithNotifier adds a notifier that is told about every finished investigation. It
+// may be given more than once.
+func WithNotifier(notifier Notifier) Option {
+	return func(e *Engine) error {
+		if notifier == nil {
+			return fmt.Errorf("notifier cannot be nil")
+		}
+		e.notifiers = append(e.notifiers, notifier)
+		return nil
+	}
+}
+
+// WithStorage sets where finished investigations are persisted. Without it reports
+// stay in their workspace only.
+func WithStorage(store Storage) Option {
+	return func(e *Engine) error {
+		e.storage = store
+		return nil
+	}
+}
+
+// WithWorkspaceRoot sets the directory incident workspaces are created in (required).
+func WithWorkspaceRoot(root string) Option {
+	return func(e *Engine) error {
+		if root == "" {
+			return fmt.Errorf("workspace root cannot be empty")
+		}
+		e.workspaceRoot = root
+		return nil
+	}
+}
+
+// WithWorkspaceTemplate adds a directory whose contents are copied into every new
+// workspace, e.g. an organization README or common scripts. It may be given more
+// than once; later templates replace files of earlier ones.
+func WithWorkspaceTemplate(dir string) Option {
+	return func(e *Engine) error {
+		if dir == "" {
+			return fmt.Errorf("workspace template cannot be empty")
+		}
+		e.workspaceTemplates = append(e.workspaceTemplates, dir)
+		return nil
+	}
+}
+
+// WithSeverityThreshold drops events below the given severity (DEBUG, INFO,
+// WARNING, ERROR, CRITICAL). Default: every event is investigated.
+func WithSeverityThreshold(severity string) Option {
+	return func(e *Engine) error {
+		e.severityThreshold = severity
+		return nil
+	}
+}
+
+// WithMaxConcurrent sets how many investigations run at once. Default: 5.
+func WithMaxConcurrent(n int) Option {
+	return func(e *Engine) error {
+		if n < 1 {
+			return fmt.Errorf("max concurrent investigations must be at least 1, got %d", n)
+		}
+		e.maxConcurrent = n
+		return nil
+	}
+}
+
+// WithMinReportSize sets the size below which a report is treated as a failed
+// investigation. Default: the tuning default (100 bytes).
+func WithMinReportSize(bytes int) Option {
+	return func(e *Engine) error {
+		if bytes < 0 {
+			return fmt.Errorf("minimum report size must be >= 0, got %d", bytes)
+		}
+		e.minReportSize = bytes
+		return nil
+	}
+}
+
+// WithLogger sets the logger the engine writes to. Default: slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(e *Engine) error {
+		if logger == nil {
+			return fmt.Errorf("logger cannot be nil")
+		}
+		e.logger = logger
+		return nil
+	}
+}